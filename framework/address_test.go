@@ -0,0 +1,41 @@
+package framework
+
+import "testing"
+
+// TestAddressIsZero 测试零地址判断
+func TestAddressIsZero(t *testing.T) {
+	if !(Address{}).IsZero() {
+		t.Error("Address{}.IsZero() = false, want true")
+	}
+	if !AddressFromBytes(nil).IsZero() {
+		t.Error("AddressFromBytes(nil).IsZero() = false, want true")
+	}
+	for _, addr := range []Address{{0x01}, {19: 0x01}, AddressFromBytes(make([]byte, 20))} {
+		if addr.IsZero() != (addr == Address{}) {
+			t.Errorf("%x.IsZero() = %v", addr, addr.IsZero())
+		}
+	}
+	if (Address{19: 0x01}).IsZero() {
+		t.Error("address with only the last byte set reported as zero")
+	}
+}
+
+// TestAddressEquals 测试地址相等判断逐字节比较
+func TestAddressEquals(t *testing.T) {
+	a := Address{0x01, 0x02, 0x03}
+	b := AddressFromBytes(append([]byte{0x01, 0x02, 0x03}, make([]byte, 17)...))
+	if !a.Equals(b) || !b.Equals(a) {
+		t.Errorf("%x.Equals(%x) = false, want true", a, b)
+	}
+	if !a.Equals(a) {
+		t.Error("a.Equals(a) = false, want true")
+	}
+	c := a
+	c[19] = 0xFF
+	if a.Equals(c) || c.Equals(a) {
+		t.Errorf("%x.Equals(%x) = true, want false", a, c)
+	}
+	if a.Equals(Address{}) || !(Address{}).Equals(Address{}) {
+		t.Error("comparison with zero address is wrong")
+	}
+}
@@ -0,0 +1,42 @@
+package framework
+
+// ==================== 批量输出序列化 ====================
+//
+// BatchCreateOutputsSimple 的 JSON 载荷参与执行结果哈希（execHash）计算，
+// 相同输入必须得到逐字节相同的 JSON。本文件不带 build tag，序列化可在非WASM环境中直接测试。
+
+// encodeBatchOutputsJSON 序列化批量输出项（手动序列化避免引入encoding/json）
+//
+// **格式**（按 items 顺序，字段顺序固定，无空白）：
+//
+//	[{"recipient":"<base64>","amount":<十进制>,"token_id":"<base64>"|null,"locking_conditions":[]},...]
+//
+// **注意**：
+//   - 输出顺序即 items 顺序，不做排序；调用方须以确定的顺序构造 items（不要从 map 遍历得到）
+//   - TokenID 为 nil 或空切片（原生币）时一律输出 null
+func encodeBatchOutputsJSON(items []struct {
+	Recipient []byte
+	Amount    uint64
+	TokenID   []byte
+}) []byte {
+	buf := make([]byte, 0, len(items)*96+2)
+	buf = append(buf, '[')
+	for i, it := range items {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"recipient":"`...)
+		buf = append(buf, base64EncodeSimple(it.Recipient)...)
+		buf = append(buf, `","amount":`...)
+		buf = append(buf, Uint64ToString(it.Amount)...)
+		if len(it.TokenID) > 0 {
+			buf = append(buf, `,"token_id":"`...)
+			buf = append(buf, base64EncodeSimple(it.TokenID)...)
+			buf = append(buf, '"')
+		} else {
+			buf = append(buf, `,"token_id":null`...)
+		}
+		buf = append(buf, `,"locking_conditions":[]}`...)
+	}
+	return append(buf, ']')
+}
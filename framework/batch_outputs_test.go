@@ -0,0 +1,66 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type batchOutputItem = struct {
+	Recipient []byte
+	Amount    uint64
+	TokenID   []byte
+}
+
+// TestEncodeBatchOutputsJSONDeterministic 测试相同输入在多次序列化间逐字节相同，且按 items 顺序输出
+func TestEncodeBatchOutputsJSONDeterministic(t *testing.T) {
+	items := []batchOutputItem{
+		{Recipient: []byte{0x01, 0x02}, Amount: 100, TokenID: []byte("USDT")},
+		{Recipient: []byte{0x03}, Amount: 18446744073709551615, TokenID: nil},
+		{Recipient: []byte{0x04, 0x05, 0x06}, Amount: 0, TokenID: []byte{}},
+	}
+	want := `[{"recipient":"AQI=","amount":100,"token_id":"VVNEVA==","locking_conditions":[]},` +
+		`{"recipient":"Aw==","amount":18446744073709551615,"token_id":null,"locking_conditions":[]},` +
+		`{"recipient":"BAUG","amount":0,"token_id":null,"locking_conditions":[]}]`
+
+	first := encodeBatchOutputsJSON(items)
+	if string(first) != want {
+		t.Fatalf("encodeBatchOutputsJSON() =\n%s\nwant\n%s", first, want)
+	}
+	for i := 0; i < 100; i++ {
+		if got := encodeBatchOutputsJSON(items); !bytes.Equal(got, first) {
+			t.Fatalf("run %d differs:\n%s\nwant\n%s", i, got, first)
+		}
+	}
+
+	// 结构等价的新切片（不共享底层数组）得到相同字节
+	clone := []batchOutputItem{
+		{Recipient: []byte{0x01, 0x02}, Amount: 100, TokenID: []byte("USDT")},
+		{Recipient: []byte{0x03}, Amount: 18446744073709551615},
+		{Recipient: []byte{0x04, 0x05, 0x06}, TokenID: make([]byte, 0, 8)},
+	}
+	if got := encodeBatchOutputsJSON(clone); !bytes.Equal(got, first) {
+		t.Errorf("equivalent items encode differently:\n%s\nwant\n%s", got, first)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 3 || decoded[1]["token_id"] != nil || decoded[2]["token_id"] != nil {
+		t.Errorf("decoded = %v, want 3 items with null token_id for native coin", decoded)
+	}
+}
+
+// TestEncodeBatchOutputsJSONOrderSensitive 测试不同的 items 顺序得到不同的字节（序列化不做排序）
+func TestEncodeBatchOutputsJSONOrderSensitive(t *testing.T) {
+	a := batchOutputItem{Recipient: []byte{0x01}, Amount: 1}
+	b := batchOutputItem{Recipient: []byte{0x02}, Amount: 2}
+
+	if bytes.Equal(encodeBatchOutputsJSON([]batchOutputItem{a, b}), encodeBatchOutputsJSON([]batchOutputItem{b, a})) {
+		t.Error("reordered items encode identically, want slice order preserved")
+	}
+	if got := string(encodeBatchOutputsJSON(nil)); got != "[]" {
+		t.Errorf("encodeBatchOutputsJSON(nil) = %s, want []", got)
+	}
+}
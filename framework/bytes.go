@@ -0,0 +1,53 @@
+package framework
+
+// ==================== 定长数值与字符串字段编解码 ====================
+//
+// 合约状态普遍使用 8 字节大端整数与 NUL 填充的定长字符串字段。各模板此前各自定义
+// uint64ToBytes / bytesToUint64 / trimNull，短切片的处理互不一致（有的返回 0，有的越界 panic）。
+// 本文件提供统一实现：
+//   - Uint64ToBytes / BytesToUint64：8 字节大端无符号整数
+//   - Int64ToBytes / BytesToInt64：8 字节大端有符号整数（二进制补码，可表示负的时间差、余额调整等）
+//   - TrimNull：去除定长字符串字段的 NUL 填充
+//
+// 十进制字符串见 Uint64ToString（contract_base.go）。
+
+// Uint64ToBytes 将 uint64 编码为 8 字节大端序字节数组
+func Uint64ToBytes(n uint64) []byte {
+	return appendBEUint64(make([]byte, 0, 8), n)
+}
+
+// BytesToUint64 将大端序字节数组解码为 uint64
+//
+// **注意**：
+//   - 只读取前 8 字节，多余字节被忽略
+//   - 不足 8 字节时缺少的尾部字节按 0 处理（与 GetStateFromChain 去除尾部零字节后的数据一致），
+//     空切片返回 0，不会越界
+func BytesToUint64(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], b)
+	return beUint64(buf[:])
+}
+
+// Int64ToBytes 将 int64 按二进制补码编码为 8 字节大端序字节数组
+func Int64ToBytes(n int64) []byte {
+	return Uint64ToBytes(uint64(n))
+}
+
+// BytesToInt64 将 8 字节大端序二进制补码解码为 int64
+//
+// 短切片的处理与 BytesToUint64 相同
+func BytesToInt64(b []byte) int64 {
+	return int64(BytesToUint64(b))
+}
+
+// TrimNull 截断到第一个 NUL 字节之前，用于解码 NUL 填充的定长字符串字段
+//
+// 不含 NUL 时原样返回；返回值与 b 共享底层数组
+func TrimNull(b []byte) []byte {
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
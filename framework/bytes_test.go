@@ -0,0 +1,79 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// TestUint64BytesRoundTrip 测试边界值编解码与大端字节序
+func TestUint64BytesRoundTrip(t *testing.T) {
+	for _, n := range []uint64{0, 1, 255, 256, 1 << 32, math.MaxUint64 - 1, math.MaxUint64} {
+		b := Uint64ToBytes(n)
+		if len(b) != 8 {
+			t.Fatalf("Uint64ToBytes(%d) length = %d, want 8", n, len(b))
+		}
+		if got := BytesToUint64(b); got != n {
+			t.Errorf("BytesToUint64(Uint64ToBytes(%d)) = %d", n, got)
+		}
+	}
+	if got := Uint64ToBytes(0x0102030405060708); !bytes.Equal(got, []byte{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("Uint64ToBytes() = %x, want big-endian 0102030405060708", got)
+	}
+	if got := Uint64ToBytes(math.MaxUint64); !bytes.Equal(got, bytes.Repeat([]byte{0xff}, 8)) {
+		t.Errorf("Uint64ToBytes(max) = %x", got)
+	}
+}
+
+// TestBytesToUint64ShortSlice 测试短切片按尾部补零解码、不越界，长切片只读前 8 字节
+func TestBytesToUint64ShortSlice(t *testing.T) {
+	cases := []struct {
+		in   []byte
+		want uint64
+	}{
+		{nil, 0},
+		{[]byte{}, 0},
+		{[]byte{0x01}, 0x0100000000000000},
+		// 256 编码为 00..0100，链上读取去除尾部零字节后只剩 7 字节
+		{[]byte{0, 0, 0, 0, 0, 0, 1}, 256},
+		{[]byte{0, 0, 0, 0, 0, 0, 0, 5, 0xff, 0xff}, 5},
+	}
+	for _, c := range cases {
+		if got := BytesToUint64(c.in); got != c.want {
+			t.Errorf("BytesToUint64(%x) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+// TestInt64BytesRoundTrip 测试有符号编解码（负数使用二进制补码）
+func TestInt64BytesRoundTrip(t *testing.T) {
+	for _, n := range []int64{0, 1, -1, -1736200000, math.MaxInt64, math.MinInt64} {
+		if got := BytesToInt64(Int64ToBytes(n)); got != n {
+			t.Errorf("BytesToInt64(Int64ToBytes(%d)) = %d", n, got)
+		}
+	}
+	if got := Int64ToBytes(-1); !bytes.Equal(got, bytes.Repeat([]byte{0xff}, 8)) {
+		t.Errorf("Int64ToBytes(-1) = %x", got)
+	}
+	if got := BytesToInt64(nil); got != 0 {
+		t.Errorf("BytesToInt64(nil) = %d, want 0", got)
+	}
+}
+
+// TestTrimNull 测试截断到第一个 NUL
+func TestTrimNull(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"", ""},
+		{"USDT", "USDT"},
+		{"USDT\x00\x00\x00", "USDT"},
+		{"\x00\x00", ""},
+		{"ab\x00cd", "ab"},
+	}
+	for _, c := range cases {
+		if got := string(TrimNull([]byte(c.in))); got != c.want {
+			t.Errorf("TrimNull(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
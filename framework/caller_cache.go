@@ -0,0 +1,37 @@
+package framework
+
+// 调用内调用者缓存
+//
+// 调用者在一次导出函数调用中不变，但模板与 helpers 常在多个辅助函数中各自调用 GetCaller，
+// 每次都分配 20 字节并执行宿主调用 get_caller。GetCaller 在首次成功读取后缓存调用者，
+// 本次调用随后的读取直接返回缓存。
+//
+// 缓存在导出函数入口由 BeginInvocation 清空（见 invocation.go），复用的 WASM 实例不会沿用
+// 上一次调用的调用者；非WASM环境另由 ResetStagedWrites（MockHost.Invoke 在调用前后执行）
+// 与 InstallMockHost 清空。
+
+var (
+	cachedCaller      Address
+	cachedCallerValid bool
+)
+
+// ResetCallerCache 清空调用者缓存（开始一次新的合约调用）
+//
+// 导出函数入口使用 BeginInvocation，一并清空其他调用范围内的状态
+func ResetCallerCache() {
+	cachedCaller = Address{}
+	cachedCallerValid = false
+}
+
+// callerFromCache 返回缓存的调用者，未缓存时通过 load 读取；load 返回零地址（宿主调用失败）时不缓存
+func callerFromCache(load func() Address) Address {
+	if cachedCallerValid {
+		return cachedCaller
+	}
+	caller := load()
+	if caller != (Address{}) {
+		cachedCaller = caller
+		cachedCallerValid = true
+	}
+	return caller
+}
@@ -0,0 +1,96 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// TestGetCallerCachedWithinInvocation 测试同一次调用内重复 GetCaller 返回首次读取的调用者
+func TestGetCallerCachedWithinInvocation(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	alice, bob := Address{0x01}, Address{0x02}
+
+	var first, second Address
+	host.Invoke(alice, nil, func() uint32 {
+		first = GetCaller()
+		host.caller = bob // 宿主侧变化不影响本次调用已缓存的调用者
+		second = GetCaller()
+		return SUCCESS
+	})
+	if first != alice || second != alice {
+		t.Errorf("GetCaller() = %x, %x, want %x twice", first, second, alice)
+	}
+}
+
+// TestGetCallerCacheClearedAcrossInvocations 测试调用之间清空缓存，不串用上一次调用的调用者
+func TestGetCallerCacheClearedAcrossInvocations(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	alice, bob := Address{0x01}, Address{0x02}
+
+	for _, caller := range []Address{alice, bob, alice} {
+		var got Address
+		host.Invoke(caller, nil, func() uint32 {
+			got = GetCaller()
+			return SUCCESS
+		})
+		if got != caller {
+			t.Errorf("GetCaller() = %x, want %x", got, caller)
+		}
+	}
+
+	// 调用结束后缓存已清空；卸载宿主后不再返回之前的调用者
+	restore := InstallMockHost(nil)
+	defer restore()
+	if got := GetCaller(); got != (Address{}) {
+		t.Errorf("GetCaller() without MockHost = %x, want zero address", got)
+	}
+}
+
+// TestGetCallerZeroNotCached 测试读取失败（零地址）时不缓存，下次重新读取
+func TestGetCallerZeroNotCached(t *testing.T) {
+	ResetCallerCache()
+	defer ResetCallerCache()
+	calls := 0
+	load := func() Address {
+		calls++
+		if calls == 1 {
+			return Address{}
+		}
+		return Address{0x03}
+	}
+	if got := callerFromCache(load); got != (Address{}) {
+		t.Errorf("first read = %x, want zero address", got)
+	}
+	for i := 0; i < 2; i++ {
+		if got := callerFromCache(load); got != (Address{0x03}) {
+			t.Errorf("read %d = %x, want 03...", i+2, got)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("load called %d times, want 2", calls)
+	}
+}
+
+// TestGetCallerResetAtEntry 测试复用实例的连续调用（不经过 MockHost.Invoke / ResetStagedWrites）
+// 在入口调用 BeginInvocation 时清空缓存，不沿用上一次调用的调用者
+func TestGetCallerResetAtEntry(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	alice, bob := Address{0x01}, Address{0x02}
+
+	for _, caller := range []Address{alice, bob} {
+		host.caller = caller
+		BeginInvocation()
+		if got := GetCaller(); got != caller {
+			t.Errorf("GetCaller() after entry = %x, want %x", got, caller)
+		}
+	}
+
+	// 读取参数不清空缓存
+	host.caller = alice
+	GetContractParams()
+	if got := GetCaller(); got != bob {
+		t.Errorf("GetCaller() after reading params = %x, want cached %x", got, bob)
+	}
+}
@@ -0,0 +1,270 @@
+// Package codec 提供定长结构的二进制编解码
+//
+// 🌟 **设计理念**：合约状态常以定长字节布局存储（字符串补 0x00、整数 8 字节大端），
+// 各模板手写 result[128:136] 一类的偏移量既冗长又容易出错。FixedCodec 按字段登记顺序
+// 自动计算偏移与总长度，编码与解码共用同一份布局定义。
+//
+// 🎯 **核心特性**：
+//   - StringField(maxLen)：定长字符串，超长截断，不足补 0x00，解码时截到第一个 0x00
+//   - Uint64Field()：8 字节大端整数
+//   - BoolField()：1 字节，0 为 false，1 为 true
+//   - AddressField()：20 字节地址
+//   - Optional()：标记末尾追加的字段，缺少这些字段的旧记录仍可解码（对应字段为零值）
+//
+// **示例**：
+//
+//	var memberCodec = codec.NewFixedCodec().
+//	    StringField(16). // status
+//	    Uint64Field().   // joinTime
+//	    Uint64Field().Optional() // tier，后续版本追加
+//
+//	data := memberCodec.Encode("ACTIVE", joinTime, tier)
+//	rec := memberCodec.Decode(data)
+//	status, joinTime, tier := rec.String(0), rec.Uint64(1), rec.Uint64(2)
+//
+// 本包不依赖宿主函数，可在WASM与非WASM环境中直接使用。
+package codec
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// fieldKind 字段类型
+type fieldKind uint8
+
+const (
+	kindString fieldKind = iota
+	kindUint64
+	kindBool
+	kindAddress
+)
+
+// field 单个字段的布局
+type field struct {
+	kind     fieldKind
+	offset   int
+	size     int
+	optional bool
+}
+
+// FixedCodec 定长结构编解码器
+//
+// 通过 NewFixedCodec 创建并按顺序登记字段；登记完成后只读，可作为包级变量在多次调用间共用
+type FixedCodec struct {
+	fields []field
+	size   int
+	// minSize 解码所需的最短长度（最后一个非 Optional 字段的结束位置）
+	minSize int
+}
+
+// NewFixedCodec 创建空的定长编解码器
+func NewFixedCodec() *FixedCodec {
+	return &FixedCodec{}
+}
+
+// StringField 登记定长字符串字段（maxLen 字节）
+func (c *FixedCodec) StringField(maxLen int) *FixedCodec {
+	return c.add(kindString, maxLen)
+}
+
+// Uint64Field 登记 8 字节大端整数字段
+func (c *FixedCodec) Uint64Field() *FixedCodec {
+	return c.add(kindUint64, 8)
+}
+
+// BoolField 登记 1 字节布尔字段
+func (c *FixedCodec) BoolField() *FixedCodec {
+	return c.add(kindBool, 1)
+}
+
+// AddressField 登记 20 字节地址字段
+func (c *FixedCodec) AddressField() *FixedCodec {
+	return c.add(kindAddress, 20)
+}
+
+// Optional 将最近登记的字段标记为可缺省
+//
+// 用于在布局末尾追加字段：追加前写入的旧记录较短，解码时缺少的字段为零值。
+// 只对末尾连续的字段有意义——其后再登记非 Optional 字段时，该字段重新变为必需。
+func (c *FixedCodec) Optional() *FixedCodec {
+	if n := len(c.fields); n > 0 && !c.fields[n-1].optional {
+		c.fields[n-1].optional = true
+		c.minSize = 0
+		for _, f := range c.fields {
+			if !f.optional {
+				c.minSize = f.offset + f.size
+			}
+		}
+	}
+	return c
+}
+
+// Size 编码后的总长度（字节）
+func (c *FixedCodec) Size() int {
+	return c.size
+}
+
+// MinSize 可解码的最短长度（字节），不含末尾 Optional 字段
+func (c *FixedCodec) MinSize() int {
+	return c.minSize
+}
+
+// NumFields 已登记的字段数
+func (c *FixedCodec) NumFields() int {
+	return len(c.fields)
+}
+
+// Encode 按登记顺序编码字段值
+//
+// **参数**：
+//   - values: 与字段一一对应的值——StringField 接受 string 或 []byte，Uint64Field 接受 uint64，
+//     BoolField 接受 bool，AddressField 接受 framework.Address
+//
+// **返回**：Size() 字节的编码数据；值的个数或类型与字段不符时返回 nil
+func (c *FixedCodec) Encode(values ...interface{}) []byte {
+	if len(values) != len(c.fields) {
+		return nil
+	}
+	result := make([]byte, c.size)
+	for i, f := range c.fields {
+		dst := result[f.offset : f.offset+f.size]
+		switch f.kind {
+		case kindString:
+			switch v := values[i].(type) {
+			case string:
+				copy(dst, v)
+			case []byte:
+				copy(dst, v)
+			default:
+				return nil
+			}
+		case kindUint64:
+			v, ok := values[i].(uint64)
+			if !ok {
+				return nil
+			}
+			putUint64(dst, v)
+		case kindBool:
+			v, ok := values[i].(bool)
+			if !ok {
+				return nil
+			}
+			if v {
+				dst[0] = 1
+			}
+		case kindAddress:
+			v, ok := values[i].(framework.Address)
+			if !ok {
+				return nil
+			}
+			copy(dst, v[:])
+		}
+	}
+	return result
+}
+
+// Decode 解码数据
+//
+// 数据短于 MinSize() 时返回全部字段为零值的记录（Valid() 为 false）；
+// 长于 MinSize() 但缺少末尾 Optional 字段（含只有部分字节的字段）时，缺少的字段为零值；超出 Size() 的部分忽略。
+func (c *FixedCodec) Decode(data []byte) Record {
+	buf := make([]byte, c.size)
+	valid := len(data) >= c.minSize
+	if valid {
+		end := 0
+		for _, f := range c.fields {
+			if f.offset+f.size <= len(data) {
+				end = f.offset + f.size
+			}
+		}
+		copy(buf, data[:end])
+	}
+	return Record{codec: c, data: buf, valid: valid}
+}
+
+// add 按顺序追加字段
+func (c *FixedCodec) add(kind fieldKind, size int) *FixedCodec {
+	if size < 0 {
+		size = 0
+	}
+	c.fields = append(c.fields, field{kind: kind, offset: c.size, size: size})
+	c.size += size
+	c.minSize = c.size
+	return c
+}
+
+// Record 解码后的记录，按字段下标（登记顺序，从 0 开始）读取
+//
+// 下标越界或字段类型不符时返回对应类型的零值
+type Record struct {
+	codec *FixedCodec
+	data  []byte
+	valid bool
+}
+
+// Valid 解码数据是否达到 MinSize()
+func (r Record) Valid() bool {
+	return r.valid
+}
+
+// String 读取字符串字段（截到第一个 0x00）
+func (r Record) String(i int) string {
+	b, ok := r.field(i, kindString)
+	if !ok {
+		return ""
+	}
+	for j := 0; j < len(b); j++ {
+		if b[j] == 0 {
+			return string(b[:j])
+		}
+	}
+	return string(b)
+}
+
+// Uint64 读取整数字段
+func (r Record) Uint64(i int) uint64 {
+	b, ok := r.field(i, kindUint64)
+	if !ok {
+		return 0
+	}
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
+
+// Bool 读取布尔字段（非 0 为 true）
+func (r Record) Bool(i int) bool {
+	b, ok := r.field(i, kindBool)
+	return ok && b[0] != 0
+}
+
+// Address 读取地址字段
+func (r Record) Address(i int) framework.Address {
+	var addr framework.Address
+	if b, ok := r.field(i, kindAddress); ok {
+		copy(addr[:], b)
+	}
+	return addr
+}
+
+// field 返回第 i 个字段的原始字节
+func (r Record) field(i int, kind fieldKind) ([]byte, bool) {
+	if r.codec == nil || i < 0 || i >= len(r.codec.fields) {
+		return nil, false
+	}
+	f := r.codec.fields[i]
+	if f.kind != kind {
+		return nil, false
+	}
+	return r.data[f.offset : f.offset+f.size], true
+}
+
+// putUint64 以大端序写入 8 字节整数
+func putUint64(dst []byte, v uint64) {
+	for i := 7; i >= 0; i-- {
+		dst[i] = byte(v)
+		v >>= 8
+	}
+}
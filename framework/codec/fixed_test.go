@@ -0,0 +1,274 @@
+package codec
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// testCodec status(16) + amount(8) + flag(1) + owner(20) + seq(8, 可缺省) = 53 字节
+func testCodec() *FixedCodec {
+	return NewFixedCodec().
+		StringField(16).
+		Uint64Field().
+		BoolField().
+		AddressField().
+		Uint64Field().Optional()
+}
+
+// TestFixedCodecLayout 测试偏移与长度按登记顺序计算，编码结果与手写布局一致
+func TestFixedCodecLayout(t *testing.T) {
+	c := testCodec()
+	if c.Size() != 53 || c.MinSize() != 45 || c.NumFields() != 5 {
+		t.Fatalf("Size/MinSize/NumFields = %d/%d/%d, want 53/45/5", c.Size(), c.MinSize(), c.NumFields())
+	}
+
+	owner := framework.Address{0x01, 0x00, 0x02}
+	data := c.Encode("ACTIVE", uint64(0x0102030405060708), true, owner, uint64(9))
+	want := make([]byte, 53)
+	copy(want[0:16], "ACTIVE")
+	copy(want[16:24], []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	want[24] = 1
+	copy(want[25:45], owner[:])
+	want[52] = 9
+	if string(data) != string(want) {
+		t.Errorf("Encode() = %x\nwant       %x", data, want)
+	}
+}
+
+// TestFixedCodecRoundTrip 测试编解码往返：字符串补 0x00 后截断还原，超长字符串截断，地址中的 0x00 保留
+func TestFixedCodecRoundTrip(t *testing.T) {
+	c := testCodec()
+	owner := framework.Address{0x00, 0xab, 0x00, 0xcd}
+	tests := []struct {
+		status, wantStatus string
+	}{
+		{"ACTIVE", "ACTIVE"},
+		{"", ""},
+		{"0123456789abcdefXYZ", "0123456789abcdef"},
+	}
+	for _, tt := range tests {
+		rec := c.Decode(c.Encode(tt.status, uint64(1<<63), false, owner, uint64(42)))
+		if !rec.Valid() || rec.String(0) != tt.wantStatus || rec.Uint64(1) != 1<<63 || rec.Bool(2) || rec.Address(3) != owner || rec.Uint64(4) != 42 {
+			t.Errorf("round trip %q = %q, %d, %v, %x, %d", tt.status, rec.String(0), rec.Uint64(1), rec.Bool(2), rec.Address(3), rec.Uint64(4))
+		}
+	}
+	if rec := c.Decode(c.Encode([]byte("PENDING"), uint64(0), true, framework.Address{}, uint64(0))); rec.String(0) != "PENDING" || !rec.Bool(2) {
+		t.Errorf("[]byte string field = %q, bool %v", rec.String(0), rec.Bool(2))
+	}
+}
+
+// TestFixedCodecShortData 测试数据不足 MinSize 时返回零值记录，缺少（或只有部分字节的）可缺省字段的旧记录仍可解码
+func TestFixedCodecShortData(t *testing.T) {
+	c := testCodec()
+	full := c.Encode("ACTIVE", uint64(7), true, framework.Address{0x11}, uint64(3))
+
+	legacy := c.Decode(full[:c.MinSize()])
+	if !legacy.Valid() || legacy.String(0) != "ACTIVE" || legacy.Uint64(1) != 7 || legacy.Uint64(4) != 0 {
+		t.Errorf("legacy record = %q, %d, seq %d", legacy.String(0), legacy.Uint64(1), legacy.Uint64(4))
+	}
+	for _, data := range [][]byte{nil, full[:16], full[:c.MinSize()-1]} {
+		rec := c.Decode(data)
+		if rec.Valid() || rec.String(0) != "" || rec.Uint64(1) != 0 || rec.Bool(2) || !rec.Address(3).IsZero() {
+			t.Errorf("Decode(%d bytes) = %q, %d, valid %v, want zero record", len(data), rec.String(0), rec.Uint64(1), rec.Valid())
+		}
+	}
+	if partial := c.Decode(full[:c.Size()-1]); !partial.Valid() || partial.Uint64(4) != 0 {
+		t.Errorf("partially present optional field = %d, want 0", partial.Uint64(4))
+	}
+	if rec := c.Decode(append(full, 0xff, 0xff)); rec.Uint64(4) != 3 {
+		t.Errorf("trailing bytes changed decoding: seq = %d", rec.Uint64(4))
+	}
+}
+
+// TestFixedCodecMismatch 测试值的个数或类型与字段不符时 Encode 返回 nil，按错误类型读取字段返回零值
+func TestFixedCodecMismatch(t *testing.T) {
+	c := testCodec()
+	bad := [][]interface{}{
+		{"ACTIVE", uint64(1), true, framework.Address{}},
+		{"ACTIVE", uint64(1), true, framework.Address{}, uint64(1), uint64(2)},
+		{"ACTIVE", 1, true, framework.Address{}, uint64(1)},
+		{uint64(1), uint64(1), true, framework.Address{}, uint64(1)},
+		{"ACTIVE", uint64(1), 1, framework.Address{}, uint64(1)},
+		{"ACTIVE", uint64(1), true, "addr", uint64(1)},
+	}
+	for i, values := range bad {
+		if got := c.Encode(values...); got != nil {
+			t.Errorf("case %d: Encode() = %x, want nil", i, got)
+		}
+	}
+
+	rec := c.Decode(c.Encode("ACTIVE", uint64(5), true, framework.Address{0x01}, uint64(0)))
+	if rec.Uint64(0) != 0 || rec.String(1) != "" || rec.Bool(3) || rec.String(-1) != "" || rec.Uint64(5) != 0 {
+		t.Error("mismatched or out-of-range field reads should return zero values")
+	}
+	if (Record{}).String(0) != "" || (Record{}).Valid() {
+		t.Error("zero Record should read as empty")
+	}
+}
+
+// TestFixedCodecOptionalMustBeTrailing 测试 Optional 之后再登记必需字段时，先前的可缺省字段变为必需
+func TestFixedCodecOptionalMustBeTrailing(t *testing.T) {
+	c := NewFixedCodec().Uint64Field().Uint64Field().Optional()
+	if c.MinSize() != 8 {
+		t.Fatalf("MinSize() = %d, want 8", c.MinSize())
+	}
+	c.Uint64Field()
+	if c.MinSize() != 24 {
+		t.Errorf("MinSize() after required field = %d, want 24", c.MinSize())
+	}
+	if c := NewFixedCodec().Optional(); c.Size() != 0 || c.MinSize() != 0 {
+		t.Errorf("Optional() on empty codec = %d/%d", c.Size(), c.MinSize())
+	}
+}
+
+// TestFixedCodecExhaustiveRoundTrip 穷举 1 到 3 个字段的全部类型组合（含末尾 Optional），
+// 每个位置轮流使用各类型的边界值：编码长度为 Size()，每个字段写在按登记顺序累加的偏移处，解码还原原值
+func TestFixedCodecExhaustiveRoundTrip(t *testing.T) {
+	const strLen = 8
+	samples := map[fieldKind][]interface{}{
+		kindString: {"", "a", "ACTIVE", "12345678", "123456789", strings.Repeat("z", 40), "中文"},
+		kindUint64: {uint64(0), uint64(1), uint64(255), uint64(256), uint64(1) << 32, uint64(1) << 63, uint64(math.MaxUint64)},
+		kindBool:   {false, true},
+		kindAddress: {
+			framework.Address{},
+			framework.Address{0x00, 0xff, 0x00, 0x01},
+			framework.AddressFromBytes([]byte(strings.Repeat("\xff", 20))),
+		},
+	}
+	sizes := map[fieldKind]int{kindString: strLen, kindUint64: 8, kindBool: 1, kindAddress: 20}
+	kinds := []fieldKind{kindString, kindUint64, kindBool, kindAddress}
+
+	var layouts [][]fieldKind
+	for n := 1; n <= 3; n++ {
+		total := int(math.Pow(float64(len(kinds)), float64(n)))
+		for i := 0; i < total; i++ {
+			layout := make([]fieldKind, n)
+			for j, x := 0, i; j < n; j, x = j+1, x/len(kinds) {
+				layout[j] = kinds[x%len(kinds)]
+			}
+			layouts = append(layouts, layout)
+		}
+	}
+
+	for _, layout := range layouts {
+		for _, optional := range []bool{false, true} {
+			c := NewFixedCodec()
+			offsets := make([]int, len(layout))
+			size := 0
+			for i, kind := range layout {
+				offsets[i] = size
+				size += sizes[kind]
+				switch kind {
+				case kindString:
+					c.StringField(strLen)
+				case kindUint64:
+					c.Uint64Field()
+				case kindBool:
+					c.BoolField()
+				case kindAddress:
+					c.AddressField()
+				}
+			}
+			if optional {
+				c.Optional()
+			}
+			minSize := size
+			if optional {
+				minSize = offsets[len(layout)-1]
+			}
+			if c.Size() != size || c.MinSize() != minSize || c.NumFields() != len(layout) {
+				t.Fatalf("layout %v optional=%v: Size/MinSize/NumFields = %d/%d/%d, want %d/%d/%d",
+					layout, optional, c.Size(), c.MinSize(), c.NumFields(), size, minSize, len(layout))
+			}
+
+			for round := 0; round < 7; round++ {
+				values := make([]interface{}, len(layout))
+				for i, kind := range layout {
+					values[i] = samples[kind][(round+i)%len(samples[kind])]
+				}
+				data := c.Encode(values...)
+				if len(data) != size {
+					t.Fatalf("layout %v: Encode(%v) = %d bytes, want %d", layout, values, len(data), size)
+				}
+				for i, kind := range layout {
+					checkFixedField(t, layout, data[offsets[i]:offsets[i]+sizes[kind]], kind, values[i])
+				}
+				rec := c.Decode(data)
+				if !rec.Valid() {
+					t.Fatalf("layout %v: Decode(Encode(%v)) invalid", layout, values)
+				}
+				for i, kind := range layout {
+					if got, want := readFixedField(rec, i, kind), expectedFixedValue(kind, values[i], strLen); got != want {
+						t.Errorf("layout %v field %d: got %v, want %v", layout, i, got, want)
+					}
+				}
+				// 缺少末尾 Optional 字段时，其余字段不变、缺少的字段为零值
+				if optional {
+					last := len(layout) - 1
+					legacy := c.Decode(data[:minSize])
+					for i, kind := range layout {
+						want := expectedFixedValue(kind, values[i], strLen)
+						if i == last {
+							want = expectedFixedValue(kind, samples[kind][0], strLen)
+						}
+						if got := readFixedField(legacy, i, kind); got != want {
+							t.Errorf("layout %v legacy field %d: got %v, want %v", layout, i, got, want)
+						}
+					}
+				}
+				if minSize > 0 && c.Decode(data[:minSize-1]).Valid() {
+					t.Errorf("layout %v: %d-byte record should be invalid", layout, minSize-1)
+				}
+			}
+		}
+	}
+}
+
+// checkFixedField 校验单个字段的原始字节：字符串截断后补 0x00，整数 8 字节大端，布尔 0/1，地址原样
+func checkFixedField(t *testing.T, layout []fieldKind, b []byte, kind fieldKind, value interface{}) {
+	t.Helper()
+	want := make([]byte, len(b))
+	switch v := value.(type) {
+	case string:
+		copy(want, v)
+	case uint64:
+		for i := 7; i >= 0; i-- {
+			want[i] = byte(v)
+			v >>= 8
+		}
+	case bool:
+		if v {
+			want[0] = 1
+		}
+	case framework.Address:
+		copy(want, v[:])
+	}
+	if string(b) != string(want) {
+		t.Errorf("layout %v: field bytes %x, want %x", layout, b, want)
+	}
+}
+
+// readFixedField 按字段类型读取解码值
+func readFixedField(rec Record, i int, kind fieldKind) interface{} {
+	switch kind {
+	case kindString:
+		return rec.String(i)
+	case kindUint64:
+		return rec.Uint64(i)
+	case kindBool:
+		return rec.Bool(i)
+	default:
+		return rec.Address(i)
+	}
+}
+
+// expectedFixedValue 编码后再解码应得到的值（字符串截断到 strLen 字节）
+func expectedFixedValue(kind fieldKind, value interface{}, strLen int) interface{} {
+	if s, ok := value.(string); ok && len(s) > strLen {
+		return s[:strLen]
+	}
+	return value
+}
@@ -1,13 +1,5 @@
-//go:build tinygo || (js && wasm)
-
-//nolint // WASM环境需要使用unsafe.Pointer访问线性内存
-
 package framework
 
-import (
-	"unsafe"
-)
-
 // ==================== WES Go合约开发框架 ====================
 //
 // 🌟 **设计理念**：为WES合约开发提供统一的Go语言框架
@@ -40,6 +32,10 @@ const (
 	ERROR_TIMEOUT              = 8
 	ERROR_NOT_IMPLEMENTED      = 9
 	ERROR_PERMISSION_DENIED    = 10
+	ERROR_QUOTA_EXCEEDED       = 11
+	ERROR_SLIPPAGE_EXCEEDED    = 12
+	ERROR_PAUSED               = 13
+	ERROR_RATE_LIMITED         = 14
 	ERROR_UNKNOWN              = 999
 )
 
@@ -73,6 +69,10 @@ type ContractBase struct {
 	// 合约配置
 	Interfaces []string
 	Features   []string
+
+	// 余额清扫（见 SweepExcess）
+	Owner            Address
+	ProtocolBalances ProtocolBalanceSource
 }
 
 // NewContractBase 创建新的合约基础实例
@@ -96,6 +96,90 @@ func (cb *ContractBase) AddFeature(feature string) {
 	cb.Features = append(cb.Features, feature)
 }
 
+// SweepExcess 将合约地址上超出协议资金的余额转给指定地址
+//
+// 🎯 **用途**：找回误转入资金池类合约的资金，同时保证协议拥有的资金不会被转出
+//
+// **参数**：
+//   - tokenID: 代币ID
+//   - to: 接收地址
+//
+// **返回**：实际清扫金额
+//
+// **注意**：
+//   - 仅 cb.Owner 可调用；Owner 未设置时任何人都无法清扫
+//   - 清扫金额 = QueryUTXOBalance(合约地址) - cb.ProtocolBalances.ProtocolBalance(tokenID)
+//   - 未设置 ProtocolBalances 时拒绝清扫
+//   - 成功后发出 ExcessSwept 事件
+//
+// **示例**：
+//
+//	pool := framework.NewContractBase("Pool", "POOL", "1.0.0")
+//	pool.Owner = owner
+//	pool.ProtocolBalances = subaccount.LiabilitySource(LEDGER_NS)
+//	swept, err := pool.SweepExcess(tokenID, treasury)
+func (cb *ContractBase) SweepExcess(tokenID TokenID, to Address) (Amount, error) {
+	if to == (Address{}) {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "sweep recipient is required")
+	}
+
+	// 1. 校验所有者并计算多余资金
+	contractAddr := GetContractAddress()
+	balance := QueryUTXOBalance(contractAddr, tokenID)
+	amount, err := PlanSweep(GetCaller(), cb.Owner, balance, tokenID, cb.ProtocolBalances)
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. 仅划转差额
+	success, _, errCode := BeginTransaction().
+		Transfer(contractAddr, to, tokenID, amount).
+		Finalize()
+	if !success {
+		return 0, NewContractError(errCode, "sweep transfer failed")
+	}
+
+	// 3. 发出事件
+	event := NewEvent("ExcessSwept")
+	event.AddStringField("token_id", string(tokenID))
+	event.AddAddressField("to", to)
+	event.AddUint64Field("amount", uint64(amount))
+	event.AddUint64Field("contract_balance", uint64(balance))
+	EmitEvent(event)
+
+	return amount, nil
+}
+
+// RegisterView 登记只读视图函数并启用 Multicall
+//
+// 🎯 **用途**：同 RegisterViewFunction，首次登记时为合约添加 "multicall" 特性
+//
+// **示例**：
+//
+//	token.RegisterView("BalanceOf", viewBalanceOf)
+//
+//	//export Multicall
+//	func Multicall() uint32 {
+//	    return token.Multicall()
+//	}
+func (cb *ContractBase) RegisterView(name string, fn ViewFunc) {
+	RegisterViewFunction(name, fn)
+	if !IsViewFunction(name) {
+		return
+	}
+	for _, f := range cb.Features {
+		if f == "multicall" {
+			return
+		}
+	}
+	cb.AddFeature("multicall")
+}
+
+// Multicall 执行标准批量查询导出函数，见 HandleMulticall
+func (cb *ContractBase) Multicall() uint32 {
+	return HandleMulticall()
+}
+
 // ==================== 宿主函数便捷方法 ====================
 // 以下方法是对全局宿主函数的便捷包装,允许通过合约实例调用
 
@@ -147,11 +231,10 @@ func (cb *ContractBase) EmitEvent(name string, data []byte) error {
 }
 
 // EmitLog 发出日志(简化版,实际应使用专门的日志宿主函数)
-func (cb *ContractBase) EmitLog(level, message string) error {
-	event := NewEvent("Log")
-	event.Data["level"] = level
-	event.Data["message"] = message
-	return EmitEvent(event)
+//
+// 秘密值使用 Secret 字段记录，见 log.go
+func (cb *ContractBase) EmitLog(level, message string, fields ...LogField) error {
+	return EmitLog(level, message, fields...)
 }
 
 // ==================== P1 HostABI 新增方法 ====================
@@ -161,56 +244,6 @@ func (cb *ContractBase) EmitLog(level, message string) error {
 
 // ==================== 通用辅助函数 ====================
 
-// GetString 从内存指针构造字符串
-//
-// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
-func GetString(ptr uint32, len uint32) string {
-	if ptr == 0 || len == 0 {
-		return ""
-	}
-	return string((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len:len]) //nolint:unsafeptr // WASM线性内存访问
-}
-
-// GetBytes 从内存指针获取字节数组
-//
-// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
-func GetBytes(ptr uint32, len uint32) []byte {
-	if ptr == 0 || len == 0 {
-		return nil
-	}
-	return (*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len:len] //nolint:unsafeptr // WASM线性内存访问
-}
-
-// AllocateString 分配字符串到WASM内存并返回指针和长度
-//
-// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
-func AllocateString(s string) (uint32, uint32) {
-	if len(s) == 0 {
-		return 0, 0
-	}
-	ptr := Malloc(uint32(len(s)))
-	if ptr == 0 {
-		return 0, 0
-	}
-	copy((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len(s)], s) //nolint:unsafeptr // WASM线性内存访问
-	return ptr, uint32(len(s))
-}
-
-// AllocateBytes 分配字节数组到WASM内存
-//
-// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
-func AllocateBytes(data []byte) (uint32, uint32) {
-	if len(data) == 0 {
-		return 0, 0
-	}
-	ptr := Malloc(uint32(len(data)))
-	if ptr == 0 {
-		return 0, 0
-	}
-	copy((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len(data)], data) //nolint:unsafeptr // WASM线性内存访问
-	return ptr, uint32(len(data))
-}
-
 // Uint64ToString 将uint64转换为字符串
 func Uint64ToString(n uint64) string {
 	if n == 0 {
@@ -231,19 +264,6 @@ func Uint64ToString(n uint64) string {
 	return string(digits)
 }
 
-// ParseUint64 从字符串解析uint64
-func ParseUint64(s string) uint64 {
-	var result uint64
-	for _, digit := range s {
-		if digit >= '0' && digit <= '9' {
-			result = result*10 + uint64(digit-'0')
-		} else {
-			break
-		}
-	}
-	return result
-}
-
 // ==================== 地址和哈希处理 ====================
 
 // AddressFromBytes 从字节数组创建地址
@@ -260,46 +280,19 @@ func (addr Address) ToBytes() []byte {
 	return addr[:]
 }
 
-// String 将地址转换为字符串（实现 fmt.Stringer 接口）
-func (addr Address) String() string {
-	return addr.ToString()
+// IsZero 判断是否为零地址（未设置的地址）
+func (addr Address) IsZero() bool {
+	return addr == Address{}
 }
 
-// AddressToString 将地址转换为 Base58Check 编码字符串
-//
-// 🎯 **架构对齐说明**：
-//   - 复用宿主 AddressManager.BytesToAddress 实现
-//   - 输出标准 Base58Check 格式（符合 pb/transaction.Address 规范）
-//   - 避免在 TinyGo 环境重复实现复杂编码逻辑
-//
-// 📋 **实现方式**：
-//   - 调用宿主函数 address_bytes_to_base58
-//   - 宿主侧委托给 AddressManager 进行编码
-//   - 不重复造轮子，完全复用统一规范实现
-func (addr Address) ToString() string {
-	// 分配缓冲区（Base58Check 地址最大 34 字符）
-	maxLen := uint32(64) // 预留足够空间
-	buffer := malloc(maxLen)
-	if buffer == 0 {
-		// 内存分配失败，回退到 hex 格式
-		return addr.ToHexString()
-	}
-
-	// 调用宿主函数进行 Base58Check 编码
-	addrPtr, _ := AllocateBytes(addr.ToBytes())
-	if addrPtr == 0 {
-		return addr.ToHexString()
-	}
-
-	actualLen := addressBytesToBase58(addrPtr, buffer, maxLen)
-	if actualLen == 0 {
-		// 编码失败，回退到 hex 格式
-		return addr.ToHexString()
-	}
+// Equals 判断两个地址是否相同（逐字节比较）
+func (addr Address) Equals(other Address) bool {
+	return addr == other
+}
 
-	// 读取 Base58 字符串
-	base58Bytes := GetBytes(buffer, actualLen)
-	return string(base58Bytes)
+// String 将地址转换为字符串（实现 fmt.Stringer 接口）
+func (addr Address) String() string {
+	return addr.ToString()
 }
 
 // ToHexString 将地址转换为十六进制字符串（调试用）
@@ -366,9 +359,9 @@ func (hash Hash) ToBytes() []byte {
 
 // ==================== JSON辅助函数 ====================
 
-// BuildJSONField 构建JSON字段
+// BuildJSONField 构建JSON字段（键与值按 JSON 字符串转义）
 func BuildJSONField(key, value string) string {
-	return `"` + key + `":"` + value + `"`
+	return `"` + escapeJSONString(key) + `":"` + escapeJSONString(value) + `"`
 }
 
 // BuildJSONObject 构建JSON对象
@@ -384,14 +377,14 @@ func BuildJSONObject(fields []string) string {
 	return result
 }
 
-// BuildJSONArray 构建JSON数组
+// BuildJSONArray 构建JSON字符串数组（元素按 JSON 字符串转义）
 func BuildJSONArray(items []string) string {
 	result := "["
 	for i, item := range items {
 		if i > 0 {
 			result += ","
 		}
-		result += `"` + item + `"`
+		result += `"` + escapeJSONString(item) + `"`
 	}
 	result += "]"
 	return result
@@ -419,8 +412,217 @@ func (cp *ContractParams) GetString() string {
 	return string(cp.data)
 }
 
-// ParseJSON 简单的JSON字段提取（字符串值）
+// ParseJSON 提取字符串字段
+//
+// 🎯 **用途**：读取调用参数中的字符串值，支持点分路径读取嵌套对象与数组元素
+//
+// **参数**：
+//   - key: 字段名；含 "." 时按路径逐层查找，数字段为数组下标（如 "metadata.name"、"documents.0.hash"）。
+//     顶层存在同名字段（键本身含 "."）时优先返回顶层字段
+//
+// **返回**：字符串值（已还原转义字符）；字段不存在或不是字符串时返回空字符串
+//
+// **示例**：
+//
+//	// {"metadata":{"name":"X","author":"Y"},"documents":[{"hash":"0xab"}]}
+//	params.ParseJSON("metadata.name")    // "X"
+//	params.ParseJSON("documents.0.hash") // "0xab"
+//	params.ParseJSON("name")             // ""（只匹配顶层字段，不会误取嵌套对象中的同名键）
+//
+// **注意**：参数不是合法 JSON 对象时，退回按 `"key":"` 文本匹配的旧行为（不支持路径与转义）
 func (cp *ContractParams) ParseJSON(key string) string {
+	raw, ok := cp.lookupJSON(key)
+	if !ok {
+		if _, valid := jsonObjectMembers(string(cp.data)); valid {
+			return ""
+		}
+		return cp.scanJSONString(key)
+	}
+	value, _ := jsonString(raw)
+	return value
+}
+
+// ParseJSONObject 提取嵌套对象字段，返回以该对象为参数的解析器
+//
+// **参数**：
+//   - key: 字段名，支持与 ParseJSON 相同的点分路径
+//
+// **返回**：子对象的 *ContractParams；字段不存在或不是对象时返回空参数（IsEmpty 为 true），可直接链式读取
+//
+// **示例**：
+//
+//	metadata := params.ParseJSONObject("metadata")
+//	name := metadata.ParseJSON("name")
+func (cp *ContractParams) ParseJSONObject(key string) *ContractParams {
+	raw, ok := cp.lookupJSON(key)
+	if !ok {
+		return NewContractParams(nil)
+	}
+	if _, isObject := jsonObjectMembers(raw); !isObject {
+		return NewContractParams(nil)
+	}
+	return NewContractParams([]byte(raw))
+}
+
+// ParseJSONStringArray 提取字符串数组字段
+//
+// **参数**：
+//   - key: 字段名，支持与 ParseJSON 相同的点分路径
+//
+// **返回**：按顺序排列的字符串（已还原转义字符）；空数组返回长度为 0 的切片；
+// 字段不存在、不是数组或任一元素不是字符串时返回 nil
+//
+// **示例**：
+//
+//	// {"recipients": ["addr1", "addr2"]}
+//	recipients := params.ParseJSONStringArray("recipients") // ["addr1", "addr2"]
+func (cp *ContractParams) ParseJSONStringArray(key string) []string {
+	items, ok := cp.lookupJSONArray(key)
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		value, isString := jsonString(item)
+		if !isString {
+			return nil
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+// ParseJSONUintArray 提取非负整数数组字段
+//
+// **参数**：
+//   - key: 字段名，支持与 ParseJSON 相同的点分路径
+//
+// **返回**：按顺序排列的整数，元素可为数字或数字字符串（如 100 或 "100"）；空数组返回长度为 0 的切片；
+// 字段不存在、不是数组或任一元素不是非负整数（含超过 uint64）时返回 nil
+//
+// **示例**：
+//
+//	// {"amounts": [100, "200"]}
+//	amounts := params.ParseJSONUintArray("amounts") // [100, 200]
+func (cp *ContractParams) ParseJSONUintArray(key string) []uint64 {
+	items, ok := cp.lookupJSONArray(key)
+	if !ok {
+		return nil
+	}
+	result := make([]uint64, 0, len(items))
+	for _, item := range items {
+		if s, quoted := jsonPlainString(item); quoted {
+			item = s
+		}
+		value, isUint := parseDecimalUint(item)
+		if !isUint {
+			return nil
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+// ParseJSONObjectArray 提取对象数组字段，每个对象返回一个参数解析器
+//
+// **参数**：
+//   - key: 字段名，支持与 ParseJSON 相同的点分路径
+//
+// **返回**：按顺序排列的 *ContractParams；空数组返回长度为 0 的切片；
+// 字段不存在、不是数组或任一元素不是对象时返回 nil
+//
+// **示例**：
+//
+//	// {"transfers": [{"to": "addr1", "amount": 100}]}
+//	for _, t := range params.ParseJSONObjectArray("transfers") {
+//	    to, amount := t.ParseJSON("to"), t.ParseJSONInt("amount")
+//	}
+func (cp *ContractParams) ParseJSONObjectArray(key string) []*ContractParams {
+	items, ok := cp.lookupJSONArray(key)
+	if !ok {
+		return nil
+	}
+	result := make([]*ContractParams, 0, len(items))
+	for _, item := range items {
+		if _, isObject := jsonObjectMembers(item); !isObject {
+			return nil
+		}
+		result = append(result, NewContractParams([]byte(item)))
+	}
+	return result
+}
+
+// HasJSONField 判断字段是否存在且不为 null
+//
+// **参数**：
+//   - key: 字段名，支持与 ParseJSON 相同的点分路径
+//
+// **返回**：字段存在且值不是 null 时为 true；参数不是合法 JSON 对象时为 false
+//
+// **示例**：
+//
+//	// 可选字段：缺失时使用默认值，显式传 0 时使用 0
+//	waiting := defaultWaiting
+//	if params.HasJSONField("waiting_period") {
+//	    w, err := params.ParseDuration("waiting_period")
+//	    ...
+//	}
+func (cp *ContractParams) HasJSONField(key string) bool {
+	raw, ok := cp.lookupJSON(key)
+	return ok && raw != "null"
+}
+
+// lookupJSONArray 按字段名或点分路径查找数组值，返回各元素的原始文本
+func (cp *ContractParams) lookupJSONArray(key string) ([]string, bool) {
+	raw, ok := cp.lookupJSON(key)
+	if !ok {
+		return nil, false
+	}
+	return jsonArrayElements(raw)
+}
+
+// parseDecimalUint 解析十进制非负整数文本，含非数字字符、为空或超过 uint64 时返回 false
+func parseDecimalUint(s string) (uint64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	var value uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		digit := uint64(c - '0')
+		if value > (^uint64(0)-digit)/10 {
+			return 0, false
+		}
+		value = value*10 + digit
+	}
+	return value, true
+}
+
+// lookupJSON 按字段名或点分路径查找值的原始文本
+func (cp *ContractParams) lookupJSON(key string) (string, bool) {
+	members, ok := jsonObjectMembers(string(cp.data))
+	if !ok {
+		return "", false
+	}
+	if raw, ok := members[key]; ok {
+		return raw, true
+	}
+	path := splitJSONPath(key)
+	if len(path) < 2 {
+		return "", false
+	}
+	raw, ok := members[path[0]]
+	if !ok {
+		return "", false
+	}
+	return jsonLookup(raw, path[1:])
+}
+
+// scanJSONString 按 "key":" 文本匹配提取字符串字段（参数不是合法 JSON 对象时使用）
+func (cp *ContractParams) scanJSONString(key string) string {
 	data := string(cp.data)
 	keyPattern := `"` + key + `":"`
 
@@ -468,7 +670,82 @@ func (cp *ContractParams) GetStringOr(key, defaultValue string) string {
 }
 
 // ParseJSONInt 从 JSON 中提取整数字段
+//
+// key 支持与 ParseJSON 相同的点分路径；字段不存在或不是数字时返回 0。
+// 负数（"-5" 解析为 0）与格式错误的值同样返回 0，需要区分时使用 ParseJSONIntChecked
 func (cp *ContractParams) ParseJSONInt(key string) uint64 {
+	raw, ok := cp.lookupJSON(key)
+	if !ok {
+		if _, valid := jsonObjectMembers(string(cp.data)); valid {
+			return 0
+		}
+		return cp.scanJSONInt(key)
+	}
+	var result uint64
+	for i := 0; i < len(raw) && raw[i] >= '0' && raw[i] <= '9'; i++ {
+		result = result*10 + uint64(raw[i]-'0')
+	}
+	return result
+}
+
+// ParseJSONIntChecked 从 JSON 中提取有符号整数字段，并报告值是否为合法整数
+//
+// 与 ParseJSONInt 不同，负数按负值返回，格式错误或越界的值不会被截断为 0 或数字前缀。
+// key 支持与 ParseJSON 相同的点分路径；值可以是 JSON 数字或十进制字符串（如 "800"）。
+//
+// **返回**：
+//   - value: 解析出的整数；字段缺失或为 null 时为 0
+//   - ok: 值不是整数（含小数、指数、非数字字符）、超出 int64 范围或参数不是 JSON 对象时为 false
+//
+// **示例**：
+//
+//	minMembers, ok := params.ParseJSONIntChecked("min_members")
+//	if !ok || minMembers < 0 {
+//	    return framework.ERROR_INVALID_PARAMS
+//	}
+func (cp *ContractParams) ParseJSONIntChecked(key string) (value int64, ok bool) {
+	if len(cp.data) == 0 {
+		return 0, true
+	}
+	raw, found := cp.lookupJSON(key)
+	if !found {
+		_, valid := jsonObjectMembers(string(cp.data))
+		return 0, valid
+	}
+	if raw == "null" {
+		return 0, true
+	}
+	if s, quoted := jsonPlainString(raw); quoted {
+		raw = s
+	}
+	return parseDecimalInt(raw)
+}
+
+// parseDecimalInt 解析十进制有符号整数文本（可带前导 "-"），格式错误或超出 int64 范围时返回 false
+func parseDecimalInt(s string) (int64, bool) {
+	negative := len(s) > 0 && s[0] == '-'
+	if negative {
+		s = s[1:]
+	}
+	magnitude, ok := parseDecimalUint(s)
+	if !ok {
+		return 0, false
+	}
+	const maxInt64 = uint64(1<<63 - 1)
+	if negative {
+		if magnitude > maxInt64+1 {
+			return 0, false
+		}
+		return -int64(magnitude-1) - 1, true
+	}
+	if magnitude > maxInt64 {
+		return 0, false
+	}
+	return int64(magnitude), true
+}
+
+// scanJSONInt 按 "key": 文本匹配提取整数字段（参数不是合法 JSON 对象时使用）
+func (cp *ContractParams) scanJSONInt(key string) uint64 {
 	data := string(cp.data)
 	// 查找 "key": 或 "key":（数字不带引号）
 	keyPattern1 := `"` + key + `":`
@@ -534,42 +811,6 @@ func (cp *ContractParams) IsEmpty() bool {
 
 // ==================== 地址解析工具 ====================
 
-// ParseAddressBase58 从 Base58Check 编码字符串解析地址
-//
-// 🎯 **架构对齐说明**：
-//   - 复用宿主 AddressManager.AddressToBytes 实现
-//   - 支持标准 Base58Check 格式（符合 pb/transaction.Address 规范）
-//   - 避免在 TinyGo 环境重复实现复杂解码逻辑
-//
-// 📋 **输入格式**：
-//   - "Cf1Kes6snEUeykiJJgrAtKPNPrAzPdPmSn" -> Address{20字节}
-func ParseAddressBase58(base58Str string) (Address, error) {
-	if base58Str == "" {
-		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "address string cannot be empty")
-	}
-
-	// 分配结果缓冲区（20 字节）
-	resultPtr := malloc(20)
-	if resultPtr == 0 {
-		return Address{}, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate memory for address")
-	}
-
-	// 调用宿主函数进行 Base58Check 解码
-	base58Ptr, base58Len := AllocateString(base58Str)
-	if base58Ptr == 0 {
-		return Address{}, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate memory for base58 string")
-	}
-
-	success := addressBase58ToBytes(base58Ptr, base58Len, resultPtr)
-	if success == 0 {
-		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "invalid base58 address format")
-	}
-
-	// 读取 20 字节地址
-	addressBytes := GetBytes(resultPtr, 20)
-	return AddressFromBytes(addressBytes), nil
-}
-
 // ⚠️ **已删除**：ParseAddressFromHex 和 hexCharToNibble
 // 原因：不符合统一地址规范（应使用 Base58Check）
 // 替代：使用 ParseAddressBase58
@@ -580,6 +821,8 @@ func ParseAddressBase58(base58Str string) (Address, error) {
 type ContractError struct {
 	Code    uint32
 	Message string
+	// Field 出错的参数名（参数校验错误时设置，见 NewFieldError）
+	Field string
 }
 
 // Error 实现error接口
@@ -595,6 +838,32 @@ func NewContractError(code uint32, message string) *ContractError {
 	}
 }
 
+// NewFieldError 创建指明出错参数的合约错误
+//
+// Message 为 "{field}: {message}"；Detail 返回的结构化信息包含 field，
+// 合约可通过 SetReturnJSON(err.Detail()) 告知调用方具体是哪个参数不合法。
+func NewFieldError(code uint32, field, message string) *ContractError {
+	return &ContractError{
+		Code:    code,
+		Message: field + ": " + message,
+		Field:   field,
+	}
+}
+
+// Detail 返回结构化错误信息：{"error": 错误码名称, "message": ..., "field": ...}
+//
+// 未指明参数的错误不含 field。
+func (ce *ContractError) Detail() map[string]interface{} {
+	detail := map[string]interface{}{
+		"error":   ErrorCodeToString(ce.Code),
+		"message": ce.Message,
+	}
+	if ce.Field != "" {
+		detail["field"] = ce.Field
+	}
+	return detail
+}
+
 // WrapError 封装错误为合约错误
 func WrapError(code uint32, err error) *ContractError {
 	if err == nil {
@@ -637,6 +906,11 @@ func (e *Event) AddUint64Field(key string, value uint64) {
 	e.Data[key] = value
 }
 
+// AddIntField 添加整数字段（AddUint64Field 的别名）
+func (e *Event) AddIntField(key string, value uint64) {
+	e.AddUint64Field(key, value)
+}
+
 // AddAddressField 添加地址字段
 func (e *Event) AddAddressField(key string, addr Address) {
 	e.Data[key] = addr.ToString()
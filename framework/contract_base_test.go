@@ -69,6 +69,10 @@ func TestErrorCodes(t *testing.T) {
 		{"ERROR_INVALID_PARAMS", ERROR_INVALID_PARAMS},
 		{"ERROR_UNAUTHORIZED", ERROR_UNAUTHORIZED},
 		{"ERROR_EXECUTION_FAILED", ERROR_EXECUTION_FAILED},
+		{"ERROR_QUOTA_EXCEEDED", ERROR_QUOTA_EXCEEDED},
+		{"ERROR_SLIPPAGE_EXCEEDED", ERROR_SLIPPAGE_EXCEEDED},
+		{"ERROR_PAUSED", ERROR_PAUSED},
+		{"ERROR_RATE_LIMITED", ERROR_RATE_LIMITED},
 	}
 
 	// 验证错误码唯一性
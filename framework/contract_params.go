@@ -0,0 +1,72 @@
+package framework
+
+// ==================== 变长宿主数据读取 ====================
+//
+// 宿主函数（get_contract_init_params、state_query_by_prefix 等）将数据写入合约提供的缓冲区并返回数据长度。
+// 数据超过缓冲区时宿主可能返回完整长度（大于缓冲区），也可能截断并返回缓冲区大小，
+// readHostBuffer 对两种情况都按需扩大缓冲区重读，直到读到完整数据或超过上限。
+
+const (
+	// MAX_CONTRACT_PARAMS_SIZE 合约调用参数的最大字节数（1MB），超过时 GetContractParams 返回空参数
+	MAX_CONTRACT_PARAMS_SIZE = 1 << 20
+
+	// contractParamsInitialBufSize 首次读取参数的缓冲区大小，覆盖绝大多数调用
+	contractParamsInitialBufSize = 8192
+)
+
+// hostBufferReader 以 bufSize 大小的缓冲区调用一次宿主函数
+//
+// 返回：
+//   - data: 缓冲区中的有效数据（至多 bufSize 字节）
+//   - n: 宿主返回的数据长度；n > bufSize 表示完整长度，n == bufSize 视为可能已截断，0 表示没有数据
+//   - err: 缓冲区分配失败等读取错误，与"没有数据"（n == 0）区分，原样返回给调用方
+type hostBufferReader func(bufSize uint32) (data []byte, n uint32, err error)
+
+// readContractParams 读取完整的合约调用参数
+//
+// 返回：
+//   - read 返回的错误（如缓冲区分配失败的 ERROR_EXECUTION_FAILED）
+//   - ERROR_QUOTA_EXCEEDED: 参数超过 MAX_CONTRACT_PARAMS_SIZE，不返回截断的数据
+//   - ERROR_EXECUTION_FAILED: 宿主返回的数据与报告的长度不一致
+func readContractParams(read hostBufferReader) ([]byte, error) {
+	return readHostBuffer(read, contractParamsInitialBufSize, MAX_CONTRACT_PARAMS_SIZE, "contract params")
+}
+
+// readHostBuffer 读取完整的变长宿主数据
+//
+// 从 initialSize 开始读取；宿主报告的长度大于缓冲区时按该长度重读，
+// 恰好填满缓冲区时加倍重读，直到读到完整数据。
+//
+// 返回：
+//   - read 返回的错误（如缓冲区分配失败），不当作没有数据
+//   - ERROR_QUOTA_EXCEEDED: 数据超过 maxSize，不返回截断的数据
+//   - ERROR_EXECUTION_FAILED: 宿主返回的数据与报告的长度不一致
+func readHostBuffer(read hostBufferReader, initialSize, maxSize uint32, what string) ([]byte, error) {
+	bufSize := min(initialSize, maxSize+1)
+	for {
+		data, n, err := read(bufSize)
+		if err != nil {
+			return nil, err
+		}
+		if n > maxSize {
+			return nil, NewContractError(ERROR_QUOTA_EXCEEDED, what+" exceed size limit")
+		}
+		if n < bufSize {
+			if uint32(len(data)) < n {
+				return nil, NewContractError(ERROR_EXECUTION_FAILED, what+" shorter than reported length")
+			}
+			return data[:n], nil
+		}
+
+		// 数据可能未读完：按报告长度或加倍扩大缓冲区（多留 1 字节以区分"恰好填满"与"被截断"），
+		// 最大读到 maxSize+1 字节，仍填满即判定超限
+		next := bufSize * 2
+		if n > bufSize {
+			next = n + 1
+		}
+		if next > maxSize+1 {
+			next = maxSize + 1
+		}
+		bufSize = next
+	}
+}
@@ -0,0 +1,134 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// largeParams 构造约 size 字节的 JSON 参数，最后一个键为 last_key
+func largeParams(size int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"recipients":[`)
+	for i := 0; b.Len() < size; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"to":"recipient_%05d","amount":%d}`, i, 1000+i)
+	}
+	b.WriteString(`],"last_key":"tail"}`)
+	return []byte(b.String())
+}
+
+// TestGetContractParamsLargePayload 测试超过初始缓冲区（8KB）的参数被完整读取，最后一个键仍可解析
+func TestGetContractParamsLargePayload(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	payload := largeParams(3 * contractParamsInitialBufSize)
+
+	var lastKey string
+	var rawLen int
+	res := host.Invoke(Address{0x42}, payload, func() uint32 {
+		params := GetContractParams()
+		lastKey = params.ParseJSON("last_key")
+		rawLen = len(params.GetRawData())
+		return SUCCESS
+	})
+	if res.Code != SUCCESS {
+		t.Fatalf("Invoke() code = %d", res.Code)
+	}
+	if lastKey != "tail" || rawLen != len(payload) {
+		t.Errorf("last_key = %q, raw length %d, want \"tail\" and %d", lastKey, rawLen, len(payload))
+	}
+}
+
+// TestReadContractParamsTruncatingHost 测试宿主截断并返回缓冲区大小时按加倍缓冲区重读
+func TestReadContractParamsTruncatingHost(t *testing.T) {
+	for _, size := range []int{0, 100, contractParamsInitialBufSize, contractParamsInitialBufSize + 1, 40000} {
+		payload := []byte(strings.Repeat("x", size))
+		var bufSizes []uint32
+		data, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+			bufSizes = append(bufSizes, bufSize)
+			n := min(uint32(len(payload)), bufSize)
+			return payload[:n], n, nil
+		})
+		if err != nil || len(data) != size {
+			t.Errorf("size %d: readContractParams() = %d bytes, %v (buffers %v)", size, len(data), err, bufSizes)
+		}
+	}
+}
+
+// TestReadContractParamsLimit 测试超过 MAX_CONTRACT_PARAMS_SIZE 的参数返回错误而不是截断
+func TestReadContractParamsLimit(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	var readErr error
+	var params *ContractParams
+	host.Invoke(Address{0x42}, largeParams(MAX_CONTRACT_PARAMS_SIZE+1), func() uint32 {
+		_, readErr = ReadContractParams()
+		params = GetContractParams()
+		return SUCCESS
+	})
+	if ce, ok := readErr.(*ContractError); !ok || ce.Code != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("ReadContractParams() error = %v, want ERROR_QUOTA_EXCEEDED", readErr)
+	}
+	if len(params.GetRawData()) != 0 || params.ParseJSON("last_key") != "" {
+		t.Errorf("GetContractParams() over limit = %d bytes, want empty", len(params.GetRawData()))
+	}
+
+	// 截断型宿主同样判定超限
+	payload := []byte(strings.Repeat("x", MAX_CONTRACT_PARAMS_SIZE+1))
+	_, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+		n := min(uint32(len(payload)), bufSize)
+		return payload[:n], n, nil
+	})
+	if ce, ok := err.(*ContractError); !ok || ce.Code != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("truncating host over limit error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	exact := payload[:MAX_CONTRACT_PARAMS_SIZE]
+	data, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+		n := min(uint32(len(exact)), bufSize)
+		return exact[:n], n, nil
+	})
+	if err != nil || len(data) != MAX_CONTRACT_PARAMS_SIZE {
+		t.Errorf("params of exactly MAX_CONTRACT_PARAMS_SIZE = %d bytes, %v", len(data), err)
+	}
+}
+
+// TestReadContractParamsAllocationFailure 测试缓冲区分配失败返回 ERROR_EXECUTION_FAILED，不当作没有参数
+func TestReadContractParamsAllocationFailure(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	var readErr error
+	var params *ContractParams
+	host.Invoke(Address{0x42}, []byte(`{"amount":"100"}`), func() uint32 {
+		restore := SetHostInterceptor(hostCallFailer{name: HOST_CALL_MALLOC})
+		defer restore()
+		_, readErr = ReadContractParams()
+		params = GetContractParams()
+		return SUCCESS
+	})
+	if ce, ok := readErr.(*ContractError); !ok || ce.Code != ERROR_EXECUTION_FAILED {
+		t.Errorf("ReadContractParams() error = %v, want ERROR_EXECUTION_FAILED", readErr)
+	}
+	if len(params.GetRawData()) != 0 {
+		t.Errorf("GetContractParams() after allocation failure = %d bytes, want empty", len(params.GetRawData()))
+	}
+
+	// 分配失败在任意一次重读时都原样返回
+	calls := 0
+	_, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+		calls++
+		if calls == 2 {
+			return nil, 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate contract params buffer")
+		}
+		return make([]byte, bufSize), bufSize + 1, nil
+	})
+	if ce, ok := err.(*ContractError); !ok || ce.Code != ERROR_EXECUTION_FAILED || calls != 2 {
+		t.Errorf("allocation failure on re-read: error = %v after %d calls, want ERROR_EXECUTION_FAILED after 2", err, calls)
+	}
+}
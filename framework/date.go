@@ -0,0 +1,67 @@
+package framework
+
+// ==================== 日期换算 ====================
+//
+// TinyGo WASM 不提供 time 包的时区与日历计算，月度限额、年度额度等按自然月/年记账的合约
+// 使用本文件的函数将 Unix 时间戳换算为公历日期。
+//
+// 统一按 UTC 计算，使用外推格里高利历（proleptic Gregorian calendar），与 time.Unix(ts, 0).UTC() 一致。
+
+// TimestampToDate 将 Unix 时间戳（秒）换算为 UTC 公历日期
+//
+// 🎯 **用途**：需要按日统计的合约（每日限额、日结报表等）
+//
+// **返回**：
+//   - year: 公历年份（如 2025）
+//   - month: 月份，1-12
+//   - day: 日，1-31
+//
+// **注意**：
+//   - 每天 00:00:00 属于当天，23:59:59 仍属于当天
+//   - 闰年按格里高利历规则：能被 4 整除且不能被 100 整除，或能被 400 整除（2000 年闰、2100 年平）
+//
+// **示例**：
+//
+//	year, month, day := framework.TimestampToDate(framework.GetTimestamp())
+//	// 1709164800 → 2024, 2, 29
+func TimestampToDate(ts uint64) (year, month, day int) {
+	// days-from-civil 逆算（Howard Hinnant），以 0000-03-01 为纪元，每 400 年（146097 天）为一个周期
+	z := ts/86400 + 719468
+	era := z / 146097
+	doe := z - era*146097                                  // 周期内第几天 [0, 146096]
+	yoe := (doe - doe/1460 + doe/36524 - doe/146096) / 365 // 周期内第几年 [0, 399]
+	doy := doe - (365*yoe + yoe/4 - yoe/100)               // 从 3 月 1 日起的年内第几天 [0, 365]
+	mp := (5*doy + 2) / 153                                // 从 3 月起的月份 [0, 11]
+
+	year = int(yoe + era*400)
+	day = int(doy - (153*mp+2)/5 + 1)
+	if mp >= 10 { // 1月、2月属于下一年
+		return year + 1, int(mp) - 9, day
+	}
+	return year, int(mp) + 3, day
+}
+
+// TimestampToYearMonth 将 Unix 时间戳（秒）换算为 UTC 公历年月，格式 YYYYMM（如 "202502"）
+//
+// 🎯 **用途**：按自然月记账的状态键（月度缴费统计、月度限额等）
+//
+// **示例**：
+//
+//	stateID := "month_stat_" + memberID + "_" + framework.TimestampToYearMonth(framework.GetTimestamp())
+//	// 1738368000（2025-02-01 00:00:00）→ "202502"
+func TimestampToYearMonth(ts uint64) string {
+	year, month, _ := TimestampToDate(ts)
+	return padDigits(uint64(year), 4) + padDigits(uint64(month), 2)
+}
+
+// padDigits 十进制数字串，不足 width 位时前补 0
+func padDigits(n uint64, width int) string {
+	var buf [20]byte
+	i := len(buf)
+	for n > 0 || len(buf)-i < width {
+		i--
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return string(buf[i:])
+}
@@ -0,0 +1,65 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimestampToDate 测试公历日期换算（月末、年末、闰年 2 月、世纪年）
+func TestTimestampToDate(t *testing.T) {
+	tests := []struct {
+		ts               uint64
+		year, month, day int
+	}{
+		{0, 1970, 1, 1},
+		{86399, 1970, 1, 1},
+		{86400, 1970, 1, 2},
+		{1704067199, 2023, 12, 31}, // 2023-12-31 23:59:59
+		{1704067200, 2024, 1, 1},
+		{1709164800, 2024, 2, 29}, // 闰年
+		{1709251200, 2024, 3, 1},
+		{1740700800, 2025, 2, 28}, // 平年
+		{1740787200, 2025, 3, 1},
+		{951782400, 2000, 2, 29},  // 2000 年能被 400 整除，为闰年
+		{4107542399, 2100, 2, 28}, // 2100 年不是闰年
+		{4107542400, 2100, 3, 1},
+	}
+	for _, tt := range tests {
+		year, month, day := TimestampToDate(tt.ts)
+		if year != tt.year || month != tt.month || day != tt.day {
+			t.Errorf("TimestampToDate(%d) = %d-%d-%d, want %d-%d-%d", tt.ts, year, month, day, tt.year, tt.month, tt.day)
+		}
+	}
+
+	// 与 time 包逐日对照（1970-2200，覆盖每个月末与闰日）
+	for ts := uint64(0); ts < 7258118400; ts += 86400 {
+		want := time.Unix(int64(ts), 0).UTC()
+		year, month, day := TimestampToDate(ts + 86399)
+		if year != want.Year() || month != int(want.Month()) || day != want.Day() {
+			t.Fatalf("TimestampToDate(%d) = %d-%d-%d, want %s", ts+86399, year, month, day, want.Format("2006-01-02"))
+		}
+	}
+}
+
+// TestTimestampToYearMonth 测试年月字符串：跨月、跨年与零填充
+func TestTimestampToYearMonth(t *testing.T) {
+	tests := []struct {
+		ts   uint64
+		want string
+	}{
+		{0, "197001"},
+		{1706745599, "202401"}, // 2024-01-31 23:59:59
+		{1706745600, "202402"}, // 2024-02-01 00:00:00
+		{1709251199, "202402"}, // 2024-02-29 23:59:59
+		{1735689599, "202412"}, // 2024-12-31 23:59:59
+		{1735689600, "202501"}, // 2025-01-01 00:00:00
+		{253402300799, "999912"},
+	}
+	for _, tt := range tests {
+		if got := TimestampToYearMonth(tt.ts); got != tt.want {
+			t.Errorf("TimestampToYearMonth(%d) = %q, want %q", tt.ts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package framework
+
+import "strings"
+
+// 导出函数显示清单（钱包渲染）
+//
+// 钱包在确认合约调用前需要把导出函数与参数展示给用户（"批准成员 Cf1... 加入计划 相互宝互助计划"）。
+// 合约在 init 中用 RegisterFunction 登记导出函数的多语言名称、参数名称与显示提示，
+// 标准导出函数 GetDisplayManifest 按请求的语言返回合并后的清单：
+//   - 名称按 locale 匹配：完全匹配 → 同语言（zh-TW 回退到 zh-CN）→ DEFAULT_DISPLAY_LOCALE → 任一已登记语言 → 函数/参数名本身
+//   - 显示提示告诉钱包如何格式化参数值：代币金额按代币精度、地址按 Base58、时间戳按本地时间等
+//
+// 显示清单只影响展示，不参与参数解析与校验。
+
+// 参数显示提示类型
+const (
+	// HINT_AMOUNT 代币金额，按 TokenRef 指向的代币精度显示
+	HINT_AMOUNT = "AMOUNT"
+	// HINT_ADDRESS 账户地址
+	HINT_ADDRESS = "ADDRESS"
+	// HINT_TIMESTAMP Unix 时间戳（秒）
+	HINT_TIMESTAMP = "TIMESTAMP"
+	// HINT_DURATION_SECONDS 时长（秒）
+	HINT_DURATION_SECONDS = "DURATION_SECONDS"
+	// HINT_BASIS_POINTS 基点（10000 = 100%）
+	HINT_BASIS_POINTS = "BASIS_POINTS"
+	// HINT_HASH 哈希或内容摘要
+	HINT_HASH = "HASH"
+)
+
+const (
+	// DISPLAY_MANIFEST_METHOD 显示清单的标准导出函数名
+	DISPLAY_MANIFEST_METHOD = "GetDisplayManifest"
+	// DEFAULT_DISPLAY_LOCALE 请求的语言没有对应名称时的回退语言
+	DEFAULT_DISPLAY_LOCALE = "en-US"
+)
+
+// Labels 多语言名称（locale → 名称），如 {"zh-CN": "批准成员", "en-US": "Approve member"}
+type Labels map[string]string
+
+// DisplayHint 参数显示提示
+type DisplayHint struct {
+	// Kind 提示类型（HINT_*），空表示按原始值显示
+	Kind string
+	// TokenRef 金额所属代币（仅 HINT_AMOUNT）：
+	//   - 同一函数中代币ID参数的参数名，如 "token_id"
+	//   - 或 "视图函数名.字段名"，如 "GetPlanInfo.token_id"，钱包以相同参数调用该视图函数读取代币ID
+	TokenRef string
+}
+
+// AmountHint 代币金额提示
+//
+// **参数**：
+//   - tokenRef: 金额所属代币的引用（见 DisplayHint.TokenRef）
+func AmountHint(tokenRef string) DisplayHint {
+	return DisplayHint{Kind: HINT_AMOUNT, TokenRef: tokenRef}
+}
+
+// Hint 不需要附加信息的显示提示（HINT_ADDRESS、HINT_TIMESTAMP 等）
+func Hint(kind string) DisplayHint {
+	return DisplayHint{Kind: kind}
+}
+
+// FunctionParam 导出函数参数的显示信息
+type FunctionParam struct {
+	// Name 参数名（JSON 字段名）
+	Name string
+	// Type 参数类型：string、uint64、address、bool、json
+	Type string
+	// Labels 多语言名称
+	Labels Labels
+	// Hint 显示提示
+	Hint DisplayHint
+}
+
+// Param 构建参数显示信息
+//
+// **参数**：
+//   - name / typ: 参数名与类型
+//   - labels: 多语言名称
+//   - hint: 显示提示（可选，最多取第一个）
+//
+// **示例**：
+//
+//	framework.Param("cap", "uint64", framework.Labels{"zh-CN": "分摊上限", "en-US": "Cap"},
+//	    framework.AmountHint("GetPlanInfo.token_id"))
+func Param(name, typ string, labels Labels, hint ...DisplayHint) FunctionParam {
+	p := FunctionParam{Name: name, Type: typ, Labels: labels}
+	if len(hint) > 0 {
+		p.Hint = hint[0]
+	}
+	return p
+}
+
+// FunctionManifest 导出函数的显示信息
+type FunctionManifest struct {
+	// Name 导出函数名
+	Name string
+	// Labels 多语言名称
+	Labels Labels
+	// Params 参数（按登记顺序）
+	Params []FunctionParam
+}
+
+var (
+	functionManifests     = map[string]FunctionManifest{}
+	functionManifestOrder []string
+)
+
+// RegisterFunction 登记导出函数的显示信息
+//
+// 🎯 **用途**：声明钱包确认调用时展示的函数名称、参数名称与显示提示，通常在合约包的 init 中调用
+//
+// **参数**：
+//   - name: 导出函数名，重复登记时覆盖
+//   - labels: 多语言名称
+//   - params: 参数显示信息（见 Param）
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterFunction("ApproveMember",
+//	        framework.Labels{"zh-CN": "批准成员", "en-US": "Approve member"},
+//	        framework.Param("plan_id", "string", framework.Labels{"zh-CN": "计划", "en-US": "Plan"}),
+//	        framework.Param("member", "address", framework.Labels{"zh-CN": "成员", "en-US": "Member"},
+//	            framework.Hint(framework.HINT_ADDRESS)),
+//	    )
+//	}
+//
+// **注意**：登记的 TokenRef 等引用在登记时不校验（视图函数可能稍后登记），
+// 由 ValidateDisplayManifest 统一校验，建议在合约测试中调用
+func RegisterFunction(name string, labels Labels, params ...FunctionParam) {
+	if name == "" {
+		return
+	}
+	if _, ok := functionManifests[name]; !ok {
+		functionManifestOrder = append(functionManifestOrder, name)
+	}
+	functionManifests[name] = FunctionManifest{Name: name, Labels: labels, Params: append([]FunctionParam(nil), params...)}
+}
+
+// GetFunctionManifest 查询导出函数的显示信息，未登记时返回 false
+func GetFunctionManifest(name string) (FunctionManifest, bool) {
+	f, ok := functionManifests[name]
+	return f, ok
+}
+
+// FunctionManifests 按登记顺序返回已登记的导出函数显示信息
+func FunctionManifests() []FunctionManifest {
+	out := make([]FunctionManifest, 0, len(functionManifestOrder))
+	for _, name := range functionManifestOrder {
+		out = append(out, functionManifests[name])
+	}
+	return out
+}
+
+// ValidateDisplayManifest 校验已登记的显示清单
+//
+// **返回**：
+//   - error: 第一个无效登记，ERROR_INVALID_PARAMS：
+//     未知的提示类型、HINT_AMOUNT 用在非 uint64 参数上、TokenRef 为空或引用不存在的参数/视图函数
+func ValidateDisplayManifest() error {
+	for _, name := range functionManifestOrder {
+		if err := validateFunctionManifest(functionManifests[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFunctionManifest 校验单个导出函数的显示信息
+func validateFunctionManifest(f FunctionManifest) error {
+	for _, p := range f.Params {
+		where := f.Name + "." + p.Name
+		switch p.Hint.Kind {
+		case "", HINT_ADDRESS, HINT_TIMESTAMP, HINT_DURATION_SECONDS, HINT_BASIS_POINTS, HINT_HASH:
+			if p.Hint.TokenRef != "" {
+				return NewContractError(ERROR_INVALID_PARAMS, where+": token_ref is only valid for AMOUNT hints")
+			}
+		case HINT_AMOUNT:
+			if p.Type != "uint64" {
+				return NewContractError(ERROR_INVALID_PARAMS, where+": AMOUNT hint requires a uint64 parameter")
+			}
+			if err := validateTokenRef(f, p.Hint.TokenRef); err != nil {
+				return NewContractError(ERROR_INVALID_PARAMS, where+": "+err.Error())
+			}
+		default:
+			return NewContractError(ERROR_INVALID_PARAMS, where+": unknown hint "+p.Hint.Kind)
+		}
+	}
+	return nil
+}
+
+// validateTokenRef 校验金额的代币引用：同一函数的字符串参数，或已登记视图函数的字段
+func validateTokenRef(f FunctionManifest, ref string) error {
+	if ref == "" {
+		return NewContractError(ERROR_INVALID_PARAMS, "token_ref is required")
+	}
+	if dot := strings.IndexByte(ref, '.'); dot >= 0 {
+		view, field := ref[:dot], ref[dot+1:]
+		if field == "" || !IsViewFunction(view) {
+			return NewContractError(ERROR_INVALID_PARAMS, "token_ref "+ref+" does not name a view function field")
+		}
+		return nil
+	}
+	for _, p := range f.Params {
+		if p.Name == ref {
+			if p.Type != "string" {
+				return NewContractError(ERROR_INVALID_PARAMS, "token_ref "+ref+" is not a string parameter")
+			}
+			return nil
+		}
+	}
+	return NewContractError(ERROR_INVALID_PARAMS, "token_ref "+ref+" is not a parameter")
+}
+
+// DisplayManifest 按请求的语言汇总显示清单
+//
+// **参数**：
+//   - locale: 请求的语言（如 "zh-CN"），空时使用 DEFAULT_DISPLAY_LOCALE
+//
+// **返回**：
+//
+//	{
+//	  "locale": "zh-CN",
+//	  "functions": [
+//	    {
+//	      "name": "SetMemberCap", "label": "设置成员分摊上限", "view": false,
+//	      "params": [
+//	        {"name": "member", "type": "address", "label": "成员", "hint": "ADDRESS"},
+//	        {"name": "cap", "type": "uint64", "label": "分摊上限", "hint": "AMOUNT", "token_ref": "GetPlanInfo.token_id"}
+//	      ]
+//	    }
+//	  ]
+//	}
+func DisplayManifest(locale string) map[string]interface{} {
+	if locale == "" {
+		locale = DEFAULT_DISPLAY_LOCALE
+	}
+	functions := make([]interface{}, 0, len(functionManifestOrder))
+	for _, name := range functionManifestOrder {
+		f := functionManifests[name]
+		params := make([]interface{}, 0, len(f.Params))
+		for _, p := range f.Params {
+			item := map[string]interface{}{
+				"name":  p.Name,
+				"type":  p.Type,
+				"label": ResolveLabel(p.Labels, locale, p.Name),
+			}
+			if p.Hint.Kind != "" {
+				item["hint"] = p.Hint.Kind
+			}
+			if p.Hint.TokenRef != "" {
+				item["token_ref"] = p.Hint.TokenRef
+			}
+			params = append(params, item)
+		}
+		functions = append(functions, map[string]interface{}{
+			"name":   f.Name,
+			"label":  ResolveLabel(f.Labels, locale, f.Name),
+			"view":   IsViewFunction(f.Name),
+			"params": params,
+		})
+	}
+	return map[string]interface{}{
+		"locale":    locale,
+		"functions": functions,
+	}
+}
+
+// ViewDisplayManifest GetDisplayManifest 的视图函数
+//
+// 参数（JSON）：
+//
+//	{
+//	  "locale": "zh-CN" // 可选，默认 DEFAULT_DISPLAY_LOCALE
+//	}
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterViewFunction(framework.DISPLAY_MANIFEST_METHOD, framework.ViewDisplayManifest)
+//	}
+//
+//	//export GetDisplayManifest
+//	func GetDisplayManifest() uint32 {
+//	    return framework.ServeView(framework.DISPLAY_MANIFEST_METHOD)
+//	}
+func ViewDisplayManifest(params *ContractParams) (interface{}, error) {
+	return DisplayManifest(params.ParseJSON("locale")), nil
+}
+
+// ResolveLabel 按语言选取名称
+//
+// 回退顺序：完全匹配 → 同语言的其他地区 → DEFAULT_DISPLAY_LOCALE → 已登记语言中按字典序的第一个 → fallback
+func ResolveLabel(labels Labels, locale, fallback string) string {
+	if label, ok := labels[locale]; ok && label != "" {
+		return label
+	}
+	lang := localeLanguage(locale)
+	best := ""
+	for l, label := range labels {
+		if label != "" && localeLanguage(l) == lang && (best == "" || l < best) {
+			best = l
+		}
+	}
+	if best != "" {
+		return labels[best]
+	}
+	if label, ok := labels[DEFAULT_DISPLAY_LOCALE]; ok && label != "" {
+		return label
+	}
+	for l, label := range labels {
+		if label != "" && (best == "" || l < best) {
+			best = l
+		}
+	}
+	if best != "" {
+		return labels[best]
+	}
+	return fallback
+}
+
+// localeLanguage 取 locale 的语言部分（小写），如 "zh-CN" → "zh"
+func localeLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
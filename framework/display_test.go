@@ -0,0 +1,123 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterFunction("TestDisplayPay",
+		Labels{"zh-CN": "缴纳", "en-US": "Pay"},
+		Param("token_id", "string", Labels{"zh-CN": "代币", "en-US": "Token"}),
+		Param("amount", "uint64", Labels{"zh-CN": "金额", "en-US": "Amount"}, AmountHint("token_id")),
+		Param("payee", "address", nil, Hint(HINT_ADDRESS)),
+	)
+	// TestViewEcho 由 view_test.go 登记为视图函数
+	RegisterFunction("TestViewEcho", Labels{"en-US": "Echo"})
+}
+
+// TestResolveLabelFallback 测试名称的语言回退顺序
+func TestResolveLabelFallback(t *testing.T) {
+	labels := Labels{"zh-CN": "批准成员", "en-US": "Approve member", "ja-JP": "メンバー承認"}
+	tests := []struct {
+		name   string
+		labels Labels
+		locale string
+		want   string
+	}{
+		{"exact", labels, "zh-CN", "批准成员"},
+		{"same language", labels, "zh-TW", "批准成员"},
+		{"language only", labels, "ja", "メンバー承認"},
+		{"case-insensitive language", labels, "EN-gb", "Approve member"},
+		{"default locale", labels, "fr-FR", "Approve member"},
+		{"first registered without default", Labels{"zh-CN": "批准成员", "ja-JP": "メンバー承認"}, "fr-FR", "メンバー承認"},
+		{"empty labels use fallback", nil, "zh-CN", "ApproveMember"},
+		{"empty label skipped", Labels{"zh-CN": "", "en-US": "Approve member"}, "zh-CN", "Approve member"},
+	}
+	for _, tt := range tests {
+		if got := ResolveLabel(tt.labels, tt.locale, "ApproveMember"); got != tt.want {
+			t.Errorf("%s: ResolveLabel(%q) = %q, want %q", tt.name, tt.locale, got, tt.want)
+		}
+	}
+}
+
+// TestDisplayManifest 测试清单按语言合并名称并带出显示提示
+func TestDisplayManifest(t *testing.T) {
+	manifest := DisplayManifest("zh-HK")
+	if manifest["locale"] != "zh-HK" {
+		t.Errorf("locale = %v, want zh-HK", manifest["locale"])
+	}
+	fn := findDisplayFunction(t, manifest, "TestDisplayPay")
+	if fn["label"] != "缴纳" || fn["view"] != false {
+		t.Errorf("TestDisplayPay = %v", fn)
+	}
+	params := fn["params"].([]interface{})
+	amount := params[1].(map[string]interface{})
+	if amount["label"] != "金额" || amount["hint"] != HINT_AMOUNT || amount["token_ref"] != "token_id" {
+		t.Errorf("amount param = %v", amount)
+	}
+	payee := params[2].(map[string]interface{})
+	if payee["label"] != "payee" || payee["hint"] != HINT_ADDRESS || payee["token_ref"] != nil {
+		t.Errorf("payee param = %v", payee)
+	}
+	if _, ok := params[0].(map[string]interface{})["hint"]; ok {
+		t.Errorf("unhinted param has hint: %v", params[0])
+	}
+
+	echo := findDisplayFunction(t, DisplayManifest(""), "TestViewEcho")
+	if echo["label"] != "Echo" || echo["view"] != true {
+		t.Errorf("TestViewEcho = %v", echo)
+	}
+
+	encoded, err := appendCanonical(nil, manifest)
+	if err != nil || !strings.Contains(string(encoded), `"token_ref":"token_id"`) {
+		t.Errorf("canonical manifest = %s, err = %v", encoded, err)
+	}
+}
+
+// TestValidateFunctionManifest 测试显示提示与代币引用的校验
+func TestValidateFunctionManifest(t *testing.T) {
+	if err := ValidateDisplayManifest(); err != nil {
+		t.Fatalf("ValidateDisplayManifest() error = %v", err)
+	}
+
+	tokenParam := Param("token_id", "string", nil)
+	tests := []struct {
+		name   string
+		params []FunctionParam
+	}{
+		{"unknown hint", []FunctionParam{Param("x", "uint64", nil, Hint("PERCENT"))}},
+		{"amount on string", []FunctionParam{tokenParam, Param("x", "string", nil, AmountHint("token_id"))}},
+		{"amount without token", []FunctionParam{Param("x", "uint64", nil, Hint(HINT_AMOUNT))}},
+		{"missing token param", []FunctionParam{Param("x", "uint64", nil, AmountHint("token_id"))}},
+		{"token param not string", []FunctionParam{Param("token_id", "uint64", nil), Param("x", "uint64", nil, AmountHint("token_id"))}},
+		{"unknown view", []FunctionParam{Param("x", "uint64", nil, AmountHint("GetNothing.token_id"))}},
+		{"view without field", []FunctionParam{Param("x", "uint64", nil, AmountHint("TestViewEcho."))}},
+		{"token ref on address", []FunctionParam{Param("x", "address", nil, DisplayHint{Kind: HINT_ADDRESS, TokenRef: "token_id"})}},
+	}
+	for _, tt := range tests {
+		err := validateFunctionManifest(FunctionManifest{Name: "F", Params: tt.params})
+		if ce, ok := err.(*ContractError); !ok || ce.Code != ERROR_INVALID_PARAMS {
+			t.Errorf("%s: error = %v, want ERROR_INVALID_PARAMS", tt.name, err)
+		}
+	}
+
+	viewRef := FunctionManifest{Name: "F", Params: []FunctionParam{Param("x", "uint64", nil, AmountHint("TestViewEcho.token_id"))}}
+	if err := validateFunctionManifest(viewRef); err != nil {
+		t.Errorf("view token_ref error = %v", err)
+	}
+}
+
+func findDisplayFunction(t *testing.T, manifest map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	for _, item := range manifest["functions"].([]interface{}) {
+		fn := item.(map[string]interface{})
+		if fn["name"] == name {
+			return fn
+		}
+	}
+	t.Fatalf("function %s not in manifest", name)
+	return nil
+}
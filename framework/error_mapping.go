@@ -35,6 +35,14 @@ func ContractErrorCodeToWESCode(contractErrorCode uint32) string {
 		return "BC_CONTRACT_INVOCATION_FAILED"
 	case ERROR_PERMISSION_DENIED:
 		return "COMMON_VALIDATION_ERROR"
+	case ERROR_QUOTA_EXCEEDED:
+		return "COMMON_VALIDATION_ERROR" // 写入预算/索引配额属于输入限制
+	case ERROR_SLIPPAGE_EXCEEDED:
+		return "COMMON_VALIDATION_ERROR" // 执行时价格超出调用者给定的容忍范围
+	case ERROR_PAUSED:
+		return "BC_CONTRACT_INVOCATION_FAILED" // 紧急暂停期间拒绝执行
+	case ERROR_RATE_LIMITED:
+		return "COMMON_VALIDATION_ERROR" // 调用频率超出限制，属于输入限制
 	case ERROR_UNKNOWN:
 		return "COMMON_INTERNAL_ERROR"
 	default:
@@ -67,6 +75,14 @@ func ContractErrorCodeToUserMessage(contractErrorCode uint32) string {
 		return "功能未实现。"
 	case ERROR_PERMISSION_DENIED:
 		return "权限不足，无法执行此操作。"
+	case ERROR_QUOTA_EXCEEDED:
+		return "超出写入配额限制，请减少本次写入的数据量或条目数。"
+	case ERROR_SLIPPAGE_EXCEEDED:
+		return "价格变动超出滑点容忍范围，请刷新报价后重试。"
+	case ERROR_PAUSED:
+		return "功能已被暂停，请等待恢复后重试。"
+	case ERROR_RATE_LIMITED:
+		return "操作过于频繁，请稍后重试。"
 	case ERROR_UNKNOWN:
 		return "未知错误，请稍后重试或联系管理员。"
 	default:
@@ -99,6 +115,14 @@ func ContractErrorCodeToHTTPStatus(contractErrorCode uint32) int {
 		return 501
 	case ERROR_PERMISSION_DENIED:
 		return 403
+	case ERROR_QUOTA_EXCEEDED:
+		return 429
+	case ERROR_SLIPPAGE_EXCEEDED:
+		return 409
+	case ERROR_PAUSED:
+		return 503
+	case ERROR_RATE_LIMITED:
+		return 429
 	case ERROR_UNKNOWN:
 		return 500
 	default:
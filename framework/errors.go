@@ -1,5 +1,3 @@
-//go:build tinygo || (js && wasm)
-
 package framework
 
 // ==================== 错误定义 ====================
@@ -53,6 +51,14 @@ func ErrorCodeToString(code uint32) string {
 		return "ERROR_NOT_IMPLEMENTED"
 	case ERROR_PERMISSION_DENIED:
 		return "ERROR_PERMISSION_DENIED"
+	case ERROR_QUOTA_EXCEEDED:
+		return "ERROR_QUOTA_EXCEEDED"
+	case ERROR_SLIPPAGE_EXCEEDED:
+		return "ERROR_SLIPPAGE_EXCEEDED"
+	case ERROR_PAUSED:
+		return "ERROR_PAUSED"
+	case ERROR_RATE_LIMITED:
+		return "ERROR_RATE_LIMITED"
 	case ERROR_UNKNOWN:
 		return "ERROR_UNKNOWN"
 	default:
@@ -0,0 +1,259 @@
+package framework
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// 事件载荷锚定
+//
+// 部分事件（按案件列出金额的结算汇总、批量处理结果）的完整内容可能超过事件大小上限，
+// 截断会使其失去审计价值。EmitEventOrAnchor 在事件规范化序列化后超过 MAX_EVENT_BYTES 时，
+// 将完整载荷写入以内容哈希寻址的状态记录，并发出小体积的锚定事件 EventPayloadAnchored
+// （原事件名、载荷哈希、字节数、读取键），索引器据读取键取回完整内容。
+//
+// 是否锚定只取决于规范化载荷的字节数，同一事件在所有节点上得到相同结果。
+
+// MAX_EVENT_BYTES 事件规范化载荷的字节上限，超出时锚定
+const MAX_EVENT_BYTES = 4096
+
+// EVENT_ANCHOR_NAME 锚定事件名
+const EVENT_ANCHOR_NAME = "EventPayloadAnchored"
+
+// EVENT_ANCHOR_STORAGE_STATE 载荷存储方式：以内容哈希寻址的状态记录
+const EVENT_ANCHOR_STORAGE_STATE = "state"
+
+// eventPayloadStatePrefix 锚定载荷状态ID前缀，完整格式：event_payload:{hex(sha256(payload))}
+const eventPayloadStatePrefix = "event_payload:"
+
+func init() {
+	RegisterEventSchema(EVENT_ANCHOR_NAME, "event", "payload_hash", "payload_size", "storage", "retrieval_key")
+}
+
+// EventEmission 事件发出计划
+type EventEmission struct {
+	// Payload 原事件的规范化载荷
+	Payload []byte
+	// Anchored 载荷超过 MAX_EVENT_BYTES，需写入状态记录并发出锚定事件
+	Anchored bool
+	// PayloadHash 载荷的 SHA-256（仅 Anchored）
+	PayloadHash []byte
+	// RetrievalKey 载荷状态ID（仅 Anchored）
+	RetrievalKey []byte
+	// Anchor 锚定事件（仅 Anchored）
+	Anchor *Event
+}
+
+// PlanEventEmission 计算事件的发出方式
+//
+// 🎯 **用途**：EmitEventOrAnchor 的核心逻辑，不依赖宿主函数，便于测试
+//
+// **返回**：发出计划；事件为空或字段类型不支持规范化时返回 ERROR_INVALID_PARAMS
+func PlanEventEmission(event *Event) (EventEmission, error) {
+	payload, err := CanonicalEventJSON(event)
+	if err != nil {
+		return EventEmission{}, err
+	}
+	if len(payload) <= MAX_EVENT_BYTES {
+		return EventEmission{Payload: payload}, nil
+	}
+
+	sum := sha256.Sum256(payload)
+	key := AnchoredPayloadStateID(sum[:])
+	anchor := NewEvent(EVENT_ANCHOR_NAME)
+	anchor.Data["event"] = event.Name
+	anchor.Data["payload_hash"] = "0x" + hex.EncodeToString(sum[:])
+	anchor.Data["payload_size"] = uint64(len(payload))
+	anchor.Data["storage"] = EVENT_ANCHOR_STORAGE_STATE
+	anchor.Data["retrieval_key"] = string(key)
+
+	return EventEmission{
+		Payload:      payload,
+		Anchored:     true,
+		PayloadHash:  sum[:],
+		RetrievalKey: key,
+		Anchor:       anchor,
+	}, nil
+}
+
+// EmitEventOrAnchor 发出事件，超过大小上限时改为锚定
+//
+// 🎯 **用途**：发出内容可能较大的审计类事件，不截断内容
+//
+// **参数**：
+//   - event: 待发出的事件，字段值须为字符串、布尔、整数、[]string、[]interface{} 或 map[string]interface{}
+//
+// **返回**：
+//   - anchored: 是否以锚定方式发出
+//   - error: 规范化失败、状态写入失败或事件发出失败
+//
+// **注意**：
+//   - 未超限时直接发出规范化载荷，字段完整保留（含数组与嵌套对象）
+//   - 超限时载荷写入 event_payload:{hex(sha256)}（相同载荷只写一次），计入写入预算；
+//     随后发出 EventPayloadAnchored 事件，原事件不再发出
+//   - 链上可通过 GetAnchoredPayload 读取完整载荷
+//
+// **示例**：
+//
+//	event := framework.NewEvent("MutualAidRoundPayoutSummary")
+//	event.AddStringField("round_id", roundID)
+//	event.AddField("claims", items)
+//	if _, err := framework.EmitEventOrAnchor(event); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func EmitEventOrAnchor(event *Event) (bool, error) {
+	plan, err := PlanEventEmission(event)
+	if err != nil {
+		return false, err
+	}
+	if !plan.Anchored {
+		return false, emitCanonicalEvent(event.Name, plan.Payload)
+	}
+
+	if existing, _, _ := GetStateFromChain(plan.RetrievalKey); len(existing) == 0 {
+		if _, err := AppendStateOutputSimple(plan.RetrievalKey, 1, plan.Payload, nil); err != nil {
+			return true, err
+		}
+	}
+	anchorPayload, err := CanonicalEventJSON(plan.Anchor)
+	if err != nil {
+		return true, err
+	}
+	return true, emitCanonicalEvent(EVENT_ANCHOR_NAME, anchorPayload)
+}
+
+// GetAnchoredPayload 读取锚定事件的完整载荷（只读）
+//
+// **参数**：
+//   - payloadHash: 锚定事件中的载荷哈希（32字节 SHA-256）
+//
+// **返回**：规范化载荷；不存在或内容与哈希不符时返回 ERROR_NOT_FOUND
+func GetAnchoredPayload(payloadHash []byte) ([]byte, error) {
+	if len(payloadHash) != sha256.Size {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "payload hash must be 32 bytes")
+	}
+	data, _, err := GetStateFromChain(AnchoredPayloadStateID(payloadHash))
+	if err != nil || len(data) == 0 {
+		return nil, NewContractError(ERROR_NOT_FOUND, "anchored payload not found")
+	}
+	// 规范化载荷以 '}' 结尾，链上读取不会截断内容；哈希校验排除不一致的记录
+	if sum := sha256.Sum256(data); string(sum[:]) != string(payloadHash) {
+		return nil, NewContractError(ERROR_NOT_FOUND, "anchored payload does not match hash")
+	}
+	return data, nil
+}
+
+// AnchoredPayloadStateID 返回锚定载荷的状态ID（即锚定事件的 retrieval_key）
+func AnchoredPayloadStateID(payloadHash []byte) []byte {
+	return []byte(eventPayloadStatePrefix + hex.EncodeToString(payloadHash))
+}
+
+// ==================== 规范化序列化 ====================
+
+// CanonicalEventJSON 返回事件的规范化 JSON
+//
+// 格式：{"event":"<name>","data":{...}}，对象键按字节序排列，无空白，
+// 整数按十进制输出；不含时间戳等执行环境字段，相同事件得到相同字节
+func CanonicalEventJSON(event *Event) ([]byte, error) {
+	if event == nil || event.Name == "" {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "event name is required")
+	}
+	buf := append([]byte(`{"event":`), quoteCanonical(event.Name)...)
+	buf = append(buf, `,"data":`...)
+	buf, err := appendCanonical(buf, event.Data)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '}'), nil
+}
+
+// appendCanonical 追加值的规范化 JSON
+func appendCanonical(buf []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case string:
+		return append(buf, quoteCanonical(v)...), nil
+	case bool:
+		if v {
+			return append(buf, "true"...), nil
+		}
+		return append(buf, "false"...), nil
+	case uint64:
+		return append(buf, formatUint(v)...), nil
+	case uint32:
+		return append(buf, formatUint(uint64(v))...), nil
+	case uint:
+		return append(buf, formatUint(uint64(v))...), nil
+	case int:
+		return appendCanonicalInt(buf, int64(v)), nil
+	case int64:
+		return appendCanonicalInt(buf, v), nil
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return appendCanonical(buf, items)
+	case []interface{}:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = appendCanonical(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf = append(buf, '{')
+		for i, k := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, quoteCanonical(k)...)
+			buf = append(buf, ':')
+			var err error
+			if buf, err = appendCanonical(buf, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	default:
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "unsupported event field type")
+	}
+}
+
+func appendCanonicalInt(buf []byte, n int64) []byte {
+	if n < 0 {
+		return append(append(buf, '-'), formatUint(uint64(-n))...)
+	}
+	return append(buf, formatUint(uint64(n))...)
+}
+
+// quoteCanonical 转义并加引号；控制字符统一输出为 \u00XX
+func quoteCanonical(s string) string {
+	const hexChars = "0123456789abcdef"
+	out := make([]byte, 0, len(s)+2)
+	out = append(out, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			out = append(out, '\\', c)
+		case c < 0x20:
+			out = append(out, '\\', 'u', '0', '0', hexChars[c>>4], hexChars[c&0x0F])
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(append(out, '"'))
+}
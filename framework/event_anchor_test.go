@@ -0,0 +1,131 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+// emittedEvents 记录 emit_event 宿主调用的事件名
+type emittedEvents []string
+
+func (e *emittedEvents) BeforeHostCall(call HostCall) error {
+	if call.Name == HOST_CALL_EMIT_EVENT {
+		*e = append(*e, call.EventName)
+	}
+	return nil
+}
+
+func summaryEvent(claims int) *Event {
+	items := make([]interface{}, claims)
+	for i := range items {
+		items[i] = map[string]interface{}{"claim_id": "claim_" + formatUint(uint64(i)), "amount": uint64(1000 + i)}
+	}
+	event := NewEvent("RoundPayoutSummary")
+	event.Data["round_id"] = "round_1"
+	event.Data["claims"] = items
+	return event
+}
+
+// TestCanonicalEventJSON 测试规范化序列化：键排序、嵌套结构与转义
+func TestCanonicalEventJSON(t *testing.T) {
+	event := NewEvent("E")
+	event.Data["b"] = uint64(2)
+	event.Data["a"] = []interface{}{"x\"y", true, map[string]interface{}{"z": -1, "y": "\n"}}
+
+	got, err := CanonicalEventJSON(event)
+	if err != nil {
+		t.Fatalf("CanonicalEventJSON() error = %v", err)
+	}
+	want := `{"event":"E","data":{"a":["x\"y",true,{"y":"\u000a","z":-1}],"b":2}}`
+	if string(got) != want {
+		t.Errorf("CanonicalEventJSON() = %s, want %s", got, want)
+	}
+
+	event.Data["bad"] = 1.5
+	if _, err := CanonicalEventJSON(event); quotaErrCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("unsupported field error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+}
+
+// TestEmitEventOrAnchorSmall 测试未超限的事件直接发出且不写状态
+func TestEmitEventOrAnchorSmall(t *testing.T) {
+	ResetStagedWrites()
+	defer ResetStagedWrites()
+	var emitted emittedEvents
+	defer SetHostInterceptor(&emitted)()
+
+	anchored, err := EmitEventOrAnchor(summaryEvent(3))
+	if err != nil || anchored {
+		t.Fatalf("EmitEventOrAnchor() = (%v, %v), want direct emit", anchored, err)
+	}
+	if len(emitted) != 1 || emitted[0] != "RoundPayoutSummary" {
+		t.Errorf("emitted = %v, want [RoundPayoutSummary]", emitted)
+	}
+	if writes := StagedStateWrites(); len(writes) != 0 {
+		t.Errorf("staged writes = %v, want none", writes)
+	}
+}
+
+// TestEmitEventOrAnchorLarge 测试超限的事件写入内容寻址状态并发出锚定事件
+func TestEmitEventOrAnchorLarge(t *testing.T) {
+	ResetStagedWrites()
+	defer ResetStagedWrites()
+	var emitted emittedEvents
+	defer SetHostInterceptor(&emitted)()
+
+	event := summaryEvent(200)
+	plan, err := PlanEventEmission(event)
+	if err != nil {
+		t.Fatalf("PlanEventEmission() error = %v", err)
+	}
+	if !plan.Anchored || len(plan.Payload) <= MAX_EVENT_BYTES {
+		t.Fatalf("plan anchored = %v, size = %d, want anchored above %d", plan.Anchored, len(plan.Payload), MAX_EVENT_BYTES)
+	}
+	sum := sha256.Sum256(plan.Payload)
+	if string(plan.PayloadHash) != string(sum[:]) {
+		t.Error("payload hash does not match canonical payload")
+	}
+	if plan.Anchor.Data["event"] != "RoundPayoutSummary" ||
+		plan.Anchor.Data["payload_size"] != uint64(len(plan.Payload)) ||
+		plan.Anchor.Data["retrieval_key"] != string(AnchoredPayloadStateID(sum[:])) {
+		t.Errorf("anchor data = %v", plan.Anchor.Data)
+	}
+	anchorPayload, _ := CanonicalEventJSON(plan.Anchor)
+	if len(anchorPayload) > MAX_EVENT_BYTES {
+		t.Errorf("anchor payload size = %d, want within limit", len(anchorPayload))
+	}
+
+	anchored, err := EmitEventOrAnchor(event)
+	if err != nil || !anchored {
+		t.Fatalf("EmitEventOrAnchor() = (%v, %v), want anchored", anchored, err)
+	}
+	if len(emitted) != 1 || emitted[0] != EVENT_ANCHOR_NAME {
+		t.Errorf("emitted = %v, want [%s]", emitted, EVENT_ANCHOR_NAME)
+	}
+	writes := StagedStateWrites()
+	if len(writes) != 1 || !strings.HasPrefix(writes[0], "event_payload:") || writes[0] != string(plan.RetrievalKey) {
+		t.Errorf("staged writes = %v, want [%s]", writes, plan.RetrievalKey)
+	}
+	if _, ok := GetEventSchema(EVENT_ANCHOR_NAME); !ok {
+		t.Error("anchor event schema not registered")
+	}
+
+	// 相同内容的事件得到相同的载荷哈希
+	again, _ := PlanEventEmission(summaryEvent(200))
+	if string(again.PayloadHash) != string(plan.PayloadHash) {
+		t.Error("canonical payload hash is not deterministic")
+	}
+}
+
+// TestGetAnchoredPayloadValidation 测试载荷哈希长度校验与缺失载荷
+func TestGetAnchoredPayloadValidation(t *testing.T) {
+	if _, err := GetAnchoredPayload([]byte{1, 2}); quotaErrCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("short hash error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if _, err := GetAnchoredPayload(make([]byte, 32)); quotaErrCode(err) != ERROR_NOT_FOUND {
+		t.Errorf("missing payload error = %v, want ERROR_NOT_FOUND", err)
+	}
+}
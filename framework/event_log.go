@@ -0,0 +1,281 @@
+package framework
+
+// 合约内事件日志
+//
+// 事件发出后进入链的事件流，合约自身无法回查。需要链上可查询审计轨迹的合约
+// （理赔案件、治理投票等）可在发出事件的同时调用 AppendEventLog，将事件追加到
+// 合约状态中的事件日志，并登记视图函数 ViewEventLog 供客户端按事件名与时间范围分页查询。
+// 事件日志是可选的：不调用 AppendEventLog 的合约不产生任何额外状态。
+//
+// 状态布局（基于 AppendIndexEntry 的具名索引 EVENT_LOG_INDEX）：
+//   - 全部事件：分区 EVENT_LOG_ALL，条目为 timestamp(8) + 事件名长度(1) + 事件名 + 规范化 data JSON
+//   - 按事件名：分区为事件名，条目为 timestamp(8) + 全部事件分区中的序号(8)
+//
+// 条目按追加顺序存放，区块时间单调不减，因此按时间范围查询时可二分定位起点。
+
+const (
+	// EVENT_LOG_INDEX 事件日志使用的索引名称
+	EVENT_LOG_INDEX = "event_log"
+	// EVENT_LOG_ALL 全部事件所在的分区（事件名不能为该值）
+	EVENT_LOG_ALL = "*"
+
+	// DEFAULT_EVENT_LOG_PAGE 查询未指定 limit 时的每页条数
+	DEFAULT_EVENT_LOG_PAGE = 20
+	// MAX_EVENT_LOG_PAGE 单次查询的最大条数
+	MAX_EVENT_LOG_PAGE = 50
+)
+
+// EventLogRecord 事件日志中的一条记录
+type EventLogRecord struct {
+	// Seq 记录在全部事件中的序号（从0开始）
+	Seq uint64
+	// Event 事件名
+	Event string
+	// Timestamp 追加时的区块时间
+	Timestamp uint64
+	// Data 事件字段的规范化 JSON（键按字节序排列，见 CanonicalEventJSON）
+	Data []byte
+}
+
+// EventLogQuery 事件日志查询条件
+type EventLogQuery struct {
+	// Event 事件名，空表示全部事件
+	Event string
+	// From 起始时间（含），0 表示不限
+	From uint64
+	// To 截止时间（含），0 表示不限
+	To uint64
+	// Cursor 上一页返回的 next_cursor，0 表示按 From 定位第一页
+	Cursor uint64
+	// Limit 本页条数，0 表示 DEFAULT_EVENT_LOG_PAGE，超过 MAX_EVENT_LOG_PAGE 时按上限
+	Limit uint32
+}
+
+// AppendEventLog 将事件追加到合约内事件日志
+//
+// 🎯 **用途**：为需要链上审计的事件保留可查询的记录，通常紧随 EmitEvent 调用
+//
+// **参数**：
+//   - event: 要记录的事件，字段类型同 CanonicalEventJSON
+//
+// **返回**：记录在全部事件中的序号；事件名为空、超过 255 字节或为 EVENT_LOG_ALL、
+// 字段无法规范化序列化、记录超过 65535 字节时返回 ERROR_INVALID_PARAMS
+//
+// **示例**：
+//
+//	event := framework.NewEvent("ClaimReviewed")
+//	event.AddStringField("claim_id", claimID)
+//	framework.EmitEvent(event)
+//	if _, err := framework.AppendEventLog(event); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+//
+// **注意**：
+//   - 每条记录写入四个状态输出（两个分区各一条目、一计数），计入当前调用的写入预算
+//   - 登记了 EVENT_LOG_INDEX 的索引配额（RegisterIndexQuota）时，配额按分区（事件名）生效
+func AppendEventLog(event *Event) (uint64, error) {
+	if event == nil || event.Name == "" || event.Name == EVENT_LOG_ALL || len(event.Name) > 0xFF {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "event log: invalid event name")
+	}
+	data, err := appendCanonical(nil, event.Data)
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := GetTimestamp()
+	record := make([]byte, 0, 9+len(event.Name)+len(data))
+	record = appendUint64BE(record, timestamp)
+	record = append(record, byte(len(event.Name)))
+	record = append(record, event.Name...)
+	record = append(record, data...)
+	seq, err := AppendIndexEntry(EVENT_LOG_INDEX, []byte(EVENT_LOG_ALL), record)
+	if err != nil {
+		return 0, err
+	}
+
+	ref := appendUint64BE(appendUint64BE(make([]byte, 0, 16), timestamp), seq)
+	if _, err := AppendIndexEntry(EVENT_LOG_INDEX, []byte(event.Name), ref); err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// QueryEventLog 按事件名与时间范围分页读取事件日志
+//
+// **返回**：
+//   - records: 按追加顺序排列的记录
+//   - nextCursor: 仍有满足条件的记录时为下一页的 Cursor，否则为 0
+//   - error: 记录损坏时返回 ERROR_EXECUTION_FAILED
+func QueryEventLog(q EventLogQuery) (records []EventLogRecord, nextCursor uint64, err error) {
+	partition := []byte(EVENT_LOG_ALL)
+	if q.Event != "" {
+		partition = []byte(q.Event)
+	}
+	limit := uint64(q.Limit)
+	if limit == 0 {
+		limit = DEFAULT_EVENT_LOG_PAGE
+	}
+	if limit > MAX_EVENT_LOG_PAGE {
+		limit = MAX_EVENT_LOG_PAGE
+	}
+
+	count := IndexEntryCount(EVENT_LOG_INDEX, partition)
+	pos := q.Cursor
+	if pos == 0 && q.From > 0 {
+		pos, err = eventLogSearch(partition, count, q.From)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	records = make([]EventLogRecord, 0, limit)
+	for ; pos < count; pos++ {
+		r, err := eventLogRecordAt(partition, pos)
+		if err != nil {
+			return nil, 0, err
+		}
+		if q.To > 0 && r.Timestamp > q.To {
+			return records, 0, nil
+		}
+		if uint64(len(records)) == limit {
+			return records, pos, nil
+		}
+		records = append(records, r)
+	}
+	return records, 0, nil
+}
+
+// ViewEventLog 事件日志查询的视图函数
+//
+// 🎯 **用途**：合约登记为视图函数后由导出函数委托 ServeView，客户端按事件名与时间范围分页查询
+//
+// 参数（JSON，均可选）：
+//
+//	{
+//	  "event": "MutualAidClaimReviewed",   // 事件名，缺省为全部事件
+//	  "from": 1736200000,                   // 起始时间（含）
+//	  "to": 1738800000,                     // 截止时间（含）
+//	  "cursor": 20,                         // 上一页返回的 next_cursor
+//	  "limit": 20                           // 每页条数，最多 50
+//	}
+//
+// 返回：
+//
+//	{
+//	  "events": [
+//	    {"seq": 3, "event": "MutualAidClaimReviewed", "timestamp": 1736200000, "data": "{\"claim_id\":\"claim_001\"}"}
+//	  ],
+//	  "next_cursor": 21,
+//	  "has_more": true
+//	}
+//
+// data 为事件字段的规范化 JSON 文本；has_more 为 false 时 next_cursor 为 0。
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterViewFunction("QueryEventLog", framework.ViewEventLog)
+//	}
+//
+//	//export QueryEventLog
+//	func QueryEventLog() uint32 {
+//	    return framework.ServeView("QueryEventLog")
+//	}
+func ViewEventLog(params *ContractParams) (interface{}, error) {
+	q := EventLogQuery{Event: params.ParseJSON("event")}
+	var err error
+	if q.From, err = params.parseUintParam("from"); err != nil {
+		return nil, err
+	}
+	if q.To, err = params.parseUintParam("to"); err != nil {
+		return nil, err
+	}
+	if q.Cursor, err = params.parseUintParam("cursor"); err != nil {
+		return nil, err
+	}
+	limit, err := params.parseUintParam("limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit > MAX_EVENT_LOG_PAGE {
+		limit = MAX_EVENT_LOG_PAGE
+	}
+	q.Limit = uint32(limit)
+
+	records, next, err := QueryEventLog(q)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		events = append(events, map[string]interface{}{
+			"seq":       r.Seq,
+			"event":     r.Event,
+			"timestamp": r.Timestamp,
+			"data":      string(r.Data),
+		})
+	}
+	return map[string]interface{}{
+		"events":      events,
+		"next_cursor": next,
+		"has_more":    next > 0,
+	}, nil
+}
+
+// eventLogSearch 二分查找分区中第一条时间不早于 from 的记录位置，不存在时返回 count
+func eventLogSearch(partition []byte, count, from uint64) (uint64, error) {
+	lo, hi := uint64(0), count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		entry, err := GetIndexEntry(EVENT_LOG_INDEX, partition, mid)
+		if err != nil || len(entry) < 8 {
+			return 0, NewContractError(ERROR_EXECUTION_FAILED, "event log: corrupted entry")
+		}
+		if readUint64BE(entry) < from {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// eventLogRecordAt 读取分区中指定位置的记录（按事件名分区时经序号读取全部事件分区中的记录）
+func eventLogRecordAt(partition []byte, pos uint64) (EventLogRecord, error) {
+	seq := pos
+	if string(partition) != EVENT_LOG_ALL {
+		ref, err := GetIndexEntry(EVENT_LOG_INDEX, partition, pos)
+		if err != nil || len(ref) != 16 {
+			return EventLogRecord{}, NewContractError(ERROR_EXECUTION_FAILED, "event log: corrupted entry")
+		}
+		seq = readUint64BE(ref[8:])
+	}
+	entry, err := GetIndexEntry(EVENT_LOG_INDEX, []byte(EVENT_LOG_ALL), seq)
+	if err != nil || len(entry) < 9 || len(entry) < 9+int(entry[8]) {
+		return EventLogRecord{}, NewContractError(ERROR_EXECUTION_FAILED, "event log: corrupted entry")
+	}
+	nameEnd := 9 + int(entry[8])
+	return EventLogRecord{
+		Seq:       seq,
+		Event:     string(entry[9:nameEnd]),
+		Timestamp: readUint64BE(entry),
+		Data:      entry[nameEnd:],
+	}, nil
+}
+
+// appendUint64BE 追加 8 字节大端编码
+func appendUint64BE(buf []byte, n uint64) []byte {
+	for i := 0; i < 8; i++ {
+		buf = append(buf, byte(n>>(56-8*i)))
+	}
+	return buf
+}
+
+// readUint64BE 读取前 8 字节的大端编码
+func readUint64BE(b []byte) uint64 {
+	var n uint64
+	for _, c := range b[:8] {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
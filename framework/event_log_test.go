@@ -0,0 +1,140 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"strings"
+	"testing"
+)
+
+// appendTestEvents 依次在 100、200、300、300、400 秒追加 Submitted/Reviewed 交替的事件（300 秒的两条在同一次调用中）
+func appendTestEvents(t *testing.T, host *MockHost) {
+	t.Helper()
+	batches := []struct {
+		timestamp uint64
+		names     []string
+	}{
+		{100, []string{"Submitted"}},
+		{200, []string{"Reviewed"}},
+		{300, []string{"Submitted", "Reviewed"}},
+		{400, []string{"Submitted"}},
+	}
+	for i, b := range batches {
+		host.Timestamp = b.timestamp
+		res := host.Invoke(Address{0x01}, nil, func() uint32 {
+			for _, name := range b.names {
+				event := NewEvent(name)
+				event.AddIntField("batch", uint64(i))
+				if _, err := AppendEventLog(event); err != nil {
+					t.Errorf("AppendEventLog(%s) error = %v", name, err)
+					return ERROR_EXECUTION_FAILED
+				}
+			}
+			return SUCCESS
+		})
+		if res.Code != SUCCESS {
+			t.Fatalf("batch %d code = %d", i, res.Code)
+		}
+	}
+}
+
+func eventLogSeqs(records []EventLogRecord) []uint64 {
+	seqs := make([]uint64, len(records))
+	for i, r := range records {
+		seqs[i] = r.Seq
+	}
+	return seqs
+}
+
+func equalSeqs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestEventLogQueryByType 测试按事件名过滤、时间范围定位与分页游标
+func TestEventLogQueryByType(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	appendTestEvents(t, host)
+
+	tests := []struct {
+		name     string
+		query    EventLogQuery
+		wantSeqs []uint64
+		wantNext uint64
+	}{
+		{"all events", EventLogQuery{}, []uint64{0, 1, 2, 3, 4}, 0},
+		{"by type", EventLogQuery{Event: "Submitted"}, []uint64{0, 2, 4}, 0},
+		{"type from", EventLogQuery{Event: "Submitted", From: 250}, []uint64{2, 4}, 0},
+		{"type to", EventLogQuery{Event: "Reviewed", To: 299}, []uint64{1}, 0},
+		{"time range", EventLogQuery{From: 200, To: 300}, []uint64{1, 2, 3}, 0},
+		{"first page", EventLogQuery{Event: "Submitted", Limit: 2}, []uint64{0, 2}, 2},
+		{"next page", EventLogQuery{Event: "Submitted", Limit: 2, Cursor: 2}, []uint64{4}, 0},
+		{"page ends at range", EventLogQuery{From: 200, To: 300, Limit: 3}, []uint64{1, 2, 3}, 0},
+		{"unknown type", EventLogQuery{Event: "Paid"}, []uint64{}, 0},
+		{"from after last", EventLogQuery{From: 500}, []uint64{}, 0},
+	}
+	for _, tt := range tests {
+		records, next, err := QueryEventLog(tt.query)
+		if err != nil {
+			t.Errorf("%s: QueryEventLog() error = %v", tt.name, err)
+			continue
+		}
+		if got := eventLogSeqs(records); !equalSeqs(got, tt.wantSeqs) || next != tt.wantNext {
+			t.Errorf("%s: QueryEventLog() = %v, next %d, want %v, next %d", tt.name, got, next, tt.wantSeqs, tt.wantNext)
+		}
+	}
+
+	records, _, _ := QueryEventLog(EventLogQuery{Event: "Reviewed", From: 300})
+	if len(records) != 1 || records[0].Event != "Reviewed" || records[0].Timestamp != 300 || string(records[0].Data) != `{"batch":2}` {
+		t.Errorf("Reviewed record = %+v, want batch 2 at 300", records)
+	}
+}
+
+// TestViewEventLog 测试视图函数的参数解析与返回结构
+func TestViewEventLog(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	appendTestEvents(t, host)
+
+	result, err := ViewEventLog(NewContractParams([]byte(`{"event":"Submitted","limit":1,"cursor":1}`)))
+	if err != nil {
+		t.Fatalf("ViewEventLog() error = %v", err)
+	}
+	got, _ := appendCanonical(nil, result)
+	want := `{"events":[{"data":"{\"batch\":2}","event":"Submitted","seq":2,"timestamp":300}],"has_more":true,"next_cursor":2}`
+	if string(got) != want {
+		t.Errorf("ViewEventLog() = %s, want %s", got, want)
+	}
+
+	if _, err := ViewEventLog(NewContractParams([]byte(`{"from":"soon"}`))); quotaErrCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("invalid from error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+}
+
+// TestAppendEventLogRejectsInvalidEvents 测试事件名为空、与全部事件分区同名或字段无法序列化时拒绝且不写入
+func TestAppendEventLogRejectsInvalidEvents(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	unsupported := NewEvent("Bad")
+	unsupported.Data["ratio"] = 1.5
+	for _, event := range []*Event{nil, NewEvent(""), NewEvent(EVENT_LOG_ALL), NewEvent(strings.Repeat("e", 256)), unsupported} {
+		res := host.Invoke(Address{0x01}, nil, func() uint32 {
+			if _, err := AppendEventLog(event); quotaErrCode(err) != ERROR_INVALID_PARAMS {
+				t.Errorf("AppendEventLog(%v) error = %v, want ERROR_INVALID_PARAMS", event, err)
+			}
+			return SUCCESS
+		})
+		if len(res.Writes) != 0 {
+			t.Errorf("AppendEventLog(%v) staged %d writes", event, len(res.Writes))
+		}
+	}
+}
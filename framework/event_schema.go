@@ -0,0 +1,68 @@
+package framework
+
+// 事件结构登记
+//
+// 合约在 init 中登记会发出的事件名与字段列表，供索引器与客户端据此解析事件。
+// EventSchemas 按登记顺序汇总，可由合约的查询导出函数直接返回。
+
+// EventSchema 事件结构
+type EventSchema struct {
+	// Name 事件名
+	Name string
+	// Fields 事件数据字段名（按登记顺序）
+	Fields []string
+}
+
+var (
+	eventSchemas     = map[string]EventSchema{}
+	eventSchemaOrder []string
+)
+
+// RegisterEventSchema 登记事件结构
+//
+// 🎯 **用途**：声明合约会发出的事件及其字段，通常在合约包的 init 中调用
+//
+// **参数**：
+//   - name: 事件名，重复登记时覆盖之前的字段列表
+//   - fields: 事件数据字段名
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterEventSchema("MutualAidRoundPayoutSummary", "plan_id", "round_id", "claims")
+//	}
+func RegisterEventSchema(name string, fields ...string) {
+	if _, ok := eventSchemas[name]; !ok {
+		eventSchemaOrder = append(eventSchemaOrder, name)
+	}
+	eventSchemas[name] = EventSchema{Name: name, Fields: append([]string(nil), fields...)}
+}
+
+// GetEventSchema 查询事件结构，未登记时返回 false
+func GetEventSchema(name string) (EventSchema, bool) {
+	s, ok := eventSchemas[name]
+	return s, ok
+}
+
+// EventSchemas 按登记顺序汇总已登记的事件结构
+//
+// **返回**：
+//
+//	[
+//	  {"event": "EventPayloadAnchored", "fields": ["event", "payload_hash", ...]}
+//	]
+func EventSchemas() []interface{} {
+	out := make([]interface{}, 0, len(eventSchemaOrder))
+	for _, name := range eventSchemaOrder {
+		s := eventSchemas[name]
+		fields := make([]interface{}, len(s.Fields))
+		for i, f := range s.Fields {
+			fields[i] = f
+		}
+		out = append(out, map[string]interface{}{
+			"event":  s.Name,
+			"fields": fields,
+		})
+	}
+	return out
+}
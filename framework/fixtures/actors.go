@@ -0,0 +1,162 @@
+// Package fixtures 提供测试共用的规范化夹具。
+//
+// 各测试文件自行编造地址和时间戳，失败信息难以阅读，夹具也不一致
+// （19字节地址之类的错误夹具会掩盖本该发现的问题）。本包统一提供：
+//   - 命名参与方：Alice / Bob / Carol / Operator / Pool，均为合法的20字节地址
+//   - 代币夹具：StableToken6Decimals / NativeToken，附带已登记的符号与精度
+//   - 时钟夹具：Clock，从 Epoch 开始按可读步长推进（clock.Advance(Days(30))）
+//   - 互助计划领域的记录构建器：MutualAidPlan / ActiveMember / SubmittedClaim / OpenRound
+//
+// 参与方地址由 sha256("weisyn/fixtures/<name>") 的前20字节确定，
+// 其 Base58Check 形式（版本字节 0x00 + 20字节地址 + 4字节双SHA256校验和）固定为：
+//
+//	Alice     15mzoN73mqC3p7yMDMYHjnTko58JKeV8XT
+//	Bob       15qiqM6M5UuN99Yx1wxETKT6Z9CusghTWa
+//	Carol     1Dup9sJEyG7Y4DWsRTEvWdf3caGSE3QkBL
+//	Operator  1G7YLAEgGWGedEbwTzt3Npbrh4uq1jRLnP
+//	Pool      14JU5QxwTUGfVL3pQqrN5McCTj7YpbpQX5
+//
+// Base58 与非WASM环境下的 Address.ToString 输出一致；失败信息中使用 Name 更易读。
+package fixtures
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ADDRESS_VERSION Base58Check 编码使用的版本字节
+const ADDRESS_VERSION = byte(0x00)
+
+// actorNames 命名参与方（顺序即文档顺序）
+var actorNames = []string{"alice", "bob", "carol", "operator", "pool"}
+
+// Alice 普通成员 / 买方
+func Alice() framework.Address { return actor("alice") }
+
+// Bob 普通成员 / 卖方
+func Bob() framework.Address { return actor("bob") }
+
+// Carol 普通成员 / 第三方
+func Carol() framework.Address { return actor("carol") }
+
+// Operator 计划运营方 / 仲裁方
+func Operator() framework.Address { return actor("operator") }
+
+// Pool 资金池 / 合约托管地址
+func Pool() framework.Address { return actor("pool") }
+
+// Actors 返回全部命名参与方（Alice、Bob、Carol、Operator、Pool）
+func Actors() []framework.Address {
+	out := make([]framework.Address, len(actorNames))
+	for i, name := range actorNames {
+		out[i] = actor(name)
+	}
+	return out
+}
+
+// Name 返回地址的可读名称，用于测试失败信息
+//
+// **返回**：命名参与方返回其名称（如 "alice"），其他地址返回十六进制形式
+func Name(addr framework.Address) string {
+	for _, name := range actorNames {
+		if actor(name) == addr {
+			return name
+		}
+	}
+	return "0x" + hex.EncodeToString(addr[:])
+}
+
+// actor 由名称确定性地派生20字节地址
+func actor(name string) framework.Address {
+	sum := sha256.Sum256([]byte("weisyn/fixtures/" + name))
+	var addr framework.Address
+	copy(addr[:], sum[:len(addr)])
+	return addr
+}
+
+// ================================================================================================
+// Base58Check
+// ================================================================================================
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58 返回地址的 Base58Check 编码
+func Base58(addr framework.Address) string {
+	payload := append([]byte{ADDRESS_VERSION}, addr[:]...)
+	payload = append(payload, checksum(payload)...)
+	return encodeBase58(payload)
+}
+
+// ParseBase58 解析 Base58Check 地址并校验版本字节、长度与校验和
+//
+// **返回**：
+//   - framework.Address: 解析出的地址
+//   - bool: 格式、长度或校验和不合法时为 false
+func ParseBase58(s string) (framework.Address, bool) {
+	var addr framework.Address
+	payload, ok := decodeBase58(s)
+	if !ok || len(payload) != 1+len(addr)+4 || payload[0] != ADDRESS_VERSION {
+		return addr, false
+	}
+	body, sum := payload[:1+len(addr)], payload[1+len(addr):]
+	if string(checksum(body)) != string(sum) {
+		return addr, false
+	}
+	copy(addr[:], body[1:])
+	return addr, true
+}
+
+// checksum 双SHA256的前4字节
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func encodeBase58(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	base, mod := big.NewInt(58), new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func decodeBase58(s string) ([]byte, bool) {
+	n, base := new(big.Int), big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		idx := -1
+		for j := 0; j < len(base58Alphabet); j++ {
+			if base58Alphabet[j] == s[i] {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, false
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	leading := 0
+	for leading < len(s) && s[leading] == base58Alphabet[0] {
+		leading++
+	}
+	return append(make([]byte, leading), n.Bytes()...), true
+}
@@ -0,0 +1,39 @@
+package fixtures
+
+// Epoch 夹具时间序列的起点（2025-01-06 21:46:40 UTC）
+const Epoch = uint64(1736200000)
+
+// Hours 返回 n 小时对应的秒数
+func Hours(n uint64) uint64 { return n * 3600 }
+
+// Days 返回 n 天对应的秒数
+func Days(n uint64) uint64 { return n * 86400 }
+
+// Clock 测试时钟
+//
+// 🎯 **用途**：以可读步长推进模拟时间，代替测试中手写的时间戳
+//
+// **示例**：
+//
+//	clock := fixtures.NewClock()
+//	round := fixtures.OpenRound("round_01", plan, clock.Now())
+//	clock.Advance(fixtures.Days(30))
+type Clock struct {
+	now uint64
+}
+
+// NewClock 创建从 Epoch 开始的时钟
+func NewClock() *Clock {
+	return &Clock{now: Epoch}
+}
+
+// Now 返回当前时间（Unix秒）
+func (c *Clock) Now() uint64 {
+	return c.now
+}
+
+// Advance 将时钟推进 d 秒，返回推进后的时间
+func (c *Clock) Advance(d uint64) uint64 {
+	c.now += d
+	return c.now
+}
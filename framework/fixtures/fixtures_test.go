@@ -0,0 +1,83 @@
+package fixtures
+
+import (
+	"testing"
+)
+
+// TestActorAddresses 测试命名参与方地址合法、互不相同且 Base58Check 形式稳定
+func TestActorAddresses(t *testing.T) {
+	want := map[string]string{
+		"alice":    "15mzoN73mqC3p7yMDMYHjnTko58JKeV8XT",
+		"bob":      "15qiqM6M5UuN99Yx1wxETKT6Z9CusghTWa",
+		"carol":    "1Dup9sJEyG7Y4DWsRTEvWdf3caGSE3QkBL",
+		"operator": "1G7YLAEgGWGedEbwTzt3Npbrh4uq1jRLnP",
+		"pool":     "14JU5QxwTUGfVL3pQqrN5McCTj7YpbpQX5",
+	}
+
+	seen := make(map[string]bool)
+	for _, addr := range Actors() {
+		name := Name(addr)
+		if len(addr) != 20 || addr == ([20]byte{}) {
+			t.Errorf("%s: invalid address %x", name, addr)
+		}
+		if seen[name] {
+			t.Errorf("%s: duplicate actor", name)
+		}
+		seen[name] = true
+
+		encoded := Base58(addr)
+		if encoded != want[name] {
+			t.Errorf("Base58(%s) = %s, want %s", name, encoded, want[name])
+		}
+		parsed, ok := ParseBase58(encoded)
+		if !ok || parsed != addr {
+			t.Errorf("ParseBase58(%s) = %x, %v, want %x with valid checksum", encoded, parsed, ok, addr)
+		}
+		if got := addr.ToString(); got != encoded {
+			t.Errorf("%s: Address.ToString() = %s, want %s", name, got, encoded)
+		}
+	}
+	if len(seen) != len(want) {
+		t.Errorf("Actors() returned %d actors, want %d", len(seen), len(want))
+	}
+}
+
+// TestParseBase58RejectsInvalid 测试校验和错误、长度错误与非法字符被拒绝
+func TestParseBase58RejectsInvalid(t *testing.T) {
+	valid := Base58(Alice())
+	corrupted := valid[:len(valid)-1] + "1"
+	if valid[len(valid)-1] == '1' {
+		corrupted = valid[:len(valid)-1] + "2"
+	}
+
+	short := encodeBase58(append([]byte{ADDRESS_VERSION}, make([]byte, 19)...))
+	for _, s := range []string{corrupted, short, "0OIl", ""} {
+		if _, ok := ParseBase58(s); ok {
+			t.Errorf("ParseBase58(%q) should fail", s)
+		}
+	}
+}
+
+// TestClockAndTokens 测试时钟推进与代币单位换算
+func TestClockAndTokens(t *testing.T) {
+	clock := NewClock()
+	if clock.Now() != Epoch {
+		t.Fatalf("NewClock().Now() = %d, want Epoch", clock.Now())
+	}
+	if got := clock.Advance(Days(30)); got != Epoch+30*86400 {
+		t.Errorf("Advance(Days(30)) = %d", got)
+	}
+
+	if got := StableToken6Decimals().Units(100); got != 100_000_000 {
+		t.Errorf("USDT Units(100) = %d, want 100000000", got)
+	}
+	if tok, ok := LookupToken(""); !ok || tok.Symbol != "WES" {
+		t.Errorf("LookupToken(native) = %+v, %v", tok, ok)
+	}
+
+	round := OpenRound("r1", MutualAidPlan(), clock.Now())
+	next := round.Next("r2")
+	if next.PeriodStart != round.PeriodEnd || next.PeriodEnd-next.PeriodStart != Days(30) {
+		t.Errorf("Next() = %+v, want contiguous 30-day round after %+v", next, round)
+	}
+}
@@ -0,0 +1,156 @@
+package fixtures
+
+import "github.com/weisyn/contract-sdk-go/framework"
+
+// 互助计划领域的记录构建器。
+//
+// 字段与状态字符串与 templates/standard/insurance/mutual-aid 的记录一致，
+// 构建器只产生值，编码由模板自身的 encode* 函数完成。
+
+// Plan 互助计划配置夹具
+type Plan struct {
+	PlanID              string
+	Name                string
+	Token               Token
+	CoverageAmount      uint64
+	ServiceFeeBP        uint64
+	SettlementPeriod    uint64
+	WaitingPeriod       uint64
+	MinMembers          uint64
+	MonthlyCapPerMember uint64
+}
+
+// MutualAidPlan 与 mutual-aid README 示例一致的计划配置（30天结算周期，8% 服务费）
+func MutualAidPlan() Plan {
+	return Plan{
+		PlanID:              "plan_xianghubao_001",
+		Name:                "相互宝互助计划",
+		Token:               NativeToken(),
+		CoverageAmount:      300000,
+		ServiceFeeBP:        800,
+		SettlementPeriod:    Days(30),
+		WaitingPeriod:       Days(1),
+		MinMembers:          1000,
+		MonthlyCapPerMember: 10000,
+	}
+}
+
+// Member 成员记录夹具
+type Member struct {
+	Address       framework.Address
+	Status        string
+	JoinTime      uint64
+	Tier          uint64
+	ActivationSeq uint64
+}
+
+// ActiveMember 在 Epoch 之前 joinedDaysAgo 天入会的 ACTIVE 成员（默认为 Alice）
+func ActiveMember(joinedDaysAgo uint64) Member {
+	return Member{
+		Address:  Alice(),
+		Status:   "ACTIVE",
+		JoinTime: Epoch - Days(joinedDaysAgo),
+	}
+}
+
+// For 返回地址替换为 addr 的成员
+func (m Member) For(addr framework.Address) Member {
+	m.Address = addr
+	return m
+}
+
+// WithTier 返回保障档位为 tier 的成员
+func (m Member) WithTier(tier uint64) Member {
+	m.Tier = tier
+	return m
+}
+
+// ActivatedAt 返回激活序号为 seq 的成员（0 表示引入激活序号之前激活的成员）
+func (m Member) ActivatedAt(seq uint64) Member {
+	m.ActivationSeq = seq
+	return m
+}
+
+// Exited 返回已退出的成员
+func (m Member) Exited() Member {
+	m.Status = "EXITED"
+	return m
+}
+
+// Pending 返回待审核的成员（尚未激活）
+func (m Member) Pending() Member {
+	m.Status = "PENDING"
+	m.ActivationSeq = 0
+	return m
+}
+
+// Claim 理赔案件记录夹具
+type Claim struct {
+	PlanID          string
+	ClaimID         string
+	Applicant       framework.Address
+	Status          string
+	RoundID         string
+	RequestedAmount uint64
+	ApprovedAmount  uint64
+	EventTime       uint64
+}
+
+// SubmittedClaim Alice 在 Epoch 提交、申请金额为 requested 的 SUBMITTED 案件
+func SubmittedClaim(claimID string, requested uint64) Claim {
+	return Claim{
+		PlanID:          MutualAidPlan().PlanID,
+		ClaimID:         claimID,
+		Applicant:       Alice(),
+		Status:          "SUBMITTED",
+		RequestedAmount: requested,
+		EventTime:       Epoch,
+	}
+}
+
+// Approved 返回在 roundID 轮次批准 amount 的案件
+func (c Claim) Approved(roundID string, amount uint64) Claim {
+	c.Status = "APPROVED"
+	c.RoundID = roundID
+	c.ApprovedAmount = amount
+	return c
+}
+
+// Rejected 返回已拒绝的案件
+func (c Claim) Rejected() Claim {
+	c.Status = "REJECTED"
+	c.ApprovedAmount = 0
+	return c
+}
+
+// Round 结算轮次记录夹具
+type Round struct {
+	PlanID      string
+	RoundID     string
+	Status      string
+	PeriodStart uint64
+	PeriodEnd   uint64
+	SnapshotSeq uint64
+}
+
+// OpenRound 从 start 开始、长度为计划结算周期的 OPEN 轮次
+func OpenRound(roundID string, plan Plan, start uint64) Round {
+	return Round{
+		PlanID:      plan.PlanID,
+		RoundID:     roundID,
+		Status:      "OPEN",
+		PeriodStart: start,
+		PeriodEnd:   start + plan.SettlementPeriod,
+	}
+}
+
+// Next 紧接本轮次的下一个 OPEN 轮次
+func (r Round) Next(roundID string) Round {
+	return Round{
+		PlanID:      r.PlanID,
+		RoundID:     roundID,
+		Status:      "OPEN",
+		PeriodStart: r.PeriodEnd,
+		PeriodEnd:   r.PeriodEnd + (r.PeriodEnd - r.PeriodStart),
+	}
+}
@@ -0,0 +1,42 @@
+package fixtures
+
+import "github.com/weisyn/contract-sdk-go/framework"
+
+// Token 代币夹具：代币ID及已登记的元数据
+type Token struct {
+	// ID 代币ID，原生币为空字符串
+	ID framework.TokenID
+	// Symbol 代币符号
+	Symbol string
+	// Decimals 精度（最小单位的小数位数）
+	Decimals uint8
+}
+
+// Units 将整数单位换算为最小单位金额，如 StableToken6Decimals().Units(100) = 100_000_000
+func (t Token) Units(whole uint64) framework.Amount {
+	amount := whole
+	for i := uint8(0); i < t.Decimals; i++ {
+		amount *= 10
+	}
+	return framework.Amount(amount)
+}
+
+// NativeToken 原生币 WES（空代币ID，9位精度，与 FormatWeiToDecimal 一致）
+func NativeToken() Token {
+	return Token{ID: "", Symbol: "WES", Decimals: 9}
+}
+
+// StableToken6Decimals 6位精度的稳定币
+func StableToken6Decimals() Token {
+	return Token{ID: "USDT", Symbol: "USDT", Decimals: 6}
+}
+
+// LookupToken 按代币ID查询已登记的代币夹具
+func LookupToken(id framework.TokenID) (Token, bool) {
+	for _, t := range []Token{NativeToken(), StableToken6Decimals()} {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Token{}, false
+}
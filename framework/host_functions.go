@@ -97,6 +97,9 @@ func stateGet(keyPtr uint32, keyLen uint32, valuePtr uint32, valueLen uint32) ui
 //go:wasmimport env state_get_from_chain
 func stateGetFromChain(stateIDPtr uint32, stateIDLen uint32, valuePtr uint32, valueLen uint32, versionPtr uint32) uint32
 
+//go:wasmimport env state_query_by_prefix
+func stateQueryByPrefix(prefixPtr uint32, prefixLen uint32, limit uint32, offset uint32, resultPtr uint32, resultLen uint32) uint32
+
 // ⚠️ **已删除**：state_put 宿主函数声明
 // 原因：违背WES架构原则，EUTXO模型无全局状态存储
 
@@ -150,6 +153,9 @@ func utxoLookupJSON(txIDPtr uint32, txIDLen uint32, index uint32, outputPtr uint
 //go:wasmimport env utxo_exists
 func utxoExists(txIDPtr uint32, txIDLen uint32, index uint32) uint32
 
+//go:wasmimport env utxo_list_by_owner
+func utxoListByOwner(ownerPtr uint32, tokenIDPtr uint32, tokenIDLen uint32, anyToken uint32, offset uint32, limit uint32, resultPtr uint32, resultLen uint32) uint32
+
 //go:wasmimport env resource_lookup
 func resourceLookup(contentHashPtr uint32, contentHashLen uint32, resourcePtr uint32, resourceSize uint32) uint32
 
@@ -392,7 +398,13 @@ func CheckABICompatibility(expectedVersion uint32) error {
 // 🎯 **修复说明**：
 //   - 严格校验宿主返回长度为 20 字节
 //   - 防御性错误处理，避免使用损坏的地址数据
+//   - 同一次调用内只执行一次宿主调用，之后返回缓存（见 caller_cache.go）
 func GetCaller() Address {
+	return callerFromCache(loadCaller)
+}
+
+// loadCaller 通过宿主调用 get_caller 读取调用者，失败时返回零地址
+func loadCaller() Address {
 	addr := malloc(20)
 	if addr == 0 {
 		return Address{}
@@ -536,21 +548,40 @@ func GetTxIndex() uint32 {
 // ===== 合约参数和返回值函数 =====
 
 // GetContractParams 获取合约调用参数
+//
+// 参数超过初始缓冲区时自动扩大缓冲区重读；超过 MAX_CONTRACT_PARAMS_SIZE 或读取失败时返回空参数，
+// 需要区分"无参数"与"读取失败"时使用 ReadContractParams
 func GetContractParams() *ContractParams {
-	// 分配足够大的缓冲区
-	bufSize := uint32(8192)
-	buffer := malloc(bufSize)
-	if buffer == 0 {
+	params, err := ReadContractParams()
+	if err != nil {
 		return NewContractParams([]byte{})
 	}
+	return params
+}
 
-	actualLen := getContractInitParams(buffer, bufSize)
-	if actualLen == 0 {
-		return NewContractParams([]byte{})
+// ReadContractParams 获取合约调用参数，参数过大或读取失败时返回错误
+//
+// **返回**：
+//   - ERROR_QUOTA_EXCEEDED: 参数超过 MAX_CONTRACT_PARAMS_SIZE（不会返回截断的参数）
+//   - ERROR_EXECUTION_FAILED: 缓冲区分配失败或宿主返回的数据不完整
+//
+// **注意**：只读取参数，不清空调用范围内的缓存与写入预算；导出函数入口先调用 BeginInvocation
+func ReadContractParams() (*ContractParams, error) {
+	data, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+		buffer := malloc(bufSize)
+		if buffer == 0 {
+			return nil, 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate contract params buffer")
+		}
+		n := getContractInitParams(buffer, bufSize)
+		if n == 0 {
+			return nil, 0, nil
+		}
+		return GetBytes(buffer, min(n, bufSize)), n, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	data := GetBytes(buffer, actualLen)
-	return NewContractParams(data)
+	return NewContractParams(data), nil
 }
 
 // SetReturnData 设置合约返回数据
@@ -578,145 +609,53 @@ func SetReturnString(s string) error {
 }
 
 // SetReturnJSON 设置JSON格式返回数据
+//
+// float64 保留 DEFAULT_JSON_FLOAT_DECIMALS 位小数，[]byte 输出为十六进制字符串，map 的键按字典序输出；
+// 包含不支持的类型时返回 ERROR_INVALID_PARAMS（含出错字段的路径），见 SetReturnJSONWithOptions
 func SetReturnJSON(obj interface{}) error {
-	jsonStr := serializeToJSON(obj)
-	if jsonStr == "" {
-		return NewContractError(ERROR_INVALID_PARAMS, "unsupported return type")
-	}
-	return SetReturnString(jsonStr)
+	return SetReturnJSONWithOptions(obj, DefaultJSONOptions())
 }
 
-// serializeToJSON 递归序列化为 JSON 字符串
+// SetReturnJSONSafe 设置JSON格式返回数据，Amount/uint64 序列化为十进制字符串
 //
-// 🎯 **修复说明**：
-//   - 新增对 Amount (uint64 别名) 的显式支持
-//   - 确保所有数值类型都能正确序列化
-func serializeToJSON(obj interface{}) string {
-	switch v := obj.(type) {
-	case string:
-		return `"` + escapeJSONString(v) + `"`
-	case Amount:
-		// 🔧 关键修复：显式支持 Amount 类型
-		return Uint64ToString(uint64(v))
-	case uint64:
-		return Uint64ToString(v)
-	case int64:
-		if v < 0 {
-			return "-" + Uint64ToString(uint64(-v))
-		}
-		return Uint64ToString(uint64(v))
-	case int:
-		return serializeToJSON(int64(v))
-	case uint32:
-		return Uint64ToString(uint64(v))
-	case int32:
-		return serializeToJSON(int64(v))
-	case bool:
-		if v {
-			return "true"
-		}
-		return "false"
-	case nil:
-		return "null"
-	case map[string]interface{}:
-		return serializeMapToJSON(v)
-	case map[string]string:
-		// 特化处理纯字符串 map
-		result := make(map[string]interface{}, len(v))
-		for k, val := range v {
-			result[k] = val
-		}
-		return serializeMapToJSON(result)
-	case map[string]uint64:
-		// 特化处理纯数字 map
-		result := make(map[string]interface{}, len(v))
-		for k, val := range v {
-			result[k] = val
-		}
-		return serializeMapToJSON(result)
-	case []interface{}:
-		return serializeArrayToJSON(v)
-	case []string:
-		// 特化处理字符串数组
-		arr := make([]interface{}, len(v))
-		for i, s := range v {
-			arr[i] = s
-		}
-		return serializeArrayToJSON(arr)
-	case []uint64:
-		// 特化处理数字数组
-		arr := make([]interface{}, len(v))
-		for i, n := range v {
-			arr[i] = n
-		}
-		return serializeArrayToJSON(arr)
-	default:
-		return ""
-	}
-}
-
-// serializeMapToJSON 序列化 map 为 JSON 对象
-func serializeMapToJSON(m map[string]interface{}) string {
-	if len(m) == 0 {
-		return "{}"
-	}
-
-	fields := make([]string, 0, len(m))
-	for key, value := range m {
-		valueJSON := serializeToJSON(value)
-		if valueJSON != "" {
-			fields = append(fields, `"`+escapeJSONString(key)+`":`+valueJSON)
-		}
-	}
-
-	result := "{"
-	for i, field := range fields {
-		if i > 0 {
-			result += ","
-		}
-		result += field
-	}
-	result += "}"
-	return result
-}
-
-// serializeArrayToJSON 序列化数组为 JSON 数组
-func serializeArrayToJSON(arr []interface{}) string {
-	if len(arr) == 0 {
-		return "[]"
-	}
-
-	result := "["
-	for i, item := range arr {
-		if i > 0 {
-			result += ","
-		}
-		result += serializeToJSON(item)
-	}
-	result += "]"
-	return result
+// 🎯 **用途**：返回可能超过 2^53 的代币金额，JavaScript 等以双精度解析数字的客户端不会丢失精度
+//
+// **注意**：
+//   - Amount、uint64（含 []uint64、map[string]uint64 中的元素）一律输出为字符串，
+//     与数值大小无关，客户端按固定类型解析；其他整数类型仍输出为数字
+//   - 客户端可用 ParseUint64 或任意大整数库还原
+//
+// **示例**：
+//
+//	framework.SetReturnJSONSafe(map[string]interface{}{
+//	    "balance": framework.Amount(18_000_000_000_000_000_000), // "balance":"18000000000000000000"
+//	})
+func SetReturnJSONSafe(obj interface{}) error {
+	opts := DefaultJSONOptions()
+	opts.QuoteUint64 = true
+	return SetReturnJSONWithOptions(obj, opts)
 }
 
-// escapeJSONString 转义 JSON 字符串中的特殊字符
-func escapeJSONString(s string) string {
-	result := ""
-	for _, c := range s {
-		switch c {
-		case '"':
-			result += `\"`
-		case '\\':
-			result += `\\`
-		case '\n':
-			result += `\n`
-		case '\r':
-			result += `\r`
-		case '\t':
-			result += `\t`
-		default:
-			result += string(c)
-		}
+// SetReturnJSONWithOptions 按指定选项设置JSON格式返回数据
+//
+// 🎯 **用途**：需要调整 []byte 编码（十六进制 / base64）或浮点数小数位数时使用
+//
+// **返回**：
+//   - error: 包含不支持的类型（如结构体、指针）、NaN/Inf 或选项无效时返回 ERROR_INVALID_PARAMS，
+//     错误信息含出错字段的路径（如 "$.pool.rate"），不写入返回数据
+//
+// **示例**：
+//
+//	framework.SetReturnJSONWithOptions(map[string]interface{}{
+//	    "tx_hash": txHash,  // "tx_hash":"q83v..."（base64）
+//	    "apr":     0.0525,  // "apr":0.0525
+//	}, framework.JSONOptions{BytesEncoding: framework.JSON_BYTES_BASE64, FloatDecimals: 4})
+func SetReturnJSONWithOptions(obj interface{}, opts JSONOptions) error {
+	jsonStr, err := marshalJSON(obj, opts)
+	if err != nil {
+		return err
 	}
-	return result
+	return SetReturnString(jsonStr)
 }
 
 // ===== 事件发出函数 =====
@@ -741,6 +680,21 @@ func EmitEvent(event *Event) error {
 	return nil
 }
 
+// emitCanonicalEvent 发出已规范化序列化的事件载荷（见 EmitEventOrAnchor）
+func emitCanonicalEvent(name string, payload []byte) error {
+	eventPtr, eventLen := AllocateBytes(payload)
+	if eventPtr == 0 {
+		return NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate event data")
+	}
+
+	result := emitEvent(eventPtr, eventLen)
+	if result != SUCCESS {
+		return NewContractError(result, "failed to emit event "+name)
+	}
+
+	return nil
+}
+
 // EmitSimpleEvent 发出简单事件
 func EmitSimpleEvent(name string, data map[string]string) error {
 	event := NewEvent(name)
@@ -765,16 +719,16 @@ func EmitSimpleEvent(name string, data map[string]string) error {
 //
 // 参数：
 //   - address: 要查询的地址
-//   - tokenID: 代币ID（空字符串""表示查询原生币）
+//   - tokenID: 代币ID（NativeTokenID() 即空字符串""表示查询原生币）
 func QueryBalance(address Address, tokenID TokenID) Amount {
 	addressPtr, _ := AllocateBytes(address.ToBytes())
 	if addressPtr == 0 {
 		return 0
 	}
 
-	// tokenID可以为空（查询原生币），所以tokenIDPtr=0是合法的
+	// tokenID可以为原生币（NativeTokenID），所以tokenIDPtr=0是合法的
 	var tokenIDPtr, tokenIDLen uint32
-	if tokenID != "" {
+	if !IsNativeToken(tokenID) {
 		tokenIDPtr, tokenIDLen = AllocateString(string(tokenID))
 		if tokenIDPtr == 0 {
 			// 分配失败
@@ -790,7 +744,13 @@ func QueryBalance(address Address, tokenID TokenID) Amount {
 // ===== 状态查询函数（可选，仅限只读操作）=====
 
 // GetState 获取状态数据（只读）
+//
+// 本次调用中已读取或已暂存的状态直接从读取缓存返回（见 state_cache.go）
 func GetState(key string) ([]byte, error) {
+	if value, ok := cachedStateValue(key); ok {
+		return value, nil
+	}
+
 	keyPtr, keyLen := AllocateString(key)
 	if keyPtr == 0 {
 		return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate key")
@@ -811,6 +771,7 @@ func GetState(key string) ([]byte, error) {
 	// 简化实现：假设实际长度存储在特定位置
 	// 实际实现中需要根据具体的宿主函数规范来处理
 	value := GetBytes(valuePtr, maxValueSize)
+	cacheStateValue(key, value)
 	return value, nil
 }
 
@@ -830,6 +791,8 @@ func GetState(key string) ([]byte, error) {
 //   - 查询链上已确认的交易，查找包含匹配stateID的StateOutput
 //   - 返回版本号最高的状态值
 //   - 如果状态不存在，返回错误
+//   - 本次调用中已读取的状态（含不存在）与已暂存的状态输出直接从读取缓存返回，
+//     暂存的状态输出返回待提交的值与版本号（见 state_cache.go）
 //
 // **示例**：
 //
@@ -845,6 +808,14 @@ func GetStateFromChain(stateID []byte) ([]byte, uint64, error) {
 		return nil, 0, NewContractError(ERROR_INVALID_PARAMS, "stateID cannot be empty")
 	}
 
+	// 查询读取缓存
+	if value, version, missing, ok := cachedStateVersion(string(stateID)); ok {
+		if missing {
+			return nil, 0, NewContractError(ERROR_NOT_FOUND, "failed to get state from chain")
+		}
+		return trimTrailingZeros(value), version, nil
+	}
+
 	// 分配内存
 	stateIDPtr, stateIDLen := AllocateBytes(stateID)
 	if stateIDPtr == 0 {
@@ -867,6 +838,9 @@ func GetStateFromChain(stateID []byte) ([]byte, uint64, error) {
 	// 调用宿主函数
 	result := stateGetFromChain(stateIDPtr, stateIDLen, valuePtr, maxValueSize, versionPtr)
 	if result != SUCCESS {
+		if result == ERROR_NOT_FOUND {
+			cacheStateMissing(string(stateID))
+		}
 		return nil, 0, NewContractError(result, "failed to get state from chain")
 	}
 
@@ -880,9 +854,63 @@ func GetStateFromChain(stateID []byte) ([]byte, uint64, error) {
 	version := uint64(versionBytes[0])<<56 | uint64(versionBytes[1])<<48 | uint64(versionBytes[2])<<40 | uint64(versionBytes[3])<<32 |
 		uint64(versionBytes[4])<<24 | uint64(versionBytes[5])<<16 | uint64(versionBytes[6])<<8 | uint64(versionBytes[7])
 
+	cacheStateVersion(string(stateID), value, version)
 	return value, version, nil
 }
 
+// QueryStatesByPrefix 按状态ID前缀分页查询链上状态
+//
+// 🎯 **用途**：列表查询（计划成员、轮次案件、提案等），无需另外维护索引
+//
+// **参数**：
+//   - prefix: 状态ID前缀（不能为空）
+//   - limit: 本页最多返回条数，1 到 MAX_STATE_QUERY_LIMIT
+//   - offset: 跳过的匹配条数（按状态ID字典序）
+//
+// **返回**：
+//   - []StateEntry: 匹配的状态（状态ID、值、版本），按状态ID字典序；少于 limit 条表示已到末尾
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、本页结果超过 MAX_STATE_QUERY_RESULT_SIZE（ERROR_QUOTA_EXCEEDED）、
+//     宿主返回的结果不完整或格式错误（ERROR_EXECUTION_FAILED）
+//
+// **注意**：
+//   - 前缀按字节匹配：计划 "plan_a" 的前缀 "claim_plan_a_" 也会匹配计划 "plan_a_b" 的状态，调用方应校验解码后的记录
+//   - 结果不经过调用内读取缓存，也不写入缓存
+//
+// **示例**：
+//
+//	entries, err := framework.QueryStatesByPrefix([]byte("proposal_"), 20, 0)
+//	if err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+//	for _, e := range entries {
+//	    // 解码 e.Value ...
+//	}
+func QueryStatesByPrefix(prefix []byte, limit, offset uint32) ([]StateEntry, error) {
+	if err := validateStateQuery(prefix, limit); err != nil {
+		return nil, err
+	}
+	prefixPtr, prefixLen := AllocateBytes(prefix)
+	if prefixPtr == 0 {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate prefix")
+	}
+
+	data, err := readHostBuffer(func(bufSize uint32) ([]byte, uint32, error) {
+		resultPtr := malloc(bufSize)
+		if resultPtr == 0 {
+			return nil, 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate state query buffer")
+		}
+		n := stateQueryByPrefix(prefixPtr, prefixLen, limit, offset, resultPtr, bufSize)
+		if n == 0 {
+			return nil, 0, nil
+		}
+		return GetBytes(resultPtr, min(n, bufSize)), n, nil
+	}, stateQueryInitialBufSize, MAX_STATE_QUERY_RESULT_SIZE, "state query result")
+	if err != nil {
+		return nil, err
+	}
+	return parseStateEntries(data, prefix, limit)
+}
+
 // trimTrailingZeros 移除尾部的零字节
 func trimTrailingZeros(data []byte) []byte {
 	// 从后往前查找第一个非零字节
@@ -1000,11 +1028,10 @@ func TokenTransfer(tokenID TokenID, to Address, amount Amount) error {
 //	}
 func TokenMint(tokenID TokenID, to Address, amount Amount) error {
 	// 参数验证
-	zeroAddr := Address{}
-	if to == zeroAddr {
+	if to.IsZero() {
 		return NewContractError(ERROR_INVALID_PARAMS, "to address cannot be zero")
 	}
-	if tokenID == "" {
+	if IsNativeToken(tokenID) {
 		return NewContractError(ERROR_INVALID_PARAMS, "tokenID cannot be empty")
 	}
 	if amount == 0 {
@@ -1081,11 +1108,10 @@ func NFTMint(tokenID TokenID, to Address, metadata map[string]string) error {
 	}
 
 	// 参数验证
-	zeroAddr := Address{}
-	if to == zeroAddr {
+	if to.IsZero() {
 		return NewContractError(ERROR_INVALID_PARAMS, "to address cannot be zero")
 	}
-	if tokenID == "" {
+	if IsNativeToken(tokenID) {
 		return NewContractError(ERROR_INVALID_PARAMS, "tokenID cannot be empty")
 	}
 
@@ -1147,8 +1173,7 @@ func NFTTransfer(tokenID TokenID, from, to Address) error {
 // ValidateAddress 验证地址格式
 func ValidateAddress(addr Address) error {
 	// 简单验证：检查是否为零地址
-	zeroAddr := Address{}
-	if addr == zeroAddr {
+	if addr.IsZero() {
 		return NewContractError(ERROR_INVALID_PARAMS, "invalid zero address")
 	}
 	return nil
@@ -1161,11 +1186,3 @@ func ValidateAmount(amount Amount) error {
 	}
 	return nil
 }
-
-// ValidateTokenID 验证代币ID
-func ValidateTokenID(tokenID TokenID) error {
-	if len(string(tokenID)) == 0 {
-		return NewContractError(ERROR_INVALID_PARAMS, "invalid empty token ID")
-	}
-	return nil
-}
@@ -4,56 +4,9 @@
 
 package framework
 
-// 该文件为非TinyGo/非WASM环境提供空实现，使得 go build ./... 能通过编译。
+// 该文件为非TinyGo/非WASM环境提供宿主函数的占位实现，使得 go build ./... 能通过编译。
 //
-// 注意：这些类型定义与 contract_base.go 中的定义保持一致
-// 但由于 build tag 的限制，需要在这里重新定义
-
-// 基础类型定义（非WASM环境）
-type Address [20]byte
-type Hash [32]byte
-type TokenID string
-type Amount uint64
-
-// ContractParams 合约参数（非WASM环境）
-type ContractParams struct {
-	data []byte
-}
-
-// NewContractParams 创建合约参数（非WASM环境）
-func NewContractParams(data []byte) *ContractParams {
-	return &ContractParams{data: data}
-}
-
-// Event 事件（非WASM环境）
-type Event struct {
-	Name string
-	Data map[string]interface{}
-}
-
-// NewEvent 创建事件（非WASM环境）
-func NewEvent(name string) *Event {
-	return &Event{
-		Name: name,
-		Data: make(map[string]interface{}),
-	}
-}
-
-// 错误码定义（非WASM环境）
-const (
-	SUCCESS                    = 0
-	ERROR_INVALID_PARAMS       = 1
-	ERROR_INSUFFICIENT_BALANCE = 2
-	ERROR_UNAUTHORIZED         = 3
-	ERROR_NOT_FOUND            = 4
-	ERROR_ALREADY_EXISTS       = 5
-	ERROR_EXECUTION_FAILED     = 6
-	ERROR_INVALID_STATE        = 7
-	ERROR_TIMEOUT              = 8
-	ERROR_NOT_IMPLEMENTED      = 9
-	ERROR_PERMISSION_DENIED    = 10
-	ERROR_UNKNOWN              = 999
-)
+// 基础类型、ContractParams、Event、ContractError 与WASM环境共用 contract_base.go 中的定义。
 
 // 注意：这些实现仅用于宿主环境的编译占位，不会在合约WASM中使用。
 
@@ -118,18 +71,42 @@ func CheckABICompatibility(expectedVersion uint32) error { return nil }
 // GetCaller 获取合约调用者地址（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func GetCaller() Address { return Address{} }
+//
+// 与WASM实现一致在同一次调用内缓存调用者（见 caller_cache.go）
+func GetCaller() Address {
+	return callerFromCache(func() Address {
+		if mockHost != nil {
+			return mockHost.caller
+		}
+		return Address{}
+	})
+}
 
 // GetContractAddress 获取当前合约地址（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func GetContractAddress() Address { return Address{} }
+func GetContractAddress() Address {
+	if mockHost != nil {
+		return mockHost.ContractAddress
+	}
+	return Address{}
+}
 
 // GetTimestamp 获取当前时间戳（占位实现）
-func GetTimestamp() uint64 { return 0 }
+func GetTimestamp() uint64 {
+	if mockHost != nil {
+		return mockHost.Timestamp
+	}
+	return 0
+}
 
 // GetBlockHeight 获取当前区块高度（占位实现）
-func GetBlockHeight() uint64 { return 0 }
+func GetBlockHeight() uint64 {
+	if mockHost != nil {
+		return mockHost.BlockHeight
+	}
+	return 0
+}
 
 // GetBlockHash 获取指定高度的区块哈希（占位实现）
 //
@@ -139,29 +116,118 @@ func GetBlockHash(height uint64) Hash { return Hash{} }
 // GetContractParams 获取合约调用参数（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func GetContractParams() *ContractParams { return NewContractParams([]byte{}) }
+func GetContractParams() *ContractParams {
+	params, err := ReadContractParams()
+	if err != nil {
+		return NewContractParams([]byte{})
+	}
+	return params
+}
+
+// ReadContractParams 获取合约调用参数，参数超过 MAX_CONTRACT_PARAMS_SIZE 时返回错误（占位实现）
+//
+// 与WASM实现一致按缓冲区分段读取模拟宿主中的参数，不清空调用范围内的缓存；
+// 缓冲区经 Malloc 分配，可用宿主调用拦截器注入分配失败
+func ReadContractParams() (*ContractParams, error) {
+	if mockHost == nil {
+		return NewContractParams([]byte{}), nil
+	}
+	data, err := readContractParams(func(bufSize uint32) ([]byte, uint32, error) {
+		if Malloc(bufSize) == 0 {
+			return nil, 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate contract params buffer")
+		}
+		return mockHost.params[:min(uint32(len(mockHost.params)), bufSize)], uint32(len(mockHost.params)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewContractParams(data), nil
+}
 
 // SetReturnData 设置返回数据（占位实现）
-func SetReturnData(data []byte) error { return nil }
+func SetReturnData(data []byte) error {
+	if mockHost != nil {
+		mockHost.setReturn(data)
+	}
+	return nil
+}
 
 // SetReturnString 设置字符串返回数据（占位实现）
-func SetReturnString(s string) error { return nil }
+func SetReturnString(s string) error { return SetReturnData([]byte(s)) }
 
 // SetReturnJSON 设置JSON返回数据（占位实现）
-func SetReturnJSON(obj interface{}) error { return nil }
+func SetReturnJSON(obj interface{}) error {
+	return SetReturnJSONWithOptions(obj, DefaultJSONOptions())
+}
+
+// SetReturnJSONSafe 设置JSON返回数据，Amount/uint64 输出为字符串（占位实现）
+func SetReturnJSONSafe(obj interface{}) error {
+	opts := DefaultJSONOptions()
+	opts.QuoteUint64 = true
+	return SetReturnJSONWithOptions(obj, opts)
+}
+
+// SetReturnJSONWithOptions 按指定选项设置JSON返回数据（占位实现）
+func SetReturnJSONWithOptions(obj interface{}, opts JSONOptions) error {
+	jsonStr, err := marshalJSON(obj, opts)
+	if err != nil {
+		return err
+	}
+	return SetReturnString(jsonStr)
+}
 
 // EmitEvent 发出事件（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func EmitEvent(event *Event) error { return nil }
+func EmitEvent(event *Event) error {
+	if event == nil {
+		return nil
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_EMIT_EVENT, EventName: event.Name}); err != nil {
+		return err
+	}
+	if mockHost != nil {
+		data := make(map[string]interface{}, len(event.Data))
+		for k, v := range event.Data {
+			data[k] = v
+		}
+		mockHost.recordEvent(MockEvent{Name: event.Name, Data: data})
+	}
+	return nil
+}
+
+// emitCanonicalEvent 发出已规范化序列化的事件载荷（占位实现）
+func emitCanonicalEvent(name string, payload []byte) error {
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_EMIT_EVENT, EventName: name}); err != nil {
+		return err
+	}
+	if mockHost != nil {
+		mockHost.recordEvent(MockEvent{Name: name, Payload: append([]byte(nil), payload...)})
+	}
+	return nil
+}
 
 // EmitSimpleEvent 发出简单事件（占位实现）
-func EmitSimpleEvent(name string, data map[string]string) error { return nil }
+func EmitSimpleEvent(name string, data map[string]string) error {
+	event := NewEvent(name)
+	for key, value := range data {
+		event.AddStringField(key, value)
+	}
+	return EmitEvent(event)
+}
 
 // CreateUTXO 创建UTXO输出（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func CreateUTXO(recipient Address, amount Amount, tokenID TokenID) error { return nil }
+func CreateUTXO(recipient Address, amount Amount, tokenID TokenID) error {
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_CREATE_UTXO_OUTPUT}); err != nil {
+		return err
+	}
+	if mockHost != nil {
+		return mockHost.transfer(MockTransfer{To: recipient, TokenID: tokenID, Amount: amount})
+	}
+	return nil
+}
 
 // TransferUTXO 执行UTXO转移（占位实现）
 //nolint:golint // 类型定义在文件前面，linter误报
@@ -171,44 +237,207 @@ func CreateUTXO(recipient Address, amount Amount, tokenID TokenID) error { retur
 // QueryBalance 查询UTXO余额（占位实现）
 //
 //nolint:golint // 类型定义在文件前面，linter误报
-func QueryBalance(address Address, tokenID TokenID) Amount { return 0 }
+func QueryBalance(address Address, tokenID TokenID) Amount {
+	if mockHost != nil {
+		return mockHost.Balance(address, tokenID)
+	}
+	return 0
+}
+
+// QueryUTXOBalance 查询地址的UTXO余额（占位实现）
+func QueryUTXOBalance(address Address, tokenID TokenID) Amount {
+	return QueryBalance(address, tokenID)
+}
+
+// QueryUTXOsByAddress 查询地址的所有UTXO（占位实现）
+//
+// 与WASM实现一致逐页调用 ListUTXOsByOwner
+func QueryUTXOsByAddress(address Address, tokenID TokenID) ([]UTXO, error) {
+	if err := validateUTXOList(address, MAX_UTXO_LIST_LIMIT); err != nil {
+		return nil, err
+	}
+	return collectUTXOs(func(offset, limit uint32) ([]UTXO, error) {
+		return ListUTXOsByOwner(address, tokenID, limit, offset)
+	})
+}
+
+// ListUTXOsByOwner 分页列出地址持有的资产UTXO（占位实现）
+//
+// 安装 MockHost 时把每个非零余额视为一个UTXO，结果经与WASM实现相同的 JSON 编解码
+func ListUTXOsByOwner(owner Address, tokenID TokenID, limit, offset uint32) ([]UTXO, error) {
+	if err := validateUTXOList(owner, limit); err != nil {
+		return nil, err
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_UTXO_LIST_BY_OWNER}); err != nil {
+		return nil, err
+	}
+	if mockHost == nil {
+		return []UTXO{}, nil
+	}
+	result := mockHost.listUTXOsByOwner(owner, tokenID, limit, offset)
+	data, err := readHostBuffer(func(bufSize uint32) ([]byte, uint32, error) {
+		return result[:min(uint32(len(result)), bufSize)], uint32(len(result)), nil
+	}, utxoListInitialBufSize, MAX_UTXO_LIST_RESULT_SIZE, "utxo list result")
+	if err != nil {
+		return nil, err
+	}
+	return parseUTXOList(data, owner, tokenID, limit)
+}
 
 // GetState 获取状态数据（占位实现）
-func GetState(key string) ([]byte, error) { return []byte{}, nil }
+//
+// 与WASM实现一致先查询读取缓存，未命中时才执行宿主调用 state_get
+func GetState(key string) ([]byte, error) {
+	if value, ok := cachedStateValue(key); ok {
+		return value, nil
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_STATE_GET, StateID: []byte(key)}); err != nil {
+		return nil, err
+	}
+	value := []byte{}
+	if mockHost != nil {
+		if v, _, ok := mockHost.State(key); ok {
+			value = v
+		}
+	}
+	cacheStateValue(key, value)
+	return value, nil
+}
 
 // GetStateFromChain 从链上查询历史状态（占位实现）
+//
+// 与WASM实现一致先查询读取缓存，未命中时才执行宿主调用 state_get_from_chain
 func GetStateFromChain(stateID []byte) ([]byte, uint64, error) {
-	return []byte{}, 0, nil
+	if len(stateID) == 0 {
+		return nil, 0, NewContractError(ERROR_INVALID_PARAMS, "stateID cannot be empty")
+	}
+	if value, version, missing, ok := cachedStateVersion(string(stateID)); ok {
+		if missing {
+			return nil, 0, NewContractError(ERROR_NOT_FOUND, "failed to get state from chain")
+		}
+		return trimStubTrailingZeros(value), version, nil
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_STATE_GET_FROM_CHAIN, StateID: stateID}); err != nil {
+		return nil, 0, err
+	}
+	if mockHost == nil {
+		return []byte{}, 0, nil
+	}
+	value, version, ok := mockHost.State(string(stateID))
+	if !ok {
+		cacheStateMissing(string(stateID))
+		return nil, 0, NewContractError(ERROR_NOT_FOUND, "failed to get state from chain")
+	}
+	cacheStateVersion(string(stateID), value, version)
+	return trimStubTrailingZeros(value), version, nil
+}
+
+// QueryStatesByPrefix 按状态ID前缀分页查询链上状态（占位实现）
+//
+// 安装 MockHost 时按状态ID字典序查询已提交的状态，结果经与WASM实现相同的 JSON 编解码
+func QueryStatesByPrefix(prefix []byte, limit, offset uint32) ([]StateEntry, error) {
+	if err := validateStateQuery(prefix, limit); err != nil {
+		return nil, err
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_STATE_QUERY_BY_PREFIX, StateID: prefix}); err != nil {
+		return nil, err
+	}
+	if mockHost == nil {
+		return []StateEntry{}, nil
+	}
+	result := mockHost.queryStatesByPrefix(prefix, limit, offset)
+	data, err := readHostBuffer(func(bufSize uint32) ([]byte, uint32, error) {
+		return result[:min(uint32(len(result)), bufSize)], uint32(len(result)), nil
+	}, stateQueryInitialBufSize, MAX_STATE_QUERY_RESULT_SIZE, "state query result")
+	if err != nil {
+		return nil, err
+	}
+	return parseStateEntries(data, prefix, limit)
+}
+
+// trimStubTrailingZeros 与WASM实现一致去掉状态值尾部的零字节
+func trimStubTrailingZeros(value []byte) []byte {
+	for len(value) > 0 && value[len(value)-1] == 0 {
+		value = value[:len(value)-1]
+	}
+	return value
 }
 
 // GetStateVersion 获取状态的当前版本号（占位实现）
 func GetStateVersion(stateID []byte) (uint64, error) {
-	return 0, nil
+	_, version, err := GetStateFromChain(stateID)
+	if err != nil {
+		return 0, nil
+	}
+	return version, nil
 }
 
 // IncrementStateVersion 递增状态版本号（占位实现）
 func IncrementStateVersion(stateID []byte) (uint64, error) {
-	return 1, nil
+	version, err := GetStateVersion(stateID)
+	if err != nil {
+		return 0, err
+	}
+	return version + 1, nil
 }
 
 // ⚠️ **已删除**：PutState 和 StateExists
 // 原因：违背WES架构原则，EUTXO模型无全局状态存储
 
 // Malloc 分配内存（占位实现）
-func Malloc(size uint32) uint32 { return malloc(size) }
+func Malloc(size uint32) uint32 {
+	if interceptHostCall(HostCall{Name: HOST_CALL_MALLOC, Size: size}) != nil {
+		return 0
+	}
+	return malloc(size)
+}
 
 // AppendStateOutputSimple 追加状态输出（占位实现）
 func AppendStateOutputSimple(stateID []byte, version uint64, execHash []byte, parentHash []byte) (uint32, error) {
-	return 0, nil
+	return stubAppendStateOutput(stateID, version, execHash)
 }
 
 // AppendStateOutput 追加状态输出（占位实现）
 func AppendStateOutput(stateID []byte, version uint64, execHash []byte, zkProof []byte, parentHash []byte) (uint32, error) {
-	return 0, nil
+	return stubAppendStateOutput(stateID, version, execHash)
+}
+
+// stubAppendStateOutput 占位状态输出：与WASM实现一致计入写入预算，再暂存输出
+func stubAppendStateOutput(stateID []byte, version uint64, payload []byte) (uint32, error) {
+	if len(stateID) == 0 {
+		return 0xFFFFFFFF, NewContractError(ERROR_INVALID_PARAMS, "stateID cannot be empty")
+	}
+	if err := chargeWriteBudget(len(stateID) + len(payload)); err != nil {
+		return 0xFFFFFFFF, err
+	}
+	return stageStateOutput(stateID, version, payload)
+}
+
+// stageStateOutput 与WASM实现一致先分配 stateID 与 execHash 内存，再调用 append_state_output，
+// 成功后记录暂存写入；安装 MockHost 时同时记录状态输出
+func stageStateOutput(stateID []byte, version uint64, payload []byte) (uint32, error) {
+	if Malloc(uint32(len(stateID))) == 0 {
+		return 0xFFFFFFFF, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate stateID")
+	}
+	if Malloc(32) == 0 {
+		return 0xFFFFFFFF, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate execHash")
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_APPEND_STATE_OUTPUT, StateID: stateID}); err != nil {
+		return 0xFFFFFFFF, err
+	}
+	stagedStateWrites = append(stagedStateWrites, string(stateID))
+	cacheStagedState(stateID, version, payload)
+	if mockHost != nil {
+		mockHost.recordWrite(stateID, version, payload)
+	}
+	return uint32(len(stagedStateWrites) - 1), nil
 }
 
 // AppendResourceOutput 追加资源输出（占位实现）
 func AppendResourceOutput(resourceBytes []byte, owner Address, lockingBytes []byte) (uint32, error) {
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_APPEND_RESOURCE_OUTPUT}); err != nil {
+		return 0xFFFFFFFF, err
+	}
 	return 0, nil
 }
 
@@ -223,6 +452,11 @@ func BatchCreateOutputsSimple(items []struct {
 
 // StateGet 状态只读查询（占位实现）
 func StateGet(key []byte) ([]byte, error) {
+	if mockHost != nil {
+		if value, _, ok := mockHost.State(string(key)); ok {
+			return value, nil
+		}
+	}
 	return nil, nil
 }
 
@@ -249,3 +483,63 @@ func GetTxHash() Hash { return Hash{} }
 
 // GetTxIndex 获取当前交易在区块内的索引（占位实现）
 func GetTxIndex() uint32 { return 0 }
+
+// DeclareExternalState 声明外部状态预期（占位实现）
+//
+// 安装 MockHost 后在本次调用内记录声明并返回声明ID，未安装时返回 ERROR_EXECUTION_FAILED
+func DeclareExternalState(claim *ExternalStateClaim) ([]byte, error) {
+	if claim == nil {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "claim cannot be nil")
+	}
+	if claim.ClaimType == "" {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "claimType cannot be empty")
+	}
+	if claim.Source == "" {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "source cannot be empty")
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_DECLARE_EXTERNAL_STATE}); err != nil {
+		return nil, err
+	}
+	if mockHost == nil {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to declare external state")
+	}
+	return mockHost.declareExternal(claim), nil
+}
+
+// ProvideEvidence 提供验证佐证（占位实现）
+//
+// APISignature 与 ResponseHash 均非空时视为佐证有效
+func ProvideEvidence(claimID []byte, evidence *Evidence) error {
+	if len(claimID) == 0 {
+		return NewContractError(ERROR_INVALID_PARAMS, "claimID cannot be empty")
+	}
+	if evidence == nil {
+		return NewContractError(ERROR_INVALID_PARAMS, "evidence cannot be nil")
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_PROVIDE_EVIDENCE}); err != nil {
+		return err
+	}
+	if mockHost == nil {
+		return NewContractError(ERROR_EXECUTION_FAILED, "failed to provide evidence")
+	}
+	if len(evidence.APISignature) == 0 || len(evidence.ResponseHash) == 0 {
+		return NewContractError(ERROR_PERMISSION_DENIED, "failed to provide evidence")
+	}
+	return mockHost.verifyExternal(claimID)
+}
+
+// QueryControlledState 查询受控外部状态（占位实现）
+//
+// 返回 MockHost.SetExternalResponse 为声明数据源预设的响应
+func QueryControlledState(claimID []byte) ([]byte, error) {
+	if len(claimID) == 0 {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "claimID cannot be empty")
+	}
+	if err := interceptHostCall(HostCall{Name: HOST_CALL_QUERY_CONTROLLED_STATE}); err != nil {
+		return nil, err
+	}
+	if mockHost == nil {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to query controlled state")
+	}
+	return mockHost.queryExternal(claimID)
+}
@@ -0,0 +1,88 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+// 该文件为非WASM环境的占位宿主函数提供调用拦截与写入暂存记录，
+// 使测试可以在宿主调用处注入故障（见 framework/testing.FaultPlan），
+// 覆盖合约中宿主调用失败后的错误分支。WASM环境不包含该文件。
+
+// 宿主调用名称（与 //go:wasmimport 名称一致）
+const (
+	HOST_CALL_MALLOC                 = "malloc"
+	HOST_CALL_APPEND_STATE_OUTPUT    = "append_state_output"
+	HOST_CALL_APPEND_RESOURCE_OUTPUT = "append_resource_output"
+	HOST_CALL_CREATE_UTXO_OUTPUT     = "create_utxo_output"
+	HOST_CALL_EMIT_EVENT             = "emit_event"
+	HOST_CALL_STATE_GET              = "state_get"
+	HOST_CALL_STATE_GET_FROM_CHAIN   = "state_get_from_chain"
+	HOST_CALL_STATE_QUERY_BY_PREFIX  = "state_query_by_prefix"
+	HOST_CALL_UTXO_LIST_BY_OWNER     = "utxo_list_by_owner"
+	HOST_CALL_DECLARE_EXTERNAL_STATE = "host_declare_external_state"
+	HOST_CALL_PROVIDE_EVIDENCE       = "host_provide_evidence"
+	HOST_CALL_QUERY_CONTROLLED_STATE = "host_query_controlled_state"
+)
+
+// HostCall 一次宿主调用的描述
+type HostCall struct {
+	// Name 宿主函数名，见 HOST_CALL_* 常量
+	Name string
+	// Size malloc 的分配字节数
+	Size uint32
+	// EventName emit_event 的事件名
+	EventName string
+	// StateID append_state_output、state_get、state_get_from_chain 的状态ID，state_query_by_prefix 的前缀
+	StateID []byte
+}
+
+// HostInterceptor 宿主调用拦截器
+//
+// 🎯 **用途**：在占位宿主函数执行前被调用，返回非 nil 错误时该宿主调用按失败处理：
+//   - malloc 返回 0
+//   - append_state_output / append_resource_output 返回 0xFFFFFFFF 及该错误
+//   - create_utxo_output / emit_event 返回该错误
+//   - state_get / state_get_from_chain 返回该错误（读取缓存命中时不执行宿主调用，也不经过拦截器）
+//   - state_query_by_prefix 返回该错误
+//   - host_declare_external_state / host_provide_evidence / host_query_controlled_state 返回该错误
+type HostInterceptor interface {
+	BeforeHostCall(call HostCall) error
+}
+
+var (
+	hostInterceptor   HostInterceptor
+	stagedStateWrites []string
+)
+
+// SetHostInterceptor 安装宿主调用拦截器
+//
+// **返回**：恢复之前拦截器的函数，通常配合 defer 使用
+//
+// **示例**：
+//
+//	restore := framework.SetHostInterceptor(plan)
+//	defer restore()
+func SetHostInterceptor(i HostInterceptor) (restore func()) {
+	prev := hostInterceptor
+	hostInterceptor = i
+	return func() { hostInterceptor = prev }
+}
+
+// StagedStateWrites 返回自上次 ResetStagedWrites 以来已暂存的状态输出ID（按写入顺序）
+func StagedStateWrites() []string {
+	out := make([]string, len(stagedStateWrites))
+	copy(out, stagedStateWrites)
+	return out
+}
+
+// ResetStagedWrites 清空已暂存的状态输出记录、写入预算、读取缓存与调用者缓存（模拟一次新的合约调用）
+func ResetStagedWrites() {
+	stagedStateWrites = nil
+	BeginInvocation()
+}
+
+// interceptHostCall 执行拦截器，未安装拦截器时返回 nil
+func interceptHostCall(call HostCall) error {
+	if hostInterceptor == nil {
+		return nil
+	}
+	return hostInterceptor.BeforeHostCall(call)
+}
@@ -0,0 +1,136 @@
+//go:build tinygo || (js && wasm)
+
+//nolint // WASM环境需要使用unsafe.Pointer访问线性内存
+
+package framework
+
+import (
+	"unsafe"
+)
+
+// ==================== WASM线性内存与地址编码 ====================
+//
+// 依赖WASM线性内存或宿主编码函数的辅助方法。非WASM环境的对应实现见 host_functions_stub.go。
+
+// GetString 从内存指针构造字符串
+//
+// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
+func GetString(ptr uint32, len uint32) string {
+	if ptr == 0 || len == 0 {
+		return ""
+	}
+	return string((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len:len]) //nolint:unsafeptr // WASM线性内存访问
+}
+
+// GetBytes 从内存指针获取字节数组
+//
+// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
+func GetBytes(ptr uint32, len uint32) []byte {
+	if ptr == 0 || len == 0 {
+		return nil
+	}
+	return (*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len:len] //nolint:unsafeptr // WASM线性内存访问
+}
+
+// AllocateString 分配字符串到WASM内存并返回指针和长度
+//
+// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
+func AllocateString(s string) (uint32, uint32) {
+	if len(s) == 0 {
+		return 0, 0
+	}
+	ptr := Malloc(uint32(len(s)))
+	if ptr == 0 {
+		return 0, 0
+	}
+	copy((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len(s)], s) //nolint:unsafeptr // WASM线性内存访问
+	return ptr, uint32(len(s))
+}
+
+// AllocateBytes 分配字节数组到WASM内存
+//
+// nolint // WASM环境需要使用unsafe.Pointer访问线性内存，这是必要的用法
+func AllocateBytes(data []byte) (uint32, uint32) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	ptr := Malloc(uint32(len(data)))
+	if ptr == 0 {
+		return 0, 0
+	}
+	copy((*[1 << 20]byte)(unsafe.Pointer(uintptr(ptr)))[:len(data)], data) //nolint:unsafeptr // WASM线性内存访问
+	return ptr, uint32(len(data))
+}
+
+// AddressToString 将地址转换为 Base58Check 编码字符串
+//
+// 🎯 **架构对齐说明**：
+//   - 复用宿主 AddressManager.BytesToAddress 实现
+//   - 输出标准 Base58Check 格式（符合 pb/transaction.Address 规范）
+//   - 避免在 TinyGo 环境重复实现复杂编码逻辑
+//
+// 📋 **实现方式**：
+//   - 调用宿主函数 address_bytes_to_base58
+//   - 宿主侧委托给 AddressManager 进行编码
+//   - 不重复造轮子，完全复用统一规范实现
+func (addr Address) ToString() string {
+	// 分配缓冲区（Base58Check 地址最大 34 字符）
+	maxLen := uint32(64) // 预留足够空间
+	buffer := malloc(maxLen)
+	if buffer == 0 {
+		// 内存分配失败，回退到 hex 格式
+		return addr.ToHexString()
+	}
+
+	// 调用宿主函数进行 Base58Check 编码
+	addrPtr, _ := AllocateBytes(addr.ToBytes())
+	if addrPtr == 0 {
+		return addr.ToHexString()
+	}
+
+	actualLen := addressBytesToBase58(addrPtr, buffer, maxLen)
+	if actualLen == 0 {
+		// 编码失败，回退到 hex 格式
+		return addr.ToHexString()
+	}
+
+	// 读取 Base58 字符串
+	base58Bytes := GetBytes(buffer, actualLen)
+	return string(base58Bytes)
+}
+
+// ParseAddressBase58 从 Base58Check 编码字符串解析地址
+//
+// 🎯 **架构对齐说明**：
+//   - 复用宿主 AddressManager.AddressToBytes 实现
+//   - 支持标准 Base58Check 格式（符合 pb/transaction.Address 规范）
+//   - 避免在 TinyGo 环境重复实现复杂解码逻辑
+//
+// 📋 **输入格式**：
+//   - "Cf1Kes6snEUeykiJJgrAtKPNPrAzPdPmSn" -> Address{20字节}
+func ParseAddressBase58(base58Str string) (Address, error) {
+	if base58Str == "" {
+		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "address string cannot be empty")
+	}
+
+	// 分配结果缓冲区（20 字节）
+	resultPtr := malloc(20)
+	if resultPtr == 0 {
+		return Address{}, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate memory for address")
+	}
+
+	// 调用宿主函数进行 Base58Check 解码
+	base58Ptr, base58Len := AllocateString(base58Str)
+	if base58Ptr == 0 {
+		return Address{}, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate memory for base58 string")
+	}
+
+	success := addressBase58ToBytes(base58Ptr, base58Len, resultPtr)
+	if success == 0 {
+		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "invalid base58 address format")
+	}
+
+	// 读取 20 字节地址
+	addressBytes := GetBytes(resultPtr, 20)
+	return AddressFromBytes(addressBytes), nil
+}
@@ -0,0 +1,553 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// 该文件为非WASM环境提供内存宿主（MockHost）。安装后，占位宿主函数读写其中的
+// 调用者、区块时间、调用参数、链上状态、余额、事件与返回值，导出函数可以在 go test 中
+// 端到端执行（见 framework/testing.Scenario）。未安装时占位函数保持空实现。
+// WASM环境不包含该文件。
+
+// MockTransfer 一次资产转移
+type MockTransfer struct {
+	// From 转出地址，零地址表示新铸造（CreateUTXO、资产输出）
+	From    Address
+	To      Address
+	TokenID TokenID
+	Amount  Amount
+}
+
+// MockEvent 一次已发出的事件
+type MockEvent struct {
+	Name string
+	// Data EmitEvent 发出的事件字段
+	Data map[string]interface{}
+	// Payload 规范化序列化的事件载荷（EmitEventOrAnchor 直接发出时），其他情况为 nil
+	Payload []byte
+}
+
+// MockStateWrite 一次状态输出
+type MockStateWrite struct {
+	StateID string
+	Version uint64
+	Value   []byte
+}
+
+// MockCallResult 一次导出函数调用的结果
+type MockCallResult struct {
+	// Code 导出函数返回码
+	Code uint32
+	// Return SetReturnData / SetReturnJSON 设置的返回数据
+	Return []byte
+	// Events 按发出顺序记录的事件
+	Events []MockEvent
+	// Writes 按写入顺序记录的状态输出（Code 为 SUCCESS 时提交）
+	Writes []MockStateWrite
+	// Transfers 按执行顺序记录的资产转移（Code 非 SUCCESS 时回滚）
+	Transfers []MockTransfer
+}
+
+type mockStateEntry struct {
+	value   []byte
+	version uint64
+}
+
+// mockExternalClaim 一次外部状态声明
+type mockExternalClaim struct {
+	source   string
+	verified bool
+}
+
+// MockHost 非WASM环境的内存宿主
+//
+// 🎯 **用途**：为导出函数提供可控的宿主环境，按调用提交或丢弃输出
+//
+// **语义**（与链上执行一致）：
+//   - GetState / GetStateFromChain 读取已提交的状态；本次调用已暂存的状态输出经读取缓存
+//     对本次调用随后的读取可见，对其他调用在调用成功后才可见
+//   - 导出函数返回 SUCCESS 时提交状态输出；否则丢弃状态输出并回滚资产转移
+//   - GetStateFromChain 与WASM实现一致去掉值尾部的零字节；状态不存在时返回 ERROR_NOT_FOUND
+//   - Transfer 意图在 Finalize 时检查转出地址余额，不足时返回 ERROR_INSUFFICIENT_BALANCE
+//
+// **示例**：
+//
+//	host := framework.NewMockHost()
+//	host.Timestamp = 1736200000
+//	restore := framework.InstallMockHost(host)
+//	defer restore()
+//	res := host.Invoke(operator, []byte(`{"plan_id":"plan_001"}`), Initialize)
+type MockHost struct {
+	// ContractAddress 当前合约地址
+	ContractAddress Address
+	// Timestamp 当前区块时间（Unix秒）
+	Timestamp uint64
+	// BlockHeight 当前区块高度
+	BlockHeight uint64
+
+	state    map[string]mockStateEntry
+	balances map[string]Amount
+	// external 受控外部状态的预设响应：数据源（ExternalStateClaim.Source）→ 响应数据
+	external map[string][]byte
+
+	// 当前调用上下文（Invoke 期间有效）
+	caller Address
+	params []byte
+	call   *MockCallResult
+	// claims 本次调用已声明的外部状态：声明ID → 声明记录
+	claims map[string]*mockExternalClaim
+
+	// lastReturn 最近一次设置的返回数据（见 GetLastReturnData）
+	lastReturn []byte
+}
+
+// MockHostSnapshot MockHost 的状态快照（见 Snapshot / Restore）
+type MockHostSnapshot struct {
+	contractAddress Address
+	timestamp       uint64
+	blockHeight     uint64
+	state           map[string]mockStateEntry
+	balances        map[string]Amount
+}
+
+var mockHost *MockHost
+
+// NewMockHost 创建空的内存宿主
+func NewMockHost() *MockHost {
+	return &MockHost{
+		state:    make(map[string]mockStateEntry),
+		balances: make(map[string]Amount),
+		external: make(map[string][]byte),
+		claims:   make(map[string]*mockExternalClaim),
+	}
+}
+
+// InstallMockHost 安装内存宿主，占位宿主函数改为读写 h
+//
+// **返回**：恢复之前宿主的函数，通常配合 defer 使用
+func InstallMockHost(h *MockHost) (restore func()) {
+	prev := mockHost
+	mockHost = h
+	ResetReadCache()
+	ResetCallerCache()
+	return func() {
+		mockHost = prev
+		ResetReadCache()
+		ResetCallerCache()
+	}
+}
+
+// SetExternalResponse 预设受控外部状态的响应
+//
+// 🎯 **用途**：测试 ISPC 受控外部交互（DeclareExternalState → ProvideEvidence → QueryControlledState）
+//
+// **参数**：
+//   - source: 数据源，与 ExternalStateClaim.Source 一致（API端点/数据库标识/文件标识）
+//   - data: QueryControlledState 返回的数据；为 nil 时删除预设，之后查询返回 ERROR_NOT_FOUND
+//
+// **注意**：声明须先通过 ProvideEvidence（APISignature 与 ResponseHash 非空）才能查询，否则返回 ERROR_PERMISSION_DENIED
+func (h *MockHost) SetExternalResponse(source string, data []byte) {
+	if data == nil {
+		delete(h.external, source)
+		return
+	}
+	h.external[source] = append([]byte(nil), data...)
+}
+
+// GetLastReturnData 读取导出函数最近一次通过 SetReturnData / SetReturnString / SetReturnJSON 设置的返回数据
+//
+// 🎯 **用途**：单元测试断言导出函数的返回内容（如查询函数的 JSON 输出）
+//
+// **返回**：
+//   - []byte: 返回数据的副本；未安装 MockHost、或最近一次 Invoke 未设置返回数据时返回 nil
+//
+// **注意**：
+//   - 每次 Invoke 开始时清空；不经 Invoke 直接调用导出函数时同样记录
+//   - 只在非WASM环境中提供，Invoke 的结果也可直接读取 MockCallResult.Return
+//
+// **示例**：
+//
+//	framework.InstallMockHost(host)
+//	if code := QueryVesting(); code != framework.SUCCESS {
+//	    t.Fatalf("QueryVesting() = %d", code)
+//	}
+//	var view map[string]interface{}
+//	json.Unmarshal(framework.GetLastReturnData(), &view)
+func GetLastReturnData() []byte {
+	if mockHost == nil || mockHost.lastReturn == nil {
+		return nil
+	}
+	return append([]byte(nil), mockHost.lastReturn...)
+}
+
+// SetState 直接写入已提交的状态（准备测试前置数据），同时丢弃该状态的读取缓存
+func (h *MockHost) SetState(stateID string, value []byte, version uint64) {
+	h.state[stateID] = mockStateEntry{value: append([]byte(nil), value...), version: version}
+	InvalidateRead(stateID)
+}
+
+// State 读取已提交的状态
+func (h *MockHost) State(stateID string) (value []byte, version uint64, ok bool) {
+	entry, ok := h.state[stateID]
+	if !ok {
+		return nil, 0, false
+	}
+	return append([]byte(nil), entry.value...), entry.version, true
+}
+
+// SetBalance 设置地址的代币余额
+func (h *MockHost) SetBalance(addr Address, tokenID TokenID, amount Amount) {
+	h.balances[mockBalanceKey(addr, tokenID)] = amount
+}
+
+// Balance 查询地址的代币余额
+func (h *MockHost) Balance(addr Address, tokenID TokenID) Amount {
+	return h.balances[mockBalanceKey(addr, tokenID)]
+}
+
+// Invoke 以 caller 身份、params 为参数执行一次导出函数
+//
+// **返回**：调用结果；返回 SUCCESS 时状态输出已提交，否则状态输出已丢弃、资产转移已回滚
+//
+// **注意**：调用前后清空暂存记录与写入预算（见 ResetStagedWrites），与一次独立的合约调用一致
+func (h *MockHost) Invoke(caller Address, params []byte, fn func() uint32) MockCallResult {
+	balances := copyMockBalances(h.balances)
+	h.caller = caller
+	h.params = append([]byte(nil), params...)
+	h.call = &MockCallResult{}
+	h.lastReturn = nil
+	h.claims = make(map[string]*mockExternalClaim)
+	ResetStagedWrites()
+	defer ResetStagedWrites()
+
+	code := fn()
+	res := *h.call
+	res.Code = code
+	h.call = nil
+
+	if code == SUCCESS {
+		for _, w := range res.Writes {
+			h.state[w.StateID] = mockStateEntry{value: w.Value, version: w.Version}
+		}
+	} else {
+		h.balances = balances
+	}
+	return res
+}
+
+// Snapshot 保存已提交的状态、余额与区块环境
+func (h *MockHost) Snapshot() *MockHostSnapshot {
+	state := make(map[string]mockStateEntry, len(h.state))
+	for k, v := range h.state {
+		state[k] = v
+	}
+	return &MockHostSnapshot{
+		contractAddress: h.ContractAddress,
+		timestamp:       h.Timestamp,
+		blockHeight:     h.BlockHeight,
+		state:           state,
+		balances:        copyMockBalances(h.balances),
+	}
+}
+
+// Restore 恢复到 Snapshot 保存的状态；同一快照可多次恢复
+func (h *MockHost) Restore(s *MockHostSnapshot) {
+	h.ContractAddress = s.contractAddress
+	h.Timestamp = s.timestamp
+	h.BlockHeight = s.blockHeight
+	h.state = make(map[string]mockStateEntry, len(s.state))
+	for k, v := range s.state {
+		h.state[k] = v
+	}
+	h.balances = copyMockBalances(s.balances)
+	ResetReadCache()
+}
+
+func copyMockBalances(m map[string]Amount) map[string]Amount {
+	out := make(map[string]Amount, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func mockBalanceKey(addr Address, tokenID TokenID) string {
+	return string(tokenID) + "\x00" + string(addr[:])
+}
+
+// ==================== 占位宿主函数使用的内部接口 ====================
+
+// queryStatesByPrefix 按状态ID字典序分页查询已提交的状态，返回与宿主函数 state_query_by_prefix 相同格式的 JSON
+func (h *MockHost) queryStatesByPrefix(prefix []byte, limit, offset uint32) []byte {
+	var ids []string
+	for id := range h.state {
+		if strings.HasPrefix(id, string(prefix)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if uint64(offset) >= uint64(len(ids)) {
+		ids = nil
+	} else {
+		ids = ids[offset:]
+	}
+	if uint32(len(ids)) > limit {
+		ids = ids[:limit]
+	}
+
+	var b strings.Builder
+	b.WriteString(`{"entries":[`)
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		entry := h.state[id]
+		fmt.Fprintf(&b, `{"state_id":"%s","value":"%s","version":%d}`,
+			base64.StdEncoding.EncodeToString([]byte(id)), base64.StdEncoding.EncodeToString(entry.value), entry.version)
+	}
+	b.WriteString(`]}`)
+	return []byte(b.String())
+}
+
+// listUTXOsByOwner 分页列出地址持有的UTXO，返回与宿主函数 utxo_list_by_owner 相同格式的 JSON
+//
+// 每个非零余额视为一个UTXO：交易哈希为余额键的 SHA-256，输出索引为 0，按交易哈希升序排列
+func (h *MockHost) listUTXOsByOwner(owner Address, tokenID TokenID, limit, offset uint32) []byte {
+	type mockUTXO struct {
+		txHash  [32]byte
+		tokenID TokenID
+		amount  Amount
+	}
+	var utxos []mockUTXO
+	for key, amount := range h.balances {
+		token, addr, _ := strings.Cut(key, "\x00")
+		if amount == 0 || addr != string(owner[:]) || (tokenID != ANY_TOKEN_ID && TokenID(token) != tokenID) {
+			continue
+		}
+		utxos = append(utxos, mockUTXO{txHash: sha256.Sum256([]byte(key)), tokenID: TokenID(token), amount: amount})
+	}
+	sort.Slice(utxos, func(i, j int) bool { return string(utxos[i].txHash[:]) < string(utxos[j].txHash[:]) })
+	if uint64(offset) >= uint64(len(utxos)) {
+		utxos = nil
+	} else {
+		utxos = utxos[offset:]
+	}
+	if uint32(len(utxos)) > limit {
+		utxos = utxos[:limit]
+	}
+
+	ownerB64 := base64.StdEncoding.EncodeToString(owner[:])
+	var b strings.Builder
+	b.WriteString(`{"utxos":[`)
+	for i, u := range utxos {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"tx_hash":"%s","index":0,"owner":"%s","token_id":"%s","amount":"%d"}`,
+			base64.StdEncoding.EncodeToString(u.txHash[:]), ownerB64, u.tokenID, u.amount)
+	}
+	b.WriteString(`]}`)
+	return []byte(b.String())
+}
+
+// recordEvent 记录事件（不在 Invoke 期间时忽略）
+func (h *MockHost) recordEvent(event MockEvent) {
+	if h.call != nil {
+		h.call.Events = append(h.call.Events, event)
+	}
+}
+
+// recordWrite 记录状态输出
+func (h *MockHost) recordWrite(stateID []byte, version uint64, value []byte) {
+	if h.call != nil {
+		h.call.Writes = append(h.call.Writes, MockStateWrite{StateID: string(stateID), Version: version, Value: append([]byte(nil), value...)})
+	}
+}
+
+// setReturn 记录返回数据（不在 Invoke 期间时只记录为最近一次返回数据）
+func (h *MockHost) setReturn(data []byte) {
+	h.lastReturn = append([]byte(nil), data...)
+	if h.call != nil {
+		h.call.Return = append([]byte(nil), data...)
+	}
+}
+
+// declareExternal 记录外部状态声明，声明ID为 sha256(类型 | 数据源 | 本次调用内的声明序号)
+func (h *MockHost) declareExternal(claim *ExternalStateClaim) []byte {
+	sum := sha256.Sum256([]byte(claim.ClaimType + "|" + claim.Source + "|" + Uint64ToString(uint64(len(h.claims)))))
+	h.claims[string(sum[:])] = &mockExternalClaim{source: claim.Source}
+	return sum[:]
+}
+
+// verifyExternal 标记声明已提供佐证
+func (h *MockHost) verifyExternal(claimID []byte) error {
+	claim, ok := h.claims[string(claimID)]
+	if !ok {
+		return NewContractError(ERROR_NOT_FOUND, "external state claim not found")
+	}
+	claim.verified = true
+	return nil
+}
+
+// queryExternal 返回已验证声明的预设响应
+func (h *MockHost) queryExternal(claimID []byte) ([]byte, error) {
+	claim, ok := h.claims[string(claimID)]
+	if !ok {
+		return nil, NewContractError(ERROR_NOT_FOUND, "external state claim not found")
+	}
+	if !claim.verified {
+		return nil, NewContractError(ERROR_PERMISSION_DENIED, "evidence not provided")
+	}
+	data, ok := h.external[claim.source]
+	if !ok {
+		return nil, NewContractError(ERROR_NOT_FOUND, "no external response for "+claim.source)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// transfer 执行资产转移；from 为零地址时为新铸造
+func (h *MockHost) transfer(t MockTransfer) error {
+	if t.From != (Address{}) {
+		key := mockBalanceKey(t.From, t.TokenID)
+		if h.balances[key] < t.Amount {
+			return NewContractError(ERROR_INSUFFICIENT_BALANCE, "insufficient balance")
+		}
+		h.balances[key] -= t.Amount
+	}
+	h.balances[mockBalanceKey(t.To, t.TokenID)] += t.Amount
+	if h.call != nil {
+		h.call.Transfers = append(h.call.Transfers, t)
+	}
+	return nil
+}
+
+// ==================== 交易构建（非WASM环境） ====================
+
+// Finalize 完成交易构建（非WASM环境）
+//
+// 安装 MockHost 时按顺序执行转账意图与输出：转账先检查全部转出地址余额，
+// 不足时不做任何修改并返回 ERROR_INSUFFICIENT_BALANCE；未安装时仅校验构建错误。
+func (tb *TransactionBuilder) Finalize() (bool, []byte, uint32) {
+	if tb.err != nil {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+	if mockHost == nil {
+		return true, nil, SUCCESS
+	}
+
+	needed := make(map[string]Amount)
+	for _, intent := range tb.draft.intents {
+		if intent.intentType != "transfer" {
+			continue
+		}
+		key := mockBalanceKey(AddressFromBytes(intent.from), TokenID(intent.tokenID))
+		needed[key] += Amount(intent.amount)
+		if mockHost.balances[key] < needed[key] {
+			return false, nil, ERROR_INSUFFICIENT_BALANCE
+		}
+	}
+
+	for _, intent := range tb.draft.intents {
+		if intent.intentType == "transfer" {
+			_ = mockHost.transfer(MockTransfer{
+				From:    AddressFromBytes(intent.from),
+				To:      AddressFromBytes(intent.to),
+				TokenID: TokenID(intent.tokenID),
+				Amount:  Amount(intent.amount),
+			})
+		}
+	}
+	for _, out := range tb.draft.outputs {
+		switch out.outputType {
+		case "state":
+			if _, err := stageStateOutput(out.stateID, out.stateVer, out.execHash); err != nil {
+				return false, nil, ERROR_EXECUTION_FAILED
+			}
+		case "asset":
+			_ = mockHost.transfer(MockTransfer{To: AddressFromBytes(out.to), TokenID: TokenID(out.tokenID), Amount: Amount(out.amount)})
+		}
+	}
+	return true, ComputeHash([]byte(tb.serializeDraft())).ToBytes(), SUCCESS
+}
+
+// ==================== 地址编码（非WASM环境） ====================
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ToString 将地址转换为 Base58Check 编码字符串（非WASM环境）
+//
+// 与宿主编码规则一致：版本字节 0x00 + 20字节地址 + 4字节双SHA256校验和
+func (addr Address) ToString() string {
+	payload := append([]byte{0x00}, addr[:]...)
+	return encodeBase58(append(payload, base58Checksum(payload)...))
+}
+
+// ParseAddressBase58 从 Base58Check 编码字符串解析地址（非WASM环境）
+func ParseAddressBase58(base58Str string) (Address, error) {
+	if base58Str == "" {
+		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "address string cannot be empty")
+	}
+	raw, ok := decodeBase58(base58Str)
+	if !ok || len(raw) != 25 || raw[0] != 0x00 || string(base58Checksum(raw[:21])) != string(raw[21:]) {
+		return Address{}, NewContractError(ERROR_INVALID_PARAMS, "invalid base58 address format")
+	}
+	return AddressFromBytes(raw[1:21]), nil
+}
+
+func base58Checksum(payload []byte) []byte {
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func encodeBase58(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func decodeBase58(s string) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		idx := -1
+		for j := 0; j < len(base58Alphabet); j++ {
+			if base58Alphabet[j] == s[i] {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+	out := n.Bytes()
+	for i := 0; i < len(s) && s[i] == base58Alphabet[0]; i++ {
+		out = append([]byte{0x00}, out...)
+	}
+	return out, true
+}
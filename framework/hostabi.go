@@ -171,27 +171,101 @@ func UTXOExists(outpoint OutPoint) bool {
 
 // QueryUTXOsByAddress 查询地址的所有UTXO（账户抽象）
 //
-// 🎯 **用途**：账户抽象层，查询地址的所有UTXO
+// 🎯 **用途**：账户抽象层，查询地址持有的全部资产UTXO（找零选币、储备证明等）
 //
 // **参数**：
 //   - address: 地址
-//   - tokenID: 代币ID（可选，nil表示查询所有代币）
+//   - tokenID: 代币ID；NativeTokenID()（空字符串）只查原生币，ANY_TOKEN_ID 查询所有代币
 //
 // **返回**：
-//   - utxos: UTXO列表
+//   - utxos: UTXO列表（OutPoint 与 Output 的 Recipient、Amount、TokenID 均已填充），按 (tx_hash, index) 升序
+//   - error: 地址为空（ERROR_INVALID_PARAMS）、UTXO 超过 MAX_UTXO_QUERY_RESULTS（ERROR_QUOTA_EXCEEDED）、
+//     宿主返回的结果不完整或格式错误（ERROR_EXECUTION_FAILED）
 //
-// **注意**：这是账户抽象层提供的便捷方法，不是HostABI原语
+// **注意**：
+//   - 这是账户抽象层提供的便捷方法，内部按 MAX_UTXO_LIST_LIMIT 逐页调用 ListUTXOsByOwner
+//   - 持有大量 UTXO 的地址应直接使用 ListUTXOsByOwner 分页处理
 //
 // **示例**：
 //
-//	utxos := QueryUTXOsByAddress(caller, nil)
+//	utxos, err := QueryUTXOsByAddress(caller, ANY_TOKEN_ID)
+//	if err != nil {
+//	    return err.(*ContractError).Code
+//	}
 //	for _, utxo := range utxos {
 //	    total += utxo.Output.Amount
 //	}
-func QueryUTXOsByAddress(address Address, tokenID TokenID) []UTXO {
-	// TODO: 实现账户抽象层查询
-	// 当前返回空列表，待实现
-	return []UTXO{}
+func QueryUTXOsByAddress(address Address, tokenID TokenID) ([]UTXO, error) {
+	if err := validateUTXOList(address, MAX_UTXO_LIST_LIMIT); err != nil {
+		return nil, err
+	}
+	return collectUTXOs(func(offset, limit uint32) ([]UTXO, error) {
+		return ListUTXOsByOwner(address, tokenID, limit, offset)
+	})
+}
+
+// ListUTXOsByOwner 分页列出地址持有的资产UTXO
+//
+// 🎯 **用途**：逐页遍历持有大量 UTXO 的地址
+//
+// **参数**：
+//   - owner: 所有者地址
+//   - tokenID: 代币ID；NativeTokenID()（空字符串）只查原生币，ANY_TOKEN_ID 查询所有代币
+//   - limit: 本页最多返回条数，1 到 MAX_UTXO_LIST_LIMIT
+//   - offset: 跳过的条数（按 (tx_hash, index) 升序）
+//
+// **返回**：
+//   - []UTXO: 本页的UTXO，按 (tx_hash, index) 升序；少于 limit 条表示已到末尾
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、本页结果超过 MAX_UTXO_LIST_RESULT_SIZE（ERROR_QUOTA_EXCEEDED）、
+//     宿主返回的结果不完整或格式错误（ERROR_EXECUTION_FAILED）
+//
+// **示例**：
+//
+//	for offset := uint32(0); ; offset += 50 {
+//	    page, err := ListUTXOsByOwner(owner, "USDT", 50, offset)
+//	    if err != nil {
+//	        return err.(*ContractError).Code
+//	    }
+//	    // 处理 page ...
+//	    if len(page) < 50 {
+//	        break
+//	    }
+//	}
+func ListUTXOsByOwner(owner Address, tokenID TokenID, limit, offset uint32) ([]UTXO, error) {
+	if err := validateUTXOList(owner, limit); err != nil {
+		return nil, err
+	}
+	ownerPtr, _ := AllocateBytes(owner.ToBytes())
+	if ownerPtr == 0 {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate owner")
+	}
+
+	// 原生币使用 tokenIDPtr=0, tokenIDLen=0（与 QueryBalance 一致）；ANY_TOKEN_ID 通过 anyToken=1 表示
+	var tokenIDPtr, tokenIDLen, anyToken uint32
+	if tokenID == ANY_TOKEN_ID {
+		anyToken = 1
+	} else if !IsNativeToken(tokenID) {
+		tokenIDPtr, tokenIDLen = AllocateString(string(tokenID))
+		if tokenIDPtr == 0 {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate tokenID")
+		}
+	}
+
+	data, err := readHostBuffer(func(bufSize uint32) ([]byte, uint32, error) {
+		resultPtr := malloc(bufSize)
+		if resultPtr == 0 {
+			return nil, 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate utxo list buffer")
+		}
+		n := utxoListByOwner(ownerPtr, tokenIDPtr, tokenIDLen, anyToken, offset, limit, resultPtr, bufSize)
+		if n == 0 {
+			return nil, 0, nil
+		}
+		return GetBytes(resultPtr, min(n, bufSize)), n, nil
+	}, utxoListInitialBufSize, MAX_UTXO_LIST_RESULT_SIZE, "utxo list result")
+	if err != nil {
+		return nil, err
+	}
+	return parseUTXOList(data, owner, tokenID, limit)
 }
 
 // ==================== 4. 资源查询（2个）====================
@@ -300,7 +374,7 @@ func ResourceExists(contentHash []byte) bool {
 // **参数**：
 //   - stateID: 状态ID（字节数组）
 //   - version: 状态版本号
-//   - execHash: 执行结果哈希（字节数组）
+//   - execHash: 执行结果哈希（字节数组；为空时使用 stateID 的哈希，需要显式 execHash 时使用 AppendStateOutputStrict）
 //   - parentHash: 父状态哈希（可选，nil表示无父状态）
 //
 // **返回**：
@@ -321,6 +395,11 @@ func AppendStateOutputSimple(stateID []byte, version uint64, execHash []byte, pa
 	if len(stateID) == 0 {
 		return 0xFFFFFFFF, NewContractError(ERROR_INVALID_PARAMS, "stateID cannot be empty")
 	}
+
+	// 计入本次调用的写入预算（见 DeclareWriteBudget）
+	if err := chargeWriteBudget(len(stateID) + len(execHash)); err != nil {
+		return 0xFFFFFFFF, err
+	}
 	
 	// 验证execHash必须是32字节（节点侧固定读取32字节）
 	// 如果execHash不是32字节，需要先计算哈希或补齐到32字节
@@ -380,6 +459,9 @@ func AppendStateOutputSimple(stateID []byte, version uint64, execHash []byte, pa
 		return outputIndex, NewContractError(ERROR_EXECUTION_FAILED, "append_state_output failed")
 	}
 
+	// 本次调用随后的读取看到待提交的状态值（见 state_cache.go）
+	cacheStagedState(stateID, version, execHash32[:])
+
 	return outputIndex, nil
 }
 
@@ -391,7 +473,7 @@ func AppendStateOutputSimple(stateID []byte, version uint64, execHash []byte, pa
 //   - items: 输出项列表，每个项包含：
 //     * Recipient: 接收者地址（字节数组）
 //     * Amount: 金额（uint64）
-//     * TokenID: 代币ID（可选，nil表示原生币）
+//     * TokenID: 代币ID（可选，nil 或空切片表示原生币，序列化为 null）
 //
 // **返回**：
 //   - count: 成功创建的输出数量
@@ -411,6 +493,9 @@ func AppendStateOutputSimple(stateID []byte, version uint64, execHash []byte, pa
 //	if err != nil {
 //	    return framework.ERROR_EXECUTION_FAILED
 //	}
+//
+// **注意**：批量输出的 JSON 参与执行结果哈希计算，输出按 items 顺序序列化、不做排序，
+// 调用方须以确定的顺序构造 items（例如按参数列表顺序），不要从 map 遍历得到
 func BatchCreateOutputsSimple(items []struct {
 	Recipient []byte
 	Amount    uint64
@@ -420,29 +505,8 @@ func BatchCreateOutputsSimple(items []struct {
 		return 0, NewContractError(ERROR_INVALID_PARAMS, "items cannot be empty")
 	}
 
-	// 构造批量输出JSON（手动序列化避免引入encoding/json）
-	batchJSON := "["
-	for i, it := range items {
-		if i > 0 {
-			batchJSON += ","
-		}
-		batchJSON += `{"recipient":"`
-		// Base64编码地址（使用标准Base64编码）
-		batchJSON += base64EncodeSimple(it.Recipient)
-		batchJSON += `","amount":`
-		batchJSON += Uint64ToString(it.Amount)
-		if len(it.TokenID) > 0 {
-			batchJSON += `,"token_id":"`
-			batchJSON += base64EncodeSimple(it.TokenID)
-			batchJSON += `"`
-		} else {
-			batchJSON += `,"token_id":null`
-		}
-		batchJSON += `,"locking_conditions":[]}`
-	}
-	batchJSON += "]"
-
-	batchBytes := []byte(batchJSON)
+	// 构造批量输出JSON（相同输入逐字节相同，见 encodeBatchOutputsJSON）
+	batchBytes := encodeBatchOutputsJSON(items)
 	batchPtr, batchLen := AllocateBytes(batchBytes)
 	if batchPtr == 0 {
 		return 0, NewContractError(ERROR_EXECUTION_FAILED, "failed to allocate batch JSON")
@@ -457,47 +521,6 @@ func BatchCreateOutputsSimple(items []struct {
 	return result, nil
 }
 
-// base64EncodeSimple Base64编码（用于地址和TokenID）
-// 使用标准Base64编码算法，适用于TinyGo WASM环境
-func base64EncodeSimple(data []byte) string {
-	if len(data) == 0 {
-		return ""
-	}
-
-	const base64Table = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
-	result := ""
-
-	for i := 0; i < len(data); i += 3 {
-		b1 := data[i]
-		b2 := byte(0)
-		b3 := byte(0)
-
-		if i+1 < len(data) {
-			b2 = data[i+1]
-		}
-		if i+2 < len(data) {
-			b3 = data[i+2]
-		}
-
-		result += string(base64Table[(b1>>2)&0x3F])
-		result += string(base64Table[((b1&0x03)<<4)|((b2>>4)&0x0F)])
-
-		if i+1 < len(data) {
-			result += string(base64Table[((b2&0x0F)<<2)|((b3>>6)&0x03)])
-		} else {
-			result += "="
-		}
-
-		if i+2 < len(data) {
-			result += string(base64Table[b3&0x3F])
-		} else {
-			result += "="
-		}
-	}
-
-	return result
-}
-
 // ==================== 5. 交易草稿构建（4个）====================
 
 // ==================== 锁定相关类型 ====================
@@ -1150,6 +1173,7 @@ func findBase64Char(c byte, table string) byte {
 // **说明**：
 //   - 从链上查询状态，获取版本号
 //   - 如果状态不存在，返回版本号0（首次创建时使用版本号1）
+//   - 本次调用已暂存该状态的输出时返回暂存的版本号，同一调用内再次写入时版本号继续递增
 //
 // **示例**：
 //
@@ -0,0 +1,96 @@
+package framework
+
+// 按分区追加的索引存储
+//
+// 状态布局：
+//   - 计数：  index:{name}:{partition}:count  → 8字节大端条目数
+//   - 条目：  index:{name}:{partition}:{seq}  → 2字节大端长度 + 条目内容
+//
+// 条目带长度前缀，读取时据此恢复被链上查询截断的尾部零字节。
+
+// AppendIndexEntry 向具名索引的分区追加一条目
+//
+// 🎯 **用途**：为按调用者输入增长的列表（附件、日志等）提供受配额约束的追加写入
+//
+// **参数**：
+//   - indexName: 索引名称，登记过 RegisterIndexQuota 时按其配额校验
+//   - partition: 分区键，通常为调用者地址（或调用者地址 + 业务ID）
+//   - entry: 条目内容，不超过 65535 字节
+//
+// **返回**：新条目的序号（从0开始）；超出配额返回 ERROR_QUOTA_EXCEEDED
+//
+// **注意**：条目与计数两次状态输出均计入当前调用的写入预算
+func AppendIndexEntry(indexName string, partition []byte, entry []byte) (uint64, error) {
+	if indexName == "" || len(partition) == 0 {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "index name and partition are required")
+	}
+	if len(entry) == 0 || len(entry) > 0xFFFF {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "index entry must be 1-65535 bytes")
+	}
+
+	count, version := indexCount(indexName, partition)
+	if err := CheckIndexQuota(indexName, count, len(entry)); err != nil {
+		return 0, err
+	}
+
+	value := make([]byte, 2+len(entry))
+	value[0] = byte(len(entry) >> 8)
+	value[1] = byte(len(entry))
+	copy(value[2:], entry)
+	if _, err := AppendStateOutputSimple(indexEntryStateID(indexName, partition, count), 1, value, nil); err != nil {
+		return 0, err
+	}
+
+	countBytes := make([]byte, 8)
+	for i := 0; i < 8; i++ {
+		countBytes[i] = byte((count + 1) >> (56 - 8*i))
+	}
+	if _, err := AppendStateOutputSimple(indexCountStateID(indexName, partition), version+1, countBytes, nil); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// IndexEntryCount 返回具名索引分区的条目数
+func IndexEntryCount(indexName string, partition []byte) uint64 {
+	count, _ := indexCount(indexName, partition)
+	return count
+}
+
+// GetIndexEntry 读取具名索引分区中指定序号的条目
+func GetIndexEntry(indexName string, partition []byte, seq uint64) ([]byte, error) {
+	data, _, err := GetStateFromChain(indexEntryStateID(indexName, partition, seq))
+	if err != nil || len(data) == 0 {
+		return nil, NewContractError(ERROR_NOT_FOUND, "index entry not found")
+	}
+	header := make([]byte, 2)
+	copy(header, data)
+	entry := make([]byte, int(header[0])<<8|int(header[1]))
+	if len(data) > 2 {
+		copy(entry, data[2:])
+	}
+	return entry, nil
+}
+
+// indexCount 读取分区条目数及计数状态版本，不存在时为 0
+func indexCount(indexName string, partition []byte) (uint64, uint64) {
+	data, version, err := GetStateFromChain(indexCountStateID(indexName, partition))
+	if err != nil || len(data) == 0 {
+		return 0, 0
+	}
+	buf := make([]byte, 8)
+	copy(buf, data)
+	var count uint64
+	for _, b := range buf {
+		count = count<<8 | uint64(b)
+	}
+	return count, version
+}
+
+func indexCountStateID(indexName string, partition []byte) []byte {
+	return []byte("index:" + indexName + ":" + string(partition) + ":count")
+}
+
+func indexEntryStateID(indexName string, partition []byte, seq uint64) []byte {
+	return []byte("index:" + indexName + ":" + string(partition) + ":" + formatUint(seq))
+}
@@ -0,0 +1,33 @@
+package framework
+
+// 调用范围状态
+//
+// 调用者缓存、状态读取缓存、写入预算只在一次导出函数调用内有效。WASM 宿主可能复用同一实例执行多次调用，
+// 包级变量不会随调用重置，因此不能假设"每次调用使用新的实例"：导出函数入口显式调用 BeginInvocation
+// 开始一次新的调用。读取参数（ReadContractParams / GetContractParams）不会清空这些状态，
+// 调用中途再次读取参数不会丢失已暂存写入的读取缓存，也不会重置已声明的写入预算。
+//
+// 非WASM环境中 MockHost.Invoke 在调用前后执行 ResetStagedWrites，效果相同。
+
+// BeginInvocation 清空调用范围内的缓存（开始一次新的合约调用）
+//
+// 🎯 **用途**：导出函数入口调用，避免复用的实例沿用上一次调用的调用者、状态读取缓存与写入预算
+//
+// **注意**：
+//   - 每个导出函数的第一条语句调用，先于读取参数、声明写入预算与读取状态
+//   - 只应在入口调用；调用中途执行会丢弃本次调用暂存写入的读取缓存并重置写入预算
+//   - ServeView / HandleMulticall 已自动调用，直接委托它们的导出函数无需重复调用
+//
+// **示例**：
+//
+//	//export Transfer
+//	func Transfer() uint32 {
+//	    framework.BeginInvocation()
+//	    params := framework.GetContractParams()
+//	    ...
+//	}
+func BeginInvocation() {
+	ResetCallerCache()
+	ResetReadCache()
+	DeclareWriteBudget(0)
+}
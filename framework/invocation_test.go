@@ -0,0 +1,36 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// TestReadParamsKeepsInvocationState 测试调用中途再次读取参数不清空读取缓存与写入预算：
+// 暂存写入后读取参数仍读到暂存值，已声明的预算与已计量字节数保持不变
+func TestReadParamsKeepsInvocationState(t *testing.T) {
+	host, reads := installStateReadCounter(t)
+	host.SetState("plan_config", []byte("config"), 2)
+
+	// 1. 入口开始调用，声明预算并暂存新值
+	BeginInvocation()
+	GetContractParams()
+	DeclareWriteBudget(64)
+	if _, err := AppendStateOutputSimple([]byte("plan_config"), 3, []byte("staged"), nil); err != nil {
+		t.Fatalf("AppendStateOutputSimple() error = %v", err)
+	}
+	_, usedBefore := WriteBudget()
+
+	// 2. 辅助函数再次读取参数
+	if _, err := ReadContractParams(); err != nil {
+		t.Fatalf("ReadContractParams() error = %v", err)
+	}
+	value, version, err := GetStateFromChain([]byte("plan_config"))
+	if err != nil || string(value) != "staged" || version != 3 {
+		t.Errorf("GetStateFromChain() after reading params = %q, %d, %v, want staged, 3", value, version, err)
+	}
+	if got := reads[HOST_CALL_STATE_GET_FROM_CHAIN+":plan_config"]; got != 0 {
+		t.Errorf("host reads after reading params = %d, want 0", got)
+	}
+	if limit, used := WriteBudget(); limit != 64 || used != usedBefore {
+		t.Errorf("WriteBudget() after reading params = %d, %d, want 64, %d", limit, used, usedBefore)
+	}
+}
@@ -0,0 +1,315 @@
+package framework
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"sort"
+)
+
+// ==================== JSON 返回值序列化 ====================
+//
+// SetReturnJSON / SetReturnJSONSafe 使用的序列化实现。本文件不区分构建环境，
+// 序列化结果可在非WASM环境中直接测试。
+//
+// 安全模式（SetReturnJSONSafe）将 Amount/uint64 输出为十进制字符串：JSON 数字在
+// JavaScript 中按双精度解析，超过 2^53 的代币金额会丢失精度。
+//
+// float64 按固定小数位数输出（不使用科学计数法），[]byte 输出为十六进制或 base64 字符串；
+// 遇到不支持的类型时返回带字段路径的错误，而不是静默丢弃字段。
+
+// JSON_BYTES_HEX / JSON_BYTES_BASE64 []byte 值的输出编码（JSONOptions.BytesEncoding）
+const (
+	// JSON_BYTES_HEX 小写十六进制，不带 0x 前缀（默认）
+	JSON_BYTES_HEX = "hex"
+	// JSON_BYTES_BASE64 标准 base64（带 = 填充）
+	JSON_BYTES_BASE64 = "base64"
+)
+
+// DEFAULT_JSON_FLOAT_DECIMALS SetReturnJSON / SetReturnJSONSafe 输出 float64 的小数位数
+const DEFAULT_JSON_FLOAT_DECIMALS = 6
+
+// maxJSONFloatDecimals float64 输出的最大小数位数
+const maxJSONFloatDecimals = 18
+
+// JSONOptions JSON 返回值序列化选项（见 SetReturnJSONWithOptions）
+type JSONOptions struct {
+	// QuoteUint64 Amount/uint64 输出为十进制字符串（同 SetReturnJSONSafe）
+	QuoteUint64 bool
+	// BytesEncoding []byte 的输出编码：JSON_BYTES_HEX（空值时的默认）或 JSON_BYTES_BASE64
+	BytesEncoding string
+	// FloatDecimals float64/float32 固定输出的小数位数（0-18），四舍五入，不使用科学计数法
+	FloatDecimals int
+	// EscapeNonASCII 非 ASCII 字符输出为 \uXXXX（超出 BMP 的字符输出为代理对），结果只含 ASCII 字符
+	EscapeNonASCII bool
+}
+
+// DefaultJSONOptions SetReturnJSON 使用的默认选项：uint64 输出为数字，[]byte 输出为十六进制，浮点数保留 6 位小数
+func DefaultJSONOptions() JSONOptions {
+	return JSONOptions{BytesEncoding: JSON_BYTES_HEX, FloatDecimals: DEFAULT_JSON_FLOAT_DECIMALS}
+}
+
+// serializeToJSON 递归序列化为 JSON 字符串，包含不支持的类型时返回空字符串
+//
+// 🎯 **修复说明**：
+//   - 新增对 Amount (uint64 别名) 的显式支持
+//   - 确保所有数值类型都能正确序列化
+func serializeToJSON(obj interface{}) string {
+	result, _ := marshalJSON(obj, DefaultJSONOptions())
+	return result
+}
+
+// serializeToJSONSafe 递归序列化为 JSON 字符串，Amount/uint64 输出为字符串
+func serializeToJSONSafe(obj interface{}) string {
+	opts := DefaultJSONOptions()
+	opts.QuoteUint64 = true
+	result, _ := marshalJSON(obj, opts)
+	return result
+}
+
+// marshalJSON 按选项序列化，遇到不支持的类型或无法表示的值时返回 ERROR_INVALID_PARAMS（含出错字段的路径）
+func marshalJSON(obj interface{}, opts JSONOptions) (string, error) {
+	if opts.BytesEncoding != "" && opts.BytesEncoding != JSON_BYTES_HEX && opts.BytesEncoding != JSON_BYTES_BASE64 {
+		return "", NewContractError(ERROR_INVALID_PARAMS, "unsupported JSON bytes encoding: "+opts.BytesEncoding)
+	}
+	if opts.FloatDecimals < 0 || opts.FloatDecimals > maxJSONFloatDecimals {
+		return "", NewContractError(ERROR_INVALID_PARAMS, "JSON float decimals must be between 0 and 18")
+	}
+	return serializeJSONValue(obj, opts, "$")
+}
+
+// serializeJSONValue 递归序列化；path 为当前值的位置（如 $.result[2].rate），用于错误信息
+func serializeJSONValue(obj interface{}, opts JSONOptions, path string) (string, error) {
+	switch v := obj.(type) {
+	case string:
+		return `"` + escapeJSONStringWith(v, opts.EscapeNonASCII) + `"`, nil
+	case Amount:
+		// 🔧 关键修复：显式支持 Amount 类型
+		return serializeJSONUint64(uint64(v), opts.QuoteUint64), nil
+	case uint64:
+		return serializeJSONUint64(v, opts.QuoteUint64), nil
+	case int64:
+		if v < 0 {
+			return "-" + formatUint(uint64(-v)), nil
+		}
+		return formatUint(uint64(v)), nil
+	case int:
+		return serializeJSONValue(int64(v), opts, path)
+	case uint32:
+		return formatUint(uint64(v)), nil
+	case int32:
+		return serializeJSONValue(int64(v), opts, path)
+	case float64:
+		return serializeJSONFloat(v, opts.FloatDecimals, path)
+	case float32:
+		return serializeJSONFloat(float64(v), opts.FloatDecimals, path)
+	case []byte:
+		if opts.BytesEncoding == JSON_BYTES_BASE64 {
+			return `"` + base64.StdEncoding.EncodeToString(v) + `"`, nil
+		}
+		return `"` + hex.EncodeToString(v) + `"`, nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	case nil:
+		return "null", nil
+	case map[string]interface{}:
+		return serializeJSONMap(v, opts, path)
+	case map[string]string:
+		// 特化处理纯字符串 map
+		result := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			result[k] = val
+		}
+		return serializeJSONMap(result, opts, path)
+	case map[string]uint64:
+		// 特化处理纯数字 map
+		result := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			result[k] = val
+		}
+		return serializeJSONMap(result, opts, path)
+	case []interface{}:
+		return serializeJSONArray(v, opts, path)
+	case []string:
+		// 特化处理字符串数组
+		arr := make([]interface{}, len(v))
+		for i, s := range v {
+			arr[i] = s
+		}
+		return serializeJSONArray(arr, opts, path)
+	case []uint64:
+		// 特化处理数字数组
+		arr := make([]interface{}, len(v))
+		for i, n := range v {
+			arr[i] = n
+		}
+		return serializeJSONArray(arr, opts, path)
+	default:
+		return "", NewContractError(ERROR_INVALID_PARAMS, "unsupported JSON value type at "+path)
+	}
+}
+
+// serializeJSONUint64 序列化 uint64，quote 为 true 时输出为字符串
+func serializeJSONUint64(n uint64, quote bool) string {
+	if quote {
+		return `"` + formatUint(n) + `"`
+	}
+	return formatUint(n)
+}
+
+// serializeJSONFloat 以固定小数位数序列化浮点数（四舍五入，不使用科学计数法）
+//
+// 不依赖 strconv 的浮点格式化，输出与平台无关；NaN、±Inf 与整数部分超过 uint64 的值无法表示，返回错误
+func serializeJSONFloat(v float64, decimals int, path string) (string, error) {
+	if v != v || v > math.MaxFloat64 || v < -math.MaxFloat64 {
+		return "", NewContractError(ERROR_INVALID_PARAMS, "JSON cannot represent NaN or Inf at "+path)
+	}
+	negative := v < 0
+	if negative {
+		v = -v
+	}
+
+	// 整数部分与小数部分分别取整，避免 v × 10^decimals 超出 uint64
+	intPart := math.Floor(v)
+	if intPart >= 18446744073709551616.0 { // 2^64
+		return "", NewContractError(ERROR_INVALID_PARAMS, "float out of range at "+path)
+	}
+	whole := uint64(intPart)
+	scale := math.Pow10(decimals)
+	frac := uint64(math.Floor((v-intPart)*scale + 0.5))
+	if float64(frac) >= scale { // 小数部分进位到整数
+		frac = 0
+		if whole == math.MaxUint64 {
+			return "", NewContractError(ERROR_INVALID_PARAMS, "float out of range at "+path)
+		}
+		whole++
+	}
+
+	result := formatUint(whole)
+	if decimals > 0 {
+		digits := formatUint(frac)
+		for len(digits) < decimals {
+			digits = "0" + digits
+		}
+		result += "." + digits
+	}
+	// 四舍五入后为 0 的负数不输出负号
+	if negative && (whole != 0 || frac != 0) {
+		result = "-" + result
+	}
+	return result, nil
+}
+
+// serializeMapToJSON 序列化 map 为 JSON 对象，包含不支持的类型时返回空字符串
+func serializeMapToJSON(m map[string]interface{}) string {
+	result, _ := serializeJSONMap(m, DefaultJSONOptions(), "$")
+	return result
+}
+
+// serializeJSONMap 序列化 map 为 JSON 对象，键按字典序输出，相同内容的返回值字节一致
+func serializeJSONMap(m map[string]interface{}, opts JSONOptions, path string) (string, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := "{"
+	for i, key := range keys {
+		valueJSON, err := serializeJSONValue(m[key], opts, path+"."+key)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			result += ","
+		}
+		result += `"` + escapeJSONStringWith(key, opts.EscapeNonASCII) + `":` + valueJSON
+	}
+	result += "}"
+	return result, nil
+}
+
+// serializeJSONArray 序列化数组为 JSON 数组
+func serializeJSONArray(arr []interface{}, opts JSONOptions, path string) (string, error) {
+	if len(arr) == 0 {
+		return "[]", nil
+	}
+
+	result := "["
+	for i, item := range arr {
+		itemJSON, err := serializeJSONValue(item, opts, path+"["+formatUint(uint64(i))+"]")
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			result += ","
+		}
+		result += itemJSON
+	}
+	result += "]"
+	return result, nil
+}
+
+// escapeJSONString 转义 JSON 字符串中的特殊字符
+//
+// '"'、'\\' 与 \n、\r、\t 使用简写，其余控制字符（0x00-0x1F）输出为 \u00XX；
+// 无效的 UTF-8 字节替换为 U+FFFD，保证结果可被标准 JSON 解析器解析
+func escapeJSONString(s string) string {
+	return escapeJSONStringWith(s, false)
+}
+
+// escapeJSONStringWith 同 escapeJSONString；asciiOnly 为 true 时非 ASCII 字符也输出为 \uXXXX
+func escapeJSONStringWith(s string, asciiOnly bool) string {
+	const hexChars = "0123456789abcdef"
+	appendU := func(buf []byte, r rune) []byte {
+		return append(buf, '\\', 'u', hexChars[r>>12&0xF], hexChars[r>>8&0xF], hexChars[r>>4&0xF], hexChars[r&0xF])
+	}
+
+	buf := make([]byte, 0, len(s))
+	for _, c := range s {
+		switch {
+		case c == '"':
+			buf = append(buf, '\\', '"')
+		case c == '\\':
+			buf = append(buf, '\\', '\\')
+		case c == '\n':
+			buf = append(buf, '\\', 'n')
+		case c == '\r':
+			buf = append(buf, '\\', 'r')
+		case c == '\t':
+			buf = append(buf, '\\', 't')
+		case c < 0x20:
+			buf = appendU(buf, c)
+		case c < 0x80 || !asciiOnly:
+			buf = append(buf, string(c)...)
+		case c > 0xFFFF:
+			// 超出 BMP：按 UTF-16 代理对输出
+			c -= 0x10000
+			buf = appendU(buf, 0xD800+(c>>10))
+			buf = appendU(buf, 0xDC00+(c&0x3FF))
+		default:
+			buf = appendU(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+// ParseUint64 从字符串解析uint64
+func ParseUint64(s string) uint64 {
+	var result uint64
+	for _, digit := range s {
+		if digit >= '0' && digit <= '9' {
+			result = result*10 + uint64(digit-'0')
+		} else {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestSerializeToJSONSafeLargeAmounts 测试安全模式下超过 2^53 的金额输出为字符串并可还原
+func TestSerializeToJSONSafeLargeAmounts(t *testing.T) {
+	const large = uint64(1)<<53 + 1 // 9007199254740993，双精度无法精确表示
+
+	tests := []struct {
+		name string
+		obj  interface{}
+		want string
+	}{
+		{"amount", Amount(large), `"9007199254740993"`},
+		{"uint64", large, `"9007199254740993"`},
+		{"max uint64", uint64(18446744073709551615), `"18446744073709551615"`},
+		{"small amount", Amount(42), `"42"`},
+		{"uint64 array", []uint64{large, 0}, `["9007199254740993","0"]`},
+		{"nested", map[string]interface{}{"balances": []interface{}{Amount(large)}}, `{"balances":["9007199254740993"]}`},
+		{"uint64 map", map[string]uint64{"total": large}, `{"total":"9007199254740993"}`},
+		{"other integers unchanged", []interface{}{uint32(7), int64(-3), 5, "s", true}, `[7,-3,5,"s",true]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serializeToJSONSafe(tt.obj); got != tt.want {
+				t.Errorf("serializeToJSONSafe() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 还原：去掉引号后用 ParseUint64 解析
+	encoded := serializeToJSONSafe(map[string]interface{}{"balance": Amount(large)})
+	value := strings.TrimSuffix(strings.TrimPrefix(encoded, `{"balance":"`), `"}`)
+	if got := ParseUint64(value); got != large {
+		t.Errorf("ParseUint64(%q) = %d, want %d", value, got, large)
+	}
+}
+
+// TestSerializeToJSONNumbers 测试默认模式仍将 Amount/uint64 输出为数字
+func TestSerializeToJSONNumbers(t *testing.T) {
+	got := serializeToJSON(map[string]interface{}{"balance": Amount(1<<53 + 1)})
+	if got != `{"balance":9007199254740993}` {
+		t.Errorf("serializeToJSON() = %s", got)
+	}
+	if got := serializeToJSON(struct{}{}); got != "" {
+		t.Errorf("serializeToJSON(unsupported) = %q, want empty", got)
+	}
+}
+
+// TestSerializeToJSONFloatAndBytes 测试混合 Amount、float64 与 []byte 的 map：浮点数固定小数位，字节按选项编码
+func TestSerializeToJSONFloatAndBytes(t *testing.T) {
+	hash := []byte{0xde, 0xad, 0xbe, 0xef, 0x00}
+	result := map[string]interface{}{
+		"amount": Amount(1500),
+		"rate":   0.0525,
+		"hash":   hash,
+		"nested": []interface{}{1e18, -0.0000004, []byte{}},
+	}
+
+	tests := []struct {
+		name string
+		opts JSONOptions
+		want string
+	}{
+		{"default", DefaultJSONOptions(), `{"amount":1500,"hash":"deadbeef00","nested":[1000000000000000000.000000,0.000000,""],"rate":0.052500}`},
+		{"base64 two decimals", JSONOptions{BytesEncoding: JSON_BYTES_BASE64, FloatDecimals: 2}, `{"amount":1500,"hash":"3q2+7wA=","nested":[1000000000000000000.00,0.00,""],"rate":0.05}`},
+		{"safe integer decimals", JSONOptions{QuoteUint64: true, FloatDecimals: 0}, `{"amount":"1500","hash":"deadbeef00","nested":[1000000000000000000,0,""],"rate":0}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalJSON(result, tt.opts)
+			if err != nil || got != tt.want {
+				t.Errorf("marshalJSON() = %s, %v\nwant %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestSerializeJSONFloatFormatting 测试浮点数四舍五入、进位与负数，不使用科学计数法
+func TestSerializeJSONFloatFormatting(t *testing.T) {
+	tests := []struct {
+		v        float64
+		decimals int
+		want     string
+	}{
+		{3.14159, 2, "3.14"},
+		{2.5, 0, "3"},
+		{0.9996, 3, "1.000"},
+		{-1.25, 1, "-1.3"},
+		{-0.0004, 3, "0.000"},
+		{1e-7, 6, "0.000000"},
+		{123456789.5, 1, "123456789.5"},
+		{float64(float32(0.5)), 2, "0.50"},
+	}
+	for _, tt := range tests {
+		if got, err := serializeJSONFloat(tt.v, tt.decimals, "$"); err != nil || got != tt.want {
+			t.Errorf("serializeJSONFloat(%v, %d) = %q, %v, want %q", tt.v, tt.decimals, got, err, tt.want)
+		}
+	}
+	if got := serializeToJSON(float32(1.5)); got != "1.500000" {
+		t.Errorf("serializeToJSON(float32) = %q", got)
+	}
+}
+
+// TestSerializeJSONErrors 测试不支持的类型、NaN/Inf 与无效选项返回带字段路径的错误
+func TestSerializeJSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      interface{}
+		opts     JSONOptions
+		wantPath string
+	}{
+		{"struct in map", map[string]interface{}{"pool": map[string]interface{}{"owner": struct{}{}}}, DefaultJSONOptions(), "$.pool.owner"},
+		{"pointer in array", map[string]interface{}{"items": []interface{}{"a", new(int)}}, DefaultJSONOptions(), "$.items[1]"},
+		{"NaN", map[string]interface{}{"rate": math.NaN()}, DefaultJSONOptions(), "$.rate"},
+		{"Inf", []interface{}{math.Inf(-1)}, DefaultJSONOptions(), "$[0]"},
+		{"float beyond uint64", 1e20 * 1e10, DefaultJSONOptions(), "$"},
+		{"bytes encoding", "x", JSONOptions{BytesEncoding: "base58"}, "base58"},
+		{"decimals", "x", JSONOptions{FloatDecimals: 19}, "between 0 and 18"},
+	}
+	for _, tt := range tests {
+		got, err := marshalJSON(tt.obj, tt.opts)
+		contractErr, ok := err.(*ContractError)
+		if got != "" || !ok || contractErr.Code != ERROR_INVALID_PARAMS || !strings.Contains(contractErr.Message, tt.wantPath) {
+			t.Errorf("%s: marshalJSON() = %q, %v, want ERROR_INVALID_PARAMS mentioning %q", tt.name, got, err, tt.wantPath)
+		}
+	}
+
+	// 不支持的字段不再被静默丢弃：整个结果为空
+	if got := serializeToJSON(map[string]interface{}{"ok": 1, "bad": struct{}{}}); got != "" {
+		t.Errorf("serializeToJSON() with unsupported field = %q, want empty", got)
+	}
+	if err := SetReturnJSON(map[string]interface{}{"bad": struct{}{}}); err == nil {
+		t.Error("SetReturnJSON() with unsupported field succeeded")
+	}
+}
+
+// TestEscapeJSONStringControlCharacters 测试包含空字节、换行等控制字符的字符串可被标准 JSON 解析器还原
+func TestEscapeJSONStringControlCharacters(t *testing.T) {
+	inputs := []string{
+		"reason\x00with\x00nulls",
+		"line1\nline2\r\n\tindented",
+		"bell\x07 backspace\b formfeed\f esc\x1b unit\x1f",
+		`quote " backslash \ slash /`,
+		"中文理由 émoji 😀",
+		"\u2028\u2029\x7f",
+	}
+	for _, s := range inputs {
+		for _, opts := range []JSONOptions{DefaultJSONOptions(), {EscapeNonASCII: true}} {
+			encoded, err := marshalJSON(map[string]interface{}{"reason": s, s: "key"}, opts)
+			if err != nil {
+				t.Fatalf("marshalJSON(%q) error = %v", s, err)
+			}
+			for i := 0; i < len(encoded); i++ {
+				if encoded[i] < 0x20 || (opts.EscapeNonASCII && encoded[i] >= 0x80) {
+					t.Errorf("marshalJSON(%q, ascii=%v) = %q contains raw byte %#x", s, opts.EscapeNonASCII, encoded, encoded[i])
+					break
+				}
+			}
+			var decoded map[string]string
+			if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+				t.Fatalf("json.Unmarshal(%q) error = %v", encoded, err)
+			}
+			if decoded["reason"] != s || decoded[s] != "key" {
+				t.Errorf("round trip of %q = %q", s, decoded)
+			}
+		}
+	}
+
+	if got := escapeJSONString("a\x00b\nc\x1f"); got != `a\u0000b\nc\u001f` {
+		t.Errorf("escapeJSONString() = %s", got)
+	}
+	if got := escapeJSONStringWith("é😀", true); got != `\u00e9\ud83d\ude00` {
+		t.Errorf("escapeJSONStringWith(ascii) = %s", got)
+	}
+	// 无效的 UTF-8 字节替换为 U+FFFD
+	if got := escapeJSONString("a\xffb"); got != "a\uFFFDb" {
+		t.Errorf("escapeJSONString(invalid utf-8) = %q", got)
+	}
+}
+
+// TestEventToJSONEscapesFields 测试事件字段中的控制字符不会产生无效 JSON
+func TestEventToJSONEscapesFields(t *testing.T) {
+	reason := "资料不全\n\x00补充\"说明\""
+	event := NewEvent("ClaimReviewed")
+	event.AddStringField("reason", reason)
+
+	var decoded struct {
+		Event string            `json:"event"`
+		Data  map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(event.ToJSON()), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal(%q) error = %v", event.ToJSON(), err)
+	}
+	if decoded.Event != "ClaimReviewed" || decoded.Data["reason"] != reason {
+		t.Errorf("decoded event = %+v", decoded)
+	}
+	if got := BuildJSONArray([]string{"a\x01", `b"`}); got != `["a\u0001","b\""]` {
+		t.Errorf("BuildJSONArray() = %s", got)
+	}
+}
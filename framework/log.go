@@ -0,0 +1,48 @@
+package framework
+
+import "github.com/weisyn/contract-sdk-go/framework/secure"
+
+// ==================== 结构化日志 ====================
+//
+// 日志以 "Log" 事件发出（Data: level, message 及附加字段）。
+// 秘密值（种子、邀请码、签名、哈希锁原像等）应使用 Secret 字段记录，
+// 发出前自动脱敏为短前缀与长度（secure.RedactForLog），完整值不会出现在事件中。
+
+// LogField 日志附加字段
+type LogField struct {
+	key   string
+	value interface{}
+}
+
+// Field 普通日志字段，值原样写入日志事件
+func Field(key string, value interface{}) LogField {
+	return LogField{key: key, value: value}
+}
+
+// Secret 秘密值日志字段，写入日志事件前自动脱敏
+//
+// **示例**：
+//
+//	framework.EmitLog("debug", "seed revealed", framework.Secret("seed", seed))
+func Secret(key string, value []byte) LogField {
+	return LogField{key: key, value: secure.RedactForLog(value)}
+}
+
+// EmitLog 发出日志事件
+//
+// **参数**：
+//   - level: 日志级别（如 "debug"、"info"、"error"）
+//   - message: 日志消息
+//   - fields: 附加字段；与 level/message 同名的字段被忽略
+func EmitLog(level, message string, fields ...LogField) error {
+	event := NewEvent("Log")
+	for _, f := range fields {
+		if f.key == "level" || f.key == "message" {
+			continue
+		}
+		event.Data[f.key] = f.value
+	}
+	event.Data["level"] = level
+	event.Data["message"] = message
+	return EmitEvent(event)
+}
@@ -0,0 +1,42 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestEmitLogRedactsSecrets 测试 Secret 字段在发出的日志事件中不出现完整值
+func TestEmitLogRedactsSecrets(t *testing.T) {
+	host := NewMockHost()
+	defer InstallMockHost(host)()
+	seed := []byte("0123456789abcdef-reveal-seed-value")
+
+	var cb ContractBase
+	res := host.Invoke(Address{1}, nil, func() uint32 {
+		if err := cb.EmitLog("debug", "seed revealed", Secret("seed", seed), Field("round", uint64(3)), Field("level", "spoofed")); err != nil {
+			return ERROR_EXECUTION_FAILED
+		}
+		return SUCCESS
+	})
+	if res.Code != SUCCESS || len(res.Events) != 1 {
+		t.Fatalf("Invoke() = %d with %d events, want SUCCESS with 1 event", res.Code, len(res.Events))
+	}
+
+	e := res.Events[0]
+	if e.Name != "Log" || e.Data["level"] != "debug" || e.Data["message"] != "seed revealed" || e.Data["round"] != uint64(3) {
+		t.Errorf("Log event = %v", e.Data)
+	}
+	if got, want := e.Data["seed"], "3031…(34 bytes)"; got != want {
+		t.Errorf("seed field = %v, want %q", got, want)
+	}
+	payload := fmt.Sprint(e.Data)
+	for _, leak := range []string{string(seed), hex.EncodeToString(seed), string(seed[:8])} {
+		if strings.Contains(payload, leak) {
+			t.Errorf("log payload contains secret %q: %s", leak, payload)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package framework
+
+// ==================== 原生币与代币ID ====================
+//
+// 全框架统一约定：空代币ID（TokenID("")）表示原生币 WES，非空代币ID表示合约代币。
+// QueryBalance / QueryUTXOBalance / TransactionBuilder 接受原生币；Mint、Burn、Approve、Freeze
+// 等只对合约代币有意义的操作拒绝原生币（ValidateTokenID）。合约应通过 IsNativeToken 分支，
+// 而不是直接与 "" 比较。
+
+// NativeTokenID 返回原生币的代币ID
+//
+// **示例**：
+//
+//	balance := framework.QueryBalance(addr, framework.NativeTokenID())
+func NativeTokenID() TokenID {
+	return ""
+}
+
+// IsNativeToken 判断代币ID是否表示原生币
+//
+// **示例**：
+//
+//	if framework.IsNativeToken(tokenID) {
+//	    // 原生币：不能铸造/销毁，只能转账
+//	}
+func IsNativeToken(tokenID TokenID) bool {
+	return tokenID == NativeTokenID()
+}
+
+// ValidateTokenID 验证代币ID为合约代币
+//
+// **返回**：
+//   - error: 代币ID表示原生币（空代币ID）时返回 ERROR_INVALID_PARAMS
+//
+// **注意**：只用于不支持原生币的操作（铸造、销毁、授权、冻结等）；转账与余额查询接受原生币
+func ValidateTokenID(tokenID TokenID) error {
+	if IsNativeToken(tokenID) {
+		return NewContractError(ERROR_INVALID_PARAMS, "invalid empty token ID")
+	}
+	return nil
+}
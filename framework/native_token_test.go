@@ -0,0 +1,81 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// testMintExport 示例铸造：与 helpers/token.Mint 一致，原生币不能铸造
+func testMintExport(to Address, tokenID TokenID, amount Amount) error {
+	if err := ValidateTokenID(tokenID); err != nil {
+		return err
+	}
+	success, _, errCode := BeginTransaction().AddAssetOutput(to, tokenID, amount).Finalize()
+	if !success {
+		return NewContractError(errCode, "mint failed")
+	}
+	return nil
+}
+
+// TestNativeTokenID 测试原生币代币ID的判断与校验
+func TestNativeTokenID(t *testing.T) {
+	if !IsNativeToken(NativeTokenID()) || !IsNativeToken(TokenID("")) {
+		t.Error("IsNativeToken(NativeTokenID()) = false, want true")
+	}
+	if IsNativeToken("USDT") {
+		t.Error("IsNativeToken(USDT) = true, want false")
+	}
+	if err := ValidateTokenID(NativeTokenID()); err == nil || err.(*ContractError).Code != ERROR_INVALID_PARAMS {
+		t.Errorf("ValidateTokenID(native) = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if err := ValidateTokenID("USDT"); err != nil {
+		t.Errorf("ValidateTokenID(USDT) = %v, want nil", err)
+	}
+}
+
+// TestNativeAndTokenBalancesAreSeparate 测试查询、转账、铸造路径中原生币与合约代币互不影响
+func TestNativeAndTokenBalancesAreSeparate(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	alice, bob := Address{0x01}, Address{0x02}
+	host.SetBalance(alice, NativeTokenID(), 1000)
+	host.SetBalance(alice, "USDT", 50)
+
+	// 查询
+	if got := QueryBalance(alice, NativeTokenID()); got != 1000 {
+		t.Errorf("QueryBalance(native) = %d, want 1000", got)
+	}
+	if got := QueryUTXOBalance(alice, "USDT"); got != 50 {
+		t.Errorf("QueryUTXOBalance(USDT) = %d, want 50", got)
+	}
+
+	// 转账：原生币转账只减少原生币余额
+	if ok, _, code := BeginTransaction().Transfer(alice, bob, NativeTokenID(), 300).Finalize(); !ok {
+		t.Fatalf("native transfer code = %d", code)
+	}
+	if ok, _, code := BeginTransaction().Transfer(alice, bob, "USDT", 20).Finalize(); !ok {
+		t.Fatalf("token transfer code = %d", code)
+	}
+	if _, _, code := BeginTransaction().Transfer(alice, bob, "USDT", 100).Finalize(); code != ERROR_INSUFFICIENT_BALANCE {
+		t.Errorf("token transfer over balance code = %d, want ERROR_INSUFFICIENT_BALANCE", code)
+	}
+	want := map[TokenID][2]Amount{NativeTokenID(): {700, 300}, "USDT": {30, 20}}
+	for tokenID, w := range want {
+		if a, b := QueryBalance(alice, tokenID), QueryBalance(bob, tokenID); a != w[0] || b != w[1] {
+			t.Errorf("balances of %q = %d/%d, want %d/%d", tokenID, a, b, w[0], w[1])
+		}
+	}
+
+	// 铸造：合约代币可以铸造，原生币拒绝且余额不变
+	if err := testMintExport(bob, "USDT", 5); err != nil {
+		t.Fatalf("mint USDT error = %v", err)
+	}
+	if got := QueryBalance(bob, "USDT"); got != 25 {
+		t.Errorf("QueryBalance(USDT) after mint = %d, want 25", got)
+	}
+	if err := testMintExport(bob, NativeTokenID(), 5); err == nil || err.(*ContractError).Code != ERROR_INVALID_PARAMS {
+		t.Errorf("mint native error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if got := QueryBalance(bob, NativeTokenID()); got != 300 {
+		t.Errorf("QueryBalance(native) after rejected mint = %d, want 300", got)
+	}
+}
@@ -0,0 +1,142 @@
+package framework
+
+// ==================== 带单位的参数解析 ====================
+//
+// ParseJSONInt 无法区分"字段缺失"与"值为 0"，遇到非数字字符即停止（"-5" 解析为 0、
+// "1e4" 解析为 1），并且会匹配嵌套对象中的同名字段。以下解析器只读取顶层字段，
+// 返回带单位的类型（见 units.go），格式或范围错误时返回 NewFieldError 创建的字段错误：
+//
+//	period, err := params.ParseRequiredDuration("settlement_period")
+//	if err != nil {
+//	    fieldErr := err.(*framework.ContractError)
+//	    framework.SetReturnJSON(fieldErr.Detail()) // {"error":"ERROR_INVALID_PARAMS","field":"settlement_period",...}
+//	    return fieldErr.Code
+//	}
+//
+// 数值可以是 JSON 数字或十进制字符串（如 "800"），null 视为缺失。
+
+// ParseDuration 解析以秒为单位的时长参数，字段缺失返回 0
+func (cp *ContractParams) ParseDuration(key string) (DurationSeconds, error) {
+	value, err := cp.parseUintParam(key)
+	return DurationSeconds(value), err
+}
+
+// ParseRequiredDuration 解析必填的时长参数，字段缺失或为 0 时返回字段错误
+func (cp *ContractParams) ParseRequiredDuration(key string) (DurationSeconds, error) {
+	value, err := cp.parseUintParam(key)
+	if err != nil {
+		return 0, err
+	}
+	if value == 0 {
+		return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "duration must be greater than 0")
+	}
+	return DurationSeconds(value), nil
+}
+
+// ParseTimestamp 解析 Unix 时间戳参数（秒），字段缺失返回 0
+func (cp *ContractParams) ParseTimestamp(key string) (TimestampUnix, error) {
+	value, err := cp.parseUintParam(key)
+	return TimestampUnix(value), err
+}
+
+// ParseBasisPoints 解析万分比参数，超过 10000（100%）时返回字段错误；字段缺失返回 0
+func (cp *ContractParams) ParseBasisPoints(key string) (BasisPoints, error) {
+	return cp.ParseBasisPointsUpTo(key, MAX_BASIS_POINTS)
+}
+
+// ParseBasisPointsUpTo 解析万分比参数，超过 max 时返回字段错误；字段缺失返回 0
+func (cp *ContractParams) ParseBasisPointsUpTo(key string, max BasisPoints) (BasisPoints, error) {
+	value, err := cp.parseUintParam(key)
+	if err != nil {
+		return 0, err
+	}
+	if value > uint64(max) {
+		return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "basis points must not exceed "+formatUint(uint64(max)))
+	}
+	return BasisPoints(value), nil
+}
+
+// ParseAmount 解析金额参数（最小单位），字段缺失返回 0
+func (cp *ContractParams) ParseAmount(key string) (Amount, error) {
+	value, err := cp.parseUintParam(key)
+	return Amount(value), err
+}
+
+// ParseAddressArray 解析 Base58 地址数组参数（如 recipients、validators），字段缺失返回 nil
+//
+// **返回**：
+//   - []Address: 按参数顺序排列的地址，空数组返回长度为 0 的切片
+//   - error: 值不是字符串数组、或某一项不是有效的 Base58 地址时返回字段错误，消息指明第一个无效项的下标与内容
+//
+// **示例**：
+//
+//	recipients, err := params.ParseAddressArray("recipients")
+//	// {"recipients": ["Cf1...", "bad"]} → recipients: index 1 ("bad"): invalid base58 address format
+func (cp *ContractParams) ParseAddressArray(key string) ([]Address, error) {
+	if len(cp.data) == 0 {
+		return nil, nil
+	}
+	members, ok := jsonObjectMembers(string(cp.data))
+	if !ok {
+		return nil, NewFieldError(ERROR_INVALID_PARAMS, key, "params are not a JSON object")
+	}
+	raw, ok := members[key]
+	if !ok || raw == "null" {
+		return nil, nil
+	}
+	items, ok := jsonArrayElements(raw)
+	if !ok {
+		return nil, NewFieldError(ERROR_INVALID_PARAMS, key, "value must be an array of addresses")
+	}
+
+	addrs := make([]Address, 0, len(items))
+	for i, item := range items {
+		str, quoted := jsonPlainString(item)
+		if !quoted {
+			return nil, NewFieldError(ERROR_INVALID_PARAMS, key, "index "+Uint64ToString(uint64(i))+": address must be a string")
+		}
+		addr, err := ParseAddressBase58(str)
+		if err != nil {
+			return nil, NewFieldError(ERROR_INVALID_PARAMS, key, "index "+Uint64ToString(uint64(i))+" (\""+str+"\"): "+err.Error())
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// parseUintParam 读取顶层非负整数参数
+//
+// 字段缺失时返回 0；参数不是 JSON 对象、值不是非负整数或超过 uint64 时返回字段错误
+func (cp *ContractParams) parseUintParam(key string) (uint64, error) {
+	if len(cp.data) == 0 {
+		return 0, nil
+	}
+	members, ok := jsonObjectMembers(string(cp.data))
+	if !ok {
+		return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "params are not a JSON object")
+	}
+	raw, ok := members[key]
+	if !ok || raw == "null" {
+		return 0, nil
+	}
+	if s, quoted := jsonPlainString(raw); quoted {
+		raw = s
+	}
+	if raw == "" {
+		return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "value must be a non-negative integer")
+	}
+
+	var value uint64
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c < '0' || c > '9' {
+			return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "value must be a non-negative integer")
+		}
+		digit := uint64(c - '0')
+		if value > (^uint64(0)-digit)/10 {
+			return 0, NewFieldError(ERROR_INVALID_PARAMS, key, "value overflows uint64")
+		}
+		value = value*10 + digit
+	}
+	return value, nil
+}
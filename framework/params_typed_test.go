@@ -0,0 +1,373 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// TestParseTypedParams 测试带单位的参数解析：数字与字符串数值、缺失字段、只读取顶层字段
+func TestParseTypedParams(t *testing.T) {
+	params := NewContractParams([]byte(`{"period": 2592000, "fee_bp": "800", "cap": 10000, "start": null, "nested": {"wait": 5}}`))
+
+	if d, err := params.ParseDuration("period"); err != nil || d != Days(30) {
+		t.Errorf("ParseDuration(period) = %d, %v", d, err)
+	}
+	if bp, err := params.ParseBasisPoints("fee_bp"); err != nil || bp != 800 {
+		t.Errorf("ParseBasisPoints(fee_bp) = %d, %v", bp, err)
+	}
+	if a, err := params.ParseAmount("cap"); err != nil || a != 10000 {
+		t.Errorf("ParseAmount(cap) = %d, %v", a, err)
+	}
+	if ts, err := params.ParseTimestamp("start"); err != nil || ts != 0 {
+		t.Errorf("ParseTimestamp(null) = %d, %v, want 0", ts, err)
+	}
+	if d, err := params.ParseDuration("wait"); err != nil || d != 0 {
+		t.Errorf("ParseDuration(nested wait) = %d, %v, want 0 (top-level only)", d, err)
+	}
+}
+
+// TestParseTypedParamsFieldErrors 测试越界与格式错误返回指明字段的错误
+func TestParseTypedParamsFieldErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		parse func(p *ContractParams) error
+		field string
+	}{
+		{"bp above 100%", `{"fee_bp": 10001}`, func(p *ContractParams) error { _, err := p.ParseBasisPoints("fee_bp"); return err }, "fee_bp"},
+		{"bp above explicit max", `{"ltv_bp": 15001}`, func(p *ContractParams) error { _, err := p.ParseBasisPointsUpTo("ltv_bp", 15000); return err }, "ltv_bp"},
+		{"zero required duration", `{"period": 0}`, func(p *ContractParams) error { _, err := p.ParseRequiredDuration("period"); return err }, "period"},
+		{"missing required duration", `{}`, func(p *ContractParams) error { _, err := p.ParseRequiredDuration("period"); return err }, "period"},
+		{"negative", `{"wait": -5}`, func(p *ContractParams) error { _, err := p.ParseDuration("wait"); return err }, "wait"},
+		{"exponent", `{"cap": 1e4}`, func(p *ContractParams) error { _, err := p.ParseAmount("cap"); return err }, "cap"},
+		{"overflow", `{"cap": 18446744073709551616}`, func(p *ContractParams) error { _, err := p.ParseAmount("cap"); return err }, "cap"},
+	}
+	for _, tt := range tests {
+		err := tt.parse(NewContractParams([]byte(tt.raw)))
+		ce, ok := err.(*ContractError)
+		if !ok || ce.Code != ERROR_INVALID_PARAMS || ce.Field != tt.field {
+			t.Errorf("%s: error = %#v, want ERROR_INVALID_PARAMS on field %q", tt.name, err, tt.field)
+			continue
+		}
+		if detail := ce.Detail(); detail["field"] != tt.field || detail["error"] != "ERROR_INVALID_PARAMS" {
+			t.Errorf("%s: Detail() = %v", tt.name, detail)
+		}
+	}
+
+	if _, err := NewContractParams([]byte(`{"period": 0}`)).ParseDuration("period"); err != nil {
+		t.Errorf("ParseDuration() of optional zero error = %v, want nil", err)
+	}
+	if detail := NewContractError(ERROR_NOT_FOUND, "missing").Detail(); len(detail) != 2 {
+		t.Errorf("Detail() without field = %v, want error and message only", detail)
+	}
+}
+
+// TestParseAddressArray 测试地址数组参数：有效列表按顺序返回、空数组与缺失字段、无效项报告下标
+func TestParseAddressArray(t *testing.T) {
+	alice, bob := Address{0xA1}, Address{0xB2, 0x01}
+	valid := `{"recipients": ["` + alice.ToString() + `", "` + bob.ToString() + `"], "empty": [], "none": null}`
+	params := NewContractParams([]byte(valid))
+
+	addrs, err := params.ParseAddressArray("recipients")
+	if err != nil || len(addrs) != 2 || !addrs[0].Equals(alice) || !addrs[1].Equals(bob) {
+		t.Errorf("ParseAddressArray(recipients) = %x, %v, want [alice bob]", addrs, err)
+	}
+	if addrs, err := params.ParseAddressArray("empty"); err != nil || addrs == nil || len(addrs) != 0 {
+		t.Errorf("ParseAddressArray(empty) = %#v, %v, want empty slice", addrs, err)
+	}
+	for _, key := range []string{"none", "missing"} {
+		if addrs, err := params.ParseAddressArray(key); err != nil || addrs != nil {
+			t.Errorf("ParseAddressArray(%s) = %x, %v, want nil", key, addrs, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		message string
+	}{
+		{"malformed entry", `{"recipients": ["` + alice.ToString() + `", "not-an-address", "` + bob.ToString() + `"]}`,
+			`recipients: index 1 ("not-an-address"): invalid base58 address format`},
+		{"empty entry", `{"recipients": [""]}`, `recipients: index 0 (""): address string cannot be empty`},
+		{"non-string entry", `{"recipients": ["` + alice.ToString() + `", 42]}`, `recipients: index 1: address must be a string`},
+		{"not an array", `{"recipients": "` + alice.ToString() + `"}`, `recipients: value must be an array of addresses`},
+	}
+	for _, tt := range tests {
+		addrs, err := NewContractParams([]byte(tt.raw)).ParseAddressArray("recipients")
+		ce, ok := err.(*ContractError)
+		if !ok || addrs != nil || ce.Code != ERROR_INVALID_PARAMS || ce.Field != "recipients" || ce.Message != tt.message {
+			t.Errorf("%s: ParseAddressArray() = %x, %#v, want field error %q", tt.name, addrs, err, tt.message)
+		}
+	}
+}
+
+// TestParseJSONNestedPaths 测试点分路径读取嵌套对象与对象数组，同名键只匹配所在层级
+func TestParseJSONNestedPaths(t *testing.T) {
+	params := NewContractParams([]byte(`{"metadata": {"name": "X", "author": "Y", "edition": 3, "tags": {"name": "inner"}},` +
+		` "documents": [{"hash": "0xaa", "pages": 2}, {"hash": "0xbb"}], "name": "top", "a.b": "literal", "a": {"b": "nested"}}`))
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"name", "top"},
+		{"metadata.name", "X"},
+		{"metadata.author", "Y"},
+		{"metadata.tags.name", "inner"},
+		{"documents.0.hash", "0xaa"},
+		{"documents.1.hash", "0xbb"},
+		{"a.b", "literal"},
+		{"author", ""},
+		{"metadata", ""},
+		{"metadata.edition", ""},
+		{"metadata.missing", ""},
+		{"documents.2.hash", ""},
+		{"documents.x.hash", ""},
+		{"name.first", ""},
+	}
+	for _, tt := range tests {
+		if got := params.ParseJSON(tt.key); got != tt.want {
+			t.Errorf("ParseJSON(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if got := params.ParseJSONInt("metadata.edition"); got != 3 {
+		t.Errorf("ParseJSONInt(metadata.edition) = %d, want 3", got)
+	}
+	if got := params.ParseJSONInt("documents.0.pages"); got != 2 {
+		t.Errorf("ParseJSONInt(documents.0.pages) = %d, want 2", got)
+	}
+	if got := params.ParseJSONInt("pages"); got != 0 {
+		t.Errorf("ParseJSONInt(pages) = %d, want 0 (nested only)", got)
+	}
+}
+
+// TestParseJSONIntChecked 测试有符号整数解析：负数、缺失与 0、越界与格式错误
+func TestParseJSONIntChecked(t *testing.T) {
+	params := NewContractParams([]byte(`{
+		"zero": 0, "positive": 800, "quoted": "800", "negative": -5, "quoted_negative": "-5",
+		"max": 9223372036854775807, "min": -9223372036854775808,
+		"over": 9223372036854775808, "under": -9223372036854775809,
+		"decimal": 1.5, "exponent": 1e4, "text": "abc", "minus": "-", "plus": "+5",
+		"empty": "", "null": null, "bool": true, "nested": {"count": -3}
+	}`))
+
+	tests := []struct {
+		key    string
+		want   int64
+		wantOK bool
+	}{
+		{"zero", 0, true},
+		{"missing", 0, true},
+		{"null", 0, true},
+		{"positive", 800, true},
+		{"quoted", 800, true},
+		{"negative", -5, true},
+		{"quoted_negative", -5, true},
+		{"nested.count", -3, true},
+		{"max", 1<<63 - 1, true},
+		{"min", -1 << 63, true},
+		{"over", 0, false},
+		{"under", 0, false},
+		{"decimal", 0, false},
+		{"exponent", 0, false},
+		{"text", 0, false},
+		{"minus", 0, false},
+		{"plus", 0, false},
+		{"empty", 0, false},
+		{"bool", 0, false},
+		{"nested", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := params.ParseJSONIntChecked(tt.key)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseJSONIntChecked(%q) = %d, %v, want %d, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// 兼容接口不变：负数仍返回 0
+	if got := params.ParseJSONInt("negative"); got != 0 {
+		t.Errorf("ParseJSONInt(negative) = %d, want 0", got)
+	}
+	if _, ok := NewContractParams([]byte(`not json`)).ParseJSONIntChecked("count"); ok {
+		t.Error("ParseJSONIntChecked on non-object params should fail")
+	}
+	if got, ok := NewContractParams(nil).ParseJSONIntChecked("count"); got != 0 || !ok {
+		t.Errorf("ParseJSONIntChecked on empty params = %d, %v, want 0, true", got, ok)
+	}
+}
+
+// TestParseJSONObject 测试子对象解析器：可链式读取，字段缺失或不是对象时返回空参数
+func TestParseJSONObject(t *testing.T) {
+	params := NewContractParams([]byte(`{"metadata":{"name":"X","links":{"home":"https://x.example"}},"title":"T","documents":[{"hash":"0xaa"}]}`))
+
+	metadata := params.ParseJSONObject("metadata")
+	if got := metadata.ParseJSON("name"); got != "X" {
+		t.Errorf("ParseJSONObject(metadata).ParseJSON(name) = %q, want X", got)
+	}
+	if got := metadata.ParseJSONObject("links").ParseJSON("home"); got != "https://x.example" {
+		t.Errorf("links.home = %q, want https://x.example", got)
+	}
+	if got := params.ParseJSONObject("documents.0").ParseJSON("hash"); got != "0xaa" {
+		t.Errorf("ParseJSONObject(documents.0).ParseJSON(hash) = %q, want 0xaa", got)
+	}
+	for _, key := range []string{"missing", "title", "documents"} {
+		if sub := params.ParseJSONObject(key); !sub.IsEmpty() || sub.ParseJSON("name") != "" {
+			t.Errorf("ParseJSONObject(%q) = %q, want empty params", key, sub.GetString())
+		}
+	}
+}
+
+// TestParseJSONEscapedValues 测试转义字符还原，值中的引号与括号不影响同级字段的定位
+func TestParseJSONEscapedValues(t *testing.T) {
+	params := NewContractParams([]byte(`{"memo":"say \"hi\" {not:\"a key\"}","path":"a\\b\/c","line":"x\ny\u00e9\ud83d\ude00","note":"\"name\":\"fake\"","asset":{"id":"x\"1","to":"inner"},"to":"real"}`))
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"memo", `say "hi" {not:"a key"}`},
+		{"path", `a\b/c`},
+		{"line", "x\nyé😀"},
+		{"asset.id", `x"1`},
+		{"to", "real"},
+	}
+	for _, tt := range tests {
+		if got := params.ParseJSON(tt.key); got != tt.want {
+			t.Errorf("ParseJSON(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestParseJSONLegacyFallback 测试参数不是合法 JSON 对象时按文本匹配读取字段
+func TestParseJSONLegacyFallback(t *testing.T) {
+	params := NewContractParams([]byte(`{"plan_id":"plan_001","amount":500,`))
+	if got := params.ParseJSON("plan_id"); got != "plan_001" {
+		t.Errorf("ParseJSON(plan_id) = %q, want plan_001", got)
+	}
+	if got := params.ParseJSONInt("amount"); got != 500 {
+		t.Errorf("ParseJSONInt(amount) = %d, want 500", got)
+	}
+}
+
+// TestParseJSONArrays 测试字符串、整数与对象数组：空白、引号内的逗号与括号、数字字符串、空数组与类型不符
+func TestParseJSONArrays(t *testing.T) {
+	params := NewContractParams([]byte(`{
+		"recipients": [ "addr1" , "a,b]" ,"say \"x,y\"" ],
+		"amounts": [100, "200" ,0],
+		"empty": [ ],
+		"mixed": ["addr1", 2],
+		"negative": [1, -2],
+		"overflow": [18446744073709551616],
+		"decimal": [1.5],
+		"name": "not an array",
+		"batch": {"to": ["x[1]", "y{2}"]},
+		"transfers": [{"to": "addr1", "amount": 100}, {"to": "a,}b", "amount": 7}],
+		"bad_transfers": [{"to": "addr1"}, "addr2"]
+	}`))
+
+	stringTests := []struct {
+		key  string
+		want []string
+	}{
+		{"recipients", []string{"addr1", "a,b]", `say "x,y"`}},
+		{"batch.to", []string{"x[1]", "y{2}"}},
+		{"empty", []string{}},
+		{"mixed", nil},
+		{"amounts", nil},
+		{"name", nil},
+		{"missing", nil},
+	}
+	for _, tt := range stringTests {
+		got := params.ParseJSONStringArray(tt.key)
+		if !equalStrings(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("ParseJSONStringArray(%q) = %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+
+	intTests := []struct {
+		key  string
+		want []uint64
+	}{
+		{"amounts", []uint64{100, 200, 0}},
+		{"empty", []uint64{}},
+		{"recipients", nil},
+		{"negative", nil},
+		{"overflow", nil},
+		{"decimal", nil},
+		{"name", nil},
+		{"missing", nil},
+	}
+	for _, tt := range intTests {
+		got := params.ParseJSONUintArray(tt.key)
+		if !equalSeqs(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("ParseJSONUintArray(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	transfers := params.ParseJSONObjectArray("transfers")
+	if len(transfers) != 2 || transfers[1].ParseJSON("to") != "a,}b" || transfers[1].ParseJSONInt("amount") != 7 || transfers[0].ParseJSONInt("amount") != 100 {
+		t.Errorf("ParseJSONObjectArray(transfers) = %v", transfers)
+	}
+	if got := params.ParseJSONObjectArray("empty"); got == nil || len(got) != 0 {
+		t.Errorf("ParseJSONObjectArray(empty) = %v, want empty slice", got)
+	}
+	for _, key := range []string{"bad_transfers", "recipients", "missing"} {
+		if got := params.ParseJSONObjectArray(key); got != nil {
+			t.Errorf("ParseJSONObjectArray(%q) = %v, want nil", key, got)
+		}
+	}
+}
+
+// TestHasJSONField 测试字段存在判断：null 视为缺失，支持点分路径，参数不是 JSON 对象时为 false
+func TestHasJSONField(t *testing.T) {
+	params := NewContractParams([]byte(`{"zero": 0, "empty": "", "none": null, "nested": {"wait": 5}}`))
+	for key, want := range map[string]bool{"zero": true, "empty": true, "none": false, "missing": false, "nested.wait": true, "wait": false} {
+		if got := params.HasJSONField(key); got != want {
+			t.Errorf("HasJSONField(%q) = %v, want %v", key, got, want)
+		}
+	}
+	if NewContractParams([]byte(`"zero": 0`)).HasJSONField("zero") {
+		t.Error("HasJSONField on non-object params = true")
+	}
+}
+
+// TestParseJSONArraysMalformed 测试数组未闭合、缺少或多余逗号、引号未闭合等格式错误时返回 nil
+func TestParseJSONArraysMalformed(t *testing.T) {
+	docs := []string{
+		`{"a": ["x", "y"`,
+		`{"a": ["x",,"y"]}`,
+		`{"a": ["x" "y"]}`,
+		`{"a": ["x",]}`,
+		`{"a": [,]}`,
+		`{"a": ["x], "b": 1}`,
+		`{"a": ["x"]] }`,
+		`{"a": [1 2]}`,
+		`{"a": [1,]}`,
+		`{"a": ["x"], "b": [}`,
+		`["x"]`,
+		`not json`,
+	}
+	for _, doc := range docs {
+		params := NewContractParams([]byte(doc))
+		if got := params.ParseJSONStringArray("a"); got != nil {
+			t.Errorf("ParseJSONStringArray on %s = %#v, want nil", doc, got)
+		}
+		if got := params.ParseJSONUintArray("a"); got != nil {
+			t.Errorf("ParseJSONUintArray on %s = %v, want nil", doc, got)
+		}
+		if got := params.ParseJSONObjectArray("a"); got != nil {
+			t.Errorf("ParseJSONObjectArray on %s = %v, want nil", doc, got)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
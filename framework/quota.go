@@ -0,0 +1,166 @@
+package framework
+
+// 写入预算与索引配额
+//
+// 合约导出函数按调用者输入构建索引（附件列表、审计日志等）时，单次调用可能写入
+// 任意多、任意大的状态，造成存储放大。本文件提供两类限制：
+//   - 写入预算：导出函数入口通过 DeclareWriteBudget 声明单次调用允许暂存的状态字节总数，
+//     状态输出暂存层（AppendStateOutputSimple、TransactionBuilder.AddStateOutput）逐次计量
+//   - 索引配额：通过 RegisterIndexQuota 为具名索引登记每个调用者的条目数上限与单条字节上限，
+//     由 AppendIndexEntry 在写入前校验
+//
+// 超出限制时统一返回 ERROR_QUOTA_EXCEEDED。Limits 汇总已登记的索引配额与批量查询（Multicall）
+// 限制，供合约的限制查询导出函数返回给客户端。
+
+// IndexQuota 具名索引的增长配额
+type IndexQuota struct {
+	// IndexName 索引名称
+	IndexName string
+	// MaxEntriesPerCaller 每个调用者（分区）允许的最大条目数，0 表示不限
+	MaxEntriesPerCaller uint32
+	// MaxEntryBytes 单条目最大字节数，0 表示不限
+	MaxEntryBytes uint32
+}
+
+var (
+	indexQuotas     = map[string]IndexQuota{}
+	indexQuotaOrder []string
+
+	writeBudgetBytes uint32
+	writeBudgetUsed  uint64
+)
+
+// RegisterIndexQuota 登记具名索引的增长配额
+//
+// 🎯 **用途**：约束按调用者输入增长的索引，通常在合约包的 init 中调用
+//
+// **参数**：
+//   - indexName: 索引名称，重复登记时覆盖之前的配额
+//   - maxEntriesPerCaller: 每个调用者（分区）的最大条目数，0 表示不限
+//   - maxEntryBytes: 单条目最大字节数，0 表示不限
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterIndexQuota("claim_evidence", 16, 256)
+//	}
+func RegisterIndexQuota(indexName string, maxEntriesPerCaller uint32, maxEntryBytes uint32) {
+	if _, ok := indexQuotas[indexName]; !ok {
+		indexQuotaOrder = append(indexQuotaOrder, indexName)
+	}
+	indexQuotas[indexName] = IndexQuota{
+		IndexName:           indexName,
+		MaxEntriesPerCaller: maxEntriesPerCaller,
+		MaxEntryBytes:       maxEntryBytes,
+	}
+}
+
+// GetIndexQuota 查询具名索引的配额，未登记时返回 false
+func GetIndexQuota(indexName string) (IndexQuota, bool) {
+	q, ok := indexQuotas[indexName]
+	return q, ok
+}
+
+// CheckIndexQuota 校验向索引追加一条目是否超出配额
+//
+// **参数**：
+//   - indexName: 索引名称，未登记配额的索引不受限制
+//   - currentEntries: 该调用者（分区）当前已有的条目数
+//   - entryBytes: 待追加条目的字节数
+//
+// **返回**：超出配额时返回 ERROR_QUOTA_EXCEEDED
+func CheckIndexQuota(indexName string, currentEntries uint64, entryBytes int) error {
+	q, ok := indexQuotas[indexName]
+	if !ok {
+		return nil
+	}
+	if q.MaxEntryBytes > 0 && entryBytes > int(q.MaxEntryBytes) {
+		return NewContractError(ERROR_QUOTA_EXCEEDED, "index "+indexName+": entry exceeds "+formatUint(uint64(q.MaxEntryBytes))+" bytes")
+	}
+	if q.MaxEntriesPerCaller > 0 && currentEntries >= uint64(q.MaxEntriesPerCaller) {
+		return NewContractError(ERROR_QUOTA_EXCEEDED, "index "+indexName+": at most "+formatUint(uint64(q.MaxEntriesPerCaller))+" entries per caller")
+	}
+	return nil
+}
+
+// DeclareWriteBudget 声明本次调用允许暂存的状态字节总数
+//
+// 🎯 **用途**：在导出函数入口调用，限制单次调用的状态写入放大
+//
+// **参数**：
+//   - maxBytesPerCall: 本次调用所有状态输出的 stateID 与负载字节之和上限，0 表示不限
+//
+// **注意**：
+//   - 声明会重置已计量的字节数，每个导出函数入口各自声明
+//   - 在入口的 BeginInvocation 之后声明：BeginInvocation 会清空之前的声明，
+//     复用的 WASM 实例不会沿用上一次调用的预算与已计量字节数；读取参数不影响已声明的预算
+//   - 超出预算的状态输出不会被暂存，返回 ERROR_QUOTA_EXCEEDED
+func DeclareWriteBudget(maxBytesPerCall uint32) {
+	writeBudgetBytes = maxBytesPerCall
+	writeBudgetUsed = 0
+}
+
+// WriteBudget 返回当前声明的写入预算与已使用字节数
+func WriteBudget() (limit uint32, used uint64) {
+	return writeBudgetBytes, writeBudgetUsed
+}
+
+// chargeWriteBudget 计量一次状态输出，超出预算时返回错误且不计入
+func chargeWriteBudget(n int) error {
+	if writeBudgetBytes > 0 && writeBudgetUsed+uint64(n) > uint64(writeBudgetBytes) {
+		return NewContractError(ERROR_QUOTA_EXCEEDED, "write budget of "+formatUint(uint64(writeBudgetBytes))+" bytes exceeded")
+	}
+	writeBudgetUsed += uint64(n)
+	return nil
+}
+
+// Limits 汇总已登记的索引配额
+//
+// 🎯 **用途**：供合约的限制查询导出函数返回，客户端据此在提交前校验输入
+//
+// **返回**：
+//
+//	{
+//	  "index_quotas": [
+//	    {"index": "claim_evidence", "max_entries_per_caller": 16, "max_entry_bytes": 256}
+//	  ],
+//	  "multicall": {"max_calls": 16, "max_request_bytes": 4096, "max_return_bytes": 8192, "views": ["GetPlanInfo"]}
+//	}
+//
+// multicall 仅在登记了视图函数（RegisterViewFunction）后出现。
+//
+// **注意**：写入预算在各导出函数入口声明，查询调用中不可见，合约应在返回前
+// 自行补充各导出函数的预算（如 "write_budgets" 字段）
+func Limits() map[string]interface{} {
+	quotas := make([]interface{}, 0, len(indexQuotaOrder))
+	for _, name := range indexQuotaOrder {
+		q := indexQuotas[name]
+		quotas = append(quotas, map[string]interface{}{
+			"index":                  q.IndexName,
+			"max_entries_per_caller": q.MaxEntriesPerCaller,
+			"max_entry_bytes":        q.MaxEntryBytes,
+		})
+	}
+	limits := map[string]interface{}{
+		"index_quotas": quotas,
+	}
+	if MulticallEnabled() {
+		limits["multicall"] = multicallLimits()
+	}
+	return limits
+}
+
+// formatUint 十进制格式化（本文件不区分构建环境，避免依赖仅WASM可用的 Uint64ToString）
+func formatUint(n uint64) string {
+	if n == 0 {
+		return "0"
+	}
+	var buf [20]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return string(buf[i:])
+}
@@ -0,0 +1,101 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+func quotaErrCode(err error) uint32 {
+	if ce, ok := err.(*ContractError); ok {
+		return ce.Code
+	}
+	return SUCCESS
+}
+
+// TestWriteBudget 测试写入预算按 stateID 与负载字节累计，超出后拒绝且不暂存
+func TestWriteBudget(t *testing.T) {
+	ResetStagedWrites()
+	defer ResetStagedWrites()
+
+	DeclareWriteBudget(20)
+	if _, err := AppendStateOutputSimple([]byte("k1"), 1, make([]byte, 8), nil); err != nil {
+		t.Fatalf("first write error = %v", err)
+	}
+	if _, err := AppendStateOutputSimple([]byte("k2"), 1, make([]byte, 8), nil); err != nil {
+		t.Fatalf("second write error = %v", err)
+	}
+	if _, used := WriteBudget(); used != 20 {
+		t.Errorf("used = %d, want 20", used)
+	}
+	if _, err := AppendStateOutputSimple([]byte("k3"), 1, []byte{1}, nil); quotaErrCode(err) != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("over-budget write error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	if got := StagedStateWrites(); len(got) != 2 {
+		t.Errorf("staged writes = %v, want 2 entries", got)
+	}
+
+	// 重新声明即开始新的计量；0 表示不限
+	DeclareWriteBudget(0)
+	if _, err := AppendStateOutputSimple([]byte("k3"), 1, make([]byte, 1024), nil); err != nil {
+		t.Errorf("unbounded write error = %v", err)
+	}
+}
+
+// TestWriteBudgetResetAtEntry 测试复用实例的下一次调用（不经过 ResetStagedWrites）在入口清空写入预算，
+// 不沿用上一次调用声明的上限与已计量字节数
+func TestWriteBudgetResetAtEntry(t *testing.T) {
+	ResetStagedWrites()
+	defer ResetStagedWrites()
+	t.Cleanup(InstallMockHost(NewMockHost()))
+
+	// 1. 第一次调用用完预算
+	BeginInvocation()
+	DeclareWriteBudget(10)
+	if _, err := AppendStateOutputSimple([]byte("k1"), 1, make([]byte, 8), nil); err != nil {
+		t.Fatalf("first call write error = %v", err)
+	}
+
+	// 2. 第二次调用未声明预算：不受上一次调用的上限限制
+	BeginInvocation()
+	if limit, used := WriteBudget(); limit != 0 || used != 0 {
+		t.Fatalf("WriteBudget() after entry = %d, %d, want 0, 0", limit, used)
+	}
+	if _, err := AppendStateOutputSimple([]byte("k2"), 1, make([]byte, 64), nil); err != nil {
+		t.Errorf("second call write error = %v", err)
+	}
+
+	// 3. 第三次调用重新声明：从 0 开始计量
+	BeginInvocation()
+	DeclareWriteBudget(10)
+	if _, err := AppendStateOutputSimple([]byte("k3"), 1, make([]byte, 8), nil); err != nil {
+		t.Errorf("third call write error = %v", err)
+	}
+}
+
+// TestCheckIndexQuota 测试索引配额的条目数与单条字节上限
+func TestCheckIndexQuota(t *testing.T) {
+	RegisterIndexQuota("test_index", 2, 4)
+
+	if err := CheckIndexQuota("test_index", 1, 4); err != nil {
+		t.Errorf("within quota error = %v", err)
+	}
+	if err := CheckIndexQuota("test_index", 2, 1); quotaErrCode(err) != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("entry count over quota error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	if err := CheckIndexQuota("test_index", 0, 5); quotaErrCode(err) != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("oversized entry error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	if err := CheckIndexQuota("unregistered_index", 1000, 1<<16); err != nil {
+		t.Errorf("unregistered index error = %v", err)
+	}
+
+	found := false
+	for _, q := range Limits()["index_quotas"].([]interface{}) {
+		m := q.(map[string]interface{})
+		if m["index"] == "test_index" {
+			found = m["max_entries_per_caller"] == uint32(2) && m["max_entry_bytes"] == uint32(4)
+		}
+	}
+	if !found {
+		t.Errorf("Limits() = %v, want test_index quota listed", Limits())
+	}
+}
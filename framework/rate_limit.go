@@ -0,0 +1,128 @@
+package framework
+
+import "math/bits"
+
+// 按键限流
+//
+// 铸造、领取、提交申请等入口需要防刷：同一地址（或同一地址 + 操作）在一段时间内最多执行若干次。
+// RateLimit 使用滑动窗口计数：时间按 windowSecs 对齐分段，记录当前段与上一段的次数，
+// 估算最近 windowSecs 秒内的次数为
+//
+//	上一段次数 × 上一段仍在滑动窗口内的比例 + 当前段次数
+//
+// 与固定窗口相比，不会在窗口边界前后各用满一次额度（短时间内达到 2 倍上限）；
+// 与逐条记录时间戳相比，状态大小固定，不随上限增长。
+//
+// 状态布局：
+//   - ratelimit:{key} → windowStart(8) + current(8) + previous(8)
+
+// RATE_LIMIT_STATE_PREFIX 限流计数的状态ID前缀
+const RATE_LIMIT_STATE_PREFIX = "ratelimit:"
+
+// rateLimitWindow 一个键的限流计数
+type rateLimitWindow struct {
+	// start 当前段的起始时间（windowSecs 的整数倍）
+	start uint64
+	// current 当前段内的次数
+	current uint64
+	// previous 上一段内的次数
+	previous uint64
+}
+
+// RateLimit 记录一次 key 的调用，超过频率限制时返回 ERROR_RATE_LIMITED
+//
+// 🎯 **用途**：铸造、领取、提交等入口的防刷限流
+//
+// **参数**：
+//   - key: 限流键，通常为 操作 + 地址（如 "mint:" + caller.ToString()），不能为空
+//   - maxPerWindow: 任意 windowSecs 秒的滑动窗口内允许的最多次数，不能为 0
+//   - windowSecs: 窗口长度（秒），不能为 0
+//
+// **返回**：
+//   - ERROR_RATE_LIMITED: 本次调用会超过限制，不计数也不写入状态
+//   - ERROR_INVALID_PARAMS: 参数无效
+//
+// **注意**：
+//   - 同一 key 应始终使用相同的 windowSecs，更换窗口长度后旧计数按新窗口对齐，可能提前或推迟恢复
+//   - 只有调用成功提交时计数才生效；合约在 RateLimit 之后返回错误码时本次调用不计入
+//
+// **示例**：
+//
+//	caller := framework.GetCaller()
+//	if err := framework.RateLimit("claim:"+caller.ToString(), 3, 24*3600); err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func RateLimit(key string, maxPerWindow uint64, windowSecs uint64) error {
+	if key == "" || maxPerWindow == 0 || windowSecs == 0 {
+		return NewContractError(ERROR_INVALID_PARAMS, "invalid rate limit parameters")
+	}
+	stateID := []byte(RATE_LIMIT_STATE_PREFIX + key)
+	w, version := loadRateLimit(stateID)
+
+	next, ok := w.record(GetTimestamp(), maxPerWindow, windowSecs)
+	if !ok {
+		return NewContractError(ERROR_RATE_LIMITED, "rate limit exceeded for "+key)
+	}
+	_, err := AppendStateOutputSimple(stateID, version+1, next.encode(), nil)
+	return err
+}
+
+// advance 将计数推进到 now 所在的段：跨过一段时上一段次数为原当前段次数，跨过两段及以上时清零
+func (w rateLimitWindow) advance(now, windowSecs uint64) rateLimitWindow {
+	start := now - now%windowSecs
+	switch {
+	case start == w.start:
+		return w
+	case start-w.start == windowSecs:
+		return rateLimitWindow{start: start, previous: w.current}
+	default:
+		return rateLimitWindow{start: start}
+	}
+}
+
+// estimate 估算 now 之前 windowSecs 秒内的次数（上一段按重叠比例折算，向上取整）
+func (w rateLimitWindow) estimate(now, windowSecs uint64) uint64 {
+	remaining := windowSecs - (now - w.start)
+	hi, lo := bits.Mul64(w.previous, remaining)
+	q, r := bits.Div64(hi, lo, windowSecs)
+	if r > 0 {
+		q++
+	}
+	return q + w.current
+}
+
+// record 计入一次调用；超过 maxPerWindow 时返回 false，计数不变
+//
+// now 早于已记录的段起点时（不应出现的时间回退）按段起点计算，不清空已有计数
+func (w rateLimitWindow) record(now, maxPerWindow, windowSecs uint64) (rateLimitWindow, bool) {
+	if now < w.start {
+		now = w.start
+	}
+	w = w.advance(now, windowSecs)
+	if w.estimate(now, windowSecs) >= maxPerWindow {
+		return w, false
+	}
+	w.current++
+	return w, true
+}
+
+// encode 编码为 24 字节
+func (w rateLimitWindow) encode() []byte {
+	buf := make([]byte, 0, 24)
+	buf = appendBEUint64(buf, w.start)
+	buf = appendBEUint64(buf, w.current)
+	return appendBEUint64(buf, w.previous)
+}
+
+// loadRateLimit 读取限流计数及状态版本，不存在时版本为 0
+//
+// 链上读取会去除尾部零字节，不足 24 字节时补齐后解析
+func loadRateLimit(stateID []byte) (rateLimitWindow, uint64) {
+	data, version, err := GetStateFromChain(stateID)
+	if err != nil || version == 0 {
+		return rateLimitWindow{}, 0
+	}
+	buf := make([]byte, 24)
+	copy(buf, data)
+	return rateLimitWindow{start: beUint64(buf[0:8]), current: beUint64(buf[8:16]), previous: beUint64(buf[16:24])}, version
+}
@@ -0,0 +1,95 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"testing"
+)
+
+// rateLimitInvoke 在 host 当前时间调用一次 RateLimit，返回结果码
+func rateLimitInvoke(host *MockHost, key string, max, window uint64) uint32 {
+	return roleInvoke(host, Address{0x0a}, func() error { return RateLimit(key, max, window) }).Code
+}
+
+// TestRateLimitWithinWindow 测试同一窗口内超过上限被拒绝、被拒绝的调用不计数、不同键互不影响
+func TestRateLimitWithinWindow(t *testing.T) {
+	host := installRoleHost(t) // Timestamp = 1000，窗口 [1000, 1100)
+
+	for i := 0; i < 3; i++ {
+		if code := rateLimitInvoke(host, "mint:a", 3, 100); code != SUCCESS {
+			t.Fatalf("call %d code = %d, want SUCCESS", i+1, code)
+		}
+		host.Timestamp += 10
+	}
+	for i := 0; i < 2; i++ {
+		if code := rateLimitInvoke(host, "mint:a", 3, 100); code != ERROR_RATE_LIMITED {
+			t.Fatalf("over-limit call code = %d, want ERROR_RATE_LIMITED", code)
+		}
+	}
+	if code := rateLimitInvoke(host, "mint:b", 3, 100); code != SUCCESS {
+		t.Fatalf("other key code = %d, want SUCCESS", code)
+	}
+
+	w, _ := loadRateLimit([]byte(RATE_LIMIT_STATE_PREFIX + "mint:a"))
+	if w != (rateLimitWindow{start: 1000, current: 3}) {
+		t.Fatalf("stored window = %+v, rejected calls must not be counted", w)
+	}
+}
+
+// TestRateLimitSlidingAndReset 测试跨入下一窗口时上一窗口按比例计入，两个窗口后完全重置
+func TestRateLimitSlidingAndReset(t *testing.T) {
+	host := installRoleHost(t)
+
+	for i := 0; i < 4; i++ {
+		if code := rateLimitInvoke(host, "claim", 4, 100); code != SUCCESS {
+			t.Fatalf("call %d code = %d, want SUCCESS", i+1, code)
+		}
+	}
+
+	// 1. 刚跨过窗口边界：上一窗口 4 次 × 90% → 4，仍然受限
+	host.Timestamp = 1110
+	if code := rateLimitInvoke(host, "claim", 4, 100); code != ERROR_RATE_LIMITED {
+		t.Fatalf("just after boundary code = %d, want ERROR_RATE_LIMITED", code)
+	}
+
+	// 2. 窗口过半：4 × 50% = 2，还可以调用 2 次
+	host.Timestamp = 1150
+	for i := 0; i < 2; i++ {
+		if code := rateLimitInvoke(host, "claim", 4, 100); code != SUCCESS {
+			t.Fatalf("half window call %d code = %d, want SUCCESS", i+1, code)
+		}
+	}
+	if code := rateLimitInvoke(host, "claim", 4, 100); code != ERROR_RATE_LIMITED {
+		t.Fatalf("half window third call code = %d, want ERROR_RATE_LIMITED", code)
+	}
+
+	// 3. 跳过一个完整窗口后计数清零，可以用满上限
+	host.Timestamp = 1350
+	for i := 0; i < 4; i++ {
+		if code := rateLimitInvoke(host, "claim", 4, 100); code != SUCCESS {
+			t.Fatalf("after reset call %d code = %d, want SUCCESS", i+1, code)
+		}
+	}
+	if code := rateLimitInvoke(host, "claim", 4, 100); code != ERROR_RATE_LIMITED {
+		t.Fatalf("after reset over-limit code = %d, want ERROR_RATE_LIMITED", code)
+	}
+}
+
+// TestRateLimitInvalidParams 测试空键、零上限、零窗口被拒绝
+func TestRateLimitInvalidParams(t *testing.T) {
+	host := installRoleHost(t)
+
+	cases := []struct {
+		key         string
+		max, window uint64
+	}{
+		{"", 1, 100},
+		{"k", 0, 100},
+		{"k", 1, 0},
+	}
+	for _, c := range cases {
+		if code := rateLimitInvoke(host, c.key, c.max, c.window); code != ERROR_INVALID_PARAMS {
+			t.Errorf("RateLimit(%q, %d, %d) code = %d, want ERROR_INVALID_PARAMS", c.key, c.max, c.window, code)
+		}
+	}
+}
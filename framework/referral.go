@@ -0,0 +1,183 @@
+package framework
+
+// 推荐关系与推荐奖励
+//
+// 增长类功能在被推荐用户完成铸造、缴费、交易等行为时向推荐人发放奖励，本文件提供：
+//   - SetReferrer：绑定用户的推荐人，每个用户只能绑定一次，绑定后不可修改
+//   - GetReferrer：查询用户的推荐人
+//   - PayReferralReward：按万分比计算奖励，并通过调用方提供的 ReferralPayout 发放给推荐人
+//
+// 奖励的发放方式由合约决定（token.Mint 增发、从合约地址转账、子账户记账等），本文件只负责
+// 推荐关系的存储、奖励金额的计算与 ReferralRewardPaid 事件。
+//
+// 状态布局：
+//   - 推荐关系：referral:{user} → referrer(20) + boundAt(8)
+
+// REFERRAL_STATE_PREFIX 推荐关系的状态ID前缀
+const REFERRAL_STATE_PREFIX = "referral:"
+
+// 推荐相关事件名
+const (
+	// EVENT_REFERRER_SET 绑定推荐人（user / referrer / bound_at）
+	EVENT_REFERRER_SET = "ReferrerSet"
+	// EVENT_REFERRAL_REWARD_PAID 发放推荐奖励（user / referrer / token_id / base_amount / reward_bp / reward）
+	EVENT_REFERRAL_REWARD_PAID = "ReferralRewardPaid"
+)
+
+// Referral 用户的推荐关系
+type Referral struct {
+	User     Address
+	Referrer Address
+	// BoundAt 绑定时间（区块时间戳）
+	BoundAt uint64
+}
+
+// ReferralPayout 向推荐人发放奖励的方式
+//
+// 🎯 **用途**：由合约提供奖励的实际发放逻辑，例如：
+//
+//	func(referrer framework.Address, tokenID framework.TokenID, reward framework.Amount) error {
+//	    return token.Mint(referrer, tokenID, reward)
+//	}
+//
+// **约定**：返回错误时 PayReferralReward 原样返回该错误，不发出 ReferralRewardPaid 事件
+type ReferralPayout func(referrer Address, tokenID TokenID, reward Amount) error
+
+// SetReferrer 绑定用户的推荐人
+//
+// 🎯 **用途**：用户注册、首次缴费等入口记录推荐关系，每个用户只能绑定一次
+//
+// **参数**：
+//   - user: 被推荐用户
+//   - referrer: 推荐人，不能为零地址或 user 本身
+//
+// **返回**：
+//   - ERROR_INVALID_PARAMS: 地址为零、自己推荐自己，或 referrer 的推荐人正是 user（互相推荐）
+//   - ERROR_ALREADY_EXISTS: user 已绑定推荐人（推荐关系不可修改）
+//
+// **注意**：
+//   - 本函数不校验调用者，合约应自行决定谁可以绑定（通常要求 user == GetCaller()）
+//   - 成功时发出 ReferrerSet 事件
+//
+// **示例**：
+//
+//	caller := framework.GetCaller()
+//	if err := framework.SetReferrer(caller, referrer); err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func SetReferrer(user, referrer Address) error {
+	if user.IsZero() || referrer.IsZero() || user == referrer {
+		return NewContractError(ERROR_INVALID_PARAMS, "invalid referral addresses")
+	}
+	if _, version := loadReferral(user); version > 0 {
+		return NewContractError(ERROR_ALREADY_EXISTS, "referrer already set")
+	}
+	if upstream, ok := GetReferrer(referrer); ok && upstream == user {
+		return NewContractError(ERROR_INVALID_PARAMS, "mutual referral is not allowed")
+	}
+
+	now := GetTimestamp()
+	value := appendBEUint64(append(make([]byte, 0, 28), referrer[:]...), now)
+	if _, err := AppendStateOutputSimple(referralStateID(user), 1, value, nil); err != nil {
+		return err
+	}
+
+	event := NewEvent(EVENT_REFERRER_SET)
+	event.AddAddressField("user", user)
+	event.AddAddressField("referrer", referrer)
+	event.AddUint64Field("bound_at", now)
+	EmitEvent(event)
+	return nil
+}
+
+// GetReferrer 查询用户的推荐人，未绑定时返回 false
+func GetReferrer(user Address) (Address, bool) {
+	r, version := loadReferral(user)
+	return r.Referrer, version > 0
+}
+
+// GetReferral 查询用户的推荐关系（含绑定时间），未绑定时返回 false
+func GetReferral(user Address) (Referral, bool) {
+	r, version := loadReferral(user)
+	return r, version > 0
+}
+
+// ReferralReward 计算推荐奖励：baseAmount * rewardBP / 10000（向下取整）
+//
+// **返回**：rewardBP 超过 MAX_BASIS_POINTS 时返回 ERROR_INVALID_PARAMS
+func ReferralReward(baseAmount Amount, rewardBP BasisPoints) (Amount, error) {
+	if rewardBP > MAX_BASIS_POINTS {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "referral reward bp exceeds 10000")
+	}
+	return baseAmount.ApplyBP(rewardBP), nil
+}
+
+// PayReferralReward 按万分比向用户的推荐人发放奖励
+//
+// 🎯 **用途**：铸造、缴费等流程的推荐奖励钩子，被推荐用户每次行为后调用一次
+//
+// **参数**：
+//   - user: 完成行为的被推荐用户
+//   - tokenID: 奖励代币
+//   - baseAmount: 计算奖励的基数（如铸造数量、缴费金额）
+//   - rewardBP: 奖励比例（万分比），不超过 MAX_BASIS_POINTS
+//   - payout: 奖励发放方式
+//
+// **返回**：
+//   - referrer: 获得奖励的推荐人
+//   - reward: 奖励金额；用户未绑定推荐人或奖励向下取整为 0 时为 0，且不调用 payout、不发出事件
+//   - error: 参数无效（ERROR_INVALID_PARAMS），或 payout 返回的错误
+//
+// **示例**：
+//
+//	if err := token.Mint(to, tokenID, amount); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+//	_, _, err := framework.PayReferralReward(to, tokenID, amount, 500, func(r framework.Address, t framework.TokenID, reward framework.Amount) error {
+//	    return token.Mint(r, t, reward)
+//	})
+func PayReferralReward(user Address, tokenID TokenID, baseAmount Amount, rewardBP BasisPoints, payout ReferralPayout) (referrer Address, reward Amount, err error) {
+	if payout == nil {
+		return Address{}, 0, NewContractError(ERROR_INVALID_PARAMS, "referral payout is not configured")
+	}
+	if reward, err = ReferralReward(baseAmount, rewardBP); err != nil {
+		return Address{}, 0, err
+	}
+	referrer, ok := GetReferrer(user)
+	if !ok || reward == 0 {
+		return Address{}, 0, nil
+	}
+	if err := payout(referrer, tokenID, reward); err != nil {
+		return Address{}, 0, err
+	}
+
+	event := NewEvent(EVENT_REFERRAL_REWARD_PAID)
+	event.AddAddressField("user", user)
+	event.AddAddressField("referrer", referrer)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("base_amount", uint64(baseAmount))
+	event.AddUint64Field("reward_bp", uint64(rewardBP))
+	event.AddUint64Field("reward", uint64(reward))
+	EmitEvent(event)
+	return referrer, reward, nil
+}
+
+// loadReferral 读取推荐关系及状态版本，不存在时版本为 0
+//
+// 链上读取会去除尾部零字节，不足 28 字节时补齐后解析
+func loadReferral(user Address) (Referral, uint64) {
+	data, version, err := GetStateFromChain(referralStateID(user))
+	if err != nil || version == 0 {
+		return Referral{}, 0
+	}
+	buf := make([]byte, 28)
+	copy(buf, data)
+	r := Referral{User: user, BoundAt: beUint64(buf[20:28])}
+	copy(r.Referrer[:], buf[:20])
+	return r, version
+}
+
+// referralStateID 返回用户推荐关系的状态ID
+func referralStateID(user Address) []byte {
+	return []byte(REFERRAL_STATE_PREFIX + user.ToString())
+}
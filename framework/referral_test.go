@@ -0,0 +1,132 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"testing"
+)
+
+var (
+	referralUser     = Address{0x01, 0x11}
+	referralReferrer = Address{0x02, 0x22}
+	referralOther    = Address{0x03, 0x33}
+)
+
+// TestSetReferrerOnce 测试推荐人只能绑定一次，失败的绑定不改变已有关系
+func TestSetReferrerOnce(t *testing.T) {
+	host := installRoleHost(t)
+
+	if _, ok := GetReferrer(referralUser); ok {
+		t.Fatal("GetReferrer() before binding = true")
+	}
+	res := roleInvoke(host, referralUser, func() error { return SetReferrer(referralUser, referralReferrer) })
+	if res.Code != SUCCESS {
+		t.Fatalf("SetReferrer() = %d", res.Code)
+	}
+	if len(res.Events) != 1 || res.Events[0].Name != EVENT_REFERRER_SET {
+		t.Errorf("SetReferrer() events = %+v", res.Events)
+	}
+
+	host.Timestamp = 2000
+	if res := roleInvoke(host, referralUser, func() error { return SetReferrer(referralUser, referralOther) }); res.Code != ERROR_ALREADY_EXISTS {
+		t.Errorf("second SetReferrer() = %d, want ERROR_ALREADY_EXISTS", res.Code)
+	}
+	r, ok := GetReferral(referralUser)
+	if !ok || r.Referrer != referralReferrer || r.User != referralUser || r.BoundAt != 1000 {
+		t.Errorf("GetReferral() = %+v, %v", r, ok)
+	}
+	if referrer, ok := GetReferrer(referralUser); !ok || referrer != referralReferrer {
+		t.Errorf("GetReferrer() = %x, %v", referrer, ok)
+	}
+}
+
+// TestSetReferrerInvalid 测试零地址、自己推荐自己与互相推荐被拒绝
+func TestSetReferrerInvalid(t *testing.T) {
+	host := installRoleHost(t)
+	cases := []struct {
+		name           string
+		user, referrer Address
+	}{
+		{"zero user", Address{}, referralReferrer},
+		{"zero referrer", referralUser, Address{}},
+		{"self", referralUser, referralUser},
+	}
+	for _, tt := range cases {
+		if res := roleInvoke(host, tt.user, func() error { return SetReferrer(tt.user, tt.referrer) }); res.Code != ERROR_INVALID_PARAMS {
+			t.Errorf("%s: SetReferrer() = %d, want ERROR_INVALID_PARAMS", tt.name, res.Code)
+		}
+	}
+
+	if res := roleInvoke(host, referralUser, func() error { return SetReferrer(referralUser, referralReferrer) }); res.Code != SUCCESS {
+		t.Fatalf("SetReferrer() = %d", res.Code)
+	}
+	if res := roleInvoke(host, referralReferrer, func() error { return SetReferrer(referralReferrer, referralUser) }); res.Code != ERROR_INVALID_PARAMS {
+		t.Errorf("mutual SetReferrer() = %d, want ERROR_INVALID_PARAMS", res.Code)
+	}
+	if _, ok := GetReferrer(referralReferrer); ok {
+		t.Error("rejected referral was stored")
+	}
+}
+
+// TestPayReferralReward 测试按万分比向推荐人发放奖励，无推荐人或奖励为 0 时不调用发放函数
+func TestPayReferralReward(t *testing.T) {
+	host := installRoleHost(t)
+	if res := roleInvoke(host, referralUser, func() error { return SetReferrer(referralUser, referralReferrer) }); res.Code != SUCCESS {
+		t.Fatalf("SetReferrer() = %d", res.Code)
+	}
+
+	var paid []Amount
+	payout := func(referrer Address, tokenID TokenID, reward Amount) error {
+		if referrer != referralReferrer || tokenID != "USDT" {
+			t.Errorf("payout(%x, %s)", referrer, tokenID)
+		}
+		paid = append(paid, reward)
+		return nil
+	}
+
+	var referrer Address
+	var reward Amount
+	res := roleInvoke(host, referralUser, func() (err error) {
+		referrer, reward, err = PayReferralReward(referralUser, "USDT", 10_001, 500, payout)
+		return err
+	})
+	if res.Code != SUCCESS || referrer != referralReferrer || reward != 500 || len(paid) != 1 || paid[0] != 500 {
+		t.Fatalf("PayReferralReward() = %x, %d, code %d, paid %v", referrer, reward, res.Code, paid)
+	}
+	if len(res.Events) != 1 || res.Events[0].Name != EVENT_REFERRAL_REWARD_PAID || res.Events[0].Data["reward"] != uint64(500) {
+		t.Errorf("PayReferralReward() events = %+v", res.Events)
+	}
+
+	// 无推荐人、奖励取整为 0：不发放、不发出事件
+	for _, tc := range []struct {
+		user Address
+		base Amount
+	}{{referralOther, 10_000}, {referralUser, 19}} {
+		res := roleInvoke(host, tc.user, func() (err error) {
+			referrer, reward, err = PayReferralReward(tc.user, "USDT", tc.base, 500, payout)
+			return err
+		})
+		if res.Code != SUCCESS || reward != 0 || !referrer.IsZero() || len(res.Events) != 0 {
+			t.Errorf("PayReferralReward(%x, %d) = %x, %d, events %d", tc.user, tc.base, referrer, reward, len(res.Events))
+		}
+	}
+	if len(paid) != 1 {
+		t.Errorf("payout called %d times, want 1", len(paid))
+	}
+
+	if _, _, err := PayReferralReward(referralUser, "USDT", 100, MAX_BASIS_POINTS+1, payout); stateQueryCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("bp over 10000 error = %v", err)
+	}
+	if _, _, err := PayReferralReward(referralUser, "USDT", 100, 500, nil); stateQueryCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("nil payout error = %v", err)
+	}
+	failing := func(Address, TokenID, Amount) error {
+		return NewContractError(ERROR_INSUFFICIENT_BALANCE, "empty pool")
+	}
+	if res := roleInvoke(host, referralUser, func() error {
+		_, _, err := PayReferralReward(referralUser, "USDT", 10_000, 500, failing)
+		return err
+	}); res.Code != ERROR_INSUFFICIENT_BALANCE || len(res.Events) != 0 {
+		t.Errorf("failing payout = %d, events %d", res.Code, len(res.Events))
+	}
+}
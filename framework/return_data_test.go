@@ -0,0 +1,88 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// testQueryExport 示例查询导出函数：按参数返回 JSON
+func testQueryExport() uint32 {
+	params := GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if planID == "" {
+		return ERROR_INVALID_PARAMS
+	}
+	result := map[string]interface{}{
+		"plan_id": planID,
+		"amount":  uint64(1500),
+		"active":  true,
+		"caller":  GetCaller().ToString(),
+	}
+	if err := SetReturnJSON(result); err != nil {
+		return ERROR_EXECUTION_FAILED
+	}
+	return SUCCESS
+}
+
+// TestGetLastReturnDataParsesExportJSON 测试导出函数的 JSON 输出可以通过 GetLastReturnData 读取并解析
+func TestGetLastReturnDataParsesExportJSON(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	caller := Address{0x42}
+
+	res := host.Invoke(caller, []byte(`{"plan_id":"plan_001"}`), testQueryExport)
+	if res.Code != SUCCESS {
+		t.Fatalf("Invoke() code = %d", res.Code)
+	}
+
+	data := GetLastReturnData()
+	if string(data) != string(res.Return) {
+		t.Errorf("GetLastReturnData() = %s, want MockCallResult.Return %s", data, res.Return)
+	}
+	var got struct {
+		PlanID string `json:"plan_id"`
+		Amount uint64 `json:"amount"`
+		Active bool   `json:"active"`
+		Caller string `json:"caller"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("return data %s is not valid JSON: %v", data, err)
+	}
+	if got.PlanID != "plan_001" || got.Amount != 1500 || !got.Active || got.Caller != caller.ToString() {
+		t.Errorf("return data = %+v", got)
+	}
+
+	// 返回副本：修改不影响之后的读取
+	data[0] = 'x'
+	if again := GetLastReturnData(); again[0] != '{' {
+		t.Errorf("GetLastReturnData() shares its buffer: %s", again)
+	}
+}
+
+// TestGetLastReturnDataLifecycle 测试每次 Invoke 清空返回数据、直接调用时同样记录、未安装宿主时返回 nil
+func TestGetLastReturnDataLifecycle(t *testing.T) {
+	if data := GetLastReturnData(); data != nil {
+		t.Errorf("GetLastReturnData() without MockHost = %s, want nil", data)
+	}
+
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	host.Invoke(Address{0x42}, []byte(`{"plan_id":"plan_001"}`), testQueryExport)
+	if res := host.Invoke(Address{0x42}, nil, testQueryExport); res.Code != ERROR_INVALID_PARAMS {
+		t.Fatalf("Invoke() without plan_id code = %d, want ERROR_INVALID_PARAMS", res.Code)
+	}
+	if data := GetLastReturnData(); data != nil {
+		t.Errorf("GetLastReturnData() after call without return = %s, want nil", data)
+	}
+
+	// 不经 Invoke 直接调用导出函数
+	if err := SetReturnString("direct"); err != nil {
+		t.Fatalf("SetReturnString() error = %v", err)
+	}
+	if data := GetLastReturnData(); string(data) != "direct" {
+		t.Errorf("GetLastReturnData() after direct call = %q, want direct", data)
+	}
+}
@@ -0,0 +1,410 @@
+package framework
+
+// 长期运营角色的密钥轮换
+//
+// 运营方、守护者、合规官、手续费接收方等角色长期由单一地址持有，本文件为这些角色提供统一的轮换协议：
+//   - RotateRoleKey：当前持有者发起轮换，指定新地址，进入待接受状态
+//   - AcceptRoleKey：新地址在接受期限内确认，完成轮换（握手确认新密钥可用）
+//   - EmergencyRotate：更高一级的管理角色直接替换持有者，跳过确认，并撤销待接受的轮换
+//
+// 待接受期间新旧两个地址同时有效（HasRole 均返回 true），运营不中断；新地址接受后旧地址失效，
+// 超过期限未接受时待接受的轮换失效，仅旧地址有效。
+//
+// 状态布局：
+//   - 持有者：    RoleConfig.StateID（默认 role:{role}）→ 20字节地址
+//   - 待接受轮换：role_pending:{role} → active(1) + from(20) + to(20) + startedAt(8) + deadline(8)
+//   - 审计索引：  index:role_key_audit:{role}:{seq}（见 AppendIndexEntry），每次完成的轮换一条
+
+// ROLE_AUDIT_INDEX 角色轮换审计索引名称
+const ROLE_AUDIT_INDEX = "role_key_audit"
+
+// DEFAULT_ROLE_ACCEPT_WINDOW 默认接受期限（秒）
+const DEFAULT_ROLE_ACCEPT_WINDOW = 7 * 24 * 3600
+
+// 轮换方式（审计条目与 RoleKeyRotated 事件的 mode 字段）
+const (
+	// ROLE_ROTATION_ACCEPTED 新地址确认后完成的轮换
+	ROLE_ROTATION_ACCEPTED = "accepted"
+	// ROLE_ROTATION_EMERGENCY 管理角色发起的紧急轮换
+	ROLE_ROTATION_EMERGENCY = "emergency"
+)
+
+// RoleConfig 可轮换角色的配置
+type RoleConfig struct {
+	// Role 角色名称
+	Role string
+	// StateID 持有者地址的状态ID，默认 role:{Role}；已有合约沿用原状态ID（如 "operator"），无需迁移
+	StateID string
+	// AcceptWindow 新地址的接受期限（秒），0 表示 DEFAULT_ROLE_ACCEPT_WINDOW
+	AcceptWindow uint64
+	// AdminRole 可执行 EmergencyRotate 的管理角色，为空时不支持紧急轮换
+	AdminRole string
+}
+
+// RoleRotation 待接受的轮换
+type RoleRotation struct {
+	Role      string
+	From      Address
+	To        Address
+	StartedAt uint64
+	// Deadline 接受期限（含），之后轮换失效
+	Deadline uint64
+}
+
+// RoleRotationRecord 审计索引中的一次已完成轮换
+type RoleRotationRecord struct {
+	Mode       string
+	OldAddress Address
+	NewAddress Address
+	// Caller 完成轮换的调用者（accepted 为新地址，emergency 为管理角色持有者）
+	Caller    Address
+	Timestamp uint64
+}
+
+var roleConfigs = map[string]RoleConfig{}
+
+// RegisterRole 登记可轮换的角色
+//
+// 🎯 **用途**：声明角色的状态ID、接受期限与紧急轮换的管理角色，通常在合约包的 init 中调用
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterRole(framework.RoleConfig{Role: "operator", StateID: "operator", AdminRole: "operator_admin"})
+//	    framework.RegisterRole(framework.RoleConfig{Role: "operator_admin"})
+//	}
+func RegisterRole(cfg RoleConfig) {
+	if cfg.StateID == "" {
+		cfg.StateID = "role:" + cfg.Role
+	}
+	if cfg.AcceptWindow == 0 {
+		cfg.AcceptWindow = DEFAULT_ROLE_ACCEPT_WINDOW
+	}
+	roleConfigs[cfg.Role] = cfg
+}
+
+// GetRoleConfig 查询角色配置，未登记时返回 false
+func GetRoleConfig(role string) (RoleConfig, bool) {
+	cfg, ok := roleConfigs[role]
+	return cfg, ok
+}
+
+// InitRole 设置角色的初始持有者（通常在 Initialize 中调用）
+//
+// **返回**：角色未登记返回 ERROR_NOT_FOUND；已有持有者返回 ERROR_ALREADY_EXISTS
+func InitRole(role string, holder Address) error {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return err
+	}
+	if holder == (Address{}) {
+		return NewContractError(ERROR_INVALID_PARAMS, "role holder cannot be zero")
+	}
+	if _, version := loadRoleHolder(cfg); version > 0 {
+		return NewContractError(ERROR_ALREADY_EXISTS, "role already has a holder")
+	}
+	_, err = AppendStateOutputSimple([]byte(cfg.StateID), 1, holder.ToBytes(), nil)
+	return err
+}
+
+// RoleHolder 查询角色的当前持有者
+//
+// **返回**：角色未登记或尚无持有者时返回 ERROR_NOT_FOUND
+func RoleHolder(role string) (Address, error) {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return Address{}, err
+	}
+	holder, version := loadRoleHolder(cfg)
+	if version == 0 {
+		return Address{}, NewContractError(ERROR_NOT_FOUND, "role has no holder")
+	}
+	return holder, nil
+}
+
+// HasRole 地址当前是否持有角色
+//
+// **双重有效期**：轮换待接受期间（RotateRoleKey 之后、AcceptRoleKey 或期限到达之前），
+// 当前持有者与待接受的新地址都返回 true，两个密钥都可以执行该角色的操作
+func HasRole(role string, addr Address) bool {
+	if addr.IsZero() {
+		return false
+	}
+	holder, err := RoleHolder(role)
+	if err != nil {
+		return false
+	}
+	if holder.Equals(addr) {
+		return true
+	}
+	pending, ok := PendingRoleRotation(role)
+	return ok && pending.To.Equals(addr)
+}
+
+// PendingRoleRotation 查询角色待接受的轮换，不存在或已过期时返回 false
+func PendingRoleRotation(role string) (RoleRotation, bool) {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return RoleRotation{}, false
+	}
+	pending, _ := loadRolePending(role)
+	if pending == nil || GetTimestamp() > pending.Deadline {
+		return RoleRotation{}, false
+	}
+	if holder, _ := loadRoleHolder(cfg); holder != pending.From {
+		return RoleRotation{}, false
+	}
+	return *pending, true
+}
+
+// RotateRoleKey 当前持有者发起角色密钥轮换
+//
+// 🎯 **用途**：替代各合约各自实现的转移函数；新地址须在接受期限内调用 AcceptRoleKey 完成轮换，
+// 期间新旧地址同时有效（见 HasRole）
+//
+// **参数**：
+//   - role: 已登记的角色
+//   - newAddress: 新持有者地址
+//
+// **返回**：
+//   - error: 角色未登记（ERROR_NOT_FOUND）、调用者不是当前持有者（ERROR_UNAUTHORIZED）、
+//     新地址无效（ERROR_INVALID_PARAMS）
+//
+// **注意**：
+//   - 只有当前持有者可以发起，待接受的新地址不能再次发起轮换
+//   - 已有待接受的轮换时以新的轮换替换
+//   - 发出 RoleKeyRotationStarted 事件
+func RotateRoleKey(role string, newAddress Address) error {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return err
+	}
+	holder, version := loadRoleHolder(cfg)
+	caller := GetCaller()
+	if version == 0 || caller != holder {
+		return NewContractError(ERROR_UNAUTHORIZED, "only the current role holder can rotate")
+	}
+	if newAddress == (Address{}) || newAddress == holder {
+		return NewContractError(ERROR_INVALID_PARAMS, "invalid new role address")
+	}
+
+	now := GetTimestamp()
+	pending := RoleRotation{Role: role, From: holder, To: newAddress, StartedAt: now, Deadline: now + cfg.AcceptWindow}
+	if pending.Deadline < now {
+		pending.Deadline = ^uint64(0)
+	}
+	if err := saveRolePending(role, &pending); err != nil {
+		return err
+	}
+
+	event := NewEvent("RoleKeyRotationStarted")
+	event.AddStringField("role", role)
+	event.AddAddressField("old_address", holder)
+	event.AddAddressField("new_address", newAddress)
+	event.AddUint64Field("deadline", pending.Deadline)
+	EmitEvent(event)
+	return nil
+}
+
+// AcceptRoleKey 新地址确认接受角色，完成轮换
+//
+// **返回**：
+//   - error: 没有待接受的轮换（ERROR_NOT_FOUND）、已过接受期限（ERROR_TIMEOUT）、
+//     调用者不是待接受的新地址（ERROR_UNAUTHORIZED）
+//
+// **注意**：旧地址随即失效；写入审计索引并发出 RoleKeyRotated 事件（mode=accepted）
+func AcceptRoleKey(role string) error {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return err
+	}
+	pending, _ := loadRolePending(role)
+	holder, version := loadRoleHolder(cfg)
+	if pending == nil || pending.From != holder {
+		return NewContractError(ERROR_NOT_FOUND, "no pending role rotation")
+	}
+	if GetTimestamp() > pending.Deadline {
+		return NewContractError(ERROR_TIMEOUT, "role rotation acceptance window expired")
+	}
+	caller := GetCaller()
+	if caller != pending.To {
+		return NewContractError(ERROR_UNAUTHORIZED, "only the pending role address can accept")
+	}
+
+	return completeRoleRotation(cfg, version, holder, pending.To, ROLE_ROTATION_ACCEPTED, nil)
+}
+
+// EmergencyRotate 管理角色直接替换角色持有者
+//
+// 🎯 **用途**：当前持有者的密钥泄露时，跳过新地址确认立即替换，并撤销待接受的轮换
+// （泄露的密钥可能已发起指向攻击者地址的轮换）
+//
+// **参数**：
+//   - role: 已登记且配置了 AdminRole 的角色
+//   - newAddress: 新持有者地址
+//
+// **返回**：
+//   - error: 角色未配置管理角色或调用者不持有管理角色（ERROR_UNAUTHORIZED）、新地址无效（ERROR_INVALID_PARAMS）
+//
+// **注意**：写入审计索引并发出 RoleKeyRotated 事件（mode=emergency，revoked_pending 为被撤销的待接受地址）
+func EmergencyRotate(role string, newAddress Address) error {
+	cfg, err := lookupRole(role)
+	if err != nil {
+		return err
+	}
+	if cfg.AdminRole == "" || !HasRole(cfg.AdminRole, GetCaller()) {
+		return NewContractError(ERROR_UNAUTHORIZED, "only the admin role can emergency rotate")
+	}
+	holder, version := loadRoleHolder(cfg)
+	if newAddress == (Address{}) || newAddress == holder {
+		return NewContractError(ERROR_INVALID_PARAMS, "invalid new role address")
+	}
+
+	revoked, _ := PendingRoleRotation(role)
+	return completeRoleRotation(cfg, version, holder, newAddress, ROLE_ROTATION_EMERGENCY, &revoked)
+}
+
+// RoleAuditCount 返回角色审计索引中的轮换记录数
+func RoleAuditCount(role string) uint64 {
+	return IndexEntryCount(ROLE_AUDIT_INDEX, []byte(role))
+}
+
+// GetRoleAuditEntry 读取角色审计索引中指定序号的轮换记录
+func GetRoleAuditEntry(role string, seq uint64) (RoleRotationRecord, error) {
+	entry, err := GetIndexEntry(ROLE_AUDIT_INDEX, []byte(role), seq)
+	if err != nil {
+		return RoleRotationRecord{}, err
+	}
+	return decodeRoleRotationRecord(entry)
+}
+
+// completeRoleRotation 写入新持有者、清除待接受的轮换、追加审计记录并发出事件
+func completeRoleRotation(cfg RoleConfig, version uint64, oldAddr, newAddr Address, mode string, revoked *RoleRotation) error {
+	if _, err := AppendStateOutputSimple([]byte(cfg.StateID), version+1, newAddr.ToBytes(), nil); err != nil {
+		return err
+	}
+	if err := saveRolePending(cfg.Role, nil); err != nil {
+		return err
+	}
+
+	record := RoleRotationRecord{Mode: mode, OldAddress: oldAddr, NewAddress: newAddr, Caller: GetCaller(), Timestamp: GetTimestamp()}
+	if _, err := AppendIndexEntry(ROLE_AUDIT_INDEX, []byte(cfg.Role), encodeRoleRotationRecord(record)); err != nil {
+		return err
+	}
+
+	event := NewEvent("RoleKeyRotated")
+	event.AddStringField("role", cfg.Role)
+	event.AddStringField("mode", mode)
+	event.AddAddressField("old_address", oldAddr)
+	event.AddAddressField("new_address", newAddr)
+	event.AddAddressField("caller", record.Caller)
+	if revoked != nil && revoked.To != (Address{}) {
+		event.AddAddressField("revoked_pending", revoked.To)
+	}
+	EmitEvent(event)
+	return nil
+}
+
+// lookupRole 查询已登记的角色配置
+func lookupRole(role string) (RoleConfig, error) {
+	cfg, ok := roleConfigs[role]
+	if !ok {
+		return RoleConfig{}, NewContractError(ERROR_NOT_FOUND, "role not registered")
+	}
+	return cfg, nil
+}
+
+// loadRoleHolder 读取持有者地址及状态版本，不存在时版本为 0
+//
+// 状态值按地址解析而不是与调用者字节串直接比较：链上读取会去除尾部零字节（不足 20 字节时补齐），
+// 超过 20 字节时只取前 20 字节
+func loadRoleHolder(cfg RoleConfig) (Address, uint64) {
+	data, version, err := GetStateFromChain([]byte(cfg.StateID))
+	if err != nil || len(data) == 0 {
+		return Address{}, 0
+	}
+	var holder Address
+	copy(holder[:], data)
+	return holder, version
+}
+
+// loadRolePending 读取待接受的轮换（不检查期限）及状态版本
+func loadRolePending(role string) (*RoleRotation, uint64) {
+	data, version, err := GetStateFromChain(rolePendingStateID(role))
+	if err != nil || len(data) == 0 || data[0] != 1 {
+		return nil, version
+	}
+	buf := make([]byte, 57)
+	copy(buf, data)
+	pending := &RoleRotation{Role: role}
+	copy(pending.From[:], buf[1:21])
+	copy(pending.To[:], buf[21:41])
+	pending.StartedAt = beUint64(buf[41:49])
+	pending.Deadline = beUint64(buf[49:57])
+	return pending, version
+}
+
+// saveRolePending 写入待接受的轮换；pending 为 nil 时清除（active=0）
+func saveRolePending(role string, pending *RoleRotation) error {
+	_, version := loadRolePending(role)
+	value := []byte{0}
+	if pending != nil {
+		value = make([]byte, 0, 57)
+		value = append(value, 1)
+		value = append(value, pending.From[:]...)
+		value = append(value, pending.To[:]...)
+		value = appendBEUint64(value, pending.StartedAt)
+		value = appendBEUint64(value, pending.Deadline)
+	} else if version == 0 {
+		return nil
+	}
+	_, err := AppendStateOutputSimple(rolePendingStateID(role), version+1, value, nil)
+	return err
+}
+
+// rolePendingStateID 待接受轮换的状态ID
+func rolePendingStateID(role string) []byte {
+	return []byte("role_pending:" + role)
+}
+
+// encodeRoleRotationRecord 编码审计记录：modeLen(1) + mode + old(20) + new(20) + caller(20) + timestamp(8)
+func encodeRoleRotationRecord(r RoleRotationRecord) []byte {
+	buf := make([]byte, 0, 1+len(r.Mode)+68)
+	buf = append(buf, byte(len(r.Mode)))
+	buf = append(buf, r.Mode...)
+	buf = append(buf, r.OldAddress[:]...)
+	buf = append(buf, r.NewAddress[:]...)
+	buf = append(buf, r.Caller[:]...)
+	return appendBEUint64(buf, r.Timestamp)
+}
+
+// decodeRoleRotationRecord 解码审计记录
+func decodeRoleRotationRecord(data []byte) (RoleRotationRecord, error) {
+	if len(data) < 1 || len(data) != 1+int(data[0])+68 {
+		return RoleRotationRecord{}, NewContractError(ERROR_INVALID_STATE, "invalid role audit entry")
+	}
+	pos := 1 + int(data[0])
+	r := RoleRotationRecord{Mode: string(data[1:pos])}
+	copy(r.OldAddress[:], data[pos:pos+20])
+	copy(r.NewAddress[:], data[pos+20:pos+40])
+	copy(r.Caller[:], data[pos+40:pos+60])
+	r.Timestamp = beUint64(data[pos+60:])
+	return r, nil
+}
+
+// appendBEUint64 追加8字节大端编码
+func appendBEUint64(buf []byte, v uint64) []byte {
+	for i := 0; i < 8; i++ {
+		buf = append(buf, byte(v>>(56-8*i)))
+	}
+	return buf
+}
+
+// beUint64 读取8字节大端编码
+func beUint64(b []byte) uint64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
@@ -0,0 +1,181 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"testing"
+)
+
+var (
+	roleAlice   = Address{0xA1}
+	roleBob     = Address{0xB2}
+	roleMallory = Address{0xC3}
+	roleCarol   = Address{0xD4}
+	roleAdmin   = Address{0xE5}
+)
+
+// roleInvoke 以 caller 身份执行角色操作，返回错误码
+func roleInvoke(host *MockHost, caller Address, op func() error) MockCallResult {
+	return host.Invoke(caller, nil, func() uint32 {
+		if err := op(); err != nil {
+			if ce, ok := err.(*ContractError); ok {
+				return ce.Code
+			}
+			return ERROR_EXECUTION_FAILED
+		}
+		return SUCCESS
+	})
+}
+
+func installRoleHost(t *testing.T) *MockHost {
+	t.Helper()
+	host := NewMockHost()
+	host.Timestamp = 1000
+	t.Cleanup(InstallMockHost(host))
+	return host
+}
+
+// TestRoleRotationDualValidity 测试待接受期间新旧地址同时有效，接受后旧地址失效，过期后新地址失效
+func TestRoleRotationDualValidity(t *testing.T) {
+	RegisterRole(RoleConfig{Role: "test_operator", StateID: "test_operator", AcceptWindow: 100})
+	host := installRoleHost(t)
+
+	if res := roleInvoke(host, roleAlice, func() error { return InitRole("test_operator", roleAlice) }); res.Code != SUCCESS {
+		t.Fatalf("InitRole() code = %d", res.Code)
+	}
+	if value, _, _ := host.State("test_operator"); string(value) != string(roleAlice.ToBytes()) {
+		t.Errorf("holder state = %x, want raw alice address", value)
+	}
+	if res := roleInvoke(host, roleBob, func() error { return RotateRoleKey("test_operator", roleBob) }); res.Code != ERROR_UNAUTHORIZED {
+		t.Errorf("RotateRoleKey() by non-holder code = %d, want ERROR_UNAUTHORIZED", res.Code)
+	}
+
+	// 1. 发起轮换：期限内新旧地址都有效
+	if res := roleInvoke(host, roleAlice, func() error { return RotateRoleKey("test_operator", roleBob) }); res.Code != SUCCESS {
+		t.Fatalf("RotateRoleKey() code = %d", res.Code)
+	}
+	for _, ts := range []uint64{1000, 1100} {
+		host.Timestamp = ts
+		if !HasRole("test_operator", roleAlice) || !HasRole("test_operator", roleBob) || HasRole("test_operator", roleCarol) {
+			t.Errorf("at %d HasRole(alice, bob, carol) = %v, %v, %v, want true, true, false", ts,
+				HasRole("test_operator", roleAlice), HasRole("test_operator", roleBob), HasRole("test_operator", roleCarol))
+		}
+	}
+
+	// 2. 超过期限：待接受的新地址失效，不能再接受
+	host.Timestamp = 1101
+	if HasRole("test_operator", roleBob) || !HasRole("test_operator", roleAlice) {
+		t.Error("expired rotation: want only alice to hold the role")
+	}
+	if res := roleInvoke(host, roleBob, func() error { return AcceptRoleKey("test_operator") }); res.Code != ERROR_TIMEOUT {
+		t.Errorf("AcceptRoleKey() after window code = %d, want ERROR_TIMEOUT", res.Code)
+	}
+
+	// 3. 重新发起并接受：旧地址失效，写入审计索引
+	roleInvoke(host, roleAlice, func() error { return RotateRoleKey("test_operator", roleBob) })
+	host.Timestamp = 1150
+	if res := roleInvoke(host, roleCarol, func() error { return AcceptRoleKey("test_operator") }); res.Code != ERROR_UNAUTHORIZED {
+		t.Errorf("AcceptRoleKey() by other address code = %d, want ERROR_UNAUTHORIZED", res.Code)
+	}
+	res := roleInvoke(host, roleBob, func() error { return AcceptRoleKey("test_operator") })
+	if res.Code != SUCCESS {
+		t.Fatalf("AcceptRoleKey() code = %d", res.Code)
+	}
+	if HasRole("test_operator", roleAlice) || !HasRole("test_operator", roleBob) {
+		t.Error("after accept: want only bob to hold the role")
+	}
+	if _, ok := PendingRoleRotation("test_operator"); ok {
+		t.Error("pending rotation remains after accept")
+	}
+	last := res.Events[len(res.Events)-1]
+	if last.Name != "RoleKeyRotated" || last.Data["mode"] != ROLE_ROTATION_ACCEPTED ||
+		last.Data["old_address"] != roleAlice.ToString() || last.Data["new_address"] != roleBob.ToString() {
+		t.Errorf("event = %s %v", last.Name, last.Data)
+	}
+	if n := RoleAuditCount("test_operator"); n != 1 {
+		t.Fatalf("RoleAuditCount() = %d, want 1", n)
+	}
+	record, err := GetRoleAuditEntry("test_operator", 0)
+	want := RoleRotationRecord{Mode: ROLE_ROTATION_ACCEPTED, OldAddress: roleAlice, NewAddress: roleBob, Caller: roleBob, Timestamp: 1150}
+	if err != nil || record != want {
+		t.Errorf("GetRoleAuditEntry() = %+v, %v, want %+v", record, err, want)
+	}
+}
+
+// TestEmergencyRotateRevokesPending 测试紧急轮换立即替换持有者并撤销待接受的轮换
+func TestEmergencyRotateRevokesPending(t *testing.T) {
+	RegisterRole(RoleConfig{Role: "test_guardian", AdminRole: "test_guardian_admin", AcceptWindow: 100})
+	RegisterRole(RoleConfig{Role: "test_guardian_admin"})
+	host := installRoleHost(t)
+
+	roleInvoke(host, roleAdmin, func() error {
+		if err := InitRole("test_guardian_admin", roleAdmin); err != nil {
+			return err
+		}
+		return InitRole("test_guardian", roleAlice)
+	})
+
+	// 泄露的密钥发起了指向攻击者地址的轮换
+	if res := roleInvoke(host, roleAlice, func() error { return RotateRoleKey("test_guardian", roleMallory) }); res.Code != SUCCESS {
+		t.Fatalf("RotateRoleKey() code = %d", res.Code)
+	}
+	if !HasRole("test_guardian", roleMallory) {
+		t.Fatal("pending address should hold the role during the window")
+	}
+
+	if res := roleInvoke(host, roleAlice, func() error { return EmergencyRotate("test_guardian", roleCarol) }); res.Code != ERROR_UNAUTHORIZED {
+		t.Errorf("EmergencyRotate() by holder code = %d, want ERROR_UNAUTHORIZED", res.Code)
+	}
+	res := roleInvoke(host, roleAdmin, func() error { return EmergencyRotate("test_guardian", roleCarol) })
+	if res.Code != SUCCESS {
+		t.Fatalf("EmergencyRotate() code = %d", res.Code)
+	}
+
+	if holder, _ := RoleHolder("test_guardian"); holder != roleCarol {
+		t.Errorf("RoleHolder() = %v, want carol", holder)
+	}
+	if HasRole("test_guardian", roleAlice) || HasRole("test_guardian", roleMallory) {
+		t.Error("old holder or revoked pending address still holds the role")
+	}
+	if res := roleInvoke(host, roleMallory, func() error { return AcceptRoleKey("test_guardian") }); res.Code != ERROR_NOT_FOUND {
+		t.Errorf("AcceptRoleKey() of revoked rotation code = %d, want ERROR_NOT_FOUND", res.Code)
+	}
+
+	last := res.Events[len(res.Events)-1]
+	if last.Data["mode"] != ROLE_ROTATION_EMERGENCY || last.Data["revoked_pending"] != roleMallory.ToString() || last.Data["caller"] != roleAdmin.ToString() {
+		t.Errorf("RoleKeyRotated event = %v", last.Data)
+	}
+	record, err := GetRoleAuditEntry("test_guardian", 0)
+	if err != nil || record.Mode != ROLE_ROTATION_EMERGENCY || record.OldAddress != roleAlice || record.NewAddress != roleCarol {
+		t.Errorf("GetRoleAuditEntry() = %+v, %v", record, err)
+	}
+}
+
+// TestHasRoleParsesStoredHolder 测试持有者状态按地址解析：20 字节、超长、尾部零字节被去除的值都识别为同一地址
+func TestHasRoleParsesStoredHolder(t *testing.T) {
+	RegisterRole(RoleConfig{Role: "test_stored_holder", StateID: "test_stored_holder"})
+	host := installRoleHost(t)
+	trailingZero := Address{0x0e, 0x01}
+
+	tests := []struct {
+		name   string
+		value  []byte
+		holder Address
+	}{
+		{"20 bytes", roleAlice.ToBytes(), roleAlice},
+		{"over-length", append(append([]byte{}, roleAlice.ToBytes()...), 0xFF, 0xEE, 0xDD), roleAlice},
+		{"trailing zeros", trailingZero.ToBytes(), trailingZero},
+	}
+	for _, tt := range tests {
+		host.SetState("test_stored_holder", tt.value, 1)
+		if !HasRole("test_stored_holder", tt.holder) {
+			t.Errorf("%s: HasRole(holder) = false, want true", tt.name)
+		}
+		if HasRole("test_stored_holder", roleBob) {
+			t.Errorf("%s: HasRole(bob) = true, want false", tt.name)
+		}
+		if holder, err := RoleHolder("test_stored_holder"); err != nil || !holder.Equals(tt.holder) {
+			t.Errorf("%s: RoleHolder() = %x, %v, want %x", tt.name, holder, err, tt.holder)
+		}
+	}
+}
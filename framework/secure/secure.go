@@ -0,0 +1,102 @@
+// Package secure 提供秘密值的比较、承诺与日志脱敏工具
+//
+// 🌟 **设计理念**：承诺-揭示种子、邀请码哈希、许可签名、哈希锁原像等秘密值
+// 统一通过本包比较与承诺，避免各模板各自实现逐字节提前退出的比较（泄露时序信息）
+// 或在日志中输出完整秘密值。
+//
+// 🎯 **核心特性**：
+//   - ConstantTimeEqual：耗时只与输入长度有关的字节比较
+//   - CommitHash：带领域分隔的承诺哈希，不同功能的承诺不能互相重放
+//   - RedactForLog：日志中只显示短前缀与长度（framework.Secret 字段自动使用）
+//
+// 本包不依赖宿主函数，可在WASM与非WASM环境中直接使用。
+package secure
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// ConstantTimeEqual 常量时间比较两个字节数组
+//
+// 🎯 **用途**：比较承诺、哈希、签名等秘密值，比较耗时不随第一个不同字节的位置变化
+//
+// **返回**：a 与 b 长度相同且内容一致时返回 true；长度不同时直接返回 false（只泄露长度）
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// commitDomainTag 承诺哈希的版本前缀
+const commitDomainTag = "weisyn/secure/commit/v1"
+
+// CommitHash 计算带领域分隔的承诺哈希
+//
+// 🎯 **用途**：承诺-揭示、邀请码、哈希锁等场景生成承诺值
+//
+// **参数**：
+//   - secret: 秘密值（如揭示的种子、邀请码）
+//   - salt: 盐值（防止对低熵秘密值穷举）
+//   - domain: 领域标识，建议为 "模板名/用途"（如 "lottery/seed"）
+//
+// **返回**：sha256(tag || len(domain) || domain || len(salt) || salt || len(secret) || secret)，
+// 长度均为4字节大端，不同字段之间的边界不会产生歧义
+//
+// **注意**：不同领域的承诺互不相同，一个功能的承诺无法在另一个功能中重放
+//
+// **示例**：
+//
+//	commitment := secure.CommitHash(seed, salt, "lottery/seed")
+func CommitHash(secret, salt []byte, domain string) [32]byte {
+	h := sha256.New()
+	h.Write([]byte(commitDomainTag))
+	writeLengthPrefixed(h, []byte(domain))
+	writeLengthPrefixed(h, salt)
+	writeLengthPrefixed(h, secret)
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// VerifyCommit 常量时间校验揭示的秘密值与盐值是否与承诺一致
+func VerifyCommit(commitment [32]byte, secret, salt []byte, domain string) bool {
+	expected := CommitHash(secret, salt, domain)
+	return ConstantTimeEqual(commitment[:], expected[:])
+}
+
+func writeLengthPrefixed(h interface{ Write([]byte) (int, error) }, b []byte) {
+	n := uint32(len(b))
+	h.Write([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+	h.Write(b)
+}
+
+// redactPrefixBytes 脱敏后保留的前缀字节数
+const redactPrefixBytes = 2
+
+// redactMinLength 显示前缀所需的最小长度，更短的值只显示长度
+const redactMinLength = 16
+
+// RedactForLog 日志脱敏：只显示短前缀与长度
+//
+// **返回**：
+//   - 长度 ≥ 16 字节：前 2 字节十六进制 + 长度，如 "3a7f…(32 bytes)"
+//   - 更短的值：只显示长度，如 "…(8 bytes)"，避免前缀占秘密值的比例过大
+func RedactForLog(b []byte) string {
+	const hexChars = "0123456789abcdef"
+	out := make([]byte, 0, 2*redactPrefixBytes+16)
+	if len(b) >= redactMinLength {
+		for _, c := range b[:redactPrefixBytes] {
+			out = append(out, hexChars[c>>4], hexChars[c&0x0F])
+		}
+	}
+	out = append(out, "…("...)
+	out = appendUint(out, uint64(len(b)))
+	out = append(out, " bytes)"...)
+	return string(out)
+}
+
+func appendUint(out []byte, n uint64) []byte {
+	if n >= 10 {
+		out = appendUint(out, n/10)
+	}
+	return append(out, byte('0'+n%10))
+}
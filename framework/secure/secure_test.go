@@ -0,0 +1,71 @@
+package secure
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConstantTimeEqual 测试相等、不等、长度不同与空输入
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []byte
+		want bool
+	}{
+		{"equal", []byte("commitment"), []byte("commitment"), true},
+		{"differ in first byte", []byte("Commitment"), []byte("commitment"), false},
+		{"differ in last byte", []byte("commitmenT"), []byte("commitment"), false},
+		{"prefix", []byte("commit"), []byte("commitment"), false},
+		{"longer", []byte("commitment!"), []byte("commitment"), false},
+		{"nil and empty", nil, []byte{}, true},
+		{"empty and non-empty", nil, []byte{0}, false},
+	}
+	for _, tt := range tests {
+		if got := ConstantTimeEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: ConstantTimeEqual(%q, %q) = %v, want %v", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestCommitHash 测试承诺哈希的确定性、领域分隔与字段边界
+func TestCommitHash(t *testing.T) {
+	secret, salt := []byte("seed"), []byte("salt")
+	c := CommitHash(secret, salt, "lottery/seed")
+	if c != CommitHash(secret, salt, "lottery/seed") {
+		t.Fatal("CommitHash() is not deterministic")
+	}
+	for name, other := range map[string][32]byte{
+		"other domain":    CommitHash(secret, salt, "invite/code"),
+		"other salt":      CommitHash(secret, []byte("salT"), "lottery/seed"),
+		"shifted bytes":   CommitHash([]byte("tseed"), []byte("sal"), "lottery/seed"),
+		"secret and salt": CommitHash(salt, secret, "lottery/seed"),
+	} {
+		if other == c {
+			t.Errorf("%s: commitment collides", name)
+		}
+	}
+
+	if !VerifyCommit(c, secret, salt, "lottery/seed") {
+		t.Error("VerifyCommit() rejected the matching reveal")
+	}
+	if VerifyCommit(c, []byte("seeD"), salt, "lottery/seed") || VerifyCommit(c, secret, salt, "hashlock") {
+		t.Error("VerifyCommit() accepted a wrong reveal")
+	}
+}
+
+// TestRedactForLog 测试脱敏只保留短前缀与长度
+func TestRedactForLog(t *testing.T) {
+	secret := []byte{0x3a, 0x7f, 0xde, 0xad, 0xbe, 0xef, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25}
+	if got, want := RedactForLog(secret), "3a7f…(32 bytes)"; got != want {
+		t.Errorf("RedactForLog(32 bytes) = %q, want %q", got, want)
+	}
+	if strings.Contains(RedactForLog(secret), "dead") {
+		t.Error("RedactForLog() leaks bytes beyond the prefix")
+	}
+	if got, want := RedactForLog([]byte("12345678")), "…(8 bytes)"; got != want {
+		t.Errorf("RedactForLog(short) = %q, want %q", got, want)
+	}
+	if got, want := RedactForLog(nil), "…(0 bytes)"; got != want {
+		t.Errorf("RedactForLog(nil) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package framework
+
+// 调用内状态读取缓存
+//
+// 一次导出函数调用中，不同的辅助函数常常各自读取同一状态（计划配置、运营方、成员记录……），
+// 每次读取都是一次宿主调用。本文件提供调用范围内的读穿透缓存：
+//   - GetState / GetStateFromChain 先查缓存，未命中时才调用宿主，并缓存读取结果
+//   - 状态输出暂存成功后（AppendStateOutputSimple、TransactionBuilder.Finalize）更新缓存，
+//     本次调用随后的读取看到待提交的值与版本号，IncrementStateVersion 也随之递增
+//   - InvalidateRead 丢弃单个键的缓存，下次读取重新调用宿主
+//
+// 缓存只是确定性数据的内存视图：命中与否只影响宿主调用次数，不影响读取结果，各节点行为一致。
+// WASM 宿主可能复用实例，缓存在导出函数入口由 BeginInvocation 清空（见 invocation.go），
+// 不会把上一次调用的状态与版本号带入本次调用；非WASM环境另由 ResetStagedWrites
+// （MockHost.Invoke 在调用前后执行）清空。
+
+// stateCacheEntry 一个状态ID的缓存
+type stateCacheEntry struct {
+	// value 状态值：宿主读取结果，或暂存的状态输出负载
+	value []byte
+	// version 状态版本号（versioned 为 true 时有效）
+	version uint64
+	// versioned 是否包含版本号：GetState 的读取结果不含版本号，不能用于 GetStateFromChain
+	versioned bool
+	// missing 状态不存在（GetStateFromChain 返回 ERROR_NOT_FOUND）
+	missing bool
+}
+
+var stateReadCache = map[string]stateCacheEntry{}
+
+// InvalidateRead 丢弃状态ID的读取缓存
+//
+// 🎯 **用途**：本次调用中状态可能被缓存之外的途径改变时（如直接调用宿主原语写入），
+// 强制下次 GetState / GetStateFromChain 重新调用宿主
+//
+// **参数**：
+//   - key: 状态ID（与 GetState 的 key、GetStateFromChain 的 stateID 相同）
+//
+// **示例**：
+//
+//	framework.InvalidateRead("plan_config")
+//	data, _ := framework.GetState("plan_config") // 重新从宿主读取
+func InvalidateRead(key string) {
+	delete(stateReadCache, key)
+}
+
+// ResetReadCache 清空全部读取缓存（开始一次新的合约调用）
+//
+// 导出函数入口使用 BeginInvocation，一并清空其他调用范围内的状态
+func ResetReadCache() {
+	stateReadCache = map[string]stateCacheEntry{}
+}
+
+// cachedStateValue 查询状态值缓存（GetState 使用），不存在的状态不命中
+func cachedStateValue(key string) ([]byte, bool) {
+	entry, ok := stateReadCache[key]
+	if !ok || entry.missing {
+		return nil, false
+	}
+	return append([]byte{}, entry.value...), true
+}
+
+// cachedStateVersion 查询带版本号的缓存（GetStateFromChain 使用）
+//
+// **返回**：value、version、missing（状态不存在）、ok（是否命中）
+func cachedStateVersion(stateID string) ([]byte, uint64, bool, bool) {
+	entry, ok := stateReadCache[stateID]
+	if !ok || !entry.versioned {
+		return nil, 0, false, false
+	}
+	return append([]byte{}, entry.value...), entry.version, entry.missing, true
+}
+
+// cacheStateValue 缓存 GetState 的读取结果（已有缓存时不覆盖，带版本号的缓存信息更完整）
+func cacheStateValue(key string, value []byte) {
+	if _, ok := stateReadCache[key]; ok {
+		return
+	}
+	stateReadCache[key] = stateCacheEntry{value: append([]byte{}, value...)}
+}
+
+// cacheStateVersion 缓存 GetStateFromChain 的读取结果
+func cacheStateVersion(stateID string, value []byte, version uint64) {
+	stateReadCache[stateID] = stateCacheEntry{value: append([]byte{}, value...), version: version, versioned: true}
+}
+
+// cacheStateMissing 缓存状态不存在
+func cacheStateMissing(stateID string) {
+	stateReadCache[stateID] = stateCacheEntry{versioned: true, missing: true}
+}
+
+// cacheStagedState 状态输出暂存成功后更新缓存，本次调用随后的读取看到待提交的值
+func cacheStagedState(stateID []byte, version uint64, value []byte) {
+	cacheStateVersion(string(stateID), value, version)
+}
@@ -0,0 +1,184 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"bytes"
+	"testing"
+)
+
+// stateReadCounter 记录每个状态ID实际执行的宿主读取次数
+type stateReadCounter map[string]int
+
+func (c stateReadCounter) BeforeHostCall(call HostCall) error {
+	if call.Name == HOST_CALL_STATE_GET || call.Name == HOST_CALL_STATE_GET_FROM_CHAIN {
+		c[call.Name+":"+string(call.StateID)]++
+	}
+	return nil
+}
+
+func installStateReadCounter(t *testing.T) (*MockHost, stateReadCounter) {
+	t.Helper()
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	reads := stateReadCounter{}
+	t.Cleanup(SetHostInterceptor(reads))
+	ResetStagedWrites()
+	t.Cleanup(ResetStagedWrites)
+	return host, reads
+}
+
+// TestStateReadCacheHostCalls 测试同一调用内重复读取只执行一次宿主调用
+func TestStateReadCacheHostCalls(t *testing.T) {
+	host, reads := installStateReadCounter(t)
+	host.SetState("plan_config", []byte("config"), 2)
+
+	for i := 0; i < 5; i++ {
+		if value, _ := GetState("plan_config"); string(value) != "config" {
+			t.Fatalf("GetState() = %q, want config", value)
+		}
+		if value, version, err := GetStateFromChain([]byte("plan_config")); err != nil || string(value) != "config" || version != 2 {
+			t.Fatalf("GetStateFromChain() = %q, %d, %v", value, version, err)
+		}
+	}
+	if reads["state_get:plan_config"] != 1 || reads["state_get_from_chain:plan_config"] != 1 {
+		t.Errorf("host reads after 5 rounds = %v, want one state_get and one state_get_from_chain", reads)
+	}
+
+	// 不存在的状态同样缓存
+	for i := 0; i < 3; i++ {
+		if _, _, err := GetStateFromChain([]byte("operator")); err == nil {
+			t.Fatal("GetStateFromChain(missing) error = nil")
+		}
+	}
+	if reads["state_get_from_chain:operator"] != 1 {
+		t.Errorf("missing state host reads = %d, want 1", reads["state_get_from_chain:operator"])
+	}
+
+	// 修改缓存返回的切片不影响后续读取
+	value, _ := GetState("plan_config")
+	value[0] = 'X'
+	if value, _ := GetState("plan_config"); string(value) != "config" {
+		t.Errorf("GetState() after caller mutation = %q, want config", value)
+	}
+
+	// InvalidateRead 与新的调用重新读取宿主
+	InvalidateRead("plan_config")
+	GetState("plan_config")
+	if reads["state_get:plan_config"] != 2 {
+		t.Errorf("host reads after InvalidateRead = %d, want 2", reads["state_get:plan_config"])
+	}
+	ResetStagedWrites()
+	GetState("plan_config")
+	if reads["state_get:plan_config"] != 3 {
+		t.Errorf("host reads in next invocation = %d, want 3", reads["state_get:plan_config"])
+	}
+}
+
+// TestStateReadAfterStagedWrite 测试读取本次调用刚暂存的状态时看到待提交的值与版本号
+func TestStateReadAfterStagedWrite(t *testing.T) {
+	host, reads := installStateReadCounter(t)
+	host.SetState("round_r1", []byte("open"), 3)
+
+	version, err := IncrementStateVersion([]byte("round_r1"))
+	if err != nil || version != 4 {
+		t.Fatalf("IncrementStateVersion() = %d, %v, want 4", version, err)
+	}
+	if _, err := AppendStateOutputSimple([]byte("round_r1"), version, []byte("settled"), nil); err != nil {
+		t.Fatalf("AppendStateOutputSimple() error = %v", err)
+	}
+
+	if value, _ := GetState("round_r1"); string(value) != "settled" {
+		t.Errorf("GetState() after staged write = %q, want settled", value)
+	}
+	if value, version, _ := GetStateFromChain([]byte("round_r1")); string(value) != "settled" || version != 4 {
+		t.Errorf("GetStateFromChain() after staged write = %q, %d, want settled, 4", value, version)
+	}
+	if version, _ := IncrementStateVersion([]byte("round_r1")); version != 5 {
+		t.Errorf("IncrementStateVersion() after staged write = %d, want 5", version)
+	}
+	if reads["state_get:round_r1"] != 0 {
+		t.Errorf("GetState() after staged write reached host %d times", reads["state_get:round_r1"])
+	}
+
+	// 不存在的状态暂存后可见，尾部零字节与链上读取一致去除
+	if _, _, err := GetStateFromChain([]byte("paid_r1")); err == nil {
+		t.Fatal("GetStateFromChain(missing) error = nil")
+	}
+	builder := BeginTransaction().AddStateOutput([]byte("paid_r1"), 1, []byte{0x07, 0x00})
+	if ok, _, code := builder.Finalize(); !ok {
+		t.Fatalf("Finalize() code = %d", code)
+	}
+	if value, version, err := GetStateFromChain([]byte("paid_r1")); err != nil || !bytes.Equal(value, []byte{0x07}) || version != 1 {
+		t.Errorf("GetStateFromChain() after builder output = %v, %d, %v", value, version, err)
+	}
+
+	// 其他调用只看到已提交的状态
+	ResetStagedWrites()
+	if value, _ := GetState("round_r1"); string(value) != "open" {
+		t.Errorf("GetState() in next invocation = %q, want committed open", value)
+	}
+}
+
+// TestStateReadCacheInvoke 测试失败调用暂存的值不会泄漏到下一次调用
+func TestStateReadCacheInvoke(t *testing.T) {
+	host, _ := installStateReadCounter(t)
+	host.SetState("counter", []byte{1}, 1)
+
+	bump := func(code uint32) func() uint32 {
+		return func() uint32 {
+			value, version, _ := GetStateFromChain([]byte("counter"))
+			if _, err := AppendStateOutputSimple([]byte("counter"), version+1, []byte{value[0] + 1}, nil); err != nil {
+				return ERROR_EXECUTION_FAILED
+			}
+			// 同一调用内再次读取看到刚写入的值
+			if again, _ := GetState("counter"); again[0] != value[0]+1 {
+				return ERROR_INVALID_STATE
+			}
+			return code
+		}
+	}
+
+	if res := host.Invoke(Address{0x01}, nil, bump(ERROR_EXECUTION_FAILED)); res.Code != ERROR_EXECUTION_FAILED {
+		t.Fatalf("failing invoke code = %d", res.Code)
+	}
+	if res := host.Invoke(Address{0x01}, nil, bump(SUCCESS)); res.Code != SUCCESS {
+		t.Fatalf("invoke code = %d, want SUCCESS", res.Code)
+	}
+	if value, version, _ := host.State("counter"); value[0] != 2 || version != 2 {
+		t.Errorf("committed counter = %d (v%d), want 2 (v2)", value[0], version)
+	}
+
+	// 测试中直接修改已提交状态时丢弃缓存
+	GetState("counter")
+	host.SetState("counter", []byte{9}, 3)
+	if value, _ := GetState("counter"); value[0] != 9 {
+		t.Errorf("GetState() after SetState = %d, want 9", value[0])
+	}
+}
+
+// TestStateReadCacheResetAtEntry 测试复用实例的下一次调用（不经过 ResetStagedWrites）在入口 BeginInvocation 清空读取缓存：
+// 上一次调用暂存但未提交的值与版本号不会被读到
+func TestStateReadCacheResetAtEntry(t *testing.T) {
+	host, reads := installStateReadCounter(t)
+	host.SetState("plan_config", []byte("config"), 2)
+
+	// 1. 第一次调用：暂存新值后失败返回，状态输出未提交
+	BeginInvocation()
+	if _, err := AppendStateOutputSimple([]byte("plan_config"), 3, []byte("discarded"), nil); err != nil {
+		t.Fatalf("AppendStateOutputSimple() error = %v", err)
+	}
+	if value, _ := GetState("plan_config"); string(value) != "discarded" {
+		t.Fatalf("GetState() within first call = %q, want staged value", value)
+	}
+
+	// 2. 第二次调用：入口 BeginInvocation 后重新从宿主读取
+	BeginInvocation()
+	value, version, err := GetStateFromChain([]byte("plan_config"))
+	if err != nil || string(value) != "config" || version != 2 {
+		t.Errorf("GetStateFromChain() after entry = %q, %d, %v, want config, 2", value, version, err)
+	}
+	if got := reads[HOST_CALL_STATE_GET_FROM_CHAIN+":plan_config"]; got != 1 {
+		t.Errorf("host reads after entry = %d, want 1", got)
+	}
+}
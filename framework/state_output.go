@@ -0,0 +1,31 @@
+package framework
+
+// AppendStateOutputStrict 追加状态输出（严格版）
+//
+// 🎯 **用途**：与 AppendStateOutputSimple 相同，但 execHash 必须显式提供
+//
+// AppendStateOutputSimple 在 execHash 为空时自动使用 stateID 的哈希，调用方漏传真实执行结果时
+// 不会报错；要求显式 execHash 的合约使用本函数，漏传时立即失败。
+//
+// **参数**：
+//   - stateID: 状态ID（字节数组）
+//   - version: 状态版本号
+//   - execHash: 执行结果哈希（不能为空；非32字节时与 AppendStateOutputSimple 一致计算哈希）
+//   - parentHash: 父状态哈希（可选，nil表示无父状态）
+//
+// **返回**：
+//   - outputIndex: 输出索引（成功时返回索引，失败时返回0xFFFFFFFF）
+//   - error: execHash 为空返回 ERROR_INVALID_PARAMS，不暂存输出、不计入写入预算；其他错误同 AppendStateOutputSimple
+//
+// **示例**：
+//
+//	outputIndex, err := framework.AppendStateOutputStrict(stateID, version, resultHash, nil)
+//	if err != nil {
+//	    return framework.ERROR_INVALID_PARAMS
+//	}
+func AppendStateOutputStrict(stateID []byte, version uint64, execHash []byte, parentHash []byte) (uint32, error) {
+	if len(execHash) == 0 {
+		return 0xFFFFFFFF, NewContractError(ERROR_INVALID_PARAMS, "execHash cannot be empty")
+	}
+	return AppendStateOutputSimple(stateID, version, execHash, parentHash)
+}
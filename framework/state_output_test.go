@@ -0,0 +1,48 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// TestAppendStateOutputStrictRejectsEmptyExecHash 测试严格版拒绝空 execHash 且不暂存输出、不计入写入预算
+func TestAppendStateOutputStrictRejectsEmptyExecHash(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	res := host.Invoke(Address{0x01}, nil, func() uint32 {
+		DeclareWriteBudget(16)
+		for _, execHash := range [][]byte{nil, {}} {
+			index, err := AppendStateOutputStrict([]byte("strict_key"), 1, execHash, nil)
+			if index != 0xFFFFFFFF || err == nil || err.(*ContractError).Code != ERROR_INVALID_PARAMS {
+				t.Errorf("AppendStateOutputStrict(%v) = %d, %v, want ERROR_INVALID_PARAMS", execHash, index, err)
+			}
+		}
+		// 预算未被消耗：16 字节内的写入仍然成功
+		if _, err := AppendStateOutputStrict([]byte("strict_key"), 1, []byte("result"), nil); err != nil {
+			t.Errorf("AppendStateOutputStrict(result) error = %v", err)
+		}
+		return SUCCESS
+	})
+	if res.Code != SUCCESS || len(res.Writes) != 1 {
+		t.Fatalf("Invoke() code = %d, writes = %v, want 1 write", res.Code, res.Writes)
+	}
+	if value, version, ok := host.State("strict_key"); !ok || string(value) != "result" || version != 1 {
+		t.Errorf("State(strict_key) = %q, %d, %v, want result at version 1", value, version, ok)
+	}
+}
+
+// TestAppendStateOutputSimpleAcceptsEmptyExecHash 测试简化版默认行为不变：空 execHash 仍然暂存
+func TestAppendStateOutputSimpleAcceptsEmptyExecHash(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+
+	res := host.Invoke(Address{0x01}, nil, func() uint32 {
+		if _, err := AppendStateOutputSimple([]byte("simple_key"), 1, nil, nil); err != nil {
+			return ERROR_EXECUTION_FAILED
+		}
+		return SUCCESS
+	})
+	if res.Code != SUCCESS || len(res.Writes) != 1 {
+		t.Errorf("Invoke() code = %d, writes = %v, want 1 write", res.Code, res.Writes)
+	}
+}
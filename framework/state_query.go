@@ -0,0 +1,86 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+// ==================== 按前缀查询状态 ====================
+//
+// GetState / GetStateFromChain 只能按完整状态ID读取单个状态。列出计划成员、轮次案件、提案等
+// 列表查询使用 QueryStatesByPrefix，由宿主函数 state_query_by_prefix 按状态ID前缀分页返回匹配的状态。
+//
+// 宿主以 JSON 返回结果（字节字段为标准 base64，与 utxo_lookup_json 一致），按状态ID字典序排列：
+//
+//	{"entries":[{"state_id":"Y2xhaW1f...","value":"AAEC...","version":3}]}
+//
+// 结果缓冲区按需扩大（见 readHostBuffer），单页结果上限 MAX_STATE_QUERY_RESULT_SIZE。
+
+const (
+	// MAX_STATE_QUERY_LIMIT 单次前缀查询最多返回的状态条数
+	MAX_STATE_QUERY_LIMIT = 100
+
+	// MAX_STATE_QUERY_RESULT_SIZE 单次前缀查询结果 JSON 的最大字节数（512KB）
+	//
+	// 状态值较大时即使条数未超过 limit 也可能超限，此时返回 ERROR_QUOTA_EXCEEDED，应减小 limit 分页读取
+	MAX_STATE_QUERY_RESULT_SIZE = 512 << 10
+
+	// stateQueryInitialBufSize 首次读取查询结果的缓冲区大小
+	stateQueryInitialBufSize = 16 << 10
+)
+
+// StateEntry 前缀查询返回的一条状态
+type StateEntry struct {
+	// StateID 完整状态ID
+	StateID []byte
+	// Value 状态值
+	Value []byte
+	// Version 状态版本号
+	Version uint64
+}
+
+// validateStateQuery 校验前缀查询参数：前缀不能为空，limit 为 1 到 MAX_STATE_QUERY_LIMIT
+func validateStateQuery(prefix []byte, limit uint32) error {
+	if len(prefix) == 0 {
+		return NewContractError(ERROR_INVALID_PARAMS, "state query prefix cannot be empty")
+	}
+	if limit == 0 || limit > MAX_STATE_QUERY_LIMIT {
+		return NewContractError(ERROR_INVALID_PARAMS, "state query limit must be between 1 and MAX_STATE_QUERY_LIMIT")
+	}
+	return nil
+}
+
+// parseStateEntries 解析宿主返回的前缀查询结果
+//
+// 空结果（长度为 0）视为没有匹配的状态。结果格式错误、条数超过 limit、
+// 或状态ID不以 prefix 开头时返回 ERROR_EXECUTION_FAILED，不返回部分结果。
+func parseStateEntries(data []byte, prefix []byte, limit uint32) ([]StateEntry, error) {
+	if len(data) == 0 {
+		return []StateEntry{}, nil
+	}
+	items := NewContractParams(data).ParseJSONObjectArray("entries")
+	if items == nil {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "malformed state query result")
+	}
+	if uint32(len(items)) > limit {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "state query returned more entries than limit")
+	}
+
+	entries := make([]StateEntry, 0, len(items))
+	for _, item := range items {
+		stateID, err := base64.StdEncoding.DecodeString(item.ParseJSON("state_id"))
+		if err != nil || !bytes.HasPrefix(stateID, prefix) {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid state_id in state query result")
+		}
+		value, err := base64.StdEncoding.DecodeString(item.ParseJSON("value"))
+		if err != nil {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid value in state query result")
+		}
+		version, ok := item.ParseJSONIntChecked("version")
+		if !ok || version < 0 {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid version in state query result")
+		}
+		entries = append(entries, StateEntry{StateID: stateID, Value: value, Version: uint64(version)})
+	}
+	return entries, nil
+}
@@ -0,0 +1,130 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func stateQueryCode(err error) uint32 {
+	if ce, ok := err.(*ContractError); ok {
+		return ce.Code
+	}
+	return SUCCESS
+}
+
+// TestQueryStatesByPrefixPagination 测试按前缀分页查询：字典序、offset/limit、不匹配前缀的状态被排除
+func TestQueryStatesByPrefixPagination(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	for i := 0; i < 5; i++ {
+		host.SetState(fmt.Sprintf("claim_plan_a_%02d", i), []byte{byte(i), 0, 0xFF}, uint64(i+1))
+	}
+	host.SetState("claim_plan_b_00", []byte("other"), 1)
+	host.SetState("claims_approved_unpaid", []byte{1}, 1)
+
+	var pages [][]StateEntry
+	for offset := uint32(0); ; offset += 2 {
+		entries, err := QueryStatesByPrefix([]byte("claim_plan_a_"), 2, offset)
+		if err != nil {
+			t.Fatalf("QueryStatesByPrefix(offset %d) error = %v", offset, err)
+		}
+		pages = append(pages, entries)
+		if len(entries) < 2 {
+			break
+		}
+	}
+	if len(pages) != 3 || len(pages[2]) != 1 {
+		t.Fatalf("pages = %d, last page %d entries, want 3 pages ending with 1", len(pages), len(pages[len(pages)-1]))
+	}
+	for i, e := range append(append(pages[0], pages[1]...), pages[2]...) {
+		if string(e.StateID) != fmt.Sprintf("claim_plan_a_%02d", i) || e.Version != uint64(i+1) || string(e.Value) != string([]byte{byte(i), 0, 0xFF}) {
+			t.Errorf("entry %d = %q / %v / %d", i, e.StateID, e.Value, e.Version)
+		}
+	}
+
+	if entries, err := QueryStatesByPrefix([]byte("claim_plan_a_"), 10, 5); err != nil || len(entries) != 0 {
+		t.Errorf("offset past end = %d entries, %v", len(entries), err)
+	}
+	if entries, err := QueryStatesByPrefix([]byte("proposal_"), 10, 0); err != nil || len(entries) != 0 {
+		t.Errorf("no match = %d entries, %v", len(entries), err)
+	}
+}
+
+// TestQueryStatesByPrefixInvalid 测试参数校验与宿主调用失败
+func TestQueryStatesByPrefixInvalid(t *testing.T) {
+	t.Cleanup(InstallMockHost(NewMockHost()))
+	for _, tt := range []struct {
+		prefix string
+		limit  uint32
+	}{{"", 10}, {"claim_", 0}, {"claim_", MAX_STATE_QUERY_LIMIT + 1}} {
+		if _, err := QueryStatesByPrefix([]byte(tt.prefix), tt.limit, 0); stateQueryCode(err) != ERROR_INVALID_PARAMS {
+			t.Errorf("QueryStatesByPrefix(%q, %d) error = %v, want ERROR_INVALID_PARAMS", tt.prefix, tt.limit, err)
+		}
+	}
+
+	restore := SetHostInterceptor(hostCallFailer{name: HOST_CALL_STATE_QUERY_BY_PREFIX})
+	defer restore()
+	if _, err := QueryStatesByPrefix([]byte("claim_"), 10, 0); err == nil {
+		t.Error("QueryStatesByPrefix() succeeded although the host call failed")
+	}
+}
+
+// hostCallFailer 使指定名称的宿主调用失败
+type hostCallFailer struct{ name string }
+
+func (f hostCallFailer) BeforeHostCall(call HostCall) error {
+	if call.Name == f.name {
+		return errors.New("injected failure")
+	}
+	return nil
+}
+
+// TestParseStateEntriesDefensive 测试宿主结果格式错误、条数超限或前缀不符时不返回部分结果
+func TestParseStateEntriesDefensive(t *testing.T) {
+	valid := `{"entries":[{"state_id":"Y2xhaW1fMQ==","value":"AQI=","version":2}]}` // claim_1
+	entries, err := parseStateEntries([]byte(valid), []byte("claim_"), 10)
+	if err != nil || len(entries) != 1 || string(entries[0].StateID) != "claim_1" || entries[0].Version != 2 {
+		t.Fatalf("parseStateEntries(valid) = %+v, %v", entries, err)
+	}
+	if entries, err := parseStateEntries(nil, []byte("claim_"), 10); err != nil || len(entries) != 0 {
+		t.Errorf("parseStateEntries(empty) = %+v, %v", entries, err)
+	}
+
+	bad := map[string]string{
+		"truncated":      valid[:len(valid)-10],
+		"not an array":   `{"entries":{}}`,
+		"missing":        `{}`,
+		"bad base64":     `{"entries":[{"state_id":"***","value":"","version":1}]}`,
+		"wrong prefix":   `{"entries":[{"state_id":"cm91bmRfMQ==","value":"","version":1}]}`, // round_1
+		"bad version":    `{"entries":[{"state_id":"Y2xhaW1fMQ==","value":"","version":-1}]}`,
+		"over the limit": `{"entries":[{"state_id":"Y2xhaW1fMQ==","value":"","version":1},{"state_id":"Y2xhaW1fMg==","value":"","version":1}]}`,
+	}
+	for name, data := range bad {
+		if entries, err := parseStateEntries([]byte(data), []byte("claim_"), 1); stateQueryCode(err) != ERROR_EXECUTION_FAILED || entries != nil {
+			t.Errorf("%s: parseStateEntries() = %+v, %v, want ERROR_EXECUTION_FAILED", name, entries, err)
+		}
+	}
+}
+
+// TestStateQueryResultSizeLimit 测试查询结果超过 MAX_STATE_QUERY_RESULT_SIZE 时返回 ERROR_QUOTA_EXCEEDED
+func TestStateQueryResultSizeLimit(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	big := []byte(strings.Repeat("v", 8<<10))
+	for i := 0; i < MAX_STATE_QUERY_LIMIT; i++ {
+		host.SetState(fmt.Sprintf("blob_%03d", i), big, 1)
+	}
+
+	if _, err := QueryStatesByPrefix([]byte("blob_"), MAX_STATE_QUERY_LIMIT, 0); stateQueryCode(err) != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("oversized page error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	// 减小 limit 后可以分页读取
+	entries, err := QueryStatesByPrefix([]byte("blob_"), 20, 0)
+	if err != nil || len(entries) != 20 || len(entries[19].Value) != len(big) {
+		t.Errorf("smaller page = %d entries, %v", len(entries), err)
+	}
+}
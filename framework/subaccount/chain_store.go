@@ -0,0 +1,33 @@
+package subaccount
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// hostStore 基于宿主状态输出的存储后端
+//
+// 读取使用 GetStateFromChain，写入使用 AppendStateOutputSimple。
+// 本文件不带 build tag，非WASM环境可配合 MockHost 与宿主调用拦截器测试宿主故障的处理；
+// WASM 环境的默认存储见 host_store.go。
+type hostStore struct{}
+
+// Load 从链上读取状态
+//
+// 状态不存在（ERROR_NOT_FOUND）时视为 0 余额、版本 0；其他宿主错误（分配失败、值过大、执行失败）原样返回，
+// 不能当作 0 余额，否则随后的 Credit 会以版本 1 覆盖已有数据
+func (hostStore) Load(key string) ([]byte, uint64, error) {
+	value, version, err := framework.GetStateFromChain([]byte(key))
+	if err != nil {
+		if ce, ok := err.(*framework.ContractError); ok && ce.Code == framework.ERROR_NOT_FOUND {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	return value, version, nil
+}
+
+// Save 追加状态输出
+func (hostStore) Save(key string, version uint64, value []byte) error {
+	_, err := framework.AppendStateOutputSimple([]byte(key), version, value, nil)
+	return err
+}
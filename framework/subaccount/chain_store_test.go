@@ -0,0 +1,70 @@
+//go:build !tinygo && !(js && wasm)
+
+package subaccount
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// failStateReads 使链上状态读取失败（模拟分配失败、值过大等宿主错误）
+type failStateReads struct{}
+
+func (failStateReads) BeforeHostCall(call framework.HostCall) error {
+	if call.Name == framework.HOST_CALL_STATE_GET_FROM_CHAIN {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "injected state read failure")
+	}
+	return nil
+}
+
+// TestHostStoreLoadPropagatesHostFailure 测试宿主读取失败时返回错误，不当作 0 余额覆盖已有余额；
+// 状态不存在时仍视为 0 余额
+func TestHostStoreLoadPropagatesHostFailure(t *testing.T) {
+	host := framework.NewMockHost()
+	t.Cleanup(framework.InstallMockHost(host))
+	ledger := NewLedger(hostStore{})
+	alice := testAddr(1)
+
+	// 1. 状态不存在：0 余额，首次存入成功
+	res := host.Invoke(alice, nil, func() uint32 {
+		if err := ledger.Credit(testNS, alice, "USDT", 100); err != nil {
+			t.Errorf("Credit() on missing state error = %v", err)
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.SUCCESS
+	})
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("first Credit code = %d", res.Code)
+	}
+
+	// 2. 注入读取失败：Credit、Balance 与守恒断言返回错误，不写入状态
+	restore := framework.SetHostInterceptor(failStateReads{})
+	res = host.Invoke(alice, nil, func() uint32 {
+		if err := ledger.Credit(testNS, alice, "USDT", 5); err == nil {
+			t.Error("Credit() with failing host read succeeded, want error")
+		}
+		if _, err := ledger.Balance(testNS, alice, "USDT"); err == nil {
+			t.Error("Balance() with failing host read succeeded, want error")
+		}
+		if err := ledger.AssertConservation(testNS, "USDT", 0); err == nil {
+			t.Error("AssertConservation() with failing host read passed, want error")
+		}
+		return framework.SUCCESS
+	})
+	restore()
+	if len(res.Writes) != 0 {
+		t.Errorf("writes with failing host read = %v, want none", res.Writes)
+	}
+
+	// 3. 恢复后余额与版本号未被覆盖
+	host.Invoke(alice, nil, func() uint32 {
+		if got, err := ledger.Balance(testNS, alice, "USDT"); err != nil || got != 100 {
+			t.Errorf("Balance() after failure = %d, %v, want 100", got, err)
+		}
+		return framework.SUCCESS
+	})
+	if _, version, ok := host.State(balanceKey(testNS, alice, "USDT")); !ok || version != 1 {
+		t.Errorf("balance state version = %d, %v, want 1", version, ok)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build tinygo || (js && wasm)
+
+package subaccount
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// defaultStore 返回WASM环境的默认存储后端（hostStore，见 chain_store.go）
+func defaultStore() Store {
+	return hostStore{}
+}
+
+// CheckContractConservation 以合约地址余额校验守恒
+//
+// 🎯 **用途**：在每次调用结束前断言 合约余额 ≥ 总负债
+//
+// **示例**：
+//
+//	if err := subaccount.CheckContractConservation("deposits", tokenID); err != nil {
+//	    return framework.ERROR_INVALID_STATE
+//	}
+func CheckContractConservation(ns string, tokenID framework.TokenID) error {
+	contractBalance := framework.QueryUTXOBalance(framework.GetContractAddress(), tokenID)
+	return AssertConservation(ns, tokenID, contractBalance)
+}
@@ -0,0 +1,8 @@
+//go:build !tinygo && !(js && wasm)
+
+package subaccount
+
+// defaultStore 非WASM环境使用内存存储，使得 go build ./... 与单元测试可运行
+func defaultStore() Store {
+	return NewMemoryStore()
+}
@@ -0,0 +1,387 @@
+// Package subaccount 提供合约内子账户（虚拟余额）台账
+//
+// 🌟 **设计理念**：合约资金统一托管在 GetContractAddress() 下，
+// 由台账记录"谁拥有多少"，避免每个模板各自实现一套易错的记账逻辑。
+//
+// 🎯 **核心特性**：
+//   - 命名空间隔离：同一合约可维护多本台账（如存款、借款）
+//   - 溢出检查：所有加减运算均为检查型运算
+//   - 定长编码：余额与总负债均以8字节大端编码存储
+//   - 增量总负债：TotalLiabilities 随每次记账增量维护，无需遍历
+//   - 守恒断言：AssertConservation 校验 合约资产 ≥ 总负债
+package subaccount
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 存储抽象 ====================
+
+// Store 台账存储后端
+//
+// 🎯 **用途**：屏蔽宿主状态读写细节，便于在非WASM环境中测试
+//
+// **约定**：
+//   - Load: 状态不存在时返回 (nil, 0, nil)
+//   - Save: 以给定版本号写入状态值
+type Store interface {
+	Load(key string) ([]byte, uint64, error)
+	Save(key string, version uint64, value []byte) error
+}
+
+// ==================== 台账 ====================
+
+// Ledger 子账户台账
+type Ledger struct {
+	store Store
+}
+
+// NewLedger 基于指定存储后端创建台账
+//
+// **示例**：
+//
+//	ledger := subaccount.NewLedger(subaccount.NewMemoryStore())
+//	err := ledger.Credit("deposits", owner, tokenID, 100)
+func NewLedger(store Store) *Ledger {
+	return &Ledger{store: store}
+}
+
+// defaultLedger 默认台账（WASM环境使用宿主状态，非WASM环境使用内存存储）
+var defaultLedger = NewLedger(defaultStore())
+
+// Credit 增加子账户余额，同时增加命名空间总负债
+//
+// **返回**：
+//   - error: 参数无效或余额/总负债溢出时返回错误
+func (l *Ledger) Credit(ns string, owner framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := validateEntry(ns, tokenID, amount); err != nil {
+		return err
+	}
+
+	accountKey := balanceKey(ns, owner, tokenID)
+	balance, balanceVersion, err := l.load(accountKey)
+	if err != nil {
+		return err
+	}
+	totalKey := liabilitiesKey(ns, tokenID)
+	total, totalVersion, err := l.load(totalKey)
+	if err != nil {
+		return err
+	}
+
+	newBalance, ok := checkedAdd(balance, uint64(amount))
+	if !ok {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "sub-account balance overflow")
+	}
+	newTotal, ok := checkedAdd(total, uint64(amount))
+	if !ok {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "total liabilities overflow")
+	}
+
+	if err := l.save(accountKey, balanceVersion, newBalance); err != nil {
+		return err
+	}
+	return l.save(totalKey, totalVersion, newTotal)
+}
+
+// Debit 扣减子账户余额，同时扣减命名空间总负债
+//
+// **返回**：
+//   - error: 虚拟余额不足时返回 ERROR_INSUFFICIENT_BALANCE，余额保持不变
+func (l *Ledger) Debit(ns string, owner framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := validateEntry(ns, tokenID, amount); err != nil {
+		return err
+	}
+
+	accountKey := balanceKey(ns, owner, tokenID)
+	balance, balanceVersion, err := l.load(accountKey)
+	if err != nil {
+		return err
+	}
+	newBalance, ok := checkedSub(balance, uint64(amount))
+	if !ok {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient sub-account balance")
+	}
+
+	totalKey := liabilitiesKey(ns, tokenID)
+	total, totalVersion, err := l.load(totalKey)
+	if err != nil {
+		return err
+	}
+	newTotal, ok := checkedSub(total, uint64(amount))
+	if !ok {
+		// 总负债小于单个子账户余额，说明台账已损坏
+		return framework.NewContractError(framework.ERROR_INVALID_STATE, "total liabilities underflow")
+	}
+
+	if err := l.save(accountKey, balanceVersion, newBalance); err != nil {
+		return err
+	}
+	return l.save(totalKey, totalVersion, newTotal)
+}
+
+// Transfer 在同一命名空间的两个子账户之间划转，总负债不变
+func (l *Ledger) Transfer(ns string, from, to framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := validateEntry(ns, tokenID, amount); err != nil {
+		return err
+	}
+	if from == to {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "cannot transfer to the same sub-account")
+	}
+
+	fromKey := balanceKey(ns, from, tokenID)
+	fromBalance, fromVersion, err := l.load(fromKey)
+	if err != nil {
+		return err
+	}
+	newFrom, ok := checkedSub(fromBalance, uint64(amount))
+	if !ok {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient sub-account balance")
+	}
+
+	toKey := balanceKey(ns, to, tokenID)
+	toBalance, toVersion, err := l.load(toKey)
+	if err != nil {
+		return err
+	}
+	newTo, ok := checkedAdd(toBalance, uint64(amount))
+	if !ok {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "sub-account balance overflow")
+	}
+
+	if err := l.save(fromKey, fromVersion, newFrom); err != nil {
+		return err
+	}
+	return l.save(toKey, toVersion, newTo)
+}
+
+// Balance 查询子账户余额（不存在时为0）
+func (l *Ledger) Balance(ns string, owner framework.Address, tokenID framework.TokenID) (framework.Amount, error) {
+	if err := validateKeyParts(ns, tokenID); err != nil {
+		return 0, err
+	}
+	balance, _, err := l.load(balanceKey(ns, owner, tokenID))
+	return framework.Amount(balance), err
+}
+
+// TotalLiabilities 查询命名空间内某代币的总负债（所有子账户余额之和）
+func (l *Ledger) TotalLiabilities(ns string, tokenID framework.TokenID) (framework.Amount, error) {
+	if err := validateKeyParts(ns, tokenID); err != nil {
+		return 0, err
+	}
+	total, _, err := l.load(liabilitiesKey(ns, tokenID))
+	return framework.Amount(total), err
+}
+
+// AssertConservation 守恒断言：合约实际持有资产必须不少于总负债
+//
+// **参数**：
+//   - assets: 合约可用于偿付该命名空间负债的资产总额
+//     （通常为合约地址余额；借贷场景需加上未偿还借款）
+//
+// **返回**：
+//   - error: assets < TotalLiabilities 时返回 ERROR_INVALID_STATE
+func (l *Ledger) AssertConservation(ns string, tokenID framework.TokenID, assets framework.Amount) error {
+	total, err := l.TotalLiabilities(ns, tokenID)
+	if err != nil {
+		return err
+	}
+	if assets < total {
+		return framework.NewContractError(framework.ERROR_INVALID_STATE, "conservation violated: assets below total liabilities")
+	}
+	return nil
+}
+
+// ==================== 默认台账便捷函数 ====================
+
+// CreditSubAccount 增加子账户余额
+//
+// 🎯 **用途**：记录用户存入合约的资金
+//
+// **参数**：
+//   - ns: 台账命名空间（如 "deposits"）
+//   - owner: 子账户所有者
+//   - tokenID: 代币ID（空字符串表示原生币）
+//   - amount: 金额（必须大于0）
+//
+// **示例**：
+//
+//	err := subaccount.CreditSubAccount("deposits", caller, tokenID, framework.Amount(amount))
+//	if err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func CreditSubAccount(ns string, owner framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	return defaultLedger.Credit(ns, owner, tokenID, amount)
+}
+
+// DebitSubAccount 扣减子账户余额
+//
+// 🎯 **用途**：记录用户从合约取出的资金，虚拟余额不足时失败
+//
+// **示例**：
+//
+//	if err := subaccount.DebitSubAccount("deposits", caller, tokenID, amount); err != nil {
+//	    return framework.ERROR_INSUFFICIENT_BALANCE
+//	}
+func DebitSubAccount(ns string, owner framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	return defaultLedger.Debit(ns, owner, tokenID, amount)
+}
+
+// GetSubAccountBalance 查询子账户余额
+func GetSubAccountBalance(ns string, owner framework.Address, tokenID framework.TokenID) (framework.Amount, error) {
+	return defaultLedger.Balance(ns, owner, tokenID)
+}
+
+// TransferBetweenSubAccounts 子账户间划转（不产生链上资产转移）
+func TransferBetweenSubAccounts(ns string, from, to framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	return defaultLedger.Transfer(ns, from, to, tokenID, amount)
+}
+
+// TotalLiabilities 查询命名空间总负债
+func TotalLiabilities(ns string, tokenID framework.TokenID) (framework.Amount, error) {
+	return defaultLedger.TotalLiabilities(ns, tokenID)
+}
+
+// AssertConservation 守恒断言（默认台账）
+func AssertConservation(ns string, tokenID framework.TokenID, assets framework.Amount) error {
+	return defaultLedger.AssertConservation(ns, tokenID, assets)
+}
+
+// LiabilitySource 以默认台账命名空间的总负债作为协议资金来源
+//
+// **示例**：
+//
+//	pool.ProtocolBalances = subaccount.LiabilitySource("deposits")
+func LiabilitySource(ns string) framework.ProtocolBalanceSource {
+	return defaultLedger.LiabilitySource(ns)
+}
+
+// LiabilitySource 以命名空间总负债作为协议资金来源
+//
+// 🎯 **用途**：供 ContractBase.SweepExcess 使用，子账户持有的资金不会被清扫
+func (l *Ledger) LiabilitySource(ns string) framework.ProtocolBalanceSource {
+	return liabilitySource{ledger: l, ns: ns}
+}
+
+// liabilitySource 台账总负债适配器
+type liabilitySource struct {
+	ledger *Ledger
+	ns     string
+}
+
+// ProtocolBalance 返回命名空间总负债
+func (s liabilitySource) ProtocolBalance(tokenID framework.TokenID) (framework.Amount, error) {
+	return s.ledger.TotalLiabilities(s.ns, tokenID)
+}
+
+// ==================== 内部辅助函数 ====================
+
+// validateEntry 验证记账参数
+func validateEntry(ns string, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := validateKeyParts(ns, tokenID); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	return nil
+}
+
+// validateKeyParts 验证状态键的组成部分
+//
+// 命名空间与代币ID以 ':' 分隔拼入状态键，二者都不能包含 ':'，
+// 否则 ("a:b", "c") 与 ("a", "b:c") 会映射到同一个状态键
+func validateKeyParts(ns string, tokenID framework.TokenID) error {
+	if ns == "" {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "namespace cannot be empty")
+	}
+	if containsSeparator(ns) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "namespace cannot contain ':'")
+	}
+	if containsSeparator(string(tokenID)) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "token id cannot contain ':'")
+	}
+	return nil
+}
+
+// containsSeparator 判断字符串是否包含状态键分隔符 ':'
+func containsSeparator(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ':' {
+			return true
+		}
+	}
+	return false
+}
+
+// load 读取定长编码的金额，状态不存在时返回0
+func (l *Ledger) load(key string) (uint64, uint64, error) {
+	value, version, err := l.store.Load(key)
+	if err != nil {
+		return 0, 0, err
+	}
+	return decodeAmount(value), version, nil
+}
+
+// save 写入定长编码的金额，版本号递增
+func (l *Ledger) save(key string, version uint64, value uint64) error {
+	return l.store.Save(key, version+1, encodeAmount(value))
+}
+
+// balanceKey 子账户余额状态键：subacct:{ns}:{owner_hex}:{token_id}
+func balanceKey(ns string, owner framework.Address, tokenID framework.TokenID) string {
+	return "subacct:" + ns + ":" + hexEncode(owner[:]) + ":" + string(tokenID)
+}
+
+// liabilitiesKey 总负债状态键：subacct_total:{ns}:{token_id}
+func liabilitiesKey(ns string, tokenID framework.TokenID) string {
+	return "subacct_total:" + ns + ":" + string(tokenID)
+}
+
+// encodeAmount 8字节大端编码
+func encodeAmount(v uint64) []byte {
+	b := make([]byte, 8)
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
+
+// decodeAmount 8字节大端解码
+//
+// 宿主读取链上状态时会去掉尾部零字节，这里按前缀补齐到8字节
+func decodeAmount(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], b)
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(buf[i])
+	}
+	return v
+}
+
+// checkedAdd 检查型加法
+func checkedAdd(a, b uint64) (uint64, bool) {
+	sum := a + b
+	return sum, sum >= a
+}
+
+// checkedSub 检查型减法
+func checkedSub(a, b uint64) (uint64, bool) {
+	if b > a {
+		return 0, false
+	}
+	return a - b, true
+}
+
+// hexEncode 十六进制编码（避免引入encoding/hex）
+func hexEncode(data []byte) string {
+	const hexChars = "0123456789abcdef"
+	out := make([]byte, len(data)*2)
+	for i, b := range data {
+		out[i*2] = hexChars[b>>4]
+		out[i*2+1] = hexChars[b&0x0f]
+	}
+	return string(out)
+}
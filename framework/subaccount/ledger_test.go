@@ -0,0 +1,190 @@
+package subaccount
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+const testNS = "deposits"
+
+func testAddr(b byte) framework.Address {
+	return framework.Address{b}
+}
+
+// TestDebitBelowZeroRejected 测试虚拟余额不足时扣减被拒绝且状态不变
+func TestDebitBelowZeroRejected(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	alice := testAddr(1)
+
+	if err := ledger.Credit(testNS, alice, "", 100); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+
+	err := ledger.Debit(testNS, alice, "", 101)
+	if err == nil {
+		t.Fatal("Debit() below zero should fail")
+	}
+	if ce, ok := err.(*framework.ContractError); !ok || ce.Code != framework.ERROR_INSUFFICIENT_BALANCE {
+		t.Errorf("Debit() error = %v, want ERROR_INSUFFICIENT_BALANCE", err)
+	}
+
+	balance, _ := ledger.Balance(testNS, alice, "")
+	if balance != 100 {
+		t.Errorf("Balance() = %d, want 100", balance)
+	}
+	total, _ := ledger.TotalLiabilities(testNS, "")
+	if total != 100 {
+		t.Errorf("TotalLiabilities() = %d, want 100", total)
+	}
+
+	if err := ledger.Transfer(testNS, alice, testAddr(2), "", 101); err == nil {
+		t.Error("Transfer() above balance should fail")
+	}
+}
+
+// TestCreditOverflowRejected 测试余额溢出被拒绝
+func TestCreditOverflowRejected(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	if err := ledger.Credit(testNS, testAddr(1), "", framework.Amount(^uint64(0))); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := ledger.Credit(testNS, testAddr(2), "", 1); err == nil {
+		t.Error("Credit() overflowing total liabilities should fail")
+	}
+}
+
+// TestKeySeparatorRejected 测试命名空间或代币ID包含 ':' 时被拒绝，
+// 避免 ("a:b", "c") 与 ("a", "b:c") 共用同一总负债状态键
+func TestKeySeparatorRejected(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	if err := ledger.Credit("a", testAddr(1), "b:c", 100); err == nil {
+		t.Error("Credit() with ':' in token id should fail")
+	}
+	if err := ledger.Credit("a:b", testAddr(1), "c", 100); err == nil {
+		t.Error("Credit() with ':' in namespace should fail")
+	}
+	if _, err := ledger.TotalLiabilities("a:b", "c"); err == nil {
+		t.Error("TotalLiabilities() with ':' in namespace should fail")
+	}
+	if _, err := ledger.Balance("a", testAddr(1), "b:c"); err == nil {
+		t.Error("Balance() with ':' in token id should fail")
+	}
+
+	if err := ledger.Credit("a_b", testAddr(1), "c", 100); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if total, _ := ledger.TotalLiabilities("a", "b_c"); total != 0 {
+		t.Errorf("TotalLiabilities(a, b_c) = %d, want 0", total)
+	}
+}
+
+// TestLiabilitiesConsistency 测试50次随机操作后总负债等于各子账户余额之和
+func TestLiabilitiesConsistency(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	rng := rand.New(rand.NewSource(707))
+	owners := []framework.Address{testAddr(1), testAddr(2), testAddr(3), testAddr(4)}
+
+	for i := 0; i < 50; i++ {
+		owner := owners[rng.Intn(len(owners))]
+		amount := framework.Amount(rng.Intn(1000) + 1)
+		switch rng.Intn(3) {
+		case 0:
+			_ = ledger.Credit(testNS, owner, "", amount)
+		case 1:
+			_ = ledger.Debit(testNS, owner, "", amount)
+		case 2:
+			_ = ledger.Transfer(testNS, owner, owners[rng.Intn(len(owners))], "", amount)
+		}
+
+		var sum framework.Amount
+		for _, o := range owners {
+			balance, err := ledger.Balance(testNS, o, "")
+			if err != nil {
+				t.Fatalf("Balance() error = %v", err)
+			}
+			sum += balance
+		}
+		total, err := ledger.TotalLiabilities(testNS, "")
+		if err != nil {
+			t.Fatalf("TotalLiabilities() error = %v", err)
+		}
+		if total != sum {
+			t.Fatalf("op %d: TotalLiabilities() = %d, sum of balances = %d", i, total, sum)
+		}
+	}
+}
+
+// TestConservationCatchesBrokenWithdrawal 测试守恒断言能发现未记账的取款
+func TestConservationCatchesBrokenWithdrawal(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	alice := testAddr(1)
+	contractBalance := framework.Amount(0)
+
+	// 正常存款：资金入账并记入台账
+	contractBalance += 500
+	if err := ledger.Credit(testNS, alice, "", 500); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := ledger.AssertConservation(testNS, "", contractBalance); err != nil {
+		t.Fatalf("AssertConservation() after deposit error = %v", err)
+	}
+
+	// 正常取款：先扣台账再转出
+	if err := ledger.Debit(testNS, alice, "", 200); err != nil {
+		t.Fatalf("Debit() error = %v", err)
+	}
+	contractBalance -= 200
+	if err := ledger.AssertConservation(testNS, "", contractBalance); err != nil {
+		t.Fatalf("AssertConservation() after withdrawal error = %v", err)
+	}
+
+	// 错误的取款：转出资金但忘记扣减台账
+	contractBalance -= 100
+	err := ledger.AssertConservation(testNS, "", contractBalance)
+	if err == nil {
+		t.Fatal("AssertConservation() should catch broken withdrawal")
+	}
+	if ce, ok := err.(*framework.ContractError); !ok || ce.Code != framework.ERROR_INVALID_STATE {
+		t.Errorf("AssertConservation() error = %v, want ERROR_INVALID_STATE", err)
+	}
+}
+
+// TestDecodeAmountTrimmed 测试尾部零字节被截断后仍能正确解码
+func TestDecodeAmountTrimmed(t *testing.T) {
+	encoded := encodeAmount(256)
+	if got := decodeAmount(encoded[:7]); got != 256 {
+		t.Errorf("decodeAmount(trimmed) = %d, want 256", got)
+	}
+}
+
+// TestLiabilitySourceSweep 测试以总负债为协议资金时，仅误转入的资金可被清扫
+func TestLiabilitySourceSweep(t *testing.T) {
+	ledger := NewLedger(NewMemoryStore())
+	owner := testAddr(9)
+	if err := ledger.Credit(testNS, testAddr(1), "", 300); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := ledger.Credit(testNS, testAddr(2), "", 200); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+
+	// 存款 500 + 误转入 40
+	contractBalance := framework.Amount(540)
+	swept, err := framework.PlanSweep(owner, owner, contractBalance, "", ledger.LiabilitySource(testNS))
+	if err != nil {
+		t.Fatalf("PlanSweep() error = %v", err)
+	}
+	if swept != 40 {
+		t.Errorf("swept = %d, want 40", swept)
+	}
+	if err := ledger.AssertConservation(testNS, "", contractBalance-swept); err != nil {
+		t.Errorf("protocol funds not preserved after sweep: %v", err)
+	}
+
+	// 清扫后不再有多余资金
+	if _, err := framework.PlanSweep(owner, owner, contractBalance-swept, "", ledger.LiabilitySource(testNS)); err == nil {
+		t.Error("second sweep should find no excess")
+	}
+}
@@ -0,0 +1,35 @@
+package subaccount
+
+// MemoryStore 内存存储后端
+//
+// 🎯 **用途**：用于非WASM环境下的单元测试与离线模拟
+type MemoryStore struct {
+	values   map[string][]byte
+	versions map[string]uint64
+}
+
+// NewMemoryStore 创建内存存储后端
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		values:   make(map[string][]byte),
+		versions: make(map[string]uint64),
+	}
+}
+
+// Load 读取状态（不存在时返回 nil, 0, nil）
+func (m *MemoryStore) Load(key string) ([]byte, uint64, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return nil, 0, nil
+	}
+	return value, m.versions[key], nil
+}
+
+// Save 写入状态
+func (m *MemoryStore) Save(key string, version uint64, value []byte) error {
+	stored := make([]byte, len(value))
+	copy(stored, value)
+	m.values[key] = stored
+	m.versions[key] = version
+	return nil
+}
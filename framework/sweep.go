@@ -0,0 +1,58 @@
+package framework
+
+// ==================== 余额清扫 ====================
+//
+// 资金池类合约（借贷、AMM、流动性池、保险）的合约地址上可能收到误转入的资金。
+// 清扫只允许转出“合约余额 - 协议资金”的差额，协议资金由合约自身的账本给出，
+// 所有者无法借清扫转走用户或协议拥有的资金。
+
+// ProtocolBalanceSource 协议资金来源
+//
+// 🎯 **用途**：由合约提供某代币上协议拥有的资金总额（用户存款、准备金、未结清负债等），
+// 清扫时这部分资金始终保留在合约地址上
+//
+// **约定**：返回错误时不进行清扫
+type ProtocolBalanceSource interface {
+	ProtocolBalance(tokenID TokenID) (Amount, error)
+}
+
+// ExcessBalance 返回合约余额超出协议资金的部分
+//
+// 协议资金不小于合约余额时返回 0（账本记录的资金多于实际余额不会被视为可清扫）
+func ExcessBalance(contractBalance, protocolBalance Amount) Amount {
+	if contractBalance <= protocolBalance {
+		return 0
+	}
+	return contractBalance - protocolBalance
+}
+
+// PlanSweep 校验清扫权限并计算可清扫金额
+//
+// 🎯 **用途**：SweepExcess 的核心逻辑，不依赖宿主函数，便于测试
+//
+// **参数**：
+//   - caller: 调用者地址，须为 owner
+//   - owner: 合约所有者，零地址视为未设置，任何调用者都无权清扫
+//   - contractBalance: 合约地址当前余额
+//   - tokenID: 代币ID
+//   - source: 协议资金来源，未提供时拒绝清扫（不会把全部余额视为多余）
+//
+// **返回**：可清扫金额；无权限返回 ERROR_UNAUTHORIZED，无多余资金返回 ERROR_INVALID_STATE
+func PlanSweep(caller, owner Address, contractBalance Amount, tokenID TokenID, source ProtocolBalanceSource) (Amount, error) {
+	if owner == (Address{}) || caller != owner {
+		return 0, NewContractError(ERROR_UNAUTHORIZED, "only owner can sweep excess balance")
+	}
+	if source == nil {
+		return 0, NewContractError(ERROR_INVALID_STATE, "protocol balance source is not configured")
+	}
+
+	protocolBalance, err := source.ProtocolBalance(tokenID)
+	if err != nil {
+		return 0, err
+	}
+	excess := ExcessBalance(contractBalance, protocolBalance)
+	if excess == 0 {
+		return 0, NewContractError(ERROR_INVALID_STATE, "no excess balance to sweep")
+	}
+	return excess, nil
+}
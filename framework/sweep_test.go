@@ -0,0 +1,74 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"errors"
+	"testing"
+)
+
+// fixedProtocolBalance 固定的协议资金表
+type fixedProtocolBalance map[TokenID]Amount
+
+func (f fixedProtocolBalance) ProtocolBalance(tokenID TokenID) (Amount, error) {
+	return f[tokenID], nil
+}
+
+type failingProtocolBalance struct{}
+
+func (failingProtocolBalance) ProtocolBalance(TokenID) (Amount, error) {
+	return 0, errors.New("ledger unavailable")
+}
+
+// TestPlanSweepOnlyExcess 测试仅清扫多余资金，协议资金保留
+func TestPlanSweepOnlyExcess(t *testing.T) {
+	owner := Address{0x01}
+	source := fixedProtocolBalance{"": 700, "USDT": 1000}
+
+	tests := []struct {
+		name     string
+		tokenID  TokenID
+		balance  Amount
+		want     Amount
+		wantCode uint32
+	}{
+		{"stray funds", "", 750, 50, SUCCESS},
+		{"token stray funds", "USDT", 1001, 1, SUCCESS},
+		{"no excess", "", 700, 0, ERROR_INVALID_STATE},
+		{"balance below protocol", "USDT", 900, 0, ERROR_INVALID_STATE},
+		{"untracked token", "OTHER", 30, 30, SUCCESS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanSweep(owner, owner, tt.balance, tt.tokenID, source)
+			if quotaErrCode(err) != tt.wantCode {
+				t.Fatalf("PlanSweep() error = %v, want code %d", err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("PlanSweep() = %d, want %d", got, tt.want)
+			}
+			if remaining := tt.balance - got; remaining < source[tt.tokenID] && tt.balance >= source[tt.tokenID] {
+				t.Errorf("remaining %d below protocol balance %d", remaining, source[tt.tokenID])
+			}
+		})
+	}
+}
+
+// TestPlanSweepGuards 测试所有者校验与协议资金来源缺失时拒绝清扫
+func TestPlanSweepGuards(t *testing.T) {
+	owner := Address{0x01}
+	source := fixedProtocolBalance{"": 100}
+
+	if _, err := PlanSweep(Address{0x02}, owner, 500, "", source); quotaErrCode(err) != ERROR_UNAUTHORIZED {
+		t.Errorf("non-owner error = %v, want ERROR_UNAUTHORIZED", err)
+	}
+	if _, err := PlanSweep(Address{}, Address{}, 500, "", source); quotaErrCode(err) != ERROR_UNAUTHORIZED {
+		t.Errorf("unset owner error = %v, want ERROR_UNAUTHORIZED", err)
+	}
+	if _, err := PlanSweep(owner, owner, 500, "", nil); quotaErrCode(err) != ERROR_INVALID_STATE {
+		t.Errorf("nil source error = %v, want ERROR_INVALID_STATE", err)
+	}
+	if got, err := PlanSweep(owner, owner, 500, "", failingProtocolBalance{}); err == nil || got != 0 {
+		t.Errorf("failing source = (%d, %v), want error", got, err)
+	}
+}
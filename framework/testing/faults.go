@@ -0,0 +1,218 @@
+//go:build !tinygo && !(js && wasm)
+
+// Package testing 提供非WASM环境下的合约测试工具。
+//
+//   - 宿主故障注入（FaultPlan / RunWithFaults）：在占位宿主函数处注入失败，
+//     覆盖合约中 malloc 返回 0、append_state_output 返回 0xFFFFFFFF、emit_event 失败等
+//     平时从未执行过的错误分支
+//   - 场景运行器（Scenario）：以多个参与方身份按脚本调用导出函数，
+//     在步骤之间延续模拟时钟与状态，覆盖跨导出函数的完整业务流程
+package testing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// faultRule 故障规则
+type faultRule struct {
+	kind      string
+	call      string
+	nth       int
+	minSize   uint32
+	eventName string
+}
+
+func (r faultRule) String() string {
+	switch r.kind {
+	case "nth":
+		return fmt.Sprintf("FailNthCall(%q, %d)", r.call, r.nth)
+	case "alloc":
+		return fmt.Sprintf("FailAllocationsAbove(%d)", r.minSize)
+	default:
+		return fmt.Sprintf("FailEmitEventMatching(%q)", r.eventName)
+	}
+}
+
+// Fault 一次已触发的故障
+type Fault struct {
+	// Call 触发故障的宿主调用
+	Call framework.HostCall
+	// N 该宿主函数在本次运行中的调用序号（从1开始）
+	N int
+	// Rule 命中的规则描述
+	Rule string
+	// Err 注入的结构化错误
+	Err *framework.ContractError
+}
+
+// FaultPlan 宿主故障注入计划
+//
+// 🎯 **用途**：描述在哪些宿主调用处注入失败，安装到占位宿主后生效。
+//
+// **示例**：
+//
+//	plan := testing.NewFaultPlan().FailNthCall(framework.HOST_CALL_APPEND_STATE_OUTPUT, 3)
+//	res := testing.RunWithFaults(t, plan, func() uint32 { return PayContribution() })
+type FaultPlan struct {
+	rules        []faultRule
+	counts       map[string]int
+	triggered    []Fault
+	stagedAtFail int
+}
+
+// NewFaultPlan 创建空的故障注入计划（不注入任何故障，仅统计调用次数）
+func NewFaultPlan() *FaultPlan {
+	return &FaultPlan{counts: make(map[string]int), stagedAtFail: -1}
+}
+
+// FailNthCall 第 n 次（从1开始）调用宿主函数 call 时失败
+func (p *FaultPlan) FailNthCall(call string, n int) *FaultPlan {
+	p.rules = append(p.rules, faultRule{kind: "nth", call: call, nth: n})
+	return p
+}
+
+// FailAllocationsAbove 分配字节数超过 bytes 的 malloc 调用失败
+func (p *FaultPlan) FailAllocationsAbove(bytes uint32) *FaultPlan {
+	p.rules = append(p.rules, faultRule{kind: "alloc", call: framework.HOST_CALL_MALLOC, minSize: bytes})
+	return p
+}
+
+// FailEmitEventMatching 发出名称为 name 的事件时失败
+func (p *FaultPlan) FailEmitEventMatching(name string) *FaultPlan {
+	p.rules = append(p.rules, faultRule{kind: "event", call: framework.HOST_CALL_EMIT_EVENT, eventName: name})
+	return p
+}
+
+// BeforeHostCall 实现 framework.HostInterceptor
+func (p *FaultPlan) BeforeHostCall(call framework.HostCall) error {
+	p.counts[call.Name]++
+	n := p.counts[call.Name]
+
+	for _, r := range p.rules {
+		if r.call != call.Name || !r.matches(call, n) {
+			continue
+		}
+		err := framework.NewContractError(framework.ERROR_EXECUTION_FAILED,
+			fmt.Sprintf("injected fault: %s call #%d (%s)", call.Name, n, r))
+		if p.stagedAtFail < 0 {
+			p.stagedAtFail = len(framework.StagedStateWrites())
+		}
+		p.triggered = append(p.triggered, Fault{Call: call, N: n, Rule: r.String(), Err: err})
+		return err
+	}
+	return nil
+}
+
+func (r faultRule) matches(call framework.HostCall, n int) bool {
+	switch r.kind {
+	case "nth":
+		return n == r.nth
+	case "alloc":
+		return call.Size > r.minSize
+	default:
+		return call.EventName == r.eventName
+	}
+}
+
+// Triggered 返回已触发的故障
+func (p *FaultPlan) Triggered() []Fault {
+	return p.triggered
+}
+
+// Calls 返回各宿主函数在本次运行中的调用次数
+func (p *FaultPlan) Calls() map[string]int {
+	out := make(map[string]int, len(p.counts))
+	for k, v := range p.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// TB 测试上下文接口（*testing.T / *testing.B 均满足）
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// FaultResult 一次故障注入运行的结果
+type FaultResult struct {
+	// Code 导出函数返回码
+	Code uint32
+	// Faults 已触发的故障
+	Faults []Fault
+	// StagedBeforeFault 首次故障前已暂存的状态输出（调用失败后由宿主整体丢弃）
+	StagedBeforeFault []string
+	// StagedAfterFault 首次故障后仍继续暂存的状态输出（吞掉错误继续执行的信号）
+	StagedAfterFault []string
+}
+
+// CountHostCalls 在不注入故障的情况下运行 fn，返回各宿主函数的调用次数
+//
+// 🎯 **用途**：枚举导出函数的全部宿主调用点，配合 FailNthCall 逐一注入故障。
+//
+// **示例**：
+//
+//	calls := testing.CountHostCalls(func() uint32 { return Payout() })
+//	for n := 1; n <= calls[framework.HOST_CALL_APPEND_STATE_OUTPUT]; n++ {
+//	    testing.RunWithFaults(t, testing.NewFaultPlan().FailNthCall(framework.HOST_CALL_APPEND_STATE_OUTPUT, n), Payout)
+//	}
+func CountHostCalls(fn func() uint32) map[string]int {
+	plan := NewFaultPlan()
+	framework.ResetStagedWrites()
+	restore := framework.SetHostInterceptor(plan)
+	defer func() {
+		restore()
+		framework.ResetStagedWrites()
+	}()
+	fn()
+	return plan.Calls()
+}
+
+// RunWithFaults 安装故障计划运行导出函数，并断言错误路径行为正确
+//
+// 断言：
+//  1. 至少触发了一次故障（否则计划未命中任何调用点，测试无意义）
+//  2. 导出函数返回非 SUCCESS 码（吞掉宿主错误返回成功会提交不完整的状态）
+//  3. 首次故障后没有继续暂存状态输出（不在失败后继续写入成员/轮次状态）
+//
+// 运行结束后清空暂存记录，模拟宿主在调用失败时整体丢弃本次输出。
+func RunWithFaults(t TB, plan *FaultPlan, fn func() uint32) FaultResult {
+	t.Helper()
+
+	framework.ResetStagedWrites()
+	restore := framework.SetHostInterceptor(plan)
+	code := fn()
+	restore()
+	staged := framework.StagedStateWrites()
+	framework.ResetStagedWrites()
+
+	res := FaultResult{Code: code, Faults: plan.Triggered()}
+	if plan.stagedAtFail >= 0 && plan.stagedAtFail <= len(staged) {
+		res.StagedBeforeFault = staged[:plan.stagedAtFail]
+		res.StagedAfterFault = staged[plan.stagedAtFail:]
+	}
+
+	if len(res.Faults) == 0 {
+		t.Errorf("fault plan %s never triggered; host calls: %v", plan.describe(), plan.Calls())
+		return res
+	}
+	if code == framework.SUCCESS {
+		t.Errorf("export returned SUCCESS after injected fault: %s", res.Faults[0].Err.Message)
+	}
+	if len(res.StagedAfterFault) > 0 {
+		t.Errorf("export kept staging writes after injected fault %q: %s",
+			res.Faults[0].Err.Message, strings.Join(res.StagedAfterFault, ", "))
+	}
+	return res
+}
+
+func (p *FaultPlan) describe() string {
+	parts := make([]string, len(p.rules))
+	for i, r := range p.rules {
+		parts[i] = r.String()
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
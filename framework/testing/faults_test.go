@@ -0,0 +1,137 @@
+//go:build !tinygo && !(js && wasm)
+
+package testing
+
+import (
+	"fmt"
+	"strings"
+	gotesting "testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// recordingTB 记录断言失败，用于验证 RunWithFaults 本身的判定
+type recordingTB struct {
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// wellBehavedExport 每次宿主调用失败都立即返回错误码
+func wellBehavedExport() uint32 {
+	if framework.Malloc(64) == 0 {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	for _, key := range []string{"member_a", "round_1", "member_month_stat_a"} {
+		if _, err := framework.AppendStateOutputSimple([]byte(key), 1, []byte("v"), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+	if err := framework.EmitEvent(framework.NewEvent("ContributionPaid")); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// swallowingExport 忽略状态写入错误继续执行（错误示例）
+func swallowingExport() uint32 {
+	for _, key := range []string{"member_a", "round_1", "member_month_stat_a"} {
+		framework.AppendStateOutputSimple([]byte(key), 1, []byte("v"), nil)
+	}
+	framework.EmitEvent(framework.NewEvent("ContributionPaid"))
+	return framework.SUCCESS
+}
+
+func TestCountHostCalls(t *gotesting.T) {
+	calls := CountHostCalls(wellBehavedExport)
+
+	if calls[framework.HOST_CALL_APPEND_STATE_OUTPUT] != 3 {
+		t.Errorf("append_state_output calls = %d, want 3", calls[framework.HOST_CALL_APPEND_STATE_OUTPUT])
+	}
+	// 1 次显式分配 + 每次状态输出分配 stateID 与 execHash
+	if calls[framework.HOST_CALL_MALLOC] != 7 {
+		t.Errorf("malloc calls = %d, want 7", calls[framework.HOST_CALL_MALLOC])
+	}
+	if calls[framework.HOST_CALL_EMIT_EVENT] != 1 {
+		t.Errorf("emit_event calls = %d, want 1", calls[framework.HOST_CALL_EMIT_EVENT])
+	}
+	if len(framework.StagedStateWrites()) != 0 {
+		t.Error("CountHostCalls should not leave staged writes behind")
+	}
+}
+
+// TestFailEachHostCallSite 在每个宿主调用点注入故障，错误路径均应正确返回
+func TestFailEachHostCallSite(t *gotesting.T) {
+	calls := CountHostCalls(wellBehavedExport)
+	for _, name := range []string{framework.HOST_CALL_MALLOC, framework.HOST_CALL_APPEND_STATE_OUTPUT, framework.HOST_CALL_EMIT_EVENT} {
+		for n := 1; n <= calls[name]; n++ {
+			t.Run(fmt.Sprintf("%s#%d", name, n), func(t *gotesting.T) {
+				res := RunWithFaults(t, NewFaultPlan().FailNthCall(name, n), wellBehavedExport)
+				if len(res.Faults) != 1 || res.Faults[0].Err.Code != framework.ERROR_EXECUTION_FAILED {
+					t.Errorf("faults = %+v, want one structured ERROR_EXECUTION_FAILED", res.Faults)
+				}
+			})
+		}
+	}
+}
+
+func TestFailNthAppendStateOutput(t *gotesting.T) {
+	res := RunWithFaults(t, NewFaultPlan().FailNthCall(framework.HOST_CALL_APPEND_STATE_OUTPUT, 3), wellBehavedExport)
+
+	if res.Code != framework.ERROR_EXECUTION_FAILED {
+		t.Errorf("code = %d, want ERROR_EXECUTION_FAILED", res.Code)
+	}
+	if got := strings.Join(res.StagedBeforeFault, ","); got != "member_a,round_1" {
+		t.Errorf("staged before fault = %q, want member_a,round_1", got)
+	}
+	if string(res.Faults[0].Call.StateID) != "member_month_stat_a" {
+		t.Errorf("fault state id = %q, want member_month_stat_a", res.Faults[0].Call.StateID)
+	}
+}
+
+func TestFailAllocationsAbove(t *gotesting.T) {
+	res := RunWithFaults(t, NewFaultPlan().FailAllocationsAbove(32), wellBehavedExport)
+	if res.Faults[0].Call.Size != 64 {
+		t.Errorf("failed allocation size = %d, want 64", res.Faults[0].Call.Size)
+	}
+
+	// 32字节以内的分配不受影响
+	tb := &recordingTB{}
+	RunWithFaults(tb, NewFaultPlan().FailAllocationsAbove(64), wellBehavedExport)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "never triggered") {
+		t.Errorf("errors = %v, want plan never triggered", tb.errors)
+	}
+}
+
+func TestFailEmitEventMatching(t *gotesting.T) {
+	res := RunWithFaults(t, NewFaultPlan().FailEmitEventMatching("ContributionPaid"), wellBehavedExport)
+	if res.Faults[0].Call.EventName != "ContributionPaid" {
+		t.Errorf("failed event = %q, want ContributionPaid", res.Faults[0].Call.EventName)
+	}
+	if len(res.StagedBeforeFault) != 3 || len(res.StagedAfterFault) != 0 {
+		t.Errorf("staged before/after = %v/%v", res.StagedBeforeFault, res.StagedAfterFault)
+	}
+}
+
+// TestRunWithFaultsDetectsSwallowedErrors 吞掉宿主错误的导出函数应被判定失败
+func TestRunWithFaultsDetectsSwallowedErrors(t *gotesting.T) {
+	tb := &recordingTB{}
+	res := RunWithFaults(tb, NewFaultPlan().FailNthCall(framework.HOST_CALL_APPEND_STATE_OUTPUT, 1), swallowingExport)
+
+	if len(tb.errors) != 2 {
+		t.Fatalf("errors = %v, want SUCCESS and staged-after-fault failures", tb.errors)
+	}
+	if !strings.Contains(tb.errors[0], "returned SUCCESS") {
+		t.Errorf("errors[0] = %q, want returned SUCCESS", tb.errors[0])
+	}
+	if !strings.Contains(tb.errors[1], "round_1, member_month_stat_a") {
+		t.Errorf("errors[1] = %q, want staged writes listed", tb.errors[1])
+	}
+	if len(res.StagedAfterFault) != 2 {
+		t.Errorf("staged after fault = %v, want 2 writes", res.StagedAfterFault)
+	}
+}
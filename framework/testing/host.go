@@ -0,0 +1,90 @@
+//go:build !tinygo && !(js && wasm)
+
+package testing
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+// ==================== 单元测试宿主 ====================
+//
+// Host 是可设置的内存宿主（framework.MockHost），面向单个导出函数或 helpers 函数的单元测试：
+// 预设调用者、区块时间、余额、链上状态与受控外部状态（ISPC）响应后直接调用被测函数。
+// 占位宿主函数（GetCaller、GetTimestamp、QueryUTXOBalance、GetState / GetStateFromChain、
+// DeclareExternalState / ProvideEvidence / QueryControlledState 等）读写该宿主。
+// 跨多个导出函数、多个参与方的流程使用 Scenario。
+
+// HostTB Host 使用的测试上下文（*testing.T 满足）
+type HostTB interface {
+	Helper()
+	Cleanup(func())
+}
+
+// Host 单元测试宿主
+//
+// 🎯 **用途**：为被测函数提供可控的宿主环境，Call / Run 以当前调用者身份执行一次调用
+//
+// **示例**：
+//
+//	host := testing.NewHost(t).SetCaller(fixtures.Alice())
+//	host.SetBalance(fixtures.Alice(), "USDT", 1000)
+//	host.SetState("config", []byte("v1"), 1)
+//	host.SetExternalResponse("https://api.example.com/price", []byte(`{"price":42}`))
+//	res := host.Run(func() error { return token.Transfer(fixtures.Alice(), fixtures.Bob(), "USDT", 100) })
+//	if res.Code != framework.SUCCESS { ... }
+type Host struct {
+	*framework.MockHost
+	caller framework.Address
+}
+
+// NewHost 创建并安装单元测试宿主，测试结束时自动卸载
+//
+// 初始区块时间为 fixtures.Epoch，区块高度为 1，合约地址为 fixtures.Pool()，调用者为 fixtures.Alice()。
+func NewHost(t HostTB) *Host {
+	host := framework.NewMockHost()
+	host.ContractAddress = fixtures.Pool()
+	host.Timestamp = fixtures.Epoch
+	host.BlockHeight = 1
+	t.Cleanup(framework.InstallMockHost(host))
+	return &Host{MockHost: host, caller: fixtures.Alice()}
+}
+
+// SetCaller 设置后续调用的调用者（GetCaller 的返回值）
+func (h *Host) SetCaller(caller framework.Address) *Host {
+	h.caller = caller
+	return h
+}
+
+// Caller 返回当前调用者
+func (h *Host) Caller() framework.Address {
+	return h.caller
+}
+
+// SetTimestamp 设置区块时间（GetTimestamp 的返回值）
+func (h *Host) SetTimestamp(ts uint64) *Host {
+	h.Timestamp = ts
+	return h
+}
+
+// Call 以当前调用者身份执行导出函数，params 为 JSON 参数（可为空）
+//
+// **返回**：调用结果；返回 SUCCESS 时状态输出已提交，否则状态输出已丢弃、资产转移已回滚
+func (h *Host) Call(fn func() uint32, params string) framework.MockCallResult {
+	return h.Invoke(h.caller, []byte(params), fn)
+}
+
+// Run 以当前调用者身份执行返回 error 的函数（helpers 函数），错误按 ContractError 的错误码映射
+//
+// **返回**：调用结果，Code 为 SUCCESS、ContractError.Code 或 ERROR_EXECUTION_FAILED
+func (h *Host) Run(op func() error) framework.MockCallResult {
+	return h.Invoke(h.caller, nil, func() uint32 {
+		if err := op(); err != nil {
+			if contractErr, ok := err.(*framework.ContractError); ok {
+				return contractErr.Code
+			}
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.SUCCESS
+	})
+}
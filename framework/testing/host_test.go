@@ -0,0 +1,118 @@
+//go:build !tinygo && !(js && wasm)
+
+package testing
+
+import (
+	gotesting "testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+// counterExport 示例导出函数：调用者每次调用计数加一，余额不足 100 时拒绝
+func counterExport() uint32 {
+	caller := framework.GetCaller()
+	if framework.QueryUTXOBalance(caller, "USDT") < 100 {
+		return framework.ERROR_INSUFFICIENT_BALANCE
+	}
+	key := "counter_" + caller.ToString()
+	data, version, _ := framework.GetStateFromChain([]byte(key))
+	next := framework.ParseUint64(string(data)) + 1
+	if _, err := framework.AppendStateOutputSimple([]byte(key), version+1, []byte(framework.Uint64ToString(next)), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// TestHostStateThroughMock 测试预设状态可由 GetState / GetStateFromChain 读取，调用成功后写入对之后的调用可见
+func TestHostStateThroughMock(t *gotesting.T) {
+	host := NewHost(t)
+	host.SetState("config", []byte("v1"), 3)
+
+	if value, err := framework.GetState("config"); err != nil || string(value) != "v1" {
+		t.Errorf("GetState() = %q, %v, want v1", value, err)
+	}
+	if value, version, err := framework.GetStateFromChain([]byte("config")); err != nil || string(value) != "v1" || version != 3 {
+		t.Errorf("GetStateFromChain() = %q, %d, %v, want v1 at version 3", value, version, err)
+	}
+	if _, _, err := framework.GetStateFromChain([]byte("missing")); err == nil {
+		t.Error("GetStateFromChain(missing) error = nil, want ERROR_NOT_FOUND")
+	}
+
+	host.SetBalance(fixtures.Alice(), "USDT", 500)
+	for i := 0; i < 2; i++ {
+		if res := host.Call(counterExport, ""); res.Code != framework.SUCCESS {
+			t.Fatalf("call %d code = %d", i+1, res.Code)
+		}
+	}
+	key := "counter_" + fixtures.Alice().ToString()
+	if value, version, ok := host.State(key); !ok || string(value) != "2" || version != 2 {
+		t.Errorf("State(%s) = %q, %d, %v, want 2 at version 2", key, value, version, ok)
+	}
+}
+
+// TestHostBalanceThroughMock 测试预设余额可由 QueryUTXOBalance 读取，调用者与区块时间可设置
+func TestHostBalanceThroughMock(t *gotesting.T) {
+	host := NewHost(t).SetCaller(fixtures.Bob()).SetTimestamp(fixtures.Epoch + 3600)
+	host.SetBalance(fixtures.Bob(), "USDT", 50)
+
+	if got := framework.QueryUTXOBalance(fixtures.Bob(), "USDT"); got != 50 {
+		t.Errorf("QueryUTXOBalance() = %d, want 50", got)
+	}
+	if got := framework.QueryUTXOBalance(fixtures.Carol(), "USDT"); got != 0 {
+		t.Errorf("QueryUTXOBalance(unfunded) = %d, want 0", got)
+	}
+	if res := host.Call(counterExport, ""); res.Code != framework.ERROR_INSUFFICIENT_BALANCE {
+		t.Errorf("underfunded call code = %d, want ERROR_INSUFFICIENT_BALANCE", res.Code)
+	}
+
+	// 转账在调用成功时提交
+	res := host.Run(func() error {
+		if framework.GetCaller() != fixtures.Bob() || framework.GetTimestamp() != fixtures.Epoch+3600 {
+			return framework.NewContractError(framework.ERROR_INVALID_STATE, "unexpected context")
+		}
+		return framework.CreateUTXO(fixtures.Bob(), 70, "USDT")
+	})
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("Run() code = %d", res.Code)
+	}
+	if got := framework.QueryUTXOBalance(fixtures.Bob(), "USDT"); got != 120 {
+		t.Errorf("QueryUTXOBalance() after mint = %d, want 120", got)
+	}
+}
+
+// TestHostExternalStateResponses 测试受控外部状态按数据源返回预设响应，未提供佐证时拒绝
+func TestHostExternalStateResponses(t *gotesting.T) {
+	host := NewHost(t)
+	const source = "https://api.example.com/price"
+	host.SetExternalResponse(source, []byte(`{"price":42}`))
+
+	query := func(withEvidence bool, src string) ([]byte, error) {
+		claimID, err := framework.DeclareExternalState(&framework.ExternalStateClaim{ClaimType: "api_response", Source: src})
+		if err != nil {
+			return nil, err
+		}
+		if withEvidence {
+			evidence := &framework.Evidence{ClaimID: claimID, APISignature: []byte("sig"), ResponseHash: []byte("hash")}
+			if err := framework.ProvideEvidence(claimID, evidence); err != nil {
+				return nil, err
+			}
+		}
+		return framework.QueryControlledState(claimID)
+	}
+
+	var data []byte
+	res := host.Run(func() (err error) {
+		data, err = query(true, source)
+		return err
+	})
+	if res.Code != framework.SUCCESS || string(data) != `{"price":42}` {
+		t.Errorf("QueryControlledState() = %s, code %d, want preset response", data, res.Code)
+	}
+	if res := host.Run(func() error { _, err := query(false, source); return err }); res.Code != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("query without evidence code = %d, want ERROR_PERMISSION_DENIED", res.Code)
+	}
+	if res := host.Run(func() error { _, err := query(true, "https://unknown.example.com"); return err }); res.Code != framework.ERROR_NOT_FOUND {
+		t.Errorf("query unknown source code = %d, want ERROR_NOT_FOUND", res.Code)
+	}
+}
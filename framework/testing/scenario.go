@@ -0,0 +1,320 @@
+//go:build !tinygo && !(js && wasm)
+
+package testing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+// ==================== 场景运行器 ====================
+//
+// Scenario 按脚本顺序以不同参与方身份调用导出函数，在步骤之间延续模拟时钟、
+// 调用者与链上状态（framework.MockHost），记录每一步的返回值、事件、状态输出与资产转移。
+// 期望不符时报告偏离的步骤及此前的完整步骤记录。
+//
+// 时间只由 AdvanceTime 推进，与运行测试时的真实时间无关。
+
+// Exports 导出函数表：导出名 → 导出函数
+type Exports map[string]func() uint32
+
+// ScenarioTB 场景运行使用的测试上下文（*testing.T 满足）
+type ScenarioTB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+// Scenario 多参与方脚本化场景
+//
+// 🎯 **用途**：覆盖跨多个导出函数、多个参与方、跨越模拟时间的业务流程
+//
+// **示例**：
+//
+//	s := testing.NewScenario(t, testing.Exports{"Initialize": Initialize, "SubmitClaim": SubmitClaim})
+//	s.As(fixtures.Operator()).Call("Initialize", planParams).ExpectSuccess()
+//	s.AdvanceTime(fixtures.Days(30))
+//	s.As(fixtures.Alice()).Call("SubmitClaim", claimParams).ExpectError(framework.ERROR_INVALID_STATE)
+type Scenario struct {
+	t       ScenarioTB
+	host    *framework.MockHost
+	exports Exports
+	start   uint64
+	steps   []*Step
+}
+
+// ScenarioSnapshot 场景快照（见 Snapshot / Restore）
+type ScenarioSnapshot struct {
+	host  *framework.MockHostSnapshot
+	steps []*Step
+}
+
+// NewScenario 创建场景并安装内存宿主，测试结束时自动卸载
+//
+// 初始区块时间为 fixtures.Epoch，区块高度为 1，合约地址为 fixtures.Pool()。
+func NewScenario(t ScenarioTB, exports Exports) *Scenario {
+	host := framework.NewMockHost()
+	host.ContractAddress = fixtures.Pool()
+	host.Timestamp = fixtures.Epoch
+	host.BlockHeight = 1
+	t.Cleanup(framework.InstallMockHost(host))
+	return &Scenario{t: t, host: host, exports: exports, start: host.Timestamp}
+}
+
+// Host 返回场景使用的内存宿主（准备前置状态或检查已提交状态）
+func (s *Scenario) Host() *framework.MockHost {
+	return s.host
+}
+
+// Now 返回当前模拟区块时间
+func (s *Scenario) Now() uint64 {
+	return s.host.Timestamp
+}
+
+// AdvanceTime 推进模拟时间 d 秒，区块高度加 1
+func (s *Scenario) AdvanceTime(d uint64) *Scenario {
+	s.host.Timestamp += d
+	s.host.BlockHeight++
+	s.steps = append(s.steps, &Step{s: s, Index: len(s.steps) + 1, Time: s.host.Timestamp, note: "advance time " + formatDuration(d)})
+	return s
+}
+
+// Fund 设置地址的代币余额
+func (s *Scenario) Fund(addr framework.Address, tokenID framework.TokenID, amount framework.Amount) *Scenario {
+	s.host.SetBalance(addr, tokenID, amount)
+	s.steps = append(s.steps, &Step{s: s, Index: len(s.steps) + 1, Time: s.host.Timestamp,
+		note: fmt.Sprintf("fund %s with %d %s", fixtures.Name(addr), amount, tokenLabel(tokenID))})
+	return s
+}
+
+// As 以 actor 身份发起后续调用
+func (s *Scenario) As(actor framework.Address) *Actor {
+	return &Actor{s: s, addr: actor}
+}
+
+// Snapshot 保存当前状态与步骤记录，Restore 后可从同一前缀分出不同分支
+func (s *Scenario) Snapshot() ScenarioSnapshot {
+	return ScenarioSnapshot{host: s.host.Snapshot(), steps: append([]*Step(nil), s.steps...)}
+}
+
+// Restore 恢复到快照时的状态与步骤记录；同一快照可多次恢复
+func (s *Scenario) Restore(snap ScenarioSnapshot) *Scenario {
+	s.host.Restore(snap.host)
+	s.steps = append([]*Step(nil), snap.steps...)
+	return s
+}
+
+// Steps 返回已执行的步骤
+func (s *Scenario) Steps() []*Step {
+	return s.steps
+}
+
+// Actor 场景参与方
+type Actor struct {
+	s    *Scenario
+	addr framework.Address
+}
+
+// Call 以该参与方身份调用导出函数，params 为 JSON 参数（可为空）
+func (a *Actor) Call(method string, params string) *Step {
+	s := a.s
+	s.t.Helper()
+	step := &Step{s: s, Index: len(s.steps) + 1, Time: s.host.Timestamp, Actor: a.addr, Method: method, Params: params, call: true}
+	s.steps = append(s.steps, step)
+
+	fn, ok := s.exports[method]
+	if !ok {
+		s.t.Fatalf("%s", s.report(step, fmt.Sprintf("unknown export %q", method)))
+		return step
+	}
+	step.Result = s.host.Invoke(a.addr, []byte(params), fn)
+	return step
+}
+
+// Step 场景中的一步
+type Step struct {
+	// Index 步骤序号（从1开始）
+	Index int
+	// Time 执行时的区块时间
+	Time uint64
+	// Actor 调用者
+	Actor framework.Address
+	// Method 导出函数名
+	Method string
+	// Params JSON 参数
+	Params string
+	// Result 调用结果（返回码、返回值、事件、状态输出、资产转移）
+	Result framework.MockCallResult
+
+	s    *Scenario
+	call bool
+	note string
+}
+
+// ExpectSuccess 断言调用返回 SUCCESS
+func (st *Step) ExpectSuccess() *Step {
+	st.s.t.Helper()
+	return st.ExpectError(framework.SUCCESS)
+}
+
+// ExpectError 断言调用返回指定错误码
+func (st *Step) ExpectError(code uint32) *Step {
+	st.s.t.Helper()
+	if st.Result.Code != code {
+		st.fail(fmt.Sprintf("expected %s, got %s", codeLabel(code), codeLabel(st.Result.Code)))
+	}
+	return st
+}
+
+// ExpectEvent 断言调用发出了指定事件
+func (st *Step) ExpectEvent(name string) *Step {
+	st.s.t.Helper()
+	if _, ok := st.Event(name); !ok {
+		st.fail(fmt.Sprintf("expected event %s", name))
+	}
+	return st
+}
+
+// ExpectWrite 断言调用写入了指定状态
+func (st *Step) ExpectWrite(stateID string) *Step {
+	st.s.t.Helper()
+	for _, w := range st.Result.Writes {
+		if w.StateID == stateID {
+			return st
+		}
+	}
+	st.fail(fmt.Sprintf("expected state write %s", stateID))
+	return st
+}
+
+// Expect 断言 check 返回 nil，用于返回值、事件字段等自定义检查
+func (st *Step) Expect(check func(st *Step) error) *Step {
+	st.s.t.Helper()
+	if err := check(st); err != nil {
+		st.fail(err.Error())
+	}
+	return st
+}
+
+// Event 返回本步发出的第一个指定名称的事件
+func (st *Step) Event(name string) (framework.MockEvent, bool) {
+	for _, e := range st.Result.Events {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return framework.MockEvent{}, false
+}
+
+// Return 返回本步的返回数据
+func (st *Step) Return() string {
+	return string(st.Result.Return)
+}
+
+func (st *Step) fail(reason string) {
+	st.s.t.Helper()
+	st.s.t.Fatalf("%s", st.s.report(st, reason))
+}
+
+// String 单行步骤描述
+func (st *Step) String() string {
+	prefix := fmt.Sprintf("step %d [%s]", st.Index, st.s.elapsed(st.Time))
+	if !st.call {
+		return prefix + " " + st.note
+	}
+	line := fmt.Sprintf("%s %s → %s", prefix, fixtures.Name(st.Actor), st.Method)
+	if st.Params != "" {
+		line += " " + st.Params
+	}
+	return line + ": " + codeLabel(st.Result.Code)
+}
+
+// detail 步骤的返回值、事件、状态输出与资产转移
+func (st *Step) detail() string {
+	var b strings.Builder
+	if len(st.Result.Return) > 0 {
+		fmt.Fprintf(&b, "\n      return: %s", st.Result.Return)
+	}
+	for _, e := range st.Result.Events {
+		fmt.Fprintf(&b, "\n      event:  %s", eventLabel(e))
+	}
+	for _, w := range st.Result.Writes {
+		fmt.Fprintf(&b, "\n      write:  %s (v%d, %d bytes)", w.StateID, w.Version, len(w.Value))
+	}
+	for _, tr := range st.Result.Transfers {
+		fmt.Fprintf(&b, "\n      transfer: %s → %s %d %s", fixtures.Name(tr.From), fixtures.Name(tr.To), tr.Amount, tokenLabel(tr.TokenID))
+	}
+	return b.String()
+}
+
+// report 失败报告：偏离的步骤、原因、该步详情与此前的步骤记录
+func (s *Scenario) report(diverged *Step, reason string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "scenario diverged at step %d: %s\n  %s%s\ntranscript:", diverged.Index, reason, diverged, diverged.detail())
+	for _, st := range s.steps {
+		if st == diverged {
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s", st)
+		if st.call && (len(st.Result.Events) > 0 || len(st.Result.Writes) > 0) {
+			fmt.Fprintf(&b, " (%d events, %d writes)", len(st.Result.Events), len(st.Result.Writes))
+		}
+	}
+	return b.String()
+}
+
+// elapsed 相对场景开始的时间，如 T+31d2h
+func (s *Scenario) elapsed(t uint64) string {
+	if t < s.start {
+		return "T-" + formatDuration(s.start-t)
+	}
+	return "T+" + formatDuration(t-s.start)
+}
+
+func formatDuration(d uint64) string {
+	days, rest := d/fixtures.Days(1), d%fixtures.Days(1)
+	hours, secs := rest/fixtures.Hours(1), rest%fixtures.Hours(1)
+	out := ""
+	if days > 0 {
+		out += fmt.Sprintf("%dd", days)
+	}
+	if hours > 0 {
+		out += fmt.Sprintf("%dh", hours)
+	}
+	if secs > 0 || out == "" {
+		out += fmt.Sprintf("%ds", secs)
+	}
+	return out
+}
+
+func codeLabel(code uint32) string {
+	return fmt.Sprintf("%s (%d)", framework.ErrorCodeToString(code), code)
+}
+
+func tokenLabel(tokenID framework.TokenID) string {
+	if framework.IsNativeToken(tokenID) {
+		return "native"
+	}
+	return string(tokenID)
+}
+
+// eventLabel 事件名与按键排序的字段
+func eventLabel(e framework.MockEvent) string {
+	if e.Payload != nil {
+		return fmt.Sprintf("%s %s", e.Name, e.Payload)
+	}
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = fmt.Sprintf("%s=%v", k, e.Data[k])
+	}
+	return e.Name + "{" + strings.Join(fields, " ") + "}"
+}
@@ -0,0 +1,131 @@
+//go:build !tinygo && !(js && wasm)
+
+package testing
+
+import (
+	"fmt"
+	"strings"
+	gotesting "testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+// scenarioRecorder 记录 Fatalf，用于验证失败报告；Cleanup 交给真实的 *testing.T
+type scenarioRecorder struct {
+	t     *gotesting.T
+	fatal []string
+}
+
+func (r *scenarioRecorder) Helper() {}
+
+func (r *scenarioRecorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = append(r.fatal, fmt.Sprintf(format, args...))
+}
+
+func (r *scenarioRecorder) Cleanup(fn func()) { r.t.Cleanup(fn) }
+
+// counterExports 计数器合约：Increment 写入状态并发出事件，Pay 创建输出后失败
+var counterExports = Exports{
+	"Increment": func() uint32 {
+		data, _ := framework.GetState("counter")
+		n := uint64(len(data)) + 1
+		if _, err := framework.AppendStateOutputSimple([]byte("counter"), n, make([]byte, n), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		event := framework.NewEvent("Incremented")
+		event.AddIntField("value", n)
+		event.AddIntField("at", framework.GetTimestamp())
+		framework.EmitEvent(event)
+		return framework.SUCCESS
+	},
+	"Pay": func() uint32 {
+		if err := framework.CreateUTXO(fixtures.Bob(), framework.Amount(10), ""); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.ERROR_INVALID_STATE
+	},
+}
+
+// TestScenarioThreadsStateAndClock 测试步骤间延续状态、调用者与模拟时间
+func TestScenarioThreadsStateAndClock(t *gotesting.T) {
+	s := NewScenario(t, counterExports)
+	s.As(fixtures.Alice()).Call("Increment", "").ExpectSuccess().ExpectWrite("counter")
+	s.AdvanceTime(fixtures.Days(30))
+	step := s.As(fixtures.Bob()).Call("Increment", "").ExpectSuccess().ExpectEvent("Incremented")
+
+	e, _ := step.Event("Incremented")
+	if e.Data["value"] != uint64(2) || e.Data["at"] != fixtures.Epoch+fixtures.Days(30) {
+		t.Errorf("Incremented = %v, want value 2 at T+30d", e.Data)
+	}
+	if step.Actor != fixtures.Bob() || len(s.Steps()) != 3 {
+		t.Errorf("step actor = %s, steps = %d", fixtures.Name(step.Actor), len(s.Steps()))
+	}
+	if s.Host().BlockHeight != 2 {
+		t.Errorf("BlockHeight = %d, want 2", s.Host().BlockHeight)
+	}
+}
+
+// TestScenarioFailedCallRollsBack 测试失败调用的资产转移被回滚
+func TestScenarioFailedCallRollsBack(t *gotesting.T) {
+	s := NewScenario(t, counterExports)
+	s.Fund(fixtures.Bob(), "", 100)
+	step := s.As(fixtures.Alice()).Call("Pay", "").ExpectError(framework.ERROR_INVALID_STATE)
+
+	if len(step.Result.Transfers) != 1 {
+		t.Errorf("transfers = %d, want 1 recorded", len(step.Result.Transfers))
+	}
+	if got := s.Host().Balance(fixtures.Bob(), ""); got != 100 {
+		t.Errorf("bob balance = %d, want 100 after rollback", got)
+	}
+}
+
+// TestScenarioSnapshotRestore 测试同一前缀的两个分支互不影响
+func TestScenarioSnapshotRestore(t *gotesting.T) {
+	s := NewScenario(t, counterExports)
+	s.As(fixtures.Alice()).Call("Increment", "").ExpectSuccess()
+	prefix := s.Snapshot()
+
+	s.AdvanceTime(fixtures.Days(1))
+	s.As(fixtures.Alice()).Call("Increment", "").ExpectSuccess()
+
+	s.Restore(prefix)
+	if s.Now() != fixtures.Epoch || len(s.Steps()) != 1 {
+		t.Fatalf("after Restore: now = %d, steps = %d", s.Now(), len(s.Steps()))
+	}
+	step := s.As(fixtures.Bob()).Call("Increment", "").ExpectSuccess()
+	if e, _ := step.Event("Incremented"); e.Data["value"] != uint64(2) {
+		t.Errorf("branch value = %v, want 2", e.Data["value"])
+	}
+}
+
+// TestScenarioFailureReport 测试失败报告指出偏离的步骤并列出此前的步骤
+func TestScenarioFailureReport(t *gotesting.T) {
+	rec := &scenarioRecorder{t: t}
+	s := NewScenario(rec, counterExports)
+	s.As(fixtures.Alice()).Call("Increment", "")
+	s.AdvanceTime(fixtures.Days(30) + fixtures.Hours(2))
+	s.As(fixtures.Operator()).Call("Increment", `{"k":1}`).ExpectError(framework.ERROR_INVALID_STATE)
+
+	if len(rec.fatal) != 1 {
+		t.Fatalf("Fatalf called %d times, want 1", len(rec.fatal))
+	}
+	report := rec.fatal[0]
+	for _, want := range []string{
+		"scenario diverged at step 3: expected ERROR_INVALID_STATE (7), got SUCCESS (0)",
+		`step 3 [T+30d2h] operator → Increment {"k":1}: SUCCESS (0)`,
+		"event:  Incremented{at=",
+		"write:  counter (v2, 2 bytes)",
+		"step 1 [T+0s] alice → Increment: SUCCESS (0) (1 events, 1 writes)",
+		"step 2 [T+30d2h] advance time 30d2h",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	s.As(fixtures.Alice()).Call("Missing", "")
+	if len(rec.fatal) != 2 || !strings.Contains(rec.fatal[1], `unknown export "Missing"`) {
+		t.Errorf("unknown export report = %v", rec.fatal)
+	}
+}
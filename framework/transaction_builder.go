@@ -1,5 +1,3 @@
-//go:build tinygo || (js && wasm)
-
 package framework
 
 // ==================== WES 合约交易构建器（链式API）====================
@@ -13,6 +11,9 @@ package framework
 // - 类型安全，编译检查
 // - 确定性保证
 // - 与 P1 HostABI 完整集成
+//
+// Finalize 调用宿主函数 host_build_transaction，见 transaction_builder_host.go；
+// 非WASM环境的实现见 host_mock_stub.go。
 
 // TransactionDraft 交易草稿（SDK侧）
 type TransactionDraft struct {
@@ -111,6 +112,10 @@ func (tb *TransactionBuilder) AddStateOutput(stateID []byte, version uint64, exe
 	if tb.err != nil {
 		return tb
 	}
+	if err := chargeWriteBudget(len(stateID) + len(execHash)); err != nil {
+		tb.err = err
+		return tb
+	}
 
 	tb.draft.outputs = append(tb.draft.outputs, OutputDescriptor{
 		outputType: "state",
@@ -196,72 +201,6 @@ func (tb *TransactionBuilder) WithFee(feeAmount Amount) *TransactionBuilder {
 	return tb
 }
 
-// Finalize 完成交易构建
-//
-// ⚠️ **内部接口**：仅供 helpers 层使用
-//
-// 🔄 **更新说明**：
-//   - 使用新的 host_build_transaction 签名（4个参数）
-//   - 返回 TxReceipt JSON，从中提取交易哈希
-func (tb *TransactionBuilder) Finalize() (bool, []byte, uint32) {
-	if tb.err != nil {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-
-	// 序列化draft为JSON（添加 sign_mode 字段）
-	draftJSON := tb.serializeDraft()
-	if draftJSON == "" {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-
-	// 调试：输出 Draft JSON，便于定位交易构建问题
-	debugEvent := NewEvent("tx_draft_debug")
-	debugEvent.AddStringField("json", draftJSON)
-	_ = EmitEvent(debugEvent)
-
-	// 调用宿主函数构建交易
-	draftPtr, draftLen := AllocateString(draftJSON)
-	if draftPtr == 0 {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-
-	// 分配 receipt 缓冲区（足够大以容纳 JSON 响应）
-	receiptSize := uint32(4096) // 4KB 应该足够
-	receiptPtr := Malloc(receiptSize)
-	if receiptPtr == 0 {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-
-	// 调用宿主函数（新版本：4个参数）
-	result := hostBuildTransaction(draftPtr, draftLen, receiptPtr, receiptSize)
-	if result != SUCCESS {
-		return false, nil, result
-	}
-
-	// 读取 receipt JSON
-	// 注意：需要找到实际的 JSON 结束位置，而不是使用整个缓冲区
-	receiptBytes := GetBytes(receiptPtr, receiptSize)
-	if receiptBytes == nil || len(receiptBytes) == 0 {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-	
-	// 找到实际的 JSON 结束位置（查找最后一个 '}'）
-	actualLen := findJSONEnd(receiptBytes)
-	if actualLen == 0 {
-		return false, nil, ERROR_EXECUTION_FAILED
-	}
-	
-	receiptJSON := string(receiptBytes[:actualLen])
-
-	// 解析 receipt JSON 提取交易哈希
-	txHash, errCode := parseTxHashFromReceipt(receiptJSON)
-	if txHash == nil {
-		return false, nil, errCode
-	}
-
-	return true, txHash, SUCCESS
-}
-
 // parseTxHashFromReceipt 从 TxReceipt JSON 中解析交易哈希
 //
 // TxReceipt 结构：
@@ -460,24 +399,43 @@ func hexEncodeSimple(data []byte) string {
 	return string(buf)
 }
 
-// ==================== 宿主函数声明 ====================
+// base64EncodeSimple Base64编码（用于地址和TokenID）
+// 使用标准Base64编码算法，适用于TinyGo WASM环境
+func base64EncodeSimple(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	const base64Table = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	result := ""
 
-// hostBuildTransaction 构建交易（宿主函数）
-//
-// 🔄 **更新说明**：
-//   - 新版本签名：4个参数（draftPtr, draftLen, receiptPtr, receiptSize）
-//   - 返回 TxReceipt JSON 到 receiptPtr，而不是交易哈希
-//   - receiptSize 是 receipt 缓冲区的最大容量
-//
-// 📋 **参数**：
-//   - draftPtr: Draft JSON 指针（在 WASM 内存中）
-//   - draftLen: Draft JSON 长度
-//   - receiptPtr: TxReceipt JSON 写入指针（在 WASM 内存中）
-//   - receiptSize: TxReceipt 缓冲区大小
-//
-// 🔧 **返回值**：
-//   - 0: 成功
-//   - 其他: 错误代码
-//
-//go:wasmimport env host_build_transaction
-func hostBuildTransaction(draftPtr uint32, draftLen uint32, receiptPtr uint32, receiptSize uint32) uint32
+	for i := 0; i < len(data); i += 3 {
+		b1 := data[i]
+		b2 := byte(0)
+		b3 := byte(0)
+
+		if i+1 < len(data) {
+			b2 = data[i+1]
+		}
+		if i+2 < len(data) {
+			b3 = data[i+2]
+		}
+
+		result += string(base64Table[(b1>>2)&0x3F])
+		result += string(base64Table[((b1&0x03)<<4)|((b2>>4)&0x0F)])
+
+		if i+1 < len(data) {
+			result += string(base64Table[((b2&0x0F)<<2)|((b3>>6)&0x03)])
+		} else {
+			result += "="
+		}
+
+		if i+2 < len(data) {
+			result += string(base64Table[b3&0x3F])
+		} else {
+			result += "="
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+//go:build tinygo || (js && wasm)
+
+package framework
+
+// Finalize 完成交易构建
+//
+// ⚠️ **内部接口**：仅供 helpers 层使用
+//
+// 🔄 **更新说明**：
+//   - 使用新的 host_build_transaction 签名（4个参数）
+//   - 返回 TxReceipt JSON，从中提取交易哈希
+func (tb *TransactionBuilder) Finalize() (bool, []byte, uint32) {
+	if tb.err != nil {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	// 序列化draft为JSON（添加 sign_mode 字段）
+	draftJSON := tb.serializeDraft()
+	if draftJSON == "" {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	// 调试：输出 Draft JSON，便于定位交易构建问题
+	debugEvent := NewEvent("tx_draft_debug")
+	debugEvent.AddStringField("json", draftJSON)
+	_ = EmitEvent(debugEvent)
+
+	// 调用宿主函数构建交易
+	draftPtr, draftLen := AllocateString(draftJSON)
+	if draftPtr == 0 {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	// 分配 receipt 缓冲区（足够大以容纳 JSON 响应）
+	receiptSize := uint32(4096) // 4KB 应该足够
+	receiptPtr := Malloc(receiptSize)
+	if receiptPtr == 0 {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	// 调用宿主函数（新版本：4个参数）
+	result := hostBuildTransaction(draftPtr, draftLen, receiptPtr, receiptSize)
+	if result != SUCCESS {
+		return false, nil, result
+	}
+
+	// 读取 receipt JSON
+	// 注意：需要找到实际的 JSON 结束位置，而不是使用整个缓冲区
+	receiptBytes := GetBytes(receiptPtr, receiptSize)
+	if receiptBytes == nil || len(receiptBytes) == 0 {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	// 找到实际的 JSON 结束位置（查找最后一个 '}'）
+	actualLen := findJSONEnd(receiptBytes)
+	if actualLen == 0 {
+		return false, nil, ERROR_EXECUTION_FAILED
+	}
+
+	receiptJSON := string(receiptBytes[:actualLen])
+
+	// 解析 receipt JSON 提取交易哈希
+	txHash, errCode := parseTxHashFromReceipt(receiptJSON)
+	if txHash == nil {
+		return false, nil, errCode
+	}
+
+	// 本次调用随后的读取看到待提交的状态值（见 state_cache.go）
+	for _, out := range tb.draft.outputs {
+		if out.outputType == "state" {
+			cacheStagedState(out.stateID, out.stateVer, out.execHash)
+		}
+	}
+
+	return true, txHash, SUCCESS
+}
+
+// ==================== 宿主函数声明 ====================
+
+// hostBuildTransaction 构建交易（宿主函数）
+//
+// 🔄 **更新说明**：
+//   - 新版本签名：4个参数（draftPtr, draftLen, receiptPtr, receiptSize）
+//   - 返回 TxReceipt JSON 到 receiptPtr，而不是交易哈希
+//   - receiptSize 是 receipt 缓冲区的最大容量
+//
+// 📋 **参数**：
+//   - draftPtr: Draft JSON 指针（在 WASM 内存中）
+//   - draftLen: Draft JSON 长度
+//   - receiptPtr: TxReceipt JSON 写入指针（在 WASM 内存中）
+//   - receiptSize: TxReceipt 缓冲区大小
+//
+// 🔧 **返回值**：
+//   - 0: 成功
+//   - 其他: 错误代码
+//
+//go:wasmimport env host_build_transaction
+func hostBuildTransaction(draftPtr uint32, draftLen uint32, receiptPtr uint32, receiptSize uint32) uint32
@@ -0,0 +1,129 @@
+package framework
+
+import "math/bits"
+
+// ==================== 带单位的数值类型 ====================
+//
+// 合约参数大多是 uint64：等待期、费率、时间戳混用同一类型时，把等待期传给费率参数、
+// 把时间戳当作时长都能通过编译。本文件为常见单位提供轻量包装类型：
+//   - DurationSeconds：时长（秒）
+//   - TimestampUnix：Unix 时间戳（秒）
+//   - BasisPoints：万分比（10000 = 100%）
+//   - Percent：百分比（100 = 100%）
+//
+// 各类型只提供单位兼容的运算（时间戳 + 时长、金额 × 费率），单位错配在编译期报错。
+// 类型底层均为 uint64，编码、事件字段等仍按 uint64 处理（显式转换 uint64(v)）。
+//
+// 参数解析见 ContractParams.ParseDuration / ParseBasisPoints / ParseAmount（params_typed.go）。
+
+const (
+	// MAX_BASIS_POINTS 万分比上限（100%），NewBasisPoints 与 ParseBasisPoints 默认不允许超过
+	MAX_BASIS_POINTS BasisPoints = 10000
+	// MAX_PERCENT 百分比上限（100%）
+	MAX_PERCENT Percent = 100
+)
+
+// DurationSeconds 以秒为单位的时长
+type DurationSeconds uint64
+
+// TimestampUnix Unix 时间戳（秒），与 GetTimestamp 的返回值同一单位
+type TimestampUnix uint64
+
+// BasisPoints 万分比（bp），10000 = 100%
+type BasisPoints uint64
+
+// Percent 百分比，100 = 100%
+type Percent uint64
+
+// Hours 返回 n 小时的时长
+func Hours(n uint64) DurationSeconds {
+	return DurationSeconds(n * 3600)
+}
+
+// Days 返回 n 天的时长
+func Days(n uint64) DurationSeconds {
+	return DurationSeconds(n * 86400)
+}
+
+// Add 返回两个时长之和
+func (d DurationSeconds) Add(other DurationSeconds) DurationSeconds {
+	return d + other
+}
+
+// IsZero 时长是否为 0
+func (d DurationSeconds) IsZero() bool {
+	return d == 0
+}
+
+// Add 返回时间戳之后 d 秒的时间戳
+func (t TimestampUnix) Add(d DurationSeconds) TimestampUnix {
+	return t + TimestampUnix(d)
+}
+
+// Since 返回从 earlier 到 t 经过的时长；earlier 晚于 t 时返回 0
+func (t TimestampUnix) Since(earlier TimestampUnix) DurationSeconds {
+	if earlier >= t {
+		return 0
+	}
+	return DurationSeconds(t - earlier)
+}
+
+// Before 时间戳是否早于 other
+func (t TimestampUnix) Before(other TimestampUnix) bool {
+	return t < other
+}
+
+// After 时间戳是否晚于 other
+func (t TimestampUnix) After(other TimestampUnix) bool {
+	return t > other
+}
+
+// NewBasisPoints 创建万分比，超过 MAX_BASIS_POINTS（100%）返回 ERROR_INVALID_PARAMS
+//
+// 杠杆倍数、溢价上限等确实需要超过 100% 的场景使用 NewBasisPointsUpTo 显式放宽上限。
+func NewBasisPoints(value uint64) (BasisPoints, error) {
+	return NewBasisPointsUpTo(value, MAX_BASIS_POINTS)
+}
+
+// NewBasisPointsUpTo 创建万分比，超过 max 返回 ERROR_INVALID_PARAMS
+func NewBasisPointsUpTo(value uint64, max BasisPoints) (BasisPoints, error) {
+	if value > uint64(max) {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "basis points "+formatUint(value)+" exceed "+formatUint(uint64(max)))
+	}
+	return BasisPoints(value), nil
+}
+
+// Percent 换算为百分比（向下取整）
+func (b BasisPoints) Percent() Percent {
+	return Percent(b / 100)
+}
+
+// NewPercent 创建百分比，超过 MAX_PERCENT（100%）返回 ERROR_INVALID_PARAMS
+func NewPercent(value uint64) (Percent, error) {
+	if value > uint64(MAX_PERCENT) {
+		return 0, NewContractError(ERROR_INVALID_PARAMS, "percent "+formatUint(value)+" exceeds 100")
+	}
+	return Percent(value), nil
+}
+
+// BasisPoints 换算为万分比
+func (p Percent) BasisPoints() BasisPoints {
+	return BasisPoints(p * 100)
+}
+
+// ApplyBP 返回 a * bp / 10000（向下取整）
+//
+// 中间结果按 128 位计算，大额金额乘以费率不会溢出；结果超过 uint64 时截断为最大值。
+func (a Amount) ApplyBP(bp BasisPoints) Amount {
+	hi, lo := bits.Mul64(uint64(a), uint64(bp))
+	if hi >= uint64(MAX_BASIS_POINTS) {
+		return Amount(^uint64(0))
+	}
+	q, _ := bits.Div64(hi, lo, uint64(MAX_BASIS_POINTS))
+	return Amount(q)
+}
+
+// ApplyPercent 返回 a * p / 100（向下取整）
+func (a Amount) ApplyPercent(p Percent) Amount {
+	return a.ApplyBP(p.BasisPoints())
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import "testing"
+
+// TestTimestampDurationArithmetic 测试时间戳与时长的运算
+func TestTimestampDurationArithmetic(t *testing.T) {
+	start := TimestampUnix(1736200000)
+	end := start.Add(Days(30))
+	if end != 1736200000+30*86400 {
+		t.Errorf("Add(Days(30)) = %d", end)
+	}
+	if got := end.Since(start); got != Days(30) {
+		t.Errorf("Since() = %d, want %d", got, Days(30))
+	}
+	if got := start.Since(end); got != 0 {
+		t.Errorf("Since() of a later timestamp = %d, want 0", got)
+	}
+	if !start.Before(end) || !end.After(start) || start.After(start) {
+		t.Error("Before/After ordering is wrong")
+	}
+	if Hours(24) != Days(1) || Days(1).Add(Hours(12)) != 129600 {
+		t.Error("Hours/Days/Add mismatch")
+	}
+}
+
+// TestNewBasisPoints 测试万分比构造：默认上限 100%，显式放宽后允许更高
+func TestNewBasisPoints(t *testing.T) {
+	if bp, err := NewBasisPoints(10000); err != nil || bp != MAX_BASIS_POINTS {
+		t.Errorf("NewBasisPoints(10000) = %d, %v", bp, err)
+	}
+	if _, err := NewBasisPoints(10001); err == nil || err.(*ContractError).Code != ERROR_INVALID_PARAMS {
+		t.Errorf("NewBasisPoints(10001) error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if bp, err := NewBasisPointsUpTo(30000, 50000); err != nil || bp != 30000 {
+		t.Errorf("NewBasisPointsUpTo(30000, 50000) = %d, %v", bp, err)
+	}
+	if _, err := NewPercent(101); err == nil {
+		t.Error("NewPercent(101) error = nil, want ERROR_INVALID_PARAMS")
+	}
+	if BasisPoints(850).Percent() != 8 || Percent(8).BasisPoints() != 800 {
+		t.Error("percent / basis point conversion mismatch")
+	}
+}
+
+// TestAmountApplyBP 测试金额乘以费率：向下取整，大额金额不溢出
+func TestAmountApplyBP(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		bp     BasisPoints
+		want   Amount
+	}{
+		{90000, 800, 7200},
+		{999, 1, 0},
+		{1000, MAX_BASIS_POINTS, 1000},
+		{Amount(^uint64(0)), 5000, Amount(^uint64(0) / 2)},
+		{Amount(^uint64(0)), 20000, Amount(^uint64(0))},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.ApplyBP(tt.bp); got != tt.want {
+			t.Errorf("Amount(%d).ApplyBP(%d) = %d, want %d", tt.amount, tt.bp, got, tt.want)
+		}
+	}
+	if got := Amount(90000).ApplyPercent(8); got != 7200 {
+		t.Errorf("ApplyPercent(8) = %d, want 7200", got)
+	}
+}
@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"encoding/base64"
+)
+
+// ==================== 按所有者列出 UTXO ====================
+//
+// QueryUTXOsByAddress / ListUTXOsByOwner 基于宿主函数 utxo_list_by_owner 分页列出地址持有的资产 UTXO，
+// 供找零选币、储备证明、NFT 持有列表等场景使用。
+//
+// 宿主以 JSON 返回结果（字节字段为标准 base64，与 utxo_lookup_json 一致；金额为十进制字符串或数字）：
+//
+//	{"utxos":[{"tx_hash":"...","index":0,"owner":"...","token_id":"USDT","amount":"1000"}]}
+//
+// 排序保证：结果按 (tx_hash, index) 字节序升序排列，同一次调用内读取的是同一个链上快照，
+// 各节点返回的顺序一致，offset 分页不会重复或遗漏。
+
+const (
+	// ANY_TOKEN_ID 代币过滤条件：列出所有代币（含原生币）的 UTXO
+	//
+	// 空代币ID表示原生币（见 NativeTokenID），因此"不过滤"使用单独的取值
+	ANY_TOKEN_ID TokenID = "*"
+
+	// MAX_UTXO_LIST_LIMIT ListUTXOsByOwner 单页最多返回的 UTXO 数
+	MAX_UTXO_LIST_LIMIT = 100
+
+	// MAX_UTXO_QUERY_RESULTS QueryUTXOsByAddress 最多返回的 UTXO 数，超过时返回 ERROR_QUOTA_EXCEEDED
+	MAX_UTXO_QUERY_RESULTS = 1000
+
+	// MAX_UTXO_LIST_RESULT_SIZE 单页结果 JSON 的最大字节数（256KB）
+	MAX_UTXO_LIST_RESULT_SIZE = 256 << 10
+
+	// utxoListInitialBufSize 首次读取单页结果的缓冲区大小
+	utxoListInitialBufSize = 16 << 10
+)
+
+// validateUTXOList 校验分页参数：地址不能为空，limit 为 1 到 MAX_UTXO_LIST_LIMIT
+func validateUTXOList(owner Address, limit uint32) error {
+	if owner.IsZero() {
+		return NewContractError(ERROR_INVALID_PARAMS, "owner address cannot be empty")
+	}
+	if limit == 0 || limit > MAX_UTXO_LIST_LIMIT {
+		return NewContractError(ERROR_INVALID_PARAMS, "utxo list limit must be between 1 and MAX_UTXO_LIST_LIMIT")
+	}
+	return nil
+}
+
+// parseUTXOList 解析宿主返回的一页 UTXO
+//
+// 空结果（长度为 0）视为没有更多 UTXO。结果格式错误、条数超过 limit、所有者或代币与查询条件不符、
+// 或未按 (tx_hash, index) 升序排列时返回 ERROR_EXECUTION_FAILED，不返回部分结果。
+func parseUTXOList(data []byte, owner Address, tokenID TokenID, limit uint32) ([]UTXO, error) {
+	if len(data) == 0 {
+		return []UTXO{}, nil
+	}
+	items := NewContractParams(data).ParseJSONObjectArray("utxos")
+	if items == nil {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "malformed utxo list result")
+	}
+	if uint32(len(items)) > limit {
+		return nil, NewContractError(ERROR_EXECUTION_FAILED, "utxo list returned more entries than limit")
+	}
+
+	utxos := make([]UTXO, 0, len(items))
+	for _, item := range items {
+		txHash, err := base64.StdEncoding.DecodeString(item.ParseJSON("tx_hash"))
+		if err != nil || len(txHash) != 32 {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid tx_hash in utxo list result")
+		}
+		index, ok := item.ParseJSONIntChecked("index")
+		if !ok || index < 0 || index > int64(^uint32(0)) {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid index in utxo list result")
+		}
+		ownerBytes, err := base64.StdEncoding.DecodeString(item.ParseJSON("owner"))
+		if err != nil || len(ownerBytes) != 20 || AddressFromBytes(ownerBytes) != owner {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "utxo list result owner mismatch")
+		}
+		token := TokenID(item.ParseJSON("token_id"))
+		if tokenID != ANY_TOKEN_ID && token != tokenID {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "utxo list result token mismatch")
+		}
+		amount, ok := parseJSONAmount(item, "amount")
+		if !ok || amount == 0 {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "invalid amount in utxo list result")
+		}
+
+		outpoint := OutPoint{TxHash: txHash, Index: uint32(index)}
+		if n := len(utxos); n > 0 && !outPointLess(utxos[n-1].OutPoint, outpoint) {
+			return nil, NewContractError(ERROR_EXECUTION_FAILED, "utxo list result out of order")
+		}
+		utxos = append(utxos, UTXO{
+			OutPoint: outpoint,
+			Output:   TxOutput{Type: "asset", Recipient: owner, Amount: amount, TokenID: token},
+		})
+	}
+	return utxos, nil
+}
+
+// parseJSONAmount 解析金额字段（JSON 数字或十进制字符串），支持完整的 uint64 范围
+func parseJSONAmount(cp *ContractParams, key string) (Amount, bool) {
+	raw, found := cp.lookupJSON(key)
+	if !found {
+		return 0, false
+	}
+	if s, quoted := jsonPlainString(raw); quoted {
+		raw = s
+	}
+	v, ok := parseDecimalUint(raw)
+	return Amount(v), ok
+}
+
+// outPointLess 按 (tx_hash, index) 字节序比较
+func outPointLess(a, b OutPoint) bool {
+	if string(a.TxHash) != string(b.TxHash) {
+		return string(a.TxHash) < string(b.TxHash)
+	}
+	return a.Index < b.Index
+}
+
+// collectUTXOs 逐页调用 list 直到取完，汇总结果
+//
+// 超过 MAX_UTXO_QUERY_RESULTS 时返回 ERROR_QUOTA_EXCEEDED（应改用 ListUTXOsByOwner 分页处理）
+func collectUTXOs(list func(offset, limit uint32) ([]UTXO, error)) ([]UTXO, error) {
+	all := []UTXO{}
+	for {
+		page, err := list(uint32(len(all)), MAX_UTXO_LIST_LIMIT)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(all) > MAX_UTXO_QUERY_RESULTS {
+			return nil, NewContractError(ERROR_QUOTA_EXCEEDED, "address holds more than MAX_UTXO_QUERY_RESULTS utxos")
+		}
+		if len(page) < MAX_UTXO_LIST_LIMIT {
+			return all, nil
+		}
+	}
+}
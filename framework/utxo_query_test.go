@@ -0,0 +1,145 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestQueryUTXOsByAddressMatchesBalance 测试按地址列出的 UTXO 金额之和与 QueryUTXOBalance 一致
+func TestQueryUTXOsByAddressMatchesBalance(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	owner := AddressFromBytes([]byte("owner_address_000001"))
+	other := AddressFromBytes([]byte("other_address_000002"))
+	host.SetBalance(owner, NativeTokenID(), 1_000)
+	host.SetBalance(owner, "USDT", 250)
+	host.SetBalance(owner, "EMPTY", 0)
+	host.SetBalance(other, "USDT", 999)
+
+	for _, token := range []TokenID{NativeTokenID(), "USDT", "EMPTY", "NONE"} {
+		utxos, err := QueryUTXOsByAddress(owner, token)
+		if err != nil {
+			t.Fatalf("QueryUTXOsByAddress(%q) error = %v", token, err)
+		}
+		var sum Amount
+		for _, u := range utxos {
+			if u.Output.Recipient != owner || u.Output.TokenID != token || len(u.OutPoint.TxHash) != 32 {
+				t.Errorf("QueryUTXOsByAddress(%q) returned %+v", token, u)
+			}
+			sum += u.Output.Amount
+		}
+		if want := QueryUTXOBalance(owner, token); sum != want {
+			t.Errorf("QueryUTXOsByAddress(%q) sum = %d, QueryUTXOBalance = %d", token, sum, want)
+		}
+	}
+
+	all, err := QueryUTXOsByAddress(owner, ANY_TOKEN_ID)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("QueryUTXOsByAddress(ANY_TOKEN_ID) = %d utxos, %v, want 2", len(all), err)
+	}
+	if !outPointLess(all[0].OutPoint, all[1].OutPoint) {
+		t.Error("QueryUTXOsByAddress(ANY_TOKEN_ID) not ordered by (tx_hash, index)")
+	}
+}
+
+// TestListUTXOsByOwnerPaging 测试分页遍历不重复不遗漏，超过 MAX_UTXO_QUERY_RESULTS 时返回 ERROR_QUOTA_EXCEEDED
+func TestListUTXOsByOwnerPaging(t *testing.T) {
+	host := NewMockHost()
+	t.Cleanup(InstallMockHost(host))
+	owner := AddressFromBytes([]byte("owner_address_000001"))
+	for i := 0; i < 7; i++ {
+		host.SetBalance(owner, TokenID(fmt.Sprintf("T%d", i)), Amount(i+1))
+	}
+
+	seen := map[TokenID]bool{}
+	var last *OutPoint
+	for offset := uint32(0); ; offset += 3 {
+		page, err := ListUTXOsByOwner(owner, ANY_TOKEN_ID, 3, offset)
+		if err != nil {
+			t.Fatalf("ListUTXOsByOwner(offset %d) error = %v", offset, err)
+		}
+		for i := range page {
+			if last != nil && !outPointLess(*last, page[i].OutPoint) {
+				t.Errorf("offset %d: entry %d out of order", offset, i)
+			}
+			last = &page[i].OutPoint
+			seen[page[i].Output.TokenID] = true
+		}
+		if len(page) < 3 {
+			break
+		}
+	}
+	if len(seen) != 7 {
+		t.Errorf("paging saw %d tokens, want 7", len(seen))
+	}
+
+	for i := 7; i <= MAX_UTXO_QUERY_RESULTS; i++ {
+		host.SetBalance(owner, TokenID(fmt.Sprintf("T%d", i)), 1)
+	}
+	if _, err := QueryUTXOsByAddress(owner, ANY_TOKEN_ID); stateQueryCode(err) != ERROR_QUOTA_EXCEEDED {
+		t.Errorf("too many utxos error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+}
+
+// TestListUTXOsByOwnerInvalid 测试参数校验与宿主调用失败
+func TestListUTXOsByOwnerInvalid(t *testing.T) {
+	t.Cleanup(InstallMockHost(NewMockHost()))
+	owner := AddressFromBytes([]byte("owner_address_000001"))
+	if _, err := QueryUTXOsByAddress(Address{}, ANY_TOKEN_ID); stateQueryCode(err) != ERROR_INVALID_PARAMS {
+		t.Errorf("empty address error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	for _, limit := range []uint32{0, MAX_UTXO_LIST_LIMIT + 1} {
+		if _, err := ListUTXOsByOwner(owner, ANY_TOKEN_ID, limit, 0); stateQueryCode(err) != ERROR_INVALID_PARAMS {
+			t.Errorf("limit %d error = %v, want ERROR_INVALID_PARAMS", limit, err)
+		}
+	}
+
+	restore := SetHostInterceptor(hostCallFailer{name: HOST_CALL_UTXO_LIST_BY_OWNER})
+	defer restore()
+	if _, err := QueryUTXOsByAddress(owner, ANY_TOKEN_ID); err == nil {
+		t.Error("QueryUTXOsByAddress() succeeded although the host call failed")
+	}
+}
+
+// TestParseUTXOListDefensive 测试宿主结果格式错误、所有者或代币不符、乱序时不返回部分结果
+func TestParseUTXOListDefensive(t *testing.T) {
+	owner := AddressFromBytes([]byte("owner_address_000001"))
+	ownerB64 := base64.StdEncoding.EncodeToString(owner[:])
+	otherB64 := base64.StdEncoding.EncodeToString([]byte("other_address_000002"))
+	hashA := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))
+	hashB := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", 32)))
+	entry := func(hash string, index int, owner, token, amount string) string {
+		return fmt.Sprintf(`{"tx_hash":"%s","index":%d,"owner":"%s","token_id":"%s","amount":%s}`, hash, index, owner, token, amount)
+	}
+
+	valid := `{"utxos":[` + entry(hashA, 1, ownerB64, "USDT", `"5"`) + `,` + entry(hashB, 0, ownerB64, "USDT", `7`) + `]}`
+	utxos, err := parseUTXOList([]byte(valid), owner, "USDT", 2)
+	if err != nil || len(utxos) != 2 || utxos[0].OutPoint.Index != 1 || utxos[0].Output.Amount != 5 || utxos[1].Output.Amount != 7 {
+		t.Fatalf("parseUTXOList(valid) = %+v, %v", utxos, err)
+	}
+	if utxos, err := parseUTXOList(nil, owner, "USDT", 2); err != nil || len(utxos) != 0 {
+		t.Errorf("parseUTXOList(empty) = %+v, %v", utxos, err)
+	}
+
+	bad := map[string]string{
+		"truncated":      valid[:len(valid)-10],
+		"missing":        `{}`,
+		"short hash":     `{"utxos":[` + entry("YWJj", 0, ownerB64, "USDT", "1") + `]}`,
+		"bad index":      `{"utxos":[` + entry(hashA, -1, ownerB64, "USDT", "1") + `]}`,
+		"other owner":    `{"utxos":[` + entry(hashA, 0, otherB64, "USDT", "1") + `]}`,
+		"other token":    `{"utxos":[` + entry(hashA, 0, ownerB64, "BTC", "1") + `]}`,
+		"zero amount":    `{"utxos":[` + entry(hashA, 0, ownerB64, "USDT", "0") + `]}`,
+		"out of order":   `{"utxos":[` + entry(hashB, 0, ownerB64, "USDT", "1") + `,` + entry(hashA, 0, ownerB64, "USDT", "1") + `]}`,
+		"duplicate":      `{"utxos":[` + entry(hashA, 0, ownerB64, "USDT", "1") + `,` + entry(hashA, 0, ownerB64, "USDT", "1") + `]}`,
+		"over the limit": `{"utxos":[` + entry(hashA, 0, ownerB64, "USDT", "1") + `,` + entry(hashB, 0, ownerB64, "USDT", "1") + `,` + entry(hashB, 1, ownerB64, "USDT", "1") + `]}`,
+	}
+	for name, data := range bad {
+		if utxos, err := parseUTXOList([]byte(data), owner, "USDT", 2); stateQueryCode(err) != ERROR_EXECUTION_FAILED || utxos != nil {
+			t.Errorf("%s: parseUTXOList() = %+v, %v, want ERROR_EXECUTION_FAILED", name, utxos, err)
+		}
+	}
+}
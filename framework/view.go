@@ -0,0 +1,635 @@
+package framework
+
+// 只读视图函数与批量查询（Multicall）
+//
+// 客户端展示一个页面往往需要多次只读查询（计划信息、成员信息、当前轮次……），
+// 每次查询都是一次独立调用。合约把只读查询登记为视图函数（RegisterViewFunction）后，
+// 即可通过标准导出函数 Multicall 在一次调用中批量执行：
+//   - 只分派到已登记的视图函数，其他方法（如 Payout）逐条拒绝
+//   - 每条调用独立返回结果或结构化错误，单条失败不影响整批
+//   - 调用条数与请求字节数受限，返回内容受返回字节预算约束；
+//     预算用尽后剩余条目标记为 skipped，客户端从 next_index 起重新提交
+//
+// 视图函数只读取状态、不暂存写入，Multicall 不改变其语义。
+
+const (
+	// MULTICALL_METHOD 批量查询的标准导出函数名
+	MULTICALL_METHOD = "Multicall"
+
+	// MAX_MULTICALL_CALLS 单次 Multicall 的最大调用条数
+	MAX_MULTICALL_CALLS = 16
+	// MAX_MULTICALL_REQUEST_BYTES 单次 Multicall 的请求参数字节上限
+	MAX_MULTICALL_REQUEST_BYTES = 4096
+	// MAX_MULTICALL_RETURN_BYTES 单次 Multicall 各条结果的返回字节预算（不含 skipped 标记）
+	MAX_MULTICALL_RETURN_BYTES = 8192
+)
+
+// Multicall 单条结果状态
+const (
+	MULTICALL_STATUS_OK      = "ok"
+	MULTICALL_STATUS_ERROR   = "error"
+	MULTICALL_STATUS_SKIPPED = "skipped"
+)
+
+// ViewFunc 只读视图函数
+//
+// **参数**：
+//   - params: 本次查询的参数（直接调用时为合约调用参数，Multicall 中为该条的 params 对象）
+//
+// **返回**：
+//   - result: 查询结果，值须为字符串、布尔、整数、[]interface{} 或 map[string]interface{}
+//   - error: 查询失败时返回 *ContractError，其错误码作为该次查询的返回码
+type ViewFunc func(params *ContractParams) (interface{}, error)
+
+// MulticallCall Multicall 中的单条调用
+type MulticallCall struct {
+	// Method 视图函数名
+	Method string
+	// Params 该条调用的参数（JSON 对象文本）
+	Params []byte
+}
+
+// MulticallResult Multicall 中单条调用的结果
+type MulticallResult struct {
+	// Method 视图函数名
+	Method string
+	// Status 结果状态：ok / error / skipped
+	Status string
+	// Result 查询结果的规范化 JSON（仅 ok）
+	Result []byte
+	// ErrorCode 错误码（仅 error）
+	ErrorCode uint32
+	// ErrorMessage 错误信息（仅 error）
+	ErrorMessage string
+}
+
+var (
+	viewFunctions     = map[string]ViewFunc{}
+	viewFunctionOrder []string
+)
+
+// RegisterViewFunction 登记只读视图函数
+//
+// 🎯 **用途**：声明可经 Multicall 批量调用的查询，通常在合约包的 init 中调用；
+// 登记任一视图函数即启用 Multicall
+//
+// **参数**：
+//   - name: 导出函数名，重复登记时覆盖；不能登记 Multicall 自身
+//   - fn: 视图函数
+//
+// **示例**：
+//
+//	func init() {
+//	    framework.RegisterViewFunction("GetPlanInfo", viewPlanInfo)
+//	}
+//
+//	//export GetPlanInfo
+//	func GetPlanInfo() uint32 {
+//	    return framework.ServeView("GetPlanInfo")
+//	}
+//
+//	//export Multicall
+//	func Multicall() uint32 {
+//	    return framework.HandleMulticall()
+//	}
+func RegisterViewFunction(name string, fn ViewFunc) {
+	if name == "" || name == MULTICALL_METHOD || fn == nil {
+		return
+	}
+	if _, ok := viewFunctions[name]; !ok {
+		viewFunctionOrder = append(viewFunctionOrder, name)
+	}
+	viewFunctions[name] = fn
+}
+
+// IsViewFunction 查询方法是否为已登记的视图函数
+func IsViewFunction(name string) bool {
+	_, ok := viewFunctions[name]
+	return ok
+}
+
+// ViewFunctions 返回已登记的视图函数名（按登记顺序）
+func ViewFunctions() []string {
+	names := make([]string, len(viewFunctionOrder))
+	copy(names, viewFunctionOrder)
+	return names
+}
+
+// MulticallEnabled 是否已启用 Multicall（已登记至少一个视图函数）
+func MulticallEnabled() bool {
+	return len(viewFunctionOrder) > 0
+}
+
+// ServeView 以当前调用参数执行视图函数并返回 JSON
+//
+// 🎯 **用途**：视图函数对应的导出函数直接委托本函数，直接调用与 Multicall 共用同一实现
+//
+// **注意**：作为导出函数入口执行 BeginInvocation
+//
+// **返回**：视图函数的错误码；未登记时返回 ERROR_NOT_IMPLEMENTED
+func ServeView(name string) uint32 {
+	BeginInvocation()
+	fn, ok := viewFunctions[name]
+	if !ok {
+		return ERROR_NOT_IMPLEMENTED
+	}
+	result, err := fn(GetContractParams())
+	if err != nil {
+		return viewErrorCode(err)
+	}
+	if err := SetReturnJSON(result); err != nil {
+		return ERROR_EXECUTION_FAILED
+	}
+	return SUCCESS
+}
+
+// HandleMulticall 执行 Multicall 导出函数
+//
+// 参数（JSON）：
+//
+//	{
+//	  "calls": [
+//	    {"method": "GetPlanInfo", "params": {"plan_id": "plan_001"}},
+//	    {"method": "GetCurrentRound", "params": {"plan_id": "plan_001"}}
+//	  ]
+//	}
+//
+// 返回：
+//
+//	{
+//	  "results": [
+//	    {"method": "GetPlanInfo", "status": "ok", "result": {...}},
+//	    {"method": "GetCurrentRound", "status": "error", "error": {"code": 4, "message": "..."}}
+//	  ],
+//	  "next_index": 2,
+//	  "has_more": false
+//	}
+//
+// **注意**：
+//   - 未登记任何视图函数时返回 ERROR_NOT_IMPLEMENTED
+//   - 参数格式错误返回 ERROR_INVALID_PARAMS；条数或请求字节超限返回 ERROR_QUOTA_EXCEEDED
+//   - 单条调用的错误只体现在该条结果中，整批仍返回 SUCCESS
+//   - 作为导出函数入口执行 BeginInvocation
+func HandleMulticall() uint32 {
+	BeginInvocation()
+	if !MulticallEnabled() {
+		return ERROR_NOT_IMPLEMENTED
+	}
+	calls, err := ParseMulticallCalls(GetContractParams().GetRawData())
+	if err != nil {
+		return viewErrorCode(err)
+	}
+	if err := SetReturnData(EncodeMulticallResults(RunMulticall(calls))); err != nil {
+		return ERROR_EXECUTION_FAILED
+	}
+	return SUCCESS
+}
+
+// ParseMulticallCalls 解析并校验 Multicall 参数
+//
+// **返回**：调用列表；格式错误返回 ERROR_INVALID_PARAMS，条数或字节超限返回 ERROR_QUOTA_EXCEEDED
+func ParseMulticallCalls(raw []byte) ([]MulticallCall, error) {
+	if len(raw) > MAX_MULTICALL_REQUEST_BYTES {
+		return nil, NewContractError(ERROR_QUOTA_EXCEEDED, "multicall request exceeds "+formatUint(MAX_MULTICALL_REQUEST_BYTES)+" bytes")
+	}
+	members, ok := jsonObjectMembers(string(raw))
+	if !ok {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "multicall params must be a JSON object")
+	}
+	items, ok := jsonArrayElements(members["calls"])
+	if !ok {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "calls must be an array")
+	}
+	if len(items) == 0 {
+		return nil, NewContractError(ERROR_INVALID_PARAMS, "calls is empty")
+	}
+	if len(items) > MAX_MULTICALL_CALLS {
+		return nil, NewContractError(ERROR_QUOTA_EXCEEDED, "at most "+formatUint(MAX_MULTICALL_CALLS)+" calls per multicall")
+	}
+
+	calls := make([]MulticallCall, 0, len(items))
+	for _, item := range items {
+		fields, ok := jsonObjectMembers(item)
+		if !ok {
+			return nil, NewContractError(ERROR_INVALID_PARAMS, "each call must be a JSON object")
+		}
+		method, ok := jsonPlainString(fields["method"])
+		if !ok || method == "" {
+			return nil, NewContractError(ERROR_INVALID_PARAMS, "call method is required")
+		}
+		params := fields["params"]
+		if params == "" {
+			params = "{}"
+		} else if params[0] != '{' {
+			return nil, NewContractError(ERROR_INVALID_PARAMS, "call params must be a JSON object")
+		}
+		calls = append(calls, MulticallCall{Method: method, Params: []byte(params)})
+	}
+	return calls, nil
+}
+
+// RunMulticall 按顺序执行各条调用
+//
+// 🎯 **用途**：HandleMulticall 的核心逻辑，不依赖宿主函数，便于测试
+//
+// **返回**：与 calls 一一对应的结果
+//
+// **注意**：
+//   - 非视图函数逐条拒绝（ERROR_PERMISSION_DENIED），不执行
+//   - 各条结果（含错误）按编码字节计入 MAX_MULTICALL_RETURN_BYTES；某条结果使预算超限时，
+//     该条及其后所有条目标记为 skipped，其后条目不再执行
+func RunMulticall(calls []MulticallCall) []MulticallResult {
+	results := make([]MulticallResult, len(calls))
+	used := 0
+	exhausted := false
+	for i, call := range calls {
+		if exhausted {
+			results[i] = MulticallResult{Method: call.Method, Status: MULTICALL_STATUS_SKIPPED}
+			continue
+		}
+		result := runView(call)
+		size := len(appendMulticallResult(nil, result))
+		if used+size > MAX_MULTICALL_RETURN_BYTES {
+			exhausted = true
+			results[i] = MulticallResult{Method: call.Method, Status: MULTICALL_STATUS_SKIPPED}
+			continue
+		}
+		used += size
+		results[i] = result
+	}
+	return results
+}
+
+// runView 执行单条调用
+func runView(call MulticallCall) MulticallResult {
+	fn, ok := viewFunctions[call.Method]
+	if !ok {
+		return multicallError(call.Method, NewContractError(ERROR_PERMISSION_DENIED, "method "+call.Method+" is not a view function"))
+	}
+	value, err := fn(NewContractParams(call.Params))
+	if err != nil {
+		return multicallError(call.Method, err)
+	}
+	encoded, err := appendCanonical(nil, value)
+	if err != nil {
+		return multicallError(call.Method, NewContractError(ERROR_EXECUTION_FAILED, "unsupported result type"))
+	}
+	return MulticallResult{Method: call.Method, Status: MULTICALL_STATUS_OK, Result: encoded}
+}
+
+func multicallError(method string, err error) MulticallResult {
+	return MulticallResult{
+		Method:       method,
+		Status:       MULTICALL_STATUS_ERROR,
+		ErrorCode:    viewErrorCode(err),
+		ErrorMessage: err.Error(),
+	}
+}
+
+// EncodeMulticallResults 编码 Multicall 返回值
+//
+// next_index 为第一条 skipped 条目的位置（无 skipped 时为条目总数），has_more 表示存在 skipped 条目
+func EncodeMulticallResults(results []MulticallResult) []byte {
+	next := len(results)
+	for i, r := range results {
+		if r.Status == MULTICALL_STATUS_SKIPPED {
+			next = i
+			break
+		}
+	}
+	buf := []byte(`{"has_more":`)
+	if next < len(results) {
+		buf = append(buf, "true"...)
+	} else {
+		buf = append(buf, "false"...)
+	}
+	buf = append(buf, `,"next_index":`...)
+	buf = append(buf, formatUint(uint64(next))...)
+	buf = append(buf, `,"results":[`...)
+	for i, r := range results {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendMulticallResult(buf, r)
+	}
+	return append(buf, "]}"...)
+}
+
+// appendMulticallResult 追加单条结果的 JSON
+func appendMulticallResult(buf []byte, r MulticallResult) []byte {
+	buf = append(buf, `{"method":`...)
+	buf = append(buf, quoteCanonical(r.Method)...)
+	buf = append(buf, `,"status":`...)
+	buf = append(buf, quoteCanonical(r.Status)...)
+	switch r.Status {
+	case MULTICALL_STATUS_OK:
+		buf = append(buf, `,"result":`...)
+		buf = append(buf, r.Result...)
+	case MULTICALL_STATUS_ERROR:
+		buf = append(buf, `,"error":{"code":`...)
+		buf = append(buf, formatUint(uint64(r.ErrorCode))...)
+		buf = append(buf, `,"message":`...)
+		buf = append(buf, quoteCanonical(r.ErrorMessage)...)
+		buf = append(buf, '}')
+	}
+	return append(buf, '}')
+}
+
+// viewErrorCode 取视图函数错误的错误码，非 ContractError 视为执行失败
+func viewErrorCode(err error) uint32 {
+	if ce, ok := err.(*ContractError); ok && ce.Code != SUCCESS {
+		return ce.Code
+	}
+	return ERROR_EXECUTION_FAILED
+}
+
+// multicallLimits 汇总 Multicall 限制（供 Limits 返回）
+func multicallLimits() map[string]interface{} {
+	views := make([]interface{}, len(viewFunctionOrder))
+	for i, name := range viewFunctionOrder {
+		views[i] = name
+	}
+	return map[string]interface{}{
+		"max_calls":         uint32(MAX_MULTICALL_CALLS),
+		"max_request_bytes": uint32(MAX_MULTICALL_REQUEST_BYTES),
+		"max_return_bytes":  uint32(MAX_MULTICALL_RETURN_BYTES),
+		"views":             views,
+	}
+}
+
+// ==================== JSON 扫描 ====================
+//
+// 以下函数按结构扫描嵌套对象与数组，只返回原始文本，不做数值转换；
+// 合约参数解析（ContractParams.ParseJSON 的点分路径）与 Multicall 共用。
+
+// jsonObjectMembers 返回对象各顶层成员的原始值文本
+func jsonObjectMembers(s string) (map[string]string, bool) {
+	i := skipJSONSpace(s, 0)
+	if i >= len(s) || s[i] != '{' {
+		return nil, false
+	}
+	members := map[string]string{}
+	i = skipJSONSpace(s, i+1)
+	if i < len(s) && s[i] == '}' {
+		return members, skipJSONSpace(s, i+1) == len(s)
+	}
+	for i < len(s) {
+		keyEnd := skipJSONValue(s, i)
+		if s[i] != '"' || keyEnd < 0 {
+			return nil, false
+		}
+		key, ok := jsonPlainString(s[i:keyEnd])
+		if !ok {
+			return nil, false
+		}
+		i = skipJSONSpace(s, keyEnd)
+		if i >= len(s) || s[i] != ':' {
+			return nil, false
+		}
+		i = skipJSONSpace(s, i+1)
+		end := skipJSONValue(s, i)
+		if end < 0 {
+			return nil, false
+		}
+		members[key] = s[i:end]
+		i = skipJSONSpace(s, end)
+		if i >= len(s) {
+			return nil, false
+		}
+		if s[i] == '}' {
+			return members, skipJSONSpace(s, i+1) == len(s)
+		}
+		if s[i] != ',' {
+			return nil, false
+		}
+		i = skipJSONSpace(s, i+1)
+	}
+	return nil, false
+}
+
+// jsonArrayElements 返回数组各元素的原始文本
+func jsonArrayElements(s string) ([]string, bool) {
+	i := skipJSONSpace(s, 0)
+	if i >= len(s) || s[i] != '[' {
+		return nil, false
+	}
+	var items []string
+	i = skipJSONSpace(s, i+1)
+	if i < len(s) && s[i] == ']' {
+		return items, skipJSONSpace(s, i+1) == len(s)
+	}
+	for i < len(s) {
+		end := skipJSONValue(s, i)
+		if end < 0 {
+			return nil, false
+		}
+		items = append(items, s[i:end])
+		i = skipJSONSpace(s, end)
+		if i >= len(s) {
+			return nil, false
+		}
+		if s[i] == ']' {
+			return items, skipJSONSpace(s, i+1) == len(s)
+		}
+		if s[i] != ',' {
+			return nil, false
+		}
+		i = skipJSONSpace(s, i+1)
+	}
+	return nil, false
+}
+
+// jsonLookup 在值 s 中按路径逐层查找：对象按成员名，数组按十进制下标
+func jsonLookup(s string, path []string) (string, bool) {
+	for _, seg := range path {
+		if members, ok := jsonObjectMembers(s); ok {
+			if s, ok = members[seg]; !ok {
+				return "", false
+			}
+			continue
+		}
+		items, ok := jsonArrayElements(s)
+		if !ok {
+			return "", false
+		}
+		idx, ok := parseJSONIndex(seg)
+		if !ok || idx >= len(items) {
+			return "", false
+		}
+		s = items[idx]
+	}
+	return s, true
+}
+
+// splitJSONPath 按 "." 切分点分路径
+func splitJSONPath(key string) []string {
+	var path []string
+	start := 0
+	for i := 0; i < len(key); i++ {
+		if key[i] == '.' {
+			path = append(path, key[start:i])
+			start = i + 1
+		}
+	}
+	return append(path, key[start:])
+}
+
+// parseJSONIndex 解析数组下标（十进制，不超过 6 位）
+func parseJSONIndex(seg string) (int, bool) {
+	if seg == "" || len(seg) > 6 {
+		return 0, false
+	}
+	idx := 0
+	for i := 0; i < len(seg); i++ {
+		if seg[i] < '0' || seg[i] > '9' {
+			return 0, false
+		}
+		idx = idx*10 + int(seg[i]-'0')
+	}
+	return idx, true
+}
+
+// jsonString 解析字符串字面量并还原转义字符（\" \\ \/ \b \f \n \r \t \uXXXX，含代理对）
+func jsonString(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false
+	}
+	v := s[1 : len(s)-1]
+	if plain, ok := jsonPlainString(s); ok {
+		return plain, true
+	}
+	buf := make([]byte, 0, len(v))
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c == '"' {
+			return "", false
+		}
+		if c != '\\' {
+			buf = append(buf, c)
+			continue
+		}
+		i++
+		if i >= len(v) {
+			return "", false
+		}
+		switch v[i] {
+		case '"', '\\', '/':
+			buf = append(buf, v[i])
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := parseHex4(v, i+1)
+			if !ok {
+				return "", false
+			}
+			i += 4
+			if r >= 0xD800 && r < 0xDC00 && i+6 < len(v) && v[i+1] == '\\' && v[i+2] == 'u' {
+				if lo, ok := parseHex4(v, i+3); ok && lo >= 0xDC00 && lo < 0xE000 {
+					r = 0x10000 + (r-0xD800)<<10 + (lo - 0xDC00)
+					i += 6
+				}
+			}
+			buf = append(buf, string(rune(r))...)
+		default:
+			return "", false
+		}
+	}
+	return string(buf), true
+}
+
+// parseHex4 解析 s[i:i+4] 的四位十六进制数
+func parseHex4(s string, i int) (int32, bool) {
+	if i+4 > len(s) {
+		return 0, false
+	}
+	var r int32
+	for _, c := range []byte(s[i : i+4]) {
+		switch {
+		case c >= '0' && c <= '9':
+			r = r<<4 | int32(c-'0')
+		case c >= 'a' && c <= 'f':
+			r = r<<4 | int32(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			r = r<<4 | int32(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+	return r, true
+}
+
+// jsonPlainString 解析不含转义的字符串字面量
+func jsonPlainString(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false
+	}
+	v := s[1 : len(s)-1]
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' || v[i] == '"' {
+			return "", false
+		}
+	}
+	return v, true
+}
+
+// skipJSONValue 返回从 i 开始的 JSON 值的结束位置（不含），格式错误返回 -1
+func skipJSONValue(s string, i int) int {
+	if i >= len(s) {
+		return -1
+	}
+	switch s[i] {
+	case '"':
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == '\\' {
+				j++
+			} else if s[j] == '"' {
+				return j + 1
+			}
+		}
+		return -1
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(s); j++ {
+			switch s[j] {
+			case '"':
+				end := skipJSONValue(s, j)
+				if end < 0 {
+					return -1
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return -1
+	default:
+		j := i
+		for j < len(s) && s[j] != ',' && s[j] != '}' && s[j] != ']' && s[j] != ' ' && s[j] != '\t' && s[j] != '\n' && s[j] != '\r' {
+			j++
+		}
+		if j == i {
+			return -1
+		}
+		return j
+	}
+}
+
+func skipJSONSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r') {
+		i++
+	}
+	return i
+}
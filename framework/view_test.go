@@ -0,0 +1,161 @@
+//go:build !tinygo && !(js && wasm)
+
+package framework
+
+import (
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterViewFunction("TestViewEcho", func(params *ContractParams) (interface{}, error) {
+		return map[string]interface{}{"params": string(params.GetRawData())}, nil
+	})
+	RegisterViewFunction("TestViewMissing", func(params *ContractParams) (interface{}, error) {
+		return nil, NewContractError(ERROR_NOT_FOUND, "record not found")
+	})
+	RegisterViewFunction("TestViewLarge", func(params *ContractParams) (interface{}, error) {
+		return strings.Repeat("x", MAX_MULTICALL_RETURN_BYTES/3), nil
+	})
+}
+
+// TestParseMulticallCalls 测试参数解析、条数与字节上限
+func TestParseMulticallCalls(t *testing.T) {
+	calls, err := ParseMulticallCalls([]byte(`{"calls": [
+		{"method": "TestViewEcho", "params": {"id": "a", "nested": {"k": [1, 2]}}},
+		{"params": {"method": "ignored"}, "method": "TestViewMissing"},
+		{"method": "TestViewEcho"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseMulticallCalls() error = %v", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("calls = %d, want 3", len(calls))
+	}
+	if calls[0].Method != "TestViewEcho" || string(calls[0].Params) != `{"id": "a", "nested": {"k": [1, 2]}}` {
+		t.Errorf("calls[0] = %+v", calls[0])
+	}
+	if calls[1].Method != "TestViewMissing" {
+		t.Errorf("calls[1].Method = %q, want top-level method", calls[1].Method)
+	}
+	if string(calls[2].Params) != "{}" {
+		t.Errorf("calls[2].Params = %q, want {}", calls[2].Params)
+	}
+
+	tooMany := `{"calls":[` + strings.Repeat(`{"method":"TestViewEcho"},`, MAX_MULTICALL_CALLS) + `{"method":"TestViewEcho"}]}`
+	tests := []struct {
+		name     string
+		raw      string
+		wantCode uint32
+	}{
+		{"not an object", `[]`, ERROR_INVALID_PARAMS},
+		{"missing calls", `{}`, ERROR_INVALID_PARAMS},
+		{"empty calls", `{"calls":[]}`, ERROR_INVALID_PARAMS},
+		{"missing method", `{"calls":[{"params":{}}]}`, ERROR_INVALID_PARAMS},
+		{"params not object", `{"calls":[{"method":"TestViewEcho","params":"x"}]}`, ERROR_INVALID_PARAMS},
+		{"truncated", `{"calls":[{"method":"TestViewEcho"}`, ERROR_INVALID_PARAMS},
+		{"too many calls", tooMany, ERROR_QUOTA_EXCEEDED},
+		{"request too large", `{"calls":[{"method":"` + strings.Repeat("a", MAX_MULTICALL_REQUEST_BYTES) + `"}]}`, ERROR_QUOTA_EXCEEDED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMulticallCalls([]byte(tt.raw)); quotaErrCode(err) != tt.wantCode {
+				t.Errorf("ParseMulticallCalls() error = %v, want code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestRunMulticallPerEntryResults 测试各条结果独立：成功、错误与非视图函数拒绝
+func TestRunMulticallPerEntryResults(t *testing.T) {
+	results := RunMulticall([]MulticallCall{
+		{Method: "TestViewEcho", Params: []byte(`{"id":"a"}`)},
+		{Method: "TestViewMissing", Params: []byte(`{}`)},
+		{Method: "Transfer", Params: []byte(`{}`)},
+		{Method: MULTICALL_METHOD, Params: []byte(`{}`)},
+		{Method: "TestViewEcho", Params: []byte(`{}`)},
+	})
+
+	want := []struct {
+		status string
+		code   uint32
+	}{
+		{MULTICALL_STATUS_OK, 0},
+		{MULTICALL_STATUS_ERROR, ERROR_NOT_FOUND},
+		{MULTICALL_STATUS_ERROR, ERROR_PERMISSION_DENIED},
+		{MULTICALL_STATUS_ERROR, ERROR_PERMISSION_DENIED},
+		{MULTICALL_STATUS_OK, 0},
+	}
+	for i, w := range want {
+		if results[i].Status != w.status || results[i].ErrorCode != w.code {
+			t.Errorf("results[%d] = %+v, want status %s code %d", i, results[i], w.status, w.code)
+		}
+	}
+	if got := string(results[0].Result); got != `{"params":"{\"id\":\"a\"}"}` {
+		t.Errorf("results[0].Result = %s", got)
+	}
+
+	encoded := string(EncodeMulticallResults(results))
+	if !strings.HasPrefix(encoded, `{"has_more":false,"next_index":5,"results":[`) {
+		t.Errorf("encoded = %s", encoded)
+	}
+	if !strings.Contains(encoded, `{"method":"TestViewMissing","status":"error","error":{"code":4,"message":"record not found"}}`) {
+		t.Errorf("encoded missing per-entry error: %s", encoded)
+	}
+}
+
+// TestRunMulticallReturnBudget 测试返回预算用尽后剩余条目标记为 skipped 且不再执行
+func TestRunMulticallReturnBudget(t *testing.T) {
+	executed := 0
+	RegisterViewFunction("TestViewCounted", func(params *ContractParams) (interface{}, error) {
+		executed++
+		return "ok", nil
+	})
+
+	results := RunMulticall([]MulticallCall{
+		{Method: "TestViewLarge"},
+		{Method: "TestViewLarge"},
+		{Method: "TestViewLarge"},
+		{Method: "TestViewCounted"},
+	})
+	statuses := make([]string, len(results))
+	for i, r := range results {
+		statuses[i] = r.Status
+	}
+	if got := strings.Join(statuses, ","); got != "ok,ok,skipped,skipped" {
+		t.Errorf("statuses = %s, want ok,ok,skipped,skipped", got)
+	}
+	if executed != 0 {
+		t.Errorf("view executed %d times after budget exhausted", executed)
+	}
+
+	encoded := string(EncodeMulticallResults(results))
+	if !strings.HasPrefix(encoded, `{"has_more":true,"next_index":2,`) {
+		t.Errorf("encoded prefix = %.40s", encoded)
+	}
+	if !strings.HasSuffix(encoded, `{"method":"TestViewLarge","status":"skipped"},{"method":"TestViewCounted","status":"skipped"}]}`) {
+		t.Errorf("encoded missing skipped markers")
+	}
+}
+
+// TestViewRegistryLimits 测试视图函数登记与 Limits 中的 Multicall 限制
+func TestViewRegistryLimits(t *testing.T) {
+	RegisterViewFunction(MULTICALL_METHOD, func(*ContractParams) (interface{}, error) { return nil, nil })
+	if IsViewFunction(MULTICALL_METHOD) {
+		t.Error("Multicall registered as a view function")
+	}
+	if !MulticallEnabled() || !IsViewFunction("TestViewEcho") {
+		t.Fatal("registered views not visible")
+	}
+
+	multicall, ok := Limits()["multicall"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Limits() = %v, want multicall limits", Limits())
+	}
+	if multicall["max_calls"] != uint32(MAX_MULTICALL_CALLS) {
+		t.Errorf("max_calls = %v", multicall["max_calls"])
+	}
+	if views := multicall["views"].([]interface{}); len(views) != len(ViewFunctions()) || views[0] != "TestViewEcho" {
+		t.Errorf("views = %v, want %v", views, ViewFunctions())
+	}
+}
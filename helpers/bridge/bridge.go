@@ -0,0 +1,197 @@
+//go:build tinygo || (js && wasm)
+
+package bridge
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// Lock 在源链锁定代币，生成可在目标链证明的锁定记录
+//
+// 🎯 **用途**：跨链桥的锁定侧：调用者的代币转入桥合约地址托管，
+// 锁定消息写入状态输出 bridge_lock:{nonce}，其包含证明可在目标链通过 Mint 验证
+//
+// **参数**：
+//   - tokenID: 锁定的代币（空字符串表示原生币）
+//   - amount: 锁定数量
+//   - destChainID: 目标链标识符
+//   - destAddress: 目标链接收地址
+//
+// **返回**：
+//   - *LockMessage: 锁定消息（含 nonce），链下中继据此生成证明
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS，余额不足返回 ERROR_INSUFFICIENT_BALANCE
+//
+// **注意**：
+//   - 源链标识符取自 framework.GetChainID()
+//   - 锁定资产的释放（返程）与权限控制是业务逻辑，需要在合约代码中实现
+//
+// **示例**：
+//
+//	msg, err := bridge.Lock("USDT", framework.Amount(1000), []byte("wes-testnet"), recipient)
+//	if err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func Lock(tokenID framework.TokenID, amount framework.Amount, destChainID []byte, destAddress framework.Address) (*LockMessage, error) {
+	// 1. 分配锁定序号并构建消息
+	sender := framework.GetCaller()
+	nonce, nonceVersion := loadNonce()
+	msg, err := NewLockMessage(framework.GetChainID(), destChainID, nonce, sender, destAddress, tokenID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 余额检查
+	if framework.QueryUTXOBalance(sender, tokenID) < amount {
+		return nil, framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to lock")
+	}
+
+	// 3. 资金转入桥合约，锁定记录与序号在同一笔交易提交
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(sender, framework.GetContractAddress(), tokenID, amount).
+		AddStateOutput(buildLockStateID(nonce), 1, EncodeLockMessage(msg)).
+		AddStateOutput([]byte(STATE_NONCE), nonceVersion+1, binary.BigEndian.AppendUint64(nil, nonce+1)).
+		Finalize()
+	if !success {
+		return nil, framework.NewContractError(errCode, "bridge lock failed")
+	}
+
+	// 4. 发出锁定事件
+	event := newBridgeEvent("BridgeLocked", msg)
+	framework.EmitEvent(event)
+
+	return msg, nil
+}
+
+// MintProof 目标链铸造所需的锁定证明
+type MintProof struct {
+	// SourceHeight 锁定记录所在的源链高度
+	SourceHeight uint64
+	// Message 源链锁定消息
+	Message *LockMessage
+	// Steps 锁定消息到源链状态根的 Merkle 路径
+	Steps []ProofStep
+}
+
+// Mint 验证源链锁定证明后在本链铸造等额代币
+//
+// 🎯 **用途**：跨链桥的铸造侧
+//
+// **参数**：
+//   - sourceChainID: 源链标识符
+//   - proof: 锁定证明
+//   - mintToken: 本链铸造的包装代币ID（空字符串表示与源链代币ID相同）
+//
+// **返回**：
+//   - error:
+//   - ERROR_INVALID_PARAMS: 消息的源链/目标链与本次铸造不符
+//   - ERROR_NOT_FOUND: 源链未配置（ConfigureSourceChain），或在该高度没有可信状态根
+//   - ERROR_PERMISSION_DENIED: 包含证明无效
+//   - ERROR_ALREADY_EXISTS: 该锁定消息已铸造过（防重放）
+//
+// **注意**：
+//   - 只使用中继者确认的可信根（SubmitSourceRoot），证明的叶子为源链桥合约写入的锁定记录（LockLeaf）
+//   - 已处理的消息记录在 bridge_processed:{message_id}，同一消息只能铸造一次
+//
+// **示例**：
+//
+//	err := bridge.Mint([]byte("wes-mainnet"), &bridge.MintProof{
+//	    SourceHeight: height,
+//	    Message:      msg,
+//	    Steps:        steps,
+//	}, "wUSDT")
+func Mint(sourceChainID []byte, proof *MintProof, mintToken framework.TokenID) error {
+	if proof == nil || proof.Message == nil {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "proof cannot be empty")
+	}
+	msg := proof.Message
+
+	// 1. 消息必须来自 sourceChainID 且以本链为目标
+	localChainID := framework.GetChainID()
+	if err := checkMintTarget(msg, sourceChainID, localChainID); err != nil {
+		return err
+	}
+
+	// 2. 以源链桥合约写入的锁定记录验证包含证明
+	cfg, ok := GetSourceChainConfig(sourceChainID)
+	if !ok {
+		return framework.NewContractError(framework.ERROR_NOT_FOUND, "source chain not configured")
+	}
+	root, ok := TrustedSourceRoot(sourceChainID, proof.SourceHeight)
+	if !ok {
+		return framework.NewContractError(framework.ERROR_NOT_FOUND, "source state root not available")
+	}
+	if !VerifyMerkleProof(LockLeaf(cfg.BridgeContract, msg), proof.Steps, root) {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "invalid lock proof")
+	}
+
+	// 3. 防重放：同一锁定消息只能铸造一次
+	messageID := MessageID(msg)
+	if IsProcessed(messageID) {
+		return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "lock message already processed")
+	}
+	if _, err := framework.AppendStateOutputSimple(buildProcessedStateID(messageID), 1, []byte{1}, nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to mark message processed")
+	}
+
+	// 4. 铸造
+	if mintToken == "" {
+		mintToken = msg.TokenID
+	}
+	if err := token.Mint(msg.Recipient, mintToken, msg.Amount); err != nil {
+		return err
+	}
+
+	// 5. 发出铸造事件
+	event := newBridgeEvent("BridgeMinted", msg)
+	event.AddStringField("mint_token_id", string(mintToken))
+	event.AddUint64Field("source_height", proof.SourceHeight)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// IsProcessed 查询锁定消息是否已在本链铸造
+func IsProcessed(messageID framework.Hash) bool {
+	_, version, err := framework.GetStateFromChain(buildProcessedStateID(messageID))
+	return err == nil && version > 0
+}
+
+// 状态ID
+const (
+	// STATE_NONCE 下一个锁定序号
+	STATE_NONCE = "bridge_nonce"
+)
+
+// loadNonce 读取下一个锁定序号及其状态版本
+func loadNonce() (nonce, version uint64) {
+	data, version, err := framework.GetStateFromChain([]byte(STATE_NONCE))
+	if err != nil || version == 0 {
+		return 0, 0
+	}
+	buf := make([]byte, 8)
+	copy(buf, data)
+	return binary.BigEndian.Uint64(buf), version
+}
+
+// newBridgeEvent 构建包含锁定消息字段的事件
+func newBridgeEvent(name string, msg *LockMessage) *framework.Event {
+	messageID := MessageID(msg)
+	event := framework.NewEvent(name)
+	event.AddStringField("message_id", hex.EncodeToString(messageID[:]))
+	event.AddStringField("source_chain_id", string(msg.SourceChainID))
+	event.AddStringField("dest_chain_id", string(msg.DestChainID))
+	event.AddUint64Field("nonce", msg.Nonce)
+	event.AddAddressField("sender", msg.Sender)
+	event.AddAddressField("recipient", msg.Recipient)
+	event.AddStringField("token_id", string(msg.TokenID))
+	event.AddUint64Field("amount", uint64(msg.Amount))
+	return event
+}
+
+// buildProcessedStateID 构建已处理消息状态ID：bridge_processed:{message_id}
+func buildProcessedStateID(messageID framework.Hash) []byte {
+	return []byte("bridge_processed:" + hex.EncodeToString(messageID[:]))
+}
@@ -0,0 +1,152 @@
+package bridge
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+var (
+	testSourceChain = []byte("wes-mainnet")
+	testDestChain   = []byte("wes-sidechain")
+)
+
+func newTestLockMessage(t *testing.T, nonce uint64) *LockMessage {
+	t.Helper()
+	msg, err := NewLockMessage(testSourceChain, testDestChain, nonce, fixtures.Alice(), fixtures.Bob(),
+		fixtures.StableToken6Decimals().ID, fixtures.StableToken6Decimals().Units(25))
+	if err != nil {
+		t.Fatalf("NewLockMessage() error = %v", err)
+	}
+	return msg
+}
+
+// TestVerifyMerkleProof 测试各叶子的包含证明均可验证，篡改的数据或路径被拒绝
+func TestVerifyMerkleProof(t *testing.T) {
+	var leaves [][]byte
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		leaves = append(leaves, EncodeLockMessage(newTestLockMessage(t, nonce)))
+	}
+	root := MerkleRoot(leaves)
+
+	for i, leaf := range leaves {
+		proof, ok := BuildMerkleProof(leaves, i)
+		if !ok {
+			t.Fatalf("BuildMerkleProof(%d) failed", i)
+		}
+		if !VerifyMerkleProof(leaf, proof, root) {
+			t.Errorf("VerifyMerkleProof(leaf %d) = false, want true", i)
+		}
+		// 其他叶子的数据不能冒用该路径
+		if VerifyMerkleProof(leaves[(i+1)%len(leaves)], proof, root) {
+			t.Errorf("VerifyMerkleProof(wrong leaf for %d) = true", i)
+		}
+	}
+
+	proof, _ := BuildMerkleProof(leaves, 2)
+	proof[0].Left = !proof[0].Left
+	if VerifyMerkleProof(leaves[2], proof, root) {
+		t.Error("proof with flipped sibling position should fail")
+	}
+	if VerifyMerkleProof(leaves[0], nil, framework.Hash{}) {
+		t.Error("zero root should never verify")
+	}
+	if _, ok := BuildMerkleProof(leaves, len(leaves)); ok {
+		t.Error("BuildMerkleProof(out of range) should fail")
+	}
+
+	// 内部节点不能被当作叶子提交
+	inner := MerkleNodeHash(MerkleLeafHash(leaves[0]), MerkleLeafHash(leaves[1]))
+	if VerifyMerkleProof(inner[:], nil, inner) {
+		t.Error("inner node accepted as leaf")
+	}
+}
+
+// TestLockLeafBinding 测试锁定记录叶子绑定源链桥合约与 bridge_lock:{nonce}：
+// 其他合约、其他状态ID下的相同消息字节不能通过验证
+func TestLockLeafBinding(t *testing.T) {
+	bridgeContract := fixtures.Pool()
+	msg := newTestLockMessage(t, 3)
+	leaves := [][]byte{
+		LockLeaf(bridgeContract, newTestLockMessage(t, 1)),
+		LockLeaf(bridgeContract, msg),
+		// 其他合约写入的同字节数据，以及本合约其他状态ID下的同字节数据
+		EncodeStateLeaf(fixtures.Carol(), buildLockStateID(3), EncodeLockMessage(msg)),
+		EncodeStateLeaf(bridgeContract, []byte("user_note:3"), EncodeLockMessage(msg)),
+	}
+	root := MerkleRoot(leaves)
+
+	proof, _ := BuildMerkleProof(leaves, 1)
+	if !VerifyMerkleProof(LockLeaf(bridgeContract, msg), proof, root) {
+		t.Fatal("lock leaf written by the bridge contract should verify")
+	}
+	for _, i := range []int{2, 3} {
+		forged, _ := BuildMerkleProof(leaves, i)
+		if VerifyMerkleProof(LockLeaf(bridgeContract, msg), forged, root) {
+			t.Errorf("leaf %d verified as a bridge lock record", i)
+		}
+	}
+	// 仅含消息编码的状态（未绑定合约与状态ID）不能证明锁定记录
+	if VerifyMerkleProof(LockLeaf(bridgeContract, msg), nil, MerkleRoot([][]byte{EncodeLockMessage(msg)})) {
+		t.Error("bare lock message leaf verified as a lock record")
+	}
+
+	// 状态ID长度前缀：不同的 (stateID, value) 划分不会得到同一叶子
+	if string(EncodeStateLeaf(bridgeContract, []byte("ab"), []byte("c"))) == string(EncodeStateLeaf(bridgeContract, []byte("a"), []byte("bc"))) {
+		t.Error("state leaf encoding is ambiguous")
+	}
+	if EncodeStateLeaf(bridgeContract, make([]byte, MAX_STATE_ID_LEN+1), nil) != nil {
+		t.Error("oversized state id should be rejected")
+	}
+}
+
+// TestLockMessageCodec 测试锁定消息编解码往返与消息ID
+func TestLockMessageCodec(t *testing.T) {
+	msg := newTestLockMessage(t, 7)
+	decoded, err := DecodeLockMessage(EncodeLockMessage(msg))
+	if err != nil {
+		t.Fatalf("DecodeLockMessage() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, msg) {
+		t.Errorf("decoded = %+v, want %+v", decoded, msg)
+	}
+
+	if MessageID(msg) == MessageID(newTestLockMessage(t, 8)) {
+		t.Error("messages with different nonces share an id")
+	}
+
+	encoded := EncodeLockMessage(msg)
+	for _, bad := range [][]byte{nil, encoded[:len(encoded)-1], append(encoded, 0), append([]byte{2}, encoded[1:]...)} {
+		if _, err := DecodeLockMessage(bad); err == nil {
+			t.Errorf("DecodeLockMessage(%x) should fail", bad)
+		}
+	}
+}
+
+// TestLockMessageValidation 测试锁定参数与铸造目标校验
+func TestLockMessageValidation(t *testing.T) {
+	usdt := fixtures.StableToken6Decimals()
+	if _, err := NewLockMessage(testSourceChain, testSourceChain, 0, fixtures.Alice(), fixtures.Bob(), usdt.ID, 1); err == nil {
+		t.Error("same source and destination chain should be rejected")
+	}
+	if _, err := NewLockMessage(testSourceChain, testDestChain, 0, fixtures.Alice(), framework.Address{}, usdt.ID, 1); err == nil {
+		t.Error("zero recipient should be rejected")
+	}
+	if _, err := NewLockMessage(testSourceChain, nil, 0, fixtures.Alice(), fixtures.Bob(), usdt.ID, 1); err == nil {
+		t.Error("empty destination chain should be rejected")
+	}
+
+	msg := newTestLockMessage(t, 0)
+	if err := checkMintTarget(msg, testSourceChain, testDestChain); err != nil {
+		t.Errorf("checkMintTarget(valid) error = %v", err)
+	}
+	// 发往其他链的消息不能在本链铸造
+	if err := checkMintTarget(msg, testSourceChain, []byte("wes-other")); err == nil {
+		t.Error("message for another destination should be rejected")
+	}
+	if err := checkMintTarget(msg, []byte("wes-other"), testDestChain); err == nil {
+		t.Error("message from another source should be rejected")
+	}
+}
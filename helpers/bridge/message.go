@@ -0,0 +1,165 @@
+package bridge
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 跨链锁定消息（纯函数） ====================
+
+// LOCK_MESSAGE_VERSION 锁定消息编码版本
+const LOCK_MESSAGE_VERSION = byte(1)
+
+// MAX_CHAIN_ID_LEN 链标识符最大长度（与 GetChainID 缓冲区一致）
+const MAX_CHAIN_ID_LEN = 64
+
+// LockMessage 源链锁定记录，目标链凭其包含证明铸造等额代币
+type LockMessage struct {
+	// SourceChainID 源链标识符（GetChainID）
+	SourceChainID []byte
+	// DestChainID 目标链标识符
+	DestChainID []byte
+	// Nonce 源链桥合约内递增的锁定序号
+	Nonce uint64
+	// Sender 源链锁定人
+	Sender framework.Address
+	// Recipient 目标链接收地址
+	Recipient framework.Address
+	// TokenID 源链锁定的代币
+	TokenID framework.TokenID
+	// Amount 锁定数量
+	Amount framework.Amount
+}
+
+// NewLockMessage 创建锁定消息并验证参数
+//
+// **返回**：
+//   - *LockMessage: 锁定消息
+//   - error: 链标识符为空/过长、源链与目标链相同、接收地址为零地址或数量为0时返回 ERROR_INVALID_PARAMS
+func NewLockMessage(sourceChainID, destChainID []byte, nonce uint64, sender, recipient framework.Address, tokenID framework.TokenID, amount framework.Amount) (*LockMessage, error) {
+	if !validChainID(sourceChainID) || !validChainID(destChainID) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid chain id")
+	}
+	if string(sourceChainID) == string(destChainID) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "destination chain must differ from source chain")
+	}
+	if recipient == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "recipient cannot be zero")
+	}
+	if amount == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	if len(tokenID) > 255 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID too long")
+	}
+	return &LockMessage{
+		SourceChainID: sourceChainID,
+		DestChainID:   destChainID,
+		Nonce:         nonce,
+		Sender:        sender,
+		Recipient:     recipient,
+		TokenID:       tokenID,
+		Amount:        amount,
+	}, nil
+}
+
+// EncodeLockMessage 锁定消息的规范编码（Merkle 叶子数据）
+//
+// 编码格式：version(1) + len(1)+sourceChainID + len(1)+destChainID + nonce(8)
+// + sender(20) + recipient(20) + len(1)+tokenID + amount(8)，整数为大端序
+func EncodeLockMessage(msg *LockMessage) []byte {
+	buf := make([]byte, 0, 64+len(msg.SourceChainID)+len(msg.DestChainID)+len(msg.TokenID))
+	buf = append(buf, LOCK_MESSAGE_VERSION)
+	buf = appendBytes(buf, msg.SourceChainID)
+	buf = appendBytes(buf, msg.DestChainID)
+	buf = binary.BigEndian.AppendUint64(buf, msg.Nonce)
+	buf = append(buf, msg.Sender[:]...)
+	buf = append(buf, msg.Recipient[:]...)
+	buf = appendBytes(buf, []byte(msg.TokenID))
+	return binary.BigEndian.AppendUint64(buf, uint64(msg.Amount))
+}
+
+// DecodeLockMessage 解码锁定消息
+//
+// **返回**：
+//   - *LockMessage: 锁定消息
+//   - error: 版本不支持或数据截断时返回 ERROR_INVALID_PARAMS
+func DecodeLockMessage(data []byte) (*LockMessage, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid lock message")
+	if len(data) == 0 || data[0] != LOCK_MESSAGE_VERSION {
+		return nil, invalid
+	}
+	msg := &LockMessage{}
+	pos := 1
+	var ok bool
+	if msg.SourceChainID, pos, ok = readBytes(data, pos); !ok {
+		return nil, invalid
+	}
+	if msg.DestChainID, pos, ok = readBytes(data, pos); !ok {
+		return nil, invalid
+	}
+	if pos+8+40 > len(data) {
+		return nil, invalid
+	}
+	msg.Nonce = binary.BigEndian.Uint64(data[pos:])
+	pos += 8
+	copy(msg.Sender[:], data[pos:pos+20])
+	copy(msg.Recipient[:], data[pos+20:pos+40])
+	pos += 40
+	var tokenID []byte
+	if tokenID, pos, ok = readBytes(data, pos); !ok || pos+8 != len(data) {
+		return nil, invalid
+	}
+	msg.TokenID = framework.TokenID(tokenID)
+	msg.Amount = framework.Amount(binary.BigEndian.Uint64(data[pos:]))
+	return msg, nil
+}
+
+// MessageID 锁定消息的唯一标识（规范编码的 SHA-256），用作防重放集合的键
+func MessageID(msg *LockMessage) framework.Hash {
+	return sha256.Sum256(EncodeLockMessage(msg))
+}
+
+// checkMintTarget 验证锁定消息来自 sourceChainID 且以本链为目标链
+func checkMintTarget(msg *LockMessage, sourceChainID, localChainID []byte) error {
+	if len(localChainID) == 0 {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "chain id unavailable")
+	}
+	if string(msg.SourceChainID) != string(sourceChainID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "message source chain mismatch")
+	}
+	if string(msg.DestChainID) != string(localChainID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "message is not destined for this chain")
+	}
+	if msg.Amount == 0 || msg.Recipient == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid lock message")
+	}
+	return nil
+}
+
+// buildLockStateID 构建锁定记录状态ID：bridge_lock:{nonce}
+func buildLockStateID(nonce uint64) []byte {
+	return []byte("bridge_lock:" + framework.Uint64ToString(nonce))
+}
+
+func validChainID(id []byte) bool {
+	return len(id) > 0 && len(id) <= MAX_CHAIN_ID_LEN
+}
+
+func appendBytes(buf, b []byte) []byte {
+	return append(append(buf, byte(len(b))), b...)
+}
+
+func readBytes(data []byte, pos int) ([]byte, int, bool) {
+	if pos >= len(data) {
+		return nil, pos, false
+	}
+	n := int(data[pos])
+	pos++
+	if pos+n > len(data) {
+		return nil, pos, false
+	}
+	return append([]byte(nil), data[pos:pos+n]...), pos + n, true
+}
@@ -0,0 +1,144 @@
+package bridge
+
+import (
+	"crypto/sha256"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== Merkle 证明（纯函数） ====================
+//
+// 本文件只包含不依赖宿主函数的 Merkle 证明计算，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试，也可供链下中继程序生成证明。
+//
+// 哈希规则（SHA-256，叶子与内部节点加不同前缀，防止第二原像攻击）：
+//   - 叶子：SHA256(0x00 || data)
+//   - 节点：SHA256(0x01 || left || right)
+//   - 奇数层最后一个节点直接提升到上一层
+
+// MAX_STATE_ID_LEN 状态叶子中状态ID的最大长度（2字节长度前缀）
+const MAX_STATE_ID_LEN = 0xFFFF
+
+// EncodeStateLeaf 状态叶子的规范编码：合约写入的一条状态 (contract, stateID, value)
+//
+// 编码格式：contract(20) + len(2)+stateID + value，长度为大端序。
+// 叶子绑定写入合约与状态ID，其他合约或其他状态ID下的相同字节不能冒用同一证明；
+// stateID 超过 MAX_STATE_ID_LEN 时返回 nil。
+func EncodeStateLeaf(contract framework.Address, stateID, value []byte) []byte {
+	if len(stateID) > MAX_STATE_ID_LEN {
+		return nil
+	}
+	buf := make([]byte, 0, 22+len(stateID)+len(value))
+	buf = append(buf, contract[:]...)
+	buf = append(buf, byte(len(stateID)>>8), byte(len(stateID)))
+	buf = append(buf, stateID...)
+	return append(buf, value...)
+}
+
+// LockLeaf 锁定记录的 Merkle 叶子：源链桥合约写入 bridge_lock:{nonce} 的锁定消息
+//
+// 链下中继者以源链状态树中的同一编码生成证明，Mint 以配置的源链桥合约地址重建叶子验证。
+func LockLeaf(bridgeContract framework.Address, msg *LockMessage) []byte {
+	return EncodeStateLeaf(bridgeContract, buildLockStateID(msg.Nonce), EncodeLockMessage(msg))
+}
+
+// ProofStep Merkle 证明中的一步：兄弟节点哈希及其位置
+type ProofStep struct {
+	// Sibling 兄弟节点哈希
+	Sibling framework.Hash
+	// Left 兄弟节点是否位于左侧
+	Left bool
+}
+
+// MerkleLeafHash 计算叶子哈希
+func MerkleLeafHash(data []byte) framework.Hash {
+	return sha256.Sum256(append([]byte{0x00}, data...))
+}
+
+// MerkleNodeHash 计算内部节点哈希
+func MerkleNodeHash(left, right framework.Hash) framework.Hash {
+	buf := make([]byte, 0, 1+2*len(left))
+	buf = append(buf, 0x01)
+	buf = append(buf, left[:]...)
+	buf = append(buf, right[:]...)
+	return sha256.Sum256(buf)
+}
+
+// VerifyMerkleProof 验证 data 是否包含在以 root 为根的 Merkle 树中
+//
+// 🎯 **用途**：跨链铸造前验证源链锁定记录的包含证明
+//
+// **参数**：
+//   - data: 叶子原始数据（如 LockLeaf 的输出）
+//   - proof: 自叶子向上的兄弟节点路径
+//   - root: 可信根（源链状态根）
+//
+// **返回**：
+//   - bool: 证明有效返回 true；root 为零值时返回 false
+func VerifyMerkleProof(data []byte, proof []ProofStep, root framework.Hash) bool {
+	if root == (framework.Hash{}) {
+		return false
+	}
+	hash := MerkleLeafHash(data)
+	for _, step := range proof {
+		if step.Left {
+			hash = MerkleNodeHash(step.Sibling, hash)
+		} else {
+			hash = MerkleNodeHash(hash, step.Sibling)
+		}
+	}
+	return hash == root
+}
+
+// MerkleRoot 计算叶子数据列表的 Merkle 根（空列表返回零值）
+func MerkleRoot(leaves [][]byte) framework.Hash {
+	level := leafHashes(leaves)
+	if len(level) == 0 {
+		return framework.Hash{}
+	}
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// BuildMerkleProof 生成第 index 个叶子的包含证明
+//
+// **返回**：
+//   - []ProofStep: 证明路径
+//   - bool: index 越界时为 false
+func BuildMerkleProof(leaves [][]byte, index int) ([]ProofStep, bool) {
+	if index < 0 || index >= len(leaves) {
+		return nil, false
+	}
+	var proof []ProofStep
+	level := leafHashes(leaves)
+	for len(level) > 1 {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, ProofStep{Sibling: level[sibling], Left: sibling < index})
+		}
+		level = nextLevel(level)
+		index /= 2
+	}
+	return proof, true
+}
+
+func leafHashes(leaves [][]byte) []framework.Hash {
+	hashes := make([]framework.Hash, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = MerkleLeafHash(leaf)
+	}
+	return hashes
+}
+
+func nextLevel(level []framework.Hash) []framework.Hash {
+	next := make([]framework.Hash, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 < len(level) {
+			next = append(next, MerkleNodeHash(level[i], level[i+1]))
+		} else {
+			next = append(next, level[i])
+		}
+	}
+	return next
+}
@@ -0,0 +1,281 @@
+package bridge
+
+import (
+	"encoding/hex"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 源链配置与状态根登记 ====================
+//
+// 目标链只信任由源链中继者集合多数确认的状态根：
+//   - ConfigureSourceChain：桥管理员（BRIDGE_ADMIN_ROLE）登记源链的桥合约地址、中继者集合与确认门限
+//   - SubmitSourceRoot：中继者对 (源链, 高度, 状态根) 投票，同一根获得门限数量的中继者确认后成为可信根
+//
+// Mint 只接受可信根下、由源链桥合约写入 bridge_lock:{nonce} 的锁定记录（见 LockLeaf），
+// 其他合约或其他状态ID下的同字节数据不能通过验证。
+//
+// 本文件只使用状态读写、角色与事件，不带 build tag，可在非WASM环境中直接测试。
+//
+// 状态布局（链标识符以 hex 编码，不与分隔符冲突）：
+//   - 源链配置：bridge_source:{chain_hex} → bridgeContract(20) + threshold(1) + count(1) + relayers(20*count)
+//   - 中继者投票：bridge_root_vote:{chain_hex}:{height}:{relayer} → root(32)
+//   - 可信状态根：bridge_root:{chain_hex}:{height} → root(32)
+
+// BRIDGE_ADMIN_ROLE 可配置源链桥合约与中继者集合的桥管理员角色
+const BRIDGE_ADMIN_ROLE = "bridge_admin"
+
+// MAX_RELAYERS 单个源链的中继者数量上限
+const MAX_RELAYERS = 16
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: BRIDGE_ADMIN_ROLE})
+}
+
+// SourceChainConfig 源链配置
+type SourceChainConfig struct {
+	// BridgeContract 源链桥合约地址，只有该合约写入的锁定记录可以铸造
+	BridgeContract framework.Address
+	// Relayers 可提交源链状态根的中继者
+	Relayers []framework.Address
+	// Threshold 状态根成为可信根所需的中继者确认数
+	Threshold uint8
+}
+
+// ConfigureSourceChain 桥管理员登记或更新源链配置
+//
+// 🎯 **用途**：确定目标链信任哪个源链桥合约、由哪些中继者确认源链状态根
+//
+// **参数**：
+//   - sourceChainID: 源链标识符
+//   - cfg: 源链桥合约地址、中继者集合与确认门限
+//
+// **返回**：
+//   - error: 调用者不是桥管理员（ERROR_UNAUTHORIZED）；链标识符无效、桥合约为零地址、
+//     中继者为空/超过 MAX_RELAYERS/重复/含零地址、门限为 0 或大于中继者数（ERROR_INVALID_PARAMS）
+//
+// **注意**：
+//   - 更新配置不影响已成为可信根的状态根；被移除中继者的投票不再计入
+//   - 发出 BridgeSourceConfigured 事件
+//
+// **示例**：
+//
+//	func init() {
+//	    // Initialize 中：framework.InitRole(bridge.BRIDGE_ADMIN_ROLE, admin)
+//	}
+//	err := bridge.ConfigureSourceChain([]byte("wes-mainnet"), bridge.SourceChainConfig{
+//	    BridgeContract: sourceBridge,
+//	    Relayers:       []framework.Address{relayerA, relayerB, relayerC},
+//	    Threshold:      2,
+//	})
+func ConfigureSourceChain(sourceChainID []byte, cfg SourceChainConfig) error {
+	// 1. 校验管理员与配置
+	caller := framework.GetCaller()
+	if !framework.HasRole(BRIDGE_ADMIN_ROLE, caller) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the bridge admin can configure source chains")
+	}
+	if !validChainID(sourceChainID) || !validSourceChainConfig(cfg) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid source chain config")
+	}
+
+	// 2. 写入配置
+	stateID := buildSourceStateID(sourceChainID)
+	_, version, _ := framework.GetStateFromChain(stateID)
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, encodeSourceChainConfig(cfg), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save source chain config")
+	}
+
+	// 3. 发出配置事件
+	event := framework.NewEvent("BridgeSourceConfigured")
+	event.AddStringField("source_chain_id", string(sourceChainID))
+	event.AddAddressField("bridge_contract", cfg.BridgeContract)
+	event.AddUint64Field("relayer_count", uint64(len(cfg.Relayers)))
+	event.AddUint64Field("threshold", uint64(cfg.Threshold))
+	event.AddAddressField("admin", caller)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// GetSourceChainConfig 查询源链配置，未配置时返回 false
+func GetSourceChainConfig(sourceChainID []byte) (SourceChainConfig, bool) {
+	if !validChainID(sourceChainID) {
+		return SourceChainConfig{}, false
+	}
+	data, version, err := framework.GetStateFromChain(buildSourceStateID(sourceChainID))
+	if err != nil || version == 0 {
+		return SourceChainConfig{}, false
+	}
+	return decodeSourceChainConfig(data)
+}
+
+// SubmitSourceRoot 中继者确认源链在指定高度的状态根
+//
+// 🎯 **用途**：目标链上记录源链状态根，获得门限数量中继者确认的根成为可信根，Mint 据此验证包含证明
+//
+// **返回**：
+//   - error: 根为零值或链标识符无效返回 ERROR_INVALID_PARAMS；源链未配置返回 ERROR_NOT_FOUND；
+//     调用者不是该源链的中继者返回 ERROR_UNAUTHORIZED；该中继者已为此高度确认了不同的根，
+//     或此高度已有不同的可信根返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：
+//   - 中继者对同一高度只能确认一个根，可信根不可覆盖
+//   - 单个中继者无法独自确立可信根（门限大于 1 时），先到的伪造根不会阻断真实根
+//   - 每次确认发出 BridgeRootSubmitted 事件，成为可信根时发出 BridgeRootFinalized 事件
+func SubmitSourceRoot(sourceChainID []byte, height uint64, root framework.Hash) error {
+	// 1. 校验参数与中继者身份
+	if !validChainID(sourceChainID) || root == (framework.Hash{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid source root")
+	}
+	cfg, ok := GetSourceChainConfig(sourceChainID)
+	if !ok {
+		return framework.NewContractError(framework.ERROR_NOT_FOUND, "source chain not configured")
+	}
+	relayer := framework.GetCaller()
+	if !isRelayer(cfg, relayer) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "caller is not a relayer for the source chain")
+	}
+	if trusted, ok := loadRoot(buildRootStateID(sourceChainID, height)); ok {
+		if trusted == root {
+			return nil
+		}
+		return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "source root already finalized")
+	}
+
+	// 2. 记录该中继者的确认
+	voteStateID := buildRootVoteStateID(sourceChainID, height, relayer)
+	if voted, ok := loadRoot(voteStateID); ok {
+		if voted != root {
+			return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "relayer already submitted a different root")
+		}
+	} else if _, err := framework.AppendStateOutputSimple(voteStateID, 1, root[:], nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save root vote")
+	}
+
+	event := framework.NewEvent("BridgeRootSubmitted")
+	event.AddStringField("source_chain_id", string(sourceChainID))
+	event.AddUint64Field("height", height)
+	event.AddStringField("root", hex.EncodeToString(root[:]))
+	event.AddAddressField("submitter", relayer)
+	framework.EmitEvent(event)
+
+	// 3. 当前中继者集合中确认同一根的数量达到门限时登记为可信根
+	confirmations := 0
+	for _, r := range cfg.Relayers {
+		if voted, ok := loadRoot(buildRootVoteStateID(sourceChainID, height, r)); ok && voted == root {
+			confirmations++
+		}
+	}
+	if confirmations < int(cfg.Threshold) {
+		return nil
+	}
+	if _, err := framework.AppendStateOutputSimple(buildRootStateID(sourceChainID, height), 1, root[:], nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save source root")
+	}
+	finalized := framework.NewEvent("BridgeRootFinalized")
+	finalized.AddStringField("source_chain_id", string(sourceChainID))
+	finalized.AddUint64Field("height", height)
+	finalized.AddStringField("root", hex.EncodeToString(root[:]))
+	finalized.AddUint64Field("confirmations", uint64(confirmations))
+	framework.EmitEvent(finalized)
+	return nil
+}
+
+// TrustedSourceRoot 查询源链在 height 的可信状态根，尚未确立时返回 false
+func TrustedSourceRoot(sourceChainID []byte, height uint64) (framework.Hash, bool) {
+	if !validChainID(sourceChainID) {
+		return framework.Hash{}, false
+	}
+	return loadRoot(buildRootStateID(sourceChainID, height))
+}
+
+// loadRoot 读取 32 字节的根，不存在时返回 false
+func loadRoot(stateID []byte) (framework.Hash, bool) {
+	data, version, err := framework.GetStateFromChain(stateID)
+	if err != nil || version == 0 {
+		return framework.Hash{}, false
+	}
+	// 链上读取会去除尾部零字节，补齐到哈希长度
+	buf := make([]byte, 32)
+	copy(buf, data)
+	return framework.HashFromBytes(buf), true
+}
+
+func isRelayer(cfg SourceChainConfig, addr framework.Address) bool {
+	for _, r := range cfg.Relayers {
+		if r.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func validSourceChainConfig(cfg SourceChainConfig) bool {
+	if cfg.BridgeContract.IsZero() || len(cfg.Relayers) == 0 || len(cfg.Relayers) > MAX_RELAYERS {
+		return false
+	}
+	if cfg.Threshold == 0 || int(cfg.Threshold) > len(cfg.Relayers) {
+		return false
+	}
+	for i, r := range cfg.Relayers {
+		if r.IsZero() {
+			return false
+		}
+		for _, other := range cfg.Relayers[:i] {
+			if other.Equals(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// encodeSourceChainConfig 编码源链配置：bridgeContract(20) + threshold(1) + count(1) + relayers(20*count)
+func encodeSourceChainConfig(cfg SourceChainConfig) []byte {
+	buf := make([]byte, 0, 22+20*len(cfg.Relayers))
+	buf = append(buf, cfg.BridgeContract[:]...)
+	buf = append(buf, cfg.Threshold, byte(len(cfg.Relayers)))
+	for _, r := range cfg.Relayers {
+		buf = append(buf, r[:]...)
+	}
+	return buf
+}
+
+// decodeSourceChainConfig 解码源链配置（兼容链上读取去除的尾部零字节）
+func decodeSourceChainConfig(data []byte) (SourceChainConfig, bool) {
+	if len(data) < 22 {
+		return SourceChainConfig{}, false
+	}
+	count := int(data[21])
+	buf := make([]byte, 22+20*count)
+	if len(data) > len(buf) {
+		return SourceChainConfig{}, false
+	}
+	copy(buf, data)
+	cfg := SourceChainConfig{
+		BridgeContract: framework.AddressFromBytes(buf[:20]),
+		Threshold:      buf[20],
+		Relayers:       make([]framework.Address, count),
+	}
+	for i := range cfg.Relayers {
+		cfg.Relayers[i] = framework.AddressFromBytes(buf[22+20*i : 42+20*i])
+	}
+	if !validSourceChainConfig(cfg) {
+		return SourceChainConfig{}, false
+	}
+	return cfg, true
+}
+
+// buildSourceStateID 构建源链配置状态ID：bridge_source:{chain_hex}
+func buildSourceStateID(chainID []byte) []byte {
+	return []byte("bridge_source:" + hex.EncodeToString(chainID))
+}
+
+// buildRootStateID 构建可信状态根状态ID：bridge_root:{chain_hex}:{height}
+func buildRootStateID(chainID []byte, height uint64) []byte {
+	return []byte("bridge_root:" + hex.EncodeToString(chainID) + ":" + framework.Uint64ToString(height))
+}
+
+// buildRootVoteStateID 构建中继者确认状态ID：bridge_root_vote:{chain_hex}:{height}:{relayer}
+func buildRootVoteStateID(chainID []byte, height uint64, relayer framework.Address) []byte {
+	return []byte("bridge_root_vote:" + hex.EncodeToString(chainID) + ":" + framework.Uint64ToString(height) + ":" + relayer.ToString())
+}
@@ -0,0 +1,126 @@
+//go:build !tinygo && !(js && wasm)
+
+package bridge
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+// newBridgeHost 创建以 Operator 为桥管理员、Alice/Bob/Carol 为 2-of-3 中继者的宿主
+func newBridgeHost(t *testing.T) *fwtesting.Host {
+	t.Helper()
+	host := fwtesting.NewHost(t).SetCaller(fixtures.Operator())
+	if res := host.Run(func() error { return framework.InitRole(BRIDGE_ADMIN_ROLE, fixtures.Operator()) }); res.Code != framework.SUCCESS {
+		t.Fatalf("InitRole() code = %d", res.Code)
+	}
+	cfg := SourceChainConfig{
+		BridgeContract: fixtures.Pool(),
+		Relayers:       []framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()},
+		Threshold:      2,
+	}
+	if res := host.Run(func() error { return ConfigureSourceChain(testSourceChain, cfg) }); res.Code != framework.SUCCESS {
+		t.Fatalf("ConfigureSourceChain() code = %d", res.Code)
+	}
+	return host
+}
+
+// TestConfigureSourceChain 测试只有桥管理员可以配置源链，无效配置被拒绝，配置编解码往返
+func TestConfigureSourceChain(t *testing.T) {
+	host := newBridgeHost(t)
+	cfg, ok := GetSourceChainConfig(testSourceChain)
+	if !ok || cfg.BridgeContract != fixtures.Pool() || cfg.Threshold != 2 || len(cfg.Relayers) != 3 || cfg.Relayers[2] != fixtures.Carol() {
+		t.Fatalf("GetSourceChainConfig() = %+v, %v", cfg, ok)
+	}
+	if _, ok := GetSourceChainConfig([]byte("wes-other")); ok {
+		t.Error("unconfigured chain reported as configured")
+	}
+
+	host.SetCaller(fixtures.Alice())
+	if res := host.Run(func() error { return ConfigureSourceChain(testSourceChain, cfg) }); res.Code != framework.ERROR_UNAUTHORIZED || len(res.Writes) != 0 {
+		t.Errorf("ConfigureSourceChain() by non-admin code = %d, %d writes", res.Code, len(res.Writes))
+	}
+
+	host.SetCaller(fixtures.Operator())
+	alice := fixtures.Alice()
+	for name, bad := range map[string]SourceChainConfig{
+		"zero contract":      {Relayers: []framework.Address{alice}, Threshold: 1},
+		"no relayers":        {BridgeContract: fixtures.Pool(), Threshold: 1},
+		"zero threshold":     {BridgeContract: fixtures.Pool(), Relayers: []framework.Address{alice}},
+		"threshold too high": {BridgeContract: fixtures.Pool(), Relayers: []framework.Address{alice}, Threshold: 2},
+		"duplicate relayer":  {BridgeContract: fixtures.Pool(), Relayers: []framework.Address{alice, alice}, Threshold: 1},
+		"zero relayer":       {BridgeContract: fixtures.Pool(), Relayers: []framework.Address{{}}, Threshold: 1},
+		"too many relayers":  {BridgeContract: fixtures.Pool(), Relayers: make([]framework.Address, MAX_RELAYERS+1), Threshold: 1},
+	} {
+		if res := host.Run(func() error { return ConfigureSourceChain(testSourceChain, bad) }); res.Code != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("ConfigureSourceChain(%s) code = %d, want ERROR_INVALID_PARAMS", name, res.Code)
+		}
+	}
+
+	// 地址尾部为零字节时，链上读取去除的尾部零字节被补齐
+	var tail framework.Address
+	tail[0] = 1
+	padded := SourceChainConfig{BridgeContract: fixtures.Pool(), Relayers: []framework.Address{alice, tail}, Threshold: 1}
+	encoded := encodeSourceChainConfig(padded)
+	decoded, ok := decodeSourceChainConfig(encoded[:len(encoded)-19])
+	if !ok || decoded.Relayers[1] != tail {
+		t.Errorf("decodeSourceChainConfig(trimmed) = %+v, %v", decoded, ok)
+	}
+}
+
+// TestSubmitSourceRootThreshold 测试状态根需门限数量中继者确认：非中继者不能提交，
+// 单个中继者抢先提交的伪造根不会成为可信根，也不阻断真实根
+func TestSubmitSourceRootThreshold(t *testing.T) {
+	host := newBridgeHost(t)
+	real := framework.Hash{1, 2, 3}
+	forged := framework.Hash{9, 9, 9}
+	submit := func(caller framework.Address, root framework.Hash) framework.MockCallResult {
+		host.SetCaller(caller)
+		return host.Run(func() error { return SubmitSourceRoot(testSourceChain, 100, root) })
+	}
+
+	if res := submit(fixtures.Operator(), forged); res.Code != framework.ERROR_UNAUTHORIZED || len(res.Writes) != 0 {
+		t.Errorf("SubmitSourceRoot() by non-relayer code = %d, %d writes", res.Code, len(res.Writes))
+	}
+	host.SetCaller(fixtures.Alice())
+	if res := host.Run(func() error { return SubmitSourceRoot([]byte("wes-other"), 100, real) }); res.Code != framework.ERROR_NOT_FOUND {
+		t.Errorf("SubmitSourceRoot() for unconfigured chain code = %d", res.Code)
+	}
+
+	// Carol 抢先提交伪造根：未达门限，不是可信根
+	if res := submit(fixtures.Carol(), forged); res.Code != framework.SUCCESS {
+		t.Fatalf("SubmitSourceRoot(forged) code = %d", res.Code)
+	}
+	if _, ok := TrustedSourceRoot(testSourceChain, 100); ok {
+		t.Fatal("single relayer vote became a trusted root")
+	}
+	if res := submit(fixtures.Carol(), real); res.Code != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("relayer changing its vote code = %d, want ERROR_ALREADY_EXISTS", res.Code)
+	}
+
+	// Alice 与 Bob 确认真实根后成为可信根
+	if res := submit(fixtures.Alice(), real); res.Code != framework.SUCCESS {
+		t.Fatalf("SubmitSourceRoot(Alice) code = %d", res.Code)
+	}
+	if _, ok := TrustedSourceRoot(testSourceChain, 100); ok {
+		t.Fatal("root trusted after one honest vote")
+	}
+	res := submit(fixtures.Bob(), real)
+	if res.Code != framework.SUCCESS || len(res.Events) != 2 || res.Events[1].Name != "BridgeRootFinalized" {
+		t.Fatalf("SubmitSourceRoot(Bob) code = %d, events = %+v", res.Code, res.Events)
+	}
+	if root, ok := TrustedSourceRoot(testSourceChain, 100); !ok || root != real {
+		t.Errorf("TrustedSourceRoot() = %x, %v, want real root", root, ok)
+	}
+
+	// 可信根不可覆盖；重复确认同一根幂等
+	if res := submit(fixtures.Carol(), forged); res.Code != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("SubmitSourceRoot(forged after finalize) code = %d", res.Code)
+	}
+	if res := submit(fixtures.Alice(), real); res.Code != framework.SUCCESS || len(res.Writes) != 0 {
+		t.Errorf("repeated confirmation code = %d, %d writes", res.Code, len(res.Writes))
+	}
+}
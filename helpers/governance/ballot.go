@@ -0,0 +1,261 @@
+package governance
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 签名选票（链下收集、批量提交） ====================
+//
+// 持币者在链下对规范化的选票消息签名，协调者收集后一次性提交。
+// 规范化消息绑定链ID、合约地址、提案ID、选项与过期时间，防止跨链、跨合约、
+// 跨提案重放；同一提案内的重放由投票记录（每个地址每个提案一条）拦截。
+
+// BALLOT_MESSAGE_TAG 选票消息前缀（版本化，变更序列化格式时递增）
+const BALLOT_MESSAGE_TAG = "WES_BALLOT_V1"
+
+// MAX_BALLOTS_PER_BATCH 单次批量提交的最大选票数
+const MAX_BALLOTS_PER_BATCH = 64
+
+// 选票处理结果
+const (
+	// BALLOT_STATUS_APPLIED 已计票
+	BALLOT_STATUS_APPLIED = "APPLIED"
+	// BALLOT_STATUS_EXPIRED 选票已过期（expiry < 当前时间）
+	BALLOT_STATUS_EXPIRED = "EXPIRED"
+	// BALLOT_STATUS_INVALID_SIGNATURE 签名校验失败
+	BALLOT_STATUS_INVALID_SIGNATURE = "INVALID_SIGNATURE"
+	// BALLOT_STATUS_INVALID_CHOICE 选项超出提案选项数
+	BALLOT_STATUS_INVALID_CHOICE = "INVALID_CHOICE"
+	// BALLOT_STATUS_DUPLICATE 同一批次中该地址的选票已计票
+	BALLOT_STATUS_DUPLICATE = "DUPLICATE"
+	// BALLOT_STATUS_ALREADY_VOTED 该地址此前已对提案投票
+	BALLOT_STATUS_ALREADY_VOTED = "ALREADY_VOTED"
+	// BALLOT_STATUS_NO_VOTING_POWER 投票权重为 0
+	BALLOT_STATUS_NO_VOTING_POWER = "NO_VOTING_POWER"
+)
+
+// Ballot 链下签名的选票
+type Ballot struct {
+	// Voter 投票者地址
+	Voter framework.Address
+	// Choice 选项序号（二元提案：0=反对，1=支持）
+	Choice uint32
+	// WeightClaim 投票者声明的权重，仅作为计票上限，0 表示不设上限
+	WeightClaim uint64
+	// Expiry 过期时间（时间戳），当前时间超过该值的选票不计
+	Expiry uint64
+	// Signature 对 BallotMessage 的签名
+	Signature []byte
+}
+
+// BallotDomain 选票签名域
+type BallotDomain struct {
+	ChainID    []byte
+	Contract   framework.Address
+	ProposalID []byte
+}
+
+// BallotResult 单张选票的处理结果
+type BallotResult struct {
+	Voter  framework.Address
+	Status string
+	// Weight 实际计入的权重（仅 APPLIED 时非 0）
+	Weight uint64
+}
+
+// BallotVerifier 选票签名校验
+//
+// 🎯 **用途**：由合约提供签名方案（如投票者登记的 Ed25519 公钥），
+// 校验 signature 是否为 voter 对 message 的签名
+type BallotVerifier interface {
+	VerifyBallot(voter framework.Address, message []byte, signature []byte) bool
+}
+
+// VotingPowerResolver 投票权重解析
+//
+// 🎯 **用途**：由合约决定投票者在提案上的实际权重（持币量、快照余额等），
+// 选票中的 WeightClaim 不作为权重来源
+type VotingPowerResolver interface {
+	VotingPower(voter framework.Address, proposalID []byte) uint64
+}
+
+// BallotStore 投票记录与计票存储
+//
+// **约定**：
+//   - HasVoted: 地址在提案上已有投票记录时返回 true
+//   - RecordVote: 写入地址的投票记录
+//   - AddTally: 将权重累加到提案选项的计票
+type BallotStore interface {
+	HasVoted(proposalID []byte, voter framework.Address) bool
+	RecordVote(proposalID []byte, voter framework.Address, choice uint32, weight uint64) error
+	AddTally(proposalID []byte, choice uint32, weight uint64) error
+}
+
+// BallotMessage 返回选票的规范化签名消息
+//
+// 格式：BALLOT_MESSAGE_TAG | chainIDLen(2) | chainID | contract(20) |
+// proposalIDLen(2) | proposalID | choice(4) | expiry(8)，整数均为大端
+func BallotMessage(domain BallotDomain, choice uint32, expiry uint64) []byte {
+	msg := make([]byte, 0, len(BALLOT_MESSAGE_TAG)+2+len(domain.ChainID)+20+2+len(domain.ProposalID)+12)
+	msg = append(msg, BALLOT_MESSAGE_TAG...)
+	msg = append(msg, byte(len(domain.ChainID)>>8), byte(len(domain.ChainID)))
+	msg = append(msg, domain.ChainID...)
+	msg = append(msg, domain.Contract[:]...)
+	msg = append(msg, byte(len(domain.ProposalID)>>8), byte(len(domain.ProposalID)))
+	msg = append(msg, domain.ProposalID...)
+	msg = append(msg, byte(choice>>24), byte(choice>>16), byte(choice>>8), byte(choice))
+	for i := 0; i < 8; i++ {
+		msg = append(msg, byte(expiry>>(56-8*i)))
+	}
+	return msg
+}
+
+// ApplySignedBallots 校验并计入一批签名选票
+//
+// 🎯 **用途**：SubmitSignedBallots 的核心逻辑，不依赖宿主函数，便于测试
+//
+// **参数**：
+//   - store: 投票记录与计票存储
+//   - domain: 签名域（链ID、合约地址、提案ID）
+//   - ballots: 选票列表，最多 MAX_BALLOTS_PER_BATCH 张
+//   - now: 当前时间，用于过期判断
+//   - numChoices: 提案选项数，选项序号须小于该值
+//   - verifier / resolver: 签名校验与权重解析
+//
+// **返回**：
+//   - results: 与 ballots 一一对应的处理结果
+//   - error: 参数无效或存储写入失败
+//
+// **注意**：
+//   - 校验顺序：选项 → 过期 → 签名 → 批内重复 → 已投票 → 权重
+//   - 无效选票跳过，不中断整批
+//   - 计入权重 = min(解析权重, WeightClaim)（WeightClaim 为 0 时不设上限）
+//   - 计票按选项汇总后每个选项只写一次，顺序按选项序号
+func ApplySignedBallots(
+	store BallotStore,
+	domain BallotDomain,
+	ballots []Ballot,
+	now uint64,
+	numChoices uint32,
+	verifier BallotVerifier,
+	resolver VotingPowerResolver,
+) ([]BallotResult, error) {
+	if len(domain.ProposalID) == 0 || numChoices == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "proposalID and choices are required")
+	}
+	if len(ballots) == 0 || len(ballots) > MAX_BALLOTS_PER_BATCH {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "ballot count out of range")
+	}
+
+	results := make([]BallotResult, len(ballots))
+	tally := make([]uint64, numChoices)
+	applied := make(map[framework.Address]bool, len(ballots))
+
+	for i, b := range ballots {
+		results[i] = BallotResult{Voter: b.Voter}
+		switch {
+		case b.Choice >= numChoices:
+			results[i].Status = BALLOT_STATUS_INVALID_CHOICE
+			continue
+		case b.Expiry < now:
+			results[i].Status = BALLOT_STATUS_EXPIRED
+			continue
+		case !verifier.VerifyBallot(b.Voter, BallotMessage(domain, b.Choice, b.Expiry), b.Signature):
+			results[i].Status = BALLOT_STATUS_INVALID_SIGNATURE
+			continue
+		case applied[b.Voter]:
+			results[i].Status = BALLOT_STATUS_DUPLICATE
+			continue
+		case store.HasVoted(domain.ProposalID, b.Voter):
+			results[i].Status = BALLOT_STATUS_ALREADY_VOTED
+			continue
+		}
+
+		weight := resolver.VotingPower(b.Voter, domain.ProposalID)
+		if b.WeightClaim > 0 && weight > b.WeightClaim {
+			weight = b.WeightClaim
+		}
+		if weight == 0 {
+			results[i].Status = BALLOT_STATUS_NO_VOTING_POWER
+			continue
+		}
+		if tally[b.Choice]+weight < tally[b.Choice] {
+			return nil, framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "tally overflow")
+		}
+
+		if err := store.RecordVote(domain.ProposalID, b.Voter, b.Choice, weight); err != nil {
+			return nil, err
+		}
+		applied[b.Voter] = true
+		tally[b.Choice] += weight
+		results[i].Status = BALLOT_STATUS_APPLIED
+		results[i].Weight = weight
+	}
+
+	for choice, weight := range tally {
+		if weight == 0 {
+			continue
+		}
+		if err := store.AddTally(domain.ProposalID, uint32(choice), weight); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// CountApplied 返回结果中已计票的选票数
+func CountApplied(results []BallotResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == BALLOT_STATUS_APPLIED {
+			n++
+		}
+	}
+	return n
+}
+
+// ==================== 内存存储 ====================
+
+// MemoryBallotStore 内存投票存储
+//
+// 🎯 **用途**：用于非WASM环境下的单元测试与离线模拟
+type MemoryBallotStore struct {
+	votes map[string]uint32
+	tally map[memoryTallyKey]uint64
+}
+
+type memoryTallyKey struct {
+	proposalID string
+	choice     uint32
+}
+
+// NewMemoryBallotStore 创建内存投票存储
+func NewMemoryBallotStore() *MemoryBallotStore {
+	return &MemoryBallotStore{
+		votes: make(map[string]uint32),
+		tally: make(map[memoryTallyKey]uint64),
+	}
+}
+
+// HasVoted 地址是否已对提案投票
+func (m *MemoryBallotStore) HasVoted(proposalID []byte, voter framework.Address) bool {
+	_, ok := m.votes[string(proposalID)+":"+string(voter[:])]
+	return ok
+}
+
+// RecordVote 记录投票
+func (m *MemoryBallotStore) RecordVote(proposalID []byte, voter framework.Address, choice uint32, weight uint64) error {
+	m.votes[string(proposalID)+":"+string(voter[:])] = choice
+	return nil
+}
+
+// AddTally 累加选项计票
+func (m *MemoryBallotStore) AddTally(proposalID []byte, choice uint32, weight uint64) error {
+	m.tally[memoryTallyKey{string(proposalID), choice}] += weight
+	return nil
+}
+
+// Tally 返回提案选项的计票
+func (m *MemoryBallotStore) Tally(proposalID []byte, choice uint32) uint64 {
+	return m.tally[memoryTallyKey{string(proposalID), choice}]
+}
@@ -0,0 +1,162 @@
+package governance
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// testKeyring 以投票者登记的 Ed25519 公钥校验签名
+type testKeyring map[framework.Address]ed25519.PublicKey
+
+func (k testKeyring) VerifyBallot(voter framework.Address, message []byte, signature []byte) bool {
+	pub, ok := k[voter]
+	return ok && len(signature) == ed25519.SignatureSize && ed25519.Verify(pub, message, signature)
+}
+
+// testPower 固定的投票权重表
+type testPower map[framework.Address]uint64
+
+func (p testPower) VotingPower(voter framework.Address, proposalID []byte) uint64 {
+	return p[voter]
+}
+
+type testVoter struct {
+	addr framework.Address
+	key  ed25519.PrivateKey
+}
+
+func newTestVoters(t *testing.T, n int) ([]testVoter, testKeyring) {
+	t.Helper()
+	voters := make([]testVoter, n)
+	keyring := testKeyring{}
+	for i := range voters {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i + 1)
+		key := ed25519.NewKeyFromSeed(seed)
+		voters[i] = testVoter{addr: framework.Address{byte(i + 1)}, key: key}
+		keyring[voters[i].addr] = key.Public().(ed25519.PublicKey)
+	}
+	return voters, keyring
+}
+
+func (v testVoter) ballot(domain BallotDomain, choice uint32, expiry uint64) Ballot {
+	return Ballot{
+		Voter:     v.addr,
+		Choice:    choice,
+		Expiry:    expiry,
+		Signature: ed25519.Sign(v.key, BallotMessage(domain, choice, expiry)),
+	}
+}
+
+// TestApplySignedBallotsMixedBatch 测试混合批次：仅有效且不重复的选票计票
+func TestApplySignedBallotsMixedBatch(t *testing.T) {
+	const now = 1_700_000_000
+	domain := BallotDomain{ChainID: []byte("wes-testnet"), Contract: framework.Address{0xC0}, ProposalID: []byte("proposal_1")}
+	voters, keyring := newTestVoters(t, 5)
+	power := testPower{voters[0].addr: 100, voters[1].addr: 40, voters[2].addr: 70, voters[3].addr: 10, voters[4].addr: 55}
+
+	wrongSig := voters[3].ballot(domain, 1, now+3600)
+	wrongSig.Signature = ed25519.Sign(voters[4].key, BallotMessage(domain, 1, now+3600)) // 他人私钥签名
+
+	otherProposal := domain
+	otherProposal.ProposalID = []byte("proposal_2")
+	replayed := voters[4].ballot(otherProposal, 1, now+3600) // 其他提案的签名不可重放
+
+	ballots := []Ballot{
+		voters[0].ballot(domain, 1, now+3600),
+		voters[1].ballot(domain, 0, now+3600),
+		voters[2].ballot(domain, 1, now-1), // 已过期
+		wrongSig,
+		voters[0].ballot(domain, 0, now+7200), // 批内重复
+		replayed,
+	}
+	ballots[1].WeightClaim = 25 // 声明权重作为上限
+
+	store := NewMemoryBallotStore()
+	results, err := ApplySignedBallots(store, domain, ballots, now, 2, keyring, power)
+	if err != nil {
+		t.Fatalf("ApplySignedBallots() error = %v", err)
+	}
+
+	want := []BallotResult{
+		{Voter: voters[0].addr, Status: BALLOT_STATUS_APPLIED, Weight: 100},
+		{Voter: voters[1].addr, Status: BALLOT_STATUS_APPLIED, Weight: 25},
+		{Voter: voters[2].addr, Status: BALLOT_STATUS_EXPIRED},
+		{Voter: voters[3].addr, Status: BALLOT_STATUS_INVALID_SIGNATURE},
+		{Voter: voters[0].addr, Status: BALLOT_STATUS_DUPLICATE},
+		{Voter: voters[4].addr, Status: BALLOT_STATUS_INVALID_SIGNATURE},
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+	if got := CountApplied(results); got != 2 {
+		t.Errorf("CountApplied() = %d, want 2", got)
+	}
+	if got := store.Tally(domain.ProposalID, 1); got != 100 {
+		t.Errorf("tally(support) = %d, want 100", got)
+	}
+	if got := store.Tally(domain.ProposalID, 0); got != 25 {
+		t.Errorf("tally(oppose) = %d, want 25", got)
+	}
+
+	// 再次提交同一批选票：已投票地址不再计票
+	results, err = ApplySignedBallots(store, domain, ballots[:2], now, 2, keyring, power)
+	if err != nil {
+		t.Fatalf("resubmit error = %v", err)
+	}
+	for i, r := range results {
+		if r.Status != BALLOT_STATUS_ALREADY_VOTED {
+			t.Errorf("resubmit results[%d] = %s, want ALREADY_VOTED", i, r.Status)
+		}
+	}
+	if got := store.Tally(domain.ProposalID, 1); got != 100 {
+		t.Errorf("tally(support) after resubmit = %d, want 100", got)
+	}
+}
+
+// TestApplySignedBallotsRejections 测试选项越界、零权重与批次参数校验
+func TestApplySignedBallotsRejections(t *testing.T) {
+	domain := BallotDomain{ChainID: []byte("wes-testnet"), Contract: framework.Address{0xC0}, ProposalID: []byte("proposal_1")}
+	voters, keyring := newTestVoters(t, 2)
+
+	results, err := ApplySignedBallots(NewMemoryBallotStore(), domain, []Ballot{
+		voters[0].ballot(domain, 2, 10),
+		voters[1].ballot(domain, 1, 10),
+	}, 5, 2, keyring, testPower{})
+	if err != nil {
+		t.Fatalf("ApplySignedBallots() error = %v", err)
+	}
+	if results[0].Status != BALLOT_STATUS_INVALID_CHOICE || results[1].Status != BALLOT_STATUS_NO_VOTING_POWER {
+		t.Errorf("results = %+v, want INVALID_CHOICE, NO_VOTING_POWER", results)
+	}
+
+	if _, err := ApplySignedBallots(NewMemoryBallotStore(), domain, nil, 5, 2, keyring, testPower{}); err == nil {
+		t.Error("empty batch should fail")
+	}
+	if _, err := ApplySignedBallots(NewMemoryBallotStore(), domain, make([]Ballot, MAX_BALLOTS_PER_BATCH+1), 5, 2, keyring, testPower{}); err == nil {
+		t.Error("oversized batch should fail")
+	}
+}
+
+// TestBallotMessageBindsDomain 测试签名消息绑定链ID、合约、提案、选项与过期时间
+func TestBallotMessageBindsDomain(t *testing.T) {
+	base := BallotDomain{ChainID: []byte("wes"), Contract: framework.Address{1}, ProposalID: []byte("p")}
+	msg := string(BallotMessage(base, 1, 100))
+
+	variants := map[string]string{
+		"chain":    string(BallotMessage(BallotDomain{ChainID: []byte("wes2"), Contract: base.Contract, ProposalID: base.ProposalID}, 1, 100)),
+		"contract": string(BallotMessage(BallotDomain{ChainID: base.ChainID, Contract: framework.Address{2}, ProposalID: base.ProposalID}, 1, 100)),
+		"proposal": string(BallotMessage(BallotDomain{ChainID: base.ChainID, Contract: base.Contract, ProposalID: []byte("q")}, 1, 100)),
+		"choice":   string(BallotMessage(base, 0, 100)),
+		"expiry":   string(BallotMessage(base, 1, 101)),
+	}
+	for name, v := range variants {
+		if v == msg {
+			t.Errorf("changing %s did not change the ballot message", name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+//go:build tinygo || (js && wasm)
+
+package governance
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// SubmitSignedBallots 批量提交链下签名选票
+//
+// 🎯 **用途**：协调者收集持币者链下签名的选票后一次性提交，投票者无需逐一发送交易
+//
+// **参数**：
+//   - proposalID: 提案ID
+//   - ballots: 签名选票，最多 MAX_BALLOTS_PER_BATCH 张
+//   - numChoices: 提案选项数（二元提案为 2：0=反对，1=支持）
+//   - verifier: 签名校验，由合约提供签名方案
+//   - resolver: 投票权重解析，WeightClaim 仅作为上限
+//
+// **返回**：
+//   - results: 与 ballots 一一对应的处理结果（APPLIED / EXPIRED / INVALID_SIGNATURE / ...）
+//   - error: 参数无效或状态写入失败
+//
+// **注意**：
+//   - 签名域为 (GetChainID, GetContractAddress, proposalID)，见 BallotMessage
+//   - 投票记录与 Vote 共用状态ID vote:{voter}:{proposal_id}，已通过 Vote 投票的地址不会被重复计票
+//   - 计票写入 tally:{proposal_id}:{choice}（8字节大端累计权重）
+//   - 每张计入的选票发出 Vote 事件（signed=true），整批发出 SignedBallotsSubmitted 事件
+//
+// **示例**：
+//
+//	results, err := governance.SubmitSignedBallots(proposalID, ballots, 2, keyVerifier{}, balanceResolver{})
+//	if err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func SubmitSignedBallots(
+	proposalID []byte,
+	ballots []Ballot,
+	numChoices uint32,
+	verifier BallotVerifier,
+	resolver VotingPowerResolver,
+) ([]BallotResult, error) {
+	domain := BallotDomain{
+		ChainID:    framework.GetChainID(),
+		Contract:   framework.GetContractAddress(),
+		ProposalID: proposalID,
+	}
+	results, err := ApplySignedBallots(hostBallotStore{}, domain, ballots, framework.GetTimestamp(), numChoices, verifier, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	caller := framework.GetCaller()
+	for i, r := range results {
+		if r.Status != BALLOT_STATUS_APPLIED {
+			continue
+		}
+		event := framework.NewEvent("Vote")
+		event.AddAddressField("voter", r.Voter)
+		event.AddField("proposal_id", string(proposalID))
+		event.AddField("support", ballots[i].Choice == 1)
+		event.AddUint64Field("choice", uint64(ballots[i].Choice))
+		event.AddUint64Field("weight", r.Weight)
+		event.AddField("signed", true)
+		event.AddAddressField("caller", caller)
+		framework.EmitEvent(event)
+	}
+
+	event := framework.NewEvent("SignedBallotsSubmitted")
+	event.AddField("proposal_id", string(proposalID))
+	event.AddUint64Field("submitted", uint64(len(ballots)))
+	event.AddUint64Field("applied", uint64(CountApplied(results)))
+	event.AddAddressField("submitter", caller)
+	framework.EmitEvent(event)
+
+	return results, nil
+}
+
+// GetTally 查询提案选项的累计权重
+func GetTally(proposalID []byte, choice uint32) uint64 {
+	weight, _ := loadTally(proposalID, choice)
+	return weight
+}
+
+// hostBallotStore 基于宿主状态的投票存储
+type hostBallotStore struct{}
+
+// HasVoted 投票记录存在即视为已投票（含通过 Vote 写入的记录）
+func (hostBallotStore) HasVoted(proposalID []byte, voter framework.Address) bool {
+	value, _, err := framework.GetStateFromChain(buildVoteStateID(voter, proposalID))
+	return err == nil && len(value) > 0
+}
+
+// RecordVote 写入投票记录：choice(4) + weight(8)，大端
+func (hostBallotStore) RecordVote(proposalID []byte, voter framework.Address, choice uint32, weight uint64) error {
+	value := append([]byte{byte(choice >> 24), byte(choice >> 16), byte(choice >> 8), byte(choice)}, encodeUint64(weight)...)
+	_, err := framework.AppendStateOutputSimple(buildVoteStateID(voter, proposalID), 1, value, nil)
+	return err
+}
+
+// AddTally 累加选项计票
+func (hostBallotStore) AddTally(proposalID []byte, choice uint32, weight uint64) error {
+	current, version := loadTally(proposalID, choice)
+	if current+weight < current {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "tally overflow")
+	}
+	_, err := framework.AppendStateOutputSimple(buildTallyStateID(proposalID, choice), version+1, encodeUint64(current+weight), nil)
+	return err
+}
+
+// loadTally 读取选项计票及状态版本，不存在时为 0
+func loadTally(proposalID []byte, choice uint32) (uint64, uint64) {
+	value, version, err := framework.GetStateFromChain(buildTallyStateID(proposalID, choice))
+	if err != nil || len(value) == 0 {
+		return 0, 0
+	}
+	// 链上读取会去除尾部零字节，补齐到8字节
+	buf := make([]byte, 8)
+	copy(buf, value)
+	var weight uint64
+	for _, b := range buf {
+		weight = weight<<8 | uint64(b)
+	}
+	return weight, version
+}
+
+// buildTallyStateID 构建计票状态ID
+func buildTallyStateID(proposalID []byte, choice uint32) []byte {
+	return []byte("tally:" + string(proposalID) + ":" + framework.Uint64ToString(uint64(choice)))
+}
+
+// encodeUint64 8字节大端编码
+func encodeUint64(v uint64) []byte {
+	buf := make([]byte, 8)
+	for i := 0; i < 8; i++ {
+		buf[i] = byte(v >> (56 - 8*i))
+	}
+	return buf
+}
@@ -0,0 +1,354 @@
+package market
+
+import (
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 清算拍卖（纯状态机） ====================
+//
+// 降价拍卖出售被没收的抵押品：价格从起始价格随时间线性下降，任何人都可以按当前价格
+// 偿还部分债务换取抵押品（允许部分成交），直到债务目标达成、抵押品售罄或拍卖到期。
+// 结算时剩余抵押品退还原所有者，未偿还的债务记为缺口（shortfall）。
+//
+// 本文件只包含不依赖宿主函数的拍卖记录与状态迁移，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金划转与状态写入见 liquidation.go。
+
+// 拍卖状态
+const (
+	// AUCTION_STATUS_ACTIVE 拍卖进行中
+	AUCTION_STATUS_ACTIVE = "ACTIVE"
+	// AUCTION_STATUS_SETTLED 已结算：剩余抵押品已退还，缺口已确定
+	AUCTION_STATUS_SETTLED = "SETTLED"
+)
+
+// AUCTION_BP 基点分母（10000 = 100%）
+const AUCTION_BP = 10000
+
+// AuctionParams 降价拍卖参数
+//
+// 价格以基点表示，相对保本价格（债务目标 / 抵押品数量，即全部抵押品恰好偿还全部债务的价格）：
+//   - StartPriceBP = 15000：起始时每单位抵押品要求偿还 1.5 倍保本价格的债务
+//   - DecayBP = 4000：到期时价格降至起始价格的 60%
+type AuctionParams struct {
+	// Duration 拍卖时长（秒），到期后不再接受出价
+	Duration uint64
+	// StartPriceBP 起始价格（基点，相对保本价格）
+	StartPriceBP uint64
+	// DecayBP 整个拍卖期内价格下降的比例（基点，相对起始价格），必须小于 10000
+	DecayBP uint64
+}
+
+// LiquidationAuction 清算拍卖记录
+type LiquidationAuction struct {
+	AuctionID string
+	Status    string
+	// Owner 抵押品原所有者，结算时剩余抵押品退还该地址
+	Owner            framework.Address
+	CollateralToken  framework.TokenID
+	CollateralAmount framework.Amount
+	DebtToken        framework.TokenID
+	DebtTarget       framework.Amount
+	StartTime        uint64
+	Duration         uint64
+	StartPriceBP     uint64
+	DecayBP          uint64
+	// CollateralSold 已售出的抵押品
+	CollateralSold framework.Amount
+	// DebtRaised 已收回的债务
+	DebtRaised framework.Amount
+	// Surplus 结算时退还原所有者的抵押品
+	Surplus framework.Amount
+	// Shortfall 结算时仍未收回的债务
+	Shortfall framework.Amount
+}
+
+// AuctionFill 一次出价的成交结果
+type AuctionFill struct {
+	// DebtPaid 出价方实际偿还的债务（不超过出价与剩余债务）
+	DebtPaid framework.Amount
+	// CollateralOut 出价方获得的抵押品
+	CollateralOut framework.Amount
+	// PriceBP 成交价格（基点，相对保本价格）
+	PriceBP uint64
+}
+
+// AuctionSettlement 拍卖结算结果
+type AuctionSettlement struct {
+	// Surplus 退还原所有者的剩余抵押品
+	Surplus framework.Amount
+	// Shortfall 未收回的债务缺口
+	Shortfall framework.Amount
+}
+
+// NewLiquidationAuction 创建处于 ACTIVE 状态的清算拍卖记录
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewLiquidationAuction(auctionID string, owner framework.Address, collateralToken framework.TokenID, collateralAmount framework.Amount, debtToken framework.TokenID, debtTarget framework.Amount, params AuctionParams, now uint64) (*LiquidationAuction, error) {
+	if auctionID == "" || len(auctionID) > 0xFFFF || len(collateralToken) > 0xFFFF || len(debtToken) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid auctionID or tokenID")
+	}
+	if owner == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "owner address cannot be zero")
+	}
+	if collateralAmount == 0 || debtTarget == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "collateral amount and debt target must be positive")
+	}
+	if collateralToken == debtToken {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "collateral and debt tokens must differ")
+	}
+	if params.Duration == 0 || params.StartPriceBP == 0 || params.DecayBP >= AUCTION_BP {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid auction params")
+	}
+	if now+params.Duration < now {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "auction duration overflow")
+	}
+
+	return &LiquidationAuction{
+		AuctionID:        auctionID,
+		Status:           AUCTION_STATUS_ACTIVE,
+		Owner:            owner,
+		CollateralToken:  collateralToken,
+		CollateralAmount: collateralAmount,
+		DebtToken:        debtToken,
+		DebtTarget:       debtTarget,
+		StartTime:        now,
+		Duration:         params.Duration,
+		StartPriceBP:     params.StartPriceBP,
+		DecayBP:          params.DecayBP,
+	}, nil
+}
+
+// EndTime 拍卖到期时间
+func (a *LiquidationAuction) EndTime() uint64 {
+	return a.StartTime + a.Duration
+}
+
+// RemainingCollateral 尚未售出的抵押品
+func (a *LiquidationAuction) RemainingCollateral() framework.Amount {
+	return a.CollateralAmount - a.CollateralSold
+}
+
+// RemainingDebt 尚未收回的债务
+func (a *LiquidationAuction) RemainingDebt() framework.Amount {
+	return a.DebtTarget - a.DebtRaised
+}
+
+// Ended 拍卖是否已结束：债务目标达成、抵押品售罄或已到期
+func (a *LiquidationAuction) Ended(now uint64) bool {
+	return a.RemainingDebt() == 0 || a.RemainingCollateral() == 0 || now >= a.EndTime()
+}
+
+// PriceBP 当前价格（基点，相对保本价格）
+//
+// 价格 = 起始价格 - 起始价格 × DecayBP × 已过时间 / 拍卖时长，到期后保持最低价格
+func (a *LiquidationAuction) PriceBP(now uint64) uint64 {
+	elapsed := uint64(0)
+	if now > a.StartTime {
+		elapsed = now - a.StartTime
+	}
+	if elapsed > a.Duration {
+		elapsed = a.Duration
+	}
+	totalDecay, _ := mulDiv(a.StartPriceBP, a.DecayBP, AUCTION_BP)
+	decay, _ := mulDiv(totalDecay, elapsed, a.Duration)
+	return a.StartPriceBP - decay
+}
+
+// Bid 按当前价格偿还债务换取抵押品
+//
+// **参数**：
+//   - debtAmount: 出价方愿意偿还的债务，超过剩余债务时按剩余债务成交
+//   - now: 当前时间
+//
+// **返回**：
+//   - AuctionFill: 实际偿还的债务与获得的抵押品；剩余抵押品不足时只成交剩余部分，
+//     偿还的债务相应减少（向上取整，舍入有利于原所有者）
+//   - error: 拍卖已结算或已售罄（ERROR_INVALID_STATE）、已到期（ERROR_TIMEOUT）、
+//     出价为 0 或过小换不到抵押品（ERROR_INVALID_PARAMS）
+func (a *LiquidationAuction) Bid(debtAmount framework.Amount, now uint64) (AuctionFill, error) {
+	if a.Status != AUCTION_STATUS_ACTIVE || a.RemainingDebt() == 0 || a.RemainingCollateral() == 0 {
+		return AuctionFill{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "auction is not active")
+	}
+	if now >= a.EndTime() {
+		return AuctionFill{}, framework.NewContractError(framework.ERROR_TIMEOUT, "auction has ended")
+	}
+	if debtAmount == 0 {
+		return AuctionFill{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "bid amount must be positive")
+	}
+
+	price := a.PriceBP(now)
+	pay := debtAmount
+	if pay > a.RemainingDebt() {
+		pay = a.RemainingDebt()
+	}
+
+	// 按当前价格折算抵押品（向下取整）：pay × 抵押品数量 / 债务目标 × 10000 / 价格
+	out := a.collateralFor(pay, price)
+	if out >= a.RemainingCollateral() {
+		out = a.RemainingCollateral()
+		if need := a.debtFor(out, price); need < pay {
+			pay = need
+		}
+	}
+	if out == 0 {
+		return AuctionFill{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "bid too small to buy collateral")
+	}
+
+	a.CollateralSold += out
+	a.DebtRaised += pay
+	return AuctionFill{DebtPaid: pay, CollateralOut: out, PriceBP: price}, nil
+}
+
+// Settle 结算已结束的拍卖
+//
+// **返回**：
+//   - AuctionSettlement: 退还原所有者的剩余抵押品与未收回的债务缺口
+//   - error: 已结算或尚未结束返回 ERROR_INVALID_STATE
+func (a *LiquidationAuction) Settle(now uint64) (AuctionSettlement, error) {
+	if a.Status != AUCTION_STATUS_ACTIVE {
+		return AuctionSettlement{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "auction already settled")
+	}
+	if !a.Ended(now) {
+		return AuctionSettlement{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "auction has not ended")
+	}
+	a.Status = AUCTION_STATUS_SETTLED
+	a.Surplus = a.RemainingCollateral()
+	a.Shortfall = a.RemainingDebt()
+	return AuctionSettlement{Surplus: a.Surplus, Shortfall: a.Shortfall}, nil
+}
+
+// collateralFor 按价格 price 偿还 debt 可获得的抵押品（向下取整，溢出时视为无穷大）
+func (a *LiquidationAuction) collateralFor(debt framework.Amount, price uint64) framework.Amount {
+	atPar, _ := mulDiv(uint64(debt), uint64(a.CollateralAmount), uint64(a.DebtTarget))
+	out, ok := mulDiv(atPar, AUCTION_BP, price)
+	if !ok {
+		return ^framework.Amount(0)
+	}
+	return framework.Amount(out)
+}
+
+// debtFor 按价格 price 购买 collateral 需要偿还的债务（向上取整，溢出时视为无穷大）
+func (a *LiquidationAuction) debtFor(collateral framework.Amount, price uint64) framework.Amount {
+	atPrice, ok := mulDivUp(uint64(collateral), price, AUCTION_BP)
+	if !ok {
+		return ^framework.Amount(0)
+	}
+	debt, ok := mulDivUp(atPrice, uint64(a.DebtTarget), uint64(a.CollateralAmount))
+	if !ok {
+		return ^framework.Amount(0)
+	}
+	return framework.Amount(debt)
+}
+
+// mulDiv 计算 a × b / c（128位中间结果，向下取整）；结果超出 uint64 时返回 false
+func mulDiv(a, b, c uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	q, _ := bits.Div64(hi, lo, c)
+	return q, true
+}
+
+// mulDivUp 计算 a × b / c（128位中间结果，向上取整）；结果超出 uint64 时返回 false
+func mulDivUp(a, b, c uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, c)
+	if r == 0 {
+		return q, true
+	}
+	if q == ^uint64(0) {
+		return 0, false
+	}
+	return q + 1, true
+}
+
+// ==================== 编码 ====================
+
+// encodeLiquidationAuction 编码拍卖记录
+//
+// 编码格式（大端）：
+//
+//	statusLen(1) + status + owner(20) +
+//	collateralAmount(8) + debtTarget(8) + startTime(8) + duration(8) + startPriceBP(8) + decayBP(8) +
+//	collateralSold(8) + debtRaised(8) + surplus(8) + shortfall(8) +
+//	collateralTokenLen(2) + collateralToken + debtTokenLen(2) + debtToken + auctionIDLen(2) + auctionID
+//
+// 记录以非空的拍卖ID结尾，链上读取去掉尾部零字节不会截断记录
+func encodeLiquidationAuction(a *LiquidationAuction) []byte {
+	data := make([]byte, 0, 1+len(a.Status)+20+80+6+len(a.CollateralToken)+len(a.DebtToken)+len(a.AuctionID))
+	data = append(data, byte(len(a.Status)))
+	data = append(data, a.Status...)
+	data = append(data, a.Owner[:]...)
+	for _, v := range []uint64{
+		uint64(a.CollateralAmount), uint64(a.DebtTarget), a.StartTime, a.Duration, a.StartPriceBP, a.DecayBP,
+		uint64(a.CollateralSold), uint64(a.DebtRaised), uint64(a.Surplus), uint64(a.Shortfall),
+	} {
+		data = appendUint64(data, v)
+	}
+	for _, s := range []string{string(a.CollateralToken), string(a.DebtToken), a.AuctionID} {
+		data = append(data, byte(len(s)>>8), byte(len(s)))
+		data = append(data, s...)
+	}
+	return data
+}
+
+// decodeLiquidationAuction 解码拍卖记录
+func decodeLiquidationAuction(data []byte) (*LiquidationAuction, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid liquidation auction record")
+	if len(data) < 1 {
+		return nil, invalid
+	}
+	pos := 1 + int(data[0])
+	// owner(20) + 10个金额/时间/参数字段(80)
+	if len(data) < pos+20+80 {
+		return nil, invalid
+	}
+
+	a := &LiquidationAuction{Status: string(data[1:pos])}
+	copy(a.Owner[:], data[pos:pos+20])
+	pos += 20
+
+	values := make([]uint64, 10)
+	for i := range values {
+		values[i] = readUint64(data[pos : pos+8])
+		pos += 8
+	}
+	a.CollateralAmount = framework.Amount(values[0])
+	a.DebtTarget = framework.Amount(values[1])
+	a.StartTime = values[2]
+	a.Duration = values[3]
+	a.StartPriceBP = values[4]
+	a.DecayBP = values[5]
+	a.CollateralSold = framework.Amount(values[6])
+	a.DebtRaised = framework.Amount(values[7])
+	a.Surplus = framework.Amount(values[8])
+	a.Shortfall = framework.Amount(values[9])
+
+	strs := make([]string, 3)
+	for i := range strs {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(data[pos])<<8 | int(data[pos+1])
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		strs[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	a.CollateralToken = framework.TokenID(strs[0])
+	a.DebtToken = framework.TokenID(strs[1])
+	a.AuctionID = strs[2]
+	if a.AuctionID == "" {
+		return nil, invalid
+	}
+	return a, nil
+}
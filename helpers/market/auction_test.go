@@ -0,0 +1,162 @@
+package market
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var testAuctionParams = AuctionParams{Duration: 1000, StartPriceBP: 15000, DecayBP: 4000}
+
+func newTestAuction(t *testing.T, collateral, debt framework.Amount) *LiquidationAuction {
+	t.Helper()
+	auction, err := NewLiquidationAuction("auction_1", testSeller, "WES", collateral, "USDT", debt, testAuctionParams, 0)
+	if err != nil {
+		t.Fatalf("NewLiquidationAuction() error = %v", err)
+	}
+	return auction
+}
+
+func mustBid(t *testing.T, a *LiquidationAuction, debt framework.Amount, now uint64, want AuctionFill) {
+	t.Helper()
+	fill, err := a.Bid(debt, now)
+	if err != nil {
+		t.Fatalf("Bid(%d) at %d error = %v", debt, now, err)
+	}
+	if fill != want {
+		t.Errorf("Bid(%d) at %d = %+v, want %+v", debt, now, fill, want)
+	}
+}
+
+// TestAuctionPriceDecay 测试价格从起始价格线性下降，到期后保持最低价格
+func TestAuctionPriceDecay(t *testing.T) {
+	a := newTestAuction(t, 1000, 800)
+	for now, want := range map[uint64]uint64{0: 15000, 250: 13500, 500: 12000, 1000: 9000, 5000: 9000} {
+		if got := a.PriceBP(now); got != want {
+			t.Errorf("PriceBP(%d) = %d, want %d", now, got, want)
+		}
+	}
+}
+
+// TestAuctionPartialFillsAcrossDecay 测试价格下降过程中多次部分成交，债务目标达成后剩余抵押品退还原所有者
+func TestAuctionPartialFillsAcrossDecay(t *testing.T) {
+	a := newTestAuction(t, 1000, 800)
+
+	// 保本价格 0.8 USDT/WES：起始价格 150% 时 300 USDT 换 250 WES，降至 120% 时换 312 WES
+	mustBid(t, a, 300, 0, AuctionFill{DebtPaid: 300, CollateralOut: 250, PriceBP: 15000})
+	mustBid(t, a, 300, 500, AuctionFill{DebtPaid: 300, CollateralOut: 312, PriceBP: 12000})
+	if a.Ended(500) {
+		t.Fatal("auction ended before debt target was met")
+	}
+
+	// 出价超过剩余债务时按剩余债务成交
+	mustBid(t, a, 1000, 900, AuctionFill{DebtPaid: 200, CollateralOut: 260, PriceBP: 9600})
+	if !a.Ended(900) || a.DebtRaised != 800 || a.CollateralSold != 822 {
+		t.Fatalf("after fills: ended = %v, raised = %d, sold = %d", a.Ended(900), a.DebtRaised, a.CollateralSold)
+	}
+	if _, err := a.Bid(1, 901); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Bid() after debt target met error = %v, want ERROR_INVALID_STATE", err)
+	}
+
+	settlement, err := a.Settle(900)
+	if err != nil {
+		t.Fatalf("Settle() error = %v", err)
+	}
+	if want := (AuctionSettlement{Surplus: 178, Shortfall: 0}); settlement != want {
+		t.Errorf("Settle() = %+v, want %+v", settlement, want)
+	}
+	if a.Status != AUCTION_STATUS_SETTLED {
+		t.Errorf("status = %s, want SETTLED", a.Status)
+	}
+	if _, err := a.Settle(900); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Settle() error = %v, want ERROR_INVALID_STATE", err)
+	}
+}
+
+// TestAuctionCollateralRunsOut 测试抵押品不足时只成交剩余抵押品，偿还的债务向上取整，缺口计入结算
+func TestAuctionCollateralRunsOut(t *testing.T) {
+	a := newTestAuction(t, 100, 800)
+
+	// 价格 96% 时 800 USDT 可换 104 WES，只剩 100 WES：按 100 WES 成交需偿还 768 USDT
+	mustBid(t, a, 800, 900, AuctionFill{DebtPaid: 768, CollateralOut: 100, PriceBP: 9600})
+	settlement, err := a.Settle(900)
+	if err != nil {
+		t.Fatalf("Settle() error = %v", err)
+	}
+	if want := (AuctionSettlement{Surplus: 0, Shortfall: 32}); settlement != want {
+		t.Errorf("Settle() = %+v, want %+v", settlement, want)
+	}
+}
+
+// TestAuctionUnfilledShortfall 测试拍卖到期仍未收回全部债务：不再接受出价，结算退还剩余抵押品并报告缺口
+func TestAuctionUnfilledShortfall(t *testing.T) {
+	a := newTestAuction(t, 100, 800)
+	mustBid(t, a, 500, 0, AuctionFill{DebtPaid: 500, CollateralOut: 41, PriceBP: 15000})
+
+	if _, err := a.Settle(999); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Settle() before end error = %v, want ERROR_INVALID_STATE", err)
+	}
+	if _, err := a.Bid(100, 1000); errCode(err) != framework.ERROR_TIMEOUT {
+		t.Errorf("Bid() after end error = %v, want ERROR_TIMEOUT", err)
+	}
+
+	settlement, err := a.Settle(1000)
+	if err != nil {
+		t.Fatalf("Settle() error = %v", err)
+	}
+	if want := (AuctionSettlement{Surplus: 59, Shortfall: 300}); settlement != want {
+		t.Errorf("Settle() = %+v, want %+v", settlement, want)
+	}
+	if a.Surplus != 59 || a.Shortfall != 300 {
+		t.Errorf("recorded surplus/shortfall = %d/%d, want 59/300", a.Surplus, a.Shortfall)
+	}
+
+	// 无人出价直接到期：全部抵押品退还，全部债务为缺口
+	idle := newTestAuction(t, 100, 800)
+	if settlement, _ := idle.Settle(1000); settlement != (AuctionSettlement{Surplus: 100, Shortfall: 800}) {
+		t.Errorf("idle Settle() = %+v, want all surplus and full shortfall", settlement)
+	}
+}
+
+// TestAuctionInvalid 测试无效参数与过小的出价
+func TestAuctionInvalid(t *testing.T) {
+	for name, params := range map[string]AuctionParams{
+		"zero duration":    {Duration: 0, StartPriceBP: 15000, DecayBP: 4000},
+		"zero start price": {Duration: 1000, StartPriceBP: 0, DecayBP: 4000},
+		"full decay":       {Duration: 1000, StartPriceBP: 15000, DecayBP: AUCTION_BP},
+	} {
+		if _, err := NewLiquidationAuction("a", testSeller, "WES", 100, "USDT", 800, params, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: error = %v, want ERROR_INVALID_PARAMS", name, err)
+		}
+	}
+	if _, err := NewLiquidationAuction("a", testSeller, "USDT", 100, "USDT", 800, testAuctionParams, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("same token error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+
+	a := newTestAuction(t, 1000, 800)
+	if _, err := a.Bid(1, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("dust Bid() error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if a.DebtRaised != 0 || a.CollateralSold != 0 {
+		t.Errorf("rejected bid changed auction: raised = %d, sold = %d", a.DebtRaised, a.CollateralSold)
+	}
+}
+
+// TestLiquidationAuctionEncoding 测试编码往返，链上读取去掉尾部零字节后仍可解码
+func TestLiquidationAuctionEncoding(t *testing.T) {
+	a := newTestAuction(t, 1000, 800)
+	mustBid(t, a, 300, 0, AuctionFill{DebtPaid: 300, CollateralOut: 250, PriceBP: 15000})
+
+	data := encodeLiquidationAuction(a)
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	got, err := decodeLiquidationAuction(data)
+	if err != nil {
+		t.Fatalf("decodeLiquidationAuction() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, a) {
+		t.Errorf("decoded = %+v, want %+v", got, a)
+	}
+}
@@ -0,0 +1,351 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 带履约保证金的托管（纯状态机） ====================
+//
+// 本文件只包含不依赖宿主函数的托管记录与状态迁移，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金划转与状态写入见 bonded_escrow.go。
+
+// 托管状态
+const (
+	// ESCROW_STATUS_FUNDING 资金募集中：买方货款与卖方保证金尚未全部到位
+	ESCROW_STATUS_FUNDING = "FUNDING"
+	// ESCROW_STATUS_ACTIVE 两侧资金均已到位，托管生效
+	ESCROW_STATUS_ACTIVE = "ACTIVE"
+	// ESCROW_STATUS_RELEASED 交易完成：货款付给卖方，保证金退还卖方
+	ESCROW_STATUS_RELEASED = "RELEASED"
+	// ESCROW_STATUS_REFUNDED 交易取消：货款退还买方，保证金退还卖方
+	ESCROW_STATUS_REFUNDED = "REFUNDED"
+	// ESCROW_STATUS_RESOLVED 仲裁裁决：货款与保证金按裁决分别划分
+	ESCROW_STATUS_RESOLVED = "RESOLVED"
+	// ESCROW_STATUS_EXPIRED 募集超时：已到位的资金均已取回
+	ESCROW_STATUS_EXPIRED = "EXPIRED"
+)
+
+// 托管资金腿
+const (
+	// ESCROW_LEG_PAYMENT 买方货款
+	ESCROW_LEG_PAYMENT = "payment"
+	// ESCROW_LEG_BOND 卖方履约保证金
+	ESCROW_LEG_BOND = "bond"
+)
+
+// BondedEscrow 带卖方履约保证金的托管记录
+//
+// 🎯 **用途**：买方货款与卖方保证金两侧资金都到位后托管才生效（ACTIVE）：
+//   - 正常完成：货款付给卖方，保证金退还卖方
+//   - 取消退款：两侧资金各自退回出资方
+//   - 仲裁裁决：货款与保证金分别划分，例如卖方违约时保证金罚没给买方
+//
+// 募集截止时间（FundingDeadline）之后仍未全部到位的，双方可各自取回已出资的一侧。
+type BondedEscrow struct {
+	EscrowID        string
+	Status          string
+	Buyer           framework.Address
+	Seller          framework.Address
+	PaymentToken    framework.TokenID
+	PaymentAmount   framework.Amount
+	BondToken       framework.TokenID
+	BondAmount      framework.Amount
+	FundingDeadline uint64
+	PaymentFunded   bool
+	BondFunded      bool
+	// PaymentToBuyer 结算时退还买方的货款（其余付给卖方）
+	PaymentToBuyer framework.Amount
+	// BondToBuyer 结算时罚没给买方的保证金（其余退还卖方）
+	BondToBuyer framework.Amount
+}
+
+// EscrowPayout 一笔从托管中划出的资金
+type EscrowPayout struct {
+	Leg     string
+	To      framework.Address
+	TokenID framework.TokenID
+	Amount  framework.Amount
+}
+
+// NewBondedEscrow 创建处于 FUNDING 状态的托管记录
+//
+// **参数**：
+//   - bondAmount: 卖方保证金，0 表示不要求保证金（保证金一侧视为已到位）
+//   - fundingDeadline: 募集截止时间（时间戳），之后不能再出资
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewBondedEscrow(buyer, seller framework.Address, paymentToken framework.TokenID, paymentAmount framework.Amount, bondToken framework.TokenID, bondAmount framework.Amount, escrowID []byte, fundingDeadline uint64) (*BondedEscrow, error) {
+	if err := validateEscrowParams(buyer, seller, paymentAmount, escrowID); err != nil {
+		return nil, err
+	}
+	if fundingDeadline == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "funding deadline cannot be zero")
+	}
+	if len(escrowID) > 0xFFFF || len(paymentToken) > 0xFFFF || len(bondToken) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "escrowID or tokenID too long")
+	}
+
+	return &BondedEscrow{
+		EscrowID:        string(escrowID),
+		Status:          ESCROW_STATUS_FUNDING,
+		Buyer:           buyer,
+		Seller:          seller,
+		PaymentToken:    paymentToken,
+		PaymentAmount:   paymentAmount,
+		BondToken:       bondToken,
+		BondAmount:      bondAmount,
+		FundingDeadline: fundingDeadline,
+		BondFunded:      bondAmount == 0,
+	}, nil
+}
+
+// Fund 出资方将自己一侧的资金存入托管
+//
+// 买方存入货款，卖方存入保证金；两侧都到位后状态变为 ACTIVE。
+//
+// **返回**：
+//   - EscrowPayout: 需要从出资方转入托管的资金（To 为出资方）
+//   - error: 非募集状态（ERROR_INVALID_STATE）、已过截止时间（ERROR_TIMEOUT）、
+//     非托管双方（ERROR_UNAUTHORIZED）或该侧已出资（ERROR_ALREADY_EXISTS）
+func (e *BondedEscrow) Fund(party framework.Address, now uint64) (EscrowPayout, error) {
+	if e.Status != ESCROW_STATUS_FUNDING {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "escrow is not funding")
+	}
+	if now > e.FundingDeadline {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_TIMEOUT, "funding deadline passed")
+	}
+
+	var leg EscrowPayout
+	switch party {
+	case e.Buyer:
+		if e.PaymentFunded {
+			return EscrowPayout{}, framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "payment already funded")
+		}
+		e.PaymentFunded = true
+		leg = EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: e.Buyer, TokenID: e.PaymentToken, Amount: e.PaymentAmount}
+	case e.Seller:
+		if e.BondFunded {
+			return EscrowPayout{}, framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "bond already funded")
+		}
+		e.BondFunded = true
+		leg = EscrowPayout{Leg: ESCROW_LEG_BOND, To: e.Seller, TokenID: e.BondToken, Amount: e.BondAmount}
+	default:
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "party is neither buyer nor seller")
+	}
+
+	if e.PaymentFunded && e.BondFunded {
+		e.Status = ESCROW_STATUS_ACTIVE
+	}
+	return leg, nil
+}
+
+// Reclaim 募集超时后，出资方取回自己一侧已存入的资金
+//
+// 两侧资金均已取回（或从未存入）后状态变为 EXPIRED。
+//
+// **返回**：
+//   - EscrowPayout: 退还给出资方的资金
+//   - error: 非募集状态或该侧未出资（ERROR_INVALID_STATE）、
+//     截止时间未到（ERROR_INVALID_STATE）、非托管双方（ERROR_UNAUTHORIZED）
+func (e *BondedEscrow) Reclaim(party framework.Address, now uint64) (EscrowPayout, error) {
+	if e.Status != ESCROW_STATUS_FUNDING {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "escrow is not funding")
+	}
+	if now <= e.FundingDeadline {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "funding deadline not reached")
+	}
+
+	var leg EscrowPayout
+	switch {
+	case party == e.Buyer && e.PaymentFunded:
+		e.PaymentFunded = false
+		leg = EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: e.Buyer, TokenID: e.PaymentToken, Amount: e.PaymentAmount}
+	case party == e.Seller && e.BondFunded && e.BondAmount > 0:
+		e.BondFunded = false
+		leg = EscrowPayout{Leg: ESCROW_LEG_BOND, To: e.Seller, TokenID: e.BondToken, Amount: e.BondAmount}
+	case party == e.Buyer || party == e.Seller:
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "nothing to reclaim")
+	default:
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "party is neither buyer nor seller")
+	}
+
+	if !e.PaymentFunded && (!e.BondFunded || e.BondAmount == 0) {
+		e.Status = ESCROW_STATUS_EXPIRED
+	}
+	return leg, nil
+}
+
+// Release 交易完成：货款付给卖方，保证金退还卖方
+func (e *BondedEscrow) Release() ([]EscrowPayout, error) {
+	return e.settle(ESCROW_STATUS_RELEASED, 0, 0)
+}
+
+// Refund 交易取消：货款退还买方，保证金退还卖方
+func (e *BondedEscrow) Refund() ([]EscrowPayout, error) {
+	return e.settle(ESCROW_STATUS_REFUNDED, e.PaymentAmount, 0)
+}
+
+// Resolve 仲裁裁决：货款与保证金分别划分
+//
+// **参数**：
+//   - paymentToBuyer: 退还买方的货款，其余付给卖方
+//   - bondToBuyer: 罚没给买方的保证金，其余退还卖方
+//
+// **示例**：
+//
+//	// 卖方未交付：货款全额退还买方，保证金全部罚没给买方
+//	payouts, err := escrow.Resolve(escrow.PaymentAmount, escrow.BondAmount)
+func (e *BondedEscrow) Resolve(paymentToBuyer, bondToBuyer framework.Amount) ([]EscrowPayout, error) {
+	if paymentToBuyer > e.PaymentAmount || bondToBuyer > e.BondAmount {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "award exceeds escrowed amount")
+	}
+	return e.settle(ESCROW_STATUS_RESOLVED, paymentToBuyer, bondToBuyer)
+}
+
+// settle 结算 ACTIVE 托管并生成划款列表（金额为0的划款省略）
+func (e *BondedEscrow) settle(status string, paymentToBuyer, bondToBuyer framework.Amount) ([]EscrowPayout, error) {
+	if e.Status != ESCROW_STATUS_ACTIVE {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_STATE, "escrow is not active")
+	}
+
+	e.Status = status
+	e.PaymentToBuyer = paymentToBuyer
+	e.BondToBuyer = bondToBuyer
+
+	payouts := make([]EscrowPayout, 0, 4)
+	payouts = appendPayout(payouts, ESCROW_LEG_PAYMENT, e.Buyer, e.PaymentToken, paymentToBuyer)
+	payouts = appendPayout(payouts, ESCROW_LEG_PAYMENT, e.Seller, e.PaymentToken, e.PaymentAmount-paymentToBuyer)
+	payouts = appendPayout(payouts, ESCROW_LEG_BOND, e.Buyer, e.BondToken, bondToBuyer)
+	payouts = appendPayout(payouts, ESCROW_LEG_BOND, e.Seller, e.BondToken, e.BondAmount-bondToBuyer)
+	return payouts, nil
+}
+
+func appendPayout(payouts []EscrowPayout, leg string, to framework.Address, tokenID framework.TokenID, amount framework.Amount) []EscrowPayout {
+	if amount == 0 {
+		return payouts
+	}
+	return append(payouts, EscrowPayout{Leg: leg, To: to, TokenID: tokenID, Amount: amount})
+}
+
+// validateEscrowParams 验证托管参数
+func validateEscrowParams(buyer, seller framework.Address, amount framework.Amount, escrowID []byte) error {
+	zeroAddr := framework.Address{}
+	if buyer == zeroAddr {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer address cannot be zero")
+	}
+	if seller == zeroAddr {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "seller address cannot be zero")
+	}
+	if buyer == seller {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer and seller addresses cannot be the same")
+	}
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	if len(escrowID) == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "escrowID cannot be empty")
+	}
+	return nil
+}
+
+// ==================== 编码 ====================
+
+// encodeBondedEscrow 编码托管记录
+//
+// 编码格式（大端）：
+//
+//	statusLen(1) + status + flags(1) + buyer(20) + seller(20) +
+//	paymentAmount(8) + bondAmount(8) + fundingDeadline(8) + paymentToBuyer(8) + bondToBuyer(8) +
+//	paymentTokenLen(2) + paymentToken + bondTokenLen(2) + bondToken + escrowIDLen(2) + escrowID
+func encodeBondedEscrow(e *BondedEscrow) []byte {
+	data := make([]byte, 0, 1+len(e.Status)+1+40+40+6+len(e.PaymentToken)+len(e.BondToken)+len(e.EscrowID))
+	data = append(data, byte(len(e.Status)))
+	data = append(data, e.Status...)
+
+	var flags byte
+	if e.PaymentFunded {
+		flags |= 1
+	}
+	if e.BondFunded {
+		flags |= 2
+	}
+	data = append(data, flags)
+	data = append(data, e.Buyer[:]...)
+	data = append(data, e.Seller[:]...)
+	for _, v := range []uint64{uint64(e.PaymentAmount), uint64(e.BondAmount), e.FundingDeadline, uint64(e.PaymentToBuyer), uint64(e.BondToBuyer)} {
+		data = appendUint64(data, v)
+	}
+	for _, s := range []string{string(e.PaymentToken), string(e.BondToken), e.EscrowID} {
+		data = append(data, byte(len(s)>>8), byte(len(s)))
+		data = append(data, s...)
+	}
+	return data
+}
+
+// decodeBondedEscrow 解码托管记录
+func decodeBondedEscrow(data []byte) (*BondedEscrow, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid bonded escrow record")
+	if len(data) < 1 {
+		return nil, invalid
+	}
+	statusLen := int(data[0])
+	pos := 1 + statusLen
+	// flags(1) + buyer/seller(40) + 5个金额/时间字段(40)
+	if len(data) < pos+1+40+40 {
+		return nil, invalid
+	}
+
+	e := &BondedEscrow{Status: string(data[1:pos])}
+	flags := data[pos]
+	e.PaymentFunded = flags&1 != 0
+	e.BondFunded = flags&2 != 0
+	pos++
+	copy(e.Buyer[:], data[pos:pos+20])
+	copy(e.Seller[:], data[pos+20:pos+40])
+	pos += 40
+
+	values := make([]uint64, 5)
+	for i := range values {
+		values[i] = readUint64(data[pos : pos+8])
+		pos += 8
+	}
+	e.PaymentAmount = framework.Amount(values[0])
+	e.BondAmount = framework.Amount(values[1])
+	e.FundingDeadline = values[2]
+	e.PaymentToBuyer = framework.Amount(values[3])
+	e.BondToBuyer = framework.Amount(values[4])
+
+	strs := make([]string, 3)
+	for i := range strs {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(data[pos])<<8 | int(data[pos+1])
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		strs[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	e.PaymentToken = framework.TokenID(strs[0])
+	e.BondToken = framework.TokenID(strs[1])
+	e.EscrowID = strs[2]
+	return e, nil
+}
+
+func appendUint64(data []byte, v uint64) []byte {
+	for i := 7; i >= 0; i-- {
+		data = append(data, byte(v>>(i*8)))
+	}
+	return data
+}
+
+func readUint64(b []byte) uint64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
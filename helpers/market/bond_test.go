@@ -0,0 +1,182 @@
+package market
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var (
+	testBuyer  = framework.Address{1}
+	testSeller = framework.Address{2}
+)
+
+func newTestBondedEscrow(t *testing.T) *BondedEscrow {
+	t.Helper()
+	escrow, err := NewBondedEscrow(testBuyer, testSeller, "", 10000, "USDT", 2000, []byte("order_1"), 1000)
+	if err != nil {
+		t.Fatalf("NewBondedEscrow() error = %v", err)
+	}
+	return escrow
+}
+
+func errCode(err error) uint32 {
+	if ce, ok := err.(*framework.ContractError); ok {
+		return ce.Code
+	}
+	return framework.SUCCESS
+}
+
+// TestBondedEscrowHalfFundedTimeout 测试仅买方出资、超过募集截止时间后买方取回货款
+func TestBondedEscrowHalfFundedTimeout(t *testing.T) {
+	escrow := newTestBondedEscrow(t)
+
+	if _, err := escrow.Fund(testBuyer, 500); err != nil {
+		t.Fatalf("Fund(buyer) error = %v", err)
+	}
+	if escrow.Status != ESCROW_STATUS_FUNDING {
+		t.Fatalf("status after half funding = %s, want FUNDING", escrow.Status)
+	}
+
+	// 截止前不能取回
+	if _, err := escrow.Reclaim(testBuyer, 1000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Reclaim() before deadline error = %v, want ERROR_INVALID_STATE", err)
+	}
+	// 截止后卖方不能再出资
+	if _, err := escrow.Fund(testSeller, 1001); errCode(err) != framework.ERROR_TIMEOUT {
+		t.Errorf("Fund(seller) after deadline error = %v, want ERROR_TIMEOUT", err)
+	}
+	// 卖方未出资，无可取回
+	if _, err := escrow.Reclaim(testSeller, 1001); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Reclaim(seller) error = %v, want ERROR_INVALID_STATE", err)
+	}
+
+	leg, err := escrow.Reclaim(testBuyer, 1001)
+	if err != nil {
+		t.Fatalf("Reclaim(buyer) error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: testBuyer, TokenID: "", Amount: 10000}
+	if leg != want {
+		t.Errorf("Reclaim(buyer) = %+v, want %+v", leg, want)
+	}
+	if escrow.Status != ESCROW_STATUS_EXPIRED {
+		t.Errorf("status after reclaim = %s, want EXPIRED", escrow.Status)
+	}
+	if _, err := escrow.Release(); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Release() on expired escrow error = %v, want ERROR_INVALID_STATE", err)
+	}
+}
+
+// TestBondedEscrowArbitrationAwardsBond 测试仲裁将保证金罚没给买方、货款退还买方
+func TestBondedEscrowArbitrationAwardsBond(t *testing.T) {
+	escrow := newTestBondedEscrow(t)
+	if _, err := escrow.Release(); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Release() before funding error = %v, want ERROR_INVALID_STATE", err)
+	}
+	escrow.Fund(testSeller, 100)
+	escrow.Fund(testBuyer, 200)
+	if escrow.Status != ESCROW_STATUS_ACTIVE {
+		t.Fatalf("status after both legs funded = %s, want ACTIVE", escrow.Status)
+	}
+
+	if _, err := escrow.Resolve(10001, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Resolve() over award error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+
+	payouts, err := escrow.Resolve(escrow.PaymentAmount, escrow.BondAmount)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []EscrowPayout{
+		{Leg: ESCROW_LEG_PAYMENT, To: testBuyer, TokenID: "", Amount: 10000},
+		{Leg: ESCROW_LEG_BOND, To: testBuyer, TokenID: "USDT", Amount: 2000},
+	}
+	if !reflect.DeepEqual(payouts, want) {
+		t.Errorf("Resolve() payouts = %+v, want %+v", payouts, want)
+	}
+	if escrow.Status != ESCROW_STATUS_RESOLVED || escrow.BondToBuyer != 2000 {
+		t.Errorf("escrow after resolve = %+v", escrow)
+	}
+}
+
+// TestBondedEscrowSettlementPaths 测试完成与取消时两侧资金的去向
+func TestBondedEscrowSettlementPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		settle func(*BondedEscrow) ([]EscrowPayout, error)
+		want   []EscrowPayout
+	}{
+		{"release", (*BondedEscrow).Release, []EscrowPayout{
+			{Leg: ESCROW_LEG_PAYMENT, To: testSeller, Amount: 10000},
+			{Leg: ESCROW_LEG_BOND, To: testSeller, TokenID: "USDT", Amount: 2000},
+		}},
+		{"refund", (*BondedEscrow).Refund, []EscrowPayout{
+			{Leg: ESCROW_LEG_PAYMENT, To: testBuyer, Amount: 10000},
+			{Leg: ESCROW_LEG_BOND, To: testSeller, TokenID: "USDT", Amount: 2000},
+		}},
+		{"split", func(e *BondedEscrow) ([]EscrowPayout, error) { return e.Resolve(4000, 500) }, []EscrowPayout{
+			{Leg: ESCROW_LEG_PAYMENT, To: testBuyer, Amount: 4000},
+			{Leg: ESCROW_LEG_PAYMENT, To: testSeller, Amount: 6000},
+			{Leg: ESCROW_LEG_BOND, To: testBuyer, TokenID: "USDT", Amount: 500},
+			{Leg: ESCROW_LEG_BOND, To: testSeller, TokenID: "USDT", Amount: 1500},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escrow := newTestBondedEscrow(t)
+			escrow.Fund(testBuyer, 1)
+			escrow.Fund(testSeller, 1)
+
+			payouts, err := tt.settle(escrow)
+			if err != nil {
+				t.Fatalf("settle error = %v", err)
+			}
+			if !reflect.DeepEqual(payouts, tt.want) {
+				t.Errorf("payouts = %+v, want %+v", payouts, tt.want)
+			}
+			if _, err := escrow.Refund(); errCode(err) != framework.ERROR_INVALID_STATE {
+				t.Errorf("second settlement error = %v, want ERROR_INVALID_STATE", err)
+			}
+		})
+	}
+}
+
+// TestBondedEscrowWithoutBond 测试不要求保证金时买方出资即生效
+func TestBondedEscrowWithoutBond(t *testing.T) {
+	escrow, err := NewBondedEscrow(testBuyer, testSeller, "", 10000, "", 0, []byte("order_2"), 1000)
+	if err != nil {
+		t.Fatalf("NewBondedEscrow() error = %v", err)
+	}
+	if _, err := escrow.Fund(testSeller, 1); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("Fund(seller) error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+	escrow.Fund(testBuyer, 1)
+	if escrow.Status != ESCROW_STATUS_ACTIVE {
+		t.Errorf("status = %s, want ACTIVE", escrow.Status)
+	}
+	if _, err := escrow.Fund(framework.Address{3}, 1); err == nil {
+		t.Error("Fund() by third party should fail")
+	}
+}
+
+// TestBondedEscrowCodec 测试托管记录编解码往返
+func TestBondedEscrowCodec(t *testing.T) {
+	escrow := newTestBondedEscrow(t)
+	escrow.Fund(testBuyer, 1)
+	escrow.Fund(testSeller, 1)
+	escrow.Resolve(3000, 2000)
+
+	decoded, err := decodeBondedEscrow(encodeBondedEscrow(escrow))
+	if err != nil {
+		t.Fatalf("decodeBondedEscrow() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, escrow) {
+		t.Errorf("decoded = %+v, want %+v", decoded, escrow)
+	}
+
+	if _, err := decodeBondedEscrow([]byte{6, 'A'}); err == nil {
+		t.Error("decodeBondedEscrow() on truncated data should fail")
+	}
+}
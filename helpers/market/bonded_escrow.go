@@ -0,0 +1,255 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// EscrowWithBond 创建带卖方履约保证金的托管
+//
+// 🎯 **用途**：买方托管货款的同时，要求卖方缴纳履约保证金，卖方违约时保证金可罚没给买方
+//
+// **参数**：
+//   - buyer: 买方地址（出资货款）
+//   - seller: 卖方地址（出资保证金）
+//   - paymentToken / paymentAmount: 货款代币与金额
+//   - bondToken / bondAmount: 保证金代币与金额（0 表示不要求保证金）
+//   - escrowID: 托管ID（由合约生成）
+//   - fundingDeadline: 募集截止时间，之后未到位的托管可由双方取回已出资的一侧
+//
+// **返回**：
+//   - error: 错误信息，nil表示成功
+//
+// **注意**：
+//   - 创建后状态为 FUNDING，双方分别调用 FundEscrow 存入各自一侧，两侧到位后变为 ACTIVE
+//   - 结算路径：ReleaseEscrow（完成）、RefundEscrow（取消）、ResolveEscrow（仲裁）
+//   - 资金由合约地址托管；权限控制（谁可以结算/仲裁）是业务逻辑，需要在合约代码中实现
+//
+// **示例**：
+//
+//	err := market.EscrowWithBond(
+//	    buyer, seller,
+//	    "", framework.Amount(10000), // 货款（原生币）
+//	    "", framework.Amount(2000),  // 保证金（原生币）
+//	    []byte("order_123"),
+//	    framework.GetTimestamp()+86400,
+//	)
+func EscrowWithBond(buyer, seller framework.Address, paymentToken framework.TokenID, paymentAmount framework.Amount, bondToken framework.TokenID, bondAmount framework.Amount, escrowID []byte, fundingDeadline uint64) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	// 1. 参数验证
+	escrow, err := NewBondedEscrow(buyer, seller, paymentToken, paymentAmount, bondToken, bondAmount, escrowID, fundingDeadline)
+	if err != nil {
+		return err
+	}
+	if fundingDeadline <= framework.GetTimestamp() {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "funding deadline must be in the future")
+	}
+
+	// 2. 托管ID唯一性检查
+	stateID := buildBondedEscrowStateID(escrowID)
+	if _, version, err := framework.GetStateFromChain(stateID); err == nil && version > 0 {
+		return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "escrow already exists")
+	}
+
+	// 3. 写入托管记录
+	if _, err := framework.AppendStateOutputSimple(stateID, 1, encodeBondedEscrow(escrow), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save escrow")
+	}
+
+	// 4. 发出事件
+	event := newBondedEscrowEvent("BondedEscrowCreated", escrow)
+	event.AddUint64Field("funding_deadline", fundingDeadline)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// FundEscrow 出资方将自己一侧的资金存入托管
+//
+// 买方存入货款，卖方存入保证金，资金转入合约地址；两侧到位后托管变为 ACTIVE。
+//
+// **返回**：
+//   - error: 余额不足返回 ERROR_INSUFFICIENT_BALANCE，已过截止时间返回 ERROR_TIMEOUT
+func FundEscrow(escrowID []byte, party framework.Address) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	escrow, version, err := loadBondedEscrow(escrowID)
+	if err != nil {
+		return err
+	}
+
+	leg, err := escrow.Fund(party, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+
+	builder := framework.BeginTransaction()
+	if leg.Amount > 0 {
+		if framework.QueryUTXOBalance(party, leg.TokenID) < leg.Amount {
+			return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to fund escrow")
+		}
+		builder = builder.Transfer(party, framework.GetContractAddress(), leg.TokenID, leg.Amount)
+	}
+	if err := commitBondedEscrow(builder, escrow, version); err != nil {
+		return err
+	}
+
+	event := newBondedEscrowEvent("EscrowFunded", escrow)
+	event.AddStringField("leg", leg.Leg)
+	event.AddAddressField("party", party)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// ReclaimEscrow 募集超时后，出资方取回自己一侧已存入的资金
+//
+// 两侧资金均已取回后托管变为 EXPIRED。
+func ReclaimEscrow(escrowID []byte, party framework.Address) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	escrow, version, err := loadBondedEscrow(escrowID)
+	if err != nil {
+		return err
+	}
+
+	leg, err := escrow.Reclaim(party, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+
+	builder := framework.BeginTransaction().Transfer(framework.GetContractAddress(), leg.To, leg.TokenID, leg.Amount)
+	if err := commitBondedEscrow(builder, escrow, version); err != nil {
+		return err
+	}
+
+	event := newBondedEscrowEvent("EscrowReclaimed", escrow)
+	event.AddStringField("leg", leg.Leg)
+	event.AddAddressField("party", party)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// ReleaseEscrow 交易完成：货款付给卖方，保证金退还卖方
+func ReleaseEscrow(escrowID []byte) error {
+	return settleBondedEscrow(escrowID, "EscrowReleased", func(e *BondedEscrow) ([]EscrowPayout, error) {
+		return e.Release()
+	})
+}
+
+// RefundEscrow 交易取消：货款退还买方，保证金退还卖方
+func RefundEscrow(escrowID []byte) error {
+	return settleBondedEscrow(escrowID, "EscrowRefunded", func(e *BondedEscrow) ([]EscrowPayout, error) {
+		return e.Refund()
+	})
+}
+
+// ResolveEscrow 仲裁裁决：货款与保证金分别划分
+//
+// **参数**：
+//   - paymentToBuyer: 退还买方的货款，其余付给卖方
+//   - bondToBuyer: 罚没给买方的保证金，其余退还卖方
+//
+// **示例**：
+//
+//	// 卖方未交付：退还货款，并将保证金罚没给买方
+//	escrow, _ := market.GetEscrow(escrowID)
+//	err := market.ResolveEscrow(escrowID, escrow.PaymentAmount, escrow.BondAmount)
+func ResolveEscrow(escrowID []byte, paymentToBuyer, bondToBuyer framework.Amount) error {
+	return settleBondedEscrow(escrowID, "EscrowResolved", func(e *BondedEscrow) ([]EscrowPayout, error) {
+		return e.Resolve(paymentToBuyer, bondToBuyer)
+	})
+}
+
+// GetEscrow 查询带保证金的托管记录
+//
+// **返回**：
+//   - *BondedEscrow: 托管记录
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetEscrow(escrowID []byte) (*BondedEscrow, error) {
+	escrow, _, err := loadBondedEscrow(escrowID)
+	return escrow, err
+}
+
+// settleBondedEscrow 执行结算迁移，划出资金并发出事件
+func settleBondedEscrow(escrowID []byte, eventName string, transition func(*BondedEscrow) ([]EscrowPayout, error)) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	escrow, version, err := loadBondedEscrow(escrowID)
+	if err != nil {
+		return err
+	}
+
+	payouts, err := transition(escrow)
+	if err != nil {
+		return err
+	}
+
+	contractAddr := framework.GetContractAddress()
+	builder := framework.BeginTransaction()
+	for _, p := range payouts {
+		builder = builder.Transfer(contractAddr, p.To, p.TokenID, p.Amount)
+	}
+	if err := commitBondedEscrow(builder, escrow, version); err != nil {
+		return err
+	}
+
+	event := newBondedEscrowEvent(eventName, escrow)
+	event.AddUint64Field("payment_to_buyer", uint64(escrow.PaymentToBuyer))
+	event.AddUint64Field("payment_to_seller", uint64(escrow.PaymentAmount-escrow.PaymentToBuyer))
+	event.AddUint64Field("bond_to_buyer", uint64(escrow.BondToBuyer))
+	event.AddUint64Field("bond_to_seller", uint64(escrow.BondAmount-escrow.BondToBuyer))
+	framework.EmitEvent(event)
+	return nil
+}
+
+// loadBondedEscrow 读取托管记录及其状态版本
+func loadBondedEscrow(escrowID []byte) (*BondedEscrow, uint64, error) {
+	if len(escrowID) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "escrowID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildBondedEscrowStateID(escrowID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "escrow not found")
+	}
+	escrow, err := decodeBondedEscrow(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return escrow, version, nil
+}
+
+// commitBondedEscrow 将资金划转与新版本托管记录放入同一笔交易提交
+func commitBondedEscrow(builder *framework.TransactionBuilder, escrow *BondedEscrow, version uint64) error {
+	stateID := buildBondedEscrowStateID([]byte(escrow.EscrowID))
+	success, _, errCode := builder.
+		AddStateOutput(stateID, version+1, encodeBondedEscrow(escrow)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "bonded escrow transaction failed")
+	}
+	return nil
+}
+
+// newBondedEscrowEvent 构建包含托管双方与两侧资金的事件
+func newBondedEscrowEvent(name string, escrow *BondedEscrow) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddField("escrow_id", escrow.EscrowID)
+	event.AddStringField("status", escrow.Status)
+	event.AddAddressField("buyer", escrow.Buyer)
+	event.AddAddressField("seller", escrow.Seller)
+	event.AddStringField("payment_token_id", string(escrow.PaymentToken))
+	event.AddUint64Field("payment_amount", uint64(escrow.PaymentAmount))
+	event.AddStringField("bond_token_id", string(escrow.BondToken))
+	event.AddUint64Field("bond_amount", uint64(escrow.BondAmount))
+	event.AddAddressField("caller", framework.GetCaller())
+	return event
+}
+
+// buildBondedEscrowStateID 构建带保证金托管的状态ID
+func buildBondedEscrowStateID(escrowID []byte) []byte {
+	return []byte("bonded_escrow:" + string(escrowID))
+}
@@ -0,0 +1,202 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== NFT 抵押借款（纯状态机） ====================
+//
+// NFT 持有者以 NFT 作抵押借款：NFT 托管在合约地址，合约地址放出借款；借款人在到期前
+// （含到期时间）归还借款取回 NFT；到期仍未归还的，出借方收取抵押的 NFT。
+//
+// 本文件只包含不依赖宿主函数的借款记录与状态迁移，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金与 NFT 划转、状态写入见 nft_collateral.go。
+
+// NFT 抵押借款状态
+const (
+	// NFT_LOAN_STATUS_ACTIVE 借款中：NFT 已托管，借款已放出
+	NFT_LOAN_STATUS_ACTIVE = "ACTIVE"
+	// NFT_LOAN_STATUS_REPAID 已还款：NFT 已退还借款人
+	NFT_LOAN_STATUS_REPAID = "REPAID"
+	// NFT_LOAN_STATUS_SEIZED 已违约收取：NFT 已转给出借方
+	NFT_LOAN_STATUS_SEIZED = "SEIZED"
+)
+
+// ESCROW_LEG_COLLATERAL 抵押品（托管的 NFT）
+const ESCROW_LEG_COLLATERAL = "collateral"
+
+// NFTLoan NFT 抵押借款记录
+type NFTLoan struct {
+	LoanID   string
+	Status   string
+	Borrower framework.Address
+	// Lender 违约后收取 NFT 的出借方（收取前为零地址）
+	Lender framework.Address
+	// NFT 抵押的 NFT 代币ID
+	NFT framework.TokenID
+	// LoanToken / LoanAmount 借款代币与金额（归还金额相同）
+	LoanToken  framework.TokenID
+	LoanAmount framework.Amount
+	// DueTime 到期时间（含），之后不能还款，出借方可收取 NFT
+	DueTime   uint64
+	CreatedAt uint64
+	ClosedAt  uint64
+}
+
+// NewNFTLoan 创建处于 ACTIVE 状态的借款记录
+//
+// **参数**：
+//   - loanAmount: 借款金额，必须大于 0
+//   - dueTime: 到期时间，必须晚于 now
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewNFTLoan(loanID string, borrower framework.Address, nftID framework.TokenID, loanToken framework.TokenID, loanAmount framework.Amount, dueTime uint64, now uint64) (*NFTLoan, error) {
+	if borrower == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "borrower address cannot be zero")
+	}
+	if nftID == "" || len(nftID) > 0xFFFF || len(loanToken) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid tokenID")
+	}
+	if nftID == loanToken {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "loan token cannot be the collateral NFT")
+	}
+	if loanAmount == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "loan amount must be greater than 0")
+	}
+	if dueTime <= now {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "due time must be in the future")
+	}
+	if loanID == "" || len(loanID) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid loanID")
+	}
+
+	return &NFTLoan{
+		LoanID:     loanID,
+		Status:     NFT_LOAN_STATUS_ACTIVE,
+		Borrower:   borrower,
+		NFT:        nftID,
+		LoanToken:  loanToken,
+		LoanAmount: loanAmount,
+		DueTime:    dueTime,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Defaulted 借款是否已违约（超过到期时间仍未归还）
+func (l *NFTLoan) Defaulted(now uint64) bool {
+	return l.Status == NFT_LOAN_STATUS_ACTIVE && now > l.DueTime
+}
+
+// Repay 借款人在到期前归还借款
+//
+// **返回**：
+//   - EscrowPayout: 退还借款人的 NFT
+//   - error: 非 ACTIVE 状态（ERROR_INVALID_STATE）、非借款人（ERROR_UNAUTHORIZED）、
+//     已过到期时间（ERROR_TIMEOUT）
+//
+// **注意**：借款金额的归还由调用方（RepayNFTLoan）与 NFT 退还放入同一笔交易
+func (l *NFTLoan) Repay(borrower framework.Address, now uint64) (EscrowPayout, error) {
+	if l.Status != NFT_LOAN_STATUS_ACTIVE {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "loan is not active")
+	}
+	if borrower != l.Borrower {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the borrower can repay")
+	}
+	if now > l.DueTime {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_TIMEOUT, "loan is past due")
+	}
+
+	l.Status = NFT_LOAN_STATUS_REPAID
+	l.ClosedAt = now
+	return EscrowPayout{Leg: ESCROW_LEG_COLLATERAL, To: l.Borrower, TokenID: l.NFT, Amount: 1}, nil
+}
+
+// Seize 到期未还款后出借方收取 NFT
+//
+// **返回**：
+//   - EscrowPayout: 转给出借方的 NFT
+//   - error: 非 ACTIVE 状态或尚未到期（ERROR_INVALID_STATE）、出借方无效（ERROR_INVALID_PARAMS）
+//
+// **注意**：谁可以作为出借方收取 NFT 是业务逻辑，由调用方校验
+func (l *NFTLoan) Seize(lender framework.Address, now uint64) (EscrowPayout, error) {
+	if l.Status != NFT_LOAN_STATUS_ACTIVE {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "loan is not active")
+	}
+	if lender == (framework.Address{}) || lender == l.Borrower {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid lender")
+	}
+	if !l.Defaulted(now) {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "loan is not yet due")
+	}
+
+	l.Status = NFT_LOAN_STATUS_SEIZED
+	l.Lender = lender
+	l.ClosedAt = now
+	return EscrowPayout{Leg: ESCROW_LEG_COLLATERAL, To: lender, TokenID: l.NFT, Amount: 1}, nil
+}
+
+// ==================== 编码 ====================
+
+// encodeNFTLoan 编码借款记录
+//
+// 编码格式（大端）：
+//
+//	statusLen(1) + status + borrower(20) + lender(20) + loanAmount(8) + dueTime(8) + createdAt(8) + closedAt(8) +
+//	nftLen(2) + nft + loanTokenLen(2) + loanToken + loanIDLen(2) + loanID
+func encodeNFTLoan(l *NFTLoan) []byte {
+	data := make([]byte, 0, 1+len(l.Status)+40+32+6+len(l.NFT)+len(l.LoanToken)+len(l.LoanID))
+	data = append(data, byte(len(l.Status)))
+	data = append(data, l.Status...)
+	data = append(data, l.Borrower[:]...)
+	data = append(data, l.Lender[:]...)
+	for _, v := range []uint64{uint64(l.LoanAmount), l.DueTime, l.CreatedAt, l.ClosedAt} {
+		data = appendUint64(data, v)
+	}
+	for _, s := range []string{string(l.NFT), string(l.LoanToken), l.LoanID} {
+		data = append(data, byte(len(s)>>8), byte(len(s)))
+		data = append(data, s...)
+	}
+	return data
+}
+
+// decodeNFTLoan 解码借款记录
+func decodeNFTLoan(data []byte) (*NFTLoan, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid NFT loan record")
+	if len(data) < 1 {
+		return nil, invalid
+	}
+	pos := 1 + int(data[0])
+	// borrower/lender(40) + 4个金额/时间字段(32)
+	if len(data) < pos+40+32 {
+		return nil, invalid
+	}
+
+	l := &NFTLoan{Status: string(data[1:pos])}
+	copy(l.Borrower[:], data[pos:pos+20])
+	copy(l.Lender[:], data[pos+20:pos+40])
+	pos += 40
+	l.LoanAmount = framework.Amount(readUint64(data[pos:]))
+	l.DueTime = readUint64(data[pos+8:])
+	l.CreatedAt = readUint64(data[pos+16:])
+	l.ClosedAt = readUint64(data[pos+24:])
+	pos += 32
+
+	strs := make([]string, 3)
+	for i := range strs {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(data[pos])<<8 | int(data[pos+1])
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		strs[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	l.NFT = framework.TokenID(strs[0])
+	l.LoanToken = framework.TokenID(strs[1])
+	l.LoanID = strs[2]
+	return l, nil
+}
@@ -0,0 +1,121 @@
+package market
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var testLender = framework.Address{3}
+
+func newTestNFTLoan(t *testing.T) *NFTLoan {
+	t.Helper()
+	loan, err := NewNFTLoan("loan_1", testBuyer, "nft_001", "USDT", 1000, 5000, 100)
+	if err != nil {
+		t.Fatalf("NewNFTLoan() error = %v", err)
+	}
+	return loan
+}
+
+// TestNFTLoanRepayBeforeDue 测试到期前（含到期时间）还款退还 NFT，之后不能收取
+func TestNFTLoanRepayBeforeDue(t *testing.T) {
+	loan := newTestNFTLoan(t)
+
+	if _, err := loan.Repay(testSeller, 1000); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Repay() by non-borrower code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+	if _, err := loan.Seize(testLender, 5000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Seize() at due time code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+
+	release, err := loan.Repay(testBuyer, 5000)
+	if err != nil {
+		t.Fatalf("Repay() at due time error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_COLLATERAL, To: testBuyer, TokenID: "nft_001", Amount: 1}
+	if release != want {
+		t.Errorf("Repay() release = %+v, want %+v", release, want)
+	}
+	if loan.Status != NFT_LOAN_STATUS_REPAID || loan.ClosedAt != 5000 {
+		t.Errorf("after Repay() status = %s, closedAt = %d", loan.Status, loan.ClosedAt)
+	}
+
+	if _, err := loan.Seize(testLender, 6000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Seize() after repay code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+	if _, err := loan.Repay(testBuyer, 5000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Repay() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestNFTLoanSeizeAfterDue 测试到期未还款后出借方收取 NFT，借款人不能再还款
+func TestNFTLoanSeizeAfterDue(t *testing.T) {
+	loan := newTestNFTLoan(t)
+
+	if _, err := loan.Repay(testBuyer, 5001); errCode(err) != framework.ERROR_TIMEOUT {
+		t.Errorf("Repay() after due code = %d, want ERROR_TIMEOUT", errCode(err))
+	}
+	if !loan.Defaulted(5001) || loan.Defaulted(5000) {
+		t.Errorf("Defaulted() = %v at 5001, %v at 5000", loan.Defaulted(5001), loan.Defaulted(5000))
+	}
+	if _, err := loan.Seize(testBuyer, 5001); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Seize() by borrower code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+
+	seized, err := loan.Seize(testLender, 5001)
+	if err != nil {
+		t.Fatalf("Seize() error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_COLLATERAL, To: testLender, TokenID: "nft_001", Amount: 1}
+	if seized != want {
+		t.Errorf("Seize() = %+v, want %+v", seized, want)
+	}
+	if loan.Status != NFT_LOAN_STATUS_SEIZED || loan.Lender != testLender {
+		t.Errorf("after Seize() status = %s, lender = %v", loan.Status, loan.Lender)
+	}
+	if _, err := loan.Seize(testLender, 6000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Seize() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestNFTLoanValidation 测试创建借款的参数验证
+func TestNFTLoanValidation(t *testing.T) {
+	cases := []struct {
+		name     string
+		borrower framework.Address
+		nft      framework.TokenID
+		amount   framework.Amount
+		due      uint64
+	}{
+		{"zero borrower", framework.Address{}, "nft_001", 1000, 5000},
+		{"empty nft", testBuyer, "", 1000, 5000},
+		{"nft as loan token", testBuyer, "USDT", 1000, 5000},
+		{"zero amount", testBuyer, "nft_001", 0, 5000},
+		{"past due", testBuyer, "nft_001", 1000, 100},
+	}
+	for _, tc := range cases {
+		if _, err := NewNFTLoan("loan_1", tc.borrower, tc.nft, "USDT", tc.amount, tc.due, 100); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: code = %d, want ERROR_INVALID_PARAMS", tc.name, errCode(err))
+		}
+	}
+}
+
+// TestNFTLoanCodec 测试借款记录编码往返
+func TestNFTLoanCodec(t *testing.T) {
+	loan := newTestNFTLoan(t)
+	if _, err := loan.Seize(testLender, 6000); err != nil {
+		t.Fatalf("Seize() error = %v", err)
+	}
+
+	decoded, err := decodeNFTLoan(encodeNFTLoan(loan))
+	if err != nil {
+		t.Fatalf("decodeNFTLoan() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, loan) {
+		t.Errorf("decodeNFTLoan() = %+v, want %+v", decoded, loan)
+	}
+	if _, err := decodeNFTLoan(encodeNFTLoan(loan)[:40]); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("decodeNFTLoan(truncated) code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
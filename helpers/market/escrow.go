@@ -1,5 +1,3 @@
-//go:build tinygo || (js && wasm)
-
 package market
 
 import (
@@ -13,7 +11,7 @@ import (
 // **参数**：
 //   - buyer: 买方地址
 //   - seller: 卖方地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - amount: 托管金额
 //   - escrowID: 托管ID（由合约生成）
 //
@@ -34,7 +32,7 @@ import (
 //	    err := market.Escrow(
 //	        buyer,
 //	        seller,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(10000),
 //	        escrowID,
 //	    )
@@ -44,6 +42,9 @@ import (
 //	    return framework.SUCCESS
 //	}
 func Escrow(buyer, seller framework.Address, tokenID framework.TokenID, amount framework.Amount, escrowID []byte) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
 	// 1. 参数验证
 	if err := validateEscrowParams(buyer, seller, amount, escrowID); err != nil {
 		return err
@@ -89,27 +90,6 @@ func Escrow(buyer, seller framework.Address, tokenID framework.TokenID, amount f
 	return nil
 }
 
-// validateEscrowParams 验证托管参数
-func validateEscrowParams(buyer, seller framework.Address, amount framework.Amount, escrowID []byte) error {
-	zeroAddr := framework.Address{}
-	if buyer == zeroAddr {
-		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer address cannot be zero")
-	}
-	if seller == zeroAddr {
-		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "seller address cannot be zero")
-	}
-	if buyer == seller {
-		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer and seller addresses cannot be the same")
-	}
-	if amount == 0 {
-		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
-	}
-	if len(escrowID) == 0 {
-		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "escrowID cannot be empty")
-	}
-	return nil
-}
-
 // Release 合约内分阶段释放操作
 //
 // 🎯 **用途**：在合约代码中创建分阶段释放计划
@@ -117,7 +97,7 @@ func validateEscrowParams(buyer, seller framework.Address, amount framework.Amou
 // **参数**：
 //   - from: 释放者地址
 //   - beneficiary: 受益人地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - totalAmount: 总释放金额
 //   - vestingID: 释放计划ID（由合约生成）
 //
@@ -138,7 +118,7 @@ func validateEscrowParams(buyer, seller framework.Address, amount framework.Amou
 //	    err := market.Release(
 //	        caller,
 //	        beneficiary,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(100000),
 //	        vestingID,
 //	    )
@@ -0,0 +1,219 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// liquidationAuctionSeqStateID 拍卖序号状态ID（十进制字符串）
+const liquidationAuctionSeqStateID = "liquidation_auction_seq"
+
+// StartLiquidationAuction 发起抵押品清算拍卖
+//
+// 🎯 **用途**：以降价拍卖出售被没收的抵押品，替代固定折扣清算——
+// 价格随时间下降，最先接受当前价格的出价方成交，不必把固定折扣让给抢先清算的一方
+//
+// **参数**：
+//   - owner: 抵押品原所有者，结算时剩余抵押品退还该地址
+//   - collateralToken / collateralAmount: 出售的抵押品（须已由合约地址持有）
+//   - debtToken / debtTarget: 需要收回的债务代币与目标金额
+//   - params: 拍卖时长、起始价格与价格衰减（见 AuctionParams）
+//
+// **返回**：
+//   - string: 拍卖ID（auction_{序号}）
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS
+//
+// **注意**：
+//   - 抵押品的没收（如扣减存款台账）与权限控制（谁可以发起清算）是业务逻辑，需要在合约代码中实现
+//   - 出价见 BidLiquidation，结算见 SettleAuction
+//
+// **示例**：
+//
+//	auctionID, err := market.StartLiquidationAuction(
+//	    borrower,
+//	    "WES", framework.Amount(1000), // 没收的抵押品
+//	    "USDT", framework.Amount(800), // 借款人的欠款
+//	    market.AuctionParams{Duration: 3600, StartPriceBP: 15000, DecayBP: 4000},
+//	)
+func StartLiquidationAuction(owner framework.Address, collateralToken framework.TokenID, collateralAmount framework.Amount, debtToken framework.TokenID, debtTarget framework.Amount, params AuctionParams) (string, error) {
+	// 1. 分配拍卖ID
+	seqData, _ := framework.GetState(liquidationAuctionSeqStateID)
+	seq := parseAuctionSeq(seqData) + 1
+	auctionID := "auction_" + framework.Uint64ToString(seq)
+
+	// 2. 参数验证
+	auction, err := NewLiquidationAuction(auctionID, owner, collateralToken, collateralAmount, debtToken, debtTarget, params, framework.GetTimestamp())
+	if err != nil {
+		return "", err
+	}
+
+	// 3. 写入拍卖记录与序号
+	success, _, errCode := framework.BeginTransaction().
+		AddStateOutput(buildLiquidationAuctionStateID(auctionID), 1, encodeLiquidationAuction(auction)).
+		AddStateOutput([]byte(liquidationAuctionSeqStateID), seq, []byte(framework.Uint64ToString(seq))).
+		Finalize()
+	if !success {
+		return "", framework.NewContractError(errCode, "failed to start liquidation auction")
+	}
+
+	// 4. 发出事件
+	event := newLiquidationAuctionEvent("LiquidationAuctionStarted", auction)
+	event.AddUint64Field("start_time", auction.StartTime)
+	event.AddUint64Field("end_time", auction.EndTime())
+	event.AddUint64Field("start_price_bp", auction.StartPriceBP)
+	event.AddUint64Field("decay_bp", auction.DecayBP)
+	framework.EmitEvent(event)
+
+	return auctionID, nil
+}
+
+// BidLiquidation 按当前价格偿还债务换取抵押品
+//
+// 出价方的债务代币转入合约地址，抵押品从合约地址转给出价方；允许部分成交，
+// 直到债务目标达成或抵押品售罄。
+//
+// **参数**：
+//   - bidder: 出价方地址
+//   - auctionID: 拍卖ID
+//   - debtAmount: 愿意偿还的债务，超过剩余债务时按剩余债务成交
+//
+// **返回**：
+//   - AuctionFill: 实际偿还的债务、获得的抵押品与成交价格
+//   - error: 拍卖不存在（ERROR_NOT_FOUND）、已结束（ERROR_INVALID_STATE / ERROR_TIMEOUT）、
+//     余额不足（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：收回的债务如何入账（如扣减借款台账）是业务逻辑，由调用方根据 AuctionFill.DebtPaid 处理
+func BidLiquidation(bidder framework.Address, auctionID string, debtAmount framework.Amount) (AuctionFill, error) {
+	auction, version, err := loadLiquidationAuction(auctionID)
+	if err != nil {
+		return AuctionFill{}, err
+	}
+
+	fill, err := auction.Bid(debtAmount, framework.GetTimestamp())
+	if err != nil {
+		return AuctionFill{}, err
+	}
+	if framework.QueryUTXOBalance(bidder, auction.DebtToken) < fill.DebtPaid {
+		return AuctionFill{}, framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to bid")
+	}
+
+	contractAddr := framework.GetContractAddress()
+	builder := framework.BeginTransaction().
+		Transfer(bidder, contractAddr, auction.DebtToken, fill.DebtPaid).
+		Transfer(contractAddr, bidder, auction.CollateralToken, fill.CollateralOut)
+	if err := commitLiquidationAuction(builder, auction, version); err != nil {
+		return AuctionFill{}, err
+	}
+
+	event := newLiquidationAuctionEvent("LiquidationBid", auction)
+	event.AddAddressField("bidder", bidder)
+	event.AddUint64Field("debt_paid", uint64(fill.DebtPaid))
+	event.AddUint64Field("collateral_out", uint64(fill.CollateralOut))
+	event.AddUint64Field("price_bp", fill.PriceBP)
+	framework.EmitEvent(event)
+	return fill, nil
+}
+
+// SettleAuction 结算已结束的拍卖
+//
+// 剩余抵押品退还原所有者；拍卖到期仍未收回全部债务时，在结果与事件中报告缺口。
+// 任何人都可以在拍卖结束后（债务目标达成、抵押品售罄或到期）调用。
+//
+// **返回**：
+//   - AuctionSettlement: 退还的剩余抵押品与债务缺口
+//   - error: 拍卖不存在（ERROR_NOT_FOUND）、已结算或尚未结束（ERROR_INVALID_STATE）
+//
+// **注意**：缺口如何处理（坏账核销、保险基金弥补等）是业务逻辑，需要在合约代码中实现
+func SettleAuction(auctionID string) (AuctionSettlement, error) {
+	auction, version, err := loadLiquidationAuction(auctionID)
+	if err != nil {
+		return AuctionSettlement{}, err
+	}
+
+	settlement, err := auction.Settle(framework.GetTimestamp())
+	if err != nil {
+		return AuctionSettlement{}, err
+	}
+
+	builder := framework.BeginTransaction()
+	if settlement.Surplus > 0 {
+		builder = builder.Transfer(framework.GetContractAddress(), auction.Owner, auction.CollateralToken, settlement.Surplus)
+	}
+	if err := commitLiquidationAuction(builder, auction, version); err != nil {
+		return AuctionSettlement{}, err
+	}
+
+	event := newLiquidationAuctionEvent("LiquidationAuctionSettled", auction)
+	event.AddUint64Field("surplus", uint64(settlement.Surplus))
+	event.AddUint64Field("shortfall", uint64(settlement.Shortfall))
+	framework.EmitEvent(event)
+	return settlement, nil
+}
+
+// GetLiquidationAuction 查询清算拍卖记录
+//
+// **返回**：
+//   - *LiquidationAuction: 拍卖记录
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetLiquidationAuction(auctionID string) (*LiquidationAuction, error) {
+	auction, _, err := loadLiquidationAuction(auctionID)
+	return auction, err
+}
+
+// loadLiquidationAuction 读取拍卖记录及其状态版本
+func loadLiquidationAuction(auctionID string) (*LiquidationAuction, uint64, error) {
+	if auctionID == "" {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "auctionID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildLiquidationAuctionStateID(auctionID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "liquidation auction not found")
+	}
+	auction, err := decodeLiquidationAuction(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return auction, version, nil
+}
+
+// commitLiquidationAuction 将资金划转与新版本拍卖记录放入同一笔交易提交
+func commitLiquidationAuction(builder *framework.TransactionBuilder, auction *LiquidationAuction, version uint64) error {
+	success, _, errCode := builder.
+		AddStateOutput(buildLiquidationAuctionStateID(auction.AuctionID), version+1, encodeLiquidationAuction(auction)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "liquidation auction transaction failed")
+	}
+	return nil
+}
+
+// newLiquidationAuctionEvent 构建包含拍卖双方资产与进度的事件
+func newLiquidationAuctionEvent(name string, auction *LiquidationAuction) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddStringField("auction_id", auction.AuctionID)
+	event.AddStringField("status", auction.Status)
+	event.AddAddressField("owner", auction.Owner)
+	event.AddStringField("collateral_token_id", string(auction.CollateralToken))
+	event.AddUint64Field("collateral_amount", uint64(auction.CollateralAmount))
+	event.AddUint64Field("collateral_sold", uint64(auction.CollateralSold))
+	event.AddStringField("debt_token_id", string(auction.DebtToken))
+	event.AddUint64Field("debt_target", uint64(auction.DebtTarget))
+	event.AddUint64Field("debt_raised", uint64(auction.DebtRaised))
+	return event
+}
+
+// buildLiquidationAuctionStateID 构建清算拍卖的状态ID
+func buildLiquidationAuctionStateID(auctionID string) []byte {
+	return []byte("liquidation_auction:" + auctionID)
+}
+
+// parseAuctionSeq 解析十进制拍卖序号，无效时视为 0
+func parseAuctionSeq(data []byte) uint64 {
+	var seq uint64
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			break
+		}
+		seq = seq*10 + uint64(c-'0')
+	}
+	return seq
+}
@@ -0,0 +1,192 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// nftLoanSeqStateID 借款序号状态ID（十进制字符串）
+const nftLoanSeqStateID = "nft_loan_seq"
+
+// LockNFTAsCollateral 以 NFT 作抵押借款
+//
+// 🎯 **用途**：NFT 持有者以 NFT 作抵押借款——NFT 从借款人转入合约地址托管，
+// 借款从合约地址放给借款人，两笔划转在同一笔交易中完成
+//
+// **参数**：
+//   - borrower: 借款人（须持有该 NFT）
+//   - tokenID: 抵押的 NFT 代币ID
+//   - loanAmount: 借款金额
+//   - tokenOut: 借款代币（空字符串表示原生币）
+//   - dueTime: 到期时间（Unix 时间戳，秒）
+//
+// **返回**：
+//   - string: 借款ID（loan_{序号}）
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、借款人未持有该 NFT（ERROR_UNAUTHORIZED）、
+//     合约可借出余额不足（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：
+//   - 借款由合约地址持有的资金放出；借款额度（估值、抵押率）与利息是业务逻辑，需要在合约代码中实现
+//   - 还款见 RepayNFTLoan，违约收取见 SeizeNFT
+//
+// **示例**：
+//
+//	loanID, err := market.LockNFTAsCollateral(caller, "nft_001", framework.Amount(1000), "USDT", framework.GetTimestamp()+30*86400)
+func LockNFTAsCollateral(borrower framework.Address, tokenID framework.TokenID, loanAmount framework.Amount, tokenOut framework.TokenID, dueTime uint64) (string, error) {
+	// 1. 分配借款ID
+	seqData, _ := framework.GetState(nftLoanSeqStateID)
+	seq := parseAuctionSeq(seqData) + 1
+	loanID := "loan_" + framework.Uint64ToString(seq)
+
+	// 2. 参数验证
+	loan, err := NewNFTLoan(loanID, borrower, tokenID, tokenOut, loanAmount, dueTime, framework.GetTimestamp())
+	if err != nil {
+		return "", err
+	}
+	if framework.QueryUTXOBalance(borrower, tokenID) < 1 {
+		return "", framework.NewContractError(framework.ERROR_UNAUTHORIZED, "borrower does not own the NFT")
+	}
+	contractAddr := framework.GetContractAddress()
+	if framework.QueryUTXOBalance(contractAddr, tokenOut) < loanAmount {
+		return "", framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient liquidity for loan")
+	}
+
+	// 3. 托管 NFT、放出借款，写入借款记录与序号
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(borrower, contractAddr, tokenID, 1).
+		Transfer(contractAddr, borrower, tokenOut, loanAmount).
+		AddStateOutput(buildNFTLoanStateID(loanID), 1, encodeNFTLoan(loan)).
+		AddStateOutput([]byte(nftLoanSeqStateID), seq, []byte(framework.Uint64ToString(seq))).
+		Finalize()
+	if !success {
+		return "", framework.NewContractError(errCode, "failed to lock NFT as collateral")
+	}
+
+	// 4. 发出事件
+	framework.EmitEvent(newNFTLoanEvent("NFTCollateralLocked", loan))
+
+	return loanID, nil
+}
+
+// RepayNFTLoan 归还借款取回 NFT
+//
+// 借款金额从借款人转入合约地址，托管的 NFT 退还借款人，两笔划转在同一笔交易中完成。
+//
+// **参数**：
+//   - borrower: 借款人（调用者）
+//   - loanID: 借款ID
+//
+// **返回**：
+//   - error: 借款不存在（ERROR_NOT_FOUND）、已还款或已收取（ERROR_INVALID_STATE）、
+//     非借款人（ERROR_UNAUTHORIZED）、已过到期时间（ERROR_TIMEOUT）、余额不足（ERROR_INSUFFICIENT_BALANCE）
+func RepayNFTLoan(borrower framework.Address, loanID string) error {
+	loan, version, err := loadNFTLoan(loanID)
+	if err != nil {
+		return err
+	}
+
+	release, err := loan.Repay(borrower, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+	if framework.QueryUTXOBalance(borrower, loan.LoanToken) < loan.LoanAmount {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to repay")
+	}
+
+	contractAddr := framework.GetContractAddress()
+	builder := framework.BeginTransaction().
+		Transfer(borrower, contractAddr, loan.LoanToken, loan.LoanAmount).
+		Transfer(contractAddr, release.To, release.TokenID, release.Amount)
+	if err := commitNFTLoan(builder, loan, version); err != nil {
+		return err
+	}
+
+	framework.EmitEvent(newNFTLoanEvent("NFTLoanRepaid", loan))
+	return nil
+}
+
+// SeizeNFT 借款违约后出借方收取抵押的 NFT
+//
+// **参数**：
+//   - lender: 收取 NFT 的出借方地址
+//   - loanID: 借款ID
+//
+// **返回**：
+//   - error: 借款不存在（ERROR_NOT_FOUND）、已还款/已收取或尚未到期（ERROR_INVALID_STATE）
+//
+// **注意**：借款由合约地址放出，谁可以作为出借方收取 NFT（如资金池运营方）是业务逻辑，需要在合约代码中校验
+func SeizeNFT(lender framework.Address, loanID string) error {
+	loan, version, err := loadNFTLoan(loanID)
+	if err != nil {
+		return err
+	}
+
+	seized, err := loan.Seize(lender, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+
+	builder := framework.BeginTransaction().
+		Transfer(framework.GetContractAddress(), seized.To, seized.TokenID, seized.Amount)
+	if err := commitNFTLoan(builder, loan, version); err != nil {
+		return err
+	}
+
+	framework.EmitEvent(newNFTLoanEvent("NFTSeized", loan))
+	return nil
+}
+
+// GetNFTLoan 查询 NFT 抵押借款记录
+//
+// **返回**：
+//   - *NFTLoan: 借款记录
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetNFTLoan(loanID string) (*NFTLoan, error) {
+	loan, _, err := loadNFTLoan(loanID)
+	return loan, err
+}
+
+// loadNFTLoan 读取借款记录及其状态版本
+func loadNFTLoan(loanID string) (*NFTLoan, uint64, error) {
+	if loanID == "" {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "loanID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildNFTLoanStateID(loanID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "NFT loan not found")
+	}
+	loan, err := decodeNFTLoan(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return loan, version, nil
+}
+
+// commitNFTLoan 将划转与新版本借款记录放入同一笔交易提交
+func commitNFTLoan(builder *framework.TransactionBuilder, loan *NFTLoan, version uint64) error {
+	success, _, errCode := builder.
+		AddStateOutput(buildNFTLoanStateID(loan.LoanID), version+1, encodeNFTLoan(loan)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "NFT loan transaction failed")
+	}
+	return nil
+}
+
+// newNFTLoanEvent 构建包含借款双方、抵押品与借款金额的事件
+func newNFTLoanEvent(name string, loan *NFTLoan) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddStringField("loan_id", loan.LoanID)
+	event.AddStringField("status", loan.Status)
+	event.AddAddressField("borrower", loan.Borrower)
+	event.AddAddressField("lender", loan.Lender)
+	event.AddStringField("nft_token_id", string(loan.NFT))
+	event.AddStringField("loan_token_id", string(loan.LoanToken))
+	event.AddUint64Field("loan_amount", uint64(loan.LoanAmount))
+	event.AddUint64Field("due_time", loan.DueTime)
+	return event
+}
+
+// buildNFTLoanStateID 构建 NFT 抵押借款的状态ID
+func buildNFTLoanStateID(loanID string) []byte {
+	return []byte("nft_loan:" + loanID)
+}
@@ -0,0 +1,181 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// nftOfferSeqStateID 报价序号状态ID（十进制字符串）
+const nftOfferSeqStateID = "nft_offer_seq"
+
+// MakeOffer 对 NFT 报价
+//
+// 🎯 **用途**：买方对未挂单的 NFT 直接报价，报价金额（原生币）从买方转入合约地址托管，
+// NFT 所有者在有效期内可通过 AcceptOffer 接受
+//
+// **参数**：
+//   - buyer: 买方地址
+//   - tokenID: NFT 代币ID
+//   - price: 报价金额（原生币）
+//   - expiry: 有效期截止时间（Unix 时间戳，秒）
+//
+// **返回**：
+//   - string: 报价ID（offer_{序号}）
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、余额不足（ERROR_INSUFFICIENT_BALANCE）
+//
+// **示例**：
+//
+//	offerID, err := market.MakeOffer(buyer, "nft_001", framework.Amount(500), framework.GetTimestamp()+86400)
+func MakeOffer(buyer framework.Address, tokenID framework.TokenID, price framework.Amount, expiry uint64) (string, error) {
+	// 1. 分配报价ID
+	seqData, _ := framework.GetState(nftOfferSeqStateID)
+	seq := parseAuctionSeq(seqData) + 1
+	offerID := "offer_" + framework.Uint64ToString(seq)
+
+	// 2. 参数验证
+	offer, err := NewNFTOffer(offerID, buyer, tokenID, price, expiry, framework.GetTimestamp())
+	if err != nil {
+		return "", err
+	}
+	if framework.QueryUTXOBalance(buyer, OFFER_PAYMENT_TOKEN) < price {
+		return "", framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to make offer")
+	}
+
+	// 3. 托管报价金额，写入报价记录与序号
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(buyer, framework.GetContractAddress(), OFFER_PAYMENT_TOKEN, price).
+		AddStateOutput(buildNFTOfferStateID(offerID), 1, encodeNFTOffer(offer)).
+		AddStateOutput([]byte(nftOfferSeqStateID), seq, []byte(framework.Uint64ToString(seq))).
+		Finalize()
+	if !success {
+		return "", framework.NewContractError(errCode, "failed to make NFT offer")
+	}
+
+	// 4. 发出事件
+	event := newNFTOfferEvent("NFTOfferMade", offer)
+	event.AddUint64Field("expiry", offer.Expiry)
+	framework.EmitEvent(event)
+
+	return offerID, nil
+}
+
+// AcceptOffer NFT 所有者接受报价
+//
+// 在同一笔交易中把 NFT 从所有者转给买方、把托管的报价金额付给所有者。
+//
+// **参数**：
+//   - owner: NFT 所有者（调用者）
+//   - offerID: 报价ID
+//
+// **返回**：
+//   - error: 报价不存在（ERROR_NOT_FOUND）、已成交或已撤回（ERROR_INVALID_STATE）、
+//     已过期（ERROR_TIMEOUT）、owner 未持有该 NFT（ERROR_UNAUTHORIZED）
+//
+// **注意**：NFT 以数量为 1 的代币余额表示，所有权以 QueryUTXOBalance 确认
+func AcceptOffer(owner framework.Address, offerID string) error {
+	offer, version, err := loadNFTOffer(offerID)
+	if err != nil {
+		return err
+	}
+
+	payout, err := offer.Accept(owner, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+	if framework.QueryUTXOBalance(owner, offer.NFT) < 1 {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "caller does not own the NFT")
+	}
+
+	builder := framework.BeginTransaction().
+		Transfer(owner, offer.Buyer, offer.NFT, 1).
+		Transfer(framework.GetContractAddress(), payout.To, payout.TokenID, payout.Amount)
+	if err := commitNFTOffer(builder, offer, version); err != nil {
+		return err
+	}
+
+	framework.EmitEvent(newNFTOfferEvent("NFTOfferAccepted", offer))
+	return nil
+}
+
+// WithdrawOffer 报价过期后买方取回托管的金额
+//
+// **参数**：
+//   - buyer: 买方地址（调用者）
+//   - offerID: 报价ID
+//
+// **返回**：
+//   - error: 报价不存在（ERROR_NOT_FOUND）、非买方（ERROR_UNAUTHORIZED）、
+//     未过期或已成交/已撤回（ERROR_INVALID_STATE）
+func WithdrawOffer(buyer framework.Address, offerID string) error {
+	offer, version, err := loadNFTOffer(offerID)
+	if err != nil {
+		return err
+	}
+
+	refund, err := offer.Withdraw(buyer, framework.GetTimestamp())
+	if err != nil {
+		return err
+	}
+
+	builder := framework.BeginTransaction().
+		Transfer(framework.GetContractAddress(), refund.To, refund.TokenID, refund.Amount)
+	if err := commitNFTOffer(builder, offer, version); err != nil {
+		return err
+	}
+
+	framework.EmitEvent(newNFTOfferEvent("NFTOfferWithdrawn", offer))
+	return nil
+}
+
+// GetOffer 查询 NFT 报价记录
+//
+// **返回**：
+//   - *NFTOffer: 报价记录
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetOffer(offerID string) (*NFTOffer, error) {
+	offer, _, err := loadNFTOffer(offerID)
+	return offer, err
+}
+
+// loadNFTOffer 读取报价记录及其状态版本
+func loadNFTOffer(offerID string) (*NFTOffer, uint64, error) {
+	if offerID == "" {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "offerID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildNFTOfferStateID(offerID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "NFT offer not found")
+	}
+	offer, err := decodeNFTOffer(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return offer, version, nil
+}
+
+// commitNFTOffer 将划转与新版本报价记录放入同一笔交易提交
+func commitNFTOffer(builder *framework.TransactionBuilder, offer *NFTOffer, version uint64) error {
+	success, _, errCode := builder.
+		AddStateOutput(buildNFTOfferStateID(offer.OfferID), version+1, encodeNFTOffer(offer)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "NFT offer transaction failed")
+	}
+	return nil
+}
+
+// newNFTOfferEvent 构建包含报价双方与金额的事件
+func newNFTOfferEvent(name string, offer *NFTOffer) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddStringField("offer_id", offer.OfferID)
+	event.AddStringField("status", offer.Status)
+	event.AddAddressField("buyer", offer.Buyer)
+	event.AddAddressField("seller", offer.Seller)
+	event.AddStringField("nft_token_id", string(offer.NFT))
+	event.AddUint64Field("price", uint64(offer.Price))
+	return event
+}
+
+// buildNFTOfferStateID 构建 NFT 报价的状态ID
+func buildNFTOfferStateID(offerID string) []byte {
+	return []byte("nft_offer:" + offerID)
+}
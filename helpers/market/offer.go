@@ -0,0 +1,189 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== NFT 报价（纯状态机） ====================
+//
+// 买方对未挂单的 NFT 直接报价：报价金额托管在合约地址，NFT 所有者在有效期内接受报价后，
+// NFT 转给买方、托管的报价金额付给所有者；报价过期仍未被接受的，买方取回托管的金额。
+//
+// 本文件只包含不依赖宿主函数的报价记录与状态迁移，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金与 NFT 划转、状态写入见 nft_offer.go。
+
+// 报价状态
+const (
+	// OFFER_STATUS_OPEN 报价有效：金额已托管，等待 NFT 所有者接受
+	OFFER_STATUS_OPEN = "OPEN"
+	// OFFER_STATUS_ACCEPTED 已成交：NFT 已转给买方，报价金额已付给所有者
+	OFFER_STATUS_ACCEPTED = "ACCEPTED"
+	// OFFER_STATUS_WITHDRAWN 已撤回：报价过期后买方取回托管的金额
+	OFFER_STATUS_WITHDRAWN = "WITHDRAWN"
+)
+
+// OFFER_PAYMENT_TOKEN 报价使用的支付代币（原生币）
+const OFFER_PAYMENT_TOKEN framework.TokenID = ""
+
+// NFTOffer NFT 报价记录
+type NFTOffer struct {
+	OfferID string
+	Status  string
+	Buyer   framework.Address
+	// Seller 接受报价的 NFT 所有者（成交前为零地址）
+	Seller framework.Address
+	// NFT 报价的 NFT 代币ID
+	NFT   framework.TokenID
+	Price framework.Amount
+	// Expiry 有效期截止时间（含），之后不能接受，买方可撤回
+	Expiry    uint64
+	CreatedAt uint64
+}
+
+// NewNFTOffer 创建处于 OPEN 状态的报价记录
+//
+// **参数**：
+//   - price: 报价金额（原生币），必须大于 0
+//   - expiry: 有效期截止时间，必须晚于 now
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewNFTOffer(offerID string, buyer framework.Address, nftID framework.TokenID, price framework.Amount, expiry uint64, now uint64) (*NFTOffer, error) {
+	if buyer == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer address cannot be zero")
+	}
+	if nftID == "" || len(nftID) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid NFT tokenID")
+	}
+	if price == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "price must be greater than 0")
+	}
+	if expiry <= now {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "expiry must be in the future")
+	}
+	if offerID == "" || len(offerID) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid offerID")
+	}
+
+	return &NFTOffer{
+		OfferID:   offerID,
+		Status:    OFFER_STATUS_OPEN,
+		Buyer:     buyer,
+		NFT:       nftID,
+		Price:     price,
+		Expiry:    expiry,
+		CreatedAt: now,
+	}, nil
+}
+
+// Expired 报价是否已过有效期
+func (o *NFTOffer) Expired(now uint64) bool {
+	return now > o.Expiry
+}
+
+// Accept NFT 所有者接受报价
+//
+// **返回**：
+//   - EscrowPayout: 从托管付给所有者的报价金额
+//   - error: 非 OPEN 状态（ERROR_INVALID_STATE）、已过期（ERROR_TIMEOUT）、
+//     买方接受自己的报价（ERROR_INVALID_PARAMS）
+//
+// **注意**：owner 是否持有该 NFT 由调用方（AcceptOffer）查询余额确认
+func (o *NFTOffer) Accept(owner framework.Address, now uint64) (EscrowPayout, error) {
+	if o.Status != OFFER_STATUS_OPEN {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "offer is not open")
+	}
+	if o.Expired(now) {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_TIMEOUT, "offer expired")
+	}
+	if owner == (framework.Address{}) || owner == o.Buyer {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "buyer cannot accept own offer")
+	}
+
+	o.Status = OFFER_STATUS_ACCEPTED
+	o.Seller = owner
+	return EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: owner, TokenID: OFFER_PAYMENT_TOKEN, Amount: o.Price}, nil
+}
+
+// Withdraw 报价过期后买方取回托管的金额
+//
+// **返回**：
+//   - EscrowPayout: 退还买方的报价金额
+//   - error: 非 OPEN 状态或未过期（ERROR_INVALID_STATE）、非买方（ERROR_UNAUTHORIZED）
+func (o *NFTOffer) Withdraw(buyer framework.Address, now uint64) (EscrowPayout, error) {
+	if o.Status != OFFER_STATUS_OPEN {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "offer is not open")
+	}
+	if buyer != o.Buyer {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the buyer can withdraw")
+	}
+	if !o.Expired(now) {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "offer not expired")
+	}
+
+	o.Status = OFFER_STATUS_WITHDRAWN
+	return EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: o.Buyer, TokenID: OFFER_PAYMENT_TOKEN, Amount: o.Price}, nil
+}
+
+// ==================== 编码 ====================
+
+// encodeNFTOffer 编码报价记录
+//
+// 编码格式（大端）：
+//
+//	statusLen(1) + status + buyer(20) + seller(20) + price(8) + expiry(8) + createdAt(8) +
+//	nftLen(2) + nft + offerIDLen(2) + offerID
+func encodeNFTOffer(o *NFTOffer) []byte {
+	data := make([]byte, 0, 1+len(o.Status)+40+24+4+len(o.NFT)+len(o.OfferID))
+	data = append(data, byte(len(o.Status)))
+	data = append(data, o.Status...)
+	data = append(data, o.Buyer[:]...)
+	data = append(data, o.Seller[:]...)
+	for _, v := range []uint64{uint64(o.Price), o.Expiry, o.CreatedAt} {
+		data = appendUint64(data, v)
+	}
+	for _, s := range []string{string(o.NFT), o.OfferID} {
+		data = append(data, byte(len(s)>>8), byte(len(s)))
+		data = append(data, s...)
+	}
+	return data
+}
+
+// decodeNFTOffer 解码报价记录
+func decodeNFTOffer(data []byte) (*NFTOffer, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid NFT offer record")
+	if len(data) < 1 {
+		return nil, invalid
+	}
+	pos := 1 + int(data[0])
+	// buyer/seller(40) + 3个金额/时间字段(24)
+	if len(data) < pos+40+24 {
+		return nil, invalid
+	}
+
+	o := &NFTOffer{Status: string(data[1:pos])}
+	copy(o.Buyer[:], data[pos:pos+20])
+	copy(o.Seller[:], data[pos+20:pos+40])
+	pos += 40
+	o.Price = framework.Amount(readUint64(data[pos:]))
+	o.Expiry = readUint64(data[pos+8:])
+	o.CreatedAt = readUint64(data[pos+16:])
+	pos += 24
+
+	strs := make([]string, 2)
+	for i := range strs {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(data[pos])<<8 | int(data[pos+1])
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		strs[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	o.NFT = framework.TokenID(strs[0])
+	o.OfferID = strs[1]
+	return o, nil
+}
@@ -0,0 +1,125 @@
+package market
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+func newTestOffer(t *testing.T) *NFTOffer {
+	t.Helper()
+	offer, err := NewNFTOffer("offer_1", testBuyer, "nft_001", 500, 1000, 100)
+	if err != nil {
+		t.Fatalf("NewNFTOffer() error = %v", err)
+	}
+	return offer
+}
+
+// TestNFTOfferAccept 测试所有者在有效期内接受报价，报价金额付给所有者
+func TestNFTOfferAccept(t *testing.T) {
+	offer := newTestOffer(t)
+
+	if _, err := offer.Accept(testBuyer, 500); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Accept(buyer) code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+
+	payout, err := offer.Accept(testSeller, 1000)
+	if err != nil {
+		t.Fatalf("Accept() at expiry error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: testSeller, TokenID: OFFER_PAYMENT_TOKEN, Amount: 500}
+	if payout != want {
+		t.Errorf("Accept() payout = %+v, want %+v", payout, want)
+	}
+	if offer.Status != OFFER_STATUS_ACCEPTED || offer.Seller != testSeller {
+		t.Errorf("after Accept() status = %s, seller = %v", offer.Status, offer.Seller)
+	}
+
+	// 成交后不能再次接受或撤回
+	if _, err := offer.Accept(testSeller, 1000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Accept() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+	if _, err := offer.Withdraw(testBuyer, 2000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Withdraw() after accept code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestNFTOfferWithdrawAfterExpiry 测试报价过期后买方取回托管金额
+func TestNFTOfferWithdrawAfterExpiry(t *testing.T) {
+	offer := newTestOffer(t)
+
+	if _, err := offer.Withdraw(testBuyer, 1000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Withdraw() before expiry code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+	if _, err := offer.Withdraw(testSeller, 1001); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Withdraw() by non-buyer code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+
+	refund, err := offer.Withdraw(testBuyer, 1001)
+	if err != nil {
+		t.Fatalf("Withdraw() error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_PAYMENT, To: testBuyer, TokenID: OFFER_PAYMENT_TOKEN, Amount: 500}
+	if refund != want {
+		t.Errorf("Withdraw() refund = %+v, want %+v", refund, want)
+	}
+	if offer.Status != OFFER_STATUS_WITHDRAWN {
+		t.Errorf("after Withdraw() status = %s, want WITHDRAWN", offer.Status)
+	}
+	if _, err := offer.Withdraw(testBuyer, 1001); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Withdraw() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestNFTOfferAcceptExpired 测试接受已过期的报价被拒绝
+func TestNFTOfferAcceptExpired(t *testing.T) {
+	offer := newTestOffer(t)
+
+	if _, err := offer.Accept(testSeller, 1001); errCode(err) != framework.ERROR_TIMEOUT {
+		t.Errorf("Accept() after expiry code = %d, want ERROR_TIMEOUT", errCode(err))
+	}
+	if offer.Status != OFFER_STATUS_OPEN || offer.Seller != (framework.Address{}) {
+		t.Errorf("rejected Accept() changed offer: status = %s, seller = %v", offer.Status, offer.Seller)
+	}
+}
+
+// TestNFTOfferValidation 测试创建报价的参数验证
+func TestNFTOfferValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		buyer  framework.Address
+		nft    framework.TokenID
+		price  framework.Amount
+		expiry uint64
+	}{
+		{"zero buyer", framework.Address{}, "nft_001", 500, 1000},
+		{"empty nft", testBuyer, "", 500, 1000},
+		{"zero price", testBuyer, "nft_001", 0, 1000},
+		{"past expiry", testBuyer, "nft_001", 500, 100},
+	}
+	for _, tc := range cases {
+		if _, err := NewNFTOffer("offer_1", tc.buyer, tc.nft, tc.price, tc.expiry, 100); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: code = %d, want ERROR_INVALID_PARAMS", tc.name, errCode(err))
+		}
+	}
+}
+
+// TestNFTOfferCodec 测试报价记录编码往返
+func TestNFTOfferCodec(t *testing.T) {
+	offer := newTestOffer(t)
+	if _, err := offer.Accept(testSeller, 500); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+
+	decoded, err := decodeNFTOffer(encodeNFTOffer(offer))
+	if err != nil {
+		t.Fatalf("decodeNFTOffer() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, offer) {
+		t.Errorf("decodeNFTOffer() = %+v, want %+v", decoded, offer)
+	}
+	if _, err := decodeNFTOffer(encodeNFTOffer(offer)[:30]); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("decodeNFTOffer(truncated) code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
@@ -0,0 +1,108 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 紧急暂停 ====================
+//
+// 托管与释放流程会划转资金，事故响应时需要紧急停止。市场所有者（MARKET_OWNER_ROLE 角色的持有者）
+// 调用 PauseMarket 后，以下入口返回 ERROR_PAUSED，直到 UnpauseMarket 恢复：
+//   - 托管：Escrow、EscrowWithBond、FundEscrow、ReclaimEscrow、ReleaseEscrow、RefundEscrow、ResolveEscrow
+//   - 释放：Release、ReleaseWithSchedule、CreateVestingBatch、ClaimVested、RevokeVesting
+//
+// 清算拍卖、NFT 报价与 NFT 抵押借款不受影响；查询函数（GetEscrow、GetVestingGrant 等）始终可用。
+//
+// 所有者是框架登记的可轮换角色（见 framework.RegisterRole），合约在 Initialize 中设置初始持有者，
+// 之后可通过 framework.RotateRoleKey / AcceptRoleKey 轮换：
+//
+//	framework.InitRole(market.MARKET_OWNER_ROLE, framework.GetCaller())
+
+// MARKET_OWNER_ROLE 可暂停与恢复市场托管/释放流程的角色
+const MARKET_OWNER_ROLE = "market_owner"
+
+// marketPauseStateID 暂停标记状态ID，值为 "paused" 或 "active"
+const marketPauseStateID = "market_paused"
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: MARKET_OWNER_ROLE})
+}
+
+// PauseMarket 市场所有者紧急暂停托管与释放流程
+//
+// 🎯 **用途**：事故响应时停止所有托管与释放资金划转
+//
+// **返回**：
+//   - error: 调用者不是市场所有者（ERROR_UNAUTHORIZED）、已处于暂停状态（ERROR_INVALID_STATE）
+//
+// **注意**：
+//   - 调用者取自 framework.GetCaller()
+//   - 发出 MarketPaused 事件
+//
+// **示例**：
+//
+//	//export EmergencyPause
+//	func EmergencyPause() uint32 {
+//	    if err := market.PauseMarket(); err != nil {
+//	        if contractErr, ok := err.(*framework.ContractError); ok {
+//	            return contractErr.Code
+//	        }
+//	        return framework.ERROR_EXECUTION_FAILED
+//	    }
+//	    return framework.SUCCESS
+//	}
+func PauseMarket() error {
+	return setMarketPaused(true, "MarketPaused")
+}
+
+// UnpauseMarket 市场所有者恢复托管与释放流程
+//
+// **返回**：
+//   - error: 调用者不是市场所有者（ERROR_UNAUTHORIZED）、未处于暂停状态（ERROR_INVALID_STATE）
+//
+// **注意**：发出 MarketUnpaused 事件
+func UnpauseMarket() error {
+	return setMarketPaused(false, "MarketUnpaused")
+}
+
+// IsMarketPaused 托管与释放流程当前是否被暂停
+func IsMarketPaused() bool {
+	data, version, err := framework.GetStateFromChain([]byte(marketPauseStateID))
+	return err == nil && version > 0 && string(data) == "paused"
+}
+
+// requireMarketActive 暂停期间返回 ERROR_PAUSED
+func requireMarketActive() error {
+	if IsMarketPaused() {
+		return framework.NewContractError(framework.ERROR_PAUSED, "market is paused")
+	}
+	return nil
+}
+
+// setMarketPaused 校验所有者并写入暂停标记
+func setMarketPaused(paused bool, eventName string) error {
+	caller := framework.GetCaller()
+	if !framework.HasRole(MARKET_OWNER_ROLE, caller) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the market owner can pause or unpause")
+	}
+	if IsMarketPaused() == paused {
+		if paused {
+			return framework.NewContractError(framework.ERROR_INVALID_STATE, "market is already paused")
+		}
+		return framework.NewContractError(framework.ERROR_INVALID_STATE, "market is not paused")
+	}
+
+	flag := "active"
+	if paused {
+		flag = "paused"
+	}
+	_, version, _ := framework.GetStateFromChain([]byte(marketPauseStateID))
+	if _, err := framework.AppendStateOutputSimple([]byte(marketPauseStateID), version+1, []byte(flag), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save pause flag")
+	}
+
+	event := framework.NewEvent(eventName)
+	event.AddAddressField("owner", caller)
+	framework.EmitEvent(event)
+	return nil
+}
@@ -0,0 +1,152 @@
+//go:build !tinygo && !(js && wasm)
+
+package market
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var testOwner = framework.Address{9}
+
+// marketInvoke 以 caller 身份执行市场操作，返回错误码
+func marketInvoke(host *framework.MockHost, caller framework.Address, op func() error) framework.MockCallResult {
+	return host.Invoke(caller, nil, func() uint32 {
+		if err := op(); err != nil {
+			if ce, ok := err.(*framework.ContractError); ok {
+				return ce.Code
+			}
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.SUCCESS
+	})
+}
+
+func installMarketHost(t *testing.T) *framework.MockHost {
+	t.Helper()
+	host := framework.NewMockHost()
+	host.ContractAddress = framework.Address{0xCC}
+	host.Timestamp = 1000
+	t.Cleanup(framework.InstallMockHost(host))
+	if res := marketInvoke(host, testOwner, func() error { return framework.InitRole(MARKET_OWNER_ROLE, testOwner) }); res.Code != framework.SUCCESS {
+		t.Fatalf("InitRole() code = %d", res.Code)
+	}
+	return host
+}
+
+// TestMarketPauseOwnerOnly 测试只有市场所有者可以暂停与恢复，重复操作返回 ERROR_INVALID_STATE
+func TestMarketPauseOwnerOnly(t *testing.T) {
+	host := installMarketHost(t)
+
+	if res := marketInvoke(host, testBuyer, PauseMarket); res.Code != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("PauseMarket() by non-owner code = %d, want ERROR_UNAUTHORIZED", res.Code)
+	}
+	res := marketInvoke(host, testOwner, PauseMarket)
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("PauseMarket() code = %d", res.Code)
+	}
+	if len(res.Events) != 1 || res.Events[0].Name != "MarketPaused" {
+		t.Errorf("PauseMarket() events = %+v, want one MarketPaused", res.Events)
+	}
+	if !IsMarketPaused() {
+		t.Error("IsMarketPaused() = false after pause")
+	}
+	if res := marketInvoke(host, testOwner, PauseMarket); res.Code != framework.ERROR_INVALID_STATE {
+		t.Errorf("PauseMarket() twice code = %d, want ERROR_INVALID_STATE", res.Code)
+	}
+
+	if res := marketInvoke(host, testBuyer, UnpauseMarket); res.Code != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("UnpauseMarket() by non-owner code = %d, want ERROR_UNAUTHORIZED", res.Code)
+	}
+	if res := marketInvoke(host, testOwner, UnpauseMarket); res.Code != framework.SUCCESS {
+		t.Fatalf("UnpauseMarket() code = %d", res.Code)
+	}
+	if IsMarketPaused() {
+		t.Error("IsMarketPaused() = true after unpause")
+	}
+	if res := marketInvoke(host, testOwner, UnpauseMarket); res.Code != framework.ERROR_INVALID_STATE {
+		t.Errorf("UnpauseMarket() twice code = %d, want ERROR_INVALID_STATE", res.Code)
+	}
+}
+
+// TestMarketPauseBlocksEntryPoints 测试暂停期间每个托管与释放入口返回 ERROR_PAUSED 且不划转资金，恢复后正常执行
+func TestMarketPauseBlocksEntryPoints(t *testing.T) {
+	host := installMarketHost(t)
+	host.SetBalance(testBuyer, "", 100000)
+	host.SetBalance(testSeller, "USDT", 100000)
+
+	// 1. 暂停前准备已注资的托管与释放计划
+	setup := []func() error{
+		func() error {
+			return EscrowWithBond(testBuyer, testSeller, "", 1000, "USDT", 200, []byte("order_refund"), 2000)
+		},
+		func() error {
+			return EscrowWithBond(testBuyer, testSeller, "", 1000, "USDT", 200, []byte("order_release"), 2000)
+		},
+		func() error { return FundEscrow([]byte("order_refund"), testBuyer) },
+		func() error { return FundEscrow([]byte("order_refund"), testSeller) },
+		func() error { return FundEscrow([]byte("order_release"), testBuyer) },
+		func() error { return FundEscrow([]byte("order_release"), testSeller) },
+		func() error {
+			return ReleaseWithSchedule(testBuyer, testSeller, "", 4000, []byte("grant_1"), VestingSchedule{StartTime: 1000, Duration: 400}, true)
+		},
+	}
+	for i, op := range setup {
+		if res := marketInvoke(host, testBuyer, op); res.Code != framework.SUCCESS {
+			t.Fatalf("setup step %d code = %d", i, res.Code)
+		}
+	}
+	host.Timestamp = 1200
+
+	entries := []struct {
+		name string
+		op   func() error
+	}{
+		{"Escrow", func() error { return Escrow(testBuyer, testSeller, "", 500, []byte("plain_1")) }},
+		{"Release", func() error { return Release(testBuyer, testSeller, "", 500, []byte("plain_vesting_1")) }},
+		{"RefundEscrow", func() error { return RefundEscrow([]byte("order_refund")) }},
+		{"ReleaseEscrow", func() error { return ReleaseEscrow([]byte("order_release")) }},
+		{"ClaimVested", func() error {
+			_, err := ClaimVested(testSeller, []byte("grant_1"))
+			return err
+		}},
+	}
+
+	// 2. 暂停：所有入口返回 ERROR_PAUSED，余额不变
+	if res := marketInvoke(host, testOwner, PauseMarket); res.Code != framework.SUCCESS {
+		t.Fatalf("PauseMarket() code = %d", res.Code)
+	}
+	buyerBefore := host.Balance(testBuyer, "")
+	contractBefore := host.Balance(host.ContractAddress, "")
+	for _, e := range entries {
+		if res := marketInvoke(host, testBuyer, e.op); res.Code != framework.ERROR_PAUSED {
+			t.Errorf("%s() while paused code = %d, want ERROR_PAUSED", e.name, res.Code)
+		}
+	}
+	if got := host.Balance(testBuyer, ""); got != buyerBefore {
+		t.Errorf("buyer balance while paused = %d, want %d", got, buyerBefore)
+	}
+	if got := host.Balance(host.ContractAddress, ""); got != contractBefore {
+		t.Errorf("contract balance while paused = %d, want %d", got, contractBefore)
+	}
+	if res := marketInvoke(host, testBuyer, func() error { return RevokeVesting(testBuyer, []byte("grant_1")) }); res.Code != framework.ERROR_PAUSED {
+		t.Errorf("RevokeVesting() while paused code = %d, want ERROR_PAUSED", res.Code)
+	}
+	if _, err := GetVestingGrant([]byte("grant_1")); err != nil {
+		t.Errorf("GetVestingGrant() while paused error = %v, queries must stay available", err)
+	}
+
+	// 3. 恢复：所有入口正常执行
+	if res := marketInvoke(host, testOwner, UnpauseMarket); res.Code != framework.SUCCESS {
+		t.Fatalf("UnpauseMarket() code = %d", res.Code)
+	}
+	for _, e := range entries {
+		if res := marketInvoke(host, testBuyer, e.op); res.Code != framework.SUCCESS {
+			t.Errorf("%s() after unpause code = %d, want SUCCESS", e.name, res.Code)
+		}
+	}
+	if got := host.Balance(testSeller, ""); got != 500+500+1000+2000 {
+		t.Errorf("seller balance after unpause = %d, want %d", got, 500+500+1000+2000)
+	}
+}
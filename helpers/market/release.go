@@ -1,5 +1,3 @@
-//go:build tinygo || (js && wasm)
-
 package market
 
 import (
@@ -13,7 +11,7 @@ import (
 // **参数**：
 //   - from: 释放者地址
 //   - beneficiary: 受益人地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - totalAmount: 总释放金额
 //   - vestingID: 释放计划ID（由合约生成）
 //
@@ -34,7 +32,7 @@ import (
 //	    err := market.Release(
 //	        caller,
 //	        beneficiary,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(100000),
 //	        vestingID,
 //	    )
@@ -44,6 +42,9 @@ import (
 //	    return framework.SUCCESS
 //	}
 func Release(from, beneficiary framework.Address, tokenID framework.TokenID, totalAmount framework.Amount, vestingID []byte) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
 	// 1. 参数验证
 	if err := validateReleaseParams(from, beneficiary, totalAmount, vestingID); err != nil {
 		return err
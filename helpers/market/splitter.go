@@ -0,0 +1,157 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/splitter"
+)
+
+// ==================== 费用分账 ====================
+//
+// 协议手续费、服务费等需要按固定比例分给多个收款方（LP、金库、推荐人等）。
+// Splitter 是 helpers/splitter 分账配置（splitter.Split）的无状态用法：份额以基点表示、
+// 总和必须为 10000，Distribute 从合约地址将一笔金额按份额一次转给各收款方。
+//
+// 与 splitter.Release 的累计记账不同，这里每次分账独立结算：按份额向下取整后的余数
+// （dust，至多收款方数量 - 1 个最小单位）计入第一个收款方，分出的总额始终等于输入金额，
+// 合约地址上不会残留零头。
+
+// SPLITTER_TOTAL_BP 份额总和（10000 = 100%）
+const SPLITTER_TOTAL_BP framework.BasisPoints = 10000
+
+// SplitRecipient 分账收款方
+type SplitRecipient struct {
+	Address framework.Address
+	// ShareBP 份额（基点），必须大于 0
+	ShareBP framework.BasisPoints
+}
+
+// SplitPayout 一次分账中某个收款方应得的金额
+type SplitPayout struct {
+	To     framework.Address
+	Amount framework.Amount
+}
+
+// Splitter 按固定基点份额分账的收款方列表
+type Splitter struct {
+	split *splitter.Split
+}
+
+// NewSplitter 创建分账器
+//
+// 🎯 **用途**：在合约中声明手续费的分配方式
+//
+// **参数**：
+//   - recipients: 收款方列表，顺序即分账顺序，第一个收款方额外获得取整余数
+//
+// **返回**：
+//   - error: 不满足 splitter.NewSplit 的规则（列表为空或超过 splitter.MAX_RECIPIENTS、
+//     地址为空或重复、份额为 0），或份额总和不等于 SPLITTER_TOTAL_BP 时返回 ERROR_INVALID_PARAMS
+//
+// **示例**：
+//
+//	feeSplitter, err := market.NewSplitter([]market.SplitRecipient{
+//	    {Address: treasuryAddr, ShareBP: 5000}, // 金库 50%，同时获得取整余数
+//	    {Address: lpPool, ShareBP: 3000},       // LP 30%
+//	    {Address: referrer, ShareBP: 2000},     // 推荐人 20%
+//	})
+func NewSplitter(recipients []SplitRecipient) (*Splitter, error) {
+	addrs := make([]framework.Address, len(recipients))
+	shares := make([]uint64, len(recipients))
+	for i, r := range recipients {
+		addrs[i], shares[i] = r.Address, uint64(r.ShareBP)
+	}
+	split, err := splitter.NewSplit(addrs, shares)
+	if err != nil {
+		return nil, err
+	}
+	if split.TotalShares() != uint64(SPLITTER_TOTAL_BP) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "recipient shares must sum to 10000 bp")
+	}
+	return &Splitter{split: split}, nil
+}
+
+// Recipients 返回收款方列表（副本）
+func (s *Splitter) Recipients() []SplitRecipient {
+	recipients := make([]SplitRecipient, len(s.split.Recipients))
+	for i, addr := range s.split.Recipients {
+		recipients[i] = SplitRecipient{Address: addr, ShareBP: framework.BasisPoints(s.split.Shares[i])}
+	}
+	return recipients
+}
+
+// Split 计算 amount 按份额分给各收款方的金额（不转账）
+//
+// 每个收款方得到 splitter.PendingPayment(amount, ShareBP, 10000, 0)，即 amount × ShareBP / 10000
+// 向下取整，余数计入第一个收款方；返回值与收款方顺序一致，金额可能为 0（amount 很小时），总和等于 amount。
+func (s *Splitter) Split(amount framework.Amount) []SplitPayout {
+	payouts := make([]SplitPayout, len(s.split.Recipients))
+	var distributed framework.Amount
+	for i, addr := range s.split.Recipients {
+		share := framework.Amount(splitter.PendingPayment(uint64(amount), s.split.Shares[i], uint64(SPLITTER_TOTAL_BP), 0))
+		payouts[i] = SplitPayout{To: addr, Amount: share}
+		distributed += share
+	}
+	payouts[0].Amount += amount - distributed
+	return payouts
+}
+
+// Distribute 从合约地址将 amount 按份额转给各收款方
+//
+// 🎯 **用途**：分配合约已收取的手续费
+//
+// **参数**：
+//   - tokenID: 代币ID（空字符串表示原生币）
+//   - amount: 分账总额
+//
+// **返回**：
+//   - error: 金额为 0（ERROR_INVALID_PARAMS）、合约余额不足（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：
+//   - 所有转账在同一笔交易中提交，任一失败则全部不生效
+//   - 分得金额为 0 的收款方不转账、不发事件
+//   - 每个收到转账的收款方发出一个 FeeDistributed 事件（to / token_id / amount / share_bp / total_amount）
+//
+// **示例**：
+//
+//	fee := framework.Amount(uint64(tradeAmount) * 30 / 10000)
+//	if err := feeSplitter.Distribute("USDT", fee); err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func (s *Splitter) Distribute(tokenID framework.TokenID, amount framework.Amount) error {
+	// 1. 校验金额与合约余额
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	contractAddr := framework.GetContractAddress()
+	if framework.QueryUTXOBalance(contractAddr, tokenID) < amount {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient contract balance to distribute")
+	}
+
+	// 2. 按份额转账，同一笔交易提交
+	payouts := s.Split(amount)
+	builder := framework.BeginTransaction()
+	for _, p := range payouts {
+		if p.Amount > 0 {
+			builder = builder.Transfer(contractAddr, p.To, tokenID, p.Amount)
+		}
+	}
+	success, _, errCode := builder.Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "fee distribution failed")
+	}
+
+	// 3. 逐个收款方发出事件
+	for i, p := range payouts {
+		if p.Amount == 0 {
+			continue
+		}
+		event := framework.NewEvent("FeeDistributed")
+		event.AddAddressField("to", p.To)
+		event.AddStringField("token_id", string(tokenID))
+		event.AddUint64Field("amount", uint64(p.Amount))
+		event.AddUint64Field("share_bp", s.split.Shares[i])
+		event.AddUint64Field("total_amount", uint64(amount))
+		framework.EmitEvent(event)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+//go:build !tinygo && !(js && wasm)
+
+package market
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/splitter"
+)
+
+var (
+	testTreasury = framework.Address{0x71}
+	testLP       = framework.Address{0x72}
+	testReferrer = framework.Address{0x73}
+)
+
+func newThreeWaySplitter(t *testing.T) *Splitter {
+	t.Helper()
+	feeSplitter, err := NewSplitter([]SplitRecipient{
+		{Address: testTreasury, ShareBP: 5000},
+		{Address: testLP, ShareBP: 3000},
+		{Address: testReferrer, ShareBP: 2000},
+	})
+	if err != nil {
+		t.Fatalf("NewSplitter() error = %v", err)
+	}
+	return feeSplitter
+}
+
+// TestSplitterThreeWayDistribute 测试三方分账：各收款方按份额收到转账，合约余额减少分账总额
+func TestSplitterThreeWayDistribute(t *testing.T) {
+	host := installMarketHost(t)
+	host.SetBalance(host.ContractAddress, "USDT", 50000)
+	feeSplitter := newThreeWaySplitter(t)
+
+	res := marketInvoke(host, testBuyer, func() error { return feeSplitter.Distribute("USDT", 10000) })
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("Distribute() code = %d", res.Code)
+	}
+	for addr, want := range map[framework.Address]framework.Amount{testTreasury: 5000, testLP: 3000, testReferrer: 2000, host.ContractAddress: 40000} {
+		if got := host.Balance(addr, "USDT"); got != want {
+			t.Errorf("balance of %x = %d, want %d", addr[0], got, want)
+		}
+	}
+	if len(res.Transfers) != 3 || len(res.Events) != 3 || res.Events[1].Name != "FeeDistributed" || res.Events[1].Data["share_bp"] != uint64(3000) {
+		t.Errorf("Distribute() transfers = %d, events = %+v", len(res.Transfers), res.Events)
+	}
+
+	// 合约余额不足或金额为 0 时不转账
+	if res := marketInvoke(host, testBuyer, func() error { return feeSplitter.Distribute("USDT", 40001) }); res.Code != framework.ERROR_INSUFFICIENT_BALANCE || len(res.Transfers) != 0 {
+		t.Errorf("Distribute() over balance code = %d, %d transfers", res.Code, len(res.Transfers))
+	}
+	if res := marketInvoke(host, testBuyer, func() error { return feeSplitter.Distribute("USDT", 0) }); res.Code != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Distribute(0) code = %d, want ERROR_INVALID_PARAMS", res.Code)
+	}
+}
+
+// TestSplitterDust 测试取整余数计入第一个收款方，分出总额等于输入金额
+func TestSplitterDust(t *testing.T) {
+	feeSplitter, err := NewSplitter([]SplitRecipient{
+		{Address: testTreasury, ShareBP: 3334},
+		{Address: testLP, ShareBP: 3333},
+		{Address: testReferrer, ShareBP: 3333},
+	})
+	if err != nil {
+		t.Fatalf("NewSplitter() error = %v", err)
+	}
+	tests := []struct {
+		amount framework.Amount
+		want   []framework.Amount
+	}{
+		{100, []framework.Amount{34, 33, 33}},
+		{10, []framework.Amount{4, 3, 3}}, // 3.334 / 3.333 / 3.333，余数 1 给第一个
+		{2, []framework.Amount{2, 0, 0}},  // 每份都取整为 0
+		{1, []framework.Amount{1, 0, 0}},
+		{^framework.Amount(0), []framework.Amount{6150144474174764509, 6148299799767393553, 6148299799767393553}}, // 128 位中间结果，不溢出
+	}
+	for _, tt := range tests {
+		payouts := feeSplitter.Split(tt.amount)
+		var total framework.Amount
+		for i, p := range payouts {
+			if p.Amount != tt.want[i] {
+				t.Errorf("Split(%d)[%d] = %d, want %d", tt.amount, i, p.Amount, tt.want[i])
+			}
+			total += p.Amount
+		}
+		if total != tt.amount {
+			t.Errorf("Split(%d) total = %d", tt.amount, total)
+		}
+	}
+
+	// 分得 0 的收款方不转账
+	host := installMarketHost(t)
+	host.SetBalance(host.ContractAddress, "", 2)
+	res := marketInvoke(host, testBuyer, func() error { return feeSplitter.Distribute("", 2) })
+	if res.Code != framework.SUCCESS || len(res.Transfers) != 1 || host.Balance(testTreasury, "") != 2 {
+		t.Errorf("Distribute(2) code = %d, %d transfers, treasury %d", res.Code, len(res.Transfers), host.Balance(testTreasury, ""))
+	}
+}
+
+// TestNewSplitterValidation 测试份额总和必须为 10000，地址不能为空或重复，收款方数量不超过 splitter.MAX_RECIPIENTS
+func TestNewSplitterValidation(t *testing.T) {
+	invalid := map[string][]SplitRecipient{
+		"empty":        nil,
+		"sum below":    {{testTreasury, 5000}, {testLP, 4999}},
+		"sum above":    {{testTreasury, 5000}, {testLP, 5001}},
+		"zero share":   {{testTreasury, 10000}, {testLP, 0}},
+		"zero address": {{framework.Address{}, 10000}},
+		"duplicate":    {{testTreasury, 5000}, {testTreasury, 5000}},
+		"share over":   {{testTreasury, 20000}},
+	}
+	tooMany := make([]SplitRecipient, splitter.MAX_RECIPIENTS+1)
+	for i := range tooMany {
+		tooMany[i] = SplitRecipient{Address: framework.Address{0x80, byte(i)}, ShareBP: 1}
+	}
+	tooMany[0].ShareBP = SPLITTER_TOTAL_BP - splitter.MAX_RECIPIENTS
+	invalid["too many"] = tooMany
+	for name, recipients := range invalid {
+		if _, err := NewSplitter(recipients); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: NewSplitter() error = %v, want ERROR_INVALID_PARAMS", name, err)
+		}
+	}
+
+	recipients := []SplitRecipient{{testTreasury, 10000}}
+	feeSplitter, err := NewSplitter(recipients)
+	if err != nil {
+		t.Fatalf("single recipient NewSplitter() error = %v", err)
+	}
+	recipients[0].ShareBP = 1
+	if got := feeSplitter.Recipients(); got[0].ShareBP != 10000 {
+		t.Errorf("Recipients() shares caller's slice: %+v", got)
+	}
+}
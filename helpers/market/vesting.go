@@ -0,0 +1,288 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 按时间表释放（纯状态机） ====================
+//
+// 授予方将代币托管在合约地址，按线性时间表逐步归属受益人：悬崖期（cliff）内不归属，
+// 之后按已经过时间占总时长的比例归属，到期后全部归属。受益人随时领取已归属未领取的部分。
+// 可撤销的授予（员工激励等）允许授予方在受益人离开时撤销：未归属部分退还授予方，
+// 已归属部分仍可由受益人领取。
+//
+// 本文件只包含不依赖宿主函数的授予记录与状态迁移，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金划转、状态写入见 vesting_schedule.go。
+
+// 释放计划状态
+const (
+	// VESTING_STATUS_ACTIVE 释放中：按时间表归属
+	VESTING_STATUS_ACTIVE = "ACTIVE"
+	// VESTING_STATUS_REVOKED 已撤销：归属冻结在撤销时刻，未归属部分已退还授予方
+	VESTING_STATUS_REVOKED = "REVOKED"
+)
+
+// 释放计划划转的资金
+const (
+	// ESCROW_LEG_VESTED 已归属部分（领取给受益人）
+	ESCROW_LEG_VESTED = "vested"
+	// ESCROW_LEG_UNVESTED 未归属部分（撤销时退还授予方）
+	ESCROW_LEG_UNVESTED = "unvested"
+)
+
+// VestingSchedule 线性释放时间表
+type VestingSchedule struct {
+	// StartTime 开始时间（Unix 时间戳，秒）
+	StartTime uint64
+	// Cliff 悬崖期（秒），StartTime+Cliff 之前归属为 0；不能超过 Duration
+	Cliff uint64
+	// Duration 总时长（秒），StartTime+Duration 起全部归属
+	Duration uint64
+}
+
+// VestingGrant 释放计划记录
+type VestingGrant struct {
+	VestingID   string
+	Status      string
+	Grantor     framework.Address
+	Beneficiary framework.Address
+	TokenID     framework.TokenID
+	TotalAmount framework.Amount
+	Schedule    VestingSchedule
+	// Revocable 授予方是否可以撤销未归属部分
+	Revocable bool
+	// Claimed 受益人已领取的金额
+	Claimed framework.Amount
+	// RevokedAt 撤销时间（未撤销为 0），归属冻结在该时刻
+	RevokedAt uint64
+}
+
+// NewVestingGrant 创建处于 ACTIVE 状态的释放计划
+//
+// **参数**：
+//   - totalAmount: 总释放金额，必须大于 0
+//   - schedule: 释放时间表，Duration 必须大于 0 且不小于 Cliff
+//   - revocable: 授予方是否可以撤销
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewVestingGrant(vestingID string, grantor, beneficiary framework.Address, tokenID framework.TokenID, totalAmount framework.Amount, schedule VestingSchedule, revocable bool) (*VestingGrant, error) {
+	if err := validateReleaseParams(grantor, beneficiary, totalAmount, []byte(vestingID)); err != nil {
+		return nil, err
+	}
+	if len(vestingID) > 0xFFFF || len(tokenID) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "vestingID or tokenID too long")
+	}
+	if schedule.Duration == 0 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "duration must be greater than 0")
+	}
+	if schedule.Cliff > schedule.Duration {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "cliff cannot exceed duration")
+	}
+	if schedule.StartTime > ^uint64(0)-schedule.Duration {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "schedule end overflows")
+	}
+
+	return &VestingGrant{
+		VestingID:   vestingID,
+		Status:      VESTING_STATUS_ACTIVE,
+		Grantor:     grantor,
+		Beneficiary: beneficiary,
+		TokenID:     tokenID,
+		TotalAmount: totalAmount,
+		Schedule:    schedule,
+		Revocable:   revocable,
+	}, nil
+}
+
+// VestedAmount 截至 now 已归属的金额（含已领取部分）
+//
+// 撤销后归属冻结在撤销时刻。
+func (g *VestingGrant) VestedAmount(now uint64) framework.Amount {
+	if g.Status == VESTING_STATUS_REVOKED && now > g.RevokedAt {
+		now = g.RevokedAt
+	}
+	s := g.Schedule
+	if now < s.StartTime+s.Cliff {
+		return 0
+	}
+	elapsed := now - s.StartTime
+	if elapsed >= s.Duration {
+		return g.TotalAmount
+	}
+	// elapsed < Duration，结果小于 TotalAmount，不会溢出
+	vested, _ := mulDiv(uint64(g.TotalAmount), elapsed, s.Duration)
+	return framework.Amount(vested)
+}
+
+// Claimable 截至 now 可领取的金额（已归属未领取）
+func (g *VestingGrant) Claimable(now uint64) framework.Amount {
+	return g.VestedAmount(now) - g.Claimed
+}
+
+// Claim 受益人领取已归属未领取的部分
+//
+// **返回**：
+//   - EscrowPayout: 付给受益人的金额
+//   - error: 非受益人（ERROR_UNAUTHORIZED）、没有可领取的金额（ERROR_INVALID_STATE）
+//
+// **注意**：撤销后受益人仍可领取撤销前已归属的部分
+func (g *VestingGrant) Claim(beneficiary framework.Address, now uint64) (EscrowPayout, error) {
+	if beneficiary != g.Beneficiary {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the beneficiary can claim")
+	}
+	amount := g.Claimable(now)
+	if amount == 0 {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "nothing to claim")
+	}
+
+	g.Claimed += amount
+	return EscrowPayout{Leg: ESCROW_LEG_VESTED, To: g.Beneficiary, TokenID: g.TokenID, Amount: amount}, nil
+}
+
+// Revoke 授予方撤销释放计划，未归属部分退还授予方
+//
+// **返回**：
+//   - EscrowPayout: 退还授予方的未归属金额
+//   - error: 不可撤销（ERROR_PERMISSION_DENIED）、非授予方（ERROR_UNAUTHORIZED）、
+//     已撤销或已全部归属（ERROR_INVALID_STATE）
+//
+// **注意**：归属冻结在 now，已归属部分（VestedAmount(now) - Claimed）仍可由受益人领取
+func (g *VestingGrant) Revoke(grantor framework.Address, now uint64) (EscrowPayout, error) {
+	if !g.Revocable {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "vesting grant is not revocable")
+	}
+	if grantor != g.Grantor {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the grantor can revoke")
+	}
+	if g.Status != VESTING_STATUS_ACTIVE {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "vesting grant is not active")
+	}
+	unvested := g.TotalAmount - g.VestedAmount(now)
+	if unvested == 0 {
+		return EscrowPayout{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "vesting grant is fully vested")
+	}
+
+	g.Status = VESTING_STATUS_REVOKED
+	g.RevokedAt = now
+	return EscrowPayout{Leg: ESCROW_LEG_UNVESTED, To: g.Grantor, TokenID: g.TokenID, Amount: unvested}, nil
+}
+
+// VestingBatchGrant 批量创建释放计划中的一项
+type VestingBatchGrant struct {
+	Beneficiary framework.Address
+	Amount      framework.Amount
+	// Start / Duration / Cliff 同 VestingSchedule 的 StartTime / Duration / Cliff
+	Start     uint64
+	Duration  uint64
+	Cliff     uint64
+	Revocable bool
+}
+
+// NewVestingGrantBatch 由同一授予方、同一代币批量创建释放计划
+//
+// 释放计划ID依次为 VestingBatchID(firstSeq)、VestingBatchID(firstSeq+1)……
+//
+// **返回**：
+//   - []*VestingGrant: 与 grants 顺序一致的释放计划
+//   - framework.Amount: 总释放金额
+//   - error: 列表为空、任一项参数无效或总金额溢出时返回 ERROR_INVALID_PARAMS
+func NewVestingGrantBatch(from framework.Address, tokenID framework.TokenID, grants []VestingBatchGrant, firstSeq uint64) ([]*VestingGrant, framework.Amount, error) {
+	if len(grants) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "grants list cannot be empty")
+	}
+
+	result := make([]*VestingGrant, 0, len(grants))
+	var total framework.Amount
+	for i, spec := range grants {
+		schedule := VestingSchedule{StartTime: spec.Start, Cliff: spec.Cliff, Duration: spec.Duration}
+		grant, err := NewVestingGrant(VestingBatchID(firstSeq+uint64(i)), from, spec.Beneficiary, tokenID, spec.Amount, schedule, spec.Revocable)
+		if err != nil {
+			return nil, 0, err
+		}
+		if total > ^framework.Amount(0)-spec.Amount {
+			return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "total amount overflows")
+		}
+		total += spec.Amount
+		result = append(result, grant)
+	}
+	return result, total, nil
+}
+
+// VestingBatchID 返回批量创建的第 seq 个释放计划ID（vesting_{seq}）
+func VestingBatchID(seq uint64) string {
+	return "vesting_" + framework.Uint64ToString(seq)
+}
+
+// ==================== 编码 ====================
+
+// encodeVestingGrant 编码释放计划记录
+//
+// 编码格式（大端）：
+//
+//	statusLen(1) + status + grantor(20) + beneficiary(20) + totalAmount(8) + claimed(8) +
+//	startTime(8) + cliff(8) + duration(8) + revokedAt(8) + revocable(1) +
+//	tokenLen(2) + token + vestingIDLen(2) + vestingID
+func encodeVestingGrant(g *VestingGrant) []byte {
+	data := make([]byte, 0, 1+len(g.Status)+40+48+1+4+len(g.TokenID)+len(g.VestingID))
+	data = append(data, byte(len(g.Status)))
+	data = append(data, g.Status...)
+	data = append(data, g.Grantor[:]...)
+	data = append(data, g.Beneficiary[:]...)
+	for _, v := range []uint64{uint64(g.TotalAmount), uint64(g.Claimed), g.Schedule.StartTime, g.Schedule.Cliff, g.Schedule.Duration, g.RevokedAt} {
+		data = appendUint64(data, v)
+	}
+	if g.Revocable {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	for _, s := range []string{string(g.TokenID), g.VestingID} {
+		data = append(data, byte(len(s)>>8), byte(len(s)))
+		data = append(data, s...)
+	}
+	return data
+}
+
+// decodeVestingGrant 解码释放计划记录
+func decodeVestingGrant(data []byte) (*VestingGrant, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid vesting grant record")
+	if len(data) < 1 {
+		return nil, invalid
+	}
+	pos := 1 + int(data[0])
+	// grantor/beneficiary(40) + 6个金额/时间字段(48) + revocable(1)
+	if len(data) < pos+40+48+1 {
+		return nil, invalid
+	}
+
+	g := &VestingGrant{Status: string(data[1:pos])}
+	copy(g.Grantor[:], data[pos:pos+20])
+	copy(g.Beneficiary[:], data[pos+20:pos+40])
+	pos += 40
+	g.TotalAmount = framework.Amount(readUint64(data[pos:]))
+	g.Claimed = framework.Amount(readUint64(data[pos+8:]))
+	g.Schedule.StartTime = readUint64(data[pos+16:])
+	g.Schedule.Cliff = readUint64(data[pos+24:])
+	g.Schedule.Duration = readUint64(data[pos+32:])
+	g.RevokedAt = readUint64(data[pos+40:])
+	g.Revocable = data[pos+48] == 1
+	pos += 49
+
+	strs := make([]string, 2)
+	for i := range strs {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(data[pos])<<8 | int(data[pos+1])
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		strs[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	g.TokenID = framework.TokenID(strs[0])
+	g.VestingID = strs[1]
+	return g, nil
+}
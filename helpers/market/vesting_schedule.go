@@ -0,0 +1,287 @@
+package market
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ReleaseWithSchedule 按时间表分阶段释放
+//
+// 🎯 **用途**：员工激励、投资解锁等场景——代币从授予方转入合约地址托管，
+// 按线性时间表（含悬崖期）逐步归属受益人，受益人通过 ClaimVested 领取已归属部分
+//
+// **参数**：
+//   - from: 授予方地址（出资）
+//   - beneficiary: 受益人地址
+//   - tokenID: 代币ID（空字符串表示原生币）
+//   - totalAmount: 总释放金额
+//   - vestingID: 释放计划ID（由合约生成）
+//   - schedule: 释放时间表（开始时间、悬崖期、总时长）
+//   - revocable: 是否允许授予方通过 RevokeVesting 撤销未归属部分
+//
+// **返回**：
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、释放计划已存在（ERROR_ALREADY_EXISTS）、
+//     余额不足（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：
+//   - 与 Release 不同，资金不直接转给受益人，而是由合约地址托管直到归属
+//   - 谁可以创建、撤销释放计划是业务逻辑，需要在合约代码中实现
+//
+// **示例**：
+//
+//	// 4 年线性释放，1 年悬崖期，员工离职时可撤销
+//	schedule := market.VestingSchedule{
+//	    StartTime: framework.GetTimestamp(),
+//	    Cliff:     365 * 86400,
+//	    Duration:  4 * 365 * 86400,
+//	}
+//	err := market.ReleaseWithSchedule(caller, employee, "", framework.Amount(48000), []byte("grant_001"), schedule, true)
+func ReleaseWithSchedule(from, beneficiary framework.Address, tokenID framework.TokenID, totalAmount framework.Amount, vestingID []byte, schedule VestingSchedule, revocable bool) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	// 1. 参数验证
+	grant, err := NewVestingGrant(string(vestingID), from, beneficiary, tokenID, totalAmount, schedule, revocable)
+	if err != nil {
+		return err
+	}
+
+	// 2. 释放计划ID唯一性检查
+	stateID := buildVestingGrantStateID(vestingID)
+	if _, version, err := framework.GetStateFromChain(stateID); err == nil && version > 0 {
+		return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "vesting grant already exists")
+	}
+
+	// 3. 查询余额
+	if framework.QueryUTXOBalance(from, tokenID) < totalAmount {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to release")
+	}
+
+	// 4. 资金转入合约地址托管，写入释放计划记录
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(from, framework.GetContractAddress(), tokenID, totalAmount).
+		AddStateOutput(stateID, 1, encodeVestingGrant(grant)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "release with schedule failed")
+	}
+
+	// 5. 发出事件
+	framework.EmitEvent(newVestingGrantEvent("VestingScheduled", grant))
+
+	return nil
+}
+
+// vestingSeqStateID 批量释放计划序号状态ID（十进制字符串）
+const vestingSeqStateID = "vesting_seq"
+
+// CreateVestingBatch 批量创建按时间表释放的计划
+//
+// 🎯 **用途**：向团队成员分配代币时一次创建多个释放计划——总金额从授予方一次转入合约地址托管，
+// 每个受益人的计划各自按时间表归属、领取与撤销
+//
+// **参数**：
+//   - from: 授予方地址（出资）
+//   - tokenID: 代币ID（空字符串表示原生币），同一批次使用同一代币
+//   - grants: 释放计划列表（受益人、金额、开始时间、总时长、悬崖期、是否可撤销）
+//
+// **返回**：
+//   - []string: 与 grants 顺序一致的释放计划ID（vesting_{序号}），用于 ClaimVested / RevokeVesting
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、ID 已被占用（ERROR_ALREADY_EXISTS）、
+//     余额不足以覆盖总金额（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：所有计划与资金划转在同一笔交易中写入，任一项无效时整批不创建
+//
+// **示例**：
+//
+//	now := framework.GetTimestamp()
+//	ids, err := market.CreateVestingBatch(caller, "TEAM", []market.VestingBatchGrant{
+//	    {Beneficiary: alice, Amount: 40000, Start: now, Duration: 4 * 365 * 86400, Cliff: 365 * 86400, Revocable: true},
+//	    {Beneficiary: bob, Amount: 20000, Start: now, Duration: 2 * 365 * 86400},
+//	})
+func CreateVestingBatch(from framework.Address, tokenID framework.TokenID, grants []VestingBatchGrant) ([]string, error) {
+	if err := requireMarketActive(); err != nil {
+		return nil, err
+	}
+	// 1. 分配释放计划ID并验证参数
+	seqData, _ := framework.GetState(vestingSeqStateID)
+	firstSeq := parseAuctionSeq(seqData) + 1
+	batch, total, err := NewVestingGrantBatch(from, tokenID, grants, firstSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. ID 唯一性检查（可能与 ReleaseWithSchedule 自定义的ID冲突）
+	for _, grant := range batch {
+		if _, version, err := framework.GetStateFromChain(buildVestingGrantStateID([]byte(grant.VestingID))); err == nil && version > 0 {
+			return nil, framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "vesting grant "+grant.VestingID+" already exists")
+		}
+	}
+
+	// 3. 查询余额
+	if framework.QueryUTXOBalance(from, tokenID) < total {
+		return nil, framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to release")
+	}
+
+	// 4. 总金额转入合约地址托管，写入全部释放计划记录与序号
+	lastSeq := firstSeq + uint64(len(batch)) - 1
+	builder := framework.BeginTransaction().
+		Transfer(from, framework.GetContractAddress(), tokenID, total)
+	ids := make([]string, 0, len(batch))
+	for _, grant := range batch {
+		builder = builder.AddStateOutput(buildVestingGrantStateID([]byte(grant.VestingID)), 1, encodeVestingGrant(grant))
+		ids = append(ids, grant.VestingID)
+	}
+	success, _, errCode := builder.
+		AddStateOutput([]byte(vestingSeqStateID), lastSeq, []byte(framework.Uint64ToString(lastSeq))).
+		Finalize()
+	if !success {
+		return nil, framework.NewContractError(errCode, "vesting batch creation failed")
+	}
+
+	// 5. 每个释放计划发出一条事件
+	for _, grant := range batch {
+		framework.EmitEvent(newVestingGrantEvent("VestingScheduled", grant))
+	}
+
+	return ids, nil
+}
+
+// ClaimVested 受益人领取已归属未领取的部分
+//
+// **返回**：
+//   - framework.Amount: 本次领取的金额
+//   - error: 释放计划不存在（ERROR_NOT_FOUND）、非受益人（ERROR_UNAUTHORIZED）、
+//     没有可领取的金额（ERROR_INVALID_STATE）
+func ClaimVested(beneficiary framework.Address, vestingID []byte) (framework.Amount, error) {
+	if err := requireMarketActive(); err != nil {
+		return 0, err
+	}
+	grant, version, err := loadVestingGrant(vestingID)
+	if err != nil {
+		return 0, err
+	}
+
+	payout, err := grant.Claim(beneficiary, framework.GetTimestamp())
+	if err != nil {
+		return 0, err
+	}
+
+	builder := framework.BeginTransaction().
+		Transfer(framework.GetContractAddress(), payout.To, payout.TokenID, payout.Amount)
+	if err := commitVestingGrant(builder, grant, version); err != nil {
+		return 0, err
+	}
+
+	event := newVestingGrantEvent("VestingClaimed", grant)
+	event.AddUint64Field("amount", uint64(payout.Amount))
+	framework.EmitEvent(event)
+	return payout.Amount, nil
+}
+
+// RevokeVesting 授予方撤销可撤销的释放计划
+//
+// 归属冻结在当前时间：未归属部分从合约地址退还授予方，已归属未领取的部分留在合约地址，
+// 受益人之后仍可通过 ClaimVested 领取。
+//
+// **参数**：
+//   - grantor: 授予方（创建释放计划时的 from）
+//   - vestingID: 释放计划ID
+//
+// **返回**：
+//   - error: 释放计划不存在（ERROR_NOT_FOUND）、创建时 revocable 为 false（ERROR_PERMISSION_DENIED）、
+//     非授予方（ERROR_UNAUTHORIZED）、已撤销或已全部归属（ERROR_INVALID_STATE）
+//
+// **示例**：
+//
+//	// 员工离职：收回未归属部分
+//	if err := market.RevokeVesting(caller, []byte("grant_001")); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func RevokeVesting(grantor framework.Address, vestingID []byte) error {
+	if err := requireMarketActive(); err != nil {
+		return err
+	}
+	grant, version, err := loadVestingGrant(vestingID)
+	if err != nil {
+		return err
+	}
+
+	now := framework.GetTimestamp()
+	refund, err := grant.Revoke(grantor, now)
+	if err != nil {
+		return err
+	}
+
+	builder := framework.BeginTransaction().
+		Transfer(framework.GetContractAddress(), refund.To, refund.TokenID, refund.Amount)
+	if err := commitVestingGrant(builder, grant, version); err != nil {
+		return err
+	}
+
+	event := newVestingGrantEvent("VestingRevoked", grant)
+	event.AddUint64Field("vested", uint64(grant.VestedAmount(now)))
+	event.AddUint64Field("unvested_refunded", uint64(refund.Amount))
+	framework.EmitEvent(event)
+	return nil
+}
+
+// GetVestingGrant 查询释放计划记录
+//
+// **返回**：
+//   - *VestingGrant: 释放计划记录（VestedAmount / Claimable 计算归属与可领取金额）
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetVestingGrant(vestingID []byte) (*VestingGrant, error) {
+	grant, _, err := loadVestingGrant(vestingID)
+	return grant, err
+}
+
+// loadVestingGrant 读取释放计划记录及其状态版本
+func loadVestingGrant(vestingID []byte) (*VestingGrant, uint64, error) {
+	if len(vestingID) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "vestingID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildVestingGrantStateID(vestingID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "vesting grant not found")
+	}
+	grant, err := decodeVestingGrant(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return grant, version, nil
+}
+
+// commitVestingGrant 将划转与新版本释放计划记录放入同一笔交易提交
+func commitVestingGrant(builder *framework.TransactionBuilder, grant *VestingGrant, version uint64) error {
+	success, _, errCode := builder.
+		AddStateOutput(buildVestingGrantStateID([]byte(grant.VestingID)), version+1, encodeVestingGrant(grant)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "vesting grant transaction failed")
+	}
+	return nil
+}
+
+// newVestingGrantEvent 构建包含授予双方、金额与时间表的事件
+func newVestingGrantEvent(name string, grant *VestingGrant) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddStringField("vesting_id", grant.VestingID)
+	event.AddStringField("status", grant.Status)
+	event.AddAddressField("grantor", grant.Grantor)
+	event.AddAddressField("beneficiary", grant.Beneficiary)
+	event.AddStringField("token_id", string(grant.TokenID))
+	event.AddUint64Field("total_amount", uint64(grant.TotalAmount))
+	event.AddUint64Field("claimed", uint64(grant.Claimed))
+	event.AddUint64Field("start_time", grant.Schedule.StartTime)
+	event.AddUint64Field("cliff", grant.Schedule.Cliff)
+	event.AddUint64Field("duration", grant.Schedule.Duration)
+	event.AddBoolField("revocable", grant.Revocable)
+	return event
+}
+
+// buildVestingGrantStateID 构建按时间表释放计划的状态ID
+//
+// 与 Release 的 vesting:{vesting_id} 状态分开，避免同一ID的两类记录互相覆盖
+func buildVestingGrantStateID(vestingID []byte) []byte {
+	return []byte("vesting_grant:" + string(vestingID))
+}
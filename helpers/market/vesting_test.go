@@ -0,0 +1,273 @@
+package market
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var (
+	testGrantor     = framework.Address{4}
+	testEmployee    = framework.Address{5}
+	testVestingPlan = VestingSchedule{StartTime: 1000, Cliff: 100, Duration: 1000}
+)
+
+func newTestVestingGrant(t *testing.T, revocable bool) *VestingGrant {
+	t.Helper()
+	grant, err := NewVestingGrant("grant_1", testGrantor, testEmployee, "USDT", 48000, testVestingPlan, revocable)
+	if err != nil {
+		t.Fatalf("NewVestingGrant() error = %v", err)
+	}
+	return grant
+}
+
+// TestVestingGrantSchedule 测试悬崖期内不归属，之后线性归属，到期后全部归属
+func TestVestingGrantSchedule(t *testing.T) {
+	grant := newTestVestingGrant(t, false)
+
+	cases := []struct {
+		now  uint64
+		want framework.Amount
+	}{
+		{500, 0},
+		{1099, 0},
+		{1100, 4800},
+		{1500, 24000},
+		{1999, 47952},
+		{2000, 48000},
+		{9000, 48000},
+	}
+	for _, tc := range cases {
+		if got := grant.VestedAmount(tc.now); got != tc.want {
+			t.Errorf("VestedAmount(%d) = %d, want %d", tc.now, got, tc.want)
+		}
+	}
+
+	if _, err := grant.Claim(testEmployee, 1099); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Claim() before cliff code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+	if _, err := grant.Claim(testGrantor, 1500); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Claim() by grantor code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+	payout, err := grant.Claim(testEmployee, 1500)
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_VESTED, To: testEmployee, TokenID: "USDT", Amount: 24000}
+	if payout != want {
+		t.Errorf("Claim() = %+v, want %+v", payout, want)
+	}
+	if got := grant.Claimable(2000); got != 24000 {
+		t.Errorf("Claimable(2000) after claim = %d, want 24000", got)
+	}
+}
+
+// TestVestingRevokeSplit 测试撤销时按撤销时刻划分：未归属部分退还授予方，已归属部分仍可由受益人领取
+func TestVestingRevokeSplit(t *testing.T) {
+	grant := newTestVestingGrant(t, true)
+	if _, err := grant.Claim(testEmployee, 1250); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	if _, err := grant.Revoke(testEmployee, 1500); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Revoke() by beneficiary code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+	refund, err := grant.Revoke(testGrantor, 1500)
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	want := EscrowPayout{Leg: ESCROW_LEG_UNVESTED, To: testGrantor, TokenID: "USDT", Amount: 24000}
+	if refund != want {
+		t.Errorf("Revoke() refund = %+v, want %+v", refund, want)
+	}
+	if grant.Status != VESTING_STATUS_REVOKED || grant.RevokedAt != 1500 {
+		t.Errorf("after Revoke() status = %s, revokedAt = %d", grant.Status, grant.RevokedAt)
+	}
+
+	// 归属冻结在撤销时刻：已归属 24000，其中 12000 已领取
+	if got := grant.VestedAmount(9000); got != 24000 {
+		t.Errorf("VestedAmount() after revoke = %d, want 24000", got)
+	}
+	payout, err := grant.Claim(testEmployee, 9000)
+	if err != nil {
+		t.Fatalf("Claim() after revoke error = %v", err)
+	}
+	if payout.Amount != 12000 || grant.Claimed+refund.Amount != grant.TotalAmount {
+		t.Errorf("Claim() after revoke = %d, claimed %d + refunded %d, want total %d", payout.Amount, grant.Claimed, refund.Amount, grant.TotalAmount)
+	}
+	if _, err := grant.Claim(testEmployee, 9000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Claim() after revoke code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+	if _, err := grant.Revoke(testGrantor, 1600); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Revoke() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestVestingRevokeBeforeCliffAndAfterEnd 测试悬崖期前撤销退还全部，全部归属后不能撤销
+func TestVestingRevokeBeforeCliffAndAfterEnd(t *testing.T) {
+	grant := newTestVestingGrant(t, true)
+	if _, err := grant.Revoke(testGrantor, 2000); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("Revoke() when fully vested code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+
+	refund, err := grant.Revoke(testGrantor, 1050)
+	if err != nil {
+		t.Fatalf("Revoke() before cliff error = %v", err)
+	}
+	if refund.Amount != 48000 || grant.Claimable(9000) != 0 {
+		t.Errorf("Revoke() before cliff refund = %d, claimable = %d, want 48000 and 0", refund.Amount, grant.Claimable(9000))
+	}
+}
+
+// TestVestingRevokeNonRevocable 测试不可撤销的授予拒绝撤销，继续按时间表归属
+func TestVestingRevokeNonRevocable(t *testing.T) {
+	grant := newTestVestingGrant(t, false)
+
+	if _, err := grant.Revoke(testGrantor, 1500); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("Revoke() non-revocable code = %d, want ERROR_PERMISSION_DENIED", errCode(err))
+	}
+	if grant.Status != VESTING_STATUS_ACTIVE || grant.VestedAmount(2000) != grant.TotalAmount {
+		t.Errorf("after rejected Revoke() status = %s, vested = %d", grant.Status, grant.VestedAmount(2000))
+	}
+}
+
+// TestVestingGrantValidation 测试创建释放计划的参数验证
+func TestVestingGrantValidation(t *testing.T) {
+	cases := []struct {
+		name        string
+		grantor     framework.Address
+		beneficiary framework.Address
+		amount      framework.Amount
+		schedule    VestingSchedule
+		vestingID   string
+	}{
+		{"zero grantor", framework.Address{}, testEmployee, 48000, testVestingPlan, "grant_1"},
+		{"self grant", testGrantor, testGrantor, 48000, testVestingPlan, "grant_1"},
+		{"zero amount", testGrantor, testEmployee, 0, testVestingPlan, "grant_1"},
+		{"empty id", testGrantor, testEmployee, 48000, testVestingPlan, ""},
+		{"zero duration", testGrantor, testEmployee, 48000, VestingSchedule{StartTime: 1000}, "grant_1"},
+		{"cliff after end", testGrantor, testEmployee, 48000, VestingSchedule{StartTime: 1000, Cliff: 2000, Duration: 1000}, "grant_1"},
+		{"end overflows", testGrantor, testEmployee, 48000, VestingSchedule{StartTime: ^uint64(0), Duration: 1}, "grant_1"},
+	}
+	for _, tc := range cases {
+		if _, err := NewVestingGrant(tc.vestingID, tc.grantor, tc.beneficiary, "USDT", tc.amount, tc.schedule, true); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: code = %d, want ERROR_INVALID_PARAMS", tc.name, errCode(err))
+		}
+	}
+}
+
+// TestVestingGrantCodec 测试释放计划记录编码往返
+func TestVestingGrantCodec(t *testing.T) {
+	grant := newTestVestingGrant(t, true)
+	if _, err := grant.Claim(testEmployee, 1250); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if _, err := grant.Revoke(testGrantor, 1500); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	decoded, err := decodeVestingGrant(encodeVestingGrant(grant))
+	if err != nil {
+		t.Fatalf("decodeVestingGrant() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, grant) {
+		t.Errorf("decodeVestingGrant() = %+v, want %+v", decoded, grant)
+	}
+	if _, err := decodeVestingGrant(encodeVestingGrant(grant)[:60]); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("decodeVestingGrant(truncated) code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestVestingGrantBatch 测试批量创建为每个受益人生成独立ID的释放计划并汇总总金额
+func TestVestingGrantBatch(t *testing.T) {
+	grants := []VestingBatchGrant{
+		{Beneficiary: testEmployee, Amount: 40000, Start: 1000, Duration: 1000, Cliff: 250, Revocable: true},
+		{Beneficiary: testBuyer, Amount: 20000, Start: 1000, Duration: 500},
+		{Beneficiary: testSeller, Amount: 6000, Start: 1500, Duration: 300},
+	}
+	batch, total, err := NewVestingGrantBatch(testGrantor, "TEAM", grants, 8)
+	if err != nil {
+		t.Fatalf("NewVestingGrantBatch() error = %v", err)
+	}
+	if total != 66000 || len(batch) != 3 {
+		t.Fatalf("NewVestingGrantBatch() total = %d, len = %d, want 66000 and 3", total, len(batch))
+	}
+	for i, id := range []string{"vesting_8", "vesting_9", "vesting_10"} {
+		g := batch[i]
+		if g.VestingID != id || g.Grantor != testGrantor || g.Beneficiary != grants[i].Beneficiary || g.TokenID != "TEAM" ||
+			g.TotalAmount != grants[i].Amount || g.Revocable != grants[i].Revocable || g.Status != VESTING_STATUS_ACTIVE {
+			t.Errorf("batch[%d] = %+v, want ID %s for %+v", i, g, id, grants[i])
+		}
+	}
+	if want := (VestingSchedule{StartTime: 1000, Cliff: 250, Duration: 1000}); batch[0].Schedule != want {
+		t.Errorf("batch[0].Schedule = %+v, want %+v", batch[0].Schedule, want)
+	}
+}
+
+// TestVestingGrantBatchVestsIndependently 测试同一批次的计划各自按时间表归属，领取与撤销互不影响
+func TestVestingGrantBatchVestsIndependently(t *testing.T) {
+	batch, _, err := NewVestingGrantBatch(testGrantor, "TEAM", []VestingBatchGrant{
+		{Beneficiary: testEmployee, Amount: 40000, Start: 1000, Duration: 1000, Cliff: 250, Revocable: true},
+		{Beneficiary: testBuyer, Amount: 20000, Start: 1000, Duration: 500},
+		{Beneficiary: testSeller, Amount: 6000, Start: 1500, Duration: 300},
+	}, 1)
+	if err != nil {
+		t.Fatalf("NewVestingGrantBatch() error = %v", err)
+	}
+	employee, buyer, seller := batch[0], batch[1], batch[2]
+
+	// 同一时刻各计划的归属不同
+	cases := []struct {
+		now  uint64
+		want [3]framework.Amount
+	}{
+		{1200, [3]framework.Amount{0, 8000, 0}},
+		{1500, [3]framework.Amount{20000, 20000, 0}},
+		{1650, [3]framework.Amount{26000, 20000, 3000}},
+	}
+	for _, tc := range cases {
+		for i, g := range batch {
+			if got := g.VestedAmount(tc.now); got != tc.want[i] {
+				t.Errorf("VestedAmount(%d) of %s = %d, want %d", tc.now, g.VestingID, got, tc.want[i])
+			}
+		}
+	}
+
+	// 撤销其中一个计划，其余计划继续归属
+	if _, err := employee.Revoke(testGrantor, 1500); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := buyer.Revoke(testGrantor, 1200); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("Revoke() non-revocable batch grant code = %d, want ERROR_PERMISSION_DENIED", errCode(err))
+	}
+	if payout, err := buyer.Claim(testBuyer, 2000); err != nil || payout.Amount != 20000 {
+		t.Errorf("buyer Claim() = %d, %v, want 20000", payout.Amount, err)
+	}
+	if _, err := buyer.Claim(testEmployee, 2000); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Claim() of another beneficiary's grant code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+	if employee.VestedAmount(2000) != 20000 || seller.VestedAmount(2000) != 6000 || seller.Claimed != 0 {
+		t.Errorf("after revoke/claim: employee vested %d, seller vested %d claimed %d", employee.VestedAmount(2000), seller.VestedAmount(2000), seller.Claimed)
+	}
+}
+
+// TestVestingGrantBatchValidation 测试任一项无效时整批拒绝
+func TestVestingGrantBatchValidation(t *testing.T) {
+	valid := VestingBatchGrant{Beneficiary: testEmployee, Amount: 1000, Start: 1000, Duration: 1000}
+	cases := []struct {
+		name   string
+		grants []VestingBatchGrant
+	}{
+		{"empty batch", nil},
+		{"zero beneficiary", []VestingBatchGrant{valid, {Amount: 1000, Start: 1000, Duration: 1000}}},
+		{"grantor as beneficiary", []VestingBatchGrant{valid, {Beneficiary: testGrantor, Amount: 1000, Start: 1000, Duration: 1000}}},
+		{"zero duration", []VestingBatchGrant{valid, {Beneficiary: testBuyer, Amount: 1000, Start: 1000}}},
+		{"total overflows", []VestingBatchGrant{valid, {Beneficiary: testBuyer, Amount: ^framework.Amount(0), Start: 1000, Duration: 1000}}},
+	}
+	for _, tc := range cases {
+		if _, _, err := NewVestingGrantBatch(testGrantor, "TEAM", tc.grants, 1); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: code = %d, want ERROR_INVALID_PARAMS", tc.name, errCode(err))
+		}
+	}
+}
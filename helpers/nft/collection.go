@@ -0,0 +1,28 @@
+package nft
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// NFT_TOKEN_NAMESPACE 集合内NFT代币ID的命名空间
+const NFT_TOKEN_NAMESPACE = "NFT"
+
+// CollectionTokenID 派生集合内NFT的代币ID
+//
+// 🎯 **用途**：按 发行合约 + 集合 + 编号 派生代币ID，不同合约、不同集合的同一编号不会碰撞
+//
+// **参数**：
+//   - issuer: 发行合约地址
+//   - collection: 集合标识
+//   - itemID: 集合内编号
+//
+// **返回**：NFT_{32位十六进制}，见 token.DeriveTokenID
+//
+// **示例**：
+//
+//	tokenID := nft.CollectionTokenID(framework.GetContractAddress(), "genesis", "42")
+//	err := nft.Mint(to, tokenID, metadata)
+func CollectionTokenID(issuer framework.Address, collection string, itemID string) framework.TokenID {
+	return token.DeriveTokenID(NFT_TOKEN_NAMESPACE, string(issuer[:]), collection, itemID)
+}
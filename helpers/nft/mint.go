@@ -13,7 +13,7 @@ import (
 //
 // **参数**：
 //   - to: 接收者地址
-//   - tokenID: NFT代币ID（必须唯一）
+//   - tokenID: NFT代币ID（必须唯一），建议使用 CollectionTokenID 派生
 //   - metadata: NFT元数据（可选）
 //
 // **返回**：
@@ -28,7 +28,7 @@ import (
 //	func MintNFT() uint32 {
 //	    params := framework.GetContractParams()
 //	    toStr := params.ParseJSON("to")
-//	    tokenIDStr := params.ParseJSON("token_id")
+//	    itemID := params.ParseJSON("item_id")
 //	    
 //	    to, err := framework.ParseAddressBase58(toStr)
 //	    if err != nil {
@@ -37,7 +37,7 @@ import (
 //	    
 //	    err = nft.Mint(
 //	        to,
-//	        framework.TokenID(tokenIDStr),
+//	        nft.CollectionTokenID(framework.GetContractAddress(), "genesis", itemID),
 //	        []byte(params.ParseJSON("metadata")),
 //	    )
 //	    if err != nil {
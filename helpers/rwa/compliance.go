@@ -0,0 +1,189 @@
+package rwa
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 监管冻结与没收 ====================
+//
+// 受监管的 RWA 代币须能按监管要求冻结受制裁持有人、或将其代币强制转移到指定地址。
+// 监管方（RWA_AUTHORITY_ROLE 角色的持有者）可以：
+//   - FreezeHolder / UnfreezeHolder：冻结或解冻持有人，冻结期间 Transfer 拒绝其发送或接收代币
+//   - SeizeAsset：不经持有人签名，将其代币强制转移到指定地址（冻结的持有人同样可被没收）
+//
+// 监管方是框架登记的可轮换角色（见 framework.RegisterRole），合约在 Initialize 中设置初始持有者：
+//
+//	framework.InitRole(rwa.RWA_AUTHORITY_ROLE, regulator)
+//
+// 自行实现转账的合约在转账前调用 RequireTransferable 即可获得同样的冻结检查。
+
+// RWA_AUTHORITY_ROLE 可冻结持有人与没收代币的监管方角色
+const RWA_AUTHORITY_ROLE = "rwa_authority"
+
+// holderFrozenStatePrefix 冻结标记状态ID前缀，完整格式：rwa_frozen_{address}，值为 "frozen" 或 "active"
+const holderFrozenStatePrefix = "rwa_frozen_"
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: RWA_AUTHORITY_ROLE})
+}
+
+// FreezeHolder 监管方冻结持有人
+//
+// 🎯 **用途**：受制裁或接受调查的持有人在解冻前不能发送或接收代币
+//
+// **参数**：
+//   - authority: 监管方地址，须为当前调用者且持有 RWA_AUTHORITY_ROLE
+//   - holder: 被冻结的持有人
+//   - reason: 冻结原因（如监管文书编号），记录在 HolderFrozen 事件中
+//
+// **返回**：
+//   - error: 监管方校验失败（ERROR_UNAUTHORIZED）、持有人为空（ERROR_INVALID_PARAMS）、
+//     已处于冻结状态（ERROR_INVALID_STATE）
+//
+// **示例**：
+//
+//	if err := rwa.FreezeHolder(framework.GetCaller(), holder, "OFAC-2025-0042"); err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func FreezeHolder(authority, holder framework.Address, reason string) error {
+	return setHolderFrozen(authority, holder, true, reason)
+}
+
+// UnfreezeHolder 监管方解冻持有人
+//
+// **返回**：
+//   - error: 监管方校验失败（ERROR_UNAUTHORIZED）、持有人为空（ERROR_INVALID_PARAMS）、
+//     未处于冻结状态（ERROR_INVALID_STATE）
+//
+// **注意**：发出 HolderUnfrozen 事件
+func UnfreezeHolder(authority, holder framework.Address) error {
+	return setHolderFrozen(authority, holder, false, "")
+}
+
+// IsHolderFrozen 持有人当前是否被冻结
+func IsHolderFrozen(holder framework.Address) bool {
+	data, version, err := framework.GetStateFromChain([]byte(holderFrozenStateID(holder)))
+	return err == nil && version > 0 && string(data) == "frozen"
+}
+
+// RequireTransferable 发送方或接收方被冻结时返回 ERROR_PERMISSION_DENIED
+func RequireTransferable(from, to framework.Address) error {
+	if IsHolderFrozen(from) {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "sender is frozen")
+	}
+	if IsHolderFrozen(to) {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "recipient is frozen")
+	}
+	return nil
+}
+
+// SeizeAsset 监管方没收持有人的代币
+//
+// 🎯 **用途**：按监管要求将持有人的代币强制转移到指定地址（如监管托管账户），无需持有人签名
+//
+// **参数**：
+//   - authority: 监管方地址，须为当前调用者且持有 RWA_AUTHORITY_ROLE
+//   - from: 被没收的持有人（可以处于冻结状态）
+//   - to: 接收没收代币的地址
+//   - tokenID: 代币ID
+//   - amount: 没收数量
+//   - reason: 没收原因（如法院命令编号），记录在 Seizure 事件中
+//
+// **返回**：
+//   - error: 监管方校验失败（ERROR_UNAUTHORIZED）、地址为空或相同、数量为 0、原因为空（ERROR_INVALID_PARAMS）、
+//     持有人余额不足（ERROR_INSUFFICIENT_BALANCE）、交易构建失败（透传错误码）
+//
+// **示例**：
+//
+//	err := rwa.SeizeAsset(framework.GetCaller(), sanctioned, custody, "RWA_RE_001", 1000, "court order 2025-17")
+//
+// **注意**：
+//   - 强制转移不经过 token.Transfer，不收取转账手续费
+//   - 发出 Seizure 事件：authority、from、to、token_id、amount、reason
+func SeizeAsset(authority, from, to framework.Address, tokenID framework.TokenID, amount framework.Amount, reason string) error {
+	// 1. 校验监管方与参数
+	if err := requireAuthority(authority); err != nil {
+		return err
+	}
+	if from.IsZero() || to.IsZero() || from.Equals(to) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "seizure requires distinct from and to addresses")
+	}
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	if reason == "" {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "seizure reason cannot be empty")
+	}
+
+	// 2. 检查持有人余额
+	if framework.QueryUTXOBalance(from, tokenID) < amount {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to seize")
+	}
+
+	// 3. 强制转移
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(from, to, tokenID, amount).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "seizure transfer failed")
+	}
+
+	// 4. 发出没收事件
+	event := framework.NewEvent("Seizure")
+	event.AddAddressField("authority", authority)
+	event.AddAddressField("from", from)
+	event.AddAddressField("to", to)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("amount", uint64(amount))
+	event.AddStringField("reason", reason)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// requireAuthority 校验 authority 为当前调用者且持有监管方角色
+func requireAuthority(authority framework.Address) error {
+	if !authority.Equals(framework.GetCaller()) || !framework.HasRole(RWA_AUTHORITY_ROLE, authority) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "caller is not the RWA authority")
+	}
+	return nil
+}
+
+// setHolderFrozen 校验监管方并写入冻结标记
+func setHolderFrozen(authority, holder framework.Address, frozen bool, reason string) error {
+	if err := requireAuthority(authority); err != nil {
+		return err
+	}
+	if holder.IsZero() {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "holder cannot be empty")
+	}
+	if IsHolderFrozen(holder) == frozen {
+		if frozen {
+			return framework.NewContractError(framework.ERROR_INVALID_STATE, "holder is already frozen")
+		}
+		return framework.NewContractError(framework.ERROR_INVALID_STATE, "holder is not frozen")
+	}
+
+	flag, eventName := "active", "HolderUnfrozen"
+	if frozen {
+		flag, eventName = "frozen", "HolderFrozen"
+	}
+	stateID := []byte(holderFrozenStateID(holder))
+	_, version, _ := framework.GetStateFromChain(stateID)
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, []byte(flag), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save freeze flag")
+	}
+
+	event := framework.NewEvent(eventName)
+	event.AddAddressField("authority", authority)
+	event.AddAddressField("holder", holder)
+	if frozen {
+		event.AddStringField("reason", reason)
+	}
+	framework.EmitEvent(event)
+	return nil
+}
+
+// holderFrozenStateID 冻结标记状态ID
+func holderFrozenStateID(holder framework.Address) string {
+	return holderFrozenStatePrefix + holder.ToString()
+}
@@ -0,0 +1,34 @@
+//go:build tinygo || (js && wasm)
+
+package rwa
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// Transfer 带冻结检查的 RWA 代币转账
+//
+// 🎯 **用途**：受监管代币的转账入口，发送方或接收方被 FreezeHolder 冻结时拒绝
+//
+// **参数**：同 token.Transfer
+//
+// **返回**：
+//   - error: 发送方或接收方被冻结（ERROR_PERMISSION_DENIED），其他错误同 token.Transfer
+//
+// **示例**：
+//
+//	//export TransferAsset
+//	func TransferAsset() uint32 {
+//	    err := rwa.Transfer(framework.GetCaller(), to, tokenID, amount)
+//	    if err != nil {
+//	        return err.(*framework.ContractError).Code
+//	    }
+//	    return framework.SUCCESS
+//	}
+func Transfer(from, to framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := RequireTransferable(from, to); err != nil {
+		return err
+	}
+	return token.Transfer(from, to, tokenID, amount)
+}
@@ -0,0 +1,130 @@
+//go:build !tinygo && !(js && wasm)
+
+package rwa
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+const testRWAToken framework.TokenID = "RWA_RE_001"
+
+// newComplianceHost 创建以 Operator 为监管方、Bob 持有 1000 代币的宿主
+func newComplianceHost(t *testing.T) *fwtesting.Host {
+	t.Helper()
+	host := fwtesting.NewHost(t).SetCaller(fixtures.Operator())
+	if res := host.Run(func() error { return framework.InitRole(RWA_AUTHORITY_ROLE, fixtures.Operator()) }); res.Code != framework.SUCCESS {
+		t.Fatalf("InitRole() code = %d", res.Code)
+	}
+	host.SetBalance(fixtures.Bob(), testRWAToken, 1000)
+	return host
+}
+
+// TestComplianceAuthorityOnly 测试只有监管方本人可以冻结、解冻与没收，失败时不写入状态、不转移代币
+func TestComplianceAuthorityOnly(t *testing.T) {
+	host := newComplianceHost(t)
+	bob, custody := fixtures.Bob(), fixtures.Carol()
+
+	ops := map[string]func(authority framework.Address) error{
+		"FreezeHolder":   func(a framework.Address) error { return FreezeHolder(a, bob, "sanctions") },
+		"UnfreezeHolder": func(a framework.Address) error { return UnfreezeHolder(a, bob) },
+		"SeizeAsset": func(a framework.Address) error {
+			return SeizeAsset(a, bob, custody, testRWAToken, 100, "court order")
+		},
+	}
+	for name, op := range ops {
+		// 非监管方调用
+		host.SetCaller(fixtures.Alice())
+		if res := host.Run(func() error { return op(fixtures.Alice()) }); res.Code != framework.ERROR_UNAUTHORIZED || len(res.Writes) != 0 || len(res.Transfers) != 0 {
+			t.Errorf("%s by non-authority: code %d, %d writes, %d transfers", name, res.Code, len(res.Writes), len(res.Transfers))
+		}
+		// 冒用监管方地址
+		if res := host.Run(func() error { return op(fixtures.Operator()) }); res.Code != framework.ERROR_UNAUTHORIZED {
+			t.Errorf("%s with borrowed authority: code %d, want ERROR_UNAUTHORIZED", name, res.Code)
+		}
+	}
+	if IsHolderFrozen(bob) || host.Balance(bob, testRWAToken) != 1000 {
+		t.Fatal("unauthorized calls changed freeze state or balance")
+	}
+
+	host.SetCaller(fixtures.Operator())
+	res := host.Run(func() error {
+		return SeizeAsset(fixtures.Operator(), bob, custody, testRWAToken, 400, "court order 2025-17")
+	})
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("SeizeAsset() code = %d", res.Code)
+	}
+	if host.Balance(bob, testRWAToken) != 600 || host.Balance(custody, testRWAToken) != 400 {
+		t.Errorf("balances after seizure = %d / %d, want 600 / 400", host.Balance(bob, testRWAToken), host.Balance(custody, testRWAToken))
+	}
+	if len(res.Events) != 1 || res.Events[0].Name != "Seizure" || res.Events[0].Data["reason"] != "court order 2025-17" || res.Events[0].Data["amount"] != uint64(400) {
+		t.Errorf("SeizeAsset() events = %+v, want one Seizure with reason", res.Events)
+	}
+
+	rejects := []struct {
+		name     string
+		op       func() error
+		wantCode uint32
+	}{
+		{"over balance", func() error { return SeizeAsset(fixtures.Operator(), bob, custody, testRWAToken, 601, "order") }, framework.ERROR_INSUFFICIENT_BALANCE},
+		{"zero amount", func() error { return SeizeAsset(fixtures.Operator(), bob, custody, testRWAToken, 0, "order") }, framework.ERROR_INVALID_PARAMS},
+		{"same address", func() error { return SeizeAsset(fixtures.Operator(), bob, bob, testRWAToken, 1, "order") }, framework.ERROR_INVALID_PARAMS},
+		{"no reason", func() error { return SeizeAsset(fixtures.Operator(), bob, custody, testRWAToken, 1, "") }, framework.ERROR_INVALID_PARAMS},
+		{"unfreeze active holder", func() error { return UnfreezeHolder(fixtures.Operator(), bob) }, framework.ERROR_INVALID_STATE},
+	}
+	for _, tt := range rejects {
+		if res := host.Run(tt.op); res.Code != tt.wantCode || len(res.Transfers) != 0 {
+			t.Errorf("%s: code %d, %d transfers, want code %d", tt.name, res.Code, len(res.Transfers), tt.wantCode)
+		}
+	}
+}
+
+// TestFrozenHolderCannotTransfer 测试冻结的持有人不能发送或接收代币，但仍可被没收；解冻后恢复
+func TestFrozenHolderCannotTransfer(t *testing.T) {
+	host := newComplianceHost(t)
+	alice, bob, custody := fixtures.Alice(), fixtures.Bob(), fixtures.Carol()
+
+	if err := RequireTransferable(bob, alice); err != nil {
+		t.Fatalf("RequireTransferable() before freeze = %v", err)
+	}
+	res := host.Run(func() error { return FreezeHolder(fixtures.Operator(), bob, "OFAC-2025-0042") })
+	if res.Code != framework.SUCCESS || len(res.Events) != 1 || res.Events[0].Name != "HolderFrozen" || res.Events[0].Data["reason"] != "OFAC-2025-0042" {
+		t.Fatalf("FreezeHolder() code = %d, events %+v", res.Code, res.Events)
+	}
+	if res := host.Run(func() error { return FreezeHolder(fixtures.Operator(), bob, "again") }); res.Code != framework.ERROR_INVALID_STATE {
+		t.Errorf("FreezeHolder() twice code = %d, want ERROR_INVALID_STATE", res.Code)
+	}
+
+	for _, pair := range [][2]framework.Address{{bob, alice}, {alice, bob}} {
+		if err := RequireTransferable(pair[0], pair[1]); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+			t.Errorf("RequireTransferable(%s → %s) = %v, want ERROR_PERMISSION_DENIED", fixtures.Name(pair[0]), fixtures.Name(pair[1]), err)
+		}
+	}
+	if err := RequireTransferable(alice, custody); err != nil {
+		t.Errorf("RequireTransferable() between unfrozen holders = %v", err)
+	}
+
+	if res := host.Run(func() error { return SeizeAsset(fixtures.Operator(), bob, custody, testRWAToken, 1000, "forfeiture") }); res.Code != framework.SUCCESS {
+		t.Errorf("SeizeAsset() from frozen holder code = %d", res.Code)
+	}
+
+	if res := host.Run(func() error { return UnfreezeHolder(fixtures.Operator(), bob) }); res.Code != framework.SUCCESS || res.Events[0].Name != "HolderUnfrozen" {
+		t.Fatalf("UnfreezeHolder() code = %d, events %+v", res.Code, res.Events)
+	}
+	if IsHolderFrozen(bob) || RequireTransferable(bob, alice) != nil {
+		t.Error("holder still frozen after UnfreezeHolder")
+	}
+}
+
+func errCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
@@ -0,0 +1,166 @@
+package rwa
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/external"
+)
+
+// ==================== KYC 证明 ====================
+//
+// RWA 转让需要确认接收方已通过 KYC，但合约内维护中心化白名单既需要专人更新，也无法说明依据。
+// VerifyKYCAttestation 通过 ISPC 受控外部交互获取 KYC 服务商签名的证明（签名与响应哈希由
+// ISPC 运行时校验），核对证明的主体后把结论缓存在状态 kyc_{subject} 中，之后的转让直接读取缓存：
+//
+//	if !rwa.IsKYCVerified(to) {
+//	    return framework.ERROR_PERMISSION_DENIED
+//	}
+//
+// 服务商返回的证明格式：
+//
+//	{
+//	  "subject": "<Base58 地址>",  // 证明所针对的地址，须与 subject 一致
+//	  "status": "passed",          // "passed" 或 "failed"
+//	  "expires_at": 1767225600     // 证明失效时间（Unix秒，可选，0 或缺省表示长期有效）
+//	}
+
+const (
+	// KYC_STATUS_PASSED 证明结论：通过
+	KYC_STATUS_PASSED = "passed"
+	// KYC_STATUS_FAILED 证明结论：未通过
+	KYC_STATUS_FAILED = "failed"
+)
+
+// KYCRecord 缓存的 KYC 验证结果
+type KYCRecord struct {
+	// Passed 证明结论是否为通过
+	Passed bool
+	// VerifiedAt 验证时的区块时间
+	VerifiedAt uint64
+	// ExpiresAt 证明失效时间，0 表示长期有效
+	ExpiresAt uint64
+}
+
+// VerifyKYCAttestation 获取并验证 KYC 服务商签名的证明
+//
+// 🎯 **用途**：在无需中心化白名单的前提下，为地址在链上留存可验证的 KYC 结论
+//
+// **参数**：
+//   - subject: 被验证的地址
+//   - attestationURL: KYC 服务商的证明端点（如 "https://kyc.example.com/api/attestation"）
+//   - evidence: 服务商签名等佐证（APISignature、ResponseHash）
+//
+// **返回**：
+//   - passed: 证明结论为通过且未过期
+//   - error: 参数为空（ERROR_INVALID_PARAMS）、ISPC 查询失败（透传）、
+//     证明的主体与 subject 不一致或结论无法识别（ERROR_INVALID_PARAMS）、写入缓存失败（ERROR_EXECUTION_FAILED）
+//
+// **示例**：
+//
+//	passed, err := rwa.VerifyKYCAttestation(
+//	    investor,
+//	    "https://kyc.example.com/api/attestation",
+//	    &framework.Evidence{
+//	        APISignature: providerSignature, // 服务商对证明的签名
+//	        ResponseHash: attestationHash,   // 证明内容哈希
+//	    },
+//	)
+//
+// **注意**：
+//   - 结论为未通过或证明已过期时返回 false 且不报错，同样写入缓存，覆盖之前的结论
+//   - 证明中的 expires_at 随缓存保存，过期后 IsKYCVerified 返回 false，需重新验证
+func VerifyKYCAttestation(subject framework.Address, attestationURL string, evidence *framework.Evidence) (bool, error) {
+	// 1. 参数验证
+	if subject.IsZero() {
+		return false, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "subject cannot be empty")
+	}
+	if attestationURL == "" {
+		return false, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "attestationURL cannot be empty")
+	}
+	if evidence == nil {
+		return false, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "evidence cannot be nil")
+	}
+
+	// 2. 通过ISPC受控机制获取证明
+	subjectStr := subject.ToString()
+	data, err := external.ValidateAndQuery(
+		"api_response",
+		attestationURL,
+		map[string]interface{}{"subject": subjectStr},
+		evidence,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// 3. 核对证明主体与结论
+	attestation := framework.NewContractParams(data)
+	if attestation.ParseJSON("subject") != subjectStr {
+		return false, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "attestation subject does not match")
+	}
+	status := attestation.ParseJSON("status")
+	if status != KYC_STATUS_PASSED && status != KYC_STATUS_FAILED {
+		return false, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "unknown attestation status: "+status)
+	}
+	record := KYCRecord{
+		Passed:     status == KYC_STATUS_PASSED,
+		VerifiedAt: framework.GetTimestamp(),
+		ExpiresAt:  attestation.ParseJSONInt("expires_at"),
+	}
+
+	// 4. 缓存结论
+	if err := saveKYCRecord(subject, record); err != nil {
+		return false, err
+	}
+	return record.valid(record.VerifiedAt), nil
+}
+
+// GetKYCRecord 读取地址缓存的 KYC 验证结果
+//
+// **返回**：缓存的结果；从未验证过时 ok 为 false
+func GetKYCRecord(subject framework.Address) (record KYCRecord, ok bool) {
+	data, version, err := framework.GetStateFromChain([]byte(kycStateID(subject)))
+	if err != nil || version == 0 || len(data) == 0 {
+		return KYCRecord{}, false
+	}
+	params := framework.NewContractParams(data)
+	return KYCRecord{
+		Passed:     params.ParseJSON("status") == KYC_STATUS_PASSED,
+		VerifiedAt: params.ParseJSONInt("verified_at"),
+		ExpiresAt:  params.ParseJSONInt("expires_at"),
+	}, true
+}
+
+// IsKYCVerified 地址是否持有通过且未过期的 KYC 证明
+//
+// 🎯 **用途**：RWA 转让前检查接收方，只读取缓存，不发起外部调用
+func IsKYCVerified(subject framework.Address) bool {
+	record, ok := GetKYCRecord(subject)
+	return ok && record.valid(framework.GetTimestamp())
+}
+
+// valid 结论为通过且在 now 时未过期
+func (r KYCRecord) valid(now uint64) bool {
+	return r.Passed && (r.ExpiresAt == 0 || now < r.ExpiresAt)
+}
+
+// kycStateID KYC 缓存的状态ID
+func kycStateID(subject framework.Address) string {
+	return "kyc_" + subject.ToString()
+}
+
+// saveKYCRecord 写入 KYC 缓存，值为 {"status":...,"verified_at":...,"expires_at":...}
+func saveKYCRecord(subject framework.Address, record KYCRecord) error {
+	status := KYC_STATUS_FAILED
+	if record.Passed {
+		status = KYC_STATUS_PASSED
+	}
+	value := `{"status":"` + status + `","verified_at":` + framework.Uint64ToString(record.VerifiedAt) +
+		`,"expires_at":` + framework.Uint64ToString(record.ExpiresAt) + `}`
+
+	stateID := []byte(kycStateID(subject))
+	_, version, _ := framework.GetStateFromChain(stateID)
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, []byte(value), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save KYC record")
+	}
+	return nil
+}
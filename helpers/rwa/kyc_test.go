@@ -0,0 +1,107 @@
+//go:build !tinygo && !(js && wasm)
+
+package rwa
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+const testKYCProvider = "https://kyc.example.com/api/attestation"
+
+func testKYCEvidence() *framework.Evidence {
+	return &framework.Evidence{APISignature: []byte("provider_sig"), ResponseHash: []byte("attestation_hash")}
+}
+
+// verifyKYC 在一次调用中执行 VerifyKYCAttestation，返回结论与调用结果
+func verifyKYC(host *fwtesting.Host, subject framework.Address, evidence *framework.Evidence) (bool, framework.MockCallResult) {
+	var passed bool
+	res := host.Run(func() error {
+		var err error
+		passed, err = VerifyKYCAttestation(subject, testKYCProvider, evidence)
+		return err
+	})
+	return passed, res
+}
+
+// TestVerifyKYCAttestation 测试服务商返回通过、未通过与过期证明时的结论与缓存
+func TestVerifyKYCAttestation(t *testing.T) {
+	host := fwtesting.NewHost(t)
+	subject := fixtures.Bob()
+
+	tests := []struct {
+		name        string
+		attestation string
+		wantPassed  bool
+	}{
+		{"passed", `{"subject":"` + subject.ToString() + `","status":"passed"}`, true},
+		{"failed", `{"subject":"` + subject.ToString() + `","status":"failed"}`, false},
+		{"passed until later", `{"subject":"` + subject.ToString() + `","status":"passed","expires_at":` + framework.Uint64ToString(fixtures.Epoch+3600) + `}`, true},
+		{"expired", `{"subject":"` + subject.ToString() + `","status":"passed","expires_at":` + framework.Uint64ToString(fixtures.Epoch) + `}`, false},
+	}
+	for _, tt := range tests {
+		host.SetExternalResponse(testKYCProvider, []byte(tt.attestation))
+		passed, res := verifyKYC(host, subject, testKYCEvidence())
+		if res.Code != framework.SUCCESS || passed != tt.wantPassed {
+			t.Errorf("%s: VerifyKYCAttestation() = %v, code %d, want %v", tt.name, passed, res.Code, tt.wantPassed)
+			continue
+		}
+		if len(res.Writes) != 1 || res.Writes[0].StateID != "kyc_"+subject.ToString() {
+			t.Errorf("%s: writes = %+v, want one kyc_{subject} record", tt.name, res.Writes)
+		}
+		if got := IsKYCVerified(subject); got != tt.wantPassed {
+			t.Errorf("%s: IsKYCVerified() = %v, want %v", tt.name, got, tt.wantPassed)
+		}
+	}
+
+	// 缓存的证明到期后不再有效
+	host.SetExternalResponse(testKYCProvider, []byte(tests[2].attestation))
+	verifyKYC(host, subject, testKYCEvidence())
+	record, ok := GetKYCRecord(subject)
+	if !ok || !record.Passed || record.VerifiedAt != fixtures.Epoch || record.ExpiresAt != fixtures.Epoch+3600 {
+		t.Errorf("GetKYCRecord() = %+v, %v", record, ok)
+	}
+	host.SetTimestamp(fixtures.Epoch + 3600)
+	if IsKYCVerified(subject) {
+		t.Error("IsKYCVerified() = true after expiry")
+	}
+	if _, ok := GetKYCRecord(fixtures.Alice()); ok || IsKYCVerified(fixtures.Alice()) {
+		t.Error("unverified address should have no KYC record")
+	}
+}
+
+// TestVerifyKYCAttestationRejects 测试主体不一致、结论无法识别、缺少佐证与参数为空时拒绝且不写入缓存
+func TestVerifyKYCAttestationRejects(t *testing.T) {
+	host := fwtesting.NewHost(t)
+	subject := fixtures.Bob()
+
+	tests := []struct {
+		name        string
+		subject     framework.Address
+		attestation string
+		evidence    *framework.Evidence
+		wantCode    uint32
+	}{
+		{"other subject", subject, `{"subject":"` + fixtures.Carol().ToString() + `","status":"passed"}`, testKYCEvidence(), framework.ERROR_INVALID_PARAMS},
+		{"unknown status", subject, `{"subject":"` + subject.ToString() + `","status":"pending"}`, testKYCEvidence(), framework.ERROR_INVALID_PARAMS},
+		{"unsigned", subject, `{"subject":"` + subject.ToString() + `","status":"passed"}`, &framework.Evidence{ResponseHash: []byte("attestation_hash")}, framework.ERROR_PERMISSION_DENIED},
+		{"nil evidence", subject, `{"subject":"` + subject.ToString() + `","status":"passed"}`, nil, framework.ERROR_INVALID_PARAMS},
+		{"zero subject", framework.Address{}, `{"subject":"","status":"passed"}`, testKYCEvidence(), framework.ERROR_INVALID_PARAMS},
+	}
+	for _, tt := range tests {
+		host.SetExternalResponse(testKYCProvider, []byte(tt.attestation))
+		passed, res := verifyKYC(host, tt.subject, tt.evidence)
+		if res.Code != tt.wantCode || passed {
+			t.Errorf("%s: VerifyKYCAttestation() = %v, code %d, want code %d", tt.name, passed, res.Code, tt.wantCode)
+		}
+		if len(res.Writes) != 0 {
+			t.Errorf("%s: staged %d writes", tt.name, len(res.Writes))
+		}
+	}
+	if _, ok := GetKYCRecord(subject); ok {
+		t.Error("rejected attestations should not be cached")
+	}
+}
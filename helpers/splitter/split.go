@@ -0,0 +1,128 @@
+package splitter
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// MAX_RECIPIENTS 单个分账配置的收款方数量上限
+const MAX_RECIPIENTS = 64
+
+// Split 分账配置：收款方及其份额
+//
+// 每个收款方应得 = 合约累计收到的金额 * 份额 / 总份额。
+type Split struct {
+	Recipients []framework.Address
+	Shares     []uint64
+}
+
+// NewSplit 校验并创建分账配置
+//
+// **返回**：
+//   - error: 收款方与份额数量不一致、为空或超过 MAX_RECIPIENTS、含零地址/重复地址/零份额、
+//     总份额溢出时返回 ERROR_INVALID_PARAMS
+func NewSplit(recipients []framework.Address, shares []uint64) (*Split, error) {
+	if len(recipients) == 0 || len(recipients) != len(shares) || len(recipients) > MAX_RECIPIENTS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "recipients and shares must be non-empty and of equal length")
+	}
+	seen := make(map[framework.Address]bool, len(recipients))
+	var total uint64
+	for i, recipient := range recipients {
+		if recipient == (framework.Address{}) || seen[recipient] {
+			return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "recipient is zero or duplicated")
+		}
+		if shares[i] == 0 {
+			return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "shares must be positive")
+		}
+		var carry uint64
+		if total, carry = bits.Add64(total, shares[i], 0); carry != 0 {
+			return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "total shares overflow")
+		}
+		seen[recipient] = true
+	}
+	return &Split{
+		Recipients: append([]framework.Address(nil), recipients...),
+		Shares:     append([]uint64(nil), shares...),
+	}, nil
+}
+
+// TotalShares 返回总份额
+func (s *Split) TotalShares() uint64 {
+	var total uint64
+	for _, share := range s.Shares {
+		total += share
+	}
+	return total
+}
+
+// SharesOf 返回收款方的份额，非收款方返回 0
+func (s *Split) SharesOf(recipient framework.Address) uint64 {
+	for i, r := range s.Recipients {
+		if r == recipient {
+			return s.Shares[i]
+		}
+	}
+	return 0
+}
+
+// PendingPayment 计算收款方当前可领取的金额
+//
+// 采用累计记账：应得 = floor(totalReceived * shares / totalShares)，可领取 = 应得 - 已领取。
+// totalReceived 为合约当前余额加上已发放总额。向下取整产生的尾差留在合约中，
+// 随后续入账累计，到达整数单位后按同一公式发放，结果与发放顺序、发放次数无关。
+func PendingPayment(totalReceived, shares, totalShares, released uint64) uint64 {
+	if totalShares == 0 {
+		return 0
+	}
+	// 128位中间结果，避免大额累计值乘法溢出；shares <= totalShares 保证商不超过 totalReceived
+	hi, lo := bits.Mul64(totalReceived, shares)
+	if hi >= totalShares {
+		return 0
+	}
+	entitled, _ := bits.Div64(hi, lo, totalShares)
+	if entitled <= released {
+		return 0
+	}
+	return entitled - released
+}
+
+// EncodeSplit 编码分账配置
+//
+// 编码格式：count(2) + [address(20) + shares(8)] * count
+func EncodeSplit(s *Split) []byte {
+	buf := binary.BigEndian.AppendUint16(nil, uint16(len(s.Recipients)))
+	for i, recipient := range s.Recipients {
+		buf = append(buf, recipient[:]...)
+		buf = binary.BigEndian.AppendUint64(buf, s.Shares[i])
+	}
+	return buf
+}
+
+// DecodeSplit 解码分账配置，并按 NewSplit 的规则校验
+//
+// 状态读取可能裁剪末尾的 0x00，不足的尾部按 0x00 补齐。
+func DecodeSplit(data []byte) (*Split, error) {
+	if len(data) < 2 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "split data too short")
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	if count == 0 || count > MAX_RECIPIENTS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid recipient count")
+	}
+	buf := make([]byte, 2+count*28)
+	if len(data) > len(buf) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "split data too long")
+	}
+	copy(buf, data)
+
+	recipients := make([]framework.Address, count)
+	shares := make([]uint64, count)
+	for i := 0; i < count; i++ {
+		entry := buf[2+i*28:]
+		copy(recipients[i][:], entry[:20])
+		shares[i] = binary.BigEndian.Uint64(entry[20:28])
+	}
+	return NewSplit(recipients, shares)
+}
@@ -0,0 +1,151 @@
+package splitter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+func newTestSplit(t *testing.T) *Split {
+	t.Helper()
+	split, err := NewSplit(
+		[]framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()},
+		[]uint64{50, 30, 20},
+	)
+	if err != nil {
+		t.Fatalf("NewSplit() error = %v", err)
+	}
+	return split
+}
+
+// release 模拟 Release：按当前余额发放，返回各收款方本次所得
+func release(split *Split, balance *uint64, released []uint64, totalReleased *uint64) []uint64 {
+	totalReceived := *balance + *totalReleased
+	payments := make([]uint64, len(split.Recipients))
+	for i := range split.Recipients {
+		payments[i] = PendingPayment(totalReceived, split.Shares[i], split.TotalShares(), released[i])
+		released[i] += payments[i]
+		*balance -= payments[i]
+		*totalReleased += payments[i]
+	}
+	return payments
+}
+
+// TestPendingPaymentDust 测试尾差留在合约中，累计入账后按份额补发
+func TestPendingPaymentDust(t *testing.T) {
+	split := newTestSplit(t)
+	released := make([]uint64, 3)
+	var balance, totalReleased uint64
+
+	// 第一次入账 101：按 50/30/20 分得 50/30/20，尾差 1 留在合约
+	balance = 101
+	if got, want := release(split, &balance, released, &totalReleased), []uint64{50, 30, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first release = %v, want %v", got, want)
+	}
+	if balance != 1 {
+		t.Errorf("dust after first release = %d, want 1", balance)
+	}
+
+	// 再次发放不会重复支付尾差
+	if got := release(split, &balance, released, &totalReleased); !reflect.DeepEqual(got, []uint64{0, 0, 0}) {
+		t.Errorf("repeated release = %v, want no payments", got)
+	}
+
+	// 第二次入账 99：累计 200，各方应得 100/60/40
+	balance += 99
+	if got, want := release(split, &balance, released, &totalReleased), []uint64{50, 30, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second release = %v, want %v", got, want)
+	}
+	if balance != 0 || !reflect.DeepEqual(released, []uint64{100, 60, 40}) {
+		t.Errorf("after second release balance = %d, released = %v", balance, released)
+	}
+}
+
+// TestPendingPaymentOrderIndependent 测试分多次发放与一次发放结果一致
+func TestPendingPaymentOrderIndependent(t *testing.T) {
+	split := newTestSplit(t)
+	deposits := []uint64{7, 13, 1, 999, 3}
+
+	released := make([]uint64, 3)
+	var balance, totalReleased uint64
+	for _, deposit := range deposits {
+		balance += deposit
+		release(split, &balance, released, &totalReleased)
+	}
+
+	once := make([]uint64, 3)
+	var onceBalance, onceTotal uint64 = 1023, 0
+	release(split, &onceBalance, once, &onceTotal)
+
+	if !reflect.DeepEqual(released, once) || balance != onceBalance {
+		t.Errorf("incremental = %v (dust %d), once = %v (dust %d)", released, balance, once, onceBalance)
+	}
+}
+
+// TestPendingPaymentLargeAmounts 测试大额累计值不会乘法溢出
+func TestPendingPaymentLargeAmounts(t *testing.T) {
+	const maxAmount = ^uint64(0)
+	if got := PendingPayment(maxAmount, 1, 2, 0); got != maxAmount/2 {
+		t.Errorf("PendingPayment(max, 1/2) = %d, want %d", got, maxAmount/2)
+	}
+	if got := PendingPayment(maxAmount, 3, 3, 0); got != maxAmount {
+		t.Errorf("PendingPayment(max, 3/3) = %d, want %d", got, maxAmount)
+	}
+	if got := PendingPayment(100, 1, 0, 0); got != 0 {
+		t.Errorf("PendingPayment with zero total shares = %d", got)
+	}
+}
+
+// TestNewSplitValidation 测试分账配置校验
+func TestNewSplitValidation(t *testing.T) {
+	alice, bob := fixtures.Alice(), fixtures.Bob()
+	tests := []struct {
+		name       string
+		recipients []framework.Address
+		shares     []uint64
+	}{
+		{"empty", nil, nil},
+		{"length mismatch", []framework.Address{alice, bob}, []uint64{1}},
+		{"zero share", []framework.Address{alice, bob}, []uint64{1, 0}},
+		{"duplicate recipient", []framework.Address{alice, alice}, []uint64{1, 1}},
+		{"zero address", []framework.Address{alice, {}}, []uint64{1, 1}},
+		{"shares overflow", []framework.Address{alice, bob}, []uint64{^uint64(0), 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSplit(tt.recipients, tt.shares); err == nil {
+				t.Error("NewSplit() should fail")
+			}
+		})
+	}
+}
+
+// TestSplitCodec 测试分账配置编解码往返
+func TestSplitCodec(t *testing.T) {
+	split := newTestSplit(t)
+	encoded := EncodeSplit(split)
+	decoded, err := DecodeSplit(encoded)
+	if err != nil {
+		t.Fatalf("DecodeSplit() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, split) {
+		t.Errorf("decoded = %+v, want %+v", decoded, split)
+	}
+	if decoded.SharesOf(fixtures.Bob()) != 30 || decoded.SharesOf(fixtures.Operator()) != 0 {
+		t.Errorf("SharesOf() mismatch")
+	}
+
+	// 状态读取裁剪末尾 0x00 后仍可解码
+	trimmed := encoded
+	for len(trimmed) > 0 && trimmed[len(trimmed)-1] == 0 {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+	if decoded, err := DecodeSplit(trimmed); err != nil || !reflect.DeepEqual(decoded, split) {
+		t.Errorf("DecodeSplit(trimmed) = %+v, %v", decoded, err)
+	}
+	if _, err := DecodeSplit([]byte{0, 1}); err == nil {
+		t.Error("DecodeSplit() with missing entry should fail")
+	}
+}
@@ -0,0 +1,188 @@
+//go:build tinygo || (js && wasm)
+
+package splitter
+
+import (
+	"encoding/binary"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// 状态ID
+const (
+	// STATE_SPLIT 分账配置
+	STATE_SPLIT = "splitter_config"
+)
+
+// Configure 设置分账配置
+//
+// 🎯 **用途**：收益分成（合作者分账、DAO 分润等）：合约收到的款项按份额分配给各收款方
+//
+// **参数**：
+//   - recipients: 收款方地址
+//   - shares: 各收款方份额（与 recipients 一一对应）
+//
+// **返回**：
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS，已配置返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：
+//   - 配置只能设置一次：已发放的金额按原份额记账，修改份额会破坏累计记账
+//   - 谁可以设置配置是业务逻辑，需要在合约代码中实现（通常在初始化时调用）
+//
+// **示例**：
+//
+//	err := splitter.Configure(
+//	    []framework.Address{artist, label, dao},
+//	    []uint64{50, 30, 20},
+//	)
+func Configure(recipients []framework.Address, shares []uint64) error {
+	split, err := NewSplit(recipients, shares)
+	if err != nil {
+		return err
+	}
+	if _, version, err := framework.GetStateFromChain([]byte(STATE_SPLIT)); err == nil && version > 0 {
+		return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "split already configured")
+	}
+	if _, err := framework.AppendStateOutputSimple([]byte(STATE_SPLIT), 1, EncodeSplit(split), nil); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save split")
+	}
+
+	event := framework.NewEvent("SplitConfigured")
+	event.AddUint64Field("recipient_count", uint64(len(split.Recipients)))
+	event.AddUint64Field("total_shares", split.TotalShares())
+	event.AddAddressField("caller", framework.GetCaller())
+	framework.EmitEvent(event)
+	return nil
+}
+
+// Release 将合约持有的该代币按份额发放给全部收款方
+//
+// 🎯 **用途**：合约地址收到的款项（销售收入、租金等）一次性分给各收款方
+//
+// **参数**：
+//   - tokenID: 代币ID（空字符串表示原生币）
+//
+// **返回**：
+//   - framework.Amount: 本次发放总额（无可发放金额时为 0，不发起交易）
+//   - error: 未配置返回 ERROR_NOT_FOUND
+//
+// **注意**：
+//   - 每个收款方的发放额见 PendingPayment：按累计收到金额计算，向下取整的尾差留在合约中，
+//     后续入账后按同一公式补发，不会偏向任何收款方
+//   - 每个收到款项的收款方发出一个 PaymentReleased 事件
+//
+// **示例**：
+//
+//	released, err := splitter.Release("USDT")
+func Release(tokenID framework.TokenID) (framework.Amount, error) {
+	split, err := GetSplit()
+	if err != nil {
+		return 0, err
+	}
+
+	// 1. 计算每个收款方的可领取金额
+	contractAddr := framework.GetContractAddress()
+	totalReleased, totalVersion := loadAmount(buildTotalReleasedStateID(tokenID))
+	totalReceived := uint64(framework.QueryUTXOBalance(contractAddr, tokenID)) + totalReleased
+	totalShares := split.TotalShares()
+
+	builder := framework.BeginTransaction()
+	payments := make([]uint64, len(split.Recipients))
+	var sum uint64
+	for i, recipient := range split.Recipients {
+		releasedStateID := buildReleasedStateID(tokenID, recipient)
+		released, version := loadAmount(releasedStateID)
+		payments[i] = PendingPayment(totalReceived, split.Shares[i], totalShares, released)
+		if payments[i] == 0 {
+			continue
+		}
+		sum += payments[i]
+		builder = builder.
+			Transfer(contractAddr, recipient, tokenID, framework.Amount(payments[i])).
+			AddStateOutput(releasedStateID, version+1, binary.BigEndian.AppendUint64(nil, released+payments[i]))
+	}
+	if sum == 0 {
+		return 0, nil
+	}
+
+	// 2. 划转与发放记录在同一笔交易提交
+	success, _, errCode := builder.
+		AddStateOutput(buildTotalReleasedStateID(tokenID), totalVersion+1, binary.BigEndian.AppendUint64(nil, totalReleased+sum)).
+		Finalize()
+	if !success {
+		return 0, framework.NewContractError(errCode, "splitter release failed")
+	}
+
+	// 3. 每个收款方发出事件
+	for i, recipient := range split.Recipients {
+		if payments[i] == 0 {
+			continue
+		}
+		event := framework.NewEvent("PaymentReleased")
+		event.AddAddressField("recipient", recipient)
+		event.AddStringField("token_id", string(tokenID))
+		event.AddUint64Field("amount", payments[i])
+		event.AddUint64Field("shares", split.Shares[i])
+		event.AddUint64Field("total_shares", totalShares)
+		framework.EmitEvent(event)
+	}
+	return framework.Amount(sum), nil
+}
+
+// Releasable 查询收款方当前可领取的金额
+//
+// **返回**：
+//   - framework.Amount: 下次 Release 时该收款方将收到的金额；非收款方或未配置时为 0
+func Releasable(recipient framework.Address, tokenID framework.TokenID) framework.Amount {
+	split, err := GetSplit()
+	if err != nil {
+		return 0
+	}
+	shares := split.SharesOf(recipient)
+	if shares == 0 {
+		return 0
+	}
+	totalReleased, _ := loadAmount(buildTotalReleasedStateID(tokenID))
+	released, _ := loadAmount(buildReleasedStateID(tokenID, recipient))
+	totalReceived := uint64(framework.QueryUTXOBalance(framework.GetContractAddress(), tokenID)) + totalReleased
+	return framework.Amount(PendingPayment(totalReceived, shares, split.TotalShares(), released))
+}
+
+// Released 查询收款方已领取的累计金额
+func Released(recipient framework.Address, tokenID framework.TokenID) framework.Amount {
+	released, _ := loadAmount(buildReleasedStateID(tokenID, recipient))
+	return framework.Amount(released)
+}
+
+// GetSplit 查询分账配置
+//
+// **返回**：
+//   - error: 未配置返回 ERROR_NOT_FOUND
+func GetSplit() (*Split, error) {
+	data, version, err := framework.GetStateFromChain([]byte(STATE_SPLIT))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "split not configured")
+	}
+	return DecodeSplit(data)
+}
+
+// loadAmount 读取8字节金额状态及其版本，不存在时为 0
+func loadAmount(stateID []byte) (amount, version uint64) {
+	data, version, err := framework.GetStateFromChain(stateID)
+	if err != nil || version == 0 {
+		return 0, 0
+	}
+	buf := make([]byte, 8)
+	copy(buf, data)
+	return binary.BigEndian.Uint64(buf), version
+}
+
+// buildTotalReleasedStateID 构建代币已发放总额状态ID：splitter_released:{token_id}
+func buildTotalReleasedStateID(tokenID framework.TokenID) []byte {
+	return []byte("splitter_released:" + string(tokenID))
+}
+
+// buildReleasedStateID 构建收款方已领取金额状态ID：splitter_released:{token_id}:{address}
+func buildReleasedStateID(tokenID framework.TokenID, recipient framework.Address) []byte {
+	return append([]byte("splitter_released:"+string(tokenID)+":"), recipient[:]...)
+}
@@ -0,0 +1,249 @@
+//go:build tinygo || (js && wasm)
+
+package staking
+
+import (
+	"encoding/hex"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/governance"
+)
+
+// slashCaseSeqStateID 罚没案件序号状态ID（十进制字符串）
+const slashCaseSeqStateID = "slash_case_seq"
+
+// SubmitSlashEvidence 提交验证者违规证据，开启罚没案件
+//
+// 🎯 **用途**：替代仅限合约所有者的直接罚没——任何质押者都可以举报，
+// 举报保证金从举报人转入合约地址锁定，被举报的验证者在申诉期内可以通过 AppealSlash 申诉
+//
+// **参数**：
+//   - proposer: 举报人（须为质押者）
+//   - validator: 被举报的验证者
+//   - offenseType: 违规类型（如 "double_sign"）
+//   - evidenceHash: 证据哈希（证据本体存放在链下）
+//   - proposedPenaltyBP: 提议的罚没比例（基点）
+//   - config: 罚没配置（保证金、申诉期、举报奖励比例等）
+//   - ledger: 质押台账
+//
+// **返回**：
+//   - string: 案件ID（slash_{序号}）
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、非质押者（ERROR_UNAUTHORIZED）、
+//     余额不足以缴纳保证金（ERROR_INSUFFICIENT_BALANCE）
+//
+// **示例**：
+//
+//	caseID, err := staking.SubmitSlashEvidence(caller, validator, "double_sign", evidenceHash, 500, slashConfig, ledger)
+func SubmitSlashEvidence(proposer, validator framework.Address, offenseType string, evidenceHash []byte, proposedPenaltyBP uint64, config SlashConfig, ledger BondLedger) (string, error) {
+	// 1. 分配案件ID
+	seqData, _ := framework.GetState(slashCaseSeqStateID)
+	seq := parseSlashCaseSeq(seqData) + 1
+	caseID := "slash_" + framework.Uint64ToString(seq)
+
+	// 2. 参数与举报人验证
+	slashCase, err := NewSlashCase(caseID, validator, proposer, offenseType, evidenceHash, proposedPenaltyBP, config, framework.GetTimestamp())
+	if err != nil {
+		return "", err
+	}
+	if !ledger.IsStaker(proposer) {
+		return "", framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only stakers can submit slash evidence")
+	}
+	if framework.QueryUTXOBalance(proposer, config.Token) < config.ProposerBond {
+		return "", framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance for proposer bond")
+	}
+
+	// 3. 锁定举报保证金，写入案件记录与序号
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(proposer, framework.GetContractAddress(), config.Token, config.ProposerBond).
+		AddStateOutput(buildSlashCaseStateID(caseID), 1, encodeSlashCase(slashCase)).
+		AddStateOutput([]byte(slashCaseSeqStateID), seq, []byte(framework.Uint64ToString(seq))).
+		Finalize()
+	if !success {
+		return "", framework.NewContractError(errCode, "failed to submit slash evidence")
+	}
+
+	// 4. 发出事件
+	event := newSlashCaseEvent("SlashEvidenceSubmitted", slashCase)
+	event.AddStringField("evidence_hash", "0x"+hex.EncodeToString(slashCase.EvidenceHash))
+	event.AddUint64Field("appeal_deadline", slashCase.AppealDeadline)
+	framework.EmitEvent(event)
+
+	return caseID, nil
+}
+
+// AppealSlash 被举报的验证者在申诉期内提交反证
+//
+// **参数**：
+//   - validator: 被举报的验证者（调用者）
+//   - caseID: 案件ID
+//   - counterEvidenceHash: 反证哈希
+//
+// **返回**：
+//   - error: 案件不存在（ERROR_NOT_FOUND）、非被举报验证者（ERROR_UNAUTHORIZED）、
+//     已申诉或已裁决（ERROR_INVALID_STATE）、申诉期已结束（ERROR_TIMEOUT）
+func AppealSlash(validator framework.Address, caseID string, counterEvidenceHash []byte) error {
+	slashCase, version, err := loadSlashCase(caseID)
+	if err != nil {
+		return err
+	}
+	if err := slashCase.Appeal(validator, counterEvidenceHash, framework.GetTimestamp()); err != nil {
+		return err
+	}
+	if err := commitSlashCase(framework.BeginTransaction(), slashCase, version); err != nil {
+		return err
+	}
+
+	event := newSlashCaseEvent("SlashAppealed", slashCase)
+	event.AddStringField("counter_evidence_hash", "0x"+hex.EncodeToString(slashCase.CounterEvidenceHash))
+	framework.EmitEvent(event)
+	return nil
+}
+
+// ResolveSlash 裁决者在申诉期结束后裁决罚没案件
+//
+// **参数**：
+//   - adjudicator: 调用者，须为 config.Adjudicator
+//   - caseID: 案件ID
+//   - upheld: 举报是否成立
+//   - config: 罚没配置（只使用 Adjudicator，其余以案件中的快照为准）
+//   - ledger: 质押台账，举报成立时扣减
+//
+// **返回**：
+//   - SlashResolution: 罚没分配、举报奖励与保证金去向
+//   - error: 非裁决者（ERROR_UNAUTHORIZED）、案件不存在（ERROR_NOT_FOUND）、
+//     已裁决或申诉期未结束（ERROR_INVALID_STATE）
+//
+// **注意**：
+//   - 举报成立：台账按 ComputeSlash 扣减，举报保证金与举报奖励从合约地址转给举报人
+//   - 举报不成立：举报保证金留在合约地址（没收），如何处置由合约决定
+//   - 罚没资金须由合约地址托管，罚没金额扣除举报奖励后留在合约地址
+func ResolveSlash(adjudicator framework.Address, caseID string, upheld bool, config SlashConfig, ledger BondLedger) (SlashResolution, error) {
+	if config.Adjudicator == (framework.Address{}) || adjudicator != config.Adjudicator {
+		return SlashResolution{}, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the adjudicator can resolve slash cases")
+	}
+	return resolveSlash(caseID, upheld, ledger, "adjudicator")
+}
+
+// ResolveSlashByVote 按治理投票结果裁决罚没案件
+//
+// 🎯 **用途**：不设裁决者时，由质押者对案件投票（governance.Vote / SubmitSignedBallots），
+// 支持票（选项 1）多于反对票（选项 0）时举报成立
+//
+// **参数**：
+//   - caseID: 案件ID
+//   - proposalID: 对应的治理提案ID
+//   - ledger: 质押台账
+//
+// **返回**：
+//   - SlashResolution: 同 ResolveSlash
+//   - error: 提案尚无计票（ERROR_INVALID_STATE），其余同 ResolveSlash
+//
+// **注意**：计票读取 governance.GetTally；投票是否已截止由合约在调用前判断
+func ResolveSlashByVote(caseID string, proposalID []byte, ledger BondLedger) (SlashResolution, error) {
+	support := governance.GetTally(proposalID, 1)
+	oppose := governance.GetTally(proposalID, 0)
+	if support == 0 && oppose == 0 {
+		return SlashResolution{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "no votes on slash proposal")
+	}
+	return resolveSlash(caseID, support > oppose, ledger, "vote")
+}
+
+// GetSlashCase 查询罚没案件（状态、保证金与裁决结果）
+//
+// **返回**：
+//   - *SlashCase: 案件记录
+//   - error: 不存在时返回 ERROR_NOT_FOUND
+func GetSlashCase(caseID string) (*SlashCase, error) {
+	slashCase, _, err := loadSlashCase(caseID)
+	return slashCase, err
+}
+
+// resolveSlash 裁决案件，结算举报保证金与奖励并发出事件
+func resolveSlash(caseID string, upheld bool, ledger BondLedger, resolvedBy string) (SlashResolution, error) {
+	slashCase, version, err := loadSlashCase(caseID)
+	if err != nil {
+		return SlashResolution{}, err
+	}
+
+	res, err := ResolveSlashCase(ledger, slashCase, upheld, framework.GetTimestamp())
+	if err != nil {
+		return SlashResolution{}, err
+	}
+
+	builder := framework.BeginTransaction()
+	if payout := res.BondReturned + res.ReporterReward; payout > 0 {
+		builder = builder.Transfer(framework.GetContractAddress(), slashCase.Proposer, slashCase.Token, payout)
+	}
+	if err := commitSlashCase(builder, slashCase, version); err != nil {
+		return SlashResolution{}, err
+	}
+
+	event := newSlashCaseEvent("SlashResolved", slashCase)
+	event.AddStringField("resolved_by", resolvedBy)
+	event.AddUint64Field("penalty", uint64(res.Penalty))
+	event.AddUint64Field("self_bond_slashed", uint64(res.SelfBondSlashed))
+	event.AddUint64Field("delegators_slashed", uint64(len(res.DelegationsSlashed)))
+	event.AddUint64Field("reporter_reward", uint64(res.ReporterReward))
+	event.AddUint64Field("bond_returned", uint64(res.BondReturned))
+	event.AddUint64Field("bond_forfeited", uint64(res.BondForfeited))
+	framework.EmitEvent(event)
+	return res, nil
+}
+
+// loadSlashCase 读取案件记录及其状态版本
+func loadSlashCase(caseID string) (*SlashCase, uint64, error) {
+	if caseID == "" {
+		return nil, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "caseID cannot be empty")
+	}
+	data, version, err := framework.GetStateFromChain(buildSlashCaseStateID(caseID))
+	if err != nil || version == 0 || len(data) == 0 {
+		return nil, 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "slash case not found")
+	}
+	slashCase, err := decodeSlashCase(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return slashCase, version, nil
+}
+
+// commitSlashCase 将资金划转与新版本案件记录放入同一笔交易提交
+func commitSlashCase(builder *framework.TransactionBuilder, slashCase *SlashCase, version uint64) error {
+	success, _, errCode := builder.
+		AddStateOutput(buildSlashCaseStateID(slashCase.CaseID), version+1, encodeSlashCase(slashCase)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "slash case transaction failed")
+	}
+	return nil
+}
+
+// newSlashCaseEvent 构建包含案件双方与保证金的事件
+func newSlashCaseEvent(name string, slashCase *SlashCase) *framework.Event {
+	event := framework.NewEvent(name)
+	event.AddStringField("case_id", slashCase.CaseID)
+	event.AddStringField("status", slashCase.Status)
+	event.AddAddressField("validator", slashCase.Validator)
+	event.AddAddressField("proposer", slashCase.Proposer)
+	event.AddStringField("offense_type", slashCase.OffenseType)
+	event.AddUint64Field("penalty_bp", slashCase.PenaltyBP)
+	event.AddStringField("token_id", string(slashCase.Token))
+	event.AddUint64Field("proposer_bond", uint64(slashCase.ProposerBond))
+	return event
+}
+
+// buildSlashCaseStateID 构建罚没案件的状态ID
+func buildSlashCaseStateID(caseID string) []byte {
+	return []byte("slash_case:" + caseID)
+}
+
+// parseSlashCaseSeq 解析十进制案件序号，无效时视为 0
+func parseSlashCaseSeq(data []byte) uint64 {
+	var seq uint64
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			break
+		}
+		seq = seq*10 + uint64(c-'0')
+	}
+	return seq
+}
@@ -0,0 +1,483 @@
+package staking
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 基于证据的罚没（纯状态机） ====================
+//
+// 任何质押者提交验证者的违规证据并锁定举报保证金，开启罚没案件；被举报的验证者在申诉期内
+// 可以提交反证；申诉期结束后由裁决者（或治理投票结果）裁决：
+//   - 成立：罚没先从验证者自有质押扣除，不足部分按委托金额比例从委托者扣除；
+//     罚没金额的一部分奖励举报人，举报保证金退还
+//   - 不成立：举报保证金被没收，抑制恶意举报
+//
+// 本文件只包含不依赖宿主函数的案件记录、状态迁移与罚没分配，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试。资金划转与状态写入见 slash.go。
+
+// 罚没案件状态
+const (
+	// SLASH_STATUS_OPEN 申诉期内：举报保证金已锁定，等待验证者申诉或申诉期结束
+	SLASH_STATUS_OPEN = "OPEN"
+	// SLASH_STATUS_APPEALED 验证者已提交反证，申诉期结束后裁决
+	SLASH_STATUS_APPEALED = "APPEALED"
+	// SLASH_STATUS_UPHELD 举报成立：已罚没，举报人获得奖励并取回保证金
+	SLASH_STATUS_UPHELD = "UPHELD"
+	// SLASH_STATUS_DISMISSED 举报不成立：举报保证金被没收
+	SLASH_STATUS_DISMISSED = "DISMISSED"
+)
+
+// BASIS_POINTS 基点分母（10000 = 100%）
+const BASIS_POINTS = 10000
+
+// MAX_EVIDENCE_HASH_LEN 证据哈希的最大长度
+const MAX_EVIDENCE_HASH_LEN = 64
+
+// SlashConfig 罚没流程配置
+type SlashConfig struct {
+	// Token 质押代币：举报保证金、罚没与举报奖励均使用该代币（空字符串表示原生币）
+	Token framework.TokenID
+	// ProposerBond 举报保证金
+	ProposerBond framework.Amount
+	// AppealWindow 申诉期时长（秒），自提交证据起计算
+	AppealWindow uint64
+	// ReporterRewardBP 举报成立时罚没金额中奖励举报人的比例（基点）
+	ReporterRewardBP uint64
+	// MaxPenaltyBP 提议罚没比例上限（基点），0 表示 10000
+	MaxPenaltyBP uint64
+	// Adjudicator 裁决者地址（ResolveSlash 的调用者）；使用治理投票裁决时可为零地址
+	Adjudicator framework.Address
+}
+
+// DelegationBond 委托者在验证者上的委托金额（或罚没金额）
+type DelegationBond struct {
+	Delegator framework.Address
+	Amount    framework.Amount
+}
+
+// BondLedger 质押台账
+//
+// 🎯 **用途**：质押与委托金额的记账方式是业务逻辑，由合约提供台账：
+//   - IsStaker: 地址是否为质押者（只有质押者可以提交罚没证据）
+//   - SelfBond: 验证者的自有质押
+//   - Delegations: 验证者收到的委托（按固定顺序返回，保证各节点分配一致）
+//   - SlashBond: 从 holder 在 validator 上的质押中扣除 amount（holder 为 validator 时扣自有质押）
+type BondLedger interface {
+	IsStaker(addr framework.Address) bool
+	SelfBond(validator framework.Address) framework.Amount
+	Delegations(validator framework.Address) []DelegationBond
+	SlashBond(validator, holder framework.Address, amount framework.Amount) error
+}
+
+// SlashCase 罚没案件记录
+type SlashCase struct {
+	CaseID    string
+	Status    string
+	Validator framework.Address
+	Proposer  framework.Address
+	// OffenseType 违规类型（如 "double_sign"、"downtime"），由合约约定
+	OffenseType string
+	// EvidenceHash 证据哈希；CounterEvidenceHash 验证者申诉时提交的反证哈希
+	EvidenceHash        []byte
+	CounterEvidenceHash []byte
+	// PenaltyBP 提议的罚没比例（基点），以验证者自有质押与委托之和为基数
+	PenaltyBP uint64
+	// Token / ProposerBond / ReporterRewardBP 提交时的配置快照，裁决时使用
+	Token            framework.TokenID
+	ProposerBond     framework.Amount
+	ReporterRewardBP uint64
+	OpenedAt         uint64
+	// AppealDeadline 申诉截止时间（含），之后才能裁决
+	AppealDeadline uint64
+	ResolvedAt     uint64
+	// 以下为裁决结果（举报成立时非 0）
+	Penalty            framework.Amount
+	SelfBondSlashed    framework.Amount
+	DelegationsSlashed []DelegationBond
+	ReporterReward     framework.Amount
+}
+
+// SlashResolution 裁决结果
+type SlashResolution struct {
+	Upheld bool
+	// Penalty 罚没总额 = SelfBondSlashed + 各委托者罚没之和
+	Penalty            framework.Amount
+	SelfBondSlashed    framework.Amount
+	DelegationsSlashed []DelegationBond
+	// ReporterReward 从罚没金额中奖励举报人的部分
+	ReporterReward framework.Amount
+	// BondReturned / BondForfeited 退还举报人 / 没收的举报保证金
+	BondReturned  framework.Amount
+	BondForfeited framework.Amount
+}
+
+// NewSlashCase 创建处于 OPEN 状态的罚没案件
+//
+// **参数**：
+//   - penaltyBP: 提议的罚没比例，须在 1..MaxPenaltyBP 之间
+//   - config: 罚没配置，保证金、奖励比例与申诉期在案件中保存快照
+//   - now: 当前时间，申诉截止时间 = now + config.AppealWindow
+//
+// **返回**：
+//   - error: 参数无效时返回 ERROR_INVALID_PARAMS
+func NewSlashCase(caseID string, validator, proposer framework.Address, offenseType string, evidenceHash []byte, penaltyBP uint64, config SlashConfig, now uint64) (*SlashCase, error) {
+	zeroAddr := framework.Address{}
+	if validator == zeroAddr || proposer == zeroAddr {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "validator and proposer cannot be zero")
+	}
+	if validator == proposer {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "validator cannot report itself")
+	}
+	if offenseType == "" || len(offenseType) > 0xFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid offense type")
+	}
+	if len(evidenceHash) == 0 || len(evidenceHash) > MAX_EVIDENCE_HASH_LEN {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid evidence hash")
+	}
+	maxPenalty := config.MaxPenaltyBP
+	if maxPenalty == 0 || maxPenalty > BASIS_POINTS {
+		maxPenalty = BASIS_POINTS
+	}
+	if penaltyBP == 0 || penaltyBP > maxPenalty {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "penalty out of range")
+	}
+	if config.ProposerBond == 0 || config.ReporterRewardBP > BASIS_POINTS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid slash config")
+	}
+	if now+config.AppealWindow < now {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "appeal window overflow")
+	}
+	if caseID == "" || len(caseID) > 0xFFFF {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid caseID")
+	}
+
+	return &SlashCase{
+		CaseID:           caseID,
+		Status:           SLASH_STATUS_OPEN,
+		Validator:        validator,
+		Proposer:         proposer,
+		OffenseType:      offenseType,
+		EvidenceHash:     append([]byte{}, evidenceHash...),
+		PenaltyBP:        penaltyBP,
+		Token:            config.Token,
+		ProposerBond:     config.ProposerBond,
+		ReporterRewardBP: config.ReporterRewardBP,
+		OpenedAt:         now,
+		AppealDeadline:   now + config.AppealWindow,
+	}, nil
+}
+
+// AppealWindowClosed 申诉期是否已结束
+func (c *SlashCase) AppealWindowClosed(now uint64) bool {
+	return now > c.AppealDeadline
+}
+
+// Appeal 被举报的验证者在申诉期内提交反证
+//
+// **返回**：
+//   - error: 非被举报验证者（ERROR_UNAUTHORIZED）、已申诉或已裁决（ERROR_INVALID_STATE）、
+//     申诉期已结束（ERROR_TIMEOUT）、反证哈希无效（ERROR_INVALID_PARAMS）
+func (c *SlashCase) Appeal(validator framework.Address, counterEvidenceHash []byte, now uint64) error {
+	if validator != c.Validator {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the accused validator can appeal")
+	}
+	if c.Status != SLASH_STATUS_OPEN {
+		return framework.NewContractError(framework.ERROR_INVALID_STATE, "slash case is not open for appeal")
+	}
+	if c.AppealWindowClosed(now) {
+		return framework.NewContractError(framework.ERROR_TIMEOUT, "appeal window closed")
+	}
+	if len(counterEvidenceHash) == 0 || len(counterEvidenceHash) > MAX_EVIDENCE_HASH_LEN {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid counter evidence hash")
+	}
+
+	c.Status = SLASH_STATUS_APPEALED
+	c.CounterEvidenceHash = append([]byte{}, counterEvidenceHash...)
+	return nil
+}
+
+// ResolveSlashCase 裁决罚没案件
+//
+// 🎯 **用途**：ResolveSlash / ResolveSlashByVote 的核心逻辑，不依赖宿主函数，便于测试
+//
+// **参数**：
+//   - ledger: 质押台账，举报成立时按罚没分配扣减
+//   - c: 罚没案件（OPEN 或 APPEALED），裁决后更新状态与结果
+//   - upheld: 举报是否成立
+//   - now: 当前时间，须晚于申诉截止时间
+//
+// **返回**：
+//   - SlashResolution: 罚没分配、举报奖励与保证金去向
+//   - error: 已裁决或申诉期未结束（ERROR_INVALID_STATE）、台账扣减失败
+//
+// **注意**：扣减顺序为验证者自有质押、各委托者（按 Delegations 返回顺序），见 ComputeSlash
+func ResolveSlashCase(ledger BondLedger, c *SlashCase, upheld bool, now uint64) (SlashResolution, error) {
+	if c.Status != SLASH_STATUS_OPEN && c.Status != SLASH_STATUS_APPEALED {
+		return SlashResolution{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "slash case already resolved")
+	}
+	if !c.AppealWindowClosed(now) {
+		return SlashResolution{}, framework.NewContractError(framework.ERROR_INVALID_STATE, "appeal window still open")
+	}
+
+	if !upheld {
+		c.Status = SLASH_STATUS_DISMISSED
+		c.ResolvedAt = now
+		return SlashResolution{BondForfeited: c.ProposerBond}, nil
+	}
+
+	res := ComputeSlash(ledger.SelfBond(c.Validator), ledger.Delegations(c.Validator), c.PenaltyBP)
+	if res.SelfBondSlashed > 0 {
+		if err := ledger.SlashBond(c.Validator, c.Validator, res.SelfBondSlashed); err != nil {
+			return SlashResolution{}, err
+		}
+	}
+	for _, d := range res.DelegationsSlashed {
+		if err := ledger.SlashBond(c.Validator, d.Delegator, d.Amount); err != nil {
+			return SlashResolution{}, err
+		}
+	}
+	reward, _ := mulDiv(uint64(res.Penalty), c.ReporterRewardBP, BASIS_POINTS)
+	res.ReporterReward = framework.Amount(reward)
+	res.BondReturned = c.ProposerBond
+
+	c.Status = SLASH_STATUS_UPHELD
+	c.ResolvedAt = now
+	c.Penalty = res.Penalty
+	c.SelfBondSlashed = res.SelfBondSlashed
+	c.DelegationsSlashed = res.DelegationsSlashed
+	c.ReporterReward = res.ReporterReward
+	return res, nil
+}
+
+// ComputeSlash 计算罚没分配
+//
+// 罚没总额 = (selfBond + 委托之和) × penaltyBP / 10000（向下取整）。先从自有质押扣除，
+// 不足部分按委托金额比例分摊到委托者：各委托者先取 floor(剩余 × 委托 / 委托之和)，
+// 取整产生的尾差按 delegations 顺序每人补 1，保证分配之和等于罚没总额且不超过各自委托。
+//
+// **返回**：SlashResolution 中的 Upheld、Penalty、SelfBondSlashed、DelegationsSlashed（只含非 0 项）
+func ComputeSlash(selfBond framework.Amount, delegations []DelegationBond, penaltyBP uint64) SlashResolution {
+	var delegated uint64
+	for _, d := range delegations {
+		delegated += uint64(d.Amount)
+	}
+	total := uint64(selfBond) + delegated
+	penalty, _ := mulDiv(total, penaltyBP, BASIS_POINTS)
+
+	res := SlashResolution{Upheld: true, Penalty: framework.Amount(penalty)}
+	fromSelf := penalty
+	if fromSelf > uint64(selfBond) {
+		fromSelf = uint64(selfBond)
+	}
+	res.SelfBondSlashed = framework.Amount(fromSelf)
+
+	remaining := penalty - fromSelf
+	if remaining == 0 || delegated == 0 {
+		return res
+	}
+	shares := make([]uint64, len(delegations))
+	var assigned uint64
+	for i, d := range delegations {
+		shares[i], _ = mulDiv(remaining, uint64(d.Amount), delegated)
+		assigned += shares[i]
+	}
+	for i := range shares {
+		if assigned == remaining {
+			break
+		}
+		if shares[i] < uint64(delegations[i].Amount) {
+			shares[i]++
+			assigned++
+		}
+	}
+	for i, d := range delegations {
+		if shares[i] > 0 {
+			res.DelegationsSlashed = append(res.DelegationsSlashed, DelegationBond{Delegator: d.Delegator, Amount: framework.Amount(shares[i])})
+		}
+	}
+	return res
+}
+
+// mulDiv 计算 a × b / c（128位中间结果，向下取整）；结果超出 uint64 时返回 false
+func mulDiv(a, b, c uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	q, _ := bits.Div64(hi, lo, c)
+	return q, true
+}
+
+// ==================== 内存台账 ====================
+
+// MemoryBondLedger 内存质押台账
+//
+// 🎯 **用途**：用于非WASM环境下的单元测试与离线模拟
+type MemoryBondLedger struct {
+	self        map[framework.Address]framework.Amount
+	delegations map[framework.Address][]DelegationBond
+}
+
+// NewMemoryBondLedger 创建内存质押台账
+func NewMemoryBondLedger() *MemoryBondLedger {
+	return &MemoryBondLedger{
+		self:        make(map[framework.Address]framework.Amount),
+		delegations: make(map[framework.Address][]DelegationBond),
+	}
+}
+
+// SetSelfBond 设置验证者的自有质押
+func (m *MemoryBondLedger) SetSelfBond(validator framework.Address, amount framework.Amount) {
+	m.self[validator] = amount
+}
+
+// AddDelegation 追加委托者对验证者的委托
+func (m *MemoryBondLedger) AddDelegation(validator, delegator framework.Address, amount framework.Amount) {
+	m.delegations[validator] = append(m.delegations[validator], DelegationBond{Delegator: delegator, Amount: amount})
+}
+
+// IsStaker 地址有自有质押或对任一验证者有委托时为质押者
+func (m *MemoryBondLedger) IsStaker(addr framework.Address) bool {
+	if m.self[addr] > 0 {
+		return true
+	}
+	for _, list := range m.delegations {
+		for _, d := range list {
+			if d.Delegator == addr && d.Amount > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SelfBond 返回验证者的自有质押
+func (m *MemoryBondLedger) SelfBond(validator framework.Address) framework.Amount {
+	return m.self[validator]
+}
+
+// Delegations 返回验证者收到的委托（按追加顺序）
+func (m *MemoryBondLedger) Delegations(validator framework.Address) []DelegationBond {
+	return append([]DelegationBond{}, m.delegations[validator]...)
+}
+
+// DelegationOf 返回委托者在验证者上的委托金额
+func (m *MemoryBondLedger) DelegationOf(validator, delegator framework.Address) framework.Amount {
+	for _, d := range m.delegations[validator] {
+		if d.Delegator == delegator {
+			return d.Amount
+		}
+	}
+	return 0
+}
+
+// SlashBond 扣减质押，超过现有金额时返回 ERROR_INSUFFICIENT_BALANCE
+func (m *MemoryBondLedger) SlashBond(validator, holder framework.Address, amount framework.Amount) error {
+	insufficient := framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "slash exceeds bond")
+	if holder == validator {
+		if m.self[validator] < amount {
+			return insufficient
+		}
+		m.self[validator] -= amount
+		return nil
+	}
+	for i, d := range m.delegations[validator] {
+		if d.Delegator == holder {
+			if d.Amount < amount {
+				return insufficient
+			}
+			m.delegations[validator][i].Amount -= amount
+			return nil
+		}
+	}
+	return insufficient
+}
+
+// ==================== 编码 ====================
+
+// encodeSlashCase 编码罚没案件
+//
+// 编码格式（整数均为大端）：
+//
+//	validator(20) + proposer(20) + penaltyBP(8) + proposerBond(8) + reporterRewardBP(8) +
+//	openedAt(8) + appealDeadline(8) + resolvedAt(8) + penalty(8) + selfBondSlashed(8) + reporterReward(8) +
+//	count(2) + [delegator(20) + amount(8)] × count +
+//	[len(2) + bytes] × (status, offenseType, evidenceHash, counterEvidenceHash, token, caseID)
+//
+// caseID 非空且放在末尾，链上读取去除尾部零字节不会截断记录
+func encodeSlashCase(c *SlashCase) []byte {
+	buf := make([]byte, 0, 40+72+2+28*len(c.DelegationsSlashed)+12+len(c.Status)+len(c.OffenseType)+len(c.EvidenceHash)+len(c.CounterEvidenceHash)+len(c.Token)+len(c.CaseID))
+	buf = append(buf, c.Validator[:]...)
+	buf = append(buf, c.Proposer[:]...)
+	for _, v := range []uint64{c.PenaltyBP, uint64(c.ProposerBond), c.ReporterRewardBP, c.OpenedAt, c.AppealDeadline,
+		c.ResolvedAt, uint64(c.Penalty), uint64(c.SelfBondSlashed), uint64(c.ReporterReward)} {
+		buf = binary.BigEndian.AppendUint64(buf, v)
+	}
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(c.DelegationsSlashed)))
+	for _, d := range c.DelegationsSlashed {
+		buf = append(buf, d.Delegator[:]...)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(d.Amount))
+	}
+	for _, s := range [][]byte{[]byte(c.Status), []byte(c.OffenseType), c.EvidenceHash, c.CounterEvidenceHash, []byte(c.Token), []byte(c.CaseID)} {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+		buf = append(buf, s...)
+	}
+	return buf
+}
+
+// decodeSlashCase 解码罚没案件
+func decodeSlashCase(data []byte) (*SlashCase, error) {
+	invalid := framework.NewContractError(framework.ERROR_INVALID_STATE, "invalid slash case record")
+	if len(data) < 40+72+2 {
+		return nil, invalid
+	}
+
+	c := &SlashCase{}
+	copy(c.Validator[:], data[0:20])
+	copy(c.Proposer[:], data[20:40])
+	nums := make([]uint64, 9)
+	for i := range nums {
+		nums[i] = binary.BigEndian.Uint64(data[40+8*i:])
+	}
+	c.PenaltyBP, c.ProposerBond, c.ReporterRewardBP = nums[0], framework.Amount(nums[1]), nums[2]
+	c.OpenedAt, c.AppealDeadline, c.ResolvedAt = nums[3], nums[4], nums[5]
+	c.Penalty, c.SelfBondSlashed, c.ReporterReward = framework.Amount(nums[6]), framework.Amount(nums[7]), framework.Amount(nums[8])
+	pos := 40 + 72
+
+	count := int(binary.BigEndian.Uint16(data[pos:]))
+	pos += 2
+	if len(data) < pos+28*count {
+		return nil, invalid
+	}
+	for i := 0; i < count; i++ {
+		var d DelegationBond
+		copy(d.Delegator[:], data[pos:pos+20])
+		d.Amount = framework.Amount(binary.BigEndian.Uint64(data[pos+20:]))
+		c.DelegationsSlashed = append(c.DelegationsSlashed, d)
+		pos += 28
+	}
+
+	fields := make([][]byte, 6)
+	for i := range fields {
+		if len(data) < pos+2 {
+			return nil, invalid
+		}
+		n := int(binary.BigEndian.Uint16(data[pos:]))
+		pos += 2
+		if len(data) < pos+n {
+			return nil, invalid
+		}
+		fields[i] = data[pos : pos+n]
+		pos += n
+	}
+	c.Status, c.OffenseType = string(fields[0]), string(fields[1])
+	c.EvidenceHash = append([]byte{}, fields[2]...)
+	if len(fields[3]) > 0 {
+		c.CounterEvidenceHash = append([]byte{}, fields[3]...)
+	}
+	c.Token, c.CaseID = framework.TokenID(fields[4]), string(fields[5])
+	return c, nil
+}
@@ -0,0 +1,200 @@
+package staking
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+var (
+	testValidator  = framework.Address{1}
+	testProposer   = framework.Address{2}
+	testDelegatorA = framework.Address{3}
+	testDelegatorB = framework.Address{4}
+)
+
+var testSlashConfig = SlashConfig{
+	Token:            "WES",
+	ProposerBond:     1000,
+	AppealWindow:     3600,
+	ReporterRewardBP: 1000,
+	MaxPenaltyBP:     5000,
+}
+
+func errCode(err error) uint32 {
+	if ce, ok := err.(*framework.ContractError); ok {
+		return ce.Code
+	}
+	return framework.SUCCESS
+}
+
+// newTestLedger 验证者自有质押 1000，委托者 A 3000、B 1000
+func newTestLedger() *MemoryBondLedger {
+	ledger := NewMemoryBondLedger()
+	ledger.SetSelfBond(testValidator, 1000)
+	ledger.AddDelegation(testValidator, testDelegatorA, 3000)
+	ledger.AddDelegation(testValidator, testDelegatorB, 1000)
+	ledger.SetSelfBond(testProposer, 500)
+	return ledger
+}
+
+func newTestSlashCase(t *testing.T, penaltyBP uint64) *SlashCase {
+	t.Helper()
+	c, err := NewSlashCase("slash_1", testValidator, testProposer, "double_sign", []byte{0xAB, 0xCD}, penaltyBP, testSlashConfig, 100)
+	if err != nil {
+		t.Fatalf("NewSlashCase() error = %v", err)
+	}
+	return c
+}
+
+// TestSlashFrivolousReport 测试举报不成立时举报保证金被没收，质押不受影响
+func TestSlashFrivolousReport(t *testing.T) {
+	ledger := newTestLedger()
+	c := newTestSlashCase(t, 2000)
+
+	if err := c.Appeal(testValidator, []byte{0x01}, 3700); err != nil {
+		t.Fatalf("Appeal() error = %v", err)
+	}
+	res, err := ResolveSlashCase(ledger, c, false, 3701)
+	if err != nil {
+		t.Fatalf("ResolveSlashCase() error = %v", err)
+	}
+
+	want := SlashResolution{BondForfeited: 1000}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ResolveSlashCase() = %+v, want %+v", res, want)
+	}
+	if c.Status != SLASH_STATUS_DISMISSED || c.Penalty != 0 || c.ResolvedAt != 3701 {
+		t.Errorf("case after dismissal: status = %s, penalty = %d, resolvedAt = %d", c.Status, c.Penalty, c.ResolvedAt)
+	}
+	if ledger.SelfBond(testValidator) != 1000 || ledger.DelegationOf(testValidator, testDelegatorA) != 3000 {
+		t.Error("dismissed case changed bonds")
+	}
+	if _, err := ResolveSlashCase(ledger, c, true, 3800); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second ResolveSlashCase() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestSlashUpheldHitsDelegatorsProRata 测试举报成立时先扣自有质押，不足部分按比例扣委托者
+func TestSlashUpheldHitsDelegatorsProRata(t *testing.T) {
+	ledger := newTestLedger()
+	// 总质押 5000 × 30% = 1500：自有质押 1000 全部扣除，剩余 500 按 3:1 分摊给 A、B
+	c := newTestSlashCase(t, 3000)
+
+	res, err := ResolveSlashCase(ledger, c, true, 3701)
+	if err != nil {
+		t.Fatalf("ResolveSlashCase() error = %v", err)
+	}
+	want := SlashResolution{
+		Upheld:          true,
+		Penalty:         1500,
+		SelfBondSlashed: 1000,
+		DelegationsSlashed: []DelegationBond{
+			{Delegator: testDelegatorA, Amount: 375},
+			{Delegator: testDelegatorB, Amount: 125},
+		},
+		ReporterReward: 150,
+		BondReturned:   1000,
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ResolveSlashCase() = %+v, want %+v", res, want)
+	}
+	if got := []framework.Amount{ledger.SelfBond(testValidator), ledger.DelegationOf(testValidator, testDelegatorA), ledger.DelegationOf(testValidator, testDelegatorB)}; !reflect.DeepEqual(got, []framework.Amount{0, 2625, 875}) {
+		t.Errorf("bonds after slash = %v, want [0 2625 875]", got)
+	}
+	if c.Status != SLASH_STATUS_UPHELD || c.Penalty != 1500 || c.ReporterReward != 150 || !reflect.DeepEqual(c.DelegationsSlashed, want.DelegationsSlashed) {
+		t.Errorf("case after upheld = %+v", c)
+	}
+}
+
+// TestSlashResolveBeforeAppealWindow 测试申诉期结束前不能裁决
+func TestSlashResolveBeforeAppealWindow(t *testing.T) {
+	ledger := newTestLedger()
+	c := newTestSlashCase(t, 3000)
+
+	for _, now := range []uint64{100, 3700} {
+		if _, err := ResolveSlashCase(ledger, c, true, now); errCode(err) != framework.ERROR_INVALID_STATE {
+			t.Errorf("ResolveSlashCase() at %d code = %d, want ERROR_INVALID_STATE", now, errCode(err))
+		}
+	}
+	if c.Status != SLASH_STATUS_OPEN || ledger.SelfBond(testValidator) != 1000 {
+		t.Errorf("early resolution changed state: status = %s, self bond = %d", c.Status, ledger.SelfBond(testValidator))
+	}
+}
+
+// TestSlashAppeal 测试只有被举报的验证者可以在申诉期内申诉一次
+func TestSlashAppeal(t *testing.T) {
+	c := newTestSlashCase(t, 3000)
+
+	if err := c.Appeal(testProposer, []byte{0x01}, 200); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("Appeal() by proposer code = %d, want ERROR_UNAUTHORIZED", errCode(err))
+	}
+	if err := c.Appeal(testValidator, []byte{0x01}, 3701); errCode(err) != framework.ERROR_TIMEOUT {
+		t.Errorf("Appeal() after window code = %d, want ERROR_TIMEOUT", errCode(err))
+	}
+	if err := c.Appeal(testValidator, []byte{0x01}, 3700); err != nil {
+		t.Fatalf("Appeal() error = %v", err)
+	}
+	if err := c.Appeal(testValidator, []byte{0x02}, 3700); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Appeal() code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
+
+// TestNewSlashCaseValidation 测试提交证据的参数验证
+func TestNewSlashCaseValidation(t *testing.T) {
+	cases := []struct {
+		name      string
+		proposer  framework.Address
+		evidence  []byte
+		penaltyBP uint64
+	}{
+		{"self report", testValidator, []byte{0x01}, 1000},
+		{"empty evidence", testProposer, nil, 1000},
+		{"zero penalty", testProposer, []byte{0x01}, 0},
+		{"penalty above max", testProposer, []byte{0x01}, 5001},
+	}
+	for _, tc := range cases {
+		if _, err := NewSlashCase("slash_1", testValidator, tc.proposer, "double_sign", tc.evidence, tc.penaltyBP, testSlashConfig, 100); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: code = %d, want ERROR_INVALID_PARAMS", tc.name, errCode(err))
+		}
+	}
+}
+
+// TestComputeSlashRounding 测试取整尾差补足到罚没总额且不超过各自委托
+func TestComputeSlashRounding(t *testing.T) {
+	delegations := []DelegationBond{{testDelegatorA, 1}, {testDelegatorB, 1}, {framework.Address{5}, 1}}
+	res := ComputeSlash(0, delegations, 6667)
+	var sum framework.Amount
+	for i, d := range res.DelegationsSlashed {
+		if d.Amount > delegations[i].Amount {
+			t.Errorf("delegator %d slashed %d > bond %d", i, d.Amount, delegations[i].Amount)
+		}
+		sum += d.Amount
+	}
+	if res.Penalty != 2 || sum != res.Penalty {
+		t.Errorf("ComputeSlash() penalty = %d, distributed = %d, want 2", res.Penalty, sum)
+	}
+}
+
+// TestSlashCaseCodec 测试案件记录编码往返
+func TestSlashCaseCodec(t *testing.T) {
+	c := newTestSlashCase(t, 3000)
+	if err := c.Appeal(testValidator, []byte{0x09}, 200); err != nil {
+		t.Fatalf("Appeal() error = %v", err)
+	}
+	if _, err := ResolveSlashCase(newTestLedger(), c, true, 3701); err != nil {
+		t.Fatalf("ResolveSlashCase() error = %v", err)
+	}
+
+	decoded, err := decodeSlashCase(encodeSlashCase(c))
+	if err != nil {
+		t.Fatalf("decodeSlashCase() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Errorf("decodeSlashCase() = %+v, want %+v", decoded, c)
+	}
+	if _, err := decodeSlashCase(encodeSlashCase(c)[:60]); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("decodeSlashCase(truncated) code = %d, want ERROR_INVALID_STATE", errCode(err))
+	}
+}
@@ -13,7 +13,7 @@ import (
 // **参数**：
 //   - staker: 质押者地址
 //   - validator: 验证者地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - amount: 质押金额
 //
 // **返回**：
@@ -37,7 +37,7 @@ import (
 //	    err := staking.Stake(
 //	        caller,
 //	        validatorAddr,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(10000),
 //	    )
 //	    if err != nil {
@@ -13,7 +13,7 @@ import (
 // **参数**：
 //   - delegator: 委托者地址
 //   - validator: 验证者地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - amount: 取消委托金额（0表示全部取消）
 //
 // **返回**：
@@ -37,7 +37,7 @@ import (
 //	    err := staking.Undelegate(
 //	        caller,
 //	        validatorAddr,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(2000),  // 部分取消委托
 //	    )
 //	    if err != nil {
@@ -13,7 +13,7 @@ import (
 // **参数**：
 //   - staker: 质押者地址
 //   - validator: 验证者地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - amount: 解质押金额（0表示全部解质押）
 //
 // **返回**：
@@ -37,7 +37,7 @@ import (
 //	    err := staking.Unstake(
 //	        caller,
 //	        validatorAddr,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(5000),  // 部分解质押
 //	    )
 //	    if err != nil {
@@ -19,7 +19,7 @@ type AirdropRecipient struct {
 // **参数**：
 //   - from: 发送者地址
 //   - recipients: 接收者列表
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //
 // **返回**：
 //   - error: 错误信息，nil表示成功
@@ -52,8 +52,8 @@ func Airdrop(from framework.Address, recipients []AirdropRecipient, tokenID fram
 		totalAmount = totalAmount.Add(recipient.Amount)
 	}
 
-	// 3. 查询余额（通过framework）
-	balance := framework.QueryUTXOBalance(from, tokenID)
+	// 3. 查询有效余额（到期代币按 0 处理）
+	balance := spendableBalance(from, tokenID)
 	if balance < totalAmount {
 		return framework.NewContractError(
 			framework.ERROR_INSUFFICIENT_BALANCE,
@@ -61,7 +61,14 @@ func Airdrop(from framework.Address, recipients []AirdropRecipient, tokenID fram
 		)
 	}
 
-	// 4. 构建交易（使用internal包链式API）
+	// 4. 记录余额快照检查点（变化前余额）
+	for _, recipient := range recipients {
+		if err := checkpointBalance(recipient.Address, tokenID); err != nil {
+			return err
+		}
+	}
+
+	// 5. 构建交易（使用internal包链式API）
 	builder := framework.BeginTransaction()
 
 	// 添加所有接收者的输出
@@ -75,7 +82,7 @@ func Airdrop(from framework.Address, recipients []AirdropRecipient, tokenID fram
 		return framework.NewContractError(errCode, "airdrop failed")
 	}
 
-	// 5. 发出空投事件
+	// 6. 发出空投事件
 	event := framework.NewEvent("Airdrop")
 	event.AddAddressField("from", from)
 	event.AddStringField("token_id", string(tokenID))
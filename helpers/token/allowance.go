@@ -0,0 +1,204 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// IncreaseAllowance 在现有授权额度上增加
+//
+// 🎯 **用途**：调整授权额度，避免 Approve 直接覆盖额度时的抢跑问题
+// （被授权方抢在改额度交易之前花掉旧额度，之后再花新额度）
+//
+// **参数**：
+//   - owner: 代币所有者地址
+//   - spender: 被授权地址
+//   - tokenID: 代币ID
+//   - delta: 增加的额度
+//
+// **返回**：
+//   - framework.Amount: 调整后的授权额度
+//   - error: 错误信息，nil表示成功
+//
+// **注意**：
+//   - 在同一次调用中读取当前额度（授权索引 approvals_{owner}）并写入新额度
+//   - 调整后的额度不能超过所有者余额，溢出返回 ERROR_INVALID_PARAMS
+//   - 发出 Approve 事件，amount 为调整后的额度
+//
+// **示例**：
+//
+//	allowance, err := token.IncreaseAllowance(caller, spender, framework.TokenID("my_token"), framework.Amount(500))
+func IncreaseAllowance(owner, spender framework.Address, tokenID framework.TokenID, delta framework.Amount) (framework.Amount, error) {
+	// 1. 参数验证
+	if err := validateApproveParams(owner, spender, tokenID, delta); err != nil {
+		return 0, err
+	}
+
+	// 2. 读取当前额度并计算新额度
+	current, err := approvalIndex.Get(owner, spender, tokenID)
+	if err != nil {
+		return 0, err
+	}
+	allowance, err := increasedAllowance(current, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. 查询有效余额（到期代币按 0 处理）
+	if spendableBalance(owner, tokenID) < allowance {
+		return 0, framework.NewContractError(
+			framework.ERROR_INSUFFICIENT_BALANCE,
+			"insufficient balance to approve",
+		)
+	}
+
+	// 4. 记录授权
+	if err := writeApproval(owner, spender, tokenID, allowance); err != nil {
+		return 0, err
+	}
+	return allowance, nil
+}
+
+// DecreaseAllowance 在现有授权额度上减少，最低减至 0
+//
+// 🎯 **用途**：与 IncreaseAllowance 配对，减少授权额度而不覆盖写入
+//
+// **参数**：
+//   - owner: 代币所有者地址
+//   - spender: 被授权地址
+//   - tokenID: 代币ID
+//   - delta: 减少的额度，超过当前额度时授权减至 0（撤销）
+//
+// **返回**：
+//   - framework.Amount: 调整后的授权额度
+//   - error: 错误信息，nil表示成功
+//
+// **注意**：
+//   - 减少额度不检查余额
+//   - 减至 0 时从授权索引中移除；原本未授权时不写入状态也不发出事件
+//
+// **示例**：
+//
+//	allowance, err := token.DecreaseAllowance(caller, spender, framework.TokenID("my_token"), framework.Amount(200))
+func DecreaseAllowance(owner, spender framework.Address, tokenID framework.TokenID, delta framework.Amount) (framework.Amount, error) {
+	// 1. 参数验证
+	if err := validateApproveParams(owner, spender, tokenID, delta); err != nil {
+		return 0, err
+	}
+
+	// 2. 读取当前额度并计算新额度
+	current, err := approvalIndex.Get(owner, spender, tokenID)
+	if err != nil {
+		return 0, err
+	}
+	if current == 0 {
+		return 0, nil
+	}
+	allowance := decreasedAllowance(current, delta)
+
+	// 3. 记录授权
+	if err := writeApproval(owner, spender, tokenID, allowance); err != nil {
+		return 0, err
+	}
+	return allowance, nil
+}
+
+// Allowance 查询 spender 对所有者代币的当前授权额度
+//
+// **返回**：未授权或已撤销时为 0；读取授权索引失败时同样返回 0
+//
+// **注意**：
+//   - 授权状态 approve:{owner}:{spender}:{tokenID} 只记录授权哈希，额度从授权索引 approvals_{owner} 读取；
+//     Approve、IncreaseAllowance、DecreaseAllowance 与 TransferFrom 都通过 storeApproval 同时写入两者
+//   - 再次 Approve 覆盖原额度（不累加），在原额度上调整使用 IncreaseAllowance / DecreaseAllowance
+//
+// **示例**：
+//
+//	remaining := token.Allowance(owner, spender, framework.TokenID("my_token"))
+func Allowance(owner, spender framework.Address, tokenID framework.TokenID) framework.Amount {
+	allowance, err := approvalIndex.Get(owner, spender, tokenID)
+	if err != nil {
+		return 0
+	}
+	return allowance
+}
+
+// TransferFrom 被授权方代所有者转账，并扣减授权额度
+//
+// 🎯 **用途**：ERC-20 风格的代理转账——所有者先 Approve，被授权方（交易所、订阅扣款合约等）
+// 在额度内从所有者地址转出代币
+//
+// **参数**：
+//   - owner: 代币所有者地址，从其 UTXO 中转出
+//   - spender: 被授权地址（通常为调用者）
+//   - to: 接收者地址
+//   - tokenID: 代币ID
+//   - amount: 转账金额，从授权额度中扣减
+//
+// **返回**：
+//   - error: 错误信息，nil表示成功
+//
+// **注意**：
+//   - 授权额度不足（含未授权）返回 ERROR_UNAUTHORIZED；额度足够但所有者余额不足返回 ERROR_INSUFFICIENT_BALANCE
+//   - 转账与 Transfer 相同（转账钩子、手续费、Rebase 换算），发出 Transfer 事件；手续费计入 amount，不另外扣减额度
+//   - 转账成功后写入扣减后的额度（额度为 0 时从授权索引中移除），依次发出 ApprovalSpent
+//     （owner / spender / token_id / amount / remaining_allowance）与 TransferFrom
+//     （spender / owner / to / token_id / amount / remaining_allowance）事件；扣减额度不发出 Approve 事件
+//
+// **示例**：
+//
+//	err := token.TransferFrom(owner, framework.GetCaller(), to, framework.TokenID("my_token"), framework.Amount(100))
+//	if err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func TransferFrom(owner, spender, to framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	// 1. 参数验证
+	if err := validateApproveParams(owner, spender, tokenID, amount); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than zero")
+	}
+
+	// 2. 读取授权额度并计算扣减后的额度
+	current, err := approvalIndex.Get(owner, spender, tokenID)
+	if err != nil {
+		return err
+	}
+	remaining, err := consumedAllowance(current, amount)
+	if err != nil {
+		return err
+	}
+
+	// 3. 从所有者地址转账（余额不足返回 ERROR_INSUFFICIENT_BALANCE）
+	if err := Transfer(owner, to, tokenID, amount); err != nil {
+		return err
+	}
+
+	// 4. 写入扣减后的额度
+	if err := storeApproval(owner, spender, tokenID, remaining); err != nil {
+		return err
+	}
+
+	// 5. 发出额度消耗事件（索引器据此与 Approve 事件一起跟踪当前额度）
+	spent := framework.NewEvent("ApprovalSpent")
+	spent.AddAddressField("owner", owner)
+	spent.AddAddressField("spender", spender)
+	spent.AddStringField("token_id", string(tokenID))
+	spent.AddUint64Field("amount", uint64(amount))
+	spent.AddUint64Field("remaining_allowance", uint64(remaining))
+	framework.EmitEvent(spent)
+
+	// 6. 发出代理转账事件
+	event := framework.NewEvent("TransferFrom")
+	event.AddAddressField("spender", spender)
+	event.AddAddressField("owner", owner)
+	event.AddAddressField("to", to)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("amount", uint64(amount))
+	event.AddUint64Field("remaining_allowance", uint64(remaining))
+	framework.EmitEvent(event)
+
+	return nil
+}
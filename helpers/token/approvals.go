@@ -0,0 +1,256 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 授权索引 ====================
+//
+// 授权状态 approve:{owner}:{spender}:{tokenID} 只记录授权哈希，无法按所有者枚举。
+// 本文件维护每个所有者的授权索引 approvals_{owner}，记录当前有效的 (spender, tokenID, amount)：
+//   - 授权额度为 0 时从索引中移除
+//   - 同一 (spender, tokenID) 再次授权时原位更新额度
+//
+// 本文件不带 build tag，索引逻辑可在非WASM环境中直接测试；
+// 宿主存储与包级函数（ListApprovals）见 approve.go，批量授权（BatchApprove）见 batch_approve.go，
+// 增减授权（IncreaseAllowance / DecreaseAllowance）与代理转账（TransferFrom / Allowance）见 allowance.go。
+
+const (
+	// approvalsStatePrefix 授权索引状态ID前缀，完整格式：approvals_{owner}
+	approvalsStatePrefix = "approvals_"
+
+	// approvalsFormat 索引记录的格式字节（空索引也保留该字节）
+	approvalsFormat byte = 1
+	// approvalEntryFixedSize 单条授权的定长部分：spender(20) + amount(8) + tokenID长度(2)
+	approvalEntryFixedSize = 20 + 8 + 2
+	// maxApprovalTokenIDLength 索引中代币ID的最大长度
+	maxApprovalTokenIDLength = 0xFFFF
+)
+
+// Approval 一条有效授权
+type Approval struct {
+	// Spender 被授权地址
+	Spender framework.Address
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Amount 授权额度
+	Amount framework.Amount
+}
+
+// ApprovalIndex 按所有者维护的授权索引
+type ApprovalIndex struct {
+	store subaccount.Store
+}
+
+// NewApprovalIndex 创建使用指定存储后端的授权索引
+func NewApprovalIndex(store subaccount.Store) *ApprovalIndex {
+	return &ApprovalIndex{store: store}
+}
+
+// ApproveEntry 批量授权中的一项（见 BatchApprove）
+type ApproveEntry struct {
+	// Spender 被授权地址
+	Spender framework.Address
+	// Amount 授权额度，为 0 时撤销该授权
+	Amount framework.Amount
+}
+
+// Set 设置授权额度
+//
+// **参数**：
+//   - owner: 代币所有者
+//   - spender: 被授权地址
+//   - tokenID: 代币ID
+//   - amount: 授权额度，为 0 时从索引中移除该授权
+//
+// **返回**：
+//   - error: 代币ID为空或过长返回 ERROR_INVALID_PARAMS
+//
+// **注意**：索引未变化（如移除不存在的授权）时不写入状态
+func (ix *ApprovalIndex) Set(owner, spender framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	return ix.SetBatch(owner, tokenID, []ApproveEntry{{Spender: spender, Amount: amount}})
+}
+
+// SetBatch 按顺序设置同一代币的多个授权额度，索引只写入一次
+//
+// **参数**：
+//   - owner: 代币所有者
+//   - tokenID: 代币ID
+//   - entries: 授权项，额度为 0 的项从索引中移除
+//
+// **返回**：
+//   - error: 代币ID为空或过长返回 ERROR_INVALID_PARAMS
+//
+// **注意**：索引未变化时不写入状态
+func (ix *ApprovalIndex) SetBatch(owner framework.Address, tokenID framework.TokenID, entries []ApproveEntry) error {
+	if framework.IsNativeToken(tokenID) || len(tokenID) > maxApprovalTokenIDLength {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid tokenID for approval index")
+	}
+
+	key := ApprovalsStateID(owner)
+	data, version, err := ix.store.Load(key)
+	if err != nil {
+		return err
+	}
+	approvals := decodeApprovals(data)
+
+	changed := false
+	for _, e := range entries {
+		var entryChanged bool
+		approvals, entryChanged = applyApproval(approvals, e.Spender, tokenID, e.Amount)
+		changed = changed || entryChanged
+	}
+	if !changed {
+		return nil
+	}
+	return ix.store.Save(key, version+1, encodeApprovals(approvals))
+}
+
+// applyApproval 在授权列表中设置一项额度：已有授权原位更新，额度为 0 时移除，新授权追加到末尾
+func applyApproval(approvals []Approval, spender framework.Address, tokenID framework.TokenID, amount framework.Amount) ([]Approval, bool) {
+	updated := make([]Approval, 0, len(approvals)+1)
+	found, changed := false, false
+	for _, a := range approvals {
+		if a.Spender != spender || a.TokenID != tokenID {
+			updated = append(updated, a)
+			continue
+		}
+		found = true
+		if amount == 0 {
+			changed = true
+			continue
+		}
+		changed = changed || a.Amount != amount
+		a.Amount = amount
+		updated = append(updated, a)
+	}
+	if !found && amount > 0 {
+		updated = append(updated, Approval{Spender: spender, TokenID: tokenID, Amount: amount})
+		changed = true
+	}
+	return updated, changed
+}
+
+// validateBatchApproveEntries 验证批量授权项：非空、被授权地址非零且不同于所有者、不重复
+func validateBatchApproveEntries(owner framework.Address, entries []ApproveEntry) error {
+	if len(entries) == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "approve entries cannot be empty")
+	}
+	zeroAddr := framework.Address{}
+	for i, e := range entries {
+		if e.Spender == zeroAddr {
+			return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "spender address cannot be zero")
+		}
+		if e.Spender == owner {
+			return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "owner and spender addresses cannot be the same")
+		}
+		for j := i + 1; j < len(entries); j++ {
+			if e.Spender == entries[j].Spender {
+				return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "duplicate spender address")
+			}
+		}
+	}
+	return nil
+}
+
+// List 返回所有者当前的全部有效授权，按首次授权顺序排列
+func (ix *ApprovalIndex) List(owner framework.Address) ([]Approval, error) {
+	data, _, err := ix.store.Load(ApprovalsStateID(owner))
+	if err != nil {
+		return nil, err
+	}
+	return decodeApprovals(data), nil
+}
+
+// Get 返回 spender 对所有者代币 tokenID 的当前授权额度，未授权时为 0
+func (ix *ApprovalIndex) Get(owner, spender framework.Address, tokenID framework.TokenID) (framework.Amount, error) {
+	approvals, err := ix.List(owner)
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range approvals {
+		if a.Spender == spender && a.TokenID == tokenID {
+			return a.Amount, nil
+		}
+	}
+	return 0, nil
+}
+
+// increasedAllowance 在当前额度上增加 delta，溢出返回 ERROR_INVALID_PARAMS
+func increasedAllowance(current, delta framework.Amount) (framework.Amount, error) {
+	if current+delta < current {
+		return 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "allowance overflow")
+	}
+	return current + delta, nil
+}
+
+// decreasedAllowance 在当前额度上减少 delta，最低为 0
+func decreasedAllowance(current, delta framework.Amount) framework.Amount {
+	if delta >= current {
+		return 0
+	}
+	return current - delta
+}
+
+// consumedAllowance 从当前额度中扣减 amount（TransferFrom 使用），返回剩余额度
+//
+// 额度不足（含未授权）时返回 ERROR_UNAUTHORIZED
+func consumedAllowance(current, amount framework.Amount) (framework.Amount, error) {
+	if amount > current {
+		return 0, framework.NewContractError(framework.ERROR_UNAUTHORIZED, "insufficient allowance")
+	}
+	return current - amount, nil
+}
+
+// ApprovalsStateID 返回所有者授权索引的状态ID
+func ApprovalsStateID(owner framework.Address) string {
+	return approvalsStatePrefix + owner.ToString()
+}
+
+// encodeApprovals 编码：格式字节 + 逐条 [spender(20) | amount(8,大端) | len(tokenID)(2,大端) | tokenID]
+//
+// 每条记录以非空的代币ID结尾，链上读取去掉尾部零字节不会截断记录
+func encodeApprovals(approvals []Approval) []byte {
+	size := 1
+	for _, a := range approvals {
+		size += approvalEntryFixedSize + len(a.TokenID)
+	}
+	data := make([]byte, 0, size)
+	data = append(data, approvalsFormat)
+	for _, a := range approvals {
+		data = append(data, a.Spender[:]...)
+		for i := 7; i >= 0; i-- {
+			data = append(data, byte(uint64(a.Amount)>>(uint(i)*8)))
+		}
+		data = append(data, byte(len(a.TokenID)>>8), byte(len(a.TokenID)))
+		data = append(data, a.TokenID...)
+	}
+	return data
+}
+
+// decodeApprovals 解码授权索引；记录不存在、格式不符或截断的条目被忽略
+func decodeApprovals(data []byte) []Approval {
+	if len(data) == 0 || data[0] != approvalsFormat {
+		return nil
+	}
+	var approvals []Approval
+	for pos := 1; pos+approvalEntryFixedSize <= len(data); {
+		var a Approval
+		copy(a.Spender[:], data[pos:pos+20])
+		var amount uint64
+		for _, b := range data[pos+20 : pos+28] {
+			amount = amount<<8 | uint64(b)
+		}
+		a.Amount = framework.Amount(amount)
+		n := int(data[pos+28])<<8 | int(data[pos+29])
+		pos += approvalEntryFixedSize
+		if n == 0 || pos+n > len(data) {
+			break
+		}
+		a.TokenID = framework.TokenID(data[pos : pos+n])
+		pos += n
+		approvals = append(approvals, a)
+	}
+	return approvals
+}
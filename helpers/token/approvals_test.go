@@ -0,0 +1,316 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// TestApprovalIndexMultiple 测试多个被授权地址、多个代币按首次授权顺序列出，不同所有者互不影响
+func TestApprovalIndexMultiple(t *testing.T) {
+	ix := NewApprovalIndex(subaccount.NewMemoryStore())
+	owner := fixtures.Alice()
+
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 1000)
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 250)
+	mustSet(t, ix, owner, fixtures.Bob(), "WES", 7)
+	mustSet(t, ix, fixtures.Bob(), fixtures.Carol(), "USDT", 99)
+
+	assertApprovals(t, ix, owner, []Approval{
+		{Spender: fixtures.Bob(), TokenID: "USDT", Amount: 1000},
+		{Spender: fixtures.Carol(), TokenID: "USDT", Amount: 250},
+		{Spender: fixtures.Bob(), TokenID: "WES", Amount: 7},
+	})
+	assertApprovals(t, ix, fixtures.Bob(), []Approval{
+		{Spender: fixtures.Carol(), TokenID: "USDT", Amount: 99},
+	})
+	assertApprovals(t, ix, fixtures.Carol(), nil)
+}
+
+// TestApprovalIndexUpdate 测试再次授权原位更新额度，不产生重复条目
+func TestApprovalIndexUpdate(t *testing.T) {
+	store := subaccount.NewMemoryStore()
+	ix := NewApprovalIndex(store)
+	owner := fixtures.Alice()
+
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 1000)
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 250)
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 1<<40)
+
+	assertApprovals(t, ix, owner, []Approval{
+		{Spender: fixtures.Bob(), TokenID: "USDT", Amount: 1 << 40},
+		{Spender: fixtures.Carol(), TokenID: "USDT", Amount: 250},
+	})
+
+	// 额度不变时不写入新版本
+	_, version, _ := store.Load(ApprovalsStateID(owner))
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 250)
+	if _, again, _ := store.Load(ApprovalsStateID(owner)); again != version {
+		t.Errorf("unchanged approval bumped version %d -> %d", version, again)
+	}
+}
+
+// TestApprovalIndexZeroing 测试额度设为 0 时从索引中移除，移除不存在的授权不写入状态
+func TestApprovalIndexZeroing(t *testing.T) {
+	store := subaccount.NewMemoryStore()
+	ix := NewApprovalIndex(store)
+	owner := fixtures.Alice()
+
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 1000)
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 250)
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 0)
+	assertApprovals(t, ix, owner, []Approval{
+		{Spender: fixtures.Carol(), TokenID: "USDT", Amount: 250},
+	})
+
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 0)
+	assertApprovals(t, ix, owner, nil)
+
+	// 已清空后重新授权
+	mustSet(t, ix, owner, fixtures.Bob(), "USDT", 5)
+	assertApprovals(t, ix, owner, []Approval{{Spender: fixtures.Bob(), TokenID: "USDT", Amount: 5}})
+
+	mustSet(t, ix, fixtures.Carol(), fixtures.Bob(), "USDT", 0)
+	if data, version, _ := store.Load(ApprovalsStateID(fixtures.Carol())); data != nil || version != 0 {
+		t.Errorf("zeroing a missing approval wrote state: %x v%d", data, version)
+	}
+
+	if err := ix.Set(owner, fixtures.Bob(), "", 1); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Set() with empty tokenID error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+}
+
+// TestApprovalIndexSetBatch 测试批量设置多个授权只写入一次，额度为 0 的项撤销对应授权
+func TestApprovalIndexSetBatch(t *testing.T) {
+	store := subaccount.NewMemoryStore()
+	ix := NewApprovalIndex(store)
+	owner := fixtures.Alice()
+	mustSet(t, ix, owner, fixtures.Carol(), "USDT", 250)
+	mustSet(t, ix, owner, fixtures.Carol(), "WES", 9)
+	_, before, _ := store.Load(ApprovalsStateID(owner))
+
+	err := ix.SetBatch(owner, "USDT", []ApproveEntry{
+		{Spender: fixtures.Bob(), Amount: 1000},
+		{Spender: fixtures.Carol(), Amount: 0},
+		{Spender: fixtures.Operator(), Amount: 30},
+	})
+	if err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	assertApprovals(t, ix, owner, []Approval{
+		{Spender: fixtures.Carol(), TokenID: "WES", Amount: 9},
+		{Spender: fixtures.Bob(), TokenID: "USDT", Amount: 1000},
+		{Spender: fixtures.Operator(), TokenID: "USDT", Amount: 30},
+	})
+	if _, after, _ := store.Load(ApprovalsStateID(owner)); after != before+1 {
+		t.Errorf("SetBatch() bumped version %d -> %d, want one write", before, after)
+	}
+
+	// 全部为撤销不存在的授权时不写入状态
+	if err := ix.SetBatch(owner, "USDT", []ApproveEntry{{Spender: fixtures.Carol(), Amount: 0}}); err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	if _, again, _ := store.Load(ApprovalsStateID(owner)); again != before+1 {
+		t.Errorf("no-op SetBatch() bumped version to %d", again)
+	}
+}
+
+// TestValidateBatchApproveEntries 测试批量授权项校验
+func TestValidateBatchApproveEntries(t *testing.T) {
+	owner := fixtures.Alice()
+	valid := []ApproveEntry{{Spender: fixtures.Bob(), Amount: 1}, {Spender: fixtures.Carol(), Amount: 0}}
+	if err := validateBatchApproveEntries(owner, valid); err != nil {
+		t.Errorf("valid entries error = %v", err)
+	}
+	for name, entries := range map[string][]ApproveEntry{
+		"empty":             nil,
+		"zero spender":      {{Spender: framework.Address{}, Amount: 1}},
+		"owner as spender":  {{Spender: owner, Amount: 1}},
+		"duplicate spender": {{Spender: fixtures.Bob(), Amount: 1}, {Spender: fixtures.Bob(), Amount: 0}},
+	} {
+		if err := validateBatchApproveEntries(owner, entries); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: error = %v, want ERROR_INVALID_PARAMS", name, err)
+		}
+	}
+}
+
+// TestAllowanceAdjust 测试从零增加、在已有额度上增加、减少超过当前额度时减至 0
+func TestAllowanceAdjust(t *testing.T) {
+	ix := NewApprovalIndex(subaccount.NewMemoryStore())
+	owner, spender := fixtures.Alice(), fixtures.Bob()
+
+	adjust := func(delta framework.Amount, increase bool) framework.Amount {
+		t.Helper()
+		current, err := ix.Get(owner, spender, "USDT")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		var next framework.Amount
+		if increase {
+			if next, err = increasedAllowance(current, delta); err != nil {
+				t.Fatalf("increasedAllowance(%d, %d) error = %v", current, delta, err)
+			}
+		} else {
+			next = decreasedAllowance(current, delta)
+		}
+		mustSet(t, ix, owner, spender, "USDT", next)
+		return next
+	}
+
+	if got := adjust(300, true); got != 300 {
+		t.Errorf("increase from zero = %d, want 300", got)
+	}
+	if got := adjust(200, true); got != 500 {
+		t.Errorf("increase from existing = %d, want 500", got)
+	}
+	if got := adjust(100, false); got != 400 {
+		t.Errorf("decrease = %d, want 400", got)
+	}
+	if got := adjust(1000, false); got != 0 {
+		t.Errorf("decrease below zero = %d, want 0", got)
+	}
+	assertApprovals(t, ix, owner, nil)
+
+	if _, err := increasedAllowance(^framework.Amount(0), 1); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("increasedAllowance(max, 1) error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if got := decreasedAllowance(0, 1); got != 0 {
+		t.Errorf("decreasedAllowance(0, 1) = %d, want 0", got)
+	}
+}
+
+// TestApproveOverwritesAllowance 测试未授权时额度为 0，再次授权覆盖原额度，增加授权在原额度上累加
+func TestApproveOverwritesAllowance(t *testing.T) {
+	ix := NewApprovalIndex(subaccount.NewMemoryStore())
+	owner, spender := fixtures.Alice(), fixtures.Bob()
+
+	allowance := func() framework.Amount {
+		t.Helper()
+		got, err := ix.Get(owner, spender, "USDT")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return got
+	}
+
+	if got := allowance(); got != 0 {
+		t.Fatalf("allowance without approval = %d, want 0", got)
+	}
+
+	// 1. Approve 语义：直接写入新额度，更大或更小都覆盖
+	mustSet(t, ix, owner, spender, "USDT", 1000)
+	mustSet(t, ix, owner, spender, "USDT", 300)
+	if got := allowance(); got != 300 {
+		t.Errorf("allowance after approving 1000 then 300 = %d, want 300 (overwrite, not 1300)", got)
+	}
+	mustSet(t, ix, owner, spender, "USDT", 800)
+	if got := allowance(); got != 800 {
+		t.Errorf("allowance after approving 800 = %d, want 800", got)
+	}
+
+	// 2. IncreaseAllowance 语义：在原额度上累加
+	next, err := increasedAllowance(allowance(), 200)
+	if err != nil {
+		t.Fatalf("increasedAllowance() error = %v", err)
+	}
+	mustSet(t, ix, owner, spender, "USDT", next)
+	if got := allowance(); got != 1000 {
+		t.Errorf("allowance after increasing by 200 = %d, want 1000", got)
+	}
+
+	// 3. 其他代币与被授权地址不受影响
+	if got, _ := ix.Get(owner, spender, "WES"); got != 0 {
+		t.Errorf("allowance for other token = %d, want 0", got)
+	}
+	if got, _ := ix.Get(owner, fixtures.Carol(), "USDT"); got != 0 {
+		t.Errorf("allowance for other spender = %d, want 0", got)
+	}
+}
+
+// TestConsumedAllowance 测试代理转账扣减额度：额度内扣减、恰好用完、超出额度与未授权返回 ERROR_UNAUTHORIZED
+func TestConsumedAllowance(t *testing.T) {
+	ix := NewApprovalIndex(subaccount.NewMemoryStore())
+	owner, spender := fixtures.Alice(), fixtures.Bob()
+	mustSet(t, ix, owner, spender, "USDT", 500)
+
+	consume := func(amount framework.Amount) (framework.Amount, error) {
+		t.Helper()
+		current, err := ix.Get(owner, spender, "USDT")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		remaining, err := consumedAllowance(current, amount)
+		if err == nil {
+			mustSet(t, ix, owner, spender, "USDT", remaining)
+		}
+		return remaining, err
+	}
+
+	if got, err := consume(200); err != nil || got != 300 {
+		t.Errorf("consume(200) = %d, %v, want 300", got, err)
+	}
+	if _, err := consume(301); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("consume over allowance error = %v, want ERROR_UNAUTHORIZED", err)
+	}
+	if got, _ := ix.Get(owner, spender, "USDT"); got != 300 {
+		t.Errorf("allowance after rejected consume = %d, want 300", got)
+	}
+	if got, err := consume(300); err != nil || got != 0 {
+		t.Errorf("consume(300) = %d, %v, want 0", got, err)
+	}
+	assertApprovals(t, ix, owner, nil)
+	if _, err := consumedAllowance(0, 1); errCode(err) != framework.ERROR_UNAUTHORIZED {
+		t.Errorf("consume without approval error = %v, want ERROR_UNAUTHORIZED", err)
+	}
+}
+
+// TestApprovalsEncodingSurvivesTrailingZeroTrim 测试链上读取去掉尾部零字节后索引仍可完整解码
+func TestApprovalsEncodingSurvivesTrailingZeroTrim(t *testing.T) {
+	approvals := []Approval{
+		{Spender: framework.Address{0x01}, TokenID: "A", Amount: 0x0100},
+		{Spender: fixtures.Bob(), TokenID: DeriveTokenID("LP", "USDT", "WES"), Amount: 1},
+	}
+	data := encodeApprovals(approvals)
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	got := decodeApprovals(data)
+	if len(got) != len(approvals) {
+		t.Fatalf("decodeApprovals() = %v, want %v", got, approvals)
+	}
+	for i := range approvals {
+		if got[i] != approvals[i] {
+			t.Errorf("approval %d = %+v, want %+v", i, got[i], approvals[i])
+		}
+	}
+	if got := decodeApprovals(encodeApprovals(nil)); len(got) != 0 {
+		t.Errorf("empty index decoded to %v", got)
+	}
+}
+
+func mustSet(t *testing.T, ix *ApprovalIndex, owner, spender framework.Address, tokenID framework.TokenID, amount framework.Amount) {
+	t.Helper()
+	if err := ix.Set(owner, spender, tokenID, amount); err != nil {
+		t.Fatalf("Set(%s, %s, %s, %d) error = %v", fixtures.Name(owner), fixtures.Name(spender), tokenID, amount, err)
+	}
+}
+
+func assertApprovals(t *testing.T, ix *ApprovalIndex, owner framework.Address, want []Approval) {
+	t.Helper()
+	got, err := ix.List(owner)
+	if err != nil {
+		t.Fatalf("List(%s) error = %v", fixtures.Name(owner), err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List(%s) = %+v, want %+v", fixtures.Name(owner), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("List(%s)[%d] = {%s %s %d}, want {%s %s %d}", fixtures.Name(owner), i,
+				fixtures.Name(got[i].Spender), got[i].TokenID, got[i].Amount,
+				fixtures.Name(want[i].Spender), want[i].TokenID, want[i].Amount)
+		}
+	}
+}
@@ -14,7 +14,7 @@ import (
 //   - owner: 代币所有者地址
 //   - spender: 被授权地址
 //   - tokenID: 代币ID
-//   - amount: 授权数量
+//   - amount: 授权数量，为 0 时撤销授权
 //
 // **返回**：
 //   - error: 错误信息，nil表示成功
@@ -22,6 +22,7 @@ import (
 // **注意**：
 //   - 授权信息需要存储在合约状态中
 //   - 需要使用StateOutput来记录授权状态
+//   - 同时更新所有者的授权索引 approvals_{owner}（见 ListApprovals），额度为 0 时从索引中移除
 //
 // **示例**：
 //
@@ -45,8 +46,8 @@ func Approve(owner, spender framework.Address, tokenID framework.TokenID, amount
 		return err
 	}
 
-	// 2. 查询余额（通过framework）
-	balance := framework.QueryUTXOBalance(owner, tokenID)
+	// 2. 查询有效余额（到期代币按 0 处理）
+	balance := spendableBalance(owner, tokenID)
 	if balance < amount {
 		return framework.NewContractError(
 			framework.ERROR_INSUFFICIENT_BALANCE,
@@ -54,15 +55,40 @@ func Approve(owner, spender framework.Address, tokenID framework.TokenID, amount
 		)
 	}
 
-	// 3. 构建授权状态ID
+	// 3. 记录授权状态、更新授权索引并发出事件
+	return writeApproval(owner, spender, tokenID, amount)
+}
+
+// writeApproval 记录授权额度：授权状态输出、授权索引与 Approve 事件
+//
+// 调用方负责参数与余额校验（见 Approve、IncreaseAllowance、DecreaseAllowance）
+func writeApproval(owner, spender framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	if err := storeApproval(owner, spender, tokenID, amount); err != nil {
+		return err
+	}
+
+	// 发出授权事件
+	event := framework.NewEvent("Approve")
+	event.AddAddressField("owner", owner)
+	event.AddAddressField("spender", spender)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("amount", uint64(amount))
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// storeApproval 写入授权状态输出并更新授权索引，不发出事件（TransferFrom 扣减额度时使用）
+func storeApproval(owner, spender framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
+	// 1. 构建授权状态ID
 	// 格式：approve:{owner}:{spender}:{tokenID}
 	stateID := buildApproveStateID(owner, spender, tokenID)
 
-	// 4. 计算授权状态哈希
+	// 2. 计算授权状态哈希
 	// 使用状态ID和金额构建哈希，用于StateOutput的execHash字段
 	execHash := computeApproveHash(stateID, amount)
 
-	// 5. 构建交易（使用internal包链式API）
+	// 3. 构建交易（使用internal包链式API）
 	// 使用StateOutput记录授权状态
 	success, _, errCode := framework.BeginTransaction().
 		AddStateOutput(stateID, 1, execHash).
@@ -72,15 +98,33 @@ func Approve(owner, spender framework.Address, tokenID framework.TokenID, amount
 		return framework.NewContractError(errCode, "approve failed")
 	}
 
-	// 6. 发出授权事件
-	event := framework.NewEvent("Approve")
-	event.AddAddressField("owner", owner)
-	event.AddAddressField("spender", spender)
-	event.AddStringField("token_id", string(tokenID))
-	event.AddUint64Field("amount", uint64(amount))
-	framework.EmitEvent(event)
+	// 4. 更新授权索引
+	return approvalIndex.Set(owner, spender, tokenID, amount)
+}
 
-	return nil
+// approvalIndex 基于链上状态的授权索引
+var approvalIndex = NewApprovalIndex(hostStateStore{})
+
+// ListApprovals 查询所有者当前的全部有效授权
+//
+// 🎯 **用途**：所有者查看已授权的地址与额度（如钱包展示、批量撤销前确认）
+//
+// **参数**：
+//   - owner: 代币所有者地址
+//
+// **返回**：按首次授权顺序排列的授权列表，额度已撤销（为 0）的授权不包含在内
+//
+// **示例**：
+//
+//	for _, a := range token.ListApprovals(caller) {
+//	    // a.Spender, a.TokenID, a.Amount
+//	}
+func ListApprovals(owner framework.Address) []Approval {
+	approvals, err := approvalIndex.List(owner)
+	if err != nil {
+		return nil
+	}
+	return approvals
 }
 
 // validateApproveParams 验证授权参数
@@ -107,21 +151,14 @@ func validateApproveParams(owner, spender framework.Address, tokenID framework.T
 	}
 
 	// 验证代币ID
-	if tokenID == "" {
+	if framework.IsNativeToken(tokenID) {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"tokenID cannot be empty",
 		)
 	}
 
-	// 验证金额
-	if amount == 0 {
-		return framework.NewContractError(
-			framework.ERROR_INVALID_PARAMS,
-			"amount must be greater than 0",
-		)
-	}
-
+	// 金额为 0 表示撤销授权，不做限制
 	return nil
 }
 
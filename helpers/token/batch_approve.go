@@ -0,0 +1,101 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// BatchApprove 批量授权
+//
+// 🎯 **用途**：在一次交易中为多个地址设置同一代币的授权额度
+//
+// **参数**：
+//   - owner: 代币所有者地址
+//   - entries: 授权项列表，每项包含被授权地址和额度；额度为 0 时撤销该地址的授权
+//   - tokenID: 代币ID
+//
+// **返回**：
+//   - error: 错误信息，nil表示成功
+//
+// **注意**：
+//   - 被授权地址不能为零地址或所有者本人，且不能重复
+//   - 每项额度单独检查不超过所有者余额（授权额度不累加）
+//   - 每项发出一个 Approve 事件，字段与 Approve 相同
+//   - 授权索引 approvals_{owner} 只写入一次（见 ListApprovals）
+//
+// **示例**：
+//
+//	func BatchApprove() uint32 {
+//	    caller := framework.GetCaller()
+//
+//	    entries := []token.ApproveEntry{
+//	        {Spender: addr1, Amount: framework.Amount(1000)},
+//	        {Spender: addr2, Amount: framework.Amount(500)},
+//	        {Spender: addr3, Amount: 0}, // 撤销 addr3 的授权
+//	    }
+//
+//	    err := token.BatchApprove(caller, entries, framework.TokenID("my_token"))
+//	    if err != nil {
+//	        return framework.ERROR_EXECUTION_FAILED
+//	    }
+//	    return framework.SUCCESS
+//	}
+func BatchApprove(owner framework.Address, entries []ApproveEntry, tokenID framework.TokenID) error {
+	// 1. 参数验证
+	if owner == (framework.Address{}) {
+		return framework.NewContractError(
+			framework.ERROR_INVALID_PARAMS,
+			"owner address cannot be zero",
+		)
+	}
+	if framework.IsNativeToken(tokenID) {
+		return framework.NewContractError(
+			framework.ERROR_INVALID_PARAMS,
+			"tokenID cannot be empty",
+		)
+	}
+	if err := validateBatchApproveEntries(owner, entries); err != nil {
+		return err
+	}
+
+	// 2. 查询有效余额（到期代币按 0 处理），每项额度均不能超过余额
+	balance := spendableBalance(owner, tokenID)
+	for _, e := range entries {
+		if balance < e.Amount {
+			return framework.NewContractError(
+				framework.ERROR_INSUFFICIENT_BALANCE,
+				"insufficient balance to approve",
+			)
+		}
+	}
+
+	// 3. 构建交易：每项一个授权状态输出
+	builder := framework.BeginTransaction()
+	for _, e := range entries {
+		stateID := buildApproveStateID(owner, e.Spender, tokenID)
+		builder.AddStateOutput(stateID, 1, computeApproveHash(stateID, e.Amount))
+	}
+
+	success, _, errCode := builder.Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "batch approve failed")
+	}
+
+	// 4. 更新授权索引
+	if err := approvalIndex.SetBatch(owner, tokenID, entries); err != nil {
+		return err
+	}
+
+	// 5. 逐项发出授权事件
+	for _, e := range entries {
+		event := framework.NewEvent("Approve")
+		event.AddAddressField("owner", owner)
+		event.AddAddressField("spender", e.Spender)
+		event.AddStringField("token_id", string(tokenID))
+		event.AddUint64Field("amount", uint64(e.Amount))
+		framework.EmitEvent(event)
+	}
+
+	return nil
+}
@@ -48,8 +48,18 @@ func BatchMint(recipients []MintRecipient, tokenID framework.TokenID) error {
 	if err := validateBatchMintParams(recipients, tokenID); err != nil {
 		return err
 	}
+	if err := checkClassMint(tokenID); err != nil {
+		return err
+	}
+
+	// 2. 记录余额快照检查点（变化前余额）
+	for _, recipient := range recipients {
+		if err := checkpointBalance(recipient.Address, tokenID); err != nil {
+			return err
+		}
+	}
 
-	// 2. 构建交易（使用internal包链式API）
+	// 3. 构建交易（使用internal包链式API）
 	// 注意：批量铸造操作实际上是创建多个UTXO输出
 	builder := framework.BeginTransaction()
 
@@ -64,7 +74,7 @@ func BatchMint(recipients []MintRecipient, tokenID framework.TokenID) error {
 		return framework.NewContractError(errCode, "batch mint failed")
 	}
 
-	// 3. 发出批量铸造事件
+	// 4. 发出批量铸造事件
 	caller := framework.GetCaller()
 	event := framework.NewEvent("BatchMint")
 	event.AddAddressField("minter", caller)
@@ -43,29 +43,38 @@ func Burn(from framework.Address, tokenID framework.TokenID, amount framework.Am
 		return err
 	}
 
-	// 2. 查询余额（通过framework）
+	// 2. 换算份额（Rebase 代币按缩放系数换算），查询余额（通过framework）
+	shares, err := rebaseState(tokenID).SharesFor(amount)
+	if err != nil {
+		return err
+	}
 	balance := framework.QueryUTXOBalance(from, tokenID)
-	if balance < amount {
+	if balance < shares {
 		return framework.NewContractError(
 			framework.ERROR_INSUFFICIENT_BALANCE,
 			"insufficient balance to burn",
 		)
 	}
 
-	// 3. 构建交易（使用framework链式API）
+	// 3. 记录余额快照检查点（变化前余额）
+	if err := checkpointBalance(from, tokenID); err != nil {
+		return err
+	}
+
+	// 4. 构建交易（使用framework链式API）
 	// 注意：在UTXO模型中，销毁代币的标准方式是将其转移到零地址
 	// 零地址是一个特殊的地址，代币一旦转移到零地址，就无法再被使用
 	// 这是UTXO模型中的标准销毁方式，符合区块链的去中心化原则
 	zeroAddr := framework.Address{}
 	success, _, errCode := framework.BeginTransaction().
-		Transfer(from, zeroAddr, tokenID, amount).
+		Transfer(from, zeroAddr, tokenID, shares).
 		Finalize()
 
 	if !success {
 		return framework.NewContractError(errCode, "burn failed")
 	}
 
-	// 4. 发出销毁事件
+	// 5. 发出销毁事件
 	event := framework.NewEvent("Burn")
 	event.AddAddressField("from", from)
 	event.AddStringField("token_id", string(tokenID))
@@ -87,7 +96,7 @@ func validateBurnParams(from framework.Address, tokenID framework.TokenID, amoun
 	}
 
 	// 验证代币ID
-	if tokenID == "" {
+	if framework.IsNativeToken(tokenID) {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"tokenID cannot be empty",
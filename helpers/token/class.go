@@ -0,0 +1,197 @@
+package token
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 代币类别注册 ====================
+//
+// 代币ID（TokenID）是调用方自选的字符串（"default"、"RWA_RE_001"、"LP_A_B"），
+// 两个合约或两个用户可能铸造到同一个ID，供应量被静默合并。本文件提供：
+//   - DeriveTokenID：按命名空间与组成部分派生抗碰撞的定长代币ID
+//   - ClassRegistry：记录代币类别的首个发行方，开启强制校验的类别拒绝其他发行方铸造
+//
+// 本文件不带 build tag，注册与校验逻辑可在非WASM环境中直接测试；
+// 宿主存储与包级函数（RegisterTokenClass 等）见 class_host.go。
+// 未注册的代币ID不受影响，已有代币无需迁移。
+
+const (
+	// MAX_TOKEN_NAMESPACE_LENGTH 代币ID中命名空间前缀的最大长度
+	MAX_TOKEN_NAMESPACE_LENGTH = 16
+	// TOKEN_ID_HASH_BYTES 代币ID中哈希部分的字节数（十六进制输出为 32 个字符）
+	TOKEN_ID_HASH_BYTES = 16
+
+	// tokenIDDomain 派生哈希的域分隔标签
+	tokenIDDomain = "weisyn.token_id.v1"
+	// tokenClassStatePrefix 类别记录状态ID前缀，完整格式：token_class:{tokenID}
+	tokenClassStatePrefix = "token_class:"
+)
+
+// 类别记录中的强制校验标记（非零值，避免链上读取去掉尾部零字节）
+const (
+	classFlagOpen     byte = 1
+	classFlagEnforced byte = 2
+)
+
+// TokenClass 已注册的代币类别
+type TokenClass struct {
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Issuer 首个发行方（通常为发行合约地址）
+	Issuer framework.Address
+	// Enforced 是否拒绝发行方以外的铸造
+	Enforced bool
+}
+
+// DeriveTokenID 派生抗碰撞的代币ID
+//
+// 🎯 **用途**：为 LP 代币、NFT 集合等按组成部分生成代币ID，替代手工拼接的字符串
+//
+// **参数**：
+//   - namespace: 简短的可读命名空间（如 "LP"、"NFT"），只保留字母、数字，最多 16 个字符
+//   - parts: 组成部分（如发行合约地址、交易对代币ID），顺序有关
+//
+// **返回**：{namespace}_{32位十六进制}，如 LP_3f5a9c0e1b2d4f6a8c0e1b2d4f6a8c0e
+//
+// **注意**：
+//   - 哈希覆盖完整的命名空间与各部分，逐项带长度前缀，("ab","c") 与 ("a","bc") 得到不同ID
+//   - 需要跨合约唯一时，应将发行合约地址作为组成部分之一
+//
+// **示例**：
+//
+//	lpTokenID := token.DeriveTokenID("LP", string(contractAddr[:]), string(tokenA), string(tokenB))
+func DeriveTokenID(namespace string, parts ...string) framework.TokenID {
+	h := sha256.New()
+	h.Write([]byte(tokenIDDomain))
+	writeLengthPrefixed(h, namespace)
+	for _, part := range parts {
+		writeLengthPrefixed(h, part)
+	}
+	sum := h.Sum(nil)
+	return framework.TokenID(namespacePrefix(namespace) + "_" + hex.EncodeToString(sum[:TOKEN_ID_HASH_BYTES]))
+}
+
+// writeLengthPrefixed 写入 4 字节大端长度与内容
+func writeLengthPrefixed(h hash.Hash, s string) {
+	n := len(s)
+	h.Write([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+	h.Write([]byte(s))
+}
+
+// namespacePrefix 命名空间的可读前缀：只保留字母与数字，截断到 MAX_TOKEN_NAMESPACE_LENGTH
+func namespacePrefix(namespace string) string {
+	prefix := make([]byte, 0, MAX_TOKEN_NAMESPACE_LENGTH)
+	for i := 0; i < len(namespace) && len(prefix) < MAX_TOKEN_NAMESPACE_LENGTH; i++ {
+		c := namespace[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			prefix = append(prefix, c)
+		}
+	}
+	if len(prefix) == 0 {
+		return "TOKEN"
+	}
+	return string(prefix)
+}
+
+// ClassRegistry 代币类别注册表
+//
+// 记录存放在 store 中（状态ID token_class:{tokenID}，发行方 20 字节 + 标记 1 字节）。
+// WASM 环境使用链上状态，任何合约都能读取同一条记录。
+type ClassRegistry struct {
+	store subaccount.Store
+}
+
+// NewClassRegistry 创建使用指定存储后端的注册表
+func NewClassRegistry(store subaccount.Store) *ClassRegistry {
+	return &ClassRegistry{store: store}
+}
+
+// Register 注册代币类别
+//
+// **参数**：
+//   - tokenID: 代币ID
+//   - issuer: 发行方
+//   - enforce: 是否拒绝发行方以外的铸造（按类别开启，便于兼容已有代币）
+//
+// **返回**：
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS；类别已由其他发行方注册返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：同一发行方重复注册时更新 enforce 标记
+func (r *ClassRegistry) Register(tokenID framework.TokenID, issuer framework.Address, enforce bool) error {
+	if framework.IsNativeToken(tokenID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID cannot be empty")
+	}
+	if issuer == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "issuer cannot be zero")
+	}
+
+	key := TokenClassStateID(tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return err
+	}
+	if existing, ok := decodeTokenClass(tokenID, data); ok {
+		if existing.Issuer != issuer {
+			return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "token class "+string(tokenID)+" is registered to another issuer")
+		}
+		if existing.Enforced == enforce {
+			return nil
+		}
+	}
+	return r.store.Save(key, version+1, encodeTokenClass(TokenClass{TokenID: tokenID, Issuer: issuer, Enforced: enforce}))
+}
+
+// Lookup 查询代币类别，未注册时返回 false
+func (r *ClassRegistry) Lookup(tokenID framework.TokenID) (TokenClass, bool, error) {
+	data, _, err := r.store.Load(TokenClassStateID(tokenID))
+	if err != nil {
+		return TokenClass{}, false, err
+	}
+	class, ok := decodeTokenClass(tokenID, data)
+	return class, ok, nil
+}
+
+// CheckMint 校验铸造方能否铸造该代币
+//
+// **返回**：类别已注册且开启强制校验、铸造方不是发行方时返回 ERROR_PERMISSION_DENIED；
+// 未注册或未开启强制校验的类别不受限制
+func (r *ClassRegistry) CheckMint(tokenID framework.TokenID, minter framework.Address) error {
+	class, ok, err := r.Lookup(tokenID)
+	if err != nil {
+		return err
+	}
+	if ok && class.Enforced && class.Issuer != minter {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "token class "+string(tokenID)+" can only be minted by its issuer")
+	}
+	return nil
+}
+
+// TokenClassStateID 返回代币类别记录的状态ID
+func TokenClassStateID(tokenID framework.TokenID) string {
+	return tokenClassStatePrefix + string(tokenID)
+}
+
+func encodeTokenClass(class TokenClass) []byte {
+	data := make([]byte, 21)
+	copy(data, class.Issuer[:])
+	data[20] = classFlagOpen
+	if class.Enforced {
+		data[20] = classFlagEnforced
+	}
+	return data
+}
+
+// decodeTokenClass 解码类别记录；记录不存在或格式无效时返回 false
+func decodeTokenClass(tokenID framework.TokenID, data []byte) (TokenClass, bool) {
+	if len(data) < 21 || (data[20] != classFlagOpen && data[20] != classFlagEnforced) {
+		return TokenClass{}, false
+	}
+	class := TokenClass{TokenID: tokenID, Enforced: data[20] == classFlagEnforced}
+	copy(class.Issuer[:], data[:20])
+	return class, true
+}
@@ -0,0 +1,81 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// classRegistry 基于链上状态的代币类别注册表
+var classRegistry = NewClassRegistry(hostStateStore{})
+
+// hostStateStore 类别记录与授权索引的宿主存储：读取使用 GetStateFromChain，写入使用 AppendStateOutputSimple
+type hostStateStore struct{}
+
+func (hostStateStore) Load(key string) ([]byte, uint64, error) {
+	value, version, err := framework.GetStateFromChain([]byte(key))
+	if err != nil {
+		return nil, 0, nil
+	}
+	return value, version, nil
+}
+
+func (hostStateStore) Save(key string, version uint64, value []byte) error {
+	_, err := framework.AppendStateOutputSimple([]byte(key), version, value, nil)
+	return err
+}
+
+// RegisterTokenClass 注册代币类别的发行方
+//
+// 🎯 **用途**：声明代币类别归属，开启 enforce 后其他合约无法铸造到该类别
+//
+// **参数**：
+//   - tokenID: 代币ID，建议使用 DeriveTokenID 派生
+//   - issuer: 发行方，通常为当前合约地址（Mint 以当前合约地址作为铸造方校验）
+//   - enforce: 是否拒绝发行方以外的铸造；为 false 时只登记归属
+//
+// **返回**：
+//   - error: 类别已由其他发行方注册时返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：成功后发出 TokenClassRegistered 事件
+//
+// **示例**：
+//
+//	contractAddr := framework.GetContractAddress()
+//	tokenID := token.DeriveTokenID("ERC20", string(contractAddr[:]), "MTK")
+//	if err := token.RegisterTokenClass(tokenID, contractAddr, true); err != nil {
+//	    return framework.ERROR_ALREADY_EXISTS
+//	}
+func RegisterTokenClass(tokenID framework.TokenID, issuer framework.Address, enforce bool) error {
+	if err := classRegistry.Register(tokenID, issuer, enforce); err != nil {
+		return err
+	}
+
+	event := framework.NewEvent("TokenClassRegistered")
+	event.AddStringField("token_id", string(tokenID))
+	event.AddAddressField("issuer", issuer)
+	event.AddBoolField("enforced", enforce)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// IsRegisteredClass 查询代币类别是否已注册
+func IsRegisteredClass(tokenID framework.TokenID) bool {
+	_, ok, err := classRegistry.Lookup(tokenID)
+	return err == nil && ok
+}
+
+// GetClassIssuer 查询代币类别的发行方，未注册时返回 false
+func GetClassIssuer(tokenID framework.TokenID) (framework.Address, bool) {
+	class, ok, err := classRegistry.Lookup(tokenID)
+	if err != nil || !ok {
+		return framework.Address{}, false
+	}
+	return class.Issuer, true
+}
+
+// checkClassMint 校验当前合约能否铸造该代币（Mint / BatchMint / MintWithState 共用）
+func checkClassMint(tokenID framework.TokenID) error {
+	return classRegistry.CheckMint(tokenID, framework.GetContractAddress())
+}
@@ -0,0 +1,128 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+var (
+	contractA = framework.Address{0xA1}
+	contractB = framework.Address{0xB2}
+)
+
+func errCode(err error) uint32 {
+	if ce, ok := err.(*framework.ContractError); ok {
+		return ce.Code
+	}
+	return framework.SUCCESS
+}
+
+// TestDeriveTokenID 测试派生ID确定、定长、带可读前缀且无拼接歧义
+func TestDeriveTokenID(t *testing.T) {
+	id := DeriveTokenID("LP", "USDT", "WES")
+	if id != DeriveTokenID("LP", "USDT", "WES") {
+		t.Fatal("DeriveTokenID() is not deterministic")
+	}
+	if !strings.HasPrefix(string(id), "LP_") || len(id) != len("LP_")+2*TOKEN_ID_HASH_BYTES {
+		t.Errorf("DeriveTokenID() = %s, want LP_ followed by %d hex chars", id, 2*TOKEN_ID_HASH_BYTES)
+	}
+
+	distinct := []framework.TokenID{
+		id,
+		DeriveTokenID("LP", "WES", "USDT"),
+		DeriveTokenID("LP", "USDTW", "ES"),
+		DeriveTokenID("LP", "USDT", "WES", ""),
+		DeriveTokenID("NFT", "USDT", "WES"),
+		DeriveTokenID("LP_", "USDT", "WES"),
+	}
+	seen := map[framework.TokenID]bool{}
+	for _, d := range distinct {
+		if seen[d] {
+			t.Errorf("DeriveTokenID() collision: %s", d)
+		}
+		seen[d] = true
+	}
+
+	if got := DeriveTokenID("real estate/中文 fund #1", "x"); !strings.HasPrefix(string(got), "realestatefund1_") {
+		t.Errorf("sanitized prefix = %s", got)
+	}
+	if got := DeriveTokenID("", "x"); !strings.HasPrefix(string(got), "TOKEN_") {
+		t.Errorf("empty namespace prefix = %s", got)
+	}
+}
+
+// TestClassRegistrySecondContractCannotMint 测试已注册且强制校验的类别拒绝其他合约铸造，未注册的旧类别不受影响
+func TestClassRegistrySecondContractCannotMint(t *testing.T) {
+	// 两个合约共享同一份链上状态
+	chain := subaccount.NewMemoryStore()
+	registryA := NewClassRegistry(chain)
+	registryB := NewClassRegistry(chain)
+
+	class := DeriveTokenID("ERC20", string(contractA[:]), "MTK")
+	if err := registryA.Register(class, contractA, true); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := registryA.CheckMint(class, contractA); err != nil {
+		t.Errorf("issuer CheckMint() error = %v", err)
+	}
+	if err := registryB.CheckMint(class, contractB); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("second contract CheckMint() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if err := registryB.Register(class, contractB, false); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("second contract Register() error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+
+	// 未注册的旧类别：任何合约都可铸造
+	for _, legacy := range []framework.TokenID{"default", "RWA_RE_001", "LP_A_B"} {
+		if err := registryB.CheckMint(legacy, contractB); err != nil {
+			t.Errorf("legacy class %s CheckMint() error = %v", legacy, err)
+		}
+	}
+
+	issued, ok, err := registryB.Lookup(class)
+	if err != nil || !ok || issued.Issuer != contractA || !issued.Enforced {
+		t.Errorf("Lookup() = %+v, %v, %v", issued, ok, err)
+	}
+}
+
+// TestClassRegistryOptInEnforcement 测试未开启强制校验的类别只登记归属，发行方可随后开启
+func TestClassRegistryOptInEnforcement(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewClassRegistry(chain)
+
+	if err := registry.Register("default", contractA, false); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.CheckMint("default", contractB); err != nil {
+		t.Errorf("unenforced class CheckMint() error = %v", err)
+	}
+
+	if err := registry.Register("default", contractA, true); err != nil {
+		t.Fatalf("enable enforcement error = %v", err)
+	}
+	if err := registry.CheckMint("default", contractB); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("enforced class CheckMint() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if _, version, _ := chain.Load(TokenClassStateID("default")); version != 2 {
+		t.Errorf("record version = %d, want 2", version)
+	}
+
+	// 重复注册相同内容不产生写入
+	if err := registry.Register("default", contractA, true); err != nil {
+		t.Errorf("idempotent Register() error = %v", err)
+	}
+	if _, version, _ := chain.Load(TokenClassStateID("default")); version != 2 {
+		t.Errorf("record version after idempotent register = %d, want 2", version)
+	}
+
+	if err := registry.Register("", contractA, true); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("empty tokenID error = %v", err)
+	}
+	if err := registry.Register("x", framework.Address{}, true); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("zero issuer error = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 到期代币 ====================
+//
+// 互助会员资格、活动门票等代币只在一段时间内有效。到期代币按代币ID登记到期时间，
+// 当前时间超过到期时间后，该代币在转账、授权与余额查询中按零余额处理。本文件提供：
+//   - ExpiryRegistry：到期时间的登记与查询
+//   - IsExpiredAt / EffectiveBalance：按给定时间判断是否到期、折算有效余额
+//
+// 本文件不带 build tag，到期逻辑可在非WASM环境中直接测试；
+// 铸造与包级查询（MintWithExpiry / IsExpired / BalanceOf）见 expiry_host.go。
+// 未登记到期时间的代币（含原生币）永不到期，已有代币不受影响。
+
+const (
+	// tokenExpiryStatePrefix 到期记录状态ID前缀，完整格式：token_expiry:{tokenID}
+	tokenExpiryStatePrefix = "token_expiry:"
+	// tokenExpiryRecordVersion 到期记录格式版本（非零值，避免链上读取去掉尾部零字节）
+	tokenExpiryRecordVersion byte = 1
+)
+
+// TokenExpiry 代币的到期登记
+type TokenExpiry struct {
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Issuer 登记到期时间的铸造方（通常为发行合约地址）
+	Issuer framework.Address
+	// Expiry 到期时间（Unix 秒），当前时间超过该值后代币失效
+	Expiry uint64
+}
+
+// ExpiryRegistry 代币到期时间注册表
+//
+// 记录存放在 store 中（状态ID token_expiry:{tokenID}）。
+type ExpiryRegistry struct {
+	store subaccount.Store
+}
+
+// NewExpiryRegistry 创建使用指定存储后端的到期注册表
+func NewExpiryRegistry(store subaccount.Store) *ExpiryRegistry {
+	return &ExpiryRegistry{store: store}
+}
+
+// SetExpiry 登记代币的到期时间
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持到期）
+//   - issuer: 铸造方
+//   - expiry: 到期时间（Unix 秒），必须大于 0
+//
+// **返回**：
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS；代币已由其他铸造方登记、
+//     或已登记了不同的到期时间返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：同一铸造方以相同到期时间重复登记（追加铸造）时不写入状态
+func (r *ExpiryRegistry) SetExpiry(tokenID framework.TokenID, issuer framework.Address, expiry uint64) error {
+	if framework.IsNativeToken(tokenID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID cannot be empty")
+	}
+	if issuer == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "issuer cannot be zero")
+	}
+	if expiry == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "expiry must be greater than 0")
+	}
+
+	key := TokenExpiryStateID(tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return err
+	}
+	if existing, ok := decodeTokenExpiry(tokenID, data); ok {
+		if existing.Issuer != issuer {
+			return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "token "+string(tokenID)+" expiry is registered to another issuer")
+		}
+		if existing.Expiry != expiry {
+			return framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "token "+string(tokenID)+" already has a different expiry")
+		}
+		return nil
+	}
+	return r.store.Save(key, version+1, encodeTokenExpiry(TokenExpiry{TokenID: tokenID, Issuer: issuer, Expiry: expiry}))
+}
+
+// Lookup 查询代币的到期登记，未登记时返回 false
+func (r *ExpiryRegistry) Lookup(tokenID framework.TokenID) (TokenExpiry, bool, error) {
+	if framework.IsNativeToken(tokenID) {
+		return TokenExpiry{}, false, nil
+	}
+	data, _, err := r.store.Load(TokenExpiryStateID(tokenID))
+	if err != nil {
+		return TokenExpiry{}, false, err
+	}
+	expiry, ok := decodeTokenExpiry(tokenID, data)
+	return expiry, ok, nil
+}
+
+// IsExpiredAt 判断代币在 now 时刻是否已到期
+//
+// 当前时间超过到期时间（now > expiry）后到期；到期时间当秒仍然有效。未登记的代币永不到期。
+func (r *ExpiryRegistry) IsExpiredAt(tokenID framework.TokenID, now uint64) (bool, error) {
+	expiry, ok, err := r.Lookup(tokenID)
+	if err != nil || !ok {
+		return false, err
+	}
+	return now > expiry.Expiry, nil
+}
+
+// EffectiveBalance 折算代币在 now 时刻的有效余额：已到期的代币余额为 0
+//
+// **参数**：
+//   - tokenID: 代币ID
+//   - balance: UTXO 余额（framework.QueryUTXOBalance）
+//   - now: 当前时间（Unix 秒）
+func (r *ExpiryRegistry) EffectiveBalance(tokenID framework.TokenID, balance framework.Amount, now uint64) (framework.Amount, error) {
+	if balance == 0 {
+		return 0, nil
+	}
+	expired, err := r.IsExpiredAt(tokenID, now)
+	if err != nil {
+		return 0, err
+	}
+	if expired {
+		return 0, nil
+	}
+	return balance, nil
+}
+
+// TokenExpiryStateID 返回到期记录的状态ID
+func TokenExpiryStateID(tokenID framework.TokenID) string {
+	return tokenExpiryStatePrefix + string(tokenID)
+}
+
+// encodeTokenExpiry 编码到期记录
+//
+// 编码格式（大端）：issuer(20) + expiry(8) + version(1)
+func encodeTokenExpiry(e TokenExpiry) []byte {
+	data := make([]byte, 0, 29)
+	data = append(data, e.Issuer[:]...)
+	for shift := 56; shift >= 0; shift -= 8 {
+		data = append(data, byte(e.Expiry>>uint(shift)))
+	}
+	return append(data, tokenExpiryRecordVersion)
+}
+
+// decodeTokenExpiry 解码到期记录；记录不存在或格式无效时返回 false
+func decodeTokenExpiry(tokenID framework.TokenID, data []byte) (TokenExpiry, bool) {
+	if len(data) < 29 || data[28] != tokenExpiryRecordVersion {
+		return TokenExpiry{}, false
+	}
+	e := TokenExpiry{TokenID: tokenID}
+	copy(e.Issuer[:], data[:20])
+	for _, b := range data[20:28] {
+		e.Expiry = e.Expiry<<8 | uint64(b)
+	}
+	return e, true
+}
@@ -0,0 +1,98 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// expiryRegistry 基于链上状态的代币到期注册表
+var expiryRegistry = NewExpiryRegistry(hostStateStore{})
+
+// MintWithExpiry 铸造一份到期代币
+//
+// 🎯 **用途**：发放限时会员资格、活动门票等到期后自动失效的代币
+//
+// **参数**：
+//   - to: 接收者地址
+//   - tokenID: 代币ID，建议每个会员周期或场次使用 DeriveTokenID 派生独立的代币ID
+//   - expiry: 到期时间（Unix 秒），必须晚于当前时间
+//
+// **返回**：
+//   - error: 参数无效或到期时间不晚于当前时间（ERROR_INVALID_PARAMS）、
+//     代币已登记不同的到期时间或由其他合约登记（ERROR_ALREADY_EXISTS）、
+//     类别开启强制校验且当前合约不是发行方（ERROR_PERMISSION_DENIED）
+//
+// **注意**：
+//   - 每次调用铸造 1 份，到期时间按代币ID登记（token_expiry:{tokenID}），同一代币ID的所有份额同时到期
+//   - 当前时间超过 expiry 后，Transfer、Airdrop、Approve、Freeze、FractionalizeNFT 与 BalanceOf 按零余额处理；
+//     Burn 不受影响，可用于清理到期代币
+//   - 铸造发出 Mint 事件，另发出 MintWithExpiry 事件（to / token_id / expiry）
+//
+// **示例**：
+//
+//	contractAddr := framework.GetContractAddress()
+//	membership := token.DeriveTokenID("MEMBER", string(contractAddr[:]), "2025")
+//	expiry := framework.TimestampUnix(framework.GetTimestamp()).Add(framework.Days(365))
+//	if err := token.MintWithExpiry(member, membership, uint64(expiry)); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func MintWithExpiry(to framework.Address, tokenID framework.TokenID, expiry uint64) error {
+	// 1. 到期时间必须晚于当前时间
+	if expiry <= framework.GetTimestamp() {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "expiry must be in the future")
+	}
+	if err := validateMintParams(to, tokenID, 1); err != nil {
+		return err
+	}
+
+	// 2. 登记到期时间（同一代币ID追加铸造时须使用相同的到期时间）
+	if err := expiryRegistry.SetExpiry(tokenID, framework.GetContractAddress(), expiry); err != nil {
+		return err
+	}
+
+	// 3. 铸造 1 份
+	if err := Mint(to, tokenID, 1); err != nil {
+		return err
+	}
+
+	// 4. 发出到期铸造事件
+	event := framework.NewEvent("MintWithExpiry")
+	event.AddAddressField("to", to)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("expiry", expiry)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// IsExpired 查询代币是否已到期（当前时间超过登记的到期时间）
+//
+// 未登记到期时间的代币（含原生币）返回 false
+func IsExpired(tokenID framework.TokenID) bool {
+	expired, err := expiryRegistry.IsExpiredAt(tokenID, framework.GetTimestamp())
+	return err == nil && expired
+}
+
+// GetTokenExpiry 查询代币登记的到期时间，未登记时返回 false
+func GetTokenExpiry(tokenID framework.TokenID) (uint64, bool) {
+	expiry, ok, err := expiryRegistry.Lookup(tokenID)
+	if err != nil || !ok {
+		return 0, false
+	}
+	return expiry.Expiry, true
+}
+
+// BalanceOf 查询地址的有效余额，已到期的代币返回 0；Rebase 代币返回 shares × scalingFactor
+func BalanceOf(owner framework.Address, tokenID framework.TokenID) framework.Amount {
+	return rebaseState(tokenID).BalanceOf(spendableBalance(owner, tokenID))
+}
+
+// spendableBalance 可用于转账、授权与冻结的余额（到期代币按 0 处理）
+func spendableBalance(owner framework.Address, tokenID framework.TokenID) framework.Amount {
+	balance, err := expiryRegistry.EffectiveBalance(tokenID, framework.QueryUTXOBalance(owner, tokenID), framework.GetTimestamp())
+	if err != nil {
+		return 0
+	}
+	return balance
+}
@@ -0,0 +1,86 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+const (
+	testTicket framework.TokenID = "TICKET_2025_gala"
+	testExpiry uint64            = 1767225600
+)
+
+// TestExpiringTokenBeforeAndAfterExpiry 测试到期前余额有效，到期时间当秒仍有效，之后按零余额处理
+func TestExpiringTokenBeforeAndAfterExpiry(t *testing.T) {
+	registry := NewExpiryRegistry(subaccount.NewMemoryStore())
+	if err := registry.SetExpiry(testTicket, contractA, testExpiry); err != nil {
+		t.Fatalf("SetExpiry() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		now     uint64
+		expired bool
+		balance framework.Amount
+	}{
+		{"before expiry", testExpiry - 86400, false, 1},
+		{"at expiry", testExpiry, false, 1},
+		{"after expiry", testExpiry + 1, true, 0},
+	}
+	for _, tt := range tests {
+		expired, err := registry.IsExpiredAt(testTicket, tt.now)
+		if err != nil || expired != tt.expired {
+			t.Errorf("%s: IsExpiredAt() = %v, %v, want %v", tt.name, expired, err, tt.expired)
+		}
+		if balance, err := registry.EffectiveBalance(testTicket, 1, tt.now); err != nil || balance != tt.balance {
+			t.Errorf("%s: EffectiveBalance() = %d, %v, want %d", tt.name, balance, err, tt.balance)
+		}
+	}
+
+	// 未登记到期时间的代币与原生币永不到期
+	for _, tokenID := range []framework.TokenID{"default", ""} {
+		if balance, _ := registry.EffectiveBalance(tokenID, 500, testExpiry+1); balance != 500 {
+			t.Errorf("EffectiveBalance(%q) after expiry = %d, want 500", tokenID, balance)
+		}
+	}
+}
+
+// TestSetExpiryConflicts 测试追加铸造须使用相同到期时间，其他铸造方不能改写
+func TestSetExpiryConflicts(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewExpiryRegistry(chain)
+	if err := registry.SetExpiry(testTicket, contractA, testExpiry); err != nil {
+		t.Fatalf("SetExpiry() error = %v", err)
+	}
+	if err := registry.SetExpiry(testTicket, contractA, testExpiry); err != nil {
+		t.Errorf("same expiry SetExpiry() error = %v", err)
+	}
+	if _, version, _ := chain.Load(TokenExpiryStateID(testTicket)); version != 1 {
+		t.Errorf("record version after repeated SetExpiry = %d, want 1", version)
+	}
+	if err := registry.SetExpiry(testTicket, contractA, testExpiry+1); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("different expiry error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+	if err := registry.SetExpiry(testTicket, contractB, testExpiry); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("other issuer error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+	if stored, ok, _ := registry.Lookup(testTicket); !ok || stored.Issuer != contractA || stored.Expiry != testExpiry {
+		t.Errorf("Lookup() = %+v, %v", stored, ok)
+	}
+
+	for _, tc := range []struct {
+		tokenID framework.TokenID
+		issuer  framework.Address
+		expiry  uint64
+	}{
+		{"", contractA, testExpiry},
+		{"TICKET_other", framework.Address{}, testExpiry},
+		{"TICKET_other", contractA, 0},
+	} {
+		if err := registry.SetExpiry(tc.tokenID, tc.issuer, tc.expiry); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("SetExpiry(%q, %x, %d) error = %v, want ERROR_INVALID_PARAMS", tc.tokenID, tc.issuer[:1], tc.expiry, err)
+		}
+	}
+}
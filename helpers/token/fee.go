@@ -0,0 +1,166 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 转账手续费代币 ====================
+//
+// 部分代币在每次转账时收取手续费。手续费按代币ID登记费率（transfer_fee_bp）与收款地址，
+// Transfer 将手续费转给收款地址，其余转给接收者。本文件提供：
+//   - TransferFeeRegistry：费率的登记与查询
+//   - TransferFee.Split：按费率拆分转账金额
+//
+// 本文件不带 build tag，费率与拆分逻辑可在非WASM环境中直接测试；
+// 费率设置与包级查询（SetTransferFee / GetTransferFee）见 fee_host.go。
+// 未登记费率的代币（含原生币）不收手续费，已有代币不受影响。
+
+const (
+	// tokenTransferFeeStatePrefix 费率记录状态ID前缀，完整格式：token_transfer_fee:{tokenID}
+	tokenTransferFeeStatePrefix = "token_transfer_fee:"
+	// tokenTransferFeeRecordVersion 费率记录格式版本（非零值，避免链上读取去掉尾部零字节）
+	tokenTransferFeeRecordVersion byte = 1
+)
+
+// TransferFee 代币的转账手续费登记
+type TransferFee struct {
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Issuer 登记费率的发行方（通常为发行合约地址），只有发行方可以修改费率
+	Issuer framework.Address
+	// FeeBP 手续费费率（万分比），0 表示不收手续费
+	FeeBP framework.BasisPoints
+	// Collector 手续费收款地址
+	Collector framework.Address
+}
+
+// Split 按费率拆分转账金额
+//
+// **返回**：
+//   - fee: 转给收款地址的手续费，amount × FeeBP / 10000 向下取整；
+//     费率不为 0 但手续费取整为 0 时（小额转账）按最低 1 个单位收取，避免拆分转账规避手续费
+//   - net: 接收者实际收到的金额（amount - fee）
+//   - error: 扣除手续费后接收者收不到任何金额时返回 ERROR_INVALID_PARAMS
+//
+// **注意**：收款地址自身转出时不收手续费
+func (f TransferFee) Split(from framework.Address, amount framework.Amount) (fee, net framework.Amount, err error) {
+	if f.FeeBP == 0 || from == f.Collector {
+		return 0, amount, nil
+	}
+	fee = amount.ApplyBP(f.FeeBP)
+	if fee == 0 {
+		fee = 1
+	}
+	if fee >= amount {
+		return 0, 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount does not cover the transfer fee")
+	}
+	return fee, amount - fee, nil
+}
+
+// TransferFeeRegistry 代币转账手续费注册表
+//
+// 记录存放在 store 中（状态ID token_transfer_fee:{tokenID}）。
+type TransferFeeRegistry struct {
+	store subaccount.Store
+}
+
+// NewTransferFeeRegistry 创建使用指定存储后端的手续费注册表
+func NewTransferFeeRegistry(store subaccount.Store) *TransferFeeRegistry {
+	return &TransferFeeRegistry{store: store}
+}
+
+// SetTransferFee 登记或修改代币的转账手续费
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持手续费）
+//   - issuer: 发行方
+//   - feeBP: 手续费费率（万分比），必须小于 10000；0 表示停止收取
+//   - collector: 手续费收款地址
+//
+// **返回**：
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS；代币已由其他发行方登记返回 ERROR_PERMISSION_DENIED
+func (r *TransferFeeRegistry) SetTransferFee(tokenID framework.TokenID, issuer framework.Address, feeBP framework.BasisPoints, collector framework.Address) error {
+	if framework.IsNativeToken(tokenID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID cannot be empty")
+	}
+	if issuer == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "issuer cannot be zero")
+	}
+	if collector == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "collector cannot be zero")
+	}
+	if feeBP >= framework.MAX_BASIS_POINTS {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "transfer fee must be below 10000 bp")
+	}
+
+	key := TokenTransferFeeStateID(tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return err
+	}
+	if existing, ok := decodeTransferFee(tokenID, data); ok && existing.Issuer != issuer {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "token "+string(tokenID)+" transfer fee is registered to another issuer")
+	}
+	return r.store.Save(key, version+1, encodeTransferFee(TransferFee{TokenID: tokenID, Issuer: issuer, FeeBP: feeBP, Collector: collector}))
+}
+
+// Lookup 查询代币的手续费登记，未登记时返回 false
+func (r *TransferFeeRegistry) Lookup(tokenID framework.TokenID) (TransferFee, bool, error) {
+	if framework.IsNativeToken(tokenID) {
+		return TransferFee{}, false, nil
+	}
+	data, _, err := r.store.Load(TokenTransferFeeStateID(tokenID))
+	if err != nil {
+		return TransferFee{}, false, err
+	}
+	fee, ok := decodeTransferFee(tokenID, data)
+	return fee, ok, nil
+}
+
+// Quote 计算一次转账的手续费与接收者实际到账金额，未登记费率的代币手续费为 0
+func (r *TransferFeeRegistry) Quote(tokenID framework.TokenID, from framework.Address, amount framework.Amount) (TransferFee, framework.Amount, framework.Amount, error) {
+	config, ok, err := r.Lookup(tokenID)
+	if err != nil {
+		return TransferFee{}, 0, 0, err
+	}
+	if !ok {
+		return TransferFee{}, 0, amount, nil
+	}
+	fee, net, err := config.Split(from, amount)
+	return config, fee, net, err
+}
+
+// TokenTransferFeeStateID 返回费率记录的状态ID
+func TokenTransferFeeStateID(tokenID framework.TokenID) string {
+	return tokenTransferFeeStatePrefix + string(tokenID)
+}
+
+// encodeTransferFee 编码费率记录
+//
+// 编码格式（大端）：issuer(20) + collector(20) + fee_bp(8) + version(1)
+func encodeTransferFee(f TransferFee) []byte {
+	data := make([]byte, 0, 49)
+	data = append(data, f.Issuer[:]...)
+	data = append(data, f.Collector[:]...)
+	for shift := 56; shift >= 0; shift -= 8 {
+		data = append(data, byte(uint64(f.FeeBP)>>uint(shift)))
+	}
+	return append(data, tokenTransferFeeRecordVersion)
+}
+
+// decodeTransferFee 解码费率记录；记录不存在或格式无效时返回 false
+func decodeTransferFee(tokenID framework.TokenID, data []byte) (TransferFee, bool) {
+	if len(data) < 49 || data[48] != tokenTransferFeeRecordVersion {
+		return TransferFee{}, false
+	}
+	f := TransferFee{TokenID: tokenID}
+	copy(f.Issuer[:], data[:20])
+	copy(f.Collector[:], data[20:40])
+	var bp uint64
+	for _, b := range data[40:48] {
+		bp = bp<<8 | uint64(b)
+	}
+	f.FeeBP = framework.BasisPoints(bp)
+	return f, true
+}
@@ -0,0 +1,58 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// transferFeeRegistry 基于链上状态的转账手续费注册表
+var transferFeeRegistry = NewTransferFeeRegistry(hostStateStore{})
+
+// SetTransferFee 开启或修改代币的转账手续费模式
+//
+// 🎯 **用途**：发行方为代币设置转账手续费（transfer_fee_bp），之后每次 Transfer 自动扣除手续费
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持手续费）
+//   - feeBP: 手续费费率（万分比），必须小于 10000；0 表示停止收取
+//   - collector: 手续费收款地址
+//
+// **返回**：
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、费率已由其他合约登记（ERROR_PERMISSION_DENIED）
+//
+// **注意**：
+//   - 费率按代币ID登记（token_transfer_fee:{tokenID}），登记方为当前合约地址，只有登记方可以修改
+//   - 只有 Transfer 收取手续费；Airdrop、Burn、Freeze 等不受影响
+//   - 成功后发出 TransferFeeSet 事件
+//
+// **示例**：
+//
+//	// 每次转账收取 0.3% 手续费给金库
+//	if err := token.SetTransferFee(tokenID, framework.BasisPoints(30), treasury); err != nil {
+//	    return framework.ERROR_INVALID_PARAMS
+//	}
+func SetTransferFee(tokenID framework.TokenID, feeBP framework.BasisPoints, collector framework.Address) error {
+	issuer := framework.GetContractAddress()
+	if err := transferFeeRegistry.SetTransferFee(tokenID, issuer, feeBP, collector); err != nil {
+		return err
+	}
+
+	event := framework.NewEvent("TransferFeeSet")
+	event.AddStringField("token_id", string(tokenID))
+	event.AddAddressField("issuer", issuer)
+	event.AddUint64Field("transfer_fee_bp", uint64(feeBP))
+	event.AddAddressField("collector", collector)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// GetTransferFee 查询代币的转账手续费登记，未登记时返回 false
+func GetTransferFee(tokenID framework.TokenID) (TransferFee, bool) {
+	config, ok, err := transferFeeRegistry.Lookup(tokenID)
+	if err != nil || !ok {
+		return TransferFee{}, false
+	}
+	return config, true
+}
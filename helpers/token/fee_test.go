@@ -0,0 +1,142 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+var (
+	feeSender    = framework.Address{0x51}
+	feeRecipient = framework.Address{0x52}
+	feeCollector = framework.Address{0x53}
+)
+
+const testFeeToken framework.TokenID = "FEE_TOKEN"
+
+// TestTransferFeeSplit 测试接收者收到 amount - fee，收款地址累计手续费
+func TestTransferFeeSplit(t *testing.T) {
+	registry := NewTransferFeeRegistry(subaccount.NewMemoryStore())
+	if err := registry.SetTransferFee(testFeeToken, contractA, 30, feeCollector); err != nil {
+		t.Fatalf("SetTransferFee() error = %v", err)
+	}
+
+	// 按 Transfer 的两条转账划分模拟余额变化
+	balances := map[framework.Address]framework.Amount{feeSender: 100000}
+	transfers := []struct {
+		amount framework.Amount
+		fee    framework.Amount
+	}{
+		{10000, 30},
+		{20000, 60},
+		{999, 2},
+	}
+	var accrued framework.Amount
+	for _, tt := range transfers {
+		config, fee, net, err := registry.Quote(testFeeToken, feeSender, tt.amount)
+		if err != nil {
+			t.Fatalf("Quote(%d) error = %v", tt.amount, err)
+		}
+		if fee != tt.fee || net != tt.amount-tt.fee {
+			t.Errorf("Quote(%d) = fee %d, net %d, want fee %d, net %d", tt.amount, fee, net, tt.fee, tt.amount-tt.fee)
+		}
+		balances[feeSender] -= net + fee
+		balances[feeRecipient] += net
+		balances[config.Collector] += fee
+		accrued += tt.fee
+	}
+
+	if got, want := balances[feeRecipient], framework.Amount(10000+20000+999)-accrued; got != want {
+		t.Errorf("recipient balance = %d, want %d", got, want)
+	}
+	if got := balances[feeCollector]; got != accrued || got != 92 {
+		t.Errorf("collector balance = %d, want 92", got)
+	}
+	if got := balances[feeSender]; got != 100000-(10000+20000+999) {
+		t.Errorf("sender balance = %d, want gross amounts deducted", got)
+	}
+}
+
+// TestTransferFeeRounding 测试小额转账手续费取整为 0 时按最低 1 个单位收取，不足以支付手续费时拒绝
+func TestTransferFeeRounding(t *testing.T) {
+	config := TransferFee{TokenID: testFeeToken, Issuer: contractA, FeeBP: 30, Collector: feeCollector}
+
+	tests := []struct {
+		name    string
+		amount  framework.Amount
+		fee     framework.Amount
+		wantErr uint32
+	}{
+		{"exact", 10000, 30, framework.SUCCESS},
+		{"rounds down", 3500, 10, framework.SUCCESS},
+		{"rounds to zero", 100, 1, framework.SUCCESS},
+		{"smallest payable", 2, 1, framework.SUCCESS},
+		{"does not cover fee", 1, 0, framework.ERROR_INVALID_PARAMS},
+	}
+	for _, tt := range tests {
+		fee, net, err := config.Split(feeSender, tt.amount)
+		if errCode(err) != tt.wantErr {
+			t.Errorf("%s: Split(%d) error = %v, want code %d", tt.name, tt.amount, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (fee != tt.fee || net != tt.amount-tt.fee) {
+			t.Errorf("%s: Split(%d) = %d, %d, want fee %d", tt.name, tt.amount, fee, net, tt.fee)
+		}
+	}
+
+	// 收款地址转出与零费率不收手续费
+	if fee, net, err := config.Split(feeCollector, 1); err != nil || fee != 0 || net != 1 {
+		t.Errorf("collector Split() = %d, %d, %v, want no fee", fee, net, err)
+	}
+	config.FeeBP = 0
+	if fee, net, err := config.Split(feeSender, 1); err != nil || fee != 0 || net != 1 {
+		t.Errorf("zero-rate Split() = %d, %d, %v, want no fee", fee, net, err)
+	}
+}
+
+// TestSetTransferFee 测试费率登记校验、发行方修改费率与未登记代币不收手续费
+func TestSetTransferFee(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewTransferFeeRegistry(chain)
+
+	invalid := []struct {
+		name      string
+		tokenID   framework.TokenID
+		feeBP     framework.BasisPoints
+		collector framework.Address
+	}{
+		{"native token", "", 30, feeCollector},
+		{"zero collector", testFeeToken, 30, framework.Address{}},
+		{"full fee", testFeeToken, framework.MAX_BASIS_POINTS, feeCollector},
+	}
+	for _, tt := range invalid {
+		if err := registry.SetTransferFee(tt.tokenID, contractA, tt.feeBP, tt.collector); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: SetTransferFee() error = %v, want ERROR_INVALID_PARAMS", tt.name, err)
+		}
+	}
+
+	if err := registry.SetTransferFee(testFeeToken, contractA, 30, feeCollector); err != nil {
+		t.Fatalf("SetTransferFee() error = %v", err)
+	}
+	if err := registry.SetTransferFee(testFeeToken, contractB, 0, feeCollector); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("other issuer SetTransferFee() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if err := registry.SetTransferFee(testFeeToken, contractA, 50, feeRecipient); err != nil {
+		t.Fatalf("issuer update SetTransferFee() error = %v", err)
+	}
+	config, ok, err := registry.Lookup(testFeeToken)
+	if err != nil || !ok || config.Issuer != contractA || config.FeeBP != 50 || config.Collector != feeRecipient {
+		t.Errorf("Lookup() = %+v, %v, %v", config, ok, err)
+	}
+	if _, version, _ := chain.Load(TokenTransferFeeStateID(testFeeToken)); version != 2 {
+		t.Errorf("fee record version = %d, want 2", version)
+	}
+
+	// 未登记费率的代币与原生币全额到账
+	for _, tokenID := range []framework.TokenID{"default", ""} {
+		if _, fee, net, err := registry.Quote(tokenID, feeSender, 1); err != nil || fee != 0 || net != 1 {
+			t.Errorf("Quote(%q) = %d, %d, %v, want no fee", tokenID, fee, net, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== NFT 碎片化 ====================
+//
+// 将单个 NFT 托管在合约地址，铸造等量可替代的份额代币，份额可以像普通代币一样转账、交易；
+// 持有全部份额的地址可以销毁份额赎回 NFT。本文件提供：
+//   - FractionVault：碎片化金库记录（NFT、份额代币、份额总量、状态）
+//   - FractionRegistry：金库的创建与赎回校验
+//
+// 本文件不带 build tag，金库逻辑可在非WASM环境中直接测试；
+// NFT 托管、份额铸造与销毁（FractionalizeNFT / Redeem）见 fraction_host.go。
+
+const (
+	// fractionVaultStatePrefix 金库记录状态ID前缀，完整格式：fraction_vault:{shareTokenID}
+	fractionVaultStatePrefix = "fraction_vault:"
+	// maxFractionNFTIDLength 金库记录中 NFT 代币ID的最大长度
+	maxFractionNFTIDLength = 0xFFFF
+)
+
+// 金库状态（非零值，避免链上读取去掉尾部零字节）
+const (
+	// FRACTION_STATUS_ACTIVE NFT 托管中，份额流通
+	FRACTION_STATUS_ACTIVE byte = 1
+	// FRACTION_STATUS_REDEEMED 份额已全部销毁，NFT 已赎回
+	FRACTION_STATUS_REDEEMED byte = 2
+)
+
+// FractionVault NFT 碎片化金库
+type FractionVault struct {
+	// ShareTokenID 份额代币ID（金库以此为键）
+	ShareTokenID framework.TokenID
+	// NFT 托管的 NFT 代币ID
+	NFT framework.TokenID
+	// Owner 碎片化时的 NFT 所有者（份额的初始持有者）
+	Owner framework.Address
+	// Shares 份额总量，赎回时须全部持有
+	Shares framework.Amount
+	// Status 金库状态
+	Status byte
+	// RedeemedBy 赎回 NFT 的地址（赎回前为零地址）
+	RedeemedBy framework.Address
+}
+
+// FractionRegistry NFT 碎片化金库注册表
+//
+// 记录存放在 store 中（状态ID fraction_vault:{shareTokenID}）。
+type FractionRegistry struct {
+	store subaccount.Store
+}
+
+// NewFractionRegistry 创建使用指定存储后端的金库注册表
+func NewFractionRegistry(store subaccount.Store) *FractionRegistry {
+	return &FractionRegistry{store: store}
+}
+
+// Fractionalize 创建碎片化金库
+//
+// **参数**：
+//   - owner: NFT 所有者
+//   - nftTokenID: NFT 代币ID
+//   - shares: 份额总量，必须大于 1
+//   - shareTokenID: 份额代币ID，不能与 NFT 相同
+//
+// **返回**：
+//   - *FractionVault: 新建的金库
+//   - error: 参数无效返回 ERROR_INVALID_PARAMS；份额代币ID已有金库返回 ERROR_ALREADY_EXISTS
+//
+// **注意**：owner 是否持有该 NFT 由调用方（FractionalizeNFT）查询余额确认
+func (r *FractionRegistry) Fractionalize(owner framework.Address, nftTokenID framework.TokenID, shares framework.Amount, shareTokenID framework.TokenID) (*FractionVault, error) {
+	if owner == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "owner address cannot be zero")
+	}
+	if nftTokenID == "" || len(nftTokenID) > maxFractionNFTIDLength {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid NFT tokenID")
+	}
+	if shareTokenID == "" || shareTokenID == nftTokenID {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "share tokenID must be set and differ from the NFT")
+	}
+	if shares < 2 {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "shares must be greater than 1")
+	}
+
+	key := FractionVaultStateID(shareTokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := decodeFractionVault(shareTokenID, data); ok {
+		return nil, framework.NewContractError(framework.ERROR_ALREADY_EXISTS, "share token "+string(shareTokenID)+" already backs a vault")
+	}
+
+	vault := &FractionVault{
+		ShareTokenID: shareTokenID,
+		NFT:          nftTokenID,
+		Owner:        owner,
+		Shares:       shares,
+		Status:       FRACTION_STATUS_ACTIVE,
+	}
+	if err := r.store.Save(key, version+1, encodeFractionVault(vault)); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+// Redeem 持有全部份额的地址赎回 NFT
+//
+// **参数**：
+//   - shareTokenID: 份额代币ID
+//   - holder: 赎回地址
+//   - holderShares: holder 当前持有的份额数量
+//
+// **返回**：
+//   - *FractionVault: 已标记为赎回的金库，调用方据此销毁 Shares 份额并释放 NFT
+//   - error: 金库不存在（ERROR_NOT_FOUND）、已赎回（ERROR_INVALID_STATE）、
+//     未持有全部份额（ERROR_PERMISSION_DENIED）
+func (r *FractionRegistry) Redeem(shareTokenID framework.TokenID, holder framework.Address, holderShares framework.Amount) (*FractionVault, error) {
+	if holder == (framework.Address{}) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "holder address cannot be zero")
+	}
+
+	key := FractionVaultStateID(shareTokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	vault, ok := decodeFractionVault(shareTokenID, data)
+	if !ok {
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "fraction vault not found")
+	}
+	if vault.Status != FRACTION_STATUS_ACTIVE {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_STATE, "fraction vault already redeemed")
+	}
+	if holderShares < vault.Shares {
+		return nil, framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "redeem requires holding all shares")
+	}
+
+	vault.Status = FRACTION_STATUS_REDEEMED
+	vault.RedeemedBy = holder
+	if err := r.store.Save(key, version+1, encodeFractionVault(vault)); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+// Lookup 查询金库，不存在时返回 false
+func (r *FractionRegistry) Lookup(shareTokenID framework.TokenID) (*FractionVault, bool, error) {
+	data, _, err := r.store.Load(FractionVaultStateID(shareTokenID))
+	if err != nil {
+		return nil, false, err
+	}
+	vault, ok := decodeFractionVault(shareTokenID, data)
+	return vault, ok, nil
+}
+
+// FractionVaultStateID 返回金库记录的状态ID
+func FractionVaultStateID(shareTokenID framework.TokenID) string {
+	return fractionVaultStatePrefix + string(shareTokenID)
+}
+
+// encodeFractionVault 编码金库记录
+//
+// 编码格式（大端）：status(1) + owner(20) + redeemedBy(20) + shares(8) + nftLen(2) + nft
+func encodeFractionVault(v *FractionVault) []byte {
+	data := make([]byte, 0, 1+40+8+2+len(v.NFT))
+	data = append(data, v.Status)
+	data = append(data, v.Owner[:]...)
+	data = append(data, v.RedeemedBy[:]...)
+	for shift := 56; shift >= 0; shift -= 8 {
+		data = append(data, byte(uint64(v.Shares)>>uint(shift)))
+	}
+	data = append(data, byte(len(v.NFT)>>8), byte(len(v.NFT)))
+	return append(data, v.NFT...)
+}
+
+// decodeFractionVault 解码金库记录；记录不存在或格式无效时返回 false
+func decodeFractionVault(shareTokenID framework.TokenID, data []byte) (*FractionVault, bool) {
+	const fixed = 1 + 40 + 8 + 2
+	if len(data) < fixed || (data[0] != FRACTION_STATUS_ACTIVE && data[0] != FRACTION_STATUS_REDEEMED) {
+		return nil, false
+	}
+	n := int(data[fixed-2])<<8 | int(data[fixed-1])
+	if n == 0 || len(data) < fixed+n {
+		return nil, false
+	}
+
+	v := &FractionVault{ShareTokenID: shareTokenID, Status: data[0]}
+	copy(v.Owner[:], data[1:21])
+	copy(v.RedeemedBy[:], data[21:41])
+	var shares uint64
+	for _, b := range data[41:49] {
+		shares = shares<<8 | uint64(b)
+	}
+	v.Shares = framework.Amount(shares)
+	v.NFT = framework.TokenID(data[fixed : fixed+n])
+	return v, true
+}
@@ -0,0 +1,129 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// fractionRegistry 基于链上状态的碎片化金库注册表
+var fractionRegistry = NewFractionRegistry(hostStateStore{})
+
+// FractionalizeNFT 将 NFT 碎片化为可替代的份额代币
+//
+// 🎯 **用途**：把单个 NFT 拆分为 shares 份可自由转账的份额，降低持有门槛
+//
+// **参数**：
+//   - owner: NFT 所有者
+//   - nftTokenID: NFT 代币ID
+//   - shares: 份额总量，必须大于 1
+//   - shareTokenID: 份额代币ID，建议使用 DeriveTokenID 派生
+//
+// **返回**：
+//   - error: 参数无效（ERROR_INVALID_PARAMS）、owner 未持有该 NFT（ERROR_INSUFFICIENT_BALANCE）、
+//     份额代币ID已有金库或已由其他发行方注册（ERROR_ALREADY_EXISTS）
+//
+// **注意**：
+//   - NFT 转入当前合约地址托管，shares 份额代币铸造给 owner
+//   - 份额代币以当前合约为发行方注册并开启强制校验，其他合约无法增发份额
+//   - 写入金库记录 fraction_vault:{shareTokenID}，发出 NFTFractionalized 事件
+//
+// **示例**：
+//
+//	contractAddr := framework.GetContractAddress()
+//	shareTokenID := token.DeriveTokenID("FRAC", string(contractAddr[:]), string(nftID))
+//	if err := token.FractionalizeNFT(caller, nftID, framework.Amount(1000), shareTokenID); err != nil {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+func FractionalizeNFT(owner framework.Address, nftTokenID framework.TokenID, shares framework.Amount, shareTokenID framework.TokenID) error {
+	// 1. 确认所有者持有该 NFT
+	if owner != (framework.Address{}) && spendableBalance(owner, nftTokenID) < 1 {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "owner does not hold the NFT")
+	}
+
+	// 2. 创建金库记录并注册份额代币类别
+	vault, err := fractionRegistry.Fractionalize(owner, nftTokenID, shares, shareTokenID)
+	if err != nil {
+		return err
+	}
+	contractAddr := framework.GetContractAddress()
+	if err := classRegistry.Register(shareTokenID, contractAddr, true); err != nil {
+		return err
+	}
+
+	// 3. 托管 NFT 并铸造份额
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(owner, contractAddr, nftTokenID, 1).
+		AddAssetOutput(owner, shareTokenID, vault.Shares).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "fractionalize failed")
+	}
+
+	// 4. 发出碎片化事件
+	event := framework.NewEvent("NFTFractionalized")
+	event.AddAddressField("owner", owner)
+	event.AddStringField("nft_token_id", string(nftTokenID))
+	event.AddStringField("share_token_id", string(shareTokenID))
+	event.AddUint64Field("shares", uint64(vault.Shares))
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// Redeem 销毁全部份额赎回 NFT
+//
+// 🎯 **用途**：持有某个金库全部份额的调用者销毁份额，取回托管的 NFT
+//
+// **参数**：
+//   - shareTokenID: 份额代币ID
+//
+// **返回**：
+//   - error: 金库不存在（ERROR_NOT_FOUND）、已赎回（ERROR_INVALID_STATE）、
+//     调用者未持有全部份额（ERROR_PERMISSION_DENIED）
+//
+// **注意**：
+//   - 赎回者为当前调用者（framework.GetCaller）
+//   - 份额转入零地址销毁，NFT 从合约地址转给赎回者，发出 NFTRedeemed 事件
+//
+// **示例**：
+//
+//	if err := token.Redeem(shareTokenID); err != nil {
+//	    return framework.ERROR_PERMISSION_DENIED
+//	}
+func Redeem(shareTokenID framework.TokenID) error {
+	// 1. 校验调用者持有全部份额并标记金库已赎回
+	holder := framework.GetCaller()
+	vault, err := fractionRegistry.Redeem(shareTokenID, holder, framework.QueryUTXOBalance(holder, shareTokenID))
+	if err != nil {
+		return err
+	}
+
+	// 2. 销毁份额并释放 NFT
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(holder, framework.Address{}, shareTokenID, vault.Shares).
+		Transfer(framework.GetContractAddress(), holder, vault.NFT, 1).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "redeem failed")
+	}
+
+	// 3. 发出赎回事件
+	event := framework.NewEvent("NFTRedeemed")
+	event.AddAddressField("holder", holder)
+	event.AddStringField("nft_token_id", string(vault.NFT))
+	event.AddStringField("share_token_id", string(shareTokenID))
+	event.AddUint64Field("shares", uint64(vault.Shares))
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// GetFractionVault 查询碎片化金库，不存在时返回 false
+func GetFractionVault(shareTokenID framework.TokenID) (*FractionVault, bool) {
+	vault, ok, err := fractionRegistry.Lookup(shareTokenID)
+	if err != nil || !ok {
+		return nil, false
+	}
+	return vault, true
+}
@@ -0,0 +1,111 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+const (
+	testNFT    framework.TokenID = "NFT_artwork_001"
+	testShares framework.Amount  = 1000
+)
+
+var testShareToken = DeriveTokenID("FRAC", string(contractA[:]), string(testNFT))
+
+// TestFractionalize 测试碎片化创建金库记录，同一份额代币不能重复碎片化
+func TestFractionalize(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewFractionRegistry(chain)
+
+	vault, err := registry.Fractionalize(fixtures.Alice(), testNFT, testShares, testShareToken)
+	if err != nil {
+		t.Fatalf("Fractionalize() error = %v", err)
+	}
+	want := FractionVault{ShareTokenID: testShareToken, NFT: testNFT, Owner: fixtures.Alice(), Shares: testShares, Status: FRACTION_STATUS_ACTIVE}
+	if *vault != want {
+		t.Errorf("Fractionalize() = %+v, want %+v", *vault, want)
+	}
+	if stored, ok, err := registry.Lookup(testShareToken); err != nil || !ok || *stored != want {
+		t.Errorf("Lookup() = %+v, %v, %v, want %+v", stored, ok, err, want)
+	}
+
+	if _, err := registry.Fractionalize(fixtures.Bob(), "NFT_other", testShares, testShareToken); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("reused share token error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+	invalid := []struct {
+		name    string
+		owner   framework.Address
+		nft     framework.TokenID
+		shares  framework.Amount
+		shareID framework.TokenID
+	}{
+		{"zero owner", framework.Address{}, testNFT, testShares, "S1"},
+		{"empty NFT", fixtures.Alice(), "", testShares, "S1"},
+		{"single share", fixtures.Alice(), testNFT, 1, "S1"},
+		{"share is NFT", fixtures.Alice(), testNFT, testShares, testNFT},
+		{"empty share ID", fixtures.Alice(), testNFT, testShares, ""},
+	}
+	for _, tc := range invalid {
+		if _, err := registry.Fractionalize(tc.owner, tc.nft, tc.shares, tc.shareID); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s error = %v, want ERROR_INVALID_PARAMS", tc.name, err)
+		}
+	}
+}
+
+// TestRedeemRejectsPartialHolder 测试未持有全部份额的地址不能赎回，金库保持托管
+func TestRedeemRejectsPartialHolder(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewFractionRegistry(chain)
+	if _, err := registry.Fractionalize(fixtures.Alice(), testNFT, testShares, testShareToken); err != nil {
+		t.Fatalf("Fractionalize() error = %v", err)
+	}
+
+	// Alice 转出 1 份后只持有 999 份
+	if _, err := registry.Redeem(testShareToken, fixtures.Alice(), testShares-1); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("partial holder Redeem() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if _, err := registry.Redeem(testShareToken, fixtures.Bob(), 1); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("minority holder Redeem() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if vault, _, _ := registry.Lookup(testShareToken); vault.Status != FRACTION_STATUS_ACTIVE || vault.RedeemedBy != (framework.Address{}) {
+		t.Errorf("vault after rejected redeem = %+v, want still active", vault)
+	}
+	if _, version, _ := chain.Load(FractionVaultStateID(testShareToken)); version != 1 {
+		t.Errorf("record version after rejected redeem = %d, want 1", version)
+	}
+
+	if _, err := registry.Redeem("FRAC_missing", fixtures.Alice(), testShares); errCode(err) != framework.ERROR_NOT_FOUND {
+		t.Errorf("missing vault Redeem() error = %v, want ERROR_NOT_FOUND", err)
+	}
+}
+
+// TestRedeemFullHolder 测试持有全部份额的地址（不一定是原所有者）赎回，金库只能赎回一次
+func TestRedeemFullHolder(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewFractionRegistry(chain)
+	if _, err := registry.Fractionalize(fixtures.Alice(), testNFT, testShares, testShareToken); err != nil {
+		t.Fatalf("Fractionalize() error = %v", err)
+	}
+
+	// Bob 从其他持有者处收齐全部份额
+	vault, err := registry.Redeem(testShareToken, fixtures.Bob(), testShares)
+	if err != nil {
+		t.Fatalf("full holder Redeem() error = %v", err)
+	}
+	if vault.NFT != testNFT || vault.Shares != testShares || vault.Status != FRACTION_STATUS_REDEEMED || vault.RedeemedBy != fixtures.Bob() {
+		t.Errorf("Redeem() = %+v, want NFT %s released to bob after burning %d shares", vault, testNFT, testShares)
+	}
+	if stored, _, _ := registry.Lookup(testShareToken); *stored != *vault {
+		t.Errorf("stored vault = %+v, want %+v", stored, vault)
+	}
+
+	if _, err := registry.Redeem(testShareToken, fixtures.Bob(), testShares); errCode(err) != framework.ERROR_INVALID_STATE {
+		t.Errorf("second Redeem() error = %v, want ERROR_INVALID_STATE", err)
+	}
+	if _, err := registry.Fractionalize(fixtures.Bob(), testNFT, testShares, testShareToken); errCode(err) != framework.ERROR_ALREADY_EXISTS {
+		t.Errorf("reuse redeemed share token error = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+}
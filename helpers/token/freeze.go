@@ -48,8 +48,8 @@ func Freeze(target framework.Address, tokenID framework.TokenID, amount framewor
 		return err
 	}
 
-	// 2. 查询余额（通过framework）
-	balance := framework.QueryUTXOBalance(target, tokenID)
+	// 2. 查询有效余额（到期代币按 0 处理）
+	balance := spendableBalance(target, tokenID)
 	if balance < amount {
 		return framework.NewContractError(
 			framework.ERROR_INSUFFICIENT_BALANCE,
@@ -97,7 +97,7 @@ func validateFreezeParams(target framework.Address, tokenID framework.TokenID, a
 	}
 
 	// 验证代币ID
-	if tokenID == "" {
+	if framework.IsNativeToken(tokenID) {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"tokenID cannot be empty",
@@ -20,6 +20,8 @@ import (
 //
 // **注意**：
 //   - 合约只能铸造自己的代币
+//   - 代币类别已注册并开启强制校验（RegisterTokenClass）时，只有发行方合约可以铸造，
+//     否则返回 ERROR_PERMISSION_DENIED；未注册的代币ID不受影响
 //   - 权限控制和总量控制是业务逻辑，需要在合约代码中实现
 //
 // **示例**：
@@ -48,18 +50,31 @@ func Mint(to framework.Address, tokenID framework.TokenID, amount framework.Amou
 	if err := validateMintParams(to, tokenID, amount); err != nil {
 		return err
 	}
+	if err := checkClassMint(tokenID); err != nil {
+		return err
+	}
+	// Rebase 代币的 amount 按余额计，换算为份额
+	shares, err := rebaseState(tokenID).SharesFor(amount)
+	if err != nil {
+		return err
+	}
+
+	// 2. 记录余额快照检查点（变化前余额）
+	if err := checkpointBalance(to, tokenID); err != nil {
+		return err
+	}
 
-	// 2. 构建交易（使用internal包链式API）
+	// 3. 构建交易（使用internal包链式API）
 	// 注意：Mint操作实际上是创建新的UTXO输出
 	success, _, errCode := framework.BeginTransaction().
-		AddAssetOutput(to, tokenID, amount).
+		AddAssetOutput(to, tokenID, shares).
 		Finalize()
 
 	if !success {
 		return framework.NewContractError(errCode, "mint failed")
 	}
 
-	// 3. 发出铸造事件
+	// 4. 发出铸造事件
 	caller := framework.GetCaller()
 	event := framework.NewEvent("Mint")
 	event.AddAddressField("to", to)
@@ -83,7 +98,7 @@ func validateMintParams(to framework.Address, tokenID framework.TokenID, amount
 	}
 
 	// 验证代币ID
-	if tokenID == "" {
+	if framework.IsNativeToken(tokenID) {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"tokenID cannot be empty",
@@ -0,0 +1,37 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// TestRegistriesRejectNativeToken 测试代币属性登记拒绝原生币，查询原生币时视为未登记
+func TestRegistriesRejectNativeToken(t *testing.T) {
+	store := subaccount.NewMemoryStore()
+	native := framework.NativeTokenID()
+
+	if err := NewClassRegistry(store).Register(native, contractA, true); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("ClassRegistry.Register(native) code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+	if err := NewExpiryRegistry(store).SetExpiry(native, contractA, 100); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("SetExpiry(native) code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+	fees := NewTransferFeeRegistry(store)
+	if err := fees.SetTransferFee(native, contractA, 100, contractB); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("SetTransferFee(native) code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+	rebases := NewRebaseRegistry(store)
+	if _, err := rebases.Rebase(native, contractA, 5000); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Rebase(native) code = %d, want ERROR_INVALID_PARAMS", errCode(err))
+	}
+
+	// 原生币转账不收手续费、不缩放
+	if _, fee, net, err := fees.Quote(native, feeSender, 1000); err != nil || fee != 0 || net != 1000 {
+		t.Errorf("Quote(native) = fee %d, net %d, %v, want 0, 1000", fee, net, err)
+	}
+	if state, ok, err := rebases.Lookup(native); err != nil || ok || state.ScalingFactor != REBASE_FACTOR_ONE {
+		t.Errorf("Rebase Lookup(native) = %+v, %v, %v, want identity", state, ok, err)
+	}
+}
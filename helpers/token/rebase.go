@@ -0,0 +1,175 @@
+package token
+
+import (
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 弹性供应（Rebase）代币 ====================
+//
+// Rebase 代币按比例调整所有持有人的余额。UTXO 无法被批量改写，因此采用份额模型：
+//   - UTXO 中记录的是份额（shares），QueryUTXOBalance 返回份额
+//   - 每个代币登记一个缩放系数（scalingFactor，定点数，REBASE_FACTOR_ONE 表示 1.0）
+//   - 对外余额 = shares × scalingFactor / REBASE_FACTOR_ONE；Rebase 只修改缩放系数，不触碰任何 UTXO
+//
+// 本文件不带 build tag，缩放系数与份额换算可在非WASM环境中直接测试；
+// Rebase / BalanceOf 与 Transfer / Mint / Burn 的份额换算见 rebase_host.go。
+// 未 Rebase 过的代币（含原生币）缩放系数为 1.0，份额即余额，已有代币不受影响。
+
+const (
+	// REBASE_FACTOR_ONE 缩放系数 1.0（定点数精度 1e9）
+	REBASE_FACTOR_ONE uint64 = 1000000000
+
+	// tokenRebaseStatePrefix 缩放系数记录状态ID前缀，完整格式：token_rebase:{tokenID}
+	tokenRebaseStatePrefix = "token_rebase:"
+	// tokenRebaseRecordVersion 缩放系数记录格式版本（非零值，避免链上读取去掉尾部零字节）
+	tokenRebaseRecordVersion byte = 1
+)
+
+// RebaseState 代币的缩放系数登记
+type RebaseState struct {
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Issuer 登记缩放系数的发行方（第一次 Rebase 的合约地址），只有发行方可以继续 Rebase
+	Issuer framework.Address
+	// ScalingFactor 缩放系数（定点数，REBASE_FACTOR_ONE 表示 1.0）
+	ScalingFactor uint64
+}
+
+// BalanceOf 将份额换算为余额：shares × ScalingFactor / REBASE_FACTOR_ONE（向下取整）
+func (s RebaseState) BalanceOf(shares framework.Amount) framework.Amount {
+	return framework.Amount(mulDivDown(uint64(shares), s.ScalingFactor, REBASE_FACTOR_ONE))
+}
+
+// SharesFor 将余额换算为份额：amount × REBASE_FACTOR_ONE / ScalingFactor（向下取整）
+//
+// **返回**：
+//   - error: 金额小于 1 份对应的余额（换算为 0 份）时返回 ERROR_INVALID_PARAMS
+func (s RebaseState) SharesFor(amount framework.Amount) (framework.Amount, error) {
+	shares := mulDivDown(uint64(amount), REBASE_FACTOR_ONE, s.ScalingFactor)
+	if shares == 0 {
+		return 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount is smaller than one share")
+	}
+	return framework.Amount(shares), nil
+}
+
+// RebaseRegistry 代币缩放系数注册表
+//
+// 记录存放在 store 中（状态ID token_rebase:{tokenID}）。
+type RebaseRegistry struct {
+	store subaccount.Store
+}
+
+// NewRebaseRegistry 创建使用指定存储后端的缩放系数注册表
+func NewRebaseRegistry(store subaccount.Store) *RebaseRegistry {
+	return &RebaseRegistry{store: store}
+}
+
+// Rebase 按 factorBP 调整代币的缩放系数：newFactor = oldFactor × factorBP / 10000
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持 Rebase）
+//   - issuer: 发行方
+//   - factorBP: 调整系数（万分比），10500 表示所有余额增加 5%，9500 表示减少 5%，必须大于 0
+//
+// **返回**：
+//   - RebaseState: 调整后的缩放系数登记
+//   - error: 参数无效或调整后缩放系数为 0 / 溢出返回 ERROR_INVALID_PARAMS；
+//     代币已由其他发行方登记返回 ERROR_PERMISSION_DENIED
+func (r *RebaseRegistry) Rebase(tokenID framework.TokenID, issuer framework.Address, factorBP framework.BasisPoints) (RebaseState, error) {
+	if framework.IsNativeToken(tokenID) {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID cannot be empty")
+	}
+	if issuer == (framework.Address{}) {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "issuer cannot be zero")
+	}
+	if factorBP == 0 {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "rebase factor must be greater than 0")
+	}
+
+	key := TokenRebaseStateID(tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return RebaseState{}, err
+	}
+	state, ok := decodeRebaseState(tokenID, data)
+	if !ok {
+		state = RebaseState{TokenID: tokenID, Issuer: issuer, ScalingFactor: REBASE_FACTOR_ONE}
+	} else if state.Issuer != issuer {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "token "+string(tokenID)+" rebase is registered to another issuer")
+	}
+
+	hi, lo := bits.Mul64(state.ScalingFactor, uint64(factorBP))
+	if hi >= uint64(framework.MAX_BASIS_POINTS) {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "scaling factor overflows")
+	}
+	factor, _ := bits.Div64(hi, lo, uint64(framework.MAX_BASIS_POINTS))
+	if factor == 0 {
+		return RebaseState{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "scaling factor would reach zero")
+	}
+	state.ScalingFactor = factor
+
+	if err := r.store.Save(key, version+1, encodeRebaseState(state)); err != nil {
+		return RebaseState{}, err
+	}
+	return state, nil
+}
+
+// Lookup 查询代币的缩放系数登记；未 Rebase 过的代币返回系数 1.0 与 false
+func (r *RebaseRegistry) Lookup(tokenID framework.TokenID) (RebaseState, bool, error) {
+	identity := RebaseState{TokenID: tokenID, ScalingFactor: REBASE_FACTOR_ONE}
+	if framework.IsNativeToken(tokenID) {
+		return identity, false, nil
+	}
+	data, _, err := r.store.Load(TokenRebaseStateID(tokenID))
+	if err != nil {
+		return identity, false, err
+	}
+	state, ok := decodeRebaseState(tokenID, data)
+	if !ok {
+		return identity, false, nil
+	}
+	return state, true, nil
+}
+
+// TokenRebaseStateID 返回缩放系数记录的状态ID
+func TokenRebaseStateID(tokenID framework.TokenID) string {
+	return tokenRebaseStatePrefix + string(tokenID)
+}
+
+// mulDivDown 返回 a × b / d（向下取整），中间结果按 128 位计算，结果超过 uint64 时截断为最大值
+func mulDivDown(a, b, d uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= d {
+		return ^uint64(0)
+	}
+	q, _ := bits.Div64(hi, lo, d)
+	return q
+}
+
+// encodeRebaseState 编码缩放系数记录
+//
+// 编码格式（大端）：issuer(20) + scaling_factor(8) + version(1)
+func encodeRebaseState(s RebaseState) []byte {
+	data := make([]byte, 0, 29)
+	data = append(data, s.Issuer[:]...)
+	for shift := 56; shift >= 0; shift -= 8 {
+		data = append(data, byte(s.ScalingFactor>>uint(shift)))
+	}
+	return append(data, tokenRebaseRecordVersion)
+}
+
+// decodeRebaseState 解码缩放系数记录；记录不存在或格式无效时返回 false
+func decodeRebaseState(tokenID framework.TokenID, data []byte) (RebaseState, bool) {
+	if len(data) < 29 || data[28] != tokenRebaseRecordVersion {
+		return RebaseState{}, false
+	}
+	s := RebaseState{TokenID: tokenID}
+	copy(s.Issuer[:], data[:20])
+	for _, b := range data[20:28] {
+		s.ScalingFactor = s.ScalingFactor<<8 | uint64(b)
+	}
+	return s, s.ScalingFactor != 0
+}
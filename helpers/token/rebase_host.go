@@ -0,0 +1,76 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// TOKEN_OWNER_ROLE 可对代币执行 Rebase 的角色
+const TOKEN_OWNER_ROLE = "token_owner"
+
+// rebaseRegistry 基于链上状态的缩放系数注册表
+var rebaseRegistry = NewRebaseRegistry(hostStateStore{})
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: TOKEN_OWNER_ROLE})
+}
+
+// Rebase 按比例调整代币所有持有人的余额
+//
+// 🎯 **用途**：弹性供应代币（算法稳定币、收益累积代币）按比例扩张或收缩全部余额
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持 Rebase）
+//   - factorBP: 调整系数（万分比），10500 表示所有余额增加 5%，9500 表示减少 5%，必须大于 0
+//
+// **返回**：
+//   - error: 调用者不是代币所有者（ERROR_UNAUTHORIZED）、参数无效或缩放系数溢出 / 归零（ERROR_INVALID_PARAMS）、
+//     缩放系数已由其他合约登记（ERROR_PERMISSION_DENIED）
+//
+// **注意**：
+//   - 调用者取自 framework.GetCaller()，须持有 TOKEN_OWNER_ROLE 角色（合约在 Initialize 中通过 framework.InitRole 设置）
+//   - Rebase 只修改缩放系数（token_rebase:{tokenID}），不改写任何 UTXO：UTXO 中记录的是份额，
+//     QueryUTXOBalance 返回份额，钱包与浏览器直接读取 UTXO 时看到的是份额而不是余额
+//   - BalanceOf 返回 shares × scalingFactor；Transfer / Mint / Burn 的 amount 按余额计，自动换算为份额（向下取整），
+//     Airdrop / BatchMint / Approve / Freeze 与 BalanceOfAt 仍按份额计
+//   - 成功后发出 Rebase 事件
+//
+// **示例**：
+//
+//	// 供应扩张 5%
+//	if err := token.Rebase(tokenID, framework.BasisPoints(10500)); err != nil {
+//	    return framework.ERROR_UNAUTHORIZED
+//	}
+func Rebase(tokenID framework.TokenID, factorBP framework.BasisPoints) error {
+	caller := framework.GetCaller()
+	if !framework.HasRole(TOKEN_OWNER_ROLE, caller) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the token owner can rebase")
+	}
+
+	state, err := rebaseRegistry.Rebase(tokenID, framework.GetContractAddress(), factorBP)
+	if err != nil {
+		return err
+	}
+
+	event := framework.NewEvent("Rebase")
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("factor_bp", uint64(factorBP))
+	event.AddUint64Field("scaling_factor", state.ScalingFactor)
+	event.AddAddressField("caller", caller)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// GetScalingFactor 查询代币的缩放系数（REBASE_FACTOR_ONE 表示 1.0），未 Rebase 过的代币返回 REBASE_FACTOR_ONE
+func GetScalingFactor(tokenID framework.TokenID) uint64 {
+	state, _, _ := rebaseRegistry.Lookup(tokenID)
+	return state.ScalingFactor
+}
+
+// rebaseState 查询代币的缩放系数登记，读取失败时按系数 1.0 处理
+func rebaseState(tokenID framework.TokenID) RebaseState {
+	state, _, _ := rebaseRegistry.Lookup(tokenID)
+	return state
+}
@@ -0,0 +1,104 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+const testRebaseToken framework.TokenID = "ELASTIC"
+
+// TestRebaseAdjustsBalancesProportionally 测试正向与负向 Rebase 按比例调整所有持有人的余额，份额不变
+func TestRebaseAdjustsBalancesProportionally(t *testing.T) {
+	registry := NewRebaseRegistry(subaccount.NewMemoryStore())
+	shares := map[framework.Address]framework.Amount{holderAlice: 1000, holderBob: 3000}
+
+	steps := []struct {
+		name     string
+		factorBP framework.BasisPoints
+		alice    framework.Amount
+		bob      framework.Amount
+	}{
+		{"expand 10%", 11000, 1100, 3300},
+		{"contract 20%", 8000, 880, 2640},
+		{"expand 25%", 12500, 1100, 3300},
+	}
+	for _, step := range steps {
+		state, err := registry.Rebase(testRebaseToken, contractA, step.factorBP)
+		if err != nil {
+			t.Fatalf("%s: Rebase() error = %v", step.name, err)
+		}
+		if got := state.BalanceOf(shares[holderAlice]); got != step.alice {
+			t.Errorf("%s: alice balance = %d, want %d", step.name, got, step.alice)
+		}
+		if got := state.BalanceOf(shares[holderBob]); got != step.bob {
+			t.Errorf("%s: bob balance = %d, want %d", step.name, got, step.bob)
+		}
+	}
+
+	// 缩放后按余额转账：换算的份额与余额一致
+	state, _, _ := registry.Lookup(testRebaseToken)
+	moved, err := state.SharesFor(550)
+	if err != nil || moved != 500 {
+		t.Fatalf("SharesFor(550) = %d, %v, want 500", moved, err)
+	}
+	shares[holderAlice] -= moved
+	shares[holderBob] += moved
+	if alice, bob := state.BalanceOf(shares[holderAlice]), state.BalanceOf(shares[holderBob]); alice != 550 || bob != 3850 {
+		t.Errorf("balances after transfer = %d, %d, want 550, 3850", alice, bob)
+	}
+}
+
+// TestRebaseValidation 测试调整系数为 0、缩放系数归零、其他发行方与未 Rebase 的代币
+func TestRebaseValidation(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewRebaseRegistry(chain)
+
+	if _, err := registry.Rebase(testRebaseToken, contractA, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("zero factor Rebase() error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if _, err := registry.Rebase("", contractA, 10500); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("native token Rebase() error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if _, err := registry.Rebase(testRebaseToken, contractA, 10500); err != nil {
+		t.Fatalf("Rebase() error = %v", err)
+	}
+	if _, err := registry.Rebase(testRebaseToken, contractB, 10500); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("other issuer Rebase() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+
+	// 反复收缩直到缩放系数归零时拒绝，已登记的系数保持不变
+	var err error
+	for i := 0; i < 20 && err == nil; i++ {
+		_, err = registry.Rebase(testRebaseToken, contractA, 1)
+	}
+	if errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("Rebase() to zero error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if state, _, _ := registry.Lookup(testRebaseToken); state.ScalingFactor == 0 {
+		t.Error("ScalingFactor reached zero")
+	}
+
+	// 未 Rebase 的代币与原生币份额即余额
+	for _, tokenID := range []framework.TokenID{"default", ""} {
+		state, ok, err := registry.Lookup(tokenID)
+		if err != nil || ok || state.BalanceOf(777) != 777 {
+			t.Errorf("Lookup(%q) = %+v, %v, %v, want identity", tokenID, state, ok, err)
+		}
+		if shares, err := state.SharesFor(777); err != nil || shares != 777 {
+			t.Errorf("identity SharesFor(777) = %d, %v", shares, err)
+		}
+	}
+}
+
+// TestRebaseSharesForDust 测试小于 1 份对应余额的金额被拒绝
+func TestRebaseSharesForDust(t *testing.T) {
+	state := RebaseState{TokenID: testRebaseToken, ScalingFactor: 3 * REBASE_FACTOR_ONE}
+	if _, err := state.SharesFor(2); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("SharesFor(2) error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if shares, err := state.SharesFor(7); err != nil || shares != 2 {
+		t.Errorf("SharesFor(7) = %d, %v, want 2 (rounded down)", shares, err)
+	}
+}
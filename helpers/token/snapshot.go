@@ -0,0 +1,175 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 余额快照 ====================
+//
+// 治理投票快照与分红基准点都需要历史余额。Snapshot 递增全局快照ID，之后每个地址的余额
+// 第一次变化前记录一条检查点（快照ID, 变化前余额）：
+//   - 快照之后余额未变化的地址没有对应检查点，历史余额等于当前余额
+//   - 每个快照ID对每个 (地址, 代币) 至多记录一条，检查点按快照ID递增排列
+//
+// 本文件不带 build tag，检查点逻辑可在非WASM环境中直接测试；
+// 包级函数（Snapshot / BalanceOfAt）与 Transfer / Mint / Burn 的检查点更新见 snapshot_host.go。
+
+const (
+	// tokenSnapshotSeqStateID 当前快照ID状态（十进制字符串），尚未创建快照时不存在
+	tokenSnapshotSeqStateID = "token_snapshot_seq"
+	// tokenCheckpointStatePrefix 余额检查点状态ID前缀，完整格式：token_checkpoint:{owner}:{tokenID}
+	tokenCheckpointStatePrefix = "token_checkpoint:"
+	// tokenCheckpointRecordVersion 检查点记录格式版本（放在末尾，避免链上读取去掉尾部零字节）
+	tokenCheckpointRecordVersion byte = 1
+	// balanceCheckpointSize 单条检查点：snapshot_id(8) + balance(8)
+	balanceCheckpointSize = 16
+)
+
+// BalanceCheckpoint 一条余额检查点：快照 SnapshotID 时的余额
+type BalanceCheckpoint struct {
+	// SnapshotID 快照ID
+	SnapshotID uint64
+	// Balance 该快照时的余额（快照后第一次变化前的余额）
+	Balance framework.Amount
+}
+
+// SnapshotRegistry 快照ID与余额检查点
+type SnapshotRegistry struct {
+	store subaccount.Store
+}
+
+// NewSnapshotRegistry 创建使用指定存储后端的快照注册表
+func NewSnapshotRegistry(store subaccount.Store) *SnapshotRegistry {
+	return &SnapshotRegistry{store: store}
+}
+
+// CurrentSnapshot 返回最近一次快照的ID，尚未创建快照时返回 0
+func (r *SnapshotRegistry) CurrentSnapshot() (uint64, error) {
+	data, _, err := r.store.Load(tokenSnapshotSeqStateID)
+	if err != nil {
+		return 0, err
+	}
+	return framework.ParseUint64(string(data)), nil
+}
+
+// Snapshot 创建新快照，返回快照ID（从 1 开始递增）
+func (r *SnapshotRegistry) Snapshot() (uint64, error) {
+	data, version, err := r.store.Load(tokenSnapshotSeqStateID)
+	if err != nil {
+		return 0, err
+	}
+	id := framework.ParseUint64(string(data)) + 1
+	if err := r.store.Save(tokenSnapshotSeqStateID, version+1, []byte(framework.Uint64ToString(id))); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateBalance 在余额变化前调用，为当前快照记录变化前的余额
+//
+// **参数**：
+//   - owner: 余额将要变化的地址
+//   - tokenID: 代币ID
+//   - before: 变化前的余额
+//
+// **注意**：尚未创建快照、或当前快照已记录过检查点时不写入状态
+func (r *SnapshotRegistry) UpdateBalance(owner framework.Address, tokenID framework.TokenID, before framework.Amount) error {
+	current, err := r.CurrentSnapshot()
+	if err != nil || current == 0 {
+		return err
+	}
+
+	key := TokenCheckpointStateID(owner, tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return err
+	}
+	checkpoints := decodeBalanceCheckpoints(data)
+	if n := len(checkpoints); n > 0 && checkpoints[n-1].SnapshotID >= current {
+		return nil
+	}
+	checkpoints = append(checkpoints, BalanceCheckpoint{SnapshotID: current, Balance: before})
+	return r.store.Save(key, version+1, encodeBalanceCheckpoints(checkpoints))
+}
+
+// BalanceAt 查询地址在快照 snapshotID 时的余额
+//
+// **参数**：
+//   - snapshotID: 快照ID（Snapshot 的返回值）
+//   - current: 当前余额，快照后余额未变化时即为快照时的余额
+//
+// **返回**：
+//   - error: snapshotID 为 0 返回 ERROR_INVALID_PARAMS；快照尚未创建返回 ERROR_NOT_FOUND
+func (r *SnapshotRegistry) BalanceAt(owner framework.Address, tokenID framework.TokenID, snapshotID uint64, current framework.Amount) (framework.Amount, error) {
+	if snapshotID == 0 {
+		return 0, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "snapshot id must be greater than 0")
+	}
+	latest, err := r.CurrentSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	if snapshotID > latest {
+		return 0, framework.NewContractError(framework.ERROR_NOT_FOUND, "snapshot "+framework.Uint64ToString(snapshotID)+" does not exist")
+	}
+
+	checkpoints, err := r.Checkpoints(owner, tokenID)
+	if err != nil {
+		return 0, err
+	}
+	// 快照 snapshotID 之后的第一次变化记录了快照时的余额
+	for _, cp := range checkpoints {
+		if cp.SnapshotID >= snapshotID {
+			return cp.Balance, nil
+		}
+	}
+	return current, nil
+}
+
+// Checkpoints 返回 (地址, 代币) 的全部检查点，按快照ID递增
+func (r *SnapshotRegistry) Checkpoints(owner framework.Address, tokenID framework.TokenID) ([]BalanceCheckpoint, error) {
+	data, _, err := r.store.Load(TokenCheckpointStateID(owner, tokenID))
+	if err != nil {
+		return nil, err
+	}
+	return decodeBalanceCheckpoints(data), nil
+}
+
+// TokenCheckpointStateID 返回余额检查点的状态ID
+func TokenCheckpointStateID(owner framework.Address, tokenID framework.TokenID) string {
+	return tokenCheckpointStatePrefix + owner.ToString() + ":" + string(tokenID)
+}
+
+// encodeBalanceCheckpoints 编码：逐条 [snapshot_id(8,大端) | balance(8,大端)] + version(1)
+func encodeBalanceCheckpoints(checkpoints []BalanceCheckpoint) []byte {
+	data := make([]byte, 0, len(checkpoints)*balanceCheckpointSize+1)
+	for _, cp := range checkpoints {
+		for shift := 56; shift >= 0; shift -= 8 {
+			data = append(data, byte(cp.SnapshotID>>uint(shift)))
+		}
+		for shift := 56; shift >= 0; shift -= 8 {
+			data = append(data, byte(uint64(cp.Balance)>>uint(shift)))
+		}
+	}
+	return append(data, tokenCheckpointRecordVersion)
+}
+
+// decodeBalanceCheckpoints 解码检查点；记录不存在或格式无效时返回空列表
+func decodeBalanceCheckpoints(data []byte) []BalanceCheckpoint {
+	n := len(data) - 1
+	if n < 0 || data[n] != tokenCheckpointRecordVersion || n%balanceCheckpointSize != 0 {
+		return nil
+	}
+	checkpoints := make([]BalanceCheckpoint, 0, n/balanceCheckpointSize)
+	for off := 0; off < n; off += balanceCheckpointSize {
+		var id, balance uint64
+		for _, b := range data[off : off+8] {
+			id = id<<8 | uint64(b)
+		}
+		for _, b := range data[off+8 : off+balanceCheckpointSize] {
+			balance = balance<<8 | uint64(b)
+		}
+		checkpoints = append(checkpoints, BalanceCheckpoint{SnapshotID: id, Balance: framework.Amount(balance)})
+	}
+	return checkpoints
+}
@@ -0,0 +1,67 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// snapshotRegistry 基于链上状态的快照注册表
+var snapshotRegistry = NewSnapshotRegistry(hostStateStore{})
+
+// Snapshot 创建余额快照
+//
+// 🎯 **用途**：治理投票快照、分红基准点——记录当前时刻所有地址、所有代币的余额，之后通过 BalanceOfAt 查询
+//
+// **返回**：
+//   - snapshotID: 快照ID（从 1 开始递增），写入状态失败时返回 0
+//
+// **注意**：
+//   - 快照不复制余额，只递增快照ID（token_snapshot_seq）；之后 Transfer、Mint、Burn、BatchMint、Airdrop
+//     在余额第一次变化前记录检查点（token_checkpoint:{owner}:{tokenID}）
+//   - 成功后发出 TokenSnapshot 事件
+//
+// **示例**：
+//
+//	// 创建提案时记录投票权快照
+//	snapshotID := token.Snapshot()
+//	if snapshotID == 0 {
+//	    return framework.ERROR_EXECUTION_FAILED
+//	}
+//	// 投票时按快照余额计票
+//	weight, err := token.BalanceOfAt(voter, govToken, snapshotID)
+func Snapshot() (snapshotID uint64) {
+	id, err := snapshotRegistry.Snapshot()
+	if err != nil {
+		return 0
+	}
+
+	event := framework.NewEvent("TokenSnapshot")
+	event.AddUint64Field("snapshot_id", id)
+	event.AddUint64Field("timestamp", framework.GetTimestamp())
+	framework.EmitEvent(event)
+
+	return id
+}
+
+// BalanceOfAt 查询地址在快照时的余额
+//
+// **参数**：
+//   - addr: 查询地址
+//   - tokenID: 代币ID
+//   - snapshotID: 快照ID（Snapshot 的返回值）
+//
+// **返回**：
+//   - framework.Amount: 快照时的 UTXO 余额（不按到期折算，Rebase 代币为份额）
+//   - error: snapshotID 为 0（ERROR_INVALID_PARAMS）、快照尚未创建（ERROR_NOT_FOUND）
+func BalanceOfAt(addr framework.Address, tokenID framework.TokenID, snapshotID uint64) (framework.Amount, error) {
+	return snapshotRegistry.BalanceAt(addr, tokenID, snapshotID, framework.QueryUTXOBalance(addr, tokenID))
+}
+
+// checkpointBalance 在余额变化前记录当前快照的检查点（Transfer / Mint / Burn / BatchMint / Airdrop 共用）
+func checkpointBalance(owner framework.Address, tokenID framework.TokenID) error {
+	if err := snapshotRegistry.UpdateBalance(owner, tokenID, framework.QueryUTXOBalance(owner, tokenID)); err != nil {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "failed to save balance checkpoint")
+	}
+	return nil
+}
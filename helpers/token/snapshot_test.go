@@ -0,0 +1,145 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+const testGovToken framework.TokenID = "GOV"
+
+var (
+	holderAlice = framework.Address{0x61}
+	holderBob   = framework.Address{0x62}
+)
+
+// snapshotLedger 按 Transfer / Mint / Burn 的顺序模拟余额变化：先记录检查点，再修改余额
+type snapshotLedger struct {
+	t        *testing.T
+	registry *SnapshotRegistry
+	balances map[framework.Address]framework.Amount
+}
+
+func (l *snapshotLedger) change(addr framework.Address, delta func(framework.Amount) framework.Amount) {
+	l.t.Helper()
+	if err := l.registry.UpdateBalance(addr, testGovToken, l.balances[addr]); err != nil {
+		l.t.Fatalf("UpdateBalance() error = %v", err)
+	}
+	l.balances[addr] = delta(l.balances[addr])
+}
+
+func (l *snapshotLedger) mint(to framework.Address, amount framework.Amount) {
+	l.change(to, func(b framework.Amount) framework.Amount { return b + amount })
+}
+
+func (l *snapshotLedger) transfer(from, to framework.Address, amount framework.Amount) {
+	l.change(from, func(b framework.Amount) framework.Amount { return b - amount })
+	l.change(to, func(b framework.Amount) framework.Amount { return b + amount })
+}
+
+func (l *snapshotLedger) burn(from framework.Address, amount framework.Amount) {
+	l.change(from, func(b framework.Amount) framework.Amount { return b - amount })
+}
+
+func (l *snapshotLedger) snapshot() uint64 {
+	l.t.Helper()
+	id, err := l.registry.Snapshot()
+	if err != nil {
+		l.t.Fatalf("Snapshot() error = %v", err)
+	}
+	return id
+}
+
+func (l *snapshotLedger) balanceAt(addr framework.Address, id uint64) framework.Amount {
+	l.t.Helper()
+	balance, err := l.registry.BalanceAt(addr, testGovToken, id, l.balances[addr])
+	if err != nil {
+		l.t.Fatalf("BalanceAt(%d) error = %v", id, err)
+	}
+	return balance
+}
+
+// TestBalanceAtPreservedAfterTransfers 测试快照前的余额在之后的转账、铸造与销毁后保持不变
+func TestBalanceAtPreservedAfterTransfers(t *testing.T) {
+	ledger := &snapshotLedger{t: t, registry: NewSnapshotRegistry(subaccount.NewMemoryStore()), balances: map[framework.Address]framework.Amount{}}
+
+	// 快照前的变化不记录检查点
+	ledger.mint(holderAlice, 1000)
+	if cps, _ := ledger.registry.Checkpoints(holderAlice, testGovToken); len(cps) != 0 {
+		t.Errorf("checkpoints before any snapshot = %+v, want none", cps)
+	}
+
+	first := ledger.snapshot()
+	ledger.transfer(holderAlice, holderBob, 300)
+	ledger.transfer(holderAlice, holderBob, 200)
+	second := ledger.snapshot()
+	ledger.burn(holderBob, 100)
+	ledger.mint(holderAlice, 50)
+	third := ledger.snapshot()
+
+	tests := []struct {
+		name string
+		addr framework.Address
+		id   uint64
+		want framework.Amount
+	}{
+		{"alice at first", holderAlice, first, 1000},
+		{"bob at first", holderBob, first, 0},
+		{"alice at second", holderAlice, second, 500},
+		{"bob at second", holderBob, second, 500},
+		{"alice at third (unchanged since)", holderAlice, third, 550},
+		{"bob at third (unchanged since)", holderBob, third, 400},
+	}
+	for _, tt := range tests {
+		if got := ledger.balanceAt(tt.addr, tt.id); got != tt.want {
+			t.Errorf("%s: BalanceAt() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// 之后的转账不改变已有快照
+	ledger.transfer(holderBob, holderAlice, 400)
+	if got := ledger.balanceAt(holderBob, third); got != 400 {
+		t.Errorf("bob at third after later transfer = %d, want 400", got)
+	}
+	if got := ledger.balanceAt(holderAlice, first); got != 1000 {
+		t.Errorf("alice at first after later transfer = %d, want 1000", got)
+	}
+
+	// 同一快照内多次变化只记录一条检查点
+	if cps, _ := ledger.registry.Checkpoints(holderAlice, testGovToken); len(cps) != 3 {
+		t.Errorf("alice checkpoints = %+v, want one per snapshot", cps)
+	}
+}
+
+// TestBalanceAtValidation 测试快照ID为 0 与尚未创建的快照
+func TestBalanceAtValidation(t *testing.T) {
+	registry := NewSnapshotRegistry(subaccount.NewMemoryStore())
+	if _, err := registry.BalanceAt(holderAlice, testGovToken, 1, 100); errCode(err) != framework.ERROR_NOT_FOUND {
+		t.Errorf("BalanceAt() before any snapshot error = %v, want ERROR_NOT_FOUND", err)
+	}
+	if _, err := registry.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if _, err := registry.BalanceAt(holderAlice, testGovToken, 0, 100); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("BalanceAt(0) error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+	if _, err := registry.BalanceAt(holderAlice, testGovToken, 2, 100); errCode(err) != framework.ERROR_NOT_FOUND {
+		t.Errorf("BalanceAt(2) error = %v, want ERROR_NOT_FOUND", err)
+	}
+	if current, _ := registry.CurrentSnapshot(); current != 1 {
+		t.Errorf("CurrentSnapshot() = %d, want 1", current)
+	}
+}
+
+// TestBalanceCheckpointCodec 测试检查点编解码，零余额结尾的记录不会被截断
+func TestBalanceCheckpointCodec(t *testing.T) {
+	checkpoints := []BalanceCheckpoint{{SnapshotID: 1, Balance: 1 << 40}, {SnapshotID: 3, Balance: 0}}
+	decoded := decodeBalanceCheckpoints(encodeBalanceCheckpoints(checkpoints))
+	if len(decoded) != 2 || decoded[0] != checkpoints[0] || decoded[1] != checkpoints[1] {
+		t.Errorf("round trip = %+v, want %+v", decoded, checkpoints)
+	}
+	if decoded := decodeBalanceCheckpoints(nil); decoded != nil {
+		t.Errorf("decode(nil) = %+v, want nil", decoded)
+	}
+}
@@ -35,7 +35,7 @@ import (
 //	    
 //	    err := token.MintWithState(
 //	        caller,
-//	        framework.TokenID(""),
+//	        framework.NativeTokenID(),
 //	        framework.Amount(1000),
 //	        balanceKey,
 //	    )
@@ -49,6 +49,9 @@ func MintWithState(to framework.Address, tokenID framework.TokenID, amount frame
 	if err := validateMintParams(to, tokenID, amount); err != nil {
 		return err
 	}
+	if err := checkClassMint(tokenID); err != nil {
+		return err
+	}
 
 	// 2. 从链上读取当前余额
 	stateID := []byte(balanceStateKey)
@@ -127,7 +130,7 @@ func MintWithState(to framework.Address, tokenID framework.TokenID, amount frame
 //	    err = token.TransferWithState(
 //	        caller,
 //	        to,
-//	        framework.TokenID(""),
+//	        framework.NativeTokenID(),
 //	        framework.Amount(amount),
 //	        "balance_",
 //	    )
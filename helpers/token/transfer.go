@@ -13,19 +13,26 @@ import (
 // **参数**：
 //   - from: 发送者地址
 //   - to: 接收者地址
-//   - tokenID: 代币ID（nil表示原生币）
+//   - tokenID: 代币ID（framework.NativeTokenID() 表示原生币）
 //   - amount: 转账金额
 //
 // **返回**：
 //   - error: 错误信息，nil表示成功
 //
+// **注意**：
+//   - 代币登记了转账钩子（SetTransferHook）时先按钩子检查：暂停返回 ERROR_PAUSED，
+//     灵魂绑定或 KYC 未通过返回 ERROR_PERMISSION_DENIED
+//   - 代币开启手续费模式（SetTransferFee）时，手续费从 amount 中扣除转给收款地址，
+//     接收者收到 amount - fee，并另发出 TransferFee 事件
+//   - Rebase 代币的 amount 按余额计，转账的 UTXO 份额为 amount / scalingFactor（向下取整）
+//
 // **示例**：
 //
 //	func Transfer() uint32 {
 //	    err := token.Transfer(
 //	        framework.GetCaller(),
 //	        recipientAddr,
-//	        nil,  // 原生币
+//	        framework.NativeTokenID(), // 原生币
 //	        framework.Amount(100),
 //	    )
 //	    if err != nil {
@@ -34,57 +41,95 @@ import (
 //	    return framework.SUCCESS
 //	}
 func Transfer(from, to framework.Address, tokenID framework.TokenID, amount framework.Amount) error {
-	// 1. 参数验证
+	// 1. 参数验证与转账钩子检查
 	if err := validateTransferParams(from, to, amount); err != nil {
 		return err
 	}
+	if err := transferHookRegistry.CheckTransfer(tokenID, from, to); err != nil {
+		return err
+	}
 
-	// 2. 查询余额（通过framework）
-	balance := framework.QueryUTXOBalance(from, tokenID)
-	if balance < amount {
+	// 2. 换算份额（Rebase 代币按缩放系数换算，其他代币份额即金额），查询有效余额（到期代币按 0 处理）
+	rebase := rebaseState(tokenID)
+	shares, err := rebase.SharesFor(amount)
+	if err != nil {
+		return err
+	}
+	balance := spendableBalance(from, tokenID)
+	if balance < shares {
 		return framework.NewContractError(
 			framework.ERROR_INSUFFICIENT_BALANCE,
 			"insufficient balance",
 		)
 	}
 
-	// 3. 构建交易（使用internal包链式API）
-	success, _, errCode := framework.BeginTransaction().
-		Transfer(from, to, tokenID, amount).
-		Finalize()
+	// 3. 计算转账手续费（未开启手续费模式的代币 fee 为 0）
+	feeConfig, fee, net, err := transferFeeRegistry.Quote(tokenID, from, shares)
+	if err != nil {
+		return err
+	}
+
+	// 4. 记录余额快照检查点（变化前余额）
+	parties := []framework.Address{from, to}
+	if fee > 0 {
+		parties = append(parties, feeConfig.Collector)
+	}
+	for _, addr := range parties {
+		if err := checkpointBalance(addr, tokenID); err != nil {
+			return err
+		}
+	}
+
+	// 5. 构建交易（使用internal包链式API）
+	builder := framework.BeginTransaction().
+		Transfer(from, to, tokenID, net)
+	if fee > 0 {
+		builder = builder.Transfer(from, feeConfig.Collector, tokenID, fee)
+	}
+	success, _, errCode := builder.Finalize()
 
 	if !success {
 		return framework.NewContractError(errCode, "transfer failed")
 	}
 
-	// 4. 发出转账事件
+	// 6. 发出转账事件（amount 为接收者实际到账金额）
 	event := framework.NewEvent("Transfer")
 	event.AddAddressField("from", from)
 	event.AddAddressField("to", to)
 	event.AddStringField("token_id", string(tokenID))
-	event.AddUint64Field("amount", uint64(amount))
+	event.AddUint64Field("amount", uint64(rebase.BalanceOf(net)))
 	framework.EmitEvent(event)
 
+	// 7. 发出手续费事件
+	if fee > 0 {
+		feeEvent := framework.NewEvent("TransferFee")
+		feeEvent.AddAddressField("from", from)
+		feeEvent.AddAddressField("collector", feeConfig.Collector)
+		feeEvent.AddStringField("token_id", string(tokenID))
+		feeEvent.AddUint64Field("fee", uint64(rebase.BalanceOf(fee)))
+		feeEvent.AddUint64Field("transfer_fee_bp", uint64(feeConfig.FeeBP))
+		framework.EmitEvent(feeEvent)
+	}
+
 	return nil
 }
 
 // validateTransferParams 验证转账参数
 func validateTransferParams(from, to framework.Address, amount framework.Amount) error {
 	// 验证地址
-	zeroAddr := framework.Address{}
-	if from == zeroAddr {
+	if from.IsZero() {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"from address cannot be zero",
 		)
 	}
-	if to == zeroAddr {
+	if to.IsZero() {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"to address cannot be zero",
 		)
 	}
-	if from == to {
+	if from.Equals(to) {
 		return framework.NewContractError(
 			framework.ERROR_INVALID_PARAMS,
 			"from and to addresses cannot be the same",
@@ -0,0 +1,176 @@
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ==================== 转账限制钩子 ====================
+//
+// 部分代币需要在转账前做统一限制：紧急暂停、仅限通过 KYC 的地址之间流转、不可转让（灵魂绑定）。
+// 发行方按代币ID登记一种钩子类型，Transfer 在执行前调用 CheckTransfer。本文件提供：
+//   - TransferHookRegistry：钩子类型的登记、查询与转账检查
+//
+// 本文件不带 build tag，钩子逻辑可在非WASM环境中直接测试；
+// 钩子设置与包级查询（SetTransferHook / GetTransferHook / SetKYCVerifier）见 transfer_hook_host.go。
+// 未登记钩子的代币（含原生币）不做限制，已有代币不受影响。
+
+const (
+	// TRANSFER_HOOK_NONE 不做限制（用于解除已登记的钩子）
+	TRANSFER_HOOK_NONE = "none"
+	// TRANSFER_HOOK_PAUSE 暂停：拒绝所有转账
+	TRANSFER_HOOK_PAUSE = "pause"
+	// TRANSFER_HOOK_KYC 发送方与接收方都须通过 KYC 验证
+	TRANSFER_HOOK_KYC = "kyc"
+	// TRANSFER_HOOK_SOULBOUND 灵魂绑定：代币不可转让（铸造与销毁不受影响）
+	TRANSFER_HOOK_SOULBOUND = "soulbound"
+)
+
+const (
+	// tokenTransferHookStatePrefix 钩子记录状态ID前缀，完整格式：token_transfer_hook:{tokenID}
+	tokenTransferHookStatePrefix = "token_transfer_hook:"
+	// tokenTransferHookRecordVersion 钩子记录格式版本（非零值，避免链上读取去掉尾部零字节）
+	tokenTransferHookRecordVersion byte = 1
+)
+
+// transferHookTypes 钩子类型与编码值的对应（下标即编码值，只能在末尾追加）
+var transferHookTypes = []string{
+	TRANSFER_HOOK_NONE,
+	TRANSFER_HOOK_PAUSE,
+	TRANSFER_HOOK_KYC,
+	TRANSFER_HOOK_SOULBOUND,
+}
+
+// TransferHook 代币的转账钩子登记
+type TransferHook struct {
+	// TokenID 代币ID
+	TokenID framework.TokenID
+	// Issuer 登记钩子的发行方（通常为发行合约地址），只有发行方可以修改
+	Issuer framework.Address
+	// HookType 钩子类型（TRANSFER_HOOK_*）
+	HookType string
+}
+
+// TransferHookRegistry 代币转账钩子注册表
+//
+// 记录存放在 store 中（状态ID token_transfer_hook:{tokenID}）。
+// KYC 钩子通过 SetKYCVerifier 设置的验证函数判断地址是否通过 KYC，未设置时一律拒绝。
+type TransferHookRegistry struct {
+	store       subaccount.Store
+	kycVerifier func(framework.Address) bool
+}
+
+// NewTransferHookRegistry 创建使用指定存储后端的转账钩子注册表
+func NewTransferHookRegistry(store subaccount.Store) *TransferHookRegistry {
+	return &TransferHookRegistry{store: store}
+}
+
+// SetKYCVerifier 设置 KYC 钩子使用的验证函数，传入 nil 清除
+func (r *TransferHookRegistry) SetKYCVerifier(verifier func(framework.Address) bool) {
+	r.kycVerifier = verifier
+}
+
+// SetTransferHook 登记或修改代币的转账钩子
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持钩子）
+//   - issuer: 发行方
+//   - hookType: 钩子类型（TRANSFER_HOOK_*）；TRANSFER_HOOK_NONE 解除限制
+//
+// **返回**：
+//   - error: 参数无效或钩子类型未知返回 ERROR_INVALID_PARAMS；代币已由其他发行方登记返回 ERROR_PERMISSION_DENIED
+func (r *TransferHookRegistry) SetTransferHook(tokenID framework.TokenID, issuer framework.Address, hookType string) error {
+	if framework.IsNativeToken(tokenID) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "tokenID cannot be empty")
+	}
+	if issuer == (framework.Address{}) {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "issuer cannot be zero")
+	}
+	if transferHookCode(hookType) < 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "unknown transfer hook type: "+hookType)
+	}
+
+	key := TokenTransferHookStateID(tokenID)
+	data, version, err := r.store.Load(key)
+	if err != nil {
+		return err
+	}
+	if existing, ok := decodeTransferHook(tokenID, data); ok && existing.Issuer != issuer {
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "token "+string(tokenID)+" transfer hook is registered to another issuer")
+	}
+	return r.store.Save(key, version+1, encodeTransferHook(TransferHook{TokenID: tokenID, Issuer: issuer, HookType: hookType}))
+}
+
+// Lookup 查询代币的钩子登记，未登记时返回 false
+func (r *TransferHookRegistry) Lookup(tokenID framework.TokenID) (TransferHook, bool, error) {
+	if framework.IsNativeToken(tokenID) {
+		return TransferHook{}, false, nil
+	}
+	data, _, err := r.store.Load(TokenTransferHookStateID(tokenID))
+	if err != nil {
+		return TransferHook{}, false, err
+	}
+	hook, ok := decodeTransferHook(tokenID, data)
+	return hook, ok, nil
+}
+
+// CheckTransfer 按代币登记的钩子检查一次转账是否允许
+//
+// **返回**：
+//   - error: 代币已暂停返回 ERROR_PAUSED；灵魂绑定代币，或 KYC 钩子下发送方/接收方未通过验证返回 ERROR_PERMISSION_DENIED；
+//     未登记钩子或钩子为 TRANSFER_HOOK_NONE 时返回 nil
+func (r *TransferHookRegistry) CheckTransfer(tokenID framework.TokenID, from, to framework.Address) error {
+	hook, ok, err := r.Lookup(tokenID)
+	if err != nil || !ok {
+		return err
+	}
+	switch hook.HookType {
+	case TRANSFER_HOOK_PAUSE:
+		return framework.NewContractError(framework.ERROR_PAUSED, "token "+string(tokenID)+" transfers are paused")
+	case TRANSFER_HOOK_SOULBOUND:
+		return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "token "+string(tokenID)+" is soulbound and cannot be transferred")
+	case TRANSFER_HOOK_KYC:
+		if r.kycVerifier == nil || !r.kycVerifier(from) {
+			return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "sender has not passed KYC")
+		}
+		if !r.kycVerifier(to) {
+			return framework.NewContractError(framework.ERROR_PERMISSION_DENIED, "recipient has not passed KYC")
+		}
+	}
+	return nil
+}
+
+// TokenTransferHookStateID 返回钩子记录的状态ID
+func TokenTransferHookStateID(tokenID framework.TokenID) string {
+	return tokenTransferHookStatePrefix + string(tokenID)
+}
+
+// transferHookCode 返回钩子类型的编码值，未知类型返回 -1
+func transferHookCode(hookType string) int {
+	for i, t := range transferHookTypes {
+		if t == hookType {
+			return i
+		}
+	}
+	return -1
+}
+
+// encodeTransferHook 编码钩子记录
+//
+// 编码格式：issuer(20) + hook_type(1) + version(1)
+func encodeTransferHook(h TransferHook) []byte {
+	data := make([]byte, 0, 22)
+	data = append(data, h.Issuer[:]...)
+	data = append(data, byte(transferHookCode(h.HookType)))
+	return append(data, tokenTransferHookRecordVersion)
+}
+
+// decodeTransferHook 解码钩子记录；记录不存在、格式无效或钩子类型未知时返回 false
+func decodeTransferHook(tokenID framework.TokenID, data []byte) (TransferHook, bool) {
+	if len(data) < 22 || data[21] != tokenTransferHookRecordVersion || int(data[20]) >= len(transferHookTypes) {
+		return TransferHook{}, false
+	}
+	h := TransferHook{TokenID: tokenID, HookType: transferHookTypes[data[20]]}
+	copy(h.Issuer[:], data[:20])
+	return h, true
+}
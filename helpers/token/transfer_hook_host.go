@@ -0,0 +1,75 @@
+//go:build tinygo || (js && wasm)
+
+package token
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// transferHookRegistry 基于链上状态的转账钩子注册表
+var transferHookRegistry = NewTransferHookRegistry(hostStateStore{})
+
+// SetTransferHook 设置代币的转账限制钩子
+//
+// 🎯 **用途**：发行方为代币开启转账限制，之后每次 Transfer 在执行前按钩子检查
+//
+// **参数**：
+//   - tokenID: 代币ID，不能为空（原生币不支持钩子）
+//   - hookType: 钩子类型——TRANSFER_HOOK_PAUSE 暂停所有转账（ERROR_PAUSED）；
+//     TRANSFER_HOOK_KYC 发送方与接收方都须通过 SetKYCVerifier 设置的验证函数；
+//     TRANSFER_HOOK_SOULBOUND 代币不可转让；TRANSFER_HOOK_NONE 解除限制
+//
+// **返回**：
+//   - error: 参数无效或钩子类型未知（ERROR_INVALID_PARAMS）、钩子已由其他合约登记（ERROR_PERMISSION_DENIED）
+//
+// **注意**：
+//   - 钩子按代币ID登记（token_transfer_hook:{tokenID}），登记方为当前合约地址，只有登记方可以修改
+//   - 只有 Transfer 检查钩子；Mint、Burn、Airdrop 等不受影响
+//   - 成功后发出 TransferHookSet 事件
+//
+// **示例**：
+//
+//	// 紧急暂停代币转账
+//	if err := token.SetTransferHook(tokenID, token.TRANSFER_HOOK_PAUSE); err != nil {
+//	    return framework.ERROR_INVALID_PARAMS
+//	}
+func SetTransferHook(tokenID framework.TokenID, hookType string) error {
+	issuer := framework.GetContractAddress()
+	if err := transferHookRegistry.SetTransferHook(tokenID, issuer, hookType); err != nil {
+		return err
+	}
+
+	event := framework.NewEvent("TransferHookSet")
+	event.AddStringField("token_id", string(tokenID))
+	event.AddAddressField("issuer", issuer)
+	event.AddStringField("hook_type", hookType)
+	framework.EmitEvent(event)
+
+	return nil
+}
+
+// GetTransferHook 查询代币的转账钩子类型，未登记时返回 TRANSFER_HOOK_NONE
+func GetTransferHook(tokenID framework.TokenID) string {
+	hook, ok, err := transferHookRegistry.Lookup(tokenID)
+	if err != nil || !ok {
+		return TRANSFER_HOOK_NONE
+	}
+	return hook.HookType
+}
+
+// SetKYCVerifier 设置 KYC 钩子使用的验证函数
+//
+// 🎯 **用途**：TRANSFER_HOOK_KYC 代币转账时用该函数判断发送方与接收方是否通过 KYC
+//
+// **注意**：
+//   - 验证函数只在本次调用内有效，不写入链上状态；应在调用 Transfer 之前（如合约包的 init 中）设置
+//   - 未设置验证函数时，KYC 钩子代币的转账一律返回 ERROR_PERMISSION_DENIED
+//
+// **示例**：
+//
+//	func init() {
+//	    token.SetKYCVerifier(rwa.IsKYCVerified)
+//	}
+func SetKYCVerifier(verifier func(framework.Address) bool) {
+	transferHookRegistry.SetKYCVerifier(verifier)
+}
@@ -0,0 +1,110 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+var (
+	hookSender    = framework.Address{0x61}
+	hookRecipient = framework.Address{0x62}
+	hookOutsider  = framework.Address{0x63}
+)
+
+const testHookToken framework.TokenID = "HOOK_TOKEN"
+
+// TestTransferHookRejections 测试每种钩子类型对转账的限制，以及切回 TRANSFER_HOOK_NONE 后恢复
+func TestTransferHookRejections(t *testing.T) {
+	registry := NewTransferHookRegistry(subaccount.NewMemoryStore())
+	registry.SetKYCVerifier(func(addr framework.Address) bool {
+		return addr == hookSender || addr == hookRecipient
+	})
+
+	if err := registry.CheckTransfer(testHookToken, hookSender, hookOutsider); err != nil {
+		t.Fatalf("CheckTransfer() without hook = %v, want nil", err)
+	}
+
+	tests := []struct {
+		hookType string
+		from, to framework.Address
+		wantErr  uint32
+	}{
+		{TRANSFER_HOOK_PAUSE, hookSender, hookRecipient, framework.ERROR_PAUSED},
+		{TRANSFER_HOOK_SOULBOUND, hookSender, hookRecipient, framework.ERROR_PERMISSION_DENIED},
+		{TRANSFER_HOOK_KYC, hookSender, hookRecipient, framework.SUCCESS},
+		{TRANSFER_HOOK_KYC, hookSender, hookOutsider, framework.ERROR_PERMISSION_DENIED},
+		{TRANSFER_HOOK_KYC, hookOutsider, hookRecipient, framework.ERROR_PERMISSION_DENIED},
+		{TRANSFER_HOOK_NONE, hookOutsider, hookRecipient, framework.SUCCESS},
+	}
+	for _, tt := range tests {
+		if err := registry.SetTransferHook(testHookToken, contractA, tt.hookType); err != nil {
+			t.Fatalf("SetTransferHook(%q) error = %v", tt.hookType, err)
+		}
+		if err := registry.CheckTransfer(testHookToken, tt.from, tt.to); errCode(err) != tt.wantErr {
+			t.Errorf("%s: CheckTransfer(%x → %x) = %v, want code %d", tt.hookType, tt.from[0], tt.to[0], err, tt.wantErr)
+		}
+	}
+
+	// 其他代币与原生币不受影响
+	for _, tokenID := range []framework.TokenID{"default", ""} {
+		if err := registry.CheckTransfer(tokenID, hookOutsider, hookRecipient); err != nil {
+			t.Errorf("CheckTransfer(%q) = %v, want nil", tokenID, err)
+		}
+	}
+}
+
+// TestTransferHookKYCWithoutVerifier 测试未设置验证函数时 KYC 钩子拒绝所有转账
+func TestTransferHookKYCWithoutVerifier(t *testing.T) {
+	registry := NewTransferHookRegistry(subaccount.NewMemoryStore())
+	if err := registry.SetTransferHook(testHookToken, contractA, TRANSFER_HOOK_KYC); err != nil {
+		t.Fatalf("SetTransferHook() error = %v", err)
+	}
+	if err := registry.CheckTransfer(testHookToken, hookSender, hookRecipient); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("CheckTransfer() without verifier = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+}
+
+// TestSetTransferHook 测试钩子登记校验与只有发行方可以修改
+func TestSetTransferHook(t *testing.T) {
+	chain := subaccount.NewMemoryStore()
+	registry := NewTransferHookRegistry(chain)
+
+	invalid := []struct {
+		name     string
+		tokenID  framework.TokenID
+		issuer   framework.Address
+		hookType string
+	}{
+		{"native token", "", contractA, TRANSFER_HOOK_PAUSE},
+		{"zero issuer", testHookToken, framework.Address{}, TRANSFER_HOOK_PAUSE},
+		{"unknown type", testHookToken, contractA, "frozen"},
+		{"empty type", testHookToken, contractA, ""},
+	}
+	for _, tt := range invalid {
+		if err := registry.SetTransferHook(tt.tokenID, tt.issuer, tt.hookType); errCode(err) != framework.ERROR_INVALID_PARAMS {
+			t.Errorf("%s: SetTransferHook() error = %v, want ERROR_INVALID_PARAMS", tt.name, err)
+		}
+	}
+
+	if err := registry.SetTransferHook(testHookToken, contractA, TRANSFER_HOOK_SOULBOUND); err != nil {
+		t.Fatalf("SetTransferHook() error = %v", err)
+	}
+	if err := registry.SetTransferHook(testHookToken, contractB, TRANSFER_HOOK_NONE); errCode(err) != framework.ERROR_PERMISSION_DENIED {
+		t.Errorf("other issuer SetTransferHook() error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if err := registry.SetTransferHook(testHookToken, contractA, TRANSFER_HOOK_PAUSE); err != nil {
+		t.Fatalf("issuer update SetTransferHook() error = %v", err)
+	}
+	hook, ok, err := registry.Lookup(testHookToken)
+	if err != nil || !ok || hook.Issuer != contractA || hook.HookType != TRANSFER_HOOK_PAUSE {
+		t.Errorf("Lookup() = %+v, %v, %v", hook, ok, err)
+	}
+	if _, version, _ := chain.Load(TokenTransferHookStateID(testHookToken)); version != 2 {
+		t.Errorf("hook record version = %d, want 2", version)
+	}
+	if _, ok, _ := registry.Lookup("other"); ok {
+		t.Error("Lookup() of unregistered token reported a hook")
+	}
+}
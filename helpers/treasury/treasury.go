@@ -0,0 +1,177 @@
+// Package treasury 提供协议金库的业务语义API
+//
+// DAO 资金、AMM 协议手续费、借贷储备金、互助险服务费等都需要一个"只进不随意出"的金库：
+// 任何人可以存入，只有金库所有者（TREASURY_OWNER_ROLE 角色的持有者）可以支出。
+//
+// 金库资金托管在合约地址下，按代币单独记账（状态 treasury_balance:{tokenID}）。
+// 合约地址上的其他资金（托管、流动性池等）不计入金库余额，也不能通过 Withdraw 支出。
+//
+// 所有者是框架登记的可轮换角色（见 framework.RegisterRole），合约在 Initialize 中设置初始持有者；
+// 由治理决定支出时，将角色授予执行提案的地址（如 DAO 的执行合约或多签）：
+//
+//	framework.InitRole(treasury.TREASURY_OWNER_ROLE, governanceExecutor)
+package treasury
+
+import (
+	"encoding/binary"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// TREASURY_OWNER_ROLE 可支出金库资金的角色
+const TREASURY_OWNER_ROLE = "treasury_owner"
+
+// treasuryBalanceStatePrefix 金库余额状态ID前缀，完整格式：treasury_balance:{tokenID}，值为 8 字节大端整数
+const treasuryBalanceStatePrefix = "treasury_balance:"
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: TREASURY_OWNER_ROLE})
+}
+
+// Deposit 调用者向金库存入代币
+//
+// 🎯 **用途**：协议收入（手续费、服务费）或捐赠计入金库
+//
+// **参数**：
+//   - tokenID: 代币ID（空字符串表示原生币）
+//   - amount: 存入金额
+//
+// **返回**：
+//   - error: 金额为 0（ERROR_INVALID_PARAMS）、调用者余额不足（ERROR_INSUFFICIENT_BALANCE）、
+//     金库余额溢出（ERROR_INVALID_PARAMS）
+//
+// **注意**：
+//   - 资金从调用者（framework.GetCaller()）转入合约地址，与金库余额记录在同一笔交易提交
+//   - 发出 TreasuryDeposit 事件（from / token_id / amount / balance）
+//
+// **示例**：
+//
+//	//export Donate
+//	func Donate() uint32 {
+//	    params := framework.GetContractParams()
+//	    amount := framework.Amount(params.ParseJSONInt("amount"))
+//	    if err := treasury.Deposit("USDT", amount); err != nil {
+//	        return err.(*framework.ContractError).Code
+//	    }
+//	    return framework.SUCCESS
+//	}
+func Deposit(tokenID framework.TokenID, amount framework.Amount) error {
+	// 1. 校验金额与余额
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+	caller := framework.GetCaller()
+	if framework.QueryUTXOBalance(caller, tokenID) < amount {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient balance to deposit")
+	}
+	balance, version := loadBalance(tokenID)
+	newBalance := balance + uint64(amount)
+	if newBalance < balance {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "treasury balance overflow")
+	}
+
+	// 2. 转入与记账在同一笔交易提交
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(caller, framework.GetContractAddress(), tokenID, amount).
+		AddStateOutput(buildBalanceStateID(tokenID), version+1, binary.BigEndian.AppendUint64(nil, newBalance)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "treasury deposit failed")
+	}
+
+	// 3. 发出存入事件
+	event := framework.NewEvent("TreasuryDeposit")
+	event.AddAddressField("from", caller)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("amount", uint64(amount))
+	event.AddUint64Field("balance", newBalance)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// Withdraw 金库所有者从金库支出代币
+//
+// 🎯 **用途**：按治理决议拨款、回购、补充储备金等
+//
+// **参数**：
+//   - tokenID: 代币ID（空字符串表示原生币）
+//   - to: 收款地址
+//   - amount: 支出金额
+//
+// **返回**：
+//   - error: 调用者不是金库所有者（ERROR_UNAUTHORIZED）、收款地址为空或金额为 0（ERROR_INVALID_PARAMS）、
+//     超过金库余额（ERROR_INSUFFICIENT_BALANCE）
+//
+// **注意**：
+//   - 调用者取自 framework.GetCaller()，须持有 TREASURY_OWNER_ROLE
+//   - 只能支出金库余额（Balance），合约地址上的其他资金不受影响
+//   - 发出 TreasuryWithdrawal 事件（operator / to / token_id / amount / balance）
+//
+// **示例**：
+//
+//	// 执行已通过的拨款提案
+//	if err := treasury.Withdraw("USDT", grantee, framework.Amount(50000)); err != nil {
+//	    return err.(*framework.ContractError).Code
+//	}
+func Withdraw(tokenID framework.TokenID, to framework.Address, amount framework.Amount) error {
+	// 1. 校验所有者与参数
+	caller := framework.GetCaller()
+	if !framework.HasRole(TREASURY_OWNER_ROLE, caller) {
+		return framework.NewContractError(framework.ERROR_UNAUTHORIZED, "only the treasury owner can withdraw")
+	}
+	if to.IsZero() {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "recipient cannot be empty")
+	}
+	if amount == 0 {
+		return framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount must be greater than 0")
+	}
+
+	// 2. 检查金库余额
+	balance, version := loadBalance(tokenID)
+	if balance < uint64(amount) {
+		return framework.NewContractError(framework.ERROR_INSUFFICIENT_BALANCE, "insufficient treasury balance")
+	}
+	newBalance := balance - uint64(amount)
+
+	// 3. 转出与记账在同一笔交易提交
+	success, _, errCode := framework.BeginTransaction().
+		Transfer(framework.GetContractAddress(), to, tokenID, amount).
+		AddStateOutput(buildBalanceStateID(tokenID), version+1, binary.BigEndian.AppendUint64(nil, newBalance)).
+		Finalize()
+	if !success {
+		return framework.NewContractError(errCode, "treasury withdrawal failed")
+	}
+
+	// 4. 发出支出事件
+	event := framework.NewEvent("TreasuryWithdrawal")
+	event.AddAddressField("operator", caller)
+	event.AddAddressField("to", to)
+	event.AddStringField("token_id", string(tokenID))
+	event.AddUint64Field("amount", uint64(amount))
+	event.AddUint64Field("balance", newBalance)
+	framework.EmitEvent(event)
+	return nil
+}
+
+// Balance 查询金库某代币的余额（累计存入减累计支出）
+func Balance(tokenID framework.TokenID) framework.Amount {
+	balance, _ := loadBalance(tokenID)
+	return framework.Amount(balance)
+}
+
+// loadBalance 读取金库余额与状态版本，未记账时返回 0, 0
+func loadBalance(tokenID framework.TokenID) (balance, version uint64) {
+	data, version, err := framework.GetStateFromChain(buildBalanceStateID(tokenID))
+	if err != nil || version == 0 {
+		return 0, 0
+	}
+	// 链上读取可能去掉尾部零字节，按 8 字节补齐
+	buf := make([]byte, 8)
+	copy(buf, data)
+	return binary.BigEndian.Uint64(buf), version
+}
+
+// buildBalanceStateID 构建金库余额状态ID：treasury_balance:{tokenID}
+func buildBalanceStateID(tokenID framework.TokenID) []byte {
+	return []byte(treasuryBalanceStatePrefix + string(tokenID))
+}
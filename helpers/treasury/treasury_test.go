@@ -0,0 +1,121 @@
+//go:build !tinygo && !(js && wasm)
+
+package treasury
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+const testToken framework.TokenID = "USDT"
+
+// newTreasuryHost 创建以 Operator 为金库所有者、Alice 与 Bob 各持有 1000 代币的宿主
+func newTreasuryHost(t *testing.T) *fwtesting.Host {
+	t.Helper()
+	host := fwtesting.NewHost(t).SetCaller(fixtures.Operator())
+	if res := host.Run(func() error { return framework.InitRole(TREASURY_OWNER_ROLE, fixtures.Operator()) }); res.Code != framework.SUCCESS {
+		t.Fatalf("InitRole() code = %d", res.Code)
+	}
+	host.SetBalance(fixtures.Alice(), testToken, 1000)
+	host.SetBalance(fixtures.Bob(), testToken, 1000)
+	return host
+}
+
+// TestTreasuryDepositWithdrawAccounting 测试存入与支出后金库余额、合约余额与收款方余额一致，
+// 合约地址上的非金库资金不计入金库余额
+func TestTreasuryDepositWithdrawAccounting(t *testing.T) {
+	host := newTreasuryHost(t)
+	alice, bob, carol := fixtures.Alice(), fixtures.Bob(), fixtures.Carol()
+	contract := host.ContractAddress
+	host.SetBalance(contract, testToken, 5000) // 托管等其他资金
+
+	for _, d := range []struct {
+		from   framework.Address
+		amount framework.Amount
+	}{{alice, 300}, {bob, 200}, {alice, 100}} {
+		host.SetCaller(d.from)
+		res := host.Run(func() error { return Deposit(testToken, d.amount) })
+		if res.Code != framework.SUCCESS {
+			t.Fatalf("Deposit(%d) code = %d", d.amount, res.Code)
+		}
+		if len(res.Events) != 1 || res.Events[0].Name != "TreasuryDeposit" || res.Events[0].Data["amount"] != uint64(d.amount) {
+			t.Errorf("Deposit(%d) events = %+v", d.amount, res.Events)
+		}
+	}
+	if got := Balance(testToken); got != 600 {
+		t.Fatalf("Balance() after deposits = %d, want 600", got)
+	}
+	if host.Balance(alice, testToken) != 600 || host.Balance(bob, testToken) != 800 || host.Balance(contract, testToken) != 5600 {
+		t.Errorf("holder balances = %d / %d / %d", host.Balance(alice, testToken), host.Balance(bob, testToken), host.Balance(contract, testToken))
+	}
+
+	host.SetCaller(fixtures.Operator())
+	res := host.Run(func() error { return Withdraw(testToken, carol, 250) })
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("Withdraw() code = %d", res.Code)
+	}
+	if len(res.Events) != 1 || res.Events[0].Name != "TreasuryWithdrawal" || res.Events[0].Data["balance"] != uint64(350) {
+		t.Errorf("Withdraw() events = %+v, want one TreasuryWithdrawal with balance 350", res.Events)
+	}
+	if Balance(testToken) != 350 || host.Balance(carol, testToken) != 250 || host.Balance(contract, testToken) != 5350 {
+		t.Errorf("after withdrawal: treasury %d, carol %d, contract %d", Balance(testToken), host.Balance(carol, testToken), host.Balance(contract, testToken))
+	}
+
+	// 只能支出金库余额，合约上的其他资金不可动用
+	if res := host.Run(func() error { return Withdraw(testToken, carol, 351) }); res.Code != framework.ERROR_INSUFFICIENT_BALANCE || len(res.Transfers) != 0 {
+		t.Errorf("Withdraw() beyond treasury balance code = %d, %d transfers", res.Code, len(res.Transfers))
+	}
+	if res := host.Run(func() error { return Withdraw(testToken, carol, 350) }); res.Code != framework.SUCCESS || Balance(testToken) != 0 {
+		t.Errorf("Withdraw() of full balance code = %d, balance %d", res.Code, Balance(testToken))
+	}
+
+	// 各代币单独记账
+	if Balance("OTHER") != 0 {
+		t.Errorf("Balance(OTHER) = %d, want 0", Balance("OTHER"))
+	}
+}
+
+// TestTreasuryWithdrawAuthorization 测试只有金库所有者可以支出，参数无效时不转移资金
+func TestTreasuryWithdrawAuthorization(t *testing.T) {
+	host := newTreasuryHost(t)
+	alice, carol := fixtures.Alice(), fixtures.Carol()
+	host.SetCaller(alice)
+	if res := host.Run(func() error { return Deposit(testToken, 500) }); res.Code != framework.SUCCESS {
+		t.Fatalf("Deposit() code = %d", res.Code)
+	}
+
+	for _, caller := range []framework.Address{alice, carol} {
+		host.SetCaller(caller)
+		if res := host.Run(func() error { return Withdraw(testToken, caller, 100) }); res.Code != framework.ERROR_UNAUTHORIZED || len(res.Transfers) != 0 || len(res.Writes) != 0 {
+			t.Errorf("Withdraw() by %s: code %d, %d transfers, %d writes", fixtures.Name(caller), res.Code, len(res.Transfers), len(res.Writes))
+		}
+	}
+	if Balance(testToken) != 500 {
+		t.Fatalf("unauthorized withdrawals changed balance to %d", Balance(testToken))
+	}
+
+	host.SetCaller(fixtures.Operator())
+	rejects := []struct {
+		name     string
+		op       func() error
+		wantCode uint32
+	}{
+		{"zero recipient", func() error { return Withdraw(testToken, framework.Address{}, 1) }, framework.ERROR_INVALID_PARAMS},
+		{"zero amount", func() error { return Withdraw(testToken, carol, 0) }, framework.ERROR_INVALID_PARAMS},
+		{"zero deposit", func() error { return Deposit(testToken, 0) }, framework.ERROR_INVALID_PARAMS},
+		{"deposit over balance", func() error { return Deposit(testToken, 1) }, framework.ERROR_INSUFFICIENT_BALANCE},
+	}
+	for _, tt := range rejects {
+		if res := host.Run(tt.op); res.Code != tt.wantCode || len(res.Transfers) != 0 {
+			t.Errorf("%s: code %d, %d transfers, want code %d", tt.name, res.Code, len(res.Transfers), tt.wantCode)
+		}
+	}
+
+	// 所有者支出成功
+	if res := host.Run(func() error { return Withdraw(testToken, carol, 100) }); res.Code != framework.SUCCESS || host.Balance(carol, testToken) != 100 {
+		t.Errorf("Withdraw() by owner code = %d, carol balance %d", res.Code, host.Balance(carol, testToken))
+	}
+}
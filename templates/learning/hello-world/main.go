@@ -110,6 +110,7 @@ type HelloContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	contract := &HelloContract{}
 
 	// 步骤1：获取部署者地址
@@ -179,6 +180,7 @@ func Initialize() uint32 {
 //
 //export SayHello
 func SayHello() uint32 {
+	framework.BeginInvocation()
 	contract := &HelloContract{}
 
 	// 步骤1：获取调用者信息
@@ -247,6 +249,7 @@ func SayHello() uint32 {
 //
 //export GetGreetingCount
 func GetGreetingCount() uint32 {
+	framework.BeginInvocation()
 	contract := &HelloContract{}
 
 	// 步骤1：读取问候计数
@@ -294,6 +297,7 @@ func GetGreetingCount() uint32 {
 //
 //export GetDeployerInfo
 func GetDeployerInfo() uint32 {
+	framework.BeginInvocation()
 	contract := &HelloContract{}
 
 	// 步骤1：读取部署信息
@@ -150,6 +150,7 @@ type BalanceQuery struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	contract := &SimpleToken{}
 
 	// 步骤1：检查 ABI 版本兼容性
@@ -238,6 +239,7 @@ func Initialize() uint32 {
 //
 //export Mint
 func Mint() uint32 {
+	framework.BeginInvocation()
 	contract := &SimpleToken{}
 	// 注意：framework.GetCaller() 返回 Address 类型，contract.GetCaller() 返回 string 类型
 	caller := framework.GetCaller() // 获取调用者地址（Address 类型）
@@ -347,6 +349,7 @@ func Mint() uint32 {
 //
 //export Transfer
 func Transfer() uint32 {
+	framework.BeginInvocation()
 	contract := &SimpleToken{}
 	// 注意：framework.GetCaller() 返回 Address 类型，contract.GetCaller() 返回 string 类型
 	caller := framework.GetCaller() // 获取调用者地址（Address 类型）
@@ -443,6 +446,7 @@ func Transfer() uint32 {
 //
 //export BalanceOf
 func BalanceOf() uint32 {
+	framework.BeginInvocation()
 	contract := &SimpleToken{}
 
 	// 步骤1：解析参数（可选）
@@ -532,6 +536,7 @@ func BalanceOf() uint32 {
 //
 //export TotalSupply
 func TotalSupply() uint32 {
+	framework.BeginInvocation()
 	contract := &SimpleToken{}
 
 	// 注意：在UTXO模型中，总供应量应该通过查询所有UTXO的总和来计算
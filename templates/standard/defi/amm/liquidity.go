@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ==================== 添加流动性报价与滑点保护 ====================
+//
+// 本文件不带 build tag，报价与滑点检查可在非WASM环境中直接测试；
+// 导出函数见 main.go。
+
+// MINIMUM_LIQUIDITY 首次添加流动性时永久锁定的 LP 数量（记在合约地址名下）
+//
+// 防止首个流动性提供者以极小的初始份额操纵每份 LP 的价值
+const MINIMUM_LIQUIDITY = 1000
+
+// LEDGER_LP LP 份额子账户台账命名空间（代币ID为 token.DeriveTokenID 派生的 LP 代币ID）
+const LEDGER_LP = "amm_lp"
+
+// liquidityQuote 添加流动性报价
+type liquidityQuote struct {
+	// AmountA 转入的代币A数量
+	AmountA uint64
+	// AmountB 转入的代币B数量：首次添加为提供者给定的数量，之后按池当前比例计算
+	AmountB uint64
+	// LPOut 提供者获得的 LP 数量
+	LPOut uint64
+	// Locked 首次添加时永久锁定的 LP 数量
+	Locked uint64
+}
+
+// quoteAddLiquidity 按池当前储备计算添加流动性需要的代币B数量与可获得的 LP 数量
+//
+// 参数：
+//   - amountA: 转入的代币A数量
+//   - amountB: 首次添加时转入的代币B数量（决定初始价格）；池已有流动性时忽略
+//   - reserveA / reserveB: 池当前储备
+//   - lpSupply: LP 总份额
+//
+// 计算：
+//   - 首次添加：LP = sqrt(amountA × amountB) - MINIMUM_LIQUIDITY
+//   - 之后添加：amountB = ceil(amountA × reserveB / reserveA)，LP = floor(amountA × lpSupply / reserveA)
+//
+// 返回：
+//   - error: 数量过小（得不到 LP）或溢出时返回 ERROR_INVALID_PARAMS
+func quoteAddLiquidity(amountA, amountB, reserveA, reserveB, lpSupply uint64) (liquidityQuote, error) {
+	if amountA == 0 {
+		return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount_a must be positive")
+	}
+
+	// 首次添加：按提供者给定的比例定价
+	if reserveA == 0 || reserveB == 0 || lpSupply == 0 {
+		if amountB == 0 {
+			return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount_b must be positive")
+		}
+		hi, lo := bits.Mul64(amountA, amountB)
+		liquidity := sqrt128(hi, lo)
+		if liquidity <= MINIMUM_LIQUIDITY {
+			return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "initial liquidity too small")
+		}
+		return liquidityQuote{AmountA: amountA, AmountB: amountB, LPOut: liquidity - MINIMUM_LIQUIDITY, Locked: MINIMUM_LIQUIDITY}, nil
+	}
+
+	// 之后添加：代币B按池当前比例计算（向上取整，舍入有利于池内已有的提供者）
+	requiredB, ok := mulDiv(amountA, reserveB, reserveA, true)
+	if !ok {
+		return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "amount_b overflow")
+	}
+	lpOut, ok := mulDiv(amountA, lpSupply, reserveA, false)
+	if !ok {
+		return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "lp amount overflow")
+	}
+	if lpOut == 0 {
+		return liquidityQuote{}, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "liquidity too small")
+	}
+	return liquidityQuote{AmountA: amountA, AmountB: requiredB, LPOut: lpOut}, nil
+}
+
+// checkLiquiditySlippage 检查报价是否在提供者给定的容忍范围内
+//
+// 参数：
+//   - minLPOut: 至少获得的 LP 数量（0 表示不限制）
+//   - maxAmountB: 至多转入的代币B数量
+//
+// 返回：
+//   - error: 池比例在提交后变动导致超出范围时返回 ERROR_SLIPPAGE_EXCEEDED
+func checkLiquiditySlippage(q liquidityQuote, minLPOut, maxAmountB uint64) error {
+	if q.AmountB > maxAmountB {
+		return framework.NewContractError(framework.ERROR_SLIPPAGE_EXCEEDED, "required amount_b exceeds max_amount_b")
+	}
+	if q.LPOut < minLPOut {
+		return framework.NewContractError(framework.ERROR_SLIPPAGE_EXCEEDED, "lp output below min_lp_out")
+	}
+	return nil
+}
+
+// mulDiv 计算 a × b / c（128位中间结果，roundUp 为 true 时向上取整）；结果超出 uint64 时返回 false
+func mulDiv(a, b, c uint64, roundUp bool) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, c)
+	if roundUp && r > 0 {
+		if q == ^uint64(0) {
+			return 0, false
+		}
+		q++
+	}
+	return q, true
+}
+
+// sqrt128 计算 128 位整数 hi:lo 的整数平方根（向下取整）
+func sqrt128(hi, lo uint64) uint64 {
+	var root uint64
+	for bit := uint64(1) << 63; bit > 0; bit >>= 1 {
+		candidate := root | bit
+		sqHi, sqLo := bits.Mul64(candidate, candidate)
+		if sqHi < hi || (sqHi == hi && sqLo <= lo) {
+			root = candidate
+		}
+	}
+	return root
+}
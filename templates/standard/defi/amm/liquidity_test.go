@@ -0,0 +1,96 @@
+//go:build !tinygo && !(js && wasm)
+
+package main
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// 池储备 10000 A / 20000 B，LP 总份额 14142（首次添加 sqrt(10000×20000)）
+const (
+	testReserveA = 10000
+	testReserveB = 20000
+	testLPSupply = 14142
+)
+
+func errCode(err error) uint32 {
+	if ce, ok := err.(*framework.ContractError); ok {
+		return ce.Code
+	}
+	return framework.SUCCESS
+}
+
+// TestAddLiquidityFirstDeposit 测试首次添加按给定比例定价并锁定最小流动性
+func TestAddLiquidityFirstDeposit(t *testing.T) {
+	q, err := quoteAddLiquidity(10000, 20000, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("quoteAddLiquidity() error = %v", err)
+	}
+	want := liquidityQuote{AmountA: 10000, AmountB: 20000, LPOut: testLPSupply - MINIMUM_LIQUIDITY, Locked: MINIMUM_LIQUIDITY}
+	if q != want {
+		t.Errorf("quoteAddLiquidity() = %+v, want %+v", q, want)
+	}
+	if _, err := quoteAddLiquidity(10, 10, 0, 0, 0); errCode(err) != framework.ERROR_INVALID_PARAMS {
+		t.Errorf("tiny first deposit error = %v, want ERROR_INVALID_PARAMS", err)
+	}
+}
+
+// TestAddLiquidityWithinTolerance 测试池比例在容忍范围内时按池比例成交
+func TestAddLiquidityWithinTolerance(t *testing.T) {
+	// 提供者按 1:2 报价 1000 A / 2000 B，容忍 1%
+	minLPOut, maxAmountB := uint64(1400), uint64(2020)
+
+	// 提交后池比例小幅变动：10000 A / 20100 B
+	q, err := quoteAddLiquidity(1000, 2000, testReserveA, 20100, testLPSupply)
+	if err != nil {
+		t.Fatalf("quoteAddLiquidity() error = %v", err)
+	}
+	if q.AmountB != 2010 || q.LPOut != 1414 {
+		t.Errorf("quote = %+v, want amount_b 2010, lp 1414", q)
+	}
+	if err := checkLiquiditySlippage(q, minLPOut, maxAmountB); err != nil {
+		t.Errorf("checkLiquiditySlippage() error = %v, want nil", err)
+	}
+}
+
+// TestAddLiquidityMovedRatioRejected 测试池比例被抢先交易推动后超出容忍范围被拒绝
+func TestAddLiquidityMovedRatioRejected(t *testing.T) {
+	minLPOut, maxAmountB := uint64(1400), uint64(2020)
+
+	tests := []struct {
+		name               string
+		reserveA, reserveB uint64
+	}{
+		// 抢先买入 A：池变为 8000 A / 25000 B，1000 A 需要 3125 B
+		{"amount_b above max", 8000, 25000},
+		// 抢先卖出 A：池变为 12500 A / 16000 B，1000 A 只能获得 1131 LP
+		{"lp below min", 12500, 16000},
+	}
+	for _, tt := range tests {
+		q, err := quoteAddLiquidity(1000, 2000, tt.reserveA, tt.reserveB, testLPSupply)
+		if err != nil {
+			t.Fatalf("%s: quoteAddLiquidity() error = %v", tt.name, err)
+		}
+		if err := checkLiquiditySlippage(q, minLPOut, maxAmountB); errCode(err) != framework.ERROR_SLIPPAGE_EXCEEDED {
+			t.Errorf("%s: quote %+v error = %v, want ERROR_SLIPPAGE_EXCEEDED", tt.name, q, err)
+		}
+	}
+}
+
+// TestSqrt128 测试 128 位整数平方根
+func TestSqrt128(t *testing.T) {
+	for _, tt := range []struct{ hi, lo, want uint64 }{
+		{0, 0, 0},
+		{0, 15, 3},
+		{0, 16, 4},
+		{0, 200000000, 14142},
+		{1, 0, 1 << 32},
+		{^uint64(0) - 1, 1, ^uint64(0)},
+	} {
+		if got := sqrt128(tt.hi, tt.lo); got != tt.want {
+			t.Errorf("sqrt128(%d, %d) = %d, want %d", tt.hi, tt.lo, got, tt.want)
+		}
+	}
+}
@@ -27,7 +27,7 @@
 // ⚠️ 注意：本示例是简化实现
 //   实际应用中需要实现：
 //   - 恒定乘积公式（x*y=k）价格计算
-//   - 滑点保护机制
+//   - 移除流动性的滑点保护
 //   - 手续费分成（给流动性提供者）
 //   - 流动性凭证代币管理
 //
@@ -42,6 +42,7 @@ package main
 import (
 	"github.com/weisyn/contract-sdk-go/helpers/token"
 	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
 )
 
 // AMMContract AMM（自动化做市商）合约
@@ -77,6 +78,7 @@ type AMMContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "AMM")
@@ -100,26 +102,29 @@ func Initialize() uint32 {
 //	  "token_a_id": "TOKEN_A",  // 代币A ID（必填）
 //	  "token_b_id": "TOKEN_B",  // 代币B ID（必填）
 //	  "amount_a": 1000,         // 代币A数量（必填）
-//	  "amount_b": 2000          // 代币B数量（必填）
+//	  "amount_b": 2000,         // 代币B数量（必填，池已有流动性时为报价时的期望数量）
+//	  "max_amount_b": 2020,     // 至多转入的代币B数量（可选，默认等于 amount_b，滑点保护）
+//	  "min_lp_out": 1400        // 至少获得的 LP 数量（可选，默认不限制，滑点保护）
 //	}
 //
 // 工作流程：
 //  1. 解析参数并验证
-//  2. 检查用户余额
-//  3. 计算流动性凭证代币数量（根据恒定乘积公式）
-//  4. 转移代币到合约
-//  5. 铸造流动性凭证代币
-//  6. 发出添加流动性事件
-//
-// ⚠️ 注意：这是一个简化实现
-//   实际应用中需要实现：
-//   - 恒定乘积公式计算（x*y=k）
-//   - 流动性凭证代币数量计算
-//   - 首次添加流动性的特殊处理
+//  2. 读取池储备与 LP 总份额
+//  3. 计算流动性凭证代币数量（首次添加为 sqrt(amountA × amountB)，之后按池比例）
+//  4. 检查滑点（实际需要的代币B <= max_amount_b，获得的 LP >= min_lp_out）
+//  5. 检查用户余额
+//  6. 转移代币到合约
+//  7. 记入流动性凭证代币
+//  8. 发出添加流动性事件
+//
+// ⚠️ 注意：池已有流动性时，代币B数量按池当前比例计算，而不是直接使用 amount_b。
+// 提交交易到执行之间池比例可能被抢先交易推动（三明治攻击），
+// 通过 max_amount_b / min_lp_out 限定可接受的范围，超出时拒绝执行。
 //
 // 返回：
 //   - framework.SUCCESS - 添加成功
 //   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_SLIPPAGE_EXCEEDED - 池比例变动超出容忍范围
 //   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
 //   - framework.ERROR_EXECUTION_FAILED - 执行失败
 //
@@ -130,82 +135,86 @@ func Initialize() uint32 {
 //       "token_a_id": "TOKEN_A",
 //       "token_b_id": "TOKEN_B",
 //       "amount_a": 1000,
-//       "amount_b": 2000
+//       "amount_b": 2000,
+//       "lp_out": 1414
 //     }
 //
 //export AddLiquidity
 func AddLiquidity() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenAIDStr := params.ParseJSON("token_a_id")
 	tokenBIDStr := params.ParseJSON("token_b_id")
 	amountA := params.ParseJSONInt("amount_a")
 	amountB := params.ParseJSONInt("amount_b")
+	maxAmountB := params.ParseJSONInt("max_amount_b")
+	minLPOut := params.ParseJSONInt("min_lp_out")
 
-	if tokenAIDStr == "" || tokenBIDStr == "" || amountA == 0 || amountB == 0 {
+	if tokenAIDStr == "" || tokenBIDStr == "" || tokenAIDStr == tokenBIDStr || amountA == 0 || amountB == 0 {
 		return framework.ERROR_INVALID_PARAMS
 	}
+	if maxAmountB == 0 {
+		maxAmountB = amountB
+	}
 
-	// 步骤2：解析代币ID
 	tokenAID := framework.TokenID(tokenAIDStr)
 	tokenBID := framework.TokenID(tokenBIDStr)
-
-	// 步骤3：获取调用者
+	lpTokenID := token.DeriveTokenID(LEDGER_LP, tokenAIDStr, tokenBIDStr)
 	caller := framework.GetCaller()
+	contractAddr := framework.GetContractAddress()
 
-	// 步骤4：检查余额
-	balanceA := framework.QueryUTXOBalance(caller, tokenAID)
-	balanceB := framework.QueryUTXOBalance(caller, tokenBID)
-	if balanceA < framework.Amount(amountA) || balanceB < framework.Amount(amountB) {
-		return framework.ERROR_INSUFFICIENT_BALANCE
+	// 步骤2：读取池储备与 LP 总份额
+	reserveA := framework.QueryUTXOBalance(contractAddr, tokenAID)
+	reserveB := framework.QueryUTXOBalance(contractAddr, tokenBID)
+	lpSupply, err := subaccount.TotalLiabilities(LEDGER_LP, lpTokenID)
+	if err != nil {
+		return contractErrorCode(err)
 	}
 
-	// 步骤5：计算流动性凭证代币数量
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该使用恒定乘积公式计算：
-	//   LP Token数量 = sqrt(amountA * amountB) - 最小流动性
-	//   首次添加流动性时，LP Token数量 = sqrt(amountA * amountB)
+	// 步骤3：计算流动性凭证代币数量
+	quote, err := quoteAddLiquidity(amountA, amountB, uint64(reserveA), uint64(reserveB), uint64(lpSupply))
+	if err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤4：检查滑点
+	if err := checkLiquiditySlippage(quote, minLPOut, maxAmountB); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤5：检查余额
+	if framework.QueryUTXOBalance(caller, tokenAID) < framework.Amount(quote.AmountA) ||
+		framework.QueryUTXOBalance(caller, tokenBID) < framework.Amount(quote.AmountB) {
+		return framework.ERROR_INSUFFICIENT_BALANCE
+	}
 
 	// 步骤6：转移代币到合约
-	contractAddr := framework.GetContractAddress()
-	err := token.Transfer(
-		caller,
-		contractAddr,
-		tokenAID,
-		framework.Amount(amountA),
-	)
-	if err != nil {
-		if contractErr, ok := err.(*framework.ContractError); ok {
-			return contractErr.Code
-		}
-		return framework.ERROR_EXECUTION_FAILED
+	if err := token.Transfer(caller, contractAddr, tokenAID, framework.Amount(quote.AmountA)); err != nil {
+		return contractErrorCode(err)
+	}
+	if err := token.Transfer(caller, contractAddr, tokenBID, framework.Amount(quote.AmountB)); err != nil {
+		return contractErrorCode(err)
 	}
 
-	err = token.Transfer(
-		caller,
-		contractAddr,
-		tokenBID,
-		framework.Amount(amountB),
-	)
-	if err != nil {
-		if contractErr, ok := err.(*framework.ContractError); ok {
-			return contractErr.Code
+	// 步骤7：记入流动性凭证代币（首次添加时最小流动性记在合约地址名下，永久锁定）
+	if quote.Locked > 0 {
+		if err := subaccount.CreditSubAccount(LEDGER_LP, contractAddr, lpTokenID, framework.Amount(quote.Locked)); err != nil {
+			return contractErrorCode(err)
 		}
-		return framework.ERROR_EXECUTION_FAILED
 	}
-
-	// 步骤7：铸造流动性凭证代币
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该铸造流动性凭证代币（LP Token）给用户
-	//   LP Token数量 = sqrt(amountA * amountB) - 最小流动性
+	if err := subaccount.CreditSubAccount(LEDGER_LP, caller, lpTokenID, framework.Amount(quote.LPOut)); err != nil {
+		return contractErrorCode(err)
+	}
 
 	// 步骤8：发出添加流动性事件
 	event := framework.NewEvent("AddLiquidity")
 	event.AddAddressField("provider", caller)
 	event.AddStringField("token_a_id", tokenAIDStr)
 	event.AddStringField("token_b_id", tokenBIDStr)
-	event.AddUint64Field("amount_a", uint64(amountA))
-	event.AddUint64Field("amount_b", uint64(amountB))
+	event.AddUint64Field("amount_a", quote.AmountA)
+	event.AddUint64Field("amount_b", quote.AmountB)
+	event.AddUint64Field("lp_out", quote.LPOut)
 	framework.EmitEvent(event)
 
 	return framework.SUCCESS
@@ -259,6 +268,7 @@ func AddLiquidity() uint32 {
 //
 //export RemoveLiquidity
 func RemoveLiquidity() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenAIDStr := params.ParseJSON("token_a_id")
@@ -388,6 +398,7 @@ func RemoveLiquidity() uint32 {
 //
 //export SwapTokens
 func SwapTokens() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenInIDStr := params.ParseJSON("token_in_id")
@@ -421,7 +432,7 @@ func SwapTokens() uint32 {
 
 	// 步骤6：检查滑点
 	if amountOut < minAmountOut {
-		return framework.ERROR_EXECUTION_FAILED // 滑点过大
+		return framework.ERROR_SLIPPAGE_EXCEEDED
 	}
 
 	// 步骤7：转移输入代币到合约
@@ -472,5 +483,13 @@ func SwapTokens() uint32 {
 	return framework.SUCCESS
 }
 
+// contractErrorCode 提取合约错误码，其他错误返回 ERROR_EXECUTION_FAILED
+func contractErrorCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	return framework.ERROR_EXECUTION_FAILED
+}
+
 func main() {}
 
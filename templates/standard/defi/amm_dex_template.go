@@ -5,6 +5,8 @@ package main
 
 import (
 	"unsafe"
+
+	"github.com/weisyn/contract-sdk-go/helpers/token"
 )
 
 // ==================== WES AMM DEX 合约模板 ====================
@@ -163,8 +165,12 @@ func generatePoolID(tokenA, tokenB string) string {
 }
 
 // generateLPTokenID 生成LP代币ID
-func generateLPTokenID(poolID string) string {
-	return "LP_" + poolID
+//
+// 按合约地址与池ID派生（token.DeriveTokenID），不同AMM合约的同名池不会铸造到同一代币类别
+func generateLPTokenID(contractAddr uint32, poolID string) string {
+	//nolint:gosec,govet // G103: WASM 环境中需要使用 unsafe.Pointer 与宿主环境交互; unsafeptr: WASM 内存访问需要 uintptr 转换
+	addr := (*[20]byte)(unsafe.Pointer(uintptr(contractAddr)))
+	return string(token.DeriveTokenID("LP", string(addr[:]), poolID))
 }
 
 // uint64ToString 将uint64转换为字符串
@@ -394,7 +400,7 @@ func AddLiquidity() uint32 {
 
 	// 生成池ID和LP代币ID
 	poolID := generatePoolID(tokenA, tokenB)
-	lpTokenID := generateLPTokenID(poolID)
+	lpTokenID := generateLPTokenID(contractAddr, poolID)
 
 	// 查询LP代币总供应量（简化实现）
 	lpTotalSupply := uint64(0) // 首次添加流动性
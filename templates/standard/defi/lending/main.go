@@ -27,13 +27,22 @@
 //  4. Withdraw - 取款
 //     - 取出存款和收益
 //
+//  5. Liquidate / BidLiquidation / SettleLiquidation - 清算
+//     - 没收借款人的抵押品，以降价拍卖出售（helpers/market 清算拍卖）
+//     - 任何人按当前价格偿还部分欠款换取抵押品
+//     - 结算时剩余抵押品退还借款人，未收回的欠款仍记在借款人名下
+//
 // ⚠️ 注意：本示例是简化实现
 //   实际应用中需要实现：
 //   - 利率计算（根据市场供需动态调整）
 //   - 抵押率检查（确保抵押品价值足够）
-//   - 清算机制（抵押品不足时清算）
+//   - 清算条件判断（需要价格预言机，本示例由合约所有者发起清算）
 //   - 存款凭证代币管理
 //
+// 📒 资金台账
+//   存款与借款均记录在 framework/subaccount 子账户台账中，
+//   每次调用结束前断言：合约余额 + 未偿还借款 ≥ 存款总负债
+//
 // 📚 相关文档
 //
 //   - [Token 模块文档](../../../helpers/token/README.md)
@@ -43,9 +52,33 @@
 package main
 
 import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
 	"github.com/weisyn/contract-sdk-go/helpers/market"
 	"github.com/weisyn/contract-sdk-go/helpers/token"
-	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// 子账户台账命名空间
+//
+// 合约资金统一托管在合约地址下，由 framework/subaccount 台账记录归属：
+//   - 存款台账：合约对存款人的负债
+//   - 借款台账：借款人对合约的欠款
+const (
+	LEDGER_DEPOSITS = "lending_deposits"
+	LEDGER_BORROWS  = "lending_borrows"
+)
+
+// STATE_OWNER 合约所有者地址状态ID（Initialize 时写入，清算权限检查使用）
+const STATE_OWNER = "owner"
+
+// 默认清算拍卖参数（Liquidate 未指定时使用）
+const (
+	// DEFAULT_AUCTION_DURATION 拍卖时长：6小时
+	DEFAULT_AUCTION_DURATION = 6 * 3600
+	// DEFAULT_AUCTION_START_PRICE_BP 起始价格：保本价格的 150%
+	DEFAULT_AUCTION_START_PRICE_BP = 15000
+	// DEFAULT_AUCTION_DECAY_BP 到期时价格降至起始价格的 40%
+	DEFAULT_AUCTION_DECAY_BP = 6000
 )
 
 // LendingContract 借贷协议合约
@@ -68,10 +101,12 @@ type LendingContract struct {
 //
 // 工作流程：
 //  1. 获取合约调用者（部署者）
-//  2. 发出合约初始化事件
+//  2. 记录合约所有者
+//  3. 发出合约初始化事件
 //
 // 返回：
 //   - framework.SUCCESS - 初始化成功
+//   - framework.ERROR_EXECUTION_FAILED - 写入状态失败
 //
 // 事件：
 //   - ContractInitialized - 合约初始化事件
@@ -82,7 +117,12 @@ type LendingContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
+	if _, err := framework.AppendStateOutputSimple([]byte(STATE_OWNER), 1, caller.ToBytes(), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Lending")
 	event.AddAddressField("owner", caller)
@@ -135,6 +175,7 @@ func Initialize() uint32 {
 //
 //export Deposit
 func Deposit() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -178,13 +219,26 @@ func Deposit() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤6：铸造存款凭证代币
+	// 步骤6：记入存款台账
+	if err := subaccount.CreditSubAccount(LEDGER_DEPOSITS, caller, tokenID, framework.Amount(amount)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 步骤7：铸造存款凭证代币
 	// ⚠️ 注意：这是一个简化实现
 	//   实际应用中，应该铸造存款凭证代币（cToken）给用户
 	//   凭证代币数量 = 存款数量 * 凭证汇率
 	//   这里简化处理，不实际铸造凭证代币
 
-	// 步骤7：发出存款事件
+	// 步骤8：守恒检查
+	if code := checkSolvency(tokenID); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤9：发出存款事件
 	event := framework.NewEvent("Deposit")
 	event.AddAddressField("depositor", caller)
 	if tokenIDStr != "" {
@@ -242,6 +296,7 @@ func Deposit() uint32 {
 //
 //export Borrow
 func Borrow() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -296,12 +351,22 @@ func Borrow() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤8：记录借款信息
+	// 步骤8：记入借款台账
 	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该使用状态输出记录借款信息
-	//   包括借款数量、利率、到期时间等
+	//   实际应用中，还应记录利率、到期时间等借款信息
+	if err := subaccount.CreditSubAccount(LEDGER_BORROWS, caller, tokenID, framework.Amount(amount)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 步骤9：守恒检查
+	if code := checkSolvency(tokenID); code != framework.SUCCESS {
+		return code
+	}
 
-	// 步骤9：发出借款事件
+	// 步骤10：发出借款事件
 	event := framework.NewEvent("Borrow")
 	event.AddAddressField("borrower", caller)
 	if tokenIDStr != "" {
@@ -359,6 +424,7 @@ func Borrow() uint32 {
 //
 //export Repay
 func Repay() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -383,10 +449,14 @@ func Repay() uint32 {
 		return framework.ERROR_INSUFFICIENT_BALANCE
 	}
 
-	// 步骤5：查询借款信息
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该从状态输出查询借款信息
-	//   包括借款数量、利率、到期时间等
+	// 步骤5：查询借款信息（还款数量不能超过借款台账余额）
+	debt, err := subaccount.GetSubAccountBalance(LEDGER_BORROWS, caller, tokenID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if framework.Amount(amount) > debt {
+		return framework.ERROR_INVALID_PARAMS
+	}
 
 	// 步骤6：计算应还金额
 	// ⚠️ 注意：这是一个简化实现
@@ -396,7 +466,7 @@ func Repay() uint32 {
 
 	// 步骤7：转移代币到合约
 	contractAddr := framework.GetContractAddress()
-	err := token.Transfer(
+	err = token.Transfer(
 		caller,                       // 从借款人地址
 		contractAddr,                 // 到合约地址
 		tokenID,                      // 代币ID
@@ -409,17 +479,25 @@ func Repay() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤8：更新借款信息
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该使用状态输出更新借款信息
-	//   减少借款余额，更新还款时间等
+	// 步骤8：更新借款台账
+	if err := subaccount.DebitSubAccount(LEDGER_BORROWS, caller, tokenID, framework.Amount(amount)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
 	// 步骤9：释放抵押品
 	// ⚠️ 注意：这是一个简化实现
 	//   实际应用中，应该根据还款比例释放抵押品
 	//   释放数量 = 还款数量 / 抵押率
 
-	// 步骤10：发出还款事件
+	// 步骤10：守恒检查
+	if code := checkSolvency(tokenID); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤11：发出还款事件
 	event := framework.NewEvent("Repay")
 	event.AddAddressField("borrower", caller)
 	if tokenIDStr != "" {
@@ -476,6 +554,7 @@ func Repay() uint32 {
 //
 //export Withdraw
 func Withdraw() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -494,10 +573,13 @@ func Withdraw() uint32 {
 	// 步骤3：获取调用者
 	caller := framework.GetCaller()
 
-	// 步骤4：查询存款信息
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该从状态输出查询存款信息
-	//   包括存款数量、存款凭证代币数量等
+	// 步骤4：扣减存款台账（虚拟余额不足时失败）
+	if err := subaccount.DebitSubAccount(LEDGER_DEPOSITS, caller, tokenID, framework.Amount(amount)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
 	// 步骤5：计算可取金额
 	// ⚠️ 注意：这是一个简化实现
@@ -531,7 +613,12 @@ func Withdraw() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤9：发出取款事件
+	// 步骤9：守恒检查
+	if code := checkSolvency(tokenID); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤10：发出取款事件
 	event := framework.NewEvent("Withdraw")
 	event.AddAddressField("depositor", caller)
 	if tokenIDStr != "" {
@@ -543,5 +630,280 @@ func Withdraw() uint32 {
 	return framework.SUCCESS
 }
 
+// Liquidate 清算
+//
+// 没收借款人在抵押代币上的全部存款，发起降价拍卖出售抵押品以收回借款人在借款代币上的全部欠款。
+// 相比固定折扣清算，价格从高到低下降，出价方只能以当时的价格成交，
+// 抵押品卖出的价值不会以固定折扣让给抢先清算的一方。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "borrower": "Cf1...",             // 借款人地址（Base58，必填）
+//	  "collateral_token": "TOKEN_001",  // 抵押代币ID（可选，空表示原生代币）
+//	  "debt_token": "TOKEN_002",        // 借款代币ID（可选，空表示原生代币，须与抵押代币不同）
+//	  "duration": 21600,                // 拍卖时长（秒，可选，默认6小时）
+//	  "start_price_bp": 15000,          // 起始价格（基点，相对保本价格，可选，默认 15000）
+//	  "decay_bp": 6000                  // 到期时价格下降比例（基点，可选，默认 6000）
+//	}
+//
+// 工作流程：
+//  1. 解析参数并检查调用者为合约所有者
+//  2. 查询借款人的抵押存款与欠款
+//  3. 没收抵押品：扣减存款台账
+//  4. 欠款转入合约名下的清算账户（拍卖期间借款人无法对该部分还款）
+//  5. 发起清算拍卖
+//  6. 守恒检查
+//  7. 发出清算事件
+//
+// ⚠️ 注意：这是一个简化实现
+//   实际应用中，应由价格预言机判断抵押率低于清算线后任何人均可发起清算，
+//   这里简化为由合约所有者发起
+//
+// 返回：
+//   - framework.SUCCESS - 清算拍卖已发起，返回数据为拍卖ID
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是合约所有者
+//   - framework.ERROR_INVALID_STATE - 借款人没有抵押存款或欠款
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - LiquidationAuctionStarted - 拍卖发起事件（helpers/market）
+//   - Liquidate - 清算事件
+//     {
+//       "borrower": "<借款人地址>",
+//       "auction_id": "auction_1",
+//       "collateral_token_id": "TOKEN_001",
+//       "collateral_amount": 10000,
+//       "debt_token_id": "TOKEN_002",
+//       "debt_amount": 8000
+//     }
+//
+//export Liquidate
+func Liquidate() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数并检查权限
+	params := framework.GetContractParams()
+	borrower, err := framework.ParseAddressBase58(params.ParseJSON("borrower"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	collateralToken := framework.TokenID(params.ParseJSON("collateral_token"))
+	debtToken := framework.TokenID(params.ParseJSON("debt_token"))
+	auctionParams := market.AuctionParams{
+		Duration:     params.GetIntOr("duration", DEFAULT_AUCTION_DURATION),
+		StartPriceBP: params.GetIntOr("start_price_bp", DEFAULT_AUCTION_START_PRICE_BP),
+		DecayBP:      params.GetIntOr("decay_bp", DEFAULT_AUCTION_DECAY_BP),
+	}
+	if collateralToken == debtToken {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if !checkOwner() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤2：查询借款人的抵押存款与欠款
+	collateral, err := subaccount.GetSubAccountBalance(LEDGER_DEPOSITS, borrower, collateralToken)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	debt, err := subaccount.GetSubAccountBalance(LEDGER_BORROWS, borrower, debtToken)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if collateral == 0 || debt == 0 {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 步骤3：没收抵押品（抵押品仍由合约地址持有，由拍卖出售）
+	if err := subaccount.DebitSubAccount(LEDGER_DEPOSITS, borrower, collateralToken, collateral); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤4：欠款转入清算账户（合约地址名下），拍卖收回的欠款从该账户扣减
+	contractAddr := framework.GetContractAddress()
+	if err := subaccount.TransferBetweenSubAccounts(LEDGER_BORROWS, borrower, contractAddr, debtToken, debt); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤5：发起清算拍卖
+	auctionID, err := market.StartLiquidationAuction(borrower, collateralToken, collateral, debtToken, debt, auctionParams)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤6：守恒检查
+	if code := checkSolvency(collateralToken); code != framework.SUCCESS {
+		return code
+	}
+	if code := checkSolvency(debtToken); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤7：发出清算事件
+	event := framework.NewEvent("Liquidate")
+	event.AddAddressField("borrower", borrower)
+	event.AddStringField("auction_id", auctionID)
+	event.AddStringField("collateral_token_id", string(collateralToken))
+	event.AddUint64Field("collateral_amount", uint64(collateral))
+	event.AddStringField("debt_token_id", string(debtToken))
+	event.AddUint64Field("debt_amount", uint64(debt))
+	framework.EmitEvent(event)
+
+	framework.SetReturnString(auctionID)
+	return framework.SUCCESS
+}
+
+// BidLiquidation 清算拍卖出价
+//
+// 按当前拍卖价格偿还被清算借款人的部分欠款，换取相应的抵押品。允许部分成交。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "auction_id": "auction_1",  // 拍卖ID（必填）
+//	  "amount": 3000              // 愿意偿还的欠款（必填，超过剩余欠款时按剩余欠款成交）
+//	}
+//
+// 工作流程：
+//  1. 解析参数并验证
+//  2. 按当前价格成交：欠款转入合约，抵押品转给出价方
+//  3. 从清算账户扣减收回的欠款
+//  4. 守恒检查
+//
+// 返回：
+//   - framework.SUCCESS - 出价成交
+//   - framework.ERROR_INVALID_PARAMS - 参数无效或出价过小
+//   - framework.ERROR_NOT_FOUND - 拍卖不存在
+//   - framework.ERROR_INVALID_STATE - 拍卖已结束
+//   - framework.ERROR_TIMEOUT - 拍卖已到期
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//
+// 事件：
+//   - LiquidationBid - 出价成交事件（helpers/market）
+//
+//export BidLiquidation
+func BidLiquidation() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数并验证
+	params := framework.GetContractParams()
+	auctionID := params.ParseJSON("auction_id")
+	amount := params.ParseJSONInt("amount")
+	if auctionID == "" || amount == 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：按当前价格成交
+	fill, err := market.BidLiquidation(framework.GetCaller(), auctionID, framework.Amount(amount))
+	if err != nil {
+		return contractErrorCode(err)
+	}
+	auction, err := market.GetLiquidationAuction(auctionID)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤3：从清算账户扣减收回的欠款
+	if err := subaccount.DebitSubAccount(LEDGER_BORROWS, framework.GetContractAddress(), auction.DebtToken, fill.DebtPaid); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤4：守恒检查
+	if code := checkSolvency(auction.CollateralToken); code != framework.SUCCESS {
+		return code
+	}
+	if code := checkSolvency(auction.DebtToken); code != framework.SUCCESS {
+		return code
+	}
+	return framework.SUCCESS
+}
+
+// SettleLiquidation 结算清算拍卖
+//
+// 拍卖结束（欠款全部收回、抵押品售罄或到期）后任何人均可调用：
+// 剩余抵押品退还借款人；未收回的欠款从清算账户转回借款人名下，借款人仍需偿还。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "auction_id": "auction_1"  // 拍卖ID（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 结算成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_NOT_FOUND - 拍卖不存在
+//   - framework.ERROR_INVALID_STATE - 拍卖已结算或尚未结束
+//
+// 事件：
+//   - LiquidationAuctionSettled - 拍卖结算事件（helpers/market，含 surplus 与 shortfall）
+//
+//export SettleLiquidation
+func SettleLiquidation() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	auctionID := params.ParseJSON("auction_id")
+	if auctionID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：结算拍卖，剩余抵押品退还借款人
+	settlement, err := market.SettleAuction(auctionID)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+	auction, err := market.GetLiquidationAuction(auctionID)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤3：未收回的欠款转回借款人名下
+	if settlement.Shortfall > 0 {
+		if err := subaccount.TransferBetweenSubAccounts(LEDGER_BORROWS, framework.GetContractAddress(), auction.Owner, auction.DebtToken, settlement.Shortfall); err != nil {
+			return contractErrorCode(err)
+		}
+	}
+
+	// 步骤4：守恒检查
+	if code := checkSolvency(auction.CollateralToken); code != framework.SUCCESS {
+		return code
+	}
+	return checkSolvency(auction.DebtToken)
+}
+
+// checkOwner 检查当前调用者是否为合约所有者
+func checkOwner() bool {
+	ownerData, _ := framework.GetState(STATE_OWNER)
+	if len(ownerData) == 0 {
+		return false
+	}
+	return string(ownerData) == string(framework.GetCaller().ToBytes())
+}
+
+// contractErrorCode 提取合约错误码，其他错误返回 ERROR_EXECUTION_FAILED
+func contractErrorCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	return framework.ERROR_EXECUTION_FAILED
+}
+
+// checkSolvency 守恒检查
+//
+// 借出的资金已离开合约地址，因此可偿付存款的资产为：
+//   合约余额 + 借款台账总额 ≥ 存款台账总负债
+func checkSolvency(tokenID framework.TokenID) uint32 {
+	outstanding, err := subaccount.TotalLiabilities(LEDGER_BORROWS, tokenID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	contractBalance := framework.QueryUTXOBalance(framework.GetContractAddress(), tokenID)
+	if err := subaccount.AssertConservation(LEDGER_DEPOSITS, tokenID, contractBalance+outstanding); err != nil {
+		return framework.ERROR_INVALID_STATE
+	}
+	return framework.SUCCESS
+}
+
 func main() {}
 
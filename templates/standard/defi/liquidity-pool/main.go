@@ -76,6 +76,7 @@ type LiquidityPoolContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "LiquidityPool")
@@ -131,6 +132,7 @@ func Initialize() uint32 {
 //
 //export AddLiquidity
 func AddLiquidity() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -240,6 +242,7 @@ func AddLiquidity() uint32 {
 //
 //export RemoveLiquidity
 func RemoveLiquidity() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -334,6 +337,7 @@ func RemoveLiquidity() uint32 {
 //
 //export QueryPoolInfo
 func QueryPoolInfo() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -74,6 +74,7 @@ type DAOContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "DAO")
@@ -125,6 +126,7 @@ func Initialize() uint32 {
 //
 //export CreateProposal
 func CreateProposal() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -221,6 +223,7 @@ func CreateProposal() uint32 {
 //
 //export Vote
 func Vote() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -320,6 +323,7 @@ func Vote() uint32 {
 //
 //export ExecuteProposal
 func ExecuteProposal() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -385,6 +389,7 @@ func ExecuteProposal() uint32 {
 //
 //export QueryProposal
 func QueryProposal() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -44,6 +44,7 @@ const (
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	contract := &GovernanceContract{}
 
 	admin := []byte(contract.GetCaller())
@@ -91,6 +92,7 @@ func Initialize() uint32 {
 //
 //export CreateProposal
 func CreateProposal() uint32 {
+	framework.BeginInvocation()
 	contract := &GovernanceContract{}
 
 	proposer := []byte(contract.GetCaller())
@@ -142,6 +144,7 @@ func CreateProposal() uint32 {
 //
 //export Vote
 func Vote() uint32 {
+	framework.BeginInvocation()
 	contract := &GovernanceContract{}
 
 	voter := []byte(contract.GetCaller())
@@ -210,6 +213,7 @@ func Vote() uint32 {
 //
 //export ExecuteProposal
 func ExecuteProposal() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 
@@ -217,6 +221,7 @@ func ExecuteProposal() uint32 {
 //
 //export CancelProposal
 func CancelProposal() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 
@@ -0,0 +1,1142 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/secure"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+// ================================================================================================
+// 陪审法庭记账（纯函数 + 存储抽象）
+// ================================================================================================
+//
+// 本文件只包含不依赖宿主函数的法庭逻辑（宿主存储与裁决指令发件箱除外，两者配合 MockHost 测试），
+// 不带 build tag，便于在非WASM环境中直接运行单元测试（go test）。
+// 状态读写经由 subaccount.Store，测试使用 subaccount.NewMemoryStore()；
+// 导出方法（main.go）负责解析参数、划转代币后调用这些逻辑。
+//
+// 陪审员质押与各类待结算资金均记入 subaccount.Ledger：
+//   - juror_stakes：陪审员质押（奖励直接计入质押）
+//   - dispute_fees：争议方预付的仲裁费，裁决后分配
+//   - court_fees：法庭手续费，归合约所有者提取
+//
+// 三个命名空间的总负债之和始终等于合约代币余额中属于法庭的部分，
+// 裁决只在命名空间之间划转，不改变总额。
+
+// 争议状态常量
+const (
+	// DISPUTE_STATUS_CREATED 已创建：等待抽选陪审团
+	DISPUTE_STATUS_CREATED = "CREATED"
+	// DISPUTE_STATUS_PANEL_DRAWN 已抽选：进入提交/揭示投票阶段
+	DISPUTE_STATUS_PANEL_DRAWN = "PANEL_DRAWN"
+	// DISPUTE_STATUS_RESOLVED 已裁决：已通知托管合约并完成质押再分配
+	DISPUTE_STATUS_RESOLVED = "RESOLVED"
+)
+
+// 裁决结果常量（与托管合约的结算动作对应）
+const (
+	// RULING_SPLIT 平票：货款按托管合约的平分规则处理
+	RULING_SPLIT = 0
+	// RULING_BUYER 支持买方：退款（refund）
+	RULING_BUYER = 1
+	// RULING_SELLER 支持卖方：放款（release）
+	RULING_SELLER = 2
+)
+
+// 台账命名空间
+const (
+	NS_JUROR_STAKES = "juror_stakes"
+	NS_DISPUTE_FEES = "dispute_fees"
+	NS_COURT_FEES   = "court_fees"
+)
+
+const (
+	// MAX_PANEL_SIZE 单个争议陪审团人数上限
+	MAX_PANEL_SIZE = 15
+	// MAX_JURORS 陪审员登记上限（抽选时需读取全部陪审员）
+	MAX_JURORS = 256
+	// MAX_ID_LENGTH 争议ID、托管ID最大字节数
+	MAX_ID_LENGTH = 32
+	// BP_DENOMINATOR 基点分母，10000 = 100%
+	BP_DENOMINATOR = 10000
+)
+
+// 默认法庭参数
+const (
+	DEFAULT_MIN_STAKE             = 1000
+	DEFAULT_COMMIT_PERIOD         = 86400 // 1天
+	DEFAULT_REVEAL_PERIOD         = 86400 // 1天
+	DEFAULT_INCOHERENT_PENALTY_BP = 1000  // 10%
+	DEFAULT_NON_REVEAL_PENALTY_BP = 2000  // 20%
+	DEFAULT_COURT_FEE_BP          = 500   // 5%
+)
+
+// 记账错误
+var (
+	errInvalidParams     = errors.New("invalid court params")
+	errNotInitialized    = errors.New("court not initialized")
+	errAlreadyExists     = errors.New("already exists")
+	errNotFound          = errors.New("not found")
+	errUnauthorized      = errors.New("unauthorized")
+	errInvalidStatus     = errors.New("dispute status does not allow this action")
+	errJurorAssigned     = errors.New("juror is assigned to an unresolved dispute")
+	errJurorPoolFull     = errors.New("juror pool is full")
+	errNotEnoughJurors   = errors.New("not enough eligible jurors")
+	errSeedNotAvailable  = errors.New("panel seed block not yet available")
+	errNotPanelist       = errors.New("caller is not on the panel")
+	errPhaseClosed       = errors.New("voting phase not open")
+	errAlreadyVoted      = errors.New("vote already committed or revealed")
+	errCommitMismatch    = errors.New("reveal does not match commitment")
+	errInsufficientStake = errors.New("insufficient stake")
+)
+
+// CourtConfig 法庭参数（Initialize 时设置）
+type CourtConfig struct {
+	Owner               framework.Address // 合约所有者，提取法庭手续费
+	TokenID             framework.TokenID // 质押与仲裁费代币（空表示原生币）
+	MinStake            uint64            // 参与抽选的最低质押
+	CommitPeriod        uint64            // 抽选后提交期时长（秒）
+	RevealPeriod        uint64            // 提交期结束后揭示期时长（秒）
+	IncoherentPenaltyBP uint64            // 投票与裁决不一致的罚没比例
+	NonRevealPenaltyBP  uint64            // 未揭示（含未提交）的罚没比例
+	CourtFeeBP          uint64            // 罚没与仲裁费合计中法庭抽取的比例
+}
+
+// Seat 陪审团席位
+type Seat struct {
+	Juror     framework.Address
+	Commit    [32]byte // sha256(dispute_id || juror || choice || salt)
+	Committed bool
+	Revealed  bool
+	Choice    uint32 // 揭示的投票：RULING_BUYER / RULING_SELLER
+	Penalty   uint64 // 裁决时罚没的质押
+	Reward    uint64 // 裁决时分得的奖励
+}
+
+// Dispute 争议
+type Dispute struct {
+	DisputeID      string
+	Disputer       framework.Address // 发起并预付仲裁费的一方
+	EscrowContract framework.Address // 接收裁决的托管合约
+	EscrowID       string
+	EvidenceHash   [32]byte
+	PanelSize      uint64
+	Fee            uint64
+	Status         string
+	CreatedHeight  uint64
+	CreatedAt      uint64
+	CommitDeadline uint64
+	RevealDeadline uint64
+	Ruling         uint32
+	VotesBuyer     uint64
+	VotesSeller    uint64
+	CourtFee       uint64
+	Seats          []Seat
+}
+
+// ArbitrationInstruction 发往托管合约的裁决指令
+type ArbitrationInstruction struct {
+	DisputeID      string
+	EscrowContract framework.Address
+	EscrowID       string
+	Ruling         uint32
+}
+
+// EscrowParties 托管交易的买卖双方，只有双方可以就该托管交易发起争议
+//
+// **注意**：当前 SDK 尚未提供跨合约调用，法庭无法读取托管合约的状态；
+// 托管合约（以其合约地址为调用者）将本合约设为仲裁人时通过 RegisterEscrow 登记双方
+type EscrowParties struct {
+	Buyer  framework.Address
+	Seller framework.Address
+}
+
+// ArbitrationTarget 裁决指令接收方
+//
+// 🎯 **用途**：ResolveDispute 通过该接口调用托管合约的 Arbitrate，
+// 返回错误时裁决整体失败，质押与争议状态保持不变
+//
+// **注意**：当前 SDK 尚未提供跨合约调用，合约内使用 stateOutboxTarget 将指令
+// 写入以托管合约和托管ID寻址的状态输出；提供跨合约调用后替换实现即可
+type ArbitrationTarget interface {
+	Arbitrate(instr ArbitrationInstruction) error
+}
+
+// ================================================================================================
+// 法庭
+// ================================================================================================
+
+// Court 陪审法庭
+type Court struct {
+	store  subaccount.Store
+	ledger *subaccount.Ledger
+}
+
+// newCourt 基于存储后端创建法庭，台账与法庭状态共用同一存储
+func newCourt(store subaccount.Store) *Court {
+	return &Court{store: store, ledger: subaccount.NewLedger(store)}
+}
+
+// initialize 保存法庭参数，只能调用一次
+func (c *Court) initialize(cfg CourtConfig) error {
+	if cfg.Owner == (framework.Address{}) || len(cfg.TokenID) > MAX_ID_LENGTH || cfg.MinStake == 0 ||
+		cfg.CommitPeriod == 0 || cfg.RevealPeriod == 0 ||
+		cfg.IncoherentPenaltyBP > BP_DENOMINATOR || cfg.NonRevealPenaltyBP > BP_DENOMINATOR || cfg.CourtFeeBP > BP_DENOMINATOR {
+		return errInvalidParams
+	}
+	if data, err := c.get(courtConfigKey, courtConfigSize); err != nil {
+		return err
+	} else if data != nil {
+		return errAlreadyExists
+	}
+	return c.put(courtConfigKey, encodeCourtConfig(cfg))
+}
+
+// config 读取法庭参数
+func (c *Court) config() (CourtConfig, error) {
+	data, err := c.get(courtConfigKey, courtConfigSize)
+	if err != nil {
+		return CourtConfig{}, err
+	}
+	if data == nil {
+		return CourtConfig{}, errNotInitialized
+	}
+	return decodeCourtConfig(data), nil
+}
+
+// ==================== 陪审员 ====================
+
+// stake 增加陪审员质押，首次质押时登记陪审员
+//
+// 代币须已由调用方转入合约地址；返回质押后的总额
+func (c *Court) stake(juror framework.Address, amount uint64) (uint64, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return 0, err
+	}
+	if juror == (framework.Address{}) || amount == 0 {
+		return 0, errInvalidParams
+	}
+	rec, err := c.juror(juror)
+	if err != nil {
+		return 0, err
+	}
+	if !rec.Registered {
+		count, err := c.jurorCount()
+		if err != nil {
+			return 0, err
+		}
+		if count >= MAX_JURORS {
+			return 0, errJurorPoolFull
+		}
+		if err := c.put(jurorAtKey(count), juror[:]); err != nil {
+			return 0, err
+		}
+		if err := c.put(jurorCountKey, framework.Uint64ToBytes(count+1)); err != nil {
+			return 0, err
+		}
+		rec = jurorRecord{Registered: true}
+		if err := c.putJuror(juror, rec); err != nil {
+			return 0, err
+		}
+	}
+	if err := c.ledger.Credit(NS_JUROR_STAKES, juror, cfg.TokenID, framework.Amount(amount)); err != nil {
+		return 0, err
+	}
+	return c.stakeOf(juror, cfg.TokenID)
+}
+
+// unstake 取回陪审员质押；陪审员仍在未裁决争议的陪审团中时锁定
+//
+// 返回剩余质押；调用方随后将 amount 从合约地址转给陪审员
+func (c *Court) unstake(juror framework.Address, amount uint64) (uint64, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return 0, err
+	}
+	if amount == 0 {
+		return 0, errInvalidParams
+	}
+	rec, err := c.juror(juror)
+	if err != nil {
+		return 0, err
+	}
+	if !rec.Registered {
+		return 0, errNotFound
+	}
+	if rec.Assigned > 0 {
+		return 0, errJurorAssigned
+	}
+	staked, err := c.stakeOf(juror, cfg.TokenID)
+	if err != nil {
+		return 0, err
+	}
+	if staked < amount {
+		return 0, errInsufficientStake
+	}
+	if err := c.ledger.Debit(NS_JUROR_STAKES, juror, cfg.TokenID, framework.Amount(amount)); err != nil {
+		return 0, err
+	}
+	return staked - amount, nil
+}
+
+// stakeOf 查询陪审员质押
+func (c *Court) stakeOf(juror framework.Address, tokenID framework.TokenID) (uint64, error) {
+	balance, err := c.ledger.Balance(NS_JUROR_STAKES, juror, tokenID)
+	return uint64(balance), err
+}
+
+// jurors 按登记顺序读取全部陪审员
+func (c *Court) jurors() ([]framework.Address, error) {
+	count, err := c.jurorCount()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]framework.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		data, err := c.get(jurorAtKey(i), 20)
+		if err != nil {
+			return nil, err
+		}
+		var addr framework.Address
+		copy(addr[:], data)
+		out = append(out, addr)
+	}
+	return out, nil
+}
+
+// ==================== 争议 ====================
+
+// registerEscrow 登记托管交易的买卖双方，每笔托管交易只登记一次
+//
+// escrowContract 为调用者地址：托管合约只能登记自身的托管交易
+func (c *Court) registerEscrow(escrowContract framework.Address, escrowID string, parties EscrowParties) error {
+	zero := framework.Address{}
+	if escrowContract == zero || escrowID == "" || len(escrowID) > MAX_ID_LENGTH ||
+		parties.Buyer == zero || parties.Seller == zero || parties.Buyer == parties.Seller {
+		return errInvalidParams
+	}
+	existing, err := c.get(escrowPartiesKey(escrowContract, escrowID), 0)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errAlreadyExists
+	}
+	data := make([]byte, 40)
+	copy(data[0:20], parties.Buyer[:])
+	copy(data[20:40], parties.Seller[:])
+	return c.put(escrowPartiesKey(escrowContract, escrowID), data)
+}
+
+// escrowParties 读取托管交易的买卖双方，未登记时返回 errNotFound
+func (c *Court) escrowParties(escrowContract framework.Address, escrowID string) (EscrowParties, error) {
+	data, err := c.get(escrowPartiesKey(escrowContract, escrowID), 40)
+	if err != nil {
+		return EscrowParties{}, err
+	}
+	if data == nil {
+		return EscrowParties{}, errNotFound
+	}
+	var parties EscrowParties
+	copy(parties.Buyer[:], data[0:20])
+	copy(parties.Seller[:], data[20:40])
+	return parties, nil
+}
+
+// createDispute 创建争议，仲裁费记入 dispute_fees（代币须已由调用方转入合约地址）
+//
+// 争议方须为托管交易登记的买方或卖方，托管交易未登记时返回 errNotFound
+func (c *Court) createDispute(d *Dispute) error {
+	cfg, err := c.config()
+	if err != nil {
+		return err
+	}
+	if d.DisputeID == "" || len(d.DisputeID) > MAX_ID_LENGTH || d.EscrowID == "" || len(d.EscrowID) > MAX_ID_LENGTH ||
+		d.EscrowContract == (framework.Address{}) || d.PanelSize == 0 || d.PanelSize > MAX_PANEL_SIZE || d.Fee == 0 {
+		return errInvalidParams
+	}
+	parties, err := c.escrowParties(d.EscrowContract, d.EscrowID)
+	if err != nil {
+		return err
+	}
+	if d.Disputer != parties.Buyer && d.Disputer != parties.Seller {
+		return errUnauthorized
+	}
+	existing, err := c.dispute(d.DisputeID)
+	if err != nil && err != errNotFound {
+		return err
+	}
+	if existing != nil {
+		return errAlreadyExists
+	}
+	if err := c.requireNoOpenDispute(d.EscrowContract, d.EscrowID); err != nil {
+		return err
+	}
+
+	d.Status = DISPUTE_STATUS_CREATED
+	d.Seats = nil
+	if err := c.ledger.Credit(NS_DISPUTE_FEES, d.Disputer, cfg.TokenID, framework.Amount(d.Fee)); err != nil {
+		return err
+	}
+	if err := c.put(escrowDisputeKey(d.EscrowContract, d.EscrowID), []byte(d.DisputeID)); err != nil {
+		return err
+	}
+	return c.saveDispute(d)
+}
+
+// requireNoOpenDispute 同一托管交易同时只能有一个未裁决的争议，上一个争议裁决后可再次发起
+func (c *Court) requireNoOpenDispute(escrowContract framework.Address, escrowID string) error {
+	activeID, err := c.get(escrowDisputeKey(escrowContract, escrowID), 0)
+	if err != nil || activeID == nil {
+		return err
+	}
+	active, err := c.dispute(string(activeID))
+	if err == errNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if active.Status != DISPUTE_STATUS_RESOLVED {
+		return errAlreadyExists
+	}
+	return nil
+}
+
+// drawPanel 按质押加权、不放回地抽选陪审团，并开启提交期
+//
+// 种子取争议创建后下一个区块的哈希（seedBlockHash）与争议ID的 SHA-256，
+// 任何人在该区块产生后调用得到相同的陪审团；争议方在创建时无法预知种子。
+// 低于最低质押的陪审员与争议方本人不参与抽选。
+//
+// ⚠️ 出块者可在一定程度上影响区块哈希，高价值争议应使用更长的种子延迟或外部随机源。
+func (c *Court) drawPanel(disputeID string, height uint64, seedBlockHash []byte, now uint64) (*Dispute, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.dispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DISPUTE_STATUS_CREATED {
+		return nil, errInvalidStatus
+	}
+	if height <= d.CreatedHeight+1 {
+		return nil, errSeedNotAvailable
+	}
+
+	// 1. 收集合格陪审员
+	all, err := c.jurors()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, 0, len(all))
+	for _, addr := range all {
+		if addr == d.Disputer {
+			continue
+		}
+		staked, err := c.stakeOf(addr, cfg.TokenID)
+		if err != nil {
+			return nil, err
+		}
+		if staked >= cfg.MinStake {
+			candidates = append(candidates, candidate{Juror: addr, Weight: staked})
+		}
+	}
+
+	// 2. 抽选
+	picked, err := selectPanel(candidates, panelSeed(seedBlockHash, disputeID), d.PanelSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 锁定入选陪审员的质押
+	d.Seats = make([]Seat, len(picked))
+	for i, idx := range picked {
+		juror := candidates[idx].Juror
+		d.Seats[i] = Seat{Juror: juror}
+		rec, err := c.juror(juror)
+		if err != nil {
+			return nil, err
+		}
+		rec.Assigned++
+		if err := c.putJuror(juror, rec); err != nil {
+			return nil, err
+		}
+	}
+
+	d.Status = DISPUTE_STATUS_PANEL_DRAWN
+	d.CommitDeadline = now + cfg.CommitPeriod
+	d.RevealDeadline = d.CommitDeadline + cfg.RevealPeriod
+	return d, c.saveDispute(d)
+}
+
+// commitVote 提交投票承诺（仅提交期内、仅陪审团成员、每席一次）
+func (c *Court) commitVote(disputeID string, juror framework.Address, commitment [32]byte, now uint64) (*Dispute, error) {
+	d, err := c.dispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DISPUTE_STATUS_PANEL_DRAWN {
+		return nil, errInvalidStatus
+	}
+	if now >= d.CommitDeadline {
+		return nil, errPhaseClosed
+	}
+	seat := d.seat(juror)
+	if seat == nil {
+		return nil, errNotPanelist
+	}
+	if seat.Committed {
+		return nil, errAlreadyVoted
+	}
+	seat.Commit = commitment
+	seat.Committed = true
+	return d, c.saveDispute(d)
+}
+
+// revealVote 揭示投票（仅揭示期内），投票与盐值须与承诺一致
+func (c *Court) revealVote(disputeID string, juror framework.Address, choice uint32, salt []byte, now uint64) (*Dispute, error) {
+	d, err := c.dispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DISPUTE_STATUS_PANEL_DRAWN {
+		return nil, errInvalidStatus
+	}
+	if now < d.CommitDeadline || now >= d.RevealDeadline {
+		return nil, errPhaseClosed
+	}
+	if choice != RULING_BUYER && choice != RULING_SELLER {
+		return nil, errInvalidParams
+	}
+	seat := d.seat(juror)
+	if seat == nil {
+		return nil, errNotPanelist
+	}
+	if !seat.Committed || seat.Revealed {
+		return nil, errAlreadyVoted
+	}
+	// 常量时间比较，比较耗时不泄露承诺与揭示值从第几个字节开始不同
+	expected := voteCommitment(disputeID, juror, choice, salt)
+	if !secure.ConstantTimeEqual(expected[:], seat.Commit[:]) {
+		return nil, errCommitMismatch
+	}
+	seat.Revealed = true
+	seat.Choice = choice
+	return d, c.saveDispute(d)
+}
+
+// resolve 揭示期结束后裁决争议（任何人可触发）
+//
+// 顺序：计票与分配（纯计算）→ 通知托管合约 → 台账划转 → 解锁陪审员 → 保存争议；
+// 通知失败时直接返回，台账与争议状态不变
+func (c *Court) resolve(disputeID string, now uint64, target ArbitrationTarget) (*Dispute, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.dispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DISPUTE_STATUS_PANEL_DRAWN {
+		return nil, errInvalidStatus
+	}
+	if now < d.RevealDeadline {
+		return nil, errPhaseClosed
+	}
+
+	// 1. 计票与罚没/奖励分配
+	stakes := make([]uint64, len(d.Seats))
+	for i, s := range d.Seats {
+		if stakes[i], err = c.stakeOf(s.Juror, cfg.TokenID); err != nil {
+			return nil, err
+		}
+	}
+	settleDispute(d, stakes, cfg)
+
+	// 2. 通知托管合约
+	if err := target.Arbitrate(ArbitrationInstruction{
+		DisputeID:      d.DisputeID,
+		EscrowContract: d.EscrowContract,
+		EscrowID:       d.EscrowID,
+		Ruling:         d.Ruling,
+	}); err != nil {
+		return nil, err
+	}
+
+	// 3. 台账划转：罚没与仲裁费 → 一致陪审员奖励 + 法庭手续费
+	if err := c.ledger.Debit(NS_DISPUTE_FEES, d.Disputer, cfg.TokenID, framework.Amount(d.Fee)); err != nil {
+		return nil, err
+	}
+	for _, s := range d.Seats {
+		if s.Penalty > 0 {
+			if err := c.ledger.Debit(NS_JUROR_STAKES, s.Juror, cfg.TokenID, framework.Amount(s.Penalty)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, s := range d.Seats {
+		if s.Reward > 0 {
+			if err := c.ledger.Credit(NS_JUROR_STAKES, s.Juror, cfg.TokenID, framework.Amount(s.Reward)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if d.CourtFee > 0 {
+		if err := c.ledger.Credit(NS_COURT_FEES, cfg.Owner, cfg.TokenID, framework.Amount(d.CourtFee)); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. 解锁陪审员
+	for _, s := range d.Seats {
+		rec, err := c.juror(s.Juror)
+		if err != nil {
+			return nil, err
+		}
+		if rec.Assigned > 0 {
+			rec.Assigned--
+		}
+		if err := c.putJuror(s.Juror, rec); err != nil {
+			return nil, err
+		}
+	}
+
+	d.Status = DISPUTE_STATUS_RESOLVED
+	return d, c.saveDispute(d)
+}
+
+// withdrawCourtFees 所有者提取法庭手续费（调用方随后将 amount 转给所有者）
+func (c *Court) withdrawCourtFees(caller framework.Address, amount uint64) error {
+	cfg, err := c.config()
+	if err != nil {
+		return err
+	}
+	if caller != cfg.Owner {
+		return errUnauthorized
+	}
+	if amount == 0 {
+		return errInvalidParams
+	}
+	return c.ledger.Debit(NS_COURT_FEES, cfg.Owner, cfg.TokenID, framework.Amount(amount))
+}
+
+// courtFees 查询可提取的法庭手续费
+func (c *Court) courtFees() (uint64, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return 0, err
+	}
+	balance, err := c.ledger.Balance(NS_COURT_FEES, cfg.Owner, cfg.TokenID)
+	return uint64(balance), err
+}
+
+// seat 查找陪审员的席位，不在陪审团中时返回 nil
+func (d *Dispute) seat(juror framework.Address) *Seat {
+	for i := range d.Seats {
+		if d.Seats[i].Juror == juror {
+			return &d.Seats[i]
+		}
+	}
+	return nil
+}
+
+// ================================================================================================
+// 抽选、承诺与裁决（纯函数）
+// ================================================================================================
+
+// candidate 抽选候选人
+type candidate struct {
+	Juror  framework.Address
+	Weight uint64 // 质押
+}
+
+// panelSeed 抽选种子：sha256(区块哈希 || 争议ID)
+func panelSeed(blockHash []byte, disputeID string) []byte {
+	h := sha256.New()
+	h.Write(blockHash)
+	h.Write([]byte(disputeID))
+	return h.Sum(nil)
+}
+
+// selectPanel 按权重不放回地抽选 size 名候选人，返回候选人下标（按席位顺序）
+//
+// 第 s 席：r = uint64(sha256(seed || s)[:8]) mod 剩余总权重，
+// 按候选人顺序累加权重，首个累计值大于 r 的候选人入选并移出候选集。
+// 相同的候选集与种子总是得到相同结果。
+func selectPanel(candidates []candidate, seed []byte, size uint64) ([]int, error) {
+	var total uint64
+	eligible := 0
+	for _, c := range candidates {
+		if c.Weight == 0 {
+			continue
+		}
+		if total+c.Weight < total {
+			return nil, errInvalidParams
+		}
+		total += c.Weight
+		eligible++
+	}
+	if size == 0 || uint64(eligible) < size {
+		return nil, errNotEnoughJurors
+	}
+
+	taken := make([]bool, len(candidates))
+	picked := make([]int, 0, size)
+	for seat := uint64(0); seat < size; seat++ {
+		h := sha256.Sum256(append(append([]byte(nil), seed...), framework.Uint64ToBytes(seat)...))
+		r := framework.BytesToUint64(h[:8]) % total
+
+		var acc uint64
+		for i, c := range candidates {
+			if taken[i] || c.Weight == 0 {
+				continue
+			}
+			acc += c.Weight
+			if r < acc {
+				taken[i] = true
+				picked = append(picked, i)
+				total -= c.Weight
+				break
+			}
+		}
+	}
+	return picked, nil
+}
+
+// voteCommitment 投票承诺：sha256(dispute_id || juror(20) || choice(4, 大端) || salt)
+//
+// 承诺绑定争议与陪审员，无法复制他人的承诺或在其他争议中重放
+func voteCommitment(disputeID string, juror framework.Address, choice uint32, salt []byte) [32]byte {
+	buf := make([]byte, 0, len(disputeID)+20+4+len(salt))
+	buf = append(buf, disputeID...)
+	buf = append(buf, juror[:]...)
+	buf = append(buf, byte(choice>>24), byte(choice>>16), byte(choice>>8), byte(choice))
+	buf = append(buf, salt...)
+	return sha256.Sum256(buf)
+}
+
+// tallyRuling 统计揭示的投票并得出裁决
+//
+// 平票规则：支持买方与支持卖方的票数相同（含无人揭示）时裁决为 RULING_SPLIT，
+// 托管资金按托管合约的平分规则处理；平票时所有揭示者均视为与裁决一致。
+func tallyRuling(seats []Seat) (ruling uint32, buyer, seller uint64) {
+	for _, s := range seats {
+		if !s.Revealed {
+			continue
+		}
+		switch s.Choice {
+		case RULING_BUYER:
+			buyer++
+		case RULING_SELLER:
+			seller++
+		}
+	}
+	switch {
+	case buyer > seller:
+		return RULING_BUYER, buyer, seller
+	case seller > buyer:
+		return RULING_SELLER, buyer, seller
+	default:
+		return RULING_SPLIT, buyer, seller
+	}
+}
+
+// settleDispute 计票并计算每席的罚没与奖励，写入 d（不修改台账）
+//
+// 规则：
+//   - 未揭示（含未提交）：罚没质押的 NonRevealPenaltyBP
+//   - 揭示但与裁决不一致：罚没质押的 IncoherentPenaltyBP
+//   - 奖励池 = 全部罚没 + 仲裁费；法庭抽取 CourtFeeBP，
+//     其余由与裁决一致的揭示者平分，除不尽的余数归法庭
+//   - 无人揭示时奖励池全部归法庭
+//
+// stakes 为各席陪审员当前质押，与 d.Seats 一一对应
+func settleDispute(d *Dispute, stakes []uint64, cfg CourtConfig) {
+	d.Ruling, d.VotesBuyer, d.VotesSeller = tallyRuling(d.Seats)
+
+	pool := d.Fee
+	coherent := uint64(0)
+	for i := range d.Seats {
+		s := &d.Seats[i]
+		s.Penalty, s.Reward = 0, 0
+		switch {
+		case !s.Revealed:
+			s.Penalty = bpOf(stakes[i], cfg.NonRevealPenaltyBP)
+		case !isCoherent(*s, d.Ruling):
+			s.Penalty = bpOf(stakes[i], cfg.IncoherentPenaltyBP)
+		default:
+			coherent++
+		}
+		pool += s.Penalty
+	}
+
+	d.CourtFee = bpOf(pool, cfg.CourtFeeBP)
+	if coherent == 0 {
+		d.CourtFee = pool
+		return
+	}
+	distributable := pool - d.CourtFee
+	share := distributable / coherent
+	d.CourtFee += distributable % coherent
+	for i := range d.Seats {
+		s := &d.Seats[i]
+		if isCoherent(*s, d.Ruling) {
+			s.Reward = share
+		}
+	}
+}
+
+// isCoherent 席位是否揭示且与裁决一致（平票时所有揭示者均一致）
+func isCoherent(s Seat, ruling uint32) bool {
+	return s.Revealed && (ruling == RULING_SPLIT || s.Choice == ruling)
+}
+
+// bpOf 计算 amount * bp / 10000（拆分计算避免溢出）
+func bpOf(amount, bp uint64) uint64 {
+	return amount/BP_DENOMINATOR*bp + amount%BP_DENOMINATOR*bp/BP_DENOMINATOR
+}
+
+// ================================================================================================
+// 裁决指令发件箱
+// ================================================================================================
+
+// ARBITRATION_INSTRUCTION_PREFIX 裁决指令状态ID前缀，
+// 完整格式：arbitration_instruction:{hex(escrow_contract)}:{escrow_id}
+const ARBITRATION_INSTRUCTION_PREFIX = "arbitration_instruction:"
+
+// stateOutboxTarget 将裁决指令写入状态输出的 ArbitrationTarget
+//
+// 托管合约（以本合约地址为仲裁人）或中继据状态ID读取指令并执行 Arbitrate
+type stateOutboxTarget struct{}
+
+// Arbitrate 写入裁决指令：dispute_id(32) + escrow_id(32) + ruling(4)
+//
+// 同一托管交易裁决后可再次发起争议，指令状态以递增版本号覆盖写入，托管合约按 dispute_id 区分
+func (stateOutboxTarget) Arbitrate(instr ArbitrationInstruction) error {
+	stateID := arbitrationInstructionStateID(instr.EscrowContract, instr.EscrowID)
+	version, err := framework.IncrementStateVersion(stateID)
+	if err != nil {
+		return err
+	}
+	_, err = framework.AppendStateOutputSimple(stateID, version, encodeArbitrationInstruction(instr), nil)
+	return err
+}
+
+// arbitrationInstructionStateID 裁决指令状态ID
+func arbitrationInstructionStateID(escrowContract framework.Address, escrowID string) []byte {
+	return []byte(ARBITRATION_INSTRUCTION_PREFIX + hex.EncodeToString(escrowContract[:]) + ":" + escrowID)
+}
+
+// encodeArbitrationInstruction 编码裁决指令
+func encodeArbitrationInstruction(instr ArbitrationInstruction) []byte {
+	out := make([]byte, 68)
+	copy(out[0:32], instr.DisputeID)
+	copy(out[32:64], instr.EscrowID)
+	putUint32(out[64:68], instr.Ruling)
+	return out
+}
+
+// ================================================================================================
+// 宿主存储
+// ================================================================================================
+
+// hostCourtStore 基于宿主状态的法庭存储（main.go 的 court 使用）
+type hostCourtStore struct{}
+
+// Load 从链上读取状态，状态不存在（ERROR_NOT_FOUND）时返回 (nil, 0, nil)
+//
+// 其他宿主错误原样返回：当作不存在会让随后的写入以版本 1 覆盖已有的质押与争议记录
+func (hostCourtStore) Load(key string) ([]byte, uint64, error) {
+	value, version, err := framework.GetStateFromChain([]byte(key))
+	if err != nil {
+		if ce, ok := err.(*framework.ContractError); ok && ce.Code == framework.ERROR_NOT_FOUND {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	return value, version, nil
+}
+
+// Save 追加状态输出
+func (hostCourtStore) Save(key string, version uint64, value []byte) error {
+	_, err := framework.AppendStateOutputSimple([]byte(key), version, value, nil)
+	return err
+}
+
+// ================================================================================================
+// 状态编码/解码
+// ================================================================================================
+//
+// 链上读取会去除尾部零字节，读取时按定长补齐（见 Court.get）。
+//
+//	法庭参数：owner(20) + tokenID(32) + minStake(8) + commitPeriod(8) + revealPeriod(8) +
+//	          incoherentBP(8) + nonRevealBP(8) + courtFeeBP(8) = 100字节
+//	陪审员：registered(1) + assigned(8) = 9字节
+//	争议头部：disputeID(32) + disputer(20) + escrowContract(20) + escrowID(32) + evidence(32) +
+//	          panelSize(8) + fee(8) + status(16) + createdHeight(8) + createdAt(8) + commitDeadline(8) +
+//	          revealDeadline(8) + ruling(4) + votesBuyer(8) + votesSeller(8) + courtFee(8) + seatCount(8) = 252字节
+//	席位：juror(20) + commit(32) + flags(1) + choice(4) + penalty(8) + reward(8) = 73字节
+
+const (
+	courtConfigKey    = "court_config"
+	jurorCountKey     = "juror_count"
+	courtConfigSize   = 100
+	jurorRecordSize   = 9
+	disputeHeaderSize = 252
+	seatSize          = 73
+)
+
+// jurorRecord 陪审员登记记录（质押记在台账中）
+type jurorRecord struct {
+	Registered bool
+	Assigned   uint64 // 所在未裁决争议的数量
+}
+
+func jurorKey(juror framework.Address) string { return "juror:" + hex.EncodeToString(juror[:]) }
+func jurorAtKey(index uint64) string          { return "juror_at:" + strconv.FormatUint(index, 10) }
+func disputeKey(disputeID string) string      { return "dispute:" + disputeID }
+
+// escrowDisputeKey 托管交易最近一次争议的ID：escrow_dispute:{hex(escrow_contract)}:{escrow_id}
+func escrowDisputeKey(escrowContract framework.Address, escrowID string) string {
+	return "escrow_dispute:" + hex.EncodeToString(escrowContract[:]) + ":" + escrowID
+}
+
+// escrowPartiesKey 托管交易买卖双方：escrow_parties:{hex(escrow_contract)}:{escrow_id}
+func escrowPartiesKey(escrowContract framework.Address, escrowID string) string {
+	return "escrow_parties:" + hex.EncodeToString(escrowContract[:]) + ":" + escrowID
+}
+
+// get 读取定长状态并补齐尾部零字节，状态不存在时返回 nil
+func (c *Court) get(key string, size int) ([]byte, error) {
+	value, _, err := c.store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if len(value) < size {
+		padded := make([]byte, size)
+		copy(padded, value)
+		value = padded
+	}
+	return value, nil
+}
+
+// put 以递增版本号写入状态
+func (c *Court) put(key string, value []byte) error {
+	_, version, err := c.store.Load(key)
+	if err != nil {
+		return err
+	}
+	return c.store.Save(key, version+1, value)
+}
+
+func (c *Court) jurorCount() (uint64, error) {
+	data, err := c.get(jurorCountKey, 8)
+	if err != nil || data == nil {
+		return 0, err
+	}
+	return framework.BytesToUint64(data), nil
+}
+
+func (c *Court) juror(juror framework.Address) (jurorRecord, error) {
+	data, err := c.get(jurorKey(juror), jurorRecordSize)
+	if err != nil || data == nil {
+		return jurorRecord{}, err
+	}
+	return jurorRecord{Registered: data[0] == 1, Assigned: framework.BytesToUint64(data[1:9])}, nil
+}
+
+func (c *Court) putJuror(juror framework.Address, rec jurorRecord) error {
+	data := make([]byte, jurorRecordSize)
+	if rec.Registered {
+		data[0] = 1
+	}
+	copy(data[1:9], framework.Uint64ToBytes(rec.Assigned))
+	return c.put(jurorKey(juror), data)
+}
+
+// dispute 读取争议，不存在时返回 errNotFound
+func (c *Court) dispute(disputeID string) (*Dispute, error) {
+	if disputeID == "" {
+		return nil, errInvalidParams
+	}
+	data, err := c.get(disputeKey(disputeID), disputeHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errNotFound
+	}
+	d := decodeDispute(data)
+	if d == nil {
+		return nil, errNotFound
+	}
+	return d, nil
+}
+
+func (c *Court) saveDispute(d *Dispute) error {
+	return c.put(disputeKey(d.DisputeID), encodeDispute(d))
+}
+
+func encodeCourtConfig(cfg CourtConfig) []byte {
+	out := make([]byte, courtConfigSize)
+	copy(out[0:20], cfg.Owner[:])
+	copy(out[20:52], cfg.TokenID)
+	copy(out[52:60], framework.Uint64ToBytes(cfg.MinStake))
+	copy(out[60:68], framework.Uint64ToBytes(cfg.CommitPeriod))
+	copy(out[68:76], framework.Uint64ToBytes(cfg.RevealPeriod))
+	copy(out[76:84], framework.Uint64ToBytes(cfg.IncoherentPenaltyBP))
+	copy(out[84:92], framework.Uint64ToBytes(cfg.NonRevealPenaltyBP))
+	copy(out[92:100], framework.Uint64ToBytes(cfg.CourtFeeBP))
+	return out
+}
+
+func decodeCourtConfig(data []byte) CourtConfig {
+	cfg := CourtConfig{
+		TokenID:             framework.TokenID(getString(data[20:52])),
+		MinStake:            framework.BytesToUint64(data[52:60]),
+		CommitPeriod:        framework.BytesToUint64(data[60:68]),
+		RevealPeriod:        framework.BytesToUint64(data[68:76]),
+		IncoherentPenaltyBP: framework.BytesToUint64(data[76:84]),
+		NonRevealPenaltyBP:  framework.BytesToUint64(data[84:92]),
+		CourtFeeBP:          framework.BytesToUint64(data[92:100]),
+	}
+	copy(cfg.Owner[:], data[0:20])
+	return cfg
+}
+
+// encodeDispute 编码争议
+func encodeDispute(d *Dispute) []byte {
+	out := make([]byte, disputeHeaderSize+seatSize*len(d.Seats))
+	copy(out[0:32], d.DisputeID)
+	copy(out[32:52], d.Disputer[:])
+	copy(out[52:72], d.EscrowContract[:])
+	copy(out[72:104], d.EscrowID)
+	copy(out[104:136], d.EvidenceHash[:])
+	copy(out[136:144], framework.Uint64ToBytes(d.PanelSize))
+	copy(out[144:152], framework.Uint64ToBytes(d.Fee))
+	copy(out[152:168], d.Status)
+	copy(out[168:176], framework.Uint64ToBytes(d.CreatedHeight))
+	copy(out[176:184], framework.Uint64ToBytes(d.CreatedAt))
+	copy(out[184:192], framework.Uint64ToBytes(d.CommitDeadline))
+	copy(out[192:200], framework.Uint64ToBytes(d.RevealDeadline))
+	putUint32(out[200:204], d.Ruling)
+	copy(out[204:212], framework.Uint64ToBytes(d.VotesBuyer))
+	copy(out[212:220], framework.Uint64ToBytes(d.VotesSeller))
+	copy(out[220:228], framework.Uint64ToBytes(d.CourtFee))
+	copy(out[228:236], framework.Uint64ToBytes(uint64(len(d.Seats))))
+	// 236..252 预留
+
+	for i, s := range d.Seats {
+		off := disputeHeaderSize + i*seatSize
+		copy(out[off:off+20], s.Juror[:])
+		copy(out[off+20:off+52], s.Commit[:])
+		if s.Committed {
+			out[off+52] |= 1
+		}
+		if s.Revealed {
+			out[off+52] |= 2
+		}
+		putUint32(out[off+53:off+57], s.Choice)
+		copy(out[off+57:off+65], framework.Uint64ToBytes(s.Penalty))
+		copy(out[off+65:off+73], framework.Uint64ToBytes(s.Reward))
+	}
+	return out
+}
+
+// decodeDispute 解码争议，数据无效时返回 nil
+func decodeDispute(data []byte) *Dispute {
+	if len(data) < disputeHeaderSize {
+		return nil
+	}
+	n := framework.BytesToUint64(data[228:236])
+	if n > MAX_PANEL_SIZE {
+		return nil
+	}
+	if size := disputeHeaderSize + int(n)*seatSize; len(data) < size {
+		padded := make([]byte, size)
+		copy(padded, data)
+		data = padded
+	}
+
+	d := &Dispute{
+		DisputeID:      getString(data[0:32]),
+		EscrowID:       getString(data[72:104]),
+		PanelSize:      framework.BytesToUint64(data[136:144]),
+		Fee:            framework.BytesToUint64(data[144:152]),
+		Status:         getString(data[152:168]),
+		CreatedHeight:  framework.BytesToUint64(data[168:176]),
+		CreatedAt:      framework.BytesToUint64(data[176:184]),
+		CommitDeadline: framework.BytesToUint64(data[184:192]),
+		RevealDeadline: framework.BytesToUint64(data[192:200]),
+		Ruling:         getUint32(data[200:204]),
+		VotesBuyer:     framework.BytesToUint64(data[204:212]),
+		VotesSeller:    framework.BytesToUint64(data[212:220]),
+		CourtFee:       framework.BytesToUint64(data[220:228]),
+	}
+	copy(d.Disputer[:], data[32:52])
+	copy(d.EscrowContract[:], data[52:72])
+	copy(d.EvidenceHash[:], data[104:136])
+
+	if n > 0 {
+		d.Seats = make([]Seat, n)
+	}
+	for i := range d.Seats {
+		off := disputeHeaderSize + i*seatSize
+		s := &d.Seats[i]
+		copy(s.Juror[:], data[off:off+20])
+		copy(s.Commit[:], data[off+20:off+52])
+		s.Committed = data[off+52]&1 != 0
+		s.Revealed = data[off+52]&2 != 0
+		s.Choice = getUint32(data[off+53 : off+57])
+		s.Penalty = framework.BytesToUint64(data[off+57 : off+65])
+		s.Reward = framework.BytesToUint64(data[off+65 : off+73])
+	}
+	return d
+}
+
+// parseHash32 解析32字节十六进制哈希（可带 0x 前缀）
+func parseHash32(s string) ([32]byte, bool) {
+	var out [32]byte
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return out, false
+	}
+	copy(out[:], b)
+	return out, true
+}
+
+// getString 读取定长字符串字段，去除尾部0x00
+func getString(b []byte) string {
+	for i := 0; i < len(b); i++ {
+		if b[i] == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+// putUint32 写入4字节大端序数值
+func putUint32(dst []byte, v uint32) {
+	dst[0], dst[1], dst[2], dst[3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+}
+
+// getUint32 读取4字节大端序数值
+func getUint32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
@@ -0,0 +1,608 @@
+//go:build !tinygo && !(js && wasm)
+
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/subaccount"
+)
+
+const (
+	testNow    = uint64(1736200000)
+	testHeight = uint64(100)
+	testToken  = framework.TokenID("USDT")
+)
+
+var (
+	testOwner    = framework.Address{0xF0}
+	testDisputer = framework.Address{0xD0}
+	testSeller   = framework.Address{0xD1}
+	testEscrow   = framework.Address{0xE0}
+	testSeedHash = []byte("block_hash_at_101")
+)
+
+var testConfig = CourtConfig{
+	Owner:               testOwner,
+	TokenID:             testToken,
+	MinStake:            1000,
+	CommitPeriod:        100,
+	RevealPeriod:        100,
+	IncoherentPenaltyBP: 1000, // 10%
+	NonRevealPenaltyBP:  2000, // 20%
+	CourtFeeBP:          500,  // 5%
+}
+
+// mockEscrow 记录收到的裁决指令的托管合约
+type mockEscrow struct {
+	received []ArbitrationInstruction
+	fail     error
+}
+
+func (m *mockEscrow) Arbitrate(instr ArbitrationInstruction) error {
+	if m.fail != nil {
+		return m.fail
+	}
+	m.received = append(m.received, instr)
+	return nil
+}
+
+// stateRecorder 记录 append_state_output 的状态ID，可注入失败
+type stateRecorder struct {
+	stateIDs []string
+	fail     error
+}
+
+func (r *stateRecorder) BeforeHostCall(call framework.HostCall) error {
+	if call.Name != framework.HOST_CALL_APPEND_STATE_OUTPUT {
+		return nil
+	}
+	if r.fail != nil {
+		return r.fail
+	}
+	r.stateIDs = append(r.stateIDs, string(call.StateID))
+	return nil
+}
+
+// newTestCourt 创建法庭并按 stakes 登记陪审员（地址为 {1}, {2}, ...）
+func newTestCourt(t *testing.T, stakes ...uint64) (*Court, []framework.Address) {
+	t.Helper()
+	c := newCourt(subaccount.NewMemoryStore())
+	if err := c.initialize(testConfig); err != nil {
+		t.Fatalf("initialize() error = %v", err)
+	}
+	jurors := make([]framework.Address, len(stakes))
+	for i, amount := range stakes {
+		jurors[i] = framework.Address{byte(i + 1)}
+		if _, err := c.stake(jurors[i], amount); err != nil {
+			t.Fatalf("stake(%d) error = %v", i, err)
+		}
+	}
+	return c, jurors
+}
+
+// registerTestEscrow 以 testEscrow 登记托管交易，买方为争议方 testDisputer、卖方为 testSeller
+func registerTestEscrow(t *testing.T, c *Court, escrowID string) {
+	t.Helper()
+	if err := c.registerEscrow(testEscrow, escrowID, EscrowParties{Buyer: testDisputer, Seller: testSeller}); err != nil {
+		t.Fatalf("registerEscrow(%s) error = %v", escrowID, err)
+	}
+}
+
+// openDispute 创建争议并抽选陪审团
+func openDispute(t *testing.T, c *Court, id string, panelSize, fee uint64) *Dispute {
+	t.Helper()
+	registerTestEscrow(t, c, "order_"+id)
+	if err := c.createDispute(&Dispute{
+		DisputeID:      id,
+		Disputer:       testDisputer,
+		EscrowContract: testEscrow,
+		EscrowID:       "order_" + id,
+		PanelSize:      panelSize,
+		Fee:            fee,
+		CreatedHeight:  testHeight,
+		CreatedAt:      testNow,
+	}); err != nil {
+		t.Fatalf("createDispute() error = %v", err)
+	}
+	d, err := c.drawPanel(id, testHeight+2, testSeedHash, testNow)
+	if err != nil {
+		t.Fatalf("drawPanel() error = %v", err)
+	}
+	return d
+}
+
+// vote 陪审员在提交期提交承诺，reveal 为 true 时在揭示期揭示
+func vote(t *testing.T, c *Court, d *Dispute, juror framework.Address, choice uint32, reveal bool) {
+	t.Helper()
+	salt := []byte("salt-" + string(juror[:1]))
+	if _, err := c.commitVote(d.DisputeID, juror, voteCommitment(d.DisputeID, juror, choice, salt), testNow); err != nil {
+		t.Fatalf("commitVote() error = %v", err)
+	}
+	if !reveal {
+		return
+	}
+	if _, err := c.revealVote(d.DisputeID, juror, choice, salt, d.CommitDeadline); err != nil {
+		t.Fatalf("revealVote() error = %v", err)
+	}
+}
+
+// totalLiabilities 三个命名空间的总负债之和（应等于法庭持有的代币）
+func totalLiabilities(t *testing.T, c *Court) uint64 {
+	t.Helper()
+	var total uint64
+	for _, ns := range []string{NS_JUROR_STAKES, NS_DISPUTE_FEES, NS_COURT_FEES} {
+		v, err := c.ledger.TotalLiabilities(ns, testToken)
+		if err != nil {
+			t.Fatalf("TotalLiabilities(%s) error = %v", ns, err)
+		}
+		total += uint64(v)
+	}
+	return total
+}
+
+func mustStake(t *testing.T, c *Court, juror framework.Address) uint64 {
+	t.Helper()
+	v, err := c.stakeOf(juror, testToken)
+	if err != nil {
+		t.Fatalf("stakeOf() error = %v", err)
+	}
+	return v
+}
+
+// TestSelectPanelDeterministicWeighted 测试抽选的确定性、不放回与按质押加权
+func TestSelectPanelDeterministicWeighted(t *testing.T) {
+	candidates := []candidate{
+		{Juror: framework.Address{1}, Weight: 9000},
+		{Juror: framework.Address{2}, Weight: 1000},
+		{Juror: framework.Address{3}, Weight: 0},
+	}
+
+	first, err := selectPanel(candidates, []byte("seed"), 2)
+	if err != nil {
+		t.Fatalf("selectPanel() error = %v", err)
+	}
+	again, _ := selectPanel(candidates, []byte("seed"), 2)
+	if len(first) != 2 || first[0] == first[1] || first[0] != again[0] || first[1] != again[1] {
+		t.Fatalf("selectPanel() = %v then %v, want the same two distinct seats", first, again)
+	}
+	if _, err := selectPanel(candidates, []byte("seed"), 3); err != errNotEnoughJurors {
+		t.Errorf("selectPanel(zero-weight candidate needed) error = %v, want errNotEnoughJurors", err)
+	}
+
+	heavy := 0
+	for i := 0; i < 1000; i++ {
+		picked, err := selectPanel(candidates, panelSeed(testSeedHash, "dispute_"+strconv.Itoa(i)), 1)
+		if err != nil {
+			t.Fatalf("selectPanel() error = %v", err)
+		}
+		if picked[0] == 0 {
+			heavy++
+		}
+	}
+	if heavy < 850 || heavy > 950 {
+		t.Errorf("heavy juror picked %d/1000 times, want about 900", heavy)
+	}
+}
+
+// TestDrawPanelEligibility 测试抽选时机、最低质押与争议方排除
+func TestDrawPanelEligibility(t *testing.T) {
+	c, jurors := newTestCourt(t, 5000, 5000, 500)
+	// 争议方本人也是陪审员，不得入选
+	if _, err := c.stake(testDisputer, 5000); err != nil {
+		t.Fatalf("stake(disputer) error = %v", err)
+	}
+	registerTestEscrow(t, c, "order_1")
+	registerTestEscrow(t, c, "order_2")
+	if err := c.createDispute(&Dispute{
+		DisputeID: "d1", Disputer: testDisputer, EscrowContract: testEscrow, EscrowID: "order_1",
+		PanelSize: 2, Fee: 300, CreatedHeight: testHeight, CreatedAt: testNow,
+	}); err != nil {
+		t.Fatalf("createDispute() error = %v", err)
+	}
+
+	if _, err := c.drawPanel("d1", testHeight+1, testSeedHash, testNow); err != errSeedNotAvailable {
+		t.Fatalf("drawPanel() before seed block error = %v, want errSeedNotAvailable", err)
+	}
+	d, err := c.drawPanel("d1", testHeight+2, testSeedHash, testNow)
+	if err != nil {
+		t.Fatalf("drawPanel() error = %v", err)
+	}
+	// 仅 jurors[0]、jurors[1] 合格（jurors[2] 低于最低质押）
+	got := map[framework.Address]bool{d.Seats[0].Juror: true, d.Seats[1].Juror: true}
+	if !got[jurors[0]] || !got[jurors[1]] {
+		t.Errorf("panel = %v, want jurors 1 and 2", got)
+	}
+	if d.CommitDeadline != testNow+100 || d.RevealDeadline != testNow+200 {
+		t.Errorf("deadlines = %d/%d", d.CommitDeadline, d.RevealDeadline)
+	}
+	if _, err := c.drawPanel("d1", testHeight+3, testSeedHash, testNow); err != errInvalidStatus {
+		t.Errorf("second drawPanel() error = %v, want errInvalidStatus", err)
+	}
+
+	if err := c.createDispute(&Dispute{
+		DisputeID: "d2", Disputer: testDisputer, EscrowContract: testEscrow, EscrowID: "order_2",
+		PanelSize: 3, Fee: 300, CreatedHeight: testHeight, CreatedAt: testNow,
+	}); err != nil {
+		t.Fatalf("createDispute(d2) error = %v", err)
+	}
+	if _, err := c.drawPanel("d2", testHeight+2, testSeedHash, testNow); err != errNotEnoughJurors {
+		t.Errorf("drawPanel(panel larger than eligible set) error = %v, want errNotEnoughJurors", err)
+	}
+}
+
+// TestCommitRevealGuards 测试承诺绑定、阶段限制与陪审团成员校验
+func TestCommitRevealGuards(t *testing.T) {
+	c, _ := newTestCourt(t, 5000, 5000, 5000)
+	d := openDispute(t, c, "d1", 3, 300)
+	juror := d.Seats[0].Juror
+
+	if _, err := c.commitVote("d1", framework.Address{0x99}, [32]byte{1}, testNow); err != errNotPanelist {
+		t.Errorf("commitVote(outsider) error = %v, want errNotPanelist", err)
+	}
+	if _, err := c.commitVote("d1", juror, [32]byte{1}, d.CommitDeadline); err != errPhaseClosed {
+		t.Errorf("commitVote(after deadline) error = %v, want errPhaseClosed", err)
+	}
+
+	salt := []byte("secret")
+	commitment := voteCommitment("d1", juror, RULING_SELLER, salt)
+	if _, err := c.commitVote("d1", juror, commitment, testNow); err != nil {
+		t.Fatalf("commitVote() error = %v", err)
+	}
+	if _, err := c.commitVote("d1", juror, commitment, testNow); err != errAlreadyVoted {
+		t.Errorf("second commitVote() error = %v, want errAlreadyVoted", err)
+	}
+
+	// 揭示期开始前不能揭示（防止其他陪审员抄票）
+	if _, err := c.revealVote("d1", juror, RULING_SELLER, salt, testNow); err != errPhaseClosed {
+		t.Errorf("revealVote(during commit) error = %v, want errPhaseClosed", err)
+	}
+	if _, err := c.revealVote("d1", juror, RULING_BUYER, salt, d.CommitDeadline); err != errCommitMismatch {
+		t.Errorf("revealVote(other choice) error = %v, want errCommitMismatch", err)
+	}
+	// 复制他人的承诺无法揭示：承诺绑定陪审员地址
+	other := d.Seats[1].Juror
+	if _, err := c.commitVote("d1", other, commitment, testNow); err != nil {
+		t.Fatalf("commitVote(copied) error = %v", err)
+	}
+	if _, err := c.revealVote("d1", other, RULING_SELLER, salt, d.CommitDeadline); err != errCommitMismatch {
+		t.Errorf("revealVote(copied commitment) error = %v, want errCommitMismatch", err)
+	}
+	if _, err := c.revealVote("d1", juror, RULING_SELLER, salt, d.CommitDeadline); err != nil {
+		t.Fatalf("revealVote() error = %v", err)
+	}
+	if _, err := c.resolve("d1", d.RevealDeadline-1, &mockEscrow{}); err != errPhaseClosed {
+		t.Errorf("resolve(before reveal deadline) error = %v, want errPhaseClosed", err)
+	}
+}
+
+// TestNonRevealSlashing 测试未揭示与不一致陪审员被罚没，罚没与仲裁费分给一致陪审员
+func TestNonRevealSlashing(t *testing.T) {
+	c, _ := newTestCourt(t, 10000, 10000, 10000, 10000)
+	d := openDispute(t, c, "d1", 4, 400)
+	before := totalLiabilities(t, c)
+
+	seats := d.Seats
+	vote(t, c, d, seats[0].Juror, RULING_SELLER, true)
+	vote(t, c, d, seats[1].Juror, RULING_SELLER, true)
+	vote(t, c, d, seats[2].Juror, RULING_BUYER, true)
+	vote(t, c, d, seats[3].Juror, RULING_SELLER, false) // 提交但未揭示
+
+	// 揭示期结束前仍在陪审团中，质押锁定
+	if _, err := c.unstake(seats[3].Juror, 1); err != errJurorAssigned {
+		t.Fatalf("unstake(assigned) error = %v, want errJurorAssigned", err)
+	}
+
+	escrow := &mockEscrow{}
+	d, err := c.resolve("d1", d.RevealDeadline, escrow)
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if d.Ruling != RULING_SELLER || d.VotesSeller != 2 || d.VotesBuyer != 1 || d.Status != DISPUTE_STATUS_RESOLVED {
+		t.Fatalf("dispute = ruling %d, votes %d/%d, status %s", d.Ruling, d.VotesBuyer, d.VotesSeller, d.Status)
+	}
+
+	// 罚没：未揭示 20% = 2000，不一致 10% = 1000；奖励池 = 2000 + 1000 + 400 = 3400
+	// 法庭手续费 5% = 170，其余 3230 由2名一致陪审员平分，各 1615
+	want := map[framework.Address]uint64{
+		seats[0].Juror: 11615,
+		seats[1].Juror: 11615,
+		seats[2].Juror: 9000,
+		seats[3].Juror: 8000,
+	}
+	for juror, stake := range want {
+		if got := mustStake(t, c, juror); got != stake {
+			t.Errorf("stake(%x) = %d, want %d", juror[:1], got, stake)
+		}
+	}
+	if fees, _ := c.courtFees(); fees != 170 || d.CourtFee != 170 {
+		t.Errorf("court fees = %d (dispute %d), want 170", fees, d.CourtFee)
+	}
+	if after := totalLiabilities(t, c); after != before {
+		t.Errorf("total liabilities = %d, want %d (resolve must not create or destroy funds)", after, before)
+	}
+
+	// 裁决后解除锁定
+	if _, err := c.unstake(seats[3].Juror, 8000); err != nil {
+		t.Errorf("unstake(after resolve) error = %v", err)
+	}
+	if len(escrow.received) != 1 {
+		t.Fatalf("escrow received %d instructions, want 1", len(escrow.received))
+	}
+}
+
+// TestTiedPanel 测试平票规则：裁决为 SPLIT，所有揭示者视为一致，仅未揭示者被罚没
+func TestTiedPanel(t *testing.T) {
+	c, _ := newTestCourt(t, 10000, 10000, 10000)
+	d := openDispute(t, c, "d1", 3, 300)
+	seats := d.Seats
+	vote(t, c, d, seats[0].Juror, RULING_BUYER, true)
+	vote(t, c, d, seats[1].Juror, RULING_SELLER, true)
+	// seats[2] 未提交
+
+	escrow := &mockEscrow{}
+	d, err := c.resolve("d1", d.RevealDeadline, escrow)
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if d.Ruling != RULING_SPLIT || escrow.received[0].Ruling != RULING_SPLIT {
+		t.Fatalf("ruling = %d (escrow %d), want RULING_SPLIT", d.Ruling, escrow.received[0].Ruling)
+	}
+	// 奖励池 = 2000 + 300 = 2300，法庭 115，其余 2185 两人平分各 1092，余数 1 归法庭
+	if got := mustStake(t, c, seats[0].Juror); got != 11092 {
+		t.Errorf("buyer voter stake = %d, want 11092", got)
+	}
+	if got := mustStake(t, c, seats[1].Juror); got != 11092 {
+		t.Errorf("seller voter stake = %d, want 11092", got)
+	}
+	if got := mustStake(t, c, seats[2].Juror); got != 8000 {
+		t.Errorf("non-revealer stake = %d, want 8000", got)
+	}
+	if d.CourtFee != 116 {
+		t.Errorf("court fee = %d, want 116", d.CourtFee)
+	}
+
+	// 无人揭示同样是平票：奖励池全部归法庭
+	d2 := openDispute(t, c, "d2", 2, 100)
+	d2, err = c.resolve("d2", d2.RevealDeadline, escrow)
+	if err != nil {
+		t.Fatalf("resolve(no reveals) error = %v", err)
+	}
+	var penalties uint64
+	for _, s := range d2.Seats {
+		penalties += s.Penalty
+		if s.Reward != 0 {
+			t.Errorf("reward with no reveals = %d, want 0", s.Reward)
+		}
+	}
+	if d2.Ruling != RULING_SPLIT || d2.CourtFee != penalties+100 {
+		t.Errorf("no-reveal dispute = ruling %d, court fee %d, want SPLIT and %d", d2.Ruling, d2.CourtFee, penalties+100)
+	}
+}
+
+// TestResolveInstructsEscrow 测试托管合约收到裁决指令，指令写入失败时裁决不生效
+func TestResolveInstructsEscrow(t *testing.T) {
+	c, _ := newTestCourt(t, 10000, 10000, 10000)
+	d := openDispute(t, c, "d1", 3, 300)
+	for _, s := range d.Seats {
+		vote(t, c, d, s.Juror, RULING_BUYER, true)
+	}
+
+	// 宿主写入失败：裁决整体失败，质押与争议状态不变
+	framework.ResetStagedWrites()
+	failing := &stateRecorder{fail: errors.New("host rejected state output")}
+	restore := framework.SetHostInterceptor(failing)
+	if _, err := c.resolve("d1", d.RevealDeadline, stateOutboxTarget{}); err == nil {
+		t.Fatal("resolve() with failing host should fail")
+	}
+	restore()
+	if got, _ := c.dispute("d1"); got.Status != DISPUTE_STATUS_PANEL_DRAWN {
+		t.Errorf("status after failed resolve = %s, want PANEL_DRAWN", got.Status)
+	}
+	if fees, _ := c.courtFees(); fees != 0 {
+		t.Errorf("court fees after failed resolve = %d, want 0", fees)
+	}
+
+	// 宿主接受写入：托管合约的指令状态被写出
+	framework.ResetStagedWrites()
+	recorder := &stateRecorder{}
+	restore = framework.SetHostInterceptor(recorder)
+	defer restore()
+	if _, err := c.resolve("d1", d.RevealDeadline, stateOutboxTarget{}); err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	wantID := string(arbitrationInstructionStateID(testEscrow, "order_d1"))
+	if !strings.HasPrefix(wantID, ARBITRATION_INSTRUCTION_PREFIX) || len(recorder.stateIDs) != 1 || recorder.stateIDs[0] != wantID {
+		t.Fatalf("host state outputs = %v, want [%s]", recorder.stateIDs, wantID)
+	}
+	if staged := framework.StagedStateWrites(); len(staged) != 1 || staged[0] != wantID {
+		t.Errorf("StagedStateWrites() = %v, want [%s]", staged, wantID)
+	}
+
+	instr := encodeArbitrationInstruction(ArbitrationInstruction{DisputeID: "d1", EscrowContract: testEscrow, EscrowID: "order_d1", Ruling: RULING_BUYER})
+	if getString(instr[0:32]) != "d1" || getString(instr[32:64]) != "order_d1" || getUint32(instr[64:68]) != RULING_BUYER {
+		t.Errorf("encoded instruction = %x", instr)
+	}
+
+	// 已裁决的争议不能再次裁决
+	if _, err := c.resolve("d1", d.RevealDeadline, &mockEscrow{}); err != errInvalidStatus {
+		t.Errorf("second resolve() error = %v, want errInvalidStatus", err)
+	}
+}
+
+// TestOneOpenDisputePerEscrow 测试同一托管交易同时只能有一个未裁决争议，裁决后可再次争议，
+// 再次裁决的指令以递增版本号写入
+func TestOneOpenDisputePerEscrow(t *testing.T) {
+	host := framework.NewMockHost()
+	t.Cleanup(framework.InstallMockHost(host))
+	c, _ := newTestCourt(t, 10000, 10000, 10000)
+	newDispute := func(id string) *Dispute {
+		return &Dispute{
+			DisputeID: id, Disputer: testDisputer, EscrowContract: testEscrow, EscrowID: "order_shared",
+			PanelSize: 3, Fee: 300, CreatedHeight: testHeight, CreatedAt: testNow,
+		}
+	}
+	resolveWithOutbox := func(d *Dispute) uint64 {
+		t.Helper()
+		for _, s := range d.Seats {
+			vote(t, c, d, s.Juror, RULING_SELLER, true)
+		}
+		res := host.Invoke(testOwner, nil, func() uint32 {
+			if _, err := c.resolve(d.DisputeID, d.RevealDeadline, stateOutboxTarget{}); err != nil {
+				t.Errorf("resolve(%s) error = %v", d.DisputeID, err)
+				return framework.ERROR_EXECUTION_FAILED
+			}
+			return framework.SUCCESS
+		})
+		if res.Code != framework.SUCCESS {
+			t.Fatalf("resolve(%s) code = %d", d.DisputeID, res.Code)
+		}
+		_, version, _ := host.State(string(arbitrationInstructionStateID(testEscrow, "order_shared")))
+		return version
+	}
+
+	registerTestEscrow(t, c, "order_shared")
+	if err := c.createDispute(newDispute("first")); err != nil {
+		t.Fatalf("createDispute(first) error = %v", err)
+	}
+	if err := c.createDispute(newDispute("second")); err != errAlreadyExists {
+		t.Fatalf("createDispute(second) while first is open error = %v, want errAlreadyExists", err)
+	}
+
+	first, err := c.drawPanel("first", testHeight+2, testSeedHash, testNow)
+	if err != nil {
+		t.Fatalf("drawPanel(first) error = %v", err)
+	}
+	if v := resolveWithOutbox(first); v != 1 {
+		t.Errorf("instruction version after first ruling = %d, want 1", v)
+	}
+
+	// 裁决后再次争议：指令以版本 2 覆盖写入
+	if err := c.createDispute(newDispute("second")); err != nil {
+		t.Fatalf("createDispute(second) after ruling error = %v", err)
+	}
+	second, err := c.drawPanel("second", testHeight+2, testSeedHash, testNow)
+	if err != nil {
+		t.Fatalf("drawPanel(second) error = %v", err)
+	}
+	if v := resolveWithOutbox(second); v != 2 {
+		t.Errorf("instruction version after second ruling = %d, want 2", v)
+	}
+	value, _, _ := host.State(string(arbitrationInstructionStateID(testEscrow, "order_shared")))
+	if getString(value[0:32]) != "second" {
+		t.Errorf("instruction dispute_id = %q, want second", getString(value[0:32]))
+	}
+}
+
+// TestCreateDisputeRequiresParty 测试只有托管合约登记的买方或卖方可以发起争议
+func TestCreateDisputeRequiresParty(t *testing.T) {
+	c, _ := newTestCourt(t, 10000, 10000, 10000)
+	newDispute := func(id string, disputer framework.Address) *Dispute {
+		return &Dispute{
+			DisputeID: id, Disputer: disputer, EscrowContract: testEscrow, EscrowID: "order_parties",
+			PanelSize: 3, Fee: 300, CreatedHeight: testHeight, CreatedAt: testNow,
+		}
+	}
+
+	if err := c.createDispute(newDispute("d1", testDisputer)); err != errNotFound {
+		t.Fatalf("createDispute(unregistered escrow) error = %v, want errNotFound", err)
+	}
+	if err := c.registerEscrow(testEscrow, "order_parties", EscrowParties{Buyer: testDisputer, Seller: testDisputer}); err != errInvalidParams {
+		t.Fatalf("registerEscrow(buyer == seller) error = %v, want errInvalidParams", err)
+	}
+	registerTestEscrow(t, c, "order_parties")
+	if err := c.registerEscrow(testEscrow, "order_parties", EscrowParties{Buyer: testOwner, Seller: testSeller}); err != errAlreadyExists {
+		t.Fatalf("second registerEscrow() error = %v, want errAlreadyExists", err)
+	}
+
+	// 其他托管合约登记的同名托管交易不影响本托管交易
+	if err := c.registerEscrow(framework.Address{0xE1}, "order_parties", EscrowParties{Buyer: testOwner, Seller: testSeller}); err != nil {
+		t.Fatalf("registerEscrow(other escrow contract) error = %v", err)
+	}
+	if err := c.createDispute(newDispute("d1", testOwner)); err != errUnauthorized {
+		t.Fatalf("createDispute(non-party) error = %v, want errUnauthorized", err)
+	}
+	if err := c.createDispute(newDispute("d1", testSeller)); err != nil {
+		t.Fatalf("createDispute(seller) error = %v", err)
+	}
+}
+
+// TestDisputeEncodingRoundTrip 测试争议编码在去除尾部零字节后仍可解码
+func TestDisputeEncodingRoundTrip(t *testing.T) {
+	d := &Dispute{
+		DisputeID: "d1", Disputer: testDisputer, EscrowContract: testEscrow, EscrowID: "order_1",
+		EvidenceHash: [32]byte{0xAB}, PanelSize: 2, Fee: 300, Status: DISPUTE_STATUS_PANEL_DRAWN,
+		CreatedHeight: testHeight, CommitDeadline: testNow + 100, RevealDeadline: testNow + 200,
+		Seats: []Seat{
+			{Juror: framework.Address{1}, Commit: [32]byte{7}, Committed: true, Revealed: true, Choice: RULING_SELLER, Reward: 5},
+			{Juror: framework.Address{2}},
+		},
+	}
+	data := encodeDispute(d)
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	got := decodeDispute(data)
+	if got == nil || got.DisputeID != "d1" || got.EscrowID != "order_1" || got.EvidenceHash != d.EvidenceHash ||
+		len(got.Seats) != 2 || got.Seats[0] != d.Seats[0] || got.Seats[1] != d.Seats[1] {
+		t.Fatalf("decodeDispute() = %+v, want %+v", got, d)
+	}
+}
+
+// failStateReads 使状态ID包含 substr 的链上状态读取失败（模拟分配失败、值过大等宿主错误）
+type failStateReads struct{ substr string }
+
+func (f failStateReads) BeforeHostCall(call framework.HostCall) error {
+	if call.Name == framework.HOST_CALL_STATE_GET_FROM_CHAIN && strings.Contains(string(call.StateID), f.substr) {
+		return framework.NewContractError(framework.ERROR_EXECUTION_FAILED, "injected state read failure")
+	}
+	return nil
+}
+
+// TestHostCourtStorePropagatesHostFailure 测试宿主读取失败时返回错误，不当作不存在而覆盖已有质押
+func TestHostCourtStorePropagatesHostFailure(t *testing.T) {
+	host := framework.NewMockHost()
+	t.Cleanup(framework.InstallMockHost(host))
+	c := newCourt(hostCourtStore{})
+	juror := framework.Address{0x01}
+
+	res := host.Invoke(testOwner, nil, func() uint32 {
+		if err := c.initialize(testConfig); err != nil {
+			t.Errorf("initialize() error = %v", err)
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		if _, err := c.stake(juror, 1500); err != nil {
+			t.Errorf("stake() error = %v", err)
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.SUCCESS
+	})
+	if res.Code != framework.SUCCESS {
+		t.Fatalf("setup code = %d", res.Code)
+	}
+
+	// 只让质押余额的读取失败：法庭参数可正常读取，旧实现会把余额当作 0 并以版本 1 覆盖
+	restore := framework.SetHostInterceptor(failStateReads{substr: NS_JUROR_STAKES})
+	var stakeErr error
+	res = host.Invoke(juror, nil, func() uint32 {
+		if _, stakeErr = c.stake(juror, 100); stakeErr != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		return framework.SUCCESS
+	})
+	restore()
+	if stakeErr == nil || len(res.Writes) != 0 {
+		t.Errorf("stake with failing host read = %v, writes %v, want error and no writes", stakeErr, res.Writes)
+	}
+
+	host.Invoke(juror, nil, func() uint32 {
+		if got, err := c.stakeOf(juror, testToken); err != nil || got != 1500 {
+			t.Errorf("stakeOf() after failure = %d, %v, want 1500", got, err)
+		}
+		return framework.SUCCESS
+	})
+}
@@ -0,0 +1,757 @@
+//go:build tinygo || (js && wasm)
+
+// Package main 提供陪审法庭（Juror Court）合约示例
+//
+// 📋 示例说明
+//
+// 本示例展示如何使用 WES Contract SDK Go 构建共享陪审员池的争议仲裁合约。
+// 托管、市场类合约不再各自指定单一仲裁人，而是以本合约地址作为仲裁人：
+// 陪审员质押代币获得抽选资格，争议发生时按质押加权抽选陪审团，
+// 陪审员以提交-揭示方式投票，裁决后不一致与未揭示陪审员的质押被罚没并分给一致陪审员。
+// 通过本示例，您可以学习：
+//   - 如何使用 framework/subaccount 台账管理质押与待结算资金
+//   - 如何以区块哈希为种子做确定性的加权抽选
+//   - 如何使用提交-揭示（commit-reveal）防止抄票
+//
+// 🎯 核心功能
+//
+//  1. StakeAsJuror / UnstakeJuror - 陪审员质押与取回
+//     - 陪审员在未裁决争议的陪审团中时质押锁定
+//
+//  2. CreateDispute - 创建争议（争议方预付仲裁费）
+//
+//  3. DrawPanel - 抽选陪审团（任何人可调用）
+//     - 种子为争议创建后下一区块的哈希，按质押加权、不放回抽选
+//
+//  4. CommitVote / RevealVote - 提交与揭示投票
+//
+//  5. ResolveDispute - 裁决（任何人可在揭示期结束后调用）
+//     - 计票、向托管合约发出裁决指令、罚没与再分配质押
+//
+//  6. WithdrawCourtFees - 所有者提取法庭手续费
+//
+// ⚠️ 注意：当前 SDK 尚未提供跨合约调用与随机数原语：
+//   - 裁决指令写入状态 arbitration_instruction:{hex(escrow_contract)}:{escrow_id}
+//     并发出 ArbitrationInstructed 事件，由托管合约集成方读取执行
+//   - 抽选种子取自区块哈希，出块者可在一定程度上影响结果
+//   法庭逻辑位于 court.go（纯函数，可在非WASM环境下单元测试）。
+//
+// 📚 相关文档
+//
+//   - [Subaccount 台账文档](../../../../framework/subaccount)
+//   - [托管示例](../../market/escrow/README.md)
+//   - [治理示例总览](../README.md)
+package main
+
+import (
+	"encoding/hex"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// JurorCourtContract 陪审法庭合约
+type JurorCourtContract struct {
+	framework.ContractBase
+}
+
+// court 基于宿主状态的法庭实例
+var court = newCourt(hostCourtStore{})
+
+// Initialize 初始化合约，调用者成为所有者
+//
+// 参数格式（JSON，均可选）:
+//
+//	{
+//	  "token": "USDT",                  // 质押与仲裁费代币（空表示原生币）
+//	  "min_stake": 1000,                // 参与抽选的最低质押（默认1000）
+//	  "commit_period": 86400,           // 抽选后提交期时长，秒（默认1天）
+//	  "reveal_period": 86400,           // 揭示期时长，秒（默认1天）
+//	  "incoherent_penalty_bp": 1000,    // 投票与裁决不一致的罚没比例（默认10%）
+//	  "non_reveal_penalty_bp": 2000,    // 未揭示的罚没比例（默认20%）
+//	  "court_fee_bp": 500               // 法庭手续费比例（默认5%）
+//	}
+//
+// 事件：
+//   - ContractInitialized
+//     {
+//       "contract": "JurorCourt",
+//       "owner": "<合约所有者地址>"
+//     }
+//
+//export Initialize
+func Initialize() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	caller := framework.GetCaller()
+	cfg := CourtConfig{
+		Owner:               caller,
+		TokenID:             framework.TokenID(params.ParseJSON("token")),
+		MinStake:            params.GetIntOr("min_stake", DEFAULT_MIN_STAKE),
+		CommitPeriod:        params.GetIntOr("commit_period", DEFAULT_COMMIT_PERIOD),
+		RevealPeriod:        params.GetIntOr("reveal_period", DEFAULT_REVEAL_PERIOD),
+		IncoherentPenaltyBP: params.GetIntOr("incoherent_penalty_bp", DEFAULT_INCOHERENT_PENALTY_BP),
+		NonRevealPenaltyBP:  params.GetIntOr("non_reveal_penalty_bp", DEFAULT_NON_REVEAL_PENALTY_BP),
+		CourtFeeBP:          params.GetIntOr("court_fee_bp", DEFAULT_COURT_FEE_BP),
+	}
+	if err := court.initialize(cfg); err != nil {
+		return courtErrorCode(err)
+	}
+
+	event := framework.NewEvent("ContractInitialized")
+	event.AddStringField("contract", "JurorCourt")
+	event.AddAddressField("owner", caller)
+	event.AddStringField("token", string(cfg.TokenID))
+	event.AddUint64Field("min_stake", cfg.MinStake)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// StakeAsJuror 质押成为陪审员（可多次追加）
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "amount": 5000    // 质押金额（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 质押成功，返回 {"juror": "...", "stake": 5000}
+//   - framework.ERROR_INVALID_PARAMS - 金额无效
+//   - framework.ERROR_INVALID_STATE - 合约未初始化或陪审员登记已满
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//
+// 事件：
+//   - JurorStaked {"juror": "...", "amount": 5000, "stake": 5000}
+//
+//export StakeAsJuror
+func StakeAsJuror() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	amount := params.ParseJSONInt("amount")
+	if amount == 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	cfg, err := court.config()
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤2：将质押转入合约地址
+	caller := framework.GetCaller()
+	if err := token.Transfer(caller, framework.GetContractAddress(), cfg.TokenID, framework.Amount(amount)); err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：记账
+	staked, err := court.stake(caller, amount)
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤4：发出事件并返回
+	event := framework.NewEvent("JurorStaked")
+	event.AddAddressField("juror", caller)
+	event.AddUint64Field("amount", amount)
+	event.AddUint64Field("stake", staked)
+	framework.EmitEvent(event)
+
+	return returnJuror(caller, cfg)
+}
+
+// UnstakeJuror 取回陪审员质押
+//
+// 陪审员在任一未裁决争议的陪审团中时质押锁定，裁决后方可取回。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "amount": 5000    // 取回金额（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 取回成功
+//   - framework.ERROR_NOT_FOUND - 调用者不是陪审员
+//   - framework.ERROR_INVALID_STATE - 陪审员仍在未裁决争议的陪审团中
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 质押不足
+//
+// 事件：
+//   - JurorUnstaked {"juror": "...", "amount": 5000, "stake": 0}
+//
+//export UnstakeJuror
+func UnstakeJuror() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	amount := params.ParseJSONInt("amount")
+	cfg, err := court.config()
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤2：记账（锁定检查在 unstake 中完成）
+	caller := framework.GetCaller()
+	remaining, err := court.unstake(caller, amount)
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：从合约地址返还质押
+	if err := token.Transfer(framework.GetContractAddress(), caller, cfg.TokenID, framework.Amount(amount)); err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤4：发出事件并返回
+	event := framework.NewEvent("JurorUnstaked")
+	event.AddAddressField("juror", caller)
+	event.AddUint64Field("amount", amount)
+	event.AddUint64Field("stake", remaining)
+	framework.EmitEvent(event)
+
+	return returnJuror(caller, cfg)
+}
+
+// RegisterEscrow 登记托管交易的买卖双方
+//
+// 托管合约将本合约地址设为仲裁人时以自身地址调用，登记后双方才能就该托管交易发起争议。
+// 每笔托管交易（escrow_contract + escrow_id）只能登记一次。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "escrow_id": "order_123",              // 托管ID（必填，≤32字节）
+//	  "buyer": "Cf1...",                     // 买方地址（必填）
+//	  "seller": "Cf2..."                     // 卖方地址（必填，不能与买方相同）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 登记成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_ALREADY_EXISTS - 该托管交易已登记
+//
+// 事件：
+//   - EscrowRegistered {"escrow_contract": "...", "escrow_id": "order_123", "buyer": "...", "seller": "..."}
+//
+//export RegisterEscrow
+func RegisterEscrow() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	buyer, err := framework.ParseAddressBase58(params.ParseJSON("buyer"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	seller, err := framework.ParseAddressBase58(params.ParseJSON("seller"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	escrowID := params.ParseJSON("escrow_id")
+
+	// 步骤2：以调用者（托管合约）地址登记
+	escrowContract := framework.GetCaller()
+	if err := court.registerEscrow(escrowContract, escrowID, EscrowParties{Buyer: buyer, Seller: seller}); err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：发出事件
+	event := framework.NewEvent("EscrowRegistered")
+	event.AddAddressField("escrow_contract", escrowContract)
+	event.AddStringField("escrow_id", escrowID)
+	event.AddAddressField("buyer", buyer)
+	event.AddAddressField("seller", seller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// CreateDispute 创建争议
+//
+// 争议方（调用者）预付仲裁费，裁决后仲裁费计入奖励池。
+// 托管合约须已将本合约地址设为该托管的仲裁人，并已通过 RegisterEscrow 登记买卖双方；
+// 调用者须为该托管交易的买方或卖方。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001",           // 争议ID（必填，≤32字节）
+//	  "escrow_contract": "Cf1...",           // 托管合约地址（必填）
+//	  "escrow_id": "order_123",              // 托管ID（必填，≤32字节）
+//	  "evidence_hash": "0x3f5a...",          // 证据哈希（必填，32字节十六进制）
+//	  "panel_size": 3,                       // 陪审团人数（必填，1~15，建议奇数）
+//	  "fee": 300                             // 仲裁费（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 创建成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是该托管交易的买方或卖方
+//   - framework.ERROR_NOT_FOUND - 托管交易未登记
+//   - framework.ERROR_ALREADY_EXISTS - 争议已存在，或该托管交易已有未裁决的争议
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//
+// 事件：
+//   - DisputeCreated
+//     {
+//       "dispute_id": "dispute_001",
+//       "disputer": "...",
+//       "escrow_contract": "...",
+//       "escrow_id": "order_123",
+//       "panel_size": 3,
+//       "fee": 300,
+//       "seed_height": 1001
+//     }
+//
+//export CreateDispute
+func CreateDispute() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	escrowContract, err := framework.ParseAddressBase58(params.ParseJSON("escrow_contract"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	evidence, ok := parseHash32(params.ParseJSON("evidence_hash"))
+	if !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	cfg, err := court.config()
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	caller := framework.GetCaller()
+	d := &Dispute{
+		DisputeID:      params.ParseJSON("dispute_id"),
+		Disputer:       caller,
+		EscrowContract: escrowContract,
+		EscrowID:       params.ParseJSON("escrow_id"),
+		EvidenceHash:   evidence,
+		PanelSize:      params.ParseJSONInt("panel_size"),
+		Fee:            params.ParseJSONInt("fee"),
+		CreatedHeight:  framework.GetBlockHeight(),
+		CreatedAt:      framework.GetTimestamp(),
+	}
+
+	// 步骤2：预付仲裁费
+	if d.Fee == 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if err := token.Transfer(caller, framework.GetContractAddress(), cfg.TokenID, framework.Amount(d.Fee)); err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：保存争议（参数校验在 createDispute 中完成）
+	if err := court.createDispute(d); err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤4：发出事件并返回
+	event := framework.NewEvent("DisputeCreated")
+	event.AddStringField("dispute_id", d.DisputeID)
+	event.AddAddressField("disputer", caller)
+	event.AddAddressField("escrow_contract", escrowContract)
+	event.AddStringField("escrow_id", d.EscrowID)
+	event.AddStringField("evidence_hash", "0x"+hex.EncodeToString(evidence[:]))
+	event.AddUint64Field("panel_size", d.PanelSize)
+	event.AddUint64Field("fee", d.Fee)
+	event.AddUint64Field("seed_height", d.CreatedHeight+1)
+	framework.EmitEvent(event)
+
+	return returnDispute(d)
+}
+
+// DrawPanel 抽选陪审团（任何人可调用）
+//
+// 须在争议创建后下一区块（seed_height）产生后调用；种子为该区块哈希与争议ID的 SHA-256，
+// 按质押加权、不放回抽选，低于最低质押的陪审员与争议方本人不参与。
+// 抽选后开启提交期，入选陪审员的质押锁定至裁决。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001"
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 抽选成功，返回争议详情（含陪审团）
+//   - framework.ERROR_NOT_FOUND - 争议不存在
+//   - framework.ERROR_INVALID_STATE - 已抽选、种子区块尚未产生或合格陪审员不足
+//
+// 事件：
+//   - PanelDrawn {"dispute_id": "...", "jurors": ["...", ...], "commit_deadline": ..., "reveal_deadline": ...}
+//
+//export DrawPanel
+func DrawPanel() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数并读取争议
+	params := framework.GetContractParams()
+	disputeID := params.ParseJSON("dispute_id")
+	d, err := court.dispute(disputeID)
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤2：以种子区块哈希抽选
+	seedHash := framework.GetBlockHash(d.CreatedHeight + 1)
+	d, err = court.drawPanel(disputeID, framework.GetBlockHeight(), seedHash.ToBytes(), framework.GetTimestamp())
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：发出事件并返回
+	jurors := make([]string, len(d.Seats))
+	for i, s := range d.Seats {
+		jurors[i] = s.Juror.ToString()
+	}
+	event := framework.NewEvent("PanelDrawn")
+	event.AddStringField("dispute_id", disputeID)
+	event.AddField("jurors", jurors)
+	event.AddUint64Field("commit_deadline", d.CommitDeadline)
+	event.AddUint64Field("reveal_deadline", d.RevealDeadline)
+	framework.EmitEvent(event)
+
+	return returnDispute(d)
+}
+
+// CommitVote 提交投票承诺（仅陪审团成员、提交期内、每席一次）
+//
+// commitment = sha256(dispute_id || juror(20字节) || choice(4字节大端) || salt)，
+// choice 为 1（支持买方）或 2（支持卖方），salt 为陪审员自选的随机字符串。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001",
+//	  "commitment": "0x9c1e..."     // 32字节十六进制
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 提交成功
+//   - framework.ERROR_UNAUTHORIZED - 调用者不在陪审团中
+//   - framework.ERROR_INVALID_STATE - 不在提交期或已提交
+//
+// 事件：
+//   - VoteCommitted {"dispute_id": "...", "juror": "..."}
+//
+//export CommitVote
+func CommitVote() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	disputeID := params.ParseJSON("dispute_id")
+	commitment, ok := parseHash32(params.ParseJSON("commitment"))
+	if !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	if _, err := court.commitVote(disputeID, caller, commitment, framework.GetTimestamp()); err != nil {
+		return courtErrorCode(err)
+	}
+
+	event := framework.NewEvent("VoteCommitted")
+	event.AddStringField("dispute_id", disputeID)
+	event.AddAddressField("juror", caller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// RevealVote 揭示投票（仅揭示期内）
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001",
+//	  "choice": 2,                  // 1 = 支持买方（退款），2 = 支持卖方（放款）
+//	  "salt": "my-secret-salt"      // 提交承诺时使用的盐值（按 UTF-8 字节参与哈希）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 揭示成功
+//   - framework.ERROR_INVALID_PARAMS - 投票无效或与承诺不符
+//   - framework.ERROR_UNAUTHORIZED - 调用者不在陪审团中
+//   - framework.ERROR_INVALID_STATE - 不在揭示期、未提交或已揭示
+//
+// 事件：
+//   - VoteRevealed {"dispute_id": "...", "juror": "...", "choice": 2}
+//
+//export RevealVote
+func RevealVote() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	disputeID := params.ParseJSON("dispute_id")
+	choice := params.ParseJSONInt("choice")
+	salt := params.ParseJSON("salt")
+	if choice > 0xFFFFFFFF {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	if _, err := court.revealVote(disputeID, caller, uint32(choice), []byte(salt), framework.GetTimestamp()); err != nil {
+		return courtErrorCode(err)
+	}
+
+	event := framework.NewEvent("VoteRevealed")
+	event.AddStringField("dispute_id", disputeID)
+	event.AddAddressField("juror", caller)
+	event.AddUint64Field("choice", choice)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// ResolveDispute 裁决争议（任何人可在揭示期结束后调用）
+//
+// 规则：
+//   - 揭示票数多的一方胜出；平票（含无人揭示）裁决为 0（SPLIT），平票时所有揭示者视为一致
+//   - 未揭示（含未提交）的陪审员罚没 non_reveal_penalty_bp，与裁决不一致的罚没 incoherent_penalty_bp
+//   - 罚没 + 仲裁费组成奖励池，法庭抽取 court_fee_bp，其余由一致陪审员平分（计入质押），余数归法庭
+//   - 向托管合约发出裁决指令，陪审员质押解锁
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001"
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 裁决成功，返回争议详情
+//   - framework.ERROR_NOT_FOUND - 争议不存在
+//   - framework.ERROR_INVALID_STATE - 未抽选、已裁决或揭示期未结束
+//
+// 事件：
+//   - ArbitrationInstructed
+//     {
+//       "dispute_id": "dispute_001",
+//       "escrow_contract": "...",
+//       "escrow_id": "order_123",
+//       "ruling": 2,
+//       "instruction_key": "arbitration_instruction:<hex>:order_123"
+//     }
+//   - DisputeResolved {"dispute_id": "...", "ruling": 2, "votes_buyer": 1, "votes_seller": 2, "court_fee": 170}
+//
+//export ResolveDispute
+func ResolveDispute() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	disputeID := params.ParseJSON("dispute_id")
+
+	// 步骤2：裁决并写入裁决指令
+	d, err := court.resolve(disputeID, framework.GetTimestamp(), stateOutboxTarget{})
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	// 步骤3：发出事件
+	instr := framework.NewEvent("ArbitrationInstructed")
+	instr.AddStringField("dispute_id", disputeID)
+	instr.AddAddressField("escrow_contract", d.EscrowContract)
+	instr.AddStringField("escrow_id", d.EscrowID)
+	instr.AddUint64Field("ruling", uint64(d.Ruling))
+	instr.AddStringField("instruction_key", string(arbitrationInstructionStateID(d.EscrowContract, d.EscrowID)))
+	framework.EmitEvent(instr)
+
+	event := framework.NewEvent("DisputeResolved")
+	event.AddStringField("dispute_id", disputeID)
+	event.AddUint64Field("ruling", uint64(d.Ruling))
+	event.AddUint64Field("votes_buyer", d.VotesBuyer)
+	event.AddUint64Field("votes_seller", d.VotesSeller)
+	event.AddUint64Field("court_fee", d.CourtFee)
+	event.AddAddressField("caller", framework.GetCaller())
+	framework.EmitEvent(event)
+
+	// 步骤4：返回争议详情
+	return returnDispute(d)
+}
+
+// WithdrawCourtFees 提取法庭手续费（仅所有者）
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "amount": 170
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 提取成功
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是所有者
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 手续费余额不足
+//
+//export WithdrawCourtFees
+func WithdrawCourtFees() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	amount := params.ParseJSONInt("amount")
+	cfg, err := court.config()
+	if err != nil {
+		return courtErrorCode(err)
+	}
+
+	caller := framework.GetCaller()
+	if err := court.withdrawCourtFees(caller, amount); err != nil {
+		return courtErrorCode(err)
+	}
+	if err := token.Transfer(framework.GetContractAddress(), caller, cfg.TokenID, framework.Amount(amount)); err != nil {
+		return courtErrorCode(err)
+	}
+
+	event := framework.NewEvent("CourtFeesWithdrawn")
+	event.AddAddressField("owner", caller)
+	event.AddUint64Field("amount", amount)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// GetDispute 查询争议
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "dispute_id": "dispute_001"
+//	}
+//
+// 返回 JSON：
+//
+//	{
+//	  "dispute_id": "dispute_001",
+//	  "status": "PANEL_DRAWN",
+//	  "ruling": 0,
+//	  "commit_deadline": 1736286400,
+//	  "reveal_deadline": 1736372800,
+//	  "panel": [
+//	    {"juror": "Cf1...", "committed": true, "revealed": false, "choice": 0, "penalty": 0, "reward": 0}
+//	  ]
+//	}
+//
+//export GetDispute
+func GetDispute() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	d, err := court.dispute(params.ParseJSON("dispute_id"))
+	if err != nil {
+		return courtErrorCode(err)
+	}
+	return returnDispute(d)
+}
+
+// GetJuror 查询陪审员质押与锁定情况
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "juror": "Cf1..."
+//	}
+//
+// 返回 JSON：
+//
+//	{"juror": "Cf1...", "stake": 5000, "assigned": 1, "locked": true}
+//
+//export GetJuror
+func GetJuror() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	juror, err := framework.ParseAddressBase58(params.ParseJSON("juror"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	cfg, err := court.config()
+	if err != nil {
+		return courtErrorCode(err)
+	}
+	return returnJuror(juror, cfg)
+}
+
+// ================================================================================================
+// 辅助函数
+// ================================================================================================
+
+// returnJuror 以 JSON 返回陪审员详情
+func returnJuror(juror framework.Address, cfg CourtConfig) uint32 {
+	staked, err := court.stakeOf(juror, cfg.TokenID)
+	if err != nil {
+		return courtErrorCode(err)
+	}
+	rec, err := court.juror(juror)
+	if err != nil {
+		return courtErrorCode(err)
+	}
+	result := map[string]interface{}{
+		"juror":    juror.ToString(),
+		"stake":    staked,
+		"assigned": rec.Assigned,
+		"locked":   rec.Assigned > 0,
+		"eligible": staked >= cfg.MinStake,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// returnDispute 以 JSON 返回争议详情
+func returnDispute(d *Dispute) uint32 {
+	panel := make([]interface{}, len(d.Seats))
+	for i, s := range d.Seats {
+		panel[i] = map[string]interface{}{
+			"juror":     s.Juror.ToString(),
+			"committed": s.Committed,
+			"revealed":  s.Revealed,
+			"choice":    uint64(s.Choice),
+			"penalty":   s.Penalty,
+			"reward":    s.Reward,
+		}
+	}
+
+	result := map[string]interface{}{
+		"dispute_id":      d.DisputeID,
+		"disputer":        d.Disputer.ToString(),
+		"escrow_contract": d.EscrowContract.ToString(),
+		"escrow_id":       d.EscrowID,
+		"evidence_hash":   "0x" + hex.EncodeToString(d.EvidenceHash[:]),
+		"panel_size":      d.PanelSize,
+		"fee":             d.Fee,
+		"status":          d.Status,
+		"seed_height":     d.CreatedHeight + 1,
+		"commit_deadline": d.CommitDeadline,
+		"reveal_deadline": d.RevealDeadline,
+		"ruling":          uint64(d.Ruling),
+		"votes_buyer":     d.VotesBuyer,
+		"votes_seller":    d.VotesSeller,
+		"court_fee":       d.CourtFee,
+		"panel":           panel,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// courtErrorCode 将法庭错误映射为合约错误码
+func courtErrorCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	switch err {
+	case errInvalidParams, errCommitMismatch:
+		return framework.ERROR_INVALID_PARAMS
+	case errNotFound:
+		return framework.ERROR_NOT_FOUND
+	case errAlreadyExists:
+		return framework.ERROR_ALREADY_EXISTS
+	case errUnauthorized, errNotPanelist:
+		return framework.ERROR_UNAUTHORIZED
+	case errInsufficientStake:
+		return framework.ERROR_INSUFFICIENT_BALANCE
+	case errNotInitialized, errInvalidStatus, errJurorAssigned, errJurorPoolFull,
+		errNotEnoughJurors, errSeedNotAvailable, errPhaseClosed, errAlreadyVoted:
+		return framework.ERROR_INVALID_STATE
+	default:
+		return framework.ERROR_EXECUTION_FAILED
+	}
+}
+
+func main() {}
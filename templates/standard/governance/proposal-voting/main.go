@@ -20,6 +20,10 @@
 //     - 使用 governance.Vote() 对提案进行投票
 //     - 支持支持/反对两种投票方式
 //
+//  3. RegisterBallotKey / SubmitBallots - 签名选票批量提交
+//     - 投票者登记 Ed25519 公钥后在链下签名选票
+//     - 协调者使用 governance.SubmitSignedBallots() 一次提交多张选票
+//
 // 📚 相关文档
 //
 //   - [Governance 模块文档](../../helpers/governance/README.md)
@@ -28,6 +32,9 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"math"
+
 	"github.com/weisyn/contract-sdk-go/helpers/governance"
 	"github.com/weisyn/contract-sdk-go/framework"
 )
@@ -60,6 +67,7 @@ type GovernanceContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Governance")
@@ -110,6 +118,7 @@ func Initialize() uint32 {
 //
 //export Propose
 func Propose() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -186,6 +195,7 @@ func Propose() uint32 {
 //
 //export Vote
 func Vote() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -223,5 +233,239 @@ func Vote() uint32 {
 	return framework.SUCCESS
 }
 
+// RegisterBallotKey 登记签名选票公钥
+//
+// 投票者登记用于链下签名选票的 Ed25519 公钥，之后可由协调者通过 SubmitBallots
+// 代为提交选票。重复调用会替换之前登记的公钥。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "public_key": "3b6a27bc..."   // Ed25519 公钥（32字节十六进制，必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 登记成功
+//   - framework.ERROR_INVALID_PARAMS - 公钥格式无效
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - BallotKeyRegistered
+//     {
+//       "voter": "<投票者地址>",
+//       "public_key": "3b6a27bc..."
+//     }
+//
+//export RegisterBallotKey
+func RegisterBallotKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	publicKeyHex := params.ParseJSON("public_key")
+
+	publicKey, ok := decodeHex(publicKeyHex)
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	stateID := ballotKeyStateID(caller)
+	_, version, _ := framework.GetStateFromChain(stateID)
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, publicKey, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	event := framework.NewEvent("BallotKeyRegistered")
+	event.AddAddressField("voter", caller)
+	event.AddStringField("public_key", publicKeyHex)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// SubmitBallots 批量提交签名选票
+//
+// 协调者收集投票者在链下签名的选票后一次性提交，使用 helpers/governance 模块的
+// SubmitSignedBallots 逐张校验并计票。签名消息见 governance.BallotMessage：
+// 绑定链ID、合约地址、提案ID、选项（0=反对，1=支持）与过期时间。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "proposal_id": "proposal_123",            // 提案ID（必填）
+//	  "ballots": [                              // 选票列表（必填，最多64张）
+//	    {
+//	      "voter": "Cf1...",                    // 投票者地址（Base58）
+//	      "choice": 1,                          // 0=反对，1=支持
+//	      "weight_claim": 1000,                 // 声明权重（可选，仅作为计票上限）
+//	      "expiry": 1736200000,                 // 过期时间（时间戳）
+//	      "signature": "9f0c..."                // Ed25519 签名（十六进制）
+//	    }
+//	  ]
+//	}
+//
+// 计票规则：
+//   - 签名须与投票者通过 RegisterBallotKey 登记的公钥匹配
+//   - 投票权重为投票者当前原生币余额，weight_claim 非 0 时取两者较小值
+//   - 过期、签名无效、批内重复、已投票（含通过 Vote 投票）的选票跳过，不中断整批
+//
+// 返回：
+//   - framework.SUCCESS - 提交成功，返回逐张处理结果
+//   - framework.ERROR_INVALID_PARAMS - 参数无效（选票为空、超过上限或格式错误）
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 返回数据：
+//
+//	{
+//	  "proposal_id": "proposal_123",
+//	  "applied_count": 1,
+//	  "results": [{"voter": "Cf1...", "status": "APPLIED", "weight": 1000}],
+//	  "tally_support": 1000,
+//	  "tally_oppose": 0
+//	}
+//
+// 事件：
+//   - Vote - 每张计入的选票（signed=true，由 SDK 自动发出）
+//   - SignedBallotsSubmitted - 整批提交（由 SDK 自动发出）
+//
+//export SubmitBallots
+func SubmitBallots() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	proposalIDStr := params.ParseJSON("proposal_id")
+	ballots, ok := parseBallots(params)
+	if proposalIDStr == "" || !ok || len(ballots) == 0 || len(ballots) > governance.MAX_BALLOTS_PER_BATCH {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	proposalID := []byte(proposalIDStr)
+	results, err := governance.SubmitSignedBallots(proposalID, ballots, 2, registeredKeyVerifier{}, balanceVotingPower{})
+	if err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	items := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		items = append(items, map[string]interface{}{
+			"voter":  r.Voter.ToString(),
+			"status": r.Status,
+			"weight": r.Weight,
+		})
+	}
+	result := map[string]interface{}{
+		"proposal_id":   proposalIDStr,
+		"applied_count": uint64(governance.CountApplied(results)),
+		"results":       items,
+		"tally_support": governance.GetTally(proposalID, 1),
+		"tally_oppose":  governance.GetTally(proposalID, 0),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// registeredKeyVerifier 以 RegisterBallotKey 登记的 Ed25519 公钥校验选票签名
+type registeredKeyVerifier struct{}
+
+func (registeredKeyVerifier) VerifyBallot(voter framework.Address, message []byte, signature []byte) bool {
+	publicKey, _, err := framework.GetStateFromChain(ballotKeyStateID(voter))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	// 链上读取会去除尾部零字节，补齐到公钥长度
+	key := make([]byte, ed25519.PublicKeySize)
+	copy(key, publicKey)
+	return len(publicKey) > 0 && ed25519.Verify(key, message, signature)
+}
+
+// balanceVotingPower 以投票者当前原生币余额作为投票权重
+type balanceVotingPower struct{}
+
+func (balanceVotingPower) VotingPower(voter framework.Address, proposalID []byte) uint64 {
+	return uint64(framework.QueryUTXOBalance(voter, framework.TokenID("")))
+}
+
+// ballotKeyStateID 签名选票公钥状态ID：ballot_key:{voter}
+func ballotKeyStateID(voter framework.Address) []byte {
+	return []byte("ballot_key:" + voter.ToString())
+}
+
+// parseBallots 解析 "ballots": [ {...}, ... ]，任一选票格式无效时返回 false
+//
+// choice 须不超过 uint32；weight_claim、expiry 须为非负整数，超过 uint64 时拒绝而不是截断
+func parseBallots(params *framework.ContractParams) ([]governance.Ballot, bool) {
+	objects := params.ParseJSONObjectArray("ballots")
+	if objects == nil {
+		return nil, false
+	}
+	ballots := make([]governance.Ballot, 0, len(objects))
+	for _, obj := range objects {
+		voter, err := framework.ParseAddressBase58(obj.ParseJSON("voter"))
+		if err != nil {
+			return nil, false
+		}
+		signature, ok := decodeHex(obj.ParseJSON("signature"))
+		if !ok {
+			return nil, false
+		}
+		choice, err := obj.ParseAmount("choice")
+		if err != nil || choice > math.MaxUint32 {
+			return nil, false
+		}
+		weightClaim, err := obj.ParseAmount("weight_claim")
+		if err != nil {
+			return nil, false
+		}
+		expiry, err := obj.ParseTimestamp("expiry")
+		if err != nil {
+			return nil, false
+		}
+		ballots = append(ballots, governance.Ballot{
+			Voter:       voter,
+			Choice:      uint32(choice),
+			WeightClaim: uint64(weightClaim),
+			Expiry:      uint64(expiry),
+			Signature:   signature,
+		})
+	}
+	return ballots, true
+}
+
+// decodeHex 解码十六进制字符串（可带 0x 前缀）
+func decodeHex(s string) ([]byte, bool) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	if len(s) == 0 || len(s)%2 != 0 {
+		return nil, false
+	}
+	out := make([]byte, len(s)/2)
+	for i := 0; i < len(out); i++ {
+		hi, ok1 := hexNibble(s[2*i])
+		lo, ok2 := hexNibble(s[2*i+1])
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		out[i] = hi<<4 | lo
+	}
+	return out, true
+}
+
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
 func main() {}
 
@@ -28,7 +28,7 @@ tinygo build -o main.wasm \
   -no-debug \
   -opt=2 \
   -gc=leaking \
-  main.go
+  .
 
 # 检查输出
 if [ -f main.wasm ]; then
@@ -0,0 +1,277 @@
+package main
+
+import "github.com/weisyn/contract-sdk-go/framework"
+
+// ==================== 钱包显示清单 ====================
+//
+// 各导出函数的中英文名称与参数显示提示，由 GetDisplayManifest 返回（见 framework.RegisterFunction）。
+// 计划内金额均以计划代币计价，代币ID由 GetPlanInfo 的 token_id 字段给出；
+// Initialize 时计划尚未创建，金额引用同一调用的 token_id 参数。
+
+// planTokenRef 计划代币ID的引用
+const planTokenRef = "GetPlanInfo.token_id"
+
+func init() {
+	framework.RegisterViewFunction(framework.DISPLAY_MANIFEST_METHOD, framework.ViewDisplayManifest)
+
+	planID := framework.Param("plan_id", "string", framework.Labels{"zh-CN": "互助计划", "en-US": "Plan"})
+	claimID := framework.Param("claim_id", "string", framework.Labels{"zh-CN": "案件编号", "en-US": "Claim ID"})
+	roundID := framework.Param("round_id", "string", framework.Labels{"zh-CN": "轮次编号", "en-US": "Round ID"})
+	member := framework.Param("member", "address", framework.Labels{"zh-CN": "成员", "en-US": "Member"}, framework.Hint(framework.HINT_ADDRESS))
+	tier := framework.Param("tier", "uint64", framework.Labels{"zh-CN": "保障档位", "en-US": "Tier"})
+	categoryID := framework.Param("category_id", "string", framework.Labels{"zh-CN": "保障类别", "en-US": "Coverage category"})
+	reason := framework.Param("reason", "string", framework.Labels{"zh-CN": "原因", "en-US": "Reason"})
+	reviewRoundID := framework.Param("review_round_id", "string", framework.Labels{"zh-CN": "评审轮次", "en-US": "Review round"})
+	amount := func(name string, labels framework.Labels) framework.FunctionParam {
+		return framework.Param(name, "uint64", labels, framework.AmountHint(planTokenRef))
+	}
+	basisPoints := func(name string, labels framework.Labels) framework.FunctionParam {
+		return framework.Param(name, "uint64", labels, framework.Hint(framework.HINT_BASIS_POINTS))
+	}
+	hash := func(name string, labels framework.Labels) framework.FunctionParam {
+		return framework.Param(name, "string", labels, framework.Hint(framework.HINT_HASH))
+	}
+
+	// 计划与成员
+	framework.RegisterFunction("Initialize",
+		framework.Labels{"zh-CN": "创建互助计划", "en-US": "Create mutual aid plan"},
+		planID,
+		framework.Param("name", "string", framework.Labels{"zh-CN": "计划名称", "en-US": "Plan name"}),
+		framework.Param("token_id", "string", framework.Labels{"zh-CN": "计价代币", "en-US": "Token"}),
+		framework.Param("coverage_amount", "uint64", framework.Labels{"zh-CN": "保障金额", "en-US": "Coverage amount"}, framework.AmountHint("token_id")),
+		basisPoints("service_fee_bp", framework.Labels{"zh-CN": "服务费率", "en-US": "Service fee"}),
+		framework.Param("settlement_period", "uint64", framework.Labels{"zh-CN": "结算周期", "en-US": "Settlement period"}, framework.Hint(framework.HINT_DURATION_SECONDS)),
+		framework.Param("waiting_period", "uint64", framework.Labels{"zh-CN": "等待期", "en-US": "Waiting period"}, framework.Hint(framework.HINT_DURATION_SECONDS)),
+		framework.Param("min_members", "uint64", framework.Labels{"zh-CN": "最少成员数", "en-US": "Minimum members"}),
+		framework.Param("monthly_cap_per_member", "uint64", framework.Labels{"zh-CN": "成员月度分摊上限", "en-US": "Monthly cap per member"}, framework.AmountHint("token_id")),
+		framework.Param("categories", "json", framework.Labels{"zh-CN": "保障类别", "en-US": "Coverage categories"}),
+		framework.Param("operator_admin", "address", framework.Labels{"zh-CN": "运营方管理员", "en-US": "Operator admin"}, framework.Hint(framework.HINT_ADDRESS)),
+	)
+
+	// 角色密钥轮换
+	role := framework.Param("role", "string", framework.Labels{"zh-CN": "角色", "en-US": "Role"})
+	newAddress := framework.Param("new_address", "address", framework.Labels{"zh-CN": "新地址", "en-US": "New address"}, framework.Hint(framework.HINT_ADDRESS))
+	framework.RegisterFunction("RotateRoleKey",
+		framework.Labels{"zh-CN": "发起角色密钥轮换", "en-US": "Rotate role key"},
+		planID, role, newAddress,
+	)
+	framework.RegisterFunction("AcceptRoleKey",
+		framework.Labels{"zh-CN": "接受角色密钥轮换", "en-US": "Accept role key"},
+		planID, role,
+	)
+	framework.RegisterFunction("EmergencyRotateRoleKey",
+		framework.Labels{"zh-CN": "紧急轮换角色密钥", "en-US": "Emergency rotate role key"},
+		planID, role, newAddress,
+	)
+
+	framework.RegisterFunction("Join",
+		framework.Labels{"zh-CN": "申请加入计划", "en-US": "Join plan"},
+		planID, tier,
+	)
+	framework.RegisterFunction("ApproveMember",
+		framework.Labels{"zh-CN": "批准成员", "en-US": "Approve member"},
+		planID, member,
+	)
+	framework.RegisterFunction("Exit",
+		framework.Labels{"zh-CN": "退出计划", "en-US": "Exit plan"},
+		planID,
+	)
+	framework.RegisterFunction("SuspendMember",
+		framework.Labels{"zh-CN": "暂停成员", "en-US": "Suspend member"},
+		planID, member, reason,
+	)
+	framework.RegisterFunction("ResumeMember",
+		framework.Labels{"zh-CN": "恢复成员", "en-US": "Resume member"},
+		planID, member, reason,
+	)
+	framework.RegisterFunction("BlacklistMember",
+		framework.Labels{"zh-CN": "拉黑成员", "en-US": "Blacklist member"},
+		planID, member, reason,
+	)
+	framework.RegisterFunction("ReconcileMemberCount",
+		framework.Labels{"zh-CN": "校正成员计数", "en-US": "Reconcile member count"},
+		planID,
+	)
+	framework.RegisterFunction("SetMemberCap",
+		framework.Labels{"zh-CN": "设置成员分摊上限", "en-US": "Set member cap"},
+		planID, member,
+		amount("cap", framework.Labels{"zh-CN": "分摊上限", "en-US": "Cap"}),
+	)
+	framework.RegisterFunction("ExcludeCategoryForMember",
+		framework.Labels{"zh-CN": "设置成员类别除外", "en-US": "Exclude category for member"},
+		planID, member, categoryID, reason,
+	)
+	framework.RegisterFunction("SetTierMultiplier",
+		framework.Labels{"zh-CN": "设置档位分摊倍数", "en-US": "Set tier multiplier"},
+		planID, tier,
+		basisPoints("multiplier_bp", framework.Labels{"zh-CN": "分摊倍数", "en-US": "Multiplier"}),
+	)
+	framework.RegisterFunction("SetFeeAdjustment",
+		framework.Labels{"zh-CN": "设置服务费调整方式", "en-US": "Set fee adjustment"},
+		planID,
+		framework.Param("mode", "string", framework.Labels{"zh-CN": "调整方式", "en-US": "Mode"}),
+		basisPoints("min_fee_bp", framework.Labels{"zh-CN": "最低服务费率", "en-US": "Minimum fee"}),
+		basisPoints("max_fee_bp", framework.Labels{"zh-CN": "最高服务费率", "en-US": "Maximum fee"}),
+	)
+	framework.RegisterFunction("SetRoundingMode",
+		framework.Labels{"zh-CN": "设置分摊取整方式", "en-US": "Set rounding mode"},
+		planID,
+		framework.Param("mode", "string", framework.Labels{"zh-CN": "取整方式", "en-US": "Mode"}),
+		framework.Param("decimals", "uint64", framework.Labels{"zh-CN": "取整位数", "en-US": "Decimals"}),
+		framework.Param("carry_forward", "bool", framework.Labels{"zh-CN": "余差结转", "en-US": "Carry forward"}),
+	)
+
+	// 案件
+	framework.RegisterFunction("SubmitClaim",
+		framework.Labels{"zh-CN": "提交互助申请", "en-US": "Submit claim"},
+		planID, claimID,
+		framework.Param("insured", "address", framework.Labels{"zh-CN": "被保障成员", "en-US": "Insured member"}, framework.Hint(framework.HINT_ADDRESS)),
+		amount("requested_amount", framework.Labels{"zh-CN": "申请金额", "en-US": "Requested amount"}),
+		framework.Param("event_time", "uint64", framework.Labels{"zh-CN": "出险时间", "en-US": "Event time"}, framework.Hint(framework.HINT_TIMESTAMP)),
+		hash("evidence_hash", framework.Labels{"zh-CN": "材料哈希", "en-US": "Evidence hash"}),
+		categoryID,
+		framework.Param("extra", "string", framework.Labels{"zh-CN": "备注", "en-US": "Remarks"}),
+	)
+	framework.RegisterFunction("AttachEvidence",
+		framework.Labels{"zh-CN": "补充申请材料", "en-US": "Attach evidence"},
+		planID, claimID,
+		framework.Param("attachment", "string", framework.Labels{"zh-CN": "材料", "en-US": "Attachment"}),
+	)
+	framework.RegisterFunction("ReviewClaim",
+		framework.Labels{"zh-CN": "评审互助申请", "en-US": "Review claim"},
+		planID, claimID,
+		framework.Param("decision", "string", framework.Labels{"zh-CN": "评审结论", "en-US": "Decision"}),
+		amount("approved_amount", framework.Labels{"zh-CN": "批准金额", "en-US": "Approved amount"}),
+		reason,
+		hash("investigation_hash", framework.Labels{"zh-CN": "调查报告哈希", "en-US": "Investigation hash"}),
+		reviewRoundID,
+	)
+	framework.RegisterFunction("BatchReviewClaims",
+		framework.Labels{"zh-CN": "批量评审互助申请", "en-US": "Batch review claims"},
+		planID, reviewRoundID,
+		framework.Param("decisions", "json", framework.Labels{"zh-CN": "评审结论", "en-US": "Decisions"}),
+	)
+	framework.RegisterFunction("CancelClaim",
+		framework.Labels{"zh-CN": "取消互助申请", "en-US": "Cancel claim"},
+		planID, claimID, reason,
+	)
+
+	// 轮次与分摊
+	framework.RegisterFunction("OpenRound",
+		framework.Labels{"zh-CN": "开启分摊轮次", "en-US": "Open round"},
+		planID, roundID,
+		framework.Param("period_start", "uint64", framework.Labels{"zh-CN": "周期开始", "en-US": "Period start"}, framework.Hint(framework.HINT_TIMESTAMP)),
+		framework.Param("period_end", "uint64", framework.Labels{"zh-CN": "周期结束", "en-US": "Period end"}, framework.Hint(framework.HINT_TIMESTAMP)),
+	)
+	framework.RegisterFunction("SettleRound",
+		framework.Labels{"zh-CN": "结算分摊轮次", "en-US": "Settle round"},
+		planID, roundID,
+	)
+	framework.RegisterFunction("AdvanceRound",
+		framework.Labels{"zh-CN": "进入下一轮次", "en-US": "Advance round"},
+		planID,
+		framework.Param("next_round_id", "string", framework.Labels{"zh-CN": "下一轮次编号", "en-US": "Next round ID"}),
+	)
+	framework.RegisterFunction("CloseRound",
+		framework.Labels{"zh-CN": "关闭分摊轮次", "en-US": "Close round"},
+		planID, roundID,
+		framework.Param("members", "json", framework.Labels{"zh-CN": "检查成员", "en-US": "Members"}),
+	)
+	framework.RegisterFunction("PayContribution",
+		framework.Labels{"zh-CN": "缴纳分摊", "en-US": "Pay contribution"},
+		planID, roundID,
+		framework.Param("pool", "address", framework.Labels{"zh-CN": "资金池地址", "en-US": "Pool"}, framework.Hint(framework.HINT_ADDRESS)),
+		amount("amount", framework.Labels{"zh-CN": "分摊金额", "en-US": "Amount"}),
+		framework.Param("contribution_id", "string", framework.Labels{"zh-CN": "缴费编号", "en-US": "Contribution ID"}),
+	)
+	framework.RegisterFunction("RecordOffchainContribution",
+		framework.Labels{"zh-CN": "登记线下分摊", "en-US": "Record offchain contribution"},
+		planID, member, roundID,
+		amount("amount", framework.Labels{"zh-CN": "分摊金额", "en-US": "Amount"}),
+		hash("reference_hash", framework.Labels{"zh-CN": "线下凭证哈希", "en-US": "Reference hash"}),
+	)
+	framework.RegisterFunction("ReconcileOffchain",
+		framework.Labels{"zh-CN": "核对线下分摊", "en-US": "Reconcile offchain contribution"},
+		planID,
+		hash("reference_hash", framework.Labels{"zh-CN": "线下凭证哈希", "en-US": "Reference hash"}),
+		framework.Param("resolution", "string", framework.Labels{"zh-CN": "核对结果", "en-US": "Resolution"}),
+	)
+	framework.RegisterFunction("Payout",
+		framework.Labels{"zh-CN": "发放互助金", "en-US": "Pay out claim"},
+		planID, claimID,
+		framework.Param("from", "address", framework.Labels{"zh-CN": "付款地址", "en-US": "From"}, framework.Hint(framework.HINT_ADDRESS)),
+		framework.Param("beneficiary", "address", framework.Labels{"zh-CN": "受益人", "en-US": "Beneficiary"}, framework.Hint(framework.HINT_ADDRESS)),
+		amount("amount", framework.Labels{"zh-CN": "发放金额", "en-US": "Amount"}),
+		framework.Param("payout_id", "string", framework.Labels{"zh-CN": "发放编号", "en-US": "Payout ID"}),
+	)
+	framework.RegisterFunction("FinalizePlan",
+		framework.Labels{"zh-CN": "终结互助计划", "en-US": "Finalize plan"},
+		planID,
+		framework.Param("pool", "address", framework.Labels{"zh-CN": "资金池地址", "en-US": "Pool"}, framework.Hint(framework.HINT_ADDRESS)),
+	)
+
+	// 查询
+	framework.RegisterFunction("GetPlanInfo",
+		framework.Labels{"zh-CN": "查询计划信息", "en-US": "Get plan info"},
+		planID,
+	)
+	framework.RegisterFunction("GetMemberInfo",
+		framework.Labels{"zh-CN": "查询成员信息", "en-US": "Get member info"},
+		planID, member,
+	)
+	framework.RegisterFunction("GetClaimInfo",
+		framework.Labels{"zh-CN": "查询案件信息", "en-US": "Get claim info"},
+		planID, claimID,
+	)
+	framework.RegisterFunction("GetRoundInfo",
+		framework.Labels{"zh-CN": "查询轮次信息", "en-US": "Get round info"},
+		planID, roundID,
+	)
+	framework.RegisterFunction("GetCurrentRound",
+		framework.Labels{"zh-CN": "查询当前轮次", "en-US": "Get current round"},
+		planID,
+	)
+	framework.RegisterFunction("PreviewSettlement",
+		framework.Labels{"zh-CN": "预览轮次结算", "en-US": "Preview settlement"},
+		planID, roundID,
+		framework.Param("pool", "address", framework.Labels{"zh-CN": "资金池地址", "en-US": "Pool"}, framework.Hint(framework.HINT_ADDRESS)),
+	)
+	framework.RegisterFunction("EstimateContribution",
+		framework.Labels{"zh-CN": "估算应缴分摊", "en-US": "Estimate contribution"},
+		planID, roundID,
+	)
+	framework.RegisterFunction("ListMembers",
+		framework.Labels{"zh-CN": "成员列表", "en-US": "List members"},
+		planID,
+		framework.Param("status", "string", framework.Labels{"zh-CN": "成员状态", "en-US": "Status"}),
+		framework.Param("offset", "uint64", framework.Labels{"zh-CN": "起始位置", "en-US": "Offset"}),
+		framework.Param("limit", "uint64", framework.Labels{"zh-CN": "条数", "en-US": "Limit"}),
+	)
+	framework.RegisterFunction("ListClaims",
+		framework.Labels{"zh-CN": "案件列表", "en-US": "List claims"},
+		planID,
+		framework.Param("status", "string", framework.Labels{"zh-CN": "案件状态", "en-US": "Status"}),
+		framework.Param("round_id", "string", framework.Labels{"zh-CN": "轮次ID", "en-US": "Round ID"}),
+		framework.Param("offset", "uint64", framework.Labels{"zh-CN": "起始位置", "en-US": "Offset"}),
+		framework.Param("limit", "uint64", framework.Labels{"zh-CN": "条数", "en-US": "Limit"}),
+	)
+	framework.RegisterFunction("QueryEventLog",
+		framework.Labels{"zh-CN": "查询事件日志", "en-US": "Query event log"},
+		framework.Param("event", "string", framework.Labels{"zh-CN": "事件名", "en-US": "Event"}),
+		framework.Param("from", "uint64", framework.Labels{"zh-CN": "起始时间", "en-US": "From"}, framework.Hint(framework.HINT_TIMESTAMP)),
+		framework.Param("to", "uint64", framework.Labels{"zh-CN": "截止时间", "en-US": "To"}, framework.Hint(framework.HINT_TIMESTAMP)),
+		framework.Param("cursor", "uint64", framework.Labels{"zh-CN": "游标", "en-US": "Cursor"}),
+		framework.Param("limit", "uint64", framework.Labels{"zh-CN": "条数", "en-US": "Limit"}),
+	)
+	framework.RegisterFunction("GetLimits",
+		framework.Labels{"zh-CN": "查询输入与写入限制", "en-US": "Get limits"},
+	)
+	framework.RegisterFunction(framework.MULTICALL_METHOD,
+		framework.Labels{"zh-CN": "批量查询", "en-US": "Multicall"},
+		framework.Param("calls", "json", framework.Labels{"zh-CN": "查询列表", "en-US": "Calls"}),
+	)
+	framework.RegisterFunction(framework.DISPLAY_MANIFEST_METHOD,
+		framework.Labels{"zh-CN": "查询显示清单", "en-US": "Get display manifest"},
+		framework.Param("locale", "string", framework.Labels{"zh-CN": "语言", "en-US": "Locale"}),
+	)
+}
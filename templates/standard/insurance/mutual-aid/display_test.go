@@ -0,0 +1,102 @@
+//go:build !tinygo && !(js && wasm)
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+// expectFunctionLabel 检查 GetDisplayManifest 返回中导出函数的名称
+func expectFunctionLabel(name, want string) func(st *fwtesting.Step) error {
+	return func(st *fwtesting.Step) error {
+		var manifest struct {
+			Functions []struct {
+				Name  string `json:"name"`
+				Label string `json:"label"`
+			} `json:"functions"`
+		}
+		if err := json.Unmarshal([]byte(st.Return()), &manifest); err != nil {
+			return fmt.Errorf("return is not JSON: %v", err)
+		}
+		for _, f := range manifest.Functions {
+			if f.Name == name {
+				if f.Label != want {
+					return fmt.Errorf("%s label = %q, want %q", name, f.Label, want)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("%s not in manifest", name)
+	}
+}
+
+// TestDisplayManifestLocaleFallback 测试按语言返回显示清单：完全匹配、同语言回退、回退到 en-US
+func TestDisplayManifestLocaleFallback(t *testing.T) {
+	s := newMutualAidScenario(t)
+	for locale, want := range map[string]string{
+		`"zh-CN"`: "批准成员",
+		`"zh-TW"`: "批准成员",
+		`"en-US"`: "Approve member",
+		`"fr-FR"`: "Approve member",
+		`""`:      "Approve member",
+	} {
+		s.As(fixtures.Alice()).Call("GetDisplayManifest", `{"locale":`+locale+`}`).
+			ExpectSuccess().Expect(expectFunctionLabel("ApproveMember", want))
+	}
+}
+
+// TestDisplayAmountHintsReferenceTokenField 测试每个带金额提示的 uint64 参数都引用真实存在的代币字段
+func TestDisplayAmountHintsReferenceTokenField(t *testing.T) {
+	if err := framework.ValidateDisplayManifest(); err != nil {
+		t.Fatalf("ValidateDisplayManifest() error = %v", err)
+	}
+
+	s := newMutualAidScenario(t)
+	amounts := 0
+	for _, f := range framework.FunctionManifests() {
+		params := map[string]framework.FunctionParam{}
+		for _, p := range f.Params {
+			params[p.Name] = p
+		}
+		for _, p := range f.Params {
+			if p.Type != "uint64" || p.Hint.Kind != framework.HINT_AMOUNT {
+				continue
+			}
+			amounts++
+			ref := p.Hint.TokenRef
+			view, field, isView := strings.Cut(ref, ".")
+			if !isView {
+				if tokenParam, ok := params[ref]; !ok || tokenParam.Type != "string" {
+					t.Errorf("%s.%s: token_ref %q is not a string parameter", f.Name, p.Name, ref)
+				}
+				continue
+			}
+
+			// 以计划参数调用视图函数，代币字段须存在且为字符串
+			if _, ok := mutualAidExports[view]; !ok {
+				t.Fatalf("%s.%s: view %s not callable in scenario", f.Name, p.Name, view)
+			}
+			s.As(fixtures.Alice()).Call(view, `{"plan_id":"`+scenarioPlanID+`"}`).ExpectSuccess().
+				Expect(func(st *fwtesting.Step) error {
+					var result map[string]interface{}
+					if err := json.Unmarshal([]byte(st.Return()), &result); err != nil {
+						return fmt.Errorf("return is not JSON: %v", err)
+					}
+					if _, ok := result[field].(string); !ok {
+						return fmt.Errorf("%s.%s: %s returns no string field %q", f.Name, p.Name, view, field)
+					}
+					return nil
+				})
+		}
+	}
+	if amounts == 0 {
+		t.Fatal("no AMOUNT hints registered")
+	}
+}
@@ -1,5 +1,3 @@
-//go:build tinygo || (js && wasm)
-
 // Package main 提供互助险（类似相互宝）业务的生产级合约。
 //
 // # 业务模型
@@ -15,31 +13,63 @@
 //  5. 轮次结算：定期结算已批准的案件，计算人均分摊额
 //  6. 费用缴纳：成员缴纳分摊费用到资金池
 //  7. 理赔给付：从资金池向受益人支付理赔款
+//  8. 计划终结：operator 在轮次结算、案件给付完毕后终结计划，资金池结余分还活跃成员
 //
 // # 设计目标
 //
 // - 完整的链上状态管理（plan_config、member、claim、round等）
+// - 一个合约承载多个计划：状态按 plan_id 隔离，计划之间的成员、轮次与案件互不干扰（见「计划命名空间」）
 // - 基于 operator 的权限控制
-// - 成员生命周期管理（PENDING/ACTIVE/EXITED/BLACKLISTED）
-// - 理赔案件状态机（SUBMITTED/UNDER_REVIEW/APPROVED/REJECTED/PAID）
+// - 成员生命周期管理（PENDING/ACTIVE/SUSPENDED/EXITED/BLACKLISTED）
+// - 理赔案件状态机（SUBMITTED/UNDER_REVIEW/APPROVED/REJECTED/PAID/CANCELLED）
 // - 轮次结算与分摊账本
 // - WES ISPC 特性：写操作同步返回业务结果，无需二次查询
 // - 查询接口支持：提供完整的链上数据查询能力
 //
 // # 状态管理
 //
-// 合约使用 WES EUTXO 模型的状态输出机制，通过 StateOutput 持久化以下状态：
-//   - plan_config: 计划配置（保障金额、服务费率、结算周期等）
-//   - operator: 运营方地址
-//   - member_{address}: 成员信息（状态、缴费记录、领取记录等）
-//   - claim_{claim_id}: 理赔案件（申请人、被保人、状态、金额等）
-//   - round_{round_id}: 结算轮次（周期、总给付额、人均分摊等）
-//   - member_round_due_{address}_{round_id}: 成员轮次应缴记录
-//   - member_month_stat_{address}_{yearMonth}: 成员月度统计（用于月度上限控制）
+// 合约使用 WES EUTXO 模型的状态输出机制，通过 StateOutput 持久化以下状态（均按 plan_id 隔离）：
+//   - plan:{plan_id}:plan_config: 计划配置（保障金额、服务费率、结算周期等）
+//   - plan:{plan_id}:operator: 运营方地址
+//   - role:plan:{plan_id}:operator_admin: operator_admin 地址
+//   - role_pending:plan:{plan_id}:{role}: 待接受的角色密钥轮换
+//   - index:role_key_audit:plan:{plan_id}:{role}:{seq}: 已完成的角色密钥轮换审计记录
+//   - plan:{plan_id}:member_{address}: 成员信息（状态、缴费记录、领取记录等）
+//   - plan:{plan_id}:claim:{claim_id}: 理赔案件（申请人、被保人、状态、金额等）
+//   - plan:{plan_id}:round:{round_id}: 结算轮次（周期、总给付额、人均分摊等）
+//   - plan:{plan_id}:member_round_due_{address}:{round_id}: 成员轮次应缴记录
+//   - plan:{plan_id}:member_month_stat_{address}_{yearMonth}: 成员月度统计（用于月度上限控制）
+//   - plan:{plan_id}:member_cap_{address}: 成员月度分摊上限覆盖（优先于计划默认上限）
+//   - plan:{plan_id}:settling_round_id: 缴费期轮次ID（AdvanceRound 维护）
+//   - plan:{plan_id}:round_arrears:{round_id}: 轮次关闭时记录的欠费总额
+//   - plan:{plan_id}:tier_multiplier_{tier}: 档位分摊系数（bp，未配置为1倍）
+//   - plan:{plan_id}:member_count_tier_{tier}: 档位活跃成员数（用于按系数加权分摊）
+//   - plan:{plan_id}:round_claims:{round_id}: 轮次已批准案件索引（ReviewClaim 批准时追加）
+//   - plan:{plan_id}:member_activation_seq: 成员激活序号（轮次快照引用）
+//   - plan:{plan_id}:round_snapshot:{round_id}: 轮次开启时的活跃成员数与分摊权重快照
+//   - plan:{plan_id}:fee_adjustment: 服务费调整配置（FIXED / CLAIMS_RATIO 及费率区间）
+//   - plan:{plan_id}:rounding_config: 人均分摊取整配置（UP / NEAREST / DOWN、取整位数、是否结转）
+//   - plan:{plan_id}:rounding_carry: 累计取整余额（有符号，正数为多收的盈余，负数为少收的缺口）
+//   - plan:{plan_id}:cumulative_collected / plan:{plan_id}:cumulative_paid: 累计分摊与累计给付（用于计算历史赔付率）
+//   - plan:{plan_id}:members_all_{page} / plan:{plan_id}:members_all_count: 成员索引（按加入顺序分页）
+//   - plan:{plan_id}:members_active_{page} / plan:{plan_id}:members_active_count / plan:{plan_id}:members_active_pos_{address}: 活跃成员集合（ApproveMember / ResumeMember 加入，Exit / SuspendMember / BlacklistMember 移除）
+//   - plan:{plan_id}:offchain_contribution_{reference_hash}: 线下缴费登记记录（成员、轮次、金额、对账状态）
+//   - plan:{plan_id}:round_paid:{round_id}: 轮次已缴金额的链上/线下拆分
+//   - plan:{plan_id}:cumulative_collected_offchain: 累计分摊中线下登记的金额
+//   - index:claim_evidence:{plan_id}:{claim_id}:{address}:{seq}: 案件补充材料（按调用者分区，受索引配额限制）
+//   - index:event_log:*:{seq} / index:event_log:{event}:{seq}: 理赔案件事件日志（所有计划共用，事件字段含 plan_id，见 QueryEventLog）
+//   - plan:{plan_id}:coverage_categories: 保障类别配置（单次给付上限、年度累计上限、等待期）
+//   - plan:{plan_id}:claim_category:{claim_id}: 案件所属类别与出险年份
+//   - plan:{plan_id}:category_usage_{address}_{category_id}_{year}: 被保人类别年度累计额度（已批准、已给付）
+//   - plan:{plan_id}:member_exclusions_{address}: 成员类别除外（类别、原因、设置时间）
+//   - plan:{plan_id}:plan_status: 计划状态（FinalizePlan 写入 FINALIZED，之后拒绝一切写操作）
+//   - plan:{plan_id}:claims_approved_unpaid: 已批准但尚未给付的案件数（FinalizePlan 的前置条件）
 //
 // # 权限控制
 //
-// - operator: 由 Initialize 时调用者地址设置，拥有审核成员、审核案件、开启/结算轮次、给付等权限
+// - operator: 每个计划各自的运营方，由该计划 Initialize 时调用者地址设置，拥有审核成员、审核案件、开启/结算轮次、给付等权限
+// - operator_admin: 由 Initialize 设置（默认为调用者），可在 operator 密钥泄露时紧急轮换 operator
+// - operator 密钥轮换：RotateRoleKey 发起、新地址 AcceptRoleKey 接受，待接受期间新旧 operator 均有效
 // - 普通成员: 可以加入计划、提交案件、缴纳分摊费用、退出计划
 //
 // # 资金流转
@@ -62,6 +92,7 @@ package main
 
 import (
 	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/codec"
 	"github.com/weisyn/contract-sdk-go/helpers/market"
 )
 
@@ -74,95 +105,173 @@ type MutualAidContract struct {
 // 常量定义
 // ================================================================================================
 
-// 成员状态常量
-//
-// 状态转换流程：
-//
-//	PENDING -> ACTIVE (通过 ApproveMember)
-//	ACTIVE -> EXITED (通过 Exit)
-//	ACTIVE -> SUSPENDED (运营方暂停，暂未实现)
-//	ACTIVE -> BLACKLISTED (运营方拉黑，暂未实现)
-const (
-	// MEMBER_STATUS_PENDING 待审核：成员已申请加入，等待 operator 审核
-	MEMBER_STATUS_PENDING = "PENDING"
-	// MEMBER_STATUS_ACTIVE 活跃：成员已激活，可以提交案件和缴纳分摊费用
-	MEMBER_STATUS_ACTIVE = "ACTIVE"
-	// MEMBER_STATUS_SUSPENDED 暂停：成员被临时暂停，不能提交案件但仍需缴纳费用
-	MEMBER_STATUS_SUSPENDED = "SUSPENDED"
-	// MEMBER_STATUS_EXITED 已退出：成员主动退出计划，不再参与分摊
-	MEMBER_STATUS_EXITED = "EXITED"
-	// MEMBER_STATUS_BLACKLISTED 黑名单：成员因违规被拉黑，不能参与任何操作
-	MEMBER_STATUS_BLACKLISTED = "BLACKLISTED"
-)
-
-// 理赔案件状态常量
-//
-// 状态转换流程：
-//
-//	SUBMITTED -> UNDER_REVIEW (审核中，暂未使用)
-//	SUBMITTED/UNDER_REVIEW -> APPROVED (通过 ReviewClaim 批准)
-//	SUBMITTED/UNDER_REVIEW -> REJECTED (通过 ReviewClaim 拒绝)
-//	APPROVED -> PAID (通过 Payout 给付)
-const (
-	// CLAIM_STATUS_SUBMITTED 已提交：成员已提交理赔申请，等待审核
-	CLAIM_STATUS_SUBMITTED = "SUBMITTED"
-	// CLAIM_STATUS_UNDER_REVIEW 审核中：案件正在审核中（当前实现中未使用）
-	CLAIM_STATUS_UNDER_REVIEW = "UNDER_REVIEW"
-	// CLAIM_STATUS_APPROVED 已批准：案件已通过审核，等待给付
-	CLAIM_STATUS_APPROVED = "APPROVED"
-	// CLAIM_STATUS_REJECTED 已拒绝：案件审核未通过
-	CLAIM_STATUS_REJECTED = "REJECTED"
-	// CLAIM_STATUS_PAID 已给付：理赔款已支付给受益人
-	CLAIM_STATUS_PAID = "PAID"
-	// CLAIM_STATUS_CANCELLED 已取消：案件被取消（暂未实现）
-	CLAIM_STATUS_CANCELLED = "CANCELLED"
-)
-
-// 轮次状态常量
-//
-// 状态转换流程：
-//
-//	OPEN -> SETTLED (通过 SettleRound 结算)
-//	SETTLED -> CLOSED (通过 CloseRound 关闭)
-const (
-	// ROUND_STATUS_OPEN 开启：轮次已开启，可以结算案件
-	ROUND_STATUS_OPEN = "OPEN"
-	// ROUND_STATUS_SETTLED 已结算：轮次已结算，计算出人均分摊额，成员可以缴费
-	ROUND_STATUS_SETTLED = "SETTLED"
-	// ROUND_STATUS_CLOSED 已关闭：轮次已关闭，未缴费的成员记录为欠费
-	ROUND_STATUS_CLOSED = "CLOSED"
-)
-
-// 审核决策常量
-//
-// 用于 ReviewClaim 函数，表示 operator 对案件的审核决定
-const (
-	// DECISION_APPROVE 批准：案件通过审核，可以给付
-	DECISION_APPROVE = "APPROVE"
-	// DECISION_REJECT 拒绝：案件未通过审核
-	DECISION_REJECT = "REJECT"
-)
-
 // 状态ID前缀常量
 //
 // 用于构建链上状态的唯一标识符（StateOutput 的 key）
 const (
 	// STATE_PLAN_CONFIG 计划配置状态ID
 	STATE_PLAN_CONFIG = "plan_config"
-	// STATE_OPERATOR 运营方地址状态ID
+	// STATE_OPERATOR 运营方地址状态ID（operator 角色的持有者，见 ROLE_OPERATOR）
 	STATE_OPERATOR = "operator"
-	// STATE_MEMBER_PREFIX 成员状态ID前缀，完整格式：member_{address}
+	// STATE_MEMBER_PREFIX 成员状态ID前缀，完整格式：plan:{plan_id}:member_{address}
 	STATE_MEMBER_PREFIX = "member_"
-	// STATE_CLAIM_PREFIX 理赔案件状态ID前缀，完整格式：claim_{claim_id}
-	STATE_CLAIM_PREFIX = "claim_"
-	// STATE_ROUND_PREFIX 轮次状态ID前缀，完整格式：round_{round_id}
-	STATE_ROUND_PREFIX = "round_"
+	// STATE_CLAIM_PREFIX 理赔案件状态ID前缀，完整格式：plan:{plan_id}:claim:{claim_id}
+	STATE_CLAIM_PREFIX = "claim:"
+	// STATE_ROUND_PREFIX 轮次状态ID前缀，完整格式：plan:{plan_id}:round:{round_id}
+	STATE_ROUND_PREFIX = "round:"
 	// STATE_MEMBER_COUNT 活跃成员数状态ID
 	STATE_MEMBER_COUNT = "member_count_active"
 	// STATE_CURRENT_ROUND 当前轮次ID状态ID
 	STATE_CURRENT_ROUND = "current_round_id"
+	// STATE_MEMBER_CAP_PREFIX 成员月度上限覆盖状态ID前缀，完整格式：plan:{plan_id}:member_cap_{address}
+	STATE_MEMBER_CAP_PREFIX = "member_cap_"
+	// STATE_SETTLING_ROUND 缴费期轮次ID状态ID（已结算、等待成员缴费的轮次）
+	STATE_SETTLING_ROUND = "settling_round_id"
+	// STATE_ROUND_ARREARS_PREFIX 轮次欠费总额状态ID前缀，完整格式：plan:{plan_id}:round_arrears:{round_id}
+	STATE_ROUND_ARREARS_PREFIX = "round_arrears:"
+	// STATE_TIER_MULTIPLIER_PREFIX 档位分摊系数状态ID前缀，完整格式：plan:{plan_id}:tier_multiplier_{tier}
+	STATE_TIER_MULTIPLIER_PREFIX = "tier_multiplier_"
+	// STATE_TIER_COUNT_PREFIX 档位活跃成员数状态ID前缀，完整格式：plan:{plan_id}:member_count_tier_{tier}
+	STATE_TIER_COUNT_PREFIX = "member_count_tier_"
+	// STATE_ROUND_CLAIMS_PREFIX 轮次案件索引状态ID前缀，完整格式：plan:{plan_id}:round_claims:{round_id}
+	STATE_ROUND_CLAIMS_PREFIX = "round_claims:"
+	// STATE_ACTIVATION_SEQ 成员激活序号状态ID（每次 ApproveMember 递增）
+	STATE_ACTIVATION_SEQ = "member_activation_seq"
+	// STATE_ROUND_SNAPSHOT_PREFIX 轮次成员快照状态ID前缀，完整格式：plan:{plan_id}:round_snapshot:{round_id}
+	STATE_ROUND_SNAPSHOT_PREFIX = "round_snapshot:"
+	// STATE_FEE_ADJUSTMENT 服务费调整配置状态ID（模式与费率区间）
+	STATE_FEE_ADJUSTMENT = "fee_adjustment"
+	// STATE_CUMULATIVE_COLLECTED 累计分摊缴费总额状态ID
+	STATE_CUMULATIVE_COLLECTED = "cumulative_collected"
+	// STATE_CUMULATIVE_PAID 累计理赔给付总额状态ID
+	STATE_CUMULATIVE_PAID = "cumulative_paid"
+	// STATE_MEMBERS_ALL_PREFIX 成员索引分页状态ID前缀，完整格式：plan:{plan_id}:members_all_{page}
+	STATE_MEMBERS_ALL_PREFIX = "members_all_"
+	// STATE_MEMBERS_ALL_COUNT 成员索引中的成员总数（含所有状态）
+	STATE_MEMBERS_ALL_COUNT = "members_all_count"
+	// STATE_MEMBERS_ACTIVE_PREFIX 活跃成员集合分页状态ID前缀，完整格式：plan:{plan_id}:members_active_{page}
+	STATE_MEMBERS_ACTIVE_PREFIX = "members_active_"
+	// STATE_MEMBERS_ACTIVE_COUNT 活跃成员集合中的成员数
+	STATE_MEMBERS_ACTIVE_COUNT = "members_active_count"
+	// STATE_MEMBERS_ACTIVE_POS_PREFIX 成员在活跃集合中的位置（序号+1，0 表示不在集合中），完整格式：plan:{plan_id}:members_active_pos_{address}
+	STATE_MEMBERS_ACTIVE_POS_PREFIX = "members_active_pos_"
+	// STATE_OFFCHAIN_PREFIX 线下缴费登记状态ID前缀，完整格式：plan:{plan_id}:offchain_contribution_{reference_hash}
+	STATE_OFFCHAIN_PREFIX = "offchain_contribution_"
+	// STATE_ROUND_PAID_PREFIX 轮次缴费拆分状态ID前缀，完整格式：plan:{plan_id}:round_paid:{round_id}
+	STATE_ROUND_PAID_PREFIX = "round_paid:"
+	// STATE_CUMULATIVE_OFFCHAIN 累计分摊中线下登记的金额状态ID（cumulative_collected 的组成部分）
+	STATE_CUMULATIVE_OFFCHAIN = "cumulative_collected_offchain"
+	// STATE_ROUNDING_CONFIG 人均分摊取整配置状态ID（取整方向、取整位数、是否结转）
+	STATE_ROUNDING_CONFIG = "rounding_config"
+	// STATE_ROUNDING_CARRY 累计取整余额状态ID（int64 补码，8字节大端）
+	STATE_ROUNDING_CARRY = "rounding_carry"
+	// STATE_COVERAGE_CATEGORIES 保障类别配置状态ID（Initialize 时写入）
+	STATE_COVERAGE_CATEGORIES = "coverage_categories"
+	// STATE_CLAIM_CATEGORY_PREFIX 案件类别状态ID前缀，完整格式：plan:{plan_id}:claim_category:{claim_id}
+	STATE_CLAIM_CATEGORY_PREFIX = "claim_category:"
+	// STATE_CATEGORY_USAGE_PREFIX 类别年度累计额度状态ID前缀，完整格式：plan:{plan_id}:category_usage_{address}_{category_id}_{year}
+	STATE_CATEGORY_USAGE_PREFIX = "category_usage_"
+	// STATE_MEMBER_EXCLUSIONS_PREFIX 成员类别除外状态ID前缀，完整格式：plan:{plan_id}:member_exclusions_{address}
+	STATE_MEMBER_EXCLUSIONS_PREFIX = "member_exclusions_"
+	// STATE_PLAN_STATUS 计划状态状态ID（FinalizePlan 写入 FINALIZED，未写入时为 ACTIVE）
+	STATE_PLAN_STATUS = "plan_status"
+	// STATE_CLAIMS_APPROVED_UNPAID 已批准但尚未给付的案件数状态ID（审核批准时递增，Payout 时递减）
+	STATE_CLAIMS_APPROVED_UNPAID = "claims_approved_unpaid"
 )
 
+// ================================================================================================
+// 计划命名空间
+// ================================================================================================
+//
+// 一个合约可同时承载多个互助计划，计划之间的成员、案件、轮次与计数互不干扰：
+//   - 计划内的全部状态ID与角色名称以计划命名空间开头：plan:{plan_id}:{key}，
+//     如 plan:{plan_id}:plan_config、plan:{plan_id}:member_{address}、plan:{plan_id}:round:{round_id}
+//   - operator / operator_admin 角色按计划登记：plan:{plan_id}:operator（持有者状态同名）、
+//     plan:{plan_id}:operator_admin（持有者状态 role:plan:{plan_id}:operator_admin）
+//
+// plan_id 只允许字母、数字、下划线与连字符（不含 ':'），命名空间在第一个 ':' 处结束，
+// 不同计划的状态ID不会因拼接而重合（如计划 X 与 cap_X、P 与 claims_P）。
+//
+// 计划内以记录ID结尾的状态ID同样以 ':' 分隔前缀与ID（如 plan:{plan_id}:claim:{claim_id}、
+// plan:{plan_id}:claim_category:{claim_id}），claim_id 与 plan_id 字符集相同、不含 ':'，
+// 一条记录的状态ID不会与另一种记录的状态ID重合（如案件 category_X 与案件 X 的类别记录）。
+//
+// 每个导出函数与视图函数先以参数中的 plan_id 调用 usePlan，之后的状态读写与权限检查都落在该计划下。
+
+// PLAN_NAMESPACE_PREFIX 计划命名空间前缀
+const PLAN_NAMESPACE_PREFIX = "plan:"
+
+// MAX_PLAN_ID_LENGTH plan_id 最大长度（与计划配置中 planID 字段长度一致）
+const MAX_PLAN_ID_LENGTH = 32
+
+// MAX_CLAIM_ID_LENGTH claim_id 最大长度（与案件记录中 claimID 字段长度一致）
+const MAX_CLAIM_ID_LENGTH = 32
+
+// MAX_ROUND_ID_LENGTH round_id 最大长度（与轮次记录中 roundID 字段长度一致）
+const MAX_ROUND_ID_LENGTH = 32
+
+// activePlanID 本次调用操作的计划ID（由 usePlan 设置）
+var activePlanID string
+
+// usePlan 校验并选定本次调用操作的计划，登记该计划的 operator / operator_admin 角色
+//
+// 返回：framework.SUCCESS，或 plan_id 为空、超过 MAX_PLAN_ID_LENGTH、含字母数字下划线连字符以外的字符时
+// framework.ERROR_INVALID_PARAMS（不选定计划）
+func usePlan(planID string) uint32 {
+	if !validPlanID(planID) {
+		activePlanID = ""
+		return framework.ERROR_INVALID_PARAMS
+	}
+	activePlanID = planID
+	framework.RegisterRole(framework.RoleConfig{Role: planRole(ROLE_OPERATOR), StateID: planKey(STATE_OPERATOR), AdminRole: planRole(ROLE_OPERATOR_ADMIN)})
+	framework.RegisterRole(framework.RoleConfig{Role: planRole(ROLE_OPERATOR_ADMIN)})
+	return framework.SUCCESS
+}
+
+// validPlanID plan_id 非空、不超过 MAX_PLAN_ID_LENGTH，且只含字母、数字、'_' 与 '-'
+func validPlanID(planID string) bool {
+	return validKeySegment(planID, MAX_PLAN_ID_LENGTH)
+}
+
+// validClaimID claim_id 非空、不超过 MAX_CLAIM_ID_LENGTH，且只含字母、数字、'_' 与 '-'
+func validClaimID(claimID string) bool {
+	return validKeySegment(claimID, MAX_CLAIM_ID_LENGTH)
+}
+
+// validRoundID round_id 非空、不超过 MAX_ROUND_ID_LENGTH，且只含字母、数字、'_' 与 '-'
+func validRoundID(roundID string) bool {
+	return validKeySegment(roundID, MAX_ROUND_ID_LENGTH)
+}
+
+// validKeySegment 状态ID片段非空、不超过 maxLen，且只含字母、数字、'_' 与 '-'（不含分隔符 ':'）
+func validKeySegment(id string, maxLen int) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// planKey 当前计划的固定状态ID，格式：plan:{plan_id}:{key}
+func planKey(key string) string {
+	return PLAN_NAMESPACE_PREFIX + activePlanID + ":" + key
+}
+
+// planPrefix 当前计划的状态ID前缀，格式：plan:{plan_id}:{prefix}
+func planPrefix(prefix string) string {
+	return PLAN_NAMESPACE_PREFIX + activePlanID + ":" + prefix
+}
+
+// planRole 当前计划的角色名称，格式：plan:{plan_id}:{role}
+func planRole(role string) string {
+	return PLAN_NAMESPACE_PREFIX + activePlanID + ":" + role
+}
+
 // ================================================================================================
 // 状态结构编码/解码
 // ================================================================================================
@@ -170,11 +279,27 @@ const (
 // 由于 WES 合约状态存储为字节数组，需要将复杂数据结构序列化为字节数组。
 // 本合约采用固定长度编码方式，便于快速解码和节省存储空间。
 //
+// 计划配置、成员、案件、轮次、线下缴费登记、月度统计与服务费调整记录的布局由 framework/codec 的
+// FixedCodec 声明（planConfigCodec 等），偏移与总长度由字段登记顺序自动计算；
+// 含 1 字节标志位的应缴记录与取整配置仍手写编码。
+//
 // 编码格式说明：
-//   - 字符串字段：固定长度，不足部分用 0x00 填充，解码时使用 trimNull 去除
-//   - 数值字段：使用 uint64ToBytes 转换为 8 字节大端序
+//   - 字符串字段：固定长度，不足部分用 0x00 填充，解码时使用 framework.TrimNull 去除
+//   - 数值字段：使用 framework.Uint64ToBytes 转换为 8 字节大端序
 //   - 布尔字段：使用 1 字节，0 表示 false，1 表示 true
 
+// planConfigCodec 计划配置布局（176字节），字段顺序见 encodePlanConfig
+var planConfigCodec = codec.NewFixedCodec().
+	StringField(32). // planID
+	StringField(64). // name
+	StringField(32). // tokenID
+	Uint64Field().   // coverageAmount
+	Uint64Field().   // serviceFeeBP
+	Uint64Field().   // settlementPeriod
+	Uint64Field().   // waitingPeriod
+	Uint64Field().   // minMembers
+	Uint64Field()    // monthlyCapPerMember
+
 // encodePlanConfig 编码计划配置信息
 //
 // 参数说明：
@@ -195,17 +320,7 @@ const (
 //	planID(32) + name(64) + tokenID(32) + coverageAmount(8) + serviceFeeBP(8) +
 //	settlementPeriod(8) + waitingPeriod(8) + minMembers(8) + monthlyCapPerMember(8) = 176字节
 func encodePlanConfig(planID, name, tokenID string, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember uint64) []byte {
-	result := make([]byte, 176)
-	copy(result[0:32], []byte(planID)[:min(32, len(planID))])
-	copy(result[32:96], []byte(name)[:min(64, len(name))])
-	copy(result[96:128], []byte(tokenID)[:min(32, len(tokenID))])
-	copy(result[128:136], uint64ToBytes(coverageAmount))
-	copy(result[136:144], uint64ToBytes(serviceFeeBP))
-	copy(result[144:152], uint64ToBytes(settlementPeriod))
-	copy(result[152:160], uint64ToBytes(waitingPeriod))
-	copy(result[160:168], uint64ToBytes(minMembers))
-	copy(result[168:176], uint64ToBytes(monthlyCapPerMember))
-	return result
+	return planConfigCodec.Encode(planID, name, tokenID, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember)
 }
 
 // decodePlanConfig 解码计划配置信息
@@ -217,21 +332,21 @@ func encodePlanConfig(planID, name, tokenID string, coverageAmount, serviceFeeBP
 //
 // 如果数据长度不足176字节，返回零值
 func decodePlanConfig(data []byte) (planID, name, tokenID string, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember uint64) {
-	if len(data) < 176 {
-		return "", "", "", 0, 0, 0, 0, 0, 0
-	}
-	planID = string(trimNull(data[0:32]))
-	name = string(trimNull(data[32:96]))
-	tokenID = string(trimNull(data[96:128]))
-	coverageAmount = bytesToUint64(data[128:136])
-	serviceFeeBP = bytesToUint64(data[136:144])
-	settlementPeriod = bytesToUint64(data[144:152])
-	waitingPeriod = bytesToUint64(data[152:160])
-	minMembers = bytesToUint64(data[160:168])
-	monthlyCapPerMember = bytesToUint64(data[168:176])
-	return
+	rec := planConfigCodec.Decode(data)
+	return rec.String(0), rec.String(1), rec.String(2), rec.Uint64(3), rec.Uint64(4), rec.Uint64(5), rec.Uint64(6), rec.Uint64(7), rec.Uint64(8)
 }
 
+// memberCodec 成员信息布局（72字节），tier / activationSeq 为后续追加的字段，旧记录缺少时为 0
+var memberCodec = codec.NewFixedCodec().
+	StringField(16).          // status
+	Uint64Field().            // joinTime
+	Uint64Field().            // totalPaid
+	Uint64Field().            // totalReceived
+	Uint64Field().            // arrearsAmount
+	Uint64Field().            // lastSettledRound
+	Uint64Field().Optional(). // tier
+	Uint64Field().Optional()  // activationSeq
+
 // encodeMember 编码成员信息
 //
 // 参数说明：
@@ -241,44 +356,45 @@ func decodePlanConfig(data []byte) (planID, name, tokenID string, coverageAmount
 //   - totalReceived: 累计领取总额
 //   - arrearsAmount: 欠费金额
 //   - lastSettledRound: 最后结算的轮次ID（数值型，简化实现）
+//   - tier: 保障档位（0 ~ MAX_TIERS-1），决定分摊系数
+//   - activationSeq: 激活序号（ApproveMember 时分配），用于判断成员是否在轮次快照内
 //
-// 返回：64字节的编码数据
+// 返回：72字节的编码数据
 //
 // 编码格式：
 //
-//	status(16) + joinTime(8) + totalPaid(8) + totalReceived(8) + arrearsAmount(8) + lastSettledRound(8) = 64字节
-func encodeMember(status string, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound uint64) []byte {
-	result := make([]byte, 64)
-	copy(result[0:16], []byte(status)[:min(16, len(status))])
-	copy(result[16:24], uint64ToBytes(joinTime))
-	copy(result[24:32], uint64ToBytes(totalPaid))
-	copy(result[32:40], uint64ToBytes(totalReceived))
-	copy(result[40:48], uint64ToBytes(arrearsAmount))
-	copy(result[48:56], uint64ToBytes(lastSettledRound))
-	return result
+//	status(16) + joinTime(8) + totalPaid(8) + totalReceived(8) + arrearsAmount(8) + lastSettledRound(8) + tier(8) + activationSeq(8) = 72字节
+func encodeMember(status string, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq uint64) []byte {
+	return memberCodec.Encode(status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq)
 }
 
 // decodeMember 解码成员信息
 //
 // 参数：
-//   - data: 64字节的编码数据
+//   - data: 72字节的编码数据
 //
 // 返回：解码后的成员信息字段
 //
-// 如果数据长度不足64字节，返回零值
-func decodeMember(data []byte) (status string, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound uint64) {
-	if len(data) < 56 {
-		return "", 0, 0, 0, 0, 0
-	}
-	status = string(trimNull(data[0:16]))
-	joinTime = bytesToUint64(data[16:24])
-	totalPaid = bytesToUint64(data[24:32])
-	totalReceived = bytesToUint64(data[32:40])
-	arrearsAmount = bytesToUint64(data[40:48])
-	lastSettledRound = bytesToUint64(data[48:56])
-	return
+// 如果数据长度不足56字节（memberCodec.MinSize()），返回零值；不含 tier / activationSeq 字段的旧记录对应字段为 0
+func decodeMember(data []byte) (status string, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq uint64) {
+	rec := memberCodec.Decode(data)
+	return rec.String(0), rec.Uint64(1), rec.Uint64(2), rec.Uint64(3), rec.Uint64(4), rec.Uint64(5), rec.Uint64(6), rec.Uint64(7)
 }
 
+// claimCodec 理赔案件布局（304字节），applicant / insured 按20字节字符串存储
+var claimCodec = codec.NewFixedCodec().
+	StringField(32). // planID
+	StringField(32). // claimID
+	StringField(20). // applicant
+	StringField(20). // insured
+	StringField(16). // status
+	StringField(32). // roundID
+	StringField(64). // evidenceHash
+	StringField(64). // investigationHash
+	Uint64Field().   // requestedAmount
+	Uint64Field().   // approvedAmount
+	Uint64Field()    // eventTime
+
 // encodeClaim 编码理赔案件信息
 //
 // 参数说明：
@@ -304,19 +420,7 @@ func decodeMember(data []byte) (status string, joinTime, totalPaid, totalReceive
 // 注意：applicant 和 insured 字段存储的是地址的20字节二进制数据（通过 string(addr.ToBytes()) 转换），
 // 解码后需要使用 addressBytesToString 转换为 Base58 格式用于 JSON 返回。
 func encodeClaim(planID, claimID, applicant, insured, status, roundID, evidenceHash, investigationHash string, requestedAmount, approvedAmount, eventTime uint64) []byte {
-	result := make([]byte, 304)
-	copy(result[0:32], []byte(planID)[:min(32, len(planID))])
-	copy(result[32:64], []byte(claimID)[:min(32, len(claimID))])
-	copy(result[64:84], []byte(applicant)[:min(20, len(applicant))])
-	copy(result[84:104], []byte(insured)[:min(20, len(insured))])
-	copy(result[104:120], []byte(status)[:min(16, len(status))])
-	copy(result[120:152], []byte(roundID)[:min(32, len(roundID))])
-	copy(result[152:216], []byte(evidenceHash)[:min(64, len(evidenceHash))])
-	copy(result[216:280], []byte(investigationHash)[:min(64, len(investigationHash))])
-	copy(result[280:288], uint64ToBytes(requestedAmount))
-	copy(result[288:296], uint64ToBytes(approvedAmount))
-	copy(result[296:304], uint64ToBytes(eventTime))
-	return result
+	return claimCodec.Encode(planID, claimID, applicant, insured, status, roundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime)
 }
 
 // decodeClaim 解码理赔案件信息
@@ -331,23 +435,23 @@ func encodeClaim(planID, claimID, applicant, insured, status, roundID, evidenceH
 // 注意：applicant 和 insured 返回的是20字节二进制数据的字符串表示，
 // 需要使用 addressBytesToString 转换为 Base58 格式。
 func decodeClaim(data []byte) (planID, claimID, applicant, insured, status, roundID, evidenceHash, investigationHash string, requestedAmount, approvedAmount, eventTime uint64) {
-	if len(data) < 304 {
-		return "", "", "", "", "", "", "", "", 0, 0, 0
-	}
-	planID = string(trimNull(data[0:32]))
-	claimID = string(trimNull(data[32:64]))
-	applicant = string(trimNull(data[64:84]))
-	insured = string(trimNull(data[84:104]))
-	status = string(trimNull(data[104:120]))
-	roundID = string(trimNull(data[120:152]))
-	evidenceHash = string(trimNull(data[152:216]))
-	investigationHash = string(trimNull(data[216:280]))
-	requestedAmount = bytesToUint64(data[280:288])
-	approvedAmount = bytesToUint64(data[288:296])
-	eventTime = bytesToUint64(data[296:304])
-	return
+	rec := claimCodec.Decode(data)
+	return rec.String(0), rec.String(1), rec.String(2), rec.String(3), rec.String(4), rec.String(5), rec.String(6), rec.String(7), rec.Uint64(8), rec.Uint64(9), rec.Uint64(10)
 }
 
+// roundCodec 轮次信息布局（136字节），snapshotSeq 为后续追加的字段，旧记录缺少时为 0
+var roundCodec = codec.NewFixedCodec().
+	StringField(32).         // planID
+	StringField(32).         // roundID
+	StringField(16).         // status
+	Uint64Field().           // periodStart
+	Uint64Field().           // periodEnd
+	Uint64Field().           // totalApprovedPayout
+	Uint64Field().           // totalServiceFee
+	Uint64Field().           // perCapitaContribution
+	Uint64Field().           // payersCount
+	Uint64Field().Optional() // snapshotSeq
+
 // encodeRound 编码轮次信息
 //
 // 参数说明：
@@ -360,49 +464,29 @@ func decodeClaim(data []byte) (planID, claimID, applicant, insured, status, roun
 //   - totalServiceFee: 该轮次总服务费
 //   - perCapitaContribution: 人均分摊额（向上取整）
 //   - payersCount: 已缴费人数（简化实现，未去重）
+//   - snapshotSeq: 轮次开启时的成员激活序号（快照引用，快照见 round_snapshot:{round_id}）
 //
-// 返回：128字节的编码数据
+// 返回：136字节的编码数据
 //
 // 编码格式：
 //
 //	planID(32) + roundID(32) + status(16) + periodStart(8) + periodEnd(8) +
-//	totalApprovedPayout(8) + totalServiceFee(8) + perCapitaContribution(8) + payersCount(8) = 128字节
-func encodeRound(planID, roundID, status string, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount uint64) []byte {
-	result := make([]byte, 128)
-	copy(result[0:32], []byte(planID)[:min(32, len(planID))])
-	copy(result[32:64], []byte(roundID)[:min(32, len(roundID))])
-	copy(result[64:80], []byte(status)[:min(16, len(status))])
-	copy(result[80:88], uint64ToBytes(periodStart))
-	copy(result[88:96], uint64ToBytes(periodEnd))
-	copy(result[96:104], uint64ToBytes(totalApprovedPayout))
-	copy(result[104:112], uint64ToBytes(totalServiceFee))
-	copy(result[112:120], uint64ToBytes(perCapitaContribution))
-	copy(result[120:128], uint64ToBytes(payersCount))
-	return result
+//	totalApprovedPayout(8) + totalServiceFee(8) + perCapitaContribution(8) + payersCount(8) + snapshotSeq(8) = 136字节
+func encodeRound(planID, roundID, status string, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq uint64) []byte {
+	return roundCodec.Encode(planID, roundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq)
 }
 
 // decodeRound 解码轮次信息
 //
 // 参数：
-//   - data: 128字节的编码数据
+//   - data: 136字节的编码数据
 //
 // 返回：解码后的轮次信息字段
 //
-// 如果数据长度不足128字节，返回零值
-func decodeRound(data []byte) (planID, roundID, status string, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount uint64) {
-	if len(data) < 128 {
-		return "", "", "", 0, 0, 0, 0, 0, 0
-	}
-	planID = string(trimNull(data[0:32]))
-	roundID = string(trimNull(data[32:64]))
-	status = string(trimNull(data[64:80]))
-	periodStart = bytesToUint64(data[80:88])
-	periodEnd = bytesToUint64(data[88:96])
-	totalApprovedPayout = bytesToUint64(data[96:104])
-	totalServiceFee = bytesToUint64(data[104:112])
-	perCapitaContribution = bytesToUint64(data[112:120])
-	payersCount = bytesToUint64(data[120:128])
-	return
+// 如果数据长度不足128字节（roundCodec.MinSize()），返回零值；不含 snapshotSeq 字段的旧记录 snapshotSeq 为 0
+func decodeRound(data []byte) (planID, roundID, status string, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq uint64) {
+	rec := roundCodec.Decode(data)
+	return rec.String(0), rec.String(1), rec.String(2), rec.Uint64(3), rec.Uint64(4), rec.Uint64(5), rec.Uint64(6), rec.Uint64(7), rec.Uint64(8), rec.Uint64(9)
 }
 
 // encodeMemberRoundDue 编码成员轮次应缴信息
@@ -410,45 +494,91 @@ func decodeRound(data []byte) (planID, roundID, status string, periodStart, peri
 // 用于记录每个成员在每个轮次的缴费情况。
 //
 // 参数说明：
-//   - dueAmount: 应缴金额（该轮次的人均分摊额）
-//   - paidAmount: 已缴金额
-//   - settled: 是否已结清（paidAmount >= dueAmount）
+//   - d.DueAmount: 应缴金额（该轮次的人均分摊额）
+//   - d.PaidAmount: 已缴金额（链上托管 + 线下登记）
+//   - d.Settled: 是否已结清（paidAmount >= dueAmount）
+//   - d.Flags: 标志位（DUE_FLAG_OFFCHAIN / DUE_FLAG_REVERSED）
+//   - d.OffchainPaid: 其中线下登记的金额
 //
-// 返回：17字节的编码数据
+// 返回：26字节的编码数据
 //
 // 编码格式：
 //
-//	dueAmount(8) + paidAmount(8) + settled(1) = 17字节
-func encodeMemberRoundDue(dueAmount, paidAmount uint64, settled bool) []byte {
-	result := make([]byte, 17)
-	copy(result[0:8], uint64ToBytes(dueAmount))
-	copy(result[8:16], uint64ToBytes(paidAmount))
-	if settled {
+//	dueAmount(8) + paidAmount(8) + settled(1) + flags(1) + offchainPaid(8) = 26字节
+func encodeMemberRoundDue(d roundDue) []byte {
+	result := make([]byte, 26)
+	copy(result[0:8], framework.Uint64ToBytes(d.DueAmount))
+	copy(result[8:16], framework.Uint64ToBytes(d.PaidAmount))
+	if d.Settled {
 		result[16] = 1
 	} else {
 		result[16] = 0
 	}
+	result[17] = d.Flags
+	copy(result[18:26], framework.Uint64ToBytes(d.OffchainPaid))
 	return result
 }
 
 // decodeMemberRoundDue 解码成员轮次应缴信息
 //
 // 参数：
-//   - data: 17字节的编码数据
+//   - data: 26字节的编码数据（兼容引入线下缴费前的17字节记录，flags 与线下金额为 0）
 //
-// 返回：解码后的应缴信息字段
+// 返回：解码后的应缴信息
 //
 // 如果数据长度不足17字节，返回零值
-func decodeMemberRoundDue(data []byte) (dueAmount, paidAmount uint64, settled bool) {
+func decodeMemberRoundDue(data []byte) roundDue {
+	var d roundDue
 	if len(data) < 17 {
-		return 0, 0, false
+		return d
+	}
+	d.DueAmount = framework.BytesToUint64(data[0:8])
+	d.PaidAmount = framework.BytesToUint64(data[8:16])
+	d.Settled = data[16] == 1
+	if len(data) >= 26 {
+		d.Flags = data[17]
+		d.OffchainPaid = framework.BytesToUint64(data[18:26])
 	}
-	dueAmount = bytesToUint64(data[0:8])
-	paidAmount = bytesToUint64(data[8:16])
-	settled = data[16] == 1
-	return
+	return d
+}
+
+// offchainEntryCodec 线下缴费登记布局（84字节）
+var offchainEntryCodec = codec.NewFixedCodec().
+	AddressField().  // member
+	StringField(32). // roundID
+	Uint64Field().   // amount
+	StringField(16). // status
+	Uint64Field()    // recordedAt
+
+// encodeOffchainEntry 编码线下缴费登记记录
+//
+// 参数说明：
+//   - member: 缴费成员地址
+//   - roundID: 轮次ID（最大32字节）
+//   - amount: 登记金额
+//   - status: 对账状态（RECORDED / VERIFIED / REVERSED）
+//   - recordedAt: 登记时间
+//
+// 编码格式：
+//
+//	member(20) + roundID(32) + amount(8) + status(16) + recordedAt(8) = 84字节
+func encodeOffchainEntry(member framework.Address, roundID string, amount uint64, status string, recordedAt uint64) []byte {
+	return offchainEntryCodec.Encode(member, roundID, amount, status, recordedAt)
+}
+
+// decodeOffchainEntry 解码线下缴费登记记录
+//
+// 如果数据长度不足84字节，返回零值
+func decodeOffchainEntry(data []byte) (member framework.Address, roundID string, amount uint64, status string, recordedAt uint64) {
+	rec := offchainEntryCodec.Decode(data)
+	return rec.Address(0), rec.String(1), rec.Uint64(2), rec.String(3), rec.Uint64(4)
 }
 
+// memberMonthStatCodec 成员月度统计布局（9字节）
+var memberMonthStatCodec = codec.NewFixedCodec().
+	Uint64Field(). // paidAmount
+	BoolField()    // capReached
+
 // encodeMemberMonthStat 编码成员月度统计信息
 //
 // 用于记录每个成员在每个自然月的缴费情况，用于月度分摊上限控制。
@@ -463,14 +593,7 @@ func decodeMemberRoundDue(data []byte) (dueAmount, paidAmount uint64, settled bo
 //
 //	paidAmount(8) + capReached(1) = 9字节
 func encodeMemberMonthStat(paidAmount uint64, capReached bool) []byte {
-	result := make([]byte, 9)
-	copy(result[0:8], uint64ToBytes(paidAmount))
-	if capReached {
-		result[8] = 1
-	} else {
-		result[8] = 0
-	}
-	return result
+	return memberMonthStatCodec.Encode(paidAmount, capReached)
 }
 
 // decodeMemberMonthStat 解码成员月度统计信息
@@ -482,82 +605,84 @@ func encodeMemberMonthStat(paidAmount uint64, capReached bool) []byte {
 //
 // 如果数据长度不足9字节，返回零值
 func decodeMemberMonthStat(data []byte) (paidAmount uint64, capReached bool) {
-	if len(data) < 9 {
-		return 0, false
-	}
-	paidAmount = bytesToUint64(data[0:8])
-	capReached = data[8] == 1
-	return
+	rec := memberMonthStatCodec.Decode(data)
+	return rec.Uint64(0), rec.Bool(1)
 }
 
-// ================================================================================================
-// 辅助函数
-// ================================================================================================
+// feeAdjustmentCodec 服务费调整配置布局（32字节）
+var feeAdjustmentCodec = codec.NewFixedCodec().
+	StringField(16). // mode
+	Uint64Field().   // minFeeBP
+	Uint64Field()    // maxFeeBP
 
-// uint64ToBytes 将 uint64 转换为 8 字节大端序字节数组
+// encodeFeeAdjustment 编码服务费调整配置
 //
-// 用于将数值字段编码到状态数据中。
+// 参数说明：
+//   - mode: 服务费模式（FIXED / CLAIMS_RATIO，最大16字节）
+//   - minFeeBP: 赔付率联动模式下的最低费率（bp）
+//   - maxFeeBP: 赔付率联动模式下的最高费率（bp）
 //
-// 参数：
-//   - n: 要转换的 uint64 值
+// 返回：32字节的编码数据
 //
-// 返回：8字节的字节数组（大端序）
-func uint64ToBytes(n uint64) []byte {
-	result := make([]byte, 8)
-	for i := 0; i < 8; i++ {
-		result[7-i] = byte(n >> (i * 8))
-	}
-	return result
+// 编码格式：
+//
+//	mode(16) + minFeeBP(8) + maxFeeBP(8) = 32字节
+func encodeFeeAdjustment(mode string, minFeeBP, maxFeeBP uint64) []byte {
+	return feeAdjustmentCodec.Encode(mode, minFeeBP, maxFeeBP)
 }
 
-// bytesToUint64 将 8 字节大端序字节数组转换为 uint64
+// decodeFeeAdjustment 解码服务费调整配置
 //
-// 用于从状态数据中解码数值字段。
-//
-// 参数：
-//   - b: 字节数组（至少8字节）
-//
-// 返回：解码后的 uint64 值
-//
-// 如果字节数组长度不足8字节，返回0
-func bytesToUint64(b []byte) uint64 {
-	if len(b) < 8 {
-		return 0
+// 如果数据长度不足32字节或未配置，返回 FIXED 模式
+func decodeFeeAdjustment(data []byte) (mode string, minFeeBP, maxFeeBP uint64) {
+	rec := feeAdjustmentCodec.Decode(data)
+	if !rec.Valid() {
+		return FEE_MODE_FIXED, 0, 0
 	}
-	var result uint64
-	for i := 0; i < 8; i++ {
-		result |= uint64(b[7-i]) << (i * 8)
+	mode = rec.String(0)
+	if mode == "" {
+		mode = FEE_MODE_FIXED
 	}
-	return result
+	return mode, rec.Uint64(1), rec.Uint64(2)
 }
 
-// min 返回两个整数中的较小值
+// encodeRoundingConfig 编码人均分摊取整配置
+//
+// 编码格式：
 //
-// 用于确保字符串字段不会超出固定长度限制。
-func min(a, b int) int {
-	if a < b {
-		return a
+//	mode(16) + decimals(8) + carryForward(8，1 表示结转) = 32字节
+func encodeRoundingConfig(cfg roundingConfig) []byte {
+	result := make([]byte, 32)
+	copy(result[0:16], []byte(cfg.Mode)[:min(16, len(cfg.Mode))])
+	copy(result[16:24], framework.Uint64ToBytes(cfg.Decimals))
+	if cfg.CarryForward {
+		copy(result[24:32], framework.Uint64ToBytes(1))
 	}
-	return b
+	return result
 }
 
-// trimNull 去除字节数组末尾的 null 字符（0x00）
+// decodeRoundingConfig 解码人均分摊取整配置
 //
-// 用于解码固定长度的字符串字段，去除填充的 null 字符。
-//
-// 参数：
-//   - b: 字节数组
-//
-// 返回：去除末尾 null 字符后的字节数组
-func trimNull(b []byte) []byte {
-	for i := 0; i < len(b); i++ {
-		if b[i] == 0 {
-			return b[:i]
-		}
+// 如果数据长度不足32字节或未配置，返回 defaultRounding（向上取整、不结转）
+func decodeRoundingConfig(data []byte) roundingConfig {
+	if len(data) < 32 {
+		return defaultRounding
 	}
-	return b
+	cfg := roundingConfig{
+		Mode:         string(framework.TrimNull(data[0:16])),
+		Decimals:     framework.BytesToUint64(data[16:24]),
+		CarryForward: framework.BytesToUint64(data[24:32]) == 1,
+	}
+	if !validRoundingMode(cfg.Mode) {
+		cfg.Mode = ROUNDING_MODE_UP
+	}
+	return cfg
 }
 
+// ================================================================================================
+// 辅助函数
+// ================================================================================================
+
 // checkOperator 检查当前调用者是否为计划的 operator
 //
 // 用于权限控制，确保只有 operator 可以执行管理操作（如审核成员、审核案件、结算轮次等）。
@@ -565,54 +690,103 @@ func trimNull(b []byte) []byte {
 // 返回：
 //   - true: 调用者是 operator
 //   - false: 调用者不是 operator 或 operator 未设置
+//
+// 注意：operator 密钥轮换待接受期间，当前 operator 与待接受的新地址均视为 operator（见 framework.HasRole）
 func checkOperator() bool {
-	operatorData, _ := framework.GetState(STATE_OPERATOR)
-	if len(operatorData) == 0 {
-		return false
+	return framework.HasRole(planRole(ROLE_OPERATOR), framework.GetCaller())
+}
+
+// loadPlanStatus 读取当前计划的状态（未写入时为 PLAN_STATUS_ACTIVE）
+func loadPlanStatus() string {
+	data, _ := framework.GetState(planKey(STATE_PLAN_STATUS))
+	if status := string(framework.TrimNull(data)); status != "" {
+		return status
 	}
-	caller := framework.GetCaller()
-	return string(operatorData) == string(caller.ToBytes())
+	return PLAN_STATUS_ACTIVE
+}
+
+// requirePlanActive 计划未初始化或已终结（FINALIZED）时拒绝写操作
+//
+// 除 Initialize 外的全部写入导出函数在选定计划后调用，未初始化的计划下不会写入任何状态；查询接口不受影响。
+//
+// 返回：framework.SUCCESS，计划配置不存在时 framework.ERROR_NOT_FOUND，计划已终结时 framework.ERROR_INVALID_STATE
+func requirePlanActive() uint32 {
+	if configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG)); len(configData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+	if loadPlanStatus() == PLAN_STATUS_FINALIZED {
+		return framework.ERROR_INVALID_STATE
+	}
+	return framework.SUCCESS
+}
+
+// adjustApprovedUnpaid 更新已批准但尚未给付的案件数：审核批准时增加 approved，给付时减少 paid（不低于 0）
+func adjustApprovedUnpaid(approved, paid uint64) uint32 {
+	data, _ := framework.GetState(planKey(STATE_CLAIMS_APPROVED_UNPAID))
+	count := framework.BytesToUint64(data) + approved
+	if count, ok := subChecked(count, paid); ok {
+		return appendVersionedState([]byte(planKey(STATE_CLAIMS_APPROVED_UNPAID)), framework.Uint64ToBytes(count))
+	}
+	return appendVersionedState([]byte(planKey(STATE_CLAIMS_APPROVED_UNPAID)), framework.Uint64ToBytes(0))
+}
+
+// requireActiveMember 读取成员记录并检查成员为 ACTIVE
+//
+// SubmitClaim、Exit、PayContribution / RecordOffchainContribution 共用，错误码一致：
+//   - 成员记录不存在：ERROR_NOT_FOUND
+//   - 状态不是 ACTIVE（PENDING / SUSPENDED / EXITED / BLACKLISTED）：ERROR_UNAUTHORIZED
+//
+// 返回：成员记录（供 decodeMember 解码）与错误码，检查通过时为 SUCCESS
+func requireActiveMember(addr framework.Address) (memberData []byte, errCode uint32) {
+	memberData, _ = framework.GetState(string(getMemberStateID(addr)))
+	if len(memberData) == 0 {
+		return nil, framework.ERROR_NOT_FOUND
+	}
+	if status, _, _, _, _, _, _, _ := decodeMember(memberData); status != MEMBER_STATUS_ACTIVE {
+		return nil, framework.ERROR_UNAUTHORIZED
+	}
+	return memberData, framework.SUCCESS
 }
 
 // getMemberStateID 获取成员状态的唯一标识符
 //
-// 用于构建 StateOutput 的 key，格式：member_{address}
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:member_{address}
 //
 // 参数：
 //   - addr: 成员地址
 //
 // 返回：成员状态ID的字节数组
 func getMemberStateID(addr framework.Address) []byte {
-	return append([]byte(STATE_MEMBER_PREFIX), addr.ToBytes()...)
+	return append([]byte(planPrefix(STATE_MEMBER_PREFIX)), addr.ToBytes()...)
 }
 
 // getClaimStateID 获取理赔案件状态的唯一标识符
 //
-// 用于构建 StateOutput 的 key，格式：claim_{claim_id}
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:claim:{claim_id}
 //
 // 参数：
 //   - claimID: 案件唯一标识符
 //
 // 返回：案件状态ID的字节数组
 func getClaimStateID(claimID string) []byte {
-	return append([]byte(STATE_CLAIM_PREFIX), []byte(claimID)...)
+	return append([]byte(planPrefix(STATE_CLAIM_PREFIX)), []byte(claimID)...)
 }
 
 // getRoundStateID 获取轮次状态的唯一标识符
 //
-// 用于构建 StateOutput 的 key，格式：round_{round_id}
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:round:{round_id}
 //
 // 参数：
 //   - roundID: 轮次唯一标识符
 //
 // 返回：轮次状态ID的字节数组
 func getRoundStateID(roundID string) []byte {
-	return append([]byte(STATE_ROUND_PREFIX), []byte(roundID)...)
+	return append([]byte(planPrefix(STATE_ROUND_PREFIX)), []byte(roundID)...)
 }
 
 // getMemberRoundDueStateID 获取成员轮次应缴状态的唯一标识符
 //
-// 用于构建 StateOutput 的 key，格式：member_round_due_{address}_{round_id}
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:member_round_due_{address}:{round_id}
 //
 // 参数：
 //   - addr: 成员地址
@@ -620,183 +794,2336 @@ func getRoundStateID(roundID string) []byte {
 //
 // 返回：成员轮次应缴状态ID的字节数组
 func getMemberRoundDueStateID(addr framework.Address, roundID string) []byte {
-	return append(append([]byte("member_round_due_"), addr.ToBytes()...), []byte("_"+roundID)...)
+	return append(append([]byte(planPrefix("member_round_due_")), addr.ToBytes()...), []byte(":"+roundID)...)
 }
 
 // getMemberMonthStatStateID 获取成员月度统计状态的唯一标识符
 //
-// 用于构建 StateOutput 的 key，格式：member_month_stat_{address}_{yearMonth}
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:member_month_stat_{address}_{yearMonth}
 //
 // 参数：
 //   - addr: 成员地址
-//   - yearMonth: 年月标识符（格式：YYYYMM，如 "202501"）
+//   - yearMonth: 年月标识符（格式：YYYYMM，如 "202501"；缴费按轮次 period_end 所在月份计，见 contributionYearMonth）
 //
 // 返回：成员月度统计状态ID的字节数组
 func getMemberMonthStatStateID(addr framework.Address, yearMonth string) []byte {
-	return append(append([]byte("member_month_stat_"), addr.ToBytes()...), []byte("_"+yearMonth)...)
+	return append(append([]byte(planPrefix("member_month_stat_")), addr.ToBytes()...), []byte("_"+yearMonth)...)
 }
 
-// addressBytesToString 将20字节的地址二进制数据转换为 Base58 地址字符串
+// getMemberCapStateID 获取成员月度上限覆盖状态的唯一标识符
 //
-// 用于将状态中存储的地址二进制数据转换为可读的 Base58 格式，用于 JSON 返回。
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:member_cap_{address}
 //
 // 参数：
-//   - addrBytes: 地址的二进制数据（至少20字节）
-//
-// 返回：Base58 格式的地址字符串
+//   - addr: 成员地址
 //
-// 如果数据长度不足20字节，返回空字符串
-func addressBytesToString(addrBytes []byte) string {
-	if len(addrBytes) < 20 {
-		return ""
-	}
-	addr := framework.AddressFromBytes(addrBytes[:20])
-	return addr.ToString()
+// 返回：成员月度上限覆盖状态ID的字节数组
+func getMemberCapStateID(addr framework.Address) []byte {
+	return append([]byte(planPrefix(STATE_MEMBER_CAP_PREFIX)), addr.ToBytes()...)
 }
 
-// ================================================================================================
-// 导出方法（Host ABI v1.1 规范）
-// ================================================================================================
-
-// Initialize 初始化互助计划
-//
-// 这是合约的第一个调用，用于创建并配置一个新的互助计划。
-// 调用者将成为计划的 operator，拥有管理权限。
-//
-// # 业务逻辑
-//
-// 1. 参数校验：检查必填参数和数值范围
-// 2. 保存计划配置到链上状态
-// 3. 设置调用者为 operator
-// 4. 初始化活跃成员计数为 0
-// 5. 发出初始化事件
-// 6. 返回完整的计划配置信息（WES ISPC 特性）
-//
-// # 参数说明（JSON格式）
-//
-//	{
-//	  "plan_id": "plan_xianghubao_001",     // 计划唯一标识符（必填）
-//	  "name": "相互宝互助计划",              // 计划名称（必填）
-//	  "token_id": "",                        // 计价代币ID，空字符串表示原生币（可选）
-//	  "coverage_amount": 300000,            // 单次给付额上限（必填，>0）
-//	  "service_fee_bp": 800,                 // 服务费率，单位 bp（万分比），如 800 = 8%（可选，默认0，最大10000）
-//	  "settlement_period": 2592000,          // 结算周期（秒），例如 30 天（必填，>0）
-//	  "waiting_period": 86400,               // 等待期（秒），例如 1 天（可选，默认0）
-//	  "min_members": 1000,                   // 最小成员数，计划生效门槛（可选，默认1）
-//	  "monthly_cap_per_member": 10000        // 单成员月度分摊上限（可选，默认1000000）
-//	}
-//
-// # 返回值
-//
-// 成功时返回 JSON 格式的计划配置信息：
-//
-//	{
-//	  "plan_id": "plan_xianghubao_001",
-//	  "name": "相互宝互助计划",
-//	  "token_id": "",
-//	  "coverage_amount": 300000,
-//	  "service_fee_bp": 800,
-//	  "settlement_period": 2592000,
-//	  "waiting_period": 86400,
-//	  "min_members": 1000,
-//	  "monthly_cap_per_member": 10000,
-//	  "operator": "Cf1...",                  // Base58 格式的 operator 地址
-//	  "member_count_active": 0,              // 初始活跃成员数
-//	  "initialized_at": 1736200000          // 初始化时间戳
-//	}
-//
-// # 状态变更
+// getRoundArrearsStateID 获取轮次欠费总额状态的唯一标识符
 //
-// - 创建 StateOutput: plan_config（计划配置）
-// - 创建 StateOutput: operator（运营方地址）
-// - 创建 StateOutput: member_count_active（活跃成员数，初始为0）
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:round_arrears:{round_id}
 //
-// # 事件
+// 参数：
+//   - roundID: 轮次唯一标识符
 //
-// 发出 MutualAidPlanInitialized 事件，包含所有计划配置参数。
+// 返回：轮次欠费总额状态ID的字节数组
+func getRoundArrearsStateID(roundID string) []byte {
+	return append([]byte(planPrefix(STATE_ROUND_ARREARS_PREFIX)), []byte(roundID)...)
+}
+
+// getRoundClaimsStateID 获取轮次案件索引状态的唯一标识符
 //
-// # 错误码
+// 用于构建 StateOutput 的 key，格式：plan:{plan_id}:round_claims:{round_id}
 //
-// - ERROR_INVALID_PARAMS: 参数无效（plan_id/name 为空，或数值范围错误）
-// - ERROR_EXECUTION_FAILED: 状态保存失败
+// 参数：
+//   - roundID: 轮次唯一标识符
 //
-//export Initialize
-func Initialize() uint32 {
-	params := framework.GetContractParams()
+// 返回：轮次案件索引状态ID的字节数组
+func getRoundClaimsStateID(roundID string) []byte {
+	return append([]byte(planPrefix(STATE_ROUND_CLAIMS_PREFIX)), []byte(roundID)...)
+}
 
-	planID := params.ParseJSON("plan_id")
-	name := params.ParseJSON("name")
-	tokenID := params.ParseJSON("token_id")
-	coverageAmount := params.ParseJSONInt("coverage_amount")
-	serviceFeeBP := params.ParseJSONInt("service_fee_bp")
-	settlementPeriod := params.ParseJSONInt("settlement_period")
-	waitingPeriod := params.ParseJSONInt("waiting_period")
-	minMembers := params.ParseJSONInt("min_members")
-	monthlyCapPerMember := params.ParseJSONInt("monthly_cap_per_member")
+// getTierMultiplierStateID 获取档位分摊系数状态的唯一标识符，格式：plan:{plan_id}:tier_multiplier_{tier}
+func getTierMultiplierStateID(tier uint64) []byte {
+	return []byte(planPrefix(STATE_TIER_MULTIPLIER_PREFIX) + framework.Uint64ToString(tier))
+}
 
-	// 参数校验
-	if planID == "" || name == "" || coverageAmount <= 0 || settlementPeriod <= 0 {
-		return framework.ERROR_INVALID_PARAMS
-	}
-	if serviceFeeBP > 10000 { // 服务费率不能超过100%
-		return framework.ERROR_INVALID_PARAMS
-	}
-	if waitingPeriod < 0 {
-		waitingPeriod = 0
-	}
-	if minMembers < 1 {
-		minMembers = 1
-	}
-	if monthlyCapPerMember <= 0 {
-		monthlyCapPerMember = 1000000 // 默认上限100万
+// getTierCountStateID 获取档位活跃成员数状态的唯一标识符，格式：plan:{plan_id}:member_count_tier_{tier}
+func getTierCountStateID(tier uint64) []byte {
+	return []byte(planPrefix(STATE_TIER_COUNT_PREFIX) + framework.Uint64ToString(tier))
+}
+
+// loadTierMultiplier 读取档位生效的分摊系数（未配置时为1倍）
+func loadTierMultiplier(tier uint64) uint64 {
+	data, _ := framework.GetState(string(getTierMultiplierStateID(tier)))
+	return tierMultiplier(framework.BytesToUint64(data))
+}
+
+// loadMemberWeight 读取各档位活跃成员数与系数，计算全部活跃成员的系数之和
+func loadMemberWeight(memberCount uint64) uint64 {
+	tierCounts := make([]uint64, MAX_TIERS)
+	multipliers := make([]uint64, MAX_TIERS)
+	for tier := uint64(0); tier < MAX_TIERS; tier++ {
+		countData, _ := framework.GetState(string(getTierCountStateID(tier)))
+		tierCounts[tier] = framework.BytesToUint64(countData)
+		multipliers[tier] = loadTierMultiplier(tier)
 	}
+	return totalMemberWeight(tierCounts, multipliers, memberCount)
+}
 
-	caller := framework.GetCaller()
+// adjustTierCount 成员激活（increase=true）或退出时更新档位活跃成员数
+func adjustTierCount(tier uint64, increase bool) uint32 {
+	stateID := getTierCountStateID(tier)
+	countData, _ := framework.GetState(string(stateID))
+	count := framework.BytesToUint64(countData)
+	if increase {
+		count++
+	} else if count > 0 {
+		count--
+	}
+	return appendVersionedState(stateID, framework.Uint64ToBytes(count))
+}
 
-	// 1. 保存计划配置
-	configData := encodePlanConfig(planID, name, tokenID, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember)
-	if _, err := framework.AppendStateOutputSimple([]byte(STATE_PLAN_CONFIG), 1, configData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+// getRoundSnapshotStateID 生成轮次成员快照状态ID
+func getRoundSnapshotStateID(roundID string) []byte {
+	return []byte(planPrefix(STATE_ROUND_SNAPSHOT_PREFIX) + roundID)
+}
+
+// takeRoundSnapshot 轮次开启时快照活跃成员数与分摊权重
+//
+// 快照写入 round_snapshot:{round_id}：memberCount(8) + totalWeightBP(8) + taken(1)，
+// taken 恒为1，用于区分"快照成员数为0"与"无快照"
+//
+// 返回：当前成员激活序号，作为快照引用写入轮次记录
+func takeRoundSnapshot(roundID string) (snapshotSeq uint64, code uint32) {
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount := framework.BytesToUint64(memberCountData)
+	seqData, _ := framework.GetState(planKey(STATE_ACTIVATION_SEQ))
+	snapshotSeq = framework.BytesToUint64(seqData)
+
+	snapshot := make([]byte, 17)
+	copy(snapshot[0:8], framework.Uint64ToBytes(memberCount))
+	copy(snapshot[8:16], framework.Uint64ToBytes(loadMemberWeight(memberCount)))
+	snapshot[16] = 1
+	if _, err := framework.AppendStateOutputSimple(getRoundSnapshotStateID(roundID), 1, snapshot, nil); err != nil {
+		return 0, framework.ERROR_EXECUTION_FAILED
 	}
+	return snapshotSeq, framework.SUCCESS
+}
 
-	// 2. 保存 operator
-	if _, err := framework.AppendStateOutputSimple([]byte(STATE_OPERATOR), 1, caller.ToBytes(), nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+// loadRoundSnapshot 读取轮次成员快照
+//
+// 返回：快照成员数、快照分摊权重（bp）、是否存在快照（引入快照前开启的轮次不存在）
+func loadRoundSnapshot(roundID string) (memberCount, totalWeight uint64, ok bool) {
+	data, _ := framework.GetState(string(getRoundSnapshotStateID(roundID)))
+	if len(data) < 17 || data[16] != 1 {
+		return 0, 0, false
 	}
+	return framework.BytesToUint64(data[0:8]), framework.BytesToUint64(data[8:16]), true
+}
 
-	// 3. 初始化成员计数
-	if _, err := framework.AppendStateOutputSimple([]byte(STATE_MEMBER_COUNT), 1, uint64ToBytes(0), nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+// roundClaimApprovedAmount 返回读取轮次内案件批准金额的函数（轮次结算的 ApprovedAmount）
+//
+// 只计入已批准或已给付、且审核时归入 roundID 的案件；案件不存在、未批准或属于其他轮次时返回 false
+func roundClaimApprovedAmount(roundID string) func(claimID string) (uint64, bool) {
+	return func(claimID string) (uint64, bool) {
+		claimData, _ := framework.GetState(string(getClaimStateID(claimID)))
+		if len(framework.TrimNull(claimData)) == 0 {
+			return 0, false
+		}
+		_, _, _, _, status, reviewRoundID, _, _, _, approvedAmount, _ := decodeClaim(claimData)
+		return approvedAmount, claimCountsTowardRound(status, reviewRoundID, roundID)
 	}
+}
 
-	// 4. 发出事件
-	event := framework.NewEvent("MutualAidPlanInitialized")
+// getClaimCategoryStateID 获取案件类别状态的唯一标识符，格式：plan:{plan_id}:claim_category:{claim_id}
+func getClaimCategoryStateID(claimID string) []byte {
+	return []byte(planPrefix(STATE_CLAIM_CATEGORY_PREFIX) + claimID)
+}
+
+// getCategoryUsageStateID 获取类别年度累计额度状态的唯一标识符，格式：plan:{plan_id}:category_usage_{address}_{category_id}_{year}
+func getCategoryUsageStateID(addr framework.Address, categoryID string, year uint64) []byte {
+	return append(append([]byte(planPrefix(STATE_CATEGORY_USAGE_PREFIX)), addr.ToBytes()...), []byte("_"+categoryID+"_"+framework.Uint64ToString(year))...)
+}
+
+// getMemberExclusionsStateID 获取成员类别除外状态的唯一标识符，格式：plan:{plan_id}:member_exclusions_{address}
+func getMemberExclusionsStateID(addr framework.Address) []byte {
+	return append([]byte(planPrefix(STATE_MEMBER_EXCLUSIONS_PREFIX)), addr.ToBytes()...)
+}
+
+// loadCoverageCategories 读取计划的保障类别配置（未配置时为空）
+func loadCoverageCategories() []coverageCategory {
+	data, _ := framework.GetState(planKey(STATE_COVERAGE_CATEGORIES))
+	return decodeCoverageCategories(data)
+}
+
+// loadMemberExclusions 读取成员的类别除外列表
+func loadMemberExclusions(addr framework.Address) []memberExclusion {
+	data, _ := framework.GetState(string(getMemberExclusionsStateID(addr)))
+	return decodeExclusions(data)
+}
+
+// loadCategoryUsage 读取被保人某类别某年度的累计额度
+func loadCategoryUsage(addr framework.Address, categoryID string, year uint64) categoryUsage {
+	data, _ := framework.GetState(string(getCategoryUsageStateID(addr, categoryID, year)))
+	return decodeCategoryUsage(data)
+}
+
+// claimCategoryUsage 案件所属类别及被保人在出险年份的累计额度
+type claimCategoryUsage struct {
+	StateID  []byte
+	Category coverageCategory
+	Year     uint64
+	Usage    categoryUsage
+}
+
+// loadClaimCategoryUsage 读取案件的类别年度额度
+//
+// claim_category:{claim_id} 记录：categoryID(32) + year(8)。
+// 案件未指定类别（计划未配置类别）时返回 false，不受年度累计上限约束。
+func loadClaimCategoryUsage(claimID string) (claimCategoryUsage, bool) {
+	data, _ := framework.GetState(string(getClaimCategoryStateID(claimID)))
+	entries := fixedEntries(data, COVERAGE_CATEGORY_ID_SIZE+8)
+	if len(entries) == 0 {
+		return claimCategoryUsage{}, false
+	}
+	category, ok := findCoverageCategory(loadCoverageCategories(), string(framework.TrimNull(entries[0][0:COVERAGE_CATEGORY_ID_SIZE])))
+	if !ok {
+		return claimCategoryUsage{}, false
+	}
+	claimData, _ := framework.GetState(string(getClaimStateID(claimID)))
+	_, _, _, insured, _, _, _, _, _, _, _ := decodeClaim(claimData)
+	insuredAddr := framework.AddressFromBytes([]byte(insured))
+	year := framework.BytesToUint64(entries[0][COVERAGE_CATEGORY_ID_SIZE:])
+	return claimCategoryUsage{
+		StateID:  getCategoryUsageStateID(insuredAddr, category.ID, year),
+		Category: category,
+		Year:     year,
+		Usage:    loadCategoryUsage(insuredAddr, category.ID, year),
+	}, true
+}
+
+// emitAuditEvent 发出理赔案件事件并追加到合约内事件日志（见 QueryEventLog）
+func emitAuditEvent(event *framework.Event) uint32 {
+	framework.EmitEvent(event)
+	if _, err := framework.AppendEventLog(event); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// rejectWithDetail 返回错误码，并以 JSON 返回结构化的拒绝原因（如 {"error":"CATEGORY_EXCLUDED",...}）
+func rejectWithDetail(code uint32, detail map[string]interface{}) uint32 {
+	if err := framework.SetReturnJSON(detail); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return code
+}
+
+// rejectParam 参数解析失败时返回结构化错误 {"error", "message", "field"}（见 framework.NewFieldError）
+func rejectParam(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return rejectWithDetail(contractErr.Code, contractErr.Detail())
+	}
+	return framework.ERROR_INVALID_PARAMS
+}
+
+// annualLimitDetail 超过类别年度累计上限时的结构化拒绝原因
+func annualLimitDetail(c claimCategoryUsage, claimID string, amount uint64) map[string]interface{} {
+	return map[string]interface{}{
+		"error":        CATEGORY_REJECT_ANNUAL_LIMIT,
+		"claim_id":     claimID,
+		"category_id":  c.Category.ID,
+		"year":         c.Year,
+		"annual_limit": c.Category.AnnualLimit,
+		"approved":     c.Usage.Approved,
+		"paid":         c.Usage.Paid,
+		"requested":    amount,
+	}
+}
+
+// loadRoundSettlementBase 读取轮次分摊基数
+//
+// 有快照时使用轮次开启时的活跃成员数与权重；旧轮次回退为当前活跃成员数与权重
+func loadRoundSettlementBase(roundID string) (memberCount, totalWeight uint64) {
+	if memberCount, totalWeight, ok := loadRoundSnapshot(roundID); ok {
+		return memberCount, totalWeight
+	}
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount = framework.BytesToUint64(memberCountData)
+	return memberCount, loadMemberWeight(memberCount)
+}
+
+// loadEffectiveServiceFeeBP 读取服务费调整配置与累计数据，计算本轮生效的服务费率
+//
+// 参数：
+//   - fixedBP: 计划配置的 service_fee_bp
+//
+// 返回：生效费率、服务费模式、参与计算的历史赔付率（bp）
+func loadEffectiveServiceFeeBP(fixedBP uint64) (feeBP uint64, mode string, ratioBP uint64) {
+	adjData, _ := framework.GetState(planKey(STATE_FEE_ADJUSTMENT))
+	mode, minFeeBP, maxFeeBP := decodeFeeAdjustment(adjData)
+	collectedData, _ := framework.GetState(planKey(STATE_CUMULATIVE_COLLECTED))
+	paidData, _ := framework.GetState(planKey(STATE_CUMULATIVE_PAID))
+	feeBP, ratioBP = effectiveServiceFeeBP(mode, fixedBP, minFeeBP, maxFeeBP, framework.BytesToUint64(paidData), framework.BytesToUint64(collectedData))
+	return feeBP, mode, ratioBP
+}
+
+// loadRoundSettlementInputs 读取轮次结算所需的链上数据（SettleRound / AdvanceRound / PreviewSettlement 共用）
+//
+// 参数：
+//   - roundID: 结算的轮次ID
+//   - serviceFeeBP: 计划配置的 service_fee_bp
+func loadRoundSettlementInputs(roundID string, serviceFeeBP uint64) roundSettlementInputs {
+	roundClaimsData, _ := framework.GetState(string(getRoundClaimsStateID(roundID)))
+	adjData, _ := framework.GetState(planKey(STATE_FEE_ADJUSTMENT))
+	feeMode, minFeeBP, maxFeeBP := decodeFeeAdjustment(adjData)
+	collectedData, _ := framework.GetState(planKey(STATE_CUMULATIVE_COLLECTED))
+	paidData, _ := framework.GetState(planKey(STATE_CUMULATIVE_PAID))
+	memberCount, totalWeight := loadRoundSettlementBase(roundID)
+	cfgData, _ := framework.GetState(planKey(STATE_ROUNDING_CONFIG))
+	carryData, _ := framework.GetState(planKey(STATE_ROUNDING_CARRY))
+
+	return roundSettlementInputs{
+		ClaimIDs:            decodeRoundClaims(roundClaimsData),
+		ApprovedAmount:      roundClaimApprovedAmount(roundID),
+		ServiceFeeBP:        serviceFeeBP,
+		FeeMode:             feeMode,
+		MinFeeBP:            minFeeBP,
+		MaxFeeBP:            maxFeeBP,
+		CumulativePaid:      framework.BytesToUint64(paidData),
+		CumulativeCollected: framework.BytesToUint64(collectedData),
+		MemberCount:         memberCount,
+		TotalWeight:         totalWeight,
+		Rounding:            decodeRoundingConfig(cfgData),
+		StoredCarry:         framework.BytesToInt64(carryData),
+	}
+}
+
+// saveRoundingCarry 写入结算后的累计取整余额
+//
+// 无应收或无人分摊时不产生取整余额，余额不变时不写入状态。
+func saveRoundingCarry(in roundSettlementInputs, p roundSettlementPlan) uint32 {
+	if p.Carry == in.StoredCarry {
+		return framework.SUCCESS
+	}
+	return appendVersionedState([]byte(planKey(STATE_ROUNDING_CARRY)), framework.Int64ToBytes(p.Carry))
+}
+
+// roundSettlementResult 轮次结算结果（SettleRound 返回值与 PreviewSettlement 共用的字段）
+func roundSettlementResult(planID, roundID string, periodStart, periodEnd, payersCount uint64, in roundSettlementInputs, p roundSettlementPlan) map[string]interface{} {
+	return map[string]interface{}{
+		"plan_id":                  planID,
+		"round_id":                 roundID,
+		"status":                   p.Status,
+		"period_start":             periodStart,
+		"period_end":               periodEnd,
+		"total_approved_payout":    p.TotalApprovedPayout,
+		"total_service_fee":        p.Settlement.TotalServiceFee,
+		"total_with_fee":           p.Settlement.TotalWithFee,
+		"per_capita_contribution":  p.Settlement.PerCapita,
+		"member_count_active":      in.MemberCount,
+		"total_weight_bp":          in.TotalWeight,
+		"service_fee_bp":           in.ServiceFeeBP,
+		"effective_service_fee_bp": p.EffectiveFeeBP,
+		"fee_mode":                 in.FeeMode,
+		"claims_ratio_bp":          p.ClaimsRatioBP,
+		"payers_count":             payersCount,
+		"rounding_mode":            in.Rounding.Mode,
+		"rounding_decimals":        in.Rounding.Decimals,
+		"collection_target":        p.Settlement.Target,
+		"rounding_carry_applied":   p.Settlement.CarryApplied,
+		"rounding_surplus":         p.Settlement.Surplus,
+		"rounding_carry":           p.Carry,
+	}
+}
+
+// addCumulative 累加计数类状态（cumulative_collected / cumulative_paid）
+func addCumulative(stateID string, amount uint64) uint32 {
+	data, _ := framework.GetState(stateID)
+	return appendVersionedState([]byte(stateID), framework.Uint64ToBytes(framework.BytesToUint64(data)+amount))
+}
+
+// getMembersAllPageStateID 生成成员索引分页状态ID
+func getMembersAllPageStateID(page uint64) []byte {
+	return []byte(planPrefix(STATE_MEMBERS_ALL_PREFIX) + framework.Uint64ToString(page))
+}
+
+// getMembersActivePageStateID 生成活跃成员集合分页状态ID
+func getMembersActivePageStateID(page uint64) []byte {
+	return []byte(planPrefix(STATE_MEMBERS_ACTIVE_PREFIX) + framework.Uint64ToString(page))
+}
+
+// getMembersActivePosStateID 生成成员在活跃集合中位置的状态ID
+func getMembersActivePosStateID(addr framework.Address) []byte {
+	return append([]byte(planPrefix(STATE_MEMBERS_ACTIVE_POS_PREFIX)), addr.ToBytes()...)
+}
+
+// getOffchainEntryStateID 生成线下缴费登记状态ID
+func getOffchainEntryStateID(referenceHash string) []byte {
+	return []byte(planPrefix(STATE_OFFCHAIN_PREFIX) + referenceHash)
+}
+
+// getRoundPaidStateID 生成轮次缴费拆分状态ID
+func getRoundPaidStateID(roundID string) []byte {
+	return []byte(planPrefix(STATE_ROUND_PAID_PREFIX) + roundID)
+}
+
+// loadRoundPaid 读取轮次已缴金额的链上/线下拆分
+//
+// 编码格式：onchainPaid(8) + offchainPaid(8)
+func loadRoundPaid(roundID string) (onchainPaid, offchainPaid uint64) {
+	data, _ := framework.GetState(string(getRoundPaidStateID(roundID)))
+	if len(data) < 16 {
+		return 0, 0
+	}
+	return framework.BytesToUint64(data[0:8]), framework.BytesToUint64(data[8:16])
+}
+
+// saveRoundPaid 写入轮次已缴金额的链上/线下拆分
+func saveRoundPaid(roundID string, onchainPaid, offchainPaid uint64) uint32 {
+	data := make([]byte, 16)
+	copy(data[0:8], framework.Uint64ToBytes(onchainPaid))
+	copy(data[8:16], framework.Uint64ToBytes(offchainPaid))
+	return appendVersionedState(getRoundPaidStateID(roundID), data)
+}
+
+// appendMemberToIndex 将首次加入的成员地址追加到成员索引 members_all
+//
+// 索引按 MEMBER_INDEX_PAGE_SIZE 分页存储，members_all_count 记录总数
+func appendMemberToIndex(addr framework.Address) uint32 {
+	_, code := appendToMemberList(getMembersAllPageStateID, planKey(STATE_MEMBERS_ALL_COUNT), addr)
+	return code
+}
+
+// appendToMemberList 将地址追加到分页存储的成员列表末尾
+//
+// 返回：地址在列表中的位置（从0开始）
+func appendToMemberList(pageStateID func(page uint64) []byte, countStateID string, addr framework.Address) (uint64, uint32) {
+	countData, _ := framework.GetState(countStateID)
+	total := framework.BytesToUint64(countData)
+	page, offset := memberIndexPageOf(total)
+
+	pageID := pageStateID(page)
+	pageData, _ := framework.GetState(string(pageID))
+	newPage, ok := appendMemberIndexPage(pageData, int(offset), addr.ToBytes())
+	if !ok {
+		return 0, framework.ERROR_EXECUTION_FAILED
+	}
+	if code := appendVersionedState(pageID, newPage); code != framework.SUCCESS {
+		return 0, code
+	}
+	return total, appendVersionedState([]byte(countStateID), framework.Uint64ToBytes(total+1))
+}
+
+// addActiveMember 将成员加入活跃成员集合 members_active（已在集合中时不重复加入）
+func addActiveMember(addr framework.Address) uint32 {
+	posData, _ := framework.GetState(string(getMembersActivePosStateID(addr)))
+	if framework.BytesToUint64(posData) > 0 {
+		return framework.SUCCESS
+	}
+	position, code := appendToMemberList(getMembersActivePageStateID, planKey(STATE_MEMBERS_ACTIVE_COUNT), addr)
+	if code != framework.SUCCESS {
+		return code
+	}
+	return appendVersionedState(getMembersActivePosStateID(addr), framework.Uint64ToBytes(position+1))
+}
+
+// removeActiveMember 将成员移出活跃成员集合 members_active
+//
+// 交换删除：集合最后一个成员移动到被删除的位置，并更新其位置记录
+func removeActiveMember(addr framework.Address) uint32 {
+	posData, _ := framework.GetState(string(getMembersActivePosStateID(addr)))
+	pos := framework.BytesToUint64(posData)
+	countData, _ := framework.GetState(planKey(STATE_MEMBERS_ACTIVE_COUNT))
+	total := framework.BytesToUint64(countData)
+	if pos == 0 || pos > total {
+		return framework.SUCCESS // 不在集合中（如引入集合前激活的成员）
+	}
+
+	// 1. 载入被删除位置与最后一个条目所在的分页，在内存中完成交换与截断
+	page, _ := memberIndexPageOf(pos - 1)
+	lastPage, _ := memberIndexPageOf(total - 1)
+	touched := []uint64{page}
+	if lastPage != page {
+		touched = append(touched, lastPage)
+	}
+	pages := make(map[uint64][]byte, len(touched))
+	for _, p := range touched {
+		pages[p], _ = framework.GetState(string(getMembersActivePageStateID(p)))
+	}
+	moved := removeFromMemberSet(pages, total, pos-1)
+
+	// 2. 每个分页只写入一次（按页号顺序，保证执行确定性）
+	for _, p := range touched {
+		if code := appendVersionedState(getMembersActivePageStateID(p), pages[p]); code != framework.SUCCESS {
+			return code
+		}
+	}
+	if moved != nil {
+		if code := appendVersionedState(getMembersActivePosStateID(framework.AddressFromBytes(moved)), framework.Uint64ToBytes(pos)); code != framework.SUCCESS {
+			return code
+		}
+	}
+	if code := appendVersionedState(getMembersActivePosStateID(addr), framework.Uint64ToBytes(0)); code != framework.SUCCESS {
+		return code
+	}
+	return appendVersionedState([]byte(planKey(STATE_MEMBERS_ACTIVE_COUNT)), framework.Uint64ToBytes(total-1))
+}
+
+// loadMemberIndex 读取成员索引中的全部地址（按加入顺序）
+func loadMemberIndex() []framework.Address {
+	return loadMemberList(getMembersAllPageStateID, planKey(STATE_MEMBERS_ALL_COUNT))
+}
+
+// loadMemberList 读取分页存储的成员列表中的全部地址
+func loadMemberList(pageStateID func(page uint64) []byte, countStateID string) []framework.Address {
+	countData, _ := framework.GetState(countStateID)
+	total := framework.BytesToUint64(countData)
+
+	members := make([]framework.Address, 0, total)
+	for page := uint64(0); page*MEMBER_INDEX_PAGE_SIZE < total; page++ {
+		pageData, _ := framework.GetState(string(pageStateID(page)))
+		for _, raw := range decodeMemberIndexPage(pageData, memberIndexPageEntries(total, page)) {
+			var addr framework.Address
+			copy(addr[:], raw)
+			members = append(members, addr)
+		}
+	}
+	return members
+}
+
+// appendVersionedState 以递增版本号写入状态输出
+//
+// 返回：framework.SUCCESS 或 framework.ERROR_EXECUTION_FAILED
+func appendVersionedState(stateID []byte, value []byte) uint32 {
+	version, err := framework.IncrementStateVersion(stateID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if _, err := framework.AppendStateOutputSimple(stateID, version, value, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// addressBytesToString 将20字节的地址二进制数据转换为 Base58 地址字符串
+//
+// 用于将状态中存储的地址二进制数据转换为可读的 Base58 格式，用于 JSON 返回。
+//
+// 参数：
+//   - addrBytes: 地址的二进制数据（至少20字节）
+//
+// 返回：Base58 格式的地址字符串
+//
+// 如果数据长度不足20字节，返回空字符串
+func addressBytesToString(addrBytes []byte) string {
+	if len(addrBytes) < 20 {
+		return ""
+	}
+	addr := framework.AddressFromBytes(addrBytes[:20])
+	return addr.ToString()
+}
+
+// ================================================================================================
+// 导出方法（Host ABI v1.1 规范）
+// ================================================================================================
+
+// Initialize 初始化互助计划
+//
+// 这是合约的第一个调用，用于创建并配置一个新的互助计划。
+// 调用者将成为计划的 operator，拥有管理权限。
+//
+// # 业务逻辑
+//
+// 1. 参数校验：检查必填参数和数值范围
+// 2. 保存计划配置到链上状态
+// 3. 设置调用者为 operator，operator_admin（可紧急轮换 operator）默认同为调用者
+// 4. 初始化活跃成员计数为 0
+// 5. 发出初始化事件
+// 6. 返回完整的计划配置信息（WES ISPC 特性）
+//
+// # 参数说明（JSON格式）
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",     // 计划唯一标识符（必填）
+//	  "name": "相互宝互助计划",              // 计划名称（必填）
+//	  "token_id": "",                        // 计价代币ID，空字符串表示原生币（可选）
+//	  "coverage_amount": 300000,            // 单次给付额上限（必填，>0）
+//	  "service_fee_bp": 800,                 // 服务费率，单位 bp（万分比），如 800 = 8%（可选，默认0，最大10000）
+//	  "settlement_period": 2592000,          // 结算周期（秒），例如 30 天（必填，>0）
+//	  "waiting_period": 86400,               // 等待期（秒），例如 1 天（可选，默认0）
+//	  "min_members": 1000,                   // 最小成员数，计划生效门槛（可选，默认1）
+//	  "monthly_cap_per_member": 10000,       // 单成员月度分摊上限（可选，默认1000000）
+//	  "operator_admin": "Cf1...",            // operator_admin 地址，Base58（可选，默认为调用者）
+//	  "categories": [                        // 保障类别（可选，1 ~ MAX_COVERAGE_CATEGORIES 个）
+//	    {"category_id": "critical_illness", "per_claim_limit": 300000, "annual_limit": 300000, "waiting_period": 7776000},
+//	    {"category_id": "accident", "per_claim_limit": 100000, "annual_limit": 200000}  // waiting_period 缺省为计划等待期
+//	  ]
+//	}
+//
+// 配置了类别的计划要求报案指定 category_id，单次给付上限与等待期按类别校验，
+// 年度累计上限在审核批准与给付时校验（见 SubmitClaim / ReviewClaim / Payout）。
+//
+// # 返回值
+//
+// 成功时返回 JSON 格式的计划配置信息：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "name": "相互宝互助计划",
+//	  "token_id": "",
+//	  "coverage_amount": 300000,
+//	  "service_fee_bp": 800,
+//	  "settlement_period": 2592000,
+//	  "waiting_period": 86400,
+//	  "min_members": 1000,
+//	  "monthly_cap_per_member": 10000,
+//	  "operator": "Cf1...",                  // Base58 格式的 operator 地址
+//	  "operator_admin": "Cf1...",            // Base58 格式的 operator_admin 地址
+//	  "member_count_active": 0,              // 初始活跃成员数
+//	  "initialized_at": 1736200000          // 初始化时间戳
+//	}
+//
+// # 状态变更
+//
+// - 创建 StateOutput: plan_config（计划配置）
+// - 创建 StateOutput: operator（运营方地址）
+// - 创建 StateOutput: role:operator_admin（operator_admin 地址）
+// - 创建 StateOutput: member_count_active（活跃成员数，初始为0）
+// - 创建 StateOutput: coverage_categories（配置了类别时）
+//
+// # 事件
+//
+// 发出 MutualAidPlanInitialized 事件，字段与返回值一致（含 operator、member_count_active、initialized_at）。
+// 配置了类别时另发出 MutualAidCoverageCategoriesConfigured 事件（categories）。
+//
+// # 错误码
+//
+// - ERROR_INVALID_PARAMS: 参数无效（plan_id/name 为空，数值范围错误，categories 格式/取值错误，或 operator_admin 地址无效）；
+//   coverage_amount 缺失或不是正整数、min_members 为负，service_fee_bp 超过 10000、settlement_period 缺失或为 0、
+//   waiting_period / monthly_cap_per_member 不是非负整数时，
+//   返回值为 {"error":"ERROR_INVALID_PARAMS","field":"service_fee_bp","message":...}
+// - ERROR_ALREADY_EXISTS: 计划已初始化（operator 已设置）
+// - ERROR_EXECUTION_FAILED: 状态保存失败
+//
+//export Initialize
+func Initialize() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+
+	name := params.ParseJSON("name")
+	tokenID := params.ParseJSON("token_id")
+
+	// 参数校验
+	if planID == "" || name == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	// 保障金额与最少成员数为负或不是整数时拒绝，而不是按 0 处理
+	coverage, ok := params.ParseJSONIntChecked("coverage_amount")
+	if !ok || coverage <= 0 {
+		return rejectParam(framework.NewFieldError(framework.ERROR_INVALID_PARAMS, "coverage_amount", "value must be a positive integer"))
+	}
+	minMembersParam, ok := params.ParseJSONIntChecked("min_members")
+	if !ok || minMembersParam < 0 {
+		return rejectParam(framework.NewFieldError(framework.ERROR_INVALID_PARAMS, "min_members", "value must be a non-negative integer"))
+	}
+	coverageAmount, minMembers := uint64(coverage), uint64(minMembersParam)
+	// 费率、周期与上限使用带单位的解析器：越界或格式错误时返回指明字段的错误
+	serviceFee, err := params.ParseBasisPoints("service_fee_bp") // 服务费率不能超过100%
+	if err != nil {
+		return rejectParam(err)
+	}
+	settlement, err := params.ParseRequiredDuration("settlement_period")
+	if err != nil {
+		return rejectParam(err)
+	}
+	waiting, err := params.ParseDuration("waiting_period")
+	if err != nil {
+		return rejectParam(err)
+	}
+	monthlyCap, err := params.ParseAmount("monthly_cap_per_member")
+	if err != nil {
+		return rejectParam(err)
+	}
+	if monthlyCap == 0 {
+		monthlyCap = DEFAULT_MONTHLY_CAP_PER_MEMBER
+	}
+	if minMembers < 1 {
+		minMembers = 1
+	}
+	serviceFeeBP, settlementPeriod, waitingPeriod, monthlyCapPerMember := uint64(serviceFee), uint64(settlement), uint64(waiting), uint64(monthlyCap)
+	categories, ok := parseCoverageCategories(string(params.GetRawData()), waitingPeriod)
+	if !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	operatorAdmin := caller
+	if adminStr := params.ParseJSON("operator_admin"); adminStr != "" {
+		admin, err := framework.ParseAddressBase58(adminStr)
+		if err != nil {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		operatorAdmin = admin
+	}
+
+	// 1. 保存计划配置
+	configData := encodePlanConfig(planID, name, tokenID, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember)
+	if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_PLAN_CONFIG)), 1, configData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 2. 保存 operator 与 operator_admin 角色持有者
+	if err := framework.InitRole(planRole(ROLE_OPERATOR), caller); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if err := framework.InitRole(planRole(ROLE_OPERATOR_ADMIN), operatorAdmin); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 3. 初始化成员计数
+	if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_MEMBER_COUNT)), 1, framework.Uint64ToBytes(0), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 4. 保存保障类别
+	if len(categories) > 0 {
+		if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_COVERAGE_CATEGORIES)), 1, encodeCoverageCategories(categories), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		event := framework.NewEvent(EVENT_COVERAGE_CATEGORIES_CONFIGURED)
+		event.AddStringField("plan_id", planID)
+		event.AddField("categories", coverageCategoryItems(categories))
+		framework.EmitEvent(event)
+	}
+
+	// 5. 发出事件（字段与返回值一致）
+	initialization := planInitialization{
+		PlanID:              planID,
+		Name:                name,
+		TokenID:             tokenID,
+		CoverageAmount:      coverageAmount,
+		ServiceFeeBP:        serviceFeeBP,
+		SettlementPeriod:    settlementPeriod,
+		WaitingPeriod:       waitingPeriod,
+		MinMembers:          minMembers,
+		MonthlyCapPerMember: monthlyCapPerMember,
+		Operator:            caller.ToString(),
+		OperatorAdmin:       operatorAdmin.ToString(),
+		InitializedAt:       framework.GetTimestamp(),
+	}
+	framework.EmitEvent(initialization.event())
+
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := initialization.fields()
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// RotateRoleKey 当前角色持有者发起密钥轮换
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_001",         // 计划ID（角色按计划区分）
+//	  "role": "operator",            // 角色：operator / operator_admin
+//	  "new_address": "Cf1..."        // 新地址，Base58
+//	}
+//
+// 新地址需在接受期限（默认 7 天）内调用 AcceptRoleKey 完成轮换。
+// 待接受期间新旧地址均持有该角色，operator 的管理操作不中断。
+//
+// 输出：
+// - StateOutput: role_pending:plan:{plan_id}:{role}
+// - Event: RoleKeyRotationStarted（role 为计划内的角色名称，如 plan:{plan_id}:operator）
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: plan_id 或 role 为空，或 new_address 无效
+// - ERROR_NOT_FOUND: 角色不存在
+// - ERROR_UNAUTHORIZED: 调用者不是当前持有者
+//
+//export RotateRoleKey
+func RotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if planID == "" || role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if !isPlanRole(role) {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	if err := framework.RotateRoleKey(planRole(role), newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// AcceptRoleKey 新地址接受待完成的角色轮换
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_001",
+//	  "role": "operator"
+//	}
+//
+// 输出：
+// - StateOutput: 角色持有者（operator 角色为 plan:{plan_id}:operator）、role_pending:plan:{plan_id}:{role}
+// - StateOutput: index:role_key_audit:plan:{plan_id}:{role}:{seq}（轮换审计记录）
+// - Event: RoleKeyRotated（mode=accepted）
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: plan_id 或 role 为空
+// - ERROR_NOT_FOUND: 角色不存在或没有待接受的轮换
+// - ERROR_TIMEOUT: 已过接受期限
+// - ERROR_UNAUTHORIZED: 调用者不是待接受的新地址
+//
+//export AcceptRoleKey
+func AcceptRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+	role := params.ParseJSON("role")
+	if planID == "" || role == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if !isPlanRole(role) {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	if err := framework.AcceptRoleKey(planRole(role)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// EmergencyRotateRoleKey operator 密钥泄露时由 operator_admin 直接替换 operator
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_001",
+//	  "role": "operator",
+//	  "new_address": "Cf1..."        // 新 operator 地址，Base58
+//	}
+//
+// 跳过新地址确认立即生效，并撤销泄露密钥可能已发起的待接受轮换。
+//
+// 输出：
+// - StateOutput: plan:{plan_id}:operator、role_pending:plan:{plan_id}:operator
+// - StateOutput: index:role_key_audit:plan:{plan_id}:operator:{seq}（轮换审计记录）
+// - Event: RoleKeyRotated（mode=emergency，含 revoked_pending）
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: plan_id 或 role 为空，或 new_address 无效
+// - ERROR_NOT_FOUND: 角色不存在
+// - ERROR_UNAUTHORIZED: 调用者不持有该计划的 operator_admin，或角色不支持紧急轮换
+//
+//export EmergencyRotateRoleKey
+func EmergencyRotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if planID == "" || role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if !isPlanRole(role) {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	if err := framework.EmergencyRotate(planRole(role), newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// Join 成为互助计划成员
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "tier": 1                           // 保障档位（可选，默认0，范围 0 ~ MAX_TIERS-1）
+//	}
+//
+// 输出：
+// - StateOutput: member_{address}
+// - StateOutput: members_all_{page}、members_all_count (首次加入时追加到成员索引)
+// - Event: MutualAidMemberJoined
+//
+// 注意：退出后重新加入时保留原记录的 arrears_amount，欠费不能通过退出再加入清除
+//
+//export Join
+func Join() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	tier := params.ParseJSONInt("tier")
+	if planID == "" || tier >= MAX_TIERS {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	memberStateID := getMemberStateID(caller)
+
+	// 1. 检查是否已加入
+	existingMemberData, _ := framework.GetState(string(memberStateID))
+	firstJoin := len(framework.TrimNull(existingMemberData)) == 0
+	var arrearsAmount uint64
+	if len(existingMemberData) > 0 {
+		var status string
+		status, _, _, _, arrearsAmount, _, _, _ = decodeMember(existingMemberData)
+		if status == MEMBER_STATUS_ACTIVE || status == MEMBER_STATUS_PENDING {
+			return framework.ERROR_ALREADY_EXISTS
+		}
+		// 暂停的成员须由 operator 恢复，不能通过重新加入解除暂停
+		if status == MEMBER_STATUS_SUSPENDED || status == MEMBER_STATUS_BLACKLISTED {
+			return framework.ERROR_UNAUTHORIZED
+		}
+	}
+
+	// 2. 创建成员记录（状态为PENDING，需要operator审核）；退出后重新加入的成员保留欠费
+	currentTime := framework.GetTimestamp()
+	memberData := encodeMember(MEMBER_STATUS_PENDING, currentTime, 0, 0, arrearsAmount, 0, tier, 0)
+	if _, err := framework.AppendStateOutputSimple(memberStateID, 1, memberData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 3. 首次加入时追加到成员索引（退出后重新加入的成员已在索引中）
+	// 成员计数仅统计ACTIVE，等待ApproveMember时再更新
+	if firstJoin {
+		if code := appendMemberToIndex(caller); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 4. 发出事件
+	event := framework.NewEvent("MutualAidMemberJoined")
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", caller)
+	event.AddStringField("status", MEMBER_STATUS_PENDING)
+	event.AddIntField("tier", tier)
+	framework.EmitEvent(event)
+
+	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	waitingPeriod := uint64(0)
+	if len(configData) > 0 {
+		_, _, _, _, _, _, waitingPeriod, _, _ = decodePlanConfig(configData)
+	}
+	result := map[string]interface{}{
+		"plan_id":          planID,
+		"member":           caller.ToString(),
+		"status":           MEMBER_STATUS_PENDING,
+		"join_time":        currentTime,
+		"waiting_period":   waitingPeriod,
+		"waiting_end_time": currentTime + waitingPeriod,
+		"tier":             tier,
+		"total_paid":       uint64(0),
+		"total_received":   uint64(0),
+		"arrears_amount":   arrearsAmount,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// ApproveMember 审核并激活成员（仅 operator 可调用）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1..." // 成员地址（Base58）
+//	}
+//
+// 输出：
+// - StateOutput: member_{address} (更新状态为ACTIVE)
+// - StateOutput: member_count_active (更新)
+// - StateOutput: member_count_tier_{tier} (更新)
+// - StateOutput: members_active_{page}、members_active_count、members_active_pos_{address} (加入活跃成员集合)
+// - Event: MutualAidMemberApproved
+//
+//export ApproveMember
+func ApproveMember() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	memberStr := params.ParseJSON("member")
+	if planID == "" || memberStr == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	member, err := framework.ParseAddressBase58(memberStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	memberStateID := getMemberStateID(member)
+	memberData, _ := framework.GetState(string(memberStateID))
+
+	// 2. 检查成员是否存在且状态为PENDING
+	if len(memberData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, _ := decodeMember(memberData)
+	if status != MEMBER_STATUS_PENDING {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 3. 分配激活序号并更新成员状态为ACTIVE
+	// 激活序号晚于轮次快照的成员不参与该轮分摊
+	seqData, _ := framework.GetState(planKey(STATE_ACTIVATION_SEQ))
+	activationSeq := framework.BytesToUint64(seqData) + 1
+	if code := appendVersionedState([]byte(planKey(STATE_ACTIVATION_SEQ)), framework.Uint64ToBytes(activationSeq)); code != framework.SUCCESS {
+		return code
+	}
+	newMemberData := encodeMember(MEMBER_STATUS_ACTIVE, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq)
+	if _, err := framework.AppendStateOutputSimple(memberStateID, 2, newMemberData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 4. 更新成员计数
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount := framework.BytesToUint64(memberCountData)
+	newMemberCount := memberCount + 1
+	if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_MEMBER_COUNT)), 2, framework.Uint64ToBytes(newMemberCount), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if code := adjustTierCount(tier, true); code != framework.SUCCESS {
+		return code
+	}
+	if code := addActiveMember(member); code != framework.SUCCESS {
+		return code
+	}
+
+	// 5. 发出事件
+	event := framework.NewEvent("MutualAidMemberApproved")
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", member)
+	event.AddIntField("tier", tier)
+	framework.EmitEvent(event)
+
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":             planID,
+		"member":              member.ToString(),
+		"status":              MEMBER_STATUS_ACTIVE,
+		"join_time":           joinTime,
+		"total_paid":          totalPaid,
+		"total_received":      totalReceived,
+		"arrears_amount":      arrearsAmount,
+		"tier":                tier,
+		"member_count_active": newMemberCount,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// Exit 退出互助计划
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001"
+//	}
+//
+// 输出：
+// - StateOutput: member_{address} (更新状态为EXITED)
+// - StateOutput: member_count_active (更新)
+// - StateOutput: member_count_tier_{tier} (更新)
+// - StateOutput: members_active_{page}、members_active_count、members_active_pos_{address} (移出活跃成员集合)
+// - Event: MutualAidMemberExited
+//
+//export Exit
+func Exit() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	if planID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	caller := framework.GetCaller()
+	memberStateID := getMemberStateID(caller)
+
+	// 1. 检查成员是否存在且状态为ACTIVE
+	memberData, code := requireActiveMember(caller)
+	if code != framework.SUCCESS {
+		return code
+	}
+	_, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq := decodeMember(memberData)
+
+	// 2. 更新成员状态为EXITED
+	newMemberData := encodeMember(MEMBER_STATUS_EXITED, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq)
+	if _, err := framework.AppendStateOutputSimple(memberStateID, 2, newMemberData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 3. 更新成员计数
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount := framework.BytesToUint64(memberCountData)
+	newMemberCount := memberCount
+	if memberCount > 0 {
+		newMemberCount = memberCount - 1
+		if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_MEMBER_COUNT)), 2, framework.Uint64ToBytes(newMemberCount), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+	if code := adjustTierCount(tier, false); code != framework.SUCCESS {
+		return code
+	}
+	if code := removeActiveMember(caller); code != framework.SUCCESS {
+		return code
+	}
+
+	// 4. 发出事件
+	event := framework.NewEvent("MutualAidMemberExited")
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", caller)
+	event.AddIntField("arrears_amount", arrearsAmount)
+	framework.EmitEvent(event)
+
+	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":             planID,
+		"member":              caller.ToString(),
+		"status":              MEMBER_STATUS_EXITED,
+		"join_time":           joinTime,
+		"total_paid":          totalPaid,
+		"total_received":      totalReceived,
+		"arrears_amount":      arrearsAmount,
+		"tier":                tier,
+		"member_count_active": newMemberCount,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// ReconcileMemberCount 按成员记录重新计算并校正活跃成员数（仅 operator 可调用）
+//
+// 遍历成员索引 members_all，按每个成员记录的当前状态统计 ACTIVE 成员数；
+// 与 member_count_active 不一致时写入重新计算的值并发出差异事件，一致时不写入状态。
+// 用于修复任何路径对活跃成员数的错误调整。
+//
+// 注意：只统计成员索引中的成员，引入成员索引之前加入的成员不在统计范围内。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001"
+//	}
+//
+// 输出：
+// - StateOutput: member_count_active (不一致时更新)
+// - Event: MutualAidMemberCountReconciled (不一致时)
+//
+//export ReconcileMemberCount
+func ReconcileMemberCount() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	if planID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 读取成员索引中每个成员的当前状态
+	members := loadMemberIndex()
+	statuses := make([]string, len(members))
+	for i, member := range members {
+		memberData, _ := framework.GetState(string(getMemberStateID(member)))
+		statuses[i], _, _, _, _, _, _, _ = decodeMember(memberData)
+	}
+
+	// 3. 重新计算并与已记录的计数比较
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	storedCount := framework.BytesToUint64(memberCountData)
+	actualCount, corrected := reconcileActiveCount(storedCount, statuses)
+
+	// 4. 不一致时校正并发出差异事件
+	if corrected {
+		if code := appendVersionedState([]byte(planKey(STATE_MEMBER_COUNT)), framework.Uint64ToBytes(actualCount)); code != framework.SUCCESS {
+			return code
+		}
+
+		event := framework.NewEvent("MutualAidMemberCountReconciled")
+		event.AddStringField("plan_id", planID)
+		event.AddIntField("previous_count", storedCount)
+		event.AddIntField("member_count_active", actualCount)
+		event.AddIntField("members_scanned", uint64(len(members)))
+		event.AddAddressField("operator", framework.GetCaller())
+		framework.EmitEvent(event)
+	}
+
+	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":             planID,
+		"members_scanned":     uint64(len(members)),
+		"previous_count":      storedCount,
+		"member_count_active": actualCount,
+		"corrected":           corrected,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SuspendMember 暂停成员（仅 operator 可调用）
+//
+// 将 ACTIVE 成员置为 SUSPENDED。暂停期间成员移出活跃成员数与活跃成员集合，
+// 不参与之后开启轮次的分摊，SubmitClaim / PayContribution / Exit 返回 ERROR_UNAUTHORIZED；
+// 已产生的欠费保留在成员记录中。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1...",                 // 成员地址（Base58）
+//	  "reason": "fraud investigation"     // 暂停原因（必填，不超过 MAX_MEMBER_STATUS_REASON_SIZE 字节）
+//	}
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: 参数缺失或原因超长
+// - ERROR_NOT_FOUND: 成员不存在
+// - ERROR_INVALID_STATE: 成员不是 ACTIVE
+//
+// 输出：
+// - StateOutput: member_{address} (更新状态为SUSPENDED)
+// - StateOutput: member_count_active、member_count_tier_{tier} (更新)
+// - StateOutput: members_active_{page}、members_active_count、members_active_pos_{address} (移出活跃成员集合)
+// - Event: MutualAidMemberSuspended
+//
+//export SuspendMember
+func SuspendMember() uint32 {
+	framework.BeginInvocation()
+	return changeMemberStatus(MEMBER_ACTION_SUSPEND)
+}
+
+// ResumeMember 恢复被暂停的成员（仅 operator 可调用）
+//
+// 将 SUSPENDED 成员恢复为 ACTIVE，重新计入活跃成员数与活跃成员集合。
+// 成员保留原激活序号，不重新计算等待期。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1...",
+//	  "reason": "investigation closed"    // 恢复原因（必填）
+//	}
+//
+// 错误码：同 SuspendMember，成员不是 SUSPENDED 时返回 ERROR_INVALID_STATE
+//
+// 输出：
+// - StateOutput: member_{address} (更新状态为ACTIVE)
+// - StateOutput: member_count_active、member_count_tier_{tier} (更新)
+// - StateOutput: members_active_{page}、members_active_count、members_active_pos_{address} (加入活跃成员集合)
+// - Event: MutualAidMemberResumed
+//
+//export ResumeMember
+func ResumeMember() uint32 {
+	framework.BeginInvocation()
+	return changeMemberStatus(MEMBER_ACTION_RESUME)
+}
+
+// BlacklistMember 拉黑成员（仅 operator 可调用）
+//
+// 将 PENDING / ACTIVE / SUSPENDED 成员置为 BLACKLISTED（终态）。
+// 成员原为 ACTIVE 时移出活跃成员数与活跃成员集合；拉黑后不能重新加入计划。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1...",
+//	  "reason": "fraudulent claim"        // 拉黑原因（必填）
+//	}
+//
+// 错误码：同 SuspendMember，成员已退出或已被拉黑时返回 ERROR_INVALID_STATE
+//
+// 输出：
+// - StateOutput: member_{address} (更新状态为BLACKLISTED)
+// - StateOutput: member_count_active、member_count_tier_{tier} (原为ACTIVE时更新)
+// - StateOutput: members_active_{page}、members_active_count、members_active_pos_{address} (原为ACTIVE时移出)
+// - Event: MutualAidMemberBlacklisted
+//
+//export BlacklistMember
+func BlacklistMember() uint32 {
+	framework.BeginInvocation()
+	return changeMemberStatus(MEMBER_ACTION_BLACKLIST)
+}
+
+// changeMemberStatus SuspendMember / ResumeMember / BlacklistMember 共用的状态调整流程
+//
+// 状态转换见 memberStatusTransition；进入或离开 ACTIVE 时同步调整活跃成员数、档位计数与活跃成员集合
+func changeMemberStatus(action string) uint32 {
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	memberStr := params.ParseJSON("member")
+	reason := params.ParseJSON("reason")
+	if planID == "" || memberStr == "" || reason == "" || len(reason) > MAX_MEMBER_STATUS_REASON_SIZE {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	member, err := framework.ParseAddressBase58(memberStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 检查成员是否存在且当前状态允许该操作
+	memberStateID := getMemberStateID(member)
+	memberData, _ := framework.GetState(string(memberStateID))
+	if len(memberData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq := decodeMember(memberData)
+	newStatus, ok := memberStatusTransition(action, status)
+	if !ok {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 3. 更新成员状态（保留统计与激活序号）
+	newMemberData := encodeMember(newStatus, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq)
+	if code := appendVersionedState(memberStateID, newMemberData); code != framework.SUCCESS {
+		return code
+	}
+
+	// 4. 进入或离开 ACTIVE 时更新成员计数与活跃成员集合
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount := framework.BytesToUint64(memberCountData)
+	newMemberCount := memberCount
+	wasActive, isActive := status == MEMBER_STATUS_ACTIVE, newStatus == MEMBER_STATUS_ACTIVE
+	if wasActive != isActive {
+		if isActive {
+			newMemberCount = memberCount + 1
+		} else if memberCount > 0 {
+			newMemberCount = memberCount - 1
+		}
+		if newMemberCount != memberCount {
+			if code := appendVersionedState([]byte(planKey(STATE_MEMBER_COUNT)), framework.Uint64ToBytes(newMemberCount)); code != framework.SUCCESS {
+				return code
+			}
+		}
+		if code := adjustTierCount(tier, isActive); code != framework.SUCCESS {
+			return code
+		}
+		updateActiveSet := removeActiveMember
+		if isActive {
+			updateActiveSet = addActiveMember
+		}
+		if code := updateActiveSet(member); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 5. 发出事件
+	event := framework.NewEvent(memberStatusEvent(action))
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", member)
+	event.AddStringField("previous_status", status)
+	event.AddStringField("status", newStatus)
+	event.AddStringField("reason", reason)
+	event.AddAddressField("operator", framework.GetCaller())
+	event.AddIntField("member_count_active", newMemberCount)
+	framework.EmitEvent(event)
+
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":             planID,
+		"member":              member.ToString(),
+		"status":              newStatus,
+		"previous_status":     status,
+		"reason":              reason,
+		"join_time":           joinTime,
+		"total_paid":          totalPaid,
+		"total_received":      totalReceived,
+		"arrears_amount":      arrearsAmount,
+		"tier":                tier,
+		"member_count_active": newMemberCount,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SetMemberCap 设置成员个人月度分摊上限（仅 operator 可调用）
+//
+// 计划的 monthly_cap_per_member 为全局默认值，高风险或高等级成员可单独设置上限。
+// PayContribution 优先使用个人上限；cap 为 0 表示清除覆盖，恢复计划默认上限。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1...",                 // 成员地址（Base58）
+//	  "cap": 20000                        // 个人月度上限，0 表示清除覆盖
+//	}
+//
+// 输出：
+// - StateOutput: member_cap_{address}
+// - Event: MutualAidMemberCapSet
+//
+//export SetMemberCap
+func SetMemberCap() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	memberStr := params.ParseJSON("member")
+	memberCap := params.ParseJSONInt("cap")
+	if planID == "" || memberStr == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	member, err := framework.ParseAddressBase58(memberStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 检查成员是否存在
+	memberData, _ := framework.GetState(string(getMemberStateID(member)))
+	if len(memberData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	// 3. 写入个人上限覆盖
+	memberCapStateID := getMemberCapStateID(member)
+	version, err := framework.IncrementStateVersion(memberCapStateID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if _, err := framework.AppendStateOutputSimple(memberCapStateID, version, framework.Uint64ToBytes(memberCap), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 4. 计算生效上限
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	var planMonthlyCap uint64 = DEFAULT_MONTHLY_CAP_PER_MEMBER
+	if len(configData) > 0 {
+		_, _, _, _, _, _, _, _, planMonthlyCap = decodePlanConfig(configData)
+	}
+	effectiveCap := effectiveMonthlyCap(planMonthlyCap, memberCap)
+
+	// 5. 发出事件
+	event := framework.NewEvent("MutualAidMemberCapSet")
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", member)
+	event.AddIntField("cap", memberCap)
+	event.AddIntField("effective_cap", effectiveCap)
+	framework.EmitEvent(event)
+
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":                planID,
+		"member":                 member.ToString(),
+		"cap":                    memberCap,
+		"monthly_cap_per_member": planMonthlyCap,
+		"effective_cap":          effectiveCap,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// ExcludeCategoryForMember 为成员设置保障类别除外（仅 operator 可调用）
+//
+// 用于核保除外（如既往症）：被除外的成员作为被保人时不能在该类别下报案，
+// SubmitClaim 返回 ERROR_PERMISSION_DENIED 及除外原因。同一类别再次设置时更新原因与时间。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "member": "Cf1...",                 // 成员地址（Base58）
+//	  "category_id": "critical_illness",  // 须为计划已配置的类别
+//	  "reason": "pre-existing condition"  // 除外原因（必填，不超过 MAX_EXCLUSION_REASON_SIZE 字节）
+//	}
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: 参数缺失、原因超长或类别未配置
+// - ERROR_NOT_FOUND: 成员不存在
+//
+// 输出：
+// - StateOutput: member_exclusions_{address}
+// - Event: MutualAidCategoryExcluded
+//
+//export ExcludeCategoryForMember
+func ExcludeCategoryForMember() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	memberStr := params.ParseJSON("member")
+	categoryID := params.ParseJSON("category_id")
+	reason := params.ParseJSON("reason")
+	if planID == "" || memberStr == "" || categoryID == "" || reason == "" || len(reason) > MAX_EXCLUSION_REASON_SIZE {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	member, err := framework.ParseAddressBase58(memberStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 检查类别已配置、成员存在
+	if _, ok := findCoverageCategory(loadCoverageCategories(), categoryID); !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	memberData, _ := framework.GetState(string(getMemberStateID(member)))
+	if len(memberData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	// 3. 写入类别除外
+	excludedAt := framework.GetTimestamp()
+	exclusions := upsertExclusion(loadMemberExclusions(member), memberExclusion{CategoryID: categoryID, Reason: reason, ExcludedAt: excludedAt})
+	if code := appendVersionedState(getMemberExclusionsStateID(member), encodeExclusions(exclusions)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 4. 发出事件
+	event := framework.NewEvent(EVENT_CATEGORY_EXCLUDED)
+	event.AddStringField("plan_id", planID)
+	event.AddAddressField("member", member)
+	event.AddStringField("category_id", categoryID)
+	event.AddStringField("reason", reason)
+	event.AddIntField("excluded_at", excludedAt)
+	event.AddAddressField("operator", framework.GetCaller())
+	framework.EmitEvent(event)
+
+	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":     planID,
+		"member":      member.ToString(),
+		"category_id": categoryID,
+		"reason":      reason,
+		"excluded_at": excludedAt,
+		"exclusions":  exclusionItems(exclusions),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SetTierMultiplier 设置档位分摊系数（仅 operator 可调用）
+//
+// 分档计划按保障等级收费：成员本轮应缴 = per_capita_contribution * multiplier_bp / 10000。
+// 未配置的档位系数为 10000（1倍）。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "tier": 2,                          // 档位（0 ~ MAX_TIERS-1）
+//	  "multiplier_bp": 30000              // 分摊系数（bp），30000 = 3倍，0 表示恢复为1倍
+//	}
+//
+// 输出：
+// - StateOutput: tier_multiplier_{tier}
+// - Event: MutualAidTierMultiplierSet
+//
+//export SetTierMultiplier
+func SetTierMultiplier() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	tier := params.ParseJSONInt("tier")
+	multiplierBP := params.ParseJSONInt("multiplier_bp")
+	if planID == "" || tier >= MAX_TIERS {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 写入档位系数
+	if code := appendVersionedState(getTierMultiplierStateID(tier), framework.Uint64ToBytes(multiplierBP)); code != framework.SUCCESS {
+		return code
+	}
+	effectiveBP := tierMultiplier(multiplierBP)
+
+	// 3. 发出事件
+	event := framework.NewEvent("MutualAidTierMultiplierSet")
+	event.AddStringField("plan_id", planID)
+	event.AddIntField("tier", tier)
+	event.AddIntField("multiplier_bp", effectiveBP)
+	framework.EmitEvent(event)
+
+	// 4. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	tierCountData, _ := framework.GetState(string(getTierCountStateID(tier)))
+	result := map[string]interface{}{
+		"plan_id":           planID,
+		"tier":              tier,
+		"multiplier_bp":     effectiveBP,
+		"member_count_tier": framework.BytesToUint64(tierCountData),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SetFeeAdjustment 设置服务费调整模式（仅 operator 可调用）
+//
+// 模式：
+//   - FIXED: 结算时使用计划配置的 service_fee_bp（默认）
+//   - CLAIMS_RATIO: 结算时按历史赔付率（cumulative_paid / cumulative_collected）
+//     在 [min_fee_bp, max_fee_bp] 区间内线性调整：fee = min + (max - min) * ratio / 10000
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "mode": "CLAIMS_RATIO",             // FIXED / CLAIMS_RATIO
+//	  "min_fee_bp": 300,                  // CLAIMS_RATIO 时必填，最低费率
+//	  "max_fee_bp": 1500                  // CLAIMS_RATIO 时必填，最高费率（不超过10000）
+//	}
+//
+// 输出：
+// - StateOutput: fee_adjustment
+// - Event: MutualAidFeeAdjustmentSet
+//
+//export SetFeeAdjustment
+func SetFeeAdjustment() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	mode := params.ParseJSON("mode")
+	minFeeBP := params.ParseJSONInt("min_fee_bp")
+	maxFeeBP := params.ParseJSONInt("max_fee_bp")
+	if planID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	switch mode {
+	case FEE_MODE_FIXED:
+		minFeeBP, maxFeeBP = 0, 0
+	case FEE_MODE_CLAIMS_RATIO:
+		if minFeeBP > maxFeeBP || maxFeeBP > 10000 { // 服务费率不能超过100%
+			return framework.ERROR_INVALID_PARAMS
+		}
+	default:
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 写入配置
+	if code := appendVersionedState([]byte(planKey(STATE_FEE_ADJUSTMENT)), encodeFeeAdjustment(mode, minFeeBP, maxFeeBP)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 3. 计算当前生效费率
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	var serviceFeeBP uint64
+	if len(configData) > 0 {
+		_, _, _, _, serviceFeeBP, _, _, _, _ = decodePlanConfig(configData)
+	}
+	collectedData, _ := framework.GetState(planKey(STATE_CUMULATIVE_COLLECTED))
+	paidData, _ := framework.GetState(planKey(STATE_CUMULATIVE_PAID))
+	effectiveFeeBP, claimsRatio := effectiveServiceFeeBP(mode, serviceFeeBP, minFeeBP, maxFeeBP, framework.BytesToUint64(paidData), framework.BytesToUint64(collectedData))
+
+	// 4. 发出事件
+	event := framework.NewEvent("MutualAidFeeAdjustmentSet")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("mode", mode)
+	event.AddIntField("min_fee_bp", minFeeBP)
+	event.AddIntField("max_fee_bp", maxFeeBP)
+	event.AddIntField("effective_service_fee_bp", effectiveFeeBP)
+	framework.EmitEvent(event)
+
+	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":                  planID,
+		"mode":                     mode,
+		"min_fee_bp":               minFeeBP,
+		"max_fee_bp":               maxFeeBP,
+		"service_fee_bp":           serviceFeeBP,
+		"effective_service_fee_bp": effectiveFeeBP,
+		"claims_ratio_bp":          claimsRatio,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SetRoundingMode 设置人均分摊取整方式（仅 operator 可调用）
+//
+// 取整方向：
+//   - UP: 向上取整（默认），成员多缴，产生取整盈余
+//   - NEAREST: 四舍五入（半数向上）
+//   - DOWN: 向下取整，成员少缴，产生取整缺口
+//
+// 人均分摊取整到 10^decimals 的整数倍。每次结算的取整余额
+// （按人均分摊可收总额 - 应收总额）累计到 rounding_carry：
+// carry_forward 为 true 时在下一次结算中抵扣盈余或补收缺口，否则只记录，供 operator 退还或核销。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "mode": "NEAREST",                  // UP / NEAREST / DOWN
+//	  "decimals": 2,                      // 可选，取整位数（0-6），默认0
+//	  "carry_forward": "true"             // 可选，是否结转取整余额，默认不结转
+//	}
+//
+// 输出：
+// - StateOutput: rounding_config
+// - Event: MutualAidRoundingModeSet
+//
+//export SetRoundingMode
+func SetRoundingMode() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	cfg := roundingConfig{
+		Mode:         params.ParseJSON("mode"),
+		Decimals:     params.ParseJSONInt("decimals"),
+		CarryForward: params.ParseJSON("carry_forward") == "true",
+	}
+	if planID == "" || !validRoundingMode(cfg.Mode) || cfg.Decimals > MAX_ROUNDING_DECIMALS {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 写入配置
+	if code := appendVersionedState([]byte(planKey(STATE_ROUNDING_CONFIG)), encodeRoundingConfig(cfg)); code != framework.SUCCESS {
+		return code
+	}
+	carryData, _ := framework.GetState(planKey(STATE_ROUNDING_CARRY))
+	carry := framework.BytesToInt64(carryData)
+
+	// 3. 发出事件
+	event := framework.NewEvent("MutualAidRoundingModeSet")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("mode", cfg.Mode)
+	event.AddIntField("decimals", cfg.Decimals)
+	event.AddBoolField("carry_forward", cfg.CarryForward)
+	framework.EmitEvent(event)
+
+	// 4. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":        planID,
+		"mode":           cfg.Mode,
+		"decimals":       cfg.Decimals,
+		"carry_forward":  cfg.CarryForward,
+		"rounding_carry": carry,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// SubmitClaim 提交互助申请（报案）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "claim_id": "claim_202501_0001",    // 字母、数字、'_' 与 '-'，最长 32 字节
+//	  "insured": "Cf1...",                // 被保人地址（Base58），可为空表示即为调用者
+//	  "requested_amount": 300000,
+//	  "event_time": 1736200000,           // 出险时间（时间戳）
+//	  "evidence_hash": "0xabc...",        // 资料哈希
+//	  "category_id": "critical_illness",  // 保障类别（计划配置了类别时必填）
+//	  "extra": "optional comments"
+//	}
+//
+// 计划配置了类别时：
+// - category_id 缺失或未配置返回 ERROR_INVALID_PARAMS
+// - 被保人在该类别下被除外返回 ERROR_PERMISSION_DENIED，
+//   返回值为 {"error":"CATEGORY_EXCLUDED","category_id":...,"reason":...,"excluded_at":...}
+// - requested_amount 超过类别 per_claim_limit 返回 ERROR_INVALID_PARAMS（等于上限可以报案）
+// - 等待期按类别 waiting_period 计算，未满返回 ERROR_INVALID_STATE
+// 拒绝时返回值均为 {"error": 拒绝原因, ...}。
+//
+// 输出：
+// - StateOutput: claim:{claim_id}
+// - StateOutput: claim_category:{claim_id}（指定类别时，记录类别与出险年份）
+// - Event: MutualAidClaimSubmitted（同时追加到事件日志 index:event_log）
+//
+//export SubmitClaim
+func SubmitClaim() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	claimID := params.ParseJSON("claim_id")
+	insuredStr := params.ParseJSON("insured")
+	requestedAmount := params.ParseJSONInt("requested_amount")
+	eventTime := params.ParseJSONInt("event_time")
+	evidenceHash := params.ParseJSON("evidence_hash")
+	categoryID := params.ParseJSON("category_id")
+	extra := params.ParseJSON("extra")
+
+	if planID == "" || !validClaimID(claimID) || requestedAmount <= 0 || eventTime <= 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	applicant := framework.GetCaller()
+	var insured framework.Address
+	if insuredStr != "" {
+		var err error
+		insured, err = framework.ParseAddressBase58(insuredStr)
+		if err != nil {
+			return framework.ERROR_INVALID_PARAMS
+		}
+	} else {
+		insured = applicant
+	}
+
+	// 1. 检查申请人是否为ACTIVE成员
+	memberData, code := requireActiveMember(applicant)
+	if code != framework.SUCCESS {
+		return code
+	}
+	_, joinTime, _, _, _, _, _, _ := decodeMember(memberData)
+
+	// 2. 检查保障类别（配置了类别时，等待期按类别计算）
+	currentTime := framework.GetTimestamp()
+	categories := loadCoverageCategories()
+	exclusion, excluded := findExclusion(loadMemberExclusions(insured), categoryID)
+	category, rejectReason := checkClaimCategory(categories, categoryID, excluded, requestedAmount, joinTime, currentTime)
+	switch rejectReason {
+	case "":
+	case CATEGORY_REJECT_EXCLUDED:
+		return rejectWithDetail(framework.ERROR_PERMISSION_DENIED, map[string]interface{}{
+			"error":       rejectReason,
+			"category_id": categoryID,
+			"reason":      exclusion.Reason,
+			"excluded_at": exclusion.ExcludedAt,
+		})
+	case CATEGORY_REJECT_WAITING_PERIOD:
+		return rejectWithDetail(framework.ERROR_INVALID_STATE, map[string]interface{}{
+			"error":          rejectReason,
+			"category_id":    categoryID,
+			"waiting_period": category.WaitingPeriod,
+			"eligible_at":    joinTime + category.WaitingPeriod,
+		})
+	case CATEGORY_REJECT_PER_CLAIM_LIMIT:
+		return rejectWithDetail(framework.ERROR_INVALID_PARAMS, map[string]interface{}{
+			"error":           rejectReason,
+			"category_id":     categoryID,
+			"per_claim_limit": category.PerClaimLimit,
+			"requested":       requestedAmount,
+		})
+	default:
+		return rejectWithDetail(framework.ERROR_INVALID_PARAMS, map[string]interface{}{
+			"error":       rejectReason,
+			"category_id": categoryID,
+		})
+	}
+
+	// 3. 未配置类别时检查计划等待期（简化：仅检查加入时间）
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(categories) == 0 && len(configData) > 0 {
+		_, _, _, _, _, _, waitingPeriod, _, _ := decodePlanConfig(configData)
+		if currentTime < joinTime+waitingPeriod {
+			return framework.ERROR_INVALID_STATE // 等待期未满
+		}
+	}
+
+	// 4. 检查案件是否已存在
+	claimStateID := getClaimStateID(claimID)
+	existingClaimData, _ := framework.GetState(string(claimStateID))
+	if len(existingClaimData) > 0 {
+		return framework.ERROR_ALREADY_EXISTS
+	}
+
+	// 5. 创建案件记录
+	claimData := encodeClaim(planID, claimID, string(applicant.ToBytes()), string(insured.ToBytes()), CLAIM_STATUS_SUBMITTED, "", evidenceHash, "", requestedAmount, 0, eventTime)
+	if _, err := framework.AppendStateOutputSimple(claimStateID, 1, claimData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if categoryID != "" {
+		claimCategory := make([]byte, COVERAGE_CATEGORY_ID_SIZE+8)
+		copy(claimCategory, categoryID)
+		copy(claimCategory[COVERAGE_CATEGORY_ID_SIZE:], framework.Uint64ToBytes(calendarYear(eventTime)))
+		if _, err := framework.AppendStateOutputSimple(getClaimCategoryStateID(claimID), 1, claimCategory, nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
+	// 6. 发出事件
+	event := framework.NewEvent("MutualAidClaimSubmitted")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("claim_id", claimID)
+	event.AddAddressField("applicant", applicant)
+	event.AddAddressField("insured", insured)
+	event.AddIntField("requested_amount", requestedAmount)
+	event.AddIntField("event_time", eventTime)
+	event.AddStringField("evidence_hash", evidenceHash)
+	event.AddStringField("category_id", categoryID)
+	event.AddStringField("extra", extra)
+	if code := emitAuditEvent(event); code != framework.SUCCESS {
+		return code
+	}
+
+	// 7. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":          planID,
+		"claim_id":         claimID,
+		"applicant":        applicant.ToString(),
+		"insured":          insured.ToString(),
+		"status":           CLAIM_STATUS_SUBMITTED,
+		"requested_amount": requestedAmount,
+		"approved_amount":  uint64(0),
+		"event_time":       eventTime,
+		"evidence_hash":    evidenceHash,
+		"category_id":      categoryID,
+		"round_id":         "",
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// AttachEvidence 为案件追加补充材料（申请人或被保人调用）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "claim_id": "claim_202501_0001",
+//	  "attachment": "ipfs://bafy..."      // 材料哈希或链接，不超过 256 字节
+//	}
+//
+// 限制：
+// - 每个调用者在每个案件下最多 16 条附件，超出或单条超长返回 ERROR_QUOTA_EXCEEDED
+// - 单次调用状态写入不超过 ATTACH_EVIDENCE_WRITE_BUDGET 字节
+// - 仅 SUBMITTED / UNDER_REVIEW 状态的案件可追加
+//
+// 输出：
+// - StateOutput: index:claim_evidence:{plan_id}:{claim_id}:{caller}:{seq} 及分区计数
+// - Event: MutualAidEvidenceAttached
+//
+//export AttachEvidence
+func AttachEvidence() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	framework.DeclareWriteBudget(ATTACH_EVIDENCE_WRITE_BUDGET)
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	claimID := params.ParseJSON("claim_id")
+	attachment := params.ParseJSON("attachment")
+	if planID == "" || claimID == "" || attachment == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 1. 检查案件存在且仍在审核中
+	claimData, _ := framework.GetState(string(getClaimStateID(claimID)))
+	if len(claimData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+	cPlanID, _, applicant, insured, status, _, _, _, _, _, _ := decodeClaim(claimData)
+	if cPlanID != planID {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if !canAttachEvidence(status) {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 2. 仅申请人或被保人可追加
+	caller := framework.GetCaller()
+	callerBytes := string(caller.ToBytes())
+	if callerBytes != applicant && callerBytes != insured {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 3. 按索引配额追加附件
+	seq, err := framework.AppendIndexEntry(EVIDENCE_INDEX, evidencePartition(planID, claimID, caller.ToBytes()), []byte(attachment))
+	if err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 4. 发出事件
+	event := framework.NewEvent("MutualAidEvidenceAttached")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("claim_id", claimID)
+	event.AddAddressField("submitter", caller)
+	event.AddIntField("seq", seq)
+	event.AddStringField("attachment", attachment)
+	framework.EmitEvent(event)
+
+	// 5. 返回业务结果
+	result := map[string]interface{}{
+		"plan_id":     planID,
+		"claim_id":    claimID,
+		"submitter":   caller.ToString(),
+		"seq":         seq,
+		"count":       seq + 1,
+		"max_entries": uint64(MAX_EVIDENCE_PER_CLAIM),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// ReviewClaim 审核互助申请（仅 operator 可调用）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "claim_id": "claim_202501_0001",
+//	  "decision": "APPROVE",              // APPROVE / REJECT
+//	  "approved_amount": 280000,          // 决定给付金额，REJECT 时可为 0
+//	  "reason": "符合互助规则",
+//	  "investigation_hash": "0xdef...",  // 调查报告哈希
+//	  "review_round_id": "round_202501_01" // APPROVE 时必填，须为 OPEN 状态的轮次
+//	}
+//
+// 批准时将案件归入 review_round_id 轮次：校验轮次存在且为 OPEN，
+// 并将 claim_id 追加到 round_claims:{round_id} 索引，供 SettleRound 汇总。
+// 轮次不存在返回 ERROR_NOT_FOUND，轮次非 OPEN 或索引已满返回 ERROR_INVALID_STATE。
+//
+// 案件指定了保障类别时，批准金额计入被保人该类别出险年份的已批准额度；
+// 超过类别 annual_limit 返回 ERROR_QUOTA_EXCEEDED，
+// 返回值为 {"error":"ANNUAL_LIMIT_EXCEEDED","category_id":...,"annual_limit":...,"approved":...,...}。
+//
+// 输出：
+// - StateOutput: claim:{claim_id} (更新状态)
+// - StateOutput: round_claims:{round_id} (APPROVE 时追加)
+// - StateOutput: claims_approved_unpaid (APPROVE 时加一)
+// - StateOutput: category_usage_{address}_{category_id}_{year} (APPROVE 且案件指定类别时)
+// - Event: MutualAidClaimReviewed（同时追加到事件日志 index:event_log）
+//
+//export ReviewClaim
+func ReviewClaim() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	claimID := params.ParseJSON("claim_id")
+	decision := params.ParseJSON("decision")
+	approvedAmount := params.ParseJSONInt("approved_amount")
+	reason := params.ParseJSON("reason")
+	investigationHash := params.ParseJSON("investigation_hash")
+	reviewRoundID := params.ParseJSON("review_round_id")
+
+	if planID == "" || claimID == "" || decision == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	if decision != DECISION_APPROVE && decision != DECISION_REJECT {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 读取案件
+	claimStateID := getClaimStateID(claimID)
+	claimData, _ := framework.GetState(string(claimStateID))
+	if len(claimData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+
+	cPlanID, cClaimID, applicant, insured, status, _, evidenceHash, _, requestedAmount, _, eventTime := decodeClaim(claimData)
+
+	// 3. 检查案件状态
+	if status != CLAIM_STATUS_SUBMITTED && status != CLAIM_STATUS_UNDER_REVIEW {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 4. 确定审核结果
+	newStatus := CLAIM_STATUS_APPROVED
+	if decision == DECISION_REJECT {
+		newStatus = CLAIM_STATUS_REJECTED
+		approvedAmount = 0
+	}
+
+	// 检查批准金额不超过请求金额
+	if decision == DECISION_APPROVE && approvedAmount > requestedAmount {
+		approvedAmount = requestedAmount
+	}
+
+	// 5. 批准时将案件归入 OPEN 轮次
+	roundClaimsCount := 0
+	if decision == DECISION_APPROVE {
+		if reviewRoundID == "" {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		roundData, _ := framework.GetState(string(getRoundStateID(reviewRoundID)))
+		if len(framework.TrimNull(roundData)) == 0 {
+			return framework.ERROR_NOT_FOUND
+		}
+		_, _, roundStatus, _, _, _, _, _, _, _ := decodeRound(roundData)
+		if roundStatus != ROUND_STATUS_OPEN {
+			return framework.ERROR_INVALID_STATE
+		}
+
+		roundClaimsStateID := getRoundClaimsStateID(reviewRoundID)
+		roundClaimsData, _ := framework.GetState(string(roundClaimsStateID))
+		index, count, ok := appendRoundClaim(roundClaimsData, cClaimID)
+		if !ok {
+			return framework.ERROR_INVALID_STATE
+		}
+		if code := appendVersionedState(roundClaimsStateID, index); code != framework.SUCCESS {
+			return code
+		}
+		if code := adjustApprovedUnpaid(1, 0); code != framework.SUCCESS {
+			return code
+		}
+		roundClaimsCount = count
+	}
+
+	// 6. 批准时占用类别年度额度
+	if decision == DECISION_APPROVE {
+		if c, ok := loadClaimCategoryUsage(cClaimID); ok {
+			usage, ok := c.Usage.reserveApproval(c.Category.AnnualLimit, approvedAmount)
+			if !ok {
+				return rejectWithDetail(framework.ERROR_QUOTA_EXCEEDED, annualLimitDetail(c, cClaimID, approvedAmount))
+			}
+			if code := appendVersionedState(c.StateID, encodeCategoryUsage(usage)); code != framework.SUCCESS {
+				return code
+			}
+		}
+	}
+
+	// 7. 更新案件状态
+	newClaimData := encodeClaim(cPlanID, cClaimID, applicant, insured, newStatus, reviewRoundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime)
+	if _, err := framework.AppendStateOutputSimple(claimStateID, 2, newClaimData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 8. 发出事件
+	event := framework.NewEvent("MutualAidClaimReviewed")
 	event.AddStringField("plan_id", planID)
-	event.AddStringField("name", name)
-	event.AddStringField("token_id", tokenID)
-	event.AddIntField("coverage_amount", coverageAmount)
-	event.AddIntField("service_fee_bp", serviceFeeBP)
-	event.AddIntField("settlement_period", settlementPeriod)
-	event.AddIntField("waiting_period", waitingPeriod)
-	event.AddIntField("min_members", minMembers)
-	event.AddIntField("monthly_cap_per_member", monthlyCapPerMember)
-	event.AddAddressField("operator", caller)
-	framework.EmitEvent(event)
+	event.AddStringField("claim_id", claimID)
+	event.AddStringField("decision", decision)
+	event.AddIntField("approved_amount", approvedAmount)
+	event.AddStringField("reason", reason)
+	event.AddStringField("investigation_hash", investigationHash)
+	event.AddStringField("review_round_id", reviewRoundID)
+	if decision == DECISION_APPROVE {
+		event.AddStringField("assigned_round_id", reviewRoundID)
+		event.AddIntField("round_claims_count", uint64(roundClaimsCount))
+	}
+	event.AddAddressField("reviewer", framework.GetCaller())
+	if code := emitAuditEvent(event); code != framework.SUCCESS {
+		return code
+	}
 
-	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 9. 返回业务结果（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":                planID,
-		"name":                   name,
-		"token_id":               tokenID,
-		"coverage_amount":        coverageAmount,
-		"service_fee_bp":         serviceFeeBP,
-		"settlement_period":      settlementPeriod,
-		"waiting_period":         waitingPeriod,
-		"min_members":            minMembers,
-		"monthly_cap_per_member": monthlyCapPerMember,
-		"operator":               caller.ToString(),
-		"member_count_active":    uint64(0),
-		"initialized_at":         framework.GetTimestamp(),
+		"plan_id":            cPlanID,
+		"claim_id":           cClaimID,
+		"applicant":          addressBytesToString([]byte(applicant)),
+		"insured":            addressBytesToString([]byte(insured)),
+		"status":             newStatus,
+		"requested_amount":   requestedAmount,
+		"approved_amount":    approvedAmount,
+		"event_time":         eventTime,
+		"evidence_hash":      evidenceHash,
+		"investigation_hash": investigationHash,
+		"round_id":           reviewRoundID,
+		"decision":           decision,
+		"reason":             reason,
+		"round_claims_count": uint64(roundClaimsCount),
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -805,76 +3132,346 @@ func Initialize() uint32 {
 	return framework.SUCCESS
 }
 
-// Join 成为互助计划成员
+// BatchReviewClaims 批量审核互助申请（仅 operator 可调用）
 //
 // 参数（JSON）：
 //
 //	{
-//	  "plan_id": "plan_xianghubao_001"
+//	  "plan_id": "plan_xianghubao_001",
+//	  "review_round_id": "round_202501_01", // 含 APPROVE 时必填，须为 OPEN 状态的轮次
+//	  "decisions": [
+//	    {"claim_id": "claim_202501_0001", "decision": "APPROVE", "approved_amount": 280000, "reason": "符合互助规则"},
+//	    {"claim_id": "claim_202501_0002", "decision": "REJECT", "approved_amount": 0, "reason": "不在保障范围"}
+//	  ]
 //	}
 //
-// 输出：
-// - StateOutput: member_{address}
-// - StateOutput: member_count_active (更新)
-// - Event: MutualAidMemberJoined
+// 逐项应用审核决定，规则与 ReviewClaim 一致；非 SUBMITTED 状态、不存在、重复或超过类别年度累计上限
+// 的案件跳过而不中断整批，跳过原因记录在返回结果的 skip_reason 中。
+// decisions 为空或超过 MAX_BATCH_REVIEW_SIZE 返回 ERROR_INVALID_PARAMS；
+// 含 APPROVE 时轮次校验同 ReviewClaim。
 //
-//export Join
-func Join() uint32 {
+// 输出：
+// - StateOutput: claim:{claim_id} (每个已应用的案件)
+// - StateOutput: round_claims:{round_id} (有批准案件时一次性写入)
+// - StateOutput: claims_approved_unpaid (加上本批批准的案件数)
+// - StateOutput: category_usage_{address}_{category_id}_{year} (批准指定类别的案件时，每个额度记录写入一次)
+// - Event: MutualAidClaimReviewed（每个已应用的案件，同时追加到事件日志 index:event_log）
+// - Event: MutualAidClaimsBatchReviewed（含逐项结果 results；超过事件大小上限时
+//   完整内容写入 event_payload:{hash}，改为发出 EventPayloadAnchored）
+//
+//export BatchReviewClaims
+func BatchReviewClaims() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	reviewRoundID := params.ParseJSON("review_round_id")
+	decisions, ok := parseReviewDecisions(string(params.GetRawData()))
+	if planID == "" || !ok || len(decisions) == 0 || len(decisions) > MAX_BATCH_REVIEW_SIZE {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 2. 含批准决定时校验轮次
+	hasApproval := false
+	for _, d := range decisions {
+		if d.Decision == DECISION_APPROVE {
+			hasApproval = true
+			break
+		}
+	}
+	var roundClaimsData []byte
+	roundClaimsStateID := getRoundClaimsStateID(reviewRoundID)
+	if hasApproval {
+		if reviewRoundID == "" {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		roundData, _ := framework.GetState(string(getRoundStateID(reviewRoundID)))
+		if len(framework.TrimNull(roundData)) == 0 {
+			return framework.ERROR_NOT_FOUND
+		}
+		_, _, roundStatus, _, _, _, _, _, _, _ := decodeRound(roundData)
+		if roundStatus != ROUND_STATUS_OPEN {
+			return framework.ERROR_INVALID_STATE
+		}
+		roundClaimsData, _ = framework.GetState(string(roundClaimsStateID))
+	}
+
+	// 3. 计算每项审核结果（轮次案件索引与类别年度额度在内存中累积，最后一次写入）
+	lookup := func(claimID string) (string, uint64, bool) {
+		claimData, _ := framework.GetState(string(getClaimStateID(claimID)))
+		if len(framework.TrimNull(claimData)) == 0 {
+			return "", 0, false
+		}
+		_, _, _, _, status, _, _, _, requestedAmount, _, _ := decodeClaim(claimData)
+		return status, requestedAmount, true
+	}
+	var usageUpdates []claimCategoryUsage
+	reserve := func(claimID string, approvedAmount uint64) bool {
+		c, ok := loadClaimCategoryUsage(claimID)
+		if !ok {
+			return true
+		}
+		pos := -1
+		for i := range usageUpdates {
+			if string(usageUpdates[i].StateID) == string(c.StateID) {
+				pos = i
+				c.Usage = usageUpdates[i].Usage
+				break
+			}
+		}
+		usage, ok := c.Usage.reserveApproval(c.Category.AnnualLimit, approvedAmount)
+		if !ok {
+			return false
+		}
+		c.Usage = usage
+		if pos < 0 {
+			usageUpdates = append(usageUpdates, c)
+		} else {
+			usageUpdates[pos] = c
+		}
+		return true
+	}
+	results, index, roundClaimsCount := planBatchReview(decisions, lookup, roundClaimsData, reserve)
+
+	// 4. 写入已应用的案件并逐项发出事件
+	appliedCount, approvedCount := 0, 0
+	items := batchReviewItems(results)
+	for _, r := range results {
+		if r.Outcome != BATCH_REVIEW_APPLIED {
+			continue
+		}
+		appliedCount++
+
+		claimStateID := getClaimStateID(r.ClaimID)
+		claimData, _ := framework.GetState(string(claimStateID))
+		cPlanID, cClaimID, applicant, insured, _, _, evidenceHash, investigationHash, requestedAmount, _, eventTime := decodeClaim(claimData)
+		roundID := ""
+		if r.NewStatus == CLAIM_STATUS_APPROVED {
+			roundID = reviewRoundID
+			approvedCount++
+		}
+		newClaimData := encodeClaim(cPlanID, cClaimID, applicant, insured, r.NewStatus, roundID, evidenceHash, investigationHash, requestedAmount, r.ApprovedAmount, eventTime)
+		if _, err := framework.AppendStateOutputSimple(claimStateID, 2, newClaimData, nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
 
+		event := framework.NewEvent("MutualAidClaimReviewed")
+		event.AddStringField("plan_id", planID)
+		event.AddStringField("claim_id", r.ClaimID)
+		event.AddStringField("decision", r.Decision)
+		event.AddIntField("approved_amount", r.ApprovedAmount)
+		event.AddStringField("reason", r.Reason)
+		event.AddStringField("review_round_id", roundID)
+		if r.NewStatus == CLAIM_STATUS_APPROVED {
+			event.AddStringField("assigned_round_id", reviewRoundID)
+		}
+		event.AddAddressField("reviewer", framework.GetCaller())
+		if code := emitAuditEvent(event); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 5. 写入轮次案件索引与类别年度额度
+	if approvedCount > 0 {
+		if code := appendVersionedState(roundClaimsStateID, index); code != framework.SUCCESS {
+			return code
+		}
+		if code := adjustApprovedUnpaid(uint64(approvedCount), 0); code != framework.SUCCESS {
+			return code
+		}
+	}
+	for _, c := range usageUpdates {
+		if code := appendVersionedState(c.StateID, encodeCategoryUsage(c.Usage)); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 6. 发出批量汇总事件（含逐项结果，超过事件大小上限时锚定）
+	skippedCount := len(results) - appliedCount
+	event := framework.NewEvent(EVENT_CLAIMS_BATCH_REVIEWED)
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("review_round_id", reviewRoundID)
+	event.AddIntField("applied_count", uint64(appliedCount))
+	event.AddIntField("skipped_count", uint64(skippedCount))
+	event.AddIntField("round_claims_count", uint64(roundClaimsCount))
+	event.AddAddressField("reviewer", framework.GetCaller())
+	event.AddField("results", items)
+	if _, err := framework.EmitEventOrAnchor(event); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 7. 返回逐项结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":            planID,
+		"review_round_id":    reviewRoundID,
+		"applied_count":      uint64(appliedCount),
+		"skipped_count":      uint64(skippedCount),
+		"round_claims_count": uint64(roundClaimsCount),
+		"results":            items,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// CancelClaim 取消互助申请（申请人本人或 operator）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "claim_id": "claim_202501_0001",
+//	  "reason": "重复提交"   // operator 取消时必填，最长 MAX_CLAIM_CANCEL_REASON_SIZE 字节
+//	}
+//
+// 申请人可取消 SUBMITTED / UNDER_REVIEW 的本人案件；operator 可取消任何给付前（SUBMITTED /
+// UNDER_REVIEW / APPROVED）的案件。其他调用者返回 ERROR_UNAUTHORIZED，申请人取消已批准案件
+// 或案件已为终态（REJECTED / PAID / CANCELLED）返回 ERROR_INVALID_STATE。
+//
+// 取消已批准的案件时：claims_approved_unpaid 减一，释放类别年度已批准额度；案件所在轮次
+// 已结算时从轮次 total_approved_payout 中扣除批准金额（轮次仍为 OPEN 时结算自然不计入）。
+//
+// 输出：
+// - StateOutput: claim:{claim_id} (状态更新为 CANCELLED)
+// - StateOutput: claims_approved_unpaid (取消已批准案件时减一)
+// - StateOutput: category_usage_{address}_{category_id}_{year} (取消已批准且指定类别的案件时)
+// - StateOutput: round:{round_id} (取消已批准案件且轮次已结算时)
+// - Event: MutualAidClaimCancelled（同时追加到事件日志 index:event_log）
+//
+//export CancelClaim
+func CancelClaim() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
 	planID := params.ParseJSON("plan_id")
-	if planID == "" {
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	claimID := params.ParseJSON("claim_id")
+	reason := params.ParseJSON("reason")
+	if planID == "" || claimID == "" || len(reason) > MAX_CLAIM_CANCEL_REASON_SIZE {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 1. 读取案件
+	claimStateID := getClaimStateID(claimID)
+	claimData, _ := framework.GetState(string(claimStateID))
+	if len(claimData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+	cPlanID, cClaimID, applicant, insured, status, roundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime := decodeClaim(claimData)
+	if cPlanID != planID {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
+	// 2. 权限检查：申请人在可自行取消的状态下按申请人处理，否则须为 operator
 	caller := framework.GetCaller()
-	memberStateID := getMemberStateID(caller)
+	isApplicant := string(caller.ToBytes()) == applicant
+	byOperator := false
+	if !isApplicant || !claimCancellable(status, false) {
+		if !checkOperator() {
+			if isApplicant {
+				return framework.ERROR_INVALID_STATE
+			}
+			return framework.ERROR_UNAUTHORIZED
+		}
+		byOperator = true
+	}
+	if !claimCancellable(status, byOperator) {
+		return framework.ERROR_INVALID_STATE
+	}
+	if byOperator && reason == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
 
-	// 1. 检查是否已加入
-	existingMemberData, _ := framework.GetState(string(memberStateID))
-	if len(existingMemberData) > 0 {
-		status, _, _, _, _, _ := decodeMember(existingMemberData)
-		if status == MEMBER_STATUS_ACTIVE || status == MEMBER_STATUS_PENDING {
-			return framework.ERROR_ALREADY_EXISTS
+	// 3. 撤回已批准案件的影响
+	roundTotal, roundTotalChanged := uint64(0), false
+	if status == CLAIM_STATUS_APPROVED {
+		if code := adjustApprovedUnpaid(0, 1); code != framework.SUCCESS {
+			return code
 		}
-		if status == MEMBER_STATUS_BLACKLISTED {
-			return framework.ERROR_UNAUTHORIZED
+		if c, ok := loadClaimCategoryUsage(cClaimID); ok {
+			if code := appendVersionedState(c.StateID, encodeCategoryUsage(c.Usage.releaseApproval(approvedAmount))); code != framework.SUCCESS {
+				return code
+			}
+		}
+
+		roundStateID := getRoundStateID(roundID)
+		roundData, _ := framework.GetState(string(roundStateID))
+		if len(framework.TrimNull(roundData)) > 0 {
+			rPlanID, rRoundID, rStatus, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq := decodeRound(roundData)
+			newTotal, changed, ok := roundTotalAfterCancel(rStatus, totalApprovedPayout, approvedAmount)
+			if !ok {
+				return framework.ERROR_INVALID_STATE
+			}
+			if changed {
+				newRoundData := encodeRound(rPlanID, rRoundID, rStatus, periodStart, periodEnd, newTotal, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq)
+				if code := appendVersionedState(roundStateID, newRoundData); code != framework.SUCCESS {
+					return code
+				}
+				roundTotal, roundTotalChanged = newTotal, true
+			}
 		}
 	}
 
-	// 2. 创建成员记录（状态为PENDING，需要operator审核）
-	currentTime := framework.GetTimestamp()
-	memberData := encodeMember(MEMBER_STATUS_PENDING, currentTime, 0, 0, 0, 0)
-	if _, err := framework.AppendStateOutputSimple(memberStateID, 1, memberData, nil); err != nil {
+	// 4. 更新案件状态（保留批准金额与轮次，供审计）
+	claimVersion := uint64(2)
+	if status == CLAIM_STATUS_APPROVED {
+		claimVersion = 3
+	}
+	newClaimData := encodeClaim(cPlanID, cClaimID, applicant, insured, CLAIM_STATUS_CANCELLED, roundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime)
+	if _, err := framework.AppendStateOutputSimple(claimStateID, claimVersion, newClaimData, nil); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 3. 更新成员计数（仅统计ACTIVE，PENDING不计入）
-	// 注意：这里不更新计数，等待ApproveMember时再更新
-
-	// 4. 发出事件
-	event := framework.NewEvent("MutualAidMemberJoined")
+	// 5. 发出事件
+	event := framework.NewEvent(EVENT_CLAIM_CANCELLED)
 	event.AddStringField("plan_id", planID)
-	event.AddAddressField("member", caller)
-	event.AddStringField("status", MEMBER_STATUS_PENDING)
-	framework.EmitEvent(event)
-
-	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
-	configData, _ := framework.GetState(STATE_PLAN_CONFIG)
-	waitingPeriod := uint64(0)
-	if len(configData) > 0 {
-		_, _, _, _, _, _, waitingPeriod, _, _ = decodePlanConfig(configData)
+	event.AddStringField("claim_id", cClaimID)
+	event.AddStringField("previous_status", status)
+	event.AddStringField("reason", reason)
+	event.AddAddressField("cancelled_by", caller)
+	event.AddBoolField("by_operator", byOperator)
+	event.AddIntField("approved_amount", approvedAmount)
+	event.AddStringField("round_id", roundID)
+	if code := emitAuditEvent(event); code != framework.SUCCESS {
+		return code
 	}
-	result := map[string]interface{}{
-		"plan_id":          planID,
-		"member":           caller.ToString(),
-		"status":           MEMBER_STATUS_PENDING,
-		"join_time":        currentTime,
-		"waiting_period":   waitingPeriod,
-		"waiting_end_time": currentTime + waitingPeriod,
-		"total_paid":       uint64(0),
-		"total_received":   uint64(0),
-		"arrears_amount":   uint64(0),
+
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":          cPlanID,
+		"claim_id":         cClaimID,
+		"applicant":        addressBytesToString([]byte(applicant)),
+		"insured":          addressBytesToString([]byte(insured)),
+		"status":           CLAIM_STATUS_CANCELLED,
+		"previous_status":  status,
+		"requested_amount": requestedAmount,
+		"approved_amount":  approvedAmount,
+		"event_time":       eventTime,
+		"round_id":         roundID,
+		"reason":           reason,
+		"cancelled_by":     caller.ToString(),
+		"by_operator":      byOperator,
+	}
+	if roundTotalChanged {
+		result["round_total_approved_payout"] = roundTotal
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -883,83 +3480,115 @@ func Join() uint32 {
 	return framework.SUCCESS
 }
 
-// ApproveMember 审核并激活成员（仅 operator 可调用）
+// OpenRound 开启新的结算轮次（仅 operator 可调用）
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
-//	  "member": "Cf1..." // 成员地址（Base58）
+//	  "round_id": "round_202501_01",
+//	  "period_start": 1736200000,
+//	  "period_end": 1738792000
 //	}
 //
+// 校验规则：
+// - round_id 只含字母、数字、'_' 与 '-'，长度不超过 MAX_ROUND_ID_LENGTH
+// - period_end - period_start 与计划 settlement_period 的偏差不超过 ROUND_PERIOD_TOLERANCE_BP
+// - period_start 不早于上一轮次的 period_end（轮次周期不重叠）
+// - 不满足时返回 ERROR_INVALID_PARAMS
+//
 // 输出：
-// - StateOutput: member_{address} (更新状态为ACTIVE)
-// - StateOutput: member_count_active (更新)
-// - Event: MutualAidMemberApproved
+// - StateOutput: round:{round_id}（记录成员激活序号作为快照引用）
+// - StateOutput: round_snapshot:{round_id}（活跃成员数与分摊权重快照）
+// - StateOutput: current_round_id (更新)
+// - Event: MutualAidRoundOpened
 //
-//export ApproveMember
-func ApproveMember() uint32 {
+//export OpenRound
+func OpenRound() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
 
 	// 1. 权限检查
 	if !checkOperator() {
 		return framework.ERROR_UNAUTHORIZED
 	}
 
-	planID := params.ParseJSON("plan_id")
-	memberStr := params.ParseJSON("member")
-	if planID == "" || memberStr == "" {
-		return framework.ERROR_INVALID_PARAMS
-	}
+	roundID := params.ParseJSON("round_id")
+	periodStart := params.ParseJSONInt("period_start")
+	periodEnd := params.ParseJSONInt("period_end")
 
-	member, err := framework.ParseAddressBase58(memberStr)
-	if err != nil {
+	if planID == "" || !validRoundID(roundID) || periodStart <= 0 || periodEnd <= periodStart {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	memberStateID := getMemberStateID(member)
-	memberData, _ := framework.GetState(string(memberStateID))
+	// 2. 检查轮次是否已存在
+	roundStateID := getRoundStateID(roundID)
+	existingRoundData, _ := framework.GetState(string(roundStateID))
+	if len(existingRoundData) > 0 {
+		return framework.ERROR_ALREADY_EXISTS
+	}
 
-	// 2. 检查成员是否存在且状态为PENDING
-	if len(memberData) == 0 {
+	// 3. 校验周期：长度需与 settlement_period 一致（容差内），且不与上一轮次重叠
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(configData) == 0 {
 		return framework.ERROR_NOT_FOUND
 	}
+	_, _, _, _, _, settlementPeriod, _, _, _ := decodePlanConfig(configData)
 
-	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound := decodeMember(memberData)
-	if status != MEMBER_STATUS_PENDING {
-		return framework.ERROR_INVALID_STATE
+	var prevPeriodEnd uint64
+	currentRoundData, _ := framework.GetState(planKey(STATE_CURRENT_ROUND))
+	if prevRoundID := string(framework.TrimNull(currentRoundData)); prevRoundID != "" {
+		prevRoundData, _ := framework.GetState(string(getRoundStateID(prevRoundID)))
+		if len(prevRoundData) > 0 {
+			_, _, _, _, prevPeriodEnd, _, _, _, _, _ = decodeRound(prevRoundData)
+		}
 	}
 
-	// 3. 更新成员状态为ACTIVE
-	newMemberData := encodeMember(MEMBER_STATUS_ACTIVE, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound)
-	if _, err := framework.AppendStateOutputSimple(memberStateID, 2, newMemberData, nil); err != nil {
+	if !validateRoundPeriod(periodStart, periodEnd, settlementPeriod, prevPeriodEnd) {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 4. 快照活跃成员，创建轮次记录
+	snapshotSeq, code := takeRoundSnapshot(roundID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	roundData := encodeRound(planID, roundID, ROUND_STATUS_OPEN, periodStart, periodEnd, 0, 0, 0, 0, snapshotSeq)
+	if _, err := framework.AppendStateOutputSimple(roundStateID, 1, roundData, nil); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 4. 更新成员计数
-	memberCountData, _ := framework.GetState(STATE_MEMBER_COUNT)
-	memberCount := bytesToUint64(memberCountData)
-	newMemberCount := memberCount + 1
-	if _, err := framework.AppendStateOutputSimple([]byte(STATE_MEMBER_COUNT), 2, uint64ToBytes(newMemberCount), nil); err != nil {
+	// 5. 更新当前轮次ID
+	if _, err := framework.AppendStateOutputSimple([]byte(planKey(STATE_CURRENT_ROUND)), 2, []byte(roundID), nil); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 5. 发出事件
-	event := framework.NewEvent("MutualAidMemberApproved")
+	// 6. 发出事件
+	event := framework.NewEvent("MutualAidRoundOpened")
 	event.AddStringField("plan_id", planID)
-	event.AddAddressField("member", member)
+	event.AddStringField("round_id", roundID)
+	event.AddIntField("period_start", periodStart)
+	event.AddIntField("period_end", periodEnd)
 	framework.EmitEvent(event)
 
-	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 7. 返回业务结果（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":             planID,
-		"member":              member.ToString(),
-		"status":              MEMBER_STATUS_ACTIVE,
-		"join_time":           joinTime,
-		"total_paid":          totalPaid,
-		"total_received":      totalReceived,
-		"arrears_amount":      arrearsAmount,
-		"member_count_active": newMemberCount,
+		"plan_id":                 planID,
+		"round_id":                roundID,
+		"status":                  ROUND_STATUS_OPEN,
+		"period_start":            periodStart,
+		"period_end":              periodEnd,
+		"total_approved_payout":   uint64(0),
+		"total_service_fee":       uint64(0),
+		"per_capita_contribution": uint64(0),
+		"payers_count":            uint64(0),
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -968,77 +3597,146 @@ func ApproveMember() uint32 {
 	return framework.SUCCESS
 }
 
-// Exit 退出互助计划
+// SettleRound 结算一个互助周期，计算人均分摊额（仅 operator 可调用）
+//
+// 计算公式：
+//
+//	total_with_fee = total_approved_payout * (10000 + effective_service_fee_bp) / 10000
+//	per_capita = ceil(total_with_fee * 10000 / total_weight_bp)
+//
+// effective_service_fee_bp：FIXED 模式为计划的 service_fee_bp；CLAIMS_RATIO 模式按
+// 历史赔付率（cumulative_paid / cumulative_collected）在 [min_fee_bp, max_fee_bp] 内线性调整，
+// 见 SetFeeAdjustment。
+//
+// 无已批准给付（total_approved_payout = 0）时轮次结算为 SETTLED_ZERO：人均分摊为 0，
+// 不开放 PayContribution / RecordOffchainContribution（返回 ERROR_INVALID_STATE），
+// 成员无需为该轮次缴费，也不会产生欠费。
+//
+// total_weight_bp 为轮次开启时（成员快照 round_snapshot:{round_id}）全部活跃成员的档位系数之和；
+// 未配置档位系数时等于 快照成员数 * 10000，即按人头均摊。成员实际应缴为 per_capita * 档位系数。
+// 轮次中途激活的成员不计入分摊基数，也不承担本轮应缴（见 PayContribution）。
+//
+// 结算前可调用 PreviewSettlement 查看同一计算的结果，预览不写入任何状态。
+// 应收总额或人均分摊超过 uint64 时拒绝结算（ERROR_INVALID_STATE），不截断金额。
 //
 // 参数（JSON）：
 //
 //	{
-//	  "plan_id": "plan_xianghubao_001"
+//	  "plan_id": "plan_xianghubao_001",
+//	  "round_id": "round_202501_01"
 //	}
 //
 // 输出：
-// - StateOutput: member_{address} (更新状态为EXITED)
-// - StateOutput: member_count_active (更新)
-// - Event: MutualAidMemberExited
+// - StateOutput: round:{round_id} (更新)
+// - Event: MutualAidRoundSettled
+// - Event: MutualAidRoundPayoutSummary（按 round_claims:{round_id} 顺序列出每个案件的批准金额；
+//   超过事件大小上限时完整内容写入 event_payload:{hash}，改为发出 EventPayloadAnchored）
 //
-//export Exit
-func Exit() uint32 {
+//export SettleRound
+func SettleRound() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
-
 	planID := params.ParseJSON("plan_id")
-	if planID == "" {
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	roundID := params.ParseJSON("round_id")
+
+	if planID == "" || roundID == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	caller := framework.GetCaller()
-	memberStateID := getMemberStateID(caller)
-	memberData, _ := framework.GetState(string(memberStateID))
+	// 2. 读取轮次
+	roundStateID := getRoundStateID(roundID)
+	roundData, _ := framework.GetState(string(roundStateID))
+	if len(roundData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
 
-	// 1. 检查成员是否存在且状态为ACTIVE
-	if len(memberData) == 0 {
+	rPlanID, rRoundID, status, periodStart, periodEnd, _, _, _, payersCount, snapshotSeq := decodeRound(roundData)
+
+	if status != ROUND_STATUS_OPEN {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 3. 读取计划配置
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(configData) == 0 {
 		return framework.ERROR_NOT_FOUND
 	}
+	_, _, _, _, serviceFeeBP, _, _, _, _ := decodePlanConfig(configData)
 
-	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound := decodeMember(memberData)
-	if status != MEMBER_STATUS_ACTIVE {
+	// 4. 读取结算数据并计算：总给付额汇总审核时归入本轮次（round_claims:{round_id}）的案件批准金额；
+	// 服务费率：固定模式使用 service_fee_bp，赔付率联动模式按历史赔付率在区间内调整
+	in := loadRoundSettlementInputs(roundID, serviceFeeBP)
+	plan, ok := planRoundSettlement(in)
+	if !ok {
 		return framework.ERROR_INVALID_STATE
 	}
 
-	// 2. 更新成员状态为EXITED
-	newMemberData := encodeMember(MEMBER_STATUS_EXITED, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound)
-	if _, err := framework.AppendStateOutputSimple(memberStateID, 2, newMemberData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 5. 只在轮次开启时的活跃成员（快照）之间分摊，轮次中途加入的成员不承担本轮案件；
+	// 无已批准给付时直接结算为 SETTLED_ZERO，不要求快照内有成员
+	if settlementLacksMembers(in.MemberCount, plan.Status) {
+		return framework.ERROR_INVALID_STATE
 	}
+	if code := saveRoundingCarry(in, plan); code != framework.SUCCESS {
+		return code
+	}
+	settlement := plan.Settlement
+	roundingSurplus, roundingShortfall := splitRoundingSurplus(settlement.Surplus)
 
-	// 3. 更新成员计数
-	memberCountData, _ := framework.GetState(STATE_MEMBER_COUNT)
-	memberCount := bytesToUint64(memberCountData)
-	newMemberCount := memberCount
-	if memberCount > 0 {
-		newMemberCount = memberCount - 1
-		if _, err := framework.AppendStateOutputSimple([]byte(STATE_MEMBER_COUNT), 2, uint64ToBytes(newMemberCount), nil); err != nil {
-			return framework.ERROR_EXECUTION_FAILED
-		}
+	// 6. 更新轮次状态
+	newRoundData := encodeRound(rPlanID, rRoundID, plan.Status, periodStart, periodEnd, plan.TotalApprovedPayout, settlement.TotalServiceFee, settlement.PerCapita, payersCount, snapshotSeq)
+	if _, err := framework.AppendStateOutputSimple(roundStateID, 2, newRoundData, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 4. 发出事件
-	event := framework.NewEvent("MutualAidMemberExited")
+	// 7. 发出事件
+	event := framework.NewEvent("MutualAidRoundSettled")
 	event.AddStringField("plan_id", planID)
-	event.AddAddressField("member", caller)
-	event.AddIntField("arrears_amount", arrearsAmount)
+	event.AddStringField("round_id", roundID)
+	event.AddStringField("status", plan.Status)
+	event.AddIntField("total_approved_payout", plan.TotalApprovedPayout)
+	event.AddIntField("member_count_active", in.MemberCount)
+	event.AddIntField("total_weight_bp", in.TotalWeight)
+	event.AddIntField("service_fee_bp", serviceFeeBP)
+	event.AddIntField("effective_service_fee_bp", plan.EffectiveFeeBP)
+	event.AddStringField("fee_mode", in.FeeMode)
+	event.AddIntField("claims_ratio_bp", plan.ClaimsRatioBP)
+	event.AddIntField("total_with_fee", settlement.TotalWithFee)
+	event.AddIntField("total_service_fee", settlement.TotalServiceFee)
+	event.AddIntField("per_capita_contribution", settlement.PerCapita)
+	event.AddStringField("rounding_mode", in.Rounding.Mode)
+	event.AddIntField("rounding_decimals", in.Rounding.Decimals)
+	event.AddIntField("collection_target", settlement.Target)
+	event.AddIntField("rounding_surplus", roundingSurplus)
+	event.AddIntField("rounding_shortfall", roundingShortfall)
 	framework.EmitEvent(event)
 
-	// 5. 返回业务结果（WES ISPC 特性：同步返回业务数据）
-	result := map[string]interface{}{
-		"plan_id":             planID,
-		"member":              caller.ToString(),
-		"status":              MEMBER_STATUS_EXITED,
-		"join_time":           joinTime,
-		"total_paid":          totalPaid,
-		"total_received":      totalReceived,
-		"arrears_amount":      arrearsAmount,
-		"member_count_active": newMemberCount,
+	// 8. 发出给付明细（案件较多时锚定）
+	claimItems := payoutSummaryItems(in.ClaimIDs, in.ApprovedAmount)
+	summary := framework.NewEvent(EVENT_ROUND_PAYOUT_SUMMARY)
+	summary.AddStringField("plan_id", planID)
+	summary.AddStringField("round_id", roundID)
+	summary.AddStringField("status", plan.Status)
+	summary.AddIntField("claims_count", uint64(len(in.ClaimIDs)))
+	summary.AddIntField("total_approved_payout", plan.TotalApprovedPayout)
+	summary.AddField("claims", claimItems)
+	if _, err := framework.EmitEventOrAnchor(summary); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
 	}
+
+	// 9. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := roundSettlementResult(rPlanID, rRoundID, periodStart, periodEnd, payersCount, in, plan)
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
 	}
@@ -1046,110 +3744,168 @@ func Exit() uint32 {
 	return framework.SUCCESS
 }
 
-// SubmitClaim 提交互助申请（报案）
+// AdvanceRound 自动推进轮次生命周期（仅 operator 可调用）
+//
+// 当前轮次的 period_end 到期后调用，一步完成以下操作：
+//  1. 关闭缴费期轮次（上一次推进时结算的轮次）：状态 SETTLED -> CLOSED，
+//     与 CloseRound 相同按活跃成员的应缴记录将未缴金额计入成员 arrears_amount，合计写入 round_arrears:{round_id}
+//  2. 结算当前轮次的已批准案件：状态 OPEN -> SETTLED，计算人均分摊，
+//     该轮次成为新的缴费期轮次，成员可在下一周期内缴费；无已批准给付时
+//     结算为 SETTLED_ZERO，成员无需缴费，下次推进时也无需关闭
+//  3. 开启下一轮次：period_start = 当前轮次 period_end，长度为 settlement_period；
+//     若已越过多个周期，跳过空档周期使新轮次覆盖当前时间
+//
+// 首个轮次需通过 OpenRound 手动开启。
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
-//	  "claim_id": "claim_202501_0001",
-//	  "insured": "Cf1...",                // 被保人地址（Base58），可为空表示即为调用者
-//	  "requested_amount": 300000,
-//	  "event_time": 1736200000,           // 出险时间（时间戳）
-//	  "evidence_hash": "0xabc...",        // 资料哈希
-//	  "extra": "optional comments"
+//	  "next_round_id": "round_202502_01"   // 可选，默认 round_{period_start}；字符集与长度同 OpenRound 的 round_id
 //	}
 //
-// 输出：
-// - StateOutput: claim_{claim_id}
-// - Event: MutualAidClaimSubmitted
+// 返回：
+//   - framework.SUCCESS - 推进成功，返回新轮次信息
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是 operator
+//   - framework.ERROR_NOT_FOUND - 计划配置或当前轮次不存在
+//   - framework.ERROR_INVALID_STATE - 当前轮次尚未到期，或本轮结算金额超过 uint64
+//   - framework.ERROR_ALREADY_EXISTS - 新轮次ID已存在
 //
-//export SubmitClaim
-func SubmitClaim() uint32 {
+// 输出：
+// - StateOutput: member_{address} (更新欠费金额，仅欠费成员)
+// - StateOutput: round:{settling_round_id} (关闭) + round_arrears:{settling_round_id}
+// - StateOutput: round:{current_round_id} (结算)
+// - StateOutput: round:{next_round_id} + current_round_id + settling_round_id (更新)
+// - Event: MutualAidRoundAdvanced
+//
+//export AdvanceRound
+func AdvanceRound() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
-
 	planID := params.ParseJSON("plan_id")
-	claimID := params.ParseJSON("claim_id")
-	insuredStr := params.ParseJSON("insured")
-	requestedAmount := params.ParseJSONInt("requested_amount")
-	eventTime := params.ParseJSONInt("event_time")
-	evidenceHash := params.ParseJSON("evidence_hash")
-	extra := params.ParseJSON("extra")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
 
-	if planID == "" || claimID == "" || requestedAmount <= 0 || eventTime <= 0 {
+	nextRoundID := params.ParseJSON("next_round_id")
+	if planID == "" || (nextRoundID != "" && !validRoundID(nextRoundID)) {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	applicant := framework.GetCaller()
-	var insured framework.Address
-	if insuredStr != "" {
-		var err error
-		insured, err = framework.ParseAddressBase58(insuredStr)
-		if err != nil {
-			return framework.ERROR_INVALID_PARAMS
-		}
-	} else {
-		insured = applicant
+	// 2. 读取计划配置与当前轮次
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(configData) == 0 {
+		return framework.ERROR_NOT_FOUND
 	}
+	_, _, _, _, serviceFeeBP, settlementPeriod, _, _, _ := decodePlanConfig(configData)
 
-	// 1. 检查申请人是否为ACTIVE成员
-	memberStateID := getMemberStateID(applicant)
-	memberData, _ := framework.GetState(string(memberStateID))
-	if len(memberData) == 0 {
+	currentRoundData, _ := framework.GetState(planKey(STATE_CURRENT_ROUND))
+	currentRoundID := string(framework.TrimNull(currentRoundData))
+	if currentRoundID == "" {
 		return framework.ERROR_NOT_FOUND
 	}
-	status, joinTime, _, _, _, _ := decodeMember(memberData)
-	if status != MEMBER_STATUS_ACTIVE {
-		return framework.ERROR_UNAUTHORIZED
+	currentRoundStateID := getRoundStateID(currentRoundID)
+	roundData, _ := framework.GetState(string(currentRoundStateID))
+	if len(roundData) == 0 {
+		return framework.ERROR_NOT_FOUND
 	}
+	rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq := decodeRound(roundData)
 
-	// 3. 检查等待期（简化：仅检查加入时间）
-	currentTime := framework.GetTimestamp()
-	configData, _ := framework.GetState(STATE_PLAN_CONFIG)
-	if len(configData) > 0 {
-		_, _, _, _, _, _, waitingPeriod, _, _ := decodePlanConfig(configData)
-		if currentTime < joinTime+waitingPeriod {
-			return framework.ERROR_INVALID_STATE // 等待期未满
-		}
+	now := framework.GetTimestamp()
+	if now < periodEnd {
+		return framework.ERROR_INVALID_STATE
 	}
 
-	// 4. 检查案件是否已存在
-	claimStateID := getClaimStateID(claimID)
-	existingClaimData, _ := framework.GetState(string(claimStateID))
-	if len(existingClaimData) > 0 {
+	// 3. 推导下一轮次
+	nextStart, nextEnd := nextRoundPeriod(periodEnd, settlementPeriod, now)
+	if nextRoundID == "" {
+		nextRoundID = "round_" + framework.Uint64ToString(nextStart)
+	}
+	nextRoundStateID := getRoundStateID(nextRoundID)
+	if existing, _ := framework.GetState(string(nextRoundStateID)); len(framework.TrimNull(existing)) > 0 {
 		return framework.ERROR_ALREADY_EXISTS
 	}
 
-	// 5. 创建案件记录
-	claimData := encodeClaim(planID, claimID, string(applicant.ToBytes()), string(insured.ToBytes()), CLAIM_STATUS_SUBMITTED, "", evidenceHash, "", requestedAmount, 0, eventTime)
-	if _, err := framework.AppendStateOutputSimple(claimStateID, 1, claimData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 4. 关闭缴费期轮次，未缴金额计入成员欠费（与 CloseRound 相同）
+	var closedRoundID string
+	var arrears uint64
+	settlingRoundData, _ := framework.GetState(planKey(STATE_SETTLING_ROUND))
+	if settlingRoundID := string(framework.TrimNull(settlingRoundData)); settlingRoundID != "" && settlingRoundID != currentRoundID {
+		sData, _ := framework.GetState(string(getRoundStateID(settlingRoundID)))
+		if len(sData) > 0 {
+			if _, _, sStatus, _, _, _, _, _, _, _ := decodeRound(sData); sStatus == ROUND_STATUS_SETTLED {
+				closure, code := closeSettledRound(settlingRoundID, nil)
+				if code != framework.SUCCESS {
+					return code
+				}
+				arrears, closedRoundID = closure.arrearsAdded, settlingRoundID
+			}
+		}
 	}
 
-	// 6. 发出事件
-	event := framework.NewEvent("MutualAidClaimSubmitted")
+	// 5. 结算当前轮次的已批准案件（已手动结算的轮次保持不变）
+	if status == ROUND_STATUS_OPEN {
+		in := loadRoundSettlementInputs(currentRoundID, serviceFeeBP)
+		plan, ok := planRoundSettlement(in)
+		if !ok {
+			return framework.ERROR_INVALID_STATE
+		}
+		if code := saveRoundingCarry(in, plan); code != framework.SUCCESS {
+			return code
+		}
+		totalApprovedPayout, status = plan.TotalApprovedPayout, plan.Status
+		totalServiceFee, perCapitaContribution = plan.Settlement.TotalServiceFee, plan.Settlement.PerCapita
+		if code := appendVersionedState(currentRoundStateID, encodeRound(rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq)); code != framework.SUCCESS {
+			return code
+		}
+	}
+	if code := appendVersionedState([]byte(planKey(STATE_SETTLING_ROUND)), []byte(currentRoundID)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 6. 快照活跃成员，开启下一轮次并更新当前轮次ID
+	nextSnapshotSeq, code := takeRoundSnapshot(nextRoundID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	if code := appendVersionedState(nextRoundStateID, encodeRound(planID, nextRoundID, ROUND_STATUS_OPEN, nextStart, nextEnd, 0, 0, 0, 0, nextSnapshotSeq)); code != framework.SUCCESS {
+		return code
+	}
+	if code := appendVersionedState([]byte(planKey(STATE_CURRENT_ROUND)), []byte(nextRoundID)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 7. 发出事件
+	event := framework.NewEvent("MutualAidRoundAdvanced")
 	event.AddStringField("plan_id", planID)
-	event.AddStringField("claim_id", claimID)
-	event.AddAddressField("applicant", applicant)
-	event.AddAddressField("insured", insured)
-	event.AddIntField("requested_amount", requestedAmount)
-	event.AddIntField("event_time", eventTime)
-	event.AddStringField("evidence_hash", evidenceHash)
-	event.AddStringField("extra", extra)
+	event.AddStringField("closed_round_id", closedRoundID)
+	event.AddIntField("arrears_amount", arrears)
+	event.AddStringField("settled_round_id", currentRoundID)
+	event.AddIntField("per_capita_contribution", perCapitaContribution)
+	event.AddStringField("round_id", nextRoundID)
+	event.AddIntField("period_start", nextStart)
+	event.AddIntField("period_end", nextEnd)
 	framework.EmitEvent(event)
 
-	// 7. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 8. 返回新轮次信息（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":          planID,
-		"claim_id":         claimID,
-		"applicant":        applicant.ToString(),
-		"insured":          insured.ToString(),
-		"status":           CLAIM_STATUS_SUBMITTED,
-		"requested_amount": requestedAmount,
-		"approved_amount":  uint64(0),
-		"event_time":       eventTime,
-		"evidence_hash":    evidenceHash,
-		"round_id":         "",
+		"plan_id":                 planID,
+		"round_id":                nextRoundID,
+		"status":                  ROUND_STATUS_OPEN,
+		"period_start":            nextStart,
+		"period_end":              nextEnd,
+		"settled_round_id":        currentRoundID,
+		"per_capita_contribution": perCapitaContribution,
+		"closed_round_id":         closedRoundID,
+		"arrears_amount":          arrears,
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -1158,186 +3914,284 @@ func SubmitClaim() uint32 {
 	return framework.SUCCESS
 }
 
-// ReviewClaim 审核互助申请（仅 operator 可调用）
+// CloseRound 关闭缴费期已结束的轮次，将成员未缴清的应缴计入欠费（仅 operator 可调用）
+//
+// 轮次状态 SETTLED -> CLOSED。逐个检查成员在该轮次的应缴记录：
+// 有记录时按 应缴 - 已缴 计算，从未缴费时按档位应缴额全额计算；
+// 未缴金额累加到成员的 arrears_amount，合计写入 round_arrears:{round_id}。
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
-//	  "claim_id": "claim_202501_0001",
-//	  "decision": "APPROVE",              // APPROVE / REJECT
-//	  "approved_amount": 280000,          // 决定给付金额，REJECT 时可为 0
-//	  "reason": "符合互助规则",
-//	  "investigation_hash": "0xdef...",  // 调查报告哈希
-//	  "review_round_id": "round_202501_01"
+//	  "round_id": "round_202501_01",
+//	  "members": ["Cf1...", "Df2..."]     // 可选，默认检查当前活跃成员集合
 //	}
 //
+// 注意：
+// - 默认只检查活跃成员；已退出成员的欠费需通过 members 显式传入
+// - 未激活（PENDING）或不在轮次快照内的成员本轮无应缴，跳过；members 中重复的地址只计一次
+// - AdvanceRound 关闭缴费期轮次时使用相同的计算（检查当前活跃成员集合）；遇到已关闭的轮次时不再重复关闭
+//
+// 返回：
+//   - framework.SUCCESS - 关闭成功，返回欠费成员明细
+//   - framework.ERROR_INVALID_PARAMS - plan_id / round_id 为空或 members 无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是 operator
+//   - framework.ERROR_NOT_FOUND - 轮次或 members 中的成员不存在
+//   - framework.ERROR_INVALID_STATE - 轮次不是 SETTLED
+//
 // 输出：
-// - StateOutput: claim_{claim_id} (更新状态)
-// - Event: MutualAidClaimReviewed
+// - StateOutput: member_{address} (更新欠费金额，仅欠费成员)
+// - StateOutput: round:{round_id} (关闭) + round_arrears:{round_id}
+// - Event: MutualAidRoundClosed
 //
-//export ReviewClaim
-func ReviewClaim() uint32 {
+//export CloseRound
+func CloseRound() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
 
 	// 1. 权限检查
 	if !checkOperator() {
 		return framework.ERROR_UNAUTHORIZED
 	}
 
-	planID := params.ParseJSON("plan_id")
-	claimID := params.ParseJSON("claim_id")
-	decision := params.ParseJSON("decision")
-	approvedAmount := params.ParseJSONInt("approved_amount")
-	reason := params.ParseJSON("reason")
-	investigationHash := params.ParseJSON("investigation_hash")
-	reviewRoundID := params.ParseJSON("review_round_id")
-
-	if planID == "" || claimID == "" || decision == "" {
+	roundID := params.ParseJSON("round_id")
+	if planID == "" || roundID == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
-
-	if decision != DECISION_APPROVE && decision != DECISION_REJECT {
-		return framework.ERROR_INVALID_PARAMS
+	members, err := params.ParseAddressArray("members")
+	if err != nil {
+		return rejectParam(err)
 	}
 
-	// 2. 读取案件
-	claimStateID := getClaimStateID(claimID)
-	claimData, _ := framework.GetState(string(claimStateID))
-	if len(claimData) == 0 {
-		return framework.ERROR_NOT_FOUND
+	// 2. 逐个成员计入欠费并关闭轮次
+	closure, code := closeSettledRound(roundID, members)
+	if code != framework.SUCCESS {
+		return code
 	}
 
-	cPlanID, cClaimID, applicant, insured, status, _, evidenceHash, _, requestedAmount, _, eventTime := decodeClaim(claimData)
+	// 3. 发出事件
+	closedAt := framework.GetTimestamp()
+	event := framework.NewEvent(EVENT_ROUND_CLOSED)
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("round_id", roundID)
+	event.AddIntField("delinquent_count", uint64(len(closure.delinquents)))
+	event.AddIntField("arrears_added", closure.arrearsAdded)
+	event.AddIntField("closed_at", closedAt)
+	framework.EmitEvent(event)
 
-	// 3. 检查案件状态
-	if status != CLAIM_STATUS_SUBMITTED && status != CLAIM_STATUS_UNDER_REVIEW {
-		return framework.ERROR_INVALID_STATE
+	// 4. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":          planID,
+		"round_id":         roundID,
+		"status":           ROUND_STATUS_CLOSED,
+		"members_checked":  closure.membersChecked,
+		"delinquent_count": uint64(len(closure.delinquents)),
+		"arrears_added":    closure.arrearsAdded,
+		"delinquents":      closure.delinquents,
+		"closed_at":        closedAt,
 	}
-
-	// 4. 更新案件状态
-	newStatus := CLAIM_STATUS_APPROVED
-	if decision == DECISION_REJECT {
-		newStatus = CLAIM_STATUS_REJECTED
-		approvedAmount = 0
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 检查批准金额不超过请求金额
-	if decision == DECISION_APPROVE && approvedAmount > requestedAmount {
-		approvedAmount = requestedAmount
+	return framework.SUCCESS
+}
+
+// roundClosure 关闭轮次时的欠费明细
+type roundClosure struct {
+	// membersChecked 检查的成员数（去重后）
+	membersChecked uint64
+	// arrearsAdded 本次计入的欠费总额
+	arrearsAdded uint64
+	// delinquents 欠费成员明细（member、arrears_added、arrears_amount）
+	delinquents []interface{}
+}
+
+// closeSettledRound 关闭 SETTLED 轮次：逐个成员的未缴金额累加到 arrears_amount，轮次置为 CLOSED 并记录欠费总额
+//
+// CloseRound 与 AdvanceRound 共用，欠费按成员的应缴记录计算；members 为 nil 时检查当前活跃成员集合。
+//
+// 返回：欠费明细与错误码；轮次或成员不存在时 framework.ERROR_NOT_FOUND，轮次不是 SETTLED 或欠费溢出时 framework.ERROR_INVALID_STATE
+func closeSettledRound(roundID string, members []framework.Address) (roundClosure, uint32) {
+	// 1. 检查轮次是否已结算
+	roundStateID := getRoundStateID(roundID)
+	roundData, _ := framework.GetState(string(roundStateID))
+	if len(roundData) == 0 {
+		return roundClosure{}, framework.ERROR_NOT_FOUND
 	}
+	rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq := decodeRound(roundData)
+	if status != ROUND_STATUS_SETTLED {
+		return roundClosure{}, framework.ERROR_INVALID_STATE
+	}
+	_, _, hasSnapshot := loadRoundSnapshot(roundID)
 
-	newClaimData := encodeClaim(cPlanID, cClaimID, applicant, insured, newStatus, reviewRoundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime)
-	if _, err := framework.AppendStateOutputSimple(claimStateID, 2, newClaimData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 2. 逐个成员计算未缴金额并计入欠费
+	if members == nil {
+		members = loadMemberList(getMembersActivePageStateID, planKey(STATE_MEMBERS_ACTIVE_COUNT))
 	}
+	seen := make(map[framework.Address]bool, len(members))
+	closure := roundClosure{delinquents: make([]interface{}, 0)}
+	for _, m := range members {
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
 
-	// 5. 发出事件
-	event := framework.NewEvent("MutualAidClaimReviewed")
-	event.AddStringField("plan_id", planID)
-	event.AddStringField("claim_id", claimID)
-	event.AddStringField("decision", decision)
-	event.AddIntField("approved_amount", approvedAmount)
-	event.AddStringField("reason", reason)
-	event.AddStringField("investigation_hash", investigationHash)
-	event.AddStringField("review_round_id", reviewRoundID)
-	event.AddAddressField("reviewer", framework.GetCaller())
-	framework.EmitEvent(event)
+		memberStateID := getMemberStateID(m)
+		memberData, _ := framework.GetState(string(memberStateID))
+		if len(memberData) == 0 {
+			return roundClosure{}, framework.ERROR_NOT_FOUND
+		}
+		mStatus, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq := decodeMember(memberData)
+		if mStatus == MEMBER_STATUS_PENDING || !memberEligibleForRound(activationSeq, snapshotSeq, hasSnapshot) {
+			continue
+		}
 
-	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
-	result := map[string]interface{}{
-		"plan_id":            cPlanID,
-		"claim_id":           cClaimID,
-		"applicant":          addressBytesToString([]byte(applicant)),
-		"insured":            addressBytesToString([]byte(insured)),
-		"status":             newStatus,
-		"requested_amount":   requestedAmount,
-		"approved_amount":    approvedAmount,
-		"event_time":         eventTime,
-		"evidence_hash":      evidenceHash,
-		"investigation_hash": investigationHash,
-		"round_id":           reviewRoundID,
-		"decision":           decision,
-		"reason":             reason,
-	}
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+		dueData, _ := framework.GetState(string(getMemberRoundDueStateID(m, roundID)))
+		due, ok := memberDue(perCapitaContribution, loadTierMultiplier(tier))
+		if !ok {
+			return roundClosure{}, framework.ERROR_INVALID_STATE
+		}
+		shortfall := memberRoundShortfall(decodeMemberRoundDue(dueData), len(dueData) > 0, due)
+		if shortfall == 0 {
+			continue
+		}
+		newArrearsAmount, ok := addChecked(arrearsAmount, shortfall)
+		if !ok {
+			return roundClosure{}, framework.ERROR_INVALID_STATE
+		}
+		if closure.arrearsAdded, ok = addChecked(closure.arrearsAdded, shortfall); !ok {
+			return roundClosure{}, framework.ERROR_INVALID_STATE
+		}
+		if code := appendVersionedState(memberStateID, encodeMember(mStatus, joinTime, totalPaid, totalReceived, newArrearsAmount, lastSettledRound, tier, activationSeq)); code != framework.SUCCESS {
+			return roundClosure{}, code
+		}
+		closure.delinquents = append(closure.delinquents, map[string]interface{}{
+			"member":         m.ToString(),
+			"arrears_added":  shortfall,
+			"arrears_amount": newArrearsAmount,
+		})
 	}
 
-	return framework.SUCCESS
+	// 3. 关闭轮次并记录欠费总额
+	if code := appendVersionedState(roundStateID, encodeRound(rPlanID, rRoundID, ROUND_STATUS_CLOSED, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq)); code != framework.SUCCESS {
+		return roundClosure{}, code
+	}
+	if code := appendVersionedState(getRoundArrearsStateID(roundID), framework.Uint64ToBytes(closure.arrearsAdded)); code != framework.SUCCESS {
+		return roundClosure{}, code
+	}
+	closure.membersChecked = uint64(len(seen))
+	return closure, framework.SUCCESS
 }
 
-// OpenRound 开启新的结算轮次（仅 operator 可调用）
+// PayContribution 成员为某一轮互助结算缴纳分摊
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
 //	  "round_id": "round_202501_01",
-//	  "period_start": 1736200000,
-//	  "period_end": 1738792000
+//	  "pool": "Df2...",                   // 资金池地址（Base58）
+//	  "amount": 500,                      // 本次缴纳金额
+//	  "contribution_id": "ctrb_202501_0001"
 //	}
 //
 // 输出：
-// - StateOutput: round_{round_id}
-// - StateOutput: current_round_id (更新)
-// - Event: MutualAidRoundOpened
+// - 使用 market.Escrow 创建实际资产托管
+// - StateOutput: member_round_due_{address}:{round_id} (更新)
+// - StateOutput: member_month_stat_{address}_{yyyymm} (更新)
+// - StateOutput: round:{round_id} (更新payers_count)、round_paid:{round_id} (累加链上缴费)
 //
-//export OpenRound
-func OpenRound() uint32 {
+// 应缴额：per_capita_contribution * 成员档位系数（tier_multiplier_{tier}）
+// 快照资格：轮次开启后才激活的成员不在本轮分摊快照内，返回 ERROR_INVALID_STATE
+// 零给付轮次：SETTLED_ZERO 轮次无应缴，成员无需缴费，调用返回 ERROR_INVALID_STATE
+// 月度上限：成员存在 member_cap_{address} 覆盖时使用覆盖值，否则使用计划的 monthly_cap_per_member
+// - Event: MutualAidContributionPaid
+//
+//export PayContribution
+func PayContribution() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
-
-	// 1. 权限检查
-	if !checkOperator() {
-		return framework.ERROR_UNAUTHORIZED
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
 	}
 
-	planID := params.ParseJSON("plan_id")
 	roundID := params.ParseJSON("round_id")
-	periodStart := params.ParseJSONInt("period_start")
-	periodEnd := params.ParseJSONInt("period_end")
+	poolStr := params.ParseJSON("pool")
+	amount := params.ParseJSONInt("amount")
+	contributionID := params.ParseJSON("contribution_id")
 
-	if planID == "" || roundID == "" || periodStart <= 0 || periodEnd <= periodStart {
+	if planID == "" || roundID == "" || poolStr == "" || amount <= 0 || contributionID == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 2. 检查轮次是否已存在
-	roundStateID := getRoundStateID(roundID)
-	existingRoundData, _ := framework.GetState(string(roundStateID))
-	if len(existingRoundData) > 0 {
-		return framework.ERROR_ALREADY_EXISTS
+	caller := framework.GetCaller()
+	pool, err := framework.ParseAddressBase58(poolStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 3. 创建轮次记录
-	roundData := encodeRound(planID, roundID, ROUND_STATUS_OPEN, periodStart, periodEnd, 0, 0, 0, 0)
-	if _, err := framework.AppendStateOutputSimple(roundStateID, 1, roundData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 1~4. 校验成员与轮次，计算应缴记录与月度统计
+	c, code := prepareContribution(caller, roundID, amount, false, false)
+	if code != framework.SUCCESS {
+		return code
 	}
 
-	// 4. 更新当前轮次ID
-	if _, err := framework.AppendStateOutputSimple([]byte(STATE_CURRENT_ROUND), 2, []byte(roundID), nil); err != nil {
+	// 5. 使用托管实现成员 -> 资金池 的资金划转
+	escrowID := []byte(planID + "_" + roundID + "_" + contributionID)
+	if err := market.Escrow(
+		caller,
+		pool,
+		framework.TokenID(""), // 使用原生币；实际应用可改为稳定币或专用代币
+		framework.Amount(amount),
+		escrowID,
+	); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 5. 发出事件
-	event := framework.NewEvent("MutualAidRoundOpened")
+	// 6~9. 更新应缴记录、月度统计、成员总缴费、累计分摊与轮次缴费
+	if code := commitContribution(c); code != framework.SUCCESS {
+		return code
+	}
+
+	// 10. 发出事件
+	event := framework.NewEvent("MutualAidContributionPaid")
 	event.AddStringField("plan_id", planID)
 	event.AddStringField("round_id", roundID)
-	event.AddIntField("period_start", periodStart)
-	event.AddIntField("period_end", periodEnd)
+	event.AddAddressField("payer", caller)
+	event.AddIntField("amount", amount)
+	event.AddStringField("contribution_id", contributionID)
 	framework.EmitEvent(event)
 
-	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 11. 返回业务结果（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":                 planID,
-		"round_id":                roundID,
-		"status":                  ROUND_STATUS_OPEN,
-		"period_start":            periodStart,
-		"period_end":              periodEnd,
-		"total_approved_payout":   uint64(0),
-		"total_service_fee":       uint64(0),
-		"per_capita_contribution": uint64(0),
-		"payers_count":            uint64(0),
+		"plan_id":                planID,
+		"round_id":               roundID,
+		"payer":                  caller.ToString(),
+		"amount":                 amount,
+		"due_amount":             c.due.DueAmount,
+		"paid_amount":            c.due.PaidAmount,
+		"settled":                c.due.Settled,
+		"month_paid_amount":      c.monthPaidAmount,
+		"monthly_cap_per_member": c.monthlyCap,
+		"cap_reached":            c.capReached,
+		"total_paid":             c.totalPaid,
+		"contribution_id":        contributionID,
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -1346,110 +4200,279 @@ func OpenRound() uint32 {
 	return framework.SUCCESS
 }
 
-// SettleRound 结算一个互助周期，计算人均分摊额（仅 operator 可调用）
-//
-// 计算公式：
-//
-//	total_with_fee = total_approved_payout * (10000 + service_fee_bp) / 10000
-//	per_capita = ceil(total_with_fee / member_count_active)
+// contributionUpdate 一笔缴费（链上托管或线下登记）校验后待写入的状态
+type contributionUpdate struct {
+	payer    framework.Address
+	roundID  string
+	amount   uint64
+	offchain bool
+
+	due roundDue // 计入本笔后的应缴记录
+
+	memberStatus     string
+	joinTime         uint64
+	totalPaid        uint64 // 计入本笔后的成员总缴费
+	totalReceived    uint64
+	arrearsAmount    uint64
+	lastSettledRound uint64
+	tier             uint64
+	activationSeq    uint64
+
+	yearMonth       string // 月度统计年月（轮次 period_end 所在月份）
+	monthPaidAmount uint64 // 计入本笔后的月度缴费
+	monthlyCap      uint64
+	capReached      bool
+}
+
+// contributionYearMonth 缴费计入的月度统计年月（YYYYMM）
+//
+// 按轮次的 period_end 所在月份计算（UTC），不取缴费时的区块时间：
+// 同一轮次的缴费、线下登记与冲正始终落在同一个月度统计中，冲正不会扣到其他月份
+func contributionYearMonth(periodEnd uint64) string {
+	return framework.TimestampToYearMonth(periodEnd)
+}
+
+// prepareContribution 校验成员与轮次并计算缴费后的状态（不写入）
+//
+// PayContribution 与 RecordOffchainContribution 共用：
+//  1. 成员必须为 ACTIVE
+//  2. 轮次必须已结算，且成员在轮次快照内
+//  3. 应缴记录未结清（线下登记时 reference_hash 不能重复）
+//  4. 不超过月度上限
+func prepareContribution(payer framework.Address, roundID string, amount uint64, offchain, referenceUsed bool) (*contributionUpdate, uint32) {
+	c := &contributionUpdate{payer: payer, roundID: roundID, amount: amount, offchain: offchain}
+
+	// 1. 检查成员是否为ACTIVE
+	memberData, code := requireActiveMember(payer)
+	if code != framework.SUCCESS {
+		return nil, code
+	}
+	c.memberStatus, c.joinTime, c.totalPaid, c.totalReceived, c.arrearsAmount, c.lastSettledRound, c.tier, c.activationSeq = decodeMember(memberData)
+
+	// 2. 检查轮次是否存在且已结算
+	roundData, _ := framework.GetState(string(getRoundStateID(roundID)))
+	if len(roundData) == 0 {
+		return nil, framework.ERROR_NOT_FOUND
+	}
+	_, _, roundStatus, _, periodEnd, _, _, perCapitaContribution, _, snapshotSeq := decodeRound(roundData)
+	if !roundAcceptsContributions(roundStatus) {
+		return nil, framework.ERROR_INVALID_STATE
+	}
+	// 轮次开启后才激活的成员不在分摊快照内，本轮无应缴
+	_, _, hasSnapshot := loadRoundSnapshot(roundID)
+	if !memberEligibleForRound(c.activationSeq, snapshotSeq, hasSnapshot) {
+		return nil, framework.ERROR_INVALID_STATE
+	}
+
+	// 3. 读取或创建成员轮次应缴记录，计入本笔缴费
+	dueData, _ := framework.GetState(string(getMemberRoundDueStateID(payer, roundID)))
+	var due roundDue
+	if len(dueData) > 0 {
+		due = decodeMemberRoundDue(dueData)
+	} else {
+		// 应缴额 = 基准人均分摊 * 成员档位系数
+		dueAmount, ok := memberDue(perCapitaContribution, loadTierMultiplier(c.tier))
+		if !ok {
+			return nil, framework.ERROR_INVALID_STATE
+		}
+		due.DueAmount = dueAmount
+	}
+	var reject string
+	if offchain {
+		c.due, reject = planOffchainRecord(referenceUsed, due, amount)
+	} else {
+		c.due, reject = applyContribution(due, amount, false)
+	}
+	switch reject {
+	case OFFCHAIN_REJECT_DUPLICATE_REFERENCE:
+		return nil, framework.ERROR_ALREADY_EXISTS
+	case OFFCHAIN_REJECT_SETTLED:
+		return nil, framework.ERROR_INVALID_STATE // 已结清
+	case OFFCHAIN_REJECT_OVERFLOW:
+		return nil, framework.ERROR_INVALID_PARAMS
+	}
+
+	// 4. 检查月度上限：成员存在个人上限覆盖时优先使用覆盖值
+	c.yearMonth = contributionYearMonth(periodEnd)
+	monthStatData, _ := framework.GetState(string(getMemberMonthStatStateID(payer, c.yearMonth)))
+	var monthPaidAmount uint64
+	var capReached bool
+	if len(monthStatData) > 0 {
+		monthPaidAmount, capReached = decodeMemberMonthStat(monthStatData)
+	}
+	c.monthlyCap = loadMonthlyCap(payer)
+	newMonthPaidAmount, ok := addChecked(monthPaidAmount, amount)
+	if !ok || newMonthPaidAmount > c.monthlyCap {
+		return nil, framework.ERROR_INVALID_PARAMS // 超过月度上限
+	}
+	if capReached {
+		return nil, framework.ERROR_INVALID_PARAMS // 月度上限已触达
+	}
+	c.monthPaidAmount = newMonthPaidAmount
+	c.capReached = newMonthPaidAmount >= c.monthlyCap
+
+	if c.totalPaid, ok = addChecked(c.totalPaid, amount); !ok {
+		return nil, framework.ERROR_INVALID_PARAMS
+	}
+	return c, framework.SUCCESS
+}
+
+// commitContribution 写入 prepareContribution 计算的缴费状态
+//
+// 输出：应缴记录、月度统计、成员总缴费、累计分摊（线下登记另计入 cumulative_collected_offchain）、
+// 轮次缴费人数与链上/线下拆分
+func commitContribution(c *contributionUpdate) uint32 {
+	// 6. 更新成员轮次应缴记录
+	if code := appendVersionedState(getMemberRoundDueStateID(c.payer, c.roundID), encodeMemberRoundDue(c.due)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 7. 更新成员月度统计
+	monthStatStateID := getMemberMonthStatStateID(c.payer, c.yearMonth)
+	if code := appendVersionedState(monthStatStateID, encodeMemberMonthStat(c.monthPaidAmount, c.capReached)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 8. 更新成员总缴费与累计分摊
+	newMemberData := encodeMember(c.memberStatus, c.joinTime, c.totalPaid, c.totalReceived, c.arrearsAmount, c.lastSettledRound, c.tier, c.activationSeq)
+	if code := appendVersionedState(getMemberStateID(c.payer), newMemberData); code != framework.SUCCESS {
+		return code
+	}
+	if code := addCumulative(planKey(STATE_CUMULATIVE_COLLECTED), c.amount); code != framework.SUCCESS {
+		return code
+	}
+	if c.offchain {
+		if code := addCumulative(planKey(STATE_CUMULATIVE_OFFCHAIN), c.amount); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 9. 更新轮次缴费人数（简化：每次缴费都增加，实际应该去重）与链上/线下拆分
+	roundStateID := getRoundStateID(c.roundID)
+	roundData, _ := framework.GetState(string(roundStateID))
+	rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, payersCount, rSnapshotSeq := decodeRound(roundData)
+	newRoundData := encodeRound(rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, payersCount+1, rSnapshotSeq)
+	if code := appendVersionedState(roundStateID, newRoundData); code != framework.SUCCESS {
+		return code
+	}
+	onchainPaid, offchainPaid := loadRoundPaid(c.roundID)
+	if c.offchain {
+		offchainPaid += c.amount
+	} else {
+		onchainPaid += c.amount
+	}
+	return saveRoundPaid(c.roundID, onchainPaid, offchainPaid)
+}
+
+// loadMonthlyCap 读取成员生效的月度分摊上限（个人覆盖优先于计划默认）
+func loadMonthlyCap(member framework.Address) uint64 {
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	var planMonthlyCap uint64 = DEFAULT_MONTHLY_CAP_PER_MEMBER
+	if len(configData) > 0 {
+		_, _, _, _, _, _, _, _, planMonthlyCap = decodePlanConfig(configData)
+	}
+	memberCapData, _ := framework.GetState(string(getMemberCapStateID(member)))
+	return effectiveMonthlyCap(planMonthlyCap, framework.BytesToUint64(memberCapData))
+}
+
+// RecordOffchainContribution 登记成员通过银行转账等线下方式缴纳的分摊（仅 operator 可调用）
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
-//	  "round_id": "round_202501_01"
+//	  "member": "Cf1...",                 // 缴费成员地址（Base58）
+//	  "round_id": "round_202501_01",
+//	  "amount": 500,                      // 线下缴纳金额
+//	  "reference_hash": "9f86d0..."       // 银行流水等凭证哈希，同一凭证只能登记一次
 //	}
 //
 // 输出：
-// - StateOutput: round_{round_id} (更新)
-// - Event: MutualAidRoundSettled
-//
-//export SettleRound
-func SettleRound() uint32 {
+// - 不执行托管转账，资金已在链下到账
+// - StateOutput: offchain_contribution_{reference_hash} (新建，状态 RECORDED)
+// - StateOutput: member_round_due_{address}:{round_id} (更新，设置 OFFCHAIN 标志)
+// - StateOutput: member_month_stat_{address}_{yyyymm}、member_{address} (更新)
+// - StateOutput: round:{round_id}、round_paid:{round_id} (更新)
+// - StateOutput: cumulative_collected、cumulative_collected_offchain (累加)
+// - Event: MutualAidOffchainContributionRecorded
+//
+// 校验规则与 PayContribution 相同（成员 ACTIVE、轮次已结算、快照资格、月度上限）；
+// reference_hash 已登记返回 ERROR_ALREADY_EXISTS
+//
+//export RecordOffchainContribution
+func RecordOffchainContribution() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
 
 	// 1. 权限检查
 	if !checkOperator() {
 		return framework.ERROR_UNAUTHORIZED
 	}
 
-	planID := params.ParseJSON("plan_id")
+	memberStr := params.ParseJSON("member")
 	roundID := params.ParseJSON("round_id")
+	amount := params.ParseJSONInt("amount")
+	referenceHash := params.ParseJSON("reference_hash")
 
-	if planID == "" || roundID == "" {
+	if planID == "" || memberStr == "" || roundID == "" || amount <= 0 || referenceHash == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
-
-	// 2. 读取轮次
-	roundStateID := getRoundStateID(roundID)
-	roundData, _ := framework.GetState(string(roundStateID))
-	if len(roundData) == 0 {
-		return framework.ERROR_NOT_FOUND
-	}
-
-	rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount := decodeRound(roundData)
-
-	if status != ROUND_STATUS_OPEN {
-		return framework.ERROR_INVALID_STATE
+	member, err := framework.ParseAddressBase58(memberStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 3. 读取计划配置
-	configData, _ := framework.GetState(STATE_PLAN_CONFIG)
-	if len(configData) == 0 {
-		return framework.ERROR_NOT_FOUND
+	// 2. 校验成员与轮次，计算应缴记录（同一凭证不能重复登记）
+	entryStateID := getOffchainEntryStateID(referenceHash)
+	entryData, _ := framework.GetState(string(entryStateID))
+	c, code := prepareContribution(member, roundID, amount, true, len(entryData) > 0)
+	if code != framework.SUCCESS {
+		return code
 	}
-	_, _, _, _, serviceFeeBP, _, _, _, _ := decodePlanConfig(configData)
-
-	// 4. 计算总给付额（简化：从参数传入，实际应从已批准的claim汇总）
-	// 这里假设totalApprovedPayout已经在OpenRound时或通过其他方式设置
-	// 实际应用中，应该遍历所有APPROVED状态的claim，汇总approved_amount
 
-	// 5. 计算服务费和人均分摊
-	totalWithFee := totalApprovedPayout * (10000 + serviceFeeBP) / 10000
-	totalServiceFee = totalWithFee - totalApprovedPayout
-
-	// 读取活跃成员数
-	memberCountData, _ := framework.GetState(STATE_MEMBER_COUNT)
-	memberCount := bytesToUint64(memberCountData)
-	if memberCount == 0 {
-		return framework.ERROR_INVALID_STATE
+	// 3. 写入线下缴费登记记录
+	now := framework.GetTimestamp()
+	if _, err := framework.AppendStateOutputSimple(entryStateID, 1, encodeOffchainEntry(member, roundID, amount, OFFCHAIN_STATUS_RECORDED, now), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 向上取整
-	perCapitaContribution = (totalWithFee + memberCount - 1) / memberCount
-
-	// 6. 更新轮次状态
-	newRoundData := encodeRound(rPlanID, rRoundID, ROUND_STATUS_SETTLED, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount)
-	if _, err := framework.AppendStateOutputSimple(roundStateID, 2, newRoundData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 4. 按缴费流程更新应缴记录、月度统计与各项合计
+	if code := commitContribution(c); code != framework.SUCCESS {
+		return code
 	}
 
-	// 7. 发出事件
-	event := framework.NewEvent("MutualAidRoundSettled")
+	// 5. 发出事件
+	event := framework.NewEvent("MutualAidOffchainContributionRecorded")
 	event.AddStringField("plan_id", planID)
 	event.AddStringField("round_id", roundID)
-	event.AddIntField("total_approved_payout", totalApprovedPayout)
-	event.AddIntField("member_count_active", memberCount)
-	event.AddIntField("service_fee_bp", serviceFeeBP)
-	event.AddIntField("total_with_fee", totalWithFee)
-	event.AddIntField("total_service_fee", totalServiceFee)
-	event.AddIntField("per_capita_contribution", perCapitaContribution)
+	event.AddAddressField("member", member)
+	event.AddIntField("amount", amount)
+	event.AddStringField("reference_hash", referenceHash)
+	event.AddStringField("status", OFFCHAIN_STATUS_RECORDED)
 	framework.EmitEvent(event)
 
-	// 8. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":                 rPlanID,
-		"round_id":                rRoundID,
-		"status":                  ROUND_STATUS_SETTLED,
-		"period_start":            periodStart,
-		"period_end":              periodEnd,
-		"total_approved_payout":   totalApprovedPayout,
-		"total_service_fee":       totalServiceFee,
-		"total_with_fee":          totalWithFee,
-		"per_capita_contribution": perCapitaContribution,
-		"member_count_active":     memberCount,
-		"service_fee_bp":          serviceFeeBP,
-		"payers_count":            payersCount,
+		"plan_id":           planID,
+		"round_id":          roundID,
+		"member":            member.ToString(),
+		"amount":            amount,
+		"reference_hash":    referenceHash,
+		"status":            OFFCHAIN_STATUS_RECORDED,
+		"due_amount":        c.due.DueAmount,
+		"paid_amount":       c.due.PaidAmount,
+		"offchain_paid":     c.due.OffchainPaid,
+		"settled":           c.due.Settled,
+		"month_paid_amount": c.monthPaidAmount,
+		"cap_reached":       c.capReached,
+		"total_paid":        c.totalPaid,
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -1458,189 +4481,207 @@ func SettleRound() uint32 {
 	return framework.SUCCESS
 }
 
-// PayContribution 成员为某一轮互助结算缴纳分摊
+// ReconcileOffchain 对账线下缴费登记：核实到账或冲正（仅 operator 可调用）
 //
 // 参数（JSON）：
 //
 //	{
 //	  "plan_id": "plan_xianghubao_001",
-//	  "round_id": "round_202501_01",
-//	  "pool": "Df2...",                   // 资金池地址（Base58）
-//	  "amount": 500,                      // 本次缴纳金额
-//	  "contribution_id": "ctrb_202501_0001"
+//	  "reference_hash": "9f86d0...",
+//	  "resolution": "VERIFIED"            // VERIFIED 或 REVERSED
 //	}
 //
+// 状态转换：RECORDED -> VERIFIED；RECORDED/VERIFIED -> REVERSED
+//
+// 冲正（REVERSED）：
+// - 重新打开成员轮次应缴：扣回已缴与线下金额，按剩余金额重新判断是否结清，设置 REVERSED 标志
+// - 扣回月度统计、成员总缴费、累计分摊、轮次缴费人数与线下拆分（扣减不足视为账本不一致，返回 ERROR_INVALID_STATE）
+// - 轮次已关闭时，冲正新增的未缴金额计入成员欠费与 round_arrears:{round_id}
+//
 // 输出：
-// - 使用 market.Escrow 创建实际资产托管
-// - StateOutput: member_round_due_{address}_{round_id} (更新)
-// - StateOutput: member_month_stat_{address}_{yyyymm} (更新)
-// - StateOutput: round_{round_id} (更新payers_count)
-// - Event: MutualAidContributionPaid
+// - StateOutput: offchain_contribution_{reference_hash} (更新状态)
+// - StateOutput: 冲正时同 RecordOffchainContribution 的各项状态 (扣回)
+// - Event: MutualAidOffchainReconciled
 //
-//export PayContribution
-func PayContribution() uint32 {
+//export ReconcileOffchain
+func ReconcileOffchain() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
-
 	planID := params.ParseJSON("plan_id")
-	roundID := params.ParseJSON("round_id")
-	poolStr := params.ParseJSON("pool")
-	amount := params.ParseJSONInt("amount")
-	contributionID := params.ParseJSON("contribution_id")
-
-	if planID == "" || roundID == "" || poolStr == "" || amount <= 0 || contributionID == "" {
-		return framework.ERROR_INVALID_PARAMS
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
 	}
 
-	caller := framework.GetCaller()
-	pool, err := framework.ParseAddressBase58(poolStr)
-	if err != nil {
-		return framework.ERROR_INVALID_PARAMS
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
 	}
 
-	// 1. 检查成员是否为ACTIVE
-	memberStateID := getMemberStateID(caller)
-	memberData, _ := framework.GetState(string(memberStateID))
-	if len(memberData) == 0 {
-		return framework.ERROR_NOT_FOUND
+	referenceHash := params.ParseJSON("reference_hash")
+	resolution := params.ParseJSON("resolution")
+	if planID == "" || referenceHash == "" {
+		return framework.ERROR_INVALID_PARAMS
 	}
-	status, _, _, _, _, _ := decodeMember(memberData)
-	if status != MEMBER_STATUS_ACTIVE {
-		return framework.ERROR_UNAUTHORIZED
+	if resolution != OFFCHAIN_STATUS_VERIFIED && resolution != OFFCHAIN_STATUS_REVERSED {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 2. 检查轮次是否存在且已结算
-	roundStateID := getRoundStateID(roundID)
-	roundData, _ := framework.GetState(string(roundStateID))
-	if len(roundData) == 0 {
+	// 2. 读取登记记录并检查状态迁移
+	entryStateID := getOffchainEntryStateID(referenceHash)
+	entryData, _ := framework.GetState(string(entryStateID))
+	if len(entryData) == 0 {
 		return framework.ERROR_NOT_FOUND
 	}
-	_, _, roundStatus, _, _, _, _, perCapitaContribution, _ := decodeRound(roundData)
-	if roundStatus != ROUND_STATUS_SETTLED {
+	member, roundID, amount, status, recordedAt := decodeOffchainEntry(entryData)
+	if !canReconcileOffchain(status, resolution) {
 		return framework.ERROR_INVALID_STATE
 	}
 
-	// 3. 读取或创建成员轮次应缴记录
-	memberRoundDueStateID := getMemberRoundDueStateID(caller, roundID)
-	memberRoundDueData, _ := framework.GetState(string(memberRoundDueStateID))
-	var dueAmount, paidAmount uint64
-	var settled bool
-	if len(memberRoundDueData) > 0 {
-		dueAmount, paidAmount, settled = decodeMemberRoundDue(memberRoundDueData)
-	} else {
-		dueAmount = perCapitaContribution
-		paidAmount = 0
-		settled = false
+	// 3. 冲正：重新打开应缴并扣回各项合计
+	var arrearsDelta uint64
+	var due roundDue
+	if resolution == OFFCHAIN_STATUS_REVERSED {
+		var code uint32
+		due, arrearsDelta, code = reverseOffchainContribution(member, roundID, status, amount)
+		if code != framework.SUCCESS {
+			return code
+		}
 	}
 
-	if settled {
-		return framework.ERROR_INVALID_STATE // 已结清
+	// 4. 更新登记记录状态
+	if code := appendVersionedState(entryStateID, encodeOffchainEntry(member, roundID, amount, resolution, recordedAt)); code != framework.SUCCESS {
+		return code
 	}
 
-	// 4. 检查月度上限（简化：使用当前月份）
-	// 实际应用中应该解析roundID或使用period_end计算年月
-	yearMonth := "202501" // 简化实现
-	memberMonthStatStateID := getMemberMonthStatStateID(caller, yearMonth)
-	memberMonthStatData, _ := framework.GetState(string(memberMonthStatStateID))
-	var monthPaidAmount uint64
-	var capReached bool
-	if len(memberMonthStatData) > 0 {
-		monthPaidAmount, capReached = decodeMemberMonthStat(memberMonthStatData)
-	}
+	// 5. 发出事件
+	event := framework.NewEvent("MutualAidOffchainReconciled")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("round_id", roundID)
+	event.AddAddressField("member", member)
+	event.AddIntField("amount", amount)
+	event.AddStringField("reference_hash", referenceHash)
+	event.AddStringField("previous_status", status)
+	event.AddStringField("status", resolution)
+	event.AddIntField("arrears_added", arrearsDelta)
+	framework.EmitEvent(event)
 
-	// 读取计划配置中的月度上限
-	configData, _ := framework.GetState(STATE_PLAN_CONFIG)
-	var monthlyCapPerMember uint64 = 1000000
-	if len(configData) > 0 {
-		_, _, _, _, _, _, _, _, monthlyCapPerMember = decodePlanConfig(configData)
+	// 6. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":         planID,
+		"round_id":        roundID,
+		"member":          member.ToString(),
+		"amount":          amount,
+		"reference_hash":  referenceHash,
+		"previous_status": status,
+		"status":          resolution,
+	}
+	if resolution == OFFCHAIN_STATUS_REVERSED {
+		result["due_amount"] = due.DueAmount
+		result["paid_amount"] = due.PaidAmount
+		result["offchain_paid"] = due.OffchainPaid
+		result["settled"] = due.Settled
+		result["arrears_added"] = arrearsDelta
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 检查是否超过月度上限
-	if monthPaidAmount+amount > monthlyCapPerMember {
-		return framework.ERROR_INVALID_PARAMS // 超过月度上限
+	return framework.SUCCESS
+}
+
+// reverseOffchainContribution 冲正一笔线下缴费，扣回应缴记录与各项合计
+//
+// 返回：冲正后的应缴记录、新增欠费（轮次已关闭时）
+func reverseOffchainContribution(member framework.Address, roundID, status string, amount uint64) (roundDue, uint64, uint32) {
+	// 1. 重新打开应缴记录
+	dueStateID := getMemberRoundDueStateID(member, roundID)
+	dueData, _ := framework.GetState(string(dueStateID))
+	roundStateID := getRoundStateID(roundID)
+	roundData, _ := framework.GetState(string(roundStateID))
+	if len(dueData) == 0 || len(roundData) == 0 {
+		return roundDue{}, 0, framework.ERROR_NOT_FOUND
 	}
-	if capReached {
-		return framework.ERROR_INVALID_PARAMS // 月度上限已触达
+	rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, payersCount, rSnapshotSeq := decodeRound(roundData)
+	due, arrearsDelta, reject := planOffchainReversal(status, decodeMemberRoundDue(dueData), amount, rStatus == ROUND_STATUS_CLOSED)
+	if reject != "" {
+		return roundDue{}, 0, framework.ERROR_INVALID_STATE
 	}
-
-	// 5. 使用托管实现成员 -> 资金池 的资金划转
-	escrowID := []byte(planID + "_" + roundID + "_" + contributionID)
-	if err := market.Escrow(
-		caller,
-		pool,
-		framework.TokenID(""), // 使用原生币；实际应用可改为稳定币或专用代币
-		framework.Amount(amount),
-		escrowID,
-	); err != nil {
-		if contractErr, ok := err.(*framework.ContractError); ok {
-			return contractErr.Code
-		}
-		return framework.ERROR_EXECUTION_FAILED
+	if code := appendVersionedState(dueStateID, encodeMemberRoundDue(due)); code != framework.SUCCESS {
+		return roundDue{}, 0, code
 	}
 
-	// 6. 更新成员轮次应缴记录
-	newPaidAmount := paidAmount + amount
-	newSettled := newPaidAmount >= dueAmount
-	newMemberRoundDueData := encodeMemberRoundDue(dueAmount, newPaidAmount, newSettled)
-	if _, err := framework.AppendStateOutputSimple(memberRoundDueStateID, 2, newMemberRoundDueData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 2. 扣回月度统计
+	monthStatStateID := getMemberMonthStatStateID(member, contributionYearMonth(rPeriodEnd))
+	monthStatData, _ := framework.GetState(string(monthStatStateID))
+	monthPaidAmount, _ := decodeMemberMonthStat(monthStatData)
+	newMonthPaidAmount, ok := subChecked(monthPaidAmount, amount)
+	if !ok {
+		return roundDue{}, 0, framework.ERROR_INVALID_STATE
 	}
-
-	// 7. 更新成员月度统计
-	newMonthPaidAmount := monthPaidAmount + amount
-	newCapReached := newMonthPaidAmount >= monthlyCapPerMember
-	newMemberMonthStatData := encodeMemberMonthStat(newMonthPaidAmount, newCapReached)
-	if _, err := framework.AppendStateOutputSimple(memberMonthStatStateID, 2, newMemberMonthStatData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	if code := appendVersionedState(monthStatStateID, encodeMemberMonthStat(newMonthPaidAmount, newMonthPaidAmount >= loadMonthlyCap(member))); code != framework.SUCCESS {
+		return roundDue{}, 0, code
 	}
 
-	// 8. 更新成员总缴费
-	_, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound := decodeMember(memberData)
-	newTotalPaid := totalPaid + amount
-	newMemberData := encodeMember(status, joinTime, newTotalPaid, totalReceived, arrearsAmount, lastSettledRound)
-	if _, err := framework.AppendStateOutputSimple(memberStateID, 2, newMemberData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 3. 扣回成员总缴费，轮次已关闭时计入欠费
+	memberStateID := getMemberStateID(member)
+	memberData, _ := framework.GetState(string(memberStateID))
+	mStatus, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq := decodeMember(memberData)
+	newTotalPaid, ok := subChecked(totalPaid, amount)
+	if !ok {
+		return roundDue{}, 0, framework.ERROR_INVALID_STATE
+	}
+	newArrearsAmount, ok := addChecked(arrearsAmount, arrearsDelta)
+	if !ok {
+		return roundDue{}, 0, framework.ERROR_INVALID_STATE
+	}
+	newMemberData := encodeMember(mStatus, joinTime, newTotalPaid, totalReceived, newArrearsAmount, lastSettledRound, tier, activationSeq)
+	if code := appendVersionedState(memberStateID, newMemberData); code != framework.SUCCESS {
+		return roundDue{}, 0, code
+	}
+
+	// 4. 扣回累计分摊（总额与线下部分）
+	for _, stateID := range []string{planKey(STATE_CUMULATIVE_COLLECTED), planKey(STATE_CUMULATIVE_OFFCHAIN)} {
+		data, _ := framework.GetState(stateID)
+		remaining, ok := subChecked(framework.BytesToUint64(data), amount)
+		if !ok {
+			return roundDue{}, 0, framework.ERROR_INVALID_STATE
+		}
+		if code := appendVersionedState([]byte(stateID), framework.Uint64ToBytes(remaining)); code != framework.SUCCESS {
+			return roundDue{}, 0, code
+		}
 	}
 
-	// 9. 更新轮次缴费人数（简化：每次缴费都增加，实际应该去重）
-	_, _, _, _, _, _, _, _, payersCount := decodeRound(roundData)
-	newPayersCount := payersCount + 1
-	// 注意：这里需要重新读取roundData以获取完整信息
-	roundData2, _ := framework.GetState(string(roundStateID))
-	rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, _ := decodeRound(roundData2)
-	newRoundData := encodeRound(rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, newPayersCount)
-	if _, err := framework.AppendStateOutputSimple(roundStateID, 3, newRoundData, nil); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 5. 扣回轮次缴费人数与线下拆分，轮次已关闭时追加轮次欠费
+	if payersCount > 0 {
+		payersCount--
 	}
-
-	// 10. 发出事件
-	event := framework.NewEvent("MutualAidContributionPaid")
-	event.AddStringField("plan_id", planID)
-	event.AddStringField("round_id", roundID)
-	event.AddAddressField("payer", caller)
-	event.AddIntField("amount", amount)
-	event.AddStringField("contribution_id", contributionID)
-	framework.EmitEvent(event)
-
-	// 11. 返回业务结果（WES ISPC 特性：同步返回业务数据）
-	result := map[string]interface{}{
-		"plan_id":                planID,
-		"round_id":               roundID,
-		"payer":                  caller.ToString(),
-		"amount":                 amount,
-		"due_amount":             dueAmount,
-		"paid_amount":            newPaidAmount,
-		"settled":                newSettled,
-		"month_paid_amount":      newMonthPaidAmount,
-		"monthly_cap_per_member": monthlyCapPerMember,
-		"cap_reached":            newCapReached,
-		"total_paid":             newTotalPaid,
-		"contribution_id":        contributionID,
+	newRoundData := encodeRound(rPlanID, rRoundID, rStatus, rPeriodStart, rPeriodEnd, rTotalApprovedPayout, rTotalServiceFee, rPerCapitaContribution, payersCount, rSnapshotSeq)
+	if code := appendVersionedState(roundStateID, newRoundData); code != framework.SUCCESS {
+		return roundDue{}, 0, code
 	}
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	onchainPaid, offchainPaid := loadRoundPaid(roundID)
+	newOffchainPaid, ok := subChecked(offchainPaid, amount)
+	if !ok {
+		return roundDue{}, 0, framework.ERROR_INVALID_STATE
+	}
+	if code := saveRoundPaid(roundID, onchainPaid, newOffchainPaid); code != framework.SUCCESS {
+		return roundDue{}, 0, code
+	}
+	if arrearsDelta > 0 {
+		arrearsData, _ := framework.GetState(string(getRoundArrearsStateID(roundID)))
+		newRoundArrears, ok := addChecked(framework.BytesToUint64(arrearsData), arrearsDelta)
+		if !ok {
+			return roundDue{}, 0, framework.ERROR_INVALID_STATE
+		}
+		if code := appendVersionedState(getRoundArrearsStateID(roundID), framework.Uint64ToBytes(newRoundArrears)); code != framework.SUCCESS {
+			return roundDue{}, 0, code
+		}
 	}
 
-	return framework.SUCCESS
+	return due, arrearsDelta, framework.SUCCESS
 }
 
 // Payout 为已通过审核的理赔案件进行给付（仅 operator 可调用）
@@ -1656,22 +4697,34 @@ func PayContribution() uint32 {
 //	  "payout_id": "payout_202501_0001"
 //	}
 //
+// 案件指定了保障类别时，给付金额计入被保人该类别出险年份的已给付额度，
+// 超过类别 annual_limit 返回 ERROR_QUOTA_EXCEEDED（返回值同 ReviewClaim）。
+//
 // 输出：
 // - 使用 market.Release 创建一次性释放计划
-// - StateOutput: claim_{claim_id} (更新状态为PAID)
-// - StateOutput: round_{round_id} (更新total_approved_payout)
-// - Event: MutualAidPayout
+// - StateOutput: claim:{claim_id} (更新状态为PAID)
+// - StateOutput: category_usage_{address}_{category_id}_{year} (案件指定类别时)
+// - StateOutput: round:{round_id} (更新total_approved_payout)
+// - StateOutput: claims_approved_unpaid (减一)
+// - Event: MutualAidPayout（同时追加到事件日志 index:event_log）
 //
 //export Payout
 func Payout() uint32 {
+	framework.BeginInvocation()
 	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
 
 	// 1. 权限检查
 	if !checkOperator() {
 		return framework.ERROR_UNAUTHORIZED
 	}
 
-	planID := params.ParseJSON("plan_id")
 	claimID := params.ParseJSON("claim_id")
 	fromStr := params.ParseJSON("from")
 	beneficiaryStr := params.ParseJSON("beneficiary")
@@ -1702,10 +4755,18 @@ func Payout() uint32 {
 		return framework.ERROR_INVALID_STATE
 	}
 
-	// 4. 检查给付金额不超过批准金额
+	// 4. 检查给付金额不超过批准金额与类别年度累计上限
 	if amount > approvedAmount {
 		return framework.ERROR_INVALID_PARAMS
 	}
+	categoryUsageData, hasCategory := loadClaimCategoryUsage(cClaimID)
+	if hasCategory {
+		usage, ok := categoryUsageData.Usage.recordPayout(categoryUsageData.Category.AnnualLimit, amount)
+		if !ok {
+			return rejectWithDetail(framework.ERROR_QUOTA_EXCEEDED, annualLimitDetail(categoryUsageData, cClaimID, amount))
+		}
+		categoryUsageData.Usage = usage
+	}
 
 	// 5. 使用Release创建一次性释放计划
 	vestingID := []byte(planID + "_" + claimID + "_" + payoutID)
@@ -1727,47 +4788,206 @@ func Payout() uint32 {
 	if _, err := framework.AppendStateOutputSimple(claimStateID, 3, newClaimData, nil); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
 	}
+	if code := addCumulative(planKey(STATE_CUMULATIVE_PAID), amount); code != framework.SUCCESS {
+		return code
+	}
+	if code := adjustApprovedUnpaid(0, 1); code != framework.SUCCESS {
+		return code
+	}
+	if hasCategory {
+		if code := appendVersionedState(categoryUsageData.StateID, encodeCategoryUsage(categoryUsageData.Usage)); code != framework.SUCCESS {
+			return code
+		}
+	}
+
+	// 7. 更新被保人的total_received（如果insured是成员）
+	// 将insured字符串（20字节原始数据）转换为Address
+	insuredAddr := framework.AddressFromBytes([]byte(insured))
+	insuredMemberStateID := getMemberStateID(insuredAddr)
+	insuredMemberData, _ := framework.GetState(string(insuredMemberStateID))
+	insuredTotalReceived := uint64(0)
+	if len(insuredMemberData) > 0 {
+		insuredStatus, insuredJoinTime, insuredTotalPaid, insuredTotalReceivedOld, insuredArrearsAmount, insuredLastSettledRound, insuredTier, insuredActivationSeq := decodeMember(insuredMemberData)
+		newInsuredTotalReceived := insuredTotalReceivedOld + amount
+		insuredTotalReceived = newInsuredTotalReceived
+		newInsuredMemberData := encodeMember(insuredStatus, insuredJoinTime, insuredTotalPaid, newInsuredTotalReceived, insuredArrearsAmount, insuredLastSettledRound, insuredTier, insuredActivationSeq)
+		if _, err := framework.AppendStateOutputSimple(insuredMemberStateID, 2, newInsuredMemberData, nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
+	// 8. 发出事件
+	event := framework.NewEvent("MutualAidPayout")
+	event.AddStringField("plan_id", planID)
+	event.AddStringField("claim_id", claimID)
+	event.AddAddressField("from", from)
+	event.AddAddressField("beneficiary", beneficiary)
+	event.AddIntField("amount", amount)
+	event.AddStringField("payout_id", payoutID)
+	if code := emitAuditEvent(event); code != framework.SUCCESS {
+		return code
+	}
+
+	// 9. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	result := map[string]interface{}{
+		"plan_id":                cPlanID,
+		"claim_id":               cClaimID,
+		"status":                 CLAIM_STATUS_PAID,
+		"applicant":              addressBytesToString([]byte(applicant)),
+		"insured":                addressBytesToString([]byte(insured)),
+		"beneficiary":            beneficiary.ToString(),
+		"requested_amount":       requestedAmount,
+		"approved_amount":        approvedAmount,
+		"payout_amount":          amount,
+		"round_id":               roundID,
+		"insured_total_received": insuredTotalReceived,
+		"payout_id":              payoutID,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// FinalizePlan 终结计划：将资金池余额按分摊权重分配给活跃成员，之后拒绝一切写操作（仅 operator 可调用）
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "pool": "Df2..."                    // 资金池地址（与 PayContribution / Payout 使用的地址相同）
+//	}
+//
+// 前置条件：
+// - 当前轮次不是 OPEN（需先 SettleRound / AdvanceRound 结算）
+// - 没有已批准但尚未给付的案件（claims_approved_unpaid 为 0）
+//
+// 资金池余额全部视为结余，按活跃成员的档位系数（与分摊相同的权重）按比例分配，
+// 取整余下的金额按活跃集合顺序每人补 1，分配总额等于余额（见 splitSurplus）；
+// 每名成员一笔 market.Release。没有活跃成员时不分配，余额留在资金池。
+// 终结后计划状态为 FINALIZED，除查询接口外的全部导出函数返回 ERROR_INVALID_STATE。
+//
+// 输出：
+// - 使用 market.Release 向每名分得金额的活跃成员释放结余
+// - StateOutput: plan_status (FINALIZED)
+// - Event: MutualAidPlanFinalized（含逐个成员的分配明细，超过事件大小上限时锚定）
+//
+// 错误码：
+// - ERROR_INVALID_PARAMS: plan_id 为空或 pool 地址无效
+// - ERROR_UNAUTHORIZED: 调用者不是 operator
+// - ERROR_NOT_FOUND: 计划不存在
+// - ERROR_INVALID_STATE: 计划已终结，或不满足前置条件
+//   （返回值为 {"error":"ROUND_OPEN","round_id":...} 或 {"error":"CLAIMS_UNPAID","approved_unpaid_claims":...}）
+//
+//export FinalizePlan
+func FinalizePlan() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	planID := params.ParseJSON("plan_id")
+	if code := usePlan(planID); code != framework.SUCCESS {
+		return code
+	}
+	if code := requirePlanActive(); code != framework.SUCCESS {
+		return code
+	}
+
+	// 1. 权限检查
+	if !checkOperator() {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	pool, err := framework.ParseAddressBase58(params.ParseJSON("pool"))
+	if planID == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(configData) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
 
-	// 7. 更新被保人的total_received（如果insured是成员）
-	// 将insured字符串（20字节原始数据）转换为Address
-	insuredAddr := framework.AddressFromBytes([]byte(insured))
-	insuredMemberStateID := getMemberStateID(insuredAddr)
-	insuredMemberData, _ := framework.GetState(string(insuredMemberStateID))
-	insuredTotalReceived := uint64(0)
-	if len(insuredMemberData) > 0 {
-		insuredStatus, insuredJoinTime, insuredTotalPaid, insuredTotalReceivedOld, insuredArrearsAmount, insuredLastSettledRound := decodeMember(insuredMemberData)
-		newInsuredTotalReceived := insuredTotalReceivedOld + amount
-		insuredTotalReceived = newInsuredTotalReceived
-		newInsuredMemberData := encodeMember(insuredStatus, insuredJoinTime, insuredTotalPaid, newInsuredTotalReceived, insuredArrearsAmount, insuredLastSettledRound)
-		if _, err := framework.AppendStateOutputSimple(insuredMemberStateID, 2, newInsuredMemberData, nil); err != nil {
+	// 2. 检查前置条件：当前轮次已结算、没有待给付的案件
+	currentRoundData, _ := framework.GetState(planKey(STATE_CURRENT_ROUND))
+	roundStatus := ""
+	roundID, roundData, ok := currentRoundRecord(currentRoundData, func(roundID string) []byte {
+		data, _ := framework.GetState(string(getRoundStateID(roundID)))
+		return data
+	})
+	if ok {
+		_, _, roundStatus, _, _, _, _, _, _, _ = decodeRound(roundData)
+	}
+	unpaidData, _ := framework.GetState(planKey(STATE_CLAIMS_APPROVED_UNPAID))
+	approvedUnpaid := framework.BytesToUint64(unpaidData)
+	switch finalizeBlocker(roundStatus, approvedUnpaid) {
+	case FINALIZE_REJECT_ROUND_OPEN:
+		return rejectWithDetail(framework.ERROR_INVALID_STATE, map[string]interface{}{
+			"error":    FINALIZE_REJECT_ROUND_OPEN,
+			"round_id": roundID,
+		})
+	case FINALIZE_REJECT_CLAIMS_UNPAID:
+		return rejectWithDetail(framework.ERROR_INVALID_STATE, map[string]interface{}{
+			"error":                  FINALIZE_REJECT_CLAIMS_UNPAID,
+			"approved_unpaid_claims": approvedUnpaid,
+		})
+	}
+
+	// 3. 按档位系数计算各活跃成员的分配额
+	members := loadMemberList(getMembersActivePageStateID, planKey(STATE_MEMBERS_ACTIVE_COUNT))
+	weights := make([]uint64, len(members))
+	for i, m := range members {
+		memberData, _ := framework.GetState(string(getMemberStateID(m)))
+		_, _, _, _, _, _, tier, _ := decodeMember(memberData)
+		weights[i] = loadTierMultiplier(tier)
+	}
+	surplus := uint64(framework.QueryUTXOBalance(pool, framework.TokenID("")))
+	shares := splitSurplus(surplus, weights)
+
+	// 4. 逐个成员释放结余
+	var distributed uint64
+	for i, m := range members {
+		if shares[i] == 0 {
+			continue
+		}
+		vestingID := []byte(planID + "_finalize_" + m.ToString())
+		if err := market.Release(pool, m, framework.TokenID(""), framework.Amount(shares[i]), vestingID); err != nil {
+			if contractErr, ok := err.(*framework.ContractError); ok {
+				return contractErr.Code
+			}
 			return framework.ERROR_EXECUTION_FAILED
 		}
+		distributed += shares[i]
 	}
 
-	// 8. 发出事件
-	event := framework.NewEvent("MutualAidPayout")
+	// 5. 写入计划状态
+	if code := appendVersionedState([]byte(planKey(STATE_PLAN_STATUS)), []byte(PLAN_STATUS_FINALIZED)); code != framework.SUCCESS {
+		return code
+	}
+
+	// 6. 发出事件（分配明细随成员数增长，超过事件大小上限时锚定）
+	items := surplusDistributionItems(members, weights, shares)
+	finalizedAt := framework.GetTimestamp()
+	event := framework.NewEvent(EVENT_PLAN_FINALIZED)
 	event.AddStringField("plan_id", planID)
-	event.AddStringField("claim_id", claimID)
-	event.AddAddressField("from", from)
-	event.AddAddressField("beneficiary", beneficiary)
-	event.AddIntField("amount", amount)
-	event.AddStringField("payout_id", payoutID)
-	framework.EmitEvent(event)
+	event.AddAddressField("pool", pool)
+	event.AddIntField("surplus", surplus)
+	event.AddIntField("distributed", distributed)
+	event.AddIntField("member_count_active", uint64(len(members)))
+	event.AddField("distributions", items)
+	event.AddIntField("finalized_at", finalizedAt)
+	if _, err := framework.EmitEventOrAnchor(event); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
-	// 9. 返回业务结果（WES ISPC 特性：同步返回业务数据）
+	// 7. 返回业务结果（WES ISPC 特性：同步返回业务数据）
 	result := map[string]interface{}{
-		"plan_id":                cPlanID,
-		"claim_id":               cClaimID,
-		"status":                 CLAIM_STATUS_PAID,
-		"applicant":              addressBytesToString([]byte(applicant)),
-		"insured":                addressBytesToString([]byte(insured)),
-		"beneficiary":            beneficiary.ToString(),
-		"requested_amount":       requestedAmount,
-		"approved_amount":        approvedAmount,
-		"payout_amount":          amount,
-		"round_id":               roundID,
-		"insured_total_received": insuredTotalReceived,
-		"payout_id":              payoutID,
+		"plan_id":             planID,
+		"status":              PLAN_STATUS_FINALIZED,
+		"pool":                pool.ToString(),
+		"surplus":             surplus,
+		"distributed":         distributed,
+		"member_count_active": uint64(len(members)),
+		"distributions":       items,
+		"finalized_at":        finalizedAt,
 	}
 	if err := framework.SetReturnJSON(result); err != nil {
 		return framework.ERROR_EXECUTION_FAILED
@@ -1779,6 +4999,22 @@ func Payout() uint32 {
 // ================================================================================================
 // 查询接口（只读）
 // ================================================================================================
+//
+// 查询以视图函数实现：导出函数通过 framework.ServeView 执行，Multicall 可批量调用。
+// GetLimits 不读取状态，其视图函数与登记在 rules.go。
+
+func init() {
+	framework.RegisterViewFunction("GetPlanInfo", viewPlanInfo)
+	framework.RegisterViewFunction("GetMemberInfo", viewMemberInfo)
+	framework.RegisterViewFunction("GetClaimInfo", viewClaimInfo)
+	framework.RegisterViewFunction("GetRoundInfo", viewRoundInfo)
+	framework.RegisterViewFunction("GetCurrentRound", viewCurrentRound)
+	framework.RegisterViewFunction("PreviewSettlement", viewPreviewSettlement)
+	framework.RegisterViewFunction("EstimateContribution", viewEstimateContribution)
+	framework.RegisterViewFunction("ListMembers", viewListMembers)
+	framework.RegisterViewFunction("ListClaims", viewListClaims)
+	framework.RegisterViewFunction("QueryEventLog", framework.ViewEventLog)
+}
 
 // GetPlanInfo 获取计划信息
 //
@@ -1788,52 +5024,88 @@ func Payout() uint32 {
 //	  "plan_id": "plan_xianghubao_001"
 //	}
 //
-// 返回：JSON格式的计划配置信息
+// 返回：JSON格式的计划配置信息，含保障类别 categories
 //
 //export GetPlanInfo
 func GetPlanInfo() uint32 {
-	params := framework.GetContractParams()
+	return framework.ServeView("GetPlanInfo")
+}
 
+// viewPlanInfo GetPlanInfo 的视图函数
+func viewPlanInfo(params *framework.ContractParams) (interface{}, error) {
 	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
 	if planID == "" {
-		return framework.ERROR_INVALID_PARAMS
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id is required")
 	}
 
-	configData, _ := framework.GetState(STATE_PLAN_CONFIG)
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
 	if len(configData) == 0 {
-		return framework.ERROR_NOT_FOUND
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "plan not found")
 	}
 
 	planIDDecoded, name, tokenID, coverageAmount, serviceFeeBP, settlementPeriod, waitingPeriod, minMembers, monthlyCapPerMember := decodePlanConfig(configData)
 
-	operatorData, _ := framework.GetState(STATE_OPERATOR)
+	// 与 checkOperator 相同经由角色持有者解析（链上读取会去除地址尾部零字节，不能按长度 >= 20 判断）
 	operatorAddr := ""
-	if len(operatorData) >= 20 {
-		operatorAddr = framework.AddressFromBytes(operatorData[:20]).ToString()
+	if operator, err := framework.RoleHolder(planRole(ROLE_OPERATOR)); err == nil {
+		operatorAddr = operator.ToString()
 	}
 
-	memberCountData, _ := framework.GetState(STATE_MEMBER_COUNT)
-	memberCount := bytesToUint64(memberCountData)
+	memberCountData, _ := framework.GetState(planKey(STATE_MEMBER_COUNT))
+	memberCount := framework.BytesToUint64(memberCountData)
 
-	result := map[string]interface{}{
-		"plan_id":                planIDDecoded,
-		"name":                   name,
-		"token_id":               tokenID,
-		"coverage_amount":        coverageAmount,
-		"service_fee_bp":         serviceFeeBP,
-		"settlement_period":      settlementPeriod,
-		"waiting_period":         waitingPeriod,
-		"min_members":            minMembers,
-		"monthly_cap_per_member": monthlyCapPerMember,
-		"operator":               operatorAddr,
-		"member_count_active":    memberCount,
-	}
+	adjData, _ := framework.GetState(planKey(STATE_FEE_ADJUSTMENT))
+	feeMode, minFeeBP, maxFeeBP := decodeFeeAdjustment(adjData)
+	effectiveFeeBP, _, claimsRatio := loadEffectiveServiceFeeBP(serviceFeeBP)
 
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	roundingData, _ := framework.GetState(planKey(STATE_ROUNDING_CONFIG))
+	rounding := decodeRoundingConfig(roundingData)
+	carryData, _ := framework.GetState(planKey(STATE_ROUNDING_CARRY))
+
+	// 累计分摊的链上/线下拆分（线下部分为 operator 登记且未冲正的金额）
+	collectedData, _ := framework.GetState(planKey(STATE_CUMULATIVE_COLLECTED))
+	offchainData, _ := framework.GetState(planKey(STATE_CUMULATIVE_OFFCHAIN))
+	paidData, _ := framework.GetState(planKey(STATE_CUMULATIVE_PAID))
+	cumulativeCollected := framework.BytesToUint64(collectedData)
+	offchainCollected := framework.BytesToUint64(offchainData)
+	var onchainCollected uint64
+	if cumulativeCollected > offchainCollected {
+		onchainCollected = cumulativeCollected - offchainCollected
 	}
 
-	return framework.SUCCESS
+	result := map[string]interface{}{
+		"plan_id":                  planIDDecoded,
+		"status":                   loadPlanStatus(),
+		"name":                     name,
+		"token_id":                 tokenID,
+		"coverage_amount":          coverageAmount,
+		"service_fee_bp":           serviceFeeBP,
+		"settlement_period":        settlementPeriod,
+		"waiting_period":           waitingPeriod,
+		"min_members":              minMembers,
+		"monthly_cap_per_member":   monthlyCapPerMember,
+		"operator":                 operatorAddr,
+		"member_count_active":      memberCount,
+		"fee_mode":                 feeMode,
+		"min_fee_bp":               minFeeBP,
+		"max_fee_bp":               maxFeeBP,
+		"effective_service_fee_bp": effectiveFeeBP,
+		"claims_ratio_bp":          claimsRatio,
+		"cumulative_collected":     cumulativeCollected,
+		"onchain_collected":        onchainCollected,
+		"offchain_collected":       offchainCollected,
+		"cumulative_paid":          framework.BytesToUint64(paidData),
+		"rounding_mode":            rounding.Mode,
+		"rounding_decimals":        rounding.Decimals,
+		"rounding_carry_forward":   rounding.CarryForward,
+		"rounding_carry":           framework.BytesToInt64(carryData),
+		"categories":               coverageCategoryItems(loadCoverageCategories()),
+	}
+
+	return result, nil
 }
 
 // GetMemberInfo 获取成员信息
@@ -1845,30 +5117,37 @@ func GetPlanInfo() uint32 {
 //	  "member": "Cf1..." // 成员地址（Base58）
 //	}
 //
-// 返回：JSON格式的成员信息
+// 返回：JSON格式的成员信息，含类别除外 exclusions 与各类别当年剩余额度 category_headroom
 //
 //export GetMemberInfo
 func GetMemberInfo() uint32 {
-	params := framework.GetContractParams()
+	return framework.ServeView("GetMemberInfo")
+}
 
+// viewMemberInfo GetMemberInfo 的视图函数
+func viewMemberInfo(params *framework.ContractParams) (interface{}, error) {
 	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
 	memberStr := params.ParseJSON("member")
 	if planID == "" || memberStr == "" {
-		return framework.ERROR_INVALID_PARAMS
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id and member are required")
 	}
 
 	member, err := framework.ParseAddressBase58(memberStr)
 	if err != nil {
-		return framework.ERROR_INVALID_PARAMS
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid member address")
 	}
 
 	memberStateID := getMemberStateID(member)
 	memberData, _ := framework.GetState(string(memberStateID))
 	if len(memberData) == 0 {
-		return framework.ERROR_NOT_FOUND
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "member not found")
 	}
 
-	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound := decodeMember(memberData)
+	status, joinTime, totalPaid, totalReceived, arrearsAmount, lastSettledRound, tier, activationSeq := decodeMember(memberData)
+	year := calendarYear(framework.GetTimestamp())
 
 	result := map[string]interface{}{
 		"plan_id":            planID,
@@ -1879,13 +5158,16 @@ func GetMemberInfo() uint32 {
 		"total_received":     totalReceived,
 		"arrears_amount":     arrearsAmount,
 		"last_settled_round": lastSettledRound,
+		"tier":               tier,
+		"tier_multiplier_bp": loadTierMultiplier(tier),
+		"activation_seq":     activationSeq,
+		"exclusions":         exclusionItems(loadMemberExclusions(member)),
+		"category_headroom": categoryHeadroomItems(loadCoverageCategories(), year, func(categoryID string) categoryUsage {
+			return loadCategoryUsage(member, categoryID, year)
+		}),
 	}
 
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
-	}
-
-	return framework.SUCCESS
+	return result, nil
 }
 
 // GetClaimInfo 获取理赔案件信息
@@ -1901,18 +5183,24 @@ func GetMemberInfo() uint32 {
 //
 //export GetClaimInfo
 func GetClaimInfo() uint32 {
-	params := framework.GetContractParams()
+	return framework.ServeView("GetClaimInfo")
+}
 
+// viewClaimInfo GetClaimInfo 的视图函数
+func viewClaimInfo(params *framework.ContractParams) (interface{}, error) {
 	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
 	claimID := params.ParseJSON("claim_id")
 	if planID == "" || claimID == "" {
-		return framework.ERROR_INVALID_PARAMS
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id and claim_id are required")
 	}
 
 	claimStateID := getClaimStateID(claimID)
 	claimData, _ := framework.GetState(string(claimStateID))
 	if len(claimData) == 0 {
-		return framework.ERROR_NOT_FOUND
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "claim not found")
 	}
 
 	cPlanID, cClaimID, applicant, insured, status, roundID, evidenceHash, investigationHash, requestedAmount, approvedAmount, eventTime := decodeClaim(claimData)
@@ -1931,11 +5219,7 @@ func GetClaimInfo() uint32 {
 		"event_time":         eventTime,
 	}
 
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
-	}
-
-	return framework.SUCCESS
+	return result, nil
 }
 
 // GetRoundInfo 获取轮次信息
@@ -1951,21 +5235,251 @@ func GetClaimInfo() uint32 {
 //
 //export GetRoundInfo
 func GetRoundInfo() uint32 {
-	params := framework.GetContractParams()
+	return framework.ServeView("GetRoundInfo")
+}
 
+// viewRoundInfo GetRoundInfo 的视图函数
+func viewRoundInfo(params *framework.ContractParams) (interface{}, error) {
 	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
 	roundID := params.ParseJSON("round_id")
 	if planID == "" || roundID == "" {
-		return framework.ERROR_INVALID_PARAMS
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id and round_id are required")
 	}
 
 	roundStateID := getRoundStateID(roundID)
 	roundData, _ := framework.GetState(string(roundStateID))
 	if len(roundData) == 0 {
-		return framework.ERROR_NOT_FOUND
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "round not found")
+	}
+
+	return roundInfo(roundID, roundData), nil
+}
+
+// GetCurrentRound 获取当前轮次信息
+//
+// 先读取 current_round_id，再读取对应的轮次记录，返回内容与 GetRoundInfo 相同；
+// 客户端无需事先知道轮次ID。当前轮次为最近一次 OpenRound / AdvanceRound 开启的轮次，
+// 其状态可能已是 SETTLED 等，以返回的 status 为准。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001"
+//	}
+//
+// 返回：JSON格式的轮次信息；尚未开启任何轮次时返回 ERROR_NOT_FOUND
+//
+//export GetCurrentRound
+func GetCurrentRound() uint32 {
+	return framework.ServeView("GetCurrentRound")
+}
+
+// viewCurrentRound GetCurrentRound 的视图函数
+func viewCurrentRound(params *framework.ContractParams) (interface{}, error) {
+	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
+	if planID == "" {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id is required")
+	}
+
+	currentRoundData, _ := framework.GetState(planKey(STATE_CURRENT_ROUND))
+	roundID, roundData, ok := currentRoundRecord(currentRoundData, func(roundID string) []byte {
+		data, _ := framework.GetState(string(getRoundStateID(roundID)))
+		return data
+	})
+	if !ok {
+		return nil, framework.NewContractError(framework.ERROR_NOT_FOUND, "no round opened")
+	}
+
+	return roundInfo(roundID, roundData), nil
+}
+
+// PreviewSettlement 预览轮次结算（只读，供 operator 结算前规划资金）
+//
+// 使用与 SettleRound 相同的计算（planRoundSettlement）返回结算结果，不写入任何状态、
+// 不发出事件；轮次状态、累计取整余额等在预览前后保持不变。只要两次调用之间状态不变，
+// 预览结果与随后 SettleRound 的返回值一致。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "round_id": "round_202501_01",
+//	  "pool": "Df2..."                    // 资金池地址（可选，提供时检查余额是否覆盖本轮给付）
+//	}
+//
+// 返回：SettleRound 返回值的全部字段，另含：
+//   - claims: 计入本轮的案件及批准金额（同 MutualAidRoundPayoutSummary）
+//   - tier_dues: 各档位成员应缴额 [{tier, tier_multiplier_bp, due}]
+//   - pool_balance: 资金池原生币余额（提供 pool 时）
+//   - warnings: 风险提示列表，如 "pool balance covers only 80% of projected payouts"
+//
+// 轮次不存在返回 ERROR_NOT_FOUND，轮次已结算返回 ERROR_INVALID_STATE。
+// 合约未登记资金池地址（PayContribution / Payout 由调用方传入），因此资金池由预览参数指定。
+//
+//export PreviewSettlement
+func PreviewSettlement() uint32 {
+	return framework.ServeView("PreviewSettlement")
+}
+
+// viewPreviewSettlement PreviewSettlement 的视图函数
+func viewPreviewSettlement(params *framework.ContractParams) (interface{}, error) {
+	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
+	roundID := params.ParseJSON("round_id")
+	poolStr := params.ParseJSON("pool")
+	if planID == "" || roundID == "" {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id and round_id are required")
+	}
+
+	// 1-2. 读取 OPEN 轮次并执行与 SettleRound 相同的结算计算，不写入累计取整余额
+	roundData, monthlyCap, in, plan, err := openRoundSettlement(roundID)
+	if err != nil {
+		return nil, err
+	}
+	rPlanID, rRoundID, _, periodStart, periodEnd, _, _, _, payersCount, _ := decodeRound(roundData)
+
+	// 3. 预览附加信息：档位应缴额与资金池余额
+	ctx := settlementPreview{
+		Now:             framework.GetTimestamp(),
+		PeriodEnd:       periodEnd,
+		MonthlyCap:      monthlyCap,
+		TierMultipliers: make([]uint64, MAX_TIERS),
+	}
+	for tier := uint64(0); tier < MAX_TIERS; tier++ {
+		ctx.TierMultipliers[tier] = loadTierMultiplier(tier)
+	}
+	if poolStr != "" {
+		pool, err := framework.ParseAddressBase58(poolStr)
+		if err != nil {
+			return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid pool address")
+		}
+		ctx.PoolBalance = uint64(framework.QueryUTXOBalance(pool, framework.TokenID("")))
+		ctx.HasPool = true
+	}
+
+	result := roundSettlementResult(rPlanID, rRoundID, periodStart, periodEnd, payersCount, in, plan)
+	result["claims"] = payoutSummaryItems(in.ClaimIDs, in.ApprovedAmount)
+	tierDues, ok := settlementTierDues(plan.Settlement.PerCapita, ctx.TierMultipliers)
+	if !ok {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_STATE, "tier due overflows")
+	}
+	result["tier_dues"] = tierDues
+	if ctx.HasPool {
+		result["pool_balance"] = ctx.PoolBalance
+	}
+	result["warnings"] = settlementWarnings(in, plan, ctx)
+	return result, nil
+}
+
+// openRoundSettlement 读取 OPEN 轮次并按当前数据执行结算计算，不写入状态（PreviewSettlement / EstimateContribution 共用）
+//
+// 校验同 SettleRound：轮次或计划不存在返回 ERROR_NOT_FOUND，轮次不是 OPEN 或结算金额溢出返回 ERROR_INVALID_STATE。
+// 返回轮次记录原始数据、计划的 monthly_cap_per_member 与 planRoundSettlement 的输入和结果。
+func openRoundSettlement(roundID string) (roundData []byte, monthlyCap uint64, in roundSettlementInputs, plan roundSettlementPlan, err error) {
+	roundData, _ = framework.GetState(string(getRoundStateID(roundID)))
+	if len(roundData) == 0 {
+		return nil, 0, in, plan, framework.NewContractError(framework.ERROR_NOT_FOUND, "round not found")
+	}
+	if _, _, status, _, _, _, _, _, _, _ := decodeRound(roundData); status != ROUND_STATUS_OPEN {
+		return nil, 0, in, plan, framework.NewContractError(framework.ERROR_INVALID_STATE, "round already settled")
+	}
+	configData, _ := framework.GetState(planKey(STATE_PLAN_CONFIG))
+	if len(configData) == 0 {
+		return nil, 0, in, plan, framework.NewContractError(framework.ERROR_NOT_FOUND, "plan not found")
+	}
+	_, _, _, _, serviceFeeBP, _, _, _, monthlyCap := decodePlanConfig(configData)
+
+	in = loadRoundSettlementInputs(roundID, serviceFeeBP)
+	plan, ok := planRoundSettlement(in)
+	if !ok {
+		return nil, 0, in, plan, framework.NewContractError(framework.ERROR_INVALID_STATE, "settlement overflows")
+	}
+	return roundData, monthlyCap, in, plan, nil
+}
+
+// EstimateContribution 估算轮次的应缴分摊（只读，供成员在结算前查看）
+//
+// 对 OPEN 轮次，按当前已批准并归入本轮的案件（round_claims:{round_id}）执行与 SettleRound 相同的计算
+// （planRoundSettlement），返回实时的人均分摊估算；调用者有成员记录时另返回按其档位系数计算的应缴额。
+// 之后再有案件批准归入本轮时估算随之变化；没有新的批准案件且其他状态不变时，
+// 估算的 per_capita_contribution 与随后 SettleRound 的返回值一致。
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "round_id": "round_202501_01"
+//	}
+//
+// 返回：
+//   - plan_id / round_id / period_end
+//   - approved_claims_count / total_approved_payout: 当前归入本轮的已批准案件数与批准总额
+//   - effective_service_fee_bp / total_with_fee / member_count_active / per_capita_contribution: 同 SettleRound
+//   - tier / tier_multiplier_bp / member_due: 调用者的档位、系数与应缴额（调用者有成员记录时）
+//   - estimated_at: 估算时的区块时间
+//
+// 轮次不存在返回 ERROR_NOT_FOUND，已结算返回 ERROR_INVALID_STATE。
+//
+//export EstimateContribution
+func EstimateContribution() uint32 {
+	return framework.ServeView("EstimateContribution")
+}
+
+// viewEstimateContribution EstimateContribution 的视图函数
+func viewEstimateContribution(params *framework.ContractParams) (interface{}, error) {
+	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
 	}
+	roundID := params.ParseJSON("round_id")
+	if planID == "" || roundID == "" {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id and round_id are required")
+	}
+
+	roundData, _, in, plan, err := openRoundSettlement(roundID)
+	if err != nil {
+		return nil, err
+	}
+	rPlanID, rRoundID, _, _, periodEnd, _, _, _, _, _ := decodeRound(roundData)
+
+	result := map[string]interface{}{
+		"plan_id":                  rPlanID,
+		"round_id":                 rRoundID,
+		"period_end":               periodEnd,
+		"approved_claims_count":    uint64(len(in.ClaimIDs)),
+		"total_approved_payout":    plan.TotalApprovedPayout,
+		"effective_service_fee_bp": plan.EffectiveFeeBP,
+		"total_with_fee":           plan.Settlement.TotalWithFee,
+		"member_count_active":      in.MemberCount,
+		"per_capita_contribution":  plan.Settlement.PerCapita,
+		"estimated_at":             framework.GetTimestamp(),
+	}
+	if memberData, _ := framework.GetState(string(getMemberStateID(framework.GetCaller()))); len(memberData) > 0 {
+		_, _, _, _, _, _, tier, _ := decodeMember(memberData)
+		multiplier := loadTierMultiplier(tier)
+		due, ok := memberDue(plan.Settlement.PerCapita, multiplier)
+		if !ok {
+			return nil, framework.NewContractError(framework.ERROR_INVALID_STATE, "member due overflows")
+		}
+		result["tier"] = tier
+		result["tier_multiplier_bp"] = multiplier
+		result["member_due"] = due
+	}
+	return result, nil
+}
 
-	rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount := decodeRound(roundData)
+// roundInfo 轮次信息（GetRoundInfo / GetCurrentRound 共用）
+func roundInfo(roundID string, roundData []byte) map[string]interface{} {
+	rPlanID, rRoundID, status, periodStart, periodEnd, totalApprovedPayout, totalServiceFee, perCapitaContribution, payersCount, snapshotSeq := decodeRound(roundData)
+	onchainPaid, offchainPaid := loadRoundPaid(roundID)
 
 	result := map[string]interface{}{
 		"plan_id":                 rPlanID,
@@ -1977,31 +5491,252 @@ func GetRoundInfo() uint32 {
 		"total_service_fee":       totalServiceFee,
 		"per_capita_contribution": perCapitaContribution,
 		"payers_count":            payersCount,
+		"onchain_paid":            onchainPaid,
+		"offchain_paid":           offchainPaid,
 	}
 
-	if err := framework.SetReturnJSON(result); err != nil {
-		return framework.ERROR_EXECUTION_FAILED
+	// 成员快照：轮次开启时的活跃成员数（分摊基数）与快照引用
+	if snapshotMemberCount, snapshotWeight, ok := loadRoundSnapshot(roundID); ok {
+		result["snapshot_member_count"] = snapshotMemberCount
+		result["snapshot_total_weight_bp"] = snapshotWeight
+		result["snapshot_seq"] = snapshotSeq
 	}
 
-	return framework.SUCCESS
+	return result
+}
+
+// ListMembers 分页列出计划成员
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "status": "ACTIVE",                 // 可选：状态过滤，为空时列出全部成员
+//	  "offset": 0,                        // 可选：起始位置（过滤后），默认0
+//	  "limit": 50                         // 可选：每页条数，默认50，最大100
+//	}
+//
+// 数据来源：
+// - status 为 ACTIVE：活跃成员集合 members_active（顺序因交换删除而不保证）
+// - 其他：成员索引 members_all（按加入顺序），按成员记录的当前状态过滤
+//
+// 返回：JSON格式的成员列表（members、total、offset、limit、next_offset、has_more）
+//
+//export ListMembers
+func ListMembers() uint32 {
+	return framework.ServeView("ListMembers")
+}
+
+// viewListMembers ListMembers 的视图函数
+func viewListMembers(params *framework.ContractParams) (interface{}, error) {
+	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
+	}
+	status := params.ParseJSON("status")
+	offset := params.ParseJSONInt("offset")
+	limit := memberListLimit(params.ParseJSONInt("limit"))
+	if planID == "" {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id is required")
+	}
+
+	// 1. 选择数据来源：活跃成员直接使用活跃集合，其余按成员索引过滤
+	var members []framework.Address
+	filter := status
+	if status == MEMBER_STATUS_ACTIVE {
+		members = loadMemberList(getMembersActivePageStateID, planKey(STATE_MEMBERS_ACTIVE_COUNT))
+		filter = ""
+	} else {
+		members = loadMemberIndex()
+	}
+	addrs := make([][]byte, len(members))
+	for i := range members {
+		addrs[i] = members[i].ToBytes()
+	}
+
+	// 2. 过滤并分页（未过滤时只读取本页成员的状态）
+	statusOf := func(addr []byte) string {
+		memberData, _ := framework.GetState(string(getMemberStateID(framework.AddressFromBytes(addr))))
+		memberStatus, _, _, _, _, _, _, _ := decodeMember(memberData)
+		return memberStatus
+	}
+	entries, total := filterMemberList(addrs, statusOf, filter, offset, limit)
+
+	// 3. 返回成员列表
+	list := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, map[string]interface{}{
+			"address": framework.AddressFromBytes(entry.Address).ToString(),
+			"status":  entry.Status,
+		})
+	}
+	nextOffset := offset + uint64(len(entries))
+	result := map[string]interface{}{
+		"plan_id":     planID,
+		"status":      status,
+		"members":     list,
+		"total":       total,
+		"offset":      offset,
+		"limit":       limit,
+		"next_offset": nextOffset,
+		"has_more":    nextOffset < total,
+	}
+	return result, nil
+}
+
+// ListClaims 分页列出计划的理赔案件
+//
+// 参数（JSON）：
+//
+//	{
+//	  "plan_id": "plan_xianghubao_001",
+//	  "status": "APPROVED",               // 可选：状态过滤
+//	  "round_id": "round_202501_01",      // 可选：归属轮次过滤
+//	  "offset": 0,                        // 可选：起始位置（过滤前，按案件ID字典序），默认0
+//	  "limit": 20                         // 可选：每页读取的案件数，默认20，最大50
+//	}
+//
+// 数据来源：按 plan:{plan_id}:claim: 前缀查询链上案件记录（framework.QueryStatesByPrefix），
+// 过滤在分页之后进行，一页返回的案件可能少于 limit；翻页以 next_offset 为准。
+//
+// 返回：JSON格式的案件列表（claims、offset、limit、next_offset、has_more）
+//
+//export ListClaims
+func ListClaims() uint32 {
+	return framework.ServeView("ListClaims")
 }
 
-// uint64ToString 将uint64转换为字符串
-func uint64ToString(n uint64) string {
-	if n == 0 {
-		return "0"
+// viewListClaims ListClaims 的视图函数
+func viewListClaims(params *framework.ContractParams) (interface{}, error) {
+	planID := params.ParseJSON("plan_id")
+	if usePlan(planID) != framework.SUCCESS {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "invalid plan_id")
 	}
-	digits := make([]byte, 0, 20)
-	num := n
-	for num > 0 {
-		digits = append(digits, byte('0'+num%10))
-		num /= 10
+	status := params.ParseJSON("status")
+	roundID := params.ParseJSON("round_id")
+	offset := params.ParseJSONInt("offset")
+	limit := claimListLimit(params.ParseJSONInt("limit"))
+	if planID == "" || offset > uint64(^uint32(0)) {
+		return nil, framework.NewContractError(framework.ERROR_INVALID_PARAMS, "plan_id is required and offset must fit in uint32")
 	}
-	// 反转数字
-	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
-		digits[i], digits[j] = digits[j], digits[i]
+
+	// 1. 按前缀读取本页案件记录
+	entries, err := framework.QueryStatesByPrefix([]byte(planPrefix(STATE_CLAIM_PREFIX)), limit, uint32(offset))
+	if err != nil {
+		return nil, err
 	}
-	return string(digits)
+
+	// 2. 解码并过滤：前缀以 ':' 结束只匹配案件记录，仍校验记录的计划与状态ID
+	list := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		cPlanID, cClaimID, applicant, insured, cStatus, cRoundID, _, _, requestedAmount, approvedAmount, eventTime := decodeClaim(e.Value)
+		if cPlanID != planID || string(getClaimStateID(cClaimID)) != string(e.StateID) {
+			continue
+		}
+		if (status != "" && cStatus != status) || (roundID != "" && cRoundID != roundID) {
+			continue
+		}
+		list = append(list, map[string]interface{}{
+			"claim_id":         cClaimID,
+			"applicant":        addressBytesToString([]byte(applicant)),
+			"insured":          addressBytesToString([]byte(insured)),
+			"status":           cStatus,
+			"round_id":         cRoundID,
+			"requested_amount": requestedAmount,
+			"approved_amount":  approvedAmount,
+			"event_time":       eventTime,
+		})
+	}
+
+	// 3. 返回案件列表
+	result := map[string]interface{}{
+		"plan_id":     planID,
+		"claims":      list,
+		"offset":      offset,
+		"limit":       uint64(limit),
+		"next_offset": offset + uint64(len(entries)),
+		"has_more":    uint32(len(entries)) == limit,
+	}
+	return result, nil
+}
+
+// QueryEventLog 分页查询合约内事件日志
+//
+// 理赔案件事件（MutualAidClaimSubmitted、MutualAidClaimReviewed、MutualAidPayout）在发出的同时
+// 追加到事件日志（见 emitAuditEvent），可按事件名与时间范围回查，事件字段含 plan_id。
+//
+// 参数（JSON，均可选）：
+//
+//	{
+//	  "event": "MutualAidClaimReviewed",   // 事件名，缺省为全部事件
+//	  "from": 1736200000,                   // 起始时间（含）
+//	  "to": 1738800000,                     // 截止时间（含）
+//	  "cursor": 20,                         // 上一页返回的 next_cursor
+//	  "limit": 20                           // 每页条数，最多 50
+//	}
+//
+// 返回：events（seq / event / timestamp / data，data 为事件字段的 JSON 文本）、next_cursor、has_more
+// （见 framework.ViewEventLog）
+//
+//export QueryEventLog
+func QueryEventLog() uint32 {
+	return framework.ServeView("QueryEventLog")
+}
+
+// GetLimits 获取合约的输入与写入限制
+//
+// 参数（JSON）：无
+//
+// 返回：
+//
+//	{
+//	  "index_quotas": [
+//	    {"index": "claim_evidence", "max_entries_per_caller": 16, "max_entry_bytes": 256}
+//	  ],
+//	  "write_budgets": {"AttachEvidence": 1024},
+//	  "multicall": {"max_calls": 16, "max_request_bytes": 4096, "max_return_bytes": 8192, "views": [...]}
+//	}
+//
+//export GetLimits
+func GetLimits() uint32 {
+	return framework.ServeView("GetLimits")
+}
+
+// Multicall 在一次调用中批量执行只读查询
+//
+// 参数（JSON）：
+//
+//	{
+//	  "calls": [
+//	    {"method": "GetPlanInfo", "params": {"plan_id": "plan_xianghubao_001"}},
+//	    {"method": "GetMemberInfo", "params": {"plan_id": "plan_xianghubao_001", "member": "Cf1..."}},
+//	    {"method": "GetCurrentRound", "params": {"plan_id": "plan_xianghubao_001"}}
+//	  ]
+//	}
+//
+// 只能调用登记为视图函数的查询（GetPlanInfo、GetMemberInfo、GetClaimInfo、GetRoundInfo、
+// GetCurrentRound、PreviewSettlement、EstimateContribution、ListMembers、ListClaims、GetLimits、GetDisplayManifest），Payout 等写入方法逐条拒绝（ERROR_PERMISSION_DENIED）；
+// 单条查询失败（如 ERROR_NOT_FOUND）只体现在该条结果中。返回格式见 framework.HandleMulticall。
+//
+//export Multicall
+func Multicall() uint32 {
+	return framework.HandleMulticall()
+}
+
+// GetDisplayManifest 获取钱包显示清单
+//
+// 参数（JSON）：
+//
+//	{
+//	  "locale": "zh-CN" // 可选，默认 en-US；没有对应语言时按同语言、en-US 顺序回退
+//	}
+//
+// 返回：各导出函数的名称、参数名称与显示提示（金额、地址、时间戳等），格式见 framework.DisplayManifest。
+// 登记见 display.go。
+//
+//export GetDisplayManifest
+func GetDisplayManifest() uint32 {
+	return framework.ServeView(framework.DISPLAY_MANIFEST_METHOD)
 }
 
 func main() {}
@@ -0,0 +1,1939 @@
+package main
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ================================================================================================
+// 业务规则（纯函数）
+// ================================================================================================
+//
+// 本文件只包含不依赖宿主函数的业务规则计算，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试（go test）。
+// 导出方法（main.go）负责读取链上状态后调用这些规则。
+
+// 成员状态常量
+//
+// 状态转换流程：
+//
+//	PENDING -> ACTIVE (通过 ApproveMember)
+//	ACTIVE -> EXITED (通过 Exit)
+//	ACTIVE -> SUSPENDED (通过 SuspendMember)
+//	SUSPENDED -> ACTIVE (通过 ResumeMember)
+//	PENDING / ACTIVE / SUSPENDED -> BLACKLISTED (通过 BlacklistMember)
+const (
+	// MEMBER_STATUS_PENDING 待审核：成员已申请加入，等待 operator 审核
+	MEMBER_STATUS_PENDING = "PENDING"
+	// MEMBER_STATUS_ACTIVE 活跃：成员已激活，可以提交案件和缴纳分摊费用
+	MEMBER_STATUS_ACTIVE = "ACTIVE"
+	// MEMBER_STATUS_SUSPENDED 暂停：成员被临时暂停，不计入活跃成员数，不能提交案件或缴纳费用
+	MEMBER_STATUS_SUSPENDED = "SUSPENDED"
+	// MEMBER_STATUS_EXITED 已退出：成员主动退出计划，不再参与分摊
+	MEMBER_STATUS_EXITED = "EXITED"
+	// MEMBER_STATUS_BLACKLISTED 黑名单：成员因违规被拉黑，不能参与任何操作
+	MEMBER_STATUS_BLACKLISTED = "BLACKLISTED"
+)
+
+// 理赔案件状态常量
+//
+// 状态转换流程：
+//
+//	SUBMITTED -> UNDER_REVIEW (审核中，暂未使用)
+//	SUBMITTED/UNDER_REVIEW -> APPROVED (通过 ReviewClaim 批准)
+//	SUBMITTED/UNDER_REVIEW -> REJECTED (通过 ReviewClaim 拒绝)
+//	APPROVED -> PAID (通过 Payout 给付)
+//	SUBMITTED/UNDER_REVIEW -> CANCELLED (通过 CancelClaim，申请人或 operator)
+//	APPROVED -> CANCELLED (通过 CancelClaim，仅 operator)
+const (
+	// CLAIM_STATUS_SUBMITTED 已提交：成员已提交理赔申请，等待审核
+	CLAIM_STATUS_SUBMITTED = "SUBMITTED"
+	// CLAIM_STATUS_UNDER_REVIEW 审核中：案件正在审核中（当前实现中未使用）
+	CLAIM_STATUS_UNDER_REVIEW = "UNDER_REVIEW"
+	// CLAIM_STATUS_APPROVED 已批准：案件已通过审核，等待给付
+	CLAIM_STATUS_APPROVED = "APPROVED"
+	// CLAIM_STATUS_REJECTED 已拒绝：案件审核未通过
+	CLAIM_STATUS_REJECTED = "REJECTED"
+	// CLAIM_STATUS_PAID 已给付：理赔款已支付给受益人
+	CLAIM_STATUS_PAID = "PAID"
+	// CLAIM_STATUS_CANCELLED 已取消：案件在给付前被申请人或 operator 取消，终态
+	CLAIM_STATUS_CANCELLED = "CANCELLED"
+)
+
+// 轮次状态常量
+//
+// 状态转换流程：
+//
+//	OPEN -> SETTLED (通过 SettleRound 或 AdvanceRound 结算)
+//	OPEN -> SETTLED_ZERO (结算时无已批准给付，成员无需缴费)
+//	SETTLED -> CLOSED (通过 CloseRound 或 AdvanceRound 关闭)
+const (
+	// ROUND_STATUS_OPEN 开启：轮次已开启，可以结算案件
+	ROUND_STATUS_OPEN = "OPEN"
+	// ROUND_STATUS_SETTLED 已结算：轮次已结算，计算出人均分摊额，成员可以缴费
+	ROUND_STATUS_SETTLED = "SETTLED"
+	// ROUND_STATUS_SETTLED_ZERO 零给付结算：轮次内无已批准案件，不产生应缴，终态
+	ROUND_STATUS_SETTLED_ZERO = "SETTLED_ZERO"
+	// ROUND_STATUS_CLOSED 已关闭：轮次已关闭，未缴费的成员记录为欠费
+	ROUND_STATUS_CLOSED = "CLOSED"
+)
+
+// 计划状态常量
+//
+// 状态转换流程：
+//
+//	ACTIVE -> FINALIZED (通过 FinalizePlan 终结，终态)
+const (
+	// PLAN_STATUS_ACTIVE 运营中：计划已初始化（未写入计划状态时即为运营中）
+	PLAN_STATUS_ACTIVE = "ACTIVE"
+	// PLAN_STATUS_FINALIZED 已终结：资金池余额已分配给活跃成员，不再接受任何写操作
+	PLAN_STATUS_FINALIZED = "FINALIZED"
+)
+
+// 审核决策常量
+//
+// 用于 ReviewClaim / BatchReviewClaims 函数，表示 operator 对案件的审核决定
+const (
+	// DECISION_APPROVE 批准：案件通过审核，可以给付
+	DECISION_APPROVE = "APPROVE"
+	// DECISION_REJECT 拒绝：案件未通过审核
+	DECISION_REJECT = "REJECT"
+)
+
+// ROUND_PERIOD_TOLERANCE_BP 轮次周期长度允许偏差，单位 bp（万分比）
+//
+// 例如结算周期为30天，允许偏差10%即±3天，可覆盖自然月长度差异（28~31天）。
+const ROUND_PERIOD_TOLERANCE_BP = 1000
+
+// validateRoundPeriod 校验新轮次的周期是否合法
+//
+// 规则：
+//  1. period_end > period_start
+//  2. 周期长度与计划 settlement_period 的偏差不超过 ROUND_PERIOD_TOLERANCE_BP
+//  3. 不与上一轮次重叠：period_start >= prevPeriodEnd（prevPeriodEnd 为 0 表示无上一轮次）
+//
+// 返回：
+//   - true: 周期合法
+//   - false: 周期不合法（调用方应返回 ERROR_INVALID_PARAMS）
+func validateRoundPeriod(periodStart, periodEnd, settlementPeriod, prevPeriodEnd uint64) bool {
+	if periodStart == 0 || periodEnd <= periodStart || settlementPeriod == 0 {
+		return false
+	}
+
+	length := periodEnd - periodStart
+	// 拆分计算 settlementPeriod * bp / 10000，避免乘法溢出
+	tolerance := settlementPeriod / 10000 * ROUND_PERIOD_TOLERANCE_BP
+	tolerance += settlementPeriod % 10000 * ROUND_PERIOD_TOLERANCE_BP / 10000
+	var diff uint64
+	if length > settlementPeriod {
+		diff = length - settlementPeriod
+	} else {
+		diff = settlementPeriod - length
+	}
+	if diff > tolerance {
+		return false
+	}
+
+	if prevPeriodEnd > 0 && periodStart < prevPeriodEnd {
+		return false
+	}
+	return true
+}
+
+// DEFAULT_MONTHLY_CAP_PER_MEMBER 未配置 monthly_cap_per_member 时的单成员月度分摊上限
+const DEFAULT_MONTHLY_CAP_PER_MEMBER = 1000000
+
+// effectiveMonthlyCap 计算成员生效的月度分摊上限
+//
+// 参数：
+//   - planCap: 计划默认上限（monthly_cap_per_member）
+//   - memberCap: 成员个人上限覆盖（member_cap_{address}），0 表示无覆盖
+//
+// 返回：存在个人覆盖时返回覆盖值，否则返回计划默认上限
+func effectiveMonthlyCap(planCap, memberCap uint64) uint64 {
+	if memberCap > 0 {
+		return memberCap
+	}
+	return planCap
+}
+
+// 人均分摊取整方式
+const (
+	// ROUNDING_MODE_UP 向上取整（默认）：成员多缴，产生取整盈余
+	ROUNDING_MODE_UP = "UP"
+	// ROUNDING_MODE_NEAREST 四舍五入（半数向上）
+	ROUNDING_MODE_NEAREST = "NEAREST"
+	// ROUNDING_MODE_DOWN 向下取整：成员少缴，产生取整缺口
+	ROUNDING_MODE_DOWN = "DOWN"
+
+	// MAX_ROUNDING_DECIMALS 取整位数上限：人均分摊取整到 10^decimals 的整数倍
+	MAX_ROUNDING_DECIMALS = 6
+)
+
+// roundingConfig 人均分摊取整配置（见 SetRoundingMode）
+type roundingConfig struct {
+	// Mode 取整方向，见 ROUNDING_MODE_*
+	Mode string
+	// Decimals 取整位数：人均分摊为 10^Decimals 的整数倍（金额最小单位为 10^0）
+	Decimals uint64
+	// CarryForward 为 true 时，累计的取整余额在下一次结算中抵扣（盈余）或补收（缺口）；
+	// 为 false 时只记录，供 operator 退还或核销
+	CarryForward bool
+}
+
+// defaultRounding 未配置时的取整方式，与引入取整配置前的行为一致
+var defaultRounding = roundingConfig{Mode: ROUNDING_MODE_UP}
+
+// validRoundingMode 取整方向是否有效
+func validRoundingMode(mode string) bool {
+	return mode == ROUNDING_MODE_UP || mode == ROUNDING_MODE_NEAREST || mode == ROUNDING_MODE_DOWN
+}
+
+// roundPerCapita 计算 amount * TIER_MULTIPLIER_BASE_BP / totalWeightBP，并按取整方向
+// 取整到 10^decimals 的整数倍；totalWeightBP 为 0 时返回 0
+//
+// 128位中间结果计算，避免大额应收乘以系数基准时溢出；分摊基数或人均分摊超过 uint64 时 ok=false
+func roundPerCapita(amount, totalWeightBP uint64, mode string, decimals uint64) (perCapita uint64, ok bool) {
+	if totalWeightBP == 0 {
+		return 0, true
+	}
+	unit := uint64(1)
+	for i := uint64(0); i < decimals; i++ {
+		unit *= 10
+	}
+	hi, divisor := bits.Mul64(totalWeightBP, unit)
+	if hi != 0 {
+		return 0, false
+	}
+	hi, lo := bits.Mul64(amount, TIER_MULTIPLIER_BASE_BP)
+	if hi >= divisor {
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, divisor)
+	roundUp := false
+	switch mode {
+	case ROUNDING_MODE_DOWN:
+	case ROUNDING_MODE_NEAREST:
+		roundUp = r >= divisor-r
+	default:
+		roundUp = r > 0
+	}
+	if roundUp {
+		if q, ok = addChecked(q, 1); !ok {
+			return 0, false
+		}
+	}
+	hi, perCapita = bits.Mul64(q, unit)
+	return perCapita, hi == 0
+}
+
+// roundSettlement 按取整配置计算的轮次结算结果
+type roundSettlement struct {
+	TotalWithFee    uint64
+	TotalServiceFee uint64
+	PerCapita       uint64
+	// Target 本轮应收总额：total_with_fee 扣除结转盈余（或加上结转缺口）
+	Target uint64
+	// CarryApplied 本轮使用的结转余额：正数为抵扣的盈余，负数为补收的缺口
+	CarryApplied int64
+	// Surplus 本轮取整余额：per_capita * total_weight_bp / 10000 - Target，负数为少收
+	Surplus int64
+}
+
+// computeRoundedSettlement 按取整配置计算轮次结算结果
+//
+// 计算公式（默认取整：向上取整到最小单位）：
+//
+//	total_with_fee = total_approved_payout * (10000 + service_fee_bp) / 10000
+//	per_capita = ceil(total_with_fee * TIER_MULTIPLIER_BASE_BP / total_weight_bp)
+//
+// totalWeightBP 为全部活跃成员档位系数之和（见 totalMemberWeight），
+// 未分档计划中等于 member_count * TIER_MULTIPLIER_BASE_BP，即按人头均摊。
+// per_capita 为基准档（1倍系数）成员的应缴额，成员实际应缴见 memberDue。
+//
+// 参数：
+//   - rounding: 取整配置
+//   - carry: 结转的取整余额（正数为盈余，负数为缺口），未启用结转时传 0
+//
+// 规则：
+//   - 无应收（total_with_fee = 0）或无人分摊（totalWeightBP = 0）时人均为 0，不使用结转
+//   - 盈余抵扣不超过 total_with_fee，未用完的部分继续结转
+//   - 结算后的结转余额 = carry - CarryApplied + Surplus（见 nextRoundingCarry）
+//
+// 取整余额按档位系数之和计算；档位成员应缴的再次向上取整（memberDue）不计入。
+// 应收总额、人均分摊或实收总额超过 uint64，或取整余额的绝对值超过 int64 时 ok=false，调用方应拒绝结算。
+func computeRoundedSettlement(totalApprovedPayout, serviceFeeBP, totalWeightBP uint64, rounding roundingConfig, carry int64) (s roundSettlement, ok bool) {
+	// 1. 含服务费的应收总额（128位中间结果）
+	hi, lo := bits.Mul64(totalApprovedPayout, 10000+serviceFeeBP)
+	if hi >= 10000 {
+		return roundSettlement{}, false
+	}
+	s.TotalWithFee, _ = bits.Div64(hi, lo, 10000)
+	s.TotalServiceFee = s.TotalWithFee - totalApprovedPayout
+	s.Target = s.TotalWithFee
+	if s.TotalWithFee == 0 || totalWeightBP == 0 {
+		return s, true
+	}
+
+	// 2. 结转盈余抵扣、结转缺口补收
+	if carry > 0 {
+		applied := uint64(carry)
+		if applied > s.Target {
+			applied = s.Target
+		}
+		s.Target -= applied
+		s.CarryApplied = int64(applied)
+	} else if carry < 0 {
+		if s.Target, ok = addChecked(s.Target, uint64(-carry)); !ok {
+			return roundSettlement{}, false
+		}
+		s.CarryApplied = carry
+	}
+
+	// 3. 人均分摊与实收总额，实收超过 uint64 时同样拒绝
+	if s.PerCapita, ok = roundPerCapita(s.Target, totalWeightBP, rounding.Mode, rounding.Decimals); !ok {
+		return roundSettlement{}, false
+	}
+	hi, lo = bits.Mul64(s.PerCapita, totalWeightBP)
+	if hi >= TIER_MULTIPLIER_BASE_BP {
+		return roundSettlement{}, false
+	}
+	collected, _ := bits.Div64(hi, lo, TIER_MULTIPLIER_BASE_BP)
+	if s.Surplus, ok = signedDiff(collected, s.Target); !ok {
+		return roundSettlement{}, false
+	}
+	return s, true
+}
+
+// nextRoundingCarry 结算后的累计取整余额，超过 int64 时 ok=false
+//
+// CarryApplied 不超过 carry 本身（未结转时为 0），carry - CarryApplied 不会溢出
+func nextRoundingCarry(carry int64, s roundSettlement) (next int64, ok bool) {
+	return addInt64Checked(carry-s.CarryApplied, s.Surplus)
+}
+
+// signedDiff 返回 a-b 的有符号差，绝对值超过 math.MaxInt64 时 ok=false
+func signedDiff(a, b uint64) (diff int64, ok bool) {
+	if a >= b {
+		if a-b > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(a - b), true
+	}
+	if b-a > math.MaxInt64 {
+		return 0, false
+	}
+	return -int64(b - a), true
+}
+
+// addInt64Checked 返回 a+b，超过 int64 时 ok=false
+func addInt64Checked(a, b int64) (sum int64, ok bool) {
+	sum = a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// splitRoundingSurplus 将有符号的取整余额拆分为盈余与缺口（用于事件字段）
+func splitRoundingSurplus(surplus int64) (over, short uint64) {
+	if surplus >= 0 {
+		return uint64(surplus), 0
+	}
+	return 0, uint64(-surplus)
+}
+
+// settledRoundStatus 返回轮次结算后的状态
+//
+// 无已批准给付时人均分摊为 0，轮次直接进入 SETTLED_ZERO：不开放缴费、不产生欠费，
+// 也不会被 AdvanceRound 关闭。
+func settledRoundStatus(totalApprovedPayout uint64) string {
+	if totalApprovedPayout == 0 {
+		return ROUND_STATUS_SETTLED_ZERO
+	}
+	return ROUND_STATUS_SETTLED
+}
+
+// roundAcceptsContributions 仅 SETTLED 轮次接受缴费（含线下缴费登记）
+func roundAcceptsContributions(roundStatus string) bool {
+	return roundStatus == ROUND_STATUS_SETTLED
+}
+
+// roundSettlementInputs 轮次结算读取的链上数据（见 loadRoundSettlementInputs）
+type roundSettlementInputs struct {
+	// ClaimIDs 轮次案件索引 round_claims:{round_id}
+	ClaimIDs []string
+	// ApprovedAmount 读取案件批准金额，读取不到的案件不计入
+	ApprovedAmount func(claimID string) (approvedAmount uint64, found bool)
+	// ServiceFeeBP 计划配置的 service_fee_bp
+	ServiceFeeBP uint64
+	// FeeMode / MinFeeBP / MaxFeeBP 服务费调整配置（见 SetFeeAdjustment）
+	FeeMode            string
+	MinFeeBP, MaxFeeBP uint64
+	// CumulativePaid / CumulativeCollected 累计给付与累计分摊，赔付率联动模式使用
+	CumulativePaid, CumulativeCollected uint64
+	// MemberCount / TotalWeight 轮次分摊基数（见 loadRoundSettlementBase）
+	MemberCount, TotalWeight uint64
+	// Rounding 取整配置
+	Rounding roundingConfig
+	// StoredCarry 结算前的累计取整余额
+	StoredCarry int64
+}
+
+// roundSettlementPlan 轮次结算计算结果
+type roundSettlementPlan struct {
+	TotalApprovedPayout uint64
+	// Status 结算后的轮次状态（SETTLED / SETTLED_ZERO）
+	Status         string
+	EffectiveFeeBP uint64
+	ClaimsRatioBP  uint64
+	Settlement     roundSettlement
+	// Carry 结算后的累计取整余额，与 StoredCarry 不同时由结算写入 rounding_carry
+	Carry int64
+}
+
+// planRoundSettlement 计算轮次结算
+//
+// SettleRound / AdvanceRound 与 PreviewSettlement 共用同一计算，保证预览与实际结算一致；
+// 函数本身不读写状态，结算按结果写入轮次记录与累计取整余额，预览只返回结果。
+//
+// 启用结转时，累计取整余额参与本轮应收总额的计算；否则只记录，供 operator 退还或核销。
+// 结算金额超过 uint64、取整余额或累计取整余额超过 int64 时 ok=false（见 computeRoundedSettlement），
+// 调用方返回 ERROR_INVALID_STATE。
+func planRoundSettlement(in roundSettlementInputs) (p roundSettlementPlan, ok bool) {
+	p.TotalApprovedPayout = sumApprovedPayout(in.ClaimIDs, in.ApprovedAmount)
+	p.Status = settledRoundStatus(p.TotalApprovedPayout)
+	p.EffectiveFeeBP, p.ClaimsRatioBP = effectiveServiceFeeBP(in.FeeMode, in.ServiceFeeBP, in.MinFeeBP, in.MaxFeeBP, in.CumulativePaid, in.CumulativeCollected)
+
+	carryIn := int64(0)
+	if in.Rounding.CarryForward {
+		carryIn = in.StoredCarry
+	}
+	if p.Settlement, ok = computeRoundedSettlement(p.TotalApprovedPayout, p.EffectiveFeeBP, in.TotalWeight, in.Rounding, carryIn); !ok {
+		return roundSettlementPlan{}, false
+	}
+	if p.Carry, ok = nextRoundingCarry(in.StoredCarry, p.Settlement); !ok {
+		return roundSettlementPlan{}, false
+	}
+	return p, true
+}
+
+// settlementLacksMembers 有已批准给付但快照内没有成员可分摊时，SettleRound 拒绝结算
+func settlementLacksMembers(memberCount uint64, status string) bool {
+	return memberCount == 0 && status != ROUND_STATUS_SETTLED_ZERO
+}
+
+// settlementPreview 结算预览的附加信息（PreviewSettlement 读取）
+type settlementPreview struct {
+	// Now / PeriodEnd 当前时间与轮次结束时间
+	Now, PeriodEnd uint64
+	// MonthlyCap 计划的 monthly_cap_per_member
+	MonthlyCap uint64
+	// TierMultipliers 各档位生效系数（下标为档位编号）
+	TierMultipliers []uint64
+	// PoolBalance 资金池余额，HasPool 为 false 时未提供资金池，不检查覆盖率
+	PoolBalance uint64
+	HasPool     bool
+}
+
+// settlementTierDues 按档位列出本轮应缴额（per_capita * 档位系数，见 memberDue），
+// 任一档位应缴额溢出时 ok=false
+func settlementTierDues(perCapita uint64, multipliers []uint64) (items []interface{}, ok bool) {
+	items = make([]interface{}, 0, len(multipliers))
+	for tier, multiplier := range multipliers {
+		due, ok := memberDue(perCapita, multiplier)
+		if !ok {
+			return nil, false
+		}
+		items = append(items, map[string]interface{}{
+			"tier":               uint64(tier),
+			"tier_multiplier_bp": multiplier,
+			"due":                due,
+		})
+	}
+	return items, true
+}
+
+// poolCoveragePercent 资金池余额占待给付总额的百分比（向下取整，上限 100）
+func poolCoveragePercent(poolBalance, projectedPayout uint64) uint64 {
+	if projectedPayout == 0 || poolBalance >= projectedPayout {
+		return 100
+	}
+	// 128位中间结果计算 balance * 100 / payout，避免大额余额乘法溢出
+	hi, lo := bits.Mul64(poolBalance, 100)
+	percent, _ := bits.Div64(hi, lo, projectedPayout)
+	return percent
+}
+
+// settlementWarnings 结算预览的风险提示
+//
+// 提示不影响结算结果，供 operator 在调用 SettleRound 前安排资金或推迟结算：
+//   - 快照内没有成员且有已批准给付：SettleRound 将返回 ERROR_INVALID_STATE
+//   - 轮次尚未到期：到期前仍可能有案件审核归入本轮
+//   - 资金池余额不足以覆盖本轮已批准给付
+//   - 最高档位应缴额超过计划的月度分摊上限，成员无法在单月内缴清
+func settlementWarnings(in roundSettlementInputs, p roundSettlementPlan, ctx settlementPreview) []string {
+	warnings := []string{}
+	if settlementLacksMembers(in.MemberCount, p.Status) {
+		warnings = append(warnings, "round snapshot has no active members; settlement would be rejected")
+	}
+	if ctx.Now < ctx.PeriodEnd {
+		warnings = append(warnings, "round period has not ended; more approved claims may still join this round")
+	}
+	if ctx.HasPool && ctx.PoolBalance < p.TotalApprovedPayout {
+		warnings = append(warnings, "pool balance covers only "+framework.Uint64ToString(poolCoveragePercent(ctx.PoolBalance, p.TotalApprovedPayout))+"% of projected payouts")
+	}
+	var maxDue uint64
+	for _, multiplier := range ctx.TierMultipliers {
+		// 应缴额溢出的档位由 settlementTierDues 拒绝，此处不再提示
+		if due, ok := memberDue(p.Settlement.PerCapita, multiplier); ok && due > maxDue {
+			maxDue = due
+		}
+	}
+	if ctx.MonthlyCap > 0 && maxDue > ctx.MonthlyCap {
+		warnings = append(warnings, "highest tier due "+framework.Uint64ToString(maxDue)+" exceeds monthly cap per member "+framework.Uint64ToString(ctx.MonthlyCap))
+	}
+	return warnings
+}
+
+// nextRoundPeriod 根据上一轮次结束时间推导下一轮次周期
+//
+// 下一轮次紧接上一轮次（period_start = prevPeriodEnd），长度为 settlementPeriod。
+// 若 now 已越过多个周期（operator 长时间未推进），跳过空档周期，
+// 使新轮次覆盖 now 所在的周期，且起点仍与 settlementPeriod 对齐。
+func nextRoundPeriod(prevPeriodEnd, settlementPeriod, now uint64) (periodStart, periodEnd uint64) {
+	periodStart = prevPeriodEnd
+	if settlementPeriod > 0 && now > prevPeriodEnd {
+		periodStart += (now - prevPeriodEnd) / settlementPeriod * settlementPeriod
+	}
+	return periodStart, periodStart + settlementPeriod
+}
+
+// TIER_MULTIPLIER_BASE_BP 档位系数基准，单位 bp（10000 = 1倍）
+const TIER_MULTIPLIER_BASE_BP = 10000
+
+// MAX_TIERS 档位数量上限，档位编号为 0 ~ MAX_TIERS-1
+const MAX_TIERS = 8
+
+// tierMultiplier 返回档位生效的分摊系数
+//
+// 参数：
+//   - configuredBP: operator 配置的档位系数（tier_multiplier_{tier}），0 表示未配置
+//
+// 返回：未配置时为 TIER_MULTIPLIER_BASE_BP（1倍），否则为配置值
+func tierMultiplier(configuredBP uint64) uint64 {
+	if configuredBP == 0 {
+		return TIER_MULTIPLIER_BASE_BP
+	}
+	return configuredBP
+}
+
+// totalMemberWeight 计算全部活跃成员的档位系数之和
+//
+// 参数：
+//   - tierCounts: 各档位活跃成员数（下标为档位编号）
+//   - multipliers: 各档位生效系数（下标为档位编号）
+//   - memberCount: 活跃成员总数
+//
+// 未计入任何档位的活跃成员（分档功能启用前激活的成员）按基准系数计算。
+func totalMemberWeight(tierCounts, multipliers []uint64, memberCount uint64) uint64 {
+	var weight, counted uint64
+	for tier, count := range tierCounts {
+		weight += count * multipliers[tier]
+		counted += count
+	}
+	if memberCount > counted {
+		weight += (memberCount - counted) * TIER_MULTIPLIER_BASE_BP
+	}
+	return weight
+}
+
+// memberDue 计算成员本轮应缴额：ceil(per_capita * multiplier / 10000)
+//
+// 128位中间结果计算，避免大额人均分摊与高档位系数相乘溢出；应缴额超过 uint64 时 ok=false
+func memberDue(perCapita, multiplierBP uint64) (due uint64, ok bool) {
+	hi, lo := bits.Mul64(perCapita, multiplierBP)
+	lo, carry := bits.Add64(lo, TIER_MULTIPLIER_BASE_BP-1, 0)
+	hi += carry
+	if hi >= TIER_MULTIPLIER_BASE_BP {
+		return 0, false
+	}
+	due, _ = bits.Div64(hi, lo, TIER_MULTIPLIER_BASE_BP)
+	return due, true
+}
+
+// currentRoundRecord 解析当前轮次：先取 current_round_id，再经 loadRound 读取轮次记录
+//
+// 尚未开启任何轮次（current_round_id 为空）或轮次记录不存在时返回 ok = false
+func currentRoundRecord(currentRoundData []byte, loadRound func(roundID string) []byte) (roundID string, roundData []byte, ok bool) {
+	roundID = string(framework.TrimNull(currentRoundData))
+	if roundID == "" {
+		return "", nil, false
+	}
+	roundData = loadRound(roundID)
+	if len(roundData) == 0 {
+		return roundID, nil, false
+	}
+	return roundID, roundData, true
+}
+
+// memberEligibleForRound 判断成员是否参与轮次分摊
+//
+// 轮次开启时快照活跃成员（记录当时的成员激活序号 snapshotSeq），
+// 激活序号大于快照序号的成员在轮次中途加入，不承担该轮已发生的案件。
+//
+// 兼容规则：
+//   - 引入快照前开启的轮次（hasSnapshot=false）：所有活跃成员均参与
+//   - 引入激活序号前激活的成员（activationSeq=0）：视为早于所有快照
+func memberEligibleForRound(activationSeq, snapshotSeq uint64, hasSnapshot bool) bool {
+	if !hasSnapshot {
+		return true
+	}
+	return activationSeq <= snapshotSeq
+}
+
+// ROUND_CLAIM_ID_SIZE 轮次案件索引中每个案件ID的固定长度（与 claim 编码中的 claimID 字段一致）
+const ROUND_CLAIM_ID_SIZE = 32
+
+// MAX_ROUND_CLAIMS 单轮次案件索引容量（受 GetState 4096 字节读取缓冲区限制）
+const MAX_ROUND_CLAIMS = 4096 / ROUND_CLAIM_ID_SIZE
+
+// decodeRoundClaims 解码轮次案件索引 round_claims:{round_id}
+//
+// 编码格式：claimID(32) * n，不足32字节部分用 0x00 填充。
+// 读取缓冲区尾部的 0x00 填充（或被裁剪的尾部）均可正确处理，遇到空条目即停止。
+func decodeRoundClaims(data []byte) []string {
+	var claims []string
+	for offset := 0; offset < len(data); offset += ROUND_CLAIM_ID_SIZE {
+		end := offset + ROUND_CLAIM_ID_SIZE
+		if end > len(data) {
+			end = len(data)
+		}
+		claimID := string(framework.TrimNull(data[offset:end]))
+		if claimID == "" {
+			break
+		}
+		claims = append(claims, claimID)
+	}
+	return claims
+}
+
+// appendRoundClaim 将案件ID追加到轮次案件索引
+//
+// 返回：
+//   - 新的索引编码
+//   - 追加后的案件数
+//   - ok: false 表示案件ID无效、已在索引中或索引已满
+func appendRoundClaim(data []byte, claimID string) (index []byte, count int, ok bool) {
+	if claimID == "" || len(claimID) > ROUND_CLAIM_ID_SIZE {
+		return nil, 0, false
+	}
+	claims := decodeRoundClaims(data)
+	if len(claims) >= MAX_ROUND_CLAIMS {
+		return nil, len(claims), false
+	}
+	for _, c := range claims {
+		if c == claimID {
+			return nil, len(claims), false
+		}
+	}
+	claims = append(claims, claimID)
+	index = make([]byte, len(claims)*ROUND_CLAIM_ID_SIZE)
+	for i, c := range claims {
+		copy(index[i*ROUND_CLAIM_ID_SIZE:], c)
+	}
+	return index, len(claims), true
+}
+
+// MEMBER_ADDRESS_SIZE 成员索引中每个地址的长度
+const MEMBER_ADDRESS_SIZE = 20
+
+// MEMBER_INDEX_PAGE_SIZE 成员索引每页的地址数（受 GetState 4096 字节读取缓冲区限制）
+const MEMBER_INDEX_PAGE_SIZE = 4096 / MEMBER_ADDRESS_SIZE
+
+// memberIndexPageOf 返回成员索引中第 position 个条目（从0开始）所在的页号与页内序号
+func memberIndexPageOf(position uint64) (page, offset uint64) {
+	return position / MEMBER_INDEX_PAGE_SIZE, position % MEMBER_INDEX_PAGE_SIZE
+}
+
+// memberIndexPageEntries 返回成员总数为 total 时第 page 页的条目数
+func memberIndexPageEntries(total, page uint64) int {
+	start := page * MEMBER_INDEX_PAGE_SIZE
+	if total <= start {
+		return 0
+	}
+	if total-start > MEMBER_INDEX_PAGE_SIZE {
+		return MEMBER_INDEX_PAGE_SIZE
+	}
+	return int(total - start)
+}
+
+// decodeMemberIndexPage 解码成员索引分页 members_all_{page}
+//
+// 编码格式：address(20) * n。地址可能以 0x00 结尾，不能按空条目判断结束，
+// 条目数由 members_all_count 推算；被裁剪的尾部按 0x00 补齐。
+func decodeMemberIndexPage(data []byte, entries int) [][]byte {
+	buf := make([]byte, entries*MEMBER_ADDRESS_SIZE)
+	copy(buf, data)
+	addrs := make([][]byte, entries)
+	for i := range addrs {
+		addrs[i] = buf[i*MEMBER_ADDRESS_SIZE : (i+1)*MEMBER_ADDRESS_SIZE]
+	}
+	return addrs
+}
+
+// appendMemberIndexPage 将地址追加到包含 entries 个条目的分页
+//
+// 返回：新的分页编码；地址长度不合法或分页已满时 ok=false
+func appendMemberIndexPage(data []byte, entries int, addr []byte) (page []byte, ok bool) {
+	if len(addr) != MEMBER_ADDRESS_SIZE || entries >= MEMBER_INDEX_PAGE_SIZE {
+		return nil, false
+	}
+	page = make([]byte, (entries+1)*MEMBER_ADDRESS_SIZE)
+	copy(page, data[:min(len(data), entries*MEMBER_ADDRESS_SIZE)])
+	copy(page[entries*MEMBER_ADDRESS_SIZE:], addr)
+	return page, true
+}
+
+// setMemberIndexEntry 覆盖包含 entries 个条目的分页中第 offset 个地址
+func setMemberIndexEntry(data []byte, entries, offset int, addr []byte) []byte {
+	page := make([]byte, entries*MEMBER_ADDRESS_SIZE)
+	copy(page, data)
+	copy(page[offset*MEMBER_ADDRESS_SIZE:], addr)
+	return page
+}
+
+// removeFromMemberSet 从分页存储的成员集合中移除第 position 个条目（从0开始）
+//
+// 采用交换删除：将最后一个条目移动到被删除位置，再截断最后一页，集合内顺序不保证。
+// pages 为涉及的分页（键为页号），调用方需预先载入 position 与最后一个条目所在的分页，
+// 本函数原地修改 pages，同一分页只需写入一次。
+//
+// 返回：被移动到 position 的地址（被删除的就是最后一个条目时为 nil）
+func removeFromMemberSet(pages map[uint64][]byte, total, position uint64) (moved []byte) {
+	if position >= total {
+		return nil
+	}
+	lastPage, lastOffset := memberIndexPageOf(total - 1)
+	if position != total-1 {
+		page, offset := memberIndexPageOf(position)
+		last := decodeMemberIndexPage(pages[lastPage], memberIndexPageEntries(total, lastPage))[lastOffset]
+		moved = append([]byte(nil), last...)
+		pages[page] = setMemberIndexEntry(pages[page], memberIndexPageEntries(total, page), int(offset), moved)
+	}
+	truncated := make([]byte, lastOffset*MEMBER_ADDRESS_SIZE)
+	copy(truncated, pages[lastPage])
+	pages[lastPage] = truncated
+	return moved
+}
+
+// MEMBER_LIST_DEFAULT_LIMIT ListMembers 未指定 limit 时每页返回的成员数
+const MEMBER_LIST_DEFAULT_LIMIT = 50
+
+// MEMBER_LIST_MAX_LIMIT ListMembers 每页返回的成员数上限
+const MEMBER_LIST_MAX_LIMIT = 100
+
+// memberListLimit 将请求的 limit 规范到 [1, MEMBER_LIST_MAX_LIMIT]，0 使用默认值
+func memberListLimit(requested uint64) uint64 {
+	if requested == 0 {
+		return MEMBER_LIST_DEFAULT_LIMIT
+	}
+	if requested > MEMBER_LIST_MAX_LIMIT {
+		return MEMBER_LIST_MAX_LIMIT
+	}
+	return requested
+}
+
+// CLAIM_LIST_DEFAULT_LIMIT ListClaims 未指定 limit 时每页读取的案件数
+const CLAIM_LIST_DEFAULT_LIMIT = 20
+
+// CLAIM_LIST_MAX_LIMIT ListClaims 每页读取的案件数上限（不超过 framework.MAX_STATE_QUERY_LIMIT）
+const CLAIM_LIST_MAX_LIMIT = 50
+
+// claimListLimit 将请求的 limit 规范到 [1, CLAIM_LIST_MAX_LIMIT]，0 使用默认值
+func claimListLimit(requested uint64) uint32 {
+	if requested == 0 {
+		return CLAIM_LIST_DEFAULT_LIMIT
+	}
+	if requested > CLAIM_LIST_MAX_LIMIT {
+		return CLAIM_LIST_MAX_LIMIT
+	}
+	return uint32(requested)
+}
+
+// memberListEntry ListMembers 返回的成员条目
+type memberListEntry struct {
+	Address []byte
+	Status  string
+}
+
+// filterMemberList 按状态过滤成员并分页
+//
+// 参数：
+//   - addrs: 成员地址（成员索引或活跃集合中的顺序）
+//   - statusOf: 读取成员当前状态
+//   - status: 状态过滤，空字符串表示不过滤（此时只读取本页成员的状态）
+//   - offset / limit: 过滤后结果中的起始位置与条数
+//
+// 返回：本页条目与过滤后的总数
+func filterMemberList(addrs [][]byte, statusOf func(addr []byte) string, status string, offset, limit uint64) (entries []memberListEntry, total uint64) {
+	entries = make([]memberListEntry, 0, limit)
+	for _, addr := range addrs {
+		memberStatus := ""
+		if status != "" {
+			if memberStatus = statusOf(addr); memberStatus != status {
+				continue
+			}
+		}
+		if total >= offset && uint64(len(entries)) < limit {
+			if memberStatus == "" {
+				memberStatus = statusOf(addr)
+			}
+			entries = append(entries, memberListEntry{Address: addr, Status: memberStatus})
+		}
+		total++
+	}
+	return entries, total
+}
+
+// reconcileActiveCount 按成员记录状态重新计算活跃成员数
+//
+// 返回：
+//   - actual: 状态为 ACTIVE 的成员数
+//   - corrected: actual 与已记录的 stored 不一致
+func reconcileActiveCount(stored uint64, statuses []string) (actual uint64, corrected bool) {
+	for _, status := range statuses {
+		if status == MEMBER_STATUS_ACTIVE {
+			actual++
+		}
+	}
+	return actual, actual != stored
+}
+
+// 服务费模式常量
+const (
+	// FEE_MODE_FIXED 固定费率：使用计划配置的 service_fee_bp
+	FEE_MODE_FIXED = "FIXED"
+	// FEE_MODE_CLAIMS_RATIO 赔付率联动：按历史赔付率在 [min_fee_bp, max_fee_bp] 区间内线性调整
+	FEE_MODE_CLAIMS_RATIO = "CLAIMS_RATIO"
+)
+
+// claimsRatioBP 计算历史赔付率（累计给付 / 累计分摊），单位 bp，上限 10000
+//
+// 累计分摊为 0（尚无历史）时返回 0。
+func claimsRatioBP(cumulativePaid, cumulativeCollected uint64) uint64 {
+	if cumulativeCollected == 0 {
+		return 0
+	}
+	if cumulativePaid >= cumulativeCollected {
+		return 10000
+	}
+	// 128位中间结果计算 paid * 10000 / collected，避免大额累计值乘法溢出
+	hi, lo := bits.Mul64(cumulativePaid, 10000)
+	ratio, _ := bits.Div64(hi, lo, cumulativeCollected)
+	return ratio
+}
+
+// effectiveServiceFeeBP 计算本轮生效的服务费率
+//
+// 规则：
+//   - FEE_MODE_FIXED：返回 fixedBP
+//   - FEE_MODE_CLAIMS_RATIO：fee = min + (max - min) * claims_ratio / 10000，
+//     赔付率越高，案件处理成本越高，服务费随之上浮；始终落在 [minBP, maxBP] 区间内。
+//     尚无历史分摊时使用 fixedBP 并截断到区间内。
+//
+// 返回：生效费率与参与计算的赔付率（FIXED 模式赔付率为 0）
+func effectiveServiceFeeBP(mode string, fixedBP, minBP, maxBP, cumulativePaid, cumulativeCollected uint64) (feeBP, ratioBP uint64) {
+	if mode != FEE_MODE_CLAIMS_RATIO || minBP > maxBP {
+		return fixedBP, 0
+	}
+	if cumulativeCollected == 0 {
+		if fixedBP < minBP {
+			return minBP, 0
+		}
+		if fixedBP > maxBP {
+			return maxBP, 0
+		}
+		return fixedBP, 0
+	}
+	ratioBP = claimsRatioBP(cumulativePaid, cumulativeCollected)
+	return minBP + (maxBP-minBP)*ratioBP/10000, ratioBP
+}
+
+// ================================================================================================
+// 批量审核
+// ================================================================================================
+
+// MAX_BATCH_REVIEW_SIZE 单次 BatchReviewClaims 最多包含的审核决定数
+const MAX_BATCH_REVIEW_SIZE = 32
+
+// 批量审核单项结果
+const (
+	// BATCH_REVIEW_APPLIED 已应用审核决定
+	BATCH_REVIEW_APPLIED = "APPLIED"
+	// BATCH_REVIEW_SKIPPED 已跳过（见 skip_reason）
+	BATCH_REVIEW_SKIPPED = "SKIPPED"
+)
+
+// 批量审核跳过原因
+const (
+	SKIP_REASON_NOT_FOUND         = "NOT_FOUND"
+	SKIP_REASON_NOT_SUBMITTED     = "NOT_SUBMITTED"
+	SKIP_REASON_INVALID_DECISION  = "INVALID_DECISION"
+	SKIP_REASON_DUPLICATE         = "DUPLICATE"
+	SKIP_REASON_ROUND_CLAIMS_FULL = "ROUND_CLAIMS_FULL"
+	SKIP_REASON_ANNUAL_LIMIT      = "ANNUAL_LIMIT_EXCEEDED"
+)
+
+// reviewDecision 批量审核中的单项审核决定
+type reviewDecision struct {
+	ClaimID        string
+	Decision       string
+	ApprovedAmount uint64
+	Reason         string
+}
+
+// batchReviewResult 批量审核单项结果
+type batchReviewResult struct {
+	ClaimID        string
+	Decision       string
+	Outcome        string
+	SkipReason     string
+	NewStatus      string
+	ApprovedAmount uint64
+	Reason         string
+}
+
+// claimLookup 读取案件当前状态与申请金额，found=false 表示案件不存在
+type claimLookup func(claimID string) (status string, requestedAmount uint64, found bool)
+
+// approvalReserver 批准前占用案件类别的年度额度，额度不足时返回 false
+type approvalReserver func(claimID string, approvedAmount uint64) bool
+
+// planBatchReview 计算批量审核每项的处理结果
+//
+// 规则：
+//   - 仅处理 SUBMITTED 状态的案件，其余（已审核、已给付等）跳过
+//   - 同一批次中重复出现的案件只处理第一次
+//   - APPROVE 的批准金额不超过申请金额，REJECT 的批准金额为 0
+//   - 批准的案件追加到轮次案件索引 roundClaims，索引已满时跳过
+//   - reserve 非 nil 时批准前占用类别年度额度，额度不足时跳过（SKIP_REASON_ANNUAL_LIMIT）
+//
+// 返回：逐项结果、追加后的轮次案件索引、索引中的案件数
+func planBatchReview(decisions []reviewDecision, lookup claimLookup, roundClaims []byte, reserve approvalReserver) (results []batchReviewResult, index []byte, count int) {
+	results = make([]batchReviewResult, 0, len(decisions))
+	index = roundClaims
+	count = len(decodeRoundClaims(roundClaims))
+	seen := make(map[string]bool, len(decisions))
+
+	for _, d := range decisions {
+		r := batchReviewResult{ClaimID: d.ClaimID, Decision: d.Decision, Outcome: BATCH_REVIEW_SKIPPED, Reason: d.Reason}
+		results = append(results, r)
+		last := &results[len(results)-1]
+
+		if d.ClaimID == "" || (d.Decision != DECISION_APPROVE && d.Decision != DECISION_REJECT) {
+			last.SkipReason = SKIP_REASON_INVALID_DECISION
+			continue
+		}
+		if seen[d.ClaimID] {
+			last.SkipReason = SKIP_REASON_DUPLICATE
+			continue
+		}
+		seen[d.ClaimID] = true
+
+		status, requestedAmount, found := lookup(d.ClaimID)
+		if !found {
+			last.SkipReason = SKIP_REASON_NOT_FOUND
+			continue
+		}
+		if status != CLAIM_STATUS_SUBMITTED {
+			last.SkipReason = SKIP_REASON_NOT_SUBMITTED
+			continue
+		}
+
+		if d.Decision == DECISION_REJECT {
+			last.NewStatus = CLAIM_STATUS_REJECTED
+		} else {
+			approvedAmount := d.ApprovedAmount
+			if approvedAmount > requestedAmount {
+				approvedAmount = requestedAmount
+			}
+			next, n, ok := appendRoundClaim(index, d.ClaimID)
+			if !ok {
+				last.SkipReason = SKIP_REASON_ROUND_CLAIMS_FULL
+				continue
+			}
+			if reserve != nil && !reserve(d.ClaimID, approvedAmount) {
+				last.SkipReason = SKIP_REASON_ANNUAL_LIMIT
+				continue
+			}
+			index, count = next, n
+			last.NewStatus = CLAIM_STATUS_APPROVED
+			last.ApprovedAmount = approvedAmount
+		}
+		last.Outcome = BATCH_REVIEW_APPLIED
+	}
+	return results, index, count
+}
+
+// parseReviewDecisions 解析 BatchReviewClaims 参数中的 decisions 数组
+//
+// 参数格式：{"decisions":[{"claim_id":"c1","decision":"APPROVE","approved_amount":100,"reason":"..."}, ...]}
+//
+// 字符串字段按 JSON 规则还原转义；approved_amount 须为非负整数（数字或数字字符串），超过 uint64 时拒绝而不是截断
+//
+// 返回：审核决定列表；decisions 缺失、格式错误或金额无效时 ok=false
+func parseReviewDecisions(raw string) (decisions []reviewDecision, ok bool) {
+	objects := framework.NewContractParams([]byte(raw)).ParseJSONObjectArray("decisions")
+	if objects == nil {
+		return nil, false
+	}
+	decisions = make([]reviewDecision, 0, len(objects))
+	for _, obj := range objects {
+		approvedAmount, err := obj.ParseAmount("approved_amount")
+		if err != nil {
+			return nil, false
+		}
+		decisions = append(decisions, reviewDecision{
+			ClaimID:        obj.ParseJSON("claim_id"),
+			Decision:       obj.ParseJSON("decision"),
+			ApprovedAmount: uint64(approvedAmount),
+			Reason:         obj.ParseJSON("reason"),
+		})
+	}
+	return decisions, true
+}
+
+// ================================================================================================
+// 线下缴费
+// ================================================================================================
+
+// 成员轮次应缴记录标志位（member_round_due 的 flags 字节）
+const (
+	// DUE_FLAG_OFFCHAIN 含线下缴费：已缴金额中有 operator 登记的线下（法币）缴费
+	DUE_FLAG_OFFCHAIN byte = 0x01
+	// DUE_FLAG_REVERSED 含冲正：曾有线下缴费被冲正
+	DUE_FLAG_REVERSED byte = 0x02
+)
+
+// 线下缴费对账状态
+//
+// 状态转换流程：
+//
+//	RECORDED -> VERIFIED (通过 ReconcileOffchain 核实到账)
+//	RECORDED/VERIFIED -> REVERSED (通过 ReconcileOffchain 冲正，重新打开应缴)
+const (
+	// OFFCHAIN_STATUS_RECORDED 已登记：operator 已登记，等待对账
+	OFFCHAIN_STATUS_RECORDED = "RECORDED"
+	// OFFCHAIN_STATUS_VERIFIED 已核实：对账确认资金已到账
+	OFFCHAIN_STATUS_VERIFIED = "VERIFIED"
+	// OFFCHAIN_STATUS_REVERSED 已冲正：资金未到账或被退回，登记金额已扣回
+	OFFCHAIN_STATUS_REVERSED = "REVERSED"
+)
+
+// 线下缴费登记/冲正拒绝原因
+const (
+	OFFCHAIN_REJECT_DUPLICATE_REFERENCE = "DUPLICATE_REFERENCE"
+	OFFCHAIN_REJECT_SETTLED             = "SETTLED"
+	OFFCHAIN_REJECT_INVALID_TRANSITION  = "INVALID_TRANSITION"
+	OFFCHAIN_REJECT_OVERFLOW            = "OVERFLOW"
+)
+
+// roundDue 成员轮次应缴记录（member_round_due_{address}:{round_id}）
+type roundDue struct {
+	DueAmount    uint64
+	PaidAmount   uint64 // 已缴总额（链上 + 线下）
+	OffchainPaid uint64 // 其中线下缴费金额
+	Settled      bool
+	Flags        byte
+}
+
+// onchainPaid 返回链上托管缴纳的金额
+func (d roundDue) onchainPaid() uint64 {
+	return d.PaidAmount - d.OffchainPaid
+}
+
+// addChecked 返回 a+b，溢出时 ok=false
+func addChecked(a, b uint64) (sum uint64, ok bool) {
+	sum, carry := bits.Add64(a, b, 0)
+	return sum, carry == 0
+}
+
+// subChecked 返回 a-b，不足时 ok=false
+func subChecked(a, b uint64) (diff uint64, ok bool) {
+	diff, borrow := bits.Sub64(a, b, 0)
+	return diff, borrow == 0
+}
+
+// applyContribution 将一笔缴费计入应缴记录
+//
+// offchain=true 时同时计入线下缴费金额并设置 DUE_FLAG_OFFCHAIN。
+//
+// 返回：更新后的记录；已结清返回 OFFCHAIN_REJECT_SETTLED，金额溢出返回 OFFCHAIN_REJECT_OVERFLOW
+func applyContribution(d roundDue, amount uint64, offchain bool) (roundDue, string) {
+	if d.Settled {
+		return d, OFFCHAIN_REJECT_SETTLED
+	}
+	paid, ok := addChecked(d.PaidAmount, amount)
+	if !ok {
+		return d, OFFCHAIN_REJECT_OVERFLOW
+	}
+	d.PaidAmount = paid
+	if offchain {
+		d.OffchainPaid += amount // OffchainPaid <= PaidAmount，不会溢出
+		d.Flags |= DUE_FLAG_OFFCHAIN
+	}
+	d.Settled = d.PaidAmount >= d.DueAmount
+	return d, ""
+}
+
+// planOffchainRecord 计算登记线下缴费后的应缴记录
+//
+// 同一 reference_hash（银行流水等凭证哈希）只能登记一次，referenceUsed=true 时拒绝。
+func planOffchainRecord(referenceUsed bool, d roundDue, amount uint64) (roundDue, string) {
+	if referenceUsed {
+		return d, OFFCHAIN_REJECT_DUPLICATE_REFERENCE
+	}
+	return applyContribution(d, amount, true)
+}
+
+// canReconcileOffchain 判断线下缴费能否从 status 迁移到 resolution
+func canReconcileOffchain(status, resolution string) bool {
+	switch resolution {
+	case OFFCHAIN_STATUS_VERIFIED:
+		return status == OFFCHAIN_STATUS_RECORDED
+	case OFFCHAIN_STATUS_REVERSED:
+		return status == OFFCHAIN_STATUS_RECORDED || status == OFFCHAIN_STATUS_VERIFIED
+	}
+	return false
+}
+
+// planOffchainReversal 计算冲正线下缴费后的应缴记录
+//
+// 冲正扣回已缴金额与线下缴费金额，并按剩余金额重新判断是否结清（重新打开应缴）。
+// 轮次已关闭（roundClosed=true）时，冲正新增的未缴金额计为欠费，通过 arrearsDelta 返回。
+//
+// 返回：更新后的记录、新增欠费、拒绝原因（状态不允许冲正或金额不足扣回）
+func planOffchainReversal(status string, d roundDue, amount uint64, roundClosed bool) (next roundDue, arrearsDelta uint64, reject string) {
+	if !canReconcileOffchain(status, OFFCHAIN_STATUS_REVERSED) {
+		return d, 0, OFFCHAIN_REJECT_INVALID_TRANSITION
+	}
+	paid, ok := subChecked(d.PaidAmount, amount)
+	if !ok {
+		return d, 0, OFFCHAIN_REJECT_OVERFLOW
+	}
+	offchainPaid, ok := subChecked(d.OffchainPaid, amount)
+	if !ok {
+		return d, 0, OFFCHAIN_REJECT_OVERFLOW
+	}
+
+	next = d
+	next.PaidAmount = paid
+	next.OffchainPaid = offchainPaid
+	next.Settled = paid >= d.DueAmount
+	next.Flags |= DUE_FLAG_REVERSED
+	if offchainPaid == 0 {
+		next.Flags &^= DUE_FLAG_OFFCHAIN
+	}
+	if roundClosed {
+		arrearsDelta = dueShortfall(next) - dueShortfall(d)
+	}
+	return next, arrearsDelta, ""
+}
+
+// dueShortfall 返回应缴记录的未缴金额
+func dueShortfall(d roundDue) uint64 {
+	if d.PaidAmount >= d.DueAmount {
+		return 0
+	}
+	return d.DueAmount - d.PaidAmount
+}
+
+// memberRoundShortfall 返回轮次关闭时成员尚未缴清的金额（CloseRound 计入欠费）
+//
+// 成员有应缴记录时按记录计算；从未缴费、没有记录（hasRecord=false）时，
+// 按档位计算的应缴额 due 全额计为未缴。
+func memberRoundShortfall(d roundDue, hasRecord bool, due uint64) uint64 {
+	if !hasRecord {
+		return due
+	}
+	return dueShortfall(d)
+}
+
+// EVENT_ROUND_CLOSED CloseRound 发出的轮次关闭事件（欠费成员数与新增欠费总额）
+const EVENT_ROUND_CLOSED = "MutualAidRoundClosed"
+
+func init() {
+	framework.RegisterEventSchema(EVENT_ROUND_CLOSED, "plan_id", "round_id", "delinquent_count", "arrears_added", "closed_at")
+}
+
+// 案件附件限制
+//
+// AttachEvidence 将附件追加到 EVIDENCE_INDEX 索引，分区为 案件ID + 调用者地址，
+// 每个调用者在每个案件下最多 MAX_EVIDENCE_PER_CLAIM 条，单条不超过 MAX_EVIDENCE_BYTES 字节。
+const (
+	EVIDENCE_INDEX         = "claim_evidence"
+	MAX_EVIDENCE_PER_CLAIM = 16
+	MAX_EVIDENCE_BYTES     = 256
+
+	// ATTACH_EVIDENCE_WRITE_BUDGET AttachEvidence 单次调用的状态写入字节上限
+	// （一条附件 + 分区计数，含状态ID）
+	ATTACH_EVIDENCE_WRITE_BUDGET = 1024
+)
+
+func init() {
+	framework.RegisterIndexQuota(EVIDENCE_INDEX, MAX_EVIDENCE_PER_CLAIM, MAX_EVIDENCE_BYTES)
+	framework.RegisterViewFunction("GetLimits", viewLimits)
+}
+
+// viewLimits GetLimits 的视图函数：已登记的索引配额、Multicall 限制与各导出函数的写入预算
+func viewLimits(params *framework.ContractParams) (interface{}, error) {
+	result := framework.Limits()
+	result["write_budgets"] = map[string]interface{}{
+		"AttachEvidence": uint64(ATTACH_EVIDENCE_WRITE_BUDGET),
+	}
+	return result, nil
+}
+
+// evidencePartition 附件索引分区键：计划ID + ":" + 案件ID + ":" + 调用者地址字节
+//
+// 案件ID只在计划内唯一，分区键带上计划ID，不同计划的同名案件各自计数
+func evidencePartition(planID, claimID string, caller []byte) []byte {
+	return append([]byte(planID+":"+claimID+":"), caller...)
+}
+
+// canAttachEvidence 附件仅可在案件审核结束前追加
+func canAttachEvidence(claimStatus string) bool {
+	return claimStatus == CLAIM_STATUS_SUBMITTED || claimStatus == CLAIM_STATUS_UNDER_REVIEW
+}
+
+// planInitialization Initialize 的计划初始化结果
+//
+// 返回值与 MutualAidPlanInitialized 事件由同一份字段构建，索引器仅凭事件即可还原完整的初始化结果
+type planInitialization struct {
+	PlanID              string
+	Name                string
+	TokenID             string
+	CoverageAmount      uint64
+	ServiceFeeBP        uint64
+	SettlementPeriod    uint64
+	WaitingPeriod       uint64
+	MinMembers          uint64
+	MonthlyCapPerMember uint64
+	// Operator Base58 格式的 operator 地址（即 Initialize 的调用者）
+	Operator string
+	// OperatorAdmin Base58 格式的 operator_admin 地址（可紧急轮换 operator，默认为 Initialize 的调用者）
+	OperatorAdmin string
+	// InitializedAt 初始化时间戳
+	InitializedAt uint64
+}
+
+// 可轮换角色
+//
+// 两个角色按计划登记（见 usePlan）：plan:{plan_id}:operator 的持有者沿用计划命名空间下的 STATE_OPERATOR 状态，
+// 轮换协议见 framework/role_rotation.go：
+// 当前 operator 调用 RotateRoleKey 发起轮换，新地址在接受期限内调用 AcceptRoleKey 完成，
+// 待接受期间新旧 operator 均可执行管理操作；operator 密钥泄露时由 operator_admin 调用 EmergencyRotateRoleKey 直接替换。
+const (
+	// ROLE_OPERATOR 运营方角色（参数中的角色名称，登记名为 plan:{plan_id}:operator）
+	ROLE_OPERATOR = "operator"
+	// ROLE_OPERATOR_ADMIN 可紧急轮换 operator 的管理角色（登记名为 plan:{plan_id}:operator_admin）
+	ROLE_OPERATOR_ADMIN = "operator_admin"
+)
+
+// isPlanRole 角色轮换导出函数接受的角色名称
+func isPlanRole(role string) bool {
+	return role == ROLE_OPERATOR || role == ROLE_OPERATOR_ADMIN
+}
+
+// EVENT_PLAN_INITIALIZED Initialize 发出的计划初始化事件
+const EVENT_PLAN_INITIALIZED = "MutualAidPlanInitialized"
+
+func init() {
+	framework.RegisterEventSchema(EVENT_PLAN_INITIALIZED, "plan_id", "name", "token_id", "coverage_amount", "service_fee_bp", "settlement_period",
+		"waiting_period", "min_members", "monthly_cap_per_member", "operator", "operator_admin", "member_count_active", "initialized_at")
+}
+
+// fields Initialize 返回值与事件共用的字段
+func (p planInitialization) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"plan_id":                p.PlanID,
+		"name":                   p.Name,
+		"token_id":               p.TokenID,
+		"coverage_amount":        p.CoverageAmount,
+		"service_fee_bp":         p.ServiceFeeBP,
+		"settlement_period":      p.SettlementPeriod,
+		"waiting_period":         p.WaitingPeriod,
+		"min_members":            p.MinMembers,
+		"monthly_cap_per_member": p.MonthlyCapPerMember,
+		"operator":               p.Operator,
+		"operator_admin":         p.OperatorAdmin,
+		"member_count_active":    uint64(0),
+		"initialized_at":         p.InitializedAt,
+	}
+}
+
+// event 构建 MutualAidPlanInitialized 事件
+func (p planInitialization) event() *framework.Event {
+	event := framework.NewEvent(EVENT_PLAN_INITIALIZED)
+	for key, value := range p.fields() {
+		event.Data[key] = value
+	}
+	return event
+}
+
+// 审计汇总事件
+//
+// 按案件列出明细的汇总事件可能超过事件大小上限，通过 framework.EmitEventOrAnchor 发出：
+// 超限时完整内容写入 event_payload:{hash}，改为发出 EventPayloadAnchored。
+const (
+	// EVENT_ROUND_PAYOUT_SUMMARY SettleRound 发出的轮次给付明细
+	EVENT_ROUND_PAYOUT_SUMMARY = "MutualAidRoundPayoutSummary"
+	// EVENT_CLAIMS_BATCH_REVIEWED BatchReviewClaims 发出的批量审核结果
+	EVENT_CLAIMS_BATCH_REVIEWED = "MutualAidClaimsBatchReviewed"
+)
+
+func init() {
+	framework.RegisterEventSchema(EVENT_ROUND_PAYOUT_SUMMARY, "plan_id", "round_id", "status", "claims_count", "total_approved_payout", "claims")
+	framework.RegisterEventSchema(EVENT_CLAIMS_BATCH_REVIEWED, "plan_id", "review_round_id", "applied_count", "skipped_count", "round_claims_count", "reviewer", "results")
+}
+
+// sumApprovedPayout 汇总轮次内案件的批准金额（轮次结算的 total_approved_payout）
+//
+// 案件列表来自 round_claims:{round_id}，只有审核批准的案件会归入轮次；
+// lookup 返回 found=false 的案件（不存在、未批准或属于其他轮次，见 claimCountsTowardRound）不计入。
+func sumApprovedPayout(claimIDs []string, lookup func(claimID string) (approvedAmount uint64, found bool)) uint64 {
+	var total uint64
+	for _, claimID := range claimIDs {
+		if amount, found := lookup(claimID); found {
+			total += amount
+		}
+	}
+	return total
+}
+
+// claimCountsTowardRound 案件的批准金额是否计入轮次结算
+//
+// 只有已批准（或之后已给付）且审核时归入该轮次的案件计入；同一案件只能审核一次，
+// 轮次案件索引也不会重复追加，因此每个案件至多计入一次。
+func claimCountsTowardRound(status, reviewRoundID, roundID string) bool {
+	if status != CLAIM_STATUS_APPROVED && status != CLAIM_STATUS_PAID {
+		return false
+	}
+	// 案件记录中的轮次ID最多保存32字节（见 encodeClaim）
+	if len(roundID) > 32 {
+		roundID = roundID[:32]
+	}
+	return reviewRoundID == roundID
+}
+
+// payoutSummaryItems 按轮次案件索引顺序列出每个案件的批准金额
+//
+// lookup 返回案件的批准金额，found=false 的案件金额记为 0 并标记 missing
+func payoutSummaryItems(claimIDs []string, lookup func(claimID string) (approvedAmount uint64, found bool)) []interface{} {
+	items := make([]interface{}, 0, len(claimIDs))
+	for _, claimID := range claimIDs {
+		item := map[string]interface{}{
+			"claim_id":        claimID,
+			"approved_amount": uint64(0),
+		}
+		if amount, found := lookup(claimID); found {
+			item["approved_amount"] = amount
+		} else {
+			item["missing"] = true
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// batchReviewItems 批量审核逐项结果（用于返回值与 MutualAidClaimsBatchReviewed 事件）
+func batchReviewItems(results []batchReviewResult) []interface{} {
+	items := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		items = append(items, map[string]interface{}{
+			"claim_id":        r.ClaimID,
+			"decision":        r.Decision,
+			"outcome":         r.Outcome,
+			"skip_reason":     r.SkipReason,
+			"status":          r.NewStatus,
+			"approved_amount": r.ApprovedAmount,
+		})
+	}
+	return items
+}
+
+// ================================================================================================
+// 保障类别
+// ================================================================================================
+//
+// 计划可在 Initialize 时配置保障类别（如重疾、意外、住院），每个类别有独立的单次给付上限、
+// 年度累计上限与等待期。配置了类别的计划要求报案指定 category_id；
+// 未配置类别的计划保持原有规则（coverage_amount 与计划等待期）。
+//
+// 年度累计额度按 被保人 × 类别 × 出险年份 计数：审核批准时占用（approved），给付时计入（paid），
+// 两者均不得超过类别的 annual_limit。operator 可为成员设置类别除外（如既往症），
+// 被除外的成员不能在该类别下报案。
+
+// MAX_COVERAGE_CATEGORIES 计划最多配置的保障类别数
+const MAX_COVERAGE_CATEGORIES = 8
+
+// COVERAGE_CATEGORY_ID_SIZE 类别ID的最大长度（字节）
+const COVERAGE_CATEGORY_ID_SIZE = 32
+
+// MAX_EXCLUSION_REASON_SIZE 类别除外原因的最大长度（字节）
+const MAX_EXCLUSION_REASON_SIZE = 64
+
+// 类别编码长度
+const (
+	// coverageCategorySize 类别配置：categoryID(32) + perClaimLimit(8) + annualLimit(8) + waitingPeriod(8)
+	coverageCategorySize = COVERAGE_CATEGORY_ID_SIZE + 24
+	// memberExclusionSize 成员类别除外：categoryID(32) + excludedAt(8) + reason(64)
+	memberExclusionSize = COVERAGE_CATEGORY_ID_SIZE + 8 + MAX_EXCLUSION_REASON_SIZE
+)
+
+// 报案/审核/给付的类别拒绝原因（结构化错误返回中的 error 字段）
+const (
+	// CATEGORY_REJECT_REQUIRED 计划配置了类别，报案未指定 category_id
+	CATEGORY_REJECT_REQUIRED = "CATEGORY_REQUIRED"
+	// CATEGORY_REJECT_UNKNOWN 类别未在计划中配置
+	CATEGORY_REJECT_UNKNOWN = "UNKNOWN_CATEGORY"
+	// CATEGORY_REJECT_EXCLUDED 被保人在该类别下被除外
+	CATEGORY_REJECT_EXCLUDED = "CATEGORY_EXCLUDED"
+	// CATEGORY_REJECT_PER_CLAIM_LIMIT 申请金额超过类别单次给付上限
+	CATEGORY_REJECT_PER_CLAIM_LIMIT = "PER_CLAIM_LIMIT_EXCEEDED"
+	// CATEGORY_REJECT_WAITING_PERIOD 类别等待期未满
+	CATEGORY_REJECT_WAITING_PERIOD = "WAITING_PERIOD"
+	// CATEGORY_REJECT_ANNUAL_LIMIT 超过类别年度累计上限
+	CATEGORY_REJECT_ANNUAL_LIMIT = "ANNUAL_LIMIT_EXCEEDED"
+)
+
+// coverageCategory 保障类别配置
+type coverageCategory struct {
+	ID            string
+	PerClaimLimit uint64
+	AnnualLimit   uint64
+	WaitingPeriod uint64
+}
+
+// parseCoverageCategories 解析 Initialize 参数中的 categories 数组
+//
+// 参数格式：{"categories":[{"category_id":"critical_illness","per_claim_limit":300000,"annual_limit":300000,"waiting_period":7776000}, ...]}
+//
+// 规则：
+//   - 未提供 categories 时返回 nil, true（计划不区分类别）
+//   - 类别数为 1 ~ MAX_COVERAGE_CATEGORIES，category_id 非空、不超过 32 字节且不重复
+//   - per_claim_limit > 0，annual_limit >= per_claim_limit
+//   - 未提供 waiting_period（或为 null）时使用计划等待期 defaultWaitingPeriod
+//   - 金额与等待期须为非负整数，超过 uint64 时拒绝
+func parseCoverageCategories(raw string, defaultWaitingPeriod uint64) ([]coverageCategory, bool) {
+	params := framework.NewContractParams([]byte(raw))
+	if !params.HasJSONField("categories") {
+		return nil, true
+	}
+	objects := params.ParseJSONObjectArray("categories")
+	if len(objects) == 0 || len(objects) > MAX_COVERAGE_CATEGORIES {
+		return nil, false
+	}
+	categories := make([]coverageCategory, 0, len(objects))
+	for _, obj := range objects {
+		perClaimLimit, err := obj.ParseAmount("per_claim_limit")
+		if err != nil {
+			return nil, false
+		}
+		annualLimit, err := obj.ParseAmount("annual_limit")
+		if err != nil {
+			return nil, false
+		}
+		c := coverageCategory{
+			ID:            obj.ParseJSON("category_id"),
+			PerClaimLimit: uint64(perClaimLimit),
+			AnnualLimit:   uint64(annualLimit),
+			WaitingPeriod: defaultWaitingPeriod,
+		}
+		if obj.HasJSONField("waiting_period") {
+			waitingPeriod, err := obj.ParseDuration("waiting_period")
+			if err != nil {
+				return nil, false
+			}
+			c.WaitingPeriod = uint64(waitingPeriod)
+		}
+		if c.ID == "" || len(c.ID) > COVERAGE_CATEGORY_ID_SIZE || c.PerClaimLimit == 0 || c.AnnualLimit < c.PerClaimLimit {
+			return nil, false
+		}
+		if _, dup := findCoverageCategory(categories, c.ID); dup {
+			return nil, false
+		}
+		categories = append(categories, c)
+	}
+	return categories, true
+}
+
+// findCoverageCategory 按ID查找类别
+func findCoverageCategory(categories []coverageCategory, id string) (coverageCategory, bool) {
+	for _, c := range categories {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return coverageCategory{}, false
+}
+
+// checkClaimCategory 报案时的类别校验
+//
+// 参数：
+//   - categories: 计划配置的类别（为空表示计划不区分类别）
+//   - categoryID: 报案指定的类别
+//   - excluded: 被保人是否在该类别下被除外
+//   - requestedAmount: 申请金额
+//   - joinTime / now: 成员加入时间与当前时间，用于类别等待期
+//
+// 返回：匹配的类别与拒绝原因（空字符串表示通过）；计划不区分类别时不校验
+func checkClaimCategory(categories []coverageCategory, categoryID string, excluded bool, requestedAmount, joinTime, now uint64) (coverageCategory, string) {
+	if len(categories) == 0 {
+		if categoryID != "" {
+			return coverageCategory{}, CATEGORY_REJECT_UNKNOWN
+		}
+		return coverageCategory{}, ""
+	}
+	if categoryID == "" {
+		return coverageCategory{}, CATEGORY_REJECT_REQUIRED
+	}
+	category, ok := findCoverageCategory(categories, categoryID)
+	if !ok {
+		return coverageCategory{}, CATEGORY_REJECT_UNKNOWN
+	}
+	if excluded {
+		return category, CATEGORY_REJECT_EXCLUDED
+	}
+	if requestedAmount > category.PerClaimLimit {
+		return category, CATEGORY_REJECT_PER_CLAIM_LIMIT
+	}
+	if now < joinTime+category.WaitingPeriod {
+		return category, CATEGORY_REJECT_WAITING_PERIOD
+	}
+	return category, ""
+}
+
+// categoryUsage 被保人某类别某年度的累计额度
+type categoryUsage struct {
+	// Approved 已批准金额（ReviewClaim / BatchReviewClaims 批准时占用）
+	Approved uint64
+	// Paid 已给付金额（Payout 时计入）
+	Paid uint64
+}
+
+// remaining 年度剩余可批准额度
+func (u categoryUsage) remaining(annualLimit uint64) uint64 {
+	if u.Approved >= annualLimit {
+		return 0
+	}
+	return annualLimit - u.Approved
+}
+
+// reserveApproval 批准时占用年度额度，超过 annualLimit 时 ok=false
+func (u categoryUsage) reserveApproval(annualLimit, amount uint64) (categoryUsage, bool) {
+	total, ok := addChecked(u.Approved, amount)
+	if !ok || total > annualLimit {
+		return u, false
+	}
+	u.Approved = total
+	return u, true
+}
+
+// recordPayout 给付时计入年度已给付金额，超过 annualLimit 时 ok=false
+func (u categoryUsage) recordPayout(annualLimit, amount uint64) (categoryUsage, bool) {
+	total, ok := addChecked(u.Paid, amount)
+	if !ok || total > annualLimit {
+		return u, false
+	}
+	u.Paid = total
+	return u, true
+}
+
+// calendarYear 时间戳所在的公历年份（UTC）
+func calendarYear(ts uint64) uint64 {
+	year, _, _ := framework.TimestampToDate(ts)
+	return uint64(year)
+}
+
+// encodeCoverageCategories 编码类别配置：每个类别 coverageCategorySize 字节
+func encodeCoverageCategories(categories []coverageCategory) []byte {
+	data := make([]byte, len(categories)*coverageCategorySize)
+	for i, c := range categories {
+		entry := data[i*coverageCategorySize:]
+		copy(entry[0:COVERAGE_CATEGORY_ID_SIZE], c.ID)
+		copy(entry[32:40], framework.Uint64ToBytes(c.PerClaimLimit))
+		copy(entry[40:48], framework.Uint64ToBytes(c.AnnualLimit))
+		copy(entry[48:56], framework.Uint64ToBytes(c.WaitingPeriod))
+	}
+	return data
+}
+
+// decodeCoverageCategories 解码类别配置（尾部被裁剪的零字节按 0 补齐）
+func decodeCoverageCategories(data []byte) []coverageCategory {
+	var categories []coverageCategory
+	for _, entry := range fixedEntries(data, coverageCategorySize) {
+		id := string(framework.TrimNull(entry[0:COVERAGE_CATEGORY_ID_SIZE]))
+		if id == "" {
+			break
+		}
+		categories = append(categories, coverageCategory{
+			ID:            id,
+			PerClaimLimit: framework.BytesToUint64(entry[32:40]),
+			AnnualLimit:   framework.BytesToUint64(entry[40:48]),
+			WaitingPeriod: framework.BytesToUint64(entry[48:56]),
+		})
+	}
+	return categories
+}
+
+// memberExclusion 成员类别除外
+type memberExclusion struct {
+	CategoryID string
+	Reason     string
+	ExcludedAt uint64
+}
+
+// upsertExclusion 添加或更新成员的类别除外（同一类别只保留一条，更新原因与时间）
+func upsertExclusion(exclusions []memberExclusion, e memberExclusion) []memberExclusion {
+	for i := range exclusions {
+		if exclusions[i].CategoryID == e.CategoryID {
+			exclusions[i] = e
+			return exclusions
+		}
+	}
+	return append(exclusions, e)
+}
+
+// findExclusion 查找成员在某类别下的除外记录
+func findExclusion(exclusions []memberExclusion, categoryID string) (memberExclusion, bool) {
+	for _, e := range exclusions {
+		if e.CategoryID == categoryID {
+			return e, true
+		}
+	}
+	return memberExclusion{}, false
+}
+
+// encodeExclusions 编码成员类别除外列表：每条 memberExclusionSize 字节
+func encodeExclusions(exclusions []memberExclusion) []byte {
+	data := make([]byte, len(exclusions)*memberExclusionSize)
+	for i, e := range exclusions {
+		entry := data[i*memberExclusionSize:]
+		copy(entry[0:COVERAGE_CATEGORY_ID_SIZE], e.CategoryID)
+		copy(entry[32:40], framework.Uint64ToBytes(e.ExcludedAt))
+		copy(entry[40:memberExclusionSize], []byte(e.Reason)[:min(MAX_EXCLUSION_REASON_SIZE, len(e.Reason))])
+	}
+	return data
+}
+
+// decodeExclusions 解码成员类别除外列表（尾部被裁剪的零字节按 0 补齐）
+func decodeExclusions(data []byte) []memberExclusion {
+	var exclusions []memberExclusion
+	for _, entry := range fixedEntries(data, memberExclusionSize) {
+		id := string(framework.TrimNull(entry[0:COVERAGE_CATEGORY_ID_SIZE]))
+		if id == "" {
+			break
+		}
+		exclusions = append(exclusions, memberExclusion{
+			CategoryID: id,
+			ExcludedAt: framework.BytesToUint64(entry[32:40]),
+			Reason:     string(framework.TrimNull(entry[40:memberExclusionSize])),
+		})
+	}
+	return exclusions
+}
+
+// encodeCategoryUsage 编码年度累计额度：approved(8) + paid(8)
+func encodeCategoryUsage(u categoryUsage) []byte {
+	return append(framework.Uint64ToBytes(u.Approved), framework.Uint64ToBytes(u.Paid)...)
+}
+
+// decodeCategoryUsage 解码年度累计额度（尾部被裁剪的零字节按 0 补齐）
+func decodeCategoryUsage(data []byte) categoryUsage {
+	entries := fixedEntries(data, 16)
+	if len(entries) == 0 {
+		return categoryUsage{}
+	}
+	return categoryUsage{Approved: framework.BytesToUint64(entries[0][0:8]), Paid: framework.BytesToUint64(entries[0][8:16])}
+}
+
+// 类别事件
+const (
+	// EVENT_COVERAGE_CATEGORIES_CONFIGURED Initialize 配置保障类别时发出
+	EVENT_COVERAGE_CATEGORIES_CONFIGURED = "MutualAidCoverageCategoriesConfigured"
+	// EVENT_CATEGORY_EXCLUDED ExcludeCategoryForMember 发出的成员类别除外事件
+	EVENT_CATEGORY_EXCLUDED = "MutualAidCategoryExcluded"
+)
+
+func init() {
+	framework.RegisterEventSchema(EVENT_COVERAGE_CATEGORIES_CONFIGURED, "plan_id", "categories")
+	framework.RegisterEventSchema(EVENT_CATEGORY_EXCLUDED, "plan_id", "member", "category_id", "reason", "excluded_at", "operator")
+}
+
+// coverageCategoryItems 类别配置列表（用于 GetPlanInfo 与 MutualAidCoverageCategoriesConfigured 事件）
+func coverageCategoryItems(categories []coverageCategory) []interface{} {
+	items := make([]interface{}, 0, len(categories))
+	for _, c := range categories {
+		items = append(items, map[string]interface{}{
+			"category_id":     c.ID,
+			"per_claim_limit": c.PerClaimLimit,
+			"annual_limit":    c.AnnualLimit,
+			"waiting_period":  c.WaitingPeriod,
+		})
+	}
+	return items
+}
+
+// exclusionItems 成员类别除外列表（用于 GetMemberInfo 与 ExcludeCategoryForMember 返回值）
+func exclusionItems(exclusions []memberExclusion) []interface{} {
+	items := make([]interface{}, 0, len(exclusions))
+	for _, e := range exclusions {
+		items = append(items, map[string]interface{}{
+			"category_id": e.CategoryID,
+			"reason":      e.Reason,
+			"excluded_at": e.ExcludedAt,
+		})
+	}
+	return items
+}
+
+// categoryHeadroomItems 各类别当年的年度累计额度与剩余额度（用于 GetMemberInfo）
+//
+// usage 返回被保人在该类别当年的累计额度
+func categoryHeadroomItems(categories []coverageCategory, year uint64, usage func(categoryID string) categoryUsage) []interface{} {
+	items := make([]interface{}, 0, len(categories))
+	for _, c := range categories {
+		u := usage(c.ID)
+		items = append(items, map[string]interface{}{
+			"category_id":  c.ID,
+			"year":         year,
+			"annual_limit": c.AnnualLimit,
+			"approved":     u.Approved,
+			"paid":         u.Paid,
+			"remaining":    u.remaining(c.AnnualLimit),
+		})
+	}
+	return items
+}
+
+// fixedEntries 按定长切分编码数据，最后一条不足时补零
+func fixedEntries(data []byte, size int) [][]byte {
+	n := (len(data) + size - 1) / size
+	padded := make([]byte, n*size)
+	copy(padded, data)
+	entries := make([][]byte, n)
+	for i := range entries {
+		entries[i] = padded[i*size : (i+1)*size]
+	}
+	return entries
+}
+
+// 计划终结的拒绝原因（FinalizePlan 返回值的 error 字段）
+const (
+	// FINALIZE_REJECT_ROUND_OPEN 当前轮次仍为 OPEN，需先结算
+	FINALIZE_REJECT_ROUND_OPEN = "ROUND_OPEN"
+	// FINALIZE_REJECT_CLAIMS_UNPAID 存在已批准但尚未给付的案件
+	FINALIZE_REJECT_CLAIMS_UNPAID = "CLAIMS_UNPAID"
+)
+
+// EVENT_PLAN_FINALIZED FinalizePlan 发出的计划终结事件（含逐个成员的分配明细，超过事件大小上限时锚定）
+const EVENT_PLAN_FINALIZED = "MutualAidPlanFinalized"
+
+func init() {
+	framework.RegisterEventSchema(EVENT_PLAN_FINALIZED, "plan_id", "pool", "surplus", "distributed", "member_count_active", "distributions", "finalized_at")
+}
+
+// finalizeBlocker 计划能否终结
+//
+// 参数：
+//   - currentRoundStatus: 当前轮次状态（尚未开启任何轮次时为空）
+//   - approvedUnpaid: 已批准但尚未给付的案件数
+//
+// 返回：拒绝原因（FINALIZE_REJECT_*），可以终结时为空
+func finalizeBlocker(currentRoundStatus string, approvedUnpaid uint64) string {
+	if currentRoundStatus == ROUND_STATUS_OPEN {
+		return FINALIZE_REJECT_ROUND_OPEN
+	}
+	if approvedUnpaid > 0 {
+		return FINALIZE_REJECT_CLAIMS_UNPAID
+	}
+	return ""
+}
+
+// splitSurplus 按分摊权重将资金池余额按比例分给活跃成员
+//
+// 每名成员先得 floor(surplus * weight / totalWeight)，取整余下的金额按成员顺序每人补 1，
+// 分配总额恰好等于 surplus。权重与分摊时相同（档位系数，见 tierMultiplier），
+// 权重之和为 0（没有活跃成员）时不分配，返回全 0。
+func splitSurplus(surplus uint64, weights []uint64) []uint64 {
+	shares := make([]uint64, len(weights))
+	var totalWeight uint64
+	for _, w := range weights {
+		totalWeight += w
+	}
+	if totalWeight == 0 {
+		return shares
+	}
+	var distributed uint64
+	for i, w := range weights {
+		hi, lo := bits.Mul64(surplus, w)
+		shares[i], _ = bits.Div64(hi, lo, totalWeight)
+		distributed += shares[i]
+	}
+	for i := 0; distributed < surplus; i = (i + 1) % len(shares) {
+		if weights[i] == 0 {
+			continue
+		}
+		shares[i]++
+		distributed++
+	}
+	return shares
+}
+
+// surplusDistributionItems 计划终结时逐个成员的分配明细（用于返回值与 MutualAidPlanFinalized 事件）
+func surplusDistributionItems(members []framework.Address, weights, shares []uint64) []interface{} {
+	items := make([]interface{}, 0, len(members))
+	for i, m := range members {
+		items = append(items, map[string]interface{}{
+			"member":    m.ToString(),
+			"weight_bp": weights[i],
+			"amount":    shares[i],
+		})
+	}
+	return items
+}
+
+// ==================== 成员暂停与拉黑 ====================
+//
+// operator 通过 SuspendMember / ResumeMember / BlacklistMember 调整成员状态：
+//
+//	ACTIVE    -> SUSPENDED   (SuspendMember)
+//	SUSPENDED -> ACTIVE      (ResumeMember)
+//	PENDING / ACTIVE / SUSPENDED -> BLACKLISTED (BlacklistMember)
+//
+// 暂停期间成员不计入活跃成员数（member_count_active、档位计数与活跃成员集合均移出），
+// 因此不参与之后开启轮次的分摊，也不能提交案件或缴费；恢复时重新计入，保留原激活序号。
+// 拉黑为终态，成员不能重新加入（见 Join）。
+
+// 成员状态调整操作
+const (
+	MEMBER_ACTION_SUSPEND   = "SUSPEND"
+	MEMBER_ACTION_RESUME    = "RESUME"
+	MEMBER_ACTION_BLACKLIST = "BLACKLIST"
+)
+
+// MAX_MEMBER_STATUS_REASON_SIZE 暂停、恢复、拉黑原因的最大长度（字节）
+const MAX_MEMBER_STATUS_REASON_SIZE = 64
+
+// 成员状态调整事件
+const (
+	// EVENT_MEMBER_SUSPENDED SuspendMember 发出
+	EVENT_MEMBER_SUSPENDED = "MutualAidMemberSuspended"
+	// EVENT_MEMBER_RESUMED ResumeMember 发出
+	EVENT_MEMBER_RESUMED = "MutualAidMemberResumed"
+	// EVENT_MEMBER_BLACKLISTED BlacklistMember 发出
+	EVENT_MEMBER_BLACKLISTED = "MutualAidMemberBlacklisted"
+)
+
+func init() {
+	for _, name := range []string{EVENT_MEMBER_SUSPENDED, EVENT_MEMBER_RESUMED, EVENT_MEMBER_BLACKLISTED} {
+		framework.RegisterEventSchema(name, "plan_id", "member", "previous_status", "status", "reason", "operator", "member_count_active")
+	}
+}
+
+// memberStatusTransition 计算成员状态调整后的新状态
+//
+// 参数：
+//   - action: MEMBER_ACTION_*
+//   - status: 成员当前状态
+//
+// 返回：新状态；当前状态不允许该操作（或操作未知）时 ok 为 false
+func memberStatusTransition(action, status string) (newStatus string, ok bool) {
+	switch action {
+	case MEMBER_ACTION_SUSPEND:
+		return MEMBER_STATUS_SUSPENDED, status == MEMBER_STATUS_ACTIVE
+	case MEMBER_ACTION_RESUME:
+		return MEMBER_STATUS_ACTIVE, status == MEMBER_STATUS_SUSPENDED
+	case MEMBER_ACTION_BLACKLIST:
+		return MEMBER_STATUS_BLACKLISTED, status == MEMBER_STATUS_PENDING || status == MEMBER_STATUS_ACTIVE || status == MEMBER_STATUS_SUSPENDED
+	}
+	return "", false
+}
+
+// memberStatusEvent 状态调整操作对应的事件名
+func memberStatusEvent(action string) string {
+	switch action {
+	case MEMBER_ACTION_SUSPEND:
+		return EVENT_MEMBER_SUSPENDED
+	case MEMBER_ACTION_RESUME:
+		return EVENT_MEMBER_RESUMED
+	}
+	return EVENT_MEMBER_BLACKLISTED
+}
+
+// ==================== 理赔取消 ====================
+//
+// CancelClaim 在给付前取消案件：
+//
+//	SUBMITTED / UNDER_REVIEW -> CANCELLED (申请人本人或 operator)
+//	APPROVED                 -> CANCELLED (仅 operator，须说明原因)
+//
+// 取消已批准的案件时撤回批准的影响：claims_approved_unpaid 减一、释放类别年度已批准额度；
+// 案件所在轮次已结算时，从轮次 total_approved_payout 中扣除批准金额。
+// 轮次仍为 OPEN 时无需调整——结算时按 claimCountsTowardRound 汇总，已取消的案件不计入。
+
+// MAX_CLAIM_CANCEL_REASON_SIZE 取消原因的最大长度（字节）
+const MAX_CLAIM_CANCEL_REASON_SIZE = 128
+
+// EVENT_CLAIM_CANCELLED CancelClaim 发出
+const EVENT_CLAIM_CANCELLED = "MutualAidClaimCancelled"
+
+func init() {
+	framework.RegisterEventSchema(EVENT_CLAIM_CANCELLED, "plan_id", "claim_id", "previous_status", "reason", "cancelled_by", "by_operator", "approved_amount", "round_id")
+}
+
+// claimCancellable 案件在当前状态下是否可由调用方取消
+//
+// 参数：
+//   - status: 案件当前状态
+//   - byOperator: 调用方为 operator（否则为申请人本人）
+func claimCancellable(status string, byOperator bool) bool {
+	switch status {
+	case CLAIM_STATUS_SUBMITTED, CLAIM_STATUS_UNDER_REVIEW:
+		return true
+	case CLAIM_STATUS_APPROVED:
+		return byOperator
+	}
+	return false
+}
+
+// roundTotalAfterCancel 取消已批准案件后轮次的 total_approved_payout
+//
+// 返回：
+//   - total: 调整后的总额；轮次为 OPEN 时原样返回（结算时重新汇总）
+//   - changed: 是否需要写回轮次记录
+//   - ok: 总额不足以扣除时为 false（轮次记录与案件不一致）
+func roundTotalAfterCancel(roundStatus string, total, approvedAmount uint64) (newTotal uint64, changed, ok bool) {
+	if roundStatus == ROUND_STATUS_OPEN || approvedAmount == 0 {
+		return total, false, true
+	}
+	newTotal, ok = subChecked(total, approvedAmount)
+	if !ok {
+		return total, false, false
+	}
+	return newTotal, true, true
+}
+
+// releaseApproval 取消已批准案件时释放占用的年度额度（不低于 0）
+func (u categoryUsage) releaseApproval(amount uint64) categoryUsage {
+	if approved, ok := subChecked(u.Approved, amount); ok {
+		u.Approved = approved
+	} else {
+		u.Approved = 0
+	}
+	return u
+}
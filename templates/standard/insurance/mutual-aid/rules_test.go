@@ -0,0 +1,1775 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+)
+
+// testPlan 30天结算周期、8% 服务费的互助计划
+var testPlan = fixtures.MutualAidPlan()
+
+// TestValidateRoundPeriod 测试轮次周期与 settlement_period 的一致性校验
+func TestValidateRoundPeriod(t *testing.T) {
+	start := fixtures.Epoch
+	tests := []struct {
+		name          string
+		periodStart   uint64
+		periodEnd     uint64
+		prevPeriodEnd uint64
+		want          bool
+	}{
+		{"exact period", start, start + testPlan.SettlementPeriod, 0, true},
+		{"within tolerance (31 days)", start, start + fixtures.Days(31), 0, true},
+		{"within tolerance (28 days)", start, start + fixtures.Days(28), 0, true},
+		{"too short", start, start + fixtures.Days(7), 0, false},
+		{"too long", start, start + fixtures.Days(60), 0, false},
+		{"end before start", start, start - 1, 0, false},
+		{"zero start", 0, testPlan.SettlementPeriod, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateRoundPeriod(tt.periodStart, tt.periodEnd, testPlan.SettlementPeriod, tt.prevPeriodEnd)
+			if got != tt.want {
+				t.Errorf("validateRoundPeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateRoundPeriodOverlap 测试新轮次不能与上一轮次重叠
+func TestValidateRoundPeriodOverlap(t *testing.T) {
+	prev := fixtures.OpenRound("round_202501_01", testPlan, fixtures.Epoch)
+	period := testPlan.SettlementPeriod
+
+	// 紧接上一轮次
+	next := prev.Next("round_202502_01")
+	if !validateRoundPeriod(next.PeriodStart, next.PeriodEnd, period, prev.PeriodEnd) {
+		t.Error("round starting at previous period_end should be accepted")
+	}
+
+	// 与上一轮次重叠一天
+	start := prev.PeriodEnd - fixtures.Days(1)
+	if validateRoundPeriod(start, start+period, period, prev.PeriodEnd) {
+		t.Error("overlapping round should be rejected")
+	}
+
+	// 与上一轮次完全相同
+	if validateRoundPeriod(prev.PeriodStart, prev.PeriodEnd, period, prev.PeriodEnd) {
+		t.Error("round with identical period should be rejected")
+	}
+}
+
+// TestEffectiveMonthlyCap 测试个人上限覆盖优先于计划默认上限
+func TestEffectiveMonthlyCap(t *testing.T) {
+	planCap := testPlan.MonthlyCapPerMember
+
+	if got := effectiveMonthlyCap(planCap, 0); got != planCap {
+		t.Errorf("effectiveMonthlyCap(no override) = %d, want %d", got, planCap)
+	}
+
+	// 高风险成员：上限低于计划默认值
+	if got := effectiveMonthlyCap(planCap, 5000); got != 5000 {
+		t.Errorf("effectiveMonthlyCap(lower override) = %d, want 5000", got)
+	}
+
+	// 高等级成员：上限高于计划默认值
+	if got := effectiveMonthlyCap(planCap, 20000); got != 20000 {
+		t.Errorf("effectiveMonthlyCap(higher override) = %d, want 20000", got)
+	}
+
+	// 同一笔缴费在默认上限下被拒绝，在覆盖上限下被接受
+	monthPaid, amount := planCap-1000, uint64(2000)
+	if monthPaid+amount <= effectiveMonthlyCap(planCap, 0) {
+		t.Error("contribution should exceed plan default cap")
+	}
+	if monthPaid+amount > effectiveMonthlyCap(planCap, 20000) {
+		t.Error("contribution should fit within overridden cap")
+	}
+}
+
+// TestComputeSettlement 测试服务费与人均分摊（向上取整）计算
+func TestComputeSettlement(t *testing.T) {
+	// 本轮批准一笔满额给付，7名成员分摊
+	s := mustRoundedSettlement(t, testPlan.CoverageAmount, testPlan.ServiceFeeBP, 7*TIER_MULTIPLIER_BASE_BP, defaultRounding, 0)
+	if s.TotalWithFee != 324000 || s.TotalServiceFee != 24000 {
+		t.Errorf("computeRoundedSettlement() total = %d, fee = %d, want 324000, 24000", s.TotalWithFee, s.TotalServiceFee)
+	}
+	// 324000 / 7 = 46285.7，向上取整
+	if s.PerCapita != 46286 {
+		t.Errorf("computeRoundedSettlement() perCapita = %d, want 46286", s.PerCapita)
+	}
+
+	if s := mustRoundedSettlement(t, testPlan.CoverageAmount, testPlan.ServiceFeeBP, 0, defaultRounding, 0); s.PerCapita != 0 {
+		t.Errorf("computeRoundedSettlement(no members) perCapita = %d, want 0", s.PerCapita)
+	}
+}
+
+// TestSettleRoundWithoutApprovedClaims 测试无已批准案件的轮次结算为 SETTLED_ZERO，且不开放缴费、不产生欠费
+func TestSettleRoundWithoutApprovedClaims(t *testing.T) {
+	s := mustRoundedSettlement(t, 0, testPlan.ServiceFeeBP, 7*TIER_MULTIPLIER_BASE_BP, defaultRounding, 0)
+	if s.TotalWithFee != 0 || s.TotalServiceFee != 0 || s.PerCapita != 0 {
+		t.Errorf("computeRoundedSettlement(0) = %d, %d, %d, want all 0", s.TotalWithFee, s.TotalServiceFee, s.PerCapita)
+	}
+
+	status := settledRoundStatus(0)
+	if status != ROUND_STATUS_SETTLED_ZERO {
+		t.Fatalf("settledRoundStatus(0) = %s, want SETTLED_ZERO", status)
+	}
+	if roundAcceptsContributions(status) {
+		t.Error("SETTLED_ZERO round should not accept contributions")
+	}
+
+	if status := settledRoundStatus(testPlan.CoverageAmount); status != ROUND_STATUS_SETTLED || !roundAcceptsContributions(status) {
+		t.Errorf("settledRoundStatus(payout) = %s, want SETTLED accepting contributions", status)
+	}
+	for _, s := range []string{ROUND_STATUS_OPEN, ROUND_STATUS_CLOSED} {
+		if roundAcceptsContributions(s) {
+			t.Errorf("roundAcceptsContributions(%s) = true, want false", s)
+		}
+	}
+}
+
+// TestClaimCountsTowardRound 测试只有归入本轮次的已批准或已给付案件计入结算
+func TestClaimCountsTowardRound(t *testing.T) {
+	longRoundID := "round_" + strings.Repeat("9", 40)
+	tests := []struct {
+		status, reviewRoundID, roundID string
+		want                           bool
+	}{
+		{CLAIM_STATUS_APPROVED, "r1", "r1", true},
+		{CLAIM_STATUS_PAID, "r1", "r1", true},
+		{CLAIM_STATUS_APPROVED, "r2", "r1", false},
+		{CLAIM_STATUS_REJECTED, "r1", "r1", false},
+		{CLAIM_STATUS_SUBMITTED, "", "r1", false},
+		{CLAIM_STATUS_UNDER_REVIEW, "r1", "r1", false},
+		{CLAIM_STATUS_APPROVED, longRoundID[:32], longRoundID, true},
+	}
+	for _, tt := range tests {
+		if got := claimCountsTowardRound(tt.status, tt.reviewRoundID, tt.roundID); got != tt.want {
+			t.Errorf("claimCountsTowardRound(%s, %q, %q) = %v, want %v", tt.status, tt.reviewRoundID, tt.roundID, got, tt.want)
+		}
+	}
+
+	amounts := map[string]uint64{"claim_a": 60000, "claim_c": 30000}
+	lookup := func(claimID string) (uint64, bool) {
+		amount, ok := amounts[claimID]
+		return amount, ok
+	}
+	if got := sumApprovedPayout([]string{"claim_a", "claim_b", "claim_c"}, lookup); got != 90000 {
+		t.Errorf("sumApprovedPayout() = %d, want 90000", got)
+	}
+}
+
+// TestNextRoundPeriod 测试自动推进时下一轮次周期的推导
+func TestNextRoundPeriod(t *testing.T) {
+	clock := fixtures.NewClock()
+	period := testPlan.SettlementPeriod
+	prev := fixtures.OpenRound("round_202501_01", testPlan, clock.Now())
+
+	// 按时推进：上一轮次到期后一天，紧接上一轮次
+	now := clock.Advance(period + fixtures.Days(1))
+	start, end := nextRoundPeriod(prev.PeriodEnd, period, now)
+	if want := prev.Next("round_202502_01"); start != want.PeriodStart || end != want.PeriodEnd {
+		t.Errorf("nextRoundPeriod(on time) = [%d, %d], want [%d, %d]", start, end, want.PeriodStart, want.PeriodEnd)
+	}
+	if !validateRoundPeriod(start, end, period, prev.PeriodEnd) {
+		t.Error("derived period should pass validateRoundPeriod")
+	}
+
+	// 延迟两个多周期推进：跳过空档周期，覆盖当前时间
+	now = clock.Advance(2*period + fixtures.Days(4))
+	start, end = nextRoundPeriod(prev.PeriodEnd, period, now)
+	if start != prev.PeriodEnd+2*period || now < start || now >= end {
+		t.Errorf("nextRoundPeriod(late) = [%d, %d], want period containing %d", start, end, now)
+	}
+	if !validateRoundPeriod(start, end, period, prev.PeriodEnd) {
+		t.Error("derived late period should pass validateRoundPeriod")
+	}
+}
+
+// TestTieredContribution 测试高档位成员按系数多缴，且各成员应缴之和覆盖本轮总额
+func TestTieredContribution(t *testing.T) {
+	// 档位0：1倍（未配置），档位1：1.5倍，档位2：3倍
+	multipliers := []uint64{tierMultiplier(0), tierMultiplier(15000), tierMultiplier(30000)}
+	tierCounts := []uint64{4, 2, 1}
+	const memberCount = uint64(7)
+
+	weight := totalMemberWeight(tierCounts, multipliers, memberCount)
+	if want := uint64(4*10000 + 2*15000 + 30000); weight != want {
+		t.Fatalf("totalMemberWeight() = %d, want %d", weight, want)
+	}
+
+	s := mustRoundedSettlement(t, testPlan.CoverageAmount, testPlan.ServiceFeeBP, weight, defaultRounding, 0)
+	totalWithFee, perCapita := s.TotalWithFee, s.PerCapita
+
+	// 高档位成员应缴按系数成比例增加
+	dues := make([]uint64, len(multipliers))
+	for tier, m := range multipliers {
+		due, ok := memberDue(perCapita, m)
+		if !ok {
+			t.Fatalf("memberDue(%d, %d) overflowed", perCapita, m)
+		}
+		dues[tier] = due
+	}
+	if dues[0] != perCapita {
+		t.Errorf("base tier due = %d, want per_capita %d", dues[0], perCapita)
+	}
+	if dues[1] < dues[0]*3/2 || dues[1] > dues[0]*3/2+1 {
+		t.Errorf("tier 1 due = %d, want ~1.5x of %d", dues[1], dues[0])
+	}
+	if dues[2] != dues[0]*3 {
+		t.Errorf("tier 2 due = %d, want 3x of %d", dues[2], dues[0])
+	}
+
+	// 总额对账：应缴之和不少于本轮总额
+	// 向上取整误差：per_capita 每人至多多收系数倍个单位，memberDue 每人至多再多收1个单位
+	var sum uint64
+	for tier, count := range tierCounts {
+		sum += count * dues[tier]
+	}
+	if sum < totalWithFee {
+		t.Errorf("sum of dues = %d, less than total_with_fee %d", sum, totalWithFee)
+	}
+	if maxRounding := weight/TIER_MULTIPLIER_BASE_BP + 1 + memberCount; sum-totalWithFee > maxRounding {
+		t.Errorf("sum of dues = %d exceeds total_with_fee %d by more than %d", sum, totalWithFee, maxRounding)
+	}
+}
+
+// TestMemberDueOverflow 测试应缴额在 uint64 边界附近的计算
+func TestMemberDueOverflow(t *testing.T) {
+	const maxUint64 = ^uint64(0)
+
+	// per_capita * multiplier 超过 uint64，但应缴额仍可表示
+	if due, ok := memberDue(maxUint64/2, 2*TIER_MULTIPLIER_BASE_BP); !ok || due != maxUint64-1 {
+		t.Errorf("memberDue(max/2, 2x) = %d, %v, want %d, true", due, ok, maxUint64-1)
+	}
+	if due, ok := memberDue(maxUint64, TIER_MULTIPLIER_BASE_BP); !ok || due != maxUint64 {
+		t.Errorf("memberDue(max, 1x) = %d, %v, want %d, true", due, ok, maxUint64)
+	}
+	if due, ok := memberDue(maxUint64, 1); !ok || due != maxUint64/TIER_MULTIPLIER_BASE_BP+1 {
+		t.Errorf("memberDue(max, 1bp) = %d, %v, want %d, true", due, ok, maxUint64/TIER_MULTIPLIER_BASE_BP+1)
+	}
+
+	// 应缴额超过 uint64 时拒绝而不是回绕
+	if due, ok := memberDue(maxUint64/2+1, 2*TIER_MULTIPLIER_BASE_BP); ok {
+		t.Errorf("memberDue(max/2+1, 2x) = %d, true, want overflow", due)
+	}
+	if due, ok := memberDue(maxUint64, TIER_MULTIPLIER_BASE_BP+1); ok {
+		t.Errorf("memberDue(max, 1.0001x) = %d, true, want overflow", due)
+	}
+	if _, ok := settlementTierDues(maxUint64, []uint64{TIER_MULTIPLIER_BASE_BP, 3 * TIER_MULTIPLIER_BASE_BP}); ok {
+		t.Error("settlementTierDues() with overflowing tier ok = true")
+	}
+}
+
+// TestTotalMemberWeightFlat 测试未分档计划退化为按人头均摊
+func TestTotalMemberWeightFlat(t *testing.T) {
+	multipliers := make([]uint64, MAX_TIERS)
+	for i := range multipliers {
+		multipliers[i] = tierMultiplier(0)
+	}
+
+	// 分档启用前激活的成员未计入任何档位，按基准系数计算
+	weight := totalMemberWeight(make([]uint64, MAX_TIERS), multipliers, 7)
+	if weight != 7*TIER_MULTIPLIER_BASE_BP {
+		t.Fatalf("totalMemberWeight(flat) = %d, want %d", weight, 7*TIER_MULTIPLIER_BASE_BP)
+	}
+
+	tiered := mustRoundedSettlement(t, testPlan.CoverageAmount, testPlan.ServiceFeeBP, weight, defaultRounding, 0).PerCapita
+	if due, ok := memberDue(tiered, multipliers[0]); tiered != 46286 || !ok || due != 46286 {
+		t.Errorf("flat plan per_capita = %d, want 46286", tiered)
+	}
+}
+
+// TestRoundClaimsIndex 测试轮次案件索引的追加与解码
+func TestRoundClaimsIndex(t *testing.T) {
+	index, count, ok := appendRoundClaim(nil, "claim_202501_0001")
+	if !ok || count != 1 {
+		t.Fatalf("appendRoundClaim(empty) = %d, %v, want 1, true", count, ok)
+	}
+	index, count, ok = appendRoundClaim(index, "claim_202501_0002")
+	if !ok || count != 2 {
+		t.Fatalf("appendRoundClaim() = %d, %v, want 2, true", count, ok)
+	}
+
+	// 同一案件不能重复归入
+	if _, _, ok := appendRoundClaim(index, "claim_202501_0001"); ok {
+		t.Error("duplicate claim should be rejected")
+	}
+	// 超长案件ID无法编码
+	if _, _, ok := appendRoundClaim(index, "claim_id_longer_than_thirty_two_bytes"); ok {
+		t.Error("claim id longer than 32 bytes should be rejected")
+	}
+
+	// GetState 返回 4096 字节零填充缓冲区
+	padded := make([]byte, 4096)
+	copy(padded, index)
+	if got := decodeRoundClaims(padded); len(got) != 2 || got[0] != "claim_202501_0001" || got[1] != "claim_202501_0002" {
+		t.Errorf("decodeRoundClaims(padded) = %v", got)
+	}
+
+	// GetStateFromChain 裁剪尾部 0x00，最后一个条目不足32字节
+	trimmed := framework.TrimNull(index[ROUND_CLAIM_ID_SIZE:])
+	trimmed = append(append([]byte{}, index[:ROUND_CLAIM_ID_SIZE]...), trimmed...)
+	if got := decodeRoundClaims(trimmed); len(got) != 2 || got[1] != "claim_202501_0002" {
+		t.Errorf("decodeRoundClaims(trimmed) = %v", got)
+	}
+}
+
+// TestRoundClaimsIndexFull 测试索引容量上限
+func TestRoundClaimsIndexFull(t *testing.T) {
+	var index []byte
+	for i := 0; i < MAX_ROUND_CLAIMS; i++ {
+		var ok bool
+		index, _, ok = appendRoundClaim(index, fmt.Sprintf("claim_%04d", i))
+		if !ok {
+			t.Fatalf("appendRoundClaim(#%d) rejected", i)
+		}
+	}
+	if len(index) != 4096 {
+		t.Errorf("full index length = %d, want 4096", len(index))
+	}
+	if _, count, ok := appendRoundClaim(index, "claim_overflow"); ok || count != MAX_ROUND_CLAIMS {
+		t.Errorf("appendRoundClaim(full) = %d, %v, want %d, false", count, ok, MAX_ROUND_CLAIMS)
+	}
+}
+
+// TestClaimsRatioServiceFee 测试赔付率联动的服务费率在区间内调整
+func TestClaimsRatioServiceFee(t *testing.T) {
+	fixedBP, minBP, maxBP := testPlan.ServiceFeeBP, uint64(300), uint64(1500)
+
+	tests := []struct {
+		name      string
+		paid      uint64
+		collected uint64
+		wantFee   uint64
+		wantRatio uint64
+	}{
+		{"no history uses fixed fee", 0, 0, fixedBP, 0},
+		{"no claims paid", 0, 1000000, minBP, 0},
+		{"low claims ratio (10%)", 100000, 1000000, 420, 1000},
+		{"half claims ratio", 500000, 1000000, 900, 5000},
+		{"high claims ratio (90%)", 900000, 1000000, 1380, 9000},
+		{"paid exceeds collected", 1500000, 1000000, maxBP, 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, ratio := effectiveServiceFeeBP(FEE_MODE_CLAIMS_RATIO, fixedBP, minBP, maxBP, tt.paid, tt.collected)
+			if fee != tt.wantFee || ratio != tt.wantRatio {
+				t.Errorf("effectiveServiceFeeBP() = %d, %d, want %d, %d", fee, ratio, tt.wantFee, tt.wantRatio)
+			}
+			if fee < minBP || fee > maxBP {
+				t.Errorf("fee %d outside bounds [%d, %d]", fee, minBP, maxBP)
+			}
+		})
+	}
+
+	// 无历史时固定费率超出区间被截断
+	if fee, _ := effectiveServiceFeeBP(FEE_MODE_CLAIMS_RATIO, 5000, minBP, maxBP, 0, 0); fee != maxBP {
+		t.Errorf("fixed fee above max = %d, want %d", fee, maxBP)
+	}
+	if fee, _ := effectiveServiceFeeBP(FEE_MODE_CLAIMS_RATIO, 100, minBP, maxBP, 0, 0); fee != minBP {
+		t.Errorf("fixed fee below min = %d, want %d", fee, minBP)
+	}
+
+	// 固定模式不受赔付率影响
+	if fee, ratio := effectiveServiceFeeBP(FEE_MODE_FIXED, fixedBP, minBP, maxBP, 900000, 1000000); fee != fixedBP || ratio != 0 {
+		t.Errorf("fixed mode = %d, %d, want %d, 0", fee, ratio, fixedBP)
+	}
+}
+
+// TestClaimsRatioBPLargeAmounts 测试大额累计值不溢出
+func TestClaimsRatioBPLargeAmounts(t *testing.T) {
+	const collected = uint64(1) << 62
+	if got := claimsRatioBP(collected/4, collected); got != 2500 {
+		t.Errorf("claimsRatioBP(large) = %d, want 2500", got)
+	}
+}
+
+// TestMemberEligibleForRound 测试轮次中途激活的成员不参与该轮分摊
+func TestMemberEligibleForRound(t *testing.T) {
+	member := fixtures.ActiveMember(60)
+	tests := []struct {
+		name        string
+		member      fixtures.Member
+		snapshotSeq uint64
+		hasSnapshot bool
+		want        bool
+	}{
+		{"activated before snapshot", member.ActivatedAt(3), 5, true, true},
+		{"activated at snapshot", member.ActivatedAt(5), 5, true, true},
+		{"activated mid-round", member.ActivatedAt(6), 5, true, false},
+		{"legacy member", member.ActivatedAt(0), 5, true, true},
+		{"empty snapshot excludes later members", member.ActivatedAt(1), 0, true, false},
+		{"legacy round without snapshot", member.ActivatedAt(6), 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := memberEligibleForRound(tt.member.ActivationSeq, tt.snapshotSeq, tt.hasSnapshot); got != tt.want {
+				t.Errorf("memberEligibleForRound(%d, %d, %v) = %v, want %v",
+					tt.member.ActivationSeq, tt.snapshotSeq, tt.hasSnapshot, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPlanBatchReviewMixed 测试混合批次：已审核案件跳过，其余逐项应用
+func TestPlanBatchReviewMixed(t *testing.T) {
+	// c2 已在上一批次批准，c4 已被拒绝
+	claims := make(map[string]fixtures.Claim)
+	for _, c := range []fixtures.Claim{
+		fixtures.SubmittedClaim("c1", 1000),
+		fixtures.SubmittedClaim("c2", 500).Approved("round_202501_01", 500),
+		fixtures.SubmittedClaim("c3", 800),
+		fixtures.SubmittedClaim("c4", 300).Rejected(),
+		fixtures.SubmittedClaim("c5", 200),
+	} {
+		claims[c.ClaimID] = c
+	}
+	lookup := func(claimID string) (string, uint64, bool) {
+		c, ok := claims[claimID]
+		return c.Status, c.RequestedAmount, ok
+	}
+	existing, _, _ := appendRoundClaim(nil, "c0")
+
+	decisions := []reviewDecision{
+		{ClaimID: "c1", Decision: DECISION_APPROVE, ApprovedAmount: 1500},
+		{ClaimID: "c2", Decision: DECISION_APPROVE, ApprovedAmount: 500},
+		{ClaimID: "c3", Decision: DECISION_REJECT, ApprovedAmount: 800},
+		{ClaimID: "c4", Decision: DECISION_APPROVE, ApprovedAmount: 300},
+		{ClaimID: "c9", Decision: DECISION_APPROVE, ApprovedAmount: 100},
+		{ClaimID: "c1", Decision: DECISION_REJECT},
+		{ClaimID: "c5", Decision: "MAYBE"},
+		{ClaimID: "c5", Decision: DECISION_APPROVE, ApprovedAmount: 150},
+	}
+	results, index, count := planBatchReview(decisions, lookup, existing, nil)
+
+	want := []struct {
+		outcome, skipReason, status string
+		approved                    uint64
+	}{
+		{BATCH_REVIEW_APPLIED, "", CLAIM_STATUS_APPROVED, 1000},
+		{BATCH_REVIEW_SKIPPED, SKIP_REASON_NOT_SUBMITTED, "", 0},
+		{BATCH_REVIEW_APPLIED, "", CLAIM_STATUS_REJECTED, 0},
+		{BATCH_REVIEW_SKIPPED, SKIP_REASON_NOT_SUBMITTED, "", 0},
+		{BATCH_REVIEW_SKIPPED, SKIP_REASON_NOT_FOUND, "", 0},
+		{BATCH_REVIEW_SKIPPED, SKIP_REASON_DUPLICATE, "", 0},
+		{BATCH_REVIEW_SKIPPED, SKIP_REASON_INVALID_DECISION, "", 0},
+		{BATCH_REVIEW_APPLIED, "", CLAIM_STATUS_APPROVED, 150},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Outcome != w.outcome || r.SkipReason != w.skipReason || r.NewStatus != w.status || r.ApprovedAmount != w.approved {
+			t.Errorf("results[%d] = %+v, want %+v", i, r, w)
+		}
+	}
+
+	// 仅批准的案件追加到索引，原有案件保留
+	if count != 3 {
+		t.Errorf("round claims count = %d, want 3", count)
+	}
+	if got := fmt.Sprint(decodeRoundClaims(index)); got != "[c0 c1 c5]" {
+		t.Errorf("round claims = %s, want [c0 c1 c5]", got)
+	}
+}
+
+// TestPlanBatchReviewRoundClaimsFull 测试轮次索引已满时批准项被跳过、拒绝项仍应用
+func TestPlanBatchReviewRoundClaimsFull(t *testing.T) {
+	var full []byte
+	for i := 0; i < MAX_ROUND_CLAIMS; i++ {
+		full, _, _ = appendRoundClaim(full, fmt.Sprintf("old_%d", i))
+	}
+	lookup := func(claimID string) (string, uint64, bool) {
+		c := fixtures.SubmittedClaim(claimID, 100)
+		return c.Status, c.RequestedAmount, true
+	}
+
+	results, _, count := planBatchReview([]reviewDecision{
+		{ClaimID: "a", Decision: DECISION_APPROVE, ApprovedAmount: 100},
+		{ClaimID: "b", Decision: DECISION_REJECT},
+	}, lookup, full, nil)
+
+	if results[0].SkipReason != SKIP_REASON_ROUND_CLAIMS_FULL || results[1].Outcome != BATCH_REVIEW_APPLIED {
+		t.Errorf("results = %+v", results)
+	}
+	if count != MAX_ROUND_CLAIMS {
+		t.Errorf("count = %d, want %d", count, MAX_ROUND_CLAIMS)
+	}
+}
+
+// TestParseReviewDecisions 测试 decisions 数组解析
+func TestParseReviewDecisions(t *testing.T) {
+	raw := `{"plan_id":"p1","decisions": [
+		{"claim_id": "c1", "decision": "APPROVE", "approved_amount": 280000, "reason": "ok, \"{fine}\""},
+		{"decision":"REJECT","claim_id":"c2","reason":"no"}
+	],"review_round_id":"r1"}`
+
+	decisions, ok := parseReviewDecisions(raw)
+	if !ok {
+		t.Fatal("parseReviewDecisions() failed")
+	}
+	want := []reviewDecision{
+		{ClaimID: "c1", Decision: DECISION_APPROVE, ApprovedAmount: 280000, Reason: `ok, "{fine}"`},
+		{ClaimID: "c2", Decision: DECISION_REJECT, Reason: "no"},
+	}
+	if fmt.Sprint(decisions) != fmt.Sprint(want) {
+		t.Errorf("decisions = %+v, want %+v", decisions, want)
+	}
+
+	for _, bad := range []string{
+		`{"plan_id":"p1"}`,
+		`{"decisions":"c1"}`,
+		`{"decisions":[{"claim_id":"c1"}`,
+		`{"decisions":[{"claim_id":"c1","decision":"APPROVE","approved_amount":18446744073709551616}]}`,
+		`{"decisions":[{"claim_id":"c1","decision":"APPROVE","approved_amount":-1}]}`,
+	} {
+		if _, ok := parseReviewDecisions(bad); ok {
+			t.Errorf("parseReviewDecisions(%s) should fail", bad)
+		}
+	}
+}
+
+// TestReconcileActiveCount 测试被错误调整的活跃成员数按成员记录校正为真实值
+func TestReconcileActiveCount(t *testing.T) {
+	members := []fixtures.Member{
+		fixtures.ActiveMember(90).For(fixtures.Alice()).ActivatedAt(1),
+		fixtures.ActiveMember(60).For(fixtures.Bob()).ActivatedAt(2).Exited(),
+		fixtures.ActiveMember(30).For(fixtures.Carol()).ActivatedAt(3),
+		fixtures.ActiveMember(1).For(fixtures.Pool()).Pending(),
+	}
+	statuses := make([]string, len(members))
+	for i, m := range members {
+		statuses[i] = m.Status
+	}
+
+	// Bob 退出时计数未减少，被多计一人
+	if actual, corrected := reconcileActiveCount(3, statuses); actual != 2 || !corrected {
+		t.Errorf("reconcileActiveCount(over-counted) = %d, %v, want 2, true", actual, corrected)
+	}
+	// 计数被减为0（如重复退出）
+	if actual, corrected := reconcileActiveCount(0, statuses); actual != 2 || !corrected {
+		t.Errorf("reconcileActiveCount(under-counted) = %d, %v, want 2, true", actual, corrected)
+	}
+	if actual, corrected := reconcileActiveCount(2, statuses); actual != 2 || corrected {
+		t.Errorf("reconcileActiveCount(consistent) = %d, %v, want 2, false", actual, corrected)
+	}
+}
+
+// TestMemberIndexPaging 测试成员索引跨页追加与解码
+func TestMemberIndexPaging(t *testing.T) {
+	if page, offset := memberIndexPageOf(MEMBER_INDEX_PAGE_SIZE + 3); page != 1 || offset != 3 {
+		t.Errorf("memberIndexPageOf() = %d, %d, want 1, 3", page, offset)
+	}
+	if got := memberIndexPageEntries(MEMBER_INDEX_PAGE_SIZE+3, 0); got != MEMBER_INDEX_PAGE_SIZE {
+		t.Errorf("entries(page 0) = %d, want %d", got, MEMBER_INDEX_PAGE_SIZE)
+	}
+	if got := memberIndexPageEntries(MEMBER_INDEX_PAGE_SIZE+3, 1); got != 3 {
+		t.Errorf("entries(page 1) = %d, want 3", got)
+	}
+
+	// 以 0x00 结尾的地址在链上读取时会被裁剪
+	trailingZero := fixtures.Bob()
+	trailingZero[19] = 0
+
+	alice := fixtures.Alice()
+	page, ok := appendMemberIndexPage(nil, 0, alice[:])
+	if !ok {
+		t.Fatal("appendMemberIndexPage(empty) failed")
+	}
+	page, ok = appendMemberIndexPage(page, 1, trailingZero[:])
+	if !ok {
+		t.Fatal("appendMemberIndexPage() failed")
+	}
+
+	for name, data := range map[string][]byte{"exact": page, "trimmed": page[:len(page)-1], "padded": append(page, make([]byte, 100)...)} {
+		addrs := decodeMemberIndexPage(data, 2)
+		if len(addrs) != 2 || string(addrs[0]) != string(alice[:]) || string(addrs[1]) != string(trailingZero[:]) {
+			t.Errorf("decodeMemberIndexPage(%s) = %x", name, addrs)
+		}
+	}
+
+	full := make([]byte, MEMBER_INDEX_PAGE_SIZE*MEMBER_ADDRESS_SIZE)
+	if _, ok := appendMemberIndexPage(full, MEMBER_INDEX_PAGE_SIZE, alice[:]); ok {
+		t.Error("appendMemberIndexPage(full page) should fail")
+	}
+	if _, ok := appendMemberIndexPage(nil, 0, alice[:19]); ok {
+		t.Error("appendMemberIndexPage(19-byte address) should fail")
+	}
+}
+
+// TestOffchainRecordDuplicateReference 测试同一凭证重复登记被拒绝，线下金额单独计入
+func TestOffchainRecordDuplicateReference(t *testing.T) {
+	due := roundDue{DueAmount: 600, PaidAmount: 100}
+
+	recorded, reject := planOffchainRecord(false, due, 500)
+	if reject != "" {
+		t.Fatalf("planOffchainRecord() reject = %s", reject)
+	}
+	want := roundDue{DueAmount: 600, PaidAmount: 600, OffchainPaid: 500, Settled: true, Flags: DUE_FLAG_OFFCHAIN}
+	if recorded != want {
+		t.Errorf("recorded = %+v, want %+v", recorded, want)
+	}
+	if recorded.onchainPaid() != 100 {
+		t.Errorf("onchainPaid() = %d, want 100", recorded.onchainPaid())
+	}
+
+	// 同一 reference_hash 再次登记
+	if got, reject := planOffchainRecord(true, due, 500); reject != OFFCHAIN_REJECT_DUPLICATE_REFERENCE || got != due {
+		t.Errorf("duplicate reference = (%+v, %q), want unchanged and %s", got, reject, OFFCHAIN_REJECT_DUPLICATE_REFERENCE)
+	}
+	// 已结清的应缴不再接受登记
+	if _, reject := planOffchainRecord(false, recorded, 1); reject != OFFCHAIN_REJECT_SETTLED {
+		t.Errorf("record on settled due reject = %q, want %s", reject, OFFCHAIN_REJECT_SETTLED)
+	}
+	if _, reject := applyContribution(roundDue{DueAmount: 1, PaidAmount: ^uint64(0) - 1}, 2, false); reject != OFFCHAIN_REJECT_OVERFLOW {
+		t.Errorf("overflowing contribution reject = %q, want %s", reject, OFFCHAIN_REJECT_OVERFLOW)
+	}
+}
+
+// TestOffchainReversalAfterRoundClosed 测试轮次关闭后冲正：重新打开应缴并计入新增欠费
+func TestOffchainReversalAfterRoundClosed(t *testing.T) {
+	// 链上缴 100 + 线下登记 500，结清 600 的应缴
+	due, _ := applyContribution(roundDue{DueAmount: 600}, 100, false)
+	due, _ = planOffchainRecord(false, due, 500)
+
+	reversed, arrears, reject := planOffchainReversal(OFFCHAIN_STATUS_VERIFIED, due, 500, true)
+	if reject != "" {
+		t.Fatalf("planOffchainReversal() reject = %s", reject)
+	}
+	want := roundDue{DueAmount: 600, PaidAmount: 100, Settled: false, Flags: DUE_FLAG_REVERSED}
+	if reversed != want {
+		t.Errorf("reversed = %+v, want %+v", reversed, want)
+	}
+	if arrears != 500 {
+		t.Errorf("arrearsDelta = %d, want 500", arrears)
+	}
+
+	// 轮次未关闭时冲正不产生欠费，成员仍可继续缴费
+	if _, arrears, _ := planOffchainReversal(OFFCHAIN_STATUS_RECORDED, due, 500, false); arrears != 0 {
+		t.Errorf("arrearsDelta before close = %d, want 0", arrears)
+	}
+	// 部分冲正后仍有线下金额，保留 OFFCHAIN 标志
+	partial, _ := planOffchainRecord(false, roundDue{DueAmount: 600}, 300)
+	partial, _ = planOffchainRecord(false, partial, 200)
+	partial, arrears, _ = planOffchainReversal(OFFCHAIN_STATUS_RECORDED, partial, 200, true)
+	if partial.Flags != DUE_FLAG_OFFCHAIN|DUE_FLAG_REVERSED || partial.OffchainPaid != 300 || arrears != 200 {
+		t.Errorf("partial reversal = %+v (arrears %d)", partial, arrears)
+	}
+
+	// 已冲正的登记不能再次冲正，扣回金额不能超过线下已缴
+	if _, _, reject := planOffchainReversal(OFFCHAIN_STATUS_REVERSED, due, 500, true); reject != OFFCHAIN_REJECT_INVALID_TRANSITION {
+		t.Errorf("double reversal reject = %q, want %s", reject, OFFCHAIN_REJECT_INVALID_TRANSITION)
+	}
+	if _, _, reject := planOffchainReversal(OFFCHAIN_STATUS_RECORDED, due, 501, true); reject != OFFCHAIN_REJECT_OVERFLOW {
+		t.Errorf("over-reversal reject = %q, want %s", reject, OFFCHAIN_REJECT_OVERFLOW)
+	}
+}
+
+// TestCanReconcileOffchain 测试线下缴费对账状态迁移
+func TestCanReconcileOffchain(t *testing.T) {
+	tests := []struct {
+		status, resolution string
+		want               bool
+	}{
+		{OFFCHAIN_STATUS_RECORDED, OFFCHAIN_STATUS_VERIFIED, true},
+		{OFFCHAIN_STATUS_RECORDED, OFFCHAIN_STATUS_REVERSED, true},
+		{OFFCHAIN_STATUS_VERIFIED, OFFCHAIN_STATUS_REVERSED, true},
+		{OFFCHAIN_STATUS_VERIFIED, OFFCHAIN_STATUS_VERIFIED, false},
+		{OFFCHAIN_STATUS_REVERSED, OFFCHAIN_STATUS_VERIFIED, false},
+		{OFFCHAIN_STATUS_RECORDED, OFFCHAIN_STATUS_RECORDED, false},
+	}
+	for _, tt := range tests {
+		if got := canReconcileOffchain(tt.status, tt.resolution); got != tt.want {
+			t.Errorf("canReconcileOffchain(%s, %s) = %v, want %v", tt.status, tt.resolution, got, tt.want)
+		}
+	}
+}
+
+// memberSetStore 模拟 members_active 分页存储，按 main.go 的 addActiveMember / removeActiveMember 流程操作
+type memberSetStore struct {
+	pages map[uint64][]byte
+	total uint64
+	pos   map[string]uint64 // 位置+1
+}
+
+func newMemberSetStore() *memberSetStore {
+	return &memberSetStore{pages: map[uint64][]byte{}, pos: map[string]uint64{}}
+}
+
+func (s *memberSetStore) add(t *testing.T, addr []byte) {
+	t.Helper()
+	if s.pos[string(addr)] > 0 {
+		return
+	}
+	page, offset := memberIndexPageOf(s.total)
+	data, ok := appendMemberIndexPage(s.pages[page], int(offset), addr)
+	if !ok {
+		t.Fatalf("appendMemberIndexPage(%d) failed", s.total)
+	}
+	s.pages[page] = data
+	s.total++
+	s.pos[string(addr)] = s.total
+}
+
+func (s *memberSetStore) remove(addr []byte) {
+	pos := s.pos[string(addr)]
+	if pos == 0 {
+		return
+	}
+	if moved := removeFromMemberSet(s.pages, s.total, pos-1); moved != nil {
+		s.pos[string(moved)] = pos
+	}
+	delete(s.pos, string(addr))
+	s.total--
+}
+
+func (s *memberSetStore) list() [][]byte {
+	var addrs [][]byte
+	for page := uint64(0); page*MEMBER_INDEX_PAGE_SIZE < s.total; page++ {
+		addrs = append(addrs, decodeMemberIndexPage(s.pages[page], memberIndexPageEntries(s.total, page))...)
+	}
+	return addrs
+}
+
+// TestListMembersActiveVsAll 测试成员索引与活跃成员集合的列表：退出成员只出现在全部成员中
+func TestListMembersActiveVsAll(t *testing.T) {
+	alice, bob, carol := fixtures.Alice(), fixtures.Bob(), fixtures.Carol()
+	statuses := map[string]string{
+		string(alice[:]): MEMBER_STATUS_ACTIVE,
+		string(bob[:]):   MEMBER_STATUS_EXITED,
+		string(carol[:]): MEMBER_STATUS_ACTIVE,
+	}
+	statusOf := func(addr []byte) string { return statuses[string(addr)] }
+
+	// Join 追加到 members_all；ApproveMember 加入、Exit 移出 members_active
+	all := [][]byte{alice[:], bob[:], carol[:]}
+	active := newMemberSetStore()
+	for _, addr := range all {
+		active.add(t, addr)
+	}
+	active.add(t, alice[:]) // 重复加入无效
+	active.remove(bob[:])
+
+	tests := []struct {
+		name   string
+		addrs  [][]byte
+		filter string
+		offset uint64
+		limit  uint64
+		want   []string
+		total  uint64
+	}{
+		{"all", all, "", 0, 10, []string{"alice", "bob", "carol"}, 3},
+		{"all paged", all, "", 1, 1, []string{"bob"}, 3},
+		{"active set", active.list(), "", 0, 10, []string{"alice", "carol"}, 2},
+		{"index filtered by ACTIVE", all, MEMBER_STATUS_ACTIVE, 0, 10, []string{"alice", "carol"}, 2},
+		{"index filtered by EXITED", all, MEMBER_STATUS_EXITED, 0, 10, []string{"bob"}, 1},
+		{"offset past end", all, MEMBER_STATUS_ACTIVE, 2, 10, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, total := filterMemberList(tt.addrs, statusOf, tt.filter, tt.offset, tt.limit)
+			var got []string
+			for _, e := range entries {
+				var addr [20]byte
+				copy(addr[:], e.Address)
+				got = append(got, fixtures.Name(addr))
+				if e.Status != statusOf(e.Address) {
+					t.Errorf("entry %s status = %s", fixtures.Name(addr), e.Status)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) || total != tt.total {
+				t.Errorf("filterMemberList() = %v (total %d), want %v (total %d)", got, total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+// TestActiveMemberSetAcrossPages 测试跨页交换删除后集合仍保持完整且位置记录正确
+func TestActiveMemberSetAcrossPages(t *testing.T) {
+	set := newMemberSetStore()
+	n := MEMBER_INDEX_PAGE_SIZE + 3
+	for i := 0; i < n; i++ {
+		set.add(t, []byte(fmt.Sprintf("member-%013d", i)))
+	}
+
+	// 删除第一页的成员，由第二页的最后一个成员补位
+	set.remove([]byte(fmt.Sprintf("member-%013d", 0)))
+	set.remove([]byte(fmt.Sprintf("member-%013d", n-2)))
+	set.remove([]byte(fmt.Sprintf("member-%013d", n-1))) // 已被移动过，不在末尾
+
+	addrs := set.list()
+	if uint64(len(addrs)) != set.total || len(addrs) != n-3 {
+		t.Fatalf("len(list) = %d, total = %d, want %d", len(addrs), set.total, n-3)
+	}
+	seen := map[string]bool{}
+	for i, addr := range addrs {
+		if set.pos[string(addr)] != uint64(i+1) {
+			t.Errorf("pos[%s] = %d, want %d", addr, set.pos[string(addr)], i+1)
+		}
+		seen[string(addr)] = true
+	}
+	for _, removed := range []int{0, n - 2, n - 1} {
+		if seen[fmt.Sprintf("member-%013d", removed)] {
+			t.Errorf("member-%d still listed after removal", removed)
+		}
+	}
+	if got := memberListLimit(0); got != MEMBER_LIST_DEFAULT_LIMIT {
+		t.Errorf("memberListLimit(0) = %d", got)
+	}
+	if got := memberListLimit(1000); got != MEMBER_LIST_MAX_LIMIT {
+		t.Errorf("memberListLimit(1000) = %d", got)
+	}
+	if claimListLimit(0) != CLAIM_LIST_DEFAULT_LIMIT || claimListLimit(7) != 7 || claimListLimit(1<<40) != CLAIM_LIST_MAX_LIMIT {
+		t.Errorf("claimListLimit() = %d / %d / %d", claimListLimit(0), claimListLimit(7), claimListLimit(1<<40))
+	}
+}
+
+// TestEvidenceQuota 测试案件附件配额：16 条 256 字节附件通过，第 17 条与超长单条被拒绝
+func TestEvidenceQuota(t *testing.T) {
+	defer framework.DeclareWriteBudget(0)
+
+	claimID := strings.Repeat("c", 64)
+	alice := fixtures.Alice()
+	partition := evidencePartition("plan_001", claimID, alice[:])
+	attachment := []byte(strings.Repeat("a", MAX_EVIDENCE_BYTES))
+
+	// 合法的最大附件在单次调用写入预算内
+	for i := 0; i < MAX_EVIDENCE_PER_CLAIM; i++ {
+		framework.DeclareWriteBudget(ATTACH_EVIDENCE_WRITE_BUDGET)
+		if err := framework.CheckIndexQuota(EVIDENCE_INDEX, uint64(i), len(attachment)); err != nil {
+			t.Fatalf("attachment %d: CheckIndexQuota() error = %v", i+1, err)
+		}
+		if _, err := framework.AppendIndexEntry(EVIDENCE_INDEX, partition, attachment); err != nil {
+			t.Fatalf("attachment %d: AppendIndexEntry() error = %v", i+1, err)
+		}
+	}
+
+	if err := framework.CheckIndexQuota(EVIDENCE_INDEX, MAX_EVIDENCE_PER_CLAIM, 1); quotaCode(err) != framework.ERROR_QUOTA_EXCEEDED {
+		t.Errorf("17th attachment error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+
+	framework.DeclareWriteBudget(ATTACH_EVIDENCE_WRITE_BUDGET)
+	oversized := append(attachment, 'x')
+	if _, err := framework.AppendIndexEntry(EVIDENCE_INDEX, partition, oversized); quotaCode(err) != framework.ERROR_QUOTA_EXCEEDED {
+		t.Errorf("oversized attachment error = %v, want ERROR_QUOTA_EXCEEDED", err)
+	}
+	if _, used := framework.WriteBudget(); used != 0 {
+		t.Errorf("rejected attachment staged %d bytes, want 0", used)
+	}
+}
+
+// TestCanAttachEvidence 测试附件仅可在审核结束前追加
+func TestCanAttachEvidence(t *testing.T) {
+	for status, want := range map[string]bool{
+		CLAIM_STATUS_SUBMITTED:    true,
+		CLAIM_STATUS_UNDER_REVIEW: true,
+		CLAIM_STATUS_APPROVED:     false,
+		CLAIM_STATUS_REJECTED:     false,
+		CLAIM_STATUS_PAID:         false,
+	} {
+		if got := canAttachEvidence(status); got != want {
+			t.Errorf("canAttachEvidence(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+// TestClaimCancellable 测试申请人与 operator 可取消的案件状态
+func TestClaimCancellable(t *testing.T) {
+	tests := []struct {
+		status            string
+		applicant, byOper bool
+	}{
+		{CLAIM_STATUS_SUBMITTED, true, true},
+		{CLAIM_STATUS_UNDER_REVIEW, true, true},
+		{CLAIM_STATUS_APPROVED, false, true},
+		{CLAIM_STATUS_REJECTED, false, false},
+		{CLAIM_STATUS_PAID, false, false},
+		{CLAIM_STATUS_CANCELLED, false, false},
+	}
+	for _, tt := range tests {
+		if got := claimCancellable(tt.status, false); got != tt.applicant {
+			t.Errorf("claimCancellable(%s, applicant) = %v, want %v", tt.status, got, tt.applicant)
+		}
+		if got := claimCancellable(tt.status, true); got != tt.byOper {
+			t.Errorf("claimCancellable(%s, operator) = %v, want %v", tt.status, got, tt.byOper)
+		}
+	}
+}
+
+// TestRoundTotalAfterCancel 测试取消已批准案件时轮次批准总额的调整：OPEN 轮次不调整，已结算轮次扣除
+func TestRoundTotalAfterCancel(t *testing.T) {
+	tests := []struct {
+		roundStatus   string
+		total, amount uint64
+		want          uint64
+		changed, ok   bool
+	}{
+		{ROUND_STATUS_OPEN, 0, 60000, 0, false, true},
+		{ROUND_STATUS_SETTLED, 90000, 60000, 30000, true, true},
+		{ROUND_STATUS_SETTLED, 90000, 0, 90000, false, true},
+		{ROUND_STATUS_SETTLED, 50000, 60000, 50000, false, false},
+	}
+	for _, tt := range tests {
+		got, changed, ok := roundTotalAfterCancel(tt.roundStatus, tt.total, tt.amount)
+		if got != tt.want || changed != tt.changed || ok != tt.ok {
+			t.Errorf("roundTotalAfterCancel(%s, %d, %d) = %d, %v, %v, want %d, %v, %v",
+				tt.roundStatus, tt.total, tt.amount, got, changed, ok, tt.want, tt.changed, tt.ok)
+		}
+	}
+}
+
+// quotaCode 提取合约错误码，非合约错误返回 SUCCESS
+func quotaCode(err error) uint32 {
+	if ce, ok := err.(*framework.ContractError); ok {
+		return ce.Code
+	}
+	return framework.SUCCESS
+}
+
+// payoutSummaryEvent 构造轮次给付明细事件（与 SettleRound 字段一致）
+func payoutSummaryEvent(claims int) *framework.Event {
+	claimIDs := make([]string, claims)
+	for i := range claimIDs {
+		claimIDs[i] = fmt.Sprintf("claim_202501_%04d", i)
+	}
+	items := payoutSummaryItems(claimIDs, func(claimID string) (uint64, bool) {
+		return 280000, claimID != claimIDs[0]
+	})
+	event := framework.NewEvent(EVENT_ROUND_PAYOUT_SUMMARY)
+	event.Data["plan_id"] = "plan_xianghubao_001"
+	event.Data["round_id"] = "round_202501_01"
+	event.Data["status"] = ROUND_STATUS_SETTLED
+	event.Data["claims_count"] = uint64(len(claimIDs))
+	event.Data["claims"] = items
+	return event
+}
+
+// TestRoundPayoutSummaryEmission 测试给付明细：少量案件直接发出，满额索引时锚定
+func TestRoundPayoutSummaryEmission(t *testing.T) {
+	small, err := framework.PlanEventEmission(payoutSummaryEvent(3))
+	if err != nil {
+		t.Fatalf("PlanEventEmission(small) error = %v", err)
+	}
+	if small.Anchored {
+		t.Errorf("3-claim summary anchored at %d bytes, want direct emit", len(small.Payload))
+	}
+	if !strings.Contains(string(small.Payload), `{"approved_amount":0,"claim_id":"claim_202501_0000","missing":true}`) {
+		t.Errorf("missing claim not marked in payload: %s", small.Payload)
+	}
+
+	large, err := framework.PlanEventEmission(payoutSummaryEvent(MAX_ROUND_CLAIMS))
+	if err != nil {
+		t.Fatalf("PlanEventEmission(large) error = %v", err)
+	}
+	if !large.Anchored {
+		t.Fatalf("%d-claim summary not anchored at %d bytes", MAX_ROUND_CLAIMS, len(large.Payload))
+	}
+	if large.Anchor.Data["event"] != EVENT_ROUND_PAYOUT_SUMMARY || large.Anchor.Data["payload_size"] != uint64(len(large.Payload)) {
+		t.Errorf("anchor data = %v", large.Anchor.Data)
+	}
+	if !strings.Contains(string(large.Payload), fmt.Sprintf("claim_202501_%04d", MAX_ROUND_CLAIMS-1)) {
+		t.Error("anchored payload is missing the last claim")
+	}
+}
+
+// TestBatchReviewEventEmission 测试批量审核事件：小批次直接发出，满批次含完整结果时锚定
+func TestBatchReviewEventEmission(t *testing.T) {
+	lookup := func(string) (string, uint64, bool) { return CLAIM_STATUS_SUBMITTED, 300000, true }
+	build := func(n int) *framework.Event {
+		decisions := make([]reviewDecision, n)
+		for i := range decisions {
+			decisions[i] = reviewDecision{ClaimID: fmt.Sprintf("claim_202501_%04d", i), Decision: DECISION_REJECT, Reason: "不在保障范围"}
+		}
+		results, _, _ := planBatchReview(decisions, lookup, nil, nil)
+		event := framework.NewEvent(EVENT_CLAIMS_BATCH_REVIEWED)
+		event.Data["plan_id"] = "plan_xianghubao_001"
+		event.Data["applied_count"] = uint64(n)
+		event.Data["results"] = batchReviewItems(results)
+		return event
+	}
+
+	if plan, err := framework.PlanEventEmission(build(2)); err != nil || plan.Anchored {
+		t.Errorf("2-item batch: anchored = %v, err = %v, want direct emit", plan.Anchored, err)
+	}
+	plan, err := framework.PlanEventEmission(build(MAX_BATCH_REVIEW_SIZE))
+	if err != nil || !plan.Anchored {
+		t.Fatalf("%d-item batch: anchored = %v, err = %v, want anchored", MAX_BATCH_REVIEW_SIZE, plan.Anchored, err)
+	}
+	if got := strings.Count(string(plan.Payload), `"outcome":"APPLIED"`); got != MAX_BATCH_REVIEW_SIZE {
+		t.Errorf("anchored payload has %d results, want %d", got, MAX_BATCH_REVIEW_SIZE)
+	}
+	if _, ok := framework.GetEventSchema(EVENT_CLAIMS_BATCH_REVIEWED); !ok {
+		t.Error("batch review event schema not registered")
+	}
+}
+
+// TestRoundingModes 测试不能整除时各取整方式的人均分摊与取整余额
+func TestRoundingModes(t *testing.T) {
+	weight := uint64(3 * TIER_MULTIPLIER_BASE_BP) // 3 名基准档成员
+
+	tests := []struct {
+		name        string
+		payout      uint64
+		rounding    roundingConfig
+		wantPer     uint64
+		wantSurplus int64
+	}{
+		{"up 100/3", 100, roundingConfig{Mode: ROUNDING_MODE_UP}, 34, 2},
+		{"nearest 100/3", 100, roundingConfig{Mode: ROUNDING_MODE_NEAREST}, 33, -1},
+		{"down 100/3", 100, roundingConfig{Mode: ROUNDING_MODE_DOWN}, 33, -1},
+		{"up 200/3", 200, roundingConfig{Mode: ROUNDING_MODE_UP}, 67, 1},
+		{"nearest 200/3", 200, roundingConfig{Mode: ROUNDING_MODE_NEAREST}, 67, 1},
+		{"down 200/3", 200, roundingConfig{Mode: ROUNDING_MODE_DOWN}, 66, -2},
+		{"up 2 decimals", 100000, roundingConfig{Mode: ROUNDING_MODE_UP, Decimals: 2}, 33400, 200},
+		{"nearest 2 decimals", 100000, roundingConfig{Mode: ROUNDING_MODE_NEAREST, Decimals: 2}, 33300, -100},
+		{"down 2 decimals", 100000, roundingConfig{Mode: ROUNDING_MODE_DOWN, Decimals: 2}, 33300, -100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustRoundedSettlement(t, tt.payout, 0, weight, tt.rounding, 0)
+			if s.PerCapita != tt.wantPer || s.Surplus != tt.wantSurplus {
+				t.Errorf("per capita = %d, surplus = %d, want %d, %d", s.PerCapita, s.Surplus, tt.wantPer, tt.wantSurplus)
+			}
+			if int64(s.PerCapita*3)-int64(tt.payout) != s.Surplus {
+				t.Errorf("surplus %d does not match collected - target", s.Surplus)
+			}
+		})
+	}
+
+	// 默认取整为向上取整
+	if perCapita := mustRoundedSettlement(t, 100, 0, weight, defaultRounding, 0).PerCapita; perCapita != 34 {
+		t.Errorf("default rounding per capita = %d, want 34 (UP)", perCapita)
+	}
+}
+
+// mustRoundedSettlement 计算轮次结算，金额溢出时测试失败
+func mustRoundedSettlement(t *testing.T, payout, serviceFeeBP, totalWeightBP uint64, rounding roundingConfig, carry int64) roundSettlement {
+	t.Helper()
+	s, ok := computeRoundedSettlement(payout, serviceFeeBP, totalWeightBP, rounding, carry)
+	if !ok {
+		t.Fatalf("computeRoundedSettlement(%d, %d, %d) overflowed", payout, serviceFeeBP, totalWeightBP)
+	}
+	return s
+}
+
+// mustNextCarry 计算结算后的累计取整余额，超过 int64 时测试失败
+func mustNextCarry(t *testing.T, carry int64, s roundSettlement) int64 {
+	t.Helper()
+	next, ok := nextRoundingCarry(carry, s)
+	if !ok {
+		t.Fatalf("nextRoundingCarry(%d, %+v) overflowed", carry, s)
+	}
+	return next
+}
+
+// TestRoundedSettlementOverflow 测试应收总额、人均分摊与实收总额在 uint64 边界附近的计算
+func TestRoundedSettlementOverflow(t *testing.T) {
+	const maxUint64 = ^uint64(0)
+	up := roundingConfig{Mode: ROUNDING_MODE_UP}
+
+	// payout * 10000 超过 uint64，但人均分摊仍可表示：按 128 位中间结果计算；
+	// 实收总额 2^63 超过 int64，取整余额按无符号差计算为 1
+	big := maxUint64 / 2
+	s := mustRoundedSettlement(t, big, 0, 2*TIER_MULTIPLIER_BASE_BP, up, 0)
+	if s.TotalWithFee != big || s.PerCapita != big/2+1 || s.Surplus != 1 {
+		t.Errorf("large settlement = %+v, want total %d, per capita %d, surplus 1", s, big, big/2+1)
+	}
+	// 应收与实收都超过 int64：向下取整的缺口同样按无符号差计算
+	near := maxUint64 - 1
+	s = mustRoundedSettlement(t, near, 0, 3*TIER_MULTIPLIER_BASE_BP, roundingConfig{Mode: ROUNDING_MODE_DOWN}, 0)
+	if s.PerCapita != near/3 || s.Surplus != -2 {
+		t.Errorf("large shortfall settlement = %+v, want per capita %d, surplus -2", s, near/3)
+	}
+	if per, ok := roundPerCapita(maxUint64, TIER_MULTIPLIER_BASE_BP, ROUNDING_MODE_DOWN, 0); !ok || per != maxUint64 {
+		t.Errorf("roundPerCapita(max, 1 member) = %d, %v, want %d, true", per, ok, maxUint64)
+	}
+
+	tests := []struct {
+		name     string
+		payout   uint64
+		feeBP    uint64
+		weight   uint64
+		rounding roundingConfig
+		carry    int64
+	}{
+		// 含服务费的应收总额超过 uint64
+		{"total with fee", maxUint64, 800, TIER_MULTIPLIER_BASE_BP, up, 0},
+		// 补收结转缺口后应收总额超过 uint64
+		{"carried shortfall", maxUint64, 0, TIER_MULTIPLIER_BASE_BP, up, -1},
+		// 档位系数之和小于基准时人均分摊超过应收总额并溢出
+		{"per capita", maxUint64, 0, TIER_MULTIPLIER_BASE_BP / 2, up, 0},
+		// 向上取整到 10^6 后人均分摊超过 uint64
+		{"rounded per capita", maxUint64, 0, TIER_MULTIPLIER_BASE_BP, roundingConfig{Mode: ROUNDING_MODE_UP, Decimals: 6}, 0},
+		// 向上取整后实收总额超过 uint64
+		{"collected", maxUint64, 0, 7 * TIER_MULTIPLIER_BASE_BP, up, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, ok := computeRoundedSettlement(tt.payout, tt.feeBP, tt.weight, tt.rounding, tt.carry); ok {
+				t.Errorf("computeRoundedSettlement() = %+v, true, want overflow", s)
+			}
+		})
+	}
+
+	in := roundSettlementInputs{
+		ClaimIDs:       []string{"claim_1"},
+		ApprovedAmount: func(string) (uint64, bool) { return maxUint64, true },
+		ServiceFeeBP:   800,
+		FeeMode:        FEE_MODE_FIXED,
+		MemberCount:    1,
+		TotalWeight:    TIER_MULTIPLIER_BASE_BP,
+		Rounding:       defaultRounding,
+	}
+	if plan, ok := planRoundSettlement(in); ok {
+		t.Errorf("planRoundSettlement() = %+v, true, want overflow", plan)
+	}
+
+	// 累计取整余额超过 int64：只记录不结转时，已接近下界的余额再计入缺口
+	in.ApprovedAmount = func(string) (uint64, bool) { return 100, true }
+	in.ServiceFeeBP = 0
+	in.TotalWeight = 3 * TIER_MULTIPLIER_BASE_BP
+	in.Rounding = roundingConfig{Mode: ROUNDING_MODE_DOWN}
+	in.StoredCarry = math.MinInt64
+	if plan, ok := planRoundSettlement(in); ok {
+		t.Errorf("planRoundSettlement(carry at min int64) = %+v, true, want overflow", plan)
+	}
+	in.StoredCarry = math.MinInt64 + 1
+	if plan, ok := planRoundSettlement(in); !ok || plan.Carry != math.MinInt64 {
+		t.Errorf("planRoundSettlement(carry at min int64 + 1) = %+v, %v, want carry %d", plan, ok, int64(math.MinInt64))
+	}
+}
+
+// TestSignedDiffBounds 测试取整余额的有符号差在 int64 边界上可以表示，超出时拒绝
+func TestSignedDiffBounds(t *testing.T) {
+	const maxUint64 = ^uint64(0)
+	tests := []struct {
+		a, b   uint64
+		want   int64
+		wantOK bool
+	}{
+		{math.MaxInt64, 0, math.MaxInt64, true},
+		{0, math.MaxInt64, -math.MaxInt64, true},
+		{maxUint64, math.MaxInt64 + 1, math.MaxInt64, true},
+		{math.MaxInt64 + 1, 0, 0, false},
+		{0, math.MaxInt64 + 1, 0, false},
+		{maxUint64, 0, 0, false},
+		{1 << 63, 1<<63 - 1, 1, true},
+		{1<<63 - 1, 1 << 63, -1, true},
+	}
+	for _, tt := range tests {
+		if got, ok := signedDiff(tt.a, tt.b); got != tt.want || ok != tt.wantOK {
+			t.Errorf("signedDiff(%d, %d) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if _, ok := addInt64Checked(math.MaxInt64, 1); ok {
+		t.Error("addInt64Checked(MaxInt64, 1) should overflow")
+	}
+	if sum, ok := addInt64Checked(math.MinInt64+1, -1); !ok || sum != math.MinInt64 {
+		t.Errorf("addInt64Checked(MinInt64+1, -1) = %d, %v, want MinInt64, true", sum, ok)
+	}
+}
+
+// TestRoundingCarryForward 测试取整余额结转：盈余抵扣下一轮应收，缺口在下一轮补收
+func TestRoundingCarryForward(t *testing.T) {
+	weight := uint64(3 * TIER_MULTIPLIER_BASE_BP)
+
+	up := roundingConfig{Mode: ROUNDING_MODE_UP, CarryForward: true}
+	first := mustRoundedSettlement(t, 100, 0, weight, up, 0)
+	carry := mustNextCarry(t, 0, first)
+	if carry != 2 {
+		t.Fatalf("carry after first round = %d, want 2", carry)
+	}
+	second := mustRoundedSettlement(t, 100, 0, weight, up, carry)
+	if second.Target != 98 || second.CarryApplied != 2 || second.PerCapita != 33 {
+		t.Errorf("second round = %+v, want target 98, applied 2, per capita 33", second)
+	}
+	if carry = mustNextCarry(t, carry, second); carry != 1 {
+		t.Errorf("carry after second round = %d, want 1", carry)
+	}
+
+	down := roundingConfig{Mode: ROUNDING_MODE_DOWN, CarryForward: true}
+	short := mustRoundedSettlement(t, 200, 0, weight, down, 0)
+	shortCarry := mustNextCarry(t, 0, short)
+	next := mustRoundedSettlement(t, 200, 0, weight, down, shortCarry)
+	if shortCarry != -2 || next.Target != 202 || next.PerCapita != 67 {
+		t.Errorf("shortfall carry = %d, next = %+v, want -2, target 202, per capita 67", shortCarry, next)
+	}
+
+	// 不结转时余额只累计，不影响应收
+	record := mustRoundedSettlement(t, 100, 0, weight, roundingConfig{Mode: ROUNDING_MODE_UP}, 0)
+	if got := mustNextCarry(t, 5, record); record.Target != 100 || got != 7 {
+		t.Errorf("recorded carry = %d, target = %d, want 7, 100", got, record.Target)
+	}
+
+	// 零给付轮次不使用也不产生取整余额
+	if zero := mustRoundedSettlement(t, 0, 800, weight, up, 10); zero.CarryApplied != 0 || zero.Surplus != 0 || zero.PerCapita != 0 {
+		t.Errorf("zero-payout settlement = %+v, want no carry and no surplus", zero)
+	}
+}
+
+// TestSettlementWarnings 测试结算预览的风险提示：快照无成员、轮次未到期、资金池不足与超过月度上限
+func TestSettlementWarnings(t *testing.T) {
+	approved := map[string]uint64{"claim_1": 60000, "claim_2": 30000}
+	in := roundSettlementInputs{
+		ClaimIDs: []string{"claim_1", "claim_2"},
+		ApprovedAmount: func(claimID string) (uint64, bool) {
+			amount, ok := approved[claimID]
+			return amount, ok
+		},
+		ServiceFeeBP: 800,
+		FeeMode:      FEE_MODE_FIXED,
+		MemberCount:  3,
+		TotalWeight:  3 * TIER_MULTIPLIER_BASE_BP,
+		Rounding:     defaultRounding,
+	}
+	plan, ok := planRoundSettlement(in)
+	if !ok || plan.TotalApprovedPayout != 90000 || plan.Settlement.PerCapita != 32400 || plan.Status != ROUND_STATUS_SETTLED {
+		t.Fatalf("planRoundSettlement() = %+v, want payout 90000, per capita 32400, SETTLED", plan)
+	}
+
+	ctx := settlementPreview{Now: 100, PeriodEnd: 100, MonthlyCap: 200000, TierMultipliers: []uint64{10000, 20000}}
+	if got := settlementWarnings(in, plan, ctx); len(got) != 0 {
+		t.Errorf("settlementWarnings() = %v, want none", got)
+	}
+
+	ctx = settlementPreview{Now: 99, PeriodEnd: 100, MonthlyCap: 50000, TierMultipliers: []uint64{10000, 20000}, PoolBalance: 72000, HasPool: true}
+	want := []string{
+		"round period has not ended; more approved claims may still join this round",
+		"pool balance covers only 80% of projected payouts",
+		"highest tier due 64800 exceeds monthly cap per member 50000",
+	}
+	if got := settlementWarnings(in, plan, ctx); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("settlementWarnings() = %v, want %v", got, want)
+	}
+
+	in.MemberCount, in.TotalWeight = 0, 0
+	noMembers, _ := planRoundSettlement(in)
+	if got := settlementWarnings(in, noMembers, settlementPreview{}); len(got) != 1 || !strings.Contains(got[0], "settlement would be rejected") {
+		t.Errorf("settlementWarnings() without members = %v, want rejection warning", got)
+	}
+	if got := poolCoveragePercent(^uint64(0)/2, ^uint64(0)); got != 49 {
+		t.Errorf("poolCoveragePercent() large amounts = %d, want 49", got)
+	}
+}
+
+// TestCurrentRoundRecord 测试当前轮次解析：开启任何轮次之前、OpenRound 之后与轮次记录缺失
+func TestCurrentRoundRecord(t *testing.T) {
+	state := map[string][]byte{}
+	loadRound := func(roundID string) []byte { return state["round_"+roundID] }
+
+	// 开启任何轮次之前：current_round_id 不存在
+	if _, _, ok := currentRoundRecord(state["current_round_id"], loadRound); ok {
+		t.Error("currentRoundRecord() before any round ok = true, want false")
+	}
+
+	// OpenRound 之后：current_round_id 指向新轮次（链上读取可能带尾部填充）
+	state["round_round_202501_01"] = []byte("round record")
+	state["current_round_id"] = append([]byte("round_202501_01"), 0, 0)
+	roundID, roundData, ok := currentRoundRecord(state["current_round_id"], loadRound)
+	if !ok || roundID != "round_202501_01" || string(roundData) != "round record" {
+		t.Errorf("currentRoundRecord() after OpenRound = %q, %q, %v", roundID, roundData, ok)
+	}
+
+	// 当前轮次ID指向不存在的轮次记录
+	state["current_round_id"] = []byte("round_202502_01")
+	if _, _, ok := currentRoundRecord(state["current_round_id"], loadRound); ok {
+		t.Error("currentRoundRecord() with missing round record ok = true, want false")
+	}
+}
+
+// TestMulticallViews 测试 Multicall 混合调用：成功查询、单条 NOT_FOUND 与写入方法被拒绝
+func TestMulticallViews(t *testing.T) {
+	// 未安装内存宿主时状态为空，GetClaimInfo 返回 NOT_FOUND
+	calls, err := framework.ParseMulticallCalls([]byte(`{"calls":[
+		{"method":"GetLimits"},
+		{"method":"GetClaimInfo","params":{"plan_id":"plan_001","claim_id":"claim_missing"}},
+		{"method":"Payout","params":{"plan_id":"plan_001","claim_id":"claim_001"}},
+		{"method":"GetLimits","params":{}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseMulticallCalls() error = %v", err)
+	}
+	results := framework.RunMulticall(calls)
+
+	want := []struct {
+		status string
+		code   uint32
+	}{
+		{framework.MULTICALL_STATUS_OK, 0},
+		{framework.MULTICALL_STATUS_ERROR, framework.ERROR_NOT_FOUND},
+		{framework.MULTICALL_STATUS_ERROR, framework.ERROR_PERMISSION_DENIED},
+		{framework.MULTICALL_STATUS_OK, 0},
+	}
+	for i, w := range want {
+		if results[i].Status != w.status || results[i].ErrorCode != w.code {
+			t.Errorf("results[%d] (%s) = %s/%d, want %s/%d", i, results[i].Method, results[i].Status, results[i].ErrorCode, w.status, w.code)
+		}
+	}
+	if got := string(results[0].Result); !strings.Contains(got, `"write_budgets":{"AttachEvidence":1024}`) || !strings.Contains(got, `"multicall":`) {
+		t.Errorf("GetLimits result = %s", got)
+	}
+	if framework.IsViewFunction("Payout") {
+		t.Error("Payout registered as a view function")
+	}
+}
+
+// TestPlanInitializedEventMatchesReturn 测试 MutualAidPlanInitialized 事件字段与 Initialize 返回值一致
+func TestPlanInitializedEventMatchesReturn(t *testing.T) {
+	initialization := planInitialization{
+		PlanID:              testPlan.PlanID,
+		Name:                testPlan.Name,
+		TokenID:             string(testPlan.Token.ID),
+		CoverageAmount:      testPlan.CoverageAmount,
+		ServiceFeeBP:        testPlan.ServiceFeeBP,
+		SettlementPeriod:    testPlan.SettlementPeriod,
+		WaitingPeriod:       testPlan.WaitingPeriod,
+		MinMembers:          testPlan.MinMembers,
+		MonthlyCapPerMember: testPlan.MonthlyCapPerMember,
+		Operator:            fixtures.Base58(fixtures.Operator()),
+		OperatorAdmin:       fixtures.Base58(fixtures.Operator()),
+		InitializedAt:       fixtures.Epoch,
+	}
+	result := initialization.fields()
+	event := initialization.event()
+
+	if event.Name != EVENT_PLAN_INITIALIZED {
+		t.Errorf("event name = %s, want %s", event.Name, EVENT_PLAN_INITIALIZED)
+	}
+	if len(event.Data) != len(result) {
+		t.Errorf("event has %d fields, return JSON has %d", len(event.Data), len(result))
+	}
+	for key, want := range result {
+		if got, ok := event.Data[key]; !ok || got != want {
+			t.Errorf("event[%s] = %v (present %v), want %v", key, got, ok, want)
+		}
+	}
+	for _, key := range []string{"operator", "initialized_at", "member_count_active"} {
+		if _, ok := event.Data[key]; !ok {
+			t.Errorf("event missing %s", key)
+		}
+	}
+
+	schema, ok := framework.GetEventSchema(EVENT_PLAN_INITIALIZED)
+	if !ok {
+		t.Fatal("plan initialized event schema not registered")
+	}
+	if len(schema.Fields) != len(result) {
+		t.Errorf("schema fields = %v, want the %d return JSON keys", schema.Fields, len(result))
+	}
+	for _, field := range schema.Fields {
+		if _, ok := result[field]; !ok {
+			t.Errorf("schema field %s missing from return JSON", field)
+		}
+	}
+}
+
+// TestParseCoverageCategories 测试类别配置解析与取值校验
+func TestParseCoverageCategories(t *testing.T) {
+	categories, ok := parseCoverageCategories(`{"plan_id":"p1","categories":[
+		{"category_id":"critical_illness","per_claim_limit":300000,"annual_limit":300000,"waiting_period":7776000},
+		{"category_id":"acc\u0069dent","per_claim_limit":100000,"annual_limit":200000}
+	]}`, fixtures.Days(7))
+	if !ok || len(categories) != 2 {
+		t.Fatalf("parseCoverageCategories() = %+v, %v", categories, ok)
+	}
+	if categories[0] != (coverageCategory{"critical_illness", 300000, 300000, 7776000}) {
+		t.Errorf("categories[0] = %+v", categories[0])
+	}
+	if categories[1].ID != "accident" {
+		t.Errorf("categories[1].ID = %q, want unescaped \"accident\"", categories[1].ID)
+	}
+	if categories[1].WaitingPeriod != fixtures.Days(7) {
+		t.Errorf("accident waiting_period = %d, want plan default %d", categories[1].WaitingPeriod, fixtures.Days(7))
+	}
+
+	if categories, ok := parseCoverageCategories(`{"plan_id":"p1"}`, 0); !ok || categories != nil {
+		t.Errorf("missing categories = %+v, %v, want nil, true", categories, ok)
+	}
+
+	tooMany := make([]string, MAX_COVERAGE_CATEGORIES+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf(`{"category_id":"c%d","per_claim_limit":1,"annual_limit":1}`, i)
+	}
+	for name, raw := range map[string]string{
+		"empty":               `{"categories":[]}`,
+		"too many":            `{"categories":[` + strings.Join(tooMany, ",") + `]}`,
+		"missing id":          `{"categories":[{"per_claim_limit":1,"annual_limit":1}]}`,
+		"id too long":         `{"categories":[{"category_id":"` + strings.Repeat("x", COVERAGE_CATEGORY_ID_SIZE+1) + `","per_claim_limit":1,"annual_limit":1}]}`,
+		"duplicate":           `{"categories":[{"category_id":"a","per_claim_limit":1,"annual_limit":1},{"category_id":"a","per_claim_limit":2,"annual_limit":2}]}`,
+		"zero per claim":      `{"categories":[{"category_id":"a","per_claim_limit":0,"annual_limit":1}]}`,
+		"annual below single": `{"categories":[{"category_id":"a","per_claim_limit":2,"annual_limit":1}]}`,
+		"not an array":        `{"categories":"a"}`,
+		"limit overflow":      `{"categories":[{"category_id":"a","per_claim_limit":1,"annual_limit":18446744073709551616}]}`,
+		"negative waiting":    `{"categories":[{"category_id":"a","per_claim_limit":1,"annual_limit":1,"waiting_period":-1}]}`,
+	} {
+		if _, ok := parseCoverageCategories(raw, 0); ok {
+			t.Errorf("%s: parseCoverageCategories() ok = true, want false", name)
+		}
+	}
+}
+
+// TestCheckClaimCategory 测试报案类别校验：单次上限（含边界）、除外、类别等待期
+func TestCheckClaimCategory(t *testing.T) {
+	categories := []coverageCategory{
+		{ID: "critical_illness", PerClaimLimit: 300000, AnnualLimit: 300000, WaitingPeriod: fixtures.Days(90)},
+		{ID: "accident", PerClaimLimit: 100000, AnnualLimit: 200000},
+	}
+	join := fixtures.Epoch
+	tests := []struct {
+		name       string
+		categories []coverageCategory
+		id         string
+		excluded   bool
+		amount     uint64
+		now        uint64
+		want       string
+	}{
+		{"no categories, legacy claim", nil, "", false, 1, join, ""},
+		{"no categories, id supplied", nil, "accident", false, 1, join, CATEGORY_REJECT_UNKNOWN},
+		{"category required", categories, "", false, 1, join, CATEGORY_REJECT_REQUIRED},
+		{"unknown category", categories, "dental", false, 1, join, CATEGORY_REJECT_UNKNOWN},
+		{"exactly at per-claim limit", categories, "accident", false, 100000, join, ""},
+		{"one over per-claim limit", categories, "accident", false, 100001, join, CATEGORY_REJECT_PER_CLAIM_LIMIT},
+		{"excluded", categories, "accident", true, 1, join, CATEGORY_REJECT_EXCLUDED},
+		{"category waiting period", categories, "critical_illness", false, 1, join + fixtures.Days(89), CATEGORY_REJECT_WAITING_PERIOD},
+		{"category waiting period over", categories, "critical_illness", false, 1, join + fixtures.Days(90), ""},
+	}
+	for _, tt := range tests {
+		if _, got := checkClaimCategory(tt.categories, tt.id, tt.excluded, tt.amount, join, tt.now); got != tt.want {
+			t.Errorf("%s: checkClaimCategory() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestCategoryUsageLimits 测试年度累计额度的占用与给付
+func TestCategoryUsageLimits(t *testing.T) {
+	var u categoryUsage
+	u, ok := u.reserveApproval(200000, 100000)
+	if !ok || u.remaining(200000) != 100000 {
+		t.Fatalf("first reserve = %+v, %v", u, ok)
+	}
+	u, ok = u.reserveApproval(200000, 100000)
+	if !ok || u.remaining(200000) != 0 {
+		t.Fatalf("reserve up to the limit = %+v, %v", u, ok)
+	}
+	if _, ok := u.reserveApproval(200000, 1); ok {
+		t.Error("reserve beyond annual limit succeeded")
+	}
+	if _, ok := u.reserveApproval(^uint64(0), ^uint64(0)); ok {
+		t.Error("overflowing reserve succeeded")
+	}
+	u, ok = u.recordPayout(200000, 200000)
+	if !ok || u.Paid != 200000 {
+		t.Fatalf("recordPayout = %+v, %v", u, ok)
+	}
+	if _, ok := u.recordPayout(200000, 1); ok {
+		t.Error("payout beyond annual limit succeeded")
+	}
+
+	// 取消已批准案件释放额度，不低于 0
+	if got := u.releaseApproval(50000); got.Approved != 150000 {
+		t.Errorf("releaseApproval(50000) approved = %d, want 150000", got.Approved)
+	}
+	if got := u.releaseApproval(^uint64(0)); got.Approved != 0 {
+		t.Errorf("releaseApproval(max) approved = %d, want 0", got.Approved)
+	}
+}
+
+// TestCalendarYear 测试时间戳的公历年份（含年末、闰年）
+func TestCalendarYear(t *testing.T) {
+	for ts, want := range map[uint64]uint64{
+		0:          1970,
+		1704067199: 2023, // 2023-12-31 23:59:59
+		1704067200: 2024, // 2024-01-01 00:00:00
+		1709164800: 2024, // 2024-02-29
+		1735689599: 2024, // 2024-12-31 23:59:59
+	} {
+		if got := calendarYear(ts); got != want {
+			t.Errorf("calendarYear(%d) = %d, want %d", ts, got, want)
+		}
+	}
+}
+
+// TestContributionYearMonth 测试轮次结束时间所在的月度统计年月（跨月、跨年、闰年 2 月、每月 1 日零点）
+func TestContributionYearMonth(t *testing.T) {
+	tests := []struct {
+		ts   uint64
+		want string
+	}{
+		{0, "197001"},
+		{1706745599, "202401"}, // 2024-01-31 23:59:59
+		{1706745600, "202402"}, // 2024-02-01 00:00:00
+		{1709164800, "202402"}, // 2024-02-29 00:00:00（闰年）
+		{1709251199, "202402"}, // 2024-02-29 23:59:59
+		{1709251200, "202403"}, // 2024-03-01 00:00:00
+		{1740700800, "202502"}, // 2025-02-28 00:00:00（平年）
+		{1740787200, "202503"}, // 2025-03-01 00:00:00
+		{1735689599, "202412"}, // 2024-12-31 23:59:59
+		{1735689600, "202501"}, // 2025-01-01 00:00:00
+		{1738368000, "202502"}, // 2025-02-01 00:00:00
+		{951782400, "200002"},  // 2000-02-29 00:00:00（2000 年为闰年）
+		{951868800, "200003"},  // 2000-03-01 00:00:00
+		{4107542399, "210002"}, // 2100-02-28 23:59:59（2100 年不是闰年）
+		{4107542400, "210003"}, // 2100-03-01 00:00:00
+	}
+	for _, tt := range tests {
+		if got := contributionYearMonth(tt.ts); got != tt.want {
+			t.Errorf("contributionYearMonth(%d) = %q, want %q", tt.ts, got, tt.want)
+		}
+	}
+
+	// 同一轮次的缴费与冲正落在同一个月度统计中，不同月份结束的轮次分属不同统计
+	if contributionYearMonth(1738368000-1) == contributionYearMonth(1738368000) {
+		t.Error("rounds ending in January and February share a monthly bucket")
+	}
+}
+
+// TestCategoryEncodingSurvivesTrailingZeroTrim 测试类别配置、除外列表与年度额度去掉尾部零字节后仍可完整解码
+func TestCategoryEncodingSurvivesTrailingZeroTrim(t *testing.T) {
+	trim := func(data []byte) []byte {
+		for len(data) > 0 && data[len(data)-1] == 0 {
+			data = data[:len(data)-1]
+		}
+		return data
+	}
+
+	categories := []coverageCategory{{ID: "accident", PerClaimLimit: 100000, AnnualLimit: 200000}, {ID: "b", PerClaimLimit: 1, AnnualLimit: 1 << 8}}
+	got := decodeCoverageCategories(trim(encodeCoverageCategories(categories)))
+	if fmt.Sprint(got) != fmt.Sprint(categories) {
+		t.Errorf("decodeCoverageCategories() = %+v, want %+v", got, categories)
+	}
+
+	exclusions := upsertExclusion(nil, memberExclusion{CategoryID: "accident", Reason: "old", ExcludedAt: 1})
+	exclusions = upsertExclusion(exclusions, memberExclusion{CategoryID: "critical_illness", ExcludedAt: 1 << 8})
+	exclusions = upsertExclusion(exclusions, memberExclusion{CategoryID: "accident", Reason: "pre-existing condition", ExcludedAt: 2})
+	gotExclusions := decodeExclusions(trim(encodeExclusions(exclusions)))
+	if len(gotExclusions) != 2 || fmt.Sprint(gotExclusions) != fmt.Sprint(exclusions) {
+		t.Errorf("decodeExclusions() = %+v, want %+v", gotExclusions, exclusions)
+	}
+	if e, ok := findExclusion(gotExclusions, "accident"); !ok || e.Reason != "pre-existing condition" {
+		t.Errorf("findExclusion(accident) = %+v, %v", e, ok)
+	}
+
+	usage := categoryUsage{Approved: 1 << 16}
+	if got := decodeCategoryUsage(trim(encodeCategoryUsage(usage))); got != usage {
+		t.Errorf("decodeCategoryUsage() = %+v, want %+v", got, usage)
+	}
+}
+
+// TestPlanBatchReviewAnnualLimit 测试批量审核中超过类别年度累计上限的批准项被跳过且不占用轮次索引
+func TestPlanBatchReviewAnnualLimit(t *testing.T) {
+	lookup := func(claimID string) (string, uint64, bool) {
+		c := fixtures.SubmittedClaim(claimID, 100000)
+		return c.Status, c.RequestedAmount, true
+	}
+	var usage categoryUsage
+	reserve := func(claimID string, amount uint64) bool {
+		next, ok := usage.reserveApproval(150000, amount)
+		if ok {
+			usage = next
+		}
+		return ok
+	}
+
+	results, index, count := planBatchReview([]reviewDecision{
+		{ClaimID: "a", Decision: DECISION_APPROVE, ApprovedAmount: 100000},
+		{ClaimID: "b", Decision: DECISION_APPROVE, ApprovedAmount: 100000},
+		{ClaimID: "c", Decision: DECISION_APPROVE, ApprovedAmount: 50000},
+	}, lookup, nil, reserve)
+
+	if results[0].Outcome != BATCH_REVIEW_APPLIED || results[1].SkipReason != SKIP_REASON_ANNUAL_LIMIT || results[2].Outcome != BATCH_REVIEW_APPLIED {
+		t.Errorf("results = %+v", results)
+	}
+	if count != 2 || fmt.Sprint(decodeRoundClaims(index)) != "[a c]" || usage.Approved != 150000 {
+		t.Errorf("round claims = %v (%d), usage = %+v", decodeRoundClaims(index), count, usage)
+	}
+}
+
+// TestSplitSurplus 测试结余按档位系数按比例分配，取整余数按顺序补足，总额等于结余
+func TestSplitSurplus(t *testing.T) {
+	tests := []struct {
+		name    string
+		surplus uint64
+		weights []uint64
+		want    []uint64
+	}{
+		{"equal weights", 7200, []uint64{10000, 10000, 10000}, []uint64{2400, 2400, 2400}},
+		{"tiered", 9000, []uint64{10000, 20000, 15000}, []uint64{2000, 4000, 3000}},
+		{"remainder to leading members", 10, []uint64{10000, 10000, 10000}, []uint64{4, 3, 3}},
+		{"zero weight skipped", 5, []uint64{0, 10000, 10000}, []uint64{0, 3, 2}},
+		{"no members", 100, nil, []uint64{}},
+		{"no surplus", 0, []uint64{10000}, []uint64{0}},
+		{"large balance", 1 << 62, []uint64{30000, 10000}, []uint64{3 << 60, 1 << 60}},
+	}
+	for _, tt := range tests {
+		got := splitSurplus(tt.surplus, tt.weights)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: splitSurplus(%d, %v) = %v, want %v", tt.name, tt.surplus, tt.weights, got, tt.want)
+		}
+	}
+}
+
+// TestFinalizeBlocker 测试计划终结的前置条件：OPEN 轮次优先于待给付案件报告
+func TestFinalizeBlocker(t *testing.T) {
+	tests := []struct {
+		roundStatus    string
+		approvedUnpaid uint64
+		want           string
+	}{
+		{"", 0, ""},
+		{ROUND_STATUS_SETTLED, 0, ""},
+		{ROUND_STATUS_CLOSED, 0, ""},
+		{ROUND_STATUS_OPEN, 0, FINALIZE_REJECT_ROUND_OPEN},
+		{ROUND_STATUS_OPEN, 2, FINALIZE_REJECT_ROUND_OPEN},
+		{ROUND_STATUS_SETTLED, 1, FINALIZE_REJECT_CLAIMS_UNPAID},
+	}
+	for _, tt := range tests {
+		if got := finalizeBlocker(tt.roundStatus, tt.approvedUnpaid); got != tt.want {
+			t.Errorf("finalizeBlocker(%q, %d) = %q, want %q", tt.roundStatus, tt.approvedUnpaid, got, tt.want)
+		}
+	}
+}
+
+// TestMemberRoundShortfall 测试关闭轮次时的未缴金额：有记录按 应缴-已缴，无记录按全额应缴
+func TestMemberRoundShortfall(t *testing.T) {
+	tests := []struct {
+		due       roundDue
+		hasRecord bool
+		want      uint64
+	}{
+		{roundDue{}, false, 3240},
+		{roundDue{DueAmount: 3240, PaidAmount: 1000}, true, 2240},
+		{roundDue{DueAmount: 3240, PaidAmount: 3240, Settled: true}, true, 0},
+		{roundDue{DueAmount: 3240, PaidAmount: 4000, Settled: true}, true, 0},
+	}
+	for _, tt := range tests {
+		if got := memberRoundShortfall(tt.due, tt.hasRecord, 3240); got != tt.want {
+			t.Errorf("memberRoundShortfall(%+v, %v) = %d, want %d", tt.due, tt.hasRecord, got, tt.want)
+		}
+	}
+}
+
+// TestMemberStatusTransition 测试暂停、恢复、拉黑允许的成员状态
+func TestMemberStatusTransition(t *testing.T) {
+	statuses := []string{MEMBER_STATUS_PENDING, MEMBER_STATUS_ACTIVE, MEMBER_STATUS_SUSPENDED, MEMBER_STATUS_EXITED, MEMBER_STATUS_BLACKLISTED}
+	tests := []struct {
+		action  string
+		want    string
+		allowed map[string]bool
+	}{
+		{MEMBER_ACTION_SUSPEND, MEMBER_STATUS_SUSPENDED, map[string]bool{MEMBER_STATUS_ACTIVE: true}},
+		{MEMBER_ACTION_RESUME, MEMBER_STATUS_ACTIVE, map[string]bool{MEMBER_STATUS_SUSPENDED: true}},
+		{MEMBER_ACTION_BLACKLIST, MEMBER_STATUS_BLACKLISTED, map[string]bool{MEMBER_STATUS_PENDING: true, MEMBER_STATUS_ACTIVE: true, MEMBER_STATUS_SUSPENDED: true}},
+	}
+	for _, tt := range tests {
+		for _, status := range statuses {
+			got, ok := memberStatusTransition(tt.action, status)
+			if ok != tt.allowed[status] || (ok && got != tt.want) {
+				t.Errorf("memberStatusTransition(%s, %s) = %q, %v", tt.action, status, got, ok)
+			}
+		}
+	}
+	if _, ok := memberStatusTransition("UNKNOWN", MEMBER_STATUS_ACTIVE); ok {
+		t.Error("unknown action should be rejected")
+	}
+}
+
+// TestRecordLayouts 测试以 FixedCodec 声明的记录的长度与旧版手写布局一致，缺少追加字段的旧记录仍可解码
+func TestRecordLayouts(t *testing.T) {
+	layouts := []struct {
+		name            string
+		size, minSize   int
+		gotSize, gotMin int
+	}{
+		{"plan_config", 176, 176, planConfigCodec.Size(), planConfigCodec.MinSize()},
+		{"member", 72, 56, memberCodec.Size(), memberCodec.MinSize()},
+		{"claim", 304, 304, claimCodec.Size(), claimCodec.MinSize()},
+		{"round", 136, 128, roundCodec.Size(), roundCodec.MinSize()},
+		{"offchain_entry", 84, 84, offchainEntryCodec.Size(), offchainEntryCodec.MinSize()},
+		{"member_month_stat", 9, 9, memberMonthStatCodec.Size(), memberMonthStatCodec.MinSize()},
+		{"fee_adjustment", 32, 32, feeAdjustmentCodec.Size(), feeAdjustmentCodec.MinSize()},
+	}
+	for _, l := range layouts {
+		if l.gotSize != l.size || l.gotMin != l.minSize {
+			t.Errorf("%s layout = %d/%d bytes, want %d/%d", l.name, l.gotSize, l.gotMin, l.size, l.minSize)
+		}
+	}
+
+	member := encodeMember(MEMBER_STATUS_ACTIVE, 100, 200, 300, 400, 5, 2, 9)
+	if string(member[56:64]) != string(framework.Uint64ToBytes(2)) || string(member[64:72]) != string(framework.Uint64ToBytes(9)) {
+		t.Errorf("member tier/activationSeq offsets changed: %x", member)
+	}
+	status, joinTime, _, _, arrears, _, tier, seq := decodeMember(member[:56])
+	if status != MEMBER_STATUS_ACTIVE || joinTime != 100 || arrears != 400 || tier != 0 || seq != 0 {
+		t.Errorf("legacy 56-byte member = %s, %d, %d, tier %d, seq %d", status, joinTime, arrears, tier, seq)
+	}
+	if status, _, _, _, _, _, tier, _ := decodeMember(member[:60]); status != MEMBER_STATUS_ACTIVE || tier != 0 {
+		t.Errorf("truncated tier field decoded as %d", tier)
+	}
+	if status, _, _, _, _, _, _, _ := decodeMember(member[:55]); status != "" {
+		t.Errorf("short member record decoded status %q", status)
+	}
+
+	round := encodeRound(testPlan.PlanID, "round_1", ROUND_STATUS_OPEN, 1, 2, 3, 4, 5, 6, 7)
+	if _, roundID, _, _, _, _, _, _, payers, snapshotSeq := decodeRound(round[:128]); roundID != "round_1" || payers != 6 || snapshotSeq != 0 {
+		t.Errorf("legacy 128-byte round = %s, payers %d, snapshotSeq %d", roundID, payers, snapshotSeq)
+	}
+	planID, name, _, coverage, _, _, _, _, monthlyCap := decodePlanConfig(encodePlanConfig(testPlan.PlanID, testPlan.Name, "", 300000, 800, 1, 2, 3, 4))
+	if planID != testPlan.PlanID || name != testPlan.Name || coverage != 300000 || monthlyCap != 4 {
+		t.Errorf("plan config round trip = %s, %s, %d, %d", planID, name, coverage, monthlyCap)
+	}
+
+	// 线下缴费登记、月度统计、服务费调整：与旧版手写偏移逐字节一致
+	member20 := framework.Address{0x01, 0x00, 0x02}
+	entry := encodeOffchainEntry(member20, "round_1", 500, OFFCHAIN_STATUS_RECORDED, 1700000000)
+	want := make([]byte, 84)
+	copy(want[0:20], member20[:])
+	copy(want[20:52], "round_1")
+	copy(want[52:60], framework.Uint64ToBytes(500))
+	copy(want[60:76], OFFCHAIN_STATUS_RECORDED)
+	copy(want[76:84], framework.Uint64ToBytes(1700000000))
+	if string(entry) != string(want) {
+		t.Errorf("offchain entry = %x\nwant             %x", entry, want)
+	}
+	if m, roundID, amount, st, at := decodeOffchainEntry(entry); m != member20 || roundID != "round_1" || amount != 500 || st != OFFCHAIN_STATUS_RECORDED || at != 1700000000 {
+		t.Errorf("offchain entry round trip = %x, %s, %d, %s, %d", m, roundID, amount, st, at)
+	}
+	if m, roundID, _, _, _ := decodeOffchainEntry(entry[:83]); !m.IsZero() || roundID != "" {
+		t.Errorf("short offchain entry decoded %x, %q", m, roundID)
+	}
+
+	if stat := encodeMemberMonthStat(42, true); string(stat) != string(append(framework.Uint64ToBytes(42), 1)) {
+		t.Errorf("month stat = %x", stat)
+	}
+	if paid, capReached := decodeMemberMonthStat(encodeMemberMonthStat(42, true)); paid != 42 || !capReached {
+		t.Errorf("month stat round trip = %d, %v", paid, capReached)
+	}
+	if paid, capReached := decodeMemberMonthStat([]byte{1, 2}); paid != 0 || capReached {
+		t.Errorf("short month stat = %d, %v", paid, capReached)
+	}
+
+	fee := encodeFeeAdjustment(FEE_MODE_CLAIMS_RATIO, 300, 1200)
+	if string(fee[16:24]) != string(framework.Uint64ToBytes(300)) || string(fee[24:32]) != string(framework.Uint64ToBytes(1200)) || string(framework.TrimNull(fee[0:16])) != FEE_MODE_CLAIMS_RATIO {
+		t.Errorf("fee adjustment = %x", fee)
+	}
+	if mode, minBP, maxBP := decodeFeeAdjustment(fee); mode != FEE_MODE_CLAIMS_RATIO || minBP != 300 || maxBP != 1200 {
+		t.Errorf("fee adjustment round trip = %s, %d, %d", mode, minBP, maxBP)
+	}
+	for _, data := range [][]byte{nil, fee[:31], make([]byte, 32)} {
+		if mode, _, _ := decodeFeeAdjustment(data); mode != FEE_MODE_FIXED {
+			t.Errorf("decodeFeeAdjustment(%d bytes) mode = %s, want FIXED", len(data), mode)
+		}
+	}
+}
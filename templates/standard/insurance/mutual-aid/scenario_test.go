@@ -0,0 +1,1296 @@
+//go:build !tinygo && !(js && wasm)
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/framework/fixtures"
+	fwtesting "github.com/weisyn/contract-sdk-go/framework/testing"
+)
+
+// 场景测试：以多个参与方身份按顺序调用导出函数，覆盖跨导出函数、跨模拟时间的完整流程。
+// 运行器见 framework/testing.Scenario。
+
+// mutualAidExports 场景中可调用的导出函数
+var mutualAidExports = fwtesting.Exports{
+	"Initialize":      Initialize,
+	"Join":            Join,
+	"ApproveMember":   ApproveMember,
+	"Exit":            Exit,
+	"OpenRound":       OpenRound,
+	"SubmitClaim":     SubmitClaim,
+	"ReviewClaim":     ReviewClaim,
+	"SettleRound":     SettleRound,
+	"PayContribution": PayContribution,
+	"Payout":          Payout,
+	"GetClaimInfo":    GetClaimInfo,
+	"GetRoundInfo":    GetRoundInfo,
+
+	"ExcludeCategoryForMember": ExcludeCategoryForMember,
+	"RotateRoleKey":            RotateRoleKey,
+	"AcceptRoleKey":            AcceptRoleKey,
+	"EmergencyRotateRoleKey":   EmergencyRotateRoleKey,
+	"GetPlanInfo":              GetPlanInfo,
+	"GetMemberInfo":            GetMemberInfo,
+	"GetDisplayManifest":       GetDisplayManifest,
+	"PreviewSettlement":        PreviewSettlement,
+	"EstimateContribution":     EstimateContribution,
+	"FinalizePlan":             FinalizePlan,
+	"CloseRound":               CloseRound,
+	"AdvanceRound":             AdvanceRound,
+	"QueryEventLog":            QueryEventLog,
+	"SuspendMember":            SuspendMember,
+	"ResumeMember":             ResumeMember,
+	"BlacklistMember":          BlacklistMember,
+	"CancelClaim":              CancelClaim,
+	"ListClaims":               ListClaims,
+}
+
+const (
+	scenarioPlanID  = "plan_xianghubao_001"
+	scenarioRoundID = "round_202501_01"
+	scenarioClaimID = "claim_202501_0001"
+
+	// scenarioApproved 批准给付金额：3 名成员、8% 服务费时人均分摊 90000 * 1.08 / 3 = 32400
+	scenarioApproved   = 90000
+	scenarioPerCapita  = 32400
+	scenarioMemberFund = 1000000
+)
+
+// newMutualAidScenario 初始化计划并加入、激活 Alice、Bob、Carol 三名成员
+func newMutualAidScenario(t *testing.T) *fwtesting.Scenario {
+	return newPlanScenario(t, "")
+}
+
+// newPlanScenario 同 newMutualAidScenario，extra 为追加到 Initialize 参数中的 JSON 字段（如 categories）
+func newPlanScenario(t *testing.T, extra string) *fwtesting.Scenario {
+	s := fwtesting.NewScenario(t, mutualAidExports)
+	members := []framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()}
+	for _, m := range members {
+		s.Fund(m, "", scenarioMemberFund)
+	}
+
+	s.As(fixtures.Operator()).Call("Initialize", fmt.Sprintf(
+		`{"plan_id":"%s","name":"%s","coverage_amount":%d,"service_fee_bp":%d,"settlement_period":%d,"waiting_period":%d,"min_members":1,"monthly_cap_per_member":%d%s}`,
+		scenarioPlanID, testPlan.Name, testPlan.CoverageAmount, testPlan.ServiceFeeBP, testPlan.SettlementPeriod, fixtures.Days(7), 200000, extra,
+	)).ExpectSuccess().ExpectEvent(EVENT_PLAN_INITIALIZED)
+
+	for _, m := range members {
+		s.As(m).Call("Join", `{"plan_id":"`+scenarioPlanID+`"}`).ExpectSuccess()
+		s.As(fixtures.Operator()).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(m))).
+			ExpectSuccess().ExpectWrite(string(getMemberStateID(m)))
+	}
+	return s
+}
+
+// openScenarioRound 以当前时间开启 30 天的结算轮次
+func openScenarioRound(s *fwtesting.Scenario) {
+	s.As(fixtures.Operator()).Call("OpenRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","period_start":%d,"period_end":%d}`,
+		scenarioPlanID, scenarioRoundID, s.Now(), s.Now()+testPlan.SettlementPeriod)).ExpectSuccess()
+}
+
+func submitClaimParams(s *fwtesting.Scenario) string {
+	return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","requested_amount":%d,"event_time":%d,"evidence_hash":"0xabc"}`,
+		scenarioPlanID, scenarioClaimID, testPlan.CoverageAmount, s.Now())
+}
+
+func payoutParams(from framework.Address) string {
+	return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","from":"%s","beneficiary":"%s","amount":%d,"payout_id":"payout_0001"}`,
+		scenarioPlanID, scenarioClaimID, fixtures.Base58(from), fixtures.Base58(fixtures.Alice()), scenarioApproved)
+}
+
+// TestScenarioMutualAidLifecycle 完整流程：加入 → 审核 → 开启轮次 → 报案 → 审核 → 结算 → 缴费 → 给付
+func TestScenarioMutualAidLifecycle(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+
+	s.AdvanceTime(fixtures.Days(8)) // 等待期 7 天已过
+	openScenarioRound(s)
+
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).
+		ExpectSuccess().ExpectWrite(string(getClaimStateID(scenarioClaimID)))
+	s.As(fixtures.Operator()).Call("ReviewClaim", fmt.Sprintf(
+		`{"plan_id":"%s","claim_id":"%s","decision":"APPROVE","approved_amount":%d,"reason":"ok","investigation_hash":"0xdef","review_round_id":"%s"}`,
+		scenarioPlanID, scenarioClaimID, scenarioApproved, scenarioRoundID)).ExpectSuccess()
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().ExpectEvent(EVENT_ROUND_PAYOUT_SUMMARY).
+		Expect(func(st *fwtesting.Step) error {
+			e, _ := st.Event("MutualAidRoundSettled")
+			if e.Data["per_capita_contribution"] != uint64(scenarioPerCapita) {
+				return fmt.Errorf("per_capita_contribution = %v, want %d", e.Data["per_capita_contribution"], scenarioPerCapita)
+			}
+			return nil
+		})
+
+	for i, m := range []framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()} {
+		s.As(m).Call("PayContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"ctrb_%d"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(pool), scenarioPerCapita, i)).
+			ExpectSuccess().ExpectEvent("MutualAidContributionPaid")
+	}
+	if got := s.Host().Balance(pool, ""); got != 3*scenarioPerCapita {
+		t.Fatalf("pool balance after contributions = %d, want %d", got, 3*scenarioPerCapita)
+	}
+
+	s.As(fixtures.Operator()).Call("Payout", payoutParams(pool)).ExpectSuccess().ExpectEvent("MutualAidPayout")
+	s.As(fixtures.Alice()).Call("GetClaimInfo", fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s"}`, scenarioPlanID, scenarioClaimID)).
+		ExpectSuccess().
+		Expect(func(st *fwtesting.Step) error {
+			if !strings.Contains(st.Return(), `"status":"`+CLAIM_STATUS_PAID+`"`) {
+				return fmt.Errorf("claim not PAID: %s", st.Return())
+			}
+			return nil
+		})
+
+	if got := s.Host().Balance(pool, ""); got != 3*scenarioPerCapita-scenarioApproved {
+		t.Errorf("pool balance after payout = %d, want %d", got, 3*scenarioPerCapita-scenarioApproved)
+	}
+	if got := s.Host().Balance(fixtures.Alice(), ""); got != scenarioMemberFund-scenarioPerCapita+scenarioApproved {
+		t.Errorf("alice balance = %d, want %d", got, scenarioMemberFund-scenarioPerCapita+scenarioApproved)
+	}
+}
+
+// stateReadRecorder 按宿主函数与状态ID记录实际执行的状态读取
+type stateReadRecorder map[string]int
+
+func (r stateReadRecorder) BeforeHostCall(call framework.HostCall) error {
+	if call.Name == framework.HOST_CALL_STATE_GET || call.Name == framework.HOST_CALL_STATE_GET_FROM_CHAIN {
+		r[call.Name+" "+string(call.StateID)]++
+	}
+	return nil
+}
+
+// TestScenarioPayContributionReadsStateOnce 缴费调用中轮次、计划配置、成员记录等被多处读取，
+// 经读取缓存后每个状态最多执行一次宿主读取
+func TestScenarioPayContributionReadsStateOnce(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess()
+
+	reads := stateReadRecorder{}
+	restore := framework.SetHostInterceptor(reads)
+	s.As(fixtures.Alice()).Call("PayContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"ctrb_0"}`,
+		scenarioPlanID, scenarioRoundID, fixtures.Base58(fixtures.Pool()), scenarioPerCapita)).
+		ExpectSuccess()
+	restore()
+
+	if reads[framework.HOST_CALL_STATE_GET+" "+string(getRoundStateID(scenarioRoundID))] != 1 {
+		t.Errorf("round state host reads = %d, want 1", reads[framework.HOST_CALL_STATE_GET+" "+string(getRoundStateID(scenarioRoundID))])
+	}
+	for key, n := range reads {
+		if n > 1 {
+			t.Errorf("%s read from host %d times in one invocation", key, n)
+		}
+	}
+}
+
+// TestScenarioWaitingPeriodViolation 等待期内报案被拒绝，等待期结束后可以报案（共享同一前缀）
+func TestScenarioWaitingPeriodViolation(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(1))
+	openScenarioRound(s)
+	prefix := s.Snapshot()
+
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectError(framework.ERROR_INVALID_STATE)
+	if _, _, ok := s.Host().State(string(getClaimStateID(scenarioClaimID))); ok {
+		t.Fatal("rejected claim was committed")
+	}
+
+	s.Restore(prefix)
+	s.AdvanceTime(fixtures.Days(7))
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+}
+
+// TestScenarioUnderfundedPayout 资金池余额不足时给付失败且不修改案件状态与余额
+func TestScenarioUnderfundedPayout(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", fmt.Sprintf(
+		`{"plan_id":"%s","claim_id":"%s","decision":"APPROVE","approved_amount":%d,"reason":"ok","investigation_hash":"0xdef","review_round_id":"%s"}`,
+		scenarioPlanID, scenarioClaimID, scenarioApproved, scenarioRoundID)).ExpectSuccess()
+
+	s.Fund(pool, "", scenarioApproved-1)
+	claimBefore, versionBefore, _ := s.Host().State(string(getClaimStateID(scenarioClaimID)))
+
+	s.As(fixtures.Operator()).Call("Payout", payoutParams(pool)).ExpectError(framework.ERROR_INSUFFICIENT_BALANCE)
+
+	claimAfter, versionAfter, _ := s.Host().State(string(getClaimStateID(scenarioClaimID)))
+	if string(claimAfter) != string(claimBefore) || versionAfter != versionBefore {
+		t.Error("failed payout changed the claim record")
+	}
+	if got := s.Host().Balance(pool, ""); got != scenarioApproved-1 {
+		t.Errorf("pool balance = %d, want %d", got, scenarioApproved-1)
+	}
+}
+
+// 保障类别：意外（单次 100000、年度 150000、无等待期），重疾（单次 300000、年度 300000、等待期 90 天）
+const scenarioCategories = `,"categories":[` +
+	`{"category_id":"accident","per_claim_limit":100000,"annual_limit":150000,"waiting_period":0},` +
+	`{"category_id":"critical_illness","per_claim_limit":300000,"annual_limit":300000,"waiting_period":7776000}]`
+
+func categoryClaimParams(s *fwtesting.Scenario, claimID, categoryID string, amount uint64) string {
+	return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","requested_amount":%d,"event_time":%d,"evidence_hash":"0xabc","category_id":"%s"}`,
+		scenarioPlanID, claimID, amount, s.Now(), categoryID)
+}
+
+func approveParams(claimID string, amount uint64) string {
+	return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","decision":"APPROVE","approved_amount":%d,"reason":"ok","investigation_hash":"0xdef","review_round_id":"%s"}`,
+		scenarioPlanID, claimID, amount, scenarioRoundID)
+}
+
+// expectReturn 断言返回值 JSON 中的字段，嵌套字段以 "." 分隔、数组按下标访问（如 "categories.1.annual_limit"）
+func expectReturn(want map[string]string) func(st *fwtesting.Step) error {
+	return func(st *fwtesting.Step) error {
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(st.Return()))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("return is not JSON: %v", err)
+		}
+		for path, w := range want {
+			if got := fmt.Sprint(jsonPath(v, path)); got != w {
+				return fmt.Errorf("return %s = %s, want %s", path, got, w)
+			}
+		}
+		return nil
+	}
+}
+
+func jsonPath(v interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v = node[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// TestScenarioCategoryPerClaimLimit 申请金额等于类别单次上限可以报案，超过 1 被拒绝；未指定类别被拒绝
+func TestScenarioCategoryPerClaimLimit(t *testing.T) {
+	s := newPlanScenario(t, scenarioCategories)
+	s.As(fixtures.Operator()).Call("GetPlanInfo", `{"plan_id":"`+scenarioPlanID+`"}`).
+		ExpectSuccess().Expect(expectReturn(map[string]string{
+		"categories.0.category_id":    "accident",
+		"categories.0.annual_limit":   "150000",
+		"categories.1.waiting_period": "7776000",
+	}))
+
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).
+		ExpectError(framework.ERROR_INVALID_PARAMS).Expect(expectReturn(map[string]string{"error": CATEGORY_REJECT_REQUIRED}))
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_over", "accident", 100001)).
+		ExpectError(framework.ERROR_INVALID_PARAMS).
+		Expect(expectReturn(map[string]string{"error": CATEGORY_REJECT_PER_CLAIM_LIMIT, "per_claim_limit": "100000"}))
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_at_limit", "accident", 100000)).
+		ExpectSuccess().ExpectWrite(string(getClaimCategoryStateID("claim_at_limit")))
+
+	// 重疾类别等待期 90 天，计划等待期 7 天不适用
+	s.AdvanceTime(fixtures.Days(8))
+	s.As(fixtures.Bob()).Call("SubmitClaim", categoryClaimParams(s, "claim_ci", "critical_illness", 300000)).
+		ExpectError(framework.ERROR_INVALID_STATE).Expect(expectReturn(map[string]string{"error": CATEGORY_REJECT_WAITING_PERIOD}))
+	s.AdvanceTime(fixtures.Days(82))
+	s.As(fixtures.Bob()).Call("SubmitClaim", categoryClaimParams(s, "claim_ci", "critical_illness", 300000)).ExpectSuccess()
+}
+
+// TestScenarioClaimIDKeySeparation claim_id 字符集受限且状态ID以 ':' 分隔，
+// 报案 "category_X" 不会覆盖报案 X 的类别记录
+func TestScenarioClaimIDKeySeparation(t *testing.T) {
+	s := newPlanScenario(t, scenarioCategories)
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "X", "accident", 50000)).
+		ExpectSuccess().ExpectWrite(string(getClaimCategoryStateID("X")))
+	before, versionBefore, ok := s.Host().State(string(getClaimCategoryStateID("X")))
+	if !ok {
+		t.Fatal("claim X category record missing")
+	}
+
+	s.As(fixtures.Bob()).Call("SubmitClaim", categoryClaimParams(s, "category_X", "accident", 60000)).ExpectSuccess()
+	after, versionAfter, _ := s.Host().State(string(getClaimCategoryStateID("X")))
+	if string(after) != string(before) || versionAfter != versionBefore {
+		t.Fatal("claim category_X overwrote the category record of claim X")
+	}
+
+	for _, claimID := range []string{"", "a:b", "claim X", strings.Repeat("c", MAX_CLAIM_ID_LENGTH+1)} {
+		s.As(fixtures.Carol()).Call("SubmitClaim", categoryClaimParams(s, claimID, "accident", 1000)).
+			ExpectError(framework.ERROR_INVALID_PARAMS)
+	}
+}
+
+// TestScenarioRoundIDKeySeparation round_id 字符集受限且轮次状态ID以 ':' 分隔，
+// 开启轮次 "claims_{R}" 不会覆盖轮次 R 的案件索引
+func TestScenarioRoundIDKeySeparation(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).
+		ExpectSuccess().ExpectWrite(string(getRoundClaimsStateID(scenarioRoundID)))
+	before, versionBefore, _ := s.Host().State(string(getRoundClaimsStateID(scenarioRoundID)))
+
+	nextStart := s.Now() + testPlan.SettlementPeriod
+	openParams := func(roundID string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","period_start":%d,"period_end":%d}`,
+			scenarioPlanID, roundID, nextStart, nextStart+testPlan.SettlementPeriod)
+	}
+	for _, roundID := range []string{"", "a:b", "round 1", strings.Repeat("r", MAX_ROUND_ID_LENGTH+1)} {
+		s.As(fixtures.Operator()).Call("OpenRound", openParams(roundID)).ExpectError(framework.ERROR_INVALID_PARAMS)
+	}
+	s.As(fixtures.Operator()).Call("AdvanceRound", `{"plan_id":"`+scenarioPlanID+`","next_round_id":"a:b"}`).
+		ExpectError(framework.ERROR_INVALID_PARAMS)
+
+	s.As(fixtures.Operator()).Call("OpenRound", openParams("claims_"+scenarioRoundID)).
+		ExpectSuccess().ExpectWrite(string(getRoundStateID("claims_" + scenarioRoundID)))
+	after, versionAfter, _ := s.Host().State(string(getRoundClaimsStateID(scenarioRoundID)))
+	if string(after) != string(before) || versionAfter != versionBefore {
+		t.Fatalf("round claims_%s overwrote the claim index of round %s", scenarioRoundID, scenarioRoundID)
+	}
+
+	seen := map[string]string{}
+	for _, roundID := range []string{"R1", "claims_R1", "arrears_R1", "snapshot_R1", "paid_R1"} {
+		for _, id := range [][]byte{getRoundStateID(roundID), getRoundClaimsStateID(roundID), getRoundArrearsStateID(roundID),
+			getRoundSnapshotStateID(roundID), getRoundPaidStateID(roundID)} {
+			if prev, ok := seen[string(id)]; ok {
+				t.Errorf("rounds %q and %q share state ID %q", prev, roundID, id)
+			}
+			seen[string(id)] = roundID
+		}
+	}
+}
+
+// TestScenarioCategoryAnnualLimit 同一被保人同一类别的第二笔批准超过年度累计上限被拒绝，调低金额后通过并可给付
+func TestScenarioCategoryAnnualLimit(t *testing.T) {
+	s := newPlanScenario(t, scenarioCategories)
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_a1", "accident", 100000)).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_a2", "accident", 100000)).ExpectSuccess()
+	s.As(fixtures.Bob()).Call("SubmitClaim", categoryClaimParams(s, "claim_b1", "accident", 100000)).ExpectSuccess()
+
+	usageStateID := string(getCategoryUsageStateID(fixtures.Alice(), "accident", calendarYear(s.Now())))
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_a1", 100000)).ExpectSuccess().ExpectWrite(usageStateID)
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_a2", 100000)).
+		ExpectError(framework.ERROR_QUOTA_EXCEEDED).
+		Expect(expectReturn(map[string]string{"error": CATEGORY_REJECT_ANNUAL_LIMIT, "approved": "100000", "annual_limit": "150000"}))
+	// 额度按被保人计算，Bob 不受 Alice 已用额度影响
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_b1", 100000)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_a2", 50000)).ExpectSuccess()
+
+	s.As(fixtures.Alice()).Call("GetMemberInfo", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(fixtures.Alice()))).
+		ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"category_headroom.0.category_id": "accident",
+			"category_headroom.0.year":        "2025",
+			"category_headroom.0.approved":    "150000",
+			"category_headroom.0.remaining":   "0",
+			"category_headroom.1.remaining":   "300000",
+		}))
+
+	pool := fixtures.Pool()
+	s.Fund(pool, "", 150000)
+	s.As(fixtures.Operator()).Call("Payout", fmt.Sprintf(`{"plan_id":"%s","claim_id":"claim_a1","from":"%s","beneficiary":"%s","amount":100000,"payout_id":"payout_a1"}`,
+		scenarioPlanID, fixtures.Base58(pool), fixtures.Base58(fixtures.Alice()))).ExpectSuccess().ExpectWrite(usageStateID)
+	if data, _, _ := s.Host().State(usageStateID); decodeCategoryUsage(data) != (categoryUsage{Approved: 150000, Paid: 100000}) {
+		t.Errorf("alice accident usage = %+v, want approved 150000 paid 100000", decodeCategoryUsage(data))
+	}
+}
+
+// TestScenarioExcludedCategory 被除外的成员在该类别下报案返回结构化错误，其他类别与其他成员不受影响
+func TestScenarioExcludedCategory(t *testing.T) {
+	s := newPlanScenario(t, scenarioCategories)
+	exclude := fmt.Sprintf(`{"plan_id":"%s","member":"%s","category_id":"accident","reason":"pre-existing condition"}`,
+		scenarioPlanID, fixtures.Base58(fixtures.Alice()))
+
+	s.As(fixtures.Bob()).Call("ExcludeCategoryForMember", exclude).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("ExcludeCategoryForMember", strings.Replace(exclude, "accident", "dental", 1)).
+		ExpectError(framework.ERROR_INVALID_PARAMS)
+	excludedAt := s.Now()
+	s.As(fixtures.Operator()).Call("ExcludeCategoryForMember", exclude).
+		ExpectSuccess().ExpectEvent(EVENT_CATEGORY_EXCLUDED).ExpectWrite(string(getMemberExclusionsStateID(fixtures.Alice())))
+
+	s.AdvanceTime(fixtures.Days(1))
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_excluded", "accident", 1000)).
+		ExpectError(framework.ERROR_PERMISSION_DENIED).
+		Expect(expectReturn(map[string]string{
+			"error":       CATEGORY_REJECT_EXCLUDED,
+			"category_id": "accident",
+			"reason":      "pre-existing condition",
+			"excluded_at": fmt.Sprint(excludedAt),
+		}))
+	if _, _, ok := s.Host().State(string(getClaimStateID("claim_excluded"))); ok {
+		t.Fatal("excluded claim was committed")
+	}
+
+	s.As(fixtures.Bob()).Call("SubmitClaim", categoryClaimParams(s, "claim_bob", "accident", 1000)).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("GetMemberInfo", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(fixtures.Alice()))).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"exclusions.0.category_id": "accident", "exclusions.1": "<nil>"}))
+	s.AdvanceTime(fixtures.Days(90))
+	s.As(fixtures.Alice()).Call("SubmitClaim", categoryClaimParams(s, "claim_ci", "critical_illness", 1000)).ExpectSuccess()
+}
+
+// approveNewMember 新成员加入后由 operator 审核激活，返回审核步骤
+func approveNewMember(s *fwtesting.Scenario, operator, member framework.Address) *fwtesting.Step {
+	s.As(member).Call("Join", `{"plan_id":"`+scenarioPlanID+`"}`).ExpectSuccess()
+	return s.As(operator).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(member)))
+}
+
+// rotateParams 角色轮换参数
+func rotateParams(role string, newAddress framework.Address) string {
+	return fmt.Sprintf(`{"plan_id":"%s","role":"%s","new_address":"%s"}`, scenarioPlanID, role, fixtures.Base58(newAddress))
+}
+
+// TestScenarioOperatorKeyRotation 轮换待接受期间新旧 operator 均可审核成员，新地址接受后旧地址失效
+func TestScenarioOperatorKeyRotation(t *testing.T) {
+	s := newMutualAidScenario(t)
+	newOperator := framework.Address{0x0e, 0x01}
+
+	s.As(fixtures.Alice()).Call("RotateRoleKey", rotateParams(ROLE_OPERATOR, newOperator)).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("RotateRoleKey", rotateParams(ROLE_OPERATOR, newOperator)).
+		ExpectSuccess().ExpectEvent("RoleKeyRotationStarted")
+
+	// 待接受期间两个密钥都有效
+	approveNewMember(s, fixtures.Operator(), framework.Address{0xd1}).ExpectSuccess()
+	approveNewMember(s, newOperator, framework.Address{0xd2}).ExpectSuccess()
+
+	s.AdvanceTime(fixtures.Days(1))
+	s.As(fixtures.Alice()).Call("AcceptRoleKey", `{"plan_id":"`+scenarioPlanID+`","role":"operator"}`).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(newOperator).Call("AcceptRoleKey", `{"plan_id":"`+scenarioPlanID+`","role":"operator"}`).
+		ExpectSuccess().ExpectEvent("RoleKeyRotated").ExpectWrite(planKey(STATE_OPERATOR))
+
+	approveNewMember(s, fixtures.Operator(), framework.Address{0xd3}).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(newOperator).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(framework.Address{0xd3}))).
+		ExpectSuccess()
+	if data, _, _ := s.Host().State(planKey(STATE_OPERATOR)); string(data) != string(newOperator.ToBytes()) {
+		t.Errorf("operator state = %x, want %x", data, newOperator.ToBytes())
+	}
+}
+
+// TestScenarioOperatorEmergencyRotation operator_admin 紧急轮换撤销泄露密钥发起的待接受轮换
+func TestScenarioOperatorEmergencyRotation(t *testing.T) {
+	admin := fixtures.Carol()
+	s := newPlanScenario(t, fmt.Sprintf(`,"operator_admin":"%s"`, fixtures.Base58(admin)))
+	attacker := framework.Address{0xba, 0xd0}
+	newOperator := framework.Address{0x0e, 0x02}
+
+	// 泄露的 operator 密钥发起指向攻击者的轮换，攻击者在待接受期间即可行使 operator 权限
+	s.As(fixtures.Operator()).Call("RotateRoleKey", rotateParams(ROLE_OPERATOR, attacker)).ExpectSuccess()
+	approveNewMember(s, attacker, framework.Address{0xd1}).ExpectSuccess()
+
+	s.As(fixtures.Operator()).Call("EmergencyRotateRoleKey", rotateParams(ROLE_OPERATOR, newOperator)).
+		ExpectError(framework.ERROR_UNAUTHORIZED)
+	step := s.As(admin).Call("EmergencyRotateRoleKey", rotateParams(ROLE_OPERATOR, newOperator)).
+		ExpectSuccess().ExpectWrite(planKey(STATE_OPERATOR))
+	if event, ok := step.Event("RoleKeyRotated"); !ok || event.Data["mode"] != "emergency" || event.Data["revoked_pending"] != fixtures.Base58(attacker) {
+		t.Errorf("RoleKeyRotated event = %+v (present %v), want emergency revoking the attacker", event.Data, ok)
+	}
+
+	s.As(attacker).Call("AcceptRoleKey", `{"plan_id":"`+scenarioPlanID+`","role":"operator"}`).ExpectError(framework.ERROR_NOT_FOUND)
+	approveNewMember(s, attacker, framework.Address{0xd2}).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(framework.Address{0xd2}))).
+		ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(newOperator).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(framework.Address{0xd2}))).
+		ExpectSuccess()
+}
+
+// TestScenarioOperatorStoredValueLength operator 状态为 20 字节、超长或尾部零字节被去除时，checkOperator 与 GetPlanInfo 均按前 20 字节解析
+func TestScenarioOperatorStoredValueLength(t *testing.T) {
+	s := newMutualAidScenario(t)
+	operator := framework.Address{0x0e, 0x01} // 尾部零字节在链上读取时被去除
+	tests := []struct {
+		name   string
+		value  []byte
+		member framework.Address
+	}{
+		{"20 bytes", operator.ToBytes(), framework.Address{0xd1}},
+		{"over-length", append(append([]byte{}, operator.ToBytes()...), 0xFF, 0xFF), framework.Address{0xd2}},
+	}
+	for _, tt := range tests {
+		s.Host().SetState(planKey(STATE_OPERATOR), tt.value, 2)
+		approveNewMember(s, operator, tt.member).ExpectSuccess()
+		approveNewMember(s, fixtures.Operator(), framework.Address{0xd3, tt.member[0]}).ExpectError(framework.ERROR_UNAUTHORIZED)
+		s.As(operator).Call("GetPlanInfo", `{"plan_id":"`+scenarioPlanID+`"}`).
+			ExpectSuccess().Expect(expectReturn(map[string]string{"operator": fixtures.Base58(operator)}))
+	}
+}
+
+// TestScenarioPreviewSettlement 结算预览不写入状态、不发出事件，返回值与随后的 SettleRound 一致
+func TestScenarioPreviewSettlement(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.Fund(pool, "", scenarioApproved*8/10)
+
+	previewParams := fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s"}`, scenarioPlanID, scenarioRoundID, fixtures.Base58(pool))
+	roundBefore, versionBefore, _ := s.Host().State(string(getRoundStateID(scenarioRoundID)))
+	noSideEffects := func(st *fwtesting.Step) error {
+		if len(st.Result.Writes) != 0 || len(st.Result.Events) != 0 {
+			return fmt.Errorf("preview wrote %d states and emitted %d events, want none", len(st.Result.Writes), len(st.Result.Events))
+		}
+		return nil
+	}
+
+	// 轮次未到期：提示仍可能有案件归入，资金池只覆盖 80%
+	s.As(fixtures.Operator()).Call("PreviewSettlement", previewParams).
+		ExpectSuccess().Expect(noSideEffects).
+		Expect(expectReturn(map[string]string{
+			"status":                  ROUND_STATUS_SETTLED,
+			"per_capita_contribution": strconv.Itoa(scenarioPerCapita),
+			"claims.0.claim_id":       scenarioClaimID,
+			"tier_dues.0.due":         strconv.Itoa(scenarioPerCapita),
+			"pool_balance":            strconv.Itoa(scenarioApproved * 8 / 10),
+			"warnings.0":              "round period has not ended; more approved claims may still join this round",
+			"warnings.1":              "pool balance covers only 80% of projected payouts",
+		}))
+	roundAfter, versionAfter, _ := s.Host().State(string(getRoundStateID(scenarioRoundID)))
+	if string(roundAfter) != string(roundBefore) || versionAfter != versionBefore {
+		t.Error("preview changed the round record")
+	}
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	preview := s.As(fixtures.Alice()).Call("PreviewSettlement", previewParams).ExpectSuccess().Expect(noSideEffects)
+	settle := s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).ExpectSuccess()
+
+	var previewed, settled map[string]interface{}
+	if err := json.Unmarshal([]byte(preview.Return()), &previewed); err != nil {
+		t.Fatalf("preview return is not JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(settle.Return()), &settled); err != nil {
+		t.Fatalf("settle return is not JSON: %v", err)
+	}
+	for key, want := range settled {
+		if got := previewed[key]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("preview %s = %v, SettleRound returned %v", key, got, want)
+		}
+	}
+
+	// 已结算的轮次不能再预览
+	s.As(fixtures.Operator()).Call("PreviewSettlement", previewParams).ExpectError(framework.ERROR_INVALID_STATE)
+}
+
+// TestScenarioInitializeFieldErrors 服务费率超过 10000 bp、结算周期为 0、保障金额或最少成员数为负时初始化失败，返回值指明出错字段
+func TestScenarioInitializeFieldErrors(t *testing.T) {
+	tests := []struct {
+		field  string
+		params string
+	}{
+		{"service_fee_bp", `"coverage_amount":300000,"service_fee_bp":10001,"settlement_period":2592000`},
+		{"service_fee_bp", `"coverage_amount":300000,"service_fee_bp":-800,"settlement_period":2592000`},
+		{"settlement_period", `"coverage_amount":300000,"service_fee_bp":800,"settlement_period":0`},
+		{"settlement_period", `"coverage_amount":300000,"service_fee_bp":800`},
+		{"waiting_period", `"coverage_amount":300000,"settlement_period":2592000,"waiting_period":-86400`},
+		{"coverage_amount", `"coverage_amount":-300000,"settlement_period":2592000`},
+		{"coverage_amount", `"coverage_amount":"3e5","settlement_period":2592000`},
+		{"coverage_amount", `"settlement_period":2592000`},
+		{"min_members", `"coverage_amount":300000,"settlement_period":2592000,"min_members":-1`},
+		{"min_members", `"coverage_amount":300000,"settlement_period":2592000,"min_members":1.5`},
+	}
+	for _, tt := range tests {
+		s := fwtesting.NewScenario(t, mutualAidExports)
+		s.As(fixtures.Operator()).Call("Initialize", fmt.Sprintf(`{"plan_id":"%s","name":"plan",%s}`, scenarioPlanID, tt.params)).
+			ExpectError(framework.ERROR_INVALID_PARAMS).
+			Expect(expectReturn(map[string]string{"error": "ERROR_INVALID_PARAMS", "field": tt.field}))
+		if data, _, _ := s.Host().State(planKey(STATE_PLAN_CONFIG)); len(data) != 0 {
+			t.Errorf("%s: rejected Initialize wrote plan_config", tt.field)
+		}
+	}
+}
+
+// TestRequireActiveMemberStatuses 成员记录不存在返回 ERROR_NOT_FOUND，非 ACTIVE 状态返回 ERROR_UNAUTHORIZED
+func TestRequireActiveMemberStatuses(t *testing.T) {
+	host := fwtesting.NewHost(t)
+	usePlan(scenarioPlanID)
+	tests := []struct {
+		status string
+		want   uint32
+	}{
+		{"", framework.ERROR_NOT_FOUND},
+		{MEMBER_STATUS_PENDING, framework.ERROR_UNAUTHORIZED},
+		{MEMBER_STATUS_ACTIVE, framework.SUCCESS},
+		{MEMBER_STATUS_SUSPENDED, framework.ERROR_UNAUTHORIZED},
+		{MEMBER_STATUS_EXITED, framework.ERROR_UNAUTHORIZED},
+		{MEMBER_STATUS_BLACKLISTED, framework.ERROR_UNAUTHORIZED},
+	}
+	for i, tt := range tests {
+		member := framework.Address{0xe0, byte(i)}
+		record := encodeMember(tt.status, fixtures.Epoch, 0, 0, 0, 0, 0, 1)
+		if tt.status != "" {
+			host.SetState(string(getMemberStateID(member)), record, 1)
+		}
+		data, code := requireActiveMember(member)
+		if code != tt.want {
+			t.Errorf("status %q: requireActiveMember() code = %d, want %d", tt.status, code, tt.want)
+		}
+		if (code == framework.SUCCESS) != (string(data) == string(record)) {
+			t.Errorf("status %q: requireActiveMember() data = %x", tt.status, data)
+		}
+	}
+}
+
+// TestScenarioMemberGuardConsistent SubmitClaim、Exit、PayContribution 对非成员与非 ACTIVE 成员返回相同的错误码
+func TestScenarioMemberGuardConsistent(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	pending := framework.Address{0xd5}
+	s.As(pending).Call("Join", `{"plan_id":"`+scenarioPlanID+`"}`).ExpectSuccess()
+	s.As(fixtures.Bob()).Call("Exit", `{"plan_id":"`+scenarioPlanID+`"}`).ExpectSuccess()
+
+	exports := map[string]string{
+		"SubmitClaim": submitClaimParams(s),
+		"Exit":        `{"plan_id":"` + scenarioPlanID + `"}`,
+		"PayContribution": fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":100,"contribution_id":"ctrb_guard"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(fixtures.Pool())),
+	}
+	for _, name := range []string{"SubmitClaim", "Exit", "PayContribution"} {
+		s.As(framework.Address{0xd6}).Call(name, exports[name]).ExpectError(framework.ERROR_NOT_FOUND)
+		s.As(pending).Call(name, exports[name]).ExpectError(framework.ERROR_UNAUTHORIZED)
+		s.As(fixtures.Bob()).Call(name, exports[name]).ExpectError(framework.ERROR_UNAUTHORIZED)
+	}
+}
+
+// TestScenarioSuspendAndBlacklistMember 暂停与拉黑的成员不计入活跃成员数，不能报案、缴费或重新加入；恢复后重新计入
+func TestScenarioSuspendAndBlacklistMember(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	statusParams := func(m framework.Address, reason string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","member":"%s","reason":"%s"}`, scenarioPlanID, fixtures.Base58(m), reason)
+	}
+	payParams := fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":100,"contribution_id":"ctrb_suspended"}`,
+		scenarioPlanID, scenarioRoundID, fixtures.Base58(fixtures.Pool()))
+	joinParams := `{"plan_id":"` + scenarioPlanID + `"}`
+
+	// 仅 operator 可调用，原因必填
+	s.As(fixtures.Alice()).Call("SuspendMember", statusParams(fixtures.Bob(), "fraud investigation")).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("SuspendMember", statusParams(fixtures.Bob(), "")).ExpectError(framework.ERROR_INVALID_PARAMS)
+	s.As(fixtures.Operator()).Call("ResumeMember", statusParams(fixtures.Bob(), "not suspended")).ExpectError(framework.ERROR_INVALID_STATE)
+
+	// 暂停：移出活跃成员数，不能报案、缴费或通过重新加入解除暂停
+	s.As(fixtures.Operator()).Call("SuspendMember", statusParams(fixtures.Bob(), "fraud investigation")).
+		ExpectSuccess().ExpectEvent(EVENT_MEMBER_SUSPENDED).Expect(expectReturn(map[string]string{
+		"status":              MEMBER_STATUS_SUSPENDED,
+		"previous_status":     MEMBER_STATUS_ACTIVE,
+		"reason":              "fraud investigation",
+		"member_count_active": "2",
+	}))
+	s.As(fixtures.Operator()).Call("SuspendMember", statusParams(fixtures.Bob(), "again")).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Bob()).Call("SubmitClaim", submitClaimParams(s)).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Bob()).Call("PayContribution", payParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Bob()).Call("Join", joinParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+
+	// 恢复：重新计入活跃成员数
+	s.As(fixtures.Operator()).Call("ResumeMember", statusParams(fixtures.Bob(), "investigation closed")).
+		ExpectSuccess().ExpectEvent(EVENT_MEMBER_RESUMED).
+		Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_ACTIVE, "member_count_active": "3"}))
+
+	// 拉黑：ACTIVE 成员移出活跃成员数，PENDING 成员不影响计数；拉黑为终态
+	s.As(fixtures.Operator()).Call("BlacklistMember", statusParams(fixtures.Carol(), "fraudulent claim")).
+		ExpectSuccess().ExpectEvent(EVENT_MEMBER_BLACKLISTED).
+		Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_BLACKLISTED, "member_count_active": "2"}))
+	pending := framework.Address{0xd7}
+	s.As(pending).Call("Join", joinParams).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("BlacklistMember", statusParams(pending, "identity mismatch")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"previous_status": MEMBER_STATUS_PENDING, "member_count_active": "2"}))
+	for _, name := range []string{"ResumeMember", "SuspendMember", "BlacklistMember"} {
+		s.As(fixtures.Operator()).Call(name, statusParams(fixtures.Carol(), "retry")).ExpectError(framework.ERROR_INVALID_STATE)
+	}
+	s.As(fixtures.Carol()).Call("SubmitClaim", submitClaimParams(s)).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Carol()).Call("PayContribution", payParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Carol()).Call("Join", joinParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Alice()).Call("GetMemberInfo", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(fixtures.Carol()))).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_BLACKLISTED}))
+}
+
+// TestScenarioEstimateContribution 估算按当前已批准案件计算，没有新的批准案件时与随后 SettleRound 的人均分摊一致
+func TestScenarioEstimateContribution(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	estimateParams := fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)
+
+	// 尚无批准案件
+	s.As(fixtures.Alice()).Call("EstimateContribution", estimateParams).
+		ExpectSuccess().Expect(expectReturn(map[string]string{
+		"approved_claims_count":   "0",
+		"per_capita_contribution": "0",
+		"member_due":              "0",
+	}))
+
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+
+	estimate := s.As(fixtures.Bob()).Call("EstimateContribution", estimateParams).
+		ExpectSuccess().Expect(func(st *fwtesting.Step) error {
+		if len(st.Result.Writes) != 0 || len(st.Result.Events) != 0 {
+			return fmt.Errorf("estimate wrote %d states and emitted %d events, want none", len(st.Result.Writes), len(st.Result.Events))
+		}
+		return nil
+	}).Expect(expectReturn(map[string]string{
+		"approved_claims_count":   "1",
+		"total_approved_payout":   strconv.Itoa(scenarioApproved),
+		"member_count_active":     "3",
+		"per_capita_contribution": strconv.Itoa(scenarioPerCapita),
+		"tier":                    "0",
+		"member_due":              strconv.Itoa(scenarioPerCapita),
+	}))
+
+	// 非成员只返回人均估算
+	outsider := s.As(framework.Address{0xd7}).Call("EstimateContribution", estimateParams).ExpectSuccess()
+	if strings.Contains(outsider.Return(), "member_due") {
+		t.Errorf("estimate for non-member = %s, want no member_due", outsider.Return())
+	}
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	settle := s.As(fixtures.Operator()).Call("SettleRound", estimateParams).ExpectSuccess()
+	var estimated, settled map[string]interface{}
+	if err := json.Unmarshal([]byte(estimate.Return()), &estimated); err != nil {
+		t.Fatalf("estimate return is not JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(settle.Return()), &settled); err != nil {
+		t.Fatalf("settle return is not JSON: %v", err)
+	}
+	for _, key := range []string{"per_capita_contribution", "total_approved_payout", "total_with_fee", "effective_service_fee_bp", "member_count_active"} {
+		if fmt.Sprint(estimated[key]) != fmt.Sprint(settled[key]) {
+			t.Errorf("estimate %s = %v, SettleRound returned %v", key, estimated[key], settled[key])
+		}
+	}
+
+	// 已结算的轮次不再估算
+	s.As(fixtures.Alice()).Call("EstimateContribution", estimateParams).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Alice()).Call("EstimateContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"missing"}`, scenarioPlanID)).
+		ExpectError(framework.ERROR_NOT_FOUND)
+}
+
+// TestScenarioConcurrentPlans 同一合约中的两个计划各自维护成员、operator、轮次与案件，同名轮次与案件互不干扰
+func TestScenarioConcurrentPlans(t *testing.T) {
+	s := newMutualAidScenario(t)
+	const planB = "plan_xianghubao_002"
+	operatorB := framework.Address{0x0e, 0x0b}
+	planParams := func(planID string, extra string) string {
+		return fmt.Sprintf(`{"plan_id":"%s"%s}`, planID, extra)
+	}
+
+	s.As(fixtures.Operator()).Call("Initialize", planParams(scenarioPlanID, `,"name":"again","coverage_amount":1,"settlement_period":2592000`)).
+		ExpectError(framework.ERROR_ALREADY_EXISTS)
+	s.As(operatorB).Call("Initialize", planParams(planB, fmt.Sprintf(`,"name":"plan b","coverage_amount":%d,"service_fee_bp":0,"settlement_period":%d,"min_members":1`,
+		testPlan.CoverageAmount, testPlan.SettlementPeriod))).ExpectSuccess()
+
+	// 计划 B 只有 Alice 一名成员，operator 权限不跨计划
+	s.As(fixtures.Alice()).Call("Join", planParams(planB, "")).ExpectSuccess()
+	approveB := planParams(planB, `,"member":"`+fixtures.Base58(fixtures.Alice())+`"`)
+	s.As(fixtures.Operator()).Call("ApproveMember", approveB).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(operatorB).Call("ApproveMember", approveB).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("GetPlanInfo", planParams(scenarioPlanID, "")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"member_count_active": "3", "operator": fixtures.Base58(fixtures.Operator())}))
+	s.As(operatorB).Call("GetPlanInfo", planParams(planB, "")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"member_count_active": "1", "operator": fixtures.Base58(operatorB)}))
+
+	// 同名轮次与案件分别存在于两个计划中
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(operatorB).Call("OpenRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","period_start":%d,"period_end":%d}`,
+		planB, scenarioRoundID, s.Now(), s.Now()+testPlan.SettlementPeriod)).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("SubmitClaim", strings.Replace(submitClaimParams(s), scenarioPlanID, planB, 1)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+
+	claimB := fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s"}`, planB, scenarioClaimID)
+	s.As(fixtures.Alice()).Call("GetClaimInfo", claimB).ExpectSuccess().Expect(expectReturn(map[string]string{"status": CLAIM_STATUS_SUBMITTED}))
+	s.As(fixtures.Alice()).Call("EstimateContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, planB, scenarioRoundID)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"approved_claims_count": "0", "member_count_active": "1"}))
+	s.As(fixtures.Alice()).Call("EstimateContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"approved_claims_count": "1", "per_capita_contribution": strconv.Itoa(scenarioPerCapita)}))
+
+	// 退出计划 B 不影响 Alice 在计划 A 中的成员资格
+	s.As(fixtures.Alice()).Call("Exit", planParams(planB, "")).ExpectSuccess()
+	memberParams := `,"member":"` + fixtures.Base58(fixtures.Alice()) + `"`
+	s.As(fixtures.Alice()).Call("GetMemberInfo", planParams(planB, memberParams)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_EXITED}))
+	s.As(fixtures.Alice()).Call("GetMemberInfo", planParams(scenarioPlanID, memberParams)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_ACTIVE}))
+
+	usePlan(planB)
+	if _, _, ok := s.Host().State(string(getRoundStateID(scenarioRoundID))); !ok {
+		t.Errorf("round state for %s not written under plan %s", scenarioRoundID, planB)
+	}
+}
+
+// TestPlanNamespaceIsolation 计划ID互为前后缀（X / cap_X、P / claims_P、X / admin_X）时状态ID与角色名称不重合，
+// 非法 plan_id 返回 ERROR_INVALID_PARAMS，未初始化的计划拒绝写入
+func TestPlanNamespaceIsolation(t *testing.T) {
+	alice := fixtures.Alice()
+	stateIDs := func(planID string) map[string]bool {
+		if code := usePlan(planID); code != framework.SUCCESS {
+			t.Fatalf("usePlan(%q) = %d", planID, code)
+		}
+		ids := map[string]bool{
+			planKey(STATE_PLAN_CONFIG):              true,
+			planKey(STATE_MEMBER_COUNT):             true,
+			planRole(ROLE_OPERATOR):                 true,
+			"role:" + planRole(ROLE_OPERATOR_ADMIN): true,
+		}
+		for _, id := range [][]byte{
+			getMemberStateID(alice), getMemberCapStateID(alice), getMemberRoundDueStateID(alice, "R"),
+			getMemberMonthStatStateID(alice, "202501"), getMemberExclusionsStateID(alice), getMembersActivePosStateID(alice),
+			getRoundStateID("R"), getRoundClaimsStateID("R"), getRoundArrearsStateID("R"), getRoundSnapshotStateID("R"), getRoundPaidStateID("R"),
+		} {
+			ids[string(id)] = true
+		}
+		return ids
+	}
+	for _, pair := range [][2]string{{"X", "cap_X"}, {"P", "claims_P"}, {"X", "admin_X"}, {"X", "X_z"}} {
+		a, b := stateIDs(pair[0]), stateIDs(pair[1])
+		for id := range a {
+			if b[id] {
+				t.Errorf("plans %q and %q share state ID %q", pair[0], pair[1], id)
+			}
+		}
+	}
+
+	// 计划 cap_X 的成员记录不会出现在计划 X 中
+	s := fwtesting.NewScenario(t, mutualAidExports)
+	initParams := func(planID string) string {
+		return fmt.Sprintf(`{"plan_id":%q,"name":"plan","coverage_amount":%d,"settlement_period":%d,"min_members":1}`,
+			planID, testPlan.CoverageAmount, testPlan.SettlementPeriod)
+	}
+	memberParams := func(planID string) string {
+		return fmt.Sprintf(`{"plan_id":%q,"member":%q}`, planID, fixtures.Base58(alice))
+	}
+	s.As(fixtures.Operator()).Call("Initialize", initParams("X")).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("Initialize", initParams("cap_X")).ExpectSuccess()
+	s.As(alice).Call("Join", `{"plan_id":"cap_X"}`).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ApproveMember", memberParams("cap_X")).ExpectSuccess()
+	s.As(alice).Call("GetMemberInfo", memberParams("X")).ExpectError(framework.ERROR_NOT_FOUND)
+	s.As(alice).Call("Join", `{"plan_id":"X"}`).ExpectSuccess()
+	s.As(alice).Call("GetMemberInfo", memberParams("cap_X")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_ACTIVE}))
+
+	for _, bad := range []string{"", "a:b", "plan x", strings.Repeat("p", MAX_PLAN_ID_LENGTH+1)} {
+		s.As(fixtures.Operator()).Call("Initialize", initParams(bad)).ExpectError(framework.ERROR_INVALID_PARAMS)
+		s.As(alice).Call("Join", fmt.Sprintf(`{"plan_id":%q}`, bad)).ExpectError(framework.ERROR_INVALID_PARAMS)
+		s.As(alice).Call("GetMemberInfo", memberParams(bad)).ExpectError(framework.ERROR_INVALID_PARAMS)
+	}
+	s.As(alice).Call("Join", `{"plan_id":"P"}`).ExpectError(framework.ERROR_NOT_FOUND)
+	usePlan("P")
+	if _, _, ok := s.Host().State(string(getMemberStateID(alice))); ok {
+		t.Error("Join wrote a member record under an uninitialized plan")
+	}
+}
+
+// TestScenarioFinalizePlan 存在 OPEN 轮次或待给付案件时拒绝终结；给付完成后终结，结余按人均分给活跃成员，之后拒绝写操作
+func TestScenarioFinalizePlan(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+	finalizeParams := fmt.Sprintf(`{"plan_id":"%s","pool":"%s"}`, scenarioPlanID, fixtures.Base58(pool))
+
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("FinalizePlan", finalizeParams).
+		ExpectError(framework.ERROR_INVALID_STATE).
+		Expect(expectReturn(map[string]string{"error": FINALIZE_REJECT_ROUND_OPEN, "round_id": scenarioRoundID}))
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).ExpectSuccess()
+	for i, m := range []framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()} {
+		s.As(m).Call("PayContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"ctrb_%d"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(pool), scenarioPerCapita, i)).ExpectSuccess()
+	}
+	s.As(fixtures.Operator()).Call("FinalizePlan", finalizeParams).
+		ExpectError(framework.ERROR_INVALID_STATE).
+		Expect(expectReturn(map[string]string{"error": FINALIZE_REJECT_CLAIMS_UNPAID, "approved_unpaid_claims": "1"}))
+
+	// 给付后资金池结余 3 * 32400 - 90000 = 7200，三名成员同档，每人 2400
+	s.As(fixtures.Operator()).Call("Payout", payoutParams(pool)).ExpectSuccess()
+	const surplus, share = 3*scenarioPerCapita - scenarioApproved, (3*scenarioPerCapita - scenarioApproved) / 3
+	before := s.Host().Balance(fixtures.Bob(), "")
+	s.As(fixtures.Alice()).Call("FinalizePlan", finalizeParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("FinalizePlan", finalizeParams).
+		ExpectSuccess().ExpectEvent(EVENT_PLAN_FINALIZED).
+		Expect(expectReturn(map[string]string{
+			"status":                 PLAN_STATUS_FINALIZED,
+			"surplus":                strconv.Itoa(surplus),
+			"distributed":            strconv.Itoa(surplus),
+			"distributions.1.amount": strconv.Itoa(share),
+		}))
+	if got := s.Host().Balance(pool, ""); got != 0 {
+		t.Errorf("pool balance after finalize = %d, want 0", got)
+	}
+	if got := s.Host().Balance(fixtures.Bob(), ""); got != before+share {
+		t.Errorf("bob balance after finalize = %d, want %d", got, before+share)
+	}
+
+	// 终结后写操作被拒绝，查询仍可用
+	s.As(framework.Address{0xd8}).Call("Join", `{"plan_id":"`+scenarioPlanID+`"}`).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Operator()).Call("OpenRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"round_after","period_start":%d,"period_end":%d}`,
+		scenarioPlanID, s.Now(), s.Now()+testPlan.SettlementPeriod)).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Operator()).Call("FinalizePlan", finalizeParams).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Alice()).Call("GetPlanInfo", `{"plan_id":"`+scenarioPlanID+`"}`).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": PLAN_STATUS_FINALIZED}))
+}
+
+// TestScenarioCloseRound 关闭已结算轮次：部分缴费与未缴费的成员记录欠费，已关闭轮次不再接受缴费
+func TestScenarioCloseRound(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+	closeParams := fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)
+	payParams := func(amount int, contributionID string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"%s"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(pool), amount, contributionID)
+	}
+
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("CloseRound", closeParams).ExpectError(framework.ERROR_INVALID_STATE)
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", closeParams).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("PayContribution", payParams(scenarioPerCapita, "ctrb_alice")).ExpectSuccess()
+	s.As(fixtures.Bob()).Call("PayContribution", payParams(10000, "ctrb_bob")).ExpectSuccess()
+
+	s.As(fixtures.Alice()).Call("CloseRound", closeParams).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Operator()).Call("CloseRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","members":["bad"]}`, scenarioPlanID, scenarioRoundID)).
+		ExpectError(framework.ERROR_INVALID_PARAMS).
+		Expect(expectReturn(map[string]string{"field": "members"}))
+
+	// Bob 少缴 32400 - 10000 = 22400，Carol 未缴 32400
+	const bobShortfall = scenarioPerCapita - 10000
+	s.As(fixtures.Operator()).Call("CloseRound", closeParams).
+		ExpectSuccess().ExpectEvent(EVENT_ROUND_CLOSED).
+		Expect(expectReturn(map[string]string{
+			"status":                      ROUND_STATUS_CLOSED,
+			"members_checked":             "3",
+			"delinquent_count":            "2",
+			"arrears_added":               strconv.Itoa(bobShortfall + scenarioPerCapita),
+			"delinquents.0.member":        fixtures.Base58(fixtures.Bob()),
+			"delinquents.0.arrears_added": strconv.Itoa(bobShortfall),
+		}))
+	s.As(fixtures.Bob()).Call("GetMemberInfo", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(fixtures.Bob()))).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"arrears_amount": strconv.Itoa(bobShortfall)}))
+	if value, _, ok := s.Host().State(string(getRoundArrearsStateID(scenarioRoundID))); !ok || framework.BytesToUint64(value) != bobShortfall+scenarioPerCapita {
+		t.Errorf("round_arrears = %d, want %d", framework.BytesToUint64(value), bobShortfall+scenarioPerCapita)
+	}
+
+	s.As(fixtures.Operator()).Call("CloseRound", closeParams).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Carol()).Call("PayContribution", payParams(scenarioPerCapita, "ctrb_carol")).ExpectError(framework.ERROR_INVALID_STATE)
+}
+
+// TestScenarioAdvanceRoundRecordsMemberArrears AdvanceRound 关闭缴费期轮次时与 CloseRound 相同将未缴金额计入成员欠费
+func TestScenarioAdvanceRoundRecordsMemberArrears(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+	advanceParams := func(nextRoundID string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","next_round_id":"%s"}`, scenarioPlanID, nextRoundID)
+	}
+	payParams := func(amount int, contributionID string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"%s"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(pool), amount, contributionID)
+	}
+	memberParams := func(m framework.Address) string {
+		return fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(m))
+	}
+
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("AdvanceRound", advanceParams("round_202502_01")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{
+		"settled_round_id":        scenarioRoundID,
+		"per_capita_contribution": strconv.Itoa(scenarioPerCapita),
+		"closed_round_id":         "",
+	}))
+	s.As(fixtures.Alice()).Call("PayContribution", payParams(scenarioPerCapita, "ctrb_alice")).ExpectSuccess()
+	s.As(fixtures.Bob()).Call("PayContribution", payParams(10000, "ctrb_bob")).ExpectSuccess()
+
+	// Bob 少缴 32400 - 10000 = 22400，Carol 未缴 32400
+	const bobShortfall = scenarioPerCapita - 10000
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("AdvanceRound", advanceParams("round_202503_01")).
+		ExpectSuccess().Expect(expectReturn(map[string]string{
+		"closed_round_id": scenarioRoundID,
+		"arrears_amount":  strconv.Itoa(bobShortfall + scenarioPerCapita),
+	}))
+	for m, want := range map[framework.Address]int{fixtures.Alice(): 0, fixtures.Bob(): bobShortfall, fixtures.Carol(): scenarioPerCapita} {
+		s.As(m).Call("GetMemberInfo", memberParams(m)).
+			ExpectSuccess().Expect(expectReturn(map[string]string{"arrears_amount": strconv.Itoa(want)}))
+	}
+	if value, _, ok := s.Host().State(string(getRoundArrearsStateID(scenarioRoundID))); !ok || framework.BytesToUint64(value) != bobShortfall+scenarioPerCapita {
+		t.Errorf("round_arrears = %d, want %d", framework.BytesToUint64(value), bobShortfall+scenarioPerCapita)
+	}
+
+	// 已由 AdvanceRound 关闭，CloseRound 不会重复计入
+	s.As(fixtures.Operator()).Call("CloseRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Carol()).Call("GetMemberInfo", memberParams(fixtures.Carol())).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"arrears_amount": strconv.Itoa(scenarioPerCapita)}))
+}
+
+// TestScenarioRejoinKeepsArrears 有欠费的成员退出后重新加入，新记录保留欠费
+func TestScenarioRejoinKeepsArrears(t *testing.T) {
+	s := newMutualAidScenario(t)
+	carolParams := fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, scenarioPlanID, fixtures.Base58(fixtures.Carol()))
+	planParams := `{"plan_id":"` + scenarioPlanID + `"}`
+
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("CloseRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).ExpectSuccess()
+
+	s.As(fixtures.Carol()).Call("Exit", planParams).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"arrears_amount": strconv.Itoa(scenarioPerCapita)}))
+	s.As(fixtures.Carol()).Call("Join", planParams).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_PENDING, "arrears_amount": strconv.Itoa(scenarioPerCapita)}))
+	s.As(fixtures.Operator()).Call("ApproveMember", carolParams).ExpectSuccess()
+	s.As(fixtures.Carol()).Call("GetMemberInfo", carolParams).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"status": MEMBER_STATUS_ACTIVE, "arrears_amount": strconv.Itoa(scenarioPerCapita)}))
+}
+
+// TestScenarioSettleRoundAggregatesClaims 结算汇总归入本轮的已批准案件：驳回的案件不计入，重复审核被拒绝且不重复计入
+func TestScenarioSettleRoundAggregatesClaims(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+
+	claims := []struct {
+		claimant framework.Address
+		claimID  string
+	}{
+		{fixtures.Alice(), "claim_a"},
+		{fixtures.Bob(), "claim_b"},
+		{fixtures.Carol(), "claim_c"},
+		{fixtures.Alice(), "claim_d"},
+	}
+	for _, c := range claims {
+		s.As(c.claimant).Call("SubmitClaim", fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","requested_amount":%d,"event_time":%d,"evidence_hash":"0xabc"}`,
+			scenarioPlanID, c.claimID, testPlan.CoverageAmount, s.Now())).ExpectSuccess()
+	}
+	rejectParams := func(claimID string) string {
+		return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","decision":"REJECT","reason":"not covered","investigation_hash":"0xdef"}`, scenarioPlanID, claimID)
+	}
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_a", 60000)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", rejectParams("claim_b")).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_c", 30000)).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ReviewClaim", rejectParams("claim_d")).ExpectSuccess()
+
+	// 已审核的案件不能再次审核，批准金额不会重复计入
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_a", 60000)).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_b", 50000)).ExpectError(framework.ERROR_INVALID_STATE)
+
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"status":                  ROUND_STATUS_SETTLED,
+			"total_approved_payout":   "90000",
+			"per_capita_contribution": strconv.Itoa(scenarioPerCapita),
+		})).
+		Expect(func(st *fwtesting.Step) error {
+			e, _ := st.Event(EVENT_ROUND_PAYOUT_SUMMARY)
+			want := `"claims":[{"approved_amount":60000,"claim_id":"claim_a"},{"approved_amount":30000,"claim_id":"claim_c"}],"claims_count":2`
+			if !strings.Contains(string(e.Payload), want) {
+				return fmt.Errorf("payout summary = %s, want claim_a and claim_c only", e.Payload)
+			}
+			return nil
+		})
+
+	s.As(fixtures.Alice()).Call("GetRoundInfo", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"total_approved_payout": "90000"}))
+}
+
+// TestScenarioClaimEventLog 理赔案件的提交、审核与给付事件写入事件日志，可按事件名与时间范围回查
+func TestScenarioClaimEventLog(t *testing.T) {
+	s := newMutualAidScenario(t)
+	pool := fixtures.Pool()
+
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	submittedAt := s.Now()
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+	s.AdvanceTime(fixtures.Days(1))
+	reviewedAt := s.Now()
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectSuccess()
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).ExpectSuccess()
+	for i, m := range []framework.Address{fixtures.Alice(), fixtures.Bob(), fixtures.Carol()} {
+		s.As(m).Call("PayContribution", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s","pool":"%s","amount":%d,"contribution_id":"ctrb_%d"}`,
+			scenarioPlanID, scenarioRoundID, fixtures.Base58(pool), scenarioPerCapita, i)).ExpectSuccess()
+	}
+	s.As(fixtures.Operator()).Call("Payout", payoutParams(pool)).ExpectSuccess()
+
+	s.As(fixtures.Alice()).Call("QueryEventLog", `{}`).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"events.0.event": "MutualAidClaimSubmitted",
+			"events.1.event": "MutualAidClaimReviewed",
+			"events.2.event": "MutualAidPayout",
+			"has_more":       "false",
+		}))
+	s.As(fixtures.Alice()).Call("QueryEventLog", `{"event":"MutualAidClaimReviewed"}`).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"events.0.seq":       "1",
+			"events.0.timestamp": strconv.FormatUint(reviewedAt, 10),
+			"events.1.seq":       "<nil>",
+		})).
+		Expect(func(st *fwtesting.Step) error {
+			if !strings.Contains(st.Return(), `\"claim_id\":\"`+scenarioClaimID+`\"`) {
+				return fmt.Errorf("reviewed event data missing claim_id: %s", st.Return())
+			}
+			return nil
+		})
+	s.As(fixtures.Alice()).Call("QueryEventLog", fmt.Sprintf(`{"from":%d,"to":%d,"limit":1}`, submittedAt, reviewedAt)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"events.0.event": "MutualAidClaimSubmitted",
+			"next_cursor":    "1",
+			"has_more":       "true",
+		}))
+	s.As(fixtures.Alice()).Call("QueryEventLog", fmt.Sprintf(`{"from":%d,"to":%d,"cursor":1}`, submittedAt, reviewedAt)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"events.0.event": "MutualAidClaimReviewed",
+			"has_more":       "false",
+		}))
+}
+
+func cancelClaimParams(claimID, reason string) string {
+	return fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","reason":"%s"}`, scenarioPlanID, claimID, reason)
+}
+
+// TestScenarioApplicantCancelsClaim 申请人可取消本人待审案件，其他成员不能取消，已取消案件不再可审核
+func TestScenarioApplicantCancelsClaim(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	s.As(fixtures.Alice()).Call("SubmitClaim", submitClaimParams(s)).ExpectSuccess()
+
+	s.As(fixtures.Bob()).Call("CancelClaim", cancelClaimParams(scenarioClaimID, "")).ExpectError(framework.ERROR_UNAUTHORIZED)
+	s.As(fixtures.Alice()).Call("CancelClaim", cancelClaimParams(scenarioClaimID, "")).
+		ExpectSuccess().ExpectEvent(EVENT_CLAIM_CANCELLED).
+		Expect(expectReturn(map[string]string{
+			"status":          CLAIM_STATUS_CANCELLED,
+			"previous_status": CLAIM_STATUS_SUBMITTED,
+			"by_operator":     "false",
+		}))
+
+	s.As(fixtures.Alice()).Call("CancelClaim", cancelClaimParams(scenarioClaimID, "")).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(scenarioClaimID, scenarioApproved)).ExpectError(framework.ERROR_INVALID_STATE)
+}
+
+// TestScenarioOperatorCancelsApprovedClaim operator 取消已结算轮次中的已批准案件，轮次批准总额随之扣除；
+// 申请人不能取消已批准案件
+func TestScenarioOperatorCancelsApprovedClaim(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	for _, c := range []struct {
+		claimant framework.Address
+		claimID  string
+		amount   uint64
+	}{{fixtures.Alice(), "claim_a", 60000}, {fixtures.Bob(), "claim_b", 30000}} {
+		s.As(c.claimant).Call("SubmitClaim", fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","requested_amount":%d,"event_time":%d,"evidence_hash":"0xabc"}`,
+			scenarioPlanID, c.claimID, testPlan.CoverageAmount, s.Now())).ExpectSuccess()
+		s.As(fixtures.Operator()).Call("ReviewClaim", approveParams(c.claimID, c.amount)).ExpectSuccess()
+	}
+	s.AdvanceTime(testPlan.SettlementPeriod)
+	s.As(fixtures.Operator()).Call("SettleRound", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"total_approved_payout": "90000"}))
+
+	s.As(fixtures.Alice()).Call("CancelClaim", cancelClaimParams("claim_a", "")).ExpectError(framework.ERROR_INVALID_STATE)
+	s.As(fixtures.Operator()).Call("CancelClaim", cancelClaimParams("claim_a", "")).ExpectError(framework.ERROR_INVALID_PARAMS)
+	s.As(fixtures.Operator()).Call("CancelClaim", cancelClaimParams("claim_a", "duplicate claim")).
+		ExpectSuccess().ExpectWrite(string(getRoundStateID(scenarioRoundID))).
+		Expect(expectReturn(map[string]string{
+			"status":                      CLAIM_STATUS_CANCELLED,
+			"previous_status":             CLAIM_STATUS_APPROVED,
+			"reason":                      "duplicate claim",
+			"by_operator":                 "true",
+			"round_total_approved_payout": "30000",
+		}))
+
+	s.As(fixtures.Alice()).Call("GetRoundInfo", fmt.Sprintf(`{"plan_id":"%s","round_id":"%s"}`, scenarioPlanID, scenarioRoundID)).
+		ExpectSuccess().Expect(expectReturn(map[string]string{"total_approved_payout": "30000"}))
+}
+
+// TestScenarioListClaims 按前缀分页列出计划案件：字典序翻页、状态过滤，其他计划（计划ID以本计划ID开头）的案件不出现
+func TestScenarioListClaims(t *testing.T) {
+	s := newMutualAidScenario(t)
+	s.AdvanceTime(fixtures.Days(8))
+	openScenarioRound(s)
+	for _, c := range []struct {
+		claimant framework.Address
+		claimID  string
+	}{{fixtures.Bob(), "claim_b"}, {fixtures.Alice(), "claim_a"}, {fixtures.Carol(), "claim_c"}} {
+		s.As(c.claimant).Call("SubmitClaim", fmt.Sprintf(`{"plan_id":"%s","claim_id":"%s","requested_amount":%d,"event_time":%d,"evidence_hash":"0xabc"}`,
+			scenarioPlanID, c.claimID, testPlan.CoverageAmount, s.Now())).ExpectSuccess()
+	}
+	s.As(fixtures.Operator()).Call("ReviewClaim", approveParams("claim_b", 30000)).ExpectSuccess()
+
+	// plan_xianghubao_001_z 以本计划ID开头，但命名空间 plan:{plan_id}: 在 ':' 处结束，其案件不在本计划的前缀范围内
+	other := scenarioPlanID + "_z"
+	s.As(fixtures.Operator()).Call("Initialize", fmt.Sprintf(
+		`{"plan_id":"%s","name":"other","coverage_amount":%d,"service_fee_bp":800,"settlement_period":%d,"waiting_period":0,"min_members":1}`,
+		other, testPlan.CoverageAmount, testPlan.SettlementPeriod)).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("Join", `{"plan_id":"`+other+`"}`).ExpectSuccess()
+	s.As(fixtures.Operator()).Call("ApproveMember", fmt.Sprintf(`{"plan_id":"%s","member":"%s"}`, other, fixtures.Base58(fixtures.Alice()))).ExpectSuccess()
+	s.As(fixtures.Alice()).Call("SubmitClaim", fmt.Sprintf(`{"plan_id":"%s","claim_id":"claim_x","requested_amount":1000,"event_time":%d,"evidence_hash":"0xabc"}`,
+		other, s.Now())).ExpectSuccess()
+
+	listParams := func(extra string) string {
+		return fmt.Sprintf(`{"plan_id":"%s"%s}`, scenarioPlanID, extra)
+	}
+	s.As(fixtures.Alice()).Call("ListClaims", listParams(`,"limit":2`)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"claims.0.claim_id": "claim_a",
+			"claims.1.claim_id": "claim_b",
+			"claims.1.status":   CLAIM_STATUS_APPROVED,
+			"next_offset":       "2",
+			"has_more":          "true",
+		}))
+	s.As(fixtures.Alice()).Call("ListClaims", listParams(`,"limit":2,"offset":2`)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"claims.0.claim_id": "claim_c",
+			"claims.0.status":   CLAIM_STATUS_SUBMITTED,
+			"claims.1":          "<nil>",
+			"next_offset":       "3",
+			"has_more":          "false",
+		}))
+	s.As(fixtures.Alice()).Call("ListClaims", listParams(`,"status":"`+CLAIM_STATUS_SUBMITTED+`"`)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{
+			"claims.0.claim_id": "claim_a",
+			"claims.1.claim_id": "claim_c",
+			"claims.2":          "<nil>",
+			"has_more":          "false",
+		}))
+	s.As(fixtures.Alice()).Call("ListClaims", fmt.Sprintf(`{"plan_id":"%s"}`, other)).ExpectSuccess().
+		Expect(expectReturn(map[string]string{"claims.0.claim_id": "claim_x", "claims.1": "<nil>"}))
+	s.As(fixtures.Alice()).Call("ListClaims", `{}`).ExpectError(framework.ERROR_INVALID_PARAMS)
+}
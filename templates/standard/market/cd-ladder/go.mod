@@ -0,0 +1,9 @@
+module github.com/weisyn/contract-sdk-go/examples/market/cd-ladder
+
+go 1.24.0
+
+toolchain go1.24.7
+
+
+require github.com/weisyn/contract-sdk-go v0.1.0-alpha
+
@@ -0,0 +1,366 @@
+package main
+
+import "errors"
+
+// ================================================================================================
+// 存单阶梯记账（纯函数）
+// ================================================================================================
+//
+// 本文件只包含不依赖宿主函数的阶梯记账逻辑，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试（go test）。
+// 导出方法（main.go）负责读取链上状态、执行资金划转后调用这些逻辑。
+//
+// 内嵌的定期存款原语：每一档（tranche）即一笔定期存款，
+// 金额锁定至到期时间，到期后可释放或滚动续存，未到期提前支取需扣除罚金。
+
+// 阶梯状态常量
+const (
+	// LADDER_STATUS_ACTIVE 运行中：仍有锁定中的档位
+	LADDER_STATUS_ACTIVE = "ACTIVE"
+	// LADDER_STATUS_CLOSED 已结束：所有档位均已到期释放
+	LADDER_STATUS_CLOSED = "CLOSED"
+	// LADDER_STATUS_BROKEN 已拆除：所有者提前拆除阶梯
+	LADDER_STATUS_BROKEN = "BROKEN"
+)
+
+// 档位状态常量
+const (
+	// TRANCHE_STATUS_LOCKED 锁定中：等待到期
+	TRANCHE_STATUS_LOCKED = "LOCKED"
+	// TRANCHE_STATUS_RELEASED 已释放：到期后资金已返还所有者
+	TRANCHE_STATUS_RELEASED = "RELEASED"
+	// TRANCHE_STATUS_BROKEN 提前支取：未到期被拆除，已扣除罚金
+	TRANCHE_STATUS_BROKEN = "BROKEN"
+)
+
+const (
+	// MAX_TRANCHES 单个阶梯最多档位数
+	MAX_TRANCHES = 24
+	// MAX_PENALTY_BP 提前支取罚金率上限（bp），10000 = 100%
+	MAX_PENALTY_BP = 10000
+)
+
+// 记账错误
+var (
+	errInvalidParams   = errors.New("invalid ladder params")
+	errInvalidTranche  = errors.New("tranche index out of range")
+	errTrancheNotReady = errors.New("tranche not matured")
+	errTrancheClosed   = errors.New("tranche already released or broken")
+	errLadderInactive  = errors.New("ladder is not active")
+	errAmountOverflow  = errors.New("amount overflow")
+)
+
+// Tranche 阶梯中的一档定期存款
+type Tranche struct {
+	Amount   uint64 // 锁定金额
+	Maturity uint64 // 到期时间（Unix时间戳，秒）
+	Status   string // LOCKED / RELEASED / BROKEN
+	Rolls    uint64 // 已滚动续存次数
+}
+
+// Ladder 存单阶梯
+type Ladder struct {
+	LadderID      string
+	Owner         [20]byte
+	TokenID       string
+	Status        string
+	TotalAmount   uint64 // 初始存入总额
+	Interval      uint64 // 相邻档位到期间隔（秒）
+	AutoRoll      bool   // 到期是否自动滚动续存
+	PenaltyBP     uint64 // 提前支取罚金率（bp）
+	CreatedAt     uint64
+	TotalReleased uint64 // 已返还所有者的金额（含拆除时的净额）
+	TotalPenalty  uint64 // 已收取的罚金
+	Tranches      []Tranche
+}
+
+// newLadder 创建存单阶梯，将总额拆分为 n 档，到期时间依次间隔 interval
+//
+// 第 i 档（从0开始）到期时间为 now + (i+1)*interval；
+// 不能整除的余数从第一档起逐档加1，保证各档金额之和等于总额。
+func newLadder(ladderID string, owner [20]byte, tokenID string, totalAmount, n, interval uint64, autoRoll bool, penaltyBP, now uint64) (*Ladder, error) {
+	if ladderID == "" || n == 0 || n > MAX_TRANCHES || interval == 0 || totalAmount < n || penaltyBP > MAX_PENALTY_BP {
+		return nil, errInvalidParams
+	}
+	if now+n*interval < now || n*interval/n != interval {
+		return nil, errAmountOverflow
+	}
+
+	base := totalAmount / n
+	remainder := totalAmount % n
+	tranches := make([]Tranche, n)
+	for i := uint64(0); i < n; i++ {
+		amount := base
+		if i < remainder {
+			amount++
+		}
+		tranches[i] = Tranche{
+			Amount:   amount,
+			Maturity: now + (i+1)*interval,
+			Status:   TRANCHE_STATUS_LOCKED,
+		}
+	}
+
+	return &Ladder{
+		LadderID:    ladderID,
+		Owner:       owner,
+		TokenID:     tokenID,
+		Status:      LADDER_STATUS_ACTIVE,
+		TotalAmount: totalAmount,
+		Interval:    interval,
+		AutoRoll:    autoRoll,
+		PenaltyBP:   penaltyBP,
+		CreatedAt:   now,
+		Tranches:    tranches,
+	}, nil
+}
+
+// roll 处理到期档位（任何人可触发）
+//
+// 开启 auto_roll 时，到期金额按原金额重新锁定到阶梯末尾：
+// 新到期时间 = 当前最晚到期时间 + interval，返回 released = 0；
+// 否则释放该档，返回需转给所有者的金额。
+func (l *Ladder) roll(index, now uint64) (released uint64, err error) {
+	if l.Status != LADDER_STATUS_ACTIVE {
+		return 0, errLadderInactive
+	}
+	if index >= uint64(len(l.Tranches)) {
+		return 0, errInvalidTranche
+	}
+	t := &l.Tranches[index]
+	if t.Status != TRANCHE_STATUS_LOCKED {
+		return 0, errTrancheClosed
+	}
+	if now < t.Maturity {
+		return 0, errTrancheNotReady
+	}
+
+	if l.AutoRoll {
+		newMaturity := l.latestMaturity() + l.Interval
+		if newMaturity < l.Interval {
+			return 0, errAmountOverflow
+		}
+		t.Maturity = newMaturity
+		t.Rolls++
+		return 0, nil
+	}
+
+	t.Status = TRANCHE_STATUS_RELEASED
+	l.TotalReleased += t.Amount
+	if l.lockedCount() == 0 {
+		l.Status = LADDER_STATUS_CLOSED
+	}
+	return t.Amount, nil
+}
+
+// breakLadder 所有者拆除阶梯
+//
+// 已到期的档位全额返还；未到期的档位按 PenaltyBP 扣除罚金后返还净额。
+// 罚金留存在合约中，计入 TotalPenalty。
+func (l *Ladder) breakLadder(now uint64) (payout, penalty uint64, err error) {
+	if l.Status != LADDER_STATUS_ACTIVE {
+		return 0, 0, errLadderInactive
+	}
+
+	for i := range l.Tranches {
+		t := &l.Tranches[i]
+		if t.Status != TRANCHE_STATUS_LOCKED {
+			continue
+		}
+		if now >= t.Maturity {
+			payout += t.Amount
+			t.Status = TRANCHE_STATUS_RELEASED
+			continue
+		}
+		fee := penaltyOf(t.Amount, l.PenaltyBP)
+		penalty += fee
+		payout += t.Amount - fee
+		t.Status = TRANCHE_STATUS_BROKEN
+	}
+
+	l.TotalReleased += payout
+	l.TotalPenalty += penalty
+	l.Status = LADDER_STATUS_BROKEN
+	return payout, penalty, nil
+}
+
+// lockedAmount 返回仍锁定在阶梯中的金额
+func (l *Ladder) lockedAmount() uint64 {
+	var total uint64
+	for _, t := range l.Tranches {
+		if t.Status == TRANCHE_STATUS_LOCKED {
+			total += t.Amount
+		}
+	}
+	return total
+}
+
+// lockedCount 返回锁定中的档位数
+func (l *Ladder) lockedCount() int {
+	count := 0
+	for _, t := range l.Tranches {
+		if t.Status == TRANCHE_STATUS_LOCKED {
+			count++
+		}
+	}
+	return count
+}
+
+// latestMaturity 返回锁定档位中最晚的到期时间
+func (l *Ladder) latestMaturity() uint64 {
+	var latest uint64
+	for _, t := range l.Tranches {
+		if t.Status == TRANCHE_STATUS_LOCKED && t.Maturity > latest {
+			latest = t.Maturity
+		}
+	}
+	return latest
+}
+
+// nextMaturity 返回锁定档位中最早的到期时间，无锁定档位时返回0
+func (l *Ladder) nextMaturity() uint64 {
+	var next uint64
+	for _, t := range l.Tranches {
+		if t.Status == TRANCHE_STATUS_LOCKED && (next == 0 || t.Maturity < next) {
+			next = t.Maturity
+		}
+	}
+	return next
+}
+
+// penaltyOf 计算提前支取罚金：amount * bp / 10000（拆分计算避免溢出）
+func penaltyOf(amount, bp uint64) uint64 {
+	return amount/10000*bp + amount%10000*bp/10000
+}
+
+// ================================================================================================
+// 状态编码/解码
+// ================================================================================================
+//
+// 编码格式（固定头部 + 每档固定长度）：
+//
+//	头部：ladderID(32) + owner(20) + tokenID(32) + status(16) + totalAmount(8) + trancheCount(8) +
+//	      interval(8) + autoRoll(1) + penaltyBP(8) + createdAt(8) + totalReleased(8) + totalPenalty(8) = 165字节
+//	档位：amount(8) + maturity(8) + status(16) + rolls(8) = 40字节
+
+const (
+	ladderHeaderSize = 165
+	trancheSize      = 40
+)
+
+// encodeLadder 编码存单阶梯
+func encodeLadder(l *Ladder) []byte {
+	result := make([]byte, ladderHeaderSize+trancheSize*len(l.Tranches))
+	putString(result[0:32], l.LadderID)
+	copy(result[32:52], l.Owner[:])
+	putString(result[52:84], l.TokenID)
+	putString(result[84:100], l.Status)
+	putUint64(result[100:108], l.TotalAmount)
+	putUint64(result[108:116], uint64(len(l.Tranches)))
+	putUint64(result[116:124], l.Interval)
+	if l.AutoRoll {
+		result[124] = 1
+	}
+	putUint64(result[125:133], l.PenaltyBP)
+	putUint64(result[133:141], l.CreatedAt)
+	putUint64(result[141:149], l.TotalReleased)
+	putUint64(result[149:157], l.TotalPenalty)
+	// 157..165 预留
+
+	for i, t := range l.Tranches {
+		off := ladderHeaderSize + i*trancheSize
+		putUint64(result[off:off+8], t.Amount)
+		putUint64(result[off+8:off+16], t.Maturity)
+		putString(result[off+16:off+32], t.Status)
+		putUint64(result[off+32:off+40], t.Rolls)
+	}
+	return result
+}
+
+// decodeLadder 解码存单阶梯，数据长度不足时返回 nil
+func decodeLadder(data []byte) *Ladder {
+	if len(data) < ladderHeaderSize {
+		return nil
+	}
+	n := getUint64(data[108:116])
+	if n == 0 || n > MAX_TRANCHES || uint64(len(data)) < ladderHeaderSize+n*trancheSize {
+		return nil
+	}
+
+	l := &Ladder{
+		LadderID:      getString(data[0:32]),
+		TokenID:       getString(data[52:84]),
+		Status:        getString(data[84:100]),
+		TotalAmount:   getUint64(data[100:108]),
+		Interval:      getUint64(data[116:124]),
+		AutoRoll:      data[124] == 1,
+		PenaltyBP:     getUint64(data[125:133]),
+		CreatedAt:     getUint64(data[133:141]),
+		TotalReleased: getUint64(data[141:149]),
+		TotalPenalty:  getUint64(data[149:157]),
+		Tranches:      make([]Tranche, n),
+	}
+	copy(l.Owner[:], data[32:52])
+
+	for i := range l.Tranches {
+		off := ladderHeaderSize + i*trancheSize
+		l.Tranches[i] = Tranche{
+			Amount:   getUint64(data[off : off+8]),
+			Maturity: getUint64(data[off+8 : off+16]),
+			Status:   getString(data[off+16 : off+32]),
+			Rolls:    getUint64(data[off+32 : off+40]),
+		}
+	}
+	return l
+}
+
+// putString 写入定长字符串字段，超长截断，不足补0x00
+func putString(dst []byte, s string) {
+	copy(dst, s)
+}
+
+// getString 读取定长字符串字段，去除尾部0x00
+func getString(b []byte) string {
+	for i := 0; i < len(b); i++ {
+		if b[i] == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+// putUint64 写入8字节大端序数值
+func putUint64(dst []byte, v uint64) {
+	for i := 7; i >= 0; i-- {
+		dst[i] = byte(v)
+		v >>= 8
+	}
+}
+
+// getUint64 读取8字节大端序数值
+func getUint64(b []byte) uint64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+// parseJSONBool 从原始JSON参数中解析布尔字段
+//
+// 支持 "key":true、"key": true 和 "key":"true" 三种写法，字段缺失时返回 false
+func parseJSONBool(raw []byte, key string) bool {
+	s := string(raw)
+	pattern := `"` + key + `":`
+	for i := 0; i+len(pattern) <= len(s); i++ {
+		if s[i:i+len(pattern)] != pattern {
+			continue
+		}
+		rest := s[i+len(pattern):]
+		for len(rest) > 0 && (rest[0] == ' ' || rest[0] == '"') {
+			rest = rest[1:]
+		}
+		return len(rest) >= 4 && rest[:4] == "true"
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package main
+
+import "testing"
+
+const (
+	testNow      = uint64(1736200000)
+	testInterval = uint64(2592000) // 30天
+)
+
+var testOwner = [20]byte{0x01}
+
+func mustNewLadder(t *testing.T, autoRoll bool) *Ladder {
+	t.Helper()
+	l, err := newLadder("ladder_001", testOwner, "", 400000, 4, testInterval, autoRoll, 100, testNow)
+	if err != nil {
+		t.Fatalf("newLadder() error = %v", err)
+	}
+	return l
+}
+
+// TestNewLadderSplit 测试总额拆分与到期时间错开
+func TestNewLadderSplit(t *testing.T) {
+	l, err := newLadder("ladder_001", testOwner, "", 10, 3, testInterval, false, 0, testNow)
+	if err != nil {
+		t.Fatalf("newLadder() error = %v", err)
+	}
+
+	var sum uint64
+	for i, tr := range l.Tranches {
+		sum += tr.Amount
+		if want := testNow + uint64(i+1)*testInterval; tr.Maturity != want {
+			t.Errorf("tranche %d maturity = %d, want %d", i, tr.Maturity, want)
+		}
+	}
+	if sum != 10 {
+		t.Errorf("sum of tranches = %d, want 10", sum)
+	}
+	if l.Tranches[0].Amount != 4 || l.Tranches[2].Amount != 3 {
+		t.Errorf("remainder distribution = %+v", l.Tranches)
+	}
+
+	if _, err := newLadder("ladder_001", testOwner, "", 3, 4, testInterval, false, 0, testNow); err != errInvalidParams {
+		t.Errorf("newLadder(amount < tranches) error = %v, want errInvalidParams", err)
+	}
+	if _, err := newLadder("ladder_001", testOwner, "", 100, MAX_TRANCHES+1, testInterval, false, 0, testNow); err != errInvalidParams {
+		t.Errorf("newLadder(too many tranches) error = %v, want errInvalidParams", err)
+	}
+}
+
+// TestFullRollCycle 测试 auto_roll 开启时完整滚动一轮
+func TestFullRollCycle(t *testing.T) {
+	l := mustNewLadder(t, true)
+
+	// 未到期不能滚动
+	if _, err := l.roll(0, testNow); err != errTrancheNotReady {
+		t.Fatalf("roll() before maturity error = %v, want errTrancheNotReady", err)
+	}
+
+	// 每档到期后依次滚动到阶梯末尾
+	for i := uint64(0); i < 4; i++ {
+		now := testNow + (i+1)*testInterval
+		released, err := l.roll(i, now)
+		if err != nil {
+			t.Fatalf("roll(%d) error = %v", i, err)
+		}
+		if released != 0 {
+			t.Errorf("roll(%d) released = %d, want 0 with auto_roll", i, released)
+		}
+		if want := testNow + (i+5)*testInterval; l.Tranches[i].Maturity != want {
+			t.Errorf("tranche %d new maturity = %d, want %d", i, l.Tranches[i].Maturity, want)
+		}
+		if l.Tranches[i].Rolls != 1 {
+			t.Errorf("tranche %d rolls = %d, want 1", i, l.Tranches[i].Rolls)
+		}
+	}
+
+	// 一轮之后阶梯保持4档错开，金额不变
+	if l.Status != LADDER_STATUS_ACTIVE {
+		t.Errorf("status = %s, want ACTIVE", l.Status)
+	}
+	if l.lockedAmount() != 400000 {
+		t.Errorf("lockedAmount() = %d, want 400000", l.lockedAmount())
+	}
+	if want := testNow + 5*testInterval; l.nextMaturity() != want {
+		t.Errorf("nextMaturity() = %d, want %d", l.nextMaturity(), want)
+	}
+
+	// 状态编码往返
+	decoded := decodeLadder(encodeLadder(l))
+	if decoded == nil {
+		t.Fatal("decodeLadder() returned nil")
+	}
+	if decoded.LadderID != l.LadderID || decoded.Owner != l.Owner || !decoded.AutoRoll || decoded.Tranches[3] != l.Tranches[3] {
+		t.Errorf("decodeLadder() = %+v, want %+v", decoded, l)
+	}
+}
+
+// TestBreakWithMaturedAndLocked 测试两档已到期、两档未到期时拆除阶梯
+func TestBreakWithMaturedAndLocked(t *testing.T) {
+	l := mustNewLadder(t, false)
+
+	// 第0档已到期并释放；第1档已到期但未处理；第2、3档未到期
+	now := testNow + 2*testInterval
+	released, err := l.roll(0, now)
+	if err != nil || released != 100000 {
+		t.Fatalf("roll(0) = %d, %v, want 100000, nil", released, err)
+	}
+
+	payout, penalty, err := l.breakLadder(now)
+	if err != nil {
+		t.Fatalf("breakLadder() error = %v", err)
+	}
+
+	// 第1档全额返还，第2、3档各扣1%罚金
+	if penalty != 2000 {
+		t.Errorf("penalty = %d, want 2000", penalty)
+	}
+	if payout != 100000+2*99000 {
+		t.Errorf("payout = %d, want %d", payout, 100000+2*99000)
+	}
+	if l.TotalReleased+l.TotalPenalty != l.TotalAmount {
+		t.Errorf("released(%d) + penalty(%d) != total(%d)", l.TotalReleased, l.TotalPenalty, l.TotalAmount)
+	}
+
+	wantStatus := []string{TRANCHE_STATUS_RELEASED, TRANCHE_STATUS_RELEASED, TRANCHE_STATUS_BROKEN, TRANCHE_STATUS_BROKEN}
+	for i, tr := range l.Tranches {
+		if tr.Status != wantStatus[i] {
+			t.Errorf("tranche %d status = %s, want %s", i, tr.Status, wantStatus[i])
+		}
+	}
+	if l.Status != LADDER_STATUS_BROKEN {
+		t.Errorf("status = %s, want BROKEN", l.Status)
+	}
+
+	// 拆除后不能再次拆除或滚动
+	if _, _, err := l.breakLadder(now); err != errLadderInactive {
+		t.Errorf("second breakLadder() error = %v, want errLadderInactive", err)
+	}
+	if _, err := l.roll(3, now+10*testInterval); err != errLadderInactive {
+		t.Errorf("roll() after break error = %v, want errLadderInactive", err)
+	}
+}
+
+// TestAutoRollOff 测试 auto_roll 关闭时到期释放，全部释放后阶梯结束
+func TestAutoRollOff(t *testing.T) {
+	l := mustNewLadder(t, false)
+
+	var total uint64
+	for i := uint64(0); i < 4; i++ {
+		now := testNow + (i+1)*testInterval
+		released, err := l.roll(i, now)
+		if err != nil {
+			t.Fatalf("roll(%d) error = %v", i, err)
+		}
+		total += released
+		if l.Tranches[i].Status != TRANCHE_STATUS_RELEASED {
+			t.Errorf("tranche %d status = %s, want RELEASED", i, l.Tranches[i].Status)
+		}
+		if i < 3 {
+			if _, err := l.roll(i, now); err != errTrancheClosed {
+				t.Errorf("second roll(%d) error = %v, want errTrancheClosed", i, err)
+			}
+		}
+	}
+
+	if total != 400000 || l.TotalReleased != 400000 {
+		t.Errorf("released total = %d (TotalReleased %d), want 400000", total, l.TotalReleased)
+	}
+	if l.Status != LADDER_STATUS_CLOSED {
+		t.Errorf("status = %s, want CLOSED", l.Status)
+	}
+	if l.nextMaturity() != 0 {
+		t.Errorf("nextMaturity() = %d, want 0", l.nextMaturity())
+	}
+}
+
+// TestParseJSONBool 测试布尔参数解析
+func TestParseJSONBool(t *testing.T) {
+	cases := map[string]bool{
+		`{"auto_roll":true}`:   true,
+		`{"auto_roll": true}`:  true,
+		`{"auto_roll":"true"}`: true,
+		`{"auto_roll":false}`:  false,
+		`{"tranches":4}`:       false,
+	}
+	for raw, want := range cases {
+		if got := parseJSONBool([]byte(raw), "auto_roll"); got != want {
+			t.Errorf("parseJSONBool(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+//go:build tinygo || (js && wasm)
+
+// Package main 提供存单阶梯（CD Ladder）合约示例
+//
+// 📋 示例说明
+//
+// 本示例展示如何使用 WES Contract SDK Go 构建存单阶梯管理合约。
+// 资金管理者将一笔资金拆分为多档定期存款，到期时间依次错开（如每月一档），
+// 到期后可自动滚动续存到阶梯末尾，保持持续的流动性节奏。
+// 通过本示例，您可以学习：
+//   - 如何使用 helpers/token 模块将资金托管到合约地址
+//   - 如何在合约内嵌定期存款（timelock deposit）原语
+//   - 如何处理滚动续存、提前支取罚金等跨档位记账
+//
+// 🎯 核心功能
+//
+//  1. CreateLadder - 创建阶梯
+//     - 将总额拆分为 N 档，到期时间依次间隔 interval_seconds
+//
+//  2. RollTranche - 处理到期档位（任何人可调用）
+//     - auto_roll 开启：按原金额重新锁定，新到期时间 = 最晚到期时间 + interval
+//     - auto_roll 关闭：释放该档资金给所有者
+//
+//  3. BreakLadder - 拆除阶梯（仅所有者）
+//     - 已到期档位全额返还，未到期档位扣除提前支取罚金后返还
+//
+//  4. GetLadder - 查询阶梯
+//     - 返回每档状态、到期时间及下一个到期时间
+//
+// ⚠️ 注意：当前 SDK 尚未提供跨合约调用，本示例内嵌定期存款原语，
+//   记账逻辑位于 ladder.go（纯函数，可在非WASM环境下单元测试）。
+//
+// 📚 相关文档
+//
+//   - [Token 模块文档](../../../helpers/token/README.md)
+//   - [Framework 文档](../../../framework/README.md)
+//   - [示例总览](../README.md)
+package main
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// CDLadderContract 存单阶梯合约
+type CDLadderContract struct {
+	framework.ContractBase
+}
+
+// 状态ID前缀：ladder_{ladder_id}
+const STATE_LADDER_PREFIX = "ladder_"
+
+// DEFAULT_PENALTY_BP 默认提前支取罚金率（bp），100 = 1%
+const DEFAULT_PENALTY_BP = 100
+
+// Initialize 初始化合约
+//
+// 事件：
+//   - ContractInitialized - 合约初始化事件
+//     {
+//       "contract": "CDLadder",
+//       "owner": "<合约所有者地址>"
+//     }
+//
+//export Initialize
+func Initialize() uint32 {
+	framework.BeginInvocation()
+	caller := framework.GetCaller()
+	event := framework.NewEvent("ContractInitialized")
+	event.AddStringField("contract", "CDLadder")
+	event.AddAddressField("owner", caller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// CreateLadder 创建存单阶梯
+//
+// 调用者将 total_amount 转入合约地址，拆分为 tranches 档定期存款，
+// 第 i 档（从0开始）到期时间为 当前时间 + (i+1) * interval_seconds。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "ladder_id": "ladder_001",     // 阶梯ID（必填）
+//	  "token": "USDT",               // 代币ID（可选，空表示原生币）
+//	  "total_amount": 400000,        // 存入总额（必填，不少于档位数）
+//	  "tranches": 4,                 // 档位数（必填，1~24）
+//	  "interval_seconds": 2592000,   // 相邻档位到期间隔（必填）
+//	  "auto_roll": true,             // 到期是否自动滚动续存（可选，默认false）
+//	  "penalty_bp": 100              // 提前支取罚金率（可选，默认100 = 1%）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 创建成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_ALREADY_EXISTS - 阶梯已存在
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - LadderCreated
+//     {
+//       "ladder_id": "ladder_001",
+//       "owner": "<所有者地址>",
+//       "total_amount": 400000,
+//       "tranches": 4,
+//       "next_maturity": 1738792000
+//     }
+//
+//export CreateLadder
+func CreateLadder() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	ladderID := params.ParseJSON("ladder_id")
+	tokenIDStr := params.ParseJSON("token")
+	totalAmount := params.ParseJSONInt("total_amount")
+	tranches := params.ParseJSONInt("tranches")
+	interval := params.ParseJSONInt("interval_seconds")
+	autoRoll := parseJSONBool(params.GetRawData(), "auto_roll")
+	penaltyBP := params.GetIntOr("penalty_bp", DEFAULT_PENALTY_BP)
+
+	// 步骤2：检查阶梯是否已存在
+	stateID := getLadderStateID(ladderID)
+	if existing, _ := framework.GetState(string(stateID)); ladderID != "" && decodeLadder(existing) != nil {
+		return framework.ERROR_ALREADY_EXISTS
+	}
+
+	// 步骤3：构建阶梯（参数校验在 newLadder 中完成）
+	caller := framework.GetCaller()
+	ladder, err := newLadder(ladderID, caller, tokenIDStr, totalAmount, tranches, interval, autoRoll, penaltyBP, framework.GetTimestamp())
+	if err != nil {
+		return ladderErrorCode(err)
+	}
+
+	// 步骤4：将资金转入合约地址
+	contractAddr := framework.GetContractAddress()
+	if err := token.Transfer(caller, contractAddr, framework.TokenID(tokenIDStr), framework.Amount(totalAmount)); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 步骤5：保存阶梯状态
+	if code := saveLadder(ladder); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	event := framework.NewEvent("LadderCreated")
+	event.AddStringField("ladder_id", ladderID)
+	event.AddAddressField("owner", caller)
+	event.AddStringField("token", tokenIDStr)
+	event.AddUint64Field("total_amount", totalAmount)
+	event.AddUint64Field("tranches", tranches)
+	event.AddUint64Field("interval_seconds", interval)
+	event.AddBoolField("auto_roll", autoRoll)
+	event.AddUint64Field("next_maturity", ladder.nextMaturity())
+	framework.EmitEvent(event)
+
+	// 步骤7：返回阶梯详情
+	return returnLadder(ladder)
+}
+
+// RollTranche 处理到期档位
+//
+// 任何人可在档位到期后调用：
+//   - auto_roll 开启：按原金额重新锁定到阶梯末尾（新到期时间 = 最晚到期时间 + interval）
+//   - auto_roll 关闭：将该档资金释放给所有者；所有档位释放后阶梯状态变为 CLOSED
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "ladder_id": "ladder_001",
+//	  "tranche_index": 0
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 处理成功
+//   - framework.ERROR_INVALID_PARAMS - 档位索引无效
+//   - framework.ERROR_NOT_FOUND - 阶梯不存在
+//   - framework.ERROR_INVALID_STATE - 档位未到期、已处理或阶梯已结束
+//
+// 事件：
+//   - TrancheRolled（auto_roll 开启）/ TrancheReleased（auto_roll 关闭）
+//
+//export RollTranche
+func RollTranche() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	ladderID := params.ParseJSON("ladder_id")
+	index := params.ParseJSONInt("tranche_index")
+	if ladderID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取阶梯
+	ladder, code := loadLadder(ladderID)
+	if code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤3：记账
+	released, err := ladder.roll(index, framework.GetTimestamp())
+	if err != nil {
+		return ladderErrorCode(err)
+	}
+
+	// 步骤4：释放资金给所有者
+	owner := framework.Address(ladder.Owner)
+	if released > 0 {
+		if err := token.Transfer(framework.GetContractAddress(), owner, framework.TokenID(ladder.TokenID), framework.Amount(released)); err != nil {
+			if contractErr, ok := err.(*framework.ContractError); ok {
+				return contractErr.Code
+			}
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
+	// 步骤5：保存阶梯状态
+	if code := saveLadder(ladder); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	tranche := ladder.Tranches[index]
+	eventName := "TrancheReleased"
+	if ladder.AutoRoll {
+		eventName = "TrancheRolled"
+	}
+	event := framework.NewEvent(eventName)
+	event.AddStringField("ladder_id", ladderID)
+	event.AddUint64Field("tranche_index", index)
+	event.AddUint64Field("amount", tranche.Amount)
+	event.AddUint64Field("maturity", tranche.Maturity)
+	event.AddUint64Field("rolls", tranche.Rolls)
+	event.AddAddressField("caller", framework.GetCaller())
+	framework.EmitEvent(event)
+
+	// 步骤7：返回阶梯详情
+	return returnLadder(ladder)
+}
+
+// BreakLadder 拆除阶梯（仅所有者）
+//
+// 已到期档位全额返还；未到期档位扣除 penalty_bp 罚金后返还净额，罚金留存合约。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "ladder_id": "ladder_001"
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 拆除成功
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是所有者
+//   - framework.ERROR_NOT_FOUND - 阶梯不存在
+//   - framework.ERROR_INVALID_STATE - 阶梯已结束或已拆除
+//
+// 事件：
+//   - LadderBroken
+//     {
+//       "ladder_id": "ladder_001",
+//       "payout": 396000,
+//       "penalty": 4000
+//     }
+//
+//export BreakLadder
+func BreakLadder() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	ladderID := params.ParseJSON("ladder_id")
+	if ladderID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取阶梯并检查权限
+	ladder, code := loadLadder(ladderID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	owner := framework.Address(ladder.Owner)
+	if framework.GetCaller() != owner {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤3：记账
+	payout, penalty, err := ladder.breakLadder(framework.GetTimestamp())
+	if err != nil {
+		return ladderErrorCode(err)
+	}
+
+	// 步骤4：返还资金
+	if payout > 0 {
+		if err := token.Transfer(framework.GetContractAddress(), owner, framework.TokenID(ladder.TokenID), framework.Amount(payout)); err != nil {
+			if contractErr, ok := err.(*framework.ContractError); ok {
+				return contractErr.Code
+			}
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
+	// 步骤5：保存阶梯状态
+	if code := saveLadder(ladder); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	event := framework.NewEvent("LadderBroken")
+	event.AddStringField("ladder_id", ladderID)
+	event.AddAddressField("owner", owner)
+	event.AddUint64Field("payout", payout)
+	event.AddUint64Field("penalty", penalty)
+	framework.EmitEvent(event)
+
+	// 步骤7：返回阶梯详情
+	return returnLadder(ladder)
+}
+
+// GetLadder 查询阶梯
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "ladder_id": "ladder_001"
+//	}
+//
+// 返回 JSON：
+//
+//	{
+//	  "ladder_id": "ladder_001",
+//	  "owner": "Cf1...",
+//	  "status": "ACTIVE",
+//	  "locked_amount": 400000,
+//	  "next_maturity": 1738792000,
+//	  "tranches": [
+//	    {"index": 0, "amount": 100000, "maturity": 1738792000, "status": "LOCKED", "rolls": 0}
+//	  ]
+//	}
+//
+//export GetLadder
+func GetLadder() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	ladderID := params.ParseJSON("ladder_id")
+	if ladderID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	ladder, code := loadLadder(ladderID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	return returnLadder(ladder)
+}
+
+// ================================================================================================
+// 辅助函数
+// ================================================================================================
+
+// getLadderStateID 获取阶梯状态ID：ladder_{ladder_id}
+func getLadderStateID(ladderID string) []byte {
+	return []byte(STATE_LADDER_PREFIX + ladderID)
+}
+
+// loadLadder 读取阶梯状态
+func loadLadder(ladderID string) (*Ladder, uint32) {
+	data, _ := framework.GetState(string(getLadderStateID(ladderID)))
+	ladder := decodeLadder(data)
+	if ladder == nil {
+		return nil, framework.ERROR_NOT_FOUND
+	}
+	return ladder, framework.SUCCESS
+}
+
+// saveLadder 保存阶梯状态（版本号递增）
+func saveLadder(ladder *Ladder) uint32 {
+	stateID := getLadderStateID(ladder.LadderID)
+	version, err := framework.IncrementStateVersion(stateID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if _, err := framework.AppendStateOutputSimple(stateID, version, encodeLadder(ladder), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// returnLadder 以 JSON 返回阶梯详情
+func returnLadder(ladder *Ladder) uint32 {
+	tranches := make([]interface{}, len(ladder.Tranches))
+	for i, t := range ladder.Tranches {
+		tranches[i] = map[string]interface{}{
+			"index":    uint64(i),
+			"amount":   t.Amount,
+			"maturity": t.Maturity,
+			"status":   t.Status,
+			"rolls":    t.Rolls,
+		}
+	}
+
+	result := map[string]interface{}{
+		"ladder_id":        ladder.LadderID,
+		"owner":            framework.Address(ladder.Owner).ToString(),
+		"token":            ladder.TokenID,
+		"status":           ladder.Status,
+		"total_amount":     ladder.TotalAmount,
+		"interval_seconds": ladder.Interval,
+		"auto_roll":        ladder.AutoRoll,
+		"penalty_bp":       ladder.PenaltyBP,
+		"created_at":       ladder.CreatedAt,
+		"locked_amount":    ladder.lockedAmount(),
+		"total_released":   ladder.TotalReleased,
+		"total_penalty":    ladder.TotalPenalty,
+		"next_maturity":    ladder.nextMaturity(),
+		"tranches":         tranches,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// ladderErrorCode 将记账错误映射为合约错误码
+func ladderErrorCode(err error) uint32 {
+	switch err {
+	case errInvalidParams, errInvalidTranche, errAmountOverflow:
+		return framework.ERROR_INVALID_PARAMS
+	case errTrancheNotReady, errTrancheClosed, errLadderInactive:
+		return framework.ERROR_INVALID_STATE
+	default:
+		return framework.ERROR_EXECUTION_FAILED
+	}
+}
+
+func main() {}
@@ -0,0 +1,646 @@
+//go:build tinygo || (js && wasm)
+
+// Package main 提供货运分段付款托管（Delivery Escrow）合约示例
+//
+// 📋 示例说明
+//
+// 本示例展示如何使用 WES Contract SDK Go 构建按物流检查点分段放款的运费托管合约。
+// 托运方把承运方的运费托管到合约，运单依次经过港口扫描、清关、签收等检查点，
+// 每个检查点由承运方 API 报告状态，任何人都可以提交 API 佐证，
+// 合约通过 ISPC 受控外部交互验证佐证后，将该段运费自动付给承运方。
+// 通过本示例，您可以学习：
+//   - 如何使用 helpers/external 模块验证并读取外部 API 数据（无需预言机）
+//   - 如何把外部佐证与里程碑托管结合，按检查点顺序分段放款
+//   - 如何为单个检查点引入仲裁，以及中途取消时的分段退款
+//
+// 🎯 核心功能
+//
+//  1. CreateShipment - 创建运单（托运方）
+//     - 托管运费，声明有序的检查点：数据源、状态字段、预期值与运费比例
+//
+//  2. ConfirmCheckpoint - 确认检查点（任何人可调用）
+//     - 提交承运方 API 佐证，状态字段与预期值一致时放款该段运费
+//     - 检查点必须按顺序确认，每个只能确认一次
+//
+//  3. DisputeCheckpoint - 对检查点提出争议（托运方或承运方）
+//     - 暂停运单，由仲裁人通过 ResolveCheckpointDispute 划分该段运费
+//
+//  4. CancelShipment - 取消运单（托运方或承运方）
+//     - 首个检查点确认前全额退还；之后只退还尚未付出的各段运费
+//
+//  5. GetShipment - 查询运单
+//
+// ⚠️ 注意：记账逻辑位于 shipment.go（纯函数，可在非WASM环境下单元测试）。
+//
+// 📚 相关文档
+//
+//   - [External 模块文档](../../../helpers/external/README.md)
+//   - [Token 模块文档](../../../helpers/token/README.md)
+//   - [Framework 文档](../../../framework/README.md)
+//   - [示例总览](../README.md)
+package main
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/external"
+	"github.com/weisyn/contract-sdk-go/helpers/token"
+)
+
+// DeliveryEscrowContract 货运分段付款托管合约
+type DeliveryEscrowContract struct {
+	framework.ContractBase
+}
+
+// 状态ID前缀：shipment_{shipment_id}
+const STATE_SHIPMENT_PREFIX = "shipment_"
+
+// Initialize 初始化合约
+//
+// 事件：
+//   - ContractInitialized - 合约初始化事件
+//     {
+//     "contract": "DeliveryEscrow",
+//     "owner": "<合约所有者地址>"
+//     }
+//
+//export Initialize
+func Initialize() uint32 {
+	framework.BeginInvocation()
+	caller := framework.GetCaller()
+	event := framework.NewEvent("ContractInitialized")
+	event.AddStringField("contract", "DeliveryEscrow")
+	event.AddAddressField("owner", caller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// CreateShipment 创建运单
+//
+// 调用者（托运方）将运费 amount 转入合约地址，按各检查点 tranche_bp 拆分为分段运费，
+// 舍入余数计入最后一个检查点。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001",                   // 运单ID（必填）
+//	  "carrier": "Cf1...",                         // 承运方地址（必填）
+//	  "arbiter": "Cf2...",                         // 仲裁人地址（必填，不能是托运方或承运方）
+//	  "token": "USDT",                             // 代币ID（可选，空表示原生币）
+//	  "amount": 100000,                            // 运费总额（必填）
+//	  "tracking_id": "MSKU1234567",                // 承运方运单号（必填）
+//	  "checkpoints": [                             // 有序检查点（必填，1~8 个，tranche_bp 之和为 10000）
+//	    {"name": "port_scan", "source": "https://api.carrier.example/track", "field": "status", "expected": "PORT_SCANNED", "tranche_bp": 2000},
+//	    {"name": "customs_clearance", "source": "https://api.customs.example/status", "field": "status", "expected": "CLEARED", "tranche_bp": 3000},
+//	    {"name": "delivered", "source": "https://api.carrier.example/track", "field": "status", "expected": "DELIVERED", "tranche_bp": 5000}
+//	  ]
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 创建成功，返回运单详情
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_ALREADY_EXISTS - 运单已存在
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - ShipmentCreated
+//     {
+//     "shipment_id": "ship_001",
+//     "shipper": "<托运方地址>",
+//     "carrier": "<承运方地址>",
+//     "amount": 100000,
+//     "checkpoints": 3
+//     }
+//
+//export CreateShipment
+func CreateShipment() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	carrierStr := params.ParseJSON("carrier")
+	arbiterStr := params.ParseJSON("arbiter")
+	tokenIDStr := params.ParseJSON("token")
+	amount := params.ParseJSONInt("amount")
+	trackingID := params.ParseJSON("tracking_id")
+	specs, ok := parseCheckpointSpecs(string(params.GetRawData()))
+	if shipmentID == "" || carrierStr == "" || arbiterStr == "" || !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	carrier, err := framework.ParseAddressBase58(carrierStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	arbiter, err := framework.ParseAddressBase58(arbiterStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：检查运单是否已存在
+	if _, code := loadShipment(shipmentID); code == framework.SUCCESS {
+		return framework.ERROR_ALREADY_EXISTS
+	}
+
+	// 步骤3：创建运单记账
+	shipper := framework.GetCaller()
+	shipment, err := newShipment(shipmentID, shipper, carrier, arbiter, tokenIDStr, trackingID, amount, specs, framework.GetTimestamp())
+	if err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤4：托管运费
+	tokenID := framework.TokenID(tokenIDStr)
+	if framework.QueryUTXOBalance(shipper, tokenID) < framework.Amount(amount) {
+		return framework.ERROR_INSUFFICIENT_BALANCE
+	}
+	if err := token.Transfer(shipper, framework.GetContractAddress(), tokenID, framework.Amount(amount)); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤5：保存运单
+	if code := saveShipment(shipment); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	event := framework.NewEvent("ShipmentCreated")
+	event.AddStringField("shipment_id", shipmentID)
+	event.AddAddressField("shipper", shipper)
+	event.AddAddressField("carrier", carrier)
+	event.AddAddressField("arbiter", arbiter)
+	event.AddStringField("tracking_id", trackingID)
+	event.AddUint64Field("amount", amount)
+	event.AddUint64Field("checkpoints", uint64(len(specs)))
+	framework.EmitEvent(event)
+
+	// 步骤7：返回运单详情
+	return returnShipment(shipment)
+}
+
+// ConfirmCheckpoint 以承运方 API 佐证确认检查点
+//
+// 任何人都可以提交佐证（通常是承运方或物流数据服务）。合约通过 ISPC 受控外部交互
+// 向检查点声明的数据源查询该运单（查询参数 tracking_id、checkpoint），验证佐证后读取
+// 响应中的状态字段，与预期值一致时把该段运费付给承运方。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001",       // 运单ID（必填）
+//	  "checkpoint_index": 0,           // 检查点索引（必填，必须是下一个待确认的检查点）
+//	  "api_signature": "0x3045...",    // 承运方 API 数字签名（必填，十六进制）
+//	  "response_hash": "0x9f86..."     // 响应数据哈希（必填，十六进制）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 确认成功，返回运单详情
+//   - framework.ERROR_INVALID_PARAMS - 参数无效、检查点越界、佐证状态与预期值不一致
+//   - framework.ERROR_NOT_FOUND - 运单不存在
+//   - framework.ERROR_INVALID_STATE - 未按顺序确认、检查点已结清、运单争议中或已结束
+//   - framework.ERROR_EXECUTION_FAILED - 佐证验证失败或执行失败
+//
+// 事件：
+//   - CheckpointConfirmed
+//     {
+//     "shipment_id": "ship_001",
+//     "checkpoint_index": 0,
+//     "checkpoint": "port_scan",
+//     "reported": "PORT_SCANNED",
+//     "released": 20000,
+//     "submitter": "<提交者地址>"
+//     }
+//
+//export ConfirmCheckpoint
+func ConfirmCheckpoint() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	index := params.ParseJSONInt("checkpoint_index")
+	signature, sigOK := decodeHex(params.ParseJSON("api_signature"))
+	responseHash, hashOK := decodeHex(params.ParseJSON("response_hash"))
+	if shipmentID == "" || !sigOK || !hashOK || len(signature) == 0 || len(responseHash) == 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取运单，校验检查点顺序（在查询外部数据之前拒绝无效请求）
+	shipment, code := loadShipment(shipmentID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	cp, err := shipment.nextCheckpoint(index)
+	if err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤3：ISPC 受控外部交互：验证佐证并读取承运方 API 响应
+	response, err := external.ValidateAndQuery(
+		"api_response",
+		cp.Source,
+		map[string]interface{}{
+			"tracking_id": shipment.TrackingID,
+			"checkpoint":  cp.Name,
+		},
+		&framework.Evidence{
+			APISignature: signature,
+			ResponseHash: responseHash,
+		},
+	)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+	reported, ok := reportedStatus(response, cp.Field)
+	if !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	name := cp.Name
+
+	// 步骤4：记账（状态与预期值不一致时拒绝）
+	released, err := shipment.confirm(index, reported, framework.GetTimestamp())
+	if err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤5：放款给承运方
+	if released > 0 {
+		if err := token.Transfer(framework.GetContractAddress(), framework.Address(shipment.Carrier), framework.TokenID(shipment.TokenID), framework.Amount(released)); err != nil {
+			return contractErrorCode(err)
+		}
+	}
+
+	// 步骤6：保存运单
+	if code := saveShipment(shipment); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤7：发出事件
+	event := framework.NewEvent("CheckpointConfirmed")
+	event.AddStringField("shipment_id", shipmentID)
+	event.AddUint64Field("checkpoint_index", index)
+	event.AddStringField("checkpoint", name)
+	event.AddStringField("reported", reported)
+	event.AddUint64Field("released", released)
+	event.AddAddressField("submitter", framework.GetCaller())
+	event.AddStringField("status", shipment.Status)
+	framework.EmitEvent(event)
+
+	// 步骤8：返回运单详情
+	return returnShipment(shipment)
+}
+
+// DisputeCheckpoint 对下一个待确认的检查点提出争议
+//
+// 托运方或承运方认为承运方 API 报告的状态与实际不符（如货物损坏、延误）时提出争议。
+// 争议期间运单暂停确认与取消，由创建运单时指定的仲裁人调用 ResolveCheckpointDispute 裁决。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001",       // 运单ID（必填）
+//	  "checkpoint_index": 1,           // 检查点索引（必填，必须是下一个待确认的检查点）
+//	  "reason": "container damaged"    // 争议原因（可选，不超过 256 字节）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 提出成功，返回运单详情
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_NOT_FOUND - 运单不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是托运方或承运方
+//   - framework.ERROR_INVALID_STATE - 检查点不是下一个待确认的检查点，或运单已在争议中/已结束
+//
+// 事件：
+//   - CheckpointDisputed
+//
+//export DisputeCheckpoint
+func DisputeCheckpoint() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	index := params.ParseJSONInt("checkpoint_index")
+	reason := params.ParseJSON("reason")
+	if shipmentID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取运单并检查权限
+	shipment, code := loadShipment(shipmentID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	caller := framework.GetCaller()
+	if caller != framework.Address(shipment.Shipper) && caller != framework.Address(shipment.Carrier) {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤3：记账
+	if err := shipment.dispute(index, caller, reason); err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤4：保存运单
+	if code := saveShipment(shipment); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤5：发出事件
+	event := framework.NewEvent("CheckpointDisputed")
+	event.AddStringField("shipment_id", shipmentID)
+	event.AddUint64Field("checkpoint_index", index)
+	event.AddAddressField("disputed_by", caller)
+	event.AddAddressField("arbiter", framework.Address(shipment.Arbiter))
+	event.AddStringField("reason", reason)
+	framework.EmitEvent(event)
+
+	// 步骤6：返回运单详情
+	return returnShipment(shipment)
+}
+
+// ResolveCheckpointDispute 仲裁人裁决争议中的检查点
+//
+// 与托管仲裁（helpers/market 的 ResolveEscrow）相同的裁决方式：仲裁人指定该段运费中
+// 付给承运方的金额，其余退还托运方；裁决后运输继续，进入下一个检查点。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001",       // 运单ID（必填）
+//	  "to_carrier": 15000              // 该段运费中付给承运方的金额（必填，可为 0）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 裁决成功，返回运单详情
+//   - framework.ERROR_INVALID_PARAMS - 参数无效或金额超过该段运费
+//   - framework.ERROR_NOT_FOUND - 运单不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是仲裁人
+//   - framework.ERROR_INVALID_STATE - 运单没有未裁决的争议
+//
+// 事件：
+//   - CheckpointDisputeResolved - 包含 to_carrier、to_shipper
+//
+//export ResolveCheckpointDispute
+func ResolveCheckpointDispute() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	toCarrier := params.ParseJSONInt("to_carrier")
+	if shipmentID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取运单并检查权限
+	shipment, code := loadShipment(shipmentID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	if framework.GetCaller() != framework.Address(shipment.Arbiter) {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤3：记账
+	index := shipment.NextIndex
+	toShipper, err := shipment.resolve(toCarrier, framework.GetTimestamp())
+	if err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤4：按裁决划转该段运费
+	contractAddr := framework.GetContractAddress()
+	tokenID := framework.TokenID(shipment.TokenID)
+	if toCarrier > 0 {
+		if err := token.Transfer(contractAddr, framework.Address(shipment.Carrier), tokenID, framework.Amount(toCarrier)); err != nil {
+			return contractErrorCode(err)
+		}
+	}
+	if toShipper > 0 {
+		if err := token.Transfer(contractAddr, framework.Address(shipment.Shipper), tokenID, framework.Amount(toShipper)); err != nil {
+			return contractErrorCode(err)
+		}
+	}
+
+	// 步骤5：保存运单
+	if code := saveShipment(shipment); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	event := framework.NewEvent("CheckpointDisputeResolved")
+	event.AddStringField("shipment_id", shipmentID)
+	event.AddUint64Field("checkpoint_index", index)
+	event.AddUint64Field("to_carrier", toCarrier)
+	event.AddUint64Field("to_shipper", toShipper)
+	event.AddStringField("status", shipment.Status)
+	framework.EmitEvent(event)
+
+	// 步骤7：返回运单详情
+	return returnShipment(shipment)
+}
+
+// CancelShipment 取消运单
+//
+// 托运方或承运方可取消运输中的运单：首个检查点确认前全额退还托运方；
+// 之后只退还尚未付出的各段运费，已付给承运方的不追回。争议未裁决时不能取消。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001"        // 运单ID（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 取消成功，返回运单详情
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_NOT_FOUND - 运单不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是托运方或承运方
+//   - framework.ERROR_INVALID_STATE - 运单争议中或已结束
+//
+// 事件：
+//   - ShipmentCancelled - 包含 refunded（本次退还金额）与 released（累计已付承运方金额）
+//
+//export CancelShipment
+func CancelShipment() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	if shipmentID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取运单并检查权限
+	shipment, code := loadShipment(shipmentID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	caller := framework.GetCaller()
+	if caller != framework.Address(shipment.Shipper) && caller != framework.Address(shipment.Carrier) {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤3：记账
+	refund, err := shipment.cancel(framework.GetTimestamp())
+	if err != nil {
+		return shipmentErrorCode(err)
+	}
+
+	// 步骤4：退还未付运费
+	if refund > 0 {
+		if err := token.Transfer(framework.GetContractAddress(), framework.Address(shipment.Shipper), framework.TokenID(shipment.TokenID), framework.Amount(refund)); err != nil {
+			return contractErrorCode(err)
+		}
+	}
+
+	// 步骤5：保存运单
+	if code := saveShipment(shipment); code != framework.SUCCESS {
+		return code
+	}
+
+	// 步骤6：发出事件
+	event := framework.NewEvent("ShipmentCancelled")
+	event.AddStringField("shipment_id", shipmentID)
+	event.AddAddressField("cancelled_by", caller)
+	event.AddUint64Field("refunded", refund)
+	event.AddUint64Field("released", shipment.Released)
+	event.AddUint64Field("checkpoints_confirmed", shipment.NextIndex)
+	framework.EmitEvent(event)
+
+	// 步骤7：返回运单详情
+	return returnShipment(shipment)
+}
+
+// GetShipment 查询运单
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "shipment_id": "ship_001"
+//	}
+//
+// 返回 JSON：
+//
+//	{
+//	  "shipment_id": "ship_001",
+//	  "status": "IN_TRANSIT",
+//	  "amount": 100000,
+//	  "released": 20000,
+//	  "refunded": 0,
+//	  "escrowed": 80000,
+//	  "next_checkpoint": 1,
+//	  "checkpoints": [
+//	    {"index": 0, "name": "port_scan", "expected": "PORT_SCANNED", "tranche_bp": 2000, "amount": 20000, "status": "CONFIRMED", ...}
+//	  ]
+//	}
+//
+//export GetShipment
+func GetShipment() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	shipmentID := params.ParseJSON("shipment_id")
+	if shipmentID == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	shipment, code := loadShipment(shipmentID)
+	if code != framework.SUCCESS {
+		return code
+	}
+	return returnShipment(shipment)
+}
+
+// ================================================================================================
+// 辅助函数
+// ================================================================================================
+
+// getShipmentStateID 获取运单状态ID：shipment_{shipment_id}
+func getShipmentStateID(shipmentID string) []byte {
+	return []byte(STATE_SHIPMENT_PREFIX + shipmentID)
+}
+
+// loadShipment 读取运单状态
+func loadShipment(shipmentID string) (*Shipment, uint32) {
+	data, _ := framework.GetState(string(getShipmentStateID(shipmentID)))
+	shipment := decodeShipment(data)
+	if shipment == nil {
+		return nil, framework.ERROR_NOT_FOUND
+	}
+	return shipment, framework.SUCCESS
+}
+
+// saveShipment 保存运单状态（版本号递增）
+func saveShipment(shipment *Shipment) uint32 {
+	stateID := getShipmentStateID(shipment.ShipmentID)
+	version, err := framework.IncrementStateVersion(stateID)
+	if err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if _, err := framework.AppendStateOutputSimple(stateID, version, encodeShipment(shipment), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// returnShipment 以 JSON 返回运单详情
+func returnShipment(shipment *Shipment) uint32 {
+	checkpoints := make([]interface{}, len(shipment.Checkpoints))
+	for i, cp := range shipment.Checkpoints {
+		checkpoints[i] = map[string]interface{}{
+			"index":      uint64(i),
+			"name":       cp.Name,
+			"source":     cp.Source,
+			"field":      cp.Field,
+			"expected":   cp.Expected,
+			"tranche_bp": cp.TrancheBP,
+			"amount":     cp.Amount,
+			"status":     cp.Status,
+			"settled_at": cp.SettledAt,
+			"to_carrier": cp.ToCarrier,
+			"to_shipper": cp.ToShipper,
+			"reported":   cp.Reported,
+		}
+	}
+
+	result := map[string]interface{}{
+		"shipment_id":     shipment.ShipmentID,
+		"shipper":         framework.Address(shipment.Shipper).ToString(),
+		"carrier":         framework.Address(shipment.Carrier).ToString(),
+		"arbiter":         framework.Address(shipment.Arbiter).ToString(),
+		"token":           shipment.TokenID,
+		"tracking_id":     shipment.TrackingID,
+		"status":          shipment.Status,
+		"amount":          shipment.Amount,
+		"released":        shipment.Released,
+		"refunded":        shipment.Refunded,
+		"escrowed":        shipment.escrowed(),
+		"next_checkpoint": shipment.NextIndex,
+		"created_at":      shipment.CreatedAt,
+		"checkpoints":     checkpoints,
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// shipmentErrorCode 将记账错误映射为合约错误码
+func shipmentErrorCode(err error) uint32 {
+	switch err {
+	case errInvalidParams, errInvalidCheckpoint, errStatusMismatch, errAwardExceedsTranche:
+		return framework.ERROR_INVALID_PARAMS
+	case errOutOfOrder, errCheckpointDone, errShipmentInactive, errShipmentDisputed, errNoDispute:
+		return framework.ERROR_INVALID_STATE
+	default:
+		return framework.ERROR_EXECUTION_FAILED
+	}
+}
+
+// contractErrorCode 将 SDK 错误转换为错误码
+func contractErrorCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	return framework.ERROR_EXECUTION_FAILED
+}
+
+func main() {}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+)
+
+// ================================================================================================
+// 运单托管记账（纯函数）
+// ================================================================================================
+//
+// 本文件只包含不依赖宿主函数的运单记账逻辑，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试（go test）。
+// 导出方法（main.go）负责读取链上状态、获取外部佐证、执行资金划转后调用这些逻辑。
+//
+// 运单即分段付款的里程碑托管：托运方（shipper）把承运方（carrier）的运费托管到合约，
+// 每个检查点（港口扫描、清关、签收……）对应一段运费（tranche），
+// 检查点经承运方 API 佐证确认后，该段运费付给承运方。
+
+// 运单状态常量
+const (
+	// SHIPMENT_STATUS_IN_TRANSIT 运输中：等待下一个检查点确认
+	SHIPMENT_STATUS_IN_TRANSIT = "IN_TRANSIT"
+	// SHIPMENT_STATUS_DISPUTED 争议中：下一个检查点已提交仲裁，暂停确认与取消
+	SHIPMENT_STATUS_DISPUTED = "DISPUTED"
+	// SHIPMENT_STATUS_DELIVERED 已完成：所有检查点均已确认或裁决
+	SHIPMENT_STATUS_DELIVERED = "DELIVERED"
+	// SHIPMENT_STATUS_CANCELLED 已取消：未付运费已退还托运方
+	SHIPMENT_STATUS_CANCELLED = "CANCELLED"
+)
+
+// 检查点状态常量
+const (
+	// CHECKPOINT_STATUS_PENDING 待确认
+	CHECKPOINT_STATUS_PENDING = "PENDING"
+	// CHECKPOINT_STATUS_CONFIRMED 已确认：佐证状态与预期一致，该段运费已付给承运方
+	CHECKPOINT_STATUS_CONFIRMED = "CONFIRMED"
+	// CHECKPOINT_STATUS_DISPUTED 争议中：等待仲裁人裁决
+	CHECKPOINT_STATUS_DISPUTED = "DISPUTED"
+	// CHECKPOINT_STATUS_RESOLVED 已裁决：该段运费按裁决在承运方与托运方之间划分
+	CHECKPOINT_STATUS_RESOLVED = "RESOLVED"
+	// CHECKPOINT_STATUS_REFUNDED 已退还：运单取消时该段运费退还托运方
+	CHECKPOINT_STATUS_REFUNDED = "REFUNDED"
+)
+
+const (
+	// MAX_CHECKPOINTS 单个运单最多检查点数
+	MAX_CHECKPOINTS = 8
+	// TRANCHE_BP_TOTAL 各检查点运费比例之和（bp），10000 = 100%
+	TRANCHE_BP_TOTAL = 10000
+	// MAX_SOURCE_BYTES 检查点数据源（承运方 API 端点）最大字节数
+	MAX_SOURCE_BYTES = 256
+	// MAX_FIELD_BYTES 检查点名称、字段名与预期值的最大字节数
+	MAX_FIELD_BYTES = 64
+)
+
+// 记账错误
+var (
+	errInvalidParams       = errors.New("invalid shipment params")
+	errInvalidCheckpoint   = errors.New("checkpoint index out of range")
+	errOutOfOrder          = errors.New("checkpoints must be confirmed in order")
+	errCheckpointDone      = errors.New("checkpoint already settled")
+	errStatusMismatch      = errors.New("reported status does not match expected value")
+	errShipmentInactive    = errors.New("shipment is not in transit")
+	errShipmentDisputed    = errors.New("shipment has an open dispute")
+	errNoDispute           = errors.New("shipment has no open dispute")
+	errAwardExceedsTranche = errors.New("award exceeds checkpoint tranche")
+)
+
+// CheckpointSpec 创建运单时声明的检查点
+type CheckpointSpec struct {
+	Name      string // 检查点名称，如 port_scan / customs_clearance / delivered
+	Source    string // 承运方 API 端点
+	Field     string // API 响应中的状态字段名，如 status
+	Expected  string // 状态字段的预期值，如 CUSTOMS_CLEARED
+	TrancheBP uint64 // 该检查点对应的运费比例（bp）
+}
+
+// Checkpoint 运单中的检查点
+type Checkpoint struct {
+	CheckpointSpec
+	Amount      uint64 // 该段运费
+	Status      string // PENDING / CONFIRMED / DISPUTED / RESOLVED / REFUNDED
+	SettledAt   uint64 // 确认、裁决或退还时间
+	ToCarrier   uint64 // 已付给承运方的金额
+	ToShipper   uint64 // 已退还托运方的金额
+	Reported    string // 确认时佐证中的状态值
+	DisputedBy  [20]byte
+	DisputeNote string
+}
+
+// Shipment 运单托管
+type Shipment struct {
+	ShipmentID  string
+	Shipper     [20]byte
+	Carrier     [20]byte
+	Arbiter     [20]byte
+	TokenID     string
+	TrackingID  string // 承运方运单号，随查询参数提交给承运方 API
+	Amount      uint64 // 托管运费总额
+	Status      string
+	NextIndex   uint64 // 下一个待确认的检查点
+	Released    uint64 // 已付给承运方的金额
+	Refunded    uint64 // 已退还托运方的金额
+	CreatedAt   uint64
+	Checkpoints []Checkpoint
+}
+
+// newShipment 创建运单，按各检查点比例拆分运费
+//
+// 各检查点金额为 amount × trancheBP / 10000（向下取整），舍入余数计入最后一个检查点，
+// 保证各段之和等于托管总额。
+func newShipment(shipmentID string, shipper, carrier, arbiter [20]byte, tokenID, trackingID string, amount uint64, specs []CheckpointSpec, now uint64) (*Shipment, error) {
+	var zero [20]byte
+	if shipmentID == "" || trackingID == "" || amount == 0 || shipper == carrier ||
+		arbiter == zero || arbiter == shipper || arbiter == carrier ||
+		len(specs) == 0 || len(specs) > MAX_CHECKPOINTS {
+		return nil, errInvalidParams
+	}
+
+	s := &Shipment{
+		ShipmentID:  shipmentID,
+		Shipper:     shipper,
+		Carrier:     carrier,
+		Arbiter:     arbiter,
+		TokenID:     tokenID,
+		TrackingID:  trackingID,
+		Amount:      amount,
+		Status:      SHIPMENT_STATUS_IN_TRANSIT,
+		CreatedAt:   now,
+		Checkpoints: make([]Checkpoint, len(specs)),
+	}
+	var totalBP, allocated uint64
+	for i, spec := range specs {
+		if spec.Name == "" || spec.Source == "" || spec.Field == "" || spec.Expected == "" || spec.TrancheBP == 0 ||
+			len(spec.Name) > MAX_FIELD_BYTES || len(spec.Field) > MAX_FIELD_BYTES || len(spec.Expected) > MAX_FIELD_BYTES ||
+			len(spec.Source) > MAX_SOURCE_BYTES {
+			return nil, errInvalidParams
+		}
+		totalBP += spec.TrancheBP
+		if totalBP > TRANCHE_BP_TOTAL {
+			return nil, errInvalidParams
+		}
+		tranche, ok := bpOf(amount, spec.TrancheBP)
+		if !ok {
+			return nil, errInvalidParams
+		}
+		s.Checkpoints[i] = Checkpoint{CheckpointSpec: spec, Amount: tranche, Status: CHECKPOINT_STATUS_PENDING}
+		allocated += tranche
+	}
+	if totalBP != TRANCHE_BP_TOTAL {
+		return nil, errInvalidParams
+	}
+	s.Checkpoints[len(specs)-1].Amount += amount - allocated
+	return s, nil
+}
+
+// confirm 以佐证中的状态值确认检查点，返回付给承运方的运费
+//
+// 检查点必须按顺序、逐个确认，且每个只能确认一次；佐证状态与预期值不一致时拒绝。
+func (s *Shipment) confirm(index uint64, reported string, now uint64) (uint64, error) {
+	cp, err := s.nextCheckpoint(index)
+	if err != nil {
+		return 0, err
+	}
+	if reported != cp.Expected {
+		return 0, errStatusMismatch
+	}
+	cp.Status = CHECKPOINT_STATUS_CONFIRMED
+	cp.Reported = reported
+	cp.SettledAt = now
+	cp.ToCarrier = cp.Amount
+	s.Released += cp.Amount
+	s.advance()
+	return cp.Amount, nil
+}
+
+// dispute 对下一个待确认的检查点提出争议，暂停该运单的确认与取消
+func (s *Shipment) dispute(index uint64, by [20]byte, note string) error {
+	cp, err := s.nextCheckpoint(index)
+	if err != nil {
+		return err
+	}
+	if len(note) > MAX_SOURCE_BYTES {
+		return errInvalidParams
+	}
+	cp.Status = CHECKPOINT_STATUS_DISPUTED
+	cp.DisputedBy = by
+	cp.DisputeNote = note
+	s.Status = SHIPMENT_STATUS_DISPUTED
+	return nil
+}
+
+// resolve 仲裁人裁决争议中的检查点：toCarrier 付给承运方，该段其余运费退还托运方
+//
+// 裁决后运输继续，进入下一个检查点。
+func (s *Shipment) resolve(toCarrier, now uint64) (toShipper uint64, err error) {
+	if s.Status != SHIPMENT_STATUS_DISPUTED {
+		return 0, errNoDispute
+	}
+	cp := &s.Checkpoints[s.NextIndex]
+	if toCarrier > cp.Amount {
+		return 0, errAwardExceedsTranche
+	}
+	toShipper = cp.Amount - toCarrier
+	cp.Status = CHECKPOINT_STATUS_RESOLVED
+	cp.SettledAt = now
+	cp.ToCarrier = toCarrier
+	cp.ToShipper = toShipper
+	s.Released += toCarrier
+	s.Refunded += toShipper
+	s.Status = SHIPMENT_STATUS_IN_TRANSIT
+	s.advance()
+	return toShipper, nil
+}
+
+// cancel 取消运单，返回退还托运方的金额
+//
+// 首个检查点确认前取消时全额退还；之后只退还尚未付出的各段运费，已付给承运方的不追回。
+// 争议未裁决时不能取消。
+func (s *Shipment) cancel(now uint64) (uint64, error) {
+	switch s.Status {
+	case SHIPMENT_STATUS_IN_TRANSIT:
+	case SHIPMENT_STATUS_DISPUTED:
+		return 0, errShipmentDisputed
+	default:
+		return 0, errShipmentInactive
+	}
+	var refund uint64
+	for i := s.NextIndex; i < uint64(len(s.Checkpoints)); i++ {
+		cp := &s.Checkpoints[i]
+		cp.Status = CHECKPOINT_STATUS_REFUNDED
+		cp.SettledAt = now
+		cp.ToShipper = cp.Amount
+		refund += cp.Amount
+	}
+	s.Refunded += refund
+	s.Status = SHIPMENT_STATUS_CANCELLED
+	return refund, nil
+}
+
+// escrowed 仍托管在合约中的运费
+func (s *Shipment) escrowed() uint64 {
+	return s.Amount - s.Released - s.Refunded
+}
+
+// nextCheckpoint 校验 index 为下一个待确认的检查点且运单处于运输中
+func (s *Shipment) nextCheckpoint(index uint64) (*Checkpoint, error) {
+	switch s.Status {
+	case SHIPMENT_STATUS_IN_TRANSIT:
+	case SHIPMENT_STATUS_DISPUTED:
+		return nil, errShipmentDisputed
+	default:
+		return nil, errShipmentInactive
+	}
+	if index >= uint64(len(s.Checkpoints)) {
+		return nil, errInvalidCheckpoint
+	}
+	if index < s.NextIndex {
+		return nil, errCheckpointDone
+	}
+	if index > s.NextIndex {
+		return nil, errOutOfOrder
+	}
+	return &s.Checkpoints[index], nil
+}
+
+// advance 进入下一个检查点，全部结清后运单完成
+func (s *Shipment) advance() {
+	s.NextIndex++
+	if s.NextIndex == uint64(len(s.Checkpoints)) {
+		s.Status = SHIPMENT_STATUS_DELIVERED
+	}
+}
+
+// bpOf 计算 amount × bp / 10000（向下取整），溢出时返回 false
+func bpOf(amount, bp uint64) (uint64, bool) {
+	if bp != 0 && amount > ^uint64(0)/bp {
+		return 0, false
+	}
+	return amount * bp / TRANCHE_BP_TOTAL, true
+}
+
+// ================================================================================================
+// 参数解析
+// ================================================================================================
+
+// parseCheckpointSpecs 解析 "checkpoints": [ {...}, ... ]
+//
+// 字符串字段按 JSON 规则还原转义；tranche_bp 超过 10000 或不是非负整数时拒绝
+func parseCheckpointSpecs(raw string) ([]CheckpointSpec, bool) {
+	objects := framework.NewContractParams([]byte(raw)).ParseJSONObjectArray("checkpoints")
+	if len(objects) == 0 || len(objects) > MAX_CHECKPOINTS {
+		return nil, false
+	}
+	specs := make([]CheckpointSpec, len(objects))
+	for i, obj := range objects {
+		trancheBP, err := obj.ParseBasisPoints("tranche_bp")
+		if err != nil {
+			return nil, false
+		}
+		specs[i] = CheckpointSpec{
+			Name:      obj.ParseJSON("name"),
+			Source:    obj.ParseJSON("source"),
+			Field:     obj.ParseJSON("field"),
+			Expected:  obj.ParseJSON("expected"),
+			TrancheBP: uint64(trancheBP),
+		}
+	}
+	return specs, true
+}
+
+// reportedStatus 提取承运方 API 响应中的状态字段，字段缺失、不是字符串或为空时返回 false
+func reportedStatus(response []byte, field string) (string, bool) {
+	status := framework.NewContractParams(response).ParseJSON(field)
+	return status, status != ""
+}
+
+// decodeHex 解码十六进制字符串（可带 0x 前缀）
+func decodeHex(s string) ([]byte, bool) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	if len(s)%2 != 0 {
+		return nil, false
+	}
+	out := make([]byte, len(s)/2)
+	for i := range out {
+		hi, ok1 := hexNibble(s[2*i])
+		lo, ok2 := hexNibble(s[2*i+1])
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		out[i] = hi<<4 | lo
+	}
+	return out, true
+}
+
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// ================================================================================================
+// 状态编码
+// ================================================================================================
+//
+// 编码格式（变长，字符串以2字节长度前缀）：
+//
+//	头部：shipper(20) + carrier(20) + arbiter(20) + tokenID + trackingID + status +
+//	      amount(8) + nextIndex(8) + released(8) + refunded(8) + createdAt(8) + checkpointCount(1)
+//	检查点：name + source + field + expected + trancheBP(8) + amount(8) + status + settledAt(8) +
+//	        toCarrier(8) + toShipper(8) + reported + disputedBy(20) + disputeNote
+//	尾部：shipmentID（非空，保证记录不以 0x00 结尾，链上读取去除尾部零字节后仍可解码）
+
+// encodeShipment 编码运单
+func encodeShipment(s *Shipment) []byte {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, s.Shipper[:]...)
+	buf = append(buf, s.Carrier[:]...)
+	buf = append(buf, s.Arbiter[:]...)
+	buf = appendString(buf, s.TokenID)
+	buf = appendString(buf, s.TrackingID)
+	buf = appendString(buf, s.Status)
+	buf = appendUint64(buf, s.Amount)
+	buf = appendUint64(buf, s.NextIndex)
+	buf = appendUint64(buf, s.Released)
+	buf = appendUint64(buf, s.Refunded)
+	buf = appendUint64(buf, s.CreatedAt)
+	buf = append(buf, byte(len(s.Checkpoints)))
+	for _, cp := range s.Checkpoints {
+		buf = appendString(buf, cp.Name)
+		buf = appendString(buf, cp.Source)
+		buf = appendString(buf, cp.Field)
+		buf = appendString(buf, cp.Expected)
+		buf = appendUint64(buf, cp.TrancheBP)
+		buf = appendUint64(buf, cp.Amount)
+		buf = appendString(buf, cp.Status)
+		buf = appendUint64(buf, cp.SettledAt)
+		buf = appendUint64(buf, cp.ToCarrier)
+		buf = appendUint64(buf, cp.ToShipper)
+		buf = appendString(buf, cp.Reported)
+		buf = append(buf, cp.DisputedBy[:]...)
+		buf = appendString(buf, cp.DisputeNote)
+	}
+	return appendString(buf, s.ShipmentID)
+}
+
+// decodeShipment 解码运单，数据格式错误时返回 nil
+func decodeShipment(data []byte) *Shipment {
+	r := &shipmentReader{data: data}
+	s := &Shipment{}
+	r.readBytes(s.Shipper[:])
+	r.readBytes(s.Carrier[:])
+	r.readBytes(s.Arbiter[:])
+	s.TokenID = r.readString()
+	s.TrackingID = r.readString()
+	s.Status = r.readString()
+	s.Amount = r.readUint64()
+	s.NextIndex = r.readUint64()
+	s.Released = r.readUint64()
+	s.Refunded = r.readUint64()
+	s.CreatedAt = r.readUint64()
+	n := int(r.readByte())
+	if r.failed || n == 0 || n > MAX_CHECKPOINTS {
+		return nil
+	}
+	s.Checkpoints = make([]Checkpoint, n)
+	for i := range s.Checkpoints {
+		cp := &s.Checkpoints[i]
+		cp.Name = r.readString()
+		cp.Source = r.readString()
+		cp.Field = r.readString()
+		cp.Expected = r.readString()
+		cp.TrancheBP = r.readUint64()
+		cp.Amount = r.readUint64()
+		cp.Status = r.readString()
+		cp.SettledAt = r.readUint64()
+		cp.ToCarrier = r.readUint64()
+		cp.ToShipper = r.readUint64()
+		cp.Reported = r.readString()
+		r.readBytes(cp.DisputedBy[:])
+		cp.DisputeNote = r.readString()
+	}
+	s.ShipmentID = r.readString()
+	if r.failed || s.ShipmentID == "" {
+		return nil
+	}
+	return s
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, byte(len(s)>>8), byte(len(s)))
+	return append(buf, s...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	for i := 7; i >= 0; i-- {
+		buf = append(buf, byte(v>>(8*uint(i))))
+	}
+	return buf
+}
+
+// shipmentReader 顺序读取编码字段，越界后 failed 置位且后续读取返回零值
+type shipmentReader struct {
+	data   []byte
+	failed bool
+}
+
+func (r *shipmentReader) take(n int) []byte {
+	if r.failed || len(r.data) < n {
+		r.failed = true
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *shipmentReader) readBytes(dst []byte) {
+	copy(dst, r.take(len(dst)))
+}
+
+func (r *shipmentReader) readByte() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *shipmentReader) readUint64() uint64 {
+	var v uint64
+	for _, c := range r.take(8) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func (r *shipmentReader) readString() string {
+	l := r.take(2)
+	if l == nil {
+		return ""
+	}
+	return string(r.take(int(l[0])<<8 | int(l[1])))
+}
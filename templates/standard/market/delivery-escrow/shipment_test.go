@@ -0,0 +1,245 @@
+package main
+
+import "testing"
+
+const testNow = uint64(1736200000)
+
+var (
+	testShipper = [20]byte{0x01}
+	testCarrier = [20]byte{0x02}
+	testArbiter = [20]byte{0x03}
+)
+
+// testSpecs 港口扫描 20% / 清关 30% / 签收 50%
+func testSpecs() []CheckpointSpec {
+	return []CheckpointSpec{
+		{Name: "port_scan", Source: "https://api.carrier.example/track", Field: "status", Expected: "PORT_SCANNED", TrancheBP: 2000},
+		{Name: "customs_clearance", Source: "https://api.customs.example/status", Field: "status", Expected: "CLEARED", TrancheBP: 3000},
+		{Name: "delivered", Source: "https://api.carrier.example/track", Field: "status", Expected: "DELIVERED", TrancheBP: 5000},
+	}
+}
+
+func mustNewShipment(t *testing.T, amount uint64) *Shipment {
+	t.Helper()
+	s, err := newShipment("ship_001", testShipper, testCarrier, testArbiter, "", "MSKU1234567", amount, testSpecs(), testNow)
+	if err != nil {
+		t.Fatalf("newShipment() error = %v", err)
+	}
+	return s
+}
+
+// TestNewShipmentSplit 测试按比例拆分运费，舍入余数计入最后一个检查点
+func TestNewShipmentSplit(t *testing.T) {
+	s := mustNewShipment(t, 1001)
+	want := []uint64{200, 300, 501}
+	for i, cp := range s.Checkpoints {
+		if cp.Amount != want[i] || cp.Status != CHECKPOINT_STATUS_PENDING {
+			t.Errorf("checkpoint %d = %d/%s, want %d/PENDING", i, cp.Amount, cp.Status, want[i])
+		}
+	}
+	if s.escrowed() != 1001 {
+		t.Errorf("escrowed() = %d, want 1001", s.escrowed())
+	}
+
+	specs := testSpecs()
+	specs[2].TrancheBP = 4999
+	if _, err := newShipment("ship_001", testShipper, testCarrier, testArbiter, "", "MSKU1234567", 1000, specs, testNow); err != errInvalidParams {
+		t.Errorf("newShipment(bp sum != 10000) error = %v, want errInvalidParams", err)
+	}
+	if _, err := newShipment("ship_001", testShipper, testCarrier, testCarrier, "", "MSKU1234567", 1000, testSpecs(), testNow); err != errInvalidParams {
+		t.Errorf("newShipment(arbiter == carrier) error = %v, want errInvalidParams", err)
+	}
+	specs = testSpecs()
+	specs[1].Expected = ""
+	if _, err := newShipment("ship_001", testShipper, testCarrier, testArbiter, "", "MSKU1234567", 1000, specs, testNow); err != errInvalidParams {
+		t.Errorf("newShipment(empty expected) error = %v, want errInvalidParams", err)
+	}
+}
+
+// TestConfirmOutOfOrder 测试检查点必须按顺序确认，且每个只能确认一次
+func TestConfirmOutOfOrder(t *testing.T) {
+	s := mustNewShipment(t, 100000)
+
+	if _, err := s.confirm(1, "CLEARED", testNow); err != errOutOfOrder {
+		t.Fatalf("confirm(1) before 0 error = %v, want errOutOfOrder", err)
+	}
+	if _, err := s.confirm(3, "DELIVERED", testNow); err != errInvalidCheckpoint {
+		t.Fatalf("confirm(3) error = %v, want errInvalidCheckpoint", err)
+	}
+
+	released, err := s.confirm(0, "PORT_SCANNED", testNow+1)
+	if err != nil || released != 20000 {
+		t.Fatalf("confirm(0) = %d, %v, want 20000", released, err)
+	}
+	if _, err := s.confirm(0, "PORT_SCANNED", testNow+2); err != errCheckpointDone {
+		t.Errorf("confirm(0) twice error = %v, want errCheckpointDone", err)
+	}
+	if _, err := s.confirm(2, "DELIVERED", testNow+2); err != errOutOfOrder {
+		t.Errorf("confirm(2) before 1 error = %v, want errOutOfOrder", err)
+	}
+
+	s.confirm(1, "CLEARED", testNow+3)
+	s.confirm(2, "DELIVERED", testNow+4)
+	if s.Status != SHIPMENT_STATUS_DELIVERED || s.Released != 100000 || s.escrowed() != 0 {
+		t.Errorf("after all confirms status=%s released=%d escrowed=%d", s.Status, s.Released, s.escrowed())
+	}
+	if _, err := s.confirm(2, "DELIVERED", testNow+5); err != errShipmentInactive {
+		t.Errorf("confirm() after delivery error = %v, want errShipmentInactive", err)
+	}
+}
+
+// TestConfirmStatusMismatch 测试佐证状态与预期值不一致时拒绝，且不改变记账
+func TestConfirmStatusMismatch(t *testing.T) {
+	s := mustNewShipment(t, 100000)
+
+	if _, err := s.confirm(0, "IN_TRANSIT", testNow); err != errStatusMismatch {
+		t.Fatalf("confirm(mismatch) error = %v, want errStatusMismatch", err)
+	}
+	if _, err := s.confirm(0, "port_scanned", testNow); err != errStatusMismatch {
+		t.Fatalf("confirm(case mismatch) error = %v, want errStatusMismatch", err)
+	}
+	if s.NextIndex != 0 || s.Released != 0 || s.Checkpoints[0].Status != CHECKPOINT_STATUS_PENDING {
+		t.Errorf("mismatch changed state: next=%d released=%d status=%s", s.NextIndex, s.Released, s.Checkpoints[0].Status)
+	}
+	if released, err := s.confirm(0, "PORT_SCANNED", testNow); err != nil || released != 20000 {
+		t.Errorf("confirm() after mismatch = %d, %v, want 20000", released, err)
+	}
+}
+
+// TestCancelMidRoute 测试中途取消只退还尚未付出的各段运费
+func TestCancelMidRoute(t *testing.T) {
+	s := mustNewShipment(t, 1001)
+	s.confirm(0, "PORT_SCANNED", testNow+1)
+
+	refund, err := s.cancel(testNow + 2)
+	if err != nil || refund != 801 {
+		t.Fatalf("cancel() = %d, %v, want 801 (300 + 501)", refund, err)
+	}
+	if s.Released != 200 || s.Refunded != 801 || s.escrowed() != 0 {
+		t.Errorf("released=%d refunded=%d escrowed=%d", s.Released, s.Refunded, s.escrowed())
+	}
+	if s.Checkpoints[0].Status != CHECKPOINT_STATUS_CONFIRMED || s.Checkpoints[0].ToShipper != 0 {
+		t.Errorf("released checkpoint changed: %+v", s.Checkpoints[0])
+	}
+	for i := 1; i < 3; i++ {
+		if cp := s.Checkpoints[i]; cp.Status != CHECKPOINT_STATUS_REFUNDED || cp.ToShipper != cp.Amount {
+			t.Errorf("checkpoint %d = %s/%d, want REFUNDED/%d", i, cp.Status, cp.ToShipper, cp.Amount)
+		}
+	}
+	if _, err := s.cancel(testNow + 3); err != errShipmentInactive {
+		t.Errorf("cancel() twice error = %v, want errShipmentInactive", err)
+	}
+	if _, err := s.confirm(1, "CLEARED", testNow+3); err != errShipmentInactive {
+		t.Errorf("confirm() after cancel error = %v, want errShipmentInactive", err)
+	}
+}
+
+// TestCancelBeforeFirstCheckpoint 测试首个检查点确认前取消全额退还
+func TestCancelBeforeFirstCheckpoint(t *testing.T) {
+	s := mustNewShipment(t, 1001)
+	refund, err := s.cancel(testNow)
+	if err != nil || refund != 1001 || s.Released != 0 {
+		t.Errorf("cancel() = %d, %v, released=%d, want full refund 1001", refund, err, s.Released)
+	}
+}
+
+// TestDisputeAndResolve 测试争议暂停确认与取消，仲裁裁决划分该段运费后运输继续
+func TestDisputeAndResolve(t *testing.T) {
+	s := mustNewShipment(t, 100000)
+	s.confirm(0, "PORT_SCANNED", testNow)
+
+	if err := s.dispute(2, testShipper, "damaged"); err != errOutOfOrder {
+		t.Fatalf("dispute(2) error = %v, want errOutOfOrder", err)
+	}
+	if _, err := s.resolve(0, testNow); err != errNoDispute {
+		t.Fatalf("resolve() without dispute error = %v, want errNoDispute", err)
+	}
+	if err := s.dispute(1, testShipper, "container damaged"); err != nil {
+		t.Fatalf("dispute(1) error = %v", err)
+	}
+	if _, err := s.confirm(1, "CLEARED", testNow); err != errShipmentDisputed {
+		t.Errorf("confirm() during dispute error = %v, want errShipmentDisputed", err)
+	}
+	if _, err := s.cancel(testNow); err != errShipmentDisputed {
+		t.Errorf("cancel() during dispute error = %v, want errShipmentDisputed", err)
+	}
+	if _, err := s.resolve(30001, testNow); err != errAwardExceedsTranche {
+		t.Errorf("resolve(> tranche) error = %v, want errAwardExceedsTranche", err)
+	}
+
+	toShipper, err := s.resolve(20000, testNow+1)
+	if err != nil || toShipper != 10000 {
+		t.Fatalf("resolve(20000) = %d, %v, want 10000 to shipper", toShipper, err)
+	}
+	if s.Status != SHIPMENT_STATUS_IN_TRANSIT || s.NextIndex != 2 || s.Released != 40000 || s.Refunded != 10000 {
+		t.Errorf("after resolve status=%s next=%d released=%d refunded=%d", s.Status, s.NextIndex, s.Released, s.Refunded)
+	}
+	if released, err := s.confirm(2, "DELIVERED", testNow+2); err != nil || released != 50000 {
+		t.Errorf("confirm(2) after resolve = %d, %v, want 50000", released, err)
+	}
+	if s.escrowed() != 0 {
+		t.Errorf("escrowed() = %d, want 0", s.escrowed())
+	}
+}
+
+// TestParseCheckpointSpecs 测试检查点参数与承运方响应解析
+func TestParseCheckpointSpecs(t *testing.T) {
+	raw := `{"shipment_id":"ship_001","checkpoints":[` +
+		`{"name":"port_scan","source":"https://api.carrier.example/track","field":"status","expected":"PORT_\u0053CANNED","tranche_bp":"2000"},` +
+		`{"name": "delivered", "source": "https://api.carrier.example/track", "field": "status", "expected": "DELIVERED", "tranche_bp": 8000}]}`
+	specs, ok := parseCheckpointSpecs(raw)
+	if !ok || len(specs) != 2 {
+		t.Fatalf("parseCheckpointSpecs() = %+v, %v", specs, ok)
+	}
+	if specs[1].Name != "delivered" || specs[1].Expected != "DELIVERED" || specs[1].TrancheBP != 8000 {
+		t.Errorf("specs[1] = %+v", specs[1])
+	}
+	if specs[0].Expected != "PORT_SCANNED" {
+		t.Errorf("specs[0].Expected = %q", specs[0].Expected)
+	}
+	for name, bad := range map[string]string{
+		"empty":              `{"checkpoints":[]}`,
+		"tranche overflow":   `{"checkpoints":[{"name":"a","tranche_bp":18446744073709551626}]}`,
+		"tranche above 100%": `{"checkpoints":[{"name":"a","tranche_bp":10001}]}`,
+		"negative tranche":   `{"checkpoints":[{"name":"a","tranche_bp":-1}]}`,
+	} {
+		if _, ok := parseCheckpointSpecs(bad); ok {
+			t.Errorf("parseCheckpointSpecs(%s) ok = true", name)
+		}
+	}
+
+	if got, ok := reportedStatus([]byte(`{"tracking_id":"MSKU1234567","status": "CLEARED"}`), "status"); !ok || got != "CLEARED" {
+		t.Errorf("reportedStatus() = %q, %v", got, ok)
+	}
+	if got, ok := reportedStatus([]byte(`{"note":"say \"status\": \"FAKE\"","status":"CLEARED"}`), "status"); !ok || got != "CLEARED" {
+		t.Errorf("reportedStatus(escaped decoy) = %q, %v", got, ok)
+	}
+	if _, ok := reportedStatus([]byte(`{"status":1}`), "status"); ok {
+		t.Error("reportedStatus(non-string) ok = true")
+	}
+}
+
+// TestShipmentCodecRoundTrip 测试状态编码往返（含链上读取时去除尾部零字节）
+func TestShipmentCodecRoundTrip(t *testing.T) {
+	s := mustNewShipment(t, 100000)
+	s.confirm(0, "PORT_SCANNED", testNow)
+	s.dispute(1, testCarrier, "")
+
+	data := encodeShipment(s)
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	got := decodeShipment(data)
+	if got == nil {
+		t.Fatal("decodeShipment() = nil")
+	}
+	if got.ShipmentID != s.ShipmentID || got.Status != SHIPMENT_STATUS_DISPUTED || got.NextIndex != 1 || got.Released != 20000 {
+		t.Errorf("decoded = %+v", got)
+	}
+	if got.Checkpoints[0].Reported != "PORT_SCANNED" || got.Checkpoints[1].DisputedBy != testCarrier || got.Checkpoints[2].Amount != 50000 {
+		t.Errorf("decoded checkpoints = %+v", got.Checkpoints)
+	}
+	if decodeShipment(data[:len(data)-3]) != nil {
+		t.Error("decodeShipment(truncated) != nil")
+	}
+}
@@ -20,6 +20,10 @@
 //     - 使用 market.Release() 创建分阶段释放计划
 //     - SDK 内部自动处理交易构建、事件发出
 //
+//  3. BondedEscrow / SettleBondedEscrow - 带卖方履约保证金的托管
+//     - 使用 market.EscrowWithBond() 创建托管，买方货款与卖方保证金两侧到位后生效
+//     - 完成、取消、仲裁、募集超时取回分别对应 market.ReleaseEscrow/RefundEscrow/ResolveEscrow/ReclaimEscrow
+//
 // ⚠️ 注意：本模块仅提供原子操作，不包含组合场景（如Swap、Liquidity等）
 //
 // 📚 相关文档
@@ -62,6 +66,7 @@ type MarketContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Market")
@@ -121,6 +126,7 @@ func Initialize() uint32 {
 //
 //export Escrow
 func Escrow() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	buyerStr := params.ParseJSON("buyer")
@@ -211,6 +217,7 @@ func Escrow() uint32 {
 //
 //export Release
 func Release() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	fromStr := params.ParseJSON("from")
@@ -254,5 +261,222 @@ func Release() uint32 {
 	return framework.SUCCESS
 }
 
+// BondedEscrow 创建或出资带卖方履约保证金的托管
+//
+// 使用 helpers/market 模块的 EscrowWithBond / FundEscrow 实现两阶段出资：
+//   - 托管不存在时，调用者作为买方创建托管并存入货款
+//   - 托管已存在时，调用者（买方或卖方）存入自己一侧的资金，卖方存入保证金
+//
+// 两侧资金都到位后托管状态变为 ACTIVE；超过 funding_deadline 仍未到位的，
+// 双方可通过 SettleBondedEscrow（action=reclaim）取回已存入的资金。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "escrow_id": "order_123",          // 托管ID（必填）
+//	  "seller": "seller_address",        // 卖方地址（创建时必填）
+//	  "amount": 10000,                   // 货款金额（创建时必填）
+//	  "bond_amount": 2000,               // 卖方保证金（创建时可选，0 表示不要求保证金）
+//	  "funding_deadline": 1736300000,    // 募集截止时间（创建时必填）
+//	  "arbiter": "arbiter_address"       // 仲裁人地址（创建时必填）
+//	}
+//
+// 工作流程：
+//  1. 解析参数
+//  2. 托管不存在时创建托管并记录仲裁人
+//  3. 调用者存入自己一侧的资金
+//  4. 返回托管视图
+//
+// 返回：
+//   - framework.SUCCESS - 成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是托管双方
+//   - framework.ERROR_TIMEOUT - 已超过募集截止时间
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 余额不足
+//
+// 事件：
+//   - BondedEscrowCreated - 托管创建事件（由 SDK 自动发出）
+//   - EscrowFunded - 出资事件（由 SDK 自动发出），包含 leg（payment/bond）与 status
+//
+//export BondedEscrow
+func BondedEscrow() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	escrowIDStr := params.ParseJSON("escrow_id")
+	if escrowIDStr == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	escrowID := []byte(escrowIDStr)
+	caller := framework.GetCaller()
+
+	// 步骤2：托管不存在时，调用者作为买方创建托管
+	if _, err := market.GetEscrow(escrowID); err != nil {
+		sellerStr := params.ParseJSON("seller")
+		arbiterStr := params.ParseJSON("arbiter")
+		amount := params.ParseJSONInt("amount")
+		bondAmount := params.ParseJSONInt("bond_amount")
+		fundingDeadline := params.ParseJSONInt("funding_deadline")
+		if sellerStr == "" || arbiterStr == "" || amount == 0 || fundingDeadline == 0 {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		seller, err1 := framework.ParseAddressBase58(sellerStr)
+		arbiter, err2 := framework.ParseAddressBase58(arbiterStr)
+		if err1 != nil || err2 != nil {
+			return framework.ERROR_INVALID_PARAMS
+		}
+
+		err := market.EscrowWithBond(
+			caller,
+			seller,
+			framework.TokenID(""), // 货款使用原生币
+			framework.Amount(amount),
+			framework.TokenID(""), // 保证金使用原生币
+			framework.Amount(bondAmount),
+			escrowID,
+			fundingDeadline,
+		)
+		if err != nil {
+			return contractErrorCode(err)
+		}
+		if _, err := framework.AppendStateOutputSimple(arbiterStateID(escrowIDStr), 1, arbiter.ToBytes(), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
+	// 步骤3：调用者存入自己一侧的资金
+	if err := market.FundEscrow(escrowID, caller); err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤4：返回托管视图
+	return returnBondedEscrow(escrowID)
+}
+
+// SettleBondedEscrow 结算带卖方履约保证金的托管
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "escrow_id": "order_123",          // 托管ID（必填）
+//	  "action": "arbitrate",             // release / refund / arbitrate / reclaim（必填）
+//	  "payment_to_buyer": 10000,         // arbitrate 时：退还买方的货款，其余付给卖方
+//	  "bond_to_buyer": 2000              // arbitrate 时：罚没给买方的保证金，其余退还卖方
+//	}
+//
+// 动作与权限：
+//   - release: 买方确认收货，货款付给卖方，保证金退还卖方
+//   - refund: 卖方取消交易，货款退还买方，保证金退还卖方
+//   - arbitrate: 仲裁人裁决，货款与保证金分别划分（例如卖方违约时退还货款并罚没保证金）
+//   - reclaim: 募集超时后，买方或卖方取回自己已存入的一侧资金
+//
+// 返回：
+//   - framework.SUCCESS - 成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者无权执行该动作
+//   - framework.ERROR_NOT_FOUND - 托管不存在
+//   - framework.ERROR_INVALID_STATE - 托管状态不允许该动作
+//
+// 事件（由 SDK 自动发出）：
+//   - EscrowReleased / EscrowRefunded / EscrowResolved - 包含 payment_to_buyer、payment_to_seller、bond_to_buyer、bond_to_seller
+//   - EscrowReclaimed - 包含取回的 leg
+//
+//export SettleBondedEscrow
+func SettleBondedEscrow() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数
+	params := framework.GetContractParams()
+	escrowIDStr := params.ParseJSON("escrow_id")
+	action := params.ParseJSON("action")
+	if escrowIDStr == "" || action == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	escrowID := []byte(escrowIDStr)
+
+	// 步骤2：读取托管记录
+	escrow, err := market.GetEscrow(escrowID)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+	caller := framework.GetCaller()
+
+	// 步骤3：按动作检查权限并结算
+	switch action {
+	case "release":
+		if caller != escrow.Buyer {
+			return framework.ERROR_UNAUTHORIZED
+		}
+		err = market.ReleaseEscrow(escrowID)
+	case "refund":
+		if caller != escrow.Seller {
+			return framework.ERROR_UNAUTHORIZED
+		}
+		err = market.RefundEscrow(escrowID)
+	case "arbitrate":
+		arbiterData, _, stateErr := framework.GetStateFromChain(arbiterStateID(escrowIDStr))
+		if stateErr != nil || len(arbiterData) < 20 {
+			return framework.ERROR_NOT_FOUND
+		}
+		var arbiter framework.Address
+		copy(arbiter[:], arbiterData[:20])
+		if caller != arbiter {
+			return framework.ERROR_UNAUTHORIZED
+		}
+		err = market.ResolveEscrow(
+			escrowID,
+			framework.Amount(params.ParseJSONInt("payment_to_buyer")),
+			framework.Amount(params.ParseJSONInt("bond_to_buyer")),
+		)
+	case "reclaim":
+		err = market.ReclaimEscrow(escrowID, caller)
+	default:
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if err != nil {
+		return contractErrorCode(err)
+	}
+
+	// 步骤4：返回托管视图
+	return returnBondedEscrow(escrowID)
+}
+
+// returnBondedEscrow 返回托管视图（WES ISPC 特性：同步返回业务数据）
+func returnBondedEscrow(escrowID []byte) uint32 {
+	escrow, err := market.GetEscrow(escrowID)
+	if err != nil {
+		return contractErrorCode(err)
+	}
+	result := map[string]interface{}{
+		"escrow_id":        escrow.EscrowID,
+		"status":           escrow.Status,
+		"buyer":            escrow.Buyer.ToString(),
+		"seller":           escrow.Seller.ToString(),
+		"payment_amount":   uint64(escrow.PaymentAmount),
+		"payment_funded":   escrow.PaymentFunded,
+		"bond_amount":      uint64(escrow.BondAmount),
+		"bond_funded":      escrow.BondFunded,
+		"funding_deadline": escrow.FundingDeadline,
+		"payment_to_buyer": uint64(escrow.PaymentToBuyer),
+		"bond_to_buyer":    uint64(escrow.BondToBuyer),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// arbiterStateID 托管仲裁人状态ID
+func arbiterStateID(escrowID string) []byte {
+	return []byte("bonded_escrow_arbiter:" + escrowID)
+}
+
+// contractErrorCode 将 SDK 错误转换为错误码
+func contractErrorCode(err error) uint32 {
+	if contractErr, ok := err.(*framework.ContractError); ok {
+		return contractErr.Code
+	}
+	return framework.ERROR_EXECUTION_FAILED
+}
+
 func main() {}
 
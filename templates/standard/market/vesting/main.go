@@ -13,16 +13,16 @@
 // 🎯 核心功能
 //
 //  1. CreateVesting - 创建释放计划
-//     - 使用 market.Release() 创建分阶段释放计划
-//     - 支持设置释放时间表和释放条件
+//     - 使用 market.ReleaseWithSchedule() 创建悬崖 + 线性释放计划
+//     - 开始时间、悬崖期、总时长随释放计划写入链上状态
 //
 //  2. ClaimVesting - 领取释放的代币
-//     - 根据释放计划领取已解锁的代币
-//     - 自动检查释放条件和时间
+//     - 按链上时间表领取已归属的代币
+//     - 悬崖期内不可领取，到期后可领取剩余全部
 //
 //  3. QueryVesting - 查询释放计划
-//     - 查询释放计划的详细信息
-//     - 查询已释放和待释放的代币数量
+//     - 查询释放计划的时间表与所处阶段
+//     - 查询已归属、已领取与可领取的代币数量
 //
 // 📚 相关文档
 //
@@ -42,9 +42,9 @@ import (
 // 简化分阶段释放操作的实现，开发者只需关注业务逻辑。
 //
 // 分阶段释放特点：
-//   - 支持线性释放（Linear Vesting）
-//   - 支持阶段性释放（Cliff Vesting）
-//   - 支持自定义释放时间表
+//   - 悬崖期（Cliff）内不归属
+//   - 悬崖期后从开始时间起线性归属（Linear Vesting）
+//   - 时间表时间计算见 schedule.go
 type VestingContract struct {
 	framework.ContractBase
 }
@@ -69,6 +69,7 @@ type VestingContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Vesting")
@@ -78,99 +79,106 @@ func Initialize() uint32 {
 	return framework.SUCCESS
 }
 
-// CreateVesting 创建分阶段释放计划
+// CreateVesting 创建悬崖 + 线性释放计划
 //
-// 使用 helpers/market 模块的 Release 函数创建分阶段释放计划。
+// 使用 helpers/market 模块的 ReleaseWithSchedule 函数创建释放计划：
+// 总金额从创建者转入合约地址托管，时间表（开始时间、悬崖期、总时长）随释放计划写入链上状态，
+// ClaimVesting 按时间表校验可领取金额。
 // 适用于代币分配、员工激励、投资解锁等场景。
-// SDK 内部会自动处理：
-//   - 交易构建（自动构建 UTXO 交易）
-//   - 事件发出（自动发出 Release 事件）
 //
 // 参数格式（JSON）:
 //
 //	{
 //	  "beneficiary": "beneficiary_address",  // 受益人地址（Base58编码，必填）
-//	  "token_id": "TOKEN_001",              // 代币ID（可选，nil表示原生代币）
+//	  "token_id": "TOKEN_001",              // 代币ID（可选，空表示原生代币）
 //	  "total_amount": 1000000,              // 总释放金额（必填）
 //	  "vesting_id": "vesting_001",          // 释放计划ID（必填）
-//	  "start_time": 1640995200,             // 开始时间（Unix时间戳，可选）
-//	  "duration": 31536000                  // 释放持续时间（秒，可选）
+//	  "start_time": 1640995200,             // 开始时间（Unix时间戳，可选，默认当前时间）
+//	  "cliff": 7776000,                     // 悬崖期（秒，从 start_time 起算，可选，默认 0）
+//	  "duration": 31536000                  // 总释放时长（秒，从 start_time 起算，必填）
 //	}
 //
+// 时间表：
+//   - start_time + cliff 之前：已归属为 0
+//   - start_time + cliff 起：按 (now - start_time) / duration 线性归属
+//   - start_time + duration 起：全部归属
+//
 // 工作流程：
 //  1. 解析参数并验证
-//  2. 解析受益人地址
-//  3. 调用 market.Release() 创建释放计划
-//     - SDK 内部自动构建交易
+//  2. 构建悬崖 + 线性时间表
+//  3. 调用 market.ReleaseWithSchedule() 托管资金并写入释放计划
 //  4. 发出释放计划创建事件
-//  5. 返回执行结果
-//
-// ⚠️ 注意：实际应用中需要业务规则检查
-//   - 释放时间表验证
-//   - 释放条件设置
-//   - 权限检查（谁可以创建释放计划）
 //
 // 返回：
 //   - framework.SUCCESS - 创建成功
-//   - framework.ERROR_INVALID_PARAMS - 参数无效
-//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//   - framework.ERROR_INVALID_PARAMS - 参数无效（时间表参数错误时返回数据为字段错误详情）
+//   - framework.ERROR_ALREADY_EXISTS - 释放计划ID已存在
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 创建者余额不足
 //
 // 事件：
-//   - Release - 释放计划创建事件（由 SDK 自动发出）
-//     {
-//       "from": "<创建者地址>",
-//       "beneficiary": "<受益人地址>",
-//       "total_amount": 1000000,
-//       "vesting_id": "vesting_001"
-//     }
-//   - VestingCreated - 释放计划创建事件（自定义）
+//   - VestingScheduled - 释放计划创建事件（由 SDK 自动发出）
 //     {
-//       "creator": "<创建者地址>",
+//       "vesting_id": "vesting_001",
+//       "grantor": "<创建者地址>",
 //       "beneficiary": "<受益人地址>",
 //       "total_amount": 1000000,
-//       "vesting_id": "vesting_001"
+//       "start_time": 1640995200,
+//       "cliff": 7776000,
+//       "duration": 31536000
 //     }
 //
 //export CreateVesting
 func CreateVesting() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	beneficiaryStr := params.ParseJSON("beneficiary")
 	tokenIDStr := params.ParseJSON("token_id")
-	totalAmount := params.ParseJSONInt("total_amount")
 	vestingIDStr := params.ParseJSON("vesting_id")
-
+	totalAmount, err := params.ParseAmount("total_amount")
+	if err != nil {
+		return rejectParam(err)
+	}
 	if beneficiaryStr == "" || totalAmount == 0 || vestingIDStr == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 步骤2：解析受益人地址
 	beneficiary, err := framework.ParseAddressBase58(beneficiaryStr)
 	if err != nil {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 步骤3：解析代币ID（可选）
-	var tokenID framework.TokenID
-	if tokenIDStr != "" {
-		tokenID = framework.TokenID(tokenIDStr)
+	// 步骤2：构建悬崖 + 线性时间表
+	startTime, err := params.ParseTimestamp("start_time")
+	if err != nil {
+		return rejectParam(err)
+	}
+	if startTime == 0 {
+		startTime = framework.TimestampUnix(framework.GetTimestamp())
+	}
+	cliff, err := params.ParseDuration("cliff")
+	if err != nil {
+		return rejectParam(err)
+	}
+	duration, err := params.ParseRequiredDuration("duration")
+	if err != nil {
+		return rejectParam(err)
+	}
+	schedule, err := cliffLinearSchedule(startTime, cliff, duration)
+	if err != nil {
+		return rejectParam(err)
 	}
 
-	// 步骤4：使用 SDK 基础能力创建释放计划
-	//
-	// SDK 提供的 market.Release() 会自动处理：
-	//   - 交易构建
-	//   - 事件发出
-	//
-	// ⚠️ 注意：实际应用中需要业务规则检查
-	//   释放时间表、释放条件、权限检查等应在应用层实现
+	// 步骤3：托管资金并写入释放计划（SDK 自动发出 VestingScheduled 事件）
 	caller := framework.GetCaller()
-	err = market.Release(
+	err = market.ReleaseWithSchedule(
 		caller,                        // 创建者地址
 		beneficiary,                   // 受益人地址
-		tokenID,                       // 代币ID
-		framework.Amount(totalAmount), // 总释放金额
+		framework.TokenID(tokenIDStr), // 代币ID
+		totalAmount,                   // 总释放金额
 		[]byte(vestingIDStr),          // 释放计划ID
+		schedule,                      // 悬崖 + 线性时间表
+		false,                         // 不可撤销
 	)
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
@@ -179,110 +187,74 @@ func CreateVesting() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤5：发出释放计划创建事件（自定义事件，包含更多信息）
-	startTimeStr := params.ParseJSON("start_time")
-	durationStr := params.ParseJSON("duration")
-
-	event := framework.NewEvent("VestingCreated")
-	event.AddAddressField("creator", caller)
-	event.AddAddressField("beneficiary", beneficiary)
-	event.AddStringField("vesting_id", vestingIDStr)
-	event.AddUint64Field("total_amount", uint64(totalAmount))
-	if tokenIDStr != "" {
-		event.AddStringField("token_id", tokenIDStr)
-	}
-	if startTimeStr != "" {
-		event.AddStringField("start_time", startTimeStr)
-	}
-	if durationStr != "" {
-		event.AddStringField("duration", durationStr)
-	}
-	framework.EmitEvent(event)
-
 	return framework.SUCCESS
 }
 
-// ClaimVesting 领取释放的代币
+// ClaimVesting 领取已归属的代币
 //
-// 根据释放计划领取已解锁的代币。
-// 实际应用中，应该检查释放条件和时间，计算可领取数量。
+// 受益人领取截至当前时间已归属、尚未领取的全部代币。
+// 悬崖期内没有可领取的代币；到期后可领取剩余全部代币。
 //
 // 参数格式（JSON）:
 //
 //	{
-//	  "vesting_id": "vesting_001",    // 释放计划ID（必填）
-//	  "amount": 10000                 // 领取数量（必填）
+//	  "vesting_id": "vesting_001"    // 释放计划ID（必填）
 //	}
 //
 // 工作流程：
 //  1. 解析参数并验证
-//  2. 查询释放计划信息
-//  3. 检查释放条件（时间、解锁比例等）
-//  4. 计算可领取数量
-//  5. 转移代币给受益人
-//  6. 更新释放计划状态
-//
-// ⚠️ 注意：这是一个简化实现
-//   实际应用中，应该：
-//   - 检查释放时间是否已到
-//   - 计算已解锁的代币数量
-//   - 检查是否已领取完毕
-//   - 更新释放计划状态（使用状态输出）
+//  2. 调用 market.ClaimVested()：按链上时间表计算可领取金额，从合约地址转给受益人并更新已领取金额
+//  3. 返回本次领取金额
 //
 // 返回：
-//   - framework.SUCCESS - 领取成功
+//   - framework.SUCCESS - 领取成功，返回数据 {"vesting_id":...,"amount":...}
 //   - framework.ERROR_INVALID_PARAMS - 参数无效
-//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//   - framework.ERROR_NOT_FOUND - 释放计划不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是受益人
+//   - framework.ERROR_INVALID_STATE - 没有可领取的代币（悬崖期内或已全部领取）
 //
 // 事件：
-//   - VestingClaimed - 代币领取事件
+//   - VestingClaimed - 代币领取事件（由 SDK 自动发出）
 //     {
-//       "beneficiary": "<受益人地址>",
 //       "vesting_id": "vesting_001",
-//       "amount": 10000
+//       "beneficiary": "<受益人地址>",
+//       "claimed": 250000,
+//       "amount": 250000
 //     }
 //
 //export ClaimVesting
 func ClaimVesting() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	vestingIDStr := params.ParseJSON("vesting_id")
-	amount := params.ParseJSONInt("amount")
-
-	if vestingIDStr == "" || amount == 0 {
+	if vestingIDStr == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 步骤2：查询释放计划信息
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该从状态输出查询释放计划信息
-	//   检查释放时间、计算可领取数量等
-
-	// 步骤3：检查释放条件
-	// ⚠️ 注意：实际应用中需要实现
-	//   - 检查释放时间是否已到
-	//   - 计算已解锁的代币数量
-	//   - 检查是否已领取完毕
-
-	// 步骤4：转移代币给受益人
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该从托管账户转移代币给受益人
-	//   这里简化处理，实际应该使用 token.Transfer() 从托管账户转移
-
-	// 步骤5：发出代币领取事件
-	caller := framework.GetCaller()
-	event := framework.NewEvent("VestingClaimed")
-	event.AddAddressField("beneficiary", caller)
-	event.AddStringField("vesting_id", vestingIDStr)
-	event.AddUint64Field("amount", uint64(amount))
-	framework.EmitEvent(event)
+	// 步骤2：按时间表领取
+	amount, err := market.ClaimVested(framework.GetCaller(), []byte(vestingIDStr))
+	if err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
+	// 步骤3：返回本次领取金额
+	result := map[string]interface{}{
+		"vesting_id": vestingIDStr,
+		"amount":     uint64(amount),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
 	return framework.SUCCESS
 }
 
 // QueryVesting 查询释放计划
 //
-// 查询释放计划的详细信息，包括总金额、已释放金额、待释放金额等。
+// 查询释放计划的时间表与当前归属情况。
 //
 // 参数格式（JSON）:
 //
@@ -290,14 +262,22 @@ func ClaimVesting() uint32 {
 //	  "vesting_id": "vesting_001"  // 释放计划ID（必填）
 //	}
 //
-// 工作流程：
-//  1. 解析参数并验证
-//  2. 查询释放计划信息（从状态输出）
-//  3. 计算已释放和待释放的代币数量
-//  4. 返回查询结果
+// 返回数据：
 //
-// ⚠️ 注意：这是一个简化实现
-//   实际应用中，应该从状态输出查询释放计划信息
+//	{
+//	  "vesting_id": "vesting_001",
+//	  "status": "ACTIVE",
+//	  "phase": "VESTING",            // PRE_CLIFF / VESTING / FULLY_VESTED
+//	  "start_time": 1640995200,
+//	  "cliff_time": 1648771200,      // start_time + cliff
+//	  "end_time": 1672531200,        // start_time + duration
+//	  "total_amount": 1000000,
+//	  "vested": 500000,
+//	  "claimed": 250000,
+//	  "claimable": 250000,
+//	  "locked": 500000,
+//	  ...
+//	}
 //
 // 返回：
 //   - framework.SUCCESS - 查询成功
@@ -306,26 +286,40 @@ func ClaimVesting() uint32 {
 //
 //export QueryVesting
 func QueryVesting() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	vestingIDStr := params.ParseJSON("vesting_id")
-
 	if vestingIDStr == "" {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
-	// 步骤2：查询释放计划信息
-	// ⚠️ 注意：这是一个简化实现
-	//   实际应用中，应该从状态输出查询释放计划信息
-	//   包括总金额、已释放金额、待释放金额、释放时间表等
-
-	// 步骤3：返回查询结果
-	// 注意：实际应用中应该返回完整的释放计划信息
-	result := `{"vesting_id":"` + vestingIDStr + `","status":"active"}`
-	framework.SetReturnData([]byte(result))
+	// 步骤2：读取释放计划
+	grant, err := market.GetVestingGrant([]byte(vestingIDStr))
+	if err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
+	// 步骤3：按当前时间返回归属情况
+	if err := framework.SetReturnJSON(vestingView(grant, framework.GetTimestamp())); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
 	return framework.SUCCESS
 }
 
-func main() {}
+// rejectParam 返回参数错误码，字段错误同时以 JSON 返回错误详情
+func rejectParam(err error) uint32 {
+	contractErr, ok := err.(*framework.ContractError)
+	if !ok {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	if contractErr.Field != "" {
+		framework.SetReturnJSON(contractErr.Detail())
+	}
+	return contractErr.Code
+}
 
+func main() {}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/market"
+)
+
+// ================================================================================================
+// 悬崖 + 线性释放预设（纯函数）
+// ================================================================================================
+//
+// 本文件只包含不依赖宿主函数的时间表构建与查询视图，不带 build tag，
+// 便于在非WASM环境中直接运行单元测试（go test）。
+// 归属计算由 market.VestingGrant 完成，导出方法（main.go）负责读写链上状态。
+//
+// 时间表以 start_time 为起点：
+//   - start_time + cliff 之前：已归属为 0
+//   - start_time + cliff 起：按 (now - start_time) / duration 线性归属（悬崖到期时一次归属悬崖期对应部分）
+//   - start_time + duration 起：全部归属
+
+// 释放阶段（QueryVesting 的 phase 字段）
+const (
+	// VESTING_PHASE_PRE_CLIFF 悬崖期内，尚无归属
+	VESTING_PHASE_PRE_CLIFF = "PRE_CLIFF"
+	// VESTING_PHASE_VESTING 线性归属中
+	VESTING_PHASE_VESTING = "VESTING"
+	// VESTING_PHASE_FULLY_VESTED 已全部归属
+	VESTING_PHASE_FULLY_VESTED = "FULLY_VESTED"
+)
+
+// cliffLinearSchedule 构建悬崖 + 线性释放时间表
+//
+// cliff 与 duration 均以 start 为起点计算；cliff 为 0 表示无悬崖期。
+// duration 为 0 或 cliff 超过 duration 时返回对应参数的字段错误。
+func cliffLinearSchedule(start framework.TimestampUnix, cliff, duration framework.DurationSeconds) (market.VestingSchedule, error) {
+	if duration.IsZero() {
+		return market.VestingSchedule{}, framework.NewFieldError(framework.ERROR_INVALID_PARAMS, "duration", "duration must be greater than 0")
+	}
+	if cliff > duration {
+		return market.VestingSchedule{}, framework.NewFieldError(framework.ERROR_INVALID_PARAMS, "cliff", "cliff must not exceed duration")
+	}
+	if uint64(start) > ^uint64(0)-uint64(duration) {
+		return market.VestingSchedule{}, framework.NewFieldError(framework.ERROR_INVALID_PARAMS, "start_time", "start_time + duration overflows")
+	}
+	return market.VestingSchedule{StartTime: uint64(start), Cliff: uint64(cliff), Duration: uint64(duration)}, nil
+}
+
+// vestingPhase 返回释放计划在 now 时刻所处的阶段
+func vestingPhase(g *market.VestingGrant, now uint64) string {
+	switch vested := g.VestedAmount(now); {
+	case vested == g.TotalAmount:
+		return VESTING_PHASE_FULLY_VESTED
+	case now < g.Schedule.StartTime+g.Schedule.Cliff:
+		return VESTING_PHASE_PRE_CLIFF
+	default:
+		return VESTING_PHASE_VESTING
+	}
+}
+
+// vestingView 构建 QueryVesting 返回的释放计划详情
+func vestingView(g *market.VestingGrant, now uint64) map[string]interface{} {
+	vested := g.VestedAmount(now)
+	return map[string]interface{}{
+		"vesting_id":   g.VestingID,
+		"status":       g.Status,
+		"phase":        vestingPhase(g, now),
+		"grantor":      g.Grantor.ToString(),
+		"beneficiary":  g.Beneficiary.ToString(),
+		"token_id":     string(g.TokenID),
+		"total_amount": uint64(g.TotalAmount),
+		"start_time":   g.Schedule.StartTime,
+		"cliff_time":   g.Schedule.StartTime + g.Schedule.Cliff,
+		"end_time":     g.Schedule.StartTime + g.Schedule.Duration,
+		"vested":       uint64(vested),
+		"claimed":      uint64(g.Claimed),
+		"claimable":    uint64(vested - g.Claimed),
+		"locked":       uint64(g.TotalAmount - vested),
+		"now":          now,
+	}
+}
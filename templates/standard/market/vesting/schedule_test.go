@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/weisyn/contract-sdk-go/framework"
+	"github.com/weisyn/contract-sdk-go/helpers/market"
+)
+
+const (
+	testStart    = framework.TimestampUnix(1735689600)
+	testCliff    = framework.DurationSeconds(90 * 86400)
+	testDuration = framework.DurationSeconds(360 * 86400)
+	testTotal    = framework.Amount(360000)
+)
+
+var (
+	testGrantor     = framework.Address{0x01}
+	testBeneficiary = framework.Address{0x02}
+)
+
+func mustNewGrant(t *testing.T) *market.VestingGrant {
+	t.Helper()
+	schedule, err := cliffLinearSchedule(testStart, testCliff, testDuration)
+	if err != nil {
+		t.Fatalf("cliffLinearSchedule() error = %v", err)
+	}
+	grant, err := market.NewVestingGrant("vesting_001", testGrantor, testBeneficiary, "", testTotal, schedule, false)
+	if err != nil {
+		t.Fatalf("NewVestingGrant() error = %v", err)
+	}
+	return grant
+}
+
+// TestCliffLinearVesting 测试悬崖期前、悬崖到期时、线性期中与到期后的归属
+func TestCliffLinearVesting(t *testing.T) {
+	grant := mustNewGrant(t)
+	start := uint64(testStart)
+
+	cases := []struct {
+		name   string
+		now    uint64
+		vested framework.Amount
+		phase  string
+	}{
+		{"before start", start - 1, 0, VESTING_PHASE_PRE_CLIFF},
+		{"pre-cliff", start + uint64(testCliff) - 1, 0, VESTING_PHASE_PRE_CLIFF},
+		{"at cliff", start + uint64(testCliff), 90000, VESTING_PHASE_VESTING},
+		{"mid", start + 180*86400, 180000, VESTING_PHASE_VESTING},
+		{"at end", start + uint64(testDuration), testTotal, VESTING_PHASE_FULLY_VESTED},
+		{"post-duration", start + uint64(testDuration) + 86400, testTotal, VESTING_PHASE_FULLY_VESTED},
+	}
+	for _, tc := range cases {
+		if got := grant.VestedAmount(tc.now); got != tc.vested {
+			t.Errorf("%s: VestedAmount() = %d, want %d", tc.name, got, tc.vested)
+		}
+		if got := vestingPhase(grant, tc.now); got != tc.phase {
+			t.Errorf("%s: vestingPhase() = %s, want %s", tc.name, got, tc.phase)
+		}
+	}
+}
+
+// TestClaimEnforcesSchedule 测试领取按时间表限制：悬崖期内拒绝，之后只能领取新归属的部分
+func TestClaimEnforcesSchedule(t *testing.T) {
+	grant := mustNewGrant(t)
+	start := uint64(testStart)
+
+	if _, err := grant.Claim(testBeneficiary, start+uint64(testCliff)-1); err == nil {
+		t.Fatal("Claim() before cliff succeeded, want error")
+	}
+
+	steps := []struct {
+		now  uint64
+		want framework.Amount
+	}{
+		{start + uint64(testCliff), 90000},
+		{start + 180*86400, 90000},
+		{start + uint64(testDuration) + 86400, 180000},
+	}
+	for _, step := range steps {
+		payout, err := grant.Claim(testBeneficiary, step.now)
+		if err != nil {
+			t.Fatalf("Claim() at %d error = %v", step.now, err)
+		}
+		if payout.Amount != step.want {
+			t.Errorf("Claim() at %d = %d, want %d", step.now, payout.Amount, step.want)
+		}
+	}
+	if grant.Claimed != testTotal {
+		t.Errorf("Claimed = %d, want %d", grant.Claimed, testTotal)
+	}
+	if _, err := grant.Claim(testBeneficiary, start+uint64(testDuration)+2*86400); err == nil {
+		t.Error("Claim() after full claim succeeded, want error")
+	}
+}
+
+// TestVestingView 测试查询视图中的时间表与金额
+func TestVestingView(t *testing.T) {
+	grant := mustNewGrant(t)
+	now := uint64(testStart) + 180*86400
+	if _, err := grant.Claim(testBeneficiary, uint64(testStart)+uint64(testCliff)); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	view := vestingView(grant, now)
+	want := map[string]interface{}{
+		"phase":      VESTING_PHASE_VESTING,
+		"cliff_time": uint64(testStart) + uint64(testCliff),
+		"end_time":   uint64(testStart) + uint64(testDuration),
+		"vested":     uint64(180000),
+		"claimed":    uint64(90000),
+		"claimable":  uint64(90000),
+		"locked":     uint64(180000),
+	}
+	for key, value := range want {
+		if view[key] != value {
+			t.Errorf("view[%s] = %v, want %v", key, view[key], value)
+		}
+	}
+}
+
+// TestCliffLinearScheduleInvalid 测试时间表参数错误返回对应字段
+func TestCliffLinearScheduleInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		start    framework.TimestampUnix
+		cliff    framework.DurationSeconds
+		duration framework.DurationSeconds
+		field    string
+	}{
+		{"zero duration", testStart, 0, 0, "duration"},
+		{"cliff after end", testStart, testDuration + 1, testDuration, "cliff"},
+		{"end overflows", framework.TimestampUnix(^uint64(0)), 0, testDuration, "start_time"},
+	}
+	for _, tc := range cases {
+		_, err := cliffLinearSchedule(tc.start, tc.cliff, tc.duration)
+		contractErr, ok := err.(*framework.ContractError)
+		if !ok || contractErr.Code != framework.ERROR_INVALID_PARAMS || contractErr.Field != tc.field {
+			t.Errorf("%s: error = %v, want ERROR_INVALID_PARAMS on %s", tc.name, err, tc.field)
+		}
+	}
+}
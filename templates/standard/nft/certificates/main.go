@@ -69,6 +69,7 @@ type CertificatesNFTContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "DigitalArtNFT")
@@ -128,6 +129,7 @@ func Initialize() uint32 {
 //
 //export MintNFT
 func MintNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -239,6 +241,7 @@ func MintNFT() uint32 {
 //
 //export TransferNFT
 func TransferNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -307,6 +310,7 @@ func TransferNFT() uint32 {
 //
 //export QueryNFT
 func QueryNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -24,6 +24,10 @@
 //     - 查询NFT的所有者
 //     - 查询NFT的元数据
 //
+//  4. UpdateTicketMetadata - 更新门票元数据
+//     - 活动时间、场馆变更时由发行方更新链上元数据
+//     - 铸造时设置 frozen 的门票元数据不可变
+//
 // 📚 相关文档
 //
 //   - [Token 模块文档](../../helpers/token/README.md)
@@ -49,6 +53,27 @@ type TicketsNFTContract struct {
 	framework.ContractBase
 }
 
+// 门票状态常量
+const (
+	// TICKET_STATUS_VALID 有效：门票未核销
+	TICKET_STATUS_VALID = byte(0)
+	// TICKET_STATUS_REDEEMED 已核销：门票已入场使用，元数据不再更新
+	TICKET_STATUS_REDEEMED = byte(1)
+)
+
+// STATE_TICKET_PREFIX 门票记录状态ID前缀，完整ID为 ticket:{token_id}
+const STATE_TICKET_PREFIX = "ticket:"
+
+// ticketRecord 门票链上记录
+//
+// 编码格式：issuer(20) + status(1) + frozen(1) + metadata
+type ticketRecord struct {
+	Issuer   framework.Address
+	Status   byte
+	Frozen   bool
+	Metadata string
+}
+
 // Initialize 初始化合约
 //
 // 合约部署时自动调用，用于初始化合约状态。
@@ -69,6 +94,7 @@ type TicketsNFTContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "DigitalArtNFT")
@@ -94,7 +120,9 @@ func Initialize() uint32 {
 //	  "ticket_name": "Sunset Over Mountains", // 艺术品名称（必填）
 //	  "event": "Alice",               // 艺术家名称（必填）
 //	  "description": "A beautiful...", // 艺术品描述（可选）
-//	  "image_url": "https://..."       // 图片URL（可选）
+//	  "image_url": "https://...",      // 图片URL（可选）
+//	  "metadata": "ipfs://...",        // 链上元数据（可选，如元数据URI）
+//	  "frozen": true                   // 元数据是否不可变（可选，默认 false）
 //	}
 //
 // 工作流程：
@@ -103,8 +131,9 @@ func Initialize() uint32 {
 //  3. 验证tokenID唯一性（检查是否已存在）
 //  4. 调用 token.Mint() 铸造NFT
 //     - SDK 内部自动构建交易
-//  5. 发出NFT铸造事件（包含元数据）
-//  6. 返回执行结果
+//  5. 写入门票记录 ticket:{token_id}（发行方为调用者，记录元数据与 frozen 标志）
+//  6. 发出NFT铸造事件（包含元数据）
+//  7. 返回执行结果
 //
 // ⚠️ 注意：实际应用中需要业务规则检查
 //   - tokenID唯一性检查（确保每个NFT唯一）
@@ -123,11 +152,13 @@ func Initialize() uint32 {
 //       "to": "<接收者地址>",
 //       "token_id": "art_001",
 //       "ticket_name": "Sunset Over Mountains",
-//       "event": "Alice"
+//       "event": "Alice",
+//       "frozen": false
 //     }
 //
 //export MintNFT
 func MintNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -170,7 +201,18 @@ func MintNFT() uint32 {
 		return framework.ERROR_EXECUTION_FAILED
 	}
 
-	// 步骤5：发出NFT铸造事件（包含元数据）
+	// 步骤5：写入门票记录（发行方 = 铸造调用者）
+	record := &ticketRecord{
+		Issuer:   framework.GetCaller(),
+		Status:   TICKET_STATUS_VALID,
+		Frozen:   parseJSONBool(params.GetRawData(), "frozen"),
+		Metadata: params.ParseJSON("metadata"),
+	}
+	if _, err := framework.AppendStateOutputSimple(buildTicketStateID(tokenIDStr), 1, encodeTicketRecord(record), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 步骤6：发出NFT铸造事件（包含元数据）
 	descriptionStr := params.ParseJSON("description")
 	imageURLStr := params.ParseJSON("image_url")
 
@@ -185,6 +227,10 @@ func MintNFT() uint32 {
 	if imageURLStr != "" {
 		event.AddStringField("image_url", imageURLStr)
 	}
+	if record.Metadata != "" {
+		event.AddStringField("metadata", record.Metadata)
+	}
+	event.AddBoolField("frozen", record.Frozen)
 	framework.EmitEvent(event)
 
 	return framework.SUCCESS
@@ -239,6 +285,7 @@ func MintNFT() uint32 {
 //
 //export TransferNFT
 func TransferNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -280,6 +327,92 @@ func TransferNFT() uint32 {
 	return framework.SUCCESS
 }
 
+// UpdateTicketMetadata 更新门票元数据
+//
+// 活动时间、场馆等信息在活动开始前可能变更，发行方可更新门票的链上元数据。
+// 铸造时设置 frozen 的门票元数据不可变，可用于承诺不可篡改的纪念藏品。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "token_id": "TICKET_001",                 // 门票tokenID（必填）
+//	  "metadata": "ipfs://.../ticket_v2.json"   // 新的元数据（必填）
+//	}
+//
+// 工作流程：
+//  1. 解析参数并验证
+//  2. 读取门票记录
+//  3. 检查调用者为发行方
+//  4. 检查门票未冻结且未核销
+//  5. 写入新版本门票记录
+//  6. 发出元数据更新事件
+//
+// 返回：
+//   - framework.SUCCESS - 更新成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_NOT_FOUND - 门票记录不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是发行方
+//   - framework.ERROR_INVALID_STATE - 元数据已冻结或门票已核销
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - TicketMetadataUpdated - 门票元数据更新事件
+//     {
+//       "token_id": "TICKET_001",
+//       "old_metadata": "ipfs://.../ticket_v1.json",
+//       "metadata": "ipfs://.../ticket_v2.json",
+//       "issuer": "<发行方地址>"
+//     }
+//
+//export UpdateTicketMetadata
+func UpdateTicketMetadata() uint32 {
+	framework.BeginInvocation()
+	// 步骤1：解析参数并验证
+	params := framework.GetContractParams()
+	tokenIDStr := params.ParseJSON("token_id")
+	metadata := params.ParseJSON("metadata")
+
+	if tokenIDStr == "" || metadata == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 步骤2：读取门票记录
+	stateID := buildTicketStateID(tokenIDStr)
+	data, version, err := framework.GetStateFromChain(stateID)
+	if err != nil || version == 0 || len(data) == 0 {
+		return framework.ERROR_NOT_FOUND
+	}
+	record := decodeTicketRecord(data)
+
+	// 步骤3：检查调用者为发行方
+	caller := framework.GetCaller()
+	if caller != record.Issuer {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	// 步骤4：冻结或已核销的门票不可更新
+	if record.Frozen || record.Status != TICKET_STATUS_VALID {
+		return framework.ERROR_INVALID_STATE
+	}
+
+	// 步骤5：写入新版本门票记录
+	oldMetadata := record.Metadata
+	record.Metadata = metadata
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, encodeTicketRecord(record), nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	// 步骤6：发出元数据更新事件
+	event := framework.NewEvent("TicketMetadataUpdated")
+	event.AddStringField("token_id", tokenIDStr)
+	event.AddStringField("old_metadata", oldMetadata)
+	event.AddStringField("metadata", metadata)
+	event.AddAddressField("issuer", caller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
 // QueryNFT 查询NFT信息
 //
 // 查询NFT的所有者信息。
@@ -307,6 +440,7 @@ func TransferNFT() uint32 {
 //
 //export QueryNFT
 func QueryNFT() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	tokenIDStr := params.ParseJSON("token_id")
@@ -328,13 +462,74 @@ func QueryNFT() uint32 {
 		return framework.ERROR_NOT_FOUND
 	}
 
-	// 步骤3：返回查询结果
-	// 注意：实际应用中应该返回NFT的完整信息（元数据等）
-	result := `{"token_id":"` + tokenIDStr + `","owner":"` + caller.ToString() + `","balance":1}`
-	framework.SetReturnData([]byte(result))
+	// 步骤3：返回查询结果（包含门票记录中的元数据与冻结标志）
+	result := map[string]interface{}{
+		"token_id": tokenIDStr,
+		"owner":    caller.ToString(),
+		"balance":  uint64(1),
+	}
+	if data, version, err := framework.GetStateFromChain(buildTicketStateID(tokenIDStr)); err == nil && version > 0 {
+		record := decodeTicketRecord(data)
+		result["issuer"] = record.Issuer.ToString()
+		result["metadata"] = record.Metadata
+		result["frozen"] = record.Frozen
+		result["redeemed"] = record.Status == TICKET_STATUS_REDEEMED
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
 
 	return framework.SUCCESS
 }
 
-func main() {}
+// buildTicketStateID 构建门票记录状态ID
+func buildTicketStateID(tokenID string) []byte {
+	return []byte(STATE_TICKET_PREFIX + tokenID)
+}
+
+// encodeTicketRecord 编码门票记录
+func encodeTicketRecord(r *ticketRecord) []byte {
+	data := make([]byte, 0, 22+len(r.Metadata))
+	data = append(data, r.Issuer[:]...)
+	data = append(data, r.Status)
+	if r.Frozen {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	return append(data, r.Metadata...)
+}
+
+// decodeTicketRecord 解码门票记录
+//
+// 链上读取会去掉末尾的零字节，元数据为空时 status/frozen 可能被截断，按 0 补齐
+func decodeTicketRecord(data []byte) *ticketRecord {
+	header := make([]byte, 22)
+	copy(header, data)
 
+	r := &ticketRecord{Status: header[20], Frozen: header[21] == 1}
+	copy(r.Issuer[:], header[:20])
+	if len(data) > 22 {
+		r.Metadata = string(data[22:])
+	}
+	return r
+}
+
+// parseJSONBool 解析 JSON 布尔字段（兼容 true 与 "true"），不存在返回 false
+func parseJSONBool(raw []byte, key string) bool {
+	s := string(raw)
+	pattern := `"` + key + `":`
+	for i := 0; i+len(pattern) <= len(s); i++ {
+		if s[i:i+len(pattern)] != pattern {
+			continue
+		}
+		rest := s[i+len(pattern):]
+		for len(rest) > 0 && (rest[0] == ' ' || rest[0] == '"') {
+			rest = rest[1:]
+		}
+		return len(rest) >= 4 && rest[:4] == "true"
+	}
+	return false
+}
+
+func main() {}
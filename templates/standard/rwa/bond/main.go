@@ -79,6 +79,7 @@ type BondContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Bond")
@@ -133,6 +134,7 @@ func Initialize() uint32 {
 //
 //export TokenizeAsset
 func TokenizeAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	assetID := params.ParseJSON("asset_id")
@@ -268,6 +270,7 @@ func TokenizeAsset() uint32 {
 //
 //export TransferAsset
 func TransferAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -385,6 +388,7 @@ func TransferAsset() uint32 {
 //
 //export EscrowAsset
 func EscrowAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	buyerStr := params.ParseJSON("buyer")
@@ -494,6 +498,7 @@ func EscrowAsset() uint32 {
 //
 //export ReleaseYield
 func ReleaseYield() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	beneficiaryStr := params.ParseJSON("beneficiary")
@@ -30,6 +30,10 @@
 //     - 使用 market.Release() 创建分阶段分红释放
 //     - 适用于公司分红、收益分配等场景
 //
+//  5. SetTransferHold - 合规冻结
+//     - 合规官冻结或解除地址的股权转移
+//     - 合规官密钥通过 RotateRoleKey / AcceptRoleKey 轮换，泄露时由 owner 紧急轮换
+//
 // 📚 相关文档
 //
 //   - [RWA 模块文档](../../helpers/rwa/README.md)
@@ -61,28 +65,75 @@ type EquityContract struct {
 	framework.ContractBase
 }
 
+// 可轮换角色（轮换协议见 framework/role_rotation.go）
+const (
+	// ROLE_OWNER 合约所有者，可紧急轮换合规官
+	ROLE_OWNER = "owner"
+	// ROLE_COMPLIANCE_OFFICER 合规官，可冻结地址的股权转移
+	ROLE_COMPLIANCE_OFFICER = "compliance_officer"
+)
+
+// STATE_TRANSFER_HOLD_PREFIX 转移冻结状态ID前缀，完整格式：transfer_hold_{address}
+const STATE_TRANSFER_HOLD_PREFIX = "transfer_hold_"
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: ROLE_OWNER})
+	framework.RegisterRole(framework.RoleConfig{Role: ROLE_COMPLIANCE_OFFICER, AdminRole: ROLE_OWNER})
+}
+
 // Initialize 初始化合约
 //
 // 在合约部署时调用一次，用于初始化合约状态。
 //
-// 参数：无
+// 参数格式（JSON，可选）:
+//
+//	{
+//	  "compliance_officer": "Cf1..."      // 合规官地址（Base58编码，默认为调用者）
+//	}
 //
 // 返回：
 //   - SUCCESS (0) - 初始化成功
+//   - ERROR_INVALID_PARAMS (1) - 合规官地址无效
+//   - ERROR_ALREADY_EXISTS (5) - 已初始化
 //
 // 事件：
 //   - ContractInitialized - 合约初始化事件
 //     {
 //     "contract": "RWA",
-//     "owner": "<合约所有者地址>"
+//     "owner": "<合约所有者地址>",
+//     "compliance_officer": "<合规官地址>"
 //     }
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
+
+	officer := caller
+	if officerStr := framework.GetContractParams().ParseJSON("compliance_officer"); officerStr != "" {
+		addr, err := framework.ParseAddressBase58(officerStr)
+		if err != nil {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		officer = addr
+	}
+	if err := framework.InitRole(ROLE_OWNER, caller); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if err := framework.InitRole(ROLE_COMPLIANCE_OFFICER, officer); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Equity")
 	event.AddAddressField("owner", caller)
+	event.AddAddressField("compliance_officer", officer)
 	framework.EmitEvent(event)
 
 	return framework.SUCCESS
@@ -133,6 +184,7 @@ func Initialize() uint32 {
 //
 //export TokenizeAsset
 func TokenizeAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	assetID := params.ParseJSON("asset_id")
@@ -250,6 +302,7 @@ func TokenizeAsset() uint32 {
 //   - ERROR_INVALID_PARAMS (1) - 参数错误
 //   - ERROR_INSUFFICIENT_BALANCE (2) - 余额不足
 //   - ERROR_EXECUTION_FAILED (6) - 执行失败
+//   - ERROR_PERMISSION_DENIED - 发送者或接收者被合规官冻结（见 SetTransferHold）
 //
 // 事件：
 //   - AssetTransferred - 资产转移事件
@@ -267,6 +320,7 @@ func TokenizeAsset() uint32 {
 //
 //export TransferAsset
 func TransferAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -289,7 +343,12 @@ func TransferAsset() uint32 {
 
 	// 步骤3：合规检查（应用层业务逻辑）
 	//
-	// ⚠️ 注意：实际应用中，这里应该包含合规检查逻辑
+	// 合规官冻结的地址不能发送或接收股权（见 SetTransferHold）
+	if transferHeld(caller) || transferHeld(to) {
+		return framework.ERROR_PERMISSION_DENIED
+	}
+	//
+	// ⚠️ 注意：实际应用中，这里还应该包含其他合规检查逻辑
 	// 例如：
 	//   - KYC/AML 检查：验证接收者是否通过 KYC/AML 验证
 	//   - 监管框架验证：验证交易是否符合当地监管要求
@@ -384,6 +443,7 @@ func TransferAsset() uint32 {
 //
 //export EscrowAsset
 func EscrowAsset() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	buyerStr := params.ParseJSON("buyer")
@@ -493,6 +553,7 @@ func EscrowAsset() uint32 {
 //
 //export ReleaseYield
 func ReleaseYield() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：获取并解析参数
 	params := framework.GetContractParams()
 	beneficiaryStr := params.ParseJSON("beneficiary")
@@ -567,4 +628,193 @@ func ReleaseYield() uint32 {
 	return framework.SUCCESS
 }
 
+// SetTransferHold 合规冻结
+//
+// 合规官冻结或解除地址的股权转移，被冻结的地址调用 TransferAsset 发送或接收股权时返回 ERROR_PERMISSION_DENIED。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "address": "Cf1...",                // 目标地址（Base58编码，必填）
+//	  "held": "true"                       // "true" 冻结，其他值解除
+//	}
+//
+// 返回：
+//   - SUCCESS (0) - 设置成功
+//   - ERROR_INVALID_PARAMS (1) - 参数错误
+//   - ERROR_UNAUTHORIZED (3) - 调用者不是合规官
+//   - ERROR_EXECUTION_FAILED (6) - 执行失败
+//
+// 事件：
+//   - EquityTransferHoldSet - 合规冻结事件
+//     {
+//     "address": "<目标地址>",
+//     "held": "true",
+//     "compliance_officer": "<合规官地址>"
+//     }
+//
+// 注意事项：
+//   - 合规官轮换待接受期间，当前合规官与新地址均可设置（见 RotateRoleKey）
+//
+//export SetTransferHold
+func SetTransferHold() uint32 {
+	framework.BeginInvocation()
+	caller := framework.GetCaller()
+	if !framework.HasRole(ROLE_COMPLIANCE_OFFICER, caller) {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
+	params := framework.GetContractParams()
+	target, err := framework.ParseAddressBase58(params.ParseJSON("address"))
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	held := params.ParseJSON("held") == "true"
+
+	stateID := transferHoldStateID(target)
+	_, version, _ := framework.GetStateFromChain(stateID)
+	value := []byte{0}
+	if held {
+		value[0] = 1
+	}
+	if _, err := framework.AppendStateOutputSimple(stateID, version+1, value, nil); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	event := framework.NewEvent("EquityTransferHoldSet")
+	event.AddAddressField("address", target)
+	if held {
+		event.AddStringField("held", "true")
+	} else {
+		event.AddStringField("held", "false")
+	}
+	event.AddAddressField("compliance_officer", caller)
+	framework.EmitEvent(event)
+
+	return framework.SUCCESS
+}
+
+// transferHoldStateID 转移冻结状态ID：transfer_hold_{address}
+func transferHoldStateID(addr framework.Address) []byte {
+	return append([]byte(STATE_TRANSFER_HOLD_PREFIX), addr.ToBytes()...)
+}
+
+// transferHeld 地址是否被合规官冻结
+func transferHeld(addr framework.Address) bool {
+	data, _ := framework.GetState(string(transferHoldStateID(addr)))
+	return len(data) > 0 && data[0] == 1
+}
+
+// RotateRoleKey 发起角色密钥轮换
+//
+// 当前角色持有者指定新地址，新地址需在接受期限（默认 7 天）内调用 AcceptRoleKey 完成轮换。
+// 待接受期间新旧地址均持有该角色（合规官轮换期间两个地址都可以 SetTransferHold）。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "compliance_officer",       // 角色：compliance_officer / owner（必填）
+//	  "new_address": "Cf1..."             // 新地址（Base58编码，必填）
+//	}
+//
+// 返回：
+//   - SUCCESS (0) - 轮换已发起
+//   - ERROR_INVALID_PARAMS (1) - 参数错误
+//   - ERROR_UNAUTHORIZED (3) - 调用者不是当前持有者
+//   - ERROR_NOT_FOUND (4) - 角色不存在
+//
+// 事件：
+//   - RoleKeyRotationStarted - 轮换发起事件（由 SDK 发出）
+//
+//export RotateRoleKey
+func RotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	if err := framework.RotateRoleKey(role, newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// AcceptRoleKey 接受角色密钥轮换
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "compliance_officer"        // 角色（必填）
+//	}
+//
+// 返回：
+//   - SUCCESS (0) - 轮换完成，旧地址失效
+//   - ERROR_UNAUTHORIZED (3) - 调用者不是待接受的新地址
+//   - ERROR_NOT_FOUND (4) - 没有待接受的轮换
+//   - ERROR_TIMEOUT (8) - 已过接受期限
+//
+// 事件：
+//   - RoleKeyRotated - 轮换完成事件（mode=accepted，由 SDK 发出）
+//
+//export AcceptRoleKey
+func AcceptRoleKey() uint32 {
+	framework.BeginInvocation()
+	role := framework.GetContractParams().ParseJSON("role")
+	if role == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	if err := framework.AcceptRoleKey(role); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
+// EmergencyRotateRoleKey 紧急轮换合规官
+//
+// 合规官密钥泄露时由 owner 直接替换，跳过新地址确认，并撤销待接受的轮换。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "compliance_officer",       // 角色（必填，owner 不支持紧急轮换）
+//	  "new_address": "Cf1..."             // 新地址（Base58编码，必填）
+//	}
+//
+// 返回：
+//   - SUCCESS (0) - 轮换完成
+//   - ERROR_INVALID_PARAMS (1) - 参数错误
+//   - ERROR_UNAUTHORIZED (3) - 调用者不是 owner，或角色不支持紧急轮换
+//
+// 事件：
+//   - RoleKeyRotated - 轮换完成事件（mode=emergency，由 SDK 发出）
+//
+//export EmergencyRotateRoleKey
+func EmergencyRotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	if err := framework.EmergencyRotate(role, newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
 func main() {}
@@ -68,6 +68,7 @@ type StakingContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Staking")
@@ -121,6 +122,7 @@ func Initialize() uint32 {
 //
 //export Stake
 func Stake() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -202,6 +204,7 @@ func Stake() uint32 {
 //
 //export Unstake
 func Unstake() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -277,6 +280,7 @@ func Unstake() uint32 {
 //
 //export Delegate
 func Delegate() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -351,6 +355,7 @@ func Delegate() uint32 {
 //
 //export Undelegate
 func Undelegate() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -69,6 +69,7 @@ type DelegationContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Delegation")
@@ -132,6 +133,7 @@ func Initialize() uint32 {
 //
 //export Delegate
 func Delegate() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -231,6 +233,7 @@ func Delegate() uint32 {
 //
 //export Undelegate
 func Undelegate() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
@@ -306,6 +309,7 @@ func Undelegate() uint32 {
 //
 //export QueryDelegation
 func QueryDelegation() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	validatorStr := params.ParseJSON("validator")
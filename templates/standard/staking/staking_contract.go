@@ -35,6 +35,7 @@ type StakingContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	contract := &StakingContract{}
 
 	// TODO: 解析初始化参数
@@ -73,6 +74,7 @@ func Initialize() uint32 {
 //
 //export Stake
 func Stake() uint32 {
+	framework.BeginInvocation()
 	contract := &StakingContract{}
 
 	staker := []byte(contract.GetCaller())
@@ -128,6 +130,7 @@ func Stake() uint32 {
 //
 //export Unstake
 func Unstake() uint32 {
+	framework.BeginInvocation()
 	contract := &StakingContract{}
 
 	staker := []byte(contract.GetCaller())
@@ -197,6 +200,7 @@ func Unstake() uint32 {
 //
 //export ClaimRewards
 func ClaimRewards() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 
@@ -204,6 +208,7 @@ func ClaimRewards() uint32 {
 //
 //export Delegate
 func Delegate() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 
@@ -27,10 +27,13 @@
 //  4. Approve - 授权
 //     - 使用 token.Approve() 授权其他地址使用代币
 //     - 支持 ERC-20 风格的授权机制
+//     - 被授权地址通过 TransferFrom 在额度内代所有者转账（token.TransferFrom()）
+//     - 通过 GetAllowance 查询剩余授权额度（token.Allowance()）
 //
 //  5. Freeze - 冻结
 //     - 使用 token.Freeze() 冻结指定地址的代币
-//     - 适用于合规、风控等场景
+//     - 适用于合规、风控等场景，仅 guardian 可调用
+//     - guardian 密钥通过 RotateRoleKey / AcceptRoleKey 轮换，泄露时由 owner 紧急轮换
 //
 //  6. Airdrop - 空投
 //     - 使用 token.Airdrop() 批量空投代币
@@ -56,35 +59,120 @@ type TokenContract struct {
 	framework.ContractBase
 }
 
+// STATE_TOKEN_ID 本合约代币ID的状态键（Initialize 注册代币类别时写入）
+const STATE_TOKEN_ID = "token_id"
+
+// 可轮换角色（轮换协议见 framework/role_rotation.go）
+const (
+	// ROLE_OWNER 合约所有者，可紧急轮换 guardian
+	ROLE_OWNER = "owner"
+	// ROLE_GUARDIAN 守护者，可冻结代币
+	ROLE_GUARDIAN = "guardian"
+)
+
+func init() {
+	framework.RegisterRole(framework.RoleConfig{Role: ROLE_OWNER})
+	framework.RegisterRole(framework.RoleConfig{Role: ROLE_GUARDIAN, AdminRole: ROLE_OWNER})
+}
+
 // Initialize 初始化合约
 //
 // 合约部署时自动调用，用于初始化合约状态。
 //
+// 参数格式（JSON，均为可选）:
+//
+//	{
+//	  "symbol": "MTK",           // 代币符号；提供时注册本合约的代币类别
+//	  "enforce_issuer": true,    // 是否拒绝其他合约铸造到该类别（默认 false）
+//	  "guardian": "Cf1..."       // 守护者地址（Base58编码，默认为调用者）
+//	}
+//
 // 工作流程：
-//  1. 获取合约调用者（部署者）
-//  2. 发出合约初始化事件
+//  1. 获取合约调用者（部署者），设置 owner 与 guardian 角色
+//  2. 提供 symbol 时：
+//     - 以 token.DeriveTokenID("ERC20", 合约地址, symbol) 派生代币ID
+//     - 调用 token.RegisterTokenClass() 登记本合约为发行方
+//     - 记录代币ID，之后的转账、铸造等操作使用该代币ID
+//  3. 发出合约初始化事件
+//
+// 未提供 symbol 时沿用原生代币（空代币ID），与之前的行为一致。
 //
 // 返回：
 //   - framework.SUCCESS - 初始化成功
+//   - framework.ERROR_INVALID_PARAMS - guardian 地址无效
+//   - framework.ERROR_ALREADY_EXISTS - 已初始化，或代币类别已由其他发行方注册
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
 //
 // 事件：
+//   - TokenClassRegistered - 代币类别注册事件（仅提供 symbol 时，由 SDK 发出）
 //   - ContractInitialized - 合约初始化事件
 //     {
 //       "contract": "Token",
-//       "owner": "<合约所有者地址>"
+//       "owner": "<合约所有者地址>",
+//       "guardian": "<守护者地址>",
+//       "token_id": "ERC20_<32位十六进制>"
 //     }
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
+	params := framework.GetContractParams()
+	symbol := params.ParseJSON("symbol")
+
+	guardian := caller
+	if guardianStr := params.ParseJSON("guardian"); guardianStr != "" {
+		addr, err := framework.ParseAddressBase58(guardianStr)
+		if err != nil {
+			return framework.ERROR_INVALID_PARAMS
+		}
+		guardian = addr
+	}
+	if err := framework.InitRole(ROLE_OWNER, caller); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	if err := framework.InitRole(ROLE_GUARDIAN, guardian); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	tokenID := framework.TokenID("")
+	if symbol != "" {
+		contractAddr := framework.GetContractAddress()
+		tokenID = token.DeriveTokenID("ERC20", string(contractAddr[:]), symbol)
+		enforce := parseJSONBool(params.GetRawData(), "enforce_issuer")
+		if err := token.RegisterTokenClass(tokenID, contractAddr, enforce); err != nil {
+			if contractErr, ok := err.(*framework.ContractError); ok {
+				return contractErr.Code
+			}
+			return framework.ERROR_EXECUTION_FAILED
+		}
+		if _, err := framework.AppendStateOutputSimple([]byte(STATE_TOKEN_ID), 1, []byte(tokenID), nil); err != nil {
+			return framework.ERROR_EXECUTION_FAILED
+		}
+	}
+
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "Token")
 	event.AddAddressField("owner", caller)
+	event.AddAddressField("guardian", guardian)
+	event.AddStringField("token_id", string(tokenID))
 	framework.EmitEvent(event)
 
 	return framework.SUCCESS
 }
 
+// contractTokenID 本合约的代币ID；Initialize 未注册代币类别时为空（原生代币）
+func contractTokenID() framework.TokenID {
+	data, _ := framework.GetState(STATE_TOKEN_ID)
+	return framework.TokenID(data)
+}
+
 // Transfer 转账代币
 //
 // 使用 helpers/token 模块的 Transfer 函数进行代币转账。
@@ -126,6 +214,7 @@ func Initialize() uint32 {
 //
 //export Transfer
 func Transfer() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -145,7 +234,7 @@ func Transfer() uint32 {
 	caller := framework.GetCaller()
 
 	// 使用helpers进行转账
-	err = token.Transfer(caller, to, framework.TokenID(""), framework.Amount(amount))
+	err = token.Transfer(caller, to, contractTokenID(), framework.Amount(amount))
 	if err != nil {
 		// 检查错误类型
 		if contractErr, ok := err.(*framework.ContractError); ok {
@@ -196,6 +285,7 @@ func Transfer() uint32 {
 //
 //export Mint
 func Mint() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -219,7 +309,7 @@ func Mint() uint32 {
 	//
 	// ⚠️ 注意：实际应用中需要权限检查
 	//   只有授权地址才能调用 Mint，权限检查逻辑应在应用层实现
-	err = token.Mint(to, framework.TokenID(""), framework.Amount(amount))
+	err = token.Mint(to, contractTokenID(), framework.Amount(amount))
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
 			return contractErr.Code
@@ -266,6 +356,7 @@ func Mint() uint32 {
 //
 //export Burn
 func Burn() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	amount := params.ParseJSONInt("amount")
@@ -278,7 +369,7 @@ func Burn() uint32 {
 	caller := framework.GetCaller()
 
 	// 使用helpers进行销毁
-	err := token.Burn(caller, framework.TokenID(""), framework.Amount(amount))
+	err := token.Burn(caller, contractTokenID(), framework.Amount(amount))
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
 			return contractErr.Code
@@ -326,6 +417,7 @@ func Burn() uint32 {
 //
 //export Approve
 func Approve() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	spenderStr := params.ParseJSON("spender")
@@ -345,7 +437,82 @@ func Approve() uint32 {
 	caller := framework.GetCaller()
 
 	// 使用helpers进行授权
-	err = token.Approve(caller, spender, framework.TokenID(""), framework.Amount(amount))
+	err = token.Approve(caller, spender, contractTokenID(), framework.Amount(amount))
+	if err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+
+	return framework.SUCCESS
+}
+
+// TransferFrom 代理转账
+//
+// 使用 helpers/token 模块的 TransferFrom 函数，由被授权地址（调用者）在授权额度内
+// 从所有者地址转出代币，并扣减授权额度。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "owner": "owner_address",     // 代币所有者地址（Base58编码，必填）
+//	  "to": "receiver_address",     // 接收者地址（Base58编码，必填）
+//	  "amount": 100                 // 转账数量（必填）
+//	}
+//
+// 工作流程：
+//  1. 解析参数并验证
+//  2. 解析所有者与接收者地址
+//  3. 调用 token.TransferFrom() 进行代理转账
+//     - SDK 内部检查并扣减调用者的授权额度
+//     - SDK 内部自动处理余额检查与交易构建
+//  4. 返回执行结果
+//
+// 返回：
+//   - framework.SUCCESS - 转账成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 授权额度不足（含未授权）
+//   - framework.ERROR_INSUFFICIENT_BALANCE - 所有者余额不足
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+// 事件：
+//   - Transfer - 转账事件（由 SDK 自动发出，from 为所有者）
+//   - ApprovalSpent - 授权额度消耗事件（由 SDK 自动发出，owner / spender / amount / remaining_allowance）
+//   - TransferFrom - 代理转账事件（由 SDK 自动发出）
+//     {
+//       "spender": "<调用者地址>",
+//       "owner": "<所有者地址>",
+//       "to": "<接收者地址>",
+//       "amount": 100,
+//       "remaining_allowance": 900
+//     }
+//
+//export TransferFrom
+func TransferFrom() uint32 {
+	framework.BeginInvocation()
+	// 获取参数
+	params := framework.GetContractParams()
+	ownerStr := params.ParseJSON("owner")
+	toStr := params.ParseJSON("to")
+	amount := params.ParseJSONInt("amount")
+
+	if ownerStr == "" || toStr == "" || amount == 0 {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 解析地址
+	owner, err := framework.ParseAddressBase58(ownerStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	to, err := framework.ParseAddressBase58(toStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 使用helpers进行代理转账（调用者为被授权地址）
+	err = token.TransferFrom(owner, framework.GetCaller(), to, contractTokenID(), framework.Amount(amount))
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
 			return contractErr.Code
@@ -356,6 +523,71 @@ func Approve() uint32 {
 	return framework.SUCCESS
 }
 
+// GetAllowance 查询授权额度
+//
+// 使用 helpers/token 模块的 Allowance 函数读取 Approve 写入的当前授权额度，
+// 供前端展示被授权地址的剩余额度。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "owner": "owner_address",     // 代币所有者地址（Base58编码，必填）
+//	  "spender": "spender_address"  // 被授权地址（Base58编码，必填）
+//	}
+//
+// 返回数据（JSON）:
+//
+//	{
+//	  "owner": "<所有者地址>",
+//	  "spender": "<被授权地址>",
+//	  "token_id": "<代币ID>",
+//	  "allowance": 900
+//	}
+//
+// 未授权、已撤销或已被 TransferFrom 用完时 allowance 为 0。
+// 再次调用 Approve 会覆盖原额度（不累加）。
+//
+// 返回：
+//   - framework.SUCCESS - 查询成功
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_EXECUTION_FAILED - 执行失败
+//
+//export GetAllowance
+func GetAllowance() uint32 {
+	framework.BeginInvocation()
+	// 获取参数
+	params := framework.GetContractParams()
+	ownerStr := params.ParseJSON("owner")
+	spenderStr := params.ParseJSON("spender")
+
+	if ownerStr == "" || spenderStr == "" {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 解析地址
+	owner, err := framework.ParseAddressBase58(ownerStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+	spender, err := framework.ParseAddressBase58(spenderStr)
+	if err != nil {
+		return framework.ERROR_INVALID_PARAMS
+	}
+
+	// 查询授权额度并返回
+	tokenID := contractTokenID()
+	result := map[string]interface{}{
+		"owner":     owner.ToString(),
+		"spender":   spender.ToString(),
+		"token_id":  string(tokenID),
+		"allowance": uint64(token.Allowance(owner, spender, tokenID)),
+	}
+	if err := framework.SetReturnJSON(result); err != nil {
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
+}
+
 // Airdrop 批量空投代币
 //
 // 使用 helpers/token 模块的 Airdrop 函数批量空投代币。
@@ -385,7 +617,7 @@ func Approve() uint32 {
 //  6. 返回执行结果
 //
 // ⚠️ 注意：
-//   - 本示例使用简化的 JSON 解析，实际应用中应使用完整的 JSON 解析库
+//   - recipients 须为字符串数组，amounts 元素可为数字或数字字符串；任一元素类型不符时整体视为参数无效
 //   - 批量空投可能涉及大量交易，需要注意 Gas 费用
 //
 // 返回：
@@ -404,23 +636,18 @@ func Approve() uint32 {
 //
 //export Airdrop
 func Airdrop() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
-	recipientsStr := params.ParseJSON("recipients")
-	amountsStr := params.ParseJSON("amounts")
-
-	if recipientsStr == "" || amountsStr == "" {
-		return framework.ERROR_INVALID_PARAMS
-	}
 
 	// 解析接收者地址数组
-	recipientAddrs := parseJSONArray(recipientsStr)
+	recipientAddrs := params.ParseJSONStringArray("recipients")
 	if len(recipientAddrs) == 0 {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
 	// 解析金额数组
-	amounts := parseJSONIntArray(amountsStr)
+	amounts := params.ParseJSONUintArray("amounts")
 	if len(amounts) == 0 {
 		return framework.ERROR_INVALID_PARAMS
 	}
@@ -447,7 +674,7 @@ func Airdrop() uint32 {
 	}
 
 	// 使用helpers进行空投
-	err := token.Airdrop(caller, recipients, framework.TokenID(""))
+	err := token.Airdrop(caller, recipients, contractTokenID())
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
 			return contractErr.Code
@@ -480,13 +707,13 @@ func Airdrop() uint32 {
 //     - SDK 内部自动构建交易
 //  4. 返回执行结果
 //
-// ⚠️ 注意：实际应用中需要权限检查
-//   - 只有授权地址才能调用 Freeze
-//   - 权限检查逻辑应在应用层实现
+// ⚠️ 注意：只有 guardian 可以调用 Freeze
+//   - guardian 轮换待接受期间，当前 guardian 与新地址均可冻结（见 RotateRoleKey）
 //
 // 返回：
 //   - framework.SUCCESS - 冻结成功
 //   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是 guardian
 //   - framework.ERROR_EXECUTION_FAILED - 执行失败
 //
 // 事件：
@@ -498,6 +725,11 @@ func Airdrop() uint32 {
 //
 //export Freeze
 func Freeze() uint32 {
+	framework.BeginInvocation()
+	if !framework.HasRole(ROLE_GUARDIAN, framework.GetCaller()) {
+		return framework.ERROR_UNAUTHORIZED
+	}
+
 	// 获取参数
 	params := framework.GetContractParams()
 	targetStr := params.ParseJSON("target")
@@ -514,7 +746,7 @@ func Freeze() uint32 {
 	}
 
 	// 使用helpers进行冻结
-	err = token.Freeze(target, framework.TokenID(""), framework.Amount(amount))
+	err = token.Freeze(target, contractTokenID(), framework.Amount(amount))
 	if err != nil {
 		if contractErr, ok := err.(*framework.ContractError); ok {
 			return contractErr.Code
@@ -525,118 +757,133 @@ func Freeze() uint32 {
 	return framework.SUCCESS
 }
 
-// parseJSONArray 解析JSON字符串数组
-func parseJSONArray(jsonStr string) []string {
-	jsonStr = trimSpace(jsonStr)
-	if len(jsonStr) < 2 || jsonStr[0] != '[' || jsonStr[len(jsonStr)-1] != ']' {
-		return nil
-	}
-
-	jsonStr = jsonStr[1 : len(jsonStr)-1]
-	if len(jsonStr) == 0 {
-		return []string{}
-	}
-
-	var result []string
-	start := 0
-	inString := false
-
-	for i := 0; i < len(jsonStr); i++ {
-		c := jsonStr[i]
-		if c == '"' {
-			inString = !inString
-		} else if c == ',' && !inString {
-			item := jsonStr[start:i]
-			item = trimSpace(item)
-			if len(item) >= 2 && item[0] == '"' && item[len(item)-1] == '"' {
-				item = item[1 : len(item)-1]
-			}
-			if len(item) > 0 {
-				result = append(result, item)
-			}
-			start = i + 1
-		}
+// RotateRoleKey 发起角色密钥轮换
+//
+// 当前角色持有者指定新地址，新地址需在接受期限（默认 7 天）内调用 AcceptRoleKey 完成轮换。
+// 待接受期间新旧地址均持有该角色（guardian 轮换期间两个地址都可以 Freeze）。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "guardian",          // 角色：guardian / owner（必填）
+//	  "new_address": "Cf1..."      // 新地址（Base58编码，必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 轮换已发起
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_NOT_FOUND - 角色不存在
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是当前持有者
+//
+// 事件：
+//   - RoleKeyRotationStarted - 轮换发起事件（由 SDK 发出）
+//
+//export RotateRoleKey
+func RotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	if start < len(jsonStr) {
-		item := jsonStr[start:]
-		item = trimSpace(item)
-		if len(item) >= 2 && item[0] == '"' && item[len(item)-1] == '"' {
-			item = item[1 : len(item)-1]
-		}
-		if len(item) > 0 {
-			result = append(result, item)
+	if err := framework.RotateRoleKey(role, newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
 		}
+		return framework.ERROR_EXECUTION_FAILED
 	}
-
-	return result
+	return framework.SUCCESS
 }
 
-// parseJSONIntArray 解析JSON整数数组
-func parseJSONIntArray(jsonStr string) []uint64 {
-	jsonStr = trimSpace(jsonStr)
-	if len(jsonStr) < 2 || jsonStr[0] != '[' || jsonStr[len(jsonStr)-1] != ']' {
-		return nil
-	}
-
-	jsonStr = jsonStr[1 : len(jsonStr)-1]
-	if len(jsonStr) == 0 {
-		return []uint64{}
-	}
-
-	var result []uint64
-	start := 0
-
-	for i := 0; i < len(jsonStr); i++ {
-		c := jsonStr[i]
-		if c == ',' {
-			item := trimSpace(jsonStr[start:i])
-			if len(item) > 0 {
-				amount := parseUint64(item)
-				result = append(result, amount)
-			}
-			start = i + 1
-		}
+// AcceptRoleKey 接受角色密钥轮换
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "guardian"           // 角色（必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 轮换完成，旧地址失效
+//   - framework.ERROR_NOT_FOUND - 没有待接受的轮换
+//   - framework.ERROR_TIMEOUT - 已过接受期限
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是待接受的新地址
+//
+// 事件：
+//   - RoleKeyRotated - 轮换完成事件（mode=accepted，由 SDK 发出）
+//
+//export AcceptRoleKey
+func AcceptRoleKey() uint32 {
+	framework.BeginInvocation()
+	role := framework.GetContractParams().ParseJSON("role")
+	if role == "" {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	if start < len(jsonStr) {
-		item := trimSpace(jsonStr[start:])
-		if len(item) > 0 {
-			amount := parseUint64(item)
-			result = append(result, amount)
+	if err := framework.AcceptRoleKey(role); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
 		}
+		return framework.ERROR_EXECUTION_FAILED
 	}
-
-	return result
+	return framework.SUCCESS
 }
 
-// trimSpace 移除字符串两端的空格
-func trimSpace(s string) string {
-	start := 0
-	end := len(s)
-
-	for start < end && s[start] == ' ' {
-		start++
-	}
-	for end > start && s[end-1] == ' ' {
-		end--
+// EmergencyRotateRoleKey 紧急轮换 guardian
+//
+// guardian 密钥泄露时由 owner 直接替换，跳过新地址确认，并撤销待接受的轮换。
+//
+// 参数格式（JSON）:
+//
+//	{
+//	  "role": "guardian",          // 角色（必填，owner 不支持紧急轮换）
+//	  "new_address": "Cf1..."      // 新地址（Base58编码，必填）
+//	}
+//
+// 返回：
+//   - framework.SUCCESS - 轮换完成
+//   - framework.ERROR_INVALID_PARAMS - 参数无效
+//   - framework.ERROR_UNAUTHORIZED - 调用者不是 owner，或角色不支持紧急轮换
+//
+// 事件：
+//   - RoleKeyRotated - 轮换完成事件（mode=emergency，由 SDK 发出）
+//
+//export EmergencyRotateRoleKey
+func EmergencyRotateRoleKey() uint32 {
+	framework.BeginInvocation()
+	params := framework.GetContractParams()
+	role := params.ParseJSON("role")
+	newAddress, err := framework.ParseAddressBase58(params.ParseJSON("new_address"))
+	if role == "" || err != nil {
+		return framework.ERROR_INVALID_PARAMS
 	}
 
-	return s[start:end]
+	if err := framework.EmergencyRotate(role, newAddress); err != nil {
+		if contractErr, ok := err.(*framework.ContractError); ok {
+			return contractErr.Code
+		}
+		return framework.ERROR_EXECUTION_FAILED
+	}
+	return framework.SUCCESS
 }
 
-// parseUint64 解析无符号整数
-func parseUint64(s string) uint64 {
-	var result uint64
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if c >= '0' && c <= '9' {
-			result = result*10 + uint64(c-'0')
-		} else {
-			break
+// parseJSONBool 解析 JSON 布尔字段（兼容 true 与 "true"），不存在返回 false
+func parseJSONBool(raw []byte, key string) bool {
+	s := string(raw)
+	pattern := `"` + key + `":`
+	for i := 0; i+len(pattern) <= len(s); i++ {
+		if s[i:i+len(pattern)] != pattern {
+			continue
+		}
+		rest := s[i+len(pattern):]
+		for len(rest) > 0 && (rest[0] == ' ' || rest[0] == '"') {
+			rest = rest[1:]
 		}
+		return len(rest) >= 4 && rest[:4] == "true"
 	}
-	return result
+	return false
 }
 
 func main() {}
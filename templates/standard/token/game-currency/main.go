@@ -82,6 +82,7 @@ type GameCurrencyContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "GameCurrency")
@@ -132,6 +133,7 @@ func Initialize() uint32 {
 //
 //export Transfer
 func Transfer() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -202,6 +204,7 @@ func Transfer() uint32 {
 //
 //export Mint
 func Mint() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -272,6 +275,7 @@ func Mint() uint32 {
 //
 //export Burn
 func Burn() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	amount := params.ParseJSONInt("amount")
@@ -332,6 +336,7 @@ func Burn() uint32 {
 //
 //export Approve
 func Approve() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	spenderStr := params.ParseJSON("spender")
@@ -391,7 +396,7 @@ func Approve() uint32 {
 //  6. 返回执行结果
 //
 // ⚠️ 注意：
-//   - 本示例使用简化的 JSON 解析，实际应用中应使用完整的 JSON 解析库
+//   - recipients 须为字符串数组，amounts 元素可为数字或数字字符串；任一元素类型不符时整体视为参数无效
 //   - 批量空投可能涉及大量交易，需要注意 Gas 费用
 //
 // 返回：
@@ -410,23 +415,18 @@ func Approve() uint32 {
 //
 //export Airdrop
 func Airdrop() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
-	recipientsStr := params.ParseJSON("recipients")
-	amountsStr := params.ParseJSON("amounts")
-
-	if recipientsStr == "" || amountsStr == "" {
-		return framework.ERROR_INVALID_PARAMS
-	}
 
 	// 解析接收者地址数组
-	recipientAddrs := parseJSONArray(recipientsStr)
+	recipientAddrs := params.ParseJSONStringArray("recipients")
 	if len(recipientAddrs) == 0 {
 		return framework.ERROR_INVALID_PARAMS
 	}
 
 	// 解析金额数组
-	amounts := parseJSONIntArray(amountsStr)
+	amounts := params.ParseJSONUintArray("amounts")
 	if len(amounts) == 0 {
 		return framework.ERROR_INVALID_PARAMS
 	}
@@ -504,6 +504,7 @@ func Airdrop() uint32 {
 //
 //export Freeze
 func Freeze() uint32 {
+	framework.BeginInvocation()
 	// 获取参数
 	params := framework.GetContractParams()
 	targetStr := params.ParseJSON("target")
@@ -531,119 +532,5 @@ func Freeze() uint32 {
 	return framework.SUCCESS
 }
 
-// parseJSONArray 解析JSON字符串数组
-func parseJSONArray(jsonStr string) []string {
-	jsonStr = trimSpace(jsonStr)
-	if len(jsonStr) < 2 || jsonStr[0] != '[' || jsonStr[len(jsonStr)-1] != ']' {
-		return nil
-	}
-
-	jsonStr = jsonStr[1 : len(jsonStr)-1]
-	if len(jsonStr) == 0 {
-		return []string{}
-	}
-
-	var result []string
-	start := 0
-	inString := false
-
-	for i := 0; i < len(jsonStr); i++ {
-		c := jsonStr[i]
-		if c == '"' {
-			inString = !inString
-		} else if c == ',' && !inString {
-			item := jsonStr[start:i]
-			item = trimSpace(item)
-			if len(item) >= 2 && item[0] == '"' && item[len(item)-1] == '"' {
-				item = item[1 : len(item)-1]
-			}
-			if len(item) > 0 {
-				result = append(result, item)
-			}
-			start = i + 1
-		}
-	}
-
-	if start < len(jsonStr) {
-		item := jsonStr[start:]
-		item = trimSpace(item)
-		if len(item) >= 2 && item[0] == '"' && item[len(item)-1] == '"' {
-			item = item[1 : len(item)-1]
-		}
-		if len(item) > 0 {
-			result = append(result, item)
-		}
-	}
-
-	return result
-}
-
-// parseJSONIntArray 解析JSON整数数组
-func parseJSONIntArray(jsonStr string) []uint64 {
-	jsonStr = trimSpace(jsonStr)
-	if len(jsonStr) < 2 || jsonStr[0] != '[' || jsonStr[len(jsonStr)-1] != ']' {
-		return nil
-	}
-
-	jsonStr = jsonStr[1 : len(jsonStr)-1]
-	if len(jsonStr) == 0 {
-		return []uint64{}
-	}
-
-	var result []uint64
-	start := 0
-
-	for i := 0; i < len(jsonStr); i++ {
-		c := jsonStr[i]
-		if c == ',' {
-			item := trimSpace(jsonStr[start:i])
-			if len(item) > 0 {
-				amount := parseUint64(item)
-				result = append(result, amount)
-			}
-			start = i + 1
-		}
-	}
-
-	if start < len(jsonStr) {
-		item := trimSpace(jsonStr[start:])
-		if len(item) > 0 {
-			amount := parseUint64(item)
-			result = append(result, amount)
-		}
-	}
-
-	return result
-}
-
-// trimSpace 移除字符串两端的空格
-func trimSpace(s string) string {
-	start := 0
-	end := len(s)
-
-	for start < end && s[start] == ' ' {
-		start++
-	}
-	for end > start && s[end-1] == ' ' {
-		end--
-	}
-
-	return s[start:end]
-}
-
-// parseUint64 解析无符号整数
-func parseUint64(s string) uint64 {
-	var result uint64
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if c >= '0' && c <= '9' {
-			result = result*10 + uint64(c-'0')
-		} else {
-			break
-		}
-	}
-	return result
-}
-
 func main() {}
 
@@ -76,6 +76,7 @@ type GovernanceTokenContract struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	caller := framework.GetCaller()
 	event := framework.NewEvent("ContractInitialized")
 	event.AddStringField("contract", "GovernanceToken")
@@ -125,6 +126,7 @@ func Initialize() uint32 {
 //
 //export Mint
 func Mint() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -200,6 +202,7 @@ func Mint() uint32 {
 //
 //export Transfer
 func Transfer() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	toStr := params.ParseJSON("to")
@@ -272,6 +275,7 @@ func Transfer() uint32 {
 //
 //export DelegateVotingPower
 func DelegateVotingPower() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	delegateStr := params.ParseJSON("delegate")
@@ -356,6 +360,7 @@ func DelegateVotingPower() uint32 {
 //
 //export VoteWithTokens
 func VoteWithTokens() uint32 {
+	framework.BeginInvocation()
 	// 步骤1：解析参数并验证
 	params := framework.GetContractParams()
 	proposalIDStr := params.ParseJSON("proposal_id")
@@ -30,6 +30,7 @@ type StandardToken struct {
 //
 //export Initialize
 func Initialize() uint32 {
+	framework.BeginInvocation()
 	contract := &StandardToken{}
 
 	// TODO: 解析初始化参数
@@ -72,6 +73,7 @@ func Initialize() uint32 {
 //
 //export Transfer
 func Transfer() uint32 {
+	framework.BeginInvocation()
 	contract := &StandardToken{}
 
 	// TODO: 解析转账参数
@@ -123,6 +125,7 @@ func Transfer() uint32 {
 //
 //export BalanceOf
 func BalanceOf() uint32 {
+	framework.BeginInvocation()
 	contract := &StandardToken{}
 
 	// TODO: 解析地址参数
@@ -140,6 +143,7 @@ func BalanceOf() uint32 {
 //
 //export Approve
 func Approve() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 
@@ -147,6 +151,7 @@ func Approve() uint32 {
 //
 //export TransferFrom
 func TransferFrom() uint32 {
+	framework.BeginInvocation()
 	return framework.ERROR_NOT_IMPLEMENTED
 }
 